@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/i18n"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/terminal"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	// Parse CLI flags
+	configPath := flag.String("config", "config/config.yaml", "Path to config file")
+	snapshotsPath := flag.String("snapshots", "", "Path to a snapshot JSONL file or directory (required)")
+	from := flag.String("from", "", "Start date (YYYY-MM-DD), inclusive")
+	to := flag.String("to", "", "End date (YYYY-MM-DD), inclusive")
+	migrationsDir := flag.String("migrations", "migrations", "Path to migrations directory")
+	allowRisky := flag.Bool("allow-risky", false, "Allow entries with a risky volatility recommendation")
+	tradesCSV := flag.String("trades-csv", "", "Write the trade log to this CSV file")
+	equityCSV := flag.String("equity-csv", "", "Write the equity curve to this CSV file")
+	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	noColor := flag.Bool("no-color", false, "Disable colors and Unicode symbols in logs and the report")
+	flag.Parse()
+
+	// Setup logging
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	if *verbose {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	plain := *noColor || terminal.Plain(os.Stderr)
+	var console io.Writer = os.Stderr
+	if plain {
+		console = terminal.NewASCIIWriter(os.Stderr)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: console, TimeFormat: time.RFC3339, NoColor: plain})
+
+	if *snapshotsPath == "" {
+		log.Fatal().Msg("-snapshots is required")
+	}
+
+	start, err := parseDate(*from)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid -from date")
+	}
+	end, err := parseDate(*to)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid -to date")
+	}
+	if !end.IsZero() {
+		// Include the whole end day
+		end = end.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+	lang, err := i18n.ParseLanguage(cfg.Locale.Language)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid locale.language")
+	}
+
+	// Parse titles as the live bot does
+	if cfg.Scan.ParserRules != "" {
+		rules, err := scanner.LoadRules(cfg.Scan.ParserRules)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load scan.parser_rules")
+		}
+		if err := scanner.SetRules(rules); err != nil {
+			log.Fatal().Err(err).Msg("Invalid scan.parser_rules")
+		}
+	}
+
+	snapshots, err := backtest.LoadSnapshots(*snapshotsPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load snapshots")
+	}
+	snapshots = backtest.FilterRange(snapshots, start, end)
+	if len(snapshots) == 0 {
+		log.Fatal().Msg("No snapshots in the requested date range")
+	}
+
+	// Use the same sizing configuration as the live bot
+	btConfig := backtest.FromConfig(cfg, *migrationsDir)
+	btConfig.AllowRisky = *allowRisky
+	engine := backtest.NewEngine(btConfig)
+
+	report, err := engine.Run(snapshots)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Backtest failed")
+	}
+
+	decimals := cfg.Precision.DisplayDecimals
+	if decimals <= 0 {
+		decimals = 2
+	}
+	var out io.Writer = os.Stdout
+	if *noColor || terminal.Plain(os.Stdout) {
+		out = terminal.NewASCIIWriter(os.Stdout)
+	}
+	printReport(out, report, decimals, i18n.New(lang))
+
+	if *tradesCSV != "" {
+		if err := writeTradesCSV(*tradesCSV, report.Trades); err != nil {
+			log.Fatal().Err(err).Msg("Failed to write trade log")
+		}
+	}
+	if *equityCSV != "" {
+		if err := writeEquityCSV(*equityCSV, report.Equity); err != nil {
+			log.Fatal().Err(err).Msg("Failed to write equity curve")
+		}
+	}
+}
+
+// parseDate parses a YYYY-MM-DD date in UTC. An empty string returns the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateLayout, value, time.UTC)
+}
+
+// printReport prints the trade log and summary statistics to out, with
+// dollar amounts shown to the given number of decimal places and labels in
+// the translator's language.
+func printReport(out io.Writer, report *backtest.Report, decimals int, tr i18n.Translator) {
+	fmt.Fprintln(out, tr.T("report.trade_log"))
+	for _, t := range report.Trades {
+		fmt.Fprintf(out, "%s  %-10s %-3s %-40.40s entry=%.3f exit=%.3f qty=%.2f pnl=%+.*f  %s\n",
+			t.ExitTime.Format(time.RFC3339), t.Platform, t.Side, t.MarketTitle,
+			t.EntryPrice, t.ExitPrice, t.Quantity, decimals, t.RealizedPnL, t.ExitReason)
+	}
+
+	s := report.Summary
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, tr.T("report.summary"))
+	fmt.Fprintf(out, "%-17s%s → %s\n", tr.T("report.period"), s.Start.Format(time.RFC3339), s.End.Format(time.RFC3339))
+	fmt.Fprintf(out, "%-17s%d\n", tr.T("report.snapshots"), len(report.Equity))
+	fmt.Fprintf(out, "%-17s%s\n", tr.T("report.trades"), tr.T("report.trades_detail", s.TotalTrades, s.WinningTrades, s.LosingTrades))
+	fmt.Fprintf(out, "%-17s%.1f%%\n", tr.T("report.win_rate"), s.WinRate*100)
+	fmt.Fprintf(out, "%-17s$%.*f\n", tr.T("report.total_pnl"), decimals, s.TotalPnL)
+	fmt.Fprintf(out, "%-17s$%.*f → $%.*f (%+.2f%%)\n", tr.T("report.equity"), decimals, s.InitialEquity, decimals, s.FinalEquity, s.ReturnPercent)
+	fmt.Fprintf(out, "%-17s%.2f\n", tr.T("report.sharpe"), s.SharpeRatio)
+	fmt.Fprintf(out, "%-17s%.2f%%\n", tr.T("report.max_drawdown"), s.MaxDrawdown*100)
+	fmt.Fprintf(out, "%-17s%s\n", tr.T("report.avg_holding"), s.AvgHoldingTime.Round(time.Minute))
+	if report.Errors > 0 {
+		fmt.Fprintf(out, "%-17s%d\n", tr.T("report.errors"), report.Errors)
+	}
+	for reason, count := range report.Skips {
+		fmt.Fprintln(out, tr.T("report.skipped", reason, count))
+	}
+}
+
+// writeTradesCSV writes the trade log to a CSV file.
+func writeTradesCSV(path string, trades []backtest.Trade) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{
+		"position_id", "platform", "market_id", "market_title", "side",
+		"entry_time", "exit_time", "entry_price", "exit_price", "quantity",
+		"realized_pnl", "safety_margin", "exit_reason",
+	})
+	for _, t := range trades {
+		w.Write([]string{
+			strconv.FormatInt(t.PositionID, 10),
+			t.Platform,
+			t.MarketID,
+			t.MarketTitle,
+			t.Side,
+			t.EntryTime.Format(time.RFC3339),
+			t.ExitTime.Format(time.RFC3339),
+			formatFloat(t.EntryPrice),
+			formatFloat(t.ExitPrice),
+			formatFloat(t.Quantity),
+			formatFloat(t.RealizedPnL),
+			formatFloat(t.SafetyMargin),
+			t.ExitReason,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeEquityCSV writes the equity curve to a CSV file.
+func writeEquityCSV(path string, equity []backtest.EquityPoint) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"timestamp", "equity"})
+	for _, p := range equity {
+		w.Write([]string{p.Timestamp.Format(time.RFC3339), formatFloat(p.Equity)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
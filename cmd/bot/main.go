@@ -5,23 +5,45 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/arbitrage"
+	"prediction-bot/internal/audit"
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/balance"
+	"prediction-bot/internal/blackout"
 	"prediction-bot/internal/bot"
 	"prediction-bot/internal/config"
 	"prediction-bot/internal/dashboard"
+	"prediction-bot/internal/datasource"
+	"prediction-bot/internal/events"
+	"prediction-bot/internal/experiment"
+	"prediction-bot/internal/health"
+	"prediction-bot/internal/i18n"
+	"prediction-bot/internal/learning"
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/paper"
 	"prediction-bot/internal/persistence"
 	"prediction-bot/internal/platform"
-	"prediction-bot/internal/platform/kalshi"
-	"prediction-bot/internal/platform/polymarket"
+	_ "prediction-bot/internal/platform/adapters"
 	"prediction-bot/internal/position"
+	"prediction-bot/internal/replay"
+	"prediction-bot/internal/risk"
 	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/settlement"
 	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/strategy"
+	"prediction-bot/internal/terminal"
 	"prediction-bot/internal/volatility"
+	"prediction-bot/internal/webui"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -34,6 +56,12 @@ func main() {
 	liveMode := flag.Bool("live", false, "Enable LIVE TRADING (REAL MONEY!) - requires confirmation")
 	verbose := flag.Bool("verbose", false, "Enable verbose logging")
 	dashboardMode := flag.Bool("dashboard", false, "Run with terminal dashboard UI")
+	flag.BoolVar(dashboardMode, "with-dashboard", false, "Same as -dashboard")
+	noColor := flag.Bool("no-color", false, "Disable colors and Unicode symbols in logs and the dashboard")
+	logFile := flag.String("log-file", "bot.log", "File logs are written to in dashboard mode, which uses the terminal")
+	replayPath := flag.String("replay", "", "Replay recorded snapshots (a JSONL file or directory) in place of the platforms, dry-run only")
+	replaySpeed := flag.Float64("replay-speed", 60, "Seconds of recorded time replayed per second")
+	replayDB := flag.String("replay-db", "replay.db", "Database a replay runs on, recreated on each replay")
 	flag.Parse()
 
 	// Determine if we're in dry-run mode
@@ -48,11 +76,29 @@ func main() {
 	} else {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
+	// Plain output for NO_COLOR, dumb terminals and when piped to a file
+	plain := *noColor || terminal.Plain(os.Stderr)
+	var console io.Writer = os.Stderr
+	if plain {
+		console = terminal.NewASCIIWriter(os.Stderr)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: console, TimeFormat: time.RFC3339, NoColor: plain})
+
+	// A replay never trades: it reruns recorded markets to debug the bot
+	if *replayPath != "" {
+		if *liveMode {
+			log.Fatal().Msg("-replay runs dry-run only and can't be combined with -live")
+		}
+		isDryRun = true
+	}
 
 	// If live mode is requested, require explicit confirmation
 	if *liveMode {
-		if !confirmLiveTrading() {
+		var prompt io.Writer = os.Stdout
+		if *noColor || terminal.Plain(os.Stdout) {
+			prompt = terminal.NewASCIIWriter(os.Stdout)
+		}
+		if !confirmLiveTrading(prompt) {
 			log.Info().Msg("Live trading cancelled by user")
 			os.Exit(0)
 		}
@@ -71,22 +117,60 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to load config")
 	}
+	lang, err := i18n.ParseLanguage(cfg.Locale.Language)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid locale.language")
+	}
 
 	log.Info().
 		Float64("bankroll_polymarket", cfg.Bankroll.Polymarket).
 		Float64("bankroll_kalshi", cfg.Bankroll.Kalshi).
+		Float64("bankroll_manifold", cfg.Bankroll.Manifold).
+		Strs("platforms", cfg.EnabledPlatforms()).
 		Msg("Configuration loaded")
 
-	// Initialize database
+	// Load the snapshots to replay, run on a fresh database of their own
+	// so replays are repeatable and never touch the bot's
+	var replayer *replay.Replay
 	dbPath := cfg.Database.Path
 	if dbPath == "" {
 		dbPath = "bot.db"
 	}
+	if *replayPath != "" {
+		snapshots, err := backtest.LoadSnapshots(*replayPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load replay snapshots")
+		}
+		replayer, err = replay.New(snapshots, *replaySpeed)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid replay")
+		}
+		dbPath = *replayDB
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+				log.Fatal().Err(err).Str("path", dbPath+suffix).Msg("Failed to remove the previous replay database")
+			}
+		}
+		log.Info().
+			Int("snapshots", len(snapshots)).
+			Time("from", snapshots[0].Timestamp).
+			Time("to", replayer.End()).
+			Float64("speed", *replaySpeed).
+			Str("db", dbPath).
+			Msg("Replaying snapshots")
+	}
+
+	// Initialize database
 	db, err := persistence.OpenDB(dbPath)
 	if err != nil {
 		log.Fatal().Err(err).Str("path", dbPath).Msg("Failed to open database")
 	}
 	defer db.Close()
+	defer func() {
+		if err := persistence.Checkpoint(db); err != nil {
+			log.Warn().Err(err).Msg("Failed to checkpoint database")
+		}
+	}()
 
 	// Run migrations
 	if err := persistence.RunMigrations(db, "migrations"); err != nil {
@@ -98,11 +182,17 @@ func main() {
 	bankRepo := persistence.NewBankrollRepository(db)
 
 	// Initialize bankroll for platforms
-	if err := bankRepo.Initialize("polymarket", cfg.Bankroll.Polymarket); err != nil {
-		log.Warn().Err(err).Msg("Failed to initialize polymarket bankroll (may already exist)")
+	platformNames := cfg.EnabledPlatforms()
+	if replayer != nil {
+		platformNames = nil
+		for _, p := range replayer.Platforms() {
+			platformNames = append(platformNames, p.Name())
+		}
 	}
-	if err := bankRepo.Initialize("kalshi", cfg.Bankroll.Kalshi); err != nil {
-		log.Warn().Err(err).Msg("Failed to initialize kalshi bankroll (may already exist)")
+	for _, name := range platformNames {
+		if err := bankRepo.Initialize(name, cfg.Bankroll.For(name)); err != nil {
+			log.Warn().Err(err).Msgf("Failed to initialize %s bankroll (may already exist)", name)
+		}
 	}
 
 	// Get Alpha Vantage API key from environment
@@ -110,64 +200,431 @@ func main() {
 	if alphaVantageKey == "" {
 		log.Warn().Msg("ALPHAVANTAGE_API_KEY not set, stock data will not be available")
 	}
+	polygonKey := os.Getenv("POLYGON_API_KEY")
 
 	// Initialize volatility service
 	volService := volatility.NewService(alphaVantageKey)
+	if polygonKey != "" {
+		volService.SetPolygonKey(polygonKey)
+	}
+	for asset, a := range cfg.Volatility.Assets {
+		volService.SetAssetConfig(asset, volatility.AssetConfig{
+			AnnualizationDays: a.AnnualizationDays,
+			MinVolatility:     a.MinVolatility,
+			MaxVolatility:     a.MaxVolatility,
+			Override:          a.Override,
+		})
+	}
+	volService.SetOverrideRepository(persistence.NewVolatilityOverrideRepository(db))
+	volService.SetPriceHistoryRepository(persistence.NewPriceHistoryRepository(db))
+	for assetClass, c := range cfg.Volatility.Cache {
+		volService.SetCacheTTL(assetClass, volatility.CacheTTL{
+			Price:   time.Duration(c.PriceSeconds * float64(time.Second)),
+			History: time.Duration(c.HistoryMinutes * float64(time.Minute)),
+		})
+	}
+	volService.SetPriceCacheRepository(persistence.NewPriceCacheRepository(db))
+	var analyzer position.VolatilityAnalyzer = volService
+
+	if replayer != nil {
+		// Replayed markets are analyzed on the prices recorded with them
+		analyzer = replayer
+	} else {
+		// Warm the cache with stored prices and the assets open positions are
+		// monitored on, so the first cycles don't all wait on the price APIs
+		var openAssets []string
+		if open, err := posRepo.GetOpen(); err != nil {
+			log.Warn().Err(err).Msg("Failed to get open positions to warm the price cache")
+		} else {
+			for _, pos := range open {
+				if pos.Asset != "" && !slices.Contains(openAssets, pos.Asset) {
+					openAssets = append(openAssets, pos.Asset)
+				}
+			}
+		}
+		if warmed, err := volService.WarmUp(openAssets); err != nil {
+			log.Warn().Err(err).Msg("Failed to warm the price cache")
+		} else {
+			log.Info().Int("assets", warmed).Msg("Price cache warmed")
+		}
+	}
+
+	// The parameters table, which learning adjusts, overrides config.yaml's
+	// parameters, except those config.yaml changed since they were last
+	// synced; the bot reads it again each scan cycle
+	baseParams := cfg.Parameters
+	paramsRepo := persistence.NewParametersRepository(db)
+	if changes, err := paramsRepo.SyncConfigValues(baseParams.Values(), "config.yaml"); err != nil {
+		log.Warn().Err(err).Msg("Failed to sync parameters table with config")
+	} else if len(changes) > 0 {
+		log.Info().Int("changes", len(changes)).Msg("Parameters table synced with config")
+	}
+	paramCache := persistence.NewParameterCache(paramsRepo, time.Minute)
+	if values, err := paramCache.Values(); err != nil {
+		log.Warn().Err(err).Msg("Failed to read parameters table, using config")
+	} else {
+		cfg.Parameters = cfg.Parameters.WithValues(values)
+	}
+
+	// Apply the active strategy's overrides on top of the parameters; the
+	// bot reloads it when its file changes
+	var strategies *strategy.Registry
+	var activeStrategy strategy.Strategy
+	if cfg.Strategies.Active != "" {
+		dir := cfg.Strategies.Dir
+		if dir == "" {
+			dir = "strategies"
+		}
+		strategies = strategy.NewRegistry(dir)
+		if err := strategies.Load(); err != nil {
+			log.Fatal().Err(err).Msg("Failed to load strategies")
+		}
+		s, ok := strategies.Get(cfg.Strategies.Active)
+		if !ok {
+			log.Fatal().Str("strategy", cfg.Strategies.Active).Strs("defined", strategies.Names()).Msg("Unknown strategies.active")
+		}
+		activeStrategy = s
+		cfg.Parameters = s.Apply(cfg.Parameters)
+		log.Info().Str("strategy", s.Name).Int("version", s.Version).Str("path", s.Path).Msg("Strategy loaded")
+	}
 
 	// Initialize sizer
 	sizerConfig := sizing.SizerConfig{
-		KellyFraction:  cfg.Parameters.KellyFraction,
-		MinPosition:    1.0,
-		MaxBankrollPct: 0.20,
+		KellyFraction:   cfg.Parameters.KellyFraction,
+		MinPosition:     1.0,
+		MaxBankrollPct:  0.20,
+		MaxLiquidityPct: cfg.Parameters.MaxLiquidityPct,
+		AmountDecimals:  cfg.Precision.AmountDecimals,
+		Correlation:     cfg.Parameters.KellyCorrelation,
 	}
 	sizer := sizing.NewSizer(sizerConfig)
 
 	// Initialize position manager
-	manager := position.NewManager(posRepo, bankRepo, volService, sizer)
+	manager := position.NewManager(posRepo, bankRepo, analyzer, sizer)
+	riskChecker := risk.NewChecker(risk.Limits{
+		MaxAssetExposure:       cfg.Risk.MaxAssetExposure,
+		MaxOpenPositions:       cfg.Risk.MaxOpenPositions,
+		MaxDirectionalExposure: cfg.Risk.MaxDirectionalExposure,
+		MaxDailyVaR:            cfg.Risk.MaxDailyVaR,
+		VaRConfidence:          cfg.Risk.VaRConfidence,
+	})
+	manager.SetRiskChecker(riskChecker)
+	err = manager.SetEntryExecution(position.EntryExecution{
+		Strategy:       cfg.Entry.Strategy,
+		Timeout:        time.Duration(cfg.Entry.TimeoutSeconds) * time.Second,
+		CrossOnTimeout: cfg.Entry.CrossOnTimeout,
+		BufferTicks:    cfg.Entry.BufferTicks,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid entry.strategy")
+	}
+	if err := manager.SetAllocation(cfg.Allocation); err != nil {
+		log.Fatal().Err(err).Msg("Invalid allocation")
+	}
+	var kellySteps []sizing.DrawdownStep
+	for _, step := range cfg.Parameters.KellyDrawdown {
+		kellySteps = append(kellySteps, sizing.DrawdownStep{Drawdown: step.Drawdown, Scale: step.Scale})
+	}
+	if err := manager.SetKellyDrawdown(kellySteps); err != nil {
+		log.Fatal().Err(err).Msg("Invalid parameters.kelly_drawdown")
+	}
+	manager.SetParametersRepo(paramsRepo)
+	manager.SetStrategy(activeStrategy.Name, activeStrategy.Version)
+	manager.SetBestStrikePerEvent(cfg.Scan.BestStrikePerEvent)
+	manager.SetFadeMinEdge(cfg.Fade.Edge())
 
 	// Initialize position monitor
 	monitor := position.NewMonitor(cfg.Parameters.StopLossPercent)
+	if cfg.Parameters.StopLossType != "" {
+		if err := monitor.SetStopLossType(cfg.Parameters.StopLossType); err != nil {
+			log.Fatal().Err(err).Msg("Invalid parameters.stop_loss_type")
+		}
+	}
+	monitor.SetTakeProfitPercent(cfg.Parameters.TakeProfitPercent)
+	monitor.SetTimeDecayLead(time.Duration(cfg.Parameters.TimeDecayExitHours * float64(time.Hour)))
+	if err := monitor.SetDecayCheckpoints(cfg.Parameters.DecayRecheckPoints); err != nil {
+		log.Fatal().Err(err).Msg("Invalid parameters.decay_recheck_points")
+	}
+	for assetClass, minutes := range cfg.Flatten.LeadMinutes {
+		monitor.SetFlattenLeadTime(assetClass, time.Duration(minutes)*time.Minute)
+	}
 
 	// Initialize scanner
+	if cfg.Scan.ParserRules != "" {
+		rules, err := scanner.LoadRules(cfg.Scan.ParserRules)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load scan.parser_rules")
+		}
+		if err := scanner.SetRules(rules); err != nil {
+			log.Fatal().Err(err).Msg("Invalid scan.parser_rules")
+		}
+		log.Info().Str("path", cfg.Scan.ParserRules).Msg("Market title parser rules loaded")
+	}
+
+	// Enrich positions recorded before newer columns existed from what the
+	// database holds; botctl backfill-positions -fetch asks the platforms
+	backfill, err := position.NewBackfiller(posRepo).Run()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to backfill legacy positions")
+	} else if backfill.Defaults > 0 || backfill.Parsed > 0 {
+		log.Info().Str("backfill", backfill.String()).Msg("Legacy positions backfilled")
+	}
+
 	sc := scanner.NewScanner(cfg.Parameters)
+	if cfg.Experiment.Name != "" {
+		exp, err := experiment.New(cfg.Experiment.Name, cfg.Experiment.Share, cfg.Experiment.Challenger)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid experiment")
+		}
+		sc.SetExperiment(exp)
+		log.Info().Str("experiment", exp.Name()).Float64("share", cfg.Experiment.Share).Msg("Parameter experiment running")
+	}
+	if cfg.Blackout.File != "" {
+		calendar, err := blackout.Load(cfg.Blackout.File)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load blackout.file")
+		}
+		sc.AddBlackoutCalendar(calendar)
+		log.Info().Str("path", cfg.Blackout.File).Int("windows", len(calendar)).Msg("Blackout calendar loaded")
+	}
+	if econ := cfg.Blackout.EconomicCalendar; econ.Enabled {
+		apiKey := os.Getenv("FMP_API_KEY")
+		if apiKey == "" {
+			log.Fatal().Msg("blackout.economic_calendar is enabled but FMP_API_KEY is not set")
+		}
+		countries := econ.Countries
+		if len(countries) == 0 {
+			countries = []string{"US"}
+		}
+		before, after := econ.HoursBefore, econ.HoursAfter
+		if before <= 0 {
+			before = 12
+		}
+		if after <= 0 {
+			after = 1
+		}
+		sc.AddBlackoutCalendar(blackout.NewEconomicCalendar(apiKey, countries,
+			time.Duration(before*float64(time.Hour)), time.Duration(after*float64(time.Hour))))
+	}
 
-	// Initialize platforms
-	var platforms []platform.Platform
+	// Initialize order tracker
+	tracker := orders.NewTracker(persistence.NewOrderRepository(db), posRepo, bankRepo)
 
-	// Try to initialize Polymarket client
-	polyClient, err := polymarket.NewClient()
-	if err != nil {
-		log.Warn().Err(err).Msg("Failed to initialize Polymarket client (check POLYMARKET_PRIVATE_KEY)")
-	} else {
-		platforms = append(platforms, polyClient)
-		log.Info().Msg("Polymarket client initialized")
+	// Initialize settler for resolved markets
+	settler := settlement.NewSettler(posRepo, persistence.NewResolutionRepository(db), manager)
+	if cfg.Settlement.RedemptionAlertHours > 0 {
+		settler.SetRedemptionWindow(time.Duration(cfg.Settlement.RedemptionAlertHours) * time.Hour)
 	}
+	settler.SetCostRepository(persistence.NewCostRepository(db))
+	settlementPrices := datasource.NewAggregator(alphaVantageKey)
+	if polygonKey != "" {
+		settlementPrices.SetPolygonKey(polygonKey)
+	}
+	settler.SetPriceHistory(settlementPrices)
 
-	// Try to initialize Kalshi client
-	kalshiClient, err := kalshi.NewClient()
-	if err != nil {
-		log.Warn().Err(err).Msg("Failed to initialize Kalshi client (check KALSHI_* env vars)")
+	// Audit every platform API call when enabled
+	apiLogRepo := persistence.NewAPILogRepository(db)
+	var apiAudit platform.APICallRecorder
+	if cfg.APILog.Enabled {
+		apiAudit = &apiLogRecorder{repo: apiLogRepo, retention: cfg.APILog.Retention()}
+	}
+
+	// Initialize the enabled platforms from the registry, or those of the
+	// replay
+	var platforms []platform.Platform
+	if replayer != nil {
+		for _, p := range replayer.Platforms() {
+			platforms = append(platforms, p)
+			wirePlatform(p, manager, tracker, settler, isDryRun)
+		}
 	} else {
-		platforms = append(platforms, kalshiClient)
-		log.Info().Msg("Kalshi client initialized")
+		for _, name := range cfg.EnabledPlatforms() {
+			p, err := platform.New(name, platform.Options{
+				DryRun: isDryRun,
+				HTTP: platform.TransportConfig{
+					RequestsPerMinute: cfg.HTTP.RequestsPerMinute[name],
+					MaxRetries:        cfg.HTTP.MaxRetries,
+					BaseBackoff:       cfg.HTTP.BaseBackoff(),
+					MaxBackoff:        cfg.HTTP.MaxBackoff(),
+					BreakerThreshold:  cfg.HTTP.BreakerThreshold,
+					BreakerCooldown:   cfg.HTTP.BreakerCooldown(),
+				},
+				Audit:             apiAudit,
+				AuditPayloadBytes: cfg.APILog.PayloadBytes,
+			})
+			if err != nil {
+				log.Warn().Err(err).Str("platform", name).Msg("Failed to initialize platform (check its credentials)")
+				continue
+			}
+			platforms = append(platforms, p)
+			wirePlatform(p, manager, tracker, settler, isDryRun)
+			log.Info().Str("platform", name).Msg("Platform client initialized")
+		}
 	}
 
 	if len(platforms) == 0 {
 		log.Fatal().Msg("No platforms initialized. Check your API keys.")
 	}
 
+	// Journal live orders before they are placed, and check those a crash
+	// interrupted against the platforms: orphaned orders still resting are
+	// cancelled and the positions they may have filled for flagged, for the
+	// reconciliation below to resolve
+	if !isDryRun {
+		intentRepo := persistence.NewOrderIntentRepository(db)
+		manager.SetIntentJournal(intentRepo)
+		intentReconciler := position.NewIntentReconciler(intentRepo, posRepo)
+		for _, p := range platforms {
+			if lookup, ok := p.(position.OrderLookup); ok {
+				intentReconciler.SetPlatform(p.Name(), lookup)
+			}
+		}
+		intents, err := intentReconciler.Run()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to reconcile interrupted orders")
+		} else if intents.Resolved > 0 || intents.Unresolved > 0 {
+			log.Warn().Str("reconciliation", intents.String()).Msg("Interrupted orders reconciled")
+		}
+	}
+
+	// Repair entries and exits a crash interrupted, before trading resumes.
+	// Live, positions in error are resolved against what the platforms hold
+	reconciler := position.NewReconciler(posRepo, bankRepo, isDryRun)
+	for _, p := range platforms {
+		reconciler.SetPositionLister(p.Name(), p)
+	}
+	reconciled, err := reconciler.Run()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to reconcile interrupted trades")
+	} else if reconciled.Repaired() > 0 || len(reconciled.Uncredited) > 0 {
+		log.Warn().Str("reconciliation", reconciled.String()).Msg("Interrupted trades reconciled")
+	}
+
+	// Record positions held on the platforms but not in the database, such
+	// as trades placed by hand, so they are monitored. Live only: dry-run
+	// positions are never on a platform
+	if !isDryRun {
+		importer := position.NewImporter(posRepo)
+		for _, p := range platforms {
+			importer.SetPlatform(p.Name(), p)
+			if getter, ok := p.(position.MarketGetter); ok {
+				importer.SetMarketGetter(p.Name(), getter)
+			}
+		}
+		imported, err := importer.Run()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to import platform positions")
+		} else if imported.Imported > 0 || imported.Failed > 0 {
+			log.Warn().Str("import", imported.String()).Msg("Platform positions imported")
+		}
+	}
+
+	// Approximate live execution in dry-run mode
+	if isDryRun {
+		manager.SetDryRunSimulation(position.DryRunSimulation{
+			Latency:  time.Duration(cfg.DryRun.LatencyMs) * time.Millisecond,
+			FeeRates: cfg.DryRun.FeeRates,
+		})
+		for _, p := range platforms {
+			if quoter, ok := p.(position.PriceQuoter); ok {
+				manager.SetPriceQuoter(p.Name(), quoter)
+			}
+			if slices.Contains(cfg.DryRun.PaperTrading, p.Name()) {
+				manager.SetPaperOrderer(p.Name(), paper.NewExchange(p, cfg.DryRun.FeeRates[p.Name()]))
+				log.Info().Str("platform", p.Name()).Msg("Paper trading dry-run fills against the live order book")
+			}
+		}
+	}
+
 	// Create bot config
 	botConfig := bot.BotConfig{
-		DryRun:          isDryRun,
-		ScanInterval:    time.Duration(cfg.Scan.IntervalSeconds) * time.Second,
-		MonitorInterval: 5 * time.Second,
+		DryRun:           isDryRun,
+		ScanInterval:     time.Duration(cfg.Scan.IntervalSeconds) * time.Second,
+		MonitorInterval:  5 * time.Second,
+		SettleInterval:   time.Minute,
+		BalanceInterval:  cfg.Balances.Interval(),
+		LearningInterval: cfg.Learning.Interval(),
+		ScanWorkers:      cfg.Scan.Workers,
+		MarketWorkers:    cfg.Scan.MarketWorkers,
+		DrainTimeout:     cfg.Shutdown.DrainTimeout(),
+		CancelOnShutdown: cfg.Shutdown.CancelRestingOrders,
+	}
+
+	// A replay runs on its clock, its cycles as often in recorded time as
+	// they would be live
+	if replayer != nil {
+		sc.SetClock(replayer.Now)
+		manager.SetClock(replayer.Now)
+		riskChecker.SetClock(replayer.Now)
+		settler.SetClock(replayer.Now)
+		botConfig.ScanInterval = replayer.Scale(botConfig.ScanInterval)
+		botConfig.MonitorInterval = replayer.Scale(botConfig.MonitorInterval)
+		botConfig.SettleInterval = replayer.Scale(botConfig.SettleInterval)
+		if botConfig.LearningInterval > 0 {
+			botConfig.LearningInterval = replayer.Scale(botConfig.LearningInterval)
+		}
 	}
 
 	// Create bot
 	tradingBot := bot.NewBot(botConfig, platforms, sc, manager)
 	tradingBot.SetMonitor(monitor)
-	tradingBot.SetVolatilityAnalyzer(volService)
+	tradingBot.SetVolatilityAnalyzer(analyzer)
+	if replayer != nil {
+		tradingBot.SetClock(replayer.Now)
+	}
 	tradingBot.SetPositionRepo(posRepo)
+	tradingBot.SetNearMissRepo(persistence.NewNearMissRepository(db))
+	tradingBot.SetScanDecisionRepo(persistence.NewScanDecisionRepository(db))
+	tradingBot.SetScanSnapshotRepo(persistence.NewScanSnapshotRepository(db))
+	tradingBot.SetOrderTracker(tracker)
+	tradingBot.SetSettler(settler)
+	tradingBot.SetSessionRepo(persistence.NewSessionRepository(db))
+	tradingBot.SetAuditRecorder(audit.NewRecorder(persistence.NewEventRepository(db)))
+	eventBus := events.NewBus()
+	tradingBot.SetEventBus(eventBus)
+	if strategies != nil {
+		tradingBot.SetStrategies(strategies, activeStrategy.Name, baseParams)
+	}
+	// Reload config.yaml's parameters, risk limits and allocation into the
+	// running bot when the file changes or on SIGHUP
+	configWatcher, err := config.NewWatcher(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to watch config file")
+	}
+	loadedConfig := *cfg
+	loadedConfig.Parameters = baseParams
+	tradingBot.SetConfigWatcher(configWatcher, &loadedConfig, paramsRepo)
+	tradingBot.SetParameterCache(paramCache)
+	if cfg.Arbitrage.Enabled {
+		tradingBot.SetArbitrageDetector(arbitrage.NewDetector(cfg.Arbitrage.MinSpread))
+		tradingBot.SetArbitrageRepo(persistence.NewArbitrageRepository(db))
+		tradingBot.SetHedgeSize(cfg.Arbitrage.HedgeSize)
+	}
+	// Adjust the parameters table from the outcomes of closed trades
+	if cfg.Learning.Interval() > 0 {
+		learner := learning.NewLearner(learning.NewCollector(db), paramsRepo, cfg.Learning.WindowTrades)
+		learner.SetEquityTracking(manager, persistence.NewEquityRepository(db))
+		learner.SetExperiment(cfg.Experiment.Name)
+		tradingBot.SetLearner(learner)
+	}
+	notifier := alert.NewNotifier()
+	if cfg.Alerts.Bell {
+		notifier.SetBell(os.Stderr)
+	}
+	notifier.SetCommand(cfg.Alerts.Command)
+	tradingBot.SetAlerter(notifier)
+
+	// Reconcile the bankrolls with the platforms' balances, live only:
+	// dry-run bankrolls are paper money
+	if !isDryRun && cfg.Balances.Interval() > 0 {
+		balances := balance.NewReconciler(bankRepo, persistence.NewBalanceCheckRepository(db))
+		for _, p := range platforms {
+			tolerance := cfg.Balances.Tolerances[p.Name()]
+			balances.SetPlatform(p.Name(), p, balance.Tolerance{Amount: tolerance.Amount, AutoAdjust: tolerance.AutoAdjust})
+		}
+		balances.SetAlerter(notifier)
+		tradingBot.SetBalanceReconciler(balances)
+	}
 
 	// Setup signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -182,18 +639,166 @@ func main() {
 		cancel()
 	}()
 
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			log.Info().Msg("Received SIGHUP, reloading config at the next scan cycle")
+			configWatcher.Force()
+		}
+	}()
+
+	// Check the bot's health against the alert thresholds, so breaches are
+	// alerted without an external Prometheus
+	thresholds := cfg.Alerts.Thresholds
+	watchdog := alert.NewWatchdog(alert.Thresholds{
+		ScanStale:    thresholds.ScanStale(),
+		MaxErrorRate: thresholds.MaxErrorRate,
+		ErrorWindow:  thresholds.ErrorWindow(),
+		MaxDrawdown:  thresholds.MaxDrawdown,
+	}, notifier)
+	go watchdog.Run(ctx, thresholds.CheckInterval(), func() (alert.Metrics, error) {
+		session := tradingBot.Session()
+		drawdown, err := manager.Drawdown()
+		return alert.Metrics{
+			LastScan: tradingBot.LastScan(),
+			Cycles:   session.ScanCycles + session.MonitorCycles + session.SettleCycles,
+			Errors:   session.Errors,
+			Drawdown: drawdown,
+		}, err
+	})
+
+	// Price open positions on the dashboards at what the platforms report
+	// for them, live only: dry-run positions are never on a platform
+	var holdingPrices dashboard.PriceGetter
+	if !isDryRun {
+		prices := dashboard.NewHoldingPrices(30 * time.Second)
+		for _, p := range platforms {
+			prices.SetPlatform(p.Name(), p)
+		}
+		holdingPrices = prices
+	}
+
+	// Serve the web dashboard alongside the bot
+	if cfg.WebUI.Listen != "" {
+		provider := dashboard.NewDBDataProvider(bankRepo, posRepo, holdingPrices)
+		provider.SetCostRepository(persistence.NewCostRepository(db))
+		provider.SetPriceHistoryRepository(persistence.NewPriceHistoryRepository(db))
+		provider.SetAPILogRepository(apiLogRepo)
+		web := webui.NewServer(provider, isDryRun)
+		web.SetRefreshInterval(time.Duration(cfg.WebUI.RefreshSeconds) * time.Second)
+		web.SetOperatorActions(persistence.NewOperatorActionRepository(db))
+		web.SetMetrics(watchdog)
+		if password := os.Getenv("WEBUI_PASSWORD"); password != "" {
+			web.SetBasicAuth(cfg.WebUI.Username, password)
+		} else {
+			log.Warn().Msg("WEBUI_PASSWORD not set, web dashboard is served without authentication")
+		}
+		go func() {
+			if err := web.ListenAndServe(ctx, cfg.WebUI.Listen); err != nil {
+				log.Error().Err(err).Msg("Web dashboard stopped with error")
+			}
+		}()
+	}
+
+	// Stream the bot's activity to dashboards attached from other terminals.
+	// A replay leaves the socket to the bot it is replaying for
+	if cfg.Dashboard.Socket != "" && replayer == nil {
+		ln, err := events.Listen(cfg.Dashboard.Socket)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to listen for dashboards")
+		}
+		mode := events.ModeDryRun
+		if !isDryRun {
+			mode = events.ModeLive
+		}
+		go func() {
+			if err := events.NewServer(eventBus, mode).Serve(ctx, ln); err != nil {
+				log.Error().Err(err).Msg("Dashboard socket stopped with error")
+			}
+		}()
+		log.Info().Str("socket", cfg.Dashboard.Socket).Msg("Streaming activity to attached dashboards")
+	}
+
+	// Report the bot's health for orchestration to restart it when stuck,
+	// at /healthz and to systemd's watchdog when run under one
+	checker := health.NewChecker(tradingBot, db, health.Thresholds{
+		ScanStale:     cfg.Health.ScanStale(time.Duration(cfg.Scan.IntervalSeconds) * time.Second),
+		MonitorStale:  cfg.Health.MonitorStale(),
+		ProbeInterval: cfg.Health.ProbeInterval(),
+	})
+	for _, p := range platforms {
+		checker.AddPlatform(p)
+	}
+	if cfg.Health.Listen != "" {
+		go func() {
+			if err := checker.ListenAndServe(ctx, cfg.Health.Listen); err != nil {
+				log.Error().Err(err).Msg("Health check stopped with error")
+			}
+		}()
+	}
+	if interval := health.WatchdogInterval(); interval > 0 {
+		go checker.RunWatchdog(ctx, interval)
+		log.Info().Dur("interval", interval).Msg("Pinging systemd watchdog while healthy")
+	}
+	if err := health.Notify(health.NotifyReady); err != nil {
+		log.Warn().Err(err).Msg("Failed to notify systemd the bot is ready")
+	}
+
+	// Stop once the replay has run through its snapshots
+	if replayer != nil {
+		replayer.Start()
+		go func() {
+			select {
+			case <-replayer.Done():
+				log.Info().Time("at", replayer.End()).Msg("Replay finished")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
 	log.Info().
 		Bool("dry_run", isDryRun).
 		Int("platforms", len(platforms)).
 		Bool("dashboard", *dashboardMode).
 		Msg("Starting bot main loop")
 
-	// Run dashboard mode if requested
+	// Run dashboard mode if requested: the bot runs in the background,
+	// streaming its activity to the dashboard
 	if *dashboardMode {
-		log.Info().Msg("Starting dashboard UI...")
-		app := dashboard.NewApp()
+		log.Info().Str("log_file", *logFile).Msg("Starting dashboard UI...")
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to open log file")
+		}
+		defer f.Close()
+		log.Logger = log.Output(f)
+
+		provider := dashboard.NewDBDataProvider(bankRepo, posRepo, holdingPrices)
+		provider.SetCostRepository(persistence.NewCostRepository(db))
+		provider.SetArbitrageRepository(persistence.NewArbitrageRepository(db))
+		provider.SetPriceHistoryRepository(persistence.NewPriceHistoryRepository(db))
+		provider.SetScanSnapshotRepository(persistence.NewScanSnapshotRepository(db))
+		provider.SetAPILogRepository(apiLogRepo)
+		app := dashboard.NewAppWithProvider(provider, isDryRun)
+		app.SetCurrencyDecimals(cfg.Precision.DisplayDecimals)
+		app.SetLanguage(lang)
+		app.SetPlain(*noColor || terminal.Plain(os.Stdout))
+		activity, unsubscribe := eventBus.Subscribe(100)
+		defer unsubscribe()
+		app.SetEvents(activity)
+		app.SetPositionActions(tradingBot)
+
+		botDone := make(chan error, 1)
+		go func() { botDone <- tradingBot.Run(ctx) }()
+
 		if err := app.Run(); err != nil {
 			log.Error().Err(err).Msg("Dashboard stopped with error")
+		}
+		cancel()
+		if err := <-botDone; err != nil {
+			log.Error().Err(err).Msg("Bot stopped with error")
 			os.Exit(1)
 		}
 		log.Info().Msg("Dashboard closed")
@@ -211,23 +816,24 @@ func main() {
 
 // confirmLiveTrading prompts the user to confirm they want to use live trading.
 // This adds an extra layer of protection against accidentally trading with real money.
-func confirmLiveTrading() bool {
-	fmt.Println()
-	fmt.Println("╔════════════════════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                         ⚠️  WARNING: LIVE TRADING ⚠️                         ║")
-	fmt.Println("╠════════════════════════════════════════════════════════════════════════════╣")
-	fmt.Println("║ You are about to enable LIVE TRADING mode.                                ║")
-	fmt.Println("║ This will place REAL orders with REAL money on prediction markets.        ║")
-	fmt.Println("║                                                                            ║")
-	fmt.Println("║ Please ensure you have:                                                    ║")
-	fmt.Println("║   1. Tested thoroughly in dry-run mode                                     ║")
-	fmt.Println("║   2. Set appropriate bankroll limits in config                             ║")
-	fmt.Println("║   3. Verified your API credentials are correct                             ║")
-	fmt.Println("║   4. Understood the risks involved in automated trading                    ║")
-	fmt.Println("║                                                                            ║")
-	fmt.Println("║ Type 'yes' to confirm, or anything else to abort:                          ║")
-	fmt.Println("╚════════════════════════════════════════════════════════════════════════════╝")
-	fmt.Print("\n> ")
+// The prompt is written to out.
+func confirmLiveTrading(out io.Writer) bool {
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "╔════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Fprintln(out, "║                         ⚠️  WARNING: LIVE TRADING ⚠️                         ║")
+	fmt.Fprintln(out, "╠════════════════════════════════════════════════════════════════════════════╣")
+	fmt.Fprintln(out, "║ You are about to enable LIVE TRADING mode.                                ║")
+	fmt.Fprintln(out, "║ This will place REAL orders with REAL money on prediction markets.        ║")
+	fmt.Fprintln(out, "║                                                                            ║")
+	fmt.Fprintln(out, "║ Please ensure you have:                                                    ║")
+	fmt.Fprintln(out, "║   1. Tested thoroughly in dry-run mode                                     ║")
+	fmt.Fprintln(out, "║   2. Set appropriate bankroll limits in config                             ║")
+	fmt.Fprintln(out, "║   3. Verified your API credentials are correct                             ║")
+	fmt.Fprintln(out, "║   4. Understood the risks involved in automated trading                    ║")
+	fmt.Fprintln(out, "║                                                                            ║")
+	fmt.Fprintln(out, "║ Type 'yes' to confirm, or anything else to abort:                          ║")
+	fmt.Fprintln(out, "╚════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Fprint(out, "\n> ")
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -238,3 +844,128 @@ func confirmLiveTrading() bool {
 	response = strings.TrimSpace(strings.ToLower(response))
 	return response == "yes"
 }
+
+// redemptionPlatform is a platform whose winning payouts must be claimed,
+// and that can claim them when configured to sign transactions.
+type redemptionPlatform interface {
+	settlement.RedemptionChecker
+	settlement.Redeemer
+	settlement.GasReporter
+	RedemptionEnabled() bool
+}
+
+// clockChecker is a platform that can measure the local clock's drift from
+// its own.
+type clockChecker interface {
+	CheckClockSkew() (time.Duration, error)
+}
+
+// wirePlatform registers a platform with the components that use what it
+// supports: trading, resolution, redemption and credential checks.
+func wirePlatform(p platform.Platform, manager *position.Manager, tracker *orders.Tracker, settler *settlement.Settler, dryRun bool) {
+	name := p.Name()
+	if canceller, ok := p.(position.OrderCanceller); ok {
+		manager.SetOrderCanceller(name, canceller)
+	}
+	if orderer, ok := p.(position.PlatformOrderer); ok {
+		manager.SetPlatformOrderer(name, orderer)
+	}
+	if sizer, ok := p.(position.LotSizer); ok {
+		manager.SetLotSize(name, sizer.LotSize())
+	}
+	if trader, ok := p.(platform.Trader); ok {
+		tracker.SetTrader(name, trader)
+	}
+	if resolver, ok := p.(settlement.Resolver); ok {
+		settler.SetResolver(name, resolver)
+	}
+	// Dry-run positions hold no tokens, so there is nothing to redeem
+	if redemption, ok := p.(redemptionPlatform); ok && !dryRun {
+		settler.SetRedemptionChecker(name, redemption)
+		if redemption.RedemptionEnabled() {
+			settler.SetRedeemer(name, redemption)
+			settler.SetGasReporter(name, redemption)
+		} else {
+			log.Warn().Str("platform", name).Msg("Redemption signer not set, winning payouts must be redeemed manually")
+		}
+	}
+	if checker, ok := p.(clockChecker); ok {
+		if skew, err := checker.CheckClockSkew(); err != nil {
+			log.Warn().Err(err).Str("platform", name).Msg("Failed to check clock skew")
+		} else {
+			log.Info().Dur("skew", skew).Str("platform", name).Msg("Clock skew checked")
+		}
+	}
+	if verifier, ok := p.(credentialVerifier); ok {
+		verifyCredentials(name, verifier, dryRun)
+	}
+}
+
+// apiLogRecorder records platform API calls in the api_log table, deleting
+// those older than retention (if set) at most hourly.
+type apiLogRecorder struct {
+	repo      *persistence.APILogRepository
+	retention time.Duration
+
+	mu     sync.Mutex
+	pruned time.Time
+}
+
+// RecordAPICall implements platform.APICallRecorder.
+func (r *apiLogRecorder) RecordAPICall(call platform.APICall) error {
+	var callErr string
+	if call.Err != nil {
+		callErr = call.Err.Error()
+	}
+	if _, err := r.repo.Record(&persistence.APICall{
+		API:          call.Platform,
+		Endpoint:     call.Endpoint,
+		Method:       call.Method,
+		StatusCode:   call.StatusCode,
+		Latency:      call.Latency,
+		Error:        callErr,
+		RequestBody:  call.RequestBody,
+		ResponseBody: call.ResponseBody,
+	}); err != nil {
+		return err
+	}
+
+	if r.retention <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.pruned) < time.Hour {
+		return nil
+	}
+	r.pruned = time.Now()
+	deleted, err := r.repo.DeleteBefore(time.Now().Add(-r.retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Debug().Int64("deleted", deleted).Msg("Pruned API calls past retention")
+	}
+	return nil
+}
+
+// credentialVerifier is a platform client that can check its credentials.
+type credentialVerifier interface {
+	VerifyCredentials() error
+}
+
+// verifyCredentials checks a platform's credentials at startup so a
+// misconfigured key is reported now rather than on the first live order.
+// Live trading stops on failure; dry-run only warns, since it places no
+// orders.
+func verifyCredentials(platformName string, client credentialVerifier, dryRun bool) {
+	err := client.VerifyCredentials()
+	if err == nil {
+		log.Info().Str("platform", platformName).Msg("Credentials verified")
+		return
+	}
+	if !dryRun {
+		log.Fatal().Err(err).Str("platform", platformName).Msg("Credential verification failed")
+	}
+	log.Warn().Err(err).Str("platform", platformName).Msg("Credential verification failed, live trading would be rejected")
+}
@@ -0,0 +1,1144 @@
+// Command botctl administers the bot through its database, whether or not
+// the bot is running.
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/audit"
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/dashboard"
+	"prediction-bot/internal/events"
+	"prediction-bot/internal/i18n"
+	"prediction-bot/internal/learning"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	_ "prediction-bot/internal/platform/adapters"
+	"prediction-bot/internal/platform/kalshi"
+	"prediction-bot/internal/platform/polymarket"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/report"
+	"prediction-bot/internal/scandiff"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/terminal"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const usage = `Usage: botctl [flags] <command> [arguments]
+
+Commands:
+  close-position <id> [-price X] [-live] [-yes]
+        Exit a position at the given or current market price, with reason
+        manual_exit. Dry-run by default: the exit is recorded without
+        placing a sell order.
+  set-volatility <asset> <volatility> [-reason text]
+        Override an asset's annualized volatility (e.g. 0.9 for 90%),
+        replacing the one the bot calculates.
+  clear-volatility <asset>
+        Remove an asset's volatility override.
+  backfill-vol -asset BTC[,ETH...] [-days 365]
+        Fetch and store each asset's hourly price history over the last
+        days and precompute its rolling volatility, so the first scans of
+        a fresh deployment have full history. Stock, FX and commodity
+        history needs ALPHAVANTAGE_API_KEY or POLYGON_API_KEY.
+  backfill-positions [-fetch]
+        Fill in metadata missing from positions recorded before the columns
+        holding it were added: entry and trade strategy, and the asset,
+        strike and direction parsed from the title. -fetch also asks each
+        platform for the outcome token and market close time.
+  actions [-limit N]
+        List the most recent operator actions, newest first.
+  balances [-platform P] [-limit N]
+        List the most recent reconciliations of each platform's bankroll
+        with the balance it reports, newest first: the discrepancy and
+        whether it matched, was recorded, adjusted or alerted.
+  events [-type entry,exit...] [-since T] [-until T] [-limit N]
+        List the bot's audit events (entry, exit, parameter_change,
+        parameter_revert, api_error, halt, resume), newest first. Times are
+        RFC 3339, a date (2006-01-02) or a duration ago (e.g. 24h).
+  export [-format csv|json] [-o file] [-platform P] [-since T] [-until T]
+        Export closed positions with their entry and exit metadata (safety
+        margin, volatility, skip and exit reasons, realized PnL) for
+        analysis in a spreadsheet or notebook, oldest exit first. Times
+        select by exit time, as for events.
+  report [-year N] [-platform P] [-ledger file] [-summary file]
+        Print the realized PnL of each platform by month and for the year
+        (the current one by default): trades, wins and losses, fees and
+        average holding time. -ledger writes a per-trade CSV of cost basis
+        and proceeds for tax filing; -summary writes the summaries as CSV.
+  scan-diff [-platform P] [-min-move X]
+        Compare each platform's latest scan with the one before: markets
+        newly eligible (+), no longer eligible and why (-), and YES price
+        moves of at least min-move (default 0.01) on markets eligible in
+        both (~).
+  dashboard [-socket path] [-log-file file]
+        Run the terminal dashboard on the bot's database, attached to the
+        running bot's activity over dashboard.socket: scans as they run,
+        eligible markets, entries and skips, and whether it trades live.
+  db doctor [-fix]
+        Check the schema version and data integrity: orphaned rows, unknown
+        position statuses, closed positions without an exit, open positions
+        on resolved markets. -fix applies the fixes that can't lose data.
+        Exits non-zero while problems remain, so it can run from cron.
+  db schema [-o file]
+        Export the database schema as SQL, stamped with its version.
+  experiment [-name N]
+        Compare the champion and challenger arms of a parameter experiment
+        (experiment.name by default): closed trades, win rate and realized
+        PnL of each.
+  alert-rules [-job name] [-o file]
+        Write Prometheus alerting rules for the bot's /metrics endpoint,
+        with the thresholds in alerts.thresholds, for deployments that
+        route alerts through Prometheus and Alertmanager.
+
+Every command that changes the bot's state is recorded as an operator
+action, with the OS user who ran it.
+
+Flags:
+`
+
+func main() {
+	// Parse CLI flags
+	configPath := flag.String("config", "config/config.yaml", "Path to config file")
+	migrationsDir := flag.String("migrations", "migrations", "Path to migrations directory")
+	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	noColor := flag.Bool("no-color", false, "Disable colors and Unicode symbols in logs")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Setup logging
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	if *verbose {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	plain := *noColor || terminal.Plain(os.Stderr)
+	var console io.Writer = os.Stderr
+	if plain {
+		console = terminal.NewASCIIWriter(os.Stderr)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: console, TimeFormat: time.RFC3339, NoColor: plain})
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	// Open the database shared with the bot
+	dbPath := cfg.Database.Path
+	if dbPath == "" {
+		dbPath = "bot.db"
+	}
+	db, err := persistence.OpenDB(dbPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", dbPath).Msg("Failed to open database")
+	}
+	defer db.Close()
+
+	// The db commands inspect the schema as it is
+	command := flag.Arg(0)
+	if command != "db" {
+		if err := persistence.RunMigrations(db, *migrationsDir); err != nil {
+			log.Fatal().Err(err).Msg("Failed to run migrations")
+		}
+	}
+
+	switch command {
+	case "close-position":
+		err = closePosition(cfg, db, flag.Args()[1:])
+	case "set-volatility":
+		err = setVolatility(db, flag.Args()[1:])
+	case "clear-volatility":
+		err = clearVolatility(db, flag.Args()[1:])
+	case "backfill-vol":
+		err = backfillVolatility(cfg, db, flag.Args()[1:])
+	case "backfill-positions":
+		err = backfillPositions(cfg, db, flag.Args()[1:])
+	case "actions":
+		err = listActions(db, flag.Args()[1:])
+	case "balances":
+		err = listBalanceChecks(db, flag.Args()[1:])
+	case "events":
+		err = listEvents(db, flag.Args()[1:])
+	case "export":
+		err = exportJournal(db, flag.Args()[1:])
+	case "report":
+		err = pnlReport(db, flag.Args()[1:])
+	case "scan-diff":
+		err = scanDiff(db, flag.Args()[1:])
+	case "dashboard":
+		err = attachDashboard(cfg, db, *noColor, flag.Args()[1:])
+	case "db":
+		err = dbCommand(db, *migrationsDir, flag.Args()[1:])
+	case "alert-rules":
+		err = alertRules(cfg, flag.Args()[1:])
+	case "experiment":
+		err = compareExperiment(cfg, db, flag.Args()[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Command failed")
+	}
+}
+
+// platformClient is what closing a position needs from a platform: the
+// current market price, and the orders to sell live.
+type platformClient interface {
+	position.PlatformOrderer
+	position.OrderCanceller
+	GetMarket(marketID string) (*types.Market, error)
+}
+
+// closePosition exits a position with ExitReasonManual.
+func closePosition(cfg *config.Config, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("close-position", flag.ExitOnError)
+	price := fs.Float64("price", 0, "Exit price (default: the current market price)")
+	live := fs.Bool("live", false, "Place a real sell order (REAL MONEY!) instead of recording a dry-run exit")
+	yes := fs.Bool("yes", false, "Skip the live confirmation prompt")
+	if err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: botctl close-position <id> [-price X] [-live] [-yes]")
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid position id %q", fs.Arg(0))
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	pos, err := posRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("get position: %w", err)
+	}
+	if pos == nil {
+		return fmt.Errorf("position %d not found", id)
+	}
+	if pos.Status != persistence.PositionStatusOpen {
+		return fmt.Errorf("position %d is %s, only open positions can be closed", id, pos.Status)
+	}
+
+	client, err := newPlatformClient(pos.Platform, *live)
+	if err != nil {
+		return err
+	}
+
+	exitPrice := *price
+	if exitPrice <= 0 {
+		if exitPrice, err = currentPrice(client, pos); err != nil {
+			return err
+		}
+	}
+
+	if *live && !*yes && !confirmClose(os.Stdout, pos, exitPrice) {
+		log.Info().Msg("Close cancelled by user")
+		return nil
+	}
+
+	// The manager only needs its repositories to exit a position, and audits
+	// the exit as the bot would
+	manager := position.NewManager(posRepo, persistence.NewBankrollRepository(db), nil, nil)
+	manager.SetTradeEvents(audit.NewRecorder(persistence.NewEventRepository(db)))
+	if *live {
+		manager.SetPlatformOrderer(pos.Platform, client)
+		manager.SetOrderCanceller(pos.Platform, client)
+	} else {
+		manager.SetDryRunSimulation(position.DryRunSimulation{FeeRates: cfg.DryRun.FeeRates})
+	}
+
+	result, err := manager.ExecuteExit(context.Background(), pos.ID, exitPrice, position.ExitReasonManual, !*live)
+	if err != nil {
+		return fmt.Errorf("close position %d: %w", id, err)
+	}
+
+	event := log.Info()
+	if result.RemainingQuantity > 0 {
+		event = log.Warn()
+	}
+	event.
+		Int64("position_id", result.PositionID).
+		Str("market", pos.MarketTitle).
+		Float64("exit_price", result.ExitPrice).
+		Float64("quantity", result.Quantity).
+		Float64("remaining", result.RemainingQuantity).
+		Float64("realized_pnl", result.RealizedPnL).
+		Str("order_id", result.OrderID).
+		Bool("dry_run", !*live).
+		Msg("Position closed manually")
+
+	details := fmt.Sprintf("exit %.2f contracts at %.3f, realized PnL %.2f", result.Quantity, result.ExitPrice, result.RealizedPnL)
+	if !*live {
+		details += ", dry run"
+	}
+	return recordAction(db, persistence.OperatorActionClosePosition, fmt.Sprintf("position %d", pos.ID), details)
+}
+
+// setVolatility overrides an asset's annualized volatility.
+func setVolatility(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("set-volatility", flag.ExitOnError)
+	reason := fs.String("reason", "", "Why the volatility is overridden")
+	if err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: botctl set-volatility <asset> <volatility> [-reason text]")
+	}
+	asset := strings.ToUpper(fs.Arg(0))
+	volatility, err := strconv.ParseFloat(fs.Arg(1), 64)
+	if err != nil || volatility <= 0 {
+		return fmt.Errorf("invalid volatility %q, want a positive annualized fraction such as 0.9", fs.Arg(1))
+	}
+
+	overrides := persistence.NewVolatilityOverrideRepository(db)
+	previous, err := overrides.Get(asset)
+	if err != nil {
+		return err
+	}
+	if err := overrides.Set(asset, volatility, *reason); err != nil {
+		return err
+	}
+	recordParameterChange(db, asset, previous, volatility, *reason)
+	log.Info().
+		Str("asset", asset).
+		Float64("volatility", volatility).
+		Str("reason", *reason).
+		Msg("Volatility override set")
+
+	details := fmt.Sprintf("volatility %.4f", volatility)
+	if *reason != "" {
+		details += ": " + *reason
+	}
+	return recordAction(db, persistence.OperatorActionSetVolatility, asset, details)
+}
+
+// clearVolatility removes an asset's volatility override.
+func clearVolatility(db *sql.DB, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: botctl clear-volatility <asset>")
+	}
+	asset := strings.ToUpper(args[0])
+
+	overrides := persistence.NewVolatilityOverrideRepository(db)
+	previous, err := overrides.Get(asset)
+	if err != nil {
+		return err
+	}
+	if err := overrides.Delete(asset); err != nil {
+		return err
+	}
+	if previous != nil {
+		recordParameterChange(db, asset, previous, nil, "cleared")
+	}
+	log.Info().Str("asset", asset).Msg("Volatility override cleared")
+
+	return recordAction(db, persistence.OperatorActionClearVolatility, asset, "")
+}
+
+// backfillVolatility backfills the price history and rolling volatility of
+// each asset.
+func backfillVolatility(cfg *config.Config, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("backfill-vol", flag.ExitOnError)
+	assets := fs.String("asset", "", "Comma-separated assets to backfill (e.g. BTC,ETH)")
+	days := fs.Int("days", 365, "Days of history to backfill")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *assets == "" || *days <= 0 {
+		return fmt.Errorf("usage: botctl backfill-vol -asset BTC[,ETH...] [-days 365]")
+	}
+
+	volService := volatility.NewService(os.Getenv("ALPHAVANTAGE_API_KEY"))
+	if polygonKey := os.Getenv("POLYGON_API_KEY"); polygonKey != "" {
+		volService.SetPolygonKey(polygonKey)
+	}
+	for asset, a := range cfg.Volatility.Assets {
+		volService.SetAssetConfig(asset, volatility.AssetConfig{
+			AnnualizationDays: a.AnnualizationDays,
+			MinVolatility:     a.MinVolatility,
+			MaxVolatility:     a.MaxVolatility,
+			Override:          a.Override,
+		})
+	}
+	volService.SetPriceHistoryRepository(persistence.NewPriceHistoryRepository(db))
+	rolling := persistence.NewRollingVolatilityRepository(db)
+
+	var failed int
+	for _, asset := range strings.Split(*assets, ",") {
+		asset = strings.TrimSpace(asset)
+		if asset == "" {
+			continue
+		}
+		result, err := volService.Backfill(asset, *days, rolling)
+		if err != nil {
+			log.Error().Err(err).Str("asset", asset).Msg("Backfill failed")
+			failed++
+			continue
+		}
+
+		event := log.Info().
+			Str("asset", result.Asset).
+			Int("fetched", result.Fetched).
+			Int("stored", result.Stored).
+			Time("first", result.First).
+			Time("last", result.Last).
+			Int("volatility_days", result.Days)
+		for _, term := range result.Latest {
+			event = event.Float64(fmt.Sprintf("vol_%dd", int(term.Horizon.Hours()/24)), term.Volatility)
+		}
+		event.Msg("Volatility backfilled")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d assets failed to backfill", failed)
+	}
+	return nil
+}
+
+// backfillPositions enriches legacy positions with the metadata that can be
+// recovered for them.
+func backfillPositions(cfg *config.Config, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("backfill-positions", flag.ExitOnError)
+	fetch := fs.Bool("fetch", false, "Fetch tokens and close times from the platforms")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Titles are parsed with the bot's rules
+	if cfg.Scan.ParserRules != "" {
+		rules, err := scanner.LoadRules(cfg.Scan.ParserRules)
+		if err != nil {
+			return fmt.Errorf("load scan.parser_rules: %w", err)
+		}
+		if err := scanner.SetRules(rules); err != nil {
+			return fmt.Errorf("invalid scan.parser_rules: %w", err)
+		}
+	}
+
+	backfiller := position.NewBackfiller(persistence.NewPositionRepository(db))
+	if *fetch {
+		for _, name := range cfg.EnabledPlatforms() {
+			client, err := newPlatformClient(name, false)
+			if err != nil {
+				log.Warn().Err(err).Str("platform", name).Msg("Skipping platform")
+				continue
+			}
+			backfiller.SetMarketGetter(name, client)
+		}
+	}
+
+	result, err := backfiller.Run()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backfilled positions: %s\n", result)
+	if result.Defaults > 0 || result.Parsed > 0 || result.Tokens > 0 || result.CloseTimes > 0 {
+		return recordAction(db, "backfill_positions", "positions", result.String())
+	}
+	return nil
+}
+
+// listActions prints the most recent operator actions.
+func listActions(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("actions", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "Number of actions to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	actions, err := persistence.NewOperatorActionRepository(db).GetRecent(*limit)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		fmt.Println("No operator actions recorded")
+		return nil
+	}
+	for _, a := range actions {
+		fmt.Printf("%s  %-16s %-18s %-12s %s\n",
+			a.CreatedAt.UTC().Format("2006-01-02 15:04:05"), a.Actor, a.Action, a.Target, a.Details)
+	}
+	return nil
+}
+
+// listBalanceChecks prints the most recent balance checks.
+func listBalanceChecks(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("balances", flag.ExitOnError)
+	platformName := fs.String("platform", "", "List checks of only this platform")
+	limit := fs.Int("limit", 20, "Number of checks to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	checks, err := persistence.NewBalanceCheckRepository(db).GetRecent(*platformName, *limit)
+	if err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		fmt.Println("No balance checks recorded")
+		return nil
+	}
+	for _, c := range checks {
+		fmt.Printf("%s  %-12s bankroll %10.2f  balance %10.2f  %+9.2f  %s\n",
+			c.CreatedAt.UTC().Format("2006-01-02 15:04:05"), c.Platform, c.Bankroll, c.Balance, c.Discrepancy(), c.Action)
+	}
+	return nil
+}
+
+// listEvents prints the audit events matching the given type and time
+// range.
+func listEvents(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	eventTypes := fs.String("type", "", "Comma-separated event types to list (default all)")
+	since := fs.String("since", "", "List events at or after this time")
+	until := fs.String("until", "", "List events before this time")
+	limit := fs.Int("limit", 50, "Number of events to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := persistence.EventFilter{Limit: *limit}
+	for _, t := range strings.Split(*eventTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			filter.Types = append(filter.Types, t)
+		}
+	}
+	now := time.Now()
+	var err error
+	if filter.Since, err = parseTime(*since, now); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if filter.Until, err = parseTime(*until, now); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	events, err := persistence.NewEventRepository(db).Query(filter)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		fmt.Println("No events recorded")
+		return nil
+	}
+	for _, e := range events {
+		target := e.Platform
+		if e.MarketID != "" {
+			target += " " + e.MarketID
+		}
+		if e.PositionID != nil {
+			target = strings.TrimSpace(fmt.Sprintf("%s position %d", target, *e.PositionID))
+		}
+		fmt.Printf("%s  %-16s %-28s %s\n",
+			e.CreatedAt.UTC().Format("2006-01-02 15:04:05"), e.Type, target, e.Details)
+	}
+	return nil
+}
+
+// parseTime parses a time given as RFC 3339, a date or a duration before
+// now. Empty is the zero time.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ago, err := time.ParseDuration(value); err == nil {
+		return now.Add(-ago), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time, a date or a duration", value)
+}
+
+// exportJournal writes the closed positions matching the given platform and
+// exit time range as CSV or JSON.
+func exportJournal(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "csv", "Output format: csv or json")
+	output := fs.String("o", "", "Write the export to this file instead of stdout")
+	platformName := fs.String("platform", "", "Export only this platform's positions")
+	since := fs.String("since", "", "Export positions exited at or after this time")
+	until := fs.String("until", "", "Export positions exited before this time")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var write func(io.Writer, []*persistence.JournalEntry) error
+	switch *format {
+	case "csv":
+		write = writeJournalCSV
+	case "json":
+		write = writeJournalJSON
+	default:
+		return fmt.Errorf("unknown format %q, want csv or json", *format)
+	}
+
+	filter := persistence.JournalFilter{Platform: *platformName}
+	now := time.Now()
+	var err error
+	if filter.Since, err = parseTime(*since, now); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if filter.Until, err = parseTime(*until, now); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	entries, err := persistence.NewPositionRepository(db).GetJournal(filter)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return write(os.Stdout, entries)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("create export file: %w", err)
+	}
+	if err := write(f, entries); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.Info().Int("positions", len(entries)).Str("path", *output).Msg("Trade journal exported")
+	return nil
+}
+
+// writeJournalCSV writes journal entries as CSV with a header row. Skip
+// reasons are joined with semicolons, and unknown times are left empty.
+func writeJournalCSV(out io.Writer, entries []*persistence.JournalEntry) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{
+		"position_id", "platform", "market_id", "market_title", "outcome", "asset",
+		"strike", "strike_upper", "direction", "side", "trade_strategy", "entry_strategy",
+		"entry_time", "exit_time", "market_close_time", "entry_price", "exit_price",
+		"quantity", "fees", "realized_pnl", "safety_margin_at_entry", "volatility_at_entry",
+		"exit_reason", "skip_reasons",
+	})
+	for _, e := range entries {
+		w.Write([]string{
+			strconv.FormatInt(e.PositionID, 10),
+			e.Platform,
+			e.MarketID,
+			e.MarketTitle,
+			e.Outcome,
+			e.Asset,
+			formatFloat(e.Strike),
+			formatFloat(e.StrikeUpper),
+			e.Direction,
+			e.Side,
+			e.TradeStrategy,
+			e.EntryStrategy,
+			formatTime(&e.EntryTime),
+			formatTime(e.ExitTime),
+			formatTime(e.MarketCloseTime),
+			formatFloat(e.EntryPrice),
+			formatFloat(e.ExitPrice),
+			formatFloat(e.Quantity),
+			formatFloat(e.Fees),
+			formatFloat(e.RealizedPnL),
+			formatFloat(e.SafetyMarginAtEntry),
+			formatFloat(e.VolatilityAtEntry),
+			e.ExitReason,
+			strings.Join(e.SkipReasons, ";"),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeJournalJSON writes journal entries as an indented JSON array.
+func writeJournalJSON(out io.Writer, entries []*persistence.JournalEntry) error {
+	if entries == nil {
+		entries = []*persistence.JournalEntry{}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(entries); err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// pnlReport prints a year's realized PnL summaries and optionally writes
+// its ledger and summaries as CSV.
+func pnlReport(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	year := fs.Int("year", time.Now().UTC().Year(), "Year to report on")
+	platformName := fs.String("platform", "", "Report only this platform's trades")
+	ledger := fs.String("ledger", "", "Write the per-trade ledger to this CSV file")
+	summary := fs.String("summary", "", "Write the monthly and yearly summaries to this CSV file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	start := time.Date(*year, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries, err := persistence.NewPositionRepository(db).GetJournal(persistence.JournalFilter{
+		Platform: *platformName,
+		Since:    start,
+		Until:    start.AddDate(1, 0, 0),
+	})
+	if err != nil {
+		return err
+	}
+	r := report.Build(entries)
+
+	if len(r.Trades) == 0 {
+		fmt.Printf("No trades closed in %d\n", *year)
+	} else {
+		fmt.Printf("%-8s %-12s %6s %5s %6s %12s %14s %10s\n",
+			"PERIOD", "PLATFORM", "TRADES", "WINS", "LOSSES", "FEES", "REALIZED PNL", "AVG HOLD")
+		for _, s := range append(r.Monthly, r.Yearly...) {
+			fmt.Printf("%-8s %-12s %6d %5d %6d %12.2f %14.2f %9.1fh\n",
+				s.Period, s.Platform, s.Trades, s.Wins, s.Losses, s.Fees, s.RealizedPnL, s.AvgHolding.Hours())
+		}
+	}
+
+	if *ledger != "" {
+		if err := writeCSVFile(*ledger, func(w io.Writer) error { return report.WriteLedgerCSV(w, r.Trades) }); err != nil {
+			return err
+		}
+	}
+	if *summary != "" {
+		summaries := append(r.Monthly, r.Yearly...)
+		if err := writeCSVFile(*summary, func(w io.Writer) error { return report.WriteSummaryCSV(w, summaries) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachDashboard runs the terminal dashboard on the database, showing the
+// activity of the bot streaming it on its socket.
+func attachDashboard(cfg *config.Config, db *sql.DB, noColor bool, args []string) error {
+	fs := flag.NewFlagSet("dashboard", flag.ExitOnError)
+	socket := fs.String("socket", cfg.Dashboard.Socket, "Socket the bot streams its activity on")
+	logFile := fs.String("log-file", "dashboard.log", "File logs are written to, since the dashboard uses the terminal")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *socket == "" {
+		return fmt.Errorf("no socket to attach to: set dashboard.socket or pass -socket")
+	}
+	lang, err := i18n.ParseLanguage(cfg.Locale.Language)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	activity, err := events.Dial(ctx, *socket, 100)
+	if err != nil {
+		return fmt.Errorf("attach to the bot (is it running?): %w", err)
+	}
+
+	f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	defer f.Close()
+	log.Logger = log.Output(f)
+
+	// Open positions are priced at entry: the bot holds the platform clients
+	provider := dashboard.NewDBDataProvider(persistence.NewBankrollRepository(db), persistence.NewPositionRepository(db), nil)
+	provider.SetCostRepository(persistence.NewCostRepository(db))
+	provider.SetArbitrageRepository(persistence.NewArbitrageRepository(db))
+	provider.SetPriceHistoryRepository(persistence.NewPriceHistoryRepository(db))
+	provider.SetScanSnapshotRepository(persistence.NewScanSnapshotRepository(db))
+	provider.SetAPILogRepository(persistence.NewAPILogRepository(db))
+
+	// Dry-run until the bot reports its mode on attaching
+	app := dashboard.NewAppWithProvider(provider, true)
+	app.SetCurrencyDecimals(cfg.Precision.DisplayDecimals)
+	app.SetLanguage(lang)
+	app.SetPlain(noColor || terminal.Plain(os.Stdout))
+	app.SetEvents(activity)
+	return app.Run()
+}
+
+// scanDiff prints how each platform's opportunity set changed between its
+// two latest scans.
+func scanDiff(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("scan-diff", flag.ExitOnError)
+	platformName := fs.String("platform", "", "Diff only this platform's scans")
+	minMove := fs.Float64("min-move", scandiff.DefaultMinPriceMove, "Smallest YES price move to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	diffs, err := scandiff.Latest(persistence.NewScanSnapshotRepository(db), *minMove)
+	if err != nil {
+		return err
+	}
+	printed := false
+	for _, d := range diffs {
+		if *platformName != "" && d.Platform != *platformName {
+			continue
+		}
+		printed = true
+		fmt.Printf("%s: %s -> %s, %d changes\n", d.Platform,
+			d.Previous.UTC().Format("2006-01-02 15:04:05"), d.Current.UTC().Format("2006-01-02 15:04:05"), len(d.Changes))
+		for _, c := range d.Changes {
+			market := c.MarketID
+			if c.Outcome != "" {
+				market += " " + c.Outcome
+			}
+			var detail string
+			switch c.Kind {
+			case scandiff.KindEligible:
+				detail = "+ " + c.Reason
+			case scandiff.KindIneligible:
+				detail = "- " + c.Reason
+			case scandiff.KindPrice:
+				detail = fmt.Sprintf("~ %.2f -> %.2f (%+.2f)", c.PreviousPrice, c.Price, c.Move())
+			}
+			if c.PreviousReason != "" && c.Kind != scandiff.KindPrice {
+				detail += " (was " + c.PreviousReason + ")"
+			}
+			fmt.Printf("  %-28s %-40s %s\n", market, detail, c.Title)
+		}
+	}
+	if !printed {
+		fmt.Println("Fewer than two scans recorded")
+	}
+	return nil
+}
+
+// compareExperiment prints the performance of each arm of a parameter
+// experiment.
+func compareExperiment(cfg *config.Config, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("experiment", flag.ExitOnError)
+	name := fs.String("name", cfg.Experiment.Name, "Experiment to compare")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("no experiment configured, pass -name")
+	}
+
+	outcomes, err := learning.NewCollector(db).CollectExperiment(*name)
+	if err != nil {
+		return err
+	}
+	arms := learning.NewAnalyzer().CompareArms(outcomes, *name)
+	fmt.Printf("%-12s %6s %5s %8s %14s %10s\n", "ARM", "TRADES", "WINS", "WIN RATE", "REALIZED PNL", "AVG PNL")
+	for _, arm := range arms {
+		fmt.Printf("%-12s %6d %5d %7.1f%% %14.2f %10.2f\n",
+			arm.Arm, arm.TradeCount, arm.WinCount, arm.WinRate*100, arm.TotalPnL, arm.AvgPnL)
+	}
+	if learning.ChallengerLeads(arms) {
+		fmt.Println("The challenger leads: consider promoting its parameters.")
+	}
+	return nil
+}
+
+// writeCSVFile creates path and writes it with write.
+func writeCSVFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.Info().Str("path", path).Msg("Report written")
+	return nil
+}
+
+// dbCommand runs a database maintenance subcommand.
+func dbCommand(db *sql.DB, migrationsDir string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: botctl db <doctor|schema> [flags]")
+	}
+	switch args[0] {
+	case "doctor":
+		return dbDoctor(db, migrationsDir, args[1:])
+	case "schema":
+		return dbSchema(db, args[1:])
+	default:
+		return fmt.Errorf("unknown db command %q, want doctor or schema", args[0])
+	}
+}
+
+// dbDoctor reports the database's integrity problems and, with -fix,
+// applies the safe fixes. Fails while any problem remains.
+func dbDoctor(db *sql.DB, migrationsDir string, args []string) error {
+	fs := flag.NewFlagSet("db doctor", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "Apply the fixes that can't lose data")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	doctor := persistence.NewDoctor(db, migrationsDir)
+	findings, err := doctor.Diagnose()
+	if err != nil {
+		return fmt.Errorf("diagnose database: %w", err)
+	}
+	version, err := persistence.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Schema version %d\n", version)
+	if len(findings) == 0 {
+		fmt.Println("No problems found")
+		return nil
+	}
+
+	var fixable int
+	for _, f := range findings {
+		action := "needs attention"
+		if f.Fix != "" {
+			action = "fix: " + f.Fix
+		}
+		if f.Fixable() {
+			fixable++
+			if !*fix {
+				action += " (run with -fix)"
+			}
+		}
+		fmt.Printf("%-26s %s\n%-26s %s\n", f.Check, f.Detail, "", action)
+	}
+
+	remaining := len(findings)
+	if *fix && fixable > 0 {
+		fixed, err := doctor.Fix(findings)
+		if err != nil {
+			return err
+		}
+		log.Info().Int("fixed", fixed).Msg("Database fixes applied")
+		remaining -= fixed
+		if err := recordAction(db, persistence.OperatorActionDatabaseFix, "database",
+			fmt.Sprintf("applied %d doctor fixes", fixed)); err != nil {
+			return err
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%d database problems remain", remaining)
+	}
+	return nil
+}
+
+// dbSchema exports the schema to a file or stdout.
+func dbSchema(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("db schema", flag.ExitOnError)
+	output := fs.String("o", "", "Write the schema to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return persistence.ExportSchema(db, os.Stdout, time.Now())
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("create schema file: %w", err)
+	}
+	if err := persistence.ExportSchema(db, f, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// alertRules writes Prometheus alerting rules matching the configured alert
+// thresholds to a file or stdout.
+func alertRules(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("alert-rules", flag.ExitOnError)
+	job := fs.String("job", "prediction-bot", "Prometheus job that scrapes the bot's /metrics")
+	output := fs.String("o", "", "Write the rules to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t := cfg.Alerts.Thresholds
+	thresholds := alert.Thresholds{
+		ScanStale:    t.ScanStale(),
+		MaxErrorRate: t.MaxErrorRate,
+		ErrorWindow:  t.ErrorWindow(),
+		MaxDrawdown:  t.MaxDrawdown,
+	}
+	if *output == "" {
+		return alert.WritePrometheusRules(os.Stdout, thresholds, *job)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("create rules file: %w", err)
+	}
+	if err := alert.WritePrometheusRules(f, thresholds, *job); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// recordAction records a change the operator made to the bot's state. The
+// change has already been made, so a failure to record it is reported but
+// not undone.
+func recordAction(db *sql.DB, action, target, details string) error {
+	_, err := persistence.NewOperatorActionRepository(db).Record(&persistence.OperatorAction{
+		Actor:   operator(),
+		Action:  action,
+		Target:  target,
+		Details: details,
+	})
+	return err
+}
+
+// recordParameterChange records a change to an asset's volatility override
+// in the audit log, from its previous override (nil if none) to
+// volatility (nil if cleared).
+func recordParameterChange(db *sql.DB, asset string, previous *persistence.VolatilityOverride, volatility interface{}, reason string) {
+	var from interface{}
+	if previous != nil {
+		from = previous.Volatility
+	}
+	audit.NewRecorder(persistence.NewEventRepository(db)).
+		ParameterChange("volatility_override:"+asset, from, volatility, reason)
+}
+
+// operator identifies the OS user running botctl.
+func operator() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return "os:" + u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return "os:" + name
+	}
+	return "os:unknown"
+}
+
+// newPlatformClient returns the client for a platform. Live exits need
+// credentials; dry-run exits only read public market prices. Platforms
+// other than Polymarket and Kalshi come from the registry.
+func newPlatformClient(platformName string, live bool) (platformClient, error) {
+	switch platformName {
+	case "polymarket":
+		if !live {
+			return polymarket.NewClientWithCreds(polymarket.Credentials{}), nil
+		}
+		client, err := polymarket.NewClient()
+		if err != nil {
+			return nil, fmt.Errorf("initialize polymarket client: %w", err)
+		}
+		return client, nil
+	case "kalshi":
+		if !live {
+			return kalshi.NewClientWithCreds(kalshi.Credentials{}), nil
+		}
+		client, err := kalshi.NewClient()
+		if err != nil {
+			return nil, fmt.Errorf("initialize kalshi client: %w", err)
+		}
+		return client, nil
+	default:
+		p, err := platform.New(platformName, platform.Options{DryRun: !live})
+		if err != nil {
+			return nil, err
+		}
+		client, ok := p.(platformClient)
+		if !ok {
+			return nil, fmt.Errorf("platform %q can't close positions", platformName)
+		}
+		return client, nil
+	}
+}
+
+// currentPrice returns the market's current price for the position's side,
+// on the outcome it trades if the market has several.
+func currentPrice(client platformClient, pos *persistence.Position) (float64, error) {
+	market, err := client.GetMarket(pos.MarketID)
+	if err != nil {
+		return 0, fmt.Errorf("get current price: %w", err)
+	}
+	for _, outcome := range market.OutcomeMarkets() {
+		if outcome.Outcome == pos.Outcome {
+			market = &outcome
+			break
+		}
+	}
+
+	price := market.OutcomeYesPrice
+	if strings.EqualFold(pos.Side, "NO") {
+		price = market.OutcomeNoPrice
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("no current price quoted for %s, pass -price", pos.MarketID)
+	}
+	return price, nil
+}
+
+// parseInterspersed parses flags that may come before or after positional
+// arguments, so both "close-position 7 -price 0.5" and
+// "close-position -price 0.5 7" work.
+func parseInterspersed(fs *flag.FlagSet, args []string) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	return fs.Parse(append([]string{"--"}, positional...))
+}
+
+// confirmClose asks the user to confirm a live exit, written to out.
+func confirmClose(out io.Writer, pos *persistence.Position, price float64) bool {
+	fmt.Fprintf(out, "Close position %d (%s %s, %.2f contracts) on %s at %.3f with a REAL sell order?\n",
+		pos.ID, pos.Side, pos.MarketTitle, pos.Quantity, pos.Platform, price)
+	fmt.Fprint(out, "Type 'yes' to confirm: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(strings.ToLower(response)) == "yes"
+}
@@ -0,0 +1,161 @@
+// Command optimize grid-searches trading parameters against the backtest
+// engine with walk-forward validation, and writes the recommended set and an
+// overfitting report.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/optimize"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/terminal"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	// Parse CLI flags
+	configPath := flag.String("config", "config/config.yaml", "Path to config file")
+	snapshotsPath := flag.String("snapshots", "", "Path to a snapshot JSONL file or directory (required)")
+	from := flag.String("from", "", "Start date (YYYY-MM-DD), inclusive")
+	to := flag.String("to", "", "End date (YYYY-MM-DD), inclusive")
+	migrationsDir := flag.String("migrations", "migrations", "Path to migrations directory")
+	allowRisky := flag.Bool("allow-risky", false, "Allow entries with a risky volatility recommendation")
+	folds := flag.Int("folds", 4, "Number of walk-forward folds")
+	objective := flag.String("objective", "return", "What to maximize: return or sharpe")
+	ranges := flag.String("ranges", "", "Comma-separated name=min:max:step ranges to search instead of the defaults")
+	workers := flag.Int("workers", runtime.NumCPU(), "Backtests to run at a time")
+	out := flag.String("o", "optimized.yaml", "Write the recommended parameters to this file")
+	reportPath := flag.String("report", "", "Write the overfitting report to this file instead of stdout")
+	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	noColor := flag.Bool("no-color", false, "Disable colors and Unicode symbols in logs")
+	flag.Parse()
+
+	// Setup logging
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	if *verbose {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	plain := *noColor || terminal.Plain(os.Stderr)
+	var console io.Writer = os.Stderr
+	if plain {
+		console = terminal.NewASCIIWriter(os.Stderr)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: console, TimeFormat: time.RFC3339, NoColor: plain})
+
+	if *snapshotsPath == "" {
+		log.Fatal().Msg("-snapshots is required")
+	}
+	score, ok := optimize.Objectives[*objective]
+	if !ok {
+		log.Fatal().Str("objective", *objective).Msg("Unknown -objective, expected return or sharpe")
+	}
+	searched := optimize.DefaultRanges()
+	if *ranges != "" {
+		searched = nil
+		for _, s := range strings.Split(*ranges, ",") {
+			r, err := optimize.ParseRange(strings.TrimSpace(s))
+			if err != nil {
+				log.Fatal().Err(err).Msg("Invalid -ranges")
+			}
+			searched = append(searched, r)
+		}
+	}
+
+	start, err := parseDate(*from)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid -from date")
+	}
+	end, err := parseDate(*to)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid -to date")
+	}
+	if !end.IsZero() {
+		// Include the whole end day
+		end = end.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	// Parse titles as the live bot does
+	if cfg.Scan.ParserRules != "" {
+		rules, err := scanner.LoadRules(cfg.Scan.ParserRules)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load scan.parser_rules")
+		}
+		if err := scanner.SetRules(rules); err != nil {
+			log.Fatal().Err(err).Msg("Invalid scan.parser_rules")
+		}
+	}
+
+	snapshots, err := backtest.LoadSnapshots(*snapshotsPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load snapshots")
+	}
+	snapshots = backtest.FilterRange(snapshots, start, end)
+	if len(snapshots) == 0 {
+		log.Fatal().Msg("No snapshots in the requested date range")
+	}
+
+	base := backtest.FromConfig(cfg, *migrationsDir)
+	base.AllowRisky = *allowRisky
+	optimizer := optimize.NewOptimizer(cfg.Parameters, searched, optimize.BacktestEvaluator(base), score, *workers)
+	result, err := optimizer.Run(snapshots, *folds)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Optimization failed")
+	}
+	if len(result.Folds) == 0 {
+		log.Warn().Msg("Too few snapshots for any walk-forward fold, the recommendation is fit on all history unvalidated")
+	}
+
+	var report io.Writer = os.Stdout
+	if *reportPath != "" {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create report file")
+		}
+		defer f.Close()
+		report = f
+	}
+	optimize.WriteReport(report, result, *objective)
+
+	if err := writeParameters(*out, result, *objective); err != nil {
+		log.Fatal().Err(err).Msg("Failed to write recommended parameters")
+	}
+	fmt.Fprintf(os.Stderr, "Recommended parameters written to %s\n", *out)
+}
+
+// parseDate parses a YYYY-MM-DD date in UTC. An empty string returns the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateLayout, value, time.UTC)
+}
+
+// writeParameters writes the recommended parameters to a YAML file.
+func writeParameters(path string, result *optimize.Result, objective string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if err := optimize.WriteParameters(f, result, objective); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
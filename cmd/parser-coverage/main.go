@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/platform"
+	"prediction-bot/internal/platform/kalshi"
+	"prediction-bot/internal/platform/polymarket"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/terminal"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	// Parse CLI flags
+	platformName := flag.String("platform", "", "Only list markets on this platform (polymarket or kalshi)")
+	limit := flag.Int("limit", 500, "Maximum number of active markets listed per platform")
+	samples := flag.Int("samples", 5, "Unparsed titles shown per category")
+	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	noColor := flag.Bool("no-color", false, "Disable colors and Unicode symbols in logs and the report")
+	rulesPath := flag.String("rules", "", "YAML file overriding the built-in parser rules, to measure them before use")
+	flag.Parse()
+
+	// Setup logging
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	if *verbose {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	plain := *noColor || terminal.Plain(os.Stderr)
+	var console io.Writer = os.Stderr
+	if plain {
+		console = terminal.NewASCIIWriter(os.Stderr)
+	}
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: console, TimeFormat: time.RFC3339, NoColor: plain})
+
+	if *rulesPath != "" {
+		rules, err := scanner.LoadRules(*rulesPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load parser rules")
+		}
+		if err := scanner.SetRules(rules); err != nil {
+			log.Fatal().Err(err).Msg("Invalid parser rules")
+		}
+	}
+
+	// Listing markets is public, so no credentials are needed
+	platforms := []platform.Platform{
+		polymarket.NewClientWithCreds(polymarket.Credentials{}),
+		kalshi.NewClientWithCreds(kalshi.Credentials{}),
+	}
+
+	isActive := true
+	var markets []types.Market
+	for _, p := range platforms {
+		if *platformName != "" && p.Name() != *platformName {
+			continue
+		}
+		listed, err := p.ListMarkets(context.Background(), types.MarketFilter{IsActive: &isActive, Limit: *limit})
+		if err != nil {
+			log.Error().Err(err).Str("platform", p.Name()).Msg("Failed to list markets")
+			continue
+		}
+		markets = append(markets, listed...)
+	}
+	if len(markets) == 0 {
+		log.Fatal().Msg("No markets listed")
+	}
+
+	var out io.Writer = os.Stdout
+	if *noColor || terminal.Plain(os.Stdout) {
+		out = terminal.NewASCIIWriter(os.Stdout)
+	}
+	printCoverage(out, scanner.ParseCoverage(markets), *samples)
+}
+
+// printCoverage prints the parse rate per platform and category, a total
+// per platform, and up to samples unparsed titles per category.
+func printCoverage(out io.Writer, coverage []scanner.Coverage, samples int) {
+	fmt.Fprintf(out, "%-12s %-24s %7s %7s %7s\n", "PLATFORM", "CATEGORY", "PARSED", "TOTAL", "RATE")
+
+	totals := make(map[string]*scanner.Coverage)
+	var order []string
+	for _, c := range coverage {
+		fmt.Fprintf(out, "%-12s %-24.24s %7d %7d %6.1f%%\n", c.Platform, c.Category, c.Parsed, c.Total, c.Rate()*100)
+
+		total, ok := totals[c.Platform]
+		if !ok {
+			total = &scanner.Coverage{Platform: c.Platform, Category: "all"}
+			totals[c.Platform] = total
+			order = append(order, c.Platform)
+		}
+		total.Parsed += c.Parsed
+		total.Total += c.Total
+	}
+
+	fmt.Fprintln(out)
+	for _, name := range order {
+		t := totals[name]
+		fmt.Fprintf(out, "%-12s %-24s %7d %7d %6.1f%%\n", t.Platform, t.Category, t.Parsed, t.Total, t.Rate()*100)
+	}
+
+	if samples <= 0 {
+		return
+	}
+	for _, c := range coverage {
+		if len(c.Unparsed) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\nUnparsed %s / %s:\n", c.Platform, c.Category)
+		shown := c.Unparsed
+		if len(shown) > samples {
+			shown = shown[:samples]
+		}
+		for _, title := range shown {
+			fmt.Fprintf(out, "  %s\n", strings.TrimSpace(title))
+		}
+		if more := len(c.Unparsed) - len(shown); more > 0 {
+			fmt.Fprintf(out, "  ... and %d more\n", more)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+//go:build e2e
+
+// Package e2e runs the bot end to end against the mock exchange: real
+// scanner, position manager, monitor, order tracker and settler on a fresh
+// database, with only the exchange and volatility data faked. Run with
+// scripts/e2e.sh or `go test -tags e2e ./e2e/...`.
+package e2e
+
+import (
+	"context"
+	"math"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/bot"
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	"prediction-bot/internal/platform/mockexchange"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/settlement"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+// stubVolatility reports every market as a safe bet, standing in for the
+// price feeds the volatility service downloads.
+type stubVolatility struct{}
+
+func (stubVolatility) AnalyzeAsset(
+	ctx context.Context,
+	asset string,
+	strikePrice float64,
+	direction volatility.Direction,
+	timeToClose time.Duration,
+) (volatility.ServiceResult, error) {
+	return volatility.ServiceResult{
+		SafetyMargin:   2.0,
+		Volatility:     0.5,
+		Recommendation: volatility.RecommendationValid,
+	}, nil
+}
+
+// waitFor polls until cond holds, failing the test after timeout.
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// TestSmoke_EntryThenStopLoss runs the bot live against the mock exchange
+// while an eligible market appears and its price then crashes through the
+// stop, and checks what the bot left in the database.
+func TestSmoke_EntryThenStopLoss(t *testing.T) {
+	db, err := persistence.OpenDB(filepath.Join(t.TempDir(), "e2e.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	exchange := mockexchange.NewServer(100.0)
+	ts := httptest.NewServer(exchange)
+	defer ts.Close()
+	client := mockexchange.NewClient(ts.URL)
+
+	// Assemble the bot as cmd/bot does for a live run
+	params := config.Parameters{
+		ProbabilityThreshold:   0.80,
+		VolatilitySafetyMargin: 1.5,
+		StopLossPercent:        0.15,
+		KellyFraction:          0.25,
+	}
+	manager := position.NewManager(posRepo, bankRepo, stubVolatility{}, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  params.KellyFraction,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+	if err := manager.SetEntryExecution(position.EntryExecution{
+		Strategy: position.EntryStrategyLimit,
+		Timeout:  time.Second,
+	}); err != nil {
+		t.Fatalf("failed to set entry execution: %v", err)
+	}
+	manager.SetPlatformOrderer(client.Name(), client)
+	manager.SetOrderCanceller(client.Name(), client)
+	manager.SetExitTimeout(time.Second)
+
+	tracker := orders.NewTracker(persistence.NewOrderRepository(db), posRepo, bankRepo)
+	tracker.SetTrader(client.Name(), client)
+	settler := settlement.NewSettler(posRepo, persistence.NewResolutionRepository(db), manager)
+	settler.SetResolver(client.Name(), client)
+
+	tradingBot := bot.NewBot(bot.BotConfig{
+		DryRun:          false,
+		ScanInterval:    100 * time.Millisecond,
+		MonitorInterval: 50 * time.Millisecond,
+		SettleInterval:  time.Second,
+	}, []platform.Platform{client}, scanner.NewScanner(params), manager)
+	tradingBot.SetMonitor(position.NewMonitor(params.StopLossPercent))
+	tradingBot.SetVolatilityAnalyzer(stubVolatility{})
+	tradingBot.SetPositionRepo(posRepo)
+	tradingBot.SetOrderTracker(tracker)
+	tradingBot.SetSettler(settler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- tradingBot.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("bot stopped with error: %v", err)
+		}
+	}()
+
+	// Step 1: an eligible market appears and the bot enters it
+	exchange.ListMarket(mockexchange.Market{
+		ID:        "btc-100k",
+		Title:     "Will Bitcoin be above $100,000 on Jan 20?",
+		EndDate:   time.Now().Add(24 * time.Hour),
+		YesPrice:  0.85,
+		Volume:    10000,
+		Liquidity: 5000,
+	})
+	var entry *persistence.Position
+	waitFor(t, 5*time.Second, "the position to open", func() bool {
+		open, err := posRepo.GetOpen()
+		if err != nil || len(open) == 0 {
+			return false
+		}
+		entry = open[0]
+		return true
+	})
+	if entry.MarketID != "btc-100k" || entry.Side != "YES" || entry.TokenID != "btc-100k:YES" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	// Step 2: the price crashes through the stop and the bot sells
+	if err := exchange.SetPrice("btc-100k", 0.60); err != nil {
+		t.Fatalf("failed to move price: %v", err)
+	}
+	var exit *persistence.Position
+	waitFor(t, 5*time.Second, "the stop loss exit", func() bool {
+		closed, err := posRepo.GetClosed()
+		if err != nil || len(closed) == 0 {
+			return false
+		}
+		exit = closed[0]
+		return true
+	})
+
+	// The database records the stop loss at the exchange's fill
+	if exit.ID != entry.ID {
+		t.Errorf("expected position %d closed, got %d", entry.ID, exit.ID)
+	}
+	if exit.ExitReason == nil || *exit.ExitReason != position.ExitReasonStopLoss {
+		t.Errorf("expected exit reason %q, got %v", position.ExitReasonStopLoss, exit.ExitReason)
+	}
+	if exit.ExitPrice == nil || *exit.ExitPrice != 0.60 {
+		t.Errorf("expected exit price 0.60, got %v", exit.ExitPrice)
+	}
+	wantPnL := (0.60 - entry.EntryPrice) * entry.Quantity
+	if exit.RealizedPnL == nil || math.Abs(*exit.RealizedPnL-wantPnL) > 0.01 {
+		t.Errorf("expected realized PnL %.2f, got %v", wantPnL, exit.RealizedPnL)
+	}
+	if open, _ := posRepo.GetOpen(); len(open) != 0 {
+		t.Errorf("expected no open positions, got %d", len(open))
+	}
+
+	bankroll, err := bankRepo.Get("mock")
+	if err != nil {
+		t.Fatalf("failed to get bankroll: %v", err)
+	}
+	if math.Abs(bankroll.CurrentAmount-(100+wantPnL)) > 0.01 {
+		t.Errorf("expected bankroll %.2f, got %.2f", 100+wantPnL, bankroll.CurrentAmount)
+	}
+
+	// The exchange saw one buy and one sell, both filled
+	placed := exchange.Orders()
+	if len(placed) != 2 {
+		t.Fatalf("expected 2 orders on the exchange, got %d", len(placed))
+	}
+	for i, side := range []types.OrderSide{types.OrderSideBuy, types.OrderSideSell} {
+		if placed[i].Side != side || placed[i].Status != types.OrderStatusFilled {
+			t.Errorf("expected order %d a filled %s, got %+v", i, side, placed[i])
+		}
+	}
+	if math.Abs(exchange.Balance()-bankroll.CurrentAmount) > 0.01 {
+		t.Errorf("expected the exchange balance %.2f to match the bankroll %.2f", exchange.Balance(), bankroll.CurrentAmount)
+	}
+}
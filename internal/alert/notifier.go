@@ -0,0 +1,95 @@
+// Package alert raises audible alerts for operators watching a live session,
+// and checks the bot's health against alert thresholds.
+package alert
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Events that raise an alert.
+const (
+	// EventStopLoss is raised when a stop loss fires.
+	EventStopLoss = "stop_loss"
+	// EventOrderFailed is raised when a live order fails, leaving a
+	// position that should have been exited open.
+	EventOrderFailed = "order_failed"
+	// EventLedgerInconsistent is raised when a bankroll no longer agrees
+	// with its ledger and entries on its platform are paused.
+	EventLedgerInconsistent = "ledger_inconsistent"
+	// EventBalanceDiscrepancy is raised when a platform's balance is
+	// further from its bankroll than the platform's tolerance.
+	EventBalanceDiscrepancy = "balance_discrepancy"
+	// EventParameterAdjusted is raised when the learning cycle changes a
+	// parameter.
+	EventParameterAdjusted = "parameter_adjusted"
+	// EventParametersReverted is raised when the learning cycle reverts the
+	// parameters to their defaults because of a drawdown.
+	EventParametersReverted = "parameters_reverted"
+)
+
+// DefaultCommandTimeout is how long an alert command may run before it is
+// killed.
+const DefaultCommandTimeout = 5 * time.Second
+
+// Notifier rings the terminal bell and/or runs a command hook on each
+// alert. With neither configured, alerts are dropped.
+type Notifier struct {
+	bell    io.Writer
+	command string
+	timeout time.Duration
+}
+
+// NewNotifier creates a Notifier with no outputs configured.
+func NewNotifier() *Notifier {
+	return &Notifier{timeout: DefaultCommandTimeout}
+}
+
+// SetBell sets the terminal the bell character is written to.
+func (n *Notifier) SetBell(w io.Writer) {
+	n.bell = w
+}
+
+// SetCommand sets a shell command run on each alert, e.g. to play a sound
+// or send a desktop notification. The event and message are passed in the
+// ALERT_EVENT and ALERT_MESSAGE environment variables.
+func (n *Notifier) SetCommand(command string) {
+	n.command = command
+}
+
+// SetCommandTimeout sets how long the command may run before it is killed.
+func (n *Notifier) SetCommandTimeout(timeout time.Duration) {
+	n.timeout = timeout
+}
+
+// Alert raises an alert for event. Failures are logged rather than
+// returned: an alert that can't be raised must not stop trading.
+func (n *Notifier) Alert(event, message string) {
+	if n.bell != nil {
+		if _, err := io.WriteString(n.bell, "\a"); err != nil {
+			log.Warn().Err(err).Str("event", event).Msg("failed to ring alert bell")
+		}
+	}
+
+	if n.command == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.command)
+	cmd.Env = append(os.Environ(), "ALERT_EVENT="+event, "ALERT_MESSAGE="+message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.Warn().
+			Err(err).
+			Str("event", event).
+			Str("output", string(out)).
+			Msg("alert command failed")
+	}
+}
@@ -0,0 +1,36 @@
+package alert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotifier_Bell(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewNotifier()
+	n.SetBell(&buf)
+
+	n.Alert(EventStopLoss, "stop loss on BTC")
+
+	if buf.String() != "\a" {
+		t.Errorf("expected bell character, got %q", buf.String())
+	}
+}
+
+func TestNotifier_Command(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alert.txt")
+	n := NewNotifier()
+	n.SetCommand(`printf '%s: %s' "$ALERT_EVENT" "$ALERT_MESSAGE" > ` + out)
+
+	n.Alert(EventOrderFailed, "exit not filled")
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("expected command to write %s: %v", out, err)
+	}
+	if string(got) != "order_failed: exit not filled" {
+		t.Errorf("unexpected command output %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package alert
+
+import (
+	_ "embed"
+	"io"
+	"math"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+//go:embed prometheus_rules.yaml.tmpl
+var rulesTemplate string
+
+var rules = template.Must(template.New("rules").Parse(rulesTemplate))
+
+// WritePrometheusRules writes Prometheus alerting rules for the metrics the
+// watchdog serves, with the same thresholds, for deployments that run
+// Prometheus and Alertmanager. job is the Prometheus job scraping the bot.
+func WritePrometheusRules(out io.Writer, thresholds Thresholds, job string) error {
+	if thresholds.ErrorWindow <= 0 {
+		thresholds.ErrorWindow = time.Hour
+	}
+	return rules.Execute(out, struct {
+		Job                 string
+		ScanStale           string
+		ScanStaleSeconds    int64
+		MaxErrorRate        float64
+		MaxErrorRatePercent string
+		ErrorWindow         string
+		MaxDrawdown         float64
+		MaxDrawdownPercent  string
+	}{
+		Job:                 job,
+		ScanStale:           promDuration(thresholds.ScanStale),
+		ScanStaleSeconds:    int64(thresholds.ScanStale.Seconds()),
+		MaxErrorRate:        thresholds.MaxErrorRate,
+		MaxErrorRatePercent: percent(thresholds.MaxErrorRate),
+		ErrorWindow:         promDuration(thresholds.ErrorWindow),
+		MaxDrawdown:         thresholds.MaxDrawdown,
+		MaxDrawdownPercent:  percent(thresholds.MaxDrawdown),
+	})
+}
+
+// percent formats a fraction as a percentage, without trailing zeros.
+func percent(fraction float64) string {
+	return strconv.FormatFloat(math.Round(fraction*10000)/100, 'f', -1, 64)
+}
+
+// promDuration formats a duration as a Prometheus range, such as "1h" or
+// "90m".
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+}
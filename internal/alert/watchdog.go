@@ -0,0 +1,231 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Events raised by the watchdog's thresholds.
+const (
+	// EventScanStale is raised when no scan cycle has succeeded for
+	// Thresholds.ScanStale.
+	EventScanStale = "scan_stale"
+	// EventErrorRate is raised when the share of cycles that failed over
+	// Thresholds.ErrorWindow exceeds Thresholds.MaxErrorRate.
+	EventErrorRate = "error_rate"
+	// EventDrawdown is raised when realized equity is more than
+	// Thresholds.MaxDrawdown below its peak.
+	EventDrawdown = "drawdown"
+)
+
+// minErrorRateCycles is how many cycles the error window must hold before
+// its error rate is checked, so one failure after a restart doesn't alert.
+const minErrorRateCycles = 5
+
+// Thresholds are the limits the watchdog alerts on. Zero disables a
+// threshold.
+type Thresholds struct {
+	ScanStale    time.Duration // Time without a successful scan cycle
+	MaxErrorRate float64       // Share of cycles failed over ErrorWindow
+	ErrorWindow  time.Duration // Window the error rate is measured over (0 defaults to an hour)
+	MaxDrawdown  float64       // Fraction of peak equity
+}
+
+// Metrics are the bot's health figures the thresholds are checked against.
+type Metrics struct {
+	// LastScan is when a scan cycle last succeeded (zero if none has).
+	LastScan time.Time
+	// Cycles and Errors count the cycles run and the errors they raised.
+	Cycles int
+	Errors int
+	// Drawdown is how far realized equity is below its peak, as a fraction
+	// of the peak.
+	Drawdown float64
+}
+
+// Alerter receives the alerts a threshold raises, such as a Notifier.
+type Alerter interface {
+	Alert(event, message string)
+}
+
+// errorSample is the cycle and error counts at a check.
+type errorSample struct {
+	at     time.Time
+	cycles int
+	errors int
+}
+
+// Watchdog checks the bot's metrics against thresholds and alerts when one
+// is breached, so monitoring works without an external Prometheus and
+// Alertmanager. Each breach alerts once, and again only after it clears.
+// It also serves the metrics last checked in the Prometheus text format
+// (see PrometheusRules for alerting on them).
+type Watchdog struct {
+	thresholds Thresholds
+	alerter    Alerter
+	started    time.Time
+	now        func() time.Time
+
+	mu      sync.Mutex
+	samples []errorSample
+	firing  map[string]bool
+	last    Metrics
+}
+
+// NewWatchdog creates a watchdog alerting through alerter (nil only logs).
+func NewWatchdog(thresholds Thresholds, alerter Alerter) *Watchdog {
+	if thresholds.ErrorWindow <= 0 {
+		thresholds.ErrorWindow = time.Hour
+	}
+	return &Watchdog{
+		thresholds: thresholds,
+		alerter:    alerter,
+		started:    time.Now(),
+		now:        time.Now,
+		firing:     make(map[string]bool),
+	}
+}
+
+// Run checks the metrics collect returns every interval until ctx is
+// cancelled. Metrics that fail to collect are logged and skipped.
+func (w *Watchdog) Run(ctx context.Context, interval time.Duration, collect func() (Metrics, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m, err := collect()
+			if err != nil {
+				log.Warn().Err(err).Msg("failed to collect metrics for alert thresholds")
+				continue
+			}
+			w.Check(m)
+		}
+	}
+}
+
+// Check checks metrics against the thresholds and alerts on each newly
+// breached one. Returns the events raised.
+func (w *Watchdog) Check(m Metrics) []string {
+	now := w.now()
+
+	w.mu.Lock()
+	w.last = m
+	w.samples = append(w.samples, errorSample{at: now, cycles: m.Cycles, errors: m.Errors})
+	for len(w.samples) > 1 && now.Sub(w.samples[1].at) >= w.thresholds.ErrorWindow {
+		w.samples = w.samples[1:]
+	}
+	oldest := w.samples[0]
+	w.mu.Unlock()
+
+	breaches := make(map[string]string)
+	if stale := w.thresholds.ScanStale; stale > 0 {
+		since := m.LastScan
+		if since.IsZero() {
+			since = w.started
+		}
+		if idle := now.Sub(since); idle >= stale {
+			breaches[EventScanStale] = fmt.Sprintf("no successful scan in %s", idle.Round(time.Second))
+		}
+	}
+	if limit := w.thresholds.MaxErrorRate; limit > 0 {
+		cycles := m.Cycles - oldest.cycles
+		if cycles >= minErrorRateCycles {
+			rate := float64(m.Errors-oldest.errors) / float64(cycles)
+			if rate > limit {
+				breaches[EventErrorRate] = fmt.Sprintf("%.0f%% of the last %d cycles failed (limit %.0f%%)", rate*100, cycles, limit*100)
+			}
+		}
+	}
+	if limit := w.thresholds.MaxDrawdown; limit > 0 && m.Drawdown > limit {
+		breaches[EventDrawdown] = fmt.Sprintf("drawdown %.1f%% exceeds %.1f%%", m.Drawdown*100, limit*100)
+	}
+
+	var raised []string
+	for _, event := range []string{EventScanStale, EventErrorRate, EventDrawdown} {
+		message, breached := breaches[event]
+		w.mu.Lock()
+		wasFiring := w.firing[event]
+		w.firing[event] = breached
+		w.mu.Unlock()
+
+		switch {
+		case breached && !wasFiring:
+			log.Warn().Str("event", event).Msg(message)
+			if w.alerter != nil {
+				w.alerter.Alert(event, message)
+			}
+			raised = append(raised, event)
+		case !breached && wasFiring:
+			log.Info().Str("event", event).Msg("alert threshold cleared")
+		}
+	}
+	return raised
+}
+
+// ServeHTTP serves the metrics last checked, and which thresholds are
+// breached, in the Prometheus text format.
+func (w *Watchdog) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := w.WritePrometheus(rw); err != nil {
+		log.Warn().Err(err).Msg("failed to write metrics")
+	}
+}
+
+// WritePrometheus writes the metrics last checked in the Prometheus text
+// format.
+func (w *Watchdog) WritePrometheus(out io.Writer) error {
+	w.mu.Lock()
+	m := w.last
+	events := make([]string, 0, len(w.firing))
+	for event := range w.firing {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	firing := make([]int, len(events))
+	for i, event := range events {
+		if w.firing[event] {
+			firing[i] = 1
+		}
+	}
+	w.mu.Unlock()
+
+	var lastScan float64
+	if !m.LastScan.IsZero() {
+		lastScan = float64(m.LastScan.Unix())
+	}
+	metrics := []struct {
+		name, kind, help string
+		value            float64
+	}{
+		{"prediction_bot_last_successful_scan_timestamp_seconds", "gauge", "Unix time the last scan cycle succeeded (0 if none has).", lastScan},
+		{"prediction_bot_cycles_total", "counter", "Scan, monitor and settlement cycles run.", float64(m.Cycles)},
+		{"prediction_bot_cycle_errors_total", "counter", "Errors raised by cycles.", float64(m.Errors)},
+		{"prediction_bot_drawdown_ratio", "gauge", "Realized equity below its peak, as a fraction of the peak.", m.Drawdown},
+	}
+	for _, metric := range metrics {
+		if _, err := fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n%s %g\n",
+			metric.name, metric.help, metric.name, metric.kind, metric.name, metric.value); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprint(out, "# HELP prediction_bot_alert_firing Whether a built-in alert threshold is breached.\n# TYPE prediction_bot_alert_firing gauge\n"); err != nil {
+		return err
+	}
+	for i, event := range events {
+		if _, err := fmt.Fprintf(out, "prediction_bot_alert_firing{alert=%q} %d\n", event, firing[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package alert
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingAlerter records the events alerted.
+type recordingAlerter struct {
+	events []string
+}
+
+func (a *recordingAlerter) Alert(event, message string) {
+	a.events = append(a.events, event)
+}
+
+func newTestWatchdog(thresholds Thresholds, alerter Alerter, now *time.Time) *Watchdog {
+	w := NewWatchdog(thresholds, alerter)
+	w.started = *now
+	w.now = func() time.Time { return *now }
+	return w
+}
+
+func TestWatchdog_ScanStaleAlertsOnceUntilCleared(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter := &recordingAlerter{}
+	w := newTestWatchdog(Thresholds{ScanStale: 15 * time.Minute}, alerter, &now)
+
+	// No scan yet: measured from when the watchdog started
+	now = now.Add(10 * time.Minute)
+	if raised := w.Check(Metrics{}); len(raised) != 0 {
+		t.Errorf("expected no alert within the threshold, got %v", raised)
+	}
+	now = now.Add(10 * time.Minute)
+	if raised := w.Check(Metrics{}); len(raised) != 1 || raised[0] != EventScanStale {
+		t.Errorf("expected scan_stale, got %v", raised)
+	}
+	now = now.Add(time.Minute)
+	if raised := w.Check(Metrics{}); len(raised) != 0 {
+		t.Errorf("expected a breach alerted once, got %v", raised)
+	}
+
+	// A scan clears it, and a later breach alerts again
+	w.Check(Metrics{LastScan: now})
+	now = now.Add(20 * time.Minute)
+	w.Check(Metrics{LastScan: now.Add(-20 * time.Minute)})
+
+	if len(alerter.events) != 2 {
+		t.Errorf("expected two alerts, got %v", alerter.events)
+	}
+}
+
+func TestWatchdog_ErrorRateOverWindow(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	w := newTestWatchdog(Thresholds{MaxErrorRate: 0.5, ErrorWindow: time.Hour}, nil, &now)
+
+	w.Check(Metrics{Cycles: 100, Errors: 10})
+	now = now.Add(30 * time.Minute)
+	// 3 errors in 4 cycles is too few cycles to judge
+	if raised := w.Check(Metrics{Cycles: 104, Errors: 13}); len(raised) != 0 {
+		t.Errorf("expected no alert below the minimum cycles, got %v", raised)
+	}
+	now = now.Add(20 * time.Minute)
+	if raised := w.Check(Metrics{Cycles: 110, Errors: 17}); len(raised) != 1 || raised[0] != EventErrorRate {
+		t.Errorf("expected error_rate for 7 errors in 10 cycles, got %v", raised)
+	}
+
+	// Once the failures age out of the window the rate clears
+	now = now.Add(2 * time.Hour)
+	w.Check(Metrics{Cycles: 130, Errors: 17})
+	now = now.Add(30 * time.Minute)
+	w.Check(Metrics{Cycles: 150, Errors: 18})
+	if w.firing[EventErrorRate] {
+		t.Error("expected the error rate cleared")
+	}
+}
+
+func TestWatchdog_Drawdown(t *testing.T) {
+	now := time.Now()
+	w := newTestWatchdog(Thresholds{MaxDrawdown: 0.2}, nil, &now)
+
+	if raised := w.Check(Metrics{Drawdown: 0.2}); len(raised) != 0 {
+		t.Errorf("expected no alert at the threshold, got %v", raised)
+	}
+	if raised := w.Check(Metrics{Drawdown: 0.25}); len(raised) != 1 || raised[0] != EventDrawdown {
+		t.Errorf("expected drawdown, got %v", raised)
+	}
+}
+
+func TestWatchdog_WritePrometheus(t *testing.T) {
+	now := time.Unix(1740830400, 0)
+	w := newTestWatchdog(Thresholds{MaxDrawdown: 0.2}, nil, &now)
+	w.Check(Metrics{LastScan: now, Cycles: 42, Errors: 3, Drawdown: 0.25})
+
+	var buf bytes.Buffer
+	if err := w.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	for _, want := range []string{
+		"# TYPE prediction_bot_cycles_total counter\nprediction_bot_cycles_total 42\n",
+		"prediction_bot_cycle_errors_total 3\n",
+		"prediction_bot_last_successful_scan_timestamp_seconds 1.7408304e+09\n",
+		"prediction_bot_drawdown_ratio 0.25\n",
+		`prediction_bot_alert_firing{alert="drawdown"} 1`,
+		`prediction_bot_alert_firing{alert="scan_stale"} 0`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in metrics:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWritePrometheusRules(t *testing.T) {
+	var buf bytes.Buffer
+	err := WritePrometheusRules(&buf, Thresholds{ScanStale: 15 * time.Minute, MaxDrawdown: 0.25}, "bot")
+	if err != nil {
+		t.Fatalf("WritePrometheusRules failed: %v", err)
+	}
+	rules := buf.String()
+	for _, want := range []string{
+		`up{job="bot"} == 0`,
+		`time() - prediction_bot_last_successful_scan_timestamp_seconds{job="bot"} > 900`,
+		`prediction_bot_drawdown_ratio{job="bot"} > 0.25`,
+		"Drawdown above 25% of peak equity",
+	} {
+		if !strings.Contains(rules, want) {
+			t.Errorf("expected %q in rules:\n%s", want, rules)
+		}
+	}
+	if strings.Contains(rules, "PredictionBotErrorRate") {
+		t.Errorf("expected no rule for a disabled threshold:\n%s", rules)
+	}
+}
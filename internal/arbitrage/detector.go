@@ -0,0 +1,163 @@
+// Package arbitrage cross-checks the implied probabilities of equivalent
+// markets listed on different platforms.
+package arbitrage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"prediction-bot/internal/scanner"
+	"prediction-bot/pkg/types"
+)
+
+// DefaultMinSpread is the divergence in implied probability at which a pair
+// of equivalent markets is flagged when no minimum is configured.
+const DefaultMinSpread = 0.05
+
+// Opportunity is a pair of equivalent markets on two platforms whose implied
+// probabilities diverge. Buying YES where it is cheaper and NO where YES is
+// dearer pays out 1.0 per contract pair whichever way the market resolves.
+type Opportunity struct {
+	Asset     string
+	Strike    float64
+	Direction string
+	// Date is the UTC day both markets resolve on.
+	Date time.Time
+
+	// YesMarket is the market with the lower YES price, where YES is bought.
+	YesMarket types.Market
+	// NoMarket is the market with the higher YES price, where NO is bought.
+	NoMarket types.Market
+
+	// YesPrice and NoPrice are the prices paid for each leg, estimated as
+	// the mid-price plus half the quoted spread.
+	YesPrice float64
+	NoPrice  float64
+	// Spread is the difference between the two markets' YES mid-prices.
+	Spread float64
+}
+
+// Cost returns the price of one contract on each leg.
+func (o Opportunity) Cost() float64 {
+	return o.YesPrice + o.NoPrice
+}
+
+// Profit returns the locked-in profit per contract pair, before fees.
+func (o Opportunity) Profit() float64 {
+	return 1.0 - o.Cost()
+}
+
+// Key identifies the pair of markets independently of their prices.
+func (o Opportunity) Key() string {
+	return fmt.Sprintf("%s:%s/%s:%s",
+		o.YesMarket.Platform, o.YesMarket.ID, o.NoMarket.Platform, o.NoMarket.ID)
+}
+
+// Detector matches equivalent markets across platforms and flags those whose
+// prices diverge.
+type Detector struct {
+	minSpread float64
+}
+
+// NewDetector creates a detector that flags pairs whose YES prices differ by
+// at least minSpread. Zero or less uses DefaultMinSpread.
+func NewDetector(minSpread float64) *Detector {
+	if minSpread <= 0 {
+		minSpread = DefaultMinSpread
+	}
+	return &Detector{minSpread: minSpread}
+}
+
+// MinSpread returns the divergence at which pairs are flagged.
+func (d *Detector) MinSpread() float64 {
+	return d.minSpread
+}
+
+// quotedMarket is a parsed market with a usable YES price.
+type quotedMarket struct {
+	market types.Market
+	parsed *scanner.ParsedMarket
+}
+
+// Detect matches the given markets, from any number of platforms, by asset,
+// strike, direction and resolution day, and returns the cross-platform pairs
+// whose YES prices differ by at least the minimum spread. Markets whose
+// titles can't be parsed, that are closed, or that have no price are
+// ignored. Opportunities are sorted by descending spread.
+func (d *Detector) Detect(markets []types.Market) []Opportunity {
+	groups := make(map[string][]quotedMarket)
+	for _, m := range markets {
+		if m.Closed || m.EndDate.IsZero() || m.OutcomeYesPrice <= 0 || m.OutcomeYesPrice >= 1 {
+			continue
+		}
+		parsed, err := scanner.ParseMarketTitle(m.Title)
+		if err != nil {
+			continue
+		}
+		key := matchKey(parsed, m.EndDate)
+		groups[key] = append(groups[key], quotedMarket{market: m, parsed: parsed})
+	}
+
+	var opportunities []Opportunity
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				if group[i].market.Platform == group[j].market.Platform {
+					continue
+				}
+				opp := pair(group[i], group[j])
+				if opp.Spread >= d.minSpread {
+					opportunities = append(opportunities, opp)
+				}
+			}
+		}
+	}
+
+	sort.Slice(opportunities, func(i, j int) bool {
+		if opportunities[i].Spread != opportunities[j].Spread {
+			return opportunities[i].Spread > opportunities[j].Spread
+		}
+		return opportunities[i].Key() < opportunities[j].Key()
+	})
+
+	return opportunities
+}
+
+// matchKey identifies equivalent markets. Platforms list resolution times in
+// different time zones and at different hours, so markets are matched on
+// the UTC day they resolve.
+func matchKey(parsed *scanner.ParsedMarket, endDate time.Time) string {
+	return fmt.Sprintf("%s|%g|%s|%s",
+		parsed.Asset, parsed.Strike, parsed.Direction, endDate.UTC().Format("2006-01-02"))
+}
+
+// pair builds the opportunity for two equivalent markets, buying YES on the
+// cheaper one.
+func pair(a, b quotedMarket) Opportunity {
+	if a.market.OutcomeYesPrice > b.market.OutcomeYesPrice {
+		a, b = b, a
+	}
+
+	date := a.market.EndDate.UTC()
+	return Opportunity{
+		Asset:     a.parsed.Asset,
+		Strike:    a.parsed.Strike,
+		Direction: a.parsed.Direction,
+		Date:      time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		YesMarket: a.market,
+		NoMarket:  b.market,
+		YesPrice:  a.market.OutcomeYesPrice + a.market.Spread/2,
+		NoPrice:   noPrice(b.market) + b.market.Spread/2,
+		Spread:    b.market.OutcomeYesPrice - a.market.OutcomeYesPrice,
+	}
+}
+
+// noPrice returns the NO mid-price of a market, derived from YES if the
+// platform doesn't quote it.
+func noPrice(m types.Market) float64 {
+	if m.OutcomeNoPrice > 0 {
+		return m.OutcomeNoPrice
+	}
+	return 1.0 - m.OutcomeYesPrice
+}
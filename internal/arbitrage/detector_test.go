@@ -0,0 +1,105 @@
+package arbitrage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+var resolution = time.Date(2026, 1, 18, 17, 0, 0, 0, time.UTC)
+
+func market(platform, id, title string, yes float64, endDate time.Time) types.Market {
+	return types.Market{
+		ID:              id,
+		Platform:        platform,
+		Title:           title,
+		EndDate:         endDate,
+		Active:          true,
+		OutcomeYesPrice: yes,
+		OutcomeNoPrice:  1 - yes,
+	}
+}
+
+func TestDetect_FlagsDivergentEquivalentMarkets(t *testing.T) {
+	markets := []types.Market{
+		market("polymarket", "poly-1", "Will Bitcoin be above $100,000 on Jan 18?", 0.82, resolution),
+		// Kalshi resolves at a different hour of the same day
+		market("kalshi", "KXBTC-100K", "Bitcoin above $100k on January 18", 0.74, resolution.Add(-5*time.Hour)),
+	}
+
+	opps := NewDetector(0.05).Detect(markets)
+	if len(opps) != 1 {
+		t.Fatalf("Expected 1 opportunity, got %d", len(opps))
+	}
+
+	opp := opps[0]
+	if opp.YesMarket.Platform != "kalshi" || opp.NoMarket.Platform != "polymarket" {
+		t.Errorf("Expected YES on kalshi and NO on polymarket, got YES on %s and NO on %s",
+			opp.YesMarket.Platform, opp.NoMarket.Platform)
+	}
+	if opp.Asset != "BTC" || opp.Strike != 100000 || opp.Direction != "above" {
+		t.Errorf("Unexpected match %s %v %s", opp.Asset, opp.Strike, opp.Direction)
+	}
+	if math.Abs(opp.Spread-0.08) > 1e-9 {
+		t.Errorf("Expected spread 0.08, got %f", opp.Spread)
+	}
+	// YES at 0.74 and NO at 0.18 pays 1.0 either way
+	if math.Abs(opp.Profit()-0.08) > 1e-9 {
+		t.Errorf("Expected profit 0.08 per pair, got %f", opp.Profit())
+	}
+}
+
+func TestDetect_IgnoresNonEquivalentMarkets(t *testing.T) {
+	title := "Will Bitcoin be above $100,000 on Jan 18?"
+	markets := []types.Market{
+		market("polymarket", "poly-1", title, 0.90, resolution),
+		// Different strike
+		market("kalshi", "k-1", "Bitcoin above $105,000 on January 18", 0.50, resolution),
+		// Different direction
+		market("kalshi", "k-2", "Bitcoin below $100,000 on January 18", 0.50, resolution),
+		// Different day
+		market("kalshi", "k-3", title, 0.50, resolution.Add(24*time.Hour)),
+		// Same platform
+		market("polymarket", "poly-2", title, 0.50, resolution),
+		// Unparseable
+		market("kalshi", "k-4", "Will it rain in Seattle on Jan 18?", 0.50, resolution),
+	}
+
+	if opps := NewDetector(0.05).Detect(markets); len(opps) != 0 {
+		t.Errorf("Expected no opportunities, got %d: %+v", len(opps), opps)
+	}
+}
+
+func TestDetect_RespectsMinSpread(t *testing.T) {
+	markets := []types.Market{
+		market("polymarket", "poly-1", "Will ETH be above $4,000 on Jan 18?", 0.80, resolution),
+		market("kalshi", "k-1", "Ethereum above $4,000 on January 18", 0.77, resolution),
+	}
+
+	if opps := NewDetector(0.05).Detect(markets); len(opps) != 0 {
+		t.Errorf("Expected 0.03 divergence to be ignored, got %d opportunities", len(opps))
+	}
+	if opps := NewDetector(0.02).Detect(markets); len(opps) != 1 {
+		t.Errorf("Expected 0.03 divergence to be flagged at 0.02, got %d opportunities", len(opps))
+	}
+}
+
+func TestOpportunity_PricesIncludeHalfSpread(t *testing.T) {
+	yes := market("kalshi", "k-1", "Bitcoin above $100k on January 18", 0.70, resolution)
+	yes.Spread = 0.04
+	no := market("polymarket", "poly-1", "Will Bitcoin be above $100,000 on Jan 18?", 0.80, resolution)
+	no.Spread = 0.02
+
+	opps := NewDetector(0).Detect([]types.Market{yes, no})
+	if len(opps) != 1 {
+		t.Fatalf("Expected 1 opportunity, got %d", len(opps))
+	}
+	if math.Abs(opps[0].YesPrice-0.72) > 1e-9 || math.Abs(opps[0].NoPrice-0.21) > 1e-9 {
+		t.Errorf("Expected leg prices 0.72 and 0.21, got %f and %f", opps[0].YesPrice, opps[0].NoPrice)
+	}
+	if math.Abs(opps[0].Profit()-0.07) > 1e-9 {
+		t.Errorf("Expected profit 0.07 per pair, got %f", opps[0].Profit())
+	}
+}
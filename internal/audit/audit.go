@@ -0,0 +1,177 @@
+// Package audit records what the bot did, such as entries, exits,
+// parameter changes, API errors and trading halts, as structured events in
+// the database, so they can be queried by type and time after the fact.
+package audit
+
+import (
+	"encoding/json"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Event types.
+const (
+	TypeEntry           = "entry"
+	TypeExit            = "exit"
+	TypeParameterChange = "parameter_change"
+	TypeParameterRevert = "parameter_revert"
+	TypeAPIError        = "api_error"
+	TypeHalt            = "halt"
+	TypeResume          = "resume"
+)
+
+// Types lists every event type.
+var Types = []string{TypeEntry, TypeExit, TypeParameterChange, TypeParameterRevert, TypeAPIError, TypeHalt, TypeResume}
+
+// Halt causes.
+const (
+	// CausePlatformStatus is a halt of trading on the platform itself.
+	CausePlatformStatus = "platform_status"
+	// CauseLedger is a pause of entries because the bankroll disagrees with
+	// its ledger.
+	CauseLedger = "ledger_inconsistent"
+)
+
+// Recorder records audit events. Failing to record an event is logged
+// rather than returned: the audit log must never interrupt trading.
+type Recorder struct {
+	repo *persistence.EventRepository
+}
+
+// NewRecorder creates a recorder that stores events in repo.
+func NewRecorder(repo *persistence.EventRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Entry records a position opened on a market.
+func (r *Recorder) Entry(platform, marketID string, result position.EntryResult, dryRun bool) {
+	r.store(r.EntryEvent(platform, marketID, result, dryRun))
+}
+
+// EntryEvent builds the event recording a position opened on a market, for
+// the position manager to record with the entry (see
+// position.TradeEvents). It is nil if the recorder is.
+func (r *Recorder) EntryEvent(platform, marketID string, result position.EntryResult, dryRun bool) *persistence.Event {
+	return r.event(TypeEntry, platform, marketID, result.PositionID, map[string]interface{}{
+		"side":           result.Side,
+		"entry_price":    result.EntryPrice,
+		"quantity":       result.Quantity,
+		"position_size":  result.PositionSize,
+		"safety_margin":  result.SafetyMargin,
+		"volatility":     result.Volatility,
+		"entry_strategy": result.Strategy,
+		"trade_strategy": result.TradeStrategy,
+		"dry_run":        dryRun,
+	})
+}
+
+// Exit records a position closed, in part or in full.
+func (r *Recorder) Exit(exit position.ExitResult) {
+	r.store(r.ExitEvent("", "", exit))
+}
+
+// ExitEvent builds the event recording a position on a market closed, in
+// part or in full, for the position manager to record with the exit. It is
+// nil if the recorder is.
+func (r *Recorder) ExitEvent(platform, marketID string, exit position.ExitResult) *persistence.Event {
+	return r.event(TypeExit, platform, marketID, exit.PositionID, map[string]interface{}{
+		"exit_reason":        exit.ExitReason,
+		"entry_price":        exit.EntryPrice,
+		"exit_price":         exit.ExitPrice,
+		"quantity":           exit.Quantity,
+		"remaining_quantity": exit.RemainingQuantity,
+		"realized_pnl":       exit.RealizedPnL,
+		"fees":               exit.Fees,
+	})
+}
+
+// ParameterChange records a trading parameter changed from one value to
+// another. A nil value means the parameter was unset.
+func (r *Recorder) ParameterChange(name string, from, to interface{}, reason string) {
+	r.record(TypeParameterChange, "", "", 0, map[string]interface{}{
+		"parameter": name,
+		"from":      from,
+		"to":        to,
+		"reason":    reason,
+	})
+}
+
+// ParameterRevert records the learned parameters reverted to their defaults
+// because realized equity fell drawdown (a fraction) below its peak, with
+// the values each reverted parameter had, by name.
+func (r *Recorder) ParameterRevert(equity, peak, drawdown float64, reverted map[string]float64) {
+	r.record(TypeParameterRevert, "", "", 0, map[string]interface{}{
+		"equity":   equity,
+		"peak":     peak,
+		"drawdown": drawdown,
+		"reverted": reverted,
+	})
+}
+
+// APIError records a failed call to a platform, naming the operation that
+// failed (e.g. "scan" or "get_price"). marketID may be empty.
+func (r *Recorder) APIError(platform, marketID, operation string, err error) {
+	r.record(TypeAPIError, platform, marketID, 0, map[string]interface{}{
+		"operation": operation,
+		"error":     err.Error(),
+	})
+}
+
+// Halt records entries on a platform being paused, for cause
+// (CausePlatformStatus or CauseLedger).
+func (r *Recorder) Halt(platform, cause, message string) {
+	r.record(TypeHalt, platform, "", 0, map[string]interface{}{
+		"cause":   cause,
+		"message": message,
+	})
+}
+
+// Resume records entries on a platform resuming after a halt for cause.
+func (r *Recorder) Resume(platform, cause string) {
+	r.record(TypeResume, platform, "", 0, map[string]interface{}{
+		"cause": cause,
+	})
+}
+
+// record stores an event. Recording to a nil recorder does nothing, so
+// callers don't need one. A zero positionID records no position.
+func (r *Recorder) record(eventType, platform, marketID string, positionID int64, details map[string]interface{}) {
+	r.store(r.event(eventType, platform, marketID, positionID, details))
+}
+
+// event builds an event, or returns nil if the recorder is nil or the
+// details can't be encoded.
+func (r *Recorder) event(eventType, platform, marketID string, positionID int64, details map[string]interface{}) *persistence.Event {
+	if r == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		log.Error().Err(err).Str("event_type", eventType).Msg("failed to encode audit event")
+		return nil
+	}
+	event := &persistence.Event{
+		Type:     eventType,
+		Platform: platform,
+		MarketID: marketID,
+		Details:  string(data),
+	}
+	if positionID != 0 {
+		event.PositionID = &positionID
+	}
+	return event
+}
+
+// store stores an event built by event, if any.
+func (r *Recorder) store(event *persistence.Event) {
+	if event == nil {
+		return
+	}
+	if _, err := r.repo.Record(event); err != nil {
+		log.Error().Err(err).Str("event_type", event.Type).Msg("failed to record audit event")
+	}
+}
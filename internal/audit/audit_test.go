@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+)
+
+func TestRecorder_RecordsStructuredEvents(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	positionID, err := persistence.NewPositionRepository(db).Create(&persistence.Position{
+		Platform:   "kalshi",
+		MarketID:   "KXBTC-1",
+		EntryPrice: 0.9,
+		Quantity:   10,
+		Side:       "YES",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	repo := persistence.NewEventRepository(db)
+	recorder := NewRecorder(repo)
+	recorder.Entry("kalshi", "KXBTC-1", position.EntryResult{PositionID: positionID, Side: "YES", EntryPrice: 0.9, Quantity: 10}, true)
+	recorder.Exit(position.ExitResult{PositionID: positionID, ExitReason: position.ExitReasonStopLoss, ExitPrice: 0.75, RealizedPnL: -1.5})
+	recorder.APIError("kalshi", "", "scan", errors.New("connection refused"))
+	recorder.Halt("kalshi", CausePlatformStatus, "exchange closed")
+	recorder.Resume("kalshi", CausePlatformStatus)
+	recorder.ParameterChange("volatility_override:SOL", nil, 0.9, "unlock")
+	recorder.ParameterRevert(800, 1000, 0.2, map[string]float64{"probability_threshold": 0.88})
+
+	events, err := repo.Query(persistence.EventFilter{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != len(Types) {
+		t.Fatalf("expected %d events, got %d", len(Types), len(events))
+	}
+
+	byType := make(map[string]*persistence.Event)
+	for _, e := range events {
+		byType[e.Type] = e
+	}
+
+	entry := byType[TypeEntry]
+	if entry == nil || entry.Platform != "kalshi" || entry.MarketID != "KXBTC-1" {
+		t.Fatalf("unexpected entry event %+v", entry)
+	}
+	if entry.PositionID == nil || *entry.PositionID != positionID {
+		t.Errorf("expected entry on position %d, got %v", positionID, entry.PositionID)
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal([]byte(entry.Details), &details); err != nil {
+		t.Fatalf("entry details are not JSON: %v", err)
+	}
+	if details["side"] != "YES" || details["entry_price"] != 0.9 || details["dry_run"] != true {
+		t.Errorf("unexpected entry details %v", details)
+	}
+
+	exit := byType[TypeExit]
+	if exit == nil || exit.PositionID == nil || *exit.PositionID != positionID {
+		t.Fatalf("unexpected exit event %+v", exit)
+	}
+	if err := json.Unmarshal([]byte(exit.Details), &details); err != nil {
+		t.Fatalf("exit details are not JSON: %v", err)
+	}
+	if details["exit_reason"] != position.ExitReasonStopLoss || details["realized_pnl"] != -1.5 {
+		t.Errorf("unexpected exit details %v", details)
+	}
+
+	apiError := byType[TypeAPIError]
+	if apiError == nil || apiError.PositionID != nil {
+		t.Fatalf("unexpected api error event %+v", apiError)
+	}
+	if err := json.Unmarshal([]byte(apiError.Details), &details); err != nil {
+		t.Fatalf("api error details are not JSON: %v", err)
+	}
+	if details["operation"] != "scan" || details["error"] != "connection refused" {
+		t.Errorf("unexpected api error details %v", details)
+	}
+
+	change := byType[TypeParameterChange]
+	if err := json.Unmarshal([]byte(change.Details), &details); err != nil {
+		t.Fatalf("parameter change details are not JSON: %v", err)
+	}
+	if details["parameter"] != "volatility_override:SOL" || details["from"] != nil || details["to"] != 0.9 {
+		t.Errorf("unexpected parameter change details %v", details)
+	}
+}
+
+func TestRecorder_NilRecordsNothing(t *testing.T) {
+	var recorder *Recorder
+	recorder.Halt("kalshi", CauseLedger, "mismatch")
+	recorder.Exit(position.ExitResult{PositionID: 1})
+}
@@ -0,0 +1,488 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/risk"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ExitReasonEndOfData is used for positions still open when the replay ends.
+// They are marked to market at the last replayed price.
+const ExitReasonEndOfData = "end_of_data"
+
+// Config contains the configuration for a backtest run.
+type Config struct {
+	// Parameters are the trading parameters under evaluation. Replayed
+	// entries need a safety margin of VolatilitySafetyMargin, if set, to be
+	// valid rather than risky.
+	Parameters config.Parameters
+	// Sizer is the position sizing configuration.
+	Sizer sizing.SizerConfig
+	// KellyDrawdown scales the Kelly fraction down while the run is in
+	// drawdown (see position.Manager.SetKellyDrawdown).
+	KellyDrawdown []sizing.DrawdownStep
+	// Bankrolls maps platform name to its starting bankroll.
+	Bankrolls map[string]float64
+	// Risk holds the portfolio limits checked before each entry.
+	Risk risk.Limits
+	// AllowRisky allows entries with a risky volatility recommendation.
+	AllowRisky bool
+	// BestStrikePerEvent enters only the best-valued strike of each event.
+	BestStrikePerEvent bool
+	// MigrationsDir is the path to the SQL migrations directory.
+	MigrationsDir string
+}
+
+// FromConfig returns the backtest configuration of the bot configured by
+// cfg: its parameters, sizing, bankrolls and risk limits, as the live bot
+// uses them.
+func FromConfig(cfg *config.Config, migrationsDir string) Config {
+	var kellySteps []sizing.DrawdownStep
+	for _, step := range cfg.Parameters.KellyDrawdown {
+		kellySteps = append(kellySteps, sizing.DrawdownStep{Drawdown: step.Drawdown, Scale: step.Scale})
+	}
+	return Config{
+		Parameters: cfg.Parameters,
+		Sizer: sizing.SizerConfig{
+			KellyFraction:   cfg.Parameters.KellyFraction,
+			MinPosition:     1.0,
+			MaxBankrollPct:  0.20,
+			MaxLiquidityPct: cfg.Parameters.MaxLiquidityPct,
+			AmountDecimals:  cfg.Precision.AmountDecimals,
+			Correlation:     cfg.Parameters.KellyCorrelation,
+		},
+		KellyDrawdown: kellySteps,
+		Bankrolls: map[string]float64{
+			"polymarket": cfg.Bankroll.Polymarket,
+			"kalshi":     cfg.Bankroll.Kalshi,
+		},
+		Risk: risk.Limits{
+			MaxAssetExposure:       cfg.Risk.MaxAssetExposure,
+			MaxOpenPositions:       cfg.Risk.MaxOpenPositions,
+			MaxDirectionalExposure: cfg.Risk.MaxDirectionalExposure,
+			MaxDailyVaR:            cfg.Risk.MaxDailyVaR,
+			VaRConfidence:          cfg.Risk.VaRConfidence,
+		},
+		BestStrikePerEvent: cfg.Scan.BestStrikePerEvent,
+		MigrationsDir:      migrationsDir,
+	}
+}
+
+// Trade is a single completed round trip in the trade log.
+type Trade struct {
+	PositionID   int64
+	Platform     string
+	MarketID     string
+	MarketTitle  string
+	Side         string
+	EntryPrice   float64
+	ExitPrice    float64
+	Quantity     float64
+	RealizedPnL  float64
+	SafetyMargin float64
+	EntryTime    time.Time
+	ExitTime     time.Time
+	ExitReason   string
+}
+
+// EquityPoint is the total account value at a point in time.
+type EquityPoint struct {
+	Timestamp time.Time
+	Equity    float64
+}
+
+// Report contains the full output of a backtest run.
+type Report struct {
+	Trades  []Trade
+	Equity  []EquityPoint
+	Skips   map[string]int
+	Errors  int
+	Summary Summary
+}
+
+// Engine replays historical snapshots through the live Scanner, Sizer and
+// position Manager.
+type Engine struct {
+	config Config
+}
+
+// NewEngine creates a new backtest engine with the given configuration.
+func NewEngine(config Config) *Engine {
+	return &Engine{config: config}
+}
+
+// run holds the state of a single backtest run.
+type run struct {
+	config     Config
+	now        time.Time
+	analyzer   *ReplayAnalyzer
+	scanner    *scanner.Scanner
+	manager    *position.Manager
+	monitor    *position.Monitor
+	positions  *persistence.PositionRepository
+	bankrolls  *persistence.BankrollRepository
+	platforms  []string
+	lastPrices map[int64]float64
+	entryTimes map[int64]time.Time
+	report     *Report
+}
+
+// Run replays the snapshots in order and returns the resulting report.
+// Each run uses a fresh in-memory database so runs are independent.
+func (e *Engine) Run(snapshots []Snapshot) (*Report, error) {
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots to replay")
+	}
+
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("open backtest db: %w", err)
+	}
+	defer db.Close()
+	// Each connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	if err := persistence.RunMigrations(db, e.config.MigrationsDir); err != nil {
+		return nil, fmt.Errorf("run migrations: %w", err)
+	}
+
+	r := &run{
+		config:     e.config,
+		analyzer:   NewReplayAnalyzer(),
+		scanner:    scanner.NewScanner(e.config.Parameters),
+		monitor:    position.NewMonitor(e.config.Parameters.StopLossPercent),
+		positions:  persistence.NewPositionRepository(db),
+		bankrolls:  persistence.NewBankrollRepository(db),
+		lastPrices: make(map[int64]float64),
+		entryTimes: make(map[int64]time.Time),
+		report:     &Report{Skips: make(map[string]int)},
+	}
+	r.analyzer.validMargin = e.config.Parameters.VolatilitySafetyMargin
+	clock := func() time.Time { return r.now }
+	r.scanner.SetClock(clock)
+	if e.config.Parameters.StopLossType != "" {
+		if err := r.monitor.SetStopLossType(e.config.Parameters.StopLossType); err != nil {
+			return nil, err
+		}
+	}
+	r.monitor.SetTakeProfitPercent(e.config.Parameters.TakeProfitPercent)
+	r.monitor.SetTimeDecayLead(time.Duration(e.config.Parameters.TimeDecayExitHours * float64(time.Hour)))
+	if err := r.monitor.SetDecayCheckpoints(e.config.Parameters.DecayRecheckPoints); err != nil {
+		return nil, err
+	}
+	r.manager = position.NewManager(r.positions, r.bankrolls, r.analyzer, sizing.NewSizer(e.config.Sizer))
+	r.manager.SetAllowRisky(e.config.AllowRisky)
+	r.manager.SetBestStrikePerEvent(e.config.BestStrikePerEvent)
+	if err := r.manager.SetKellyDrawdown(e.config.KellyDrawdown); err != nil {
+		return nil, err
+	}
+	checker := risk.NewChecker(e.config.Risk)
+	checker.SetClock(clock)
+	r.manager.SetRiskChecker(checker)
+	r.manager.SetClock(clock)
+
+	for _, snap := range snapshots {
+		if err := r.step(snap); err != nil {
+			return nil, fmt.Errorf("replay snapshot %s: %w", snap.Timestamp.Format(time.RFC3339), err)
+		}
+	}
+
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+
+	var initial float64
+	for _, name := range r.platforms {
+		initial += e.config.Bankrolls[name]
+	}
+	r.report.Summary = Summarize(r.report.Trades, r.report.Equity, initial)
+	return r.report, nil
+}
+
+// step replays a single snapshot: monitor open positions, then scan for entries.
+func (r *run) step(snap Snapshot) error {
+	r.now = snap.Timestamp
+	r.analyzer.Record(snap)
+
+	byPlatform := make(map[string][]types.Market)
+	index := make(map[string]types.Market)
+	for _, m := range snap.Markets {
+		byPlatform[m.Platform] = append(byPlatform[m.Platform], m)
+		index[marketKey(m.Platform, m.ID)] = m
+	}
+
+	for name := range byPlatform {
+		if err := r.ensureBankroll(name); err != nil {
+			return err
+		}
+	}
+
+	if err := r.monitorPositions(index); err != nil {
+		return err
+	}
+
+	if _, err := r.manager.AdjustKellyFraction(); err != nil {
+		return err
+	}
+
+	for _, name := range r.platforms {
+		markets, ok := byPlatform[name]
+		if !ok {
+			continue
+		}
+		if err := r.scanPlatform(&replayPlatform{name: name, markets: markets}); err != nil {
+			return err
+		}
+	}
+
+	return r.recordEquity()
+}
+
+// ensureBankroll initializes the bankroll the first time a platform appears.
+func (r *run) ensureBankroll(name string) error {
+	for _, p := range r.platforms {
+		if p == name {
+			return nil
+		}
+	}
+	if err := r.bankrolls.Initialize(name, r.config.Bankrolls[name]); err != nil {
+		return fmt.Errorf("initialize bankroll: %w", err)
+	}
+	r.platforms = append(r.platforms, name)
+	return nil
+}
+
+// monitorPositions applies resolution, stop loss, decay checkpoint and
+// volatility exits.
+func (r *run) monitorPositions(index map[string]types.Market) error {
+	open, err := r.positions.GetOpen()
+	if err != nil {
+		return fmt.Errorf("get open positions: %w", err)
+	}
+
+	for _, pos := range open {
+		market, ok := index[marketKey(pos.Platform, pos.MarketID)]
+		if !ok {
+			continue
+		}
+		price := sidePrice(market, pos.Side)
+		r.lastPrices[pos.ID] = price
+
+		if market.Closed || !market.EndDate.After(r.now) {
+			if err := r.exit(pos, settlementPrice(market, pos.Side), position.ExitReasonResolved); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if r.monitor.Trailing() {
+			if err := r.manager.TrackPeakPrice(pos, price); err != nil {
+				return fmt.Errorf("track peak price: %w", err)
+			}
+		}
+
+		if r.monitor.CheckStopLoss(pos, price) {
+			if err := r.exit(pos, price, position.ExitReasonStopLoss); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if r.monitor.CheckTakeProfit(pos, price) {
+			if err := r.exit(pos, price, position.ExitReasonTakeProfit); err != nil {
+				return err
+			}
+			continue
+		}
+
+		shouldExit, err := r.monitor.CheckTimeDecayExit(context.Background(), pos, r.analyzer, r.now)
+		if err != nil {
+			log.Debug().Err(err).Int64("position_id", pos.ID).Msg("backtest time decay check failed")
+			r.report.Errors++
+			continue
+		}
+		if shouldExit {
+			if err := r.exit(pos, price, position.ExitReasonTimeDecay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Positions are stored at wall-clock time; decay is measured from
+		// the replayed entry
+		if entry, ok := r.entryTimes[pos.ID]; ok {
+			pos.EntryTime = entry
+		}
+		_, shouldExit, err = r.monitor.CheckDecayCheckpoint(context.Background(), pos, r.analyzer, r.now)
+		if err != nil {
+			log.Debug().Err(err).Int64("position_id", pos.ID).Msg("backtest decay checkpoint failed")
+			r.report.Errors++
+			continue
+		}
+		if shouldExit {
+			if err := r.exit(pos, price, position.ExitReasonVolatility); err != nil {
+				return err
+			}
+			continue
+		}
+
+		shouldExit, err = r.monitor.CheckVolatilityExit(context.Background(), pos, r.analyzer, market.EndDate.Sub(r.now))
+		if err != nil {
+			log.Debug().Err(err).Int64("position_id", pos.ID).Msg("backtest volatility check failed")
+			r.report.Errors++
+			continue
+		}
+		if shouldExit {
+			if err := r.exit(pos, price, position.ExitReasonVolatility); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanPlatform runs the live scanner and entry logic against a replayed platform.
+func (r *run) scanPlatform(p *replayPlatform) error {
+	eligible, err := r.scanner.Scan(context.Background(), p)
+	if err != nil {
+		return fmt.Errorf("scan %s: %w", p.Name(), err)
+	}
+
+	for _, market := range r.manager.SelectStrikes(context.Background(), eligible) {
+		result, err := r.manager.ProcessEntry(context.Background(), market, true)
+		if err != nil {
+			log.Debug().Err(err).Str("market_id", market.Market.ID).Msg("backtest entry failed")
+			r.report.Errors++
+			continue
+		}
+		if result.Skipped {
+			r.report.Skips[result.SkipReason]++
+			continue
+		}
+		r.entryTimes[result.PositionID] = r.now
+		r.lastPrices[result.PositionID] = result.EntryPrice
+	}
+
+	return nil
+}
+
+// exit closes a position through the Manager and appends it to the trade log.
+func (r *run) exit(pos *persistence.Position, price float64, reason string) error {
+	result, err := r.manager.ExecuteExit(context.Background(), pos.ID, price, reason, true)
+	if err != nil {
+		return fmt.Errorf("exit position %d: %w", pos.ID, err)
+	}
+
+	r.report.Trades = append(r.report.Trades, Trade{
+		PositionID:   pos.ID,
+		Platform:     pos.Platform,
+		MarketID:     pos.MarketID,
+		MarketTitle:  pos.MarketTitle,
+		Side:         pos.Side,
+		EntryPrice:   result.EntryPrice,
+		ExitPrice:    result.ExitPrice,
+		Quantity:     result.Quantity,
+		RealizedPnL:  result.RealizedPnL,
+		SafetyMargin: pos.SafetyMarginAtEntry,
+		EntryTime:    r.entryTimes[pos.ID],
+		ExitTime:     r.now,
+		ExitReason:   reason,
+	})
+	delete(r.lastPrices, pos.ID)
+	return nil
+}
+
+// recordEquity appends the current bankroll plus open position value to the curve.
+func (r *run) recordEquity() error {
+	// Only platforms seen in the replay count; migrations may seed others
+	var equity float64
+	for _, name := range r.platforms {
+		bankroll, err := r.bankrolls.Get(name)
+		if err != nil {
+			return fmt.Errorf("get bankroll: %w", err)
+		}
+		if bankroll != nil {
+			equity += bankroll.CurrentAmount
+		}
+	}
+
+	open, err := r.positions.GetOpen()
+	if err != nil {
+		return fmt.Errorf("get open positions: %w", err)
+	}
+	for _, pos := range open {
+		price, ok := r.lastPrices[pos.ID]
+		if !ok {
+			price = pos.EntryPrice
+		}
+		equity += price * pos.Quantity
+	}
+
+	r.report.Equity = append(r.report.Equity, EquityPoint{Timestamp: r.now, Equity: equity})
+	return nil
+}
+
+// finish closes positions still open at the end of the replay.
+func (r *run) finish() error {
+	open, err := r.positions.GetOpen()
+	if err != nil {
+		return fmt.Errorf("get open positions: %w", err)
+	}
+
+	for _, pos := range open {
+		price, ok := r.lastPrices[pos.ID]
+		if !ok {
+			price = pos.EntryPrice
+		}
+		if err := r.exit(pos, price, ExitReasonEndOfData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// marketKey returns a unique key for a market across platforms.
+func marketKey(platform, marketID string) string {
+	return platform + ":" + marketID
+}
+
+// sidePrice returns the market price for the side held.
+func sidePrice(market types.Market, side string) float64 {
+	if side == "NO" {
+		return market.OutcomeNoPrice
+	}
+	return market.OutcomeYesPrice
+}
+
+// settlementPrice returns the payout per contract (1.0 or 0.0) for the side
+// held in a resolved market. Winner flags on tokens take precedence; otherwise
+// the final price decides the outcome.
+func settlementPrice(market types.Market, side string) float64 {
+	for _, token := range market.Tokens {
+		if !token.Winner {
+			continue
+		}
+		if strings.EqualFold(token.Outcome, side) {
+			return 1.0
+		}
+		return 0.0
+	}
+
+	if sidePrice(market, side) >= 0.5 {
+		return 1.0
+	}
+	return 0.0
+}
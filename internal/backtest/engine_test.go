@@ -0,0 +1,224 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+var testStart = time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+// testEngine creates an engine with the live bot's default parameters.
+func testEngine() *Engine {
+	params := config.Parameters{
+		ProbabilityThreshold:   0.80,
+		VolatilitySafetyMargin: 1.5,
+		StopLossPercent:        0.15,
+		KellyFraction:          0.25,
+	}
+	return NewEngine(Config{
+		Parameters: params,
+		Sizer: sizing.SizerConfig{
+			KellyFraction:  params.KellyFraction,
+			MinPosition:    1.0,
+			MaxBankrollPct: 0.20,
+		},
+		Bankrolls:     map[string]float64{"polymarket": 100.0},
+		MigrationsDir: "../../migrations",
+	})
+}
+
+// historySnapshots returns one snapshot per day with BTC prices and no markets,
+// giving the analyzer enough daily history to calculate volatility.
+func historySnapshots() []Snapshot {
+	prices := []float64{60000, 60300, 59900, 60200, 60100, 60400}
+	snapshots := make([]Snapshot, 0, len(prices))
+	for i, p := range prices {
+		snapshots = append(snapshots, Snapshot{
+			Timestamp: testStart.Add(time.Duration(i) * 24 * time.Hour),
+			Prices:    map[string]float64{"BTC": p},
+		})
+	}
+	return snapshots
+}
+
+// btcMarket returns a BTC market snapshot entry closing at endDate.
+func btcMarket(yesPrice float64, endDate time.Time, closed bool) types.Market {
+	return types.Market{
+		ID:              "btc-50k",
+		Platform:        "polymarket",
+		Title:           "Will Bitcoin be above $50,000 on Jan 16?",
+		EndDate:         endDate,
+		Active:          true,
+		Closed:          closed,
+		Liquidity:       1000,
+		OutcomeYesPrice: yesPrice,
+		OutcomeNoPrice:  1 - yesPrice,
+	}
+}
+
+func marketSnapshot(at time.Time, market types.Market) Snapshot {
+	return Snapshot{
+		Timestamp: at,
+		Markets:   []types.Market{market},
+		Prices:    map[string]float64{"BTC": 60500},
+	}
+}
+
+func TestRunResolvedWin(t *testing.T) {
+	snapshots := historySnapshots()
+	day := testStart.Add(6 * 24 * time.Hour)
+	endDate := day.Add(12 * time.Hour)
+	snapshots = append(snapshots,
+		marketSnapshot(day, btcMarket(0.85, endDate, false)),
+		marketSnapshot(day.Add(6*time.Hour), btcMarket(0.92, endDate, false)),
+		marketSnapshot(endDate, btcMarket(0.99, endDate, true)),
+	)
+
+	report, err := testEngine().Run(snapshots)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d (skips=%v errors=%d)", len(report.Trades), report.Skips, report.Errors)
+	}
+
+	trade := report.Trades[0]
+	if trade.ExitReason != position.ExitReasonResolved {
+		t.Errorf("expected exit reason %s, got %s", position.ExitReasonResolved, trade.ExitReason)
+	}
+	if trade.ExitPrice != 1.0 {
+		t.Errorf("expected settlement at 1.0, got %f", trade.ExitPrice)
+	}
+	if !trade.EntryTime.Equal(day) {
+		t.Errorf("expected entry at %v, got %v", day, trade.EntryTime)
+	}
+	if trade.RealizedPnL <= 0 {
+		t.Errorf("expected positive PnL, got %f", trade.RealizedPnL)
+	}
+
+	if report.Summary.WinRate != 1.0 {
+		t.Errorf("expected win rate 1.0, got %f", report.Summary.WinRate)
+	}
+	if len(report.Equity) != len(snapshots) {
+		t.Errorf("expected %d equity points, got %d", len(snapshots), len(report.Equity))
+	}
+	final := report.Equity[len(report.Equity)-1].Equity
+	if diff := final - (100.0 + trade.RealizedPnL); diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected final equity %f, got %f", 100.0+trade.RealizedPnL, final)
+	}
+}
+
+func TestRunRequiresSafetyMargin(t *testing.T) {
+	snapshots := historySnapshots()
+	day := testStart.Add(6 * 24 * time.Hour)
+	endDate := day.Add(12 * time.Hour)
+	snapshots = append(snapshots,
+		marketSnapshot(day, btcMarket(0.85, endDate, false)),
+		marketSnapshot(endDate, btcMarket(0.99, endDate, true)),
+	)
+
+	// The trade taken at the default margin is risky past the one required
+	engine := testEngine()
+	engine.config.Parameters.VolatilitySafetyMargin = volatility.MaxSafetyMargin + 1
+	report, err := engine.Run(snapshots)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Trades) != 0 || len(report.Skips) == 0 {
+		t.Errorf("expected the entry skipped as risky, got %d trades (skips=%v)", len(report.Trades), report.Skips)
+	}
+}
+
+func TestRunStopLoss(t *testing.T) {
+	snapshots := historySnapshots()
+	day := testStart.Add(6 * 24 * time.Hour)
+	endDate := day.Add(12 * time.Hour)
+	snapshots = append(snapshots,
+		marketSnapshot(day, btcMarket(0.85, endDate, false)),
+		marketSnapshot(day.Add(2*time.Hour), btcMarket(0.60, endDate, false)),
+	)
+
+	report, err := testEngine().Run(snapshots)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(report.Trades))
+	}
+
+	trade := report.Trades[0]
+	if trade.ExitReason != position.ExitReasonStopLoss {
+		t.Errorf("expected exit reason %s, got %s", position.ExitReasonStopLoss, trade.ExitReason)
+	}
+	if trade.ExitPrice != 0.60 {
+		t.Errorf("expected exit at 0.60, got %f", trade.ExitPrice)
+	}
+	if report.Summary.LosingTrades != 1 {
+		t.Errorf("expected 1 losing trade, got %d", report.Summary.LosingTrades)
+	}
+	if report.Summary.MaxDrawdown <= 0 {
+		t.Errorf("expected a drawdown, got %f", report.Summary.MaxDrawdown)
+	}
+}
+
+func TestRunClosesOpenPositionsAtEndOfData(t *testing.T) {
+	snapshots := historySnapshots()
+	day := testStart.Add(6 * 24 * time.Hour)
+	endDate := day.Add(12 * time.Hour)
+	snapshots = append(snapshots,
+		marketSnapshot(day, btcMarket(0.85, endDate, false)),
+		marketSnapshot(day.Add(time.Hour), btcMarket(0.88, endDate, false)),
+	)
+
+	report, err := testEngine().Run(snapshots)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(report.Trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(report.Trades))
+	}
+	if report.Trades[0].ExitReason != ExitReasonEndOfData {
+		t.Errorf("expected exit reason %s, got %s", ExitReasonEndOfData, report.Trades[0].ExitReason)
+	}
+	if report.Trades[0].ExitPrice != 0.88 {
+		t.Errorf("expected exit at last price 0.88, got %f", report.Trades[0].ExitPrice)
+	}
+}
+
+func TestRunNoSnapshots(t *testing.T) {
+	if _, err := testEngine().Run(nil); err == nil {
+		t.Error("expected error for empty snapshots")
+	}
+}
+
+func TestSettlementPrice(t *testing.T) {
+	market := types.Market{
+		OutcomeYesPrice: 0.2,
+		OutcomeNoPrice:  0.8,
+		Tokens: []types.Token{
+			{Outcome: "Yes", Winner: true},
+			{Outcome: "No"},
+		},
+	}
+
+	if got := settlementPrice(market, "YES"); got != 1.0 {
+		t.Errorf("expected YES to win via token flag, got %f", got)
+	}
+	if got := settlementPrice(market, "NO"); got != 0.0 {
+		t.Errorf("expected NO to lose via token flag, got %f", got)
+	}
+
+	market.Tokens = nil
+	if got := settlementPrice(market, "NO"); got != 1.0 {
+		t.Errorf("expected NO to win via final price, got %f", got)
+	}
+}
@@ -0,0 +1,163 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"prediction-bot/internal/datasource"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+// replayPlatform implements platform.Platform by serving the markets of the
+// current snapshot for a single platform.
+type replayPlatform struct {
+	name    string
+	markets []types.Market
+}
+
+// Name returns the platform identifier.
+func (p *replayPlatform) Name() string {
+	return p.name
+}
+
+// ListMarkets returns the markets of the current snapshot.
+func (p *replayPlatform) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
+	return p.markets, nil
+}
+
+// GetOrderBook returns an empty order book (snapshots carry prices only).
+func (p *replayPlatform) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	return &types.OrderBook{TokenID: tokenID}, nil
+}
+
+// GetBalance is not tracked by the replay platform.
+func (p *replayPlatform) GetBalance() (float64, error) {
+	return 0, nil
+}
+
+// GetPositions is not tracked by the replay platform.
+func (p *replayPlatform) GetPositions() ([]types.Position, error) {
+	return []types.Position{}, nil
+}
+
+// Resolution returns how a replayed market stands at now: resolved once it
+// is closed or past its end date, to the side that settles at 1.0.
+func Resolution(market types.Market, now time.Time) types.Resolution {
+	resolution := types.Resolution{MarketID: market.ID}
+	if !market.Closed && market.EndDate.After(now) {
+		return resolution
+	}
+	resolution.Resolved = true
+	resolution.Outcome = "NO"
+	if settlementPrice(market, "YES") == 1.0 {
+		resolution.Outcome = "YES"
+	}
+	return resolution
+}
+
+// ReplayAnalyzer implements position.VolatilityAnalyzer from the underlying
+// prices recorded in the replayed snapshots, using the same volatility
+// calculation and analysis as volatility.Service.
+type ReplayAnalyzer struct {
+	mapper  *datasource.SymbolMapper
+	history map[string][]types.Price
+	// validMargin, if set, is the safety margin a trade needs to be valid
+	// rather than risky, in place of volatility.SafetyMarginValidThreshold.
+	validMargin float64
+}
+
+// NewReplayAnalyzer creates an analyzer with an empty price history.
+func NewReplayAnalyzer() *ReplayAnalyzer {
+	return &ReplayAnalyzer{
+		mapper:  datasource.NewSymbolMapper(),
+		history: make(map[string][]types.Price),
+	}
+}
+
+// Record appends the snapshot's underlying prices to the history.
+func (a *ReplayAnalyzer) Record(snap Snapshot) {
+	for symbol, price := range snap.Prices {
+		a.history[symbol] = append(a.history[symbol], types.Price{
+			Symbol:    symbol,
+			Price:     price,
+			Timestamp: snap.Timestamp,
+			Source:    "backtest",
+		})
+	}
+}
+
+// AnalyzeAsset analyzes the asset using the prices replayed so far.
+func (a *ReplayAnalyzer) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+	result := volatility.ServiceResult{
+		Asset:       asset,
+		StrikePrice: strikePrice,
+		Direction:   direction,
+		TimeToClose: timeToClose,
+		IsCrypto:    a.mapper.IsCrypto(asset),
+		AssetClass:  a.mapper.AssetClass(asset),
+	}
+
+	history := a.history[asset]
+	if len(history) == 0 {
+		return result, fmt.Errorf("no replayed prices for %s", asset)
+	}
+	latest := history[len(history)-1]
+	result.CurrentPrice = latest.Price
+	result.Timestamp = latest.Timestamp
+	result.AnalysisTimeToClose = volatility.AnalysisTimeToClose(result.AssetClass, result.Timestamp, result.Timestamp.Add(timeToClose))
+
+	// CalculateVolatility expects daily prices, like the live data sources return
+	tradingDays := volatility.TradingDaysFor(result.AssetClass)
+	result.Volatility = volatility.CalculateVolatilityDays(dailyCloses(history), tradingDays)
+	if result.Volatility <= 0 {
+		return result, fmt.Errorf("could not calculate volatility for %s: insufficient data", asset)
+	}
+
+	result.TermStructure = volatility.CalculateTermStructure(history, volatility.DefaultHorizons, tradingDays)
+
+	analysis := volatility.Analyze(volatility.AnalysisInput{
+		CurrentPrice:      result.CurrentPrice,
+		StrikePrice:       strikePrice,
+		Direction:         direction,
+		Volatility:        result.Volatility,
+		TimeToCloseHours:  result.AnalysisTimeToClose.Hours(),
+		IsCrypto:          result.IsCrypto,
+		AnnualizationDays: tradingDays,
+		TermStructure:     result.TermStructure,
+	})
+
+	result.Volatility = analysis.Volatility
+	result.VolatilityHorizon = analysis.Horizon
+	result.DistanceToStrike = analysis.DistanceToStrike
+	result.ExpectedMove = analysis.ExpectedMove
+	result.SafetyMargin = analysis.SafetyMargin
+	result.Recommendation = analysis.Recommendation
+	if a.validMargin > 0 && result.Recommendation != volatility.RecommendationReject {
+		result.Recommendation = volatility.RecommendationRisky
+		if result.SafetyMargin >= a.validMargin {
+			result.Recommendation = volatility.RecommendationValid
+		}
+	}
+
+	return result, nil
+}
+
+// dailyCloses reduces a price history to the last price of each UTC day.
+func dailyCloses(history []types.Price) []types.Price {
+	var closes []types.Price
+	for _, p := range history {
+		if n := len(closes); n > 0 && sameDay(closes[n-1].Timestamp, p.Timestamp) {
+			closes[n-1] = p
+			continue
+		}
+		closes = append(closes, p)
+	}
+	return closes
+}
+
+// sameDay reports whether two timestamps fall on the same UTC day.
+func sameDay(a, b time.Time) bool {
+	return a.UTC().Truncate(24 * time.Hour).Equal(b.UTC().Truncate(24 * time.Hour))
+}
@@ -0,0 +1,115 @@
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Snapshot is a point-in-time view of listed markets and underlying asset prices.
+type Snapshot struct {
+	// Timestamp is when the snapshot was recorded.
+	Timestamp time.Time `json:"timestamp"`
+	// Markets are the markets listed on all platforms at Timestamp.
+	Markets []types.Market `json:"markets"`
+	// Prices maps underlying asset symbols (e.g. "BTC") to their spot price.
+	Prices map[string]float64 `json:"prices"`
+}
+
+// LoadSnapshots reads snapshots from a JSONL file (one snapshot per line)
+// or from a directory of .json/.jsonl files. Snapshots are returned sorted
+// by timestamp.
+func LoadSnapshots(path string) ([]Snapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat snapshots: %w", err)
+	}
+
+	var files []string
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("read snapshots dir: %w", err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".jsonl")) {
+				continue
+			}
+			files = append(files, filepath.Join(path, name))
+		}
+	} else {
+		files = []string{path}
+	}
+
+	var snapshots []Snapshot
+	for _, file := range files {
+		loaded, err := loadSnapshotFile(file)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, loaded...)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+
+	return snapshots, nil
+}
+
+// loadSnapshotFile reads all snapshots from a single file.
+func loadSnapshotFile(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var snap Snapshot
+		if err := json.Unmarshal([]byte(text), &snap); err != nil {
+			return nil, fmt.Errorf("parse %s line %d: %w", path, line, err)
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return snapshots, nil
+}
+
+// FilterRange returns the snapshots with timestamps in [start, end].
+// A zero start or end leaves that side of the range open.
+func FilterRange(snapshots []Snapshot, start, end time.Time) []Snapshot {
+	var filtered []Snapshot
+	for _, snap := range snapshots {
+		if !start.IsZero() && snap.Timestamp.Before(start) {
+			continue
+		}
+		if !end.IsZero() && snap.Timestamp.After(end) {
+			continue
+		}
+		filtered = append(filtered, snap)
+	}
+	return filtered
+}
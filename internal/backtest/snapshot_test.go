@@ -0,0 +1,67 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSnapshotsSortsByTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"timestamp":"2026-01-02T00:00:00Z","prices":{"BTC":61000}}
+
+{"timestamp":"2026-01-01T00:00:00Z","prices":{"BTC":60000},"markets":[{"id":"m1","platform":"kalshi","title":"x"}]}
+`
+	if err := os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := LoadSnapshots(dir)
+	if err != nil {
+		t.Fatalf("LoadSnapshots failed: %v", err)
+	}
+
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+	}
+	if snapshots[0].Prices["BTC"] != 60000 {
+		t.Errorf("expected earliest snapshot first, got BTC=%f", snapshots[0].Prices["BTC"])
+	}
+	if len(snapshots[0].Markets) != 1 {
+		t.Errorf("expected 1 market in first snapshot, got %d", len(snapshots[0].Markets))
+	}
+}
+
+func TestLoadSnapshotsInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.jsonl")
+	if err := os.WriteFile(path, []byte("{not json}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadSnapshots(path); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestFilterRange(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		{Timestamp: base},
+		{Timestamp: base.Add(24 * time.Hour)},
+		{Timestamp: base.Add(48 * time.Hour)},
+	}
+
+	filtered := FilterRange(snapshots, base.Add(time.Hour), time.Time{})
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 snapshots after start, got %d", len(filtered))
+	}
+
+	filtered = FilterRange(snapshots, base, base.Add(24*time.Hour))
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 snapshots in closed range, got %d", len(filtered))
+	}
+}
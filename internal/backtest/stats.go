@@ -0,0 +1,129 @@
+package backtest
+
+import (
+	"math"
+	"time"
+)
+
+// Summary contains aggregate statistics for a backtest run.
+type Summary struct {
+	Start          time.Time
+	End            time.Time
+	TotalTrades    int
+	WinningTrades  int
+	LosingTrades   int
+	WinRate        float64 // 0.0 - 1.0
+	TotalPnL       float64
+	InitialEquity  float64
+	FinalEquity    float64
+	ReturnPercent  float64
+	SharpeRatio    float64 // Annualized, from equity curve returns
+	MaxDrawdown    float64 // As a decimal (0.15 = 15%)
+	AvgHoldingTime time.Duration
+}
+
+// Summarize computes summary statistics from a trade log and equity curve.
+func Summarize(trades []Trade, equity []EquityPoint, initialEquity float64) Summary {
+	summary := Summary{
+		TotalTrades:   len(trades),
+		InitialEquity: initialEquity,
+		FinalEquity:   initialEquity,
+		SharpeRatio:   SharpeRatio(equity),
+		MaxDrawdown:   MaxDrawdown(equity),
+	}
+
+	if len(equity) > 0 {
+		summary.Start = equity[0].Timestamp
+		summary.End = equity[len(equity)-1].Timestamp
+	}
+
+	var totalHolding time.Duration
+	for _, t := range trades {
+		summary.TotalPnL += t.RealizedPnL
+		if t.RealizedPnL > 0 {
+			summary.WinningTrades++
+		} else if t.RealizedPnL < 0 {
+			summary.LosingTrades++
+		}
+		totalHolding += t.ExitTime.Sub(t.EntryTime)
+	}
+
+	if len(trades) > 0 {
+		summary.WinRate = float64(summary.WinningTrades) / float64(len(trades))
+		summary.AvgHoldingTime = totalHolding / time.Duration(len(trades))
+	}
+
+	// Final equity reflects closed trades only, after end-of-data exits
+	summary.FinalEquity = initialEquity + summary.TotalPnL
+	if initialEquity > 0 {
+		summary.ReturnPercent = summary.TotalPnL / initialEquity * 100
+	}
+
+	return summary
+}
+
+// SharpeRatio computes the annualized Sharpe ratio of the equity curve's
+// period returns (risk-free rate of zero). The annualization factor is derived
+// from the average spacing between equity points. Returns 0 if there are
+// fewer than three points or returns have no variance.
+func SharpeRatio(equity []EquityPoint) float64 {
+	if len(equity) < 3 {
+		return 0
+	}
+
+	returns := make([]float64, 0, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		prev := equity[i-1].Equity
+		if prev <= 0 {
+			continue
+		}
+		returns = append(returns, (equity[i].Equity-prev)/prev)
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, r := range returns {
+		sum += r
+	}
+	mean := sum / float64(len(returns))
+
+	var sumSquaredDiff float64
+	for _, r := range returns {
+		diff := r - mean
+		sumSquaredDiff += diff * diff
+	}
+	stdDev := math.Sqrt(sumSquaredDiff / float64(len(returns)-1))
+	if stdDev == 0 {
+		return 0
+	}
+
+	span := equity[len(equity)-1].Timestamp.Sub(equity[0].Timestamp)
+	if span <= 0 {
+		return 0
+	}
+	avgPeriod := span / time.Duration(len(equity)-1)
+	periodsPerYear := float64(365*24*time.Hour) / float64(avgPeriod)
+
+	return mean / stdDev * math.Sqrt(periodsPerYear)
+}
+
+// MaxDrawdown returns the largest peak-to-trough decline of the equity curve
+// as a fraction of the peak.
+func MaxDrawdown(equity []EquityPoint) float64 {
+	var peak, maxDrawdown float64
+	for _, point := range equity {
+		if point.Equity > peak {
+			peak = point.Equity
+		}
+		if peak <= 0 {
+			continue
+		}
+		drawdown := (peak - point.Equity) / peak
+		if drawdown > maxDrawdown {
+			maxDrawdown = drawdown
+		}
+	}
+	return maxDrawdown
+}
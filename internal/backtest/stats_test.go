@@ -0,0 +1,73 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func equityCurve(values ...float64) []EquityPoint {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]EquityPoint, len(values))
+	for i, v := range values {
+		points[i] = EquityPoint{Timestamp: start.Add(time.Duration(i) * 24 * time.Hour), Equity: v}
+	}
+	return points
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	dd := MaxDrawdown(equityCurve(100, 120, 90, 110, 130, 117))
+	// Peak 120 → trough 90 = 25%
+	if math.Abs(dd-0.25) > 1e-9 {
+		t.Errorf("expected drawdown 0.25, got %f", dd)
+	}
+
+	if dd := MaxDrawdown(equityCurve(100, 110, 120)); dd != 0 {
+		t.Errorf("expected no drawdown for rising curve, got %f", dd)
+	}
+}
+
+func TestSharpeRatio(t *testing.T) {
+	if s := SharpeRatio(equityCurve(100, 110)); s != 0 {
+		t.Errorf("expected 0 for too few points, got %f", s)
+	}
+	if s := SharpeRatio(equityCurve(100, 100, 100)); s != 0 {
+		t.Errorf("expected 0 for flat curve, got %f", s)
+	}
+
+	rising := SharpeRatio(equityCurve(100, 101, 103, 104, 106))
+	if rising <= 0 {
+		t.Errorf("expected positive Sharpe for rising curve, got %f", rising)
+	}
+	falling := SharpeRatio(equityCurve(100, 99, 97, 96, 94))
+	if falling >= 0 {
+		t.Errorf("expected negative Sharpe for falling curve, got %f", falling)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	entry := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []Trade{
+		{RealizedPnL: 5, EntryTime: entry, ExitTime: entry.Add(2 * time.Hour)},
+		{RealizedPnL: -2, EntryTime: entry, ExitTime: entry.Add(4 * time.Hour)},
+		{RealizedPnL: 3, EntryTime: entry, ExitTime: entry.Add(6 * time.Hour)},
+	}
+
+	s := Summarize(trades, equityCurve(100, 105, 103, 106), 100)
+
+	if s.TotalTrades != 3 || s.WinningTrades != 2 || s.LosingTrades != 1 {
+		t.Errorf("unexpected trade counts: %+v", s)
+	}
+	if math.Abs(s.WinRate-2.0/3.0) > 1e-9 {
+		t.Errorf("expected win rate 0.667, got %f", s.WinRate)
+	}
+	if s.TotalPnL != 6 || s.FinalEquity != 106 {
+		t.Errorf("expected PnL 6 and final equity 106, got %f and %f", s.TotalPnL, s.FinalEquity)
+	}
+	if math.Abs(s.ReturnPercent-6) > 1e-9 {
+		t.Errorf("expected return 6%%, got %f", s.ReturnPercent)
+	}
+	if s.AvgHoldingTime != 4*time.Hour {
+		t.Errorf("expected avg holding 4h, got %s", s.AvgHoldingTime)
+	}
+}
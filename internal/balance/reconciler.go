@@ -0,0 +1,158 @@
+// Package balance reconciles the bankroll the bot tracks for each platform
+// with the balance the platform reports, which deposits, withdrawals, fees
+// and trades made by hand change without the bot knowing.
+package balance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/persistence"
+
+	"github.com/rs/zerolog/log"
+)
+
+// matchTolerance is the discrepancy below which a balance matches its
+// bankroll: half a cent, the rounding of balances reported in cents.
+const matchTolerance = 0.005
+
+// BalanceGetter defines the interface for fetching a platform's balance.
+type BalanceGetter interface {
+	// GetBalance returns the available balance in dollars.
+	GetBalance() (float64, error)
+}
+
+// Tolerance is how far a platform's balance may be from its bankroll before
+// it is alerted.
+type Tolerance struct {
+	// Amount is the discrepancy in dollars, either way, within which the
+	// bankroll is left or adjusted.
+	Amount float64
+	// AutoAdjust sets the bankroll to the balance when the discrepancy is
+	// within Amount.
+	AutoAdjust bool
+}
+
+// Reconciler compares each platform's bankroll with its balance, records
+// the comparison and adjusts the bankroll or alerts according to the
+// platform's tolerance. A discrepancy beyond tolerance alerts once, and
+// again only after it has come back within tolerance.
+type Reconciler struct {
+	bankrolls  *persistence.BankrollRepository
+	checks     *persistence.BalanceCheckRepository
+	platforms  map[string]BalanceGetter
+	tolerances map[string]Tolerance
+	alerter    alert.Alerter
+	// alerted holds the platforms whose discrepancy has been alerted.
+	alerted map[string]bool
+}
+
+// NewReconciler creates a Reconciler recording its checks in checks.
+func NewReconciler(bankrolls *persistence.BankrollRepository, checks *persistence.BalanceCheckRepository) *Reconciler {
+	return &Reconciler{
+		bankrolls:  bankrolls,
+		checks:     checks,
+		platforms:  make(map[string]BalanceGetter),
+		tolerances: make(map[string]Tolerance),
+		alerted:    make(map[string]bool),
+	}
+}
+
+// SetPlatform registers a platform whose balance is checked, with its
+// tolerance.
+func (r *Reconciler) SetPlatform(name string, getter BalanceGetter, tolerance Tolerance) {
+	r.platforms[name] = getter
+	r.tolerances[name] = tolerance
+}
+
+// SetAlerter sets where discrepancies beyond tolerance are alerted. Without
+// one they are only logged.
+func (r *Reconciler) SetAlerter(alerter alert.Alerter) {
+	r.alerter = alerter
+}
+
+// Run checks every registered platform with a bankroll and returns the
+// checks recorded. A platform whose balance can't be fetched is logged and
+// skipped.
+func (r *Reconciler) Run() ([]*persistence.BalanceCheck, error) {
+	names := make([]string, 0, len(r.platforms))
+	for name := range r.platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var checks []*persistence.BalanceCheck
+	for _, name := range names {
+		check, err := r.check(name)
+		if err != nil {
+			return checks, err
+		}
+		if check != nil {
+			checks = append(checks, check)
+		}
+	}
+	return checks, nil
+}
+
+// check reconciles one platform, returning nil if it has no bankroll or its
+// balance couldn't be fetched.
+func (r *Reconciler) check(name string) (*persistence.BalanceCheck, error) {
+	bankroll, err := r.bankrolls.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if bankroll == nil {
+		return nil, nil
+	}
+
+	balance, err := r.platforms[name].GetBalance()
+	if err != nil {
+		log.Warn().Err(err).Str("platform", name).Msg("failed to fetch balance to reconcile")
+		return nil, nil
+	}
+
+	check := &persistence.BalanceCheck{Platform: name, Bankroll: bankroll.CurrentAmount, Balance: balance}
+	discrepancy := check.Discrepancy()
+	tolerance := r.tolerances[name]
+	switch {
+	case math.Abs(discrepancy) < matchTolerance:
+		check.Action = persistence.BalanceActionMatched
+	case math.Abs(discrepancy) <= tolerance.Amount && tolerance.AutoAdjust:
+		if err := r.bankrolls.Update(name, balance); err != nil {
+			return nil, fmt.Errorf("adjust bankroll: %w", err)
+		}
+		check.Action = persistence.BalanceActionAdjusted
+	case math.Abs(discrepancy) <= tolerance.Amount:
+		check.Action = persistence.BalanceActionRecorded
+	default:
+		check.Action = persistence.BalanceActionAlerted
+	}
+
+	if _, err := r.checks.Record(check); err != nil {
+		return nil, err
+	}
+
+	event := log.Info()
+	if check.Action != persistence.BalanceActionMatched {
+		event = log.Warn()
+	}
+	event.
+		Str("platform", name).
+		Float64("bankroll", check.Bankroll).
+		Float64("balance", balance).
+		Float64("discrepancy", discrepancy).
+		Str("action", check.Action).
+		Msg("bankroll reconciled with platform balance")
+
+	wasAlerted := r.alerted[name]
+	r.alerted[name] = check.Action == persistence.BalanceActionAlerted
+	if r.alerted[name] && !wasAlerted && r.alerter != nil {
+		r.alerter.Alert(alert.EventBalanceDiscrepancy, fmt.Sprintf(
+			"%s balance %.2f is %+.2f from the bankroll %.2f, beyond the %.2f tolerance",
+			name, balance, discrepancy, check.Bankroll, tolerance.Amount))
+	}
+
+	return check, nil
+}
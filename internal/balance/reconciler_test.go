@@ -0,0 +1,120 @@
+package balance
+
+import (
+	"errors"
+	"testing"
+
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/persistence"
+)
+
+// fixedBalance reports a set balance, or fails with err.
+type fixedBalance struct {
+	balance float64
+	err     error
+}
+
+func (f *fixedBalance) GetBalance() (float64, error) {
+	return f.balance, f.err
+}
+
+// recordingAlerter records the events alerted.
+type recordingAlerter struct {
+	events []string
+}
+
+func (a *recordingAlerter) Alert(event, message string) {
+	a.events = append(a.events, event)
+}
+
+func setupReconciler(t *testing.T) (*Reconciler, *persistence.BankrollRepository) {
+	t.Helper()
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Each connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	bankrolls := persistence.NewBankrollRepository(db)
+	for platform, amount := range map[string]float64{"polymarket": 1000, "kalshi": 500} {
+		if err := bankrolls.Initialize(platform, amount); err != nil {
+			t.Fatalf("failed to initialize bankroll: %v", err)
+		}
+	}
+	return NewReconciler(bankrolls, persistence.NewBalanceCheckRepository(db)), bankrolls
+}
+
+func TestReconciler_ActsWithinTolerance(t *testing.T) {
+	tests := []struct {
+		name       string
+		balance    float64
+		tolerance  Tolerance
+		wantAction string
+		wantAmount float64
+	}{
+		{"matched", 1000.004, Tolerance{Amount: 1, AutoAdjust: true}, persistence.BalanceActionMatched, 1000},
+		{"adjusted", 999.25, Tolerance{Amount: 1, AutoAdjust: true}, persistence.BalanceActionAdjusted, 999.25},
+		{"recorded", 999.25, Tolerance{Amount: 1}, persistence.BalanceActionRecorded, 1000},
+		{"alerted", 1050, Tolerance{Amount: 1, AutoAdjust: true}, persistence.BalanceActionAlerted, 1000},
+		{"no tolerance", 999.9, Tolerance{}, persistence.BalanceActionAlerted, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler, bankrolls := setupReconciler(t)
+			reconciler.SetPlatform("polymarket", &fixedBalance{balance: tt.balance}, tt.tolerance)
+
+			checks, err := reconciler.Run()
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if len(checks) != 1 || checks[0].Action != tt.wantAction {
+				t.Fatalf("expected %s, got %+v", tt.wantAction, checks)
+			}
+			bankroll, _ := bankrolls.Get("polymarket")
+			if bankroll.CurrentAmount != tt.wantAmount {
+				t.Errorf("expected bankroll %v, got %v", tt.wantAmount, bankroll.CurrentAmount)
+			}
+			// Adjustments are recorded in the ledger
+			if err := bankrolls.CheckLedger("polymarket"); err != nil {
+				t.Errorf("expected the ledger consistent: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconciler_AlertsOnceUntilWithinTolerance(t *testing.T) {
+	reconciler, _ := setupReconciler(t)
+	alerter := &recordingAlerter{}
+	reconciler.SetAlerter(alerter)
+	polymarket := &fixedBalance{balance: 1100}
+	reconciler.SetPlatform("polymarket", polymarket, Tolerance{Amount: 1})
+	reconciler.SetPlatform("kalshi", &fixedBalance{err: errors.New("unavailable")}, Tolerance{Amount: 1})
+
+	for i := 0; i < 2; i++ {
+		checks, err := reconciler.Run()
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		// The platform whose balance is unavailable is skipped
+		if len(checks) != 1 || checks[0].Platform != "polymarket" {
+			t.Fatalf("expected only polymarket checked, got %+v", checks)
+		}
+	}
+	if len(alerter.events) != 1 || alerter.events[0] != alert.EventBalanceDiscrepancy {
+		t.Fatalf("expected one discrepancy alert, got %v", alerter.events)
+	}
+
+	polymarket.balance = 1000
+	reconciler.Run()
+	polymarket.balance = 900
+	reconciler.Run()
+	if len(alerter.events) != 2 {
+		t.Errorf("expected a new discrepancy alerted again, got %v", alerter.events)
+	}
+}
@@ -0,0 +1,98 @@
+// Package blackout keeps a calendar of scheduled events, such as FOMC
+// decisions, CPI releases and token unlocks, around which no new positions
+// are entered.
+package blackout
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Window is a period around an event during which markets on its assets
+// are not entered.
+type Window struct {
+	Name  string    `yaml:"name"`
+	Start time.Time `yaml:"start"`
+	End   time.Time `yaml:"end"`
+	// Assets lists the asset symbols the event moves (e.g. "SOL" for a
+	// Solana token unlock). Empty applies to every asset.
+	Assets []string `yaml:"assets"`
+}
+
+// Covers reports whether the window blacks out a market on asset that is
+// open from from until to: whether the two periods overlap and the window
+// applies to the asset. A zero to leaves the market open indefinitely.
+func (w Window) Covers(asset string, from, to time.Time) bool {
+	if (!to.IsZero() && !w.Start.Before(to)) || !w.End.After(from) {
+		return false
+	}
+	if len(w.Assets) == 0 {
+		return true
+	}
+	for _, a := range w.Assets {
+		if strings.EqualFold(a, asset) {
+			return true
+		}
+	}
+	return false
+}
+
+// Calendar is a source of blackout windows.
+type Calendar interface {
+	// Windows returns the windows that end after now.
+	Windows(now time.Time) ([]Window, error)
+}
+
+// Static is a fixed list of blackout windows.
+type Static []Window
+
+// Windows returns the windows that end after now.
+func (s Static) Windows(now time.Time) ([]Window, error) {
+	var windows []Window
+	for _, w := range s {
+		if w.End.After(now) {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+// file is the format of a blackout calendar file.
+type file struct {
+	Blackouts []Window `yaml:"blackouts"`
+}
+
+// Load reads a YAML file of blackout windows:
+//
+//	blackouts:
+//	  - name: FOMC
+//	    start: 2026-10-28T06:00:00Z
+//	    end: 2026-10-28T19:00:00Z
+//	  - name: SOL unlock
+//	    start: 2026-11-01T00:00:00Z
+//	    end: 2026-11-02T00:00:00Z
+//	    assets: [SOL]
+func Load(path string) (Static, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read blackout file: %w", err)
+	}
+
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse blackout file: %w", err)
+	}
+	for i, w := range f.Blackouts {
+		if w.Start.IsZero() || w.End.IsZero() {
+			return nil, fmt.Errorf("blackout %d (%s): start and end are required", i+1, w.Name)
+		}
+		if !w.End.After(w.Start) {
+			return nil, fmt.Errorf("blackout %d (%s): end must be after start", i+1, w.Name)
+		}
+	}
+	return Static(f.Blackouts), nil
+}
@@ -0,0 +1,88 @@
+package blackout
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWindowCovers(t *testing.T) {
+	start := time.Date(2026, 10, 28, 6, 0, 0, 0, time.UTC)
+	fomc := Window{Name: "FOMC", Start: start, End: start.Add(13 * time.Hour)}
+	unlock := Window{Name: "SOL unlock", Start: start, End: start.Add(24 * time.Hour), Assets: []string{"SOL"}}
+
+	tests := []struct {
+		name     string
+		window   Window
+		asset    string
+		from, to time.Time
+		want     bool
+	}{
+		{"market open across the window", fomc, "BTC", start.Add(-time.Hour), start.Add(time.Hour), true},
+		{"market closing before the window", fomc, "BTC", start.Add(-2 * time.Hour), start, false},
+		{"market opening after the window", fomc, "BTC", start.Add(13 * time.Hour), start.Add(20 * time.Hour), false},
+		{"asset the window applies to", unlock, "sol", start.Add(-time.Hour), start.Add(time.Hour), true},
+		{"other asset", unlock, "BTC", start.Add(-time.Hour), start.Add(time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Covers(tt.asset, tt.from, tt.to); got != tt.want {
+				t.Errorf("Covers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blackouts.yaml")
+	data := `
+blackouts:
+  - name: FOMC
+    start: 2026-10-28T06:00:00Z
+    end: 2026-10-28T19:00:00Z
+  - name: SOL unlock
+    start: 2026-11-01T00:00:00Z
+    end: 2026-11-02T00:00:00Z
+    assets: [SOL]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write blackouts: %v", err)
+	}
+
+	calendar, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(calendar) != 2 || calendar[1].Assets[0] != "SOL" {
+		t.Fatalf("expected 2 windows, got %+v", calendar)
+	}
+
+	// Windows that have ended are left out
+	windows, _ := calendar.Windows(time.Date(2026, 10, 30, 0, 0, 0, 0, time.UTC))
+	if len(windows) != 1 || windows[0].Name != "SOL unlock" {
+		t.Errorf("expected only the unlock ahead, got %+v", windows)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "blackouts.yaml")
+	data := `
+blackouts:
+  - name: backwards
+    start: 2026-10-28T19:00:00Z
+    end: 2026-10-28T06:00:00Z
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write blackouts: %v", err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "end must be after start") {
+		t.Errorf("expected error for a window ending before it starts, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package blackout
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	economicCalendarURL = "https://financialmodelingprep.com/api/v3/economic_calendar"
+
+	// calendarLookahead is how far ahead releases are fetched, longer than
+	// any market the bot enters stays open.
+	calendarLookahead = 14 * 24 * time.Hour
+	// calendarRefresh is how long fetched releases are used before the
+	// calendar is fetched again.
+	calendarRefresh = 6 * time.Hour
+)
+
+// EconomicCalendar blacks out every asset around the high-impact releases
+// (rate decisions, CPI, payrolls) of the Financial Modeling Prep economic
+// calendar. Releases are fetched at most every few hours; if a refresh
+// fails, the releases fetched before keep being used.
+type EconomicCalendar struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	countries  map[string]bool
+	before     time.Duration
+	after      time.Duration
+
+	mu        sync.Mutex
+	windows   []Window
+	fetchedAt time.Time
+}
+
+// NewEconomicCalendar creates an economic calendar that blacks out from
+// before each high-impact release in countries (e.g. "US") until after it.
+func NewEconomicCalendar(apiKey string, countries []string, before, after time.Duration) *EconomicCalendar {
+	c := &EconomicCalendar{
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		baseURL:   economicCalendarURL,
+		apiKey:    apiKey,
+		countries: make(map[string]bool),
+		before:    before,
+		after:     after,
+	}
+	for _, country := range countries {
+		c.countries[strings.ToUpper(country)] = true
+	}
+	return c
+}
+
+// economicEvent represents a release in the economic calendar response.
+type economicEvent struct {
+	Event   string `json:"event"`
+	Date    string `json:"date"` // UTC, "2006-01-02 15:04:05"
+	Country string `json:"country"`
+	Impact  string `json:"impact"` // "Low", "Medium" or "High"
+}
+
+// Windows returns the windows around high-impact releases that end after
+// now.
+func (c *EconomicCalendar) Windows(now time.Time) ([]Window, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fetchedAt.IsZero() || now.Sub(c.fetchedAt) >= calendarRefresh {
+		events, err := c.fetch(now.Add(-c.after), now.Add(calendarLookahead))
+		if err != nil {
+			if c.fetchedAt.IsZero() {
+				return nil, err
+			}
+			log.Warn().Err(err).Msg("Failed to refresh economic calendar, using releases fetched before")
+		} else {
+			c.windows = c.toWindows(events)
+			c.fetchedAt = now
+		}
+	}
+	return Static(c.windows).Windows(now)
+}
+
+// fetch fetches the releases scheduled between from and to.
+func (c *EconomicCalendar) fetch(from, to time.Time) ([]economicEvent, error) {
+	params := url.Values{}
+	params.Set("from", from.UTC().Format("2006-01-02"))
+	params.Set("to", to.UTC().Format("2006-01-02"))
+	params.Set("apikey", c.apiKey)
+
+	resp, err := c.httpClient.Get(c.baseURL + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var events []economicEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return events, nil
+}
+
+// toWindows converts the high-impact releases in the calendar's countries
+// into blackout windows. Releases with an unreadable date are skipped.
+func (c *EconomicCalendar) toWindows(events []economicEvent) []Window {
+	var windows []Window
+	for _, e := range events {
+		if !strings.EqualFold(e.Impact, "High") || !c.countries[strings.ToUpper(e.Country)] {
+			continue
+		}
+		at, err := time.Parse("2006-01-02 15:04:05", e.Date)
+		if err != nil {
+			log.Debug().Err(err).Str("event", e.Event).Msg("Skipping economic release with unreadable date")
+			continue
+		}
+		windows = append(windows, Window{
+			Name:  e.Country + " " + e.Event,
+			Start: at.Add(-c.before),
+			End:   at.Add(c.after),
+		})
+	}
+	return windows
+}
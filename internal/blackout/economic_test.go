@@ -0,0 +1,62 @@
+package blackout
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEconomicCalendar_BlacksOutHighImpactReleases(t *testing.T) {
+	requests := 0
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.URL.Query().Get("apikey") != "test-key" {
+			t.Errorf("expected the API key sent, got %q", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[
+			{"event": "Fed Interest Rate Decision", "date": "2026-10-28 18:00:00", "country": "US", "impact": "High"},
+			{"event": "Redbook", "date": "2026-10-27 12:55:00", "country": "US", "impact": "Low"},
+			{"event": "ECB Interest Rate Decision", "date": "2026-10-29 12:15:00", "country": "EU", "impact": "High"}
+		]`)
+	}))
+	defer server.Close()
+
+	calendar := NewEconomicCalendar("test-key", []string{"us"}, 12*time.Hour, time.Hour)
+	calendar.baseURL = server.URL
+
+	now := time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC)
+	windows, err := calendar.Windows(now)
+	if err != nil {
+		t.Fatalf("Windows failed: %v", err)
+	}
+	if len(windows) != 1 {
+		t.Fatalf("expected only the US rate decision, got %+v", windows)
+	}
+	decision := time.Date(2026, 10, 28, 18, 0, 0, 0, time.UTC)
+	if !windows[0].Start.Equal(decision.Add(-12*time.Hour)) || !windows[0].End.Equal(decision.Add(time.Hour)) {
+		t.Errorf("expected 12h before to 1h after the decision, got %s to %s", windows[0].Start, windows[0].End)
+	}
+
+	// Fetched releases are reused until the refresh, and kept if it fails
+	if _, err := calendar.Windows(now.Add(time.Hour)); err != nil || requests != 1 {
+		t.Errorf("expected the cached releases reused, got %d requests, %v", requests, err)
+	}
+	failing = true
+	windows, err = calendar.Windows(now.Add(calendarRefresh))
+	if err != nil || len(windows) != 1 || requests != 2 {
+		t.Errorf("expected the earlier releases kept after a failed refresh, got %+v, %v", windows, err)
+	}
+
+	fresh := NewEconomicCalendar("test-key", []string{"US"}, time.Hour, time.Hour)
+	fresh.baseURL = server.URL
+	if _, err := fresh.Windows(now); err == nil {
+		t.Error("expected error when the first fetch fails, got nil")
+	}
+}
@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ClosePosition exits an open position at its market's current price with
+// ExitReasonManual, through the same Manager.ExecuteExit path as the
+// monitor cycle's exits. It is safe to call while the bot runs: a position
+// the monitor cycle exits first fails to be claimed.
+func (b *Bot) ClosePosition(ctx context.Context, positionID int64) error {
+	pos, err := b.openPosition(positionID)
+	if err != nil {
+		return err
+	}
+	currentPrice, err := b.currentPrice(pos)
+	if err != nil {
+		return err
+	}
+
+	exit, err := b.manager.ExecuteExit(ctx, pos.ID, currentPrice, position.ExitReasonManual, b.config.DryRun)
+	if err != nil {
+		b.alertExitFailure(pos, err)
+		b.countError()
+		return fmt.Errorf("close position %d: %w", pos.ID, err)
+	}
+	b.recordExit(exit)
+
+	log.Info().
+		Int64("position_id", pos.ID).
+		Float64("exit_price", exit.ExitPrice).
+		Float64("quantity", exit.Quantity).
+		Float64("remaining", exit.RemainingQuantity).
+		Float64("realized_pnl", exit.RealizedPnL).
+		Msg("position closed manually")
+	return nil
+}
+
+// RefreshPrice fetches the current price of an open position's market from
+// its platform.
+func (b *Bot) RefreshPrice(positionID int64) (float64, error) {
+	pos, err := b.openPosition(positionID)
+	if err != nil {
+		return 0, err
+	}
+	return b.currentPrice(pos)
+}
+
+// openPosition returns an open position by ID.
+func (b *Bot) openPosition(positionID int64) (*persistence.Position, error) {
+	if b.positionRepo == nil {
+		return nil, fmt.Errorf("position repository not set")
+	}
+	pos, err := b.positionRepo.GetByID(positionID)
+	if err != nil {
+		return nil, fmt.Errorf("get position: %w", err)
+	}
+	if pos == nil {
+		return nil, fmt.Errorf("position not found: %d", positionID)
+	}
+	if pos.Status != persistence.PositionStatusOpen {
+		return nil, fmt.Errorf("position %d is %s, not open", positionID, pos.Status)
+	}
+	return pos, nil
+}
+
+// currentPrice fetches the current price of a position's market from its
+// platform.
+func (b *Bot) currentPrice(pos *persistence.Position) (float64, error) {
+	provider := b.priceProvider(pos.Platform)
+	if provider == nil {
+		return 0, fmt.Errorf("platform %s not found or does not support price lookup", pos.Platform)
+	}
+	price, err := provider.GetCurrentPrice(pos.MarketID)
+	if err != nil {
+		b.audit.APIError(pos.Platform, pos.MarketID, "get_price", err)
+		return 0, fmt.Errorf("get current price: %w", err)
+	}
+	return price, nil
+}
+
+// priceProvider returns the named platform if it supports price lookups.
+func (b *Bot) priceProvider(platformName string) PriceProvider {
+	for _, p := range b.platforms {
+		if provider, ok := p.(PriceProvider); ok && p.Name() == platformName {
+			return provider
+		}
+	}
+	return nil
+}
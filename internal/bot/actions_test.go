@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/pkg/types"
+)
+
+func TestClosePosition_ExitsManually(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	posID, err := posRepo.Create(&persistence.Position{
+		Platform:   "mock",
+		MarketID:   "test-market-manual",
+		Asset:      "BTC",
+		EntryPrice: 0.80,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	mockPlatform := &MockPlatformWithPrice{
+		name:         "mock",
+		balance:      100.0,
+		markets:      []types.Market{},
+		currentPrice: 0.85,
+	}
+	manager := position.NewManager(posRepo, bankRepo, nil, nil)
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    10 * time.Second,
+		MonitorInterval: 5 * time.Second,
+	}, []platform.Platform{mockPlatform}, scanner.NewScanner(config.Parameters{}), manager)
+	bot.SetPositionRepo(posRepo)
+
+	if price, err := bot.RefreshPrice(posID); err != nil || price != 0.85 {
+		t.Errorf("expected the platform's current price, got %v (%v)", price, err)
+	}
+
+	if err := bot.ClosePosition(context.Background(), posID); err != nil {
+		t.Fatalf("ClosePosition failed: %v", err)
+	}
+	closedPos, err := posRepo.GetByID(posID)
+	if err != nil {
+		t.Fatalf("failed to get position: %v", err)
+	}
+	if closedPos.Status != "closed" {
+		t.Errorf("expected position to be closed, got status %s", closedPos.Status)
+	}
+	if closedPos.ExitReason == nil || *closedPos.ExitReason != position.ExitReasonManual {
+		t.Errorf("expected exit reason %q, got %v", position.ExitReasonManual, closedPos.ExitReason)
+	}
+	if session := bot.Session(); session.Exits != 1 {
+		t.Errorf("expected the exit counted in the session, got %d", session.Exits)
+	}
+
+	// Closed positions can't be closed or priced again
+	if err := bot.ClosePosition(context.Background(), posID); err == nil {
+		t.Error("expected an error closing a closed position")
+	}
+	if _, err := bot.RefreshPrice(posID); err == nil {
+		t.Error("expected an error pricing a closed position")
+	}
+}
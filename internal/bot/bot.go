@@ -2,14 +2,31 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/arbitrage"
+	"prediction-bot/internal/audit"
+	"prediction-bot/internal/balance"
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/events"
+	"prediction-bot/internal/learning"
+	"prediction-bot/internal/orders"
 	"prediction-bot/internal/persistence"
 	"prediction-bot/internal/platform"
 	"prediction-bot/internal/position"
 	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/settlement"
+	"prediction-bot/internal/strategy"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
@@ -21,6 +38,29 @@ type BotConfig struct {
 	ScanInterval time.Duration
 	// MonitorInterval is the duration between position monitoring cycles.
 	MonitorInterval time.Duration
+	// SettleInterval is the duration between market resolution checks.
+	// Defaults to MonitorInterval if zero.
+	SettleInterval time.Duration
+	// BalanceInterval is the duration between reconciliations of the
+	// bankrolls with the platforms' balances (see SetBalanceReconciler).
+	BalanceInterval time.Duration
+	// LearningInterval is the duration between learning cycles (see
+	// SetLearner).
+	LearningInterval time.Duration
+	// ScanWorkers is how many platforms are scanned at once (0 or 1 scans
+	// them one after another).
+	ScanWorkers int
+	// MarketWorkers is how many of a platform's eligible markets are
+	// analyzed at once. Entries are made one at a time regardless, since
+	// they share the bankroll and portfolio limits.
+	MarketWorkers int
+	// DrainTimeout is how long the entry or exit in progress at shutdown
+	// gets to finish placing and recording its orders (0 cuts it off as
+	// soon as the bot is stopped).
+	DrainTimeout time.Duration
+	// CancelOnShutdown cancels the orders still resting on the open
+	// positions' markets once the bot has stopped, live only.
+	CancelOnShutdown bool
 }
 
 // PriceProvider defines the interface for getting current market prices.
@@ -28,29 +68,87 @@ type PriceProvider interface {
 	GetCurrentPrice(marketID string) (float64, error)
 }
 
+// StatusProvider defines the interface for checking whether a platform is
+// accepting trades.
+type StatusProvider interface {
+	GetPlatformStatus() (types.PlatformStatus, error)
+}
+
+// Alerter defines the interface for raising audible alerts to an operator
+// (see the alert package for events).
+type Alerter interface {
+	Alert(event, message string)
+}
+
+// CacheReporter is implemented by volatility analyzers that cache the
+// prices they fetch, whose hits and misses are logged each scan cycle.
+type CacheReporter interface {
+	CacheStats() volatility.CacheStats
+}
+
 // Bot is the main trading bot that orchestrates scanning and position management.
 type Bot struct {
-	config       BotConfig
-	platforms    []platform.Platform
-	scanner      *scanner.Scanner
-	manager      *position.Manager
-	monitor      *position.Monitor
-	volatility   position.VolatilityAnalyzer
-	positionRepo *persistence.PositionRepository
+	config        BotConfig
+	platforms     []platform.Platform
+	scanner       *scanner.Scanner
+	manager       *position.Manager
+	monitor       *position.Monitor
+	volatility    position.VolatilityAnalyzer
+	positionRepo  *persistence.PositionRepository
+	nearMissRepo  *persistence.NearMissRepository
+	decisionRepo  *persistence.ScanDecisionRepository
+	snapshotRepo  *persistence.ScanSnapshotRepository
+	orderTracker  *orders.Tracker
+	settler       *settlement.Settler
+	balances      *balance.Reconciler
+	learner       *learning.Learner
+	statuses      map[string]types.PlatformStatus
+	arbitrage     *arbitrage.Detector
+	arbitrageRepo *persistence.ArbitrageRepository
+	hedgeSize     float64
+	alerter       Alerter
+	sessionRepo   *persistence.SessionRepository
+	session       persistence.Session
+	scanStats     map[string]scanner.ScanStats
+	ledgerPaused  map[string]bool
+	events        *events.Bus
+	audit         *audit.Recorder
+	strategies    *strategy.Registry
+	strategyName  string
+	baseParams    config.Parameters
+	configWatcher *config.Watcher
+	loadedConfig  *config.Config
+	paramsRepo    *persistence.ParametersRepository
+	paramCache    *persistence.ParameterCache
+	appliedParams config.Parameters
+	lastScan      time.Time
+	lastMonitor   time.Time
+	now           func() time.Time
+
+	// mu guards the session, scan stats, statuses, ledger pauses and last
+	// scan while platforms are scanned concurrently, and the session and
+	// last cycles while they are read for alert thresholds and health
+	// checks.
+	mu sync.Mutex
 }
 
 // NewBot creates a new trading bot with the given configuration and dependencies.
 func NewBot(
 	config BotConfig,
 	platforms []platform.Platform,
-	scanner *scanner.Scanner,
+	sc *scanner.Scanner,
 	manager *position.Manager,
 ) *Bot {
 	return &Bot{
-		config:    config,
-		platforms: platforms,
-		scanner:   scanner,
-		manager:   manager,
+		config:       config,
+		platforms:    platforms,
+		scanner:      sc,
+		manager:      manager,
+		statuses:     make(map[string]types.PlatformStatus),
+		scanStats:    make(map[string]scanner.ScanStats),
+		ledgerPaused: make(map[string]bool),
+		session:      persistence.Session{StartedAt: time.Now(), DryRun: config.DryRun},
+		now:          time.Now,
 	}
 }
 
@@ -59,92 +157,401 @@ func NewBot(
 // the position manager for potential entry.
 //
 // Flow:
-// 1. For each platform, scan for eligible markets
-// 2. For each eligible market, process entry through position manager
-// 3. Log results
-func (b *Bot) RunScanCycle() error {
+// 1. Reload config.yaml and the active strategy if their files changed, and
+//    apply the parameters table's values if they changed
+// 2. Scale the Kelly fraction for the current drawdown
+// 3. For each platform, skip it if trading is halted or its bankroll is inconsistent
+// 4. Scan the platform for eligible markets
+// 5. For each eligible market, process entry through position manager
+// 6. Log results
+//
+// Up to ScanWorkers platforms are scanned at once and up to MarketWorkers
+// of a platform's markets analyzed at once; entries are made one at a time.
+// Once ctx is cancelled no further markets are entered and its error is
+// returned. Platforms that fail to scan don't stop the cycle: their errors
+// are returned joined once it completes, and it counts as a successful scan
+// if any platform scanned.
+func (b *Bot) RunScanCycle(ctx context.Context) error {
 	log.Info().Msg("starting scan cycle")
+	b.mu.Lock()
+	b.session.ScanCycles++
+	b.mu.Unlock()
 
-	var totalEligible int
-	var totalProcessed int
-	var totalSkipped int
+	b.reloadConfig()
+	b.reloadStrategy()
+	b.refreshParameters()
 
-	for _, p := range b.platforms {
-		platformName := p.Name()
+	// Size this cycle's entries for the current drawdown
+	if _, err := b.manager.AdjustKellyFraction(); err != nil {
+		log.Warn().Err(err).Msg("failed to adjust kelly fraction")
+	}
+
+	// Platforms are scanned concurrently, each into its own totals and error
+	totals := make([]scanTotals, len(b.platforms))
+	errs := make([]error, len(b.platforms))
+	platforms := newGroup(b.config.ScanWorkers)
+	for i, p := range b.platforms {
+		platforms.Go(func() error {
+			totals[i], errs[i] = b.scanPlatform(ctx, p)
+			return nil
+		})
+	}
+	platforms.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	scanned := 0
+	for _, err := range errs {
+		if err == nil {
+			scanned++
+		}
+	}
+	if scanned > 0 || len(b.platforms) == 0 {
+		b.mu.Lock()
+		b.lastScan = time.Now()
+		b.mu.Unlock()
+	}
+
+	var totalEligible, totalProcessed, totalSkipped int
+	for _, t := range totals {
+		totalEligible += t.eligible
+		totalProcessed += t.processed
+		totalSkipped += t.skipped
+	}
+
+	log.Info().
+		Int("total_eligible", totalEligible).
+		Int("total_processed", totalProcessed).
+		Int("total_skipped", totalSkipped).
+		Msg("scan cycle complete")
+
+	if reporter, ok := b.volatility.(CacheReporter); ok {
+		stats := reporter.CacheStats()
 		log.Info().
+			Int("price_hits", stats.PriceHits).
+			Int("price_misses", stats.PriceMisses).
+			Int("history_hits", stats.HistoryHits).
+			Int("history_misses", stats.HistoryMisses).
+			Float64("hit_rate", stats.HitRate()).
+			Msg("volatility cache")
+	}
+
+	if err := b.RunArbitrageCycle(ctx); err != nil {
+		log.Error().Err(err).Msg("arbitrage check failed")
+		b.countError()
+	}
+
+	return errors.Join(errs...)
+}
+
+// scanTotals counts what a scan cycle did on a platform.
+type scanTotals struct {
+	eligible  int
+	processed int
+	skipped   int
+}
+
+// scanPlatform scans a platform and processes the entries of its eligible
+// markets (steps 3 to 5 of RunScanCycle). Returns an error only if the
+// scan itself fails.
+func (b *Bot) scanPlatform(ctx context.Context, p platform.Platform) (scanTotals, error) {
+	var totals scanTotals
+	platformName := p.Name()
+
+	// Pause entries while the platform is halted or maintenance is near
+	if status := b.refreshStatus(p); status.EntriesPaused() {
+		log.Warn().
 			Str("platform", platformName).
-			Msg("scanning platform")
+			Str("status", status.Message).
+			Msg("platform halted or maintenance imminent, pausing entries")
+		return totals, nil
+	}
+
+	// Sizing off a corrupted bankroll compounds the damage
+	if !b.checkBankroll(platformName) {
+		return totals, nil
+	}
 
-		// Scan platform for eligible markets
-		eligibleMarkets, err := b.scanner.Scan(p)
+	log.Info().
+		Str("platform", platformName).
+		Msg("scanning platform")
+	b.events.Publish(events.Event{Type: events.ScanStarted, Platform: platformName})
+
+	// Scan platform for eligible markets
+	scan, err := b.scanner.Evaluate(ctx, p)
+	if err != nil && ctx.Err() != nil {
+		// Cancelled by shutdown, not a platform failure
+		return totals, ctx.Err()
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("platform", platformName).
+			Msg("failed to scan platform")
+		b.events.Publish(events.Event{Type: events.ScanFailed, Platform: platformName, Detail: err.Error()})
+		b.audit.APIError(platformName, "", "scan", err)
+		return totals, fmt.Errorf("scan platform %s: %w", platformName, err)
+	}
+
+	eligibleMarkets, stats := scan.Eligible, scan.Stats
+	b.mu.Lock()
+	b.scanStats[platformName] = stats
+	b.mu.Unlock()
+	log.Info().
+		Str("platform", platformName).
+		Int("listed_markets", stats.Listed).
+		Int("eligible_markets", len(eligibleMarkets)).
+		Dict("rejections", rejectionsDict(stats)).
+		Msg("scan complete")
+
+	totals.eligible = len(eligibleMarkets)
+	for _, market := range eligibleMarkets {
+		b.events.Publish(events.Event{
+			Type:     events.MarketEligible,
+			Platform: platformName,
+			MarketID: market.Market.ID,
+			Title:    market.Market.Title,
+			Side:     market.BetSide,
+			Price:    market.Probability,
+		})
+	}
+	b.events.Publish(events.Event{Type: events.ScanCompleted, Platform: platformName, Count: len(eligibleMarkets)})
+
+	b.recordNearMisses(platformName, scan.NearMisses)
+
+	decisions := make([]*persistence.ScanDecision, 0, stats.Listed)
+	for _, rejection := range scan.Rejections {
+		decisions = append(decisions, rejectionDecision(rejection))
+	}
+
+	// Process each eligible market, or the best strike of each event. The
+	// markets are analyzed concurrently, then entered one at a time in order.
+	selected := b.manager.SelectStrikes(ctx, eligibleMarkets)
+	decisions = append(decisions, unselectedDecisions(eligibleMarkets, selected)...)
+	analyses := make([]position.EntryAnalysis, len(selected))
+	analysisErrs := make([]error, len(selected))
+	markets := newGroup(b.config.MarketWorkers)
+	for i, market := range selected {
+		markets.Go(func() error {
+			analyses[i], analysisErrs[i] = b.manager.AnalyzeEntry(ctx, market)
+			return nil
+		})
+	}
+	markets.Wait()
+
+	entryCtx, finish := b.inFlight(ctx)
+	defer finish()
+	for i, market := range selected {
+		// Shutting down: leave the remaining markets for the next run
+		if ctx.Err() != nil {
+			break
+		}
+
+		log.Debug().
+			Str("platform", platformName).
+			Str("market_id", market.Market.ID).
+			Str("title", market.Market.Title).
+			Float64("probability", market.Probability).
+			Str("bet_side", market.BetSide).
+			Msg("processing eligible market")
+
+		var result position.EntryResult
+		err := analysisErrs[i]
+		if err == nil {
+			result, err = b.manager.Enter(entryCtx, analyses[i], b.config.DryRun)
+		}
 		if err != nil {
 			log.Error().
 				Err(err).
 				Str("platform", platformName).
-				Msg("failed to scan platform")
-			return fmt.Errorf("scan platform %s: %w", platformName, err)
+				Str("market_id", market.Market.ID).
+				Msg("failed to process entry")
+			b.countError()
+			// Continue processing other markets
+			continue
 		}
+		decisions = append(decisions, entryDecision(market, result))
 
-		log.Info().
-			Str("platform", platformName).
-			Int("eligible_markets", len(eligibleMarkets)).
-			Msg("scan complete")
-
-		totalEligible += len(eligibleMarkets)
-
-		// Process each eligible market
-		for _, market := range eligibleMarkets {
-			log.Debug().
+		if result.Skipped {
+			log.Info().
+				Str("platform", platformName).
+				Str("market_id", market.Market.ID).
+				Str("skip_reason", result.SkipReason).
+				Msg("market skipped")
+			b.events.Publish(events.Event{
+				Type:     events.EntrySkipped,
+				Platform: platformName,
+				MarketID: market.Market.ID,
+				Title:    market.Market.Title,
+				Detail:   result.SkipReason,
+			})
+			totals.skipped++
+		} else {
+			log.Info().
 				Str("platform", platformName).
 				Str("market_id", market.Market.ID).
-				Str("title", market.Market.Title).
-				Float64("probability", market.Probability).
-				Str("bet_side", market.BetSide).
-				Msg("processing eligible market")
+				Int64("position_id", result.PositionID).
+				Float64("position_size", result.PositionSize).
+				Float64("entry_price", result.EntryPrice).
+				Float64("quantity", result.Quantity).
+				Float64("safety_margin", result.SafetyMargin).
+				Str("side", result.Side).
+				Str("trade_strategy", result.TradeStrategy).
+				Str("entry_strategy", result.Strategy).
+				Bool("dry_run", b.config.DryRun).
+				Msg("position opened")
+			b.events.Publish(events.Event{
+				Type:     events.PositionOpened,
+				Platform: platformName,
+				MarketID: market.Market.ID,
+				Title:    market.Market.Title,
+				Side:     result.Side,
+				Price:    result.EntryPrice,
+				Size:     result.PositionSize,
+			})
+			totals.processed++
+			b.mu.Lock()
+			b.session.Entries++
+			b.mu.Unlock()
+		}
+	}
 
-			result, err := b.manager.ProcessEntry(market, b.config.DryRun)
-			if err != nil {
-				log.Error().
-					Err(err).
-					Str("platform", platformName).
-					Str("market_id", market.Market.ID).
-					Msg("failed to process entry")
-				// Continue processing other markets
-				continue
-			}
+	b.recordDecisions(platformName, decisions)
 
-			if result.Skipped {
-				log.Info().
-					Str("platform", platformName).
-					Str("market_id", market.Market.ID).
-					Str("skip_reason", result.SkipReason).
-					Msg("market skipped")
-				totalSkipped++
-			} else {
-				log.Info().
-					Str("platform", platformName).
-					Str("market_id", market.Market.ID).
-					Int64("position_id", result.PositionID).
-					Float64("position_size", result.PositionSize).
-					Float64("entry_price", result.EntryPrice).
-					Float64("quantity", result.Quantity).
-					Float64("safety_margin", result.SafetyMargin).
-					Bool("dry_run", b.config.DryRun).
-					Msg("position opened")
-				totalProcessed++
-			}
+	return totals, nil
+}
+
+// RunArbitrageCycle lists the markets on every platform whose entries aren't paused,
+// matches equivalent markets across platforms and logs those whose implied
+// probabilities diverge. Opportunities are recorded if a repository is set,
+// and hedged if a hedge size is set and buying both legs locks in a profit.
+func (b *Bot) RunArbitrageCycle(ctx context.Context) error {
+	if b.arbitrage == nil {
+		return nil
+	}
+
+	// Scan workers update the statuses concurrently
+	paused := make(map[string]bool)
+	b.mu.Lock()
+	for name, status := range b.statuses {
+		paused[name] = status.EntriesPaused()
+	}
+	b.mu.Unlock()
+
+	isActive := true
+	var markets []types.Market
+	for _, p := range b.platforms {
+		if paused[p.Name()] {
+			continue
+		}
+		listed, err := p.ListMarkets(ctx, types.MarketFilter{IsActive: &isActive, Limit: 500})
+		if err != nil {
+			return fmt.Errorf("list markets on %s: %w", p.Name(), err)
+		}
+		markets = append(markets, listed...)
+	}
+
+	opportunities := b.arbitrage.Detect(markets)
+	for _, opp := range opportunities {
+		log.Warn().
+			Str("asset", opp.Asset).
+			Float64("strike", opp.Strike).
+			Str("direction", opp.Direction).
+			Time("resolution_date", opp.Date).
+			Str("yes_platform", opp.YesMarket.Platform).
+			Str("yes_market_id", opp.YesMarket.ID).
+			Float64("yes_price", opp.YesPrice).
+			Str("no_platform", opp.NoMarket.Platform).
+			Str("no_market_id", opp.NoMarket.ID).
+			Float64("no_price", opp.NoPrice).
+			Float64("spread", opp.Spread).
+			Float64("profit_per_pair", opp.Profit()).
+			Msg("ARBITRAGE: cross-platform price divergence detected")
+
+		if b.arbitrageRepo == nil {
+			continue
+		}
+		record := &persistence.Arbitrage{
+			Asset:          opp.Asset,
+			Strike:         opp.Strike,
+			Direction:      opp.Direction,
+			ResolutionDate: opp.Date,
+			YesPlatform:    opp.YesMarket.Platform,
+			YesMarketID:    opp.YesMarket.ID,
+			YesPrice:       opp.YesPrice,
+			NoPlatform:     opp.NoMarket.Platform,
+			NoMarketID:     opp.NoMarket.ID,
+			NoPrice:        opp.NoPrice,
+			Spread:         opp.Spread,
+		}
+		if _, err := b.arbitrageRepo.Record(record); err != nil {
+			log.Warn().Err(err).Str("pair", opp.Key()).Msg("failed to record arbitrage")
+			continue
+		}
+
+		if b.hedgeSize > 0 && opp.Profit() > 0 && !record.Hedged() {
+			b.hedge(ctx, opp, record.ID)
 		}
 	}
 
 	log.Info().
-		Int("total_eligible", totalEligible).
-		Int("total_processed", totalProcessed).
-		Int("total_skipped", totalSkipped).
-		Msg("scan cycle complete")
+		Int("markets", len(markets)).
+		Int("opportunities", len(opportunities)).
+		Msg("arbitrage check complete")
 
 	return nil
 }
 
+// hedge opens YES and NO on the two markets of an opportunity and records
+// the legs against it.
+func (b *Bot) hedge(ctx context.Context, opp arbitrage.Opportunity, arbitrageID int64) {
+	parsed := &scanner.ParsedMarket{Asset: opp.Asset, Strike: opp.Strike, Direction: opp.Direction}
+	legs := []position.HedgeLeg{
+		{Market: opp.YesMarket, Parsed: parsed, Side: "YES", Price: opp.YesPrice},
+		{Market: opp.NoMarket, Parsed: parsed, Side: "NO", Price: opp.NoPrice},
+	}
+
+	results, err := b.manager.ProcessHedge(ctx, legs, b.hedgeSize, b.config.DryRun)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("pair", opp.Key()).
+			Int("legs_unhedged", len(results)).
+			Msg("ALERT: failed to open hedge")
+		b.mu.Lock()
+		b.session.Errors++
+		b.session.Entries += len(results)
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Lock()
+	b.session.Entries += len(results)
+	b.mu.Unlock()
+	if len(results) != len(legs) {
+		if len(results) > 0 {
+			log.Info().
+				Str("pair", opp.Key()).
+				Str("skip_reason", results[0].SkipReason).
+				Msg("hedge skipped")
+		}
+		return
+	}
+
+	if err := b.arbitrageRepo.SetHedge(arbitrageID, results[0].PositionID, results[1].PositionID); err != nil {
+		log.Error().Err(err).Str("pair", opp.Key()).Msg("failed to record hedge legs")
+		return
+	}
+
+	log.Info().
+		Str("pair", opp.Key()).
+		Int64("yes_position_id", results[0].PositionID).
+		Int64("no_position_id", results[1].PositionID).
+		Float64("quantity", results[0].Quantity).
+		Float64("cost", results[0].PositionSize+results[1].PositionSize).
+		Bool("dry_run", b.config.DryRun).
+		Msg("hedged positions opened")
+}
+
 // SetMonitor sets the position monitor for exit checks.
 func (b *Bot) SetMonitor(monitor *position.Monitor) {
 	b.monitor = monitor
@@ -160,18 +567,469 @@ func (b *Bot) SetPositionRepo(repo *persistence.PositionRepository) {
 	b.positionRepo = repo
 }
 
+// SetNearMissRepo sets the repository used to record scanner near misses.
+func (b *Bot) SetNearMissRepo(repo *persistence.NearMissRepository) {
+	b.nearMissRepo = repo
+}
+
+// SetScanDecisionRepo sets the repository used to record what was decided
+// about every market a scan evaluated.
+func (b *Bot) SetScanDecisionRepo(repo *persistence.ScanDecisionRepository) {
+	b.decisionRepo = repo
+}
+
+// SetScanSnapshotRepo sets the repository used to keep each platform's
+// latest scan cycles, so consecutive scans can be diffed.
+func (b *Bot) SetScanSnapshotRepo(repo *persistence.ScanSnapshotRepository) {
+	b.snapshotRepo = repo
+}
+
+// SetOrderTracker sets the order tracker used to refresh order fills.
+func (b *Bot) SetOrderTracker(tracker *orders.Tracker) {
+	b.orderTracker = tracker
+}
+
+// SetSettler sets the settler used to close positions in resolved markets.
+func (b *Bot) SetSettler(settler *settlement.Settler) {
+	b.settler = settler
+}
+
+// SetBalanceReconciler sets the reconciler run every BalanceInterval to
+// check the bankrolls against the platforms' balances.
+func (b *Bot) SetBalanceReconciler(reconciler *balance.Reconciler) {
+	b.balances = reconciler
+}
+
+// SetLearner sets the learner run every LearningInterval to adjust the
+// parameters table from the outcomes of closed trades.
+func (b *Bot) SetLearner(learner *learning.Learner) {
+	b.learner = learner
+}
+
+// SetArbitrageDetector sets the detector used to cross-check prices of
+// equivalent markets across platforms after each scan cycle.
+func (b *Bot) SetArbitrageDetector(detector *arbitrage.Detector) {
+	b.arbitrage = detector
+}
+
+// SetArbitrageRepo sets the repository detected arbitrage opportunities are
+// recorded in. Hedged positions are only opened if it is set, so their legs
+// can be recognised and held to resolution.
+func (b *Bot) SetArbitrageRepo(repo *persistence.ArbitrageRepository) {
+	b.arbitrageRepo = repo
+}
+
+// SetAlerter sets the alerter notified when a stop loss fires or a live exit
+// fails.
+func (b *Bot) SetAlerter(alerter Alerter) {
+	b.alerter = alerter
+}
+
+// SetSessionRepo sets the repository the session summary is recorded in on
+// shutdown.
+func (b *Bot) SetSessionRepo(repo *persistence.SessionRepository) {
+	b.sessionRepo = repo
+}
+
+// SetEventBus sets the bus scan progress and entry decisions are published
+// to as they happen.
+func (b *Bot) SetEventBus(bus *events.Bus) {
+	b.events = bus
+}
+
+// SetAuditRecorder sets the recorder entries, exits, API errors and halts
+// are recorded in for auditing. Entries and exits are recorded by the
+// position manager, in the same transaction as the trade.
+func (b *Bot) SetAuditRecorder(recorder *audit.Recorder) {
+	b.audit = recorder
+	if b.manager != nil {
+		b.manager.SetTradeEvents(recorder)
+	}
+}
+
+// SetHedgeSize sets the dollars spent across both legs when hedging a
+// detected arbitrage. Zero only reports opportunities.
+func (b *Bot) SetHedgeSize(size float64) {
+	b.hedgeSize = size
+}
+
+// SetClock sets the clock positions are checked for flattening and time
+// decay at, for replaying history. Session times stay on the wall clock.
+func (b *Bot) SetClock(now func() time.Time) {
+	b.now = now
+}
+
+// Session returns the tally of the current session so far.
+func (b *Bot) Session() persistence.Session {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.session
+}
+
+// LastScan returns when a scan cycle last succeeded (zero if none has).
+func (b *Bot) LastScan() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastScan
+}
+
+// LastMonitor returns when a monitor cycle last succeeded (zero if none
+// has).
+func (b *Bot) LastMonitor() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastMonitor
+}
+
+// ScanStats returns the rejection counts of the most recent scan of each
+// platform, by platform name.
+func (b *Bot) ScanStats() map[string]scanner.ScanStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make(map[string]scanner.ScanStats, len(b.scanStats))
+	for platformName, s := range b.scanStats {
+		stats[platformName] = s
+	}
+	return stats
+}
+
+// rejectionsDict returns a scan's rejection counts as a log field, ordered
+// by criterion.
+func rejectionsDict(stats scanner.ScanStats) *zerolog.Event {
+	criteria := make([]string, 0, len(stats.Rejections))
+	for criterion := range stats.Rejections {
+		criteria = append(criteria, criterion)
+	}
+	sort.Strings(criteria)
+
+	dict := zerolog.Dict()
+	for _, criterion := range criteria {
+		dict.Int(criterion, stats.Rejections[criterion])
+	}
+	return dict
+}
+
+// recordExit adds an exit to the session tally. Partially filled exits leave
+// the position open, so they are counted once the rest is sold.
+func (b *Bot) recordExit(exit position.ExitResult) {
+	if exit.RemainingQuantity > 0 {
+		return
+	}
+	b.mu.Lock()
+	b.session.Exits++
+	b.session.RealizedPnL += exit.RealizedPnL
+	b.mu.Unlock()
+}
+
+// countError adds an error to the session tally.
+func (b *Bot) countError() {
+	b.mu.Lock()
+	b.session.Errors++
+	b.mu.Unlock()
+}
+
+// endSession logs a summary of the session and records it if a repository is
+// set.
+func (b *Bot) endSession() {
+	// Handlers may still read the session; persist a copy of it
+	b.mu.Lock()
+	b.session.EndedAt = time.Now()
+	session := b.session
+	b.mu.Unlock()
+
+	log.Info().
+		Time("started_at", session.StartedAt).
+		Dur("uptime", session.Uptime()).
+		Bool("dry_run", session.DryRun).
+		Int("scan_cycles", session.ScanCycles).
+		Int("monitor_cycles", session.MonitorCycles).
+		Int("settle_cycles", session.SettleCycles).
+		Int("entries", session.Entries).
+		Int("exits", session.Exits).
+		Float64("realized_pnl", session.RealizedPnL).
+		Int("errors", session.Errors).
+		Msg("session summary")
+
+	if b.sessionRepo == nil {
+		return
+	}
+	id, err := b.sessionRepo.Record(&session)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to record session")
+		return
+	}
+	b.mu.Lock()
+	b.session.ID = id
+	b.mu.Unlock()
+}
+
+// alert notifies the alerter, if one is set.
+func (b *Bot) alert(event, message string) {
+	if b.alerter != nil {
+		b.alerter.Alert(event, message)
+	}
+}
+
+// alertExitFailure alerts that a live exit failed and the position is still
+// open. Dry-run exits don't place orders, so their failures aren't alerted.
+func (b *Bot) alertExitFailure(pos *persistence.Position, err error) {
+	if b.config.DryRun {
+		return
+	}
+	b.alert(alert.EventOrderFailed, fmt.Sprintf("exit failed for %s %s: %v", pos.Platform, pos.MarketID, err))
+}
+
+// refreshStatus fetches the current status of a platform and alerts when
+// trading halts or resumes. Platforms that don't report a status are treated
+// as active; if the check fails, the last known status is kept.
+func (b *Bot) refreshStatus(p platform.Platform) types.PlatformStatus {
+	name := p.Name()
+	b.mu.Lock()
+	previous, known := b.statuses[name]
+	b.mu.Unlock()
+
+	provider, ok := p.(StatusProvider)
+	if !ok {
+		return types.PlatformStatus{Platform: name, TradingActive: true}
+	}
+
+	status, err := provider.GetPlatformStatus()
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("platform", name).
+			Msg("failed to check platform status")
+		b.audit.APIError(name, "", "get_status", err)
+		if known {
+			return previous
+		}
+		return types.PlatformStatus{Platform: name, TradingActive: true}
+	}
+	b.mu.Lock()
+	b.statuses[name] = status
+	b.mu.Unlock()
+
+	wasHalted := known && previous.Halted()
+	switch {
+	case status.Halted() && !wasHalted:
+		event := log.Error().
+			Str("platform", name).
+			Bool("trading_active", status.TradingActive).
+			Bool("maintenance", status.Maintenance).
+			Str("status", status.Message)
+		if !status.ResumeAt.IsZero() {
+			event = event.Time("resume_at", status.ResumeAt)
+		}
+		event.Msg("ALERT: platform trading halted, entries paused and exits cannot execute")
+		b.audit.Halt(name, audit.CausePlatformStatus, status.Message)
+	case !status.Halted() && wasHalted:
+		log.Warn().
+			Str("platform", name).
+			Msg("ALERT: platform trading resumed")
+		b.audit.Resume(name, audit.CausePlatformStatus)
+	}
+
+	return status
+}
+
+// checkBankroll reports whether a platform's bankroll agrees with its
+// ledger, alerting when entries are paused because it doesn't and logging
+// when they resume. If the check itself fails, entries are paused without
+// an alert.
+func (b *Bot) checkBankroll(platformName string) bool {
+	err := b.manager.CheckBankroll(platformName)
+	if err != nil && !errors.Is(err, persistence.ErrLedgerInconsistent) {
+		log.Error().
+			Err(err).
+			Str("platform", platformName).
+			Msg("failed to check bankroll ledger, pausing entries")
+		return false
+	}
+
+	b.mu.Lock()
+	wasPaused := b.ledgerPaused[platformName]
+	b.ledgerPaused[platformName] = err != nil
+	b.mu.Unlock()
+	switch {
+	case err != nil && !wasPaused:
+		log.Error().
+			Err(err).
+			Str("platform", platformName).
+			Msg("ALERT: bankroll inconsistent with ledger, entries paused")
+		b.alert(alert.EventLedgerInconsistent, err.Error())
+		b.audit.Halt(platformName, audit.CauseLedger, err.Error())
+	case err != nil:
+		log.Warn().
+			Str("platform", platformName).
+			Msg("bankroll inconsistent with ledger, pausing entries")
+	case wasPaused:
+		log.Warn().
+			Str("platform", platformName).
+			Msg("ALERT: bankroll consistent with ledger, entries resumed")
+		b.audit.Resume(platformName, audit.CauseLedger)
+	}
+	return err == nil
+}
+
+// recordNearMisses persists the near misses from the last scan so the
+// learning system can evaluate the eligibility thresholds.
+func (b *Bot) recordNearMisses(platformName string, nearMisses []scanner.NearMiss) {
+	if b.nearMissRepo == nil {
+		return
+	}
+
+	for _, nm := range nearMisses {
+		err := b.nearMissRepo.Record(&persistence.NearMiss{
+			Platform:    nm.Market.Platform,
+			MarketID:    nm.Market.ID,
+			MarketTitle: nm.Market.Title,
+			Asset:       nm.Parsed.Asset,
+			Criterion:   nm.Failure.Criterion,
+			Value:       nm.Failure.Value,
+			Threshold:   nm.Failure.Threshold,
+			Shortfall:   nm.Failure.Shortfall,
+			Probability: nm.Probability,
+			BetSide:     nm.BetSide,
+		})
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("platform", platformName).
+				Str("market_id", nm.Market.ID).
+				Msg("failed to record near miss")
+		}
+	}
+}
+
+// recordDecisions persists what a scan cycle decided about each market it
+// evaluated, so the markets filtered out can later be checked against how
+// they resolved, and as the platform's latest scan snapshot.
+func (b *Bot) recordDecisions(platformName string, decisions []*persistence.ScanDecision) {
+	if b.decisionRepo != nil {
+		if err := b.decisionRepo.RecordAll(decisions); err != nil {
+			log.Warn().
+				Err(err).
+				Str("platform", platformName).
+				Int("decisions", len(decisions)).
+				Msg("failed to record scan decisions")
+		}
+	}
+	if b.snapshotRepo != nil {
+		if err := b.snapshotRepo.Record(platformName, decisions); err != nil {
+			log.Warn().
+				Err(err).
+				Str("platform", platformName).
+				Int("decisions", len(decisions)).
+				Msg("failed to record scan snapshot")
+		}
+	}
+}
+
+// rejectionDecision is the decision for a market the scanner rejected.
+func rejectionDecision(rejection scanner.Rejection) *persistence.ScanDecision {
+	decision := marketDecision(rejection.Market, rejection.Parsed, rejection.Probability, rejection.BetSide)
+	decision.Eligible = rejection.Eligible
+	decision.Reason = strings.Join(rejection.Reasons, ",")
+	return decision
+}
+
+// unselectedDecisions returns the decisions for the eligible markets left
+// out of selected in favor of a better strike of their event.
+func unselectedDecisions(eligible, selected []scanner.EligibleMarket) []*persistence.ScanDecision {
+	if len(eligible) == len(selected) {
+		return nil
+	}
+
+	key := func(m types.Market) string { return m.Platform + "|" + m.ID + "|" + m.Outcome }
+	kept := make(map[string]bool, len(selected))
+	for _, market := range selected {
+		kept[key(market.Market)] = true
+	}
+
+	var decisions []*persistence.ScanDecision
+	for _, market := range eligible {
+		if kept[key(market.Market)] {
+			continue
+		}
+		decision := marketDecision(market.Market, market.Parsed, market.Probability, market.BetSide)
+		decision.Eligible = true
+		decision.Reason = position.SkipReasonStrikeNotSelected
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
+// entryDecision is the decision for an eligible market processed for
+// entry: its skip reason, or ScanDecisionEntered.
+func entryDecision(market scanner.EligibleMarket, result position.EntryResult) *persistence.ScanDecision {
+	decision := marketDecision(market.Market, market.Parsed, market.Probability, market.BetSide)
+	decision.Eligible = true
+	decision.Reason = persistence.ScanDecisionEntered
+	if result.Skipped {
+		decision.Reason = result.SkipReason
+	}
+	// Markets skipped before the volatility analysis have no volatility
+	if result.Volatility > 0 {
+		safetyMargin, volatility := result.SafetyMargin, result.Volatility
+		decision.SafetyMargin = &safetyMargin
+		decision.Volatility = &volatility
+	}
+	return decision
+}
+
+// marketDecision is the part of a scan decision that describes the market.
+func marketDecision(market types.Market, parsed *scanner.ParsedMarket, probability float64, betSide string) *persistence.ScanDecision {
+	decision := &persistence.ScanDecision{
+		Platform:    market.Platform,
+		MarketID:    market.ID,
+		Outcome:     market.Outcome,
+		MarketTitle: market.Title,
+		YesPrice:    market.OutcomeYesPrice,
+		NoPrice:     market.OutcomeNoPrice,
+		Probability: probability,
+		BetSide:     betSide,
+	}
+	if parsed != nil {
+		decision.Asset = parsed.Asset
+	}
+	return decision
+}
+
 // RunMonitorCycle executes a single monitoring cycle for all open positions.
 // It checks each position for stop loss and volatility exit conditions.
 //
 // Flow:
-// 1. Fetch all open positions from database
-// 2. For each position:
+// 1. Refresh order fills so position quantities are current
+// 2. Check platform status; positions on halted platforms are only reported
+// 3. Fetch all open positions from database
+// 4. For each position:
 //    a. Get current market price
 //    b. Check stop loss condition
-//    c. Check volatility exit condition
-//    d. Execute exit if any condition is triggered
-func (b *Bot) RunMonitorCycle() error {
+//    c. Re-analyze with fresh data if a decay checkpoint has been reached
+//    d. Check volatility exit condition
+//    e. Execute exit if any condition is triggered
+//
+// Once ctx is cancelled the remaining positions are left unchecked and its
+// error is returned.
+func (b *Bot) RunMonitorCycle(ctx context.Context) (err error) {
 	log.Info().Msg("starting monitor cycle")
+	b.mu.Lock()
+	b.session.MonitorCycles++
+	b.mu.Unlock()
+	defer func() {
+		if err == nil {
+			b.mu.Lock()
+			b.lastMonitor = time.Now()
+			b.mu.Unlock()
+		}
+	}()
+
+	// Refresh order fills before checking positions
+	if b.orderTracker != nil {
+		if err := b.orderTracker.Poll(); err != nil {
+			log.Error().Err(err).Msg("failed to poll order status")
+		}
+	}
 
 	// Fetch all open positions
 	if b.positionRepo == nil {
@@ -179,11 +1037,27 @@ func (b *Bot) RunMonitorCycle() error {
 		return nil
 	}
 
+	halted := make(map[string]bool)
+	for _, p := range b.platforms {
+		if b.refreshStatus(p).Halted() {
+			halted[p.Name()] = true
+		}
+	}
+
 	positions, err := b.positionRepo.GetOpen()
 	if err != nil {
 		return fmt.Errorf("get open positions: %w", err)
 	}
 
+	// Hedge legs only pay out together, so they are held to resolution
+	var hedged map[int64]bool
+	if b.arbitrageRepo != nil {
+		hedged, err = b.arbitrageRepo.HedgedPositionIDs()
+		if err != nil {
+			return fmt.Errorf("get hedged positions: %w", err)
+		}
+	}
+
 	if len(positions) == 0 {
 		log.Debug().Msg("no open positions to monitor")
 		return nil
@@ -195,9 +1069,21 @@ func (b *Bot) RunMonitorCycle() error {
 
 	var totalExited int
 	var stopLossExits int
+	var takeProfitExits int
 	var volatilityExits int
+	var flattenExits int
+	var timeDecayExits int
+	var decayRechecks int
+	var haltedPositions int
+	now := b.now()
 
+	exitCtx, finish := b.inFlight(ctx)
+	defer finish()
 	for _, pos := range positions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		log.Debug().
 			Int64("position_id", pos.ID).
 			Str("platform", pos.Platform).
@@ -205,15 +1091,26 @@ func (b *Bot) RunMonitorCycle() error {
 			Float64("entry_price", pos.EntryPrice).
 			Msg("checking position")
 
-		// Find the platform for this position
-		var platformClient PriceProvider
-		for _, p := range b.platforms {
-			if provider, ok := p.(PriceProvider); ok && p.Name() == pos.Platform {
-				platformClient = provider
-				break
-			}
+		// Stops can't execute while the platform is halted
+		if halted[pos.Platform] {
+			log.Warn().
+				Int64("position_id", pos.ID).
+				Str("platform", pos.Platform).
+				Str("market_id", pos.MarketID).
+				Msg("platform halted, exit checks deferred")
+			haltedPositions++
+			continue
+		}
+
+		if hedged[pos.ID] {
+			log.Debug().
+				Int64("position_id", pos.ID).
+				Msg("hedged position, held to resolution")
+			continue
 		}
 
+		// Find the platform for this position
+		platformClient := b.priceProvider(pos.Platform)
 		if platformClient == nil {
 			log.Warn().
 				Str("platform", pos.Platform).
@@ -230,37 +1127,185 @@ func (b *Bot) RunMonitorCycle() error {
 				Int64("position_id", pos.ID).
 				Str("market_id", pos.MarketID).
 				Msg("failed to get current price")
+			b.audit.APIError(pos.Platform, pos.MarketID, "get_price", err)
+			b.countError()
 			continue
 		}
 
+		// Track the peak price for trailing stops
+		if b.monitor != nil && b.monitor.Trailing() {
+			if err := b.manager.TrackPeakPrice(pos, currentPrice); err != nil {
+				log.Error().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Msg("failed to record peak price")
+			}
+		}
+
 		// Check stop loss
 		if b.monitor != nil && b.monitor.CheckStopLoss(pos, currentPrice) {
 			log.Info().
 				Int64("position_id", pos.ID).
 				Float64("entry_price", pos.EntryPrice).
+				Float64("peak_price", pos.PeakPrice).
 				Float64("current_price", currentPrice).
 				Msg("stop loss triggered")
+			b.alert(alert.EventStopLoss, fmt.Sprintf("stop loss on %s %s at %.2f", pos.Platform, pos.MarketID, currentPrice))
 
-			_, err := b.manager.ExecuteExit(pos.ID, currentPrice, position.ExitReasonStopLoss, b.config.DryRun)
+			exit, err := b.manager.ExecuteExit(exitCtx, pos.ID, currentPrice, position.ExitReasonStopLoss, b.config.DryRun)
 			if err != nil {
 				log.Error().
 					Err(err).
 					Int64("position_id", pos.ID).
 					Msg("failed to execute stop loss exit")
+				b.alertExitFailure(pos, err)
+				b.countError()
 				continue
 			}
+			b.recordExit(exit)
 
 			stopLossExits++
 			totalExited++
 			continue
 		}
 
+		// Check take profit
+		if b.monitor != nil && b.monitor.CheckTakeProfit(pos, currentPrice) {
+			log.Info().
+				Int64("position_id", pos.ID).
+				Float64("entry_price", pos.EntryPrice).
+				Float64("current_price", currentPrice).
+				Msg("take profit triggered")
+
+			exit, err := b.manager.ExecuteExit(exitCtx, pos.ID, currentPrice, position.ExitReasonTakeProfit, b.config.DryRun)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Msg("failed to execute take profit exit")
+				b.alertExitFailure(pos, err)
+				b.countError()
+				continue
+			}
+			b.recordExit(exit)
+
+			takeProfitExits++
+			totalExited++
+			continue
+		}
+
+		// Flatten ahead of market close if enabled for the asset class
+		if b.monitor != nil && b.monitor.CheckFlatten(pos, now) {
+			log.Info().
+				Int64("position_id", pos.ID).
+				Str("asset", pos.Asset).
+				Time("market_close", *pos.MarketCloseTime).
+				Float64("current_price", currentPrice).
+				Msg("end-of-day flatten triggered")
+
+			exit, err := b.manager.ExecuteExit(exitCtx, pos.ID, currentPrice, position.ExitReasonFlatten, b.config.DryRun)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Msg("failed to execute flatten exit")
+				b.alertExitFailure(pos, err)
+				b.countError()
+				continue
+			}
+			b.recordExit(exit)
+
+			flattenExits++
+			totalExited++
+			continue
+		}
+
+		// Exit early if the margin has deteriorated close to market close
+		if b.monitor != nil && b.volatility != nil {
+			shouldExit, err := b.monitor.CheckTimeDecayExit(ctx, pos, b.volatility, now)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Msg("failed to check time decay exit")
+			} else if shouldExit {
+				log.Info().
+					Int64("position_id", pos.ID).
+					Time("market_close", *pos.MarketCloseTime).
+					Float64("safety_margin_at_entry", pos.SafetyMarginAtEntry).
+					Float64("current_price", currentPrice).
+					Msg("time decay exit triggered")
+
+				exit, err := b.manager.ExecuteExit(exitCtx, pos.ID, currentPrice, position.ExitReasonTimeDecay, b.config.DryRun)
+				if err != nil {
+					log.Error().
+						Err(err).
+						Int64("position_id", pos.ID).
+						Msg("failed to execute time decay exit")
+					b.alertExitFailure(pos, err)
+					b.countError()
+					continue
+				}
+				b.recordExit(exit)
+
+				timeDecayExits++
+				totalExited++
+				continue
+			}
+		}
+
+		// Re-analyze with fresh data at decay checkpoints, since the margin
+		// erodes as time passes even when the price doesn't move
+		if b.monitor != nil && b.volatility != nil {
+			checkpoint, shouldExit, err := b.monitor.CheckDecayCheckpoint(ctx, pos, b.volatility, now)
+			if err != nil {
+				log.Error().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Msg("failed to re-analyze at decay checkpoint")
+			} else if checkpoint > 0 {
+				decayRechecks++
+				log.Info().
+					Int64("position_id", pos.ID).
+					Float64("time_left", checkpoint).
+					Bool("exit", shouldExit).
+					Msg("decay checkpoint re-analysis")
+			}
+
+			if shouldExit {
+				log.Info().
+					Int64("position_id", pos.ID).
+					Float64("entry_price", pos.EntryPrice).
+					Float64("current_price", currentPrice).
+					Msg("volatility exit triggered at decay checkpoint")
+
+				exit, err := b.manager.ExecuteExit(exitCtx, pos.ID, currentPrice, position.ExitReasonVolatility, b.config.DryRun)
+				if err != nil {
+					log.Error().
+						Err(err).
+						Int64("position_id", pos.ID).
+						Msg("failed to execute volatility exit")
+					b.alertExitFailure(pos, err)
+					b.countError()
+					continue
+				}
+				b.recordExit(exit)
+
+				volatilityExits++
+				totalExited++
+				continue
+			}
+		}
+
 		// Check volatility exit
 		if b.monitor != nil && b.volatility != nil {
 			// Calculate time to close (use 24h as default if not available)
 			timeToClose := 24 * time.Hour
+			if pos.MarketCloseTime != nil && pos.MarketCloseTime.After(now) {
+				timeToClose = pos.MarketCloseTime.Sub(now)
+			}
 
-			shouldExit, err := b.monitor.CheckVolatilityExit(pos, b.volatility, timeToClose)
+			shouldExit, err := b.monitor.CheckVolatilityExit(ctx, pos, b.volatility, timeToClose)
 			if err != nil {
 				log.Error().
 					Err(err).
@@ -276,14 +1321,17 @@ func (b *Bot) RunMonitorCycle() error {
 					Float64("current_price", currentPrice).
 					Msg("volatility exit triggered")
 
-				_, err := b.manager.ExecuteExit(pos.ID, currentPrice, position.ExitReasonVolatility, b.config.DryRun)
+				exit, err := b.manager.ExecuteExit(exitCtx, pos.ID, currentPrice, position.ExitReasonVolatility, b.config.DryRun)
 				if err != nil {
 					log.Error().
 						Err(err).
 						Int64("position_id", pos.ID).
 						Msg("failed to execute volatility exit")
+					b.alertExitFailure(pos, err)
+					b.countError()
 					continue
 				}
+				b.recordExit(exit)
 
 				volatilityExits++
 				totalExited++
@@ -301,19 +1349,142 @@ func (b *Bot) RunMonitorCycle() error {
 		Int("total_monitored", len(positions)).
 		Int("total_exited", totalExited).
 		Int("stop_loss_exits", stopLossExits).
+		Int("take_profit_exits", takeProfitExits).
 		Int("volatility_exits", volatilityExits).
+		Int("flatten_exits", flattenExits).
+		Int("time_decay_exits", timeDecayExits).
+		Int("decay_rechecks", decayRechecks).
+		Int("halted_positions", haltedPositions).
 		Msg("monitor cycle complete")
 
 	return nil
 }
 
+// RunSettlementCycle checks open positions for market resolution and
+// settles those whose market has resolved.
+func (b *Bot) RunSettlementCycle() error {
+	if b.settler == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	b.session.SettleCycles++
+	b.mu.Unlock()
+	result, err := b.settler.Run()
+	if err != nil {
+		return fmt.Errorf("run settlement: %w", err)
+	}
+	for _, exit := range result.Settled {
+		b.recordExit(exit)
+	}
+
+	log.Info().
+		Int("checked", result.Checked).
+		Int("settled", len(result.Settled)).
+		Int("pending_redemption", result.Pending).
+		Int("overdue_redemption", result.Overdue).
+		Msg("settlement cycle complete")
+
+	return nil
+}
+
+// RunBalanceCycle reconciles the bankrolls with the platforms' balances.
+// The bankrolls are only adjusted between other cycles, so an entry sized
+// off a bankroll never sees it change midway.
+func (b *Bot) RunBalanceCycle() error {
+	if b.balances == nil {
+		return nil
+	}
+
+	checks, err := b.balances.Run()
+	if err != nil {
+		return fmt.Errorf("reconcile balances: %w", err)
+	}
+
+	log.Info().
+		Int("checked", len(checks)).
+		Msg("balance cycle complete")
+
+	return nil
+}
+
+// RunLearningCycle runs the learning loop and logs, audits and alerts each
+// parameter it changes, or the parameters it reverted because of a
+// drawdown. The changes are applied from the next scan cycle.
+func (b *Bot) RunLearningCycle() error {
+	if b.learner == nil {
+		return nil
+	}
+
+	result, err := b.learner.Run()
+	if err != nil && result == nil {
+		return fmt.Errorf("run learning: %w", err)
+	}
+	for _, c := range result.Changes {
+		log.Info().
+			Str("parameter", c.Name).
+			Float64("old", c.OldValue).
+			Float64("new", c.NewValue).
+			Str("reason", c.Reason).
+			Msg("parameter adjusted")
+		b.audit.ParameterChange(c.Name, c.OldValue, c.NewValue, c.Reason)
+		if !result.Reverted {
+			b.alert(alert.EventParameterAdjusted, fmt.Sprintf("%s %.4g -> %.4g (%s)", c.Name, c.OldValue, c.NewValue, c.Reason))
+		}
+	}
+	if result.Reverted && len(result.Changes) > 0 {
+		reverted := make(map[string]float64, len(result.Changes))
+		for _, c := range result.Changes {
+			reverted[c.Name] = c.OldValue
+		}
+		b.audit.ParameterRevert(result.Equity, result.Peak, result.Drawdown(), reverted)
+		log.Warn().
+			Float64("equity", result.Equity).
+			Float64("peak", result.Peak).
+			Float64("drawdown", result.Drawdown()).
+			Int("reverted", len(result.Changes)).
+			Msg("parameters reverted to defaults on drawdown")
+		b.alert(alert.EventParametersReverted, fmt.Sprintf("drawdown %.1f%% from peak equity $%.2f: %d parameters reverted to defaults",
+			result.Drawdown()*100, result.Peak, len(result.Changes)))
+	}
+	if len(result.Changes) > 0 && b.paramCache != nil {
+		b.paramCache.Invalidate()
+	}
+	if err != nil {
+		return fmt.Errorf("run learning: %w", err)
+	}
+
+	for _, arm := range result.Arms {
+		log.Info().
+			Str("arm", arm.Arm).
+			Int("trades", arm.TradeCount).
+			Float64("win_rate", arm.WinRate).
+			Float64("avg_pnl", arm.AvgPnL).
+			Msg("experiment arm")
+	}
+	if learning.ChallengerLeads(result.Arms) {
+		log.Info().Msg("experiment challenger leads the champion, consider promoting its parameters")
+	}
+	log.Info().
+		Int("adjusted", len(result.Changes)).
+		Bool("reverted", result.Reverted).
+		Msg("learning cycle complete")
+
+	return nil
+}
+
 // Run starts the main bot loop with scan and monitor cycles.
 // It runs until the context is cancelled, executing:
 // - An immediate scan cycle on start
 // - Scan cycles at ScanInterval
 // - Monitor cycles at MonitorInterval
+// - Settlement cycles at SettleInterval
+// - Balance reconciliations at BalanceInterval, if set
+// - Learning cycles at LearningInterval, if set
 //
-// Graceful shutdown is handled via context cancellation.
+// Graceful shutdown is handled via context cancellation: the context is
+// passed to each cycle, so platform requests in flight are abandoned and
+// errors caused by the cancellation aren't counted.
 func (b *Bot) Run(ctx context.Context) error {
 	log.Info().
 		Dur("scan_interval", b.config.ScanInterval).
@@ -323,13 +1494,33 @@ func (b *Bot) Run(ctx context.Context) error {
 		Msg("bot starting")
 
 	// Run immediate scan cycle on start
-	if err := b.RunScanCycle(); err != nil {
+	if err := b.RunScanCycle(ctx); err != nil && ctx.Err() == nil {
 		log.Error().Err(err).Msg("initial scan cycle failed")
+		b.countError()
 	}
 
 	// Run immediate monitor cycle on start
-	if err := b.RunMonitorCycle(); err != nil {
+	if err := b.RunMonitorCycle(ctx); err != nil && ctx.Err() == nil {
 		log.Error().Err(err).Msg("initial monitor cycle failed")
+		b.countError()
+	}
+
+	// Settle positions in markets that resolved while the bot was stopped
+	if err := b.RunSettlementCycle(); err != nil {
+		log.Error().Err(err).Msg("initial settlement cycle failed")
+		b.countError()
+	}
+
+	// Reconcile bankrolls changed while the bot was stopped
+	if err := b.RunBalanceCycle(); err != nil {
+		log.Error().Err(err).Msg("initial balance cycle failed")
+		b.countError()
+	}
+
+	// Learn from trades closed while the bot was stopped
+	if err := b.RunLearningCycle(); err != nil {
+		log.Error().Err(err).Msg("initial learning cycle failed")
+		b.countError()
 	}
 
 	// Create tickers for scan and monitor cycles
@@ -339,22 +1530,67 @@ func (b *Bot) Run(ctx context.Context) error {
 	monitorTicker := time.NewTicker(b.config.MonitorInterval)
 	defer monitorTicker.Stop()
 
+	settleInterval := b.config.SettleInterval
+	if settleInterval <= 0 {
+		settleInterval = b.config.MonitorInterval
+	}
+	settleTicker := time.NewTicker(settleInterval)
+	defer settleTicker.Stop()
+
+	// Balances are only checked with a reconciler and an interval
+	var balanceTick <-chan time.Time
+	if b.balances != nil && b.config.BalanceInterval > 0 {
+		balanceTicker := time.NewTicker(b.config.BalanceInterval)
+		defer balanceTicker.Stop()
+		balanceTick = balanceTicker.C
+	}
+
+	// Learning only runs with a learner and an interval
+	var learningTick <-chan time.Time
+	if b.learner != nil && b.config.LearningInterval > 0 {
+		learningTicker := time.NewTicker(b.config.LearningInterval)
+		defer learningTicker.Stop()
+		learningTick = learningTicker.C
+	}
+
 	log.Info().Msg("bot running, press Ctrl+C to stop")
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Info().Msg("shutting down bot gracefully")
+			b.drain()
+			b.endSession()
 			return nil
 
 		case <-scanTicker.C:
-			if err := b.RunScanCycle(); err != nil {
+			if err := b.RunScanCycle(ctx); err != nil && ctx.Err() == nil {
 				log.Error().Err(err).Msg("scan cycle failed")
+				b.countError()
 			}
 
 		case <-monitorTicker.C:
-			if err := b.RunMonitorCycle(); err != nil {
+			if err := b.RunMonitorCycle(ctx); err != nil && ctx.Err() == nil {
 				log.Error().Err(err).Msg("monitor cycle failed")
+				b.countError()
+			}
+
+		case <-settleTicker.C:
+			if err := b.RunSettlementCycle(); err != nil {
+				log.Error().Err(err).Msg("settlement cycle failed")
+				b.countError()
+			}
+
+		case <-balanceTick:
+			if err := b.RunBalanceCycle(); err != nil {
+				log.Error().Err(err).Msg("balance cycle failed")
+				b.countError()
+			}
+
+		case <-learningTick:
+			if err := b.RunLearningCycle(); err != nil {
+				log.Error().Err(err).Msg("learning cycle failed")
+				b.countError()
 			}
 		}
 	}
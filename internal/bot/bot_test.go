@@ -2,10 +2,18 @@ package bot
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/arbitrage"
+	"prediction-bot/internal/audit"
 	"prediction-bot/internal/config"
+	"prediction-bot/internal/events"
 	"prediction-bot/internal/persistence"
 	"prediction-bot/internal/platform"
 	"prediction-bot/internal/position"
@@ -28,14 +36,14 @@ func (m *MockPlatform) Name() string {
 	return m.name
 }
 
-func (m *MockPlatform) ListMarkets(filter types.MarketFilter) ([]types.Market, error) {
+func (m *MockPlatform) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
 	if m.listErr != nil {
 		return nil, m.listErr
 	}
 	return m.markets, nil
 }
 
-func (m *MockPlatform) GetOrderBook(tokenID string) (*types.OrderBook, error) {
+func (m *MockPlatform) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
 	return &types.OrderBook{}, nil
 }
 
@@ -52,14 +60,17 @@ type MockVolatilityAnalyzer struct {
 	safetyMargin   float64
 	vol            float64
 	recommendation volatility.Recommendation
+	timeToClose    time.Duration // Of the last analysis
 }
 
 func (m *MockVolatilityAnalyzer) AnalyzeAsset(
+	ctx context.Context,
 	asset string,
 	strikePrice float64,
 	direction volatility.Direction,
 	timeToClose time.Duration,
 ) (volatility.ServiceResult, error) {
+	m.timeToClose = timeToClose
 	return volatility.ServiceResult{
 		SafetyMargin:   m.safetyMargin,
 		Volatility:     m.vol,
@@ -146,13 +157,30 @@ func TestRunScanCycle_ExecutesWithoutError(t *testing.T) {
 		ScanInterval:    10 * time.Second,
 		MonitorInterval: 5 * time.Second,
 	}, []platform.Platform{mockPlatform}, sc, manager)
+	bus := events.NewBus()
+	activity, unsubscribe := bus.Subscribe(10)
+	defer unsubscribe()
+	bot.SetEventBus(bus)
 
 	// Run single scan cycle
-	err = bot.RunScanCycle()
+	err = bot.RunScanCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunScanCycle failed: %v", err)
 	}
 
+	// The scan and entry are published as they happen
+	wantEvents := []events.Type{events.ScanStarted, events.MarketEligible, events.ScanCompleted, events.PositionOpened}
+	for _, want := range wantEvents {
+		select {
+		case event := <-activity:
+			if event.Type != want {
+				t.Errorf("expected %s event, got %+v", want, event)
+			}
+		default:
+			t.Errorf("expected %s event, got none", want)
+		}
+	}
+
 	// Verify that the eligible market was processed
 	// Check if position was created
 	positions, err := posRepo.GetOpen()
@@ -282,7 +310,7 @@ func TestRunScanCycle_MultiplePlatforms(t *testing.T) {
 	}, []platform.Platform{platform1, platform2}, sc, manager)
 
 	// Run scan cycle
-	err = bot.RunScanCycle()
+	err = bot.RunScanCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunScanCycle failed: %v", err)
 	}
@@ -311,6 +339,249 @@ func TestRunScanCycle_MultiplePlatforms(t *testing.T) {
 	}
 }
 
+// BarrierVolatilityAnalyzer is a valid analysis that waits, up to a
+// timeout, until another analysis is running alongside it.
+type BarrierVolatilityAnalyzer struct {
+	mu         sync.Mutex
+	inFlight   int
+	overlapped bool
+}
+
+func (m *BarrierVolatilityAnalyzer) AnalyzeAsset(
+	ctx context.Context,
+	asset string,
+	strikePrice float64,
+	direction volatility.Direction,
+	timeToClose time.Duration,
+) (volatility.ServiceResult, error) {
+	m.mu.Lock()
+	m.inFlight++
+	m.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		if m.inFlight > 1 {
+			m.overlapped = true
+		}
+		done := m.overlapped
+		m.mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	m.mu.Lock()
+	m.inFlight--
+	m.mu.Unlock()
+	return volatility.ServiceResult{SafetyMargin: 2.0, Volatility: 0.5, Recommendation: volatility.RecommendationValid}, nil
+}
+
+// TestRunScanCycle_ScansConcurrently tests that platforms and markets are
+// scanned concurrently while every entry is still made once.
+func TestRunScanCycle_ScansConcurrently(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	// Each connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+
+	endDate := time.Now().Add(24 * time.Hour)
+	var platforms []platform.Platform
+	for _, name := range []string{"platform1", "platform2"} {
+		if err := bankRepo.Initialize(name, 100.0); err != nil {
+			t.Fatalf("failed to initialize bankroll: %v", err)
+		}
+		p := &MockPlatform{name: name, balance: 100.0}
+		for i, strike := range []int{90, 95, 100} {
+			p.markets = append(p.markets, types.Market{
+				ID:              fmt.Sprintf("%s-%d", name, i),
+				Platform:        name,
+				Title:           fmt.Sprintf("Will Bitcoin be above $%d,000 on Jan 20?", strike),
+				OutcomeYesPrice: 0.85,
+				OutcomeNoPrice:  0.15,
+				Liquidity:       5000.0,
+				Active:          true,
+				EndDate:         endDate,
+			})
+		}
+		platforms = append(platforms, p)
+	}
+
+	analyzer := &BarrierVolatilityAnalyzer{}
+	manager := position.NewManager(posRepo, bankRepo, analyzer, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+	sc := scanner.NewScanner(config.Parameters{ProbabilityThreshold: 0.80})
+
+	bot := NewBot(BotConfig{
+		DryRun:        true,
+		ScanWorkers:   2,
+		MarketWorkers: 3,
+	}, platforms, sc, manager)
+
+	if err := bot.RunScanCycle(context.Background()); err != nil {
+		t.Fatalf("RunScanCycle failed: %v", err)
+	}
+
+	if !analyzer.overlapped {
+		t.Error("expected markets analyzed concurrently")
+	}
+	positions, err := posRepo.GetOpen()
+	if err != nil {
+		t.Fatalf("failed to get open positions: %v", err)
+	}
+	if len(positions) != 6 {
+		t.Errorf("expected 6 positions, got %d", len(positions))
+	}
+	if session := bot.Session(); session.Entries != 6 {
+		t.Errorf("expected 6 entries in the session, got %d", session.Entries)
+	}
+	for _, name := range []string{"platform1", "platform2"} {
+		if err := bankRepo.CheckLedger(name); err != nil {
+			t.Errorf("expected %s bankroll consistent with its ledger: %v", name, err)
+		}
+		if stats := bot.ScanStats()[name]; stats.Eligible != 3 {
+			t.Errorf("expected 3 eligible markets on %s, got %+v", name, stats)
+		}
+	}
+}
+
+// TestRunScanCycle_ContinuesPastFailedPlatform tests that a platform
+// failing to scan doesn't stop the others, and its error is returned once
+// the cycle completes.
+func TestRunScanCycle_ContinuesPastFailedPlatform(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	for _, name := range []string{"flaky", "mock"} {
+		if err := bankRepo.Initialize(name, 100.0); err != nil {
+			t.Fatalf("failed to initialize bankroll: %v", err)
+		}
+	}
+
+	listErr := errors.New("connection reset")
+	flaky := &MockPlatform{name: "flaky", balance: 100.0, listErr: listErr}
+	healthy := &MockPlatform{
+		name:    "mock",
+		balance: 100.0,
+		markets: []types.Market{{
+			ID:              "healthy-market",
+			Platform:        "mock",
+			Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+			OutcomeYesPrice: 0.85,
+			OutcomeNoPrice:  0.15,
+			Liquidity:       5000.0,
+			Active:          true,
+			EndDate:         time.Now().Add(24 * time.Hour),
+		}},
+	}
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+	bot := NewBot(BotConfig{DryRun: true, ScanWorkers: 1}, []platform.Platform{flaky, healthy}, scanner.NewScanner(config.Parameters{ProbabilityThreshold: 0.80}), manager)
+
+	if err := bot.RunScanCycle(context.Background()); !errors.Is(err, listErr) {
+		t.Fatalf("expected the flaky platform's error, got %v", err)
+	}
+
+	positions, err := posRepo.GetOpen()
+	if err != nil {
+		t.Fatalf("failed to get open positions: %v", err)
+	}
+	if len(positions) != 1 || positions[0].Platform != "mock" {
+		t.Errorf("expected the healthy platform's market entered, got %+v", positions)
+	}
+	if bot.LastScan().IsZero() {
+		t.Error("expected the cycle to count as a scan")
+	}
+}
+
+// TestRunScanCycle_StopsWhenCancelled tests that a cancelled scan cycle
+// enters no markets and returns the cancellation.
+func TestRunScanCycle_StopsWhenCancelled(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	mockPlatform := &MockPlatform{
+		name:    "mock",
+		balance: 100.0,
+		markets: []types.Market{{
+			ID:              "cancelled-market",
+			Platform:        "mock",
+			Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+			OutcomeYesPrice: 0.85,
+			OutcomeNoPrice:  0.15,
+			Liquidity:       5000.0,
+			Active:          true,
+			EndDate:         time.Now().Add(24 * time.Hour),
+		}},
+	}
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+	bot := NewBot(BotConfig{DryRun: true}, []platform.Platform{mockPlatform}, scanner.NewScanner(config.Parameters{ProbabilityThreshold: 0.80}), manager)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := bot.RunScanCycle(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	positions, err := posRepo.GetOpen()
+	if err != nil {
+		t.Fatalf("failed to get open positions: %v", err)
+	}
+	if len(positions) != 0 {
+		t.Errorf("expected no positions opened, got %d", len(positions))
+	}
+}
+
 // TestRunScanCycle_NoEligibleMarkets tests that scan cycle handles empty results gracefully.
 func TestRunScanCycle_NoEligibleMarkets(t *testing.T) {
 	// Create temporary database
@@ -378,7 +649,7 @@ func TestRunScanCycle_NoEligibleMarkets(t *testing.T) {
 	}, []platform.Platform{mockPlatform}, sc, manager)
 
 	// Run scan cycle - should succeed without error
-	err = bot.RunScanCycle()
+	err = bot.RunScanCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunScanCycle failed: %v", err)
 	}
@@ -491,7 +762,7 @@ func TestRunMonitorCycle_ChecksAllOpenPositions(t *testing.T) {
 	bot.SetPositionRepo(posRepo)
 
 	// Run monitor cycle - should complete without error
-	err = bot.RunMonitorCycle()
+	err = bot.RunMonitorCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunMonitorCycle failed: %v", err)
 	}
@@ -507,6 +778,15 @@ func TestRunMonitorCycle_ChecksAllOpenPositions(t *testing.T) {
 	}
 }
 
+// MockAlerter records the alerts raised.
+type MockAlerter struct {
+	events []string
+}
+
+func (m *MockAlerter) Alert(event, message string) {
+	m.events = append(m.events, event)
+}
+
 // TestRunMonitorCycle_TriggersStopLoss tests that stop loss exits are triggered.
 func TestRunMonitorCycle_TriggersStopLoss(t *testing.T) {
 	// Create temporary database
@@ -603,13 +883,19 @@ func TestRunMonitorCycle_TriggersStopLoss(t *testing.T) {
 	bot.SetMonitor(monitor)
 	bot.SetVolatilityAnalyzer(mockVolatility)
 	bot.SetPositionRepo(posRepo)
+	alerter := &MockAlerter{}
+	bot.SetAlerter(alerter)
 
 	// Run monitor cycle
-	err = bot.RunMonitorCycle()
+	err = bot.RunMonitorCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunMonitorCycle failed: %v", err)
 	}
 
+	if len(alerter.events) != 1 || alerter.events[0] != alert.EventStopLoss {
+		t.Errorf("expected a stop loss alert, got %v", alerter.events)
+	}
+
 	// Position should be closed due to stop loss
 	closedPos, err := posRepo.GetByID(posID)
 	if err != nil {
@@ -629,6 +915,163 @@ func TestRunMonitorCycle_TriggersStopLoss(t *testing.T) {
 	}
 }
 
+// TestRunMonitorCycle_TriggersTakeProfit tests that take profit exits are triggered.
+func TestRunMonitorCycle_TriggersTakeProfit(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	posID, err := posRepo.Create(&persistence.Position{
+		Platform:   "mock",
+		MarketID:   "test-market-take-profit",
+		Asset:      "BTC",
+		Strike:     100000,
+		Direction:  "above",
+		EntryPrice: 0.80,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// Take profit at 10%: 0.80 * 1.10 = 0.88, current price 0.92 is above
+	mockPlatform := &MockPlatformWithPrice{
+		name:         "mock",
+		balance:      100.0,
+		markets:      []types.Market{},
+		currentPrice: 0.92,
+	}
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizer)
+
+	monitor := position.NewMonitor(0.15)
+	monitor.SetTakeProfitPercent(0.10)
+
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    10 * time.Second,
+		MonitorInterval: 5 * time.Second,
+	}, []platform.Platform{mockPlatform}, scanner.NewScanner(config.Parameters{}), manager)
+	bot.SetMonitor(monitor)
+	bot.SetVolatilityAnalyzer(mockVolatility)
+	bot.SetPositionRepo(posRepo)
+
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+
+	closedPos, err := posRepo.GetByID(posID)
+	if err != nil {
+		t.Fatalf("failed to get position: %v", err)
+	}
+	if closedPos.Status != "closed" {
+		t.Errorf("expected position to be closed, got status %s", closedPos.Status)
+	}
+	if closedPos.ExitReason == nil || *closedPos.ExitReason != position.ExitReasonTakeProfit {
+		t.Errorf("expected exit reason %q, got %v", position.ExitReasonTakeProfit, closedPos.ExitReason)
+	}
+}
+
+// TestRunMonitorCycle_TriggersTimeDecayExit tests that positions whose safety
+// margin deteriorated are closed ahead of market close.
+func TestRunMonitorCycle_TriggersTimeDecayExit(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	closeTime := time.Now().Add(time.Hour)
+	posID, err := posRepo.Create(&persistence.Position{
+		Platform:            "mock",
+		MarketID:            "test-market-time-decay",
+		Asset:               "BTC",
+		Strike:              100000,
+		Direction:           "above",
+		EntryPrice:          0.90,
+		Quantity:            10.0,
+		Side:                "YES",
+		Status:              "open",
+		SafetyMarginAtEntry: 2.0,
+		MarketCloseTime:     &closeTime,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// Price is above the stop loss and the margin above the volatility exit
+	// threshold, but the margin has fallen from 2.0 at entry to 1.2
+	mockPlatform := &MockPlatformWithPrice{
+		name:         "mock",
+		balance:      100.0,
+		markets:      []types.Market{},
+		currentPrice: 0.85,
+	}
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   1.2,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizer)
+
+	monitor := position.NewMonitor(0.15)
+	monitor.SetTimeDecayLead(2 * time.Hour)
+
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    10 * time.Second,
+		MonitorInterval: 5 * time.Second,
+	}, []platform.Platform{mockPlatform}, scanner.NewScanner(config.Parameters{}), manager)
+	bot.SetMonitor(monitor)
+	bot.SetVolatilityAnalyzer(mockVolatility)
+	bot.SetPositionRepo(posRepo)
+
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+
+	closedPos, err := posRepo.GetByID(posID)
+	if err != nil {
+		t.Fatalf("failed to get position: %v", err)
+	}
+	if closedPos.Status != "closed" {
+		t.Errorf("expected position to be closed, got status %s", closedPos.Status)
+	}
+	if closedPos.ExitReason == nil || *closedPos.ExitReason != position.ExitReasonTimeDecay {
+		t.Errorf("expected exit reason %q, got %v", position.ExitReasonTimeDecay, closedPos.ExitReason)
+	}
+}
+
 // TestRunMonitorCycle_TriggersVolatilityExit tests that volatility exits are triggered.
 func TestRunMonitorCycle_TriggersVolatilityExit(t *testing.T) {
 	// Create temporary database
@@ -654,7 +1097,9 @@ func TestRunMonitorCycle_TriggersVolatilityExit(t *testing.T) {
 		t.Fatalf("failed to initialize bankroll: %v", err)
 	}
 
-	// Create an open position
+	// Create an open position, its market closing in 6h
+	now := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+	closeTime := now.Add(6 * time.Hour)
 	pos := &persistence.Position{
 		Platform:            "mock",
 		MarketID:            "test-market-vol-exit",
@@ -668,6 +1113,7 @@ func TestRunMonitorCycle_TriggersVolatilityExit(t *testing.T) {
 		Status:              "open",
 		SafetyMarginAtEntry: 2.0,
 		VolatilityAtEntry:   0.5,
+		MarketCloseTime:     &closeTime,
 	}
 	posID, err := posRepo.Create(pos)
 	if err != nil {
@@ -723,13 +1169,19 @@ func TestRunMonitorCycle_TriggersVolatilityExit(t *testing.T) {
 	bot.SetMonitor(monitor)
 	bot.SetVolatilityAnalyzer(mockVolatility)
 	bot.SetPositionRepo(posRepo)
+	bot.SetClock(func() time.Time { return now })
 
 	// Run monitor cycle
-	err = bot.RunMonitorCycle()
+	err = bot.RunMonitorCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunMonitorCycle failed: %v", err)
 	}
 
+	// Volatility is re-analyzed over the time left to the market's close
+	if mockVolatility.timeToClose != 6*time.Hour {
+		t.Errorf("expected volatility analyzed over 6h to close, got %s", mockVolatility.timeToClose)
+	}
+
 	// Position should be closed due to volatility exit
 	closedPos, err := posRepo.GetByID(posID)
 	if err != nil {
@@ -825,7 +1277,7 @@ func TestRunMonitorCycle_NoOpenPositions(t *testing.T) {
 	bot.SetPositionRepo(posRepo)
 
 	// Run monitor cycle - should succeed without error
-	err = bot.RunMonitorCycle()
+	err = bot.RunMonitorCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunMonitorCycle failed: %v", err)
 	}
@@ -844,14 +1296,14 @@ func (m *MockPlatformWithPrice) Name() string {
 	return m.name
 }
 
-func (m *MockPlatformWithPrice) ListMarkets(filter types.MarketFilter) ([]types.Market, error) {
+func (m *MockPlatformWithPrice) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
 	if m.listErr != nil {
 		return nil, m.listErr
 	}
 	return m.markets, nil
 }
 
-func (m *MockPlatformWithPrice) GetOrderBook(tokenID string) (*types.OrderBook, error) {
+func (m *MockPlatformWithPrice) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
 	// Return order book with the current price
 	return &types.OrderBook{
 		Bids: []types.Level{{Price: m.currentPrice, Size: 100}},
@@ -972,9 +1424,13 @@ func TestRunScanCycle_SkipsIneligibleMarkets(t *testing.T) {
 		ScanInterval:    10 * time.Second,
 		MonitorInterval: 5 * time.Second,
 	}, []platform.Platform{mockPlatform}, sc, manager)
+	decisionRepo := persistence.NewScanDecisionRepository(db)
+	bot.SetScanDecisionRepo(decisionRepo)
+	snapshotRepo := persistence.NewScanSnapshotRepository(db)
+	bot.SetScanSnapshotRepo(snapshotRepo)
 
 	// Run scan cycle
-	err = bot.RunScanCycle()
+	err = bot.RunScanCycle(context.Background())
 	if err != nil {
 		t.Fatalf("RunScanCycle failed: %v", err)
 	}
@@ -992,7 +1448,53 @@ func TestRunScanCycle_SkipsIneligibleMarkets(t *testing.T) {
 	if len(positions) > 0 && positions[0].MarketID != "eligible-market" {
 		t.Errorf("expected market ID 'eligible-market', got %s", positions[0].MarketID)
 	}
-}
+
+	// Verify the rejections were counted per criterion
+	stats, ok := bot.ScanStats()["mock"]
+	if !ok {
+		t.Fatal("expected scan stats for mock platform")
+	}
+	if stats.Listed != 3 || stats.Eligible != 1 {
+		t.Errorf("expected 3 listed and 1 eligible, got %d and %d", stats.Listed, stats.Eligible)
+	}
+	if stats.Rejections[scanner.CriterionProbability] != 1 {
+		t.Errorf("expected 1 probability rejection, got %d", stats.Rejections[scanner.CriterionProbability])
+	}
+	if stats.Rejections[scanner.RejectionUnparseable] != 1 {
+		t.Errorf("expected 1 unparseable rejection, got %d", stats.Rejections[scanner.RejectionUnparseable])
+	}
+
+	// Verify every evaluated market's decision was recorded
+	decisions, err := decisionRepo.GetSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get scan decisions: %v", err)
+	}
+	reasons := make(map[string]*persistence.ScanDecision)
+	for _, d := range decisions {
+		reasons[d.MarketID] = d
+	}
+	if len(reasons) != 3 {
+		t.Fatalf("expected decisions for 3 markets, got %d", len(reasons))
+	}
+	if d := reasons["eligible-market"]; d.Reason != persistence.ScanDecisionEntered || d.SafetyMargin == nil || *d.SafetyMargin != 2.0 {
+		t.Errorf("expected eligible-market entered with safety margin 2.0, got %+v", d)
+	}
+	if d := reasons["low-prob-market"]; d.Reason != scanner.CriterionProbability || d.Eligible || d.Asset != "ETH" || d.YesPrice != 0.50 {
+		t.Errorf("expected low-prob-market rejected on probability, got %+v", d)
+	}
+	if d := reasons["political-market"]; d.Reason != scanner.RejectionUnparseable || !d.Eligible || d.SafetyMargin != nil {
+		t.Errorf("expected political-market eligible but unparseable, got %+v", d)
+	}
+
+	// Verify the cycle was kept as the platform's latest snapshot
+	snapshots, err := snapshotRepo.GetLatest("mock", 1)
+	if err != nil {
+		t.Fatalf("failed to get scan snapshots: %v", err)
+	}
+	if len(snapshots) != 1 || len(snapshots[0].Decisions) != 3 {
+		t.Errorf("expected a snapshot of 3 decisions, got %+v", snapshots)
+	}
+}
 
 // TestRun_ExecutesCyclesWithTicker tests that Run executes scan and monitor cycles
 // based on configured intervals until context is cancelled.
@@ -1184,6 +1686,92 @@ func TestRun_GracefulShutdownOnContextCancel(t *testing.T) {
 	}
 }
 
+// TestRun_RecordsSessionOnShutdown tests that Run records a summary of the
+// session when it shuts down.
+func TestRun_RecordsSessionOnShutdown(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	// A position below its stop loss is closed by the initial monitor cycle
+	_, err = posRepo.Create(&persistence.Position{
+		Platform:   "mock",
+		MarketID:   "test-market-session",
+		Asset:      "BTC",
+		Strike:     100000,
+		Direction:  "above",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	mockPlatform := &MockPlatformWithPrice{
+		name:         "mock",
+		balance:      100.0,
+		markets:      []types.Market{},
+		currentPrice: 0.70,
+	}
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizer)
+
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    time.Minute,
+		MonitorInterval: time.Minute,
+	}, []platform.Platform{mockPlatform}, scanner.NewScanner(config.Parameters{}), manager)
+	bot.SetMonitor(position.NewMonitor(0.15))
+	bot.SetPositionRepo(posRepo)
+	sessionRepo := persistence.NewSessionRepository(db)
+	bot.SetSessionRepo(sessionRepo)
+
+	// Cancelled once the initial cycles have run
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := bot.Run(ctx); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	sessions, err := sessionRepo.GetRecent(1)
+	if err != nil {
+		t.Fatalf("failed to get sessions: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 recorded session, got %d", len(sessions))
+	}
+
+	session := sessions[0]
+	if !session.DryRun || session.ScanCycles != 1 || session.MonitorCycles != 1 {
+		t.Errorf("expected one dry-run scan and monitor cycle, got %+v", session)
+	}
+	if session.Exits != 1 || session.RealizedPnL >= 0 {
+		t.Errorf("expected one losing exit, got %d exits with PnL %f", session.Exits, session.RealizedPnL)
+	}
+	if session.Errors != 0 {
+		t.Errorf("expected no errors, got %d", session.Errors)
+	}
+}
+
 // TestRun_RunsImmediateScanOnStart tests that Run executes an immediate scan cycle
 // when started, before waiting for the first ticker interval.
 func TestRun_RunsImmediateScanOnStart(t *testing.T) {
@@ -1296,3 +1884,429 @@ func TestRun_RunsImmediateScanOnStart(t *testing.T) {
 		t.Errorf("expected market ID 'immediate-scan-market', got %s", positions[0].MarketID)
 	}
 }
+
+// MockPlatformWithStatus adds platform status reporting to MockPlatformWithPrice.
+type MockPlatformWithStatus struct {
+	MockPlatformWithPrice
+	status types.PlatformStatus
+}
+
+func (m *MockPlatformWithStatus) GetPlatformStatus() (types.PlatformStatus, error) {
+	return m.status, nil
+}
+
+// TestHaltedPlatform_PausesEntriesAndDefersExits tests that a halted platform
+// is not scanned and its positions are not exited until trading resumes.
+func TestHaltedPlatform_PausesEntriesAndDefersExits(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	// Open position whose price is below the stop loss threshold
+	posID, err := posRepo.Create(&persistence.Position{
+		Platform:   "mock",
+		MarketID:   "halted-market",
+		Asset:      "BTC",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	mockPlatform := &MockPlatformWithStatus{
+		MockPlatformWithPrice: MockPlatformWithPrice{
+			name:         "mock",
+			balance:      100.0,
+			currentPrice: 0.70,
+			markets: []types.Market{
+				{
+					ID:              "new-market",
+					Platform:        "mock",
+					Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+					OutcomeYesPrice: 0.85,
+					OutcomeNoPrice:  0.15,
+					Liquidity:       5000.0,
+					Active:          true,
+					EndDate:         time.Now().Add(24 * time.Hour),
+				},
+			},
+		},
+		status: types.PlatformStatus{Platform: "mock", TradingActive: false, Message: "trading inactive"},
+	}
+
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	})
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizer)
+	sc := scanner.NewScanner(config.Parameters{
+		ProbabilityThreshold:   0.80,
+		VolatilitySafetyMargin: 1.5,
+		StopLossPercent:        0.15,
+		KellyFraction:          0.25,
+	})
+
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    10 * time.Second,
+		MonitorInterval: 5 * time.Second,
+	}, []platform.Platform{mockPlatform}, sc, manager)
+	bot.SetMonitor(position.NewMonitor(0.15))
+	bot.SetPositionRepo(posRepo)
+	eventRepo := persistence.NewEventRepository(db)
+	bot.SetAuditRecorder(audit.NewRecorder(eventRepo))
+
+	// Halted: no entry, stop loss deferred
+	if err := bot.RunScanCycle(context.Background()); err != nil {
+		t.Fatalf("RunScanCycle failed: %v", err)
+	}
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+
+	positions, _ := posRepo.GetOpen()
+	if len(positions) != 1 || positions[0].ID != posID {
+		t.Fatalf("expected only the existing position to remain open, got %d positions", len(positions))
+	}
+
+	// Resumed: stop loss executes
+	mockPlatform.status = types.PlatformStatus{Platform: "mock", TradingActive: true}
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+
+	pos, _ := posRepo.GetByID(posID)
+	if pos.Status != "closed" {
+		t.Errorf("expected position closed after trading resumed, got %s", pos.Status)
+	}
+
+	// The halt, resumption and exit are audited once each, oldest last
+	audited, err := eventRepo.Query(persistence.EventFilter{})
+	if err != nil {
+		t.Fatalf("failed to query events: %v", err)
+	}
+	var got []string
+	for _, e := range audited {
+		got = append(got, e.Type)
+	}
+	if want := []string{audit.TypeExit, audit.TypeResume, audit.TypeHalt}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected audit events %v, got %v", want, got)
+	}
+}
+
+// TestScheduledMaintenance_PausesEntriesOnly tests that a platform ahead of
+// its maintenance is not scanned, but its positions are still exited while
+// orders execute.
+func TestScheduledMaintenance_PausesEntriesOnly(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	// Open position whose price is below the stop loss threshold
+	posID, err := posRepo.Create(&persistence.Position{
+		Platform:   "mock",
+		MarketID:   "halted-market",
+		Asset:      "BTC",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	mockPlatform := &MockPlatformWithStatus{
+		MockPlatformWithPrice: MockPlatformWithPrice{
+			name:         "mock",
+			balance:      100.0,
+			currentPrice: 0.70,
+			markets: []types.Market{
+				{
+					ID:              "new-market",
+					Platform:        "mock",
+					Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+					OutcomeYesPrice: 0.85,
+					OutcomeNoPrice:  0.15,
+					Liquidity:       5000.0,
+					Active:          true,
+					EndDate:         time.Now().Add(24 * time.Hour),
+				},
+			},
+		},
+		status: types.PlatformStatus{Platform: "mock", TradingActive: true, MaintenanceScheduled: true, Message: "maintenance scheduled"},
+	}
+
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	})
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizer)
+	sc := scanner.NewScanner(config.Parameters{
+		ProbabilityThreshold:   0.80,
+		VolatilitySafetyMargin: 1.5,
+		StopLossPercent:        0.15,
+		KellyFraction:          0.25,
+	})
+
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    10 * time.Second,
+		MonitorInterval: 5 * time.Second,
+	}, []platform.Platform{mockPlatform}, sc, manager)
+	bot.SetMonitor(position.NewMonitor(0.15))
+	bot.SetPositionRepo(posRepo)
+
+	if err := bot.RunScanCycle(context.Background()); err != nil {
+		t.Fatalf("RunScanCycle failed: %v", err)
+	}
+	if pos, _ := posRepo.GetByMarket("mock", "new-market"); pos != nil {
+		t.Errorf("expected no entry ahead of maintenance, got position %d", pos.ID)
+	}
+
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+	pos, _ := posRepo.GetByID(posID)
+	if pos.Status != "closed" {
+		t.Errorf("expected the stop loss to execute ahead of maintenance, got %s", pos.Status)
+	}
+}
+
+// TestInconsistentBankroll_PausesEntries tests that entries stop and an
+// alert is raised once when the bankroll disagrees with its ledger, and
+// resume once it is consistent again.
+func TestInconsistentBankroll_PausesEntries(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	// A write that bypasses the ledger
+	if _, err := db.Exec(`UPDATE bankroll SET current_micros = 1000000000 WHERE platform = 'mock'`); err != nil {
+		t.Fatalf("failed to corrupt bankroll: %v", err)
+	}
+
+	mockPlatform := &MockPlatform{
+		name:    "mock",
+		balance: 100.0,
+		markets: []types.Market{
+			{
+				ID:              "market-1",
+				Platform:        "mock",
+				Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+				OutcomeYesPrice: 0.85,
+				OutcomeNoPrice:  0.15,
+				Liquidity:       5000.0,
+				Active:          true,
+				EndDate:         time.Now().Add(24 * time.Hour),
+			},
+		},
+	}
+
+	mockVolatility := &MockVolatilityAnalyzer{
+		safetyMargin:   2.0,
+		vol:            0.5,
+		recommendation: volatility.RecommendationValid,
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	})
+	manager := position.NewManager(posRepo, bankRepo, mockVolatility, sizer)
+	sc := scanner.NewScanner(config.Parameters{
+		ProbabilityThreshold:   0.80,
+		VolatilitySafetyMargin: 1.5,
+		StopLossPercent:        0.15,
+		KellyFraction:          0.25,
+	})
+
+	bot := NewBot(BotConfig{
+		DryRun:          true,
+		ScanInterval:    10 * time.Second,
+		MonitorInterval: 5 * time.Second,
+	}, []platform.Platform{mockPlatform}, sc, manager)
+	alerter := &MockAlerter{}
+	bot.SetAlerter(alerter)
+
+	// Inconsistent: no entry, one alert across cycles
+	for i := 0; i < 2; i++ {
+		if err := bot.RunScanCycle(context.Background()); err != nil {
+			t.Fatalf("RunScanCycle failed: %v", err)
+		}
+	}
+	positions, _ := posRepo.GetOpen()
+	if len(positions) != 0 {
+		t.Fatalf("expected no entries while inconsistent, got %d positions", len(positions))
+	}
+	if len(alerter.events) != 1 || alerter.events[0] != alert.EventLedgerInconsistent {
+		t.Errorf("expected one ledger alert, got %v", alerter.events)
+	}
+
+	// Reconciled: entries resume
+	if err := bankRepo.Initialize("mock", 100.0); err != nil {
+		t.Fatalf("failed to reinitialize bankroll: %v", err)
+	}
+	if err := bot.RunScanCycle(context.Background()); err != nil {
+		t.Fatalf("RunScanCycle failed: %v", err)
+	}
+	positions, _ = posRepo.GetOpen()
+	if len(positions) != 1 {
+		t.Errorf("expected entries to resume, got %d positions", len(positions))
+	}
+}
+
+// TestRunArbitrageCycle_HedgesAndHoldsLegs tests that a divergence between
+// equivalent markets is recorded and hedged, and that the hedge legs are not
+// stopped out.
+func TestRunArbitrageCycle_HedgesAndHoldsLegs(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	posRepo := persistence.NewPositionRepository(db)
+	bankRepo := persistence.NewBankrollRepository(db)
+	arbRepo := persistence.NewArbitrageRepository(db)
+	bankRepo.Initialize("polymarket", 100.0)
+	bankRepo.Initialize("kalshi", 100.0)
+
+	endDate := time.Now().Add(24 * time.Hour)
+	poly := &MockPlatformWithPrice{
+		name:         "polymarket",
+		currentPrice: 0.01,
+		markets: []types.Market{{
+			ID:              "0xBTC",
+			Platform:        "polymarket",
+			Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+			OutcomeYesPrice: 0.82,
+			OutcomeNoPrice:  0.18,
+			Active:          true,
+			EndDate:         endDate,
+		}},
+	}
+	kalshi := &MockPlatformWithPrice{
+		name:         "kalshi",
+		currentPrice: 0.01,
+		markets: []types.Market{{
+			ID:              "KXBTC",
+			Platform:        "kalshi",
+			Title:           "Bitcoin above $100k on January 20",
+			OutcomeYesPrice: 0.74,
+			OutcomeNoPrice:  0.26,
+			Active:          true,
+			EndDate:         endDate,
+		}},
+	}
+
+	manager := position.NewManager(posRepo, bankRepo, &MockVolatilityAnalyzer{}, sizing.NewSizer(sizing.SizerConfig{}))
+	bot := NewBot(BotConfig{DryRun: true}, []platform.Platform{poly, kalshi}, nil, manager)
+	bot.SetMonitor(position.NewMonitor(0.15))
+	bot.SetPositionRepo(posRepo)
+	bot.SetArbitrageDetector(arbitrage.NewDetector(0.05))
+	bot.SetArbitrageRepo(arbRepo)
+	bot.SetHedgeSize(9.2)
+
+	if err := bot.RunArbitrageCycle(context.Background()); err != nil {
+		t.Fatalf("RunArbitrageCycle failed: %v", err)
+	}
+
+	opportunities, _ := arbRepo.GetSince(time.Now().Add(-time.Hour))
+	if len(opportunities) != 1 || !opportunities[0].Hedged() {
+		t.Fatalf("expected 1 hedged opportunity, got %+v", opportunities)
+	}
+
+	positions, _ := posRepo.GetOpen()
+	if len(positions) != 2 {
+		t.Fatalf("expected 2 hedge legs, got %d", len(positions))
+	}
+
+	// A second cycle does not hedge the same pair again
+	if err := bot.RunArbitrageCycle(context.Background()); err != nil {
+		t.Fatalf("RunArbitrageCycle failed: %v", err)
+	}
+	positions, _ = posRepo.GetOpen()
+	if len(positions) != 2 {
+		t.Fatalf("expected hedge to be opened once, got %d positions", len(positions))
+	}
+
+	// Both legs are far below their stop loss but are held to resolution
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+	positions, _ = posRepo.GetOpen()
+	if len(positions) != 2 {
+		t.Errorf("expected hedge legs to stay open, got %d open positions", len(positions))
+	}
+}
+
+func TestRunMonitorCycle_RecordsLastMonitor(t *testing.T) {
+	bot := NewBot(BotConfig{DryRun: true}, nil, nil, nil)
+	if !bot.LastMonitor().IsZero() {
+		t.Fatalf("expected no monitor cycle yet, got %v", bot.LastMonitor())
+	}
+
+	before := time.Now()
+	if err := bot.RunMonitorCycle(context.Background()); err != nil {
+		t.Fatalf("RunMonitorCycle failed: %v", err)
+	}
+	if bot.LastMonitor().Before(before) {
+		t.Errorf("expected the monitor cycle recorded, got %v", bot.LastMonitor())
+	}
+}
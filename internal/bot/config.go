@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"fmt"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/risk"
+	"prediction-bot/internal/sizing"
+
+	"github.com/rs/zerolog/log"
+)
+
+// configReloadReason is the reason config changes are recorded with in
+// parameter_history.
+const configReloadReason = "config reload"
+
+// SetConfigWatcher sets the watcher config.yaml is reloaded from at the
+// start of each scan cycle, the config the components were configured with
+// (its parameters without the active strategy's overrides) and where the
+// changes applied are recorded, in parameter_history. Without a repository
+// changes are only logged.
+func (b *Bot) SetConfigWatcher(watcher *config.Watcher, loaded *config.Config, params *persistence.ParametersRepository) {
+	b.configWatcher = watcher
+	b.loadedConfig = loaded
+	b.paramsRepo = params
+}
+
+// reloadConfig reloads config.yaml if it changed or a reload was forced,
+// and applies its parameters, risk limits, allocation, fade and best strike
+// setting to the scanner, manager and monitor between cycles, so a cycle
+// never runs with part of a change. A config that doesn't load or apply is
+// logged and the loaded one kept. Other settings need a restart.
+func (b *Bot) reloadConfig() {
+	if b.configWatcher == nil {
+		return
+	}
+	next, err := b.configWatcher.Reload()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to reload config, keeping the loaded one")
+		return
+	}
+	if next == nil {
+		return
+	}
+
+	if err := b.applyConfig(next); err != nil {
+		log.Warn().Err(err).Msg("invalid config, keeping the loaded one")
+		if err := b.applyConfig(b.loadedConfig); err != nil {
+			log.Error().Err(err).Msg("failed to restore the loaded config")
+		}
+		return
+	}
+
+	b.syncConfigParameters(next.Parameters)
+
+	changes := b.loadedConfig.ReloadableChanges(next)
+	for _, c := range changes {
+		log.Info().Str("setting", c.Name).Float64("old", c.OldValue).Float64("new", c.NewValue).Msg("config setting changed")
+		if b.paramsRepo == nil {
+			continue
+		}
+		if err := b.paramsRepo.RecordChange(c.Name, c.OldValue, c.NewValue, configReloadReason); err != nil {
+			log.Warn().Err(err).Str("setting", c.Name).Msg("failed to record config change")
+		}
+	}
+	if b.loadedConfig.RequiresRestart(next) {
+		log.Warn().Msg("config changes outside parameters, risk, allocation, fade and scan.best_strike_per_event need a restart to apply")
+	}
+	b.loadedConfig = next
+	log.Info().Int("changes", len(changes)).Msg("config reloaded")
+}
+
+// applyConfig applies cfg's reloadable settings, with the active strategy's
+// overrides on its parameters. Settings that can be invalid are applied
+// first; on an error the rest isn't applied.
+func (b *Bot) applyConfig(cfg *config.Config) error {
+	params := b.activeParameters(cfg.Parameters)
+	if err := b.applyParameters(params); err != nil {
+		return err
+	}
+
+	var kellySteps []sizing.DrawdownStep
+	for _, step := range cfg.Parameters.KellyDrawdown {
+		kellySteps = append(kellySteps, sizing.DrawdownStep{Drawdown: step.Drawdown, Scale: step.Scale})
+	}
+	if err := b.manager.SetKellyDrawdown(kellySteps); err != nil {
+		return fmt.Errorf("parameters.kelly_drawdown: %w", err)
+	}
+	if err := b.manager.SetAllocation(cfg.Allocation); err != nil {
+		return fmt.Errorf("allocation: %w", err)
+	}
+	if b.monitor != nil {
+		if err := b.monitor.SetDecayCheckpoints(params.DecayRecheckPoints); err != nil {
+			return fmt.Errorf("parameters.decay_recheck_points: %w", err)
+		}
+	}
+
+	b.baseParams = cfg.Parameters
+	checker := risk.NewChecker(risk.Limits{
+		MaxAssetExposure:       cfg.Risk.MaxAssetExposure,
+		MaxOpenPositions:       cfg.Risk.MaxOpenPositions,
+		MaxDirectionalExposure: cfg.Risk.MaxDirectionalExposure,
+		MaxDailyVaR:            cfg.Risk.MaxDailyVaR,
+		VaRConfidence:          cfg.Risk.VaRConfidence,
+	})
+	checker.SetClock(b.now)
+	b.manager.SetRiskChecker(checker)
+	b.manager.SetBestStrikePerEvent(cfg.Scan.BestStrikePerEvent)
+	b.manager.SetFadeMinEdge(cfg.Fade.Edge())
+	return nil
+}
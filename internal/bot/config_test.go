@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+)
+
+// writeConfig writes a config file and moves its modification time forward
+// so each write is seen as a change.
+func writeConfig(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to set config time: %v", err)
+	}
+}
+
+func TestReloadConfig_AppliesAndRecordsChanges(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	modTime := time.Now().Add(-time.Hour)
+	writeConfig(t, path, "parameters:\n  kelly_fraction: 0.25\nrisk:\n  max_open_positions: 10\n", modTime)
+
+	loaded, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	watcher, err := config.NewWatcher(path)
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := position.NewManager(persistence.NewPositionRepository(db), persistence.NewBankrollRepository(db), &MockVolatilityAnalyzer{}, sizer)
+	params := persistence.NewParametersRepository(db)
+	bot := NewBot(BotConfig{DryRun: true}, []platform.Platform{}, scanner.NewScanner(loaded.Parameters), manager)
+	bot.SetConfigWatcher(watcher, loaded, params)
+
+	// Unchanged: nothing is reloaded
+	bot.reloadConfig()
+	if history, _ := params.GetHistory("parameters.kelly_fraction", 10); len(history) != 0 {
+		t.Fatalf("expected no change recorded, got %+v", history)
+	}
+
+	modTime = modTime.Add(time.Minute)
+	writeConfig(t, path, "parameters:\n  kelly_fraction: 0.10\nrisk:\n  max_open_positions: 5\nallocation:\n  favorite: 0.8\n", modTime)
+	bot.reloadConfig()
+
+	if got := sizer.KellyFraction(); got != 0.10 {
+		t.Errorf("expected the kelly fraction reloaded to 0.10, got %v", got)
+	}
+	for name, want := range map[string][2]float64{
+		"parameters.kelly_fraction": {0.25, 0.10},
+		"risk.max_open_positions":   {10, 5},
+		"allocation.favorite":       {0, 0.8},
+	} {
+		history, err := params.GetHistory(name, 10)
+		if err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(history) != 1 || history[0].OldValue != want[0] || history[0].NewValue != want[1] || history[0].Reason != configReloadReason {
+			t.Errorf("expected %s recorded from %v to %v, got %+v", name, want[0], want[1], history)
+		}
+	}
+
+	// An invalid config is rejected whole: the valid kelly fraction isn't
+	// applied either
+	modTime = modTime.Add(time.Minute)
+	writeConfig(t, path, "parameters:\n  kelly_fraction: 0.50\nallocation:\n  unknown: 0.5\n", modTime)
+	bot.reloadConfig()
+	if got := sizer.KellyFraction(); got != 0.10 {
+		t.Errorf("expected the kelly fraction kept at 0.10, got %v", got)
+	}
+	if history, _ := params.GetHistory("parameters.kelly_fraction", 10); len(history) != 1 {
+		t.Errorf("expected no change recorded for an invalid config, got %+v", history)
+	}
+
+	// A forced reload (SIGHUP) loads the file though it didn't change
+	writeConfig(t, path, "parameters:\n  kelly_fraction: 0.15\n", modTime)
+	bot.reloadConfig()
+	if got := sizer.KellyFraction(); got != 0.10 {
+		t.Errorf("expected an unchanged file not reloaded, got %v", got)
+	}
+	watcher.Force()
+	bot.reloadConfig()
+	if got := sizer.KellyFraction(); got != 0.15 {
+		t.Errorf("expected a forced reload to apply 0.15, got %v", got)
+	}
+}
+
+func TestConfig_ReloadableChangesAndRestart(t *testing.T) {
+	old := &config.Config{
+		Parameters: config.Parameters{KellyFraction: 0.25, KellyDrawdown: []config.KellyDrawdownStep{{Drawdown: 0.1, Scale: 0.5}}},
+		Scan:       config.Scan{IntervalSeconds: 10},
+		Fade:       config.Fade{Enabled: true},
+	}
+	next := &config.Config{
+		Parameters: config.Parameters{KellyFraction: 0.25},
+		Scan:       config.Scan{IntervalSeconds: 10, BestStrikePerEvent: true},
+	}
+
+	changes := old.ReloadableChanges(next)
+	want := []config.Change{
+		{Name: "fade.enabled", OldValue: 1, NewValue: 0},
+		{Name: "parameters.kelly_drawdown[0].drawdown", OldValue: 0.1, NewValue: 0},
+		{Name: "parameters.kelly_drawdown[0].scale", OldValue: 0.5, NewValue: 0},
+		{Name: "scan.best_strike_per_event", OldValue: 0, NewValue: 1},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], changes[i])
+		}
+	}
+	if old.RequiresRestart(next) {
+		t.Error("expected reloadable changes not to need a restart")
+	}
+
+	next.Scan.IntervalSeconds = 30
+	if !old.RequiresRestart(next) {
+		t.Error("expected a scan interval change to need a restart")
+	}
+}
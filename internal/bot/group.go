@@ -0,0 +1,60 @@
+package bot
+
+import "sync"
+
+// group runs tasks on at most limit goroutines at once, like an errgroup
+// with a limit, and keeps the first error. Tasks added after one has failed
+// are skipped, so a group limited to one runs tasks in order and stops at
+// the first failure.
+type group struct {
+	wg  sync.WaitGroup
+	sem chan struct{}
+	mu  sync.Mutex
+	err error
+}
+
+// newGroup creates a group running up to limit tasks at once (1 if limit
+// is below 1).
+func newGroup(limit int) *group {
+	if limit < 1 {
+		limit = 1
+	}
+	return &group{sem: make(chan struct{}, limit)}
+}
+
+// Go runs task once a goroutine is free, unless a task has failed.
+func (g *group) Go(task func() error) {
+	g.sem <- struct{}{}
+	if g.failed() {
+		<-g.sem
+		return
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			<-g.sem
+			g.wg.Done()
+		}()
+		if err := task(); err != nil {
+			g.mu.Lock()
+			if g.err == nil {
+				g.err = err
+			}
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait waits for the running tasks and returns the first error.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+// failed reports whether a task has failed.
+func (g *group) failed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err != nil
+}
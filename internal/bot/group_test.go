@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGroup_SerialStopsAtFirstFailure(t *testing.T) {
+	g := newGroup(1)
+	var ran []int
+	for i := 0; i < 3; i++ {
+		g.Go(func() error {
+			ran = append(ran, i)
+			if i == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err == nil || err.Error() != "failed" {
+		t.Errorf("expected the task's error, got %v", err)
+	}
+	if len(ran) != 2 || ran[0] != 0 || ran[1] != 1 {
+		t.Errorf("expected tasks run in order up to the failure, got %v", ran)
+	}
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"reflect"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/strategy"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SetParameterCache sets the cache of the parameters table the scanner,
+// sizer and monitor are configured from at the start of each scan cycle, so
+// adjustments made by learning or botctl take effect in the running bot.
+// The table's values override config.yaml's, and values learned for a
+// platform and asset override those for its markets; the active strategy's
+// override all of them. Without it the bot keeps config.yaml's parameters.
+func (b *Bot) SetParameterCache(cache *persistence.ParameterCache) {
+	b.paramCache = cache
+}
+
+// refreshParameters applies the current parameters to the scanner, sizer and
+// monitor if they changed since they were last applied, and the parameters
+// learned for each platform and asset to the scanner.
+func (b *Bot) refreshParameters() {
+	if b.paramCache == nil {
+		return
+	}
+	b.refreshScopes()
+	params := b.activeParameters(b.baseParams)
+	if reflect.DeepEqual(params, b.appliedParams) {
+		return
+	}
+	if err := b.applyParameters(params); err != nil {
+		log.Warn().Err(err).Msg("invalid stop loss type in parameters")
+	}
+	log.Info().
+		Float64("probability_threshold", params.ProbabilityThreshold).
+		Float64("volatility_safety_margin", params.VolatilitySafetyMargin).
+		Float64("stop_loss_percent", params.StopLossPercent).
+		Float64("kelly_fraction", params.KellyFraction).
+		Msg("parameters applied")
+}
+
+// scopedParameters resolves the parameters of each platform and asset from
+// a snapshot of the parameters table: config.yaml's, with the table's values
+// for the scope (see persistence.ScopedValues) and the active strategy's
+// overrides applied.
+type scopedParameters struct {
+	base     config.Parameters
+	values   map[string]float64
+	strategy *strategy.Strategy
+}
+
+// Parameters implements scanner.ParameterScopes.
+func (p *scopedParameters) Parameters(platform, asset string) config.Parameters {
+	params := p.base.WithValues(persistence.ScopedValues(p.values, platform, asset))
+	if p.strategy != nil {
+		params = p.strategy.Apply(params)
+	}
+	return params
+}
+
+// refreshScopes has the scanner filter each market with the parameters
+// learned for its platform and asset, if any were, from the table's values
+// as they are now, so a scan never sees part of a change.
+func (b *Bot) refreshScopes() {
+	// A table that can't be read is logged by withTableValues
+	values, _ := b.paramCache.Values()
+	if !persistence.HasScopes(values) {
+		b.scanner.SetParameterScopes(nil)
+		return
+	}
+	scopes := &scopedParameters{base: b.baseParams, values: values}
+	if b.strategies != nil && b.strategyName != "" {
+		if s, ok := b.strategies.Get(b.strategyName); ok {
+			scopes.strategy = &s
+		}
+	}
+	b.scanner.SetParameterScopes(scopes)
+}
+
+// withTableValues returns base with the parameters table's values, if a
+// cache is set, applied. A table that can't be read is logged and the last
+// values read, if any, used.
+func (b *Bot) withTableValues(base config.Parameters) config.Parameters {
+	if b.paramCache == nil {
+		return base
+	}
+	values, err := b.paramCache.Values()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to read parameters table, using the last values read")
+	}
+	return base.WithValues(values)
+}
+
+// syncConfigParameters sets the parameters table's values that params, from
+// config.yaml, changed, so an edit to config.yaml overrides what learning
+// adjusted, and applies them. Without a repository it does nothing.
+func (b *Bot) syncConfigParameters(params config.Parameters) {
+	if b.paramsRepo == nil {
+		return
+	}
+	changes, err := b.paramsRepo.SyncConfigValues(params.Values(), configReloadReason)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to sync parameters table with config")
+		return
+	}
+	if len(changes) == 0 || b.paramCache == nil {
+		return
+	}
+	b.paramCache.Invalidate()
+	b.refreshParameters()
+}
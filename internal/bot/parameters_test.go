@@ -0,0 +1,165 @@
+package bot
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/alert"
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/learning"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/pkg/types"
+)
+
+func TestRefreshParameters_AppliesTableChanges(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	base := config.Parameters{KellyFraction: 0.25, StopLossPercent: 0.15, TakeProfitPercent: 0.5}
+	params := persistence.NewParametersRepository(db)
+	if _, err := params.SyncConfigValues(base.Values(), "config.yaml"); err != nil {
+		t.Fatalf("SyncConfigValues failed: %v", err)
+	}
+
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := position.NewManager(persistence.NewPositionRepository(db), persistence.NewBankrollRepository(db), &MockVolatilityAnalyzer{}, sizer)
+	bot := NewBot(BotConfig{DryRun: true}, []platform.Platform{}, scanner.NewScanner(base), manager)
+	bot.SetMonitor(position.NewMonitor(base.StopLossPercent))
+	bot.paramsRepo = params
+	bot.baseParams = base
+	bot.SetParameterCache(persistence.NewParameterCache(params, 0))
+
+	// Learning adjusts the table: the next cycle sizes and exits with it
+	if err := params.SaveWithReason("kelly_fraction", 0.40, "learning"); err != nil {
+		t.Fatalf("SaveWithReason failed: %v", err)
+	}
+	if err := params.SaveWithReason("stop_loss_percent", 0.10, "learning"); err != nil {
+		t.Fatalf("SaveWithReason failed: %v", err)
+	}
+	bot.refreshParameters()
+
+	if got := sizer.KellyFraction(); got != 0.40 {
+		t.Errorf("expected the learned kelly fraction 0.40, got %v", got)
+	}
+	if got := bot.appliedParams.StopLossPercent; got != 0.10 {
+		t.Errorf("expected the learned stop loss 0.10, got %v", got)
+	}
+	// Parameters not in the table keep config.yaml's value
+	if got := bot.appliedParams.TakeProfitPercent; got != 0.5 {
+		t.Errorf("expected take profit kept at 0.5, got %v", got)
+	}
+
+	// A config.yaml change overrides the learned value
+	next := base
+	next.KellyFraction = 0.10
+	bot.syncConfigParameters(next)
+	if got := sizer.KellyFraction(); got != 0.10 {
+		t.Errorf("expected config's kelly fraction 0.10, got %v", got)
+	}
+	if got := bot.appliedParams.StopLossPercent; got != 0.10 {
+		t.Errorf("expected the learned stop loss kept at 0.10, got %v", got)
+	}
+}
+
+func TestRunLearningCycle_AlertsAndAppliesAdjustments(t *testing.T) {
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	// 25 winning trades entered at 0.92 pull probability_threshold up
+	posRepo := persistence.NewPositionRepository(db)
+	for i := 0; i < 25; i++ {
+		id, err := posRepo.Create(&persistence.Position{Platform: "mock", MarketID: fmt.Sprintf("m-%d", i), EntryPrice: 0.92, Quantity: 10, Side: "YES", Status: "open"})
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		if err := posRepo.Close(id, 1.0, "market_resolved", types.Dollars(0.8)); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+
+	base := config.Parameters{ProbabilityThreshold: 0.80, KellyFraction: 0.25}
+	params := persistence.NewParametersRepository(db)
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := position.NewManager(posRepo, persistence.NewBankrollRepository(db), &MockVolatilityAnalyzer{}, sizer)
+	bot := NewBot(BotConfig{DryRun: true}, []platform.Platform{}, scanner.NewScanner(base), manager)
+	bot.baseParams = base
+	bot.SetParameterCache(persistence.NewParameterCache(params, time.Hour))
+	bot.refreshParameters()
+	alerter := &MockAlerter{}
+	bot.SetAlerter(alerter)
+	bot.SetLearner(learning.NewLearner(learning.NewCollector(db), params, 0))
+
+	if err := bot.RunLearningCycle(); err != nil {
+		t.Fatalf("RunLearningCycle failed: %v", err)
+	}
+	if len(alerter.events) != 1 || alerter.events[0] != alert.EventParameterAdjusted {
+		t.Errorf("expected one parameter_adjusted alert, got %v", alerter.events)
+	}
+
+	// The cache was invalidated: the next cycle applies the adjustment
+	bot.refreshParameters()
+	if got := bot.appliedParams.ProbabilityThreshold; got < 0.8799 || got > 0.8801 {
+		t.Errorf("expected probability_threshold adjusted to 0.88, got %v", got)
+	}
+
+	// A drawdown from the recorded peak reverts it, alerted once
+	learner := learning.NewLearner(learning.NewCollector(db), params, 0)
+	equity := stubEquity(1000)
+	learner.SetEquityTracking(&equity, persistence.NewEquityRepository(db))
+	bot.SetLearner(learner)
+	for _, e := range []stubEquity{1000, 700, 700} {
+		equity = e
+		if err := bot.RunLearningCycle(); err != nil {
+			t.Fatalf("RunLearningCycle failed: %v", err)
+		}
+	}
+	if len(alerter.events) != 2 || alerter.events[1] != alert.EventParametersReverted {
+		t.Errorf("expected one parameters_reverted alert, got %v", alerter.events)
+	}
+	bot.refreshParameters()
+	if got := bot.appliedParams.ProbabilityThreshold; got != 0.80 {
+		t.Errorf("expected probability_threshold reverted to 0.80, got %v", got)
+	}
+}
+
+// stubEquity is a learning.EquitySource reporting a set equity.
+type stubEquity float64
+
+func (e *stubEquity) RealizedEquity() (float64, error) {
+	return float64(*e), nil
+}
+
+func TestParameters_ValuesRoundTrip(t *testing.T) {
+	p := config.Parameters{KellyFraction: 0.25, MinLiquidity: 100, KellyDrawdown: []config.KellyDrawdownStep{{Drawdown: 0.1, Scale: 0.5}}}
+	values := p.Values()
+	if values["kelly_fraction"] != 0.25 || values["min_liquidity"] != 100 {
+		t.Errorf("expected values by YAML name, got %v", values)
+	}
+
+	got := p.WithValues(map[string]float64{"kelly_fraction": 0.4, "unknown": 1})
+	if got.KellyFraction != 0.4 || got.MinLiquidity != 100 || len(got.KellyDrawdown) != 1 {
+		t.Errorf("expected only kelly_fraction overridden, got %+v", got)
+	}
+	if p.KellyFraction != 0.25 {
+		t.Errorf("expected the original unchanged, got %v", p.KellyFraction)
+	}
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// inFlight returns the context an entry or exit places and records its
+// orders with. A shutdown stops the cycles between markets and positions,
+// but the one in progress keeps its context for DrainTimeout after ctx is
+// cancelled, so it isn't cut off between placing an order and recording
+// the position. finish releases it.
+func (b *Bot) inFlight(ctx context.Context) (work context.Context, finish context.CancelFunc) {
+	if b.config.DrainTimeout <= 0 {
+		return ctx, func() {}
+	}
+	work, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(b.config.DrainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			log.Warn().
+				Dur("drain_timeout", b.config.DrainTimeout).
+				Msg("in-flight orders cut off at shutdown")
+			cancel()
+		case <-work.Done():
+		}
+	})
+	return work, func() {
+		stop()
+		cancel()
+	}
+}
+
+// drain settles what is left once the cycles have stopped for shutdown:
+// it records the fills of the orders still being tracked, so positions
+// match what executed, and cancels the orders resting on the open
+// positions' markets if CancelOnShutdown is set.
+func (b *Bot) drain() {
+	if b.orderTracker != nil {
+		if err := b.orderTracker.Poll(); err != nil {
+			log.Error().Err(err).Msg("failed to poll order status at shutdown")
+		}
+	}
+
+	if !b.config.CancelOnShutdown || b.config.DryRun {
+		return
+	}
+	cancelled, err := b.manager.CancelRestingOrders()
+	if err != nil {
+		log.Error().Err(err).Int("cancelled", cancelled).Msg("failed to cancel resting orders at shutdown")
+		return
+	}
+	log.Info().Int("cancelled", cancelled).Msg("resting orders cancelled at shutdown")
+}
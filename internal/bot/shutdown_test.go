@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInFlight_OutlivesShutdownByDrainTimeout(t *testing.T) {
+	bot := NewBot(BotConfig{DryRun: true, DrainTimeout: 50 * time.Millisecond}, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	work, finish := bot.inFlight(ctx)
+	defer finish()
+
+	cancel()
+	select {
+	case <-work.Done():
+		t.Fatal("expected the in-flight context to outlive the shutdown")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case <-work.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the in-flight context cancelled after the drain timeout")
+	}
+}
+
+func TestInFlight_WithoutDrainTimeout(t *testing.T) {
+	bot := NewBot(BotConfig{DryRun: true}, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	work, finish := bot.inFlight(ctx)
+	defer finish()
+
+	cancel()
+	if work.Err() == nil {
+		t.Error("expected the in-flight context cancelled with the shutdown")
+	}
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"time"
+
+	"prediction-bot/internal/config"
+	"prediction-bot/internal/strategy"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SetStrategies sets the registry the active strategy is reloaded from at
+// the start of each scan cycle, and the parameters from config.yaml its
+// overrides apply on top of. The components must already be configured
+// with the active strategy applied; a reload only applies changes.
+func (b *Bot) SetStrategies(registry *strategy.Registry, active string, base config.Parameters) {
+	b.strategies = registry
+	b.strategyName = active
+	b.baseParams = base
+}
+
+// reloadStrategy reloads the strategy files if they changed and applies the
+// active strategy's filters, thresholds, sizing and exits to the scanner,
+// sizer and monitor. Invalid files are logged and the loaded strategy kept.
+func (b *Bot) reloadStrategy() {
+	if b.strategies == nil || b.strategyName == "" {
+		return
+	}
+	changed, err := b.strategies.Reload()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to reload strategies, keeping the loaded ones")
+		return
+	}
+	if !changed {
+		return
+	}
+	s, ok := b.strategies.Get(b.strategyName)
+	if !ok {
+		log.Warn().Str("strategy", b.strategyName).Msg("active strategy no longer defined, keeping the loaded one")
+		return
+	}
+
+	if err := b.applyParameters(b.activeParameters(b.baseParams)); err != nil {
+		log.Warn().Err(err).Msg("invalid stop loss type in strategy")
+	}
+	b.manager.SetStrategy(s.Name, s.Version)
+	log.Info().
+		Str("strategy", s.Name).
+		Int("version", s.Version).
+		Str("path", s.Path).
+		Msg("strategy reloaded")
+}
+
+// activeParameters returns base with the parameters table's values and the
+// active strategy's overrides, if one is loaded, applied.
+func (b *Bot) activeParameters(base config.Parameters) config.Parameters {
+	base = b.withTableValues(base)
+	if b.strategies == nil || b.strategyName == "" {
+		return base
+	}
+	s, ok := b.strategies.Get(b.strategyName)
+	if !ok {
+		return base
+	}
+	return s.Apply(base)
+}
+
+// applyParameters applies params' filters, thresholds, sizing and exits to
+// the scanner, sizer and monitor. An invalid stop loss type is returned
+// after the rest is applied.
+func (b *Bot) applyParameters(params config.Parameters) error {
+	b.appliedParams = params
+	b.scanner.SetParameters(params)
+	b.manager.SetSizing(params.KellyFraction, params.MaxLiquidityPct, params.KellyCorrelation)
+	if b.monitor == nil {
+		return nil
+	}
+	b.monitor.SetStopLossPercent(params.StopLossPercent)
+	b.monitor.SetTakeProfitPercent(params.TakeProfitPercent)
+	b.monitor.SetTimeDecayLead(time.Duration(params.TimeDecayExitHours * float64(time.Hour)))
+	if params.StopLossType != "" {
+		if err := b.monitor.SetStopLossType(params.StopLossType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,11 +12,70 @@ import (
 type Bankroll struct {
 	Polymarket float64 `yaml:"polymarket"`
 	Kalshi     float64 `yaml:"kalshi"`
+	// Manifold is in play money (mana). Manifold is only traded when it is
+	// above 0, unless Platforms lists it.
+	Manifold float64 `yaml:"manifold"`
+	// Other holds the bankrolls of any other platform, by name.
+	Other map[string]float64 `yaml:",inline"`
 }
 
+// For returns the bankroll of a platform.
+func (b Bankroll) For(platform string) float64 {
+	switch platform {
+	case "polymarket":
+		return b.Polymarket
+	case "kalshi":
+		return b.Kalshi
+	case "manifold":
+		return b.Manifold
+	default:
+		return b.Other[platform]
+	}
+}
+
+// Allocation reserves a share of each platform's bankroll for each trade
+// strategy, by name: "favorite" (buying the side a market favors), "fade"
+// and "hedge". Shares are fractions summing to at most 1; strategies left
+// out don't trade. Empty lets every strategy use the whole bankroll.
+type Allocation map[string]float64
+
 // Scan contains the scanning configuration.
 type Scan struct {
 	IntervalSeconds int `yaml:"interval_seconds"`
+	// BestStrikePerEvent enters only the best-valued strike of each event
+	// instead of every eligible one.
+	BestStrikePerEvent bool `yaml:"best_strike_per_event"`
+	// ParserRules is a YAML file overriding the built-in market title
+	// parser rules. Empty uses the built-in rules.
+	ParserRules string `yaml:"parser_rules"`
+	// Workers is how many platforms are scanned at once (0 or 1 scans them
+	// one after another).
+	Workers int `yaml:"workers"`
+	// MarketWorkers is how many of a platform's eligible markets are
+	// analyzed at once (0 or 1 analyzes them one after another). Entries
+	// are made one at a time regardless.
+	MarketWorkers int `yaml:"market_workers"`
+}
+
+// Blackout contains the event blackout calendar: markets that stay open into
+// a window around a scheduled event (an FOMC decision, a CPI release, a
+// token unlock) are skipped by the scanner.
+type Blackout struct {
+	// File is a YAML file of blackout windows (empty for none; see
+	// config/blackouts.yaml for the format).
+	File string `yaml:"file"`
+	// EconomicCalendar adds windows around high-impact economic releases.
+	EconomicCalendar EconomicCalendar `yaml:"economic_calendar"`
+}
+
+// EconomicCalendar blacks out every asset around the high-impact releases
+// of the Financial Modeling Prep economic calendar. The API key is read from
+// FMP_API_KEY.
+type EconomicCalendar struct {
+	Enabled     bool     `yaml:"enabled"`
+	Countries   []string `yaml:"countries"`    // Countries whose releases count (empty defaults to US)
+	HoursBefore float64  `yaml:"hours_before"` // Hours blacked out before a release (0 defaults to 12)
+	HoursAfter  float64  `yaml:"hours_after"`  // Hours blacked out after a release (0 defaults to 1)
 }
 
 // Parameters contains the trading parameters.
@@ -23,7 +83,80 @@ type Parameters struct {
 	ProbabilityThreshold   float64 `yaml:"probability_threshold"`
 	VolatilitySafetyMargin float64 `yaml:"volatility_safety_margin"`
 	StopLossPercent        float64 `yaml:"stop_loss_percent"`
+	StopLossType           string  `yaml:"stop_loss_type"`        // "fixed" (default) or "trailing"
+	TakeProfitPercent      float64 `yaml:"take_profit_percent"`   // Zero disables take profit
+	TimeDecayExitHours     float64 `yaml:"time_decay_exit_hours"` // Hours before close; zero disables
 	KellyFraction          float64 `yaml:"kelly_fraction"`
+	MaxLiquidityPct        float64 `yaml:"max_liquidity_pct"` // Max share of market liquidity per position
+	KellyCorrelation       float64 `yaml:"kelly_correlation"` // Share of same-way exposure on an asset taken off Kelly stakes; zero disables
+	// KellyDrawdown scales kelly_fraction down while the bankroll is in
+	// drawdown; empty keeps it fixed.
+	KellyDrawdown []KellyDrawdownStep `yaml:"kelly_drawdown"`
+	// DecayRecheckPoints are the shares of a position's holding period left
+	// at which it is re-analyzed with fresh data; empty disables.
+	DecayRecheckPoints []float64 `yaml:"decay_recheck_points"`
+
+	// Scan filter thresholds. Zero leaves a filter at its default: 48h max
+	// time to close, $100 min liquidity, and no volume, min time or spread
+	// limit.
+	MinLiquidity    float64 `yaml:"min_liquidity"`      // Dollars
+	MinVolume       float64 `yaml:"min_volume"`         // Dollars
+	MinHoursToClose float64 `yaml:"min_hours_to_close"` // Hours
+	MaxHoursToClose float64 `yaml:"max_hours_to_close"` // Hours
+	MaxSpread       float64 `yaml:"max_spread"`         // Ask minus bid, in price units
+
+	// MinMinutesToClose is a safety buffer: markets closing within it are
+	// never entered, whatever the other thresholds. Zero disables it.
+	MinMinutesToClose float64 `yaml:"min_minutes_to_close"`
+}
+
+// Values returns the numeric parameters by their YAML name, as they are
+// named in the parameters table.
+func (p Parameters) Values() map[string]float64 {
+	values := make(map[string]float64)
+	for name, field := range p.fields() {
+		values[name] = *field
+	}
+	return values
+}
+
+// WithValues returns a copy of p with the numeric parameters named in values
+// set to them. Unknown names are ignored.
+func (p Parameters) WithValues(values map[string]float64) Parameters {
+	for name, field := range p.fields() {
+		if v, ok := values[name]; ok {
+			*field = v
+		}
+	}
+	return p
+}
+
+// fields returns pointers to p's numeric parameters by YAML name.
+func (p *Parameters) fields() map[string]*float64 {
+	return map[string]*float64{
+		"probability_threshold":    &p.ProbabilityThreshold,
+		"volatility_safety_margin": &p.VolatilitySafetyMargin,
+		"stop_loss_percent":        &p.StopLossPercent,
+		"take_profit_percent":      &p.TakeProfitPercent,
+		"time_decay_exit_hours":    &p.TimeDecayExitHours,
+		"kelly_fraction":           &p.KellyFraction,
+		"max_liquidity_pct":        &p.MaxLiquidityPct,
+		"kelly_correlation":        &p.KellyCorrelation,
+		"min_liquidity":            &p.MinLiquidity,
+		"min_volume":               &p.MinVolume,
+		"min_hours_to_close":       &p.MinHoursToClose,
+		"max_hours_to_close":       &p.MaxHoursToClose,
+		"max_spread":               &p.MaxSpread,
+		"min_minutes_to_close":     &p.MinMinutesToClose,
+	}
+}
+
+// KellyDrawdownStep scales kelly_fraction by Scale once realized equity is
+// Drawdown (a fraction, 0.10 = 10%) or more below its peak. The deepest step
+// reached applies.
+type KellyDrawdownStep struct {
+	Drawdown float64 `yaml:"drawdown"`
+	Scale    float64 `yaml:"scale"`
 }
 
 // Database contains the database configuration.
@@ -31,12 +164,404 @@ type Database struct {
 	Path string `yaml:"path"`
 }
 
+// DryRun contains the execution simulation used in dry-run mode.
+type DryRun struct {
+	LatencyMs    int                `yaml:"latency_ms"`
+	FeeRates     map[string]float64 `yaml:"fee_rates"`     // Fraction of notional per platform
+	PaperTrading []string           `yaml:"paper_trading"` // Platforms filled against their live order books
+}
+
+// Flatten contains the end-of-day flattening policy.
+type Flatten struct {
+	// LeadMinutes maps an asset class (crypto, stock, fx, commodity) to how
+	// many minutes before market close its positions are closed. Classes
+	// that are absent or set to 0 are held through resolution.
+	LeadMinutes map[string]int `yaml:"lead_minutes"`
+}
+
+// Settlement contains the settlement configuration for resolved markets.
+type Settlement struct {
+	// RedemptionAlertHours is how long a winning payout may stay unredeemed
+	// before an alert is raised.
+	RedemptionAlertHours int `yaml:"redemption_alert_hours"`
+}
+
+// Balances contains the reconciliation of each platform's bankroll with the
+// balance the platform reports, which deposits, withdrawals, fees and
+// trades made by hand change without the bot knowing. It runs live only.
+type Balances struct {
+	// IntervalMinutes is how often balances are checked (0 disables).
+	IntervalMinutes int `yaml:"interval_minutes"`
+	// Tolerances holds each platform's tolerance, by name. A platform left
+	// out has none: any discrepancy is alerted.
+	Tolerances map[string]BalanceTolerance `yaml:"tolerances"`
+}
+
+// Interval returns how often balances are checked, 0 if disabled.
+func (b Balances) Interval() time.Duration {
+	return time.Duration(b.IntervalMinutes) * time.Minute
+}
+
+// Learning configures the learning cycle, which moves the parameters table
+// toward the parameter values recent closed trades did best with.
+type Learning struct {
+	// IntervalMinutes is how often the learning cycle runs (0 disables).
+	IntervalMinutes int `yaml:"interval_minutes"`
+	// WindowTrades is how many of the most recent closed trades are
+	// analyzed (0 defaults to 100).
+	WindowTrades int `yaml:"window_trades"`
+}
+
+// Interval returns how often the learning cycle runs, 0 if disabled.
+func (l Learning) Interval() time.Duration {
+	return time.Duration(l.IntervalMinutes) * time.Minute
+}
+
+// Experiment configures a parameter experiment: a share of markets is
+// scanned with challenger values for some parameters, and the positions
+// entered are flagged with their arm so the learning module can compare them
+// with the champion (the configured parameters) before a change is promoted.
+type Experiment struct {
+	// Name identifies the experiment on positions (empty disables).
+	Name string `yaml:"name"`
+	// Share is the fraction of markets assigned to the challenger, in (0, 1).
+	Share float64 `yaml:"share"`
+	// Challenger maps parameter names (e.g. probability_threshold) to the
+	// challenger's values.
+	Challenger map[string]float64 `yaml:"challenger"`
+}
+
+// BalanceTolerance is how far a platform's balance may be from its bankroll
+// before it is alerted.
+type BalanceTolerance struct {
+	// Amount is the discrepancy in dollars, either way, within which the
+	// bankroll is left or adjusted. Beyond it an alert is raised and the
+	// bankroll is left for an operator to look into.
+	Amount float64 `yaml:"amount"`
+	// AutoAdjust sets the bankroll to the platform's balance when the
+	// discrepancy is within Amount.
+	AutoAdjust bool `yaml:"auto_adjust"`
+}
+
+// HTTP contains the middleware every platform client's requests go
+// through: a token-bucket rate limit per platform, retries with exponential
+// backoff and jitter of requests answered 429 or 5xx, and a circuit breaker
+// that stops requests to a platform that keeps failing.
+type HTTP struct {
+	// RequestsPerMinute holds each platform's rate limit, by name. A
+	// platform left out, or set to 0, isn't limited.
+	RequestsPerMinute map[string]int `yaml:"requests_per_minute"`
+	// MaxRetries is how many times a failed request is retried (0 disables).
+	MaxRetries int `yaml:"max_retries"`
+	// BaseBackoffMs is the wait before the first retry, doubled for each
+	// one after up to MaxBackoffMs. A Retry-After header takes precedence.
+	BaseBackoffMs int `yaml:"base_backoff_ms"`
+	MaxBackoffMs  int `yaml:"max_backoff_ms"`
+	// BreakerThreshold is how many requests to a platform must fail in a
+	// row, retries exhausted, to open its circuit (0 disables).
+	BreakerThreshold int `yaml:"breaker_threshold"`
+	// BreakerCooldownSeconds is how long an open circuit fails requests
+	// before one is let through to test the platform.
+	BreakerCooldownSeconds int `yaml:"breaker_cooldown_seconds"`
+}
+
+// APILog contains the audit log of platform API calls, kept in the api_log
+// table and aggregated into the dashboard's API health.
+type APILog struct {
+	Enabled bool `yaml:"enabled"`
+	// PayloadBytes is how much of each request and response body is kept
+	// (0 keeps none).
+	PayloadBytes int `yaml:"payload_bytes"`
+	// RetentionDays is how long calls are kept (0 keeps them all).
+	RetentionDays int `yaml:"retention_days"`
+}
+
+// Retention returns how long calls are kept, 0 if forever.
+func (a APILog) Retention() time.Duration {
+	return time.Duration(a.RetentionDays) * 24 * time.Hour
+}
+
+// BaseBackoff returns the wait before the first retry.
+func (h HTTP) BaseBackoff() time.Duration {
+	return time.Duration(h.BaseBackoffMs) * time.Millisecond
+}
+
+// MaxBackoff returns the longest wait between retries.
+func (h HTTP) MaxBackoff() time.Duration {
+	return time.Duration(h.MaxBackoffMs) * time.Millisecond
+}
+
+// BreakerCooldown returns how long an open circuit fails requests.
+func (h HTTP) BreakerCooldown() time.Duration {
+	return time.Duration(h.BreakerCooldownSeconds) * time.Second
+}
+
+// VolatilityAsset contains the volatility tuning for one asset. Zero values
+// keep the defaults.
+type VolatilityAsset struct {
+	AnnualizationDays float64 `yaml:"annualization_days"` // Trading days per year
+	MinVolatility     float64 `yaml:"min_volatility"`     // Floor on calculated volatility
+	MaxVolatility     float64 `yaml:"max_volatility"`     // Ceiling on calculated volatility
+	Override          float64 `yaml:"override"`           // Used instead of calculated volatility
+}
+
+// VolatilityCache contains how long fetched prices of an asset class are
+// reused. Zero fetches every time.
+type VolatilityCache struct {
+	PriceSeconds   float64 `yaml:"price_seconds"`   // Spot price
+	HistoryMinutes float64 `yaml:"history_minutes"` // Hourly price history
+}
+
+// Volatility contains the per-asset volatility configuration.
+type Volatility struct {
+	// Assets maps an asset symbol (BTC, SOL) to its tuning.
+	Assets map[string]VolatilityAsset `yaml:"assets"`
+	// Cache maps an asset class (crypto, stock, fx, commodity) to how long
+	// its prices are cached.
+	Cache map[string]VolatilityCache `yaml:"cache"`
+}
+
+// Precision contains the decimal precision of dollar amounts.
+type Precision struct {
+	// AmountDecimals is the number of decimal places position sizes are
+	// rounded down to (0 defaults to cents). Amounts are stored and PnL is
+	// calculated at full precision regardless.
+	AmountDecimals int `yaml:"amount_decimals"`
+	// DisplayDecimals is the number of decimal places dollar amounts are
+	// shown with (0 defaults to cents).
+	DisplayDecimals int `yaml:"display_decimals"`
+}
+
+// Arbitrage contains the cross-platform arbitrage configuration.
+type Arbitrage struct {
+	Enabled bool `yaml:"enabled"`
+	// MinSpread is the divergence in YES price at which equivalent markets
+	// are flagged (0 defaults to 0.05).
+	MinSpread float64 `yaml:"min_spread"`
+	// HedgeSize is the dollars spent across both legs when hedging a
+	// flagged pair. 0 only reports opportunities.
+	HedgeSize float64 `yaml:"hedge_size"`
+}
+
+// Risk contains the portfolio-wide limits on new entries. Exposure limits
+// are fractions of capital (cash plus the cost of open positions). Zero
+// disables a limit.
+type Risk struct {
+	MaxAssetExposure       float64 `yaml:"max_asset_exposure"`       // Max share of capital on one asset
+	MaxOpenPositions       int     `yaml:"max_open_positions"`       // Max positions open at once
+	MaxDirectionalExposure float64 `yaml:"max_directional_exposure"` // Max share of capital betting one way on an asset class
+	MaxDailyVaR            float64 `yaml:"max_daily_var"`            // Max share of capital the portfolio's daily VaR may reach
+	VaRConfidence          float64 `yaml:"var_confidence"`           // Confidence daily VaR is estimated at (0 defaults to 0.95)
+}
+
+// Entry contains how live entries are executed.
+type Entry struct {
+	// Strategy is "market" (buy with a market order), "limit" (post a
+	// limit order a tick inside the spread) or "passive" (post a limit order
+	// buffer_ticks below the quoted price). Empty defaults to market.
+	Strategy       string `yaml:"strategy"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"`  // How long a limit entry rests (0 defaults to 30)
+	CrossOnTimeout bool   `yaml:"cross_on_timeout"` // Buy the unfilled rest at the ask instead of abandoning it (not passive)
+	BufferTicks    int    `yaml:"buffer_ticks"`     // Cents below the quoted price a passive entry bids (0 defaults to 1)
+}
+
+// Fade contains the fade strategy: buying the opposite of the side a market
+// favors when the volatility model says that side is overpriced.
+type Fade struct {
+	Enabled bool `yaml:"enabled"`
+	// MinEdge is how far below the market's price the model probability of
+	// the favored side must be to fade it (0 defaults to 0.20).
+	MinEdge float64 `yaml:"min_edge"`
+}
+
+// Edge returns the minimum edge to fade at, 0 if fading is disabled.
+func (f Fade) Edge() float64 {
+	if !f.Enabled {
+		return 0
+	}
+	if f.MinEdge <= 0 {
+		return 0.20
+	}
+	return f.MinEdge
+}
+
+// Alerts contains the audible alerts raised when a stop loss fires, a live
+// exit fails or a health threshold is breached, for operators watching a
+// live session.
+type Alerts struct {
+	Bell bool `yaml:"bell"` // Ring the terminal bell
+	// Command is run through the shell on each alert with ALERT_EVENT and
+	// ALERT_MESSAGE set (empty disables it).
+	Command    string          `yaml:"command"`
+	Thresholds AlertThresholds `yaml:"thresholds"`
+}
+
+// AlertThresholds are the bot's health limits checked every CheckSeconds,
+// raising an alert when breached. Zero disables a threshold.
+type AlertThresholds struct {
+	ScanStaleMinutes   float64 `yaml:"scan_stale_minutes"`   // Minutes without a successful scan cycle
+	MaxErrorRate       float64 `yaml:"max_error_rate"`       // Share of cycles failed over the error window
+	ErrorWindowMinutes float64 `yaml:"error_window_minutes"` // Window the error rate is measured over (0 defaults to 60)
+	MaxDrawdown        float64 `yaml:"max_drawdown"`         // Fraction of peak equity
+	CheckSeconds       int     `yaml:"check_seconds"`        // How often thresholds are checked (0 defaults to 60)
+}
+
+// ScanStale returns ScanStaleMinutes as a duration.
+func (t AlertThresholds) ScanStale() time.Duration {
+	return time.Duration(t.ScanStaleMinutes * float64(time.Minute))
+}
+
+// ErrorWindow returns ErrorWindowMinutes as a duration.
+func (t AlertThresholds) ErrorWindow() time.Duration {
+	return time.Duration(t.ErrorWindowMinutes * float64(time.Minute))
+}
+
+// CheckInterval returns how often thresholds are checked.
+func (t AlertThresholds) CheckInterval() time.Duration {
+	if t.CheckSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(t.CheckSeconds) * time.Second
+}
+
+// WebUI contains the web dashboard, served over HTTP alongside the bot.
+type WebUI struct {
+	// Listen is the address to serve on, e.g. ":8080" (empty disables it).
+	Listen string `yaml:"listen"`
+	// Username is the basic auth user. The password is read from
+	// WEBUI_PASSWORD; without it the dashboard is served without auth.
+	Username       string `yaml:"username"`
+	RefreshSeconds int    `yaml:"refresh_seconds"` // How often the page reloads (0 defaults to 10)
+}
+
+// Shutdown contains how the bot winds down when it is stopped.
+type Shutdown struct {
+	// DrainSeconds is how long the entry or exit in progress gets to
+	// finish placing and recording its orders (0 defaults to 30; negative
+	// cuts it off right away).
+	DrainSeconds int `yaml:"drain_seconds"`
+	// CancelRestingOrders cancels the orders still resting on the open
+	// positions' markets once the bot has stopped, live only.
+	CancelRestingOrders bool `yaml:"cancel_resting_orders"`
+}
+
+// DrainTimeout returns how long the entry or exit in progress gets to
+// finish.
+func (s Shutdown) DrainTimeout() time.Duration {
+	switch {
+	case s.DrainSeconds < 0:
+		return 0
+	case s.DrainSeconds == 0:
+		return 30 * time.Second
+	}
+	return time.Duration(s.DrainSeconds) * time.Second
+}
+
+// Health contains the health check orchestration restarts a stuck bot on.
+type Health struct {
+	// Listen is the address /healthz is served on, e.g. ":8081" (empty
+	// disables it). It is served without auth and shows no trading data.
+	Listen              string  `yaml:"listen"`
+	ScanStaleMinutes    float64 `yaml:"scan_stale_minutes"`    // Minutes without a successful scan cycle (0 defaults to 3 scan intervals)
+	MonitorStaleMinutes float64 `yaml:"monitor_stale_minutes"` // Minutes without a successful monitor cycle (0 defaults to 5)
+	ProbeSeconds        int     `yaml:"probe_seconds"`         // How often platform APIs are probed (0 defaults to 60)
+}
+
+// ScanStale returns ScanStaleMinutes as a duration, or three of
+// scanInterval if unset.
+func (h Health) ScanStale(scanInterval time.Duration) time.Duration {
+	if h.ScanStaleMinutes <= 0 {
+		return 3 * scanInterval
+	}
+	return time.Duration(h.ScanStaleMinutes * float64(time.Minute))
+}
+
+// MonitorStale returns MonitorStaleMinutes as a duration.
+func (h Health) MonitorStale() time.Duration {
+	if h.MonitorStaleMinutes <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(h.MonitorStaleMinutes * float64(time.Minute))
+}
+
+// ProbeInterval returns how often platform APIs are probed.
+func (h Health) ProbeInterval() time.Duration {
+	if h.ProbeSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(h.ProbeSeconds) * time.Second
+}
+
+// Dashboard contains the terminal dashboard's connection to a running bot.
+type Dashboard struct {
+	// Socket is the unix socket the bot streams its activity on, for
+	// dashboards attached with botctl dashboard (empty disables it).
+	Socket string `yaml:"socket"`
+}
+
+// Locale contains the language settings.
+type Locale struct {
+	// Language is the language of the dashboard and reports: "en" or
+	// "pt-BR" (empty defaults to English). Log messages stay in English.
+	Language string `yaml:"language"`
+}
+
+// Strategies contains the strategy files: named, versioned overrides of the
+// parameters, validated against internal/strategy/schema.json (see
+// strategies/ for examples). Files are reloaded when they change.
+type Strategies struct {
+	// Dir is the directory of strategy files (empty defaults to
+	// "strategies").
+	Dir string `yaml:"dir"`
+	// Active is the name of the strategy trading with, whose overrides
+	// apply on top of parameters (empty trades with parameters alone).
+	Active string `yaml:"active"`
+}
+
 // Config is the main configuration struct.
 type Config struct {
+	// Platforms lists the platforms to trade, by registered name. Empty
+	// trades Polymarket and Kalshi, and Manifold when it has a bankroll.
+	Platforms  []string   `yaml:"platforms"`
 	Bankroll   Bankroll   `yaml:"bankroll"`
+	Allocation Allocation `yaml:"allocation"`
 	Scan       Scan       `yaml:"scan"`
+	Blackout   Blackout   `yaml:"blackout"`
 	Parameters Parameters `yaml:"parameters"`
+	Strategies Strategies `yaml:"strategies"`
 	Database   Database   `yaml:"database"`
+	DryRun     DryRun     `yaml:"dry_run"`
+	Flatten    Flatten    `yaml:"flatten"`
+	Settlement Settlement `yaml:"settlement"`
+	Balances   Balances   `yaml:"balances"`
+	Learning   Learning   `yaml:"learning"`
+	Experiment Experiment `yaml:"experiment"`
+	HTTP       HTTP       `yaml:"http"`
+	APILog     APILog     `yaml:"api_log"`
+	Volatility Volatility `yaml:"volatility"`
+	Precision  Precision  `yaml:"precision"`
+	Arbitrage  Arbitrage  `yaml:"arbitrage"`
+	Risk       Risk       `yaml:"risk"`
+	Entry      Entry      `yaml:"entry"`
+	Fade       Fade       `yaml:"fade"`
+	Alerts     Alerts     `yaml:"alerts"`
+	WebUI      WebUI      `yaml:"web_ui"`
+	Health     Health     `yaml:"health"`
+	Shutdown   Shutdown   `yaml:"shutdown"`
+	Dashboard  Dashboard  `yaml:"dashboard"`
+	Locale     Locale     `yaml:"locale"`
+}
+
+// EnabledPlatforms returns the names of the platforms to trade.
+func (c *Config) EnabledPlatforms() []string {
+	if len(c.Platforms) > 0 {
+		return c.Platforms
+	}
+	platforms := []string{"polymarket", "kalshi"}
+	if c.Bankroll.Manifold > 0 {
+		platforms = append(platforms, "manifold")
+	}
+	return platforms
 }
 
 // LoadConfig loads configuration from a YAML file.
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watcher reloads a config file when it changes, or when asked to (on
+// SIGHUP, say) even if it didn't.
+type Watcher struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	forced  bool
+}
+
+// NewWatcher creates a watcher of the config file at path, already loaded
+// as it is now.
+func NewWatcher(path string) (*Watcher, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat config file: %w", err)
+	}
+	return &Watcher{path: path, modTime: info.ModTime()}, nil
+}
+
+// Force makes the next Reload load the file whether or not it changed.
+func (w *Watcher) Force() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.forced = true
+}
+
+// Reload loads the file if it was modified since the last load or a reload
+// was forced. Returns nil if it wasn't loaded. A file that no longer loads
+// returns its error and isn't retried until it is modified again.
+func (w *Watcher) Reload() (*Config, error) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return nil, fmt.Errorf("stat config file: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.forced && info.ModTime().Equal(w.modTime) {
+		return nil, nil
+	}
+	w.forced = false
+	w.modTime = info.ModTime()
+	return LoadConfig(w.path)
+}
+
+// Change is a numeric setting that differs between two configs, named by
+// its YAML path (e.g. "risk.max_open_positions"). Booleans are 0 or 1.
+type Change struct {
+	Name     string
+	OldValue float64
+	NewValue float64
+}
+
+// Reloadable returns a copy of c with only the settings a running bot
+// applies when the config is reloaded: the parameters, the risk limits, the
+// allocation, the fade strategy and whether only the best strike of each
+// event is entered.
+func (c *Config) Reloadable() Config {
+	return Config{
+		Parameters: c.Parameters,
+		Risk:       c.Risk,
+		Allocation: c.Allocation,
+		Fade:       c.Fade,
+		Scan:       Scan{BestStrikePerEvent: c.Scan.BestStrikePerEvent},
+	}
+}
+
+// RequiresRestart reports whether next changes settings a running bot
+// doesn't apply on reload.
+func (c *Config) RequiresRestart(next *Config) bool {
+	return !reflect.DeepEqual(c.withoutReloadable(), next.withoutReloadable())
+}
+
+// withoutReloadable returns a copy of c with the reloadable settings
+// cleared.
+func (c *Config) withoutReloadable() Config {
+	rest := *c
+	rest.Parameters = Parameters{}
+	rest.Risk = Risk{}
+	rest.Allocation = nil
+	rest.Fade = Fade{}
+	rest.Scan.BestStrikePerEvent = false
+	return rest
+}
+
+// ReloadableChanges returns the numeric reloadable settings that differ
+// between c and next, sorted by name. A setting present in only one of them
+// (an allocation share, a list entry) is 0 in the other.
+func (c *Config) ReloadableChanges(next *Config) []Change {
+	before := make(map[string]float64)
+	after := make(map[string]float64)
+	old, updated := c.Reloadable(), next.Reloadable()
+	flatten("", reflect.ValueOf(old), before)
+	flatten("", reflect.ValueOf(updated), after)
+
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	var changes []Change
+	for name := range names {
+		if before[name] != after[name] {
+			changes = append(changes, Change{Name: name, OldValue: before[name], NewValue: after[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// flatten records the numeric and boolean values under v by their YAML
+// path below prefix. Strings are skipped.
+func flatten(prefix string, v reflect.Value, values map[string]float64) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" || !field.IsExported() {
+				continue
+			}
+			flatten(join(prefix, name), v.Field(i), values)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			flatten(join(prefix, fmt.Sprint(key.Interface())), v.MapIndex(key), values)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), v.Index(i), values)
+		}
+	case reflect.Float32, reflect.Float64:
+		values[prefix] = v.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		values[prefix] = float64(v.Int())
+	case reflect.Bool:
+		if v.Bool() {
+			values[prefix] = 1
+		} else {
+			values[prefix] = 0
+		}
+	}
+}
+
+// join appends name to a YAML path.
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
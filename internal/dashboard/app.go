@@ -3,37 +3,70 @@ package dashboard
 import (
 	"fmt"
 
+	"prediction-bot/internal/events"
+	"prediction-bot/internal/i18n"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 // App represents the dashboard application
 type App struct {
-	program *tea.Program
+	model Model
 }
 
 // NewApp creates a new dashboard application
 func NewApp() *App {
-	model := NewModel()
-	program := tea.NewProgram(model, tea.WithAltScreen())
-
 	return &App{
-		program: program,
+		model: NewModel(),
 	}
 }
 
 // NewAppWithProvider creates a new dashboard application with a data provider.
 func NewAppWithProvider(provider DataProvider, dryRun bool) *App {
-	model := NewModelWithProvider(provider, dryRun)
-	program := tea.NewProgram(model, tea.WithAltScreen())
-
 	return &App{
-		program: program,
+		model: NewModelWithProvider(provider, dryRun),
+	}
+}
+
+// SetCurrencyDecimals sets the number of decimal places dollar amounts are
+// shown with. Must be called before Run.
+func (a *App) SetCurrencyDecimals(decimals int) {
+	a.model.SetCurrencyDecimals(decimals)
+}
+
+// SetLanguage sets the language the dashboard is shown in. Must be called
+// before Run.
+func (a *App) SetLanguage(lang i18n.Language) {
+	a.model.SetLanguage(lang)
+}
+
+// SetPlain disables colors and draws the dashboard with ASCII characters
+// only. Must be called before Run.
+func (a *App) SetPlain(plain bool) {
+	if plain {
+		lipgloss.SetColorProfile(termenv.Ascii)
 	}
+	a.model.SetPlain(plain)
+}
+
+// SetEvents sets the channel the bot's events are received from, to show
+// its activity as it happens. Must be called before Run.
+func (a *App) SetEvents(ch <-chan events.Event) {
+	a.model.SetEvents(ch)
+}
+
+// SetPositionActions sets how open positions are closed and repriced from
+// the dashboard. Must be called before Run.
+func (a *App) SetPositionActions(actions PositionActions) {
+	a.model.SetPositionActions(actions)
 }
 
 // Run starts the dashboard application
 func (a *App) Run() error {
-	if _, err := a.program.Run(); err != nil {
+	program := tea.NewProgram(a.model, tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
 		return fmt.Errorf("running dashboard: %w", err)
 	}
 	return nil
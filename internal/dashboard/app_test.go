@@ -1,11 +1,19 @@
 package dashboard
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+	"unicode"
 
 	"prediction-bot/internal/dashboard/views"
+	"prediction-bot/internal/events"
+	"prediction-bot/internal/i18n"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestNewApp(t *testing.T) {
@@ -157,6 +165,308 @@ func TestModelViewShowsPositions(t *testing.T) {
 	}
 }
 
+// MockArbitrageProvider also reports arbitrage opportunities.
+type MockArbitrageProvider struct {
+	MockDataProvider
+	arbitrage []views.ArbitrageData
+}
+
+func (m *MockArbitrageProvider) GetArbitrage() ([]views.ArbitrageData, error) {
+	return m.arbitrage, nil
+}
+
+func TestModelViewShowsArbitrageForArbitrageProvider(t *testing.T) {
+	if view := NewModelWithProvider(&MockDataProvider{}, true).View(); strings.Contains(view, "Arbitrage") {
+		t.Error("expected no arbitrage section for a provider without arbitrage data")
+	}
+
+	provider := &MockArbitrageProvider{
+		arbitrage: []views.ArbitrageData{{
+			Asset:       "ETH",
+			Strike:      4000,
+			Direction:   "above",
+			YesPlatform: "kalshi",
+			YesPrice:    0.70,
+			NoPlatform:  "polymarket",
+			NoPrice:     0.22,
+			Spread:      0.08,
+		}},
+	}
+	model := NewModelWithProvider(provider, true)
+	updated, _ := model.Update(model.fetchDataCmd()())
+
+	view := updated.(Model).View()
+	if !strings.Contains(view, "Arbitrage") || !strings.Contains(view, "ETH") {
+		t.Errorf("expected view to contain arbitrage section, got: %s", view)
+	}
+}
+
+// MockEquityProvider also reports the bankroll over time.
+type MockEquityProvider struct {
+	MockDataProvider
+	equity views.EquityData
+}
+
+func (m *MockEquityProvider) GetEquity() (views.EquityData, error) {
+	return m.equity, nil
+}
+
+func TestModelViewShowsEquityForEquityProvider(t *testing.T) {
+	if view := NewModelWithProvider(&MockDataProvider{}, true).View(); strings.Contains(view, "Equity") {
+		t.Error("expected no equity section for a provider without bankroll history")
+	}
+
+	provider := &MockEquityProvider{equity: views.EquityData{Curve: []float64{100, 120, 90}, DailyPnL: -30}}
+	model := NewModelWithProvider(provider, true)
+	updated, _ := model.Update(model.fetchDataCmd()())
+
+	view := updated.(Model).View()
+	if !strings.Contains(view, "Equity") || !strings.Contains(view, "-25.0%") {
+		t.Errorf("expected view to contain the equity section, got: %s", view)
+	}
+}
+
+// MockPnLProvider also reports realized PnL by period.
+type MockPnLProvider struct {
+	MockDataProvider
+	pnl []views.PnLData
+}
+
+func (m *MockPnLProvider) GetPnLReport() ([]views.PnLData, error) {
+	return m.pnl, nil
+}
+
+func TestModelTogglesPnLReport(t *testing.T) {
+	toggle := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}}
+
+	// Providers without a report can't toggle it on
+	model := NewModelWithProvider(&MockDataProvider{}, true)
+	updated, _ := model.Update(toggle)
+	if strings.Contains(updated.(Model).View(), "Realized PnL") {
+		t.Error("expected no PnL section for a provider without a report")
+	}
+
+	provider := &MockPnLProvider{pnl: []views.PnLData{{Period: "2026-03", Platform: "kalshi", Trades: 2, Wins: 2, RealizedPnL: 3.5}}}
+	model = NewModelWithProvider(provider, true)
+	if strings.Contains(model.View(), "Realized PnL") {
+		t.Error("expected the PnL section hidden until toggled")
+	}
+
+	updated, cmd := model.Update(toggle)
+	if cmd == nil {
+		t.Fatal("expected toggling the report on to fetch it")
+	}
+	updated, _ = updated.Update(cmd())
+	view := updated.(Model).View()
+	if !strings.Contains(view, "Realized PnL") || !strings.Contains(view, "2026-03") {
+		t.Errorf("expected view to contain the PnL section, got: %s", view)
+	}
+
+	updated, _ = updated.Update(toggle)
+	if strings.Contains(updated.(Model).View(), "Realized PnL") {
+		t.Error("expected the PnL section hidden after toggling it off")
+	}
+}
+
+// MockScanDiffProvider also reports the changes between scans.
+type MockScanDiffProvider struct {
+	MockDataProvider
+	diffs []views.ScanDiffData
+}
+
+func (m *MockScanDiffProvider) GetScanDiff() ([]views.ScanDiffData, error) {
+	return m.diffs, nil
+}
+
+func TestModelTogglesScanDiff(t *testing.T) {
+	toggle := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}
+
+	// Providers without scan snapshots can't toggle it on
+	model := NewModelWithProvider(&MockDataProvider{}, true)
+	updated, _ := model.Update(toggle)
+	if strings.Contains(updated.(Model).View(), "Scan Diff") {
+		t.Error("expected no scan diff section for a provider without snapshots")
+	}
+
+	provider := &MockScanDiffProvider{diffs: []views.ScanDiffData{{
+		Platform: "kalshi",
+		Changes:  []views.ScanChangeData{{Kind: views.ScanChangeEligible, MarketID: "KXBTC-1", Reason: "entered"}},
+	}}}
+	model = NewModelWithProvider(provider, true)
+
+	updated, cmd := model.Update(toggle)
+	if cmd == nil {
+		t.Fatal("expected toggling the scan diff on to fetch it")
+	}
+	updated, _ = updated.Update(cmd())
+	view := updated.(Model).View()
+	if !strings.Contains(view, "Scan Diff") || !strings.Contains(view, "KXBTC-1") {
+		t.Errorf("expected view to contain the scan diff section, got: %s", view)
+	}
+
+	updated, _ = updated.Update(toggle)
+	if strings.Contains(updated.(Model).View(), "Scan Diff") {
+		t.Error("expected the scan diff section hidden after toggling it off")
+	}
+}
+
+// MockFunnelProvider also reports each platform's latest scan funnel.
+type MockFunnelProvider struct {
+	MockDataProvider
+	funnels []views.FunnelData
+}
+
+func (m *MockFunnelProvider) GetScanFunnel() ([]views.FunnelData, error) {
+	return m.funnels, nil
+}
+
+func TestModelTogglesScanFunnel(t *testing.T) {
+	toggle := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}
+
+	// Providers without scan snapshots can't toggle it on
+	model := NewModelWithProvider(&MockDataProvider{}, true)
+	updated, _ := model.Update(toggle)
+	if strings.Contains(updated.(Model).View(), "Scan Funnel") {
+		t.Error("expected no scan funnel section for a provider without snapshots")
+	}
+
+	provider := &MockFunnelProvider{funnels: []views.FunnelData{{
+		Platform: "kalshi",
+		Stages: []views.FunnelStageData{
+			{Name: "fetched", Count: 40, Skips: []views.FunnelSkipData{{Reason: "liquidity", Count: 40}}},
+			{Name: "filtered"},
+		},
+	}}}
+	model = NewModelWithProvider(provider, true)
+	model.width = 120
+
+	updated, cmd := model.Update(toggle)
+	if cmd == nil {
+		t.Fatal("expected toggling the scan funnel on to fetch it")
+	}
+	updated, _ = updated.Update(cmd())
+	view := updated.(Model).View()
+	if !strings.Contains(view, "Scan Funnel") || !strings.Contains(view, "liquidity 40") {
+		t.Errorf("expected view to contain the scan funnel section, got: %s", view)
+	}
+
+	updated, _ = updated.Update(toggle)
+	if strings.Contains(updated.(Model).View(), "Scan Funnel") {
+		t.Error("expected the scan funnel section hidden after toggling it off")
+	}
+}
+
+// MockTradesProvider also reports closed trades a page at a time.
+type MockTradesProvider struct {
+	MockDataProvider
+	trades []views.TradeData
+	pages  []int
+}
+
+func (m *MockTradesProvider) GetClosedTrades(page, pageSize int) (views.TradesPage, error) {
+	m.pages = append(m.pages, page)
+	result := views.TradesPage{Page: page, PageSize: pageSize, Total: len(m.trades)}
+	start := page * pageSize
+	end := min(start+pageSize, len(m.trades))
+	if start < end {
+		result.Trades = m.trades[start:end]
+	}
+	return result, nil
+}
+
+func TestModelPagesRecentTrades(t *testing.T) {
+	press := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+
+	// Providers without closed trades can't toggle them on
+	model := NewModelWithProvider(&MockDataProvider{}, true)
+	updated, _ := model.Update(press('t'))
+	if strings.Contains(updated.(Model).View(), "Recent Trades") {
+		t.Error("expected no trades section for a provider without closed trades")
+	}
+
+	provider := &MockTradesProvider{}
+	for i := 0; i < tradesPageSize+5; i++ {
+		provider.trades = append(provider.trades, views.TradeData{Platform: "kalshi", Asset: "BTC", Side: "YES", ExitReason: "resolved"})
+	}
+	provider.trades[tradesPageSize].ExitReason = "stop_loss"
+	model = NewModelWithProvider(provider, true)
+	model.width = 120
+
+	updated, cmd := model.Update(press('t'))
+	if cmd == nil {
+		t.Fatal("expected toggling the trades on to fetch them")
+	}
+	updated, _ = updated.Update(cmd())
+	view := updated.(Model).View()
+	if !strings.Contains(view, "Recent Trades") || !strings.Contains(view, "Page 1 of 2") || strings.Contains(view, "stop_loss") {
+		t.Errorf("expected the first page of trades, got: %s", view)
+	}
+
+	updated, cmd = updated.Update(press(']'))
+	if cmd == nil {
+		t.Fatal("expected paging to fetch the next page")
+	}
+	updated, _ = updated.Update(cmd())
+	view = updated.(Model).View()
+	if !strings.Contains(view, "Page 2 of 2") || !strings.Contains(view, "stop_loss") {
+		t.Errorf("expected the second page of trades, got: %s", view)
+	}
+
+	// There is no page past the last
+	if _, cmd := updated.Update(press(']')); cmd != nil {
+		t.Error("expected no fetch paging past the last page")
+	}
+
+	updated, cmd = updated.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if cmd == nil {
+		t.Fatal("expected paging back to fetch the previous page")
+	}
+	updated, _ = updated.Update(cmd())
+	if !strings.Contains(updated.(Model).View(), "Page 1 of 2") {
+		t.Error("expected the first page after paging back")
+	}
+	if want := []int{0, 1, 0}; !reflect.DeepEqual(provider.pages, want) {
+		t.Errorf("expected pages %v fetched, got %v", want, provider.pages)
+	}
+
+	updated, _ = updated.Update(press('t'))
+	if strings.Contains(updated.(Model).View(), "Recent Trades") {
+		t.Error("expected the trades section hidden after toggling it off")
+	}
+}
+
+func TestModelViewUsesConfiguredLanguage(t *testing.T) {
+	model := NewModel()
+	model.SetLanguage(i18n.Portuguese)
+
+	view := model.View()
+
+	for _, want := range []string{"Banca", "Nenhuma posição aberta", "Estatísticas", "[SIMULAÇÃO]", "sair"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q, got: %s", want, view)
+		}
+	}
+	if strings.Contains(view, "Statistics") {
+		t.Errorf("expected no English labels, got: %s", view)
+	}
+}
+
+func TestModelViewPlainUsesASCIIOnly(t *testing.T) {
+	model := NewModel()
+	model.SetPlain(true)
+
+	view := model.View()
+
+	for _, r := range view {
+		if r > unicode.MaxASCII {
+			t.Fatalf("expected ASCII-only view, found %q in: %s", r, view)
+		}
+	}
+	if !strings.Contains(view, "q quit * r refresh") {
+		t.Errorf("expected ASCII help separator, got: %s", view)
+	}
+}
+
 func TestModelViewShowsNoPositionsMessage(t *testing.T) {
 	model := NewModel()
 	// No positions set
@@ -218,3 +528,163 @@ func TestModelViewShowsLiveStatus(t *testing.T) {
 		t.Errorf("expected view to contain 'LIVE' status, got: %s", view)
 	}
 }
+
+func TestModelUpdate_StreamsEvents(t *testing.T) {
+	ch := make(chan events.Event, 4)
+	model := NewModel()
+	model.SetEvents(ch)
+
+	var tm tea.Model = model
+	tm, cmd := tm.Update(eventMsg(events.Event{Type: events.ScanStarted, Platform: "kalshi"}))
+	if cmd == nil {
+		t.Error("expected the model to keep waiting for events")
+	}
+	if view := tm.View(); !strings.Contains(view, "[SCANNING kalshi]") {
+		t.Errorf("expected scanning indicator, got: %s", view)
+	}
+
+	tm, _ = tm.Update(eventMsg(events.Event{Type: events.MarketEligible, Platform: "kalshi", Title: "Bitcoin above $100,000", Side: "YES", Price: 0.85}))
+	tm, _ = tm.Update(eventMsg(events.Event{Type: events.ScanCompleted, Platform: "kalshi", Count: 1}))
+
+	view := tm.View()
+	if strings.Contains(view, "SCANNING") {
+		t.Errorf("expected scanning indicator cleared after the scan, got: %s", view)
+	}
+	for _, want := range []string{"Activity", "ELIGIBLE", "YES@0.85", "scan complete, 1 eligible"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q, got: %s", want, view)
+		}
+	}
+
+	// The next event is read from the channel
+	ch <- events.Event{Type: events.PositionOpened, Platform: "kalshi"}
+	if msg := model.waitForEventCmd()(); msg.(eventMsg).Type != events.PositionOpened {
+		t.Errorf("expected the published event, got %+v", msg)
+	}
+	close(ch)
+	if msg := model.waitForEventCmd()(); msg != (eventsClosedMsg{}) {
+		t.Errorf("expected the channel closing to be reported, got %+v", msg)
+	}
+}
+
+func TestModelUpdate_AttachesToBot(t *testing.T) {
+	model := NewModel()
+
+	var tm tea.Model = model
+	tm, _ = tm.Update(eventMsg(events.Event{Type: events.Attached, Detail: events.ModeLive}))
+	if view := tm.View(); !strings.Contains(view, "LIVE") {
+		t.Errorf("expected the bot's live mode, got: %s", view)
+	}
+
+	tm, cmd := tm.Update(eventsClosedMsg{})
+	if cmd != nil {
+		t.Error("expected the model to stop waiting for events")
+	}
+	if view := tm.View(); !strings.Contains(view, "[BOT DISCONNECTED]") {
+		t.Errorf("expected disconnected indicator, got: %s", view)
+	}
+}
+
+func TestModelRecordEvent_KeepsLatestActivity(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < maxActivity+3; i++ {
+		model.recordEvent(events.Event{Type: events.MarketEligible, Count: i})
+	}
+
+	if len(model.activity) != maxActivity || model.activity[0].Count != 3 {
+		t.Errorf("expected the latest %d events, got %+v", maxActivity, model.activity)
+	}
+}
+
+// MockPositionActions records the positions closed and repriced.
+type MockPositionActions struct {
+	closed []int64
+	price  float64
+}
+
+func (m *MockPositionActions) ClosePosition(ctx context.Context, positionID int64) error {
+	m.closed = append(m.closed, positionID)
+	return nil
+}
+
+func (m *MockPositionActions) RefreshPrice(positionID int64) (float64, error) {
+	if m.price == 0 {
+		return 0, errors.New("no price")
+	}
+	return m.price, nil
+}
+
+func TestModelActsOnSelectedPosition(t *testing.T) {
+	press := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
+
+	provider := &MockDataProvider{positions: []views.PositionData{
+		{ID: 7, Platform: "kalshi", Asset: "BTC", EntryPrice: 0.80, CurrentPrice: 0.80, Quantity: 10, Side: "YES"},
+		{ID: 9, Platform: "kalshi", Asset: "ETH", EntryPrice: 0.60, CurrentPrice: 0.60, Quantity: 5, Side: "NO"},
+	}}
+	model := NewModelWithProvider(provider, true)
+	model.width = 120
+	updated, _ := model.Update(model.fetchDataCmd()())
+
+	// Positions can't be selected without actions to take on them
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if updated.(Model).selected != 0 {
+		t.Error("expected no selection without position actions")
+	}
+
+	actions := &MockPositionActions{price: 0.72}
+	model.SetPositionActions(actions)
+	updated, _ = model.Update(model.fetchDataCmd()())
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := updated.(Model).selected; got != 9 {
+		t.Fatalf("expected the last position selected, got %d", got)
+	}
+
+	// Refreshing the price shows it in place of the provider's
+	updated, cmd := updated.Update(press('f'))
+	if cmd == nil {
+		t.Fatal("expected refreshing the price to fetch it")
+	}
+	updated, _ = updated.Update(cmd())
+	view := updated.(Model).View()
+	if !strings.Contains(view, "$0.72") || !strings.Contains(view, "Position 9 priced at $0.72") {
+		t.Errorf("expected the refreshed price shown, got: %s", view)
+	}
+	updated, _ = updated.Update(model.fetchDataCmd()())
+	if got := updated.(Model).positions[1].CurrentPrice; got != 0.72 {
+		t.Errorf("expected the refreshed price kept over the provider's, got %v", got)
+	}
+
+	// Closing asks first, and any key but y cancels
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyUp})
+	updated, cmd = updated.Update(press('c'))
+	if cmd != nil || !strings.Contains(updated.(Model).View(), "Close position 7 (BTC)") {
+		t.Fatalf("expected a confirmation prompt for position 7, got: %s", updated.(Model).View())
+	}
+	updated, cmd = updated.Update(press('n'))
+	if cmd != nil || len(actions.closed) != 0 {
+		t.Fatal("expected the close cancelled")
+	}
+
+	updated, _ = updated.Update(press('c'))
+	updated, cmd = updated.Update(press('y'))
+	if cmd == nil {
+		t.Fatal("expected confirming to close the position")
+	}
+	updated, cmd = updated.Update(cmd())
+	if !reflect.DeepEqual(actions.closed, []int64{7}) {
+		t.Errorf("expected position 7 closed, got %v", actions.closed)
+	}
+	if cmd == nil || !strings.Contains(updated.(Model).View(), "Closed position 7") {
+		t.Error("expected the close reported and the positions refreshed")
+	}
+
+	// A failed refresh is reported
+	actions.price = 0
+	_, cmd = updated.Update(press('f'))
+	updated, _ = updated.Update(cmd())
+	if !strings.Contains(updated.(Model).View(), "Failed to refresh the price of position 7") {
+		t.Errorf("expected the failure reported, got: %s", updated.(Model).View())
+	}
+}
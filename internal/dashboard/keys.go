@@ -2,6 +2,9 @@ package dashboard
 
 import (
 	"fmt"
+	"strings"
+
+	"prediction-bot/internal/i18n"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
@@ -12,6 +15,20 @@ type KeyMap struct {
 	Quit    key.Binding
 	Refresh key.Binding
 	Pause   key.Binding
+	PnL     key.Binding
+	Scan    key.Binding
+	Funnel  key.Binding
+	Trades  key.Binding
+	// NextPage and PrevPage page through the recent trades while shown
+	NextPage key.Binding
+	PrevPage key.Binding
+	// Up and Down select an open position for Close and Price to act on
+	Up    key.Binding
+	Down  key.Binding
+	Close key.Binding
+	Price key.Binding
+
+	ascii bool
 }
 
 // DefaultKeyMap returns the default keybindings.
@@ -29,9 +46,72 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("p"),
 			key.WithHelp("p", "pause"),
 		),
+		PnL: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "pnl report"),
+		),
+		Scan: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "scan diff"),
+		),
+		Funnel: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "scan funnel"),
+		),
+		Trades: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "trades"),
+		),
+		NextPage: key.NewBinding(
+			key.WithKeys("]", "pgdown"),
+			key.WithHelp("[ ]", "page trades"),
+		),
+		PrevPage: key.NewBinding(
+			key.WithKeys("[", "pgup"),
+			key.WithHelp("[ ]", "page trades"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("j/k", "select position"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("j/k", "select position"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "close position"),
+		),
+		Price: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "refresh price"),
+		),
 	}
 }
 
+// SetTranslator sets the language the keybinding help is shown in.
+func (k *KeyMap) SetTranslator(tr i18n.Translator) {
+	k.Quit.SetHelp("q", tr.T("key.quit"))
+	k.Refresh.SetHelp("r", tr.T("key.refresh"))
+	k.Pause.SetHelp("p", tr.T("key.pause"))
+	k.PnL.SetHelp("m", tr.T("key.pnl"))
+	k.Scan.SetHelp("d", tr.T("key.scan_diff"))
+	k.Funnel.SetHelp("s", tr.T("key.funnel"))
+	k.Trades.SetHelp("t", tr.T("key.trades"))
+	k.NextPage.SetHelp("[ ]", tr.T("key.page"))
+	k.PrevPage.SetHelp("[ ]", tr.T("key.page"))
+	k.Up.SetHelp("j/k", tr.T("key.select"))
+	k.Down.SetHelp("j/k", tr.T("key.select"))
+	k.Close.SetHelp("c", tr.T("key.close"))
+	k.Price.SetHelp("f", tr.T("key.price"))
+}
+
+// SetASCII sets whether the help separator is drawn with ASCII characters
+// only.
+func (k *KeyMap) SetASCII(ascii bool) {
+	k.ascii = ascii
+}
+
 // HelpView returns a formatted help view showing all keybindings.
 func (k KeyMap) HelpView() string {
 	helpStyle := lipgloss.NewStyle().
@@ -42,24 +122,27 @@ func (k KeyMap) HelpView() string {
 		Foreground(lipgloss.Color("39"))
 
 	separator := helpStyle.Render(" • ")
+	if k.ascii {
+		separator = helpStyle.Render(" * ")
+	}
 
-	items := []string{
-		fmt.Sprintf("%s %s", keyStyle.Render("q"), helpStyle.Render("quit")),
-		fmt.Sprintf("%s %s", keyStyle.Render("r"), helpStyle.Render("refresh")),
-		fmt.Sprintf("%s %s", keyStyle.Render("p"), helpStyle.Render("pause")),
+	var items []string
+	for _, b := range k.ShortHelp() {
+		items = append(items, fmt.Sprintf("%s %s", keyStyle.Render(b.Help().Key), helpStyle.Render(b.Help().Desc)))
 	}
 
-	return fmt.Sprintf("%s%s%s%s%s", items[0], separator, items[1], separator, items[2])
+	return strings.Join(items, separator)
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Quit, k.Refresh, k.Pause}
+	return []key.Binding{k.Quit, k.Refresh, k.Pause, k.PnL, k.Scan, k.Funnel, k.Trades, k.NextPage, k.Down, k.Close, k.Price}
 }
 
 // FullHelp returns keybindings for the expanded help view.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Quit, k.Refresh, k.Pause},
+		{k.Quit, k.Refresh, k.Pause, k.PnL, k.Scan, k.Funnel, k.Trades, k.NextPage, k.PrevPage},
+		{k.Up, k.Down, k.Close, k.Price},
 	}
 }
@@ -1,12 +1,16 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"prediction-bot/internal/dashboard/views"
+	"prediction-bot/internal/events"
+	"prediction-bot/internal/i18n"
 )
 
 // tickMsg is sent on each tick to update the timestamp
@@ -18,10 +22,44 @@ type quitMsg struct{}
 // dataUpdateMsg is sent when data is refreshed
 type dataUpdateMsg struct {
 	bankrolls []views.BankrollData
+	equity    views.EquityData
 	positions []views.PositionData
 	stats     views.StatsData
+	arbitrage []views.ArbitrageData
+	pnl       []views.PnLData
+	scanDiff  []views.ScanDiffData
+	funnel    []views.FunnelData
+	trades    views.TradesPage
 }
 
+// eventMsg carries an event published by the bot
+type eventMsg events.Event
+
+// eventsClosedMsg is sent when the bot's events stop, such as when the bot
+// a dashboard is attached to exits
+type eventsClosedMsg struct{}
+
+// positionClosedMsg is sent when closing a position from the dashboard
+// finishes
+type positionClosedMsg struct {
+	id  int64
+	err error
+}
+
+// positionPriceMsg is sent when refreshing a position's price from the
+// dashboard finishes
+type positionPriceMsg struct {
+	id    int64
+	price float64
+	err   error
+}
+
+// maxActivity is how many of the latest activity lines are shown.
+const maxActivity = 8
+
+// tradesPageSize is how many closed trades are shown per page.
+const tradesPageSize = 10
+
 // DataProvider defines the interface for fetching dashboard data.
 type DataProvider interface {
 	GetBankrolls() ([]views.BankrollData, error)
@@ -29,6 +67,58 @@ type DataProvider interface {
 	GetStats() (views.StatsData, error)
 }
 
+// ArbitrageProvider defines the interface for data providers that report
+// detected arbitrage opportunities. The arbitrage section is only shown for
+// providers that implement it.
+type ArbitrageProvider interface {
+	GetArbitrage() ([]views.ArbitrageData, error)
+}
+
+// EquityProvider defines the interface for data providers that report the
+// bankroll over time. The equity section is only shown for providers that
+// implement it.
+type EquityProvider interface {
+	GetEquity() (views.EquityData, error)
+}
+
+// PnLProvider defines the interface for data providers that report realized
+// PnL by period. The PnL section can only be toggled on for providers that
+// implement it.
+type PnLProvider interface {
+	GetPnLReport() ([]views.PnLData, error)
+}
+
+// ScanDiffProvider defines the interface for data providers that report how
+// each platform's latest scan differs from the one before. The scan diff
+// section can only be toggled on for providers that implement it.
+type ScanDiffProvider interface {
+	GetScanDiff() ([]views.ScanDiffData, error)
+}
+
+// FunnelProvider defines the interface for data providers that report how
+// far each platform's markets got in its latest scan. The scan funnel
+// section can only be toggled on for providers that implement it.
+type FunnelProvider interface {
+	GetScanFunnel() ([]views.FunnelData, error)
+}
+
+// TradesProvider defines the interface for data providers that report
+// closed trades a page at a time. The recent trades section can only be
+// toggled on for providers that implement it.
+type TradesProvider interface {
+	GetClosedTrades(page, pageSize int) (views.TradesPage, error)
+}
+
+// PositionActions defines the interface for acting on open positions from
+// the dashboard. Positions can only be selected, closed and repriced once
+// it is set (see SetPositionActions).
+type PositionActions interface {
+	// ClosePosition exits an open position at its market's current price.
+	ClosePosition(ctx context.Context, positionID int64) error
+	// RefreshPrice fetches the current price of an open position's market.
+	RefreshPrice(positionID int64) (float64, error)
+}
+
 // Model represents the dashboard state
 type Model struct {
 	lastUpdate    time.Time
@@ -38,13 +128,41 @@ type Model struct {
 	height        int
 	dryRun        bool
 	bankrolls     []views.BankrollData
+	equity        views.EquityData
 	positions     []views.PositionData
 	stats         views.StatsData
+	arbitrage     []views.ArbitrageData
+	pnl           []views.PnLData
+	showPnL       bool
+	scanDiff      []views.ScanDiffData
+	showScanDiff  bool
+	funnel        []views.FunnelData
+	showFunnel    bool
+	trades        views.TradesPage
+	tradesPage    int
+	showTrades    bool
 	bankrollView  *views.BankrollView
+	equityView    *views.EquityView
 	positionsView *views.PositionsView
 	statsView     *views.StatsView
+	arbitrageView *views.ArbitrageView
+	pnlView       *views.PnLView
+	scanDiffView  *views.ScanDiffView
+	funnelView    *views.FunnelView
+	tradesView    *views.TradesView
+	activityView  *views.ActivityView
+	activity      []views.ActivityData
+	scanning      string // Platform being scanned, if any
+	events        <-chan events.Event
+	disconnected  bool // The bot's events stopped
 	keyMap        KeyMap
+	tr            i18n.Translator
 	dataProvider  DataProvider
+	actions       PositionActions
+	selected      int64             // ID of the selected position, if any
+	confirmClose  int64             // ID of the position awaiting confirmation to close
+	notice        string            // Outcome of the last position action
+	prices        map[int64]float64 // Refreshed prices by position ID
 	err           error
 }
 
@@ -58,9 +176,18 @@ func NewModel() Model {
 		height:        24,
 		dryRun:        true,
 		bankrollView:  views.NewBankrollView(),
+		equityView:    views.NewEquityView(),
 		positionsView: views.NewPositionsView(),
 		statsView:     views.NewStatsView(),
+		arbitrageView: views.NewArbitrageView(),
+		pnlView:       views.NewPnLView(),
+		scanDiffView:  views.NewScanDiffView(),
+		funnelView:    views.NewFunnelView(),
+		tradesView:    views.NewTradesView(),
+		activityView:  views.NewActivityView(),
 		keyMap:        DefaultKeyMap(),
+		tr:            i18n.New(i18n.DefaultLanguage),
+		prices:        make(map[int64]float64),
 	}
 }
 
@@ -72,15 +199,86 @@ func NewModelWithProvider(provider DataProvider, dryRun bool) Model {
 	return m
 }
 
+// SetCurrencyDecimals sets the number of decimal places dollar amounts are
+// shown with.
+func (m Model) SetCurrencyDecimals(decimals int) {
+	currency := views.NewCurrency(decimals)
+	m.bankrollView.SetCurrency(currency)
+	m.equityView.SetCurrency(currency)
+	m.positionsView.SetCurrency(currency)
+	m.statsView.SetCurrency(currency)
+	m.pnlView.SetCurrency(currency)
+	m.tradesView.SetCurrency(currency)
+}
+
+// SetLanguage sets the language the dashboard is shown in.
+func (m *Model) SetLanguage(lang i18n.Language) {
+	m.tr = i18n.New(lang)
+	m.keyMap.SetTranslator(m.tr)
+	m.bankrollView.SetTranslator(m.tr)
+	m.equityView.SetTranslator(m.tr)
+	m.positionsView.SetTranslator(m.tr)
+	m.statsView.SetTranslator(m.tr)
+	m.arbitrageView.SetTranslator(m.tr)
+	m.pnlView.SetTranslator(m.tr)
+	m.scanDiffView.SetTranslator(m.tr)
+	m.funnelView.SetTranslator(m.tr)
+	m.tradesView.SetTranslator(m.tr)
+	m.activityView.SetTranslator(m.tr)
+}
+
+// SetPlain switches the dashboard to ASCII-only boxes and separators, for
+// terminals that can't draw Unicode.
+func (m *Model) SetPlain(plain bool) {
+	glyphs := views.UnicodeGlyphs()
+	if plain {
+		glyphs = views.ASCIIGlyphs()
+	}
+	m.keyMap.SetASCII(plain)
+	m.bankrollView.SetGlyphs(glyphs)
+	m.equityView.SetGlyphs(glyphs)
+	m.positionsView.SetGlyphs(glyphs)
+	m.statsView.SetGlyphs(glyphs)
+	m.arbitrageView.SetGlyphs(glyphs)
+	m.pnlView.SetGlyphs(glyphs)
+	m.scanDiffView.SetGlyphs(glyphs)
+	m.funnelView.SetGlyphs(glyphs)
+	m.tradesView.SetGlyphs(glyphs)
+	m.activityView.SetGlyphs(glyphs)
+}
+
+// SetEvents sets the channel the bot's events are received from. Scans and
+// entry decisions are then shown as they happen, in an activity section.
+func (m *Model) SetEvents(ch <-chan events.Event) {
+	m.events = ch
+}
+
+// SetPositionActions sets how open positions are acted on. Positions can
+// then be selected with the arrow keys, closed and repriced.
+func (m *Model) SetPositionActions(actions PositionActions) {
+	m.actions = actions
+}
+
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(tickCmd(), m.fetchDataCmd())
+	return tea.Batch(tickCmd(), m.fetchDataCmd(), m.waitForEventCmd())
 }
 
 // Update implements tea.Model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// A pending close is confirmed with y and cancelled by any other key
+		if m.confirmClose != 0 {
+			id := m.confirmClose
+			m.confirmClose = 0
+			m.notice = ""
+			if msg.String() == "y" {
+				return m, m.closePositionCmd(id)
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			m.quitting = true
@@ -92,6 +290,82 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Toggle pause
 			m.paused = !m.paused
 			return m, nil
+		case "m":
+			// Toggle the PnL report, fetching it right away when shown
+			if _, ok := m.dataProvider.(PnLProvider); !ok {
+				return m, nil
+			}
+			m.showPnL = !m.showPnL
+			if m.showPnL {
+				return m, m.fetchDataCmd()
+			}
+			return m, nil
+		case "d":
+			// Toggle the scan diff, fetching it right away when shown
+			if _, ok := m.dataProvider.(ScanDiffProvider); !ok {
+				return m, nil
+			}
+			m.showScanDiff = !m.showScanDiff
+			if m.showScanDiff {
+				return m, m.fetchDataCmd()
+			}
+			return m, nil
+		case "s":
+			// Toggle the scan funnel, fetching it right away when shown
+			if _, ok := m.dataProvider.(FunnelProvider); !ok {
+				return m, nil
+			}
+			m.showFunnel = !m.showFunnel
+			if m.showFunnel {
+				return m, m.fetchDataCmd()
+			}
+			return m, nil
+		case "t":
+			// Toggle the recent trades from their first page, fetching them
+			// right away when shown
+			if _, ok := m.dataProvider.(TradesProvider); !ok {
+				return m, nil
+			}
+			m.showTrades = !m.showTrades
+			m.tradesPage = 0
+			if m.showTrades {
+				return m, m.fetchDataCmd()
+			}
+			return m, nil
+		case "]", "pgdown":
+			if !m.showTrades || m.tradesPage+1 >= m.trades.Pages() {
+				return m, nil
+			}
+			m.tradesPage++
+			return m, m.fetchDataCmd()
+		case "[", "pgup":
+			if !m.showTrades || m.tradesPage == 0 {
+				return m, nil
+			}
+			m.tradesPage--
+			return m, m.fetchDataCmd()
+		case "up", "k":
+			m.moveSelection(-1)
+			return m, nil
+		case "down", "j":
+			m.moveSelection(1)
+			return m, nil
+		case "c":
+			// Ask before closing: the exit is sold at the market price
+			if pos, ok := m.selectedPosition(); ok {
+				m.confirmClose = pos.ID
+				label := pos.Asset
+				if label == "" {
+					label = pos.MarketTitle
+				}
+				m.notice = m.tr.T("actions.confirm_close", pos.ID, label)
+			}
+			return m, nil
+		case "f":
+			if pos, ok := m.selectedPosition(); ok {
+				return m, m.refreshPriceCmd(pos.ID)
+			}
+			return m, nil
 		}
 
 	case tea.WindowSizeMsg:
@@ -109,11 +383,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case dataUpdateMsg:
 		m.bankrolls = msg.bankrolls
+		m.equity = msg.equity
 		m.positions = msg.positions
+		m.applyPrices()
 		m.stats = msg.stats
+		m.arbitrage = msg.arbitrage
+		m.pnl = msg.pnl
+		m.scanDiff = msg.scanDiff
+		m.funnel = msg.funnel
+		m.trades = msg.trades
 		m.err = nil
 		return m, nil
 
+	case eventMsg:
+		m.recordEvent(events.Event(msg))
+		cmd := m.waitForEventCmd()
+		// Refresh right away when a position opens instead of at the next tick
+		if msg.Type == events.PositionOpened {
+			cmd = tea.Batch(cmd, m.fetchDataCmd())
+		}
+		return m, cmd
+
+	case eventsClosedMsg:
+		m.disconnected = true
+		m.scanning = ""
+		return m, nil
+
+	case positionClosedMsg:
+		if msg.err != nil {
+			m.notice = m.tr.T("actions.close_failed", msg.id, msg.err)
+			return m, nil
+		}
+		m.notice = m.tr.T("actions.closed", msg.id)
+		return m, m.fetchDataCmd()
+
+	case positionPriceMsg:
+		if msg.err != nil {
+			m.notice = m.tr.T("actions.price_failed", msg.id, msg.err)
+			return m, nil
+		}
+		m.prices[msg.id] = msg.price
+		m.applyPrices()
+		m.notice = m.tr.T("actions.priced", msg.id, msg.price)
+		return m, nil
+
 	case quitMsg:
 		m.quitting = true
 		return m, tea.Quit
@@ -125,7 +438,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // View implements tea.Model
 func (m Model) View() string {
 	if m.quitting {
-		return "Goodbye!\n"
+		return m.tr.T("dashboard.goodbye") + "\n"
 	}
 
 	// Styles
@@ -145,20 +458,33 @@ func (m Model) View() string {
 		MarginTop(1)
 
 	// Header
-	title := titleStyle.Render("Prediction Market Bot")
-	timestamp := timestampStyle.Render(fmt.Sprintf("Last Update: %s", m.lastUpdate.Format("15:04:05")))
+	title := titleStyle.Render(m.tr.T("dashboard.title"))
+	timestamp := timestampStyle.Render(m.tr.T("dashboard.last_update", m.lastUpdate.Format("15:04:05")))
 
 	// Status indicators
 	var statusParts []string
 
 	// Mode indicator
 	if m.dryRun {
-		statusParts = append(statusParts, statusStyle.Render("[DRY-RUN]"))
+		statusParts = append(statusParts, statusStyle.Render(m.tr.T("dashboard.dry_run")))
 	} else {
 		statusParts = append(statusParts, lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("196")).
-			Render("[LIVE]"))
+			Render(m.tr.T("dashboard.live")))
+	}
+
+	// Scanning indicator
+	if m.scanning != "" {
+		statusParts = append(statusParts, statusStyle.Render(m.tr.T("dashboard.scanning", m.scanning)))
+	}
+
+	// Disconnected indicator
+	if m.disconnected {
+		statusParts = append(statusParts, lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196")).
+			Render(m.tr.T("dashboard.disconnected")))
 	}
 
 	// Paused indicator
@@ -167,7 +493,7 @@ func (m Model) View() string {
 			Bold(true).
 			Foreground(lipgloss.Color("214")). // Orange
 			Blink(true)
-		statusParts = append(statusParts, pausedStyle.Render("[PAUSED]"))
+		statusParts = append(statusParts, pausedStyle.Render(m.tr.T("dashboard.paused")))
 	}
 
 	statusText := ""
@@ -179,6 +505,9 @@ func (m Model) View() string {
 	}
 
 	header := fmt.Sprintf("%s %s\n%s", title, statusText, timestamp)
+	if m.notice != "" {
+		header += "\n" + statusStyle.Render(m.notice)
+	}
 
 	// Calculate available width for sections
 	sectionWidth := m.width - 2
@@ -189,17 +518,57 @@ func (m Model) View() string {
 	// Bankroll section
 	bankrollSection := m.bankrollView.Render(m.bankrolls, sectionWidth)
 
-	// Positions section
+	// Positions section, with the selected position highlighted
+	m.positionsView.SetSelected(m.selected)
 	positionsSection := m.positionsView.Render(m.positions, sectionWidth)
 
 	// Stats section
 	statsSection := m.statsView.Render(m.stats, sectionWidth)
 
+	sections := []string{header, bankrollSection}
+
+	// Equity section, if the provider reports it
+	if _, ok := m.dataProvider.(EquityProvider); ok {
+		sections = append(sections, m.equityView.Render(m.equity, sectionWidth))
+	}
+
+	sections = append(sections, positionsSection, statsSection)
+
+	// Arbitrage section, if the provider reports it
+	if _, ok := m.dataProvider.(ArbitrageProvider); ok {
+		sections = append(sections, m.arbitrageView.Render(m.arbitrage, sectionWidth))
+	}
+
+	// PnL report section, if toggled on
+	if m.showPnL {
+		sections = append(sections, m.pnlView.Render(m.pnl, sectionWidth))
+	}
+
+	// Scan diff section, if toggled on
+	if m.showScanDiff {
+		sections = append(sections, m.scanDiffView.Render(m.scanDiff, sectionWidth))
+	}
+
+	// Scan funnel section, if toggled on
+	if m.showFunnel {
+		sections = append(sections, m.funnelView.Render(m.funnel, sectionWidth))
+	}
+
+	// Recent trades section, if toggled on
+	if m.showTrades {
+		sections = append(sections, m.tradesView.Render(m.trades, sectionWidth))
+	}
+
+	// Activity section, if the bot's events are streamed
+	if m.events != nil {
+		sections = append(sections, m.activityView.Render(m.activity, sectionWidth))
+	}
+
 	// Help text using keymap
 	help := helpStyle.Render(m.keyMap.HelpView())
+	sections = append(sections, help)
 
-	return fmt.Sprintf("\n%s\n\n%s\n\n%s\n\n%s\n\n%s\n",
-		header, bankrollSection, positionsSection, statsSection, help)
+	return "\n" + strings.Join(sections, "\n\n") + "\n"
 }
 
 // tickCmd returns a command that sends a tick message after 1 second
@@ -220,10 +589,180 @@ func (m Model) fetchDataCmd() tea.Cmd {
 		positions, _ := m.dataProvider.GetPositions()
 		stats, _ := m.dataProvider.GetStats()
 
+		var equity views.EquityData
+		if provider, ok := m.dataProvider.(EquityProvider); ok {
+			equity, _ = provider.GetEquity()
+		}
+
+		var arbitrage []views.ArbitrageData
+		if provider, ok := m.dataProvider.(ArbitrageProvider); ok {
+			arbitrage, _ = provider.GetArbitrage()
+		}
+
+		// The report reads the year's trades, so only while it is shown
+		var pnl []views.PnLData
+		if provider, ok := m.dataProvider.(PnLProvider); ok && m.showPnL {
+			pnl, _ = provider.GetPnLReport()
+		}
+		var scanDiff []views.ScanDiffData
+		if provider, ok := m.dataProvider.(ScanDiffProvider); ok && m.showScanDiff {
+			scanDiff, _ = provider.GetScanDiff()
+		}
+		var funnel []views.FunnelData
+		if provider, ok := m.dataProvider.(FunnelProvider); ok && m.showFunnel {
+			funnel, _ = provider.GetScanFunnel()
+		}
+		var trades views.TradesPage
+		if provider, ok := m.dataProvider.(TradesProvider); ok && m.showTrades {
+			trades, _ = provider.GetClosedTrades(m.tradesPage, tradesPageSize)
+		}
+
 		return dataUpdateMsg{
 			bankrolls: bankrolls,
+			equity:    equity,
 			positions: positions,
 			stats:     stats,
+			arbitrage: arbitrage,
+			pnl:       pnl,
+			scanDiff:  scanDiff,
+			funnel:    funnel,
+			trades:    trades,
+		}
+	}
+}
+
+// moveSelection moves the selected position by delta rows, from the first
+// or last row if none is selected. Positions are only selectable while
+// position actions are set.
+func (m *Model) moveSelection(delta int) {
+	if m.actions == nil || len(m.positions) == 0 {
+		return
+	}
+	index := -1
+	for i, pos := range m.positions {
+		if pos.ID == m.selected {
+			index = i
 		}
 	}
+	switch {
+	case index < 0 && delta > 0:
+		index = 0
+	case index < 0:
+		index = len(m.positions) - 1
+	default:
+		index = max(0, min(index+delta, len(m.positions)-1))
+	}
+	m.selected = m.positions[index].ID
+}
+
+// selectedPosition returns the selected position, if it is still open and
+// position actions are set.
+func (m Model) selectedPosition() (views.PositionData, bool) {
+	if m.actions == nil {
+		return views.PositionData{}, false
+	}
+	for _, pos := range m.positions {
+		if pos.ID == m.selected {
+			return pos, true
+		}
+	}
+	return views.PositionData{}, false
+}
+
+// applyPrices shows each open position's refreshed price in place of the
+// provider's, and forgets those of positions no longer open. A selection of
+// a position no longer open is cleared.
+func (m *Model) applyPrices() {
+	open := make(map[int64]bool, len(m.positions))
+	for i := range m.positions {
+		id := m.positions[i].ID
+		open[id] = true
+		if price, ok := m.prices[id]; ok {
+			m.positions[i].CurrentPrice = price
+		}
+	}
+	for id := range m.prices {
+		if !open[id] {
+			delete(m.prices, id)
+		}
+	}
+	if !open[m.selected] {
+		m.selected = 0
+	}
+}
+
+// closePositionCmd returns a command that closes a position.
+func (m Model) closePositionCmd(id int64) tea.Cmd {
+	actions := m.actions
+	return func() tea.Msg {
+		return positionClosedMsg{id: id, err: actions.ClosePosition(context.Background(), id)}
+	}
+}
+
+// refreshPriceCmd returns a command that fetches a position's current price.
+func (m Model) refreshPriceCmd(id int64) tea.Cmd {
+	actions := m.actions
+	return func() tea.Msg {
+		price, err := actions.RefreshPrice(id)
+		return positionPriceMsg{id: id, price: price, err: err}
+	}
+}
+
+// waitForEventCmd returns a command that waits for the bot's next event.
+func (m Model) waitForEventCmd() tea.Cmd {
+	if m.events == nil {
+		return nil
+	}
+
+	ch := m.events
+	return func() tea.Msg {
+		event, ok := <-ch
+		if !ok {
+			return eventsClosedMsg{}
+		}
+		return eventMsg(event)
+	}
+}
+
+// recordEvent updates the scanning indicator and activity from an event,
+// keeping the latest maxActivity lines.
+func (m *Model) recordEvent(event events.Event) {
+	activity := views.ActivityData{
+		Time:     event.Time,
+		Platform: event.Platform,
+		Title:    event.Title,
+		Side:     event.Side,
+		Price:    event.Price,
+		Count:    event.Count,
+		Detail:   event.Detail,
+	}
+
+	switch event.Type {
+	case events.Attached:
+		// A dashboard attached over the socket learns the bot's mode from it
+		m.dryRun = event.Detail != events.ModeLive
+		return
+	case events.ScanStarted:
+		m.scanning = event.Platform
+		return
+	case events.ScanFailed:
+		m.scanning = ""
+		return
+	case events.ScanCompleted:
+		m.scanning = ""
+		activity.Kind = views.ActivityScanned
+	case events.MarketEligible:
+		activity.Kind = views.ActivityEligible
+	case events.EntrySkipped:
+		activity.Kind = views.ActivitySkipped
+	case events.PositionOpened:
+		activity.Kind = views.ActivityOpened
+	default:
+		return
+	}
+
+	m.activity = append(m.activity, activity)
+	if len(m.activity) > maxActivity {
+		m.activity = m.activity[len(m.activity)-maxActivity:]
+	}
 }
@@ -0,0 +1,81 @@
+package dashboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// PositionLister fetches the positions the account holds on a platform.
+type PositionLister interface {
+	GetPositions() ([]types.Position, error)
+}
+
+// HoldingPrices is a PriceGetter pricing markets at the current price their
+// platform reports for the positions held on them. Each platform's positions
+// are fetched at most once per refresh interval, however often the
+// dashboard redraws. Markets not held, or on platforms that don't report
+// prices, are unpriced.
+type HoldingPrices struct {
+	mu        sync.Mutex
+	platforms map[string]PositionLister
+	refresh   time.Duration
+	fetched   map[string]time.Time
+	prices    map[string]map[string]float64
+	now       func() time.Time
+}
+
+// NewHoldingPrices creates a HoldingPrices refreshing each platform's
+// positions every refresh.
+func NewHoldingPrices(refresh time.Duration) *HoldingPrices {
+	return &HoldingPrices{
+		platforms: make(map[string]PositionLister),
+		refresh:   refresh,
+		fetched:   make(map[string]time.Time),
+		prices:    make(map[string]map[string]float64),
+		now:       time.Now,
+	}
+}
+
+// SetPlatform registers a platform whose positions are priced.
+func (h *HoldingPrices) SetPlatform(name string, lister PositionLister) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.platforms[name] = lister
+}
+
+// GetCurrentPrice implements PriceGetter. A platform whose positions can't
+// be fetched keeps the prices last fetched until the next refresh.
+func (h *HoldingPrices) GetCurrentPrice(platform, marketID string) (float64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	lister := h.platforms[platform]
+	if lister == nil {
+		return 0, fmt.Errorf("no positions for platform %s", platform)
+	}
+
+	now := h.now()
+	if fetched, ok := h.fetched[platform]; !ok || now.Sub(fetched) >= h.refresh {
+		h.fetched[platform] = now
+		held, err := lister.GetPositions()
+		if err != nil {
+			return 0, fmt.Errorf("get %s positions: %w", platform, err)
+		}
+		prices := make(map[string]float64, len(held))
+		for _, pos := range held {
+			if pos.CurrentPrice > 0 {
+				prices[pos.MarketTicker] = pos.CurrentPrice
+			}
+		}
+		h.prices[platform] = prices
+	}
+
+	price, ok := h.prices[platform][marketID]
+	if !ok {
+		return 0, fmt.Errorf("no current price for %s on %s", marketID, platform)
+	}
+	return price, nil
+}
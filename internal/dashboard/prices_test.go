@@ -0,0 +1,58 @@
+package dashboard
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// countingLister returns fixed positions, counting the fetches.
+type countingLister struct {
+	positions []types.Position
+	err       error
+	fetches   int
+}
+
+func (l *countingLister) GetPositions() ([]types.Position, error) {
+	l.fetches++
+	return l.positions, l.err
+}
+
+func TestHoldingPrices_RefreshesPerInterval(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	lister := &countingLister{positions: []types.Position{
+		{MarketTicker: "0xpriced", CurrentPrice: 0.85},
+		{MarketTicker: "0xunpriced"},
+	}}
+	prices := NewHoldingPrices(30 * time.Second)
+	prices.now = func() time.Time { return now }
+	prices.SetPlatform("polymarket", lister)
+
+	for i := 0; i < 3; i++ {
+		price, err := prices.GetCurrentPrice("polymarket", "0xpriced")
+		if err != nil || price != 0.85 {
+			t.Fatalf("expected 0.85, got %v, %v", price, err)
+		}
+	}
+	if _, err := prices.GetCurrentPrice("polymarket", "0xunpriced"); err == nil {
+		t.Error("expected a position without a price unpriced")
+	}
+	if _, err := prices.GetCurrentPrice("kalshi", "KXBTC"); err == nil {
+		t.Error("expected an unregistered platform unpriced")
+	}
+	if lister.fetches != 1 {
+		t.Errorf("expected one fetch within the interval, got %d", lister.fetches)
+	}
+
+	// A failed refresh keeps the last prices until the next
+	now = now.Add(time.Minute)
+	lister.err = errors.New("unavailable")
+	if _, err := prices.GetCurrentPrice("polymarket", "0xpriced"); err == nil {
+		t.Error("expected the failed fetch reported")
+	}
+	if price, err := prices.GetCurrentPrice("polymarket", "0xpriced"); err != nil || price != 0.85 {
+		t.Errorf("expected the last price kept, got %v, %v", price, err)
+	}
+}
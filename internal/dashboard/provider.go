@@ -1,17 +1,39 @@
 package dashboard
 
 import (
+	"strings"
+	"time"
+
 	"prediction-bot/internal/dashboard/views"
+	"prediction-bot/internal/funnel"
 	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/report"
+	"prediction-bot/internal/scandiff"
+	"prediction-bot/pkg/types"
 )
 
 // DBDataProvider implements DataProvider using database repositories.
 type DBDataProvider struct {
-	bankrollRepo *persistence.BankrollRepository
-	positionRepo *persistence.PositionRepository
-	priceGetter  PriceGetter
+	bankrollRepo  *persistence.BankrollRepository
+	positionRepo  *persistence.PositionRepository
+	costRepo      *persistence.CostRepository
+	arbitrageRepo *persistence.ArbitrageRepository
+	historyRepo   *persistence.PriceHistoryRepository
+	snapshotRepo  *persistence.ScanSnapshotRepository
+	apiLogRepo    *persistence.APILogRepository
+	priceGetter   PriceGetter
 }
 
+// arbitrageWindow is how recently an opportunity must have been seen to be
+// shown.
+const arbitrageWindow = time.Hour
+
+// apiHealthWindow is how far back platform API calls are aggregated.
+const apiHealthWindow = time.Hour
+
+// equityWindow is how far back the equity curve is plotted.
+const equityWindow = 30 * 24 * time.Hour
+
 // PriceGetter interface for getting current market prices.
 type PriceGetter interface {
 	GetCurrentPrice(platform, marketID string) (float64, error)
@@ -30,6 +52,36 @@ func NewDBDataProvider(
 	}
 }
 
+// SetCostRepository sets the cost ledger used to report gas spend. Without
+// it, stats only reflect platform fees.
+func (p *DBDataProvider) SetCostRepository(repo *persistence.CostRepository) {
+	p.costRepo = repo
+}
+
+// SetArbitrageRepository sets the repository detected arbitrage
+// opportunities are read from.
+func (p *DBDataProvider) SetArbitrageRepository(repo *persistence.ArbitrageRepository) {
+	p.arbitrageRepo = repo
+}
+
+// SetPriceHistoryRepository sets the repository underlying asset prices are
+// read from to show each position's price action since entry.
+func (p *DBDataProvider) SetPriceHistoryRepository(repo *persistence.PriceHistoryRepository) {
+	p.historyRepo = repo
+}
+
+// SetScanSnapshotRepository sets the repository scan snapshots are read
+// from to show how each platform's latest scan differs from the one before.
+func (p *DBDataProvider) SetScanSnapshotRepository(repo *persistence.ScanSnapshotRepository) {
+	p.snapshotRepo = repo
+}
+
+// SetAPILogRepository sets the API call log platform API health is
+// aggregated from for the stats.
+func (p *DBDataProvider) SetAPILogRepository(repo *persistence.APILogRepository) {
+	p.apiLogRepo = repo
+}
+
 // GetBankrolls implements DataProvider.
 func (p *DBDataProvider) GetBankrolls() ([]views.BankrollData, error) {
 	if p.bankrollRepo == nil {
@@ -74,22 +126,51 @@ func (p *DBDataProvider) GetPositions() ([]views.PositionData, error) {
 			}
 		}
 
+		var closeTime time.Time
+		if pos.MarketCloseTime != nil {
+			closeTime = *pos.MarketCloseTime
+		}
+
 		result = append(result, views.PositionData{
-			ID:           pos.ID,
-			Platform:     pos.Platform,
-			MarketTitle:  pos.MarketTitle,
-			Asset:        pos.Asset,
-			EntryPrice:   pos.EntryPrice,
-			CurrentPrice: currentPrice,
-			Quantity:     pos.Quantity,
-			Side:         pos.Side,
-			EntryTime:    pos.EntryTime,
+			ID:                pos.ID,
+			Platform:          pos.Platform,
+			MarketTitle:       pos.MarketTitle,
+			Asset:             pos.Asset,
+			EntryPrice:        pos.EntryPrice,
+			CurrentPrice:      currentPrice,
+			Quantity:          pos.Quantity,
+			Side:              pos.Side,
+			EntryTime:         pos.EntryTime,
+			Direction:         pos.Direction,
+			SafetyMargin:      pos.SafetyMarginAtEntry,
+			CloseTime:         closeTime,
+			UnderlyingHistory: p.underlyingHistory(pos),
 		})
 	}
 
 	return result, nil
 }
 
+// underlyingHistory returns the stored prices of a position's underlying
+// asset since entry. The sparkline is informational, so it is left empty if
+// history can't be read.
+func (p *DBDataProvider) underlyingHistory(pos *persistence.Position) []float64 {
+	if p.historyRepo == nil || pos.Asset == "" {
+		return nil
+	}
+
+	prices, err := p.historyRepo.GetSince(strings.ToUpper(pos.Asset), pos.EntryTime)
+	if err != nil {
+		return nil
+	}
+
+	history := make([]float64, 0, len(prices))
+	for _, price := range prices {
+		history = append(history, price.Price)
+	}
+	return history
+}
+
 // GetStats implements DataProvider.
 func (p *DBDataProvider) GetStats() (views.StatsData, error) {
 	if p.positionRepo == nil {
@@ -111,7 +192,8 @@ func (p *DBDataProvider) GetStats() (views.StatsData, error) {
 	var stats views.StatsData
 	stats.TotalTrades = len(positions)
 
-	var totalRealizedPnL float64
+	// Summed as types.Money so totals over many trades are exact
+	var totalRealizedPnL, totalFees types.Money
 	var maxBalance, minBalance, currentBalance float64
 
 	for _, pos := range positions {
@@ -125,7 +207,8 @@ func (p *DBDataProvider) GetStats() (views.StatsData, error) {
 		} else if pnl < 0 {
 			stats.LosingTrades++
 		}
-		totalRealizedPnL += pnl
+		totalRealizedPnL += types.Dollars(pnl)
+		totalFees += types.Dollars(pos.Fees)
 
 		// Track balance for drawdown calculation
 		currentBalance += pnl
@@ -137,7 +220,24 @@ func (p *DBDataProvider) GetStats() (views.StatsData, error) {
 		}
 	}
 
-	stats.RealizedPnL = totalRealizedPnL
+	stats.RealizedPnL = totalRealizedPnL.Float64()
+	stats.Fees = totalFees.Float64()
+
+	// Gas is tracked outside positions, so subtract it for net profitability
+	var gas types.Money
+	if p.costRepo != nil {
+		summaries, err := p.costRepo.Summarize()
+		if err != nil {
+			return views.StatsData{}, err
+		}
+		for _, s := range summaries {
+			if s.Kind == persistence.CostKindGas {
+				gas += s.Total
+			}
+		}
+	}
+	stats.GasCost = gas.Float64()
+	stats.NetPnL = (totalRealizedPnL - gas).Float64()
 
 	// Calculate unrealized PnL from open positions
 	var unrealizedPnL float64
@@ -159,9 +259,192 @@ func (p *DBDataProvider) GetStats() (views.StatsData, error) {
 		stats.MaxDrawdown = (maxBalance - minBalance) / maxBalance
 	}
 
+	if p.apiLogRepo != nil {
+		health, err := p.apiLogRepo.Summarize(time.Now().Add(-apiHealthWindow))
+		if err != nil {
+			return views.StatsData{}, err
+		}
+		for _, h := range health {
+			stats.APIHealth = append(stats.APIHealth, views.APIHealthData{
+				API:        h.API,
+				Calls:      h.Calls,
+				Failures:   h.Failures,
+				AvgLatency: h.AvgLatency,
+			})
+		}
+	}
+
 	return stats, nil
 }
 
+// GetArbitrage implements ArbitrageProvider. It returns the opportunities
+// seen within the last hour.
+func (p *DBDataProvider) GetArbitrage() ([]views.ArbitrageData, error) {
+	if p.arbitrageRepo == nil {
+		return nil, nil
+	}
+
+	opportunities, err := p.arbitrageRepo.GetSince(time.Now().Add(-arbitrageWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []views.ArbitrageData
+	for _, a := range opportunities {
+		result = append(result, views.ArbitrageData{
+			Asset:          a.Asset,
+			Strike:         a.Strike,
+			Direction:      a.Direction,
+			ResolutionDate: a.ResolutionDate,
+			YesPlatform:    a.YesPlatform,
+			YesPrice:       a.YesPrice,
+			NoPlatform:     a.NoPlatform,
+			NoPrice:        a.NoPrice,
+			Spread:         a.Spread,
+			Hedged:         a.Hedged(),
+			LastSeen:       a.LastSeen,
+		})
+	}
+
+	return result, nil
+}
+
+// GetPnLReport implements PnLProvider. It returns the realized PnL of each
+// platform by month so far this year, followed by the year's totals.
+func (p *DBDataProvider) GetPnLReport() ([]views.PnLData, error) {
+	if p.positionRepo == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	entries, err := p.positionRepo.GetJournal(persistence.JournalFilter{
+		Since: time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := report.Build(entries)
+	var result []views.PnLData
+	for _, s := range append(r.Monthly, r.Yearly...) {
+		result = append(result, views.PnLData{
+			Period:      s.Period,
+			Platform:    s.Platform,
+			Trades:      s.Trades,
+			Wins:        s.Wins,
+			Losses:      s.Losses,
+			Fees:        s.Fees,
+			RealizedPnL: s.RealizedPnL,
+			AvgHolding:  s.AvgHolding,
+		})
+	}
+
+	return result, nil
+}
+
+// GetScanDiff implements ScanDiffProvider. It returns the changes between
+// each platform's two latest scans.
+func (p *DBDataProvider) GetScanDiff() ([]views.ScanDiffData, error) {
+	if p.snapshotRepo == nil {
+		return nil, nil
+	}
+
+	diffs, err := scandiff.Latest(p.snapshotRepo, scandiff.DefaultMinPriceMove)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []views.ScanDiffData
+	for _, d := range diffs {
+		data := views.ScanDiffData{Platform: d.Platform, Previous: d.Previous}
+		for _, c := range d.Changes {
+			data.Changes = append(data.Changes, views.ScanChangeData{
+				Kind:           c.Kind,
+				MarketID:       c.MarketID,
+				Title:          c.Title,
+				Reason:         c.Reason,
+				PreviousReason: c.PreviousReason,
+				PreviousPrice:  c.PreviousPrice,
+				Price:          c.Price,
+			})
+		}
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// GetScanFunnel implements FunnelProvider. It returns each platform's latest
+// scan broken down by stage.
+func (p *DBDataProvider) GetScanFunnel() ([]views.FunnelData, error) {
+	if p.snapshotRepo == nil {
+		return nil, nil
+	}
+
+	funnels, err := funnel.Latest(p.snapshotRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []views.FunnelData
+	for _, f := range funnels {
+		data := views.FunnelData{Platform: f.Platform, ScannedAt: f.ScannedAt}
+		for _, stage := range f.Stages {
+			s := views.FunnelStageData{Name: stage.Name, Count: stage.Count}
+			for _, skip := range stage.Skips {
+				s.Skips = append(s.Skips, views.FunnelSkipData{Reason: skip.Reason, Count: skip.Count})
+			}
+			data.Stages = append(data.Stages, s)
+		}
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// GetClosedTrades implements TradesProvider. It returns a page of the
+// closed positions, most recently closed first.
+func (p *DBDataProvider) GetClosedTrades(page, pageSize int) (views.TradesPage, error) {
+	result := views.TradesPage{Page: page, PageSize: pageSize}
+	if p.positionRepo == nil {
+		return result, nil
+	}
+
+	positions, total, err := p.positionRepo.GetClosedPaged(pageSize, page*pageSize)
+	if err != nil {
+		return result, err
+	}
+
+	result.Total = total
+	for _, pos := range positions {
+		trade := views.TradeData{
+			ID:          pos.ID,
+			Platform:    pos.Platform,
+			MarketTitle: pos.MarketTitle,
+			Asset:       pos.Asset,
+			Side:        pos.Side,
+			EntryPrice:  pos.EntryPrice,
+			Quantity:    pos.Quantity,
+			EntryTime:   pos.EntryTime,
+		}
+		if pos.ExitPrice != nil {
+			trade.ExitPrice = *pos.ExitPrice
+		}
+		if pos.ExitTime != nil {
+			trade.ExitTime = *pos.ExitTime
+		}
+		if pos.ExitReason != nil {
+			trade.ExitReason = *pos.ExitReason
+		}
+		if pos.RealizedPnL != nil {
+			trade.RealizedPnL = *pos.RealizedPnL
+		}
+		result.Trades = append(result.Trades, trade)
+	}
+
+	return result, nil
+}
+
 // NullPriceGetter is a no-op price getter that returns the entry price.
 type NullPriceGetter struct{}
 
@@ -169,3 +452,46 @@ type NullPriceGetter struct{}
 func (n *NullPriceGetter) GetCurrentPrice(platform, marketID string) (float64, error) {
 	return 0, nil
 }
+
+// GetEquity implements EquityProvider. It returns the total bankroll across
+// platforms after each change over the last equityWindow, with the realized
+// PnL of the trades closed today (UTC).
+func (p *DBDataProvider) GetEquity() (views.EquityData, error) {
+	if p.bankrollRepo == nil {
+		return views.EquityData{}, nil
+	}
+
+	history, err := p.bankrollRepo.GetHistory(time.Now().Add(-equityWindow))
+	if err != nil {
+		return views.EquityData{}, err
+	}
+
+	// Each point changes one platform, so the total carries the others'
+	// latest amounts
+	var data views.EquityData
+	amounts := make(map[string]types.Money)
+	var total types.Money
+	for _, point := range history {
+		amount := types.Dollars(point.Amount)
+		total += amount - amounts[point.Platform]
+		amounts[point.Platform] = amount
+		data.Curve = append(data.Curve, total.Float64())
+	}
+
+	if p.positionRepo != nil {
+		now := time.Now().UTC()
+		entries, err := p.positionRepo.GetJournal(persistence.JournalFilter{
+			Since: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			return views.EquityData{}, err
+		}
+		var daily types.Money
+		for _, e := range entries {
+			daily += types.Dollars(e.RealizedPnL)
+		}
+		data.DailyPnL = daily.Float64()
+	}
+
+	return data, nil
+}
@@ -0,0 +1,129 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ActivityKind identifies a kind of bot activity.
+type ActivityKind string
+
+const (
+	ActivityScanned  ActivityKind = "scanned"
+	ActivityEligible ActivityKind = "eligible"
+	ActivitySkipped  ActivityKind = "skipped"
+	ActivityOpened   ActivityKind = "opened"
+)
+
+// ActivityData represents something the bot did, for display.
+type ActivityData struct {
+	Time     time.Time
+	Kind     ActivityKind
+	Platform string
+	Title    string
+	Side     string
+	Price    float64
+	Count    int    // Eligible markets, for ActivityScanned
+	Detail   string // Skip reason, for ActivitySkipped
+}
+
+// ActivityView renders the bot's latest scan and entry activity, newest
+// first.
+type ActivityView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	timeStyle     lipgloss.Style
+	rowStyle      lipgloss.Style
+	positiveStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	platformStyle lipgloss.Style
+	tr            i18n.Translator
+}
+
+// NewActivityView creates a new ActivityView with default styles.
+func NewActivityView() *ActivityView {
+	return &ActivityView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		timeStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")),
+		rowStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		platformStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")), // Blue
+		tr: i18n.New(i18n.DefaultLanguage),
+	}
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *ActivityView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes are drawn with.
+func (v *ActivityView) SetGlyphs(g Glyphs) {
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders the activity, newest first.
+func (v *ActivityView) Render(activity []ActivityData, width int) string {
+	title := v.titleStyle.Render(v.tr.T("activity.title"))
+
+	if len(activity) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("activity.empty"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	lines := make([]string, 0, len(activity))
+	for i := len(activity) - 1; i >= 0; i-- {
+		lines = append(lines, v.renderRow(activity[i], width-6))
+	}
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(strings.Join(lines, "\n")))
+}
+
+// renderRow renders one activity line, fitting the market title into width.
+func (v *ActivityView) renderRow(a ActivityData, width int) string {
+	prefix := fmt.Sprintf("%s %s ",
+		v.timeStyle.Render(a.Time.Format("15:04:05")),
+		v.platformStyle.Render(fmt.Sprintf("%-5s", abbreviatePlatform(a.Platform))))
+
+	var label, detail string
+	switch a.Kind {
+	case ActivityScanned:
+		return prefix + v.neutralStyle.Render(v.tr.T("activity.scanned", a.Count))
+	case ActivityEligible:
+		label = v.rowStyle.Render(fmt.Sprintf("%-8s", v.tr.T("activity.eligible")))
+		detail = fmt.Sprintf("%s@%.2f", a.Side, a.Price)
+	case ActivitySkipped:
+		label = v.neutralStyle.Render(fmt.Sprintf("%-8s", v.tr.T("activity.skipped")))
+		detail = a.Detail
+	case ActivityOpened:
+		label = v.positiveStyle.Render(fmt.Sprintf("%-8s", v.tr.T("activity.opened")))
+		detail = fmt.Sprintf("%s@%.2f", a.Side, a.Price)
+	}
+
+	// Time, platform and label take 24 columns
+	titleWidth := width - 24 - len(detail) - 2
+	if titleWidth < 10 {
+		titleWidth = 10
+	}
+	return fmt.Sprintf("%s%s %s %s", prefix, label,
+		v.rowStyle.Render(fmt.Sprintf("%-*s", titleWidth, truncateString(a.Title, titleWidth))),
+		v.rowStyle.Render(detail))
+}
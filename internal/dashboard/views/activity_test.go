@@ -0,0 +1,36 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActivityView_RenderNewestFirst(t *testing.T) {
+	at := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	activity := []ActivityData{
+		{Time: at, Kind: ActivityScanned, Platform: "kalshi", Count: 2},
+		{Time: at.Add(time.Second), Kind: ActivityEligible, Platform: "kalshi", Title: "Bitcoin above $100,000", Side: "YES", Price: 0.85},
+		{Time: at.Add(2 * time.Second), Kind: ActivitySkipped, Platform: "kalshi", Title: "Ethereum above $4,000", Detail: "low_safety_margin"},
+		{Time: at.Add(3 * time.Second), Kind: ActivityOpened, Platform: "kalshi", Title: "Bitcoin above $100,000", Side: "YES", Price: 0.86},
+	}
+
+	output := NewActivityView().Render(activity, 100)
+
+	for _, want := range []string{"scan complete, 2 eligible", "ELIGIBLE", "YES@0.85", "SKIPPED", "low_safety_margin", "OPENED", "YES@0.86", "KALSH"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+	if strings.Index(output, "OPENED") > strings.Index(output, "scan complete") {
+		t.Errorf("expected newest activity first, got: %s", output)
+	}
+}
+
+func TestActivityView_RenderEmpty(t *testing.T) {
+	output := NewActivityView().Render(nil, 80)
+
+	if !strings.Contains(output, "Waiting for the first scan") {
+		t.Errorf("expected empty state message, got: %s", output)
+	}
+}
@@ -0,0 +1,180 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ArbitrageData represents a detected cross-platform price divergence for
+// display.
+type ArbitrageData struct {
+	Asset          string
+	Strike         float64
+	Direction      string
+	ResolutionDate time.Time
+	YesPlatform    string
+	YesPrice       float64
+	NoPlatform     string
+	NoPrice        float64
+	Spread         float64
+	Hedged         bool
+	LastSeen       time.Time
+}
+
+// Profit returns the locked-in profit per contract pair, before fees.
+func (a ArbitrageData) Profit() float64 {
+	return 1.0 - a.YesPrice - a.NoPrice
+}
+
+// ArbitrageView renders detected arbitrage opportunities.
+type ArbitrageView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	headerStyle   lipgloss.Style
+	rowStyle      lipgloss.Style
+	positiveStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	assetStyle    lipgloss.Style
+	platformStyle lipgloss.Style
+	tr            i18n.Translator
+	glyphs        Glyphs
+}
+
+// NewArbitrageView creates a new ArbitrageView with default styles.
+func NewArbitrageView() *ArbitrageView {
+	return &ArbitrageView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		headerStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("241")),
+		rowStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		assetStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("214")), // Orange
+		platformStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")), // Blue
+		tr:     i18n.New(i18n.DefaultLanguage),
+		glyphs: UnicodeGlyphs(),
+	}
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *ArbitrageView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *ArbitrageView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders the arbitrage view with the given data.
+func (v *ArbitrageView) Render(opportunities []ArbitrageData, width int) string {
+	title := v.titleStyle.Render(v.tr.T("arbitrage.title"))
+
+	if len(opportunities) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("arbitrage.empty"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	if width < CompactWidth {
+		var cards []string
+		for _, a := range opportunities {
+			cards = append(cards, v.renderCard(a))
+		}
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(strings.Join(cards, "\n\n")))
+	}
+
+	var lines []string
+	lines = append(lines, v.headerStyle.Render(
+		fmt.Sprintf("%-5s %-12s %-6s %-12s %-12s %-7s %-7s %s",
+			v.tr.T("arbitrage.asset"), v.tr.T("arbitrage.strike"), v.tr.T("arbitrage.date"),
+			"YES", "NO", v.tr.T("arbitrage.spread"), v.tr.T("arbitrage.profit"), "")))
+	lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
+
+	for _, a := range opportunities {
+		lines = append(lines, v.renderRow(a))
+	}
+
+	content := strings.Join(lines, "\n")
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+}
+
+// renderRow renders a single opportunity row.
+func (v *ArbitrageView) renderRow(a ArbitrageData) string {
+	asset := v.assetStyle.Render(fmt.Sprintf("%-5s", truncateString(a.Asset, 5)))
+	strike := v.rowStyle.Render(fmt.Sprintf("%-12s", truncateString(strikeLabel(a), 12)))
+	date := v.rowStyle.Render(fmt.Sprintf("%-6s", a.ResolutionDate.Format(v.tr.T("arbitrage.date_layout"))))
+
+	yes := v.platformStyle.Render(fmt.Sprintf("%-12s", legLabel(a.YesPlatform, a.YesPrice)))
+	no := v.platformStyle.Render(fmt.Sprintf("%-12s", legLabel(a.NoPlatform, a.NoPrice)))
+	spread := v.rowStyle.Render(fmt.Sprintf("%-7s", fmt.Sprintf("%.1f%%", a.Spread*100)))
+
+	return fmt.Sprintf("%s %s %s %s %s %s %s %s", asset, strike, date, yes, no, spread, v.renderProfit(a, 7), v.renderStatus(a))
+}
+
+// renderCard renders a single opportunity as a stacked card for narrow
+// terminals.
+func (v *ArbitrageView) renderCard(a ArbitrageData) string {
+	heading := fmt.Sprintf("%s %s %s",
+		v.assetStyle.Render(a.Asset),
+		v.rowStyle.Render(strikeLabel(a)),
+		v.rowStyle.Render(a.ResolutionDate.Format(v.tr.T("arbitrage.date_layout"))))
+	legs := fmt.Sprintf("  YES %s  NO %s",
+		v.platformStyle.Render(legLabel(a.YesPlatform, a.YesPrice)),
+		v.platformStyle.Render(legLabel(a.NoPlatform, a.NoPrice)))
+	spread := fmt.Sprintf("  %s %s  %s %s %s",
+		v.headerStyle.Render(v.tr.T("arbitrage.spread")), v.rowStyle.Render(fmt.Sprintf("%.1f%%", a.Spread*100)),
+		v.headerStyle.Render(v.tr.T("arbitrage.profit")), v.renderProfit(a, 0), v.renderStatus(a))
+	return strings.Join([]string{heading, legs, strings.TrimRight(spread, " ")}, "\n")
+}
+
+// renderProfit renders the opportunity's profit, padded to width.
+func (v *ArbitrageView) renderProfit(a ArbitrageData, width int) string {
+	p := a.Profit()
+	if p > 0 {
+		return v.positiveStyle.Render(fmt.Sprintf("%-*s", width, fmt.Sprintf("+%.2f", p)))
+	}
+	return v.neutralStyle.Render(fmt.Sprintf("%-*s", width, fmt.Sprintf("%.2f", p)))
+}
+
+// renderStatus renders the hedge status, empty if not hedged.
+func (v *ArbitrageView) renderStatus(a ArbitrageData) string {
+	if !a.Hedged {
+		return ""
+	}
+	return v.positiveStyle.Render(v.tr.T("arbitrage.hedged"))
+}
+
+// strikeLabel formats the strike with its direction, e.g. ">100000".
+func strikeLabel(a ArbitrageData) string {
+	direction := ">"
+	if a.Direction == "below" {
+		direction = "<"
+	}
+	return fmt.Sprintf("%s%g", direction, a.Strike)
+}
+
+// legLabel formats a leg as platform@price, e.g. "KALSH@0.74".
+func legLabel(platform string, price float64) string {
+	return fmt.Sprintf("%s@%.2f", abbreviatePlatform(platform), price)
+}
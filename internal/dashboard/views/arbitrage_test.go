@@ -0,0 +1,71 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestArbitrageView_RenderOpportunity(t *testing.T) {
+	opportunities := []ArbitrageData{
+		{
+			Asset:          "BTC",
+			Strike:         100000,
+			Direction:      "above",
+			ResolutionDate: time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC),
+			YesPlatform:    "kalshi",
+			YesPrice:       0.74,
+			NoPlatform:     "polymarket",
+			NoPrice:        0.18,
+			Spread:         0.08,
+			Hedged:         true,
+		},
+	}
+
+	output := NewArbitrageView().Render(opportunities, 100)
+
+	for _, want := range []string{"BTC", ">100000", "Jan 18", "KALSH@0.74", "POLY@0.18", "8.0%", "+0.08", "HEDGED"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestArbitrageView_RenderEmpty(t *testing.T) {
+	output := NewArbitrageView().Render(nil, 80)
+
+	if !strings.Contains(output, "No price divergences") {
+		t.Errorf("expected empty state message, got: %s", output)
+	}
+}
+
+func TestArbitrageView_RenderCompactCards(t *testing.T) {
+	opportunities := []ArbitrageData{
+		{
+			Asset:          "BTC",
+			Strike:         100000,
+			Direction:      "above",
+			ResolutionDate: time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC),
+			YesPlatform:    "kalshi",
+			YesPrice:       0.74,
+			NoPlatform:     "polymarket",
+			NoPrice:        0.18,
+			Spread:         0.08,
+		},
+	}
+
+	output := NewArbitrageView().Render(opportunities, 50)
+
+	for _, line := range strings.Split(output, "\n") {
+		if w := lipgloss.Width(line); w > 50 {
+			t.Errorf("expected lines to fit in 50 columns, got %d: %q", w, line)
+		}
+	}
+	for _, want := range []string{"BTC >100000 Jan 18", "YES KALSH@0.74  NO POLY@0.18", "Spread 8.0%  Profit +0.08"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected card to contain %q, got: %s", want, output)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"prediction-bot/internal/i18n"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -36,6 +38,9 @@ type BankrollView struct {
 	positiveStyle lipgloss.Style
 	negativeStyle lipgloss.Style
 	neutralStyle  lipgloss.Style
+	currency      Currency
+	tr            i18n.Translator
+	glyphs        Glyphs
 }
 
 // NewBankrollView creates a new BankrollView with default styles.
@@ -63,15 +68,34 @@ func NewBankrollView() *BankrollView {
 			Foreground(lipgloss.Color("196")), // Red
 		neutralStyle: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")), // Gray
+		currency: NewCurrency(DefaultCurrencyDecimals),
+		tr:       i18n.New(i18n.DefaultLanguage),
+		glyphs:   UnicodeGlyphs(),
 	}
 }
 
+// SetCurrency sets how dollar amounts are formatted.
+func (v *BankrollView) SetCurrency(c Currency) {
+	v.currency = c
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *BankrollView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *BankrollView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
 // Render renders the bankroll view with the given data.
 func (v *BankrollView) Render(data []BankrollData, width int) string {
-	title := v.titleStyle.Render("Bankroll")
+	title := v.titleStyle.Render(v.tr.T("bankroll.title"))
 
 	if len(data) == 0 {
-		content := v.neutralStyle.Render("No bankroll data available")
+		content := v.neutralStyle.Render(v.tr.T("bankroll.empty"))
 		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
 	}
 
@@ -88,9 +112,9 @@ func (v *BankrollView) Render(data []BankrollData, width int) string {
 
 	// Add separator and total if multiple platforms
 	if len(data) > 1 {
-		lines = append(lines, strings.Repeat("─", width-6))
+		lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
 		totalData := BankrollData{
-			Platform:      "Total",
+			Platform:      v.tr.T("bankroll.total"),
 			InitialAmount: totalInitial,
 			CurrentAmount: totalCurrent,
 		}
@@ -108,17 +132,17 @@ func (v *BankrollView) renderPlatformLine(b BankrollData) string {
 	label := v.labelStyle.Render(platform)
 
 	// Format current amount
-	amount := v.valueStyle.Render(fmt.Sprintf("$%.2f", b.CurrentAmount))
+	amount := v.valueStyle.Render(v.currency.Format(b.CurrentAmount))
 
 	// Format delta
 	delta := b.Delta()
 	var deltaStr string
 	if delta > 0 {
-		deltaStr = v.positiveStyle.Render(fmt.Sprintf("+$%.2f", delta))
+		deltaStr = v.positiveStyle.Render(v.currency.FormatSigned(delta))
 	} else if delta < 0 {
-		deltaStr = v.negativeStyle.Render(fmt.Sprintf("-$%.2f", -delta))
+		deltaStr = v.negativeStyle.Render(v.currency.FormatSigned(delta))
 	} else {
-		deltaStr = v.neutralStyle.Render("$0.00")
+		deltaStr = v.neutralStyle.Render(v.currency.Format(0))
 	}
 
 	// Format percent change
@@ -0,0 +1,38 @@
+package views
+
+import "fmt"
+
+// DefaultCurrencyDecimals is the number of decimal places dollar amounts are
+// shown with unless configured otherwise.
+const DefaultCurrencyDecimals = 2
+
+// Currency formats dollar amounts for display.
+type Currency struct {
+	Decimals int
+}
+
+// NewCurrency creates a Currency showing the given number of decimal places.
+// Values of 0 or less use DefaultCurrencyDecimals.
+func NewCurrency(decimals int) Currency {
+	if decimals <= 0 {
+		decimals = DefaultCurrencyDecimals
+	}
+	return Currency{Decimals: decimals}
+}
+
+// Format formats an amount as "$1.23".
+func (c Currency) Format(amount float64) string {
+	if amount < 0 {
+		return fmt.Sprintf("-$%.*f", c.Decimals, -amount)
+	}
+	return fmt.Sprintf("$%.*f", c.Decimals, amount)
+}
+
+// FormatSigned formats an amount with an explicit sign, as "+$1.23" or
+// "-$1.23". Zero is formatted without a sign.
+func (c Currency) FormatSigned(amount float64) string {
+	if amount > 0 {
+		return "+" + c.Format(amount)
+	}
+	return c.Format(amount)
+}
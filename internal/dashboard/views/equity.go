@@ -0,0 +1,151 @@
+package views
+
+import (
+	"fmt"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EquityData represents the bankroll across all platforms over time for
+// display.
+type EquityData struct {
+	// Curve is the total bankroll after each change, oldest first.
+	Curve []float64
+	// DailyPnL is the realized PnL of the trades closed today.
+	DailyPnL float64
+}
+
+// Current returns the latest total bankroll, 0 without a curve.
+func (e EquityData) Current() float64 {
+	if len(e.Curve) == 0 {
+		return 0
+	}
+	return e.Curve[len(e.Curve)-1]
+}
+
+// Peak returns the highest total bankroll on the curve.
+func (e EquityData) Peak() float64 {
+	var peak float64
+	for _, v := range e.Curve {
+		peak = maxFloat(peak, v)
+	}
+	return peak
+}
+
+// Drawdown returns how far the current total is below the peak, as a
+// fraction of the peak.
+func (e EquityData) Drawdown() float64 {
+	peak := e.Peak()
+	if peak <= 0 {
+		return 0
+	}
+	return (peak - e.Current()) / peak
+}
+
+// EquityView renders the equity curve as a sparkline with the drawdown from
+// its peak and the day's PnL.
+type EquityView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	labelStyle    lipgloss.Style
+	valueStyle    lipgloss.Style
+	positiveStyle lipgloss.Style
+	negativeStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	currency      Currency
+	tr            i18n.Translator
+	glyphs        Glyphs
+}
+
+// NewEquityView creates a new EquityView with default styles.
+func NewEquityView() *EquityView {
+	return &EquityView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		labelStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")),
+		valueStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		negativeStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196")), // Red
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		currency: NewCurrency(DefaultCurrencyDecimals),
+		tr:       i18n.New(i18n.DefaultLanguage),
+		glyphs:   UnicodeGlyphs(),
+	}
+}
+
+// SetCurrency sets how dollar amounts are formatted.
+func (v *EquityView) SetCurrency(c Currency) {
+	v.currency = c
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *EquityView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and the sparkline are drawn with.
+func (v *EquityView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders the equity curve across the section, green if it ends at
+// or above where it started and red if below, over the current total, the
+// drawdown from the peak and the day's PnL.
+func (v *EquityView) Render(data EquityData, width int) string {
+	title := v.titleStyle.Render(v.tr.T("equity.title"))
+
+	if len(data.Curve) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("equity.empty"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	curve := Sparkline(data.Curve, width-6, v.glyphs.Spark)
+	if curve != "" {
+		if data.Current() >= data.Curve[0] {
+			curve = v.positiveStyle.Render(curve)
+		} else {
+			curve = v.negativeStyle.Render(curve)
+		}
+	}
+
+	drawdown := v.neutralStyle.Render("0.0%")
+	if dd := data.Drawdown(); dd > 0 {
+		drawdown = v.negativeStyle.Render(fmt.Sprintf("-%.1f%%", dd*100))
+	}
+	daily := v.neutralStyle.Render(v.currency.Format(0))
+	switch {
+	case data.DailyPnL > 0:
+		daily = v.positiveStyle.Render(v.currency.FormatSigned(data.DailyPnL))
+	case data.DailyPnL < 0:
+		daily = v.negativeStyle.Render(v.currency.FormatSigned(data.DailyPnL))
+	}
+
+	summary := fmt.Sprintf("%s %s  %s %s  %s %s  %s %s",
+		v.labelStyle.Render(v.tr.T("equity.current")), v.valueStyle.Render(v.currency.Format(data.Current())),
+		v.labelStyle.Render(v.tr.T("equity.peak")), v.valueStyle.Render(v.currency.Format(data.Peak())),
+		v.labelStyle.Render(v.tr.T("equity.drawdown")), drawdown,
+		v.labelStyle.Render(v.tr.T("equity.today")), daily)
+
+	content := summary
+	if curve != "" {
+		content = curve + "\n" + summary
+	}
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+}
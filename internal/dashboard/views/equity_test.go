@@ -0,0 +1,41 @@
+package views
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEquityData_Drawdown(t *testing.T) {
+	data := EquityData{Curve: []float64{100, 150, 120}}
+	if data.Current() != 120 || data.Peak() != 150 {
+		t.Errorf("expected current 120 and peak 150, got %v and %v", data.Current(), data.Peak())
+	}
+	if got := data.Drawdown(); got < 0.1999 || got > 0.2001 {
+		t.Errorf("expected a 20%% drawdown, got %v", got)
+	}
+	if got := (EquityData{}).Drawdown(); got != 0 {
+		t.Errorf("expected no drawdown without a curve, got %v", got)
+	}
+}
+
+func TestEquityView_Render(t *testing.T) {
+	data := EquityData{Curve: []float64{100, 150, 120}, DailyPnL: 12.5}
+
+	output := NewEquityView().Render(data, 80)
+	for _, want := range []string{"Equity", "$120.00", "$150.00", "-20.0%", "+$12.50"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+	if !strings.ContainsAny(output, "▁▂▃▄▅▆▇█") {
+		t.Errorf("expected a sparkline of the curve, got: %s", output)
+	}
+}
+
+func TestEquityView_RenderEmpty(t *testing.T) {
+	output := NewEquityView().Render(EquityData{}, 80)
+
+	if !strings.Contains(output, "No bankroll history") {
+		t.Errorf("expected empty state message, got: %s", output)
+	}
+}
@@ -0,0 +1,137 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FunnelData represents a platform's latest scan broken down by the stages
+// markets pass on their way to an entry for display.
+type FunnelData struct {
+	Platform  string
+	ScannedAt time.Time
+	Stages    []FunnelStageData
+}
+
+// FunnelStageData represents how many markets reached a stage of the scan
+// funnel, and why those that went no further dropped out.
+type FunnelStageData struct {
+	Name  string // Shown as its "funnel.<name>" label
+	Count int
+	Skips []FunnelSkipData // Most common first
+}
+
+// FunnelSkipData represents how many markets dropped out for a reason.
+type FunnelSkipData struct {
+	Reason string
+	Count  int
+}
+
+// funnelLabelWidth is the width of the stage label column.
+const funnelLabelWidth = 16
+
+// FunnelView renders each platform's latest scan funnel.
+type FunnelView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	headerStyle   lipgloss.Style
+	rowStyle      lipgloss.Style
+	positiveStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	tr            i18n.Translator
+	glyphs        Glyphs
+}
+
+// NewFunnelView creates a new FunnelView with default styles.
+func NewFunnelView() *FunnelView {
+	return &FunnelView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		headerStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("241")),
+		rowStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		tr:     i18n.New(i18n.DefaultLanguage),
+		glyphs: UnicodeGlyphs(),
+	}
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *FunnelView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *FunnelView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders the scan funnel of each platform: how many markets reached
+// each stage, and the reasons those that went no further dropped out,
+// shortened to fit the section.
+func (v *FunnelView) Render(funnels []FunnelData, width int) string {
+	title := v.titleStyle.Render(v.tr.T("funnel.title"))
+
+	if len(funnels) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("funnel.empty"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	// Whatever the label and count columns leave for the skip reasons
+	skipWidth := width - 6 - funnelLabelWidth - 8
+
+	var lines []string
+	for i, f := range funnels {
+		if i > 0 {
+			lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
+		}
+		lines = append(lines, v.headerStyle.Render(v.tr.T("funnel.scanned",
+			abbreviatePlatform(f.Platform), f.ScannedAt.Local().Format("15:04:05"))))
+		for j, s := range f.Stages {
+			lines = append(lines, v.renderStage(s, j == len(f.Stages)-1, skipWidth))
+		}
+	}
+
+	content := strings.Join(lines, "\n")
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+}
+
+// renderStage renders a single stage's row. The last stage's count is
+// highlighted if any market made it through.
+func (v *FunnelView) renderStage(s FunnelStageData, last bool, skipWidth int) string {
+	label := truncateString(v.tr.T("funnel."+s.Name), funnelLabelWidth)
+	countStyle := v.rowStyle
+	if last && s.Count > 0 {
+		countStyle = v.positiveStyle
+	}
+	row := fmt.Sprintf("%s %s",
+		v.rowStyle.Render(fmt.Sprintf("%-*s", funnelLabelWidth, label)),
+		countStyle.Render(fmt.Sprintf("%5d", s.Count)))
+	if len(s.Skips) == 0 || skipWidth <= 0 {
+		return row
+	}
+
+	skips := make([]string, len(s.Skips))
+	for i, skip := range s.Skips {
+		skips[i] = fmt.Sprintf("%s %d", skip.Reason, skip.Count)
+	}
+	return row + "  " + v.neutralStyle.Render(truncateString(strings.Join(skips, ", "), skipWidth))
+}
@@ -0,0 +1,40 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFunnelView_Render(t *testing.T) {
+	funnels := []FunnelData{{
+		Platform:  "kalshi",
+		ScannedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Stages: []FunnelStageData{
+			{Name: "fetched", Count: 120, Skips: []FunnelSkipData{{Reason: "liquidity", Count: 80}, {Reason: "probability", Count: 30}}},
+			{Name: "filtered", Count: 10},
+			{Name: "entered", Count: 0},
+		},
+	}}
+
+	output := NewFunnelView().Render(funnels, 100)
+	for _, want := range []string{"Scan Funnel", "KALSH: scanned at", "Fetched", "120", "liquidity 80, probability 30", "Passed filters", "Entered"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+
+	// Narrow sections shorten the skip reasons
+	output = NewFunnelView().Render(funnels, 50)
+	if !strings.Contains(output, "liquidity 80") || strings.Contains(output, "probability 30") {
+		t.Errorf("expected the skip reasons shortened, got: %s", output)
+	}
+}
+
+func TestFunnelView_RenderEmpty(t *testing.T) {
+	output := NewFunnelView().Render(nil, 80)
+
+	if !strings.Contains(output, "Waiting for the first scan") {
+		t.Errorf("expected empty state message, got: %s", output)
+	}
+}
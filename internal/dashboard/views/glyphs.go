@@ -0,0 +1,29 @@
+package views
+
+import "github.com/charmbracelet/lipgloss"
+
+// Glyphs are the box-drawing characters views are drawn with.
+type Glyphs struct {
+	Border lipgloss.Border
+	Rule   string // Horizontal separator between rows
+	Spark  []rune // Sparkline levels, lowest first
+}
+
+// UnicodeGlyphs draws rounded boxes with line-drawing characters.
+func UnicodeGlyphs() Glyphs {
+	return Glyphs{
+		Border: lipgloss.RoundedBorder(),
+		Rule:   "─",
+		Spark:  []rune("▁▂▃▄▅▆▇█"),
+	}
+}
+
+// ASCIIGlyphs draws boxes in plain ASCII, for limited terminals and output
+// piped to files.
+func ASCIIGlyphs() Glyphs {
+	return Glyphs{
+		Border: lipgloss.ASCIIBorder(),
+		Rule:   "-",
+		Spark:  []rune("_.-~^"),
+	}
+}
@@ -0,0 +1,33 @@
+package views
+
+import (
+	"fmt"
+	"time"
+)
+
+// Section widths at which tables change layout. Sections are two columns
+// narrower than the terminal.
+const (
+	// CompactWidth is the narrowest section a table is drawn in. Narrower
+	// sections show each row as a stacked card.
+	CompactWidth = 74
+	// WideWidth is the section width from which tables show extra columns.
+	WideWidth = 118
+)
+
+// formatDuration formats a duration compactly: "2d3h", "5h12m" or "42m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,138 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PnLData represents a platform's realized PnL over a month or year for
+// display.
+type PnLData struct {
+	Period      string // "2026-03" for a month, "2026" for a year
+	Platform    string
+	Trades      int
+	Wins        int
+	Losses      int
+	Fees        float64
+	RealizedPnL float64 // Net of fees
+	AvgHolding  time.Duration
+}
+
+// PnLView renders a realized PnL report by period and platform.
+type PnLView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	headerStyle   lipgloss.Style
+	rowStyle      lipgloss.Style
+	positiveStyle lipgloss.Style
+	negativeStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	platformStyle lipgloss.Style
+	currency      Currency
+	tr            i18n.Translator
+	glyphs        Glyphs
+}
+
+// NewPnLView creates a new PnLView with default styles.
+func NewPnLView() *PnLView {
+	return &PnLView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		headerStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("241")),
+		rowStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		negativeStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196")), // Red
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		platformStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")), // Blue
+		currency: NewCurrency(DefaultCurrencyDecimals),
+		tr:       i18n.New(i18n.DefaultLanguage),
+		glyphs:   UnicodeGlyphs(),
+	}
+}
+
+// SetCurrency sets how dollar amounts are formatted.
+func (v *PnLView) SetCurrency(c Currency) {
+	v.currency = c
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *PnLView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *PnLView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders the PnL report with the given rows. Sections narrower than
+// CompactWidth leave out the fees and holding time.
+func (v *PnLView) Render(rows []PnLData, width int) string {
+	title := v.titleStyle.Render(v.tr.T("pnl.title"))
+
+	if len(rows) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("pnl.empty"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	full := width >= CompactWidth
+	header := fmt.Sprintf("%-8s %-6s %-7s %-8s %-12s",
+		v.tr.T("pnl.period"), v.tr.T("pnl.platform"), v.tr.T("pnl.trades"),
+		v.tr.T("pnl.win_loss"), v.tr.T("pnl.realized"))
+	if full {
+		header += fmt.Sprintf(" %-10s %s", v.tr.T("pnl.fees"), v.tr.T("pnl.avg_holding"))
+	}
+
+	lines := []string{v.headerStyle.Render(header), strings.Repeat(v.glyphs.Rule, width-6)}
+	for _, r := range rows {
+		lines = append(lines, v.renderRow(r, full))
+	}
+
+	content := strings.Join(lines, "\n")
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+}
+
+// renderRow renders a single period's row.
+func (v *PnLView) renderRow(r PnLData, full bool) string {
+	period := v.rowStyle.Render(fmt.Sprintf("%-8s", r.Period))
+	platform := v.platformStyle.Render(fmt.Sprintf("%-6s", abbreviatePlatform(r.Platform)))
+	trades := v.rowStyle.Render(fmt.Sprintf("%-7d", r.Trades))
+	winLoss := v.rowStyle.Render(fmt.Sprintf("%-8s", fmt.Sprintf("%d/%d", r.Wins, r.Losses)))
+
+	pnlStyle := v.neutralStyle
+	switch {
+	case r.RealizedPnL > 0:
+		pnlStyle = v.positiveStyle
+	case r.RealizedPnL < 0:
+		pnlStyle = v.negativeStyle
+	}
+	pnl := pnlStyle.Render(fmt.Sprintf("%-12s", v.currency.FormatSigned(r.RealizedPnL)))
+
+	row := fmt.Sprintf("%s %s %s %s %s", period, platform, trades, winLoss, pnl)
+	if !full {
+		return strings.TrimRight(row, " ")
+	}
+	fees := v.rowStyle.Render(fmt.Sprintf("%-10s", v.currency.Format(r.Fees)))
+	return fmt.Sprintf("%s %s %s", row, fees, v.rowStyle.Render(formatDuration(r.AvgHolding)))
+}
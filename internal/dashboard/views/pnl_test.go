@@ -0,0 +1,35 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPnLView_RenderRows(t *testing.T) {
+	rows := []PnLData{
+		{Period: "2026-03", Platform: "kalshi", Trades: 3, Wins: 2, Losses: 1, Fees: 0.15, RealizedPnL: 4.2, AvgHolding: 5*time.Hour + 30*time.Minute},
+		{Period: "2026-04", Platform: "polymarket", Trades: 1, Losses: 1, RealizedPnL: -2.5, AvgHolding: 26 * time.Hour},
+	}
+
+	output := NewPnLView().Render(rows, 100)
+	for _, want := range []string{"Realized PnL", "2026-03", "KALSH", "2/1", "+$4.20", "$0.15", "5h30m", "POLY", "-$2.50", "1d2h"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+
+	// Narrow sections leave out fees and holding time
+	output = NewPnLView().Render(rows, 50)
+	if !strings.Contains(output, "+$4.20") || strings.Contains(output, "5h30m") {
+		t.Errorf("expected compact rows without holding time, got: %s", output)
+	}
+}
+
+func TestPnLView_RenderEmpty(t *testing.T) {
+	output := NewPnLView().Render(nil, 80)
+
+	if !strings.Contains(output, "No trades closed") {
+		t.Errorf("expected empty state message, got: %s", output)
+	}
+}
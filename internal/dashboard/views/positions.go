@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"prediction-bot/internal/i18n"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -19,6 +21,12 @@ type PositionData struct {
 	Quantity     float64
 	Side         string
 	EntryTime    time.Time
+	Direction    string    // "above" or "below" the strike
+	SafetyMargin float64   // Volatility safety margin at entry
+	CloseTime    time.Time // Market close time (zero if unknown)
+	// UnderlyingHistory is the underlying asset's price since entry, oldest
+	// first, shown as a sparkline.
+	UnderlyingHistory []float64
 }
 
 // UnrealizedPnL calculates the unrealized profit/loss.
@@ -39,6 +47,33 @@ func (p PositionData) HoldingTime() time.Duration {
 	return time.Since(p.EntryTime)
 }
 
+// TimeToClose returns the duration until the market closes, or 0 if the
+// close time is unknown or has passed.
+func (p PositionData) TimeToClose() time.Duration {
+	if p.CloseTime.IsZero() {
+		return 0
+	}
+	if d := time.Until(p.CloseTime); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Favorable reports whether the underlying has moved in the position's favor
+// since entry: up for YES above or NO below, down otherwise. Returns true
+// without at least two prices.
+func (p PositionData) Favorable() bool {
+	h := p.UnderlyingHistory
+	if len(h) < 2 {
+		return true
+	}
+	bullish := (p.Direction == "below") == (p.Side == "NO")
+	return (h[len(h)-1] >= h[0]) == bullish
+}
+
+// sparkWidth is the number of characters in a position's sparkline.
+const sparkWidth = 8
+
 // PositionsView renders positions information.
 type PositionsView struct {
 	titleStyle    lipgloss.Style
@@ -50,6 +85,11 @@ type PositionsView struct {
 	neutralStyle  lipgloss.Style
 	assetStyle    lipgloss.Style
 	platformStyle lipgloss.Style
+	cursorStyle   lipgloss.Style
+	currency      Currency
+	tr            i18n.Translator
+	glyphs        Glyphs
+	selected      int64
 }
 
 // NewPositionsView creates a new PositionsView with default styles.
@@ -81,60 +121,137 @@ func NewPositionsView() *PositionsView {
 			Foreground(lipgloss.Color("214")), // Orange
 		platformStyle: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("39")), // Blue
+		cursorStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")),
+		currency: NewCurrency(DefaultCurrencyDecimals),
+		tr:       i18n.New(i18n.DefaultLanguage),
+		glyphs:   UnicodeGlyphs(),
 	}
 }
 
+// SetCurrency sets how dollar amounts are formatted. Contract prices are
+// always shown in cents.
+func (v *PositionsView) SetCurrency(c Currency) {
+	v.currency = c
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *PositionsView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *PositionsView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// SetSelected sets the ID of the position highlighted with a cursor, or 0
+// for none. Rows are indented to make room for the cursor while one is set.
+func (v *PositionsView) SetSelected(id int64) {
+	v.selected = id
+}
+
 // Render renders the positions view with the given data.
 func (v *PositionsView) Render(positions []PositionData, width int) string {
-	title := v.titleStyle.Render("Open Positions")
+	title := v.titleStyle.Render(v.tr.T("positions.title"))
 
 	if len(positions) == 0 {
-		content := v.neutralStyle.Render("No open positions")
+		content := v.neutralStyle.Render(v.tr.T("positions.empty"))
 		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
 	}
 
+	wide := width >= WideWidth
+	if width < CompactWidth {
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(v.renderCards(positions)))
+	}
+
 	var lines []string
 
 	// Header
-	header := v.renderHeader()
+	header := v.renderHeader(wide)
+	if v.selected != 0 {
+		header = "  " + header
+	}
 	lines = append(lines, header)
-	lines = append(lines, strings.Repeat("─", width-6))
+	lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
 
 	// Position rows
 	var totalPnL float64
 	for _, pos := range positions {
-		line := v.renderPositionRow(pos, width)
+		line := v.cursor(pos) + v.renderPositionRow(pos, wide)
 		lines = append(lines, line)
 		totalPnL += pos.UnrealizedPnL()
 	}
 
 	// Total PnL
-	lines = append(lines, strings.Repeat("─", width-6))
+	lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
 	lines = append(lines, v.renderTotalPnL(totalPnL))
 
 	content := strings.Join(lines, "\n")
 	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
 }
 
-// renderHeader renders the table header.
-func (v *PositionsView) renderHeader() string {
-	return v.headerStyle.Render(
-		fmt.Sprintf("%-6s %-10s %-5s %-6s %-6s %-8s %-10s",
-			"Plat", "Asset", "Side", "Entry", "Curr", "Qty", "PnL"))
+// renderCards renders each position as a stacked card for narrow terminals.
+func (v *PositionsView) renderCards(positions []PositionData) string {
+	var cards []string
+	var totalPnL float64
+	for _, pos := range positions {
+		heading := v.cursor(pos) + fmt.Sprintf("%s %s %s",
+			v.assetStyle.Render(v.assetLabel(pos)),
+			v.rowStyle.Render(pos.Side),
+			v.platformStyle.Render(abbreviatePlatform(pos.Platform)))
+		if spark := v.renderSparkline(pos); spark != "" {
+			heading += " " + spark
+		}
+		prices := v.rowStyle.Render(fmt.Sprintf("  %s $%.2f  %s $%.2f",
+			v.tr.T("positions.entry"), pos.EntryPrice,
+			v.tr.T("positions.current"), pos.CurrentPrice))
+		pnl := fmt.Sprintf("  %s  %s %s",
+			v.rowStyle.Render(fmt.Sprintf("%s %.1f", v.tr.T("positions.quantity"), pos.Quantity)),
+			v.headerStyle.Render(v.tr.T("positions.pnl")), v.renderPnL(pos.UnrealizedPnL()))
+		cards = append(cards, strings.Join([]string{heading, prices, pnl}, "\n"))
+		totalPnL += pos.UnrealizedPnL()
+	}
+	cards = append(cards, v.renderTotalPnL(totalPnL))
+	return strings.Join(cards, "\n\n")
+}
+
+// cursor returns the cursor for the selected position's row, blank for the
+// others, and nothing while no position is selected.
+func (v *PositionsView) cursor(pos PositionData) string {
+	switch {
+	case v.selected == 0:
+		return ""
+	case pos.ID == v.selected:
+		return v.cursorStyle.Render(">") + " "
+	default:
+		return "  "
+	}
+}
+
+// renderHeader renders the table header. The wide header adds the safety
+// margin and time to close.
+func (v *PositionsView) renderHeader(wide bool) string {
+	header := fmt.Sprintf("%-6s %-10s %-5s %-6s %-6s %-8s %-10s %-*s",
+		v.tr.T("positions.platform"), v.tr.T("positions.asset"), v.tr.T("positions.side"),
+		v.tr.T("positions.entry"), v.tr.T("positions.current"), v.tr.T("positions.quantity"),
+		v.tr.T("positions.pnl"), sparkWidth, v.tr.T("positions.trend"))
+	if wide {
+		header += fmt.Sprintf(" %-7s %s", v.tr.T("positions.margin"), v.tr.T("positions.closes_in"))
+	}
+	return v.headerStyle.Render(header)
 }
 
 // renderPositionRow renders a single position row.
-func (v *PositionsView) renderPositionRow(pos PositionData, width int) string {
+func (v *PositionsView) renderPositionRow(pos PositionData, wide bool) string {
 	// Platform (abbreviated)
 	platform := abbreviatePlatform(pos.Platform)
 	platformStr := v.platformStyle.Render(fmt.Sprintf("%-6s", platform))
 
 	// Asset
-	asset := pos.Asset
-	if asset == "" {
-		asset = truncateTitle(pos.MarketTitle, 10)
-	}
-	assetStr := v.assetStyle.Render(fmt.Sprintf("%-10s", truncateString(asset, 10)))
+	assetStr := v.assetStyle.Render(fmt.Sprintf("%-10s", v.assetLabel(pos)))
 
 	// Side
 	side := v.rowStyle.Render(fmt.Sprintf("%-5s", pos.Side))
@@ -149,34 +266,78 @@ func (v *PositionsView) renderPositionRow(pos PositionData, width int) string {
 	qty := v.rowStyle.Render(fmt.Sprintf("%-8.1f", pos.Quantity))
 
 	// PnL with color
-	pnl := pos.UnrealizedPnL()
-	var pnlStr string
-	if pnl > 0 {
-		pnlStr = v.positiveStyle.Render(fmt.Sprintf("+$%.2f", pnl))
-	} else if pnl < 0 {
-		pnlStr = v.negativeStyle.Render(fmt.Sprintf("-$%.2f", -pnl))
-	} else {
-		pnlStr = v.neutralStyle.Render("$0.00")
+	pnlStr := v.renderPnL(pos.UnrealizedPnL())
+
+	row := fmt.Sprintf("%s %s %s %-6s %-6s %s %s",
+		platformStr, assetStr, side, entry, current, qty, padRight(pnlStr, 10))
+
+	// Underlying price since entry
+	spark := v.renderSparkline(pos)
+	if !wide {
+		return strings.TrimRight(row+" "+spark, " ")
 	}
+	row += " " + padRight(spark, sparkWidth)
 
-	return fmt.Sprintf("%s %s %s %-6s %-6s %s %s",
-		platformStr, assetStr, side, entry, current, qty, pnlStr)
+	margin := "-"
+	if pos.SafetyMargin > 0 {
+		margin = fmt.Sprintf("%.2f", pos.SafetyMargin)
+	}
+	closesIn := "-"
+	if d := pos.TimeToClose(); d > 0 {
+		closesIn = formatDuration(d)
+	}
+	return row + v.rowStyle.Render(fmt.Sprintf(" %-7s %s", margin, closesIn))
+}
+
+// renderSparkline renders the underlying's price since entry, green if it
+// has moved in the position's favor and red if against. Returns an empty
+// string without price history.
+func (v *PositionsView) renderSparkline(pos PositionData) string {
+	spark := Sparkline(pos.UnderlyingHistory, sparkWidth, v.glyphs.Spark)
+	if spark == "" {
+		return ""
+	}
+	if pos.Favorable() {
+		return v.positiveStyle.Render(spark)
+	}
+	return v.negativeStyle.Render(spark)
+}
+
+// assetLabel returns the asset shown for a position, falling back to a short
+// form of the market title.
+func (v *PositionsView) assetLabel(pos PositionData) string {
+	asset := pos.Asset
+	if asset == "" {
+		asset = truncateTitle(pos.MarketTitle, 10)
+	}
+	return truncateString(asset, 10)
+}
+
+// renderPnL renders a PnL amount colored by its sign.
+func (v *PositionsView) renderPnL(pnl float64) string {
+	switch {
+	case pnl > 0:
+		return v.positiveStyle.Render(v.currency.FormatSigned(pnl))
+	case pnl < 0:
+		return v.negativeStyle.Render(v.currency.FormatSigned(pnl))
+	default:
+		return v.neutralStyle.Render(v.currency.Format(0))
+	}
 }
 
 // renderTotalPnL renders the total P&L line.
 func (v *PositionsView) renderTotalPnL(totalPnL float64) string {
-	label := v.headerStyle.Render("Total Unrealized PnL:")
+	label := v.headerStyle.Render(v.tr.T("positions.total_unrealized"))
+	return fmt.Sprintf("%s %s", label, v.renderPnL(totalPnL))
+}
 
-	var pnlStr string
-	if totalPnL > 0 {
-		pnlStr = v.positiveStyle.Render(fmt.Sprintf("+$%.2f", totalPnL))
-	} else if totalPnL < 0 {
-		pnlStr = v.negativeStyle.Render(fmt.Sprintf("-$%.2f", -totalPnL))
-	} else {
-		pnlStr = v.neutralStyle.Render("$0.00")
+// padRight pads a rendered string with spaces to width columns. Unlike
+// fmt's padding, styling escape codes don't count towards the width.
+func padRight(s string, width int) string {
+	if pad := width - lipgloss.Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
 	}
-
-	return fmt.Sprintf("%s %s", label, pnlStr)
+	return s
 }
 
 // abbreviatePlatform returns an abbreviated platform name.
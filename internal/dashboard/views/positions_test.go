@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestPositionsView_RenderSinglePosition(t *testing.T) {
@@ -171,3 +173,103 @@ func TestPositionsView_CalculateUnrealizedPnL_Negative(t *testing.T) {
 		t.Errorf("expected PnL %.2f, got %.2f", expectedPnL, actualPnL)
 	}
 }
+
+func TestPositionsView_RenderCompactCards(t *testing.T) {
+	positions := []PositionData{
+		{
+			Platform:     "kalshi",
+			Asset:        "ETH",
+			EntryPrice:   0.80,
+			CurrentPrice: 0.90,
+			Quantity:     10.0,
+			Side:         "NO",
+		},
+	}
+
+	output := NewPositionsView().Render(positions, 50)
+
+	for _, line := range strings.Split(output, "\n") {
+		if w := lipgloss.Width(line); w > 50 {
+			t.Errorf("expected lines to fit in 50 columns, got %d: %q", w, line)
+		}
+	}
+	for _, want := range []string{"ETH NO KALSH", "Entry $0.80  Curr $0.90", "Qty 10.0  PnL +$1.00"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected card to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestPositionsView_RenderWideColumns(t *testing.T) {
+	positions := []PositionData{
+		{
+			Platform:     "polymarket",
+			Asset:        "BTC",
+			EntryPrice:   0.85,
+			CurrentPrice: 0.90,
+			Quantity:     10.0,
+			Side:         "YES",
+			SafetyMargin: 1.91,
+			CloseTime:    time.Now().Add(5*time.Hour + 30*time.Second),
+		},
+	}
+
+	if output := NewPositionsView().Render(positions, 80); strings.Contains(output, "Margin") {
+		t.Errorf("expected no extra columns at 80 columns, got: %s", output)
+	}
+
+	output := NewPositionsView().Render(positions, 140)
+	for _, want := range []string{"Margin", "Closes In", "1.91", "5h00m"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected wide table to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestPositionsView_RenderSparkline(t *testing.T) {
+	positions := []PositionData{
+		{
+			Platform:          "polymarket",
+			Asset:             "BTC",
+			EntryPrice:        0.85,
+			CurrentPrice:      0.80,
+			Quantity:          10.0,
+			Side:              "YES",
+			Direction:         "above",
+			UnderlyingHistory: []float64{101000, 100500, 100000},
+		},
+	}
+
+	view := NewPositionsView()
+	view.SetGlyphs(ASCIIGlyphs())
+	output := view.Render(positions, 80)
+
+	if !strings.Contains(output, "Trend") || !strings.Contains(output, "^-_") {
+		t.Errorf("expected output to contain trend sparkline, got: %s", output)
+	}
+}
+
+func TestPositionsView_RenderSelected(t *testing.T) {
+	positions := []PositionData{
+		{ID: 1, Platform: "kalshi", Asset: "BTC", EntryPrice: 0.80, CurrentPrice: 0.85, Quantity: 10, Side: "YES"},
+		{ID: 2, Platform: "kalshi", Asset: "ETH", EntryPrice: 0.70, CurrentPrice: 0.65, Quantity: 5, Side: "NO"},
+	}
+
+	view := NewPositionsView()
+	if output := view.Render(positions, 100); strings.Contains(output, "> ") {
+		t.Errorf("expected no cursor without a selection, got: %s", output)
+	}
+
+	view.SetSelected(2)
+	for _, width := range []int{100, 60} {
+		var cursorLine string
+		for _, line := range strings.Split(view.Render(positions, width), "\n") {
+			if strings.Contains(line, "> ") {
+				cursorLine = line
+			}
+		}
+		if !strings.Contains(cursorLine, "ETH") {
+			t.Errorf("width %d: expected the cursor on the selected position, got line %q", width, cursorLine)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Kinds of scan change.
+const (
+	ScanChangeEligible   = "eligible"   // Newly eligible
+	ScanChangeIneligible = "ineligible" // No longer eligible
+	ScanChangePrice      = "price"      // Price moved on a market eligible in both scans
+)
+
+// maxScanChanges is how many of a platform's changes are shown.
+const maxScanChanges = 8
+
+// ScanDiffData represents how a platform's opportunity set changed between
+// its two latest scans for display.
+type ScanDiffData struct {
+	Platform string
+	Previous time.Time // When the previous scan ran
+	Changes  []ScanChangeData
+}
+
+// ScanChangeData represents one market's change between two scans.
+type ScanChangeData struct {
+	Kind           string
+	MarketID       string
+	Title          string
+	Reason         string
+	PreviousReason string
+	PreviousPrice  float64
+	Price          float64
+}
+
+// ScanDiffView renders the changes between each platform's latest scans.
+type ScanDiffView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	headerStyle   lipgloss.Style
+	rowStyle      lipgloss.Style
+	positiveStyle lipgloss.Style
+	negativeStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	platformStyle lipgloss.Style
+	tr            i18n.Translator
+	glyphs        Glyphs
+}
+
+// NewScanDiffView creates a new ScanDiffView with default styles.
+func NewScanDiffView() *ScanDiffView {
+	return &ScanDiffView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		headerStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("241")),
+		rowStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		negativeStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196")), // Red
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		platformStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")), // Blue
+		tr:     i18n.New(i18n.DefaultLanguage),
+		glyphs: UnicodeGlyphs(),
+	}
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *ScanDiffView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *ScanDiffView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders the scan diff of each platform. Sections narrower than
+// CompactWidth shorten market titles further.
+func (v *ScanDiffView) Render(diffs []ScanDiffData, width int) string {
+	title := v.titleStyle.Render(v.tr.T("scan_diff.title"))
+
+	if len(diffs) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("scan_diff.pending"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	titleWidth := 32
+	if width < CompactWidth {
+		titleWidth = 18
+	}
+
+	var lines []string
+	for i, d := range diffs {
+		if i > 0 {
+			lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
+		}
+		lines = append(lines, v.headerStyle.Render(v.tr.T("scan_diff.since",
+			abbreviatePlatform(d.Platform), len(d.Changes), d.Previous.Local().Format("15:04:05"))))
+		if len(d.Changes) == 0 {
+			lines = append(lines, v.neutralStyle.Render(v.tr.T("scan_diff.empty")))
+			continue
+		}
+		for j, c := range d.Changes {
+			if j == maxScanChanges {
+				lines = append(lines, v.neutralStyle.Render(fmt.Sprintf("+%d", len(d.Changes)-maxScanChanges)))
+				break
+			}
+			lines = append(lines, v.renderChange(c, titleWidth))
+		}
+	}
+
+	content := strings.Join(lines, "\n")
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+}
+
+// renderChange renders a single market's change.
+func (v *ScanDiffView) renderChange(c ScanChangeData, titleWidth int) string {
+	label := c.Title
+	if label == "" {
+		label = c.MarketID
+	}
+	market := v.rowStyle.Render(fmt.Sprintf("%-*s", titleWidth, truncateString(label, titleWidth)))
+
+	var sign, detail string
+	style := v.neutralStyle
+	switch c.Kind {
+	case ScanChangeEligible:
+		sign, style, detail = "+", v.positiveStyle, c.Reason
+	case ScanChangeIneligible:
+		sign, style, detail = "-", v.negativeStyle, c.Reason
+	default:
+		sign = "~"
+		detail = fmt.Sprintf("%.2f -> %.2f", c.PreviousPrice, c.Price)
+		switch {
+		case c.Price > c.PreviousPrice:
+			style = v.positiveStyle
+		case c.Price < c.PreviousPrice:
+			style = v.negativeStyle
+		}
+	}
+	if c.Kind != ScanChangePrice && c.PreviousReason != "" {
+		detail += " (" + v.tr.T("scan_diff.was", c.PreviousReason) + ")"
+	}
+
+	return fmt.Sprintf("%s %s %s", style.Render(sign), market, v.rowStyle.Render(detail))
+}
@@ -0,0 +1,49 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanDiffView_RenderChanges(t *testing.T) {
+	diffs := []ScanDiffData{
+		{
+			Platform: "kalshi",
+			Previous: time.Now().Add(-time.Minute),
+			Changes: []ScanChangeData{
+				{Kind: ScanChangeEligible, MarketID: "KXBTC-1", Title: "Bitcoin above $100k?", Reason: "entered", PreviousReason: "probability"},
+				{Kind: ScanChangeIneligible, MarketID: "KXETH-1", Reason: "not_listed", PreviousReason: "volatility_risky"},
+				{Kind: ScanChangePrice, MarketID: "KXBTC-2", PreviousPrice: 0.90, Price: 0.93},
+			},
+		},
+		{Platform: "polymarket", Previous: time.Now().Add(-time.Minute)},
+	}
+
+	output := NewScanDiffView().Render(diffs, 100)
+	for _, want := range []string{"Scan Diff", "KALSH: 3 changes", "Bitcoin above $100k?", "entered (was probability)", "KXETH-1", "not_listed", "0.90 -> 0.93", "POLY: 0 changes", "No changes since the previous scan"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestScanDiffView_RenderLimitsChanges(t *testing.T) {
+	diff := ScanDiffData{Platform: "kalshi"}
+	for i := 0; i < maxScanChanges+3; i++ {
+		diff.Changes = append(diff.Changes, ScanChangeData{Kind: ScanChangeEligible, MarketID: "market", Reason: "entered"})
+	}
+
+	output := NewScanDiffView().Render([]ScanDiffData{diff}, 100)
+	if strings.Count(output, "entered") != maxScanChanges || !strings.Contains(output, "+3") {
+		t.Errorf("expected %d changes and a count of the rest, got: %s", maxScanChanges, output)
+	}
+}
+
+func TestScanDiffView_RenderPending(t *testing.T) {
+	output := NewScanDiffView().Render(nil, 80)
+
+	if !strings.Contains(output, "Waiting for a second scan") {
+		t.Errorf("expected pending state message, got: %s", output)
+	}
+}
@@ -0,0 +1,51 @@
+package views
+
+import "strings"
+
+// Sparkline renders values as a line of width characters, scaled between
+// their minimum and maximum using the given levels. Longer series are
+// sampled down to width, always keeping the latest value. Returns an empty
+// string for fewer than two values.
+func Sparkline(values []float64, width int, levels []rune) string {
+	if len(values) < 2 || width <= 0 || len(levels) == 0 {
+		return ""
+	}
+
+	if len(values) > width {
+		sampled := make([]float64, width)
+		for i := range sampled {
+			sampled[i] = values[(i+1)*len(values)/width-1]
+		}
+		values = sampled
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = minFloat(low, v)
+		high = maxFloat(high, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := len(levels) / 2
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(levels)-1))
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
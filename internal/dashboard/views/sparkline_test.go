@@ -0,0 +1,48 @@
+package views
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	levels := []rune("_.-~^")
+
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"too few values", []float64{1}, 8, ""},
+		{"scaled to range", []float64{10, 12, 14, 16, 18}, 8, "_.-~^"},
+		{"flat line", []float64{5, 5, 5}, 8, "---"},
+		{"sampled to width keeping latest", []float64{1, 9, 2, 8, 3, 7, 4, 6}, 4, "^-._"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width, levels); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPositionData_Favorable(t *testing.T) {
+	rising := []float64{100, 101, 103}
+
+	tests := []struct {
+		direction, side string
+		want            bool
+	}{
+		{"above", "YES", true},
+		{"above", "NO", false},
+		{"below", "YES", false},
+		{"below", "NO", true},
+	}
+
+	for _, tt := range tests {
+		pos := PositionData{Direction: tt.direction, Side: tt.side, UnderlyingHistory: rising}
+		if got := pos.Favorable(); got != tt.want {
+			t.Errorf("Favorable() for %s %s = %v, want %v", tt.side, tt.direction, got, tt.want)
+		}
+	}
+}
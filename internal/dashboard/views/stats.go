@@ -3,6 +3,9 @@ package views
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,7 +18,27 @@ type StatsData struct {
 	TotalPnL      float64
 	RealizedPnL   float64
 	UnrealizedPnL float64
+	Fees          float64 // Platform fees, already included in RealizedPnL
+	GasCost       float64 // On-chain gas, not included in RealizedPnL
+	NetPnL        float64 // RealizedPnL less GasCost
 	MaxDrawdown   float64 // As a decimal (0.15 = 15%)
+	APIHealth     []APIHealthData
+}
+
+// APIHealthData is one platform API's health over the last hour.
+type APIHealthData struct {
+	API        string
+	Calls      int
+	Failures   int // Calls that failed to connect, or were answered 429 or 5xx
+	AvgLatency time.Duration
+}
+
+// FailureRate returns the percentage of calls that failed.
+func (a APIHealthData) FailureRate() float64 {
+	if a.Calls == 0 {
+		return 0
+	}
+	return float64(a.Failures) / float64(a.Calls) * 100
 }
 
 // WinRate calculates the win rate as a percentage.
@@ -36,6 +59,9 @@ type StatsView struct {
 	negativeStyle lipgloss.Style
 	neutralStyle  lipgloss.Style
 	warningStyle  lipgloss.Style
+	currency      Currency
+	tr            i18n.Translator
+	glyphs        Glyphs
 }
 
 // NewStatsView creates a new StatsView with default styles.
@@ -66,12 +92,31 @@ func NewStatsView() *StatsView {
 		warningStyle: lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("214")), // Orange
+		currency: NewCurrency(DefaultCurrencyDecimals),
+		tr:       i18n.New(i18n.DefaultLanguage),
+		glyphs:   UnicodeGlyphs(),
 	}
 }
 
+// SetCurrency sets how dollar amounts are formatted.
+func (v *StatsView) SetCurrency(c Currency) {
+	v.currency = c
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *StatsView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *StatsView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
 // Render renders the stats view with the given data.
 func (v *StatsView) Render(stats StatsData, width int) string {
-	title := v.titleStyle.Render("Statistics")
+	title := v.titleStyle.Render(v.tr.T("stats.title"))
 
 	var lines []string
 
@@ -82,29 +127,46 @@ func (v *StatsView) Render(stats StatsData, width int) string {
 	lines = append(lines, v.renderWinRateRow(stats))
 
 	// Separator
-	lines = append(lines, strings.Repeat("─", width-6))
+	lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
 
 	// PnL rows
-	lines = append(lines, v.renderPnLRow("Total PnL", stats.TotalPnL))
-	lines = append(lines, v.renderPnLRow("Realized", stats.RealizedPnL))
-	lines = append(lines, v.renderPnLRow("Unrealized", stats.UnrealizedPnL))
+	lines = append(lines, v.renderPnLRow(v.tr.T("stats.total_pnl"), stats.TotalPnL))
+	lines = append(lines, v.renderPnLRow(v.tr.T("stats.realized"), stats.RealizedPnL))
+	lines = append(lines, v.renderPnLRow(v.tr.T("stats.unrealized"), stats.UnrealizedPnL))
+
+	// Separator
+	lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
+
+	// Cost rows
+	lines = append(lines, v.renderCostRow(v.tr.T("stats.fees"), stats.Fees))
+	lines = append(lines, v.renderCostRow(v.tr.T("stats.gas"), stats.GasCost))
+	lines = append(lines, v.renderPnLRow(v.tr.T("stats.net_realized"), stats.NetPnL))
 
 	// Separator
-	lines = append(lines, strings.Repeat("─", width-6))
+	lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
 
 	// Drawdown row
 	lines = append(lines, v.renderDrawdownRow(stats))
 
+	// API health rows
+	if len(stats.APIHealth) > 0 {
+		lines = append(lines, strings.Repeat(v.glyphs.Rule, width-6))
+		lines = append(lines, v.labelStyle.Width(0).Render(v.tr.T("stats.api")))
+		for _, api := range stats.APIHealth {
+			lines = append(lines, v.renderAPIRow(api))
+		}
+	}
+
 	content := strings.Join(lines, "\n")
 	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
 }
 
 // renderTradesRow renders the trades count row.
 func (v *StatsView) renderTradesRow(stats StatsData) string {
-	label := v.labelStyle.Render("Trades")
+	label := v.labelStyle.Render(v.tr.T("stats.trades"))
 
-	winsStr := v.positiveStyle.Render(fmt.Sprintf("%dW", stats.WinningTrades))
-	lossesStr := v.negativeStyle.Render(fmt.Sprintf("%dL", stats.LosingTrades))
+	winsStr := v.positiveStyle.Render(v.tr.T("stats.wins", stats.WinningTrades))
+	lossesStr := v.negativeStyle.Render(v.tr.T("stats.losses", stats.LosingTrades))
 	totalStr := v.valueStyle.Render(fmt.Sprintf("%d", stats.TotalTrades))
 
 	return fmt.Sprintf("%s %s (%s / %s)", label, totalStr, winsStr, lossesStr)
@@ -112,7 +174,7 @@ func (v *StatsView) renderTradesRow(stats StatsData) string {
 
 // renderWinRateRow renders the win rate row.
 func (v *StatsView) renderWinRateRow(stats StatsData) string {
-	label := v.labelStyle.Render("Win Rate")
+	label := v.labelStyle.Render(v.tr.T("stats.win_rate"))
 
 	winRate := stats.WinRate()
 	var rateStyle lipgloss.Style
@@ -137,19 +199,52 @@ func (v *StatsView) renderPnLRow(labelText string, pnl float64) string {
 
 	var pnlStr string
 	if pnl > 0 {
-		pnlStr = v.positiveStyle.Render(fmt.Sprintf("+$%.2f", pnl))
+		pnlStr = v.positiveStyle.Render(v.currency.FormatSigned(pnl))
 	} else if pnl < 0 {
-		pnlStr = v.negativeStyle.Render(fmt.Sprintf("-$%.2f", -pnl))
+		pnlStr = v.negativeStyle.Render(v.currency.FormatSigned(pnl))
 	} else {
-		pnlStr = v.neutralStyle.Render("$0.00")
+		pnlStr = v.neutralStyle.Render(v.currency.Format(0))
 	}
 
 	return fmt.Sprintf("%s %s", label, pnlStr)
 }
 
+// renderCostRow renders a cost row, highlighted when non-zero.
+func (v *StatsView) renderCostRow(labelText string, cost float64) string {
+	label := v.labelStyle.Render(labelText)
+
+	costStr := v.neutralStyle.Render(v.currency.Format(0))
+	if cost > 0 {
+		costStr = v.warningStyle.Render(v.currency.Format(cost))
+	}
+
+	return fmt.Sprintf("%s %s", label, costStr)
+}
+
+// renderAPIRow renders a platform API's calls, failure rate and latency.
+func (v *StatsView) renderAPIRow(api APIHealthData) string {
+	label := v.labelStyle.Render(api.API)
+
+	var failedStyle lipgloss.Style
+	switch rate := api.FailureRate(); {
+	case rate >= 5:
+		failedStyle = v.negativeStyle
+	case rate > 0:
+		failedStyle = v.warningStyle
+	default:
+		failedStyle = v.neutralStyle
+	}
+
+	calls := v.valueStyle.Render(v.tr.T("stats.api_calls", api.Calls))
+	failed := failedStyle.Render(v.tr.T("stats.api_failed", api.FailureRate()))
+	latency := v.neutralStyle.Render(fmt.Sprintf("%dms", api.AvgLatency.Milliseconds()))
+
+	return fmt.Sprintf("%s %s  %s  %s", label, calls, failed, latency)
+}
+
 // renderDrawdownRow renders the max drawdown row.
 func (v *StatsView) renderDrawdownRow(stats StatsData) string {
-	label := v.labelStyle.Render("Max Drawdown")
+	label := v.labelStyle.Render(v.tr.T("stats.max_drawdown"))
 
 	drawdownPct := stats.MaxDrawdown * 100
 
@@ -3,6 +3,7 @@ package views
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestStatsView_Render_EmptyStats(t *testing.T) {
@@ -51,6 +52,23 @@ func TestStatsView_Render_WithData(t *testing.T) {
 	}
 }
 
+func TestStatsView_Render_APIHealth(t *testing.T) {
+	view := NewStatsView()
+
+	if result := view.Render(StatsData{}, 60); strings.Contains(result, "API") {
+		t.Errorf("expected no API section without calls, got: %s", result)
+	}
+
+	result := view.Render(StatsData{APIHealth: []APIHealthData{
+		{API: "polymarket", Calls: 400, Failures: 6, AvgLatency: 180 * time.Millisecond},
+	}}, 70)
+	for _, want := range []string{"API (last hour)", "polymarket", "400 calls", "1.5% failed", "180ms"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in output, got: %s", want, result)
+		}
+	}
+}
+
 func TestStatsData_WinRate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -148,3 +166,39 @@ func TestStatsView_Render_DrawdownDisplay(t *testing.T) {
 		t.Errorf("expected drawdown '15' in output, got: %s", result)
 	}
 }
+
+func TestStatsView_Render_CostsDisplay(t *testing.T) {
+	view := NewStatsView()
+	stats := StatsData{
+		TotalTrades: 4,
+		RealizedPnL: 12.00,
+		Fees:        1.25,
+		GasCost:     0.40,
+		NetPnL:      11.60,
+	}
+
+	result := view.Render(stats, 60)
+
+	for _, want := range []string{"Fees", "1.25", "Gas", "0.40", "Net Realized", "+$11.60"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in output, got: %s", want, result)
+		}
+	}
+}
+
+func TestStatsView_Render_CurrencyDecimals(t *testing.T) {
+	view := NewStatsView()
+	view.SetCurrency(NewCurrency(4))
+	stats := StatsData{
+		TotalTrades: 1,
+		TotalPnL:    0.0042,
+		RealizedPnL: 0.0042,
+	}
+
+	result := view.Render(stats, 60)
+
+	// Sub-cent PnL is visible rather than rounded to $0.00
+	if !strings.Contains(result, "+$0.0042") {
+		t.Errorf("expected '+$0.0042' in output, got: %s", result)
+	}
+}
@@ -0,0 +1,181 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TradeData represents a closed position for display.
+type TradeData struct {
+	ID          int64
+	Platform    string
+	MarketTitle string
+	Asset       string
+	Side        string
+	EntryPrice  float64
+	ExitPrice   float64
+	Quantity    float64
+	EntryTime   time.Time
+	ExitTime    time.Time
+	ExitReason  string
+	RealizedPnL float64
+}
+
+// HoldingTime returns how long the position was held, or 0 if either time
+// is unknown.
+func (t TradeData) HoldingTime() time.Duration {
+	if t.EntryTime.IsZero() || t.ExitTime.IsZero() || t.ExitTime.Before(t.EntryTime) {
+		return 0
+	}
+	return t.ExitTime.Sub(t.EntryTime)
+}
+
+// TradesPage is a page of closed trades, most recently closed first.
+type TradesPage struct {
+	Trades []TradeData
+	// Page is the page shown, from 0, of PageSize trades each.
+	Page     int
+	PageSize int
+	// Total is how many trades have closed in all.
+	Total int
+}
+
+// Pages returns how many pages the closed trades fill, at least 1.
+func (p TradesPage) Pages() int {
+	if p.PageSize <= 0 || p.Total <= p.PageSize {
+		return 1
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
+
+// TradesView renders a page of recently closed trades.
+type TradesView struct {
+	titleStyle    lipgloss.Style
+	boxStyle      lipgloss.Style
+	headerStyle   lipgloss.Style
+	rowStyle      lipgloss.Style
+	positiveStyle lipgloss.Style
+	negativeStyle lipgloss.Style
+	neutralStyle  lipgloss.Style
+	platformStyle lipgloss.Style
+	currency      Currency
+	tr            i18n.Translator
+	glyphs        Glyphs
+}
+
+// NewTradesView creates a new TradesView with default styles.
+func NewTradesView() *TradesView {
+	return &TradesView{
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("212")).
+			MarginBottom(1),
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1),
+		headerStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("241")),
+		rowStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")),
+		positiveStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")), // Green
+		negativeStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196")), // Red
+		neutralStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")), // Gray
+		platformStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")), // Blue
+		currency: NewCurrency(DefaultCurrencyDecimals),
+		tr:       i18n.New(i18n.DefaultLanguage),
+		glyphs:   UnicodeGlyphs(),
+	}
+}
+
+// SetCurrency sets how dollar amounts are formatted.
+func (v *TradesView) SetCurrency(c Currency) {
+	v.currency = c
+}
+
+// SetTranslator sets the language labels are shown in.
+func (v *TradesView) SetTranslator(tr i18n.Translator) {
+	v.tr = tr
+}
+
+// SetGlyphs sets the characters boxes and separators are drawn with.
+func (v *TradesView) SetGlyphs(g Glyphs) {
+	v.glyphs = g
+	v.boxStyle = v.boxStyle.Border(g.Border)
+}
+
+// Render renders a page of closed trades and where it falls among them.
+// Sections narrower than CompactWidth leave out the entry and exit prices.
+func (v *TradesView) Render(page TradesPage, width int) string {
+	title := v.titleStyle.Render(v.tr.T("trades.title"))
+
+	if len(page.Trades) == 0 {
+		content := v.neutralStyle.Render(v.tr.T("trades.empty"))
+		return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+	}
+
+	full := width >= CompactWidth
+	header := fmt.Sprintf("%-12s %-6s %-10s %-4s", v.tr.T("trades.closed"), v.tr.T("trades.platform"),
+		v.tr.T("trades.market"), v.tr.T("trades.side"))
+	if full {
+		header += fmt.Sprintf(" %-6s %-6s", v.tr.T("trades.entry"), v.tr.T("trades.exit"))
+	}
+	header += fmt.Sprintf(" %-12s %-6s %s", v.tr.T("trades.reason"), v.tr.T("trades.held"), v.tr.T("trades.pnl"))
+
+	lines := []string{v.headerStyle.Render(header), strings.Repeat(v.glyphs.Rule, width-6)}
+	for _, t := range page.Trades {
+		lines = append(lines, v.renderRow(t, full))
+	}
+	lines = append(lines, v.neutralStyle.Render(v.tr.T("trades.page", page.Page+1, page.Pages(), page.Total)))
+
+	content := strings.Join(lines, "\n")
+	return fmt.Sprintf("%s\n%s", title, v.boxStyle.Width(width-4).Render(content))
+}
+
+// renderRow renders a single trade's row.
+func (v *TradesView) renderRow(t TradeData, full bool) string {
+	closed := "-"
+	if !t.ExitTime.IsZero() {
+		closed = t.ExitTime.Local().Format("Jan 02 15:04")
+	}
+	market := t.Asset
+	if market == "" {
+		market = truncateTitle(t.MarketTitle, 10)
+	}
+
+	row := fmt.Sprintf("%s %s %s %s",
+		v.rowStyle.Render(fmt.Sprintf("%-12s", closed)),
+		v.platformStyle.Render(fmt.Sprintf("%-6s", abbreviatePlatform(t.Platform))),
+		v.rowStyle.Render(fmt.Sprintf("%-10s", truncateString(market, 10))),
+		v.rowStyle.Render(fmt.Sprintf("%-4s", t.Side)))
+	if full {
+		row += v.rowStyle.Render(fmt.Sprintf(" %-6s %-6s", fmt.Sprintf("$%.2f", t.EntryPrice), fmt.Sprintf("$%.2f", t.ExitPrice)))
+	}
+
+	held := "-"
+	if d := t.HoldingTime(); d > 0 {
+		held = formatDuration(d)
+	}
+	row += v.rowStyle.Render(fmt.Sprintf(" %-12s %-6s ", truncateString(t.ExitReason, 12), held))
+
+	pnlStyle := v.neutralStyle
+	switch {
+	case t.RealizedPnL > 0:
+		pnlStyle = v.positiveStyle
+	case t.RealizedPnL < 0:
+		pnlStyle = v.negativeStyle
+	}
+	return row + pnlStyle.Render(v.currency.FormatSigned(t.RealizedPnL))
+}
@@ -0,0 +1,58 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTradesView_RenderPage(t *testing.T) {
+	closed := time.Date(2026, 3, 14, 15, 30, 0, 0, time.Local)
+	page := TradesPage{
+		Trades: []TradeData{
+			{Platform: "kalshi", Asset: "BTC", Side: "YES", EntryPrice: 0.90, ExitPrice: 1.0, EntryTime: closed.Add(-26 * time.Hour), ExitTime: closed, ExitReason: "resolved", RealizedPnL: 0.30},
+			{Platform: "polymarket", MarketTitle: "Will Ethereum reach $5,000?", Side: "NO", EntryPrice: 0.85, ExitPrice: 0.70, EntryTime: closed.Add(-90 * time.Minute), ExitTime: closed, ExitReason: "stop_loss", RealizedPnL: -1.5},
+		},
+		Page:     1,
+		PageSize: 2,
+		Total:    5,
+	}
+
+	output := NewTradesView().Render(page, 100)
+	for _, want := range []string{"Recent Trades", "Mar 14 15:30", "KALSH", "BTC", "$0.90", "$1.00", "resolved", "1d2h", "+$0.30", "POLY", "ETH", "stop_loss", "1h30m", "-$1.50", "Page 2 of 3, 5 trades"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got: %s", want, output)
+		}
+	}
+
+	// Narrow sections leave out entry and exit prices
+	output = NewTradesView().Render(page, 60)
+	if !strings.Contains(output, "+$0.30") || strings.Contains(output, "$0.90") {
+		t.Errorf("expected compact rows without prices, got: %s", output)
+	}
+}
+
+func TestTradesView_RenderEmpty(t *testing.T) {
+	output := NewTradesView().Render(TradesPage{}, 80)
+
+	if !strings.Contains(output, "No trades closed yet") {
+		t.Errorf("expected empty state message, got: %s", output)
+	}
+}
+
+func TestTradesPage_Pages(t *testing.T) {
+	tests := []struct {
+		total, size, want int
+	}{
+		{0, 10, 1},
+		{10, 10, 1},
+		{11, 10, 2},
+		{25, 10, 3},
+		{5, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := (TradesPage{Total: tt.total, PageSize: tt.size}).Pages(); got != tt.want {
+			t.Errorf("Pages() of %d by %d = %d, want %d", tt.total, tt.size, got, tt.want)
+		}
+	}
+}
@@ -1,35 +1,80 @@
 package datasource
 
 import (
+	"errors"
 	"fmt"
 
 	"prediction-bot/internal/datasource/alphavantage"
 	"prediction-bot/internal/datasource/binance"
+	"prediction-bot/internal/datasource/coinbase"
+	"prediction-bot/internal/datasource/polygon"
 	"prediction-bot/pkg/types"
 )
 
+// PriceProvider defines the interface for a source of spot prices and
+// hourly price history.
+type PriceProvider interface {
+	GetPrice(symbol string) (types.Price, error)
+	GetHistory(symbol string, hours int) ([]types.Price, error)
+}
+
+// priceSource is a provider in a fallback chain, with the symbol it uses
+// for each asset.
+type priceSource struct {
+	name     string
+	provider PriceProvider
+	symbol   func(SymbolMapping) string
+}
+
 // Aggregator routes price requests to the appropriate data source.
 type Aggregator struct {
-	mapper     *SymbolMapper
-	binance    *binance.Client
-	alphaVantage *alphavantage.Client
+	mapper *SymbolMapper
+	// crypto and stock are tried in order until a provider succeeds. The
+	// stock providers also serve FX and commodities.
+	crypto []priceSource
+	stock  []priceSource
 }
 
 // NewAggregator creates a new data source aggregator.
 // alphaVantageKey can be empty if Alpha Vantage is not needed.
 func NewAggregator(alphaVantageKey string) *Aggregator {
-	var avClient *alphavantage.Client
+	var stock []priceSource
 	if alphaVantageKey != "" {
-		avClient = alphavantage.NewClientWithKey(alphaVantageKey)
+		stock = append(stock, priceSource{
+			name:     "alphavantage",
+			provider: alphavantage.NewClientWithKey(alphaVantageKey),
+			symbol:   func(m SymbolMapping) string { return m.AlphaSymbol },
+		})
 	}
 
 	return &Aggregator{
-		mapper:       NewSymbolMapper(),
-		binance:      binance.NewClient(),
-		alphaVantage: avClient,
+		mapper: NewSymbolMapper(),
+		crypto: []priceSource{
+			{
+				name:     "binance",
+				provider: binance.NewClient(),
+				symbol:   func(m SymbolMapping) string { return m.BinanceSymbol },
+			},
+			{
+				name:     "coinbase",
+				provider: coinbase.NewClient(),
+				symbol:   func(m SymbolMapping) string { return m.CoinbaseSymbol },
+			},
+		},
+		stock: stock,
 	}
 }
 
+// SetPolygonKey adds Polygon as the first stock, index, FX and metals data
+// source. Indices such as the S&P 500 are only available from Polygon.
+func (a *Aggregator) SetPolygonKey(apiKey string) {
+	a.stock = append([]priceSource{{
+		name:     "polygon",
+		provider: polygon.NewClient(apiKey),
+		symbol:   func(m SymbolMapping) string { return m.PolygonSymbol },
+	}}, a.stock...)
+}
+
 // GetPrice fetches the current price for an asset, routing to the appropriate source.
 func (a *Aggregator) GetPrice(asset string) (types.Price, error) {
 	mapping, ok := a.mapper.Lookup(asset)
@@ -37,33 +82,71 @@ func (a *Aggregator) GetPrice(asset string) (types.Price, error) {
 		return types.Price{}, fmt.Errorf("unknown asset: %s", asset)
 	}
 
-	if mapping.IsCrypto {
-		return a.binance.GetPrice(mapping.BinanceSymbol)
-	}
-
-	if a.alphaVantage == nil {
-		return types.Price{}, fmt.Errorf("Alpha Vantage not configured for asset: %s", asset)
-	}
-
-	return a.alphaVantage.GetPrice(mapping.AlphaSymbol)
+	var price types.Price
+	err := a.try(mapping, func(p PriceProvider, symbol string) error {
+		var err error
+		price, err = p.GetPrice(symbol)
+		return err
+	})
+	return price, err
 }
 
-// GetHistory fetches historical prices for an asset.
-// Currently only supported for crypto assets via Binance.
+// GetHistory fetches hourly historical prices for an asset. Stocks and
+// indices only have prices for market hours, FX and metals for weekdays,
+// and oil one price a day.
 func (a *Aggregator) GetHistory(asset string, hours int) ([]types.Price, error) {
 	mapping, ok := a.mapper.Lookup(asset)
 	if !ok {
 		return nil, fmt.Errorf("unknown asset: %s", asset)
 	}
 
-	if !mapping.IsCrypto {
-		return nil, fmt.Errorf("historical data not supported for stocks yet: %s", asset)
+	var history []types.Price
+	err := a.try(mapping, func(p PriceProvider, symbol string) error {
+		var err error
+		history, err = p.GetHistory(symbol, hours)
+		if err == nil && len(history) == 0 {
+			err = fmt.Errorf("no history for %s", symbol)
+		}
+		return err
+	})
+	return history, err
+}
+
+// try calls fetch with each provider of the asset's class that lists the
+// asset, in order, until one succeeds. Returns the errors of all providers
+// if none do.
+func (a *Aggregator) try(mapping SymbolMapping, fetch func(PriceProvider, string) error) error {
+	sources := a.stock
+	if mapping.IsCrypto {
+		sources = a.crypto
 	}
 
-	return a.binance.GetHistory(mapping.BinanceSymbol, hours)
+	var errs []error
+	for _, source := range sources {
+		symbol := source.symbol(mapping)
+		if symbol == "" {
+			continue
+		}
+		err := fetch(source.provider, symbol)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", source.name, err))
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("no %s provider for asset: %s", a.mapper.AssetClass(mapping.CommonName), mapping.CommonName)
+	}
+	return errors.Join(errs...)
 }
 
 // IsCrypto returns true if the asset is a cryptocurrency.
 func (a *Aggregator) IsCrypto(asset string) bool {
 	return a.mapper.IsCrypto(asset)
 }
+
+// AssetClass returns the asset class of the asset (see AssetClassCrypto),
+// or an empty string if the asset is unknown.
+func (a *Aggregator) AssetClass(asset string) string {
+	return a.mapper.AssetClass(asset)
+}
@@ -1,7 +1,10 @@
 package datasource
 
 import (
+	"errors"
 	"testing"
+
+	"prediction-bot/pkg/types"
 )
 
 func TestAggregator_GetPrice_Bitcoin_RoutesBinance(t *testing.T) {
@@ -111,8 +114,8 @@ func TestSymbolMapper_Lookup(t *testing.T) {
 		{"bitcoin", "BTCUSDT", true},
 		{"BTC", "BTCUSDT", true},
 		{"Ethereum", "ETHUSDT", true},
-		{"S&P 500", "SPY", false},
 		{"SPY", "SPY", false},
+		{"Tesla", "TSLA", false},
 	}
 
 	for _, tc := range testCases {
@@ -134,3 +137,133 @@ func TestSymbolMapper_Lookup(t *testing.T) {
 		}
 	}
 }
+
+// mockCryptoProvider returns a fixed price, or fails if err is set.
+type mockCryptoProvider struct {
+	source  string
+	err     error
+	symbols []string
+}
+
+func (m *mockCryptoProvider) GetPrice(symbol string) (types.Price, error) {
+	m.symbols = append(m.symbols, symbol)
+	if m.err != nil {
+		return types.Price{}, m.err
+	}
+	return types.Price{Symbol: symbol, Price: 100, Source: m.source}, nil
+}
+
+func (m *mockCryptoProvider) GetHistory(symbol string, hours int) ([]types.Price, error) {
+	m.symbols = append(m.symbols, symbol)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []types.Price{{Symbol: symbol, Price: 100, Source: m.source}}, nil
+}
+
+func TestAggregator_CryptoFallsBackToNextProvider(t *testing.T) {
+	primary := &mockCryptoProvider{source: "primary", err: errors.New("rate limited")}
+	secondary := &mockCryptoProvider{source: "secondary"}
+
+	agg := NewAggregator("")
+	agg.crypto = []priceSource{
+		{name: "primary", provider: primary, symbol: func(m SymbolMapping) string { return m.BinanceSymbol }},
+		{name: "secondary", provider: secondary, symbol: func(m SymbolMapping) string { return m.CoinbaseSymbol }},
+	}
+
+	price, err := agg.GetPrice("BTC")
+	if err != nil {
+		t.Fatalf("GetPrice: %v", err)
+	}
+	if price.Source != "secondary" || price.Symbol != "BTC-USD" {
+		t.Errorf("expected secondary BTC-USD price, got %s %s", price.Source, price.Symbol)
+	}
+
+	history, err := agg.GetHistory("ETH", 24)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Symbol != "ETH-USD" {
+		t.Errorf("expected ETH-USD history from secondary, got %+v", history)
+	}
+
+	// Each provider is asked with its own symbol
+	if len(primary.symbols) != 2 || primary.symbols[0] != "BTCUSDT" {
+		t.Errorf("expected primary tried with Binance symbols, got %v", primary.symbols)
+	}
+
+	// All providers failing reports every error
+	secondary.err = errors.New("unavailable")
+	if _, err := agg.GetPrice("BTC"); err == nil {
+		t.Error("expected error when all providers fail, got nil")
+	}
+}
+
+func TestAggregator_StocksRouteToStockProviders(t *testing.T) {
+	agg := NewAggregator("")
+	if _, err := agg.GetPrice("SPX"); err == nil {
+		t.Error("expected error without a stock provider, got nil")
+	}
+
+	polygon := &mockCryptoProvider{source: "polygon"}
+	alpha := &mockCryptoProvider{source: "alphavantage"}
+	agg.stock = []priceSource{
+		{name: "polygon", provider: polygon, symbol: func(m SymbolMapping) string { return m.PolygonSymbol }},
+		{name: "alphavantage", provider: alpha, symbol: func(m SymbolMapping) string { return m.AlphaSymbol }},
+	}
+
+	price, err := agg.GetPrice("S&P 500")
+	if err != nil {
+		t.Fatalf("GetPrice: %v", err)
+	}
+	if price.Source != "polygon" || price.Symbol != "I:SPX" {
+		t.Errorf("expected polygon I:SPX price, got %s %s", price.Source, price.Symbol)
+	}
+
+	polygon.err = errors.New("not entitled")
+	history, err := agg.GetHistory("AAPL", 24)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Source != "alphavantage" {
+		t.Errorf("expected AAPL history from Alpha Vantage, got %+v", history)
+	}
+
+	// Indices have no Alpha Vantage symbol to fall back to
+	if _, err := agg.GetPrice("SPX"); err == nil {
+		t.Error("expected error when Polygon fails for an index, got nil")
+	}
+	if len(alpha.symbols) != 1 {
+		t.Errorf("expected Alpha Vantage only asked for AAPL, got %v", alpha.symbols)
+	}
+}
+
+func TestSymbolMapper_AssetClass(t *testing.T) {
+	m := NewSymbolMapper()
+
+	testCases := []struct {
+		input   string
+		class   string
+		polygon string
+		alpha   string
+	}{
+		{"BTC", AssetClassCrypto, "", ""},
+		{"SPX", AssetClassStock, "I:SPX", ""},
+		{"EUR/USD", AssetClassFX, "C:EURUSD", "EUR/USD"},
+		{"eurusd", AssetClassFX, "C:EURUSD", "EUR/USD"},
+		{"Gold", AssetClassCommodity, "C:XAUUSD", "XAU/USD"},
+		{"WTI", AssetClassCommodity, "", "WTI"},
+		{"Dogecoin", "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := m.AssetClass(tc.input); got != tc.class {
+			t.Errorf("AssetClass(%s): expected %q, got %q", tc.input, tc.class, got)
+		}
+		mapping, _ := m.Lookup(tc.input)
+		if mapping.PolygonSymbol != tc.polygon || mapping.AlphaSymbol != tc.alpha {
+			t.Errorf("Lookup(%s): expected Polygon %q and Alpha %q, got %q and %q",
+				tc.input, tc.polygon, tc.alpha, mapping.PolygonSymbol, mapping.AlphaSymbol)
+		}
+	}
+}
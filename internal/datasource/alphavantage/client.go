@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"prediction-bot/pkg/types"
@@ -15,6 +17,13 @@ const (
 	baseURL = "https://www.alphavantage.co/query"
 )
 
+// commodities lists the commodity series Alpha Vantage publishes as daily
+// spot prices, by symbol.
+var commodities = map[string]bool{
+	"WTI":   true,
+	"BRENT": true,
+}
+
 // Client is an Alpha Vantage API client.
 type Client struct {
 	httpClient *http.Client
@@ -55,39 +64,284 @@ type globalQuoteResponse struct {
 	} `json:"Global Quote"`
 }
 
-// GetPrice fetches the current price for a symbol from Alpha Vantage.
+// GetPrice fetches the current price for a symbol from Alpha Vantage. A
+// "FROM/TO" symbol such as "EUR/USD" is a currency pair, and "WTI" and
+// "BRENT" are the latest daily oil prices.
 func (c *Client) GetPrice(symbol string) (types.Price, error) {
+	if from, to, ok := strings.Cut(symbol, "/"); ok {
+		return c.getExchangeRate(symbol, from, to)
+	}
+	if commodities[symbol] {
+		prices, err := c.getCommodity(symbol)
+		if err != nil {
+			return types.Price{}, err
+		}
+		return prices[len(prices)-1], nil
+	}
+
 	url := fmt.Sprintf("%s?function=GLOBAL_QUOTE&symbol=%s&apikey=%s",
 		baseURL, symbol, c.apiKey)
 
-	resp, err := c.httpClient.Get(url)
+	var quote globalQuoteResponse
+	if err := c.get(url, &quote); err != nil {
+		return types.Price{}, err
+	}
+
+	if quote.GlobalQuote.Symbol == "" {
+		return types.Price{}, fmt.Errorf("empty response (rate limit or invalid symbol)")
+	}
+
+	price, err := strconv.ParseFloat(quote.GlobalQuote.Price, 64)
 	if err != nil {
-		return types.Price{}, fmt.Errorf("http get: %w", err)
+		return types.Price{}, fmt.Errorf("parse price: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return types.Price{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	return types.Price{
+		Symbol:    symbol,
+		Price:     price,
+		Timestamp: time.Now(),
+		Source:    "alphavantage",
+	}, nil
+}
+
+// intradayResponse represents the Alpha Vantage TIME_SERIES_INTRADAY
+// response at a 60 minute interval.
+type intradayResponse struct {
+	MetaData struct {
+		TimeZone string `json:"6. Time Zone"`
+	} `json:"Meta Data"`
+	TimeSeries map[string]struct {
+		Close string `json:"4. close"`
+	} `json:"Time Series (60min)"`
+	Note        string `json:"Note"`
+	Information string `json:"Information"`
+}
+
+// fxIntradayResponse represents the Alpha Vantage FX_INTRADAY response at
+// a 60 minute interval. It differs from intradayResponse only in its keys.
+type fxIntradayResponse struct {
+	MetaData struct {
+		TimeZone string `json:"7. Time Zone"`
+	} `json:"Meta Data"`
+	TimeSeries map[string]struct {
+		Close string `json:"4. close"`
+	} `json:"Time Series FX (60min)"`
+	Note        string `json:"Note"`
+	Information string `json:"Information"`
+}
+
+// GetHistory fetches hourly prices for a symbol over the last hours, oldest
+// first, from the regular trading session. Alpha Vantage serves about a
+// month of intraday history. Currency pairs ("EUR/USD") trade around the
+// clock on weekdays, and oil ("WTI") has one price a day.
+func (c *Client) GetHistory(symbol string, hours int) ([]types.Price, error) {
+	var prices []types.Price
+	var err error
+	if from, to, ok := strings.Cut(symbol, "/"); ok {
+		var series fxIntradayResponse
+		url := fmt.Sprintf("%s?function=FX_INTRADAY&from_symbol=%s&to_symbol=%s&interval=60min&outputsize=full&apikey=%s",
+			baseURL, from, to, c.apiKey)
+		if err := c.get(url, &series); err != nil {
+			return nil, err
+		}
+		prices, err = parseIntraday(intradayResponse(series), symbol)
+	} else if commodities[symbol] {
+		prices, err = c.getCommodity(symbol)
+	} else {
+		var series intradayResponse
+		url := fmt.Sprintf("%s?function=TIME_SERIES_INTRADAY&symbol=%s&interval=60min&outputsize=full&extended_hours=false&apikey=%s",
+			baseURL, symbol, c.apiKey)
+		if err := c.get(url, &series); err != nil {
+			return nil, err
+		}
+		prices, err = parseIntraday(series, symbol)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	var quote globalQuoteResponse
-	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
-		return types.Price{}, fmt.Errorf("decode response: %w", err)
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	start := sort.Search(len(prices), func(i int) bool { return !prices[i].Timestamp.Before(since) })
+	return prices[start:], nil
+}
+
+// parseIntraday converts an intraday response into prices, oldest first.
+// Timestamps are given in the series' time zone.
+func parseIntraday(series intradayResponse, symbol string) ([]types.Price, error) {
+	if len(series.TimeSeries) == 0 {
+		if series.Note != "" || series.Information != "" {
+			return nil, fmt.Errorf("empty response: %s%s", series.Note, series.Information)
+		}
+		return nil, fmt.Errorf("empty response (rate limit or invalid symbol)")
 	}
 
-	if quote.GlobalQuote.Symbol == "" {
+	zone := time.UTC
+	if series.MetaData.TimeZone != "" {
+		loc, err := time.LoadLocation(series.MetaData.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("load time zone: %w", err)
+		}
+		zone = loc
+	}
+
+	prices := make([]types.Price, 0, len(series.TimeSeries))
+	for stamp, bar := range series.TimeSeries {
+		ts, err := time.ParseInLocation("2006-01-02 15:04:05", stamp, zone)
+		if err != nil {
+			return nil, fmt.Errorf("parse timestamp %q: %w", stamp, err)
+		}
+		price, err := strconv.ParseFloat(bar.Close, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse price: %w", err)
+		}
+		prices = append(prices, types.Price{
+			Symbol:    symbol,
+			Price:     price,
+			Timestamp: ts,
+			Source:    "alphavantage",
+		})
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Timestamp.Before(prices[j].Timestamp)
+	})
+	return prices, nil
+}
+
+// exchangeRateResponse represents the Alpha Vantage CURRENCY_EXCHANGE_RATE
+// response.
+type exchangeRateResponse struct {
+	Rate struct {
+		Rate          string `json:"5. Exchange Rate"`
+		LastRefreshed string `json:"6. Last Refreshed"`
+		TimeZone      string `json:"7. Time Zone"`
+	} `json:"Realtime Currency Exchange Rate"`
+	Note        string `json:"Note"`
+	Information string `json:"Information"`
+}
+
+// getExchangeRate fetches the current exchange rate of a currency pair.
+func (c *Client) getExchangeRate(symbol, from, to string) (types.Price, error) {
+	url := fmt.Sprintf("%s?function=CURRENCY_EXCHANGE_RATE&from_currency=%s&to_currency=%s&apikey=%s",
+		baseURL, from, to, c.apiKey)
+
+	var quote exchangeRateResponse
+	if err := c.get(url, &quote); err != nil {
+		return types.Price{}, err
+	}
+	return parseExchangeRate(quote, symbol)
+}
+
+// parseExchangeRate converts an exchange rate response into a price,
+// timestamped when the rate was last refreshed.
+func parseExchangeRate(quote exchangeRateResponse, symbol string) (types.Price, error) {
+	if quote.Rate.Rate == "" {
+		if quote.Note != "" || quote.Information != "" {
+			return types.Price{}, fmt.Errorf("empty response: %s%s", quote.Note, quote.Information)
+		}
 		return types.Price{}, fmt.Errorf("empty response (rate limit or invalid symbol)")
 	}
 
-	price, err := strconv.ParseFloat(quote.GlobalQuote.Price, 64)
+	price, err := strconv.ParseFloat(quote.Rate.Rate, 64)
 	if err != nil {
 		return types.Price{}, fmt.Errorf("parse price: %w", err)
 	}
 
+	zone := time.UTC
+	if quote.Rate.TimeZone != "" {
+		loc, err := time.LoadLocation(quote.Rate.TimeZone)
+		if err != nil {
+			return types.Price{}, fmt.Errorf("load time zone: %w", err)
+		}
+		zone = loc
+	}
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", quote.Rate.LastRefreshed, zone)
+	if err != nil {
+		return types.Price{}, fmt.Errorf("parse timestamp %q: %w", quote.Rate.LastRefreshed, err)
+	}
+
 	return types.Price{
 		Symbol:    symbol,
 		Price:     price,
-		Timestamp: time.Now(),
+		Timestamp: ts,
 		Source:    "alphavantage",
 	}, nil
 }
+
+// commodityResponse represents an Alpha Vantage commodity series response
+// (WTI, BRENT).
+type commodityResponse struct {
+	Data []struct {
+		Date  string `json:"date"`
+		Value string `json:"value"`
+	} `json:"data"`
+	Note        string `json:"Note"`
+	Information string `json:"Information"`
+}
+
+// getCommodity fetches the daily prices of a commodity, oldest first.
+func (c *Client) getCommodity(symbol string) ([]types.Price, error) {
+	url := fmt.Sprintf("%s?function=%s&interval=daily&apikey=%s", baseURL, symbol, c.apiKey)
+
+	var series commodityResponse
+	if err := c.get(url, &series); err != nil {
+		return nil, err
+	}
+	return parseCommodity(series, symbol)
+}
+
+// parseCommodity converts a commodity series into prices, oldest first.
+// Each price is timestamped at the start of its UTC day. Days without a
+// price (given as ".") are skipped.
+func parseCommodity(series commodityResponse, symbol string) ([]types.Price, error) {
+	prices := make([]types.Price, 0, len(series.Data))
+	for _, point := range series.Data {
+		if point.Value == "." {
+			continue
+		}
+		ts, err := time.Parse("2006-01-02", point.Date)
+		if err != nil {
+			return nil, fmt.Errorf("parse date %q: %w", point.Date, err)
+		}
+		price, err := strconv.ParseFloat(point.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse price: %w", err)
+		}
+		prices = append(prices, types.Price{
+			Symbol:    symbol,
+			Price:     price,
+			Timestamp: ts,
+			Source:    "alphavantage",
+		})
+	}
+
+	if len(prices) == 0 {
+		if series.Note != "" || series.Information != "" {
+			return nil, fmt.Errorf("empty response: %s%s", series.Note, series.Information)
+		}
+		return nil, fmt.Errorf("empty response (rate limit or invalid symbol)")
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Timestamp.Before(prices[j].Timestamp)
+	})
+	return prices, nil
+}
+
+// get fetches url and decodes its JSON response into v.
+func (c *Client) get(url string, v any) error {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
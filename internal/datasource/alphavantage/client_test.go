@@ -3,6 +3,7 @@ package alphavantage
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestGetPrice_SPY_ReturnsPositivePrice(t *testing.T) {
@@ -55,3 +56,87 @@ func TestNewClientWithKey_CreatesClient(t *testing.T) {
 		t.Errorf("expected api key 'test-key', got '%s'", client.apiKey)
 	}
 }
+
+func TestParseIntraday_SortsAndUsesSeriesTimeZone(t *testing.T) {
+	var series intradayResponse
+	series.MetaData.TimeZone = "US/Eastern"
+	series.TimeSeries = map[string]struct {
+		Close string `json:"4. close"`
+	}{
+		"2026-01-20 15:00:00": {Close: "601.25"},
+		"2026-01-20 14:00:00": {Close: "600.50"},
+	}
+
+	prices, err := parseIntraday(series, "SPY")
+	if err != nil {
+		t.Fatalf("parseIntraday: %v", err)
+	}
+
+	if len(prices) != 2 {
+		t.Fatalf("expected 2 prices, got %d", len(prices))
+	}
+	if prices[0].Price != 600.50 || prices[1].Price != 601.25 {
+		t.Errorf("expected prices oldest first, got %v then %v", prices[0].Price, prices[1].Price)
+	}
+	// 14:00 EST is 19:00 UTC
+	if got := prices[0].Timestamp.UTC().Hour(); got != 19 {
+		t.Errorf("expected 19:00 UTC, got %d:00", got)
+	}
+}
+
+func TestParseIntraday_RateLimited_ReturnsError(t *testing.T) {
+	series := intradayResponse{Note: "Thank you for using Alpha Vantage!"}
+	if _, err := parseIntraday(series, "SPY"); err == nil {
+		t.Error("expected error for an empty series, got nil")
+	}
+}
+
+func TestParseExchangeRate_UsesLastRefreshed(t *testing.T) {
+	var quote exchangeRateResponse
+	quote.Rate.Rate = "1.08420000"
+	quote.Rate.LastRefreshed = "2026-01-20 14:05:01"
+	quote.Rate.TimeZone = "UTC"
+
+	price, err := parseExchangeRate(quote, "EUR/USD")
+	if err != nil {
+		t.Fatalf("parseExchangeRate: %v", err)
+	}
+	if price.Price != 1.0842 || price.Symbol != "EUR/USD" {
+		t.Errorf("expected EUR/USD at 1.0842, got %+v", price)
+	}
+	if want := time.Date(2026, 1, 20, 14, 5, 1, 0, time.UTC); !price.Timestamp.Equal(want) {
+		t.Errorf("expected timestamp %s, got %s", want, price.Timestamp)
+	}
+
+	if _, err := parseExchangeRate(exchangeRateResponse{Information: "premium endpoint"}, "EUR/USD"); err == nil {
+		t.Error("expected error for an empty response, got nil")
+	}
+}
+
+func TestParseCommodity_SortsAndSkipsMissingDays(t *testing.T) {
+	var series commodityResponse
+	series.Data = []struct {
+		Date  string `json:"date"`
+		Value string `json:"value"`
+	}{
+		{Date: "2026-01-20", Value: "71.35"},
+		{Date: "2026-01-19", Value: "."},
+		{Date: "2026-01-16", Value: "70.10"},
+	}
+
+	prices, err := parseCommodity(series, "WTI")
+	if err != nil {
+		t.Fatalf("parseCommodity: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("expected 2 prices, got %d", len(prices))
+	}
+	if prices[0].Price != 70.10 || prices[1].Price != 71.35 {
+		t.Errorf("expected prices oldest first, got %v then %v", prices[0].Price, prices[1].Price)
+	}
+
+	series.Data = series.Data[1:2]
+	if _, err := parseCommodity(series, "WTI"); err == nil {
+		t.Error("expected error for a series without prices, got nil")
+	}
+}
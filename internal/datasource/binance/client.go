@@ -12,6 +12,9 @@ import (
 
 const (
 	baseURL = "https://api.binance.com/api/v3"
+
+	// maxKlines is the most klines Binance returns per request.
+	maxKlines = 1000
 )
 
 // Client is a Binance API client.
@@ -66,15 +69,32 @@ func (c *Client) GetPrice(symbol string) (types.Price, error) {
 	}, nil
 }
 
-// GetHistory fetches historical hourly prices (klines) for a symbol.
-// hours specifies how many hourly data points to fetch (max 1000 per request).
+// GetHistory fetches historical hourly prices (klines) for a symbol,
+// oldest first. Requests are paged since Binance returns at most 1000
+// klines per request.
 func (c *Client) GetHistory(symbol string, hours int) ([]types.Price, error) {
-	if hours > 1000 {
-		hours = 1000 // Binance limit
+	if hours <= maxKlines {
+		return c.klines(fmt.Sprintf("%s/klines?symbol=%s&interval=1h&limit=%d", baseURL, symbol, hours), symbol)
 	}
 
-	url := fmt.Sprintf("%s/klines?symbol=%s&interval=1h&limit=%d", baseURL, symbol, hours)
+	end := time.Now()
+	start := end.Truncate(time.Hour).Add(-time.Duration(hours-1) * time.Hour)
+
+	var prices []types.Price
+	for pageStart := start; pageStart.Before(end); pageStart = pageStart.Add(maxKlines * time.Hour) {
+		page, err := c.klines(fmt.Sprintf("%s/klines?symbol=%s&interval=1h&startTime=%d&limit=%d",
+			baseURL, symbol, pageStart.UnixMilli(), maxKlines), symbol)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, page...)
+	}
+	return prices, nil
+}
 
+// klines fetches a page of klines and converts them to prices at each
+// kline's close, timestamped with its open.
+func (c *Client) klines(url, symbol string) ([]types.Price, error) {
 	resp, err := c.httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("http get: %w", err)
@@ -0,0 +1,143 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+const (
+	baseURL = "https://api.exchange.coinbase.com"
+
+	// maxCandles is the most candles Coinbase returns per request.
+	maxCandles = 300
+)
+
+// Client is a Coinbase Exchange public market data client.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a new Coinbase client.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// tickerResponse represents the Coinbase product ticker response.
+type tickerResponse struct {
+	Price string `json:"price"`
+}
+
+// GetPrice fetches the current price for a product (e.g. "BTC-USD").
+func (c *Client) GetPrice(symbol string) (types.Price, error) {
+	body, err := c.get(fmt.Sprintf("%s/products/%s/ticker", baseURL, symbol))
+	if err != nil {
+		return types.Price{}, err
+	}
+
+	var ticker tickerResponse
+	if err := json.Unmarshal(body, &ticker); err != nil {
+		return types.Price{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	price, err := strconv.ParseFloat(ticker.Price, 64)
+	if err != nil {
+		return types.Price{}, fmt.Errorf("parse price: %w", err)
+	}
+
+	return types.Price{
+		Symbol:    symbol,
+		Price:     price,
+		Timestamp: time.Now(),
+		Source:    "coinbase",
+	}, nil
+}
+
+// GetHistory fetches historical hourly prices (candles) for a product,
+// oldest first. Requests are paged since Coinbase returns at most 300
+// candles per request.
+func (c *Client) GetHistory(symbol string, hours int) ([]types.Price, error) {
+	end := time.Now().Truncate(time.Hour)
+	start := end.Add(-time.Duration(hours) * time.Hour)
+
+	var prices []types.Price
+	for pageStart := start; pageStart.Before(end); pageStart = pageStart.Add(maxCandles * time.Hour) {
+		pageEnd := pageStart.Add(maxCandles * time.Hour)
+		if pageEnd.After(end) {
+			pageEnd = end
+		}
+
+		url := fmt.Sprintf("%s/products/%s/candles?granularity=3600&start=%s&end=%s",
+			baseURL, symbol, pageStart.UTC().Format(time.RFC3339), pageEnd.UTC().Format(time.RFC3339))
+		body, err := c.get(url)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := parseCandles(body, symbol)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, page...)
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Timestamp.Before(prices[j].Timestamp)
+	})
+
+	return prices, nil
+}
+
+// get performs a GET request and returns the body of a successful response.
+func (c *Client) get(url string) ([]byte, error) {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return body, nil
+}
+
+// parseCandles converts a candles response into prices at each candle's
+// close. Each candle is [time, low, high, open, close, volume], newest first.
+func parseCandles(body []byte, symbol string) ([]types.Price, error) {
+	var candles [][]float64
+	if err := json.Unmarshal(body, &candles); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	prices := make([]types.Price, 0, len(candles))
+	for _, candle := range candles {
+		if len(candle) < 5 {
+			continue
+		}
+
+		prices = append(prices, types.Price{
+			Symbol:    symbol,
+			Price:     candle[4],
+			Timestamp: time.Unix(int64(candle[0]), 0),
+			Source:    "coinbase",
+		})
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,33 @@
+package coinbase
+
+import (
+	"testing"
+)
+
+func TestParseCandles_UsesClosePrice(t *testing.T) {
+	body := []byte(`[
+		[1700003600, 36900.5, 37200.0, 37000.0, 37150.25, 120.5],
+		[1700000000, 36800.0, 37050.0, 36850.0, 37000.0, 98.1]
+	]`)
+
+	prices, err := parseCandles(body, "BTC-USD")
+	if err != nil {
+		t.Fatalf("parseCandles: %v", err)
+	}
+
+	if len(prices) != 2 {
+		t.Fatalf("expected 2 prices, got %d", len(prices))
+	}
+	if prices[0].Price != 37150.25 || prices[0].Timestamp.Unix() != 1700003600 {
+		t.Errorf("expected close 37150.25 at 1700003600, got %f at %d", prices[0].Price, prices[0].Timestamp.Unix())
+	}
+	if prices[1].Source != "coinbase" || prices[1].Symbol != "BTC-USD" {
+		t.Errorf("expected coinbase BTC-USD price, got %s %s", prices[1].Source, prices[1].Symbol)
+	}
+}
+
+func TestParseCandles_InvalidBody_ReturnsError(t *testing.T) {
+	if _, err := parseCandles([]byte(`{"message":"NotFound"}`), "XYZ-USD"); err == nil {
+		t.Error("expected error for non-array response, got nil")
+	}
+}
@@ -2,12 +2,25 @@ package datasource
 
 import "strings"
 
+// Asset classes of mapped assets.
+const (
+	AssetClassCrypto    = "crypto"
+	AssetClassStock     = "stock"
+	AssetClassFX        = "fx"
+	AssetClassCommodity = "commodity"
+)
+
 // SymbolMapping contains the mapping from a common name to exchange symbols.
 type SymbolMapping struct {
-	CommonName    string
-	BinanceSymbol string
-	AlphaSymbol   string
-	IsCrypto      bool
+	CommonName     string
+	BinanceSymbol  string
+	CoinbaseSymbol string
+	AlphaSymbol    string
+	PolygonSymbol  string // "I:" prefixed for indices, "C:" for currencies
+	IsCrypto       bool
+	// Class is the asset class of non-crypto assets other than stocks and
+	// indices (AssetClassFX, AssetClassCommodity). Empty for the rest.
+	Class string
 }
 
 // SymbolMapper maps common asset names to exchange-specific symbols.
@@ -21,59 +34,132 @@ func NewSymbolMapper() *SymbolMapper {
 		mappings: make(map[string]SymbolMapping),
 	}
 
-	// Cryptocurrencies (Binance)
+	// Cryptocurrencies (Binance, Coinbase)
 	m.addMapping(SymbolMapping{
-		CommonName:    "Bitcoin",
-		BinanceSymbol: "BTCUSDT",
-		IsCrypto:      true,
+		CommonName:     "Bitcoin",
+		BinanceSymbol:  "BTCUSDT",
+		CoinbaseSymbol: "BTC-USD",
+		IsCrypto:       true,
 	})
 	m.addMapping(SymbolMapping{
-		CommonName:    "BTC",
-		BinanceSymbol: "BTCUSDT",
-		IsCrypto:      true,
+		CommonName:     "BTC",
+		BinanceSymbol:  "BTCUSDT",
+		CoinbaseSymbol: "BTC-USD",
+		IsCrypto:       true,
 	})
 	m.addMapping(SymbolMapping{
-		CommonName:    "Ethereum",
-		BinanceSymbol: "ETHUSDT",
-		IsCrypto:      true,
+		CommonName:     "Ethereum",
+		BinanceSymbol:  "ETHUSDT",
+		CoinbaseSymbol: "ETH-USD",
+		IsCrypto:       true,
 	})
 	m.addMapping(SymbolMapping{
-		CommonName:    "ETH",
-		BinanceSymbol: "ETHUSDT",
-		IsCrypto:      true,
+		CommonName:     "ETH",
+		BinanceSymbol:  "ETHUSDT",
+		CoinbaseSymbol: "ETH-USD",
+		IsCrypto:       true,
 	})
 	m.addMapping(SymbolMapping{
-		CommonName:    "Solana",
-		BinanceSymbol: "SOLUSDT",
-		IsCrypto:      true,
+		CommonName:     "Solana",
+		BinanceSymbol:  "SOLUSDT",
+		CoinbaseSymbol: "SOL-USD",
+		IsCrypto:       true,
 	})
 	m.addMapping(SymbolMapping{
-		CommonName:    "SOL",
-		BinanceSymbol: "SOLUSDT",
-		IsCrypto:      true,
+		CommonName:     "SOL",
+		BinanceSymbol:  "SOLUSDT",
+		CoinbaseSymbol: "SOL-USD",
+		IsCrypto:       true,
 	})
 
-	// Stocks/ETFs (Alpha Vantage)
-	m.addMapping(SymbolMapping{
-		CommonName:  "S&P 500",
-		AlphaSymbol: "SPY",
-		IsCrypto:    false,
-	})
-	m.addMapping(SymbolMapping{
-		CommonName:  "SPY",
-		AlphaSymbol: "SPY",
-		IsCrypto:    false,
-	})
-	m.addMapping(SymbolMapping{
-		CommonName:  "Nasdaq",
-		AlphaSymbol: "QQQ",
-		IsCrypto:    false,
-	})
-	m.addMapping(SymbolMapping{
-		CommonName:  "QQQ",
-		AlphaSymbol: "QQQ",
-		IsCrypto:    false,
-	})
+	// Stock indices (Polygon; Alpha Vantage has no index quotes)
+	for _, name := range []string{"SPX", "S&P 500"} {
+		m.addMapping(SymbolMapping{
+			CommonName:    name,
+			PolygonSymbol: "I:SPX",
+		})
+	}
+	for _, name := range []string{"NDX", "Nasdaq 100", "Nasdaq"} {
+		m.addMapping(SymbolMapping{
+			CommonName:    name,
+			PolygonSymbol: "I:NDX",
+		})
+	}
+
+	// Stocks/ETFs (Polygon, Alpha Vantage), by ticker and by name
+	stocks := []struct{ ticker, name string }{
+		{"SPY", ""},
+		{"QQQ", ""},
+		{"AAPL", "Apple"},
+		{"MSFT", "Microsoft"},
+		{"NVDA", "Nvidia"},
+		{"AMZN", "Amazon"},
+		{"GOOGL", "Alphabet"},
+		{"META", "Meta"},
+		{"TSLA", "Tesla"},
+	}
+	for _, stock := range stocks {
+		for _, name := range []string{stock.ticker, stock.name} {
+			if name == "" {
+				continue
+			}
+			m.addMapping(SymbolMapping{
+				CommonName:    name,
+				AlphaSymbol:   stock.ticker,
+				PolygonSymbol: stock.ticker,
+			})
+		}
+	}
+
+	// Currency pairs (Polygon, Alpha Vantage), by pair with and without a
+	// slash. Alpha Vantage symbols are "FROM/TO" pairs.
+	pairs := []struct{ pair, name string }{
+		{"EUR/USD", "Euro"},
+		{"GBP/USD", "Pound"},
+		{"USD/JPY", "Yen"},
+	}
+	for _, pair := range pairs {
+		from, to, _ := strings.Cut(pair.pair, "/")
+		for _, name := range []string{pair.pair, from + to, pair.name} {
+			m.addMapping(SymbolMapping{
+				CommonName:    name,
+				AlphaSymbol:   pair.pair,
+				PolygonSymbol: "C:" + from + to,
+				Class:         AssetClassFX,
+			})
+		}
+	}
+
+	// Commodities. Precious metals trade as currency pairs against the
+	// dollar (Polygon, Alpha Vantage); oil only as Alpha Vantage's daily
+	// spot series.
+	metals := []struct{ symbol, name string }{
+		{"XAU", "Gold"},
+		{"XAG", "Silver"},
+	}
+	for _, metal := range metals {
+		for _, name := range []string{metal.symbol, metal.name} {
+			m.addMapping(SymbolMapping{
+				CommonName:    name,
+				AlphaSymbol:   metal.symbol + "/USD",
+				PolygonSymbol: "C:" + metal.symbol + "USD",
+				Class:         AssetClassCommodity,
+			})
+		}
+	}
+	oils := []struct{ symbol, name string }{
+		{"WTI", "Oil"},
+		{"BRENT", "Brent"},
+	}
+	for _, oil := range oils {
+		for _, name := range []string{oil.symbol, oil.name} {
+			m.addMapping(SymbolMapping{
+				CommonName:  name,
+				AlphaSymbol: oil.symbol,
+				Class:       AssetClassCommodity,
+			})
+		}
+	}
 
 	return m
 }
@@ -100,3 +186,19 @@ func (m *SymbolMapper) IsCrypto(commonName string) bool {
 	}
 	return mapping.IsCrypto
 }
+
+// AssetClass returns the asset class of the asset, or an empty string if the
+// asset is unknown.
+func (m *SymbolMapper) AssetClass(commonName string) string {
+	mapping, ok := m.Lookup(commonName)
+	if !ok {
+		return ""
+	}
+	if mapping.IsCrypto {
+		return AssetClassCrypto
+	}
+	if mapping.Class != "" {
+		return mapping.Class
+	}
+	return AssetClassStock
+}
@@ -0,0 +1,124 @@
+// Package polygon is a Polygon.io market data client for stock, index and
+// currency prices.
+package polygon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+const (
+	baseURL = "https://api.polygon.io"
+
+	// quoteLookback is how far back the latest minute bar is searched for,
+	// enough to span a long weekend.
+	quoteLookback = 4 * 24 * time.Hour
+)
+
+// Client is a Polygon.io aggregates client. Symbols are Polygon tickers:
+// "AAPL" for a stock, "I:SPX" for an index, "C:EURUSD" for a currency pair
+// or "C:XAUUSD" for a precious metal.
+type Client struct {
+	httpClient *http.Client
+	apiKey     string
+}
+
+// NewClient creates a new Polygon client with an API key.
+func NewClient(apiKey string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		apiKey: apiKey,
+	}
+}
+
+// aggregatesResponse represents the Polygon aggregates (bars) response.
+type aggregatesResponse struct {
+	Status  string `json:"status"`
+	Error   string `json:"error"`
+	Results []struct {
+		Close     float64 `json:"c"`
+		Timestamp int64   `json:"t"` // Bar start, Unix milliseconds
+	} `json:"results"`
+}
+
+// GetPrice fetches the latest price for a symbol: the close of its most
+// recent minute bar. Outside market hours this is the last session's final
+// price, and on plans with delayed data it lags the market.
+func (c *Client) GetPrice(symbol string) (types.Price, error) {
+	now := time.Now()
+	prices, err := c.aggregates(symbol, "minute", now.Add(-quoteLookback), now, "desc", 1)
+	if err != nil {
+		return types.Price{}, err
+	}
+	if len(prices) == 0 {
+		return types.Price{}, fmt.Errorf("no recent price for %s", symbol)
+	}
+	return prices[0], nil
+}
+
+// GetHistory fetches hourly prices for a symbol over the last hours,
+// oldest first. Stocks and indices only trade in market hours, so there are
+// fewer prices than hours.
+func (c *Client) GetHistory(symbol string, hours int) ([]types.Price, error) {
+	now := time.Now()
+	return c.aggregates(symbol, "hour", now.Add(-time.Duration(hours)*time.Hour), now, "asc", 50000)
+}
+
+// aggregates fetches the bars of a symbol between from and to.
+func (c *Client) aggregates(symbol, timespan string, from, to time.Time, sort string, limit int) ([]types.Price, error) {
+	query := url.Values{}
+	query.Set("adjusted", "true")
+	query.Set("sort", sort)
+	query.Set("limit", fmt.Sprint(limit))
+	query.Set("apiKey", c.apiKey)
+
+	u := fmt.Sprintf("%s/v2/aggs/ticker/%s/range/1/%s/%d/%d?%s",
+		baseURL, url.PathEscape(symbol), timespan, from.UnixMilli(), to.UnixMilli(), query.Encode())
+
+	resp, err := c.httpClient.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return parseAggregates(body, symbol)
+}
+
+// parseAggregates converts an aggregates response into prices at each bar's
+// close, timestamped with the bar's start.
+func parseAggregates(body []byte, symbol string) ([]types.Price, error) {
+	var response aggregatesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if response.Status == "ERROR" || response.Status == "NOT_AUTHORIZED" {
+		return nil, fmt.Errorf("polygon error: %s", response.Error)
+	}
+
+	prices := make([]types.Price, 0, len(response.Results))
+	for _, bar := range response.Results {
+		prices = append(prices, types.Price{
+			Symbol:    symbol,
+			Price:     bar.Close,
+			Timestamp: time.UnixMilli(bar.Timestamp),
+			Source:    "polygon",
+		})
+	}
+	return prices, nil
+}
@@ -0,0 +1,45 @@
+package polygon
+
+import (
+	"testing"
+)
+
+func TestParseAggregates_UsesClosePrice(t *testing.T) {
+	body := []byte(`{
+		"ticker": "I:SPX",
+		"status": "OK",
+		"results": [
+			{"o": 6001.5, "h": 6010.0, "l": 5995.25, "c": 6005.75, "t": 1768917600000},
+			{"o": 6005.75, "h": 6020.0, "l": 6003.0, "c": 6018.5, "t": 1768921200000}
+		]
+	}`)
+
+	prices, err := parseAggregates(body, "I:SPX")
+	if err != nil {
+		t.Fatalf("parseAggregates: %v", err)
+	}
+
+	if len(prices) != 2 {
+		t.Fatalf("expected 2 prices, got %d", len(prices))
+	}
+	if prices[1].Price != 6018.5 || prices[1].Timestamp.UnixMilli() != 1768921200000 {
+		t.Errorf("expected close 6018.5 at 1768921200000, got %f at %d", prices[1].Price, prices[1].Timestamp.UnixMilli())
+	}
+	if prices[0].Source != "polygon" || prices[0].Symbol != "I:SPX" {
+		t.Errorf("expected polygon I:SPX price, got %s %s", prices[0].Source, prices[0].Symbol)
+	}
+}
+
+func TestParseAggregates_ErrorStatus_ReturnsError(t *testing.T) {
+	body := []byte(`{"status": "NOT_AUTHORIZED", "error": "You are not entitled to this data."}`)
+	if _, err := parseAggregates(body, "I:SPX"); err == nil {
+		t.Error("expected error for unauthorized response, got nil")
+	}
+}
+
+func TestParseAggregates_NoResults_ReturnsEmpty(t *testing.T) {
+	prices, err := parseAggregates([]byte(`{"status": "OK", "resultsCount": 0}`), "AAPL")
+	if err != nil || len(prices) != 0 {
+		t.Errorf("expected no prices, got %v (%v)", prices, err)
+	}
+}
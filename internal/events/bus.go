@@ -0,0 +1,102 @@
+// Package events is an in-process event bus: the bot publishes what it is
+// doing as it happens, and displays such as the dashboard subscribe instead
+// of waiting to see the results in the database.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type identifies what happened.
+type Type string
+
+const (
+	// ScanStarted is published when the bot starts scanning a platform.
+	ScanStarted Type = "scan_started"
+	// MarketEligible is published for each market a scan finds eligible.
+	MarketEligible Type = "market_eligible"
+	// EntrySkipped is published when the bot decides not to enter an
+	// eligible market, with the reason as Detail.
+	EntrySkipped Type = "entry_skipped"
+	// PositionOpened is published when the bot enters a market.
+	PositionOpened Type = "position_opened"
+	// ScanCompleted is published when the bot finishes scanning a platform,
+	// with the number of eligible markets as Count.
+	ScanCompleted Type = "scan_completed"
+	// ScanFailed is published when scanning a platform fails, with the
+	// error as Detail.
+	ScanFailed Type = "scan_failed"
+)
+
+// Event is something the bot did.
+type Event struct {
+	Type     Type
+	Time     time.Time
+	Platform string
+	MarketID string
+	Title    string
+	Side     string
+	Price    float64
+	Size     float64 // Position size in dollars, for PositionOpened
+	Count    int
+	Detail   string
+}
+
+// Bus delivers published events to every subscriber. Publishing never
+// blocks: a subscriber that falls behind by more than its buffer misses
+// events rather than stalling the bot.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	now         func() time.Time
+}
+
+// NewBus creates an event bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[chan Event]struct{}),
+		now:         time.Now,
+	}
+}
+
+// Publish delivers event to the subscribers, stamping its time if unset.
+// Publishing to a nil bus does nothing, so publishers don't need one.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = b.now()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the events published from now on,
+// buffering up to buffer of them, and a function that unsubscribes and
+// closes the channel.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
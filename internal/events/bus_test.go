@@ -0,0 +1,58 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBus_DeliversToSubscribers(t *testing.T) {
+	bus := NewBus()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	bus.now = func() time.Time { return now }
+
+	first, unsubscribeFirst := bus.Subscribe(4)
+	second, unsubscribeSecond := bus.Subscribe(4)
+	defer unsubscribeSecond()
+
+	bus.Publish(Event{Type: MarketEligible, MarketID: "m1"})
+
+	for _, ch := range []<-chan Event{first, second} {
+		event := <-ch
+		if event.Type != MarketEligible || event.MarketID != "m1" || !event.Time.Equal(now) {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, open := <-first; open {
+		t.Error("expected channel closed after unsubscribing")
+	}
+	bus.Publish(Event{Type: ScanCompleted})
+	if event := <-second; event.Type != ScanCompleted {
+		t.Errorf("expected remaining subscriber to receive events, got %+v", event)
+	}
+}
+
+func TestBus_DropsEventsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	bus.Publish(Event{Type: ScanStarted})
+	bus.Publish(Event{Type: ScanCompleted})
+
+	if event := <-ch; event.Type != ScanStarted {
+		t.Errorf("expected the buffered event, got %+v", event)
+	}
+	select {
+	case event := <-ch:
+		t.Errorf("expected the overflowing event to be dropped, got %+v", event)
+	default:
+	}
+}
+
+func TestBus_NilPublishIsNoOp(t *testing.T) {
+	var bus *Bus
+	bus.Publish(Event{Type: ScanStarted})
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Attached is sent to each client of a Server as it connects, ahead of the
+// bus's events, with the bot's mode as Detail (ModeDryRun or ModeLive).
+const Attached Type = "attached"
+
+// Modes a bot reports to attached clients.
+const (
+	ModeDryRun = "dry_run"
+	ModeLive   = "live"
+)
+
+// socketBuffer is how many events a client can fall behind by before it
+// misses some, as for any subscriber.
+const socketBuffer = 100
+
+// Server streams a bus's events to the clients of a unix socket, such as a
+// dashboard attached to a running bot, one JSON object per line.
+type Server struct {
+	bus  *Bus
+	mode string
+}
+
+// NewServer creates a server streaming bus's events, announcing the bot's
+// mode (ModeDryRun or ModeLive) to each client.
+func NewServer(bus *Bus, mode string) *Server {
+	return &Server{bus: bus, mode: mode}
+}
+
+// Listen listens on a unix socket at path, replacing a socket left behind
+// by a bot that didn't shut down cleanly. Only the current user can
+// connect.
+func Listen(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("restrict socket permissions: %w", err)
+	}
+	return ln, nil
+}
+
+// Serve streams events to each client that connects to ln until ctx is
+// cancelled, then closes ln and disconnects the clients.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept connection: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.stream(ctx, conn)
+		}()
+	}
+}
+
+// stream writes events to a client until it disconnects or ctx is
+// cancelled.
+func (s *Server) stream(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	ch, unsubscribe := s.bus.Subscribe(socketBuffer)
+	defer unsubscribe()
+
+	// Reading only returns once the client hangs up
+	hungUp := make(chan struct{})
+	go func() {
+		conn.Read(make([]byte, 1))
+		close(hungUp)
+	}()
+
+	enc := json.NewEncoder(conn)
+	if err := enc.Encode(Event{Type: Attached, Time: s.bus.now(), Detail: s.mode}); err != nil {
+		return
+	}
+	for {
+		select {
+		case event := <-ch:
+			if err := enc.Encode(event); err != nil {
+				log.Debug().Err(err).Msg("event client disconnected")
+				return
+			}
+		case <-hungUp:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Dial connects to a Server's socket at path and returns a channel
+// receiving its events, buffering up to buffer of them. The channel is
+// closed once the server goes away or ctx is cancelled.
+func Dial(ctx context.Context, path string, buffer int) (<-chan Event, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", path, err)
+	}
+
+	ch := make(chan Event, buffer)
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		defer close(ch)
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var event Event
+			if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+				log.Warn().Err(err).Msg("skipping malformed event")
+				continue
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Warn().Err(err).Msg("event stream ended")
+		}
+	}()
+	return ch, nil
+}
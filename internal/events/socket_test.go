@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServer_StreamsEvents(t *testing.T) {
+	dir, err := os.MkdirTemp("", "events")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bot.sock")
+
+	// A socket left behind by a previous run is replaced
+	stale, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	if l, ok := stale.(interface{ SetUnlinkOnClose(bool) }); ok {
+		l.SetUnlinkOnClose(false)
+	}
+	stale.Close()
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("expected the stale socket replaced, got %v", err)
+	}
+	bus := NewBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- NewServer(bus, ModeLive).Serve(ctx, ln) }()
+
+	ch, err := Dial(context.Background(), path, 4)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	receive := func() Event {
+		select {
+		case event := <-ch:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for an event")
+			return Event{}
+		}
+	}
+
+	if event := receive(); event.Type != Attached || event.Detail != ModeLive {
+		t.Fatalf("expected the bot's mode first, got %+v", event)
+	}
+	bus.Publish(Event{Type: ScanStarted, Platform: "kalshi"})
+	if event := receive(); event.Type != ScanStarted || event.Platform != "kalshi" {
+		t.Errorf("expected the published event, got %+v", event)
+	}
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("expected Serve to stop cleanly, got %v", err)
+	}
+	if _, open := <-ch; open {
+		t.Error("expected the channel closed once the server stopped")
+	}
+	if _, err := Dial(context.Background(), path, 4); err == nil {
+		t.Error("expected no server listening after it stopped")
+	}
+}
@@ -0,0 +1,91 @@
+// Package experiment runs champion/challenger parameter experiments: a
+// share of the markets is scanned with a challenger set of parameters
+// instead of the live ones (the champion), and the positions entered in
+// either arm are flagged with it, so learning can compare the two before
+// the challenger's parameters are promoted.
+package experiment
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"prediction-bot/internal/config"
+)
+
+// Arms a market can be assigned.
+const (
+	ArmChampion   = "champion"
+	ArmChallenger = "challenger"
+)
+
+// Overridable are the parameters a challenger may override: the scan
+// filter thresholds, which decide what is entered. Sizing and exits apply
+// to every position alike.
+var Overridable = []string{
+	"probability_threshold",
+	"min_liquidity",
+	"min_volume",
+	"min_hours_to_close",
+	"max_hours_to_close",
+	"max_spread",
+}
+
+// Experiment assigns markets to the champion or challenger arm.
+type Experiment struct {
+	name      string
+	share     float64
+	overrides map[string]float64
+}
+
+// New creates an experiment called name assigning share (between 0 and 1,
+// exclusive) of the markets to a challenger with overrides, by parameter
+// name (see Overridable).
+func New(name string, share float64, overrides map[string]float64) (*Experiment, error) {
+	if name == "" {
+		return nil, fmt.Errorf("experiment needs a name")
+	}
+	if share <= 0 || share >= 1 {
+		return nil, fmt.Errorf("experiment share must be between 0 and 1, got %v", share)
+	}
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("experiment challenger overrides no parameters")
+	}
+	allowed := make(map[string]bool, len(Overridable))
+	for _, param := range Overridable {
+		allowed[param] = true
+	}
+	params := make([]string, 0, len(overrides))
+	for param := range overrides {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	for _, param := range params {
+		if !allowed[param] {
+			return nil, fmt.Errorf("experiment challenger can't override %q, only %v", param, Overridable)
+		}
+	}
+	return &Experiment{name: name, share: share, overrides: overrides}, nil
+}
+
+// Name returns the experiment's name, which positions are flagged with.
+func (e *Experiment) Name() string {
+	return e.name
+}
+
+// Arm returns the arm a platform's market is assigned to. Assignment is a
+// hash of the experiment name and the market, so a market stays in its arm
+// across scans and restarts, and each experiment draws its own split.
+func (e *Experiment) Arm(platform, marketID string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", e.name, platform, marketID)
+	if float64(h.Sum64()%10000)/10000 < e.share {
+		return ArmChallenger
+	}
+	return ArmChampion
+}
+
+// Challenger returns params with the challenger's overrides applied.
+func (e *Experiment) Challenger(params config.Parameters) config.Parameters {
+	return params.WithValues(e.overrides)
+}
@@ -0,0 +1,55 @@
+package experiment
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"prediction-bot/internal/config"
+)
+
+func TestNew_Validates(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		share     float64
+		overrides map[string]float64
+		err       string
+	}{
+		{"", 0.2, map[string]float64{"probability_threshold": 0.85}, "needs a name"},
+		{"t", 0, map[string]float64{"probability_threshold": 0.85}, "between 0 and 1"},
+		{"t", 1, map[string]float64{"probability_threshold": 0.85}, "between 0 and 1"},
+		{"t", 0.2, nil, "no parameters"},
+		{"t", 0.2, map[string]float64{"kelly_fraction": 0.5}, `"kelly_fraction"`},
+	} {
+		if _, err := New(tc.name, tc.share, tc.overrides); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("New(%q, %v, %v): expected error containing %q, got %v", tc.name, tc.share, tc.overrides, tc.err, err)
+		}
+	}
+}
+
+func TestExperiment_ArmIsStableAndSplitsByShare(t *testing.T) {
+	e, err := New("threshold-85", 0.25, map[string]float64{"probability_threshold": 0.85})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	challengers := 0
+	for i := 0; i < 4000; i++ {
+		id := fmt.Sprintf("market-%d", i)
+		arm := e.Arm("polymarket", id)
+		if again := e.Arm("polymarket", id); again != arm {
+			t.Fatalf("expected %s to stay in %s, got %s", id, arm, again)
+		}
+		if arm == ArmChallenger {
+			challengers++
+		}
+	}
+	if share := float64(challengers) / 4000; share < 0.22 || share > 0.28 {
+		t.Errorf("expected about 25%% challengers, got %.1f%%", share*100)
+	}
+
+	params := e.Challenger(config.Parameters{ProbabilityThreshold: 0.80, MinLiquidity: 100})
+	if params.ProbabilityThreshold != 0.85 || params.MinLiquidity != 100 {
+		t.Errorf("expected only the threshold overridden, got %+v", params)
+	}
+}
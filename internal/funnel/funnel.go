@@ -0,0 +1,145 @@
+// Package funnel breaks a platform's latest scan cycle down into the stages
+// markets pass on their way to an entry: fetched, passed the eligibility
+// filters, parseable, volatility-valid, sized and entered, with why the
+// markets that dropped out at each stage did.
+package funnel
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/scanner"
+)
+
+// Stages of the funnel, in order.
+const (
+	StageFetched    = "fetched"
+	StageFiltered   = "filtered"
+	StageParseable  = "parseable"
+	StageVolatility = "volatility"
+	StageSized      = "sized"
+	StageEntered    = "entered"
+)
+
+// Stages are the funnel's stages, in order.
+var Stages = []string{StageFetched, StageFiltered, StageParseable, StageVolatility, StageSized, StageEntered}
+
+// dropped maps the reasons a market passed the filters but wasn't entered
+// to the last stage it reached. Reasons not listed, such as the portfolio
+// and VaR limits or an unfilled entry, are those of markets that were sized.
+var dropped = map[string]string{
+	scanner.RejectionUnparseable:           StageFiltered,
+	scanner.RejectionSideUnavailable:       StageParseable,
+	scanner.RejectionBlackout:              StageParseable,
+	position.SkipReasonStrikeNotSelected:   StageParseable,
+	position.SkipReasonDuplicate:           StageParseable,
+	position.SkipReasonVolatilityReject:    StageParseable,
+	position.SkipReasonVolatilityRisky:     StageParseable,
+	position.SkipReasonInsufficientFunds:   StageVolatility,
+	position.SkipReasonAllocationExhausted: StageVolatility,
+	position.SkipReasonSizingNoEdge:        StageVolatility,
+	position.SkipReasonSizingTooSmall:      StageVolatility,
+	position.SkipReasonSizingCorrelated:    StageVolatility,
+}
+
+// Skip is how many markets dropped out for a reason.
+type Skip struct {
+	Reason string
+	Count  int
+}
+
+// Stage is how many markets reached a stage of the funnel.
+type Stage struct {
+	Name  string
+	Count int
+	// Skips count the markets that reached the stage but not the next by
+	// reason, most common first. A market failing several eligibility
+	// criteria is counted under each of them.
+	Skips []Skip
+}
+
+// Funnel is a platform's scan cycle broken down by stage.
+type Funnel struct {
+	Platform  string
+	ScannedAt time.Time
+	Stages    []Stage // One for each of Stages, in order
+}
+
+// Build breaks a scan snapshot's decisions down by stage.
+func Build(snapshot *persistence.ScanSnapshot) Funnel {
+	index := make(map[string]int, len(Stages))
+	stages := make([]Stage, len(Stages))
+	skips := make([]map[string]int, len(Stages))
+	for i, name := range Stages {
+		index[name] = i
+		stages[i].Name = name
+		skips[i] = make(map[string]int)
+	}
+
+	for _, d := range snapshot.Decisions {
+		reached := reachedStage(d)
+		last := index[reached]
+		for i := 0; i <= last; i++ {
+			stages[i].Count++
+		}
+		if reached == StageEntered {
+			continue
+		}
+		for _, reason := range strings.Split(d.Reason, ",") {
+			skips[last][reason]++
+		}
+	}
+
+	for i := range stages {
+		for reason, count := range skips[i] {
+			stages[i].Skips = append(stages[i].Skips, Skip{Reason: reason, Count: count})
+		}
+		sort.Slice(stages[i].Skips, func(a, b int) bool {
+			sa, sb := stages[i].Skips[a], stages[i].Skips[b]
+			if sa.Count != sb.Count {
+				return sa.Count > sb.Count
+			}
+			return sa.Reason < sb.Reason
+		})
+	}
+
+	return Funnel{Platform: snapshot.Platform, ScannedAt: snapshot.ScannedAt, Stages: stages}
+}
+
+// reachedStage returns the last stage a market's decision reached.
+func reachedStage(d *persistence.ScanDecision) string {
+	switch {
+	case !d.Eligible:
+		return StageFetched
+	case d.Reason == persistence.ScanDecisionEntered:
+		return StageEntered
+	}
+	if stage, ok := dropped[d.Reason]; ok {
+		return stage
+	}
+	return StageSized
+}
+
+// Latest breaks down each platform's latest scan, in platform name order.
+func Latest(repo *persistence.ScanSnapshotRepository) ([]Funnel, error) {
+	platforms, err := repo.GetPlatforms()
+	if err != nil {
+		return nil, err
+	}
+
+	var funnels []Funnel
+	for _, platform := range platforms {
+		snapshots, err := repo.GetLatest(platform, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshots) == 0 {
+			continue
+		}
+		funnels = append(funnels, Build(snapshots[0]))
+	}
+	return funnels, nil
+}
@@ -0,0 +1,94 @@
+package funnel
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+func TestBuild(t *testing.T) {
+	snapshot := &persistence.ScanSnapshot{
+		Platform:  "kalshi",
+		ScannedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Decisions: []*persistence.ScanDecision{
+			{MarketID: "a", Reason: "probability,liquidity"},
+			{MarketID: "b", Reason: "liquidity"},
+			{MarketID: "c", Eligible: true, Reason: "unparseable"},
+			{MarketID: "d", Eligible: true, Reason: "volatility_risky"},
+			{MarketID: "e", Eligible: true, Reason: "sizing_no_edge"},
+			{MarketID: "f", Eligible: true, Reason: "portfolio_limit"},
+			{MarketID: "g", Eligible: true, Reason: "entered"},
+		},
+	}
+
+	f := Build(snapshot)
+	if f.Platform != "kalshi" || !f.ScannedAt.Equal(snapshot.ScannedAt) {
+		t.Errorf("unexpected funnel header %+v", f)
+	}
+	var counts []int
+	for _, s := range f.Stages {
+		counts = append(counts, s.Count)
+	}
+	if want := []int{7, 5, 4, 3, 2, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected stage counts %v, got %v", want, counts)
+	}
+
+	want := [][]Skip{
+		{{Reason: "liquidity", Count: 2}, {Reason: "probability", Count: 1}},
+		{{Reason: "unparseable", Count: 1}},
+		{{Reason: "volatility_risky", Count: 1}},
+		{{Reason: "sizing_no_edge", Count: 1}},
+		{{Reason: "portfolio_limit", Count: 1}},
+		nil,
+	}
+	for i, s := range f.Stages {
+		if s.Name != Stages[i] || !reflect.DeepEqual(s.Skips, want[i]) {
+			t.Errorf("stage %d: expected %s skipping %v, got %+v", i, Stages[i], want[i], s)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_funnel_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := persistence.OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := persistence.NewScanSnapshotRepository(db)
+	record := func(platform string, decisions ...*persistence.ScanDecision) {
+		if err := repo.Record(platform, decisions); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	record("kalshi", &persistence.ScanDecision{MarketID: "KXBTC-1", Reason: "probability"})
+	record("kalshi", &persistence.ScanDecision{MarketID: "KXBTC-1", Eligible: true, Reason: "entered"})
+	record("polymarket")
+
+	funnels, err := Latest(repo)
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if len(funnels) != 2 || funnels[0].Platform != "kalshi" || funnels[1].Platform != "polymarket" {
+		t.Fatalf("expected a funnel for each platform, got %+v", funnels)
+	}
+	if entered := funnels[0].Stages[len(Stages)-1].Count; entered != 1 {
+		t.Errorf("expected kalshi's latest scan to have entered KXBTC-1, got %d entered", entered)
+	}
+	if fetched := funnels[1].Stages[0].Count; fetched != 0 {
+		t.Errorf("expected nothing fetched on polymarket, got %d", fetched)
+	}
+}
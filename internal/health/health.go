@@ -0,0 +1,190 @@
+// Package health checks that the bot is alive and able to trade: that its
+// scan and monitor cycles are running on schedule, its database answers and
+// the platform APIs are reachable. The checks are served at /healthz, and
+// the liveness ones feed systemd's watchdog, so orchestration can restart a
+// stuck bot.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// probeTimeout is how long a platform API gets to answer a probe.
+const probeTimeout = 10 * time.Second
+
+// Names of the checks.
+const (
+	CheckScan     = "scan"
+	CheckMonitor  = "monitor"
+	CheckDatabase = "database"
+)
+
+// Cycles reports when the bot's cycles last succeeded, such as a bot.Bot.
+type Cycles interface {
+	LastScan() time.Time
+	LastMonitor() time.Time
+}
+
+// Pinger checks a database connection, such as a *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Platform is a platform API probed for reachability by listing a market.
+type Platform interface {
+	Name() string
+	ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error)
+}
+
+// Thresholds are how stale the bot's cycles can get before it is unhealthy,
+// and how often the platform APIs are probed. Zero disables a staleness
+// check.
+type Thresholds struct {
+	ScanStale     time.Duration
+	MonitorStale  time.Duration
+	ProbeInterval time.Duration
+}
+
+// Check is the result of one of the checks.
+type Check struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the result of all the checks. Live is whether the cycle and
+// database checks pass: platform reachability doesn't count, as restarting
+// the bot can't fix a platform outage.
+type Report struct {
+	Healthy     bool      `json:"healthy"`
+	Live        bool      `json:"live"`
+	CheckedAt   time.Time `json:"checked_at"`
+	LastScan    time.Time `json:"last_scan"`
+	LastMonitor time.Time `json:"last_monitor"`
+	Checks      []Check   `json:"checks"`
+}
+
+// probe is the result of a platform API's last probe.
+type probe struct {
+	at  time.Time
+	err error
+}
+
+// Checker checks the bot's health.
+type Checker struct {
+	cycles     Cycles
+	db         Pinger
+	platforms  []Platform
+	thresholds Thresholds
+	started    time.Time
+	now        func() time.Time
+
+	// mu guards the probe results, and serializes probes so concurrent
+	// checks don't probe a platform twice.
+	mu     sync.Mutex
+	probes map[string]probe
+}
+
+// NewChecker creates a checker of cycles and db (nil skips the database
+// check). Cycles are only stale once the thresholds pass after it is
+// created, so a bot still starting up is healthy.
+func NewChecker(cycles Cycles, db Pinger, thresholds Thresholds) *Checker {
+	if thresholds.ProbeInterval <= 0 {
+		thresholds.ProbeInterval = time.Minute
+	}
+	return &Checker{
+		cycles:     cycles,
+		db:         db,
+		thresholds: thresholds,
+		started:    time.Now(),
+		now:        time.Now,
+		probes:     make(map[string]probe),
+	}
+}
+
+// AddPlatform probes a platform's API for reachability, at most once every
+// ProbeInterval.
+func (c *Checker) AddPlatform(p Platform) {
+	c.platforms = append(c.platforms, p)
+}
+
+// Check runs the checks. The bot is healthy if all of them pass.
+func (c *Checker) Check(ctx context.Context) Report {
+	now := c.now()
+	report := Report{
+		CheckedAt:   now.UTC(),
+		LastScan:    c.cycles.LastScan(),
+		LastMonitor: c.cycles.LastMonitor(),
+	}
+
+	report.Checks = append(report.Checks,
+		c.checkCycle(CheckScan, report.LastScan, c.thresholds.ScanStale, now),
+		c.checkCycle(CheckMonitor, report.LastMonitor, c.thresholds.MonitorStale, now))
+	if c.db != nil {
+		check := Check{Name: CheckDatabase, OK: true}
+		if err := c.db.PingContext(ctx); err != nil {
+			check = Check{Name: CheckDatabase, Detail: fmt.Sprintf("ping database: %v", err)}
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	report.Live = allOK(report.Checks)
+	for _, p := range c.platforms {
+		check := Check{Name: p.Name(), OK: true}
+		if err := c.probe(ctx, p, now); err != nil {
+			check = Check{Name: p.Name(), Detail: fmt.Sprintf("list markets: %v", err)}
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	report.Healthy = allOK(report.Checks)
+	return report
+}
+
+// allOK reports whether all checks passed.
+func allOK(checks []Check) bool {
+	for _, check := range checks {
+		if !check.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCycle checks that a cycle succeeded within stale.
+func (c *Checker) checkCycle(name string, last time.Time, stale time.Duration, now time.Time) Check {
+	if stale <= 0 {
+		return Check{Name: name, OK: true}
+	}
+	since := last
+	if since.IsZero() {
+		since = c.started
+	}
+	if idle := now.Sub(since); idle >= stale {
+		return Check{Name: name, Detail: fmt.Sprintf("no successful %s cycle in %s", name, idle.Round(time.Second))}
+	}
+	return Check{Name: name, OK: true}
+}
+
+// probe returns the error of a platform's latest probe, probing it again
+// if the latest is older than ProbeInterval.
+func (c *Checker) probe(ctx context.Context, p Platform, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last, probed := c.probes[p.Name()]
+	if probed && now.Sub(last.at) < c.thresholds.ProbeInterval {
+		return last.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	_, err := p.ListMarkets(ctx, types.MarketFilter{Limit: 1})
+
+	c.probes[p.Name()] = probe{at: now, err: err}
+	return err
+}
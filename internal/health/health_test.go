@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// fakeCycles reports fixed cycle times.
+type fakeCycles struct {
+	scan, monitor time.Time
+}
+
+func (c *fakeCycles) LastScan() time.Time    { return c.scan }
+func (c *fakeCycles) LastMonitor() time.Time { return c.monitor }
+
+// fakePinger fails pings with err.
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) PingContext(ctx context.Context) error { return p.err }
+
+// fakePlatform counts the probes it answers, failing them with err.
+type fakePlatform struct {
+	err    error
+	probes int
+}
+
+func (p *fakePlatform) Name() string { return "kalshi" }
+
+func (p *fakePlatform) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
+	p.probes++
+	return nil, p.err
+}
+
+func newTestChecker(cycles Cycles, db Pinger, thresholds Thresholds, now *time.Time) *Checker {
+	c := NewChecker(cycles, db, thresholds)
+	c.started = *now
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func failing(report Report) []string {
+	var names []string
+	for _, check := range report.Checks {
+		if !check.OK {
+			names = append(names, check.Name)
+		}
+	}
+	return names
+}
+
+func TestChecker_StaleCycles(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cycles := &fakeCycles{}
+	c := newTestChecker(cycles, &fakePinger{}, Thresholds{ScanStale: 15 * time.Minute, MonitorStale: 5 * time.Minute}, &now)
+
+	// No cycle yet: measured from when the checker was created
+	now = now.Add(4 * time.Minute)
+	if report := c.Check(context.Background()); !report.Healthy {
+		t.Errorf("expected a starting bot to be healthy, got %v", failing(report))
+	}
+
+	now = now.Add(2 * time.Minute)
+	cycles.scan = now
+	report := c.Check(context.Background())
+	if got := failing(report); report.Healthy || len(got) != 1 || got[0] != CheckMonitor {
+		t.Errorf("expected the monitor cycle stale, got %v", got)
+	}
+
+	cycles.monitor = now
+	now = now.Add(16 * time.Minute)
+	report = c.Check(context.Background())
+	if got := failing(report); len(got) != 2 || got[0] != CheckScan {
+		t.Errorf("expected both cycles stale, got %v", got)
+	}
+	if !report.LastScan.Equal(cycles.scan) || !report.LastMonitor.Equal(cycles.monitor) {
+		t.Errorf("expected the last cycles reported, got %v and %v", report.LastScan, report.LastMonitor)
+	}
+}
+
+func TestChecker_DatabaseAndPlatforms(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	db := &fakePinger{err: errors.New("database is locked")}
+	p := &fakePlatform{err: errors.New("connection refused")}
+	c := newTestChecker(&fakeCycles{}, db, Thresholds{ProbeInterval: time.Minute}, &now)
+	c.AddPlatform(p)
+
+	report := c.Check(context.Background())
+	if got := failing(report); report.Healthy || report.Live || len(got) != 2 || got[0] != CheckDatabase || got[1] != "kalshi" {
+		t.Errorf("expected the database and platform to fail, got %v", got)
+	}
+
+	// Probes are cached for the probe interval. An unreachable platform
+	// leaves the bot unhealthy but live.
+	db.err, p.err = nil, nil
+	if report := c.Check(context.Background()); report.Healthy || !report.Live || p.probes != 1 {
+		t.Errorf("expected the failed probe reused, got %v after %d probes", failing(report), p.probes)
+	}
+	now = now.Add(time.Minute)
+	if report := c.Check(context.Background()); !report.Healthy || p.probes != 2 {
+		t.Errorf("expected a fresh probe to pass, got %v after %d probes", failing(report), p.probes)
+	}
+}
+
+func TestChecker_ServeHTTP(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	db := &fakePinger{}
+	c := newTestChecker(&fakeCycles{scan: now, monitor: now}, db, Thresholds{}, &now)
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 while healthy, got %d", rec.Code)
+	}
+
+	db.err = errors.New("disk I/O error")
+	rec = httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while unhealthy, got %d", rec.Code)
+	}
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Healthy || len(failing(report)) != 1 || failing(report)[0] != CheckDatabase {
+		t.Errorf("expected the database check to fail, got %+v", report)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	if err := Notify(NotifyWatchdog); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != NotifyWatchdog {
+		t.Errorf("expected %q, got %q (%v)", NotifyWatchdog, buf[:n], err)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := Notify(NotifyReady); err != nil {
+		t.Errorf("expected no-op without a socket, got %v", err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "")
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("expected no watchdog, got %v", got)
+	}
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	if got := WatchdogInterval(); got != 15*time.Second {
+		t.Errorf("expected half of WatchdogSec, got %v", got)
+	}
+	t.Setenv("WATCHDOG_PID", "1")
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("expected no watchdog for another process, got %v", got)
+	}
+}
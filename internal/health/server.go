@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown.
+const shutdownTimeout = 5 * time.Second
+
+// ServeHTTP serves the checks' report as JSON, with 200 OK if the bot is
+// healthy and 503 Service Unavailable if not.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Error().Err(err).Msg("failed to write health check response")
+	}
+}
+
+// Handler returns the checker's route:
+//
+//	GET /healthz   the checks' report
+func (c *Checker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", c)
+	return mux
+}
+
+// ListenAndServe serves on addr until ctx is cancelled, then shuts down
+// gracefully.
+func (c *Checker) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           c.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	log.Info().Str("addr", addr).Msg("health check listening")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve health check: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("shut down health check: %w", err)
+		}
+		if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve health check: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Notifications systemd understands (see sd_notify(3)).
+const (
+	NotifyReady    = "READY=1"
+	NotifyWatchdog = "WATCHDOG=1"
+)
+
+// Notify sends state to systemd over $NOTIFY_SOCKET. Without a socket,
+// when the bot isn't run by systemd with Type=notify, it does nothing.
+func Notify(state string) error {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil
+	}
+	// Abstract sockets are given with a leading @
+	if path[0] == '@' {
+		path = "\x00" + path[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("connect to systemd: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("notify systemd: %w", err)
+	}
+	return nil
+}
+
+// WatchdogInterval returns how often systemd expects the watchdog to be
+// pinged: half the WatchdogSec it set in $WATCHDOG_USEC, so a ping is never
+// late. Zero if systemd's watchdog isn't enabled for the bot.
+func WatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// RunWatchdog pings systemd's watchdog every interval while the bot is
+// live, until ctx is cancelled. Once its cycles stall or the database fails
+// the pings stop and systemd restarts the bot after WatchdogSec. Platform
+// outages don't withhold pings.
+func (c *Checker) RunWatchdog(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report := c.Check(ctx)
+			if !report.Live {
+				log.Warn().Interface("checks", report.Checks).Msg("liveness check failed, withholding watchdog ping")
+				continue
+			}
+			if err := Notify(NotifyWatchdog); err != nil {
+				log.Warn().Err(err).Msg("failed to ping systemd watchdog")
+			}
+		}
+	}
+}
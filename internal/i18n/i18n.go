@@ -0,0 +1,74 @@
+// Package i18n translates the operator-facing text of the dashboard and
+// reports.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language identifies a supported language.
+type Language string
+
+// Supported languages.
+const (
+	English    Language = "en"
+	Portuguese Language = "pt-BR"
+)
+
+// DefaultLanguage is used when no language is configured.
+const DefaultLanguage = English
+
+// ParseLanguage parses a language setting such as "en", "pt-BR" or "pt_br".
+// An empty setting returns DefaultLanguage.
+func ParseLanguage(value string) (Language, error) {
+	switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), "_", "-") {
+	case "":
+		return DefaultLanguage, nil
+	case "en", "en-us", "en-gb":
+		return English, nil
+	case "pt", "pt-br":
+		return Portuguese, nil
+	default:
+		return "", fmt.Errorf("unsupported language %q (supported: %s, %s)", value, English, Portuguese)
+	}
+}
+
+// Translator looks up messages in one language.
+type Translator struct {
+	lang Language
+}
+
+// New creates a translator for the given language. Unsupported languages
+// fall back to English.
+func New(lang Language) Translator {
+	if _, ok := catalogs[lang]; !ok {
+		lang = English
+	}
+	return Translator{lang: lang}
+}
+
+// Language returns the translator's language.
+func (t Translator) Language() Language {
+	if t.lang == "" {
+		return English
+	}
+	return t.lang
+}
+
+// T returns the message for key, formatted with args as by fmt.Sprintf if
+// any are given. Messages missing from the language fall back to English,
+// and keys missing from English are returned as is.
+func (t Translator) T(key string, args ...any) string {
+	msg, ok := catalogs[t.Language()][key]
+	if !ok {
+		msg, ok = catalogs[English][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
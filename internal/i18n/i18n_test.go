@@ -0,0 +1,69 @@
+package i18n
+
+import "testing"
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Language
+	}{
+		{"", English},
+		{"en", English},
+		{"EN-us", English},
+		{"pt-BR", Portuguese},
+		{"pt_br", Portuguese},
+		{"pt", Portuguese},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLanguage(tt.value)
+		if err != nil {
+			t.Errorf("ParseLanguage(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLanguage(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := ParseLanguage("fr"); err == nil {
+		t.Error("expected error for unsupported language")
+	}
+}
+
+func TestTranslator_T(t *testing.T) {
+	pt := New(Portuguese)
+
+	if got := pt.T("stats.title"); got != "Estatísticas" {
+		t.Errorf("expected Portuguese title, got %q", got)
+	}
+	if got := pt.T("report.trades_detail", 3, 2, 1); got != "3 (2 ganhas, 1 perdidas)" {
+		t.Errorf("expected formatted message, got %q", got)
+	}
+	if got := pt.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("expected unknown key returned as is, got %q", got)
+	}
+
+	// The zero value and unsupported languages use English
+	if got := (Translator{}).T("stats.title"); got != "Statistics" {
+		t.Errorf("expected English from zero value, got %q", got)
+	}
+	if got := New("fr").T("stats.title"); got != "Statistics" {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+}
+
+func TestCatalogs_AreComplete(t *testing.T) {
+	for lang, messages := range catalogs {
+		for key := range catalogs[English] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%s is missing %q", lang, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := catalogs[English][key]; !ok {
+				t.Errorf("%s has %q, which English lacks", lang, key)
+			}
+		}
+	}
+}
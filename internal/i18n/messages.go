@@ -0,0 +1,310 @@
+package i18n
+
+// catalogs holds the messages of each language by key. Keys are grouped by
+// the screen or report they appear on.
+var catalogs = map[Language]map[string]string{
+	English: {
+		// Dashboard header and help
+		"dashboard.title":        "Prediction Market Bot",
+		"dashboard.last_update":  "Last Update: %s",
+		"dashboard.dry_run":      "[DRY-RUN]",
+		"dashboard.live":         "[LIVE]",
+		"dashboard.paused":       "[PAUSED]",
+		"dashboard.disconnected": "[BOT DISCONNECTED]",
+		"dashboard.goodbye":      "Goodbye!",
+		"key.quit":               "quit",
+		"key.refresh":            "refresh",
+		"key.pause":              "pause",
+		"key.pnl":                "pnl report",
+		"key.scan_diff":          "scan diff",
+		"key.funnel":             "scan funnel",
+		"key.trades":             "trades",
+		"key.page":               "page trades",
+		"key.select":             "select position",
+		"key.close":              "close position",
+		"key.price":              "refresh price",
+
+		// Bankroll
+		"bankroll.title": "Bankroll",
+		"bankroll.empty": "No bankroll data available",
+		"bankroll.total": "Total",
+
+		// Equity
+		"equity.title":    "Equity",
+		"equity.empty":    "No bankroll history yet",
+		"equity.current":  "Now",
+		"equity.peak":     "Peak",
+		"equity.drawdown": "Drawdown",
+		"equity.today":    "Today",
+
+		// Positions
+		"positions.title":            "Open Positions",
+		"positions.empty":            "No open positions",
+		"positions.platform":         "Plat",
+		"positions.asset":            "Asset",
+		"positions.side":             "Side",
+		"positions.entry":            "Entry",
+		"positions.current":          "Curr",
+		"positions.quantity":         "Qty",
+		"positions.pnl":              "PnL",
+		"positions.trend":            "Trend",
+		"positions.margin":           "Margin",
+		"positions.closes_in":        "Closes In",
+		"positions.total_unrealized": "Total Unrealized PnL:",
+
+		// Statistics
+		"stats.title":        "Statistics",
+		"stats.trades":       "Trades",
+		"stats.wins":         "%dW",
+		"stats.losses":       "%dL",
+		"stats.win_rate":     "Win Rate",
+		"stats.total_pnl":    "Total PnL",
+		"stats.realized":     "Realized",
+		"stats.unrealized":   "Unrealized",
+		"stats.fees":         "Fees",
+		"stats.gas":          "Gas",
+		"stats.net_realized": "Net Realized",
+		"stats.max_drawdown": "Max Drawdown",
+		"stats.api":          "API (last hour)",
+		"stats.api_calls":    "%d calls",
+		"stats.api_failed":   "%.1f%% failed",
+
+		// Arbitrage
+		"arbitrage.title":       "Arbitrage",
+		"arbitrage.empty":       "No price divergences detected",
+		"arbitrage.asset":       "Asset",
+		"arbitrage.strike":      "Strike",
+		"arbitrage.date":        "Date",
+		"arbitrage.spread":      "Spread",
+		"arbitrage.profit":      "Profit",
+		"arbitrage.hedged":      "HEDGED",
+		"arbitrage.date_layout": "Jan 02",
+
+		// Realized PnL report
+		"pnl.title":       "Realized PnL",
+		"pnl.empty":       "No trades closed this year",
+		"pnl.period":      "Period",
+		"pnl.platform":    "Plat",
+		"pnl.trades":      "Trades",
+		"pnl.win_loss":    "W/L",
+		"pnl.realized":    "Realized",
+		"pnl.fees":        "Fees",
+		"pnl.avg_holding": "Avg Hold",
+
+		// Scan diff
+		"scan_diff.title":   "Scan Diff",
+		"scan_diff.empty":   "No changes since the previous scan",
+		"scan_diff.pending": "Waiting for a second scan",
+		"scan_diff.was":     "was %s",
+		"scan_diff.since":   "%s: %d changes since %s",
+
+		// Scan funnel
+		"funnel.title":      "Scan Funnel",
+		"funnel.empty":      "Waiting for the first scan",
+		"funnel.scanned":    "%s: scanned at %s",
+		"funnel.fetched":    "Fetched",
+		"funnel.filtered":   "Passed filters",
+		"funnel.parseable":  "Parseable",
+		"funnel.volatility": "Volatility-valid",
+		"funnel.sized":      "Sized",
+		"funnel.entered":    "Entered",
+
+		// Recent trades
+		"trades.title":    "Recent Trades",
+		"trades.empty":    "No trades closed yet",
+		"trades.closed":   "Closed",
+		"trades.platform": "Plat",
+		"trades.market":   "Market",
+		"trades.side":     "Side",
+		"trades.entry":    "Entry",
+		"trades.exit":     "Exit",
+		"trades.reason":   "Reason",
+		"trades.held":     "Held",
+		"trades.pnl":      "PnL",
+		"trades.page":     "Page %d of %d, %d trades",
+
+		// Activity
+		"dashboard.scanning": "[SCANNING %s]",
+		"activity.title":     "Activity",
+		"activity.empty":     "Waiting for the first scan",
+		"activity.scanned":   "scan complete, %d eligible",
+		"activity.eligible":  "ELIGIBLE",
+		"activity.skipped":   "SKIPPED",
+		"activity.opened":    "OPENED",
+
+		// Position actions
+		"actions.confirm_close": "Close position %d (%s) at the market price? Press y to confirm",
+		"actions.closed":        "Closed position %d",
+		"actions.close_failed":  "Failed to close position %d: %v",
+		"actions.priced":        "Position %d priced at $%.2f",
+		"actions.price_failed":  "Failed to refresh the price of position %d: %v",
+
+		// Backtest report
+		"report.trade_log":     "=== Trade Log ===",
+		"report.summary":       "=== Summary ===",
+		"report.period":        "Period:",
+		"report.snapshots":     "Snapshots:",
+		"report.trades":        "Trades:",
+		"report.trades_detail": "%d (%d won, %d lost)",
+		"report.win_rate":      "Win rate:",
+		"report.total_pnl":     "Total P&L:",
+		"report.equity":        "Equity:",
+		"report.sharpe":        "Sharpe ratio:",
+		"report.max_drawdown":  "Max drawdown:",
+		"report.avg_holding":   "Avg holding:",
+		"report.errors":        "Errors:",
+		"report.skipped":       "Skipped (%s): %d",
+	},
+	Portuguese: {
+		// Dashboard header and help
+		"dashboard.title":        "Bot de Mercados de Previsão",
+		"dashboard.last_update":  "Última atualização: %s",
+		"dashboard.dry_run":      "[SIMULAÇÃO]",
+		"dashboard.live":         "[AO VIVO]",
+		"dashboard.paused":       "[PAUSADO]",
+		"dashboard.disconnected": "[BOT DESCONECTADO]",
+		"dashboard.goodbye":      "Até logo!",
+		"key.quit":               "sair",
+		"key.refresh":            "atualizar",
+		"key.pause":              "pausar",
+		"key.pnl":                "relatório pnl",
+		"key.scan_diff":          "diff de varredura",
+		"key.funnel":             "funil de varredura",
+		"key.trades":             "operações",
+		"key.page":               "paginar operações",
+		"key.select":             "selecionar posição",
+		"key.close":              "fechar posição",
+		"key.price":              "atualizar preço",
+
+		// Bankroll
+		"bankroll.title": "Banca",
+		"bankroll.empty": "Nenhum dado de banca disponível",
+		"bankroll.total": "Total",
+
+		// Equity
+		"equity.title":    "Patrimônio",
+		"equity.empty":    "Ainda sem histórico de banca",
+		"equity.current":  "Agora",
+		"equity.peak":     "Pico",
+		"equity.drawdown": "Queda",
+		"equity.today":    "Hoje",
+
+		// Positions
+		"positions.title":            "Posições Abertas",
+		"positions.empty":            "Nenhuma posição aberta",
+		"positions.platform":         "Plat",
+		"positions.asset":            "Ativo",
+		"positions.side":             "Lado",
+		"positions.entry":            "Entr.",
+		"positions.current":          "Atual",
+		"positions.quantity":         "Qtd",
+		"positions.pnl":              "PnL",
+		"positions.trend":            "Tendência",
+		"positions.margin":           "Margem",
+		"positions.closes_in":        "Fecha Em",
+		"positions.total_unrealized": "PnL Não Realizado Total:",
+
+		// Statistics
+		"stats.title":        "Estatísticas",
+		"stats.trades":       "Operações",
+		"stats.wins":         "%dG",
+		"stats.losses":       "%dP",
+		"stats.win_rate":     "Taxa de Acerto",
+		"stats.total_pnl":    "PnL Total",
+		"stats.realized":     "Realizado",
+		"stats.unrealized":   "Não Realizado",
+		"stats.fees":         "Taxas",
+		"stats.gas":          "Gás",
+		"stats.net_realized": "Realiz. Líquido",
+		"stats.max_drawdown": "Drawdown Máximo",
+		"stats.api":          "API (última hora)",
+		"stats.api_calls":    "%d chamadas",
+		"stats.api_failed":   "%.1f%% falharam",
+
+		// Arbitrage
+		"arbitrage.title":       "Arbitragem",
+		"arbitrage.empty":       "Nenhuma divergência de preço detectada",
+		"arbitrage.asset":       "Ativo",
+		"arbitrage.strike":      "Strike",
+		"arbitrage.date":        "Data",
+		"arbitrage.spread":      "Spread",
+		"arbitrage.profit":      "Lucro",
+		"arbitrage.hedged":      "PROTEGIDA",
+		"arbitrage.date_layout": "02/01",
+
+		// Realized PnL report
+		"pnl.title":       "PnL Realizado",
+		"pnl.empty":       "Nenhuma operação encerrada este ano",
+		"pnl.period":      "Período",
+		"pnl.platform":    "Plat",
+		"pnl.trades":      "Oper.",
+		"pnl.win_loss":    "G/P",
+		"pnl.realized":    "Realizado",
+		"pnl.fees":        "Taxas",
+		"pnl.avg_holding": "Duração",
+
+		// Diff de varredura
+		"scan_diff.title":   "Diff de Varredura",
+		"scan_diff.empty":   "Nenhuma mudança desde a varredura anterior",
+		"scan_diff.pending": "Aguardando uma segunda varredura",
+		"scan_diff.was":     "era %s",
+		"scan_diff.since":   "%s: %d mudanças desde %s",
+
+		// Scan funnel
+		"funnel.title":      "Funil de Varredura",
+		"funnel.empty":      "Aguardando a primeira varredura",
+		"funnel.scanned":    "%s: varrido às %s",
+		"funnel.fetched":    "Obtidos",
+		"funnel.filtered":   "Passaram filtros",
+		"funnel.parseable":  "Interpretáveis",
+		"funnel.volatility": "Volatilidade ok",
+		"funnel.sized":      "Dimensionados",
+		"funnel.entered":    "Entraram",
+
+		// Recent trades
+		"trades.title":    "Operações Recentes",
+		"trades.empty":    "Nenhuma operação encerrada ainda",
+		"trades.closed":   "Encerrada",
+		"trades.platform": "Plat",
+		"trades.market":   "Mercado",
+		"trades.side":     "Lado",
+		"trades.entry":    "Entr.",
+		"trades.exit":     "Saída",
+		"trades.reason":   "Motivo",
+		"trades.held":     "Tempo",
+		"trades.pnl":      "PnL",
+		"trades.page":     "Página %d de %d, %d operações",
+
+		// Activity
+		"dashboard.scanning": "[ANALISANDO %s]",
+		"activity.title":     "Atividade",
+		"activity.empty":     "Aguardando a primeira análise",
+		"activity.scanned":   "análise concluída, %d elegíveis",
+		"activity.eligible":  "ELEGÍVEL",
+		"activity.skipped":   "IGNORADO",
+		"activity.opened":    "ABERTA",
+
+		// Position actions
+		"actions.confirm_close": "Fechar a posição %d (%s) a preço de mercado? Pressione y para confirmar",
+		"actions.closed":        "Posição %d fechada",
+		"actions.close_failed":  "Falha ao fechar a posição %d: %v",
+		"actions.priced":        "Posição %d cotada a $%.2f",
+		"actions.price_failed":  "Falha ao atualizar o preço da posição %d: %v",
+
+		// Backtest report
+		"report.trade_log":     "=== Registro de Operações ===",
+		"report.summary":       "=== Resumo ===",
+		"report.period":        "Período:",
+		"report.snapshots":     "Snapshots:",
+		"report.trades":        "Operações:",
+		"report.trades_detail": "%d (%d ganhas, %d perdidas)",
+		"report.win_rate":      "Taxa de acerto:",
+		"report.total_pnl":     "P&L total:",
+		"report.equity":        "Patrimônio:",
+		"report.sharpe":        "Índice Sharpe:",
+		"report.max_drawdown":  "Drawdown máximo:",
+		"report.avg_holding":   "Duração média:",
+		"report.errors":        "Erros:",
+		"report.skipped":       "Ignoradas (%s): %d",
+	},
+}
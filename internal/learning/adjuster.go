@@ -172,5 +172,10 @@ func DefaultParameters() map[string]float64 {
 		"volatility_safety_margin": 1.5,
 		"stop_loss_percent":        0.15,
 		"kelly_fraction":           0.25,
+		"min_liquidity":            100.0,
+		"min_volume":               0.0,
+		"min_hours_to_close":       0.0,
+		"max_hours_to_close":       48.0,
+		"max_spread":               0.10,
 	}
 }
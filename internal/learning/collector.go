@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+
+	"prediction-bot/pkg/types"
 )
 
 // TradeOutcome represents a completed trade with all its parameters and results.
@@ -21,6 +23,18 @@ type TradeOutcome struct {
 	EntryTime   time.Time
 	ExitTime    time.Time
 	ExitReason  string
+	// MarketOutcome is the winning side if the market's resolution was
+	// recorded, empty otherwise.
+	MarketOutcome string
+
+	// Strategy is the trading strategy the position was entered on
+	// ("fade"), empty for trades that follow the market.
+	Strategy string
+
+	// Experiment and Arm are the parameter experiment the position was
+	// entered under and its arm, empty outside one.
+	Experiment string
+	Arm        string
 
 	// Parameters used at entry time
 	SafetyMargin float64
@@ -32,6 +46,19 @@ func (t TradeOutcome) IsWin() bool {
 	return t.RealizedPnL > 0
 }
 
+// FilterStrategy returns the outcomes of trades entered on strategy, so
+// each strategy can be analyzed apart. An empty strategy selects trades
+// that follow the market.
+func FilterStrategy(outcomes []TradeOutcome, strategy string) []TradeOutcome {
+	filtered := make([]TradeOutcome, 0, len(outcomes))
+	for _, o := range outcomes {
+		if o.Strategy == strategy {
+			filtered = append(filtered, o)
+		}
+	}
+	return filtered
+}
+
 // ReturnPercent calculates the percentage return on the trade.
 // Formula: (exit - entry) / entry * 100
 func (t TradeOutcome) ReturnPercent() float64 {
@@ -51,14 +78,16 @@ func NewCollector(db *sql.DB) *Collector {
 	return &Collector{db: db}
 }
 
-// CollectOutcomes retrieves closed trades from the database.
+// CollectOutcomes retrieves closed trades from the database, leaving out
+// positions imported from a platform, which the bot didn't decide to enter.
 // Returns empty slice if there are fewer than minTrades closed positions.
 // Results are ordered by exit time descending (most recent first).
 func (c *Collector) CollectOutcomes(minTrades int) ([]TradeOutcome, error) {
 	// First, count how many closed trades we have
 	var count int
 	err := c.db.QueryRow(`
-		SELECT COUNT(*) FROM positions WHERE status = 'closed'
+		SELECT COUNT(*) FROM positions
+		WHERE status = 'closed' AND COALESCE(entry_strategy, '') != 'imported'
 	`).Scan(&count)
 	if err != nil {
 		return nil, fmt.Errorf("count closed positions: %w", err)
@@ -69,19 +98,38 @@ func (c *Collector) CollectOutcomes(minTrades int) ([]TradeOutcome, error) {
 		return []TradeOutcome{}, nil
 	}
 
-	// Query closed positions ordered by exit time desc, limited to minTrades
+	return c.CollectRecent(minTrades)
+}
+
+// CollectRecent retrieves up to limit of the most recent closed trades,
+// leaving out imported positions, most recent first.
+func (c *Collector) CollectRecent(limit int) ([]TradeOutcome, error) {
+	return c.collect("", limit)
+}
+
+// CollectExperiment retrieves the closed trades entered under the named
+// parameter experiment, in either arm, most recent first.
+func (c *Collector) CollectExperiment(name string) ([]TradeOutcome, error) {
+	return c.collect("AND p.experiment = ?", name, -1)
+}
+
+// collect retrieves closed trades that aren't imported and match the extra
+// condition, most recent first. The last arg is the limit (-1 for none).
+func (c *Collector) collect(condition string, args ...interface{}) ([]TradeOutcome, error) {
 	rows, err := c.db.Query(`
 		SELECT
-			id, platform, COALESCE(asset, ''), COALESCE(strike, 0),
-			COALESCE(direction, ''), side, entry_price, COALESCE(exit_price, 0),
-			quantity, COALESCE(realized_pnl, 0), entry_time, COALESCE(exit_time, entry_time),
-			COALESCE(exit_reason, ''),
-			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0)
-		FROM positions
-		WHERE status = 'closed'
-		ORDER BY exit_time DESC
+			p.id, p.platform, COALESCE(p.asset, ''), COALESCE(p.strike, 0),
+			COALESCE(p.direction, ''), p.side, p.entry_price_micros, COALESCE(p.exit_price_micros, 0),
+			p.quantity, COALESCE(p.realized_pnl_micros, 0), p.entry_time, COALESCE(p.exit_time, p.entry_time),
+			COALESCE(p.exit_reason, ''), COALESCE(r.outcome, ''),
+			COALESCE(p.safety_margin_at_entry, 0), COALESCE(p.volatility_at_entry, 0),
+			COALESCE(p.trade_strategy, ''), COALESCE(p.experiment, ''), COALESCE(p.experiment_arm, '')
+		FROM positions p
+		LEFT JOIN market_resolutions r ON r.platform = p.platform AND r.market_id = p.market_id
+		WHERE p.status = 'closed' AND COALESCE(p.entry_strategy, '') != 'imported' `+condition+`
+		ORDER BY p.exit_time DESC
 		LIMIT ?
-	`, minTrades)
+	`, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query closed positions: %w", err)
 	}
@@ -91,16 +139,21 @@ func (c *Collector) CollectOutcomes(minTrades int) ([]TradeOutcome, error) {
 	for rows.Next() {
 		var o TradeOutcome
 		var entryTimeStr, exitTimeStr string
+		var entryPrice, exitPrice, realizedPnL types.Money
 		err := rows.Scan(
 			&o.PositionID, &o.Platform, &o.Asset, &o.Strike,
-			&o.Direction, &o.Side, &o.EntryPrice, &o.ExitPrice,
-			&o.Quantity, &o.RealizedPnL, &entryTimeStr, &exitTimeStr,
-			&o.ExitReason,
+			&o.Direction, &o.Side, &entryPrice, &exitPrice,
+			&o.Quantity, &realizedPnL, &entryTimeStr, &exitTimeStr,
+			&o.ExitReason, &o.MarketOutcome,
 			&o.SafetyMargin, &o.Volatility,
+			&o.Strategy, &o.Experiment, &o.Arm,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan trade outcome: %w", err)
 		}
+		o.EntryPrice = entryPrice.Float64()
+		o.ExitPrice = exitPrice.Float64()
+		o.RealizedPnL = realizedPnL.Float64()
 
 		// Parse timestamps from SQLite format
 		o.EntryTime = parseTime(entryTimeStr)
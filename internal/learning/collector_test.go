@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -75,7 +76,7 @@ func TestCollector_CollectOutcomes_Returns20ClosedTrades(t *testing.T) {
 		}
 
 		// Close the position
-		err = posRepo.Close(id, exitPrice, "market_resolved", pnl)
+		err = posRepo.Close(id, exitPrice, "market_resolved", types.Dollars(pnl))
 		if err != nil {
 			t.Fatalf("failed to close position: %v", err)
 		}
@@ -141,7 +142,7 @@ func TestCollector_CollectOutcomes_ReturnsEmptyWhenNotEnoughTrades(t *testing.T)
 			t.Fatalf("failed to create position: %v", err)
 		}
 
-		err = posRepo.Close(id, 0.75, "stop_loss", -2.50)
+		err = posRepo.Close(id, 0.75, "stop_loss", types.Dollars(-2.50))
 		if err != nil {
 			t.Fatalf("failed to close position: %v", err)
 		}
@@ -189,7 +190,7 @@ func TestCollector_CollectOutcomes_ExcludesOpenPositions(t *testing.T) {
 
 		// Only close the first 10
 		if i < 10 {
-			err = posRepo.Close(id, 0.92, "market_resolved", 7.0)
+			err = posRepo.Close(id, 0.92, "market_resolved", types.Dollars(7.0))
 			if err != nil {
 				t.Fatalf("failed to close position: %v", err)
 			}
@@ -278,7 +279,7 @@ func TestCollector_CollectOutcomes_IncludesParametersUsed(t *testing.T) {
 			t.Fatalf("failed to create position: %v", err)
 		}
 
-		err = posRepo.Close(id, 0.92, "market_resolved", 7.0)
+		err = posRepo.Close(id, 0.92, "market_resolved", types.Dollars(7.0))
 		if err != nil {
 			t.Fatalf("failed to close position: %v", err)
 		}
@@ -332,7 +333,7 @@ func TestCollector_CollectOutcomes_OrderedByExitTime(t *testing.T) {
 			t.Fatalf("failed to create position: %v", err)
 		}
 
-		err = posRepo.Close(id, 0.92, "market_resolved", 7.0)
+		err = posRepo.Close(id, 0.92, "market_resolved", types.Dollars(7.0))
 		if err != nil {
 			t.Fatalf("failed to close position: %v", err)
 		}
@@ -355,3 +356,95 @@ func TestCollector_CollectOutcomes_OrderedByExitTime(t *testing.T) {
 		}
 	}
 }
+
+func TestCollector_CollectOutcomes_IncludesMarketOutcome(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	posRepo := persistence.NewPositionRepository(db)
+	resRepo := persistence.NewResolutionRepository(db)
+
+	for _, marketID := range []string{"resolved-market", "stopped-market"} {
+		id, err := posRepo.Create(&persistence.Position{
+			Platform:   "kalshi",
+			MarketID:   marketID,
+			EntryPrice: 0.85,
+			Quantity:   10,
+			Side:       "YES",
+			Status:     "open",
+		})
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		if err := posRepo.Close(id, 1.0, "market_resolved", types.Dollars(1.5)); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+	if err := resRepo.Record("kalshi", "resolved-market", "YES"); err != nil {
+		t.Fatalf("failed to record resolution: %v", err)
+	}
+
+	outcomes, err := NewCollector(db).CollectOutcomes(2)
+	if err != nil {
+		t.Fatalf("CollectOutcomes failed: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, o := range outcomes {
+		found[o.MarketOutcome] = true
+	}
+	if !found["YES"] || !found[""] {
+		t.Errorf("expected one outcome with recorded resolution and one without, got %+v", outcomes)
+	}
+}
+
+func TestCollector_CollectOutcomes_TagsFades(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	posRepo := persistence.NewPositionRepository(db)
+
+	// Every fifth trade fades the market
+	for i := 0; i < 20; i++ {
+		pos := &persistence.Position{
+			Platform:    "polymarket",
+			MarketID:    "test-market-" + string(rune('a'+i)),
+			MarketTitle: "Test Market",
+			Asset:       "BTC",
+			Direction:   "above",
+			EntryPrice:  0.85,
+			Quantity:    100,
+			Side:        "YES",
+			Status:      "open",
+		}
+		if i%5 == 0 {
+			pos.EntryPrice, pos.Side, pos.TradeStrategy = 0.15, "NO", "fade"
+		}
+
+		id, err := posRepo.Create(pos)
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		if err := posRepo.Close(id, 1.0, "market_resolved", types.Dollars(15.0)); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+
+	outcomes, err := NewCollector(db).CollectOutcomes(20)
+	if err != nil {
+		t.Fatalf("CollectOutcomes failed: %v", err)
+	}
+
+	fades := FilterStrategy(outcomes, "fade")
+	if len(fades) != 4 {
+		t.Fatalf("expected 4 fades, got %d", len(fades))
+	}
+	for _, o := range fades {
+		if o.Side != "NO" || o.EntryPrice != 0.15 {
+			t.Errorf("expected fades to buy NO at 0.15, got %s at %v", o.Side, o.EntryPrice)
+		}
+	}
+	if follows := FilterStrategy(outcomes, ""); len(follows) != 16 {
+		t.Errorf("expected 16 trades following the market, got %d", len(follows))
+	}
+}
@@ -0,0 +1,57 @@
+package learning
+
+import "prediction-bot/internal/experiment"
+
+// ArmStats contains statistics for one arm of a parameter experiment.
+type ArmStats struct {
+	Arm        string  // experiment.ArmChampion or experiment.ArmChallenger
+	TradeCount int     // Total number of trades in the arm
+	WinCount   int     // Number of winning trades
+	WinRate    float64 // Win rate (0.0 - 1.0)
+	TotalPnL   float64 // Sum of all realized PnL
+	AvgPnL     float64 // Average PnL per trade
+}
+
+// CompareArms returns the statistics of the champion and the challenger arm
+// of the named experiment, in that order, from the outcomes of trades
+// entered under it. Trades outside the experiment are ignored.
+func (a *Analyzer) CompareArms(outcomes []TradeOutcome, name string) []ArmStats {
+	arms := []ArmStats{{Arm: experiment.ArmChampion}, {Arm: experiment.ArmChallenger}}
+	for _, outcome := range outcomes {
+		if outcome.Experiment != name {
+			continue
+		}
+		for i := range arms {
+			if outcome.Arm != arms[i].Arm {
+				continue
+			}
+			arms[i].TradeCount++
+			arms[i].TotalPnL += outcome.RealizedPnL
+			if outcome.IsWin() {
+				arms[i].WinCount++
+			}
+		}
+	}
+
+	for i := range arms {
+		if arms[i].TradeCount > 0 {
+			arms[i].WinRate = float64(arms[i].WinCount) / float64(arms[i].TradeCount)
+			arms[i].AvgPnL = arms[i].TotalPnL / float64(arms[i].TradeCount)
+		}
+	}
+	return arms
+}
+
+// ChallengerLeads reports whether the challenger has made more per trade
+// than the champion, with at least MinTradesPerSegment trades in each arm,
+// so its parameters are worth promoting. arms is as returned by CompareArms.
+func ChallengerLeads(arms []ArmStats) bool {
+	if len(arms) != 2 {
+		return false
+	}
+	champion, challenger := arms[0], arms[1]
+	if champion.TradeCount < MinTradesPerSegment || challenger.TradeCount < MinTradesPerSegment {
+		return false
+	}
+	return challenger.AvgPnL > champion.AvgPnL
+}
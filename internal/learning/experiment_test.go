@@ -0,0 +1,81 @@
+package learning
+
+import (
+	"fmt"
+	"testing"
+
+	"prediction-bot/internal/experiment"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// createExperimentTrades creates closed trades entered under an experiment
+// arm, the first wins of them winning $1 and the rest losing $1.
+func createExperimentTrades(t *testing.T, repo *persistence.PositionRepository, name, arm string, count, wins int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		id, err := repo.Create(&persistence.Position{
+			Platform:      "polymarket",
+			MarketID:      fmt.Sprintf("%s-%s-%d", name, arm, i),
+			Asset:         "BTC",
+			EntryPrice:    0.9,
+			Quantity:      10,
+			Side:          "YES",
+			Status:        "open",
+			Experiment:    name,
+			ExperimentArm: arm,
+		})
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		exitPrice, pnl := 1.0, 1.0
+		if i >= wins {
+			exitPrice, pnl = 0.0, -1.0
+		}
+		if err := repo.Close(id, exitPrice, "market_resolved", types.Dollars(pnl)); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+}
+
+func TestAnalyzer_CompareArms(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positions := persistence.NewPositionRepository(db)
+	createExperimentTrades(t, positions, "threshold-90", experiment.ArmChampion, 6, 3)
+	createExperimentTrades(t, positions, "threshold-90", experiment.ArmChallenger, 5, 4)
+	createExperimentTrades(t, positions, "older", experiment.ArmChallenger, 3, 0)
+	createClosedTrades(t, positions, 4, 0.9, 1.5, true)
+
+	outcomes, err := NewCollector(db).CollectExperiment("threshold-90")
+	if err != nil {
+		t.Fatalf("CollectExperiment failed: %v", err)
+	}
+	if len(outcomes) != 11 {
+		t.Fatalf("expected the experiment's 11 trades, got %d", len(outcomes))
+	}
+
+	arms := NewAnalyzer().CompareArms(outcomes, "threshold-90")
+	want := []ArmStats{
+		{Arm: experiment.ArmChampion, TradeCount: 6, WinCount: 3, WinRate: 0.5, TotalPnL: 0, AvgPnL: 0},
+		{Arm: experiment.ArmChallenger, TradeCount: 5, WinCount: 4, WinRate: 0.8, TotalPnL: 3, AvgPnL: 0.6},
+	}
+	if len(arms) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, arms)
+	}
+	for i := range want {
+		if arms[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], arms[i])
+		}
+	}
+	if !ChallengerLeads(arms) {
+		t.Error("expected the challenger to lead")
+	}
+
+	// Too few challenger trades to promote it
+	arms[1].TradeCount = MinTradesPerSegment - 1
+	if ChallengerLeads(arms) {
+		t.Error("expected no lead with too few challenger trades")
+	}
+}
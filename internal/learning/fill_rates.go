@@ -0,0 +1,64 @@
+package learning
+
+import (
+	"fmt"
+	"time"
+
+	"prediction-bot/internal/orders"
+)
+
+// EntryFillRate summarizes how often entries placed with one strategy
+// filled. Comparing the fill rate of passive entries with their better
+// prices shows whether waiting below the quoted price is worth the trades
+// it misses.
+type EntryFillRate struct {
+	Strategy string // Entry strategy, e.g. "passive"
+	Attempts int    // Entries placed
+	Filled   int    // Entries at least partly filled
+	// AvgEntryPrice is the average fill price of the filled entries.
+	AvgEntryPrice float64
+}
+
+// FillRate returns the share of attempts that filled, or 0 if there were
+// none.
+func (r EntryFillRate) FillRate() float64 {
+	if r.Attempts == 0 {
+		return 0
+	}
+	return float64(r.Filled) / float64(r.Attempts)
+}
+
+// CollectEntryFillRates retrieves the fill rate of each entry strategy over
+// the entries placed at or after since, ordered by strategy. Entries
+// recorded before their strategy was are left out.
+func (c *Collector) CollectEntryFillRates(since time.Time) ([]EntryFillRate, error) {
+	rows, err := c.db.Query(`
+		SELECT entry_strategy,
+			COUNT(*),
+			COUNT(*) - COUNT(CASE WHEN exit_reason = ? THEN 1 END),
+			COALESCE(AVG(CASE WHEN COALESCE(exit_reason, '') != ? THEN entry_price_micros END), 0) / 1000000.0
+		FROM positions
+		WHERE COALESCE(entry_strategy, '') != '' AND entry_time >= ?
+		GROUP BY entry_strategy
+		ORDER BY entry_strategy
+	`, orders.ExitReasonUnfilled, orders.ExitReasonUnfilled, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("query entry fill rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []EntryFillRate
+	for rows.Next() {
+		var r EntryFillRate
+		if err := rows.Scan(&r.Strategy, &r.Attempts, &r.Filled, &r.AvgEntryPrice); err != nil {
+			return nil, fmt.Errorf("scan entry fill rate: %w", err)
+		}
+		rates = append(rates, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate entry fill rates: %w", err)
+	}
+
+	return rates, nil
+}
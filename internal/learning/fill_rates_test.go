@@ -0,0 +1,72 @@
+package learning
+
+import (
+	"testing"
+	"time"
+
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/persistence"
+)
+
+func TestCollector_CollectEntryFillRates(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	posRepo := persistence.NewPositionRepository(db)
+	entries := []struct {
+		strategy   string
+		entryPrice float64
+		unfilled   bool
+	}{
+		{"passive", 0.78, false},
+		{"passive", 0.80, true},
+		{"passive", 0.76, false},
+		{"passive", 0.80, true},
+		{"limit", 0.79, false},
+		{"", 0.80, false},
+	}
+	for i, e := range entries {
+		id, err := posRepo.Create(&persistence.Position{
+			Platform:      "polymarket",
+			MarketID:      "market-" + string(rune('a'+i)),
+			EntryPrice:    e.entryPrice,
+			Quantity:      10,
+			Side:          "YES",
+			Status:        persistence.PositionStatusOpen,
+			EntryStrategy: e.strategy,
+		})
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		if e.unfilled {
+			if err := posRepo.Close(id, e.entryPrice, orders.ExitReasonUnfilled, 0); err != nil {
+				t.Fatalf("failed to close position: %v", err)
+			}
+		}
+	}
+
+	collector := NewCollector(db)
+	rates, err := collector.CollectEntryFillRates(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CollectEntryFillRates failed: %v", err)
+	}
+
+	if len(rates) != 2 {
+		t.Fatalf("expected limit and passive rates, got %+v", rates)
+	}
+	if rates[0].Strategy != "limit" || rates[0].FillRate() != 1 {
+		t.Errorf("expected every limit entry filled, got %+v", rates[0])
+	}
+	passive := rates[1]
+	if passive.Strategy != "passive" || passive.Attempts != 4 || passive.Filled != 2 || passive.FillRate() != 0.5 {
+		t.Errorf("expected 2 of 4 passive entries filled, got %+v", passive)
+	}
+	if passive.AvgEntryPrice < 0.7699 || passive.AvgEntryPrice > 0.7701 {
+		t.Errorf("expected average passive fill price 0.77, got %v", passive.AvgEntryPrice)
+	}
+
+	later, err := collector.CollectEntryFillRates(time.Now().Add(time.Hour))
+	if err != nil || len(later) != 0 {
+		t.Errorf("expected no entries placed in the future, got %+v (%v)", later, err)
+	}
+}
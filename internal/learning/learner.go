@@ -0,0 +1,250 @@
+package learning
+
+import (
+	"fmt"
+	"sort"
+
+	"prediction-bot/internal/persistence"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultWindowTrades is how many of the most recent closed trades are
+// analyzed when no window is set.
+const DefaultWindowTrades = 100
+
+// tunedParameters are the parameters the learner adjusts, with the
+// analyzer segment their trades are grouped by.
+var tunedParameters = []struct {
+	name    string
+	segment string
+}{
+	{"probability_threshold", "probability"},
+	{"volatility_safety_margin", "safety_margin"},
+}
+
+// EquitySource reports the realized equity, in dollars, the drawdown
+// guardrail is measured on.
+type EquitySource interface {
+	RealizedEquity() (float64, error)
+}
+
+// Result is what a pass of the learning loop did.
+type Result struct {
+	// Changes are the parameter changes saved.
+	Changes []persistence.ParameterChange
+	// Reverted is set when the drawdown guardrail was hit and the
+	// parameters reverted to DefaultParameters instead of adjusted.
+	Reverted bool
+	// Equity and Peak are the realized equity and its recorded peak, zero
+	// without equity tracking.
+	Equity float64
+	Peak   float64
+	// Arms compares the champion and challenger of the parameter experiment
+	// (see SetExperiment), nil without one.
+	Arms []ArmStats
+}
+
+// Drawdown returns how far equity is below its peak, as a fraction of the
+// peak.
+func (r *Result) Drawdown() float64 {
+	peak := persistence.EquityPeak{Peak: r.Peak}
+	return peak.Drawdown(r.Equity)
+}
+
+// Learner runs the learning loop: it collects the outcomes of recent closed
+// trades, finds the best performing segment of each tuned parameter and
+// moves the parameter toward it in the parameters table, within the
+// guardrails, globally and for each platform and asset traded (see
+// persistence.ScopedName). In a deep drawdown it reverts the parameters to
+// DefaultParameters instead.
+type Learner struct {
+	collector  *Collector
+	params     *persistence.ParametersRepository
+	analyzer   *Analyzer
+	adjuster   *Adjuster
+	guardrails *Guardrails
+	equity     EquitySource
+	peaks      *persistence.EquityRepository
+	experiment string
+	window     int
+}
+
+// NewLearner creates a Learner analyzing the window most recent closed
+// trades (DefaultWindowTrades if not positive) and adjusting params.
+func NewLearner(collector *Collector, params *persistence.ParametersRepository, window int) *Learner {
+	if window <= 0 {
+		window = DefaultWindowTrades
+	}
+	return &Learner{
+		collector:  collector,
+		params:     params,
+		analyzer:   NewAnalyzer(),
+		adjuster:   NewAdjuster(),
+		guardrails: NewGuardrails(),
+		window:     window,
+	}
+}
+
+// SetEquityTracking sets where the realized equity comes from and where its
+// peak is tracked, for the drawdown guardrail. Without them parameters are
+// never reverted.
+func (l *Learner) SetEquityTracking(source EquitySource, peaks *persistence.EquityRepository) {
+	l.equity = source
+	l.peaks = peaks
+}
+
+// SetExperiment sets the parameter experiment whose arms each pass compares,
+// over all its closed trades. Empty compares none.
+func (l *Learner) SetExperiment(name string) {
+	l.experiment = name
+}
+
+// Run makes one pass of the learning loop. Only trades that follow the
+// market are analyzed. Once realized equity is DrawdownRevertThreshold below
+// its peak, the parameters are reverted to DefaultParameters on each pass
+// instead, until it recovers; only parameters not at their default are
+// changed.
+func (l *Learner) Run() (*Result, error) {
+	result := &Result{}
+	if l.experiment != "" {
+		outcomes, err := l.collector.CollectExperiment(l.experiment)
+		if err != nil {
+			return nil, fmt.Errorf("collect experiment outcomes: %w", err)
+		}
+		result.Arms = l.analyzer.CompareArms(outcomes, l.experiment)
+	}
+	if l.equity != nil && l.peaks != nil {
+		equity, err := l.equity.RealizedEquity()
+		if err != nil {
+			return nil, fmt.Errorf("compute realized equity: %w", err)
+		}
+		peak, err := l.peaks.RecordEquity(equity)
+		if err != nil {
+			return nil, fmt.Errorf("track equity peak: %w", err)
+		}
+		result.Equity = equity
+		result.Peak = peak.Peak
+
+		if l.guardrails.CheckDrawdown(equity, peak.Peak) {
+			result.Reverted = true
+			reason := fmt.Sprintf("learning: drawdown %.1f%%, reverted to defaults", result.Drawdown()*100)
+			result.Changes, err = l.params.RevertTo(DefaultParameters(), reason)
+			if err != nil {
+				return nil, fmt.Errorf("revert parameters: %w", err)
+			}
+			return result, nil
+		}
+	}
+
+	outcomes, err := l.collector.CollectRecent(l.window)
+	if err != nil {
+		return nil, fmt.Errorf("collect outcomes: %w", err)
+	}
+	outcomes = FilterStrategy(outcomes, "")
+
+	current, err := l.params.GetCurrent()
+	if err != nil {
+		return nil, fmt.Errorf("get parameters: %w", err)
+	}
+	values := make(map[string]float64, len(current))
+	for name, param := range current {
+		values[name] = param.Value
+	}
+	scopes := GroupByScope(outcomes)
+
+	for _, tuned := range tunedParameters {
+		param, ok := current[tuned.name]
+		if !ok || param.MaxValue <= param.MinValue {
+			continue
+		}
+		change, err := l.adjust(Scope{}, tuned.name, tuned.segment, param.Value, param, outcomes)
+		if err != nil {
+			return result, err
+		}
+		if change != nil {
+			result.Changes = append(result.Changes, *change)
+		}
+
+		// With trades on more than one platform and asset, each is also
+		// learned apart, from the value it falls back to
+		if len(scopes) < 2 {
+			continue
+		}
+		for _, scope := range sortedScopes(scopes) {
+			value := persistence.ScopedValues(values, scope.Platform, scope.Asset)[tuned.name]
+			change, err := l.adjust(scope, tuned.name, tuned.segment, value, param, scopes[scope])
+			if err != nil {
+				return result, err
+			}
+			if change != nil {
+				result.Changes = append(result.Changes, *change)
+			}
+		}
+	}
+	return result, nil
+}
+
+// adjust moves the value of a parameter in scope (global if empty) toward
+// the best performing segment of outcomes, within the bounds of the global
+// parameter and the guardrails. Returns the change saved, nil if none.
+func (l *Learner) adjust(scope Scope, name, segment string, value float64, global persistence.Parameter, outcomes []TradeOutcome) (*persistence.ParameterChange, error) {
+	scoped := persistence.ScopedName(scope.Platform, scope.Asset, name)
+	last, err := l.params.GetLastAdjustmentTime(scoped)
+	if err != nil {
+		return nil, fmt.Errorf("get last adjustment of %s: %w", scoped, err)
+	}
+	if ok, why := l.guardrails.CheckCanAdjust(len(outcomes), last); !ok {
+		log.Debug().Str("parameter", scoped).Str("reason", why).Msg("parameter not adjusted")
+		return nil, nil
+	}
+
+	segments := l.analyzer.AnalyzeBySegment(outcomes, segment)
+	next := l.adjuster.SuggestAdjustment(value, segments, AdjustmentBounds{Min: global.MinValue, Max: global.MaxValue})
+	if next == value {
+		return nil, nil
+	}
+	best := findBestSegment(segments)
+	reason := fmt.Sprintf("learning: %s %.2f-%.2f won %.0f%% of %d trades",
+		segment, best.RangeStart, best.RangeEnd, best.WinRate*100, best.TradeCount)
+	if err := l.params.SaveScoped(scope.Platform, scope.Asset, name, next, reason); err != nil {
+		return nil, fmt.Errorf("save %s: %w", scoped, err)
+	}
+	return &persistence.ParameterChange{Name: scoped, OldValue: value, NewValue: next, Reason: reason}, nil
+}
+
+// Scope is a platform and asset parameters are learned for apart. Empty is
+// global.
+type Scope struct {
+	Platform string
+	Asset    string
+}
+
+// GroupByScope groups outcomes by the platform and asset traded, leaving
+// out trades whose asset isn't known.
+func GroupByScope(outcomes []TradeOutcome) map[Scope][]TradeOutcome {
+	scopes := make(map[Scope][]TradeOutcome)
+	for _, o := range outcomes {
+		if o.Platform == "" || o.Asset == "" {
+			continue
+		}
+		scope := Scope{Platform: o.Platform, Asset: o.Asset}
+		scopes[scope] = append(scopes[scope], o)
+	}
+	return scopes
+}
+
+// sortedScopes returns the scopes of groups by platform, then asset.
+func sortedScopes(groups map[Scope][]TradeOutcome) []Scope {
+	scopes := make([]Scope, 0, len(groups))
+	for scope := range groups {
+		scopes = append(scopes, scope)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if scopes[i].Platform != scopes[j].Platform {
+			return scopes[i].Platform < scopes[j].Platform
+		}
+		return scopes[i].Asset < scopes[j].Asset
+	})
+	return scopes
+}
@@ -0,0 +1,207 @@
+package learning
+
+import (
+	"fmt"
+	"testing"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// fixedEquity is an EquitySource reporting a set equity.
+type fixedEquity float64
+
+func (e *fixedEquity) RealizedEquity() (float64, error) {
+	return float64(*e), nil
+}
+
+// createClosedTrades creates count closed positions entered at entryPrice
+// with safetyMargin, each winning or losing a dollar.
+func createClosedTrades(t *testing.T, repo *persistence.PositionRepository, count int, entryPrice, safetyMargin float64, win bool) {
+	t.Helper()
+	createTradesOn(t, repo, "polymarket", "BTC", count, entryPrice, safetyMargin, win)
+}
+
+// createTradesOn creates count closed trades of asset on platform, each
+// winning or losing $1.
+func createTradesOn(t *testing.T, repo *persistence.PositionRepository, platform, asset string, count int, entryPrice, safetyMargin float64, win bool) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		id, err := repo.Create(&persistence.Position{
+			Platform:            platform,
+			MarketID:            fmt.Sprintf("market-%s-%v-%v-%d", asset, entryPrice, safetyMargin, i),
+			Asset:               asset,
+			EntryPrice:          entryPrice,
+			Quantity:            10,
+			Side:                "YES",
+			Status:              "open",
+			SafetyMarginAtEntry: safetyMargin,
+		})
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		exitPrice, pnl := 1.0, 1.0
+		if !win {
+			exitPrice, pnl = 0.0, -1.0
+		}
+		if err := repo.Close(id, exitPrice, "market_resolved", types.Dollars(pnl)); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+}
+
+func TestLearner_Run_AdjustsTowardBestSegment(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positions := persistence.NewPositionRepository(db)
+	createClosedTrades(t, positions, 15, 0.92, 2.2, true)
+	createClosedTrades(t, positions, 15, 0.82, 1.3, false)
+
+	params := persistence.NewParametersRepository(db)
+	learner := NewLearner(NewCollector(db), params, 0)
+	equity := fixedEquity(1000)
+	learner.SetEquityTracking(&equity, persistence.NewEquityRepository(db))
+
+	result, err := learner.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	changes := result.Changes
+	if result.Reverted || result.Peak != 1000 {
+		t.Errorf("expected no revert at the peak, got %+v", result)
+	}
+	// Each moves at most 10% toward the winning segment
+	want := map[string]float64{"probability_threshold": 0.88, "volatility_safety_margin": 1.65}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for _, c := range changes {
+		param, err := params.GetByName(c.Name)
+		if err != nil {
+			t.Fatalf("GetByName failed: %v", err)
+		}
+		if diff := param.Value - want[c.Name]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("expected %s saved as %v, got %v", c.Name, want[c.Name], param.Value)
+		}
+		history, err := params.GetHistory(c.Name, 10)
+		if err != nil {
+			t.Fatalf("GetHistory failed: %v", err)
+		}
+		if len(history) != 1 || history[0].Reason != c.Reason {
+			t.Errorf("expected the change to %s recorded with %q, got %+v", c.Name, c.Reason, history)
+		}
+	}
+
+	// The cooldown holds the next adjustment
+	if result, err := learner.Run(); err != nil || len(result.Changes) != 0 {
+		t.Errorf("expected no changes within the cooldown, got %+v, %v", result, err)
+	}
+}
+
+func TestLearner_Run_InsufficientTrades(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	createClosedTrades(t, persistence.NewPositionRepository(db), 10, 0.92, 2.2, true)
+	learner := NewLearner(NewCollector(db), persistence.NewParametersRepository(db), 0)
+
+	if result, err := learner.Run(); err != nil || len(result.Changes) != 0 {
+		t.Errorf("expected no changes with 10 trades, got %+v, %v", result, err)
+	}
+}
+
+func TestLearner_Run_RevertsToDefaultsInDrawdown(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	createClosedTrades(t, persistence.NewPositionRepository(db), 30, 0.92, 2.2, true)
+	params := persistence.NewParametersRepository(db)
+	if err := params.SaveWithReason("probability_threshold", 0.88, "learning"); err != nil {
+		t.Fatalf("SaveWithReason failed: %v", err)
+	}
+	if err := params.SaveWithReason("volatility_safety_margin", 1.65, "learning"); err != nil {
+		t.Fatalf("SaveWithReason failed: %v", err)
+	}
+
+	learner := NewLearner(NewCollector(db), params, 0)
+	equity := fixedEquity(1000)
+	peaks := persistence.NewEquityRepository(db)
+	learner.SetEquityTracking(&equity, peaks)
+	if _, err := learner.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// 20% below the recorded peak: reverted instead of adjusted
+	equity = 800
+	result, err := learner.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !result.Reverted || result.Peak != 1000 || result.Drawdown() != 0.2 {
+		t.Errorf("expected a revert at 20%% drawdown, got %+v", result)
+	}
+	if len(result.Changes) != 2 {
+		t.Fatalf("expected the 2 learned parameters reverted, got %+v", result.Changes)
+	}
+	defaults := DefaultParameters()
+	for _, name := range []string{"probability_threshold", "volatility_safety_margin"} {
+		if param, _ := params.GetByName(name); param.Value != defaults[name] {
+			t.Errorf("expected %s reverted to %v, got %v", name, defaults[name], param.Value)
+		}
+	}
+
+	// Still in drawdown: nothing left to revert
+	if result, err := learner.Run(); err != nil || !result.Reverted || len(result.Changes) != 0 {
+		t.Errorf("expected no further changes, got %+v, %v", result, err)
+	}
+}
+
+func TestLearner_Run_AdjustsEachPlatformAndAsset(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// BTC on Polymarket wins at 92%, ETH on Kalshi at 87%; each moves toward
+	// its own winning segment, the global parameter toward the best overall
+	positions := persistence.NewPositionRepository(db)
+	createTradesOn(t, positions, "polymarket", "BTC", 15, 0.92, 2.2, true)
+	createTradesOn(t, positions, "polymarket", "BTC", 15, 0.82, 2.2, false)
+	createTradesOn(t, positions, "kalshi", "ETH", 20, 0.87, 2.2, true)
+
+	params := persistence.NewParametersRepository(db)
+	result, err := NewLearner(NewCollector(db), params, 0).Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	changed := make(map[string]float64)
+	for _, c := range result.Changes {
+		changed[c.Name] = c.NewValue
+	}
+	want := map[string]float64{
+		"probability_threshold":                0.875,
+		"kalshi:ETH:probability_threshold":     0.875,
+		"polymarket:BTC:probability_threshold": 0.88,
+	}
+	for name, value := range want {
+		got, ok := changed[name]
+		if diff := got - value; !ok || diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("expected %s adjusted to %v, got %v (%+v)", name, value, got, result.Changes)
+		}
+	}
+
+	current, err := params.GetCurrent()
+	if err != nil {
+		t.Fatalf("GetCurrent failed: %v", err)
+	}
+	values := make(map[string]float64)
+	for name, p := range current {
+		values[name] = p.Value
+	}
+	if got := persistence.ScopedValues(values, "polymarket", "BTC")["probability_threshold"]; got-0.88 > 1e-9 || 0.88-got > 1e-9 {
+		t.Errorf("expected Polymarket BTC to resolve to its own 0.88, got %v", got)
+	}
+	if got := persistence.ScopedValues(values, "polymarket", "SOL")["probability_threshold"]; got-0.875 > 1e-9 || 0.875-got > 1e-9 {
+		t.Errorf("expected Polymarket SOL to fall back to the global 0.875, got %v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package learning
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// NearMissRecord is a market that failed exactly one eligibility threshold,
+// as recorded by the scanner.
+type NearMissRecord struct {
+	Platform    string
+	MarketID    string
+	Asset       string
+	Criterion   string  // Scanner criterion, e.g. "probability" or "liquidity"
+	Value       float64 // Market value at its closest approach
+	Threshold   float64 // Threshold the value was checked against
+	Shortfall   float64 // Distance beyond the threshold (>= 0)
+	Probability float64
+	SeenCount   int
+	LastSeen    time.Time
+}
+
+// NearMissSegment summarizes near misses for one criterion within a
+// shortfall range.
+type NearMissSegment struct {
+	Criterion      string
+	RangeStart     float64 // Shortfall range start (inclusive)
+	RangeEnd       float64 // Shortfall range end (exclusive)
+	MarketCount    int     // Distinct markets in this range
+	AvgShortfall   float64
+	AvgProbability float64
+}
+
+// CollectNearMisses retrieves near misses last seen at or after since.
+func (c *Collector) CollectNearMisses(since time.Time) ([]NearMissRecord, error) {
+	rows, err := c.db.Query(`
+		SELECT platform, market_id, COALESCE(asset, ''), criterion,
+			value, threshold, shortfall, COALESCE(probability, 0),
+			seen_count, last_seen
+		FROM near_misses
+		WHERE last_seen >= ?
+		ORDER BY criterion, shortfall
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("query near misses: %w", err)
+	}
+	defer rows.Close()
+
+	var records []NearMissRecord
+	for rows.Next() {
+		var r NearMissRecord
+		var lastSeenStr string
+		err := rows.Scan(
+			&r.Platform, &r.MarketID, &r.Asset, &r.Criterion,
+			&r.Value, &r.Threshold, &r.Shortfall, &r.Probability,
+			&r.SeenCount, &lastSeenStr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan near miss: %w", err)
+		}
+		r.LastSeen = parseTime(lastSeenStr)
+		records = append(records, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate near misses: %w", err)
+	}
+
+	return records, nil
+}
+
+// AnalyzeNearMisses groups near misses for a criterion by how far they fell
+// outside the threshold. Supported criteria:
+// - "probability": shortfall in probability (0-0.02, 0.02-0.05, 0.05-0.10, 0.10+)
+// - "time_to_resolution": hours beyond the window (0-6, 6-12, 12-24, 24+)
+// - "liquidity": dollars below the minimum (0-25, 25-50, 50-100, 100+)
+// - "volume": dollars below the minimum (0-100, 100-500, 500+)
+// - "min_time_to_resolution": hours short of the window (0-1, 1-3, 3+)
+// - "spread": price units above the maximum (0-0.02, 0.02-0.05, 0.05+)
+func (a *Analyzer) AnalyzeNearMisses(records []NearMissRecord, criterion string) []NearMissSegment {
+	var bounds []float64
+	switch criterion {
+	case "probability":
+		bounds = []float64{0, 0.02, 0.05, 0.10, 1.0}
+	case "time_to_resolution":
+		bounds = []float64{0, 6, 12, 24, 24 * 365}
+	case "liquidity":
+		bounds = []float64{0, 25, 50, 100, math.Inf(1)}
+	case "volume":
+		bounds = []float64{0, 100, 500, math.Inf(1)}
+	case "min_time_to_resolution":
+		bounds = []float64{0, 1, 3, math.Inf(1)}
+	case "spread":
+		bounds = []float64{0, 0.02, 0.05, math.Inf(1)}
+	default:
+		return []NearMissSegment{}
+	}
+
+	segments := make([]NearMissSegment, len(bounds)-1)
+	for i := range segments {
+		segments[i] = NearMissSegment{
+			Criterion:  criterion,
+			RangeStart: bounds[i],
+			RangeEnd:   bounds[i+1],
+		}
+	}
+
+	for _, r := range records {
+		if r.Criterion != criterion {
+			continue
+		}
+		for i := range segments {
+			if r.Shortfall >= segments[i].RangeStart && r.Shortfall < segments[i].RangeEnd {
+				segments[i].MarketCount++
+				segments[i].AvgShortfall += r.Shortfall
+				segments[i].AvgProbability += r.Probability
+				break
+			}
+		}
+	}
+
+	for i := range segments {
+		if segments[i].MarketCount > 0 {
+			segments[i].AvgShortfall /= float64(segments[i].MarketCount)
+			segments[i].AvgProbability /= float64(segments[i].MarketCount)
+		}
+	}
+
+	return segments
+}
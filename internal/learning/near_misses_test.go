@@ -0,0 +1,76 @@
+package learning
+
+import (
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+func TestCollector_CollectNearMisses(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := persistence.NewNearMissRepository(db)
+	records := []persistence.NearMiss{
+		{Platform: "polymarket", MarketID: "m1", Asset: "BTC", Criterion: "time_to_resolution", Value: 52, Threshold: 48, Shortfall: 4, Probability: 0.9},
+		{Platform: "kalshi", MarketID: "m2", Asset: "ETH", Criterion: "probability", Value: 0.79, Threshold: 0.80, Shortfall: 0.01, Probability: 0.79},
+	}
+	for i := range records {
+		if err := repo.Record(&records[i]); err != nil {
+			t.Fatalf("failed to record near miss: %v", err)
+		}
+	}
+
+	collector := NewCollector(db)
+	nearMisses, err := collector.CollectNearMisses(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CollectNearMisses failed: %v", err)
+	}
+
+	if len(nearMisses) != 2 {
+		t.Fatalf("expected 2 near misses, got %d", len(nearMisses))
+	}
+	if nearMisses[0].Criterion != "probability" || nearMisses[0].Asset != "ETH" {
+		t.Errorf("expected ETH probability near miss first, got %+v", nearMisses[0])
+	}
+	if nearMisses[1].LastSeen.IsZero() {
+		t.Error("expected last seen to be parsed")
+	}
+}
+
+func TestAnalyzer_AnalyzeNearMisses(t *testing.T) {
+	records := []NearMissRecord{
+		{Criterion: "time_to_resolution", Shortfall: 2, Probability: 0.90},
+		{Criterion: "time_to_resolution", Shortfall: 4, Probability: 0.86},
+		{Criterion: "time_to_resolution", Shortfall: 30, Probability: 0.95},
+		{Criterion: "liquidity", Shortfall: 10, Probability: 0.90},
+	}
+
+	analyzer := NewAnalyzer()
+	segments := analyzer.AnalyzeNearMisses(records, "time_to_resolution")
+
+	if len(segments) != 4 {
+		t.Fatalf("expected 4 segments, got %d", len(segments))
+	}
+
+	// 0-6h: two markets
+	if segments[0].MarketCount != 2 {
+		t.Errorf("expected 2 markets within 6h, got %d", segments[0].MarketCount)
+	}
+	if segments[0].AvgShortfall != 3 {
+		t.Errorf("expected avg shortfall 3h, got %f", segments[0].AvgShortfall)
+	}
+	if segments[0].AvgProbability < 0.879 || segments[0].AvgProbability > 0.881 {
+		t.Errorf("expected avg probability 0.88, got %f", segments[0].AvgProbability)
+	}
+
+	// 24h+: one market
+	if segments[3].MarketCount != 1 {
+		t.Errorf("expected 1 market beyond 24h, got %d", segments[3].MarketCount)
+	}
+
+	if len(analyzer.AnalyzeNearMisses(records, "unknown")) != 0 {
+		t.Error("expected no segments for unknown criterion")
+	}
+}
@@ -0,0 +1,306 @@
+// Package optimize searches trading parameters against the backtest engine
+// with walk-forward validation: parameters are fit on one stretch of
+// history and scored on the stretch after it, so the report shows how much
+// of the fitted performance holds up on data the fit didn't see.
+package optimize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/config"
+)
+
+// Range is the values a parameter is searched over: Min to Max by Step,
+// both included.
+type Range struct {
+	// Name is the parameter's YAML name (e.g. probability_threshold).
+	Name string
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// Values returns the range's values, from Min up.
+func (r Range) Values() []float64 {
+	if r.Step <= 0 || r.Max <= r.Min {
+		return []float64{r.Min}
+	}
+	var values []float64
+	n := int(math.Floor((r.Max-r.Min)/r.Step + 1e-9))
+	for i := 0; i <= n; i++ {
+		// Round away the drift of repeated float steps
+		values = append(values, math.Round((r.Min+float64(i)*r.Step)*1e6)/1e6)
+	}
+	return values
+}
+
+// DefaultRanges are the ranges searched when none are given: probability
+// threshold, safety margin, Kelly fraction and stop loss, within the bounds
+// the parameters table allows them.
+func DefaultRanges() []Range {
+	return []Range{
+		{Name: "probability_threshold", Min: 0.75, Max: 0.95, Step: 0.05},
+		{Name: "volatility_safety_margin", Min: 1.0, Max: 2.5, Step: 0.5},
+		{Name: "kelly_fraction", Min: 0.10, Max: 0.40, Step: 0.10},
+		{Name: "stop_loss_percent", Min: 0.10, Max: 0.30, Step: 0.10},
+	}
+}
+
+// ParseRange parses a range written name=min:max:step.
+func ParseRange(s string) (Range, error) {
+	name, bounds, ok := strings.Cut(s, "=")
+	parts := strings.Split(bounds, ":")
+	if !ok || name == "" || len(parts) != 3 {
+		return Range{}, fmt.Errorf("range %q: expected name=min:max:step", s)
+	}
+	if _, known := (config.Parameters{}).Values()[name]; !known {
+		return Range{}, fmt.Errorf("range %q: unknown parameter %s", s, name)
+	}
+	var values [3]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return Range{}, fmt.Errorf("range %q: %w", s, err)
+		}
+		values[i] = v
+	}
+	r := Range{Name: name, Min: values[0], Max: values[1], Step: values[2]}
+	if r.Max < r.Min || r.Step < 0 {
+		return Range{}, fmt.Errorf("range %q: expected min <= max and a positive step", s)
+	}
+	return r, nil
+}
+
+// Grid returns every combination of the ranges' values, by parameter name.
+func Grid(ranges []Range) []map[string]float64 {
+	grid := []map[string]float64{{}}
+	for _, r := range ranges {
+		var next []map[string]float64
+		for _, candidate := range grid {
+			for _, v := range r.Values() {
+				c := make(map[string]float64, len(candidate)+1)
+				for name, value := range candidate {
+					c[name] = value
+				}
+				c[r.Name] = v
+				next = append(next, c)
+			}
+		}
+		grid = next
+	}
+	return grid
+}
+
+// Fold is one step of a walk-forward: parameters are fit on Train and
+// scored on Test, the stretch of history right after it.
+type Fold struct {
+	Train []backtest.Snapshot
+	Test  []backtest.Snapshot
+}
+
+// WalkForward splits snapshots, sorted by time, into folds+1 stretches of
+// equal duration. Fold i trains on the stretches up to and including i and
+// tests on the one after it, so each fold sees more history than the last.
+// Folds with an empty stretch are left out.
+func WalkForward(snapshots []backtest.Snapshot, folds int) []Fold {
+	if len(snapshots) == 0 || folds <= 0 {
+		return nil
+	}
+	start := snapshots[0].Timestamp
+	span := snapshots[len(snapshots)-1].Timestamp.Sub(start)
+	segment := span / time.Duration(folds+1)
+
+	var result []Fold
+	for i := 0; i < folds; i++ {
+		trainEnd := start.Add(segment * time.Duration(i+1))
+		testEnd := start.Add(segment * time.Duration(i+2))
+		if i == folds-1 {
+			testEnd = snapshots[len(snapshots)-1].Timestamp.Add(time.Nanosecond)
+		}
+		var fold Fold
+		for _, snap := range snapshots {
+			switch {
+			case snap.Timestamp.Before(trainEnd):
+				fold.Train = append(fold.Train, snap)
+			case snap.Timestamp.Before(testEnd):
+				fold.Test = append(fold.Test, snap)
+			}
+		}
+		if len(fold.Train) > 0 && len(fold.Test) > 0 {
+			result = append(result, fold)
+		}
+	}
+	return result
+}
+
+// Objective scores a backtest; higher is better.
+type Objective func(backtest.Summary) float64
+
+// Objectives are the objectives that can be optimized, by name.
+var Objectives = map[string]Objective{
+	"return": func(s backtest.Summary) float64 { return s.ReturnPercent },
+	"sharpe": func(s backtest.Summary) float64 { return s.SharpeRatio },
+}
+
+// Evaluator backtests parameters over snapshots.
+type Evaluator func(params config.Parameters, snapshots []backtest.Snapshot) (backtest.Summary, error)
+
+// BacktestEvaluator returns an Evaluator that runs the backtest engine with
+// base, its parameters and Kelly fraction replaced by those evaluated.
+func BacktestEvaluator(base backtest.Config) Evaluator {
+	return func(params config.Parameters, snapshots []backtest.Snapshot) (backtest.Summary, error) {
+		cfg := base
+		cfg.Parameters = params
+		cfg.Sizer.KellyFraction = params.KellyFraction
+		report, err := backtest.NewEngine(cfg).Run(snapshots)
+		if err != nil {
+			return backtest.Summary{}, err
+		}
+		return report.Summary, nil
+	}
+}
+
+// Optimizer grid-searches parameter ranges with walk-forward validation.
+type Optimizer struct {
+	base      config.Parameters
+	ranges    []Range
+	evaluate  Evaluator
+	objective Objective
+	workers   int
+}
+
+// NewOptimizer creates an optimizer of ranges over base, the parameters the
+// ones not searched keep, scoring backtests run by evaluate on objective
+// with up to workers at a time.
+func NewOptimizer(base config.Parameters, ranges []Range, evaluate Evaluator, objective Objective, workers int) *Optimizer {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Optimizer{base: base, ranges: ranges, evaluate: evaluate, objective: objective, workers: workers}
+}
+
+// Candidate is a set of searched parameter values and its score.
+type Candidate struct {
+	Values  map[string]float64
+	Score   float64
+	Summary backtest.Summary
+}
+
+// FoldResult is the best candidate fit on a fold's training stretch and how
+// it scored on the test stretch.
+type FoldResult struct {
+	TrainStart, TrainEnd time.Time
+	TestStart, TestEnd   time.Time
+	Best                 Candidate
+	// TestScore and Test are the best candidate's score and backtest on
+	// the test stretch.
+	TestScore float64
+	Test      backtest.Summary
+}
+
+// Result is the outcome of an optimization.
+type Result struct {
+	// Recommended is the best candidate over all the snapshots, and
+	// Parameters base with its values.
+	Recommended Candidate
+	Parameters  config.Parameters
+	// Candidates is how many parameter sets were searched.
+	Candidates int
+	Folds      []FoldResult
+}
+
+// Run fits the ranges on each fold's training stretch, scores the best fit
+// on its test stretch, then fits them on all the snapshots for the
+// recommended set.
+func (o *Optimizer) Run(snapshots []backtest.Snapshot, folds int) (*Result, error) {
+	grid := Grid(o.ranges)
+	result := &Result{Candidates: len(grid)}
+
+	for i, fold := range WalkForward(snapshots, folds) {
+		best, err := o.best(grid, fold.Train)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %w", i+1, err)
+		}
+		test, err := o.evaluate(o.base.WithValues(best.Values), fold.Test)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d test: %w", i+1, err)
+		}
+		result.Folds = append(result.Folds, FoldResult{
+			TrainStart: fold.Train[0].Timestamp,
+			TrainEnd:   fold.Train[len(fold.Train)-1].Timestamp,
+			TestStart:  fold.Test[0].Timestamp,
+			TestEnd:    fold.Test[len(fold.Test)-1].Timestamp,
+			Best:       best,
+			TestScore:  o.objective(test),
+			Test:       test,
+		})
+	}
+
+	best, err := o.best(grid, snapshots)
+	if err != nil {
+		return nil, fmt.Errorf("full history: %w", err)
+	}
+	result.Recommended = best
+	result.Parameters = o.base.WithValues(best.Values)
+	return result, nil
+}
+
+// best backtests every candidate in grid over snapshots and returns the
+// highest scoring, the first in grid order on a tie.
+func (o *Optimizer) best(grid []map[string]float64, snapshots []backtest.Snapshot) (Candidate, error) {
+	candidates := make([]Candidate, len(grid))
+	errs := make([]error, len(grid))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				summary, err := o.evaluate(o.base.WithValues(grid[i]), snapshots)
+				candidates[i] = Candidate{Values: grid[i], Score: o.objective(summary), Summary: summary}
+				errs[i] = err
+			}
+		}()
+	}
+	for i := range grid {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	best := -1
+	for i, c := range candidates {
+		if errs[i] != nil {
+			return Candidate{}, fmt.Errorf("backtest %s: %w", FormatValues(c.Values), errs[i])
+		}
+		if best < 0 || c.Score > candidates[best].Score {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Candidate{}, fmt.Errorf("no candidates to search")
+	}
+	return candidates[best], nil
+}
+
+// FormatValues formats parameter values as name=value pairs sorted by name.
+func FormatValues(values map[string]float64) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%g", name, values[name])
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,158 @@
+package optimize
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/config"
+)
+
+func TestRange_Values(t *testing.T) {
+	got := Range{Name: "probability_threshold", Min: 0.75, Max: 0.95, Step: 0.05}.Values()
+	want := []float64{0.75, 0.8, 0.85, 0.9, 0.95}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := (Range{Min: 0.5, Max: 0.5}).Values(); !reflect.DeepEqual(got, []float64{0.5}) {
+		t.Errorf("expected a fixed value, got %v", got)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	r, err := ParseRange("kelly_fraction=0.1:0.3:0.1")
+	if err != nil {
+		t.Fatalf("ParseRange failed: %v", err)
+	}
+	if r != (Range{Name: "kelly_fraction", Min: 0.1, Max: 0.3, Step: 0.1}) {
+		t.Errorf("unexpected range %+v", r)
+	}
+	for _, bad := range []string{"kelly_fraction", "kelly_fraction=0.1:0.3", "unknown=0:1:0.1", "kelly_fraction=0.3:0.1:0.1", "kelly_fraction=a:b:c"} {
+		if _, err := ParseRange(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	grid := Grid(DefaultRanges())
+	if len(grid) != 5*4*4*3 {
+		t.Errorf("expected 240 candidates, got %d", len(grid))
+	}
+	if len(grid[0]) != 4 {
+		t.Errorf("expected each candidate to set the 4 parameters, got %v", grid[0])
+	}
+}
+
+// snapshotsEvery returns n snapshots a day apart.
+func snapshotsEvery(n int) []backtest.Snapshot {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := make([]backtest.Snapshot, n)
+	for i := range snapshots {
+		snapshots[i].Timestamp = start.AddDate(0, 0, i)
+	}
+	return snapshots
+}
+
+func TestWalkForward(t *testing.T) {
+	folds := WalkForward(snapshotsEvery(50), 4)
+	if len(folds) != 4 {
+		t.Fatalf("expected 4 folds, got %d", len(folds))
+	}
+	for i, f := range folds {
+		// Training grows by a stretch each fold and ends where the test starts
+		if i > 0 && len(f.Train) <= len(folds[i-1].Train) {
+			t.Errorf("fold %d: expected more training than the fold before, got %d", i+1, len(f.Train))
+		}
+		if !f.Train[len(f.Train)-1].Timestamp.Before(f.Test[0].Timestamp) {
+			t.Errorf("fold %d: expected the test after the training", i+1)
+		}
+	}
+	last := folds[len(folds)-1]
+	if len(last.Train)+len(last.Test) != 50 {
+		t.Errorf("expected the last fold to cover every snapshot, got %d", len(last.Train)+len(last.Test))
+	}
+}
+
+func TestOptimizer_Run(t *testing.T) {
+	snapshots := snapshotsEvery(50)
+	mid := snapshots[25].Timestamp
+
+	// A threshold of 0.85 does best early, 0.9 late: each fold fits the
+	// mostly early history it saw, which doesn't hold up on the later tests
+	evaluate := func(params config.Parameters, snaps []backtest.Snapshot) (backtest.Summary, error) {
+		var ret float64
+		for _, s := range snaps {
+			best := 0.85
+			if !s.Timestamp.Before(mid) {
+				best = 0.90
+			}
+			if params.ProbabilityThreshold == best {
+				ret++
+			} else {
+				ret -= 0.5
+			}
+		}
+		return backtest.Summary{ReturnPercent: ret, TotalTrades: len(snaps)}, nil
+	}
+	ranges := []Range{{Name: "probability_threshold", Min: 0.80, Max: 0.90, Step: 0.05}}
+	optimizer := NewOptimizer(config.Parameters{KellyFraction: 0.25}, ranges, evaluate, Objectives["return"], 2)
+
+	result, err := optimizer.Run(snapshots, 4)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Candidates != 3 || len(result.Folds) != 4 {
+		t.Fatalf("expected 3 candidates over 4 folds, got %d over %d", result.Candidates, len(result.Folds))
+	}
+	if got := result.Folds[0].Best.Values["probability_threshold"]; got != 0.85 {
+		t.Errorf("expected the first fold to fit 0.85, got %v", got)
+	}
+	if result.Parameters.ProbabilityThreshold != result.Recommended.Values["probability_threshold"] || result.Parameters.KellyFraction != 0.25 {
+		t.Errorf("expected the recommended threshold over the base parameters, got %+v", result.Parameters)
+	}
+
+	o := result.Overfitting()
+	if o.InSample <= o.OutOfSample {
+		t.Errorf("expected out-of-sample below in-sample, got %+v", o)
+	}
+	if !reflect.DeepEqual(o.Spread["probability_threshold"], []float64{0.85}) || o.Stable != 4 {
+		t.Errorf("expected every fold to pick the recommended 0.85, got %+v", o)
+	}
+
+	var report, params bytes.Buffer
+	WriteReport(&report, result, "return")
+	if !strings.Contains(report.String(), "Overfitting") || !strings.Contains(report.String(), "Recommended") {
+		t.Errorf("expected the report to show the overfitting and recommendation, got:\n%s", report.String())
+	}
+	if err := WriteParameters(&params, result, "return"); err != nil {
+		t.Fatalf("WriteParameters failed: %v", err)
+	}
+	if !strings.Contains(params.String(), "parameters:\n  probability_threshold: ") {
+		t.Errorf("expected a parameters section, got:\n%s", params.String())
+	}
+}
+
+func TestResult_Overfitting(t *testing.T) {
+	stable := map[string]float64{"kelly_fraction": 0.2}
+	result := &Result{
+		Recommended: Candidate{Values: stable},
+		Folds: []FoldResult{
+			{Best: Candidate{Values: stable, Score: 10}, TestScore: 8},
+			{Best: Candidate{Values: map[string]float64{"kelly_fraction": 0.3}, Score: 10}, TestScore: 2},
+		},
+	}
+	o := result.Overfitting()
+	if o.InSample != 10 || o.OutOfSample != 5 || o.Efficiency != 0.5 || o.PositiveFolds != 2 || o.Stable != 1 || o.Likely {
+		t.Errorf("unexpected report %+v", o)
+	}
+
+	// Losing out of sample is overfit however well it fit
+	result.Folds[1].TestScore = -9
+	if o := result.Overfitting(); !o.Likely {
+		t.Errorf("expected a losing fit reported overfit, got %+v", o)
+	}
+}
@@ -0,0 +1,138 @@
+package optimize
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MinEfficiency is the walk-forward efficiency (out-of-sample over
+// in-sample score) below which a fit is reported as likely overfit.
+const MinEfficiency = 0.5
+
+// Overfitting measures how much of the fitted performance held up out of
+// sample across the folds.
+type Overfitting struct {
+	// InSample and OutOfSample are the mean scores of each fold's best
+	// candidate on its training and test stretches.
+	InSample    float64
+	OutOfSample float64
+	// Efficiency is OutOfSample over InSample, 0 if InSample isn't
+	// positive.
+	Efficiency float64
+	// PositiveFolds is how many folds scored above zero out of sample.
+	PositiveFolds int
+	// Stable is how many folds picked the recommended values.
+	Stable int
+	// Spread is the values each parameter was picked with across the
+	// folds, sorted.
+	Spread map[string][]float64
+	// Likely is set when the fit is likely overfit: out of sample it lost,
+	// or kept less than MinEfficiency of its in-sample score.
+	Likely bool
+}
+
+// Overfitting returns the overfitting report of the walk-forward folds.
+func (r *Result) Overfitting() Overfitting {
+	o := Overfitting{Spread: make(map[string][]float64)}
+	if len(r.Folds) == 0 {
+		return o
+	}
+	for _, f := range r.Folds {
+		o.InSample += f.Best.Score
+		o.OutOfSample += f.TestScore
+		if f.TestScore > 0 {
+			o.PositiveFolds++
+		}
+		if reflect.DeepEqual(f.Best.Values, r.Recommended.Values) {
+			o.Stable++
+		}
+		for name, value := range f.Best.Values {
+			if !contains(o.Spread[name], value) {
+				o.Spread[name] = append(o.Spread[name], value)
+			}
+		}
+	}
+	o.InSample /= float64(len(r.Folds))
+	o.OutOfSample /= float64(len(r.Folds))
+	if o.InSample > 0 {
+		o.Efficiency = o.OutOfSample / o.InSample
+	}
+	for _, values := range o.Spread {
+		sort.Float64s(values)
+	}
+	o.Likely = o.OutOfSample <= 0 || o.Efficiency < MinEfficiency
+	return o
+}
+
+// WriteReport writes each fold's fit and test scores and the overfitting
+// report.
+func WriteReport(w io.Writer, r *Result, objective string) {
+	fmt.Fprintf(w, "Walk-forward: %d folds, %d candidates, objective %s\n\n", len(r.Folds), r.Candidates, objective)
+	fmt.Fprintf(w, "%-4s %-23s %-23s %10s %10s %7s  %s\n", "FOLD", "TRAIN", "TEST", "IN", "OUT", "TRADES", "BEST")
+	for i, f := range r.Folds {
+		fmt.Fprintf(w, "%-4d %-23s %-23s %10.2f %10.2f %7d  %s\n", i+1,
+			formatSpan(f.TrainStart, f.TrainEnd), formatSpan(f.TestStart, f.TestEnd),
+			f.Best.Score, f.TestScore, f.Test.TotalTrades, FormatValues(f.Best.Values))
+	}
+
+	o := r.Overfitting()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Overfitting")
+	fmt.Fprintf(w, "  In-sample score:      %.2f\n", o.InSample)
+	fmt.Fprintf(w, "  Out-of-sample score:  %.2f\n", o.OutOfSample)
+	fmt.Fprintf(w, "  Efficiency:           %.0f%%\n", o.Efficiency*100)
+	fmt.Fprintf(w, "  Positive folds:       %d of %d\n", o.PositiveFolds, len(r.Folds))
+	fmt.Fprintf(w, "  Picked recommended:   %d of %d folds\n", o.Stable, len(r.Folds))
+	names := make([]string, 0, len(o.Spread))
+	for name := range o.Spread {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-22s%v\n", name+":", o.Spread[name])
+	}
+	if o.Likely {
+		fmt.Fprintln(w, "  Likely overfit: the fit didn't hold up out of sample.")
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Recommended (%.2f over all history, %d trades): %s\n",
+		r.Recommended.Score, r.Recommended.Summary.TotalTrades, FormatValues(r.Recommended.Values))
+}
+
+// WriteParameters writes the recommended values as a parameters section of
+// config.yaml.
+func WriteParameters(w io.Writer, r *Result, objective string) error {
+	o := r.Overfitting()
+	fmt.Fprintf(w, "# Recommended by optimize on %s: %.2f over all history, %.2f out of sample (%.0f%% efficiency)\n",
+		objective, r.Recommended.Score, o.OutOfSample, o.Efficiency*100)
+	if o.Likely {
+		fmt.Fprintln(w, "# Likely overfit: check the report before using these.")
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]map[string]float64{"parameters": r.Recommended.Values}); err != nil {
+		return fmt.Errorf("encode parameters: %w", err)
+	}
+	return enc.Close()
+}
+
+// formatSpan formats a stretch of history as its first and last day.
+func formatSpan(start, end time.Time) string {
+	return start.UTC().Format("2006-01-02") + ".." + end.UTC().Format("2006-01-02")
+}
+
+// contains reports whether values holds v.
+func contains(values []float64, v float64) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
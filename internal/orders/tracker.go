@@ -0,0 +1,374 @@
+// Package orders manages the lifecycle of orders placed by the bot.
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ExitReasonUnfilled closes a position whose entry orders ended without any fill.
+const ExitReasonUnfilled = "entry_unfilled"
+
+// ErrNoTrader is returned when no trader is registered for a platform.
+var ErrNoTrader = errors.New("no trader registered for platform")
+
+// Tracker persists orders, polls platforms for their fill status and keeps
+// the quantity of linked positions in line with what was actually executed.
+type Tracker struct {
+	orderRepo    *persistence.OrderRepository
+	positionRepo *persistence.PositionRepository
+	bankrollRepo *persistence.BankrollRepository
+	traders      map[string]platform.Trader
+}
+
+// NewTracker creates a new order tracker with the given repositories.
+func NewTracker(
+	orderRepo *persistence.OrderRepository,
+	positionRepo *persistence.PositionRepository,
+	bankrollRepo *persistence.BankrollRepository,
+) *Tracker {
+	return &Tracker{
+		orderRepo:    orderRepo,
+		positionRepo: positionRepo,
+		bankrollRepo: bankrollRepo,
+		traders:      make(map[string]platform.Trader),
+	}
+}
+
+// SetTrader registers the trader used for orders on a platform.
+func (t *Tracker) SetTrader(platformName string, trader platform.Trader) {
+	t.traders[platformName] = trader
+}
+
+// Place submits an order and records it. A non-zero positionID links the
+// order to the position it opens, so fills are reflected in its quantity.
+func (t *Tracker) Place(ctx context.Context, platformName string, order types.Order, positionID int64, dryRun bool) (types.OrderResult, error) {
+	trader, ok := t.traders[platformName]
+	if !ok {
+		return types.OrderResult{}, fmt.Errorf("%w: %s", ErrNoTrader, platformName)
+	}
+
+	result, err := trader.PlaceOrder(ctx, order, dryRun)
+	if err != nil {
+		return result, fmt.Errorf("place order: %w", err)
+	}
+
+	// Simulated orders fill immediately and completely
+	if result.Status == types.OrderStatusSimulated {
+		result.Filled = result.Size
+	}
+
+	record := &persistence.Order{
+		OrderID:  result.OrderID,
+		Platform: platformName,
+		MarketID: order.MarketID,
+		TokenID:  order.TokenID,
+		Side:     string(order.Side),
+		Price:    result.Price,
+		Size:     result.Size,
+		Filled:   result.Filled,
+		Fees:     result.Fees,
+		Status:   string(result.Status),
+		IsDryRun: result.IsDryRun,
+	}
+	if positionID != 0 {
+		record.PositionID = &positionID
+	}
+
+	if _, err := t.orderRepo.Create(record); err != nil {
+		return result, fmt.Errorf("record order: %w", err)
+	}
+
+	if record.Fees > 0 {
+		if err := t.chargeFees(record, types.Dollars(record.Fees)); err != nil {
+			return result, err
+		}
+	}
+
+	// Orders that are already final (e.g. IOC) settle right away
+	if !result.IsResting() && !result.IsDryRun {
+		if err := t.settle(record); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// Poll refreshes the status of every active order and applies fills.
+// Errors for individual orders are logged and do not stop the poll.
+func (t *Tracker) Poll() error {
+	active, err := t.orderRepo.GetActive()
+	if err != nil {
+		return fmt.Errorf("get active orders: %w", err)
+	}
+
+	for _, o := range active {
+		trader, ok := t.traders[o.Platform]
+		if !ok {
+			continue
+		}
+
+		status, err := trader.GetOrderStatus(o.OrderID)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("platform", o.Platform).
+				Str("order_id", o.OrderID).
+				Msg("failed to get order status")
+			continue
+		}
+
+		if err := t.apply(o, status); err != nil {
+			log.Error().
+				Err(err).
+				Str("platform", o.Platform).
+				Str("order_id", o.OrderID).
+				Msg("failed to apply order update")
+		}
+	}
+
+	return nil
+}
+
+// GetOrderStatus returns the current state of an order from its platform
+// and records any change.
+func (t *Tracker) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	o, trader, err := t.lookup(orderID)
+	if err != nil {
+		return types.OrderResult{}, err
+	}
+
+	status, err := trader.GetOrderStatus(orderID)
+	if err != nil {
+		return status, fmt.Errorf("get order status: %w", err)
+	}
+
+	return status, t.apply(o, status)
+}
+
+// CancelOrder cancels an order and records its final state.
+func (t *Tracker) CancelOrder(orderID string) error {
+	o, trader, err := t.lookup(orderID)
+	if err != nil {
+		return err
+	}
+
+	if err := trader.CancelOrder(orderID); err != nil {
+		return fmt.Errorf("cancel order: %w", err)
+	}
+
+	// Re-read to capture any fills that happened before the cancel
+	status, err := trader.GetOrderStatus(orderID)
+	if err != nil {
+		return fmt.Errorf("get order status after cancel: %w", err)
+	}
+
+	return t.apply(o, status)
+}
+
+// lookup returns a stored order and the trader for its platform.
+func (t *Tracker) lookup(orderID string) (*persistence.Order, platform.Trader, error) {
+	o, err := t.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o == nil {
+		return nil, nil, fmt.Errorf("order not found: %s", orderID)
+	}
+
+	trader, ok := t.traders[o.Platform]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrNoTrader, o.Platform)
+	}
+
+	return o, trader, nil
+}
+
+// apply records a status update for a stored order and propagates fills and
+// fees to the linked position.
+func (t *Tracker) apply(o *persistence.Order, status types.OrderResult) error {
+	if status.Filled == o.Filled && status.Fees == o.Fees && string(status.Status) == o.Status {
+		return nil
+	}
+
+	wasResting := isResting(o.Status)
+	newFees := types.Dollars(status.Fees) - types.Dollars(o.Fees)
+
+	if err := t.orderRepo.UpdateFill(o.OrderID, status.Filled, status.Fees, string(status.Status)); err != nil {
+		return err
+	}
+	o.Filled = status.Filled
+	o.Fees = status.Fees
+	o.Status = string(status.Status)
+
+	if newFees > 0 {
+		if err := t.chargeFees(o, newFees); err != nil {
+			return err
+		}
+	}
+
+	log.Info().
+		Str("platform", o.Platform).
+		Str("order_id", o.OrderID).
+		Str("status", o.Status).
+		Float64("filled", o.Filled).
+		Float64("size", o.Size).
+		Msg("order updated")
+
+	if wasResting && !status.IsResting() {
+		return t.settle(o)
+	}
+
+	return t.syncPosition(o)
+}
+
+// chargeFees deducts platform fees charged on an order from the bankroll and
+// adds them to the linked position, so its realized PnL is net of them.
+// o.Fees must already include fees: the order's total fees key the charge.
+func (t *Tracker) chargeFees(o *persistence.Order, fees types.Money) error {
+	key := persistence.OrderKey(o.OrderID, fmt.Sprintf("%s:%d", persistence.BankrollOpFees, types.Dollars(o.Fees)))
+	applied, err := t.bankrollRepo.AddToBalanceOnce(o.Platform, -fees, key)
+	if err != nil {
+		return fmt.Errorf("deduct order fees: %w", err)
+	}
+	if !applied {
+		log.Warn().
+			Str("platform", o.Platform).
+			Str("order_id", o.OrderID).
+			Float64("fees", fees.Float64()).
+			Msg("order fees already charged, skipping")
+		return nil
+	}
+	log.Info().
+		Str("platform", o.Platform).
+		Str("order_id", o.OrderID).
+		Float64("fees", fees.Float64()).
+		Msg("order fees charged")
+
+	if o.PositionID == nil {
+		return nil
+	}
+
+	// Retry once if another writer updated the position concurrently
+	for attempt := 0; attempt < 2; attempt++ {
+		pos, err := t.positionRepo.GetByID(*o.PositionID)
+		if err != nil {
+			return err
+		}
+		if pos == nil {
+			return nil
+		}
+
+		pos.Fees = (types.Dollars(pos.Fees) + fees).Float64()
+		err = t.positionRepo.Update(pos)
+		if errors.Is(err, persistence.ErrConflict) {
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("record fees on position %d: %w", *o.PositionID, persistence.ErrConflict)
+}
+
+// settle handles an order reaching a final state: unfilled entry size is
+// refunded to the bankroll and the position quantity is finalized.
+func (t *Tracker) settle(o *persistence.Order) error {
+	if o.PositionID == nil || o.Side != string(types.OrderSideBuy) {
+		return nil
+	}
+
+	if unfilled := o.Size - o.Filled; unfilled > 0 {
+		refund := types.Cost(o.Price, unfilled)
+		applied, err := t.bankrollRepo.AddToBalanceOnce(o.Platform, refund, persistence.OrderKey(o.OrderID, persistence.BankrollOpRefund))
+		if err != nil {
+			return fmt.Errorf("refund unfilled order: %w", err)
+		}
+		if !applied {
+			log.Warn().
+				Str("platform", o.Platform).
+				Str("order_id", o.OrderID).
+				Msg("unfilled order size already refunded, skipping")
+			return t.syncPosition(o)
+		}
+		log.Info().
+			Str("platform", o.Platform).
+			Str("order_id", o.OrderID).
+			Float64("unfilled", unfilled).
+			Float64("refund", refund.Float64()).
+			Msg("refunded unfilled order size")
+	}
+
+	return t.syncPosition(o)
+}
+
+// syncPosition sets the linked position's quantity to the total filled by
+// its entry orders. A position whose entry orders all ended without a fill
+// is closed.
+func (t *Tracker) syncPosition(o *persistence.Order) error {
+	if o.PositionID == nil || o.Side != string(types.OrderSideBuy) {
+		return nil
+	}
+
+	entries, err := t.orderRepo.GetByPosition(*o.PositionID)
+	if err != nil {
+		return err
+	}
+
+	var filled float64
+	resting := false
+	for _, e := range entries {
+		if e.Side != string(types.OrderSideBuy) {
+			continue
+		}
+		filled += e.Filled
+		if isResting(e.Status) {
+			resting = true
+		}
+	}
+
+	// Until something fills, keep the planned quantity so the position is
+	// still recognizable; it is closed below if nothing ever fills
+	if filled == 0 && resting {
+		return nil
+	}
+
+	// Retry once if another writer updated the position concurrently
+	for attempt := 0; attempt < 2; attempt++ {
+		pos, err := t.positionRepo.GetByID(*o.PositionID)
+		if err != nil {
+			return err
+		}
+		if pos == nil || pos.Status == persistence.PositionStatusClosed {
+			return nil
+		}
+
+		if filled == 0 {
+			return t.positionRepo.Close(pos.ID, pos.EntryPrice, ExitReasonUnfilled, 0)
+		}
+
+		if pos.Quantity == filled {
+			return nil
+		}
+		pos.Quantity = filled
+		err = t.positionRepo.Update(pos)
+		if errors.Is(err, persistence.ErrConflict) {
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("update position %d quantity: %w", *o.PositionID, persistence.ErrConflict)
+}
+
+// isResting reports whether a stored order status can still fill.
+func isResting(status string) bool {
+	return types.OrderResult{Status: types.OrderStatus(status)}.IsResting()
+}
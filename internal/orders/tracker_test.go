@@ -0,0 +1,303 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/platform"
+	"prediction-bot/pkg/types"
+)
+
+// setupTestDB creates a temporary test database with migrations.
+func setupTestDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+
+	tmpFile, err := os.CreateTemp("", "test_orders_*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpFile.Close()
+
+	db, err := persistence.OpenDB(tmpFile.Name())
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		db.Close()
+		os.Remove(tmpFile.Name())
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	cleanup := func() {
+		db.Close()
+		os.Remove(tmpFile.Name())
+	}
+
+	return db, cleanup
+}
+
+// MockTrader simulates a platform's order book state for testing.
+type MockTrader struct {
+	orders map[string]types.OrderResult
+	nextID int
+}
+
+var _ platform.Trader = (*MockTrader)(nil)
+
+func newMockTrader() *MockTrader {
+	return &MockTrader{orders: make(map[string]types.OrderResult)}
+}
+
+func (m *MockTrader) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	m.nextID++
+	result := types.OrderResult{
+		OrderID:  fmt.Sprintf("order-%d", m.nextID),
+		MarketID: order.MarketID,
+		TokenID:  order.TokenID,
+		Side:     order.Side,
+		Price:    order.Price,
+		Size:     order.Size,
+		Status:   types.OrderStatusOpen,
+		IsDryRun: dryRun,
+	}
+	if dryRun {
+		result.OrderID = "dryrun-" + result.OrderID
+		result.Status = types.OrderStatusSimulated
+	}
+	m.orders[result.OrderID] = result
+	return result, nil
+}
+
+func (m *MockTrader) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	o, ok := m.orders[orderID]
+	if !ok {
+		return o, fmt.Errorf("order %s not found", orderID)
+	}
+	return o, nil
+}
+
+func (m *MockTrader) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	var open []types.OrderResult
+	for _, o := range m.orders {
+		if o.MarketID == marketID && o.IsResting() {
+			open = append(open, o)
+		}
+	}
+	return open, nil
+}
+
+func (m *MockTrader) CancelOrder(orderID string) error {
+	o := m.orders[orderID]
+	o.Status = types.OrderStatusCancelled
+	m.orders[orderID] = o
+	return nil
+}
+
+// fill simulates the exchange filling part of an order.
+func (m *MockTrader) fill(orderID string, filled float64) {
+	o := m.orders[orderID]
+	o.Filled = filled
+	o.Status = types.OrderStatusPartial
+	if filled >= o.Size {
+		o.Status = types.OrderStatusFilled
+	}
+	m.orders[orderID] = o
+}
+
+// setupTracker creates a tracker with a funded bankroll and an open position
+// of 10 contracts at 0.80 whose cost has already been deducted.
+func setupTracker(t *testing.T) (*Tracker, *MockTrader, *persistence.PositionRepository, *persistence.BankrollRepository, int64, func()) {
+	t.Helper()
+
+	db, cleanup := setupTestDB(t)
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 42.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "market-1",
+		EntryPrice: 0.80,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     persistence.PositionStatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+
+	trader := newMockTrader()
+	tracker := NewTracker(persistence.NewOrderRepository(db), positionRepo, bankrollRepo)
+	tracker.SetTrader("polymarket", trader)
+
+	return tracker, trader, positionRepo, bankrollRepo, positionID, cleanup
+}
+
+func entryOrder() types.Order {
+	return types.Order{
+		MarketID: "market-1",
+		TokenID:  "token-yes",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    0.80,
+		Size:     10.0,
+	}
+}
+
+func TestTrackerPartialFillAdjustsPositionQuantity(t *testing.T) {
+	tracker, trader, positionRepo, bankrollRepo, positionID, cleanup := setupTracker(t)
+	defer cleanup()
+
+	result, err := tracker.Place(context.Background(), "polymarket", entryOrder(), positionID, false)
+	if err != nil {
+		t.Fatalf("Place failed: %v", err)
+	}
+
+	// Exchange fills 4 of 10
+	trader.fill(result.OrderID, 4)
+	if err := tracker.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Quantity != 4 {
+		t.Errorf("Expected quantity 4 after partial fill, got %f", pos.Quantity)
+	}
+
+	// Remaining size is cancelled
+	if err := tracker.CancelOrder(result.OrderID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+
+	pos, _ = positionRepo.GetByID(positionID)
+	if pos.Quantity != 4 {
+		t.Errorf("Expected quantity to stay 4 after cancel, got %f", pos.Quantity)
+	}
+	if pos.Status != persistence.PositionStatusOpen {
+		t.Errorf("Expected position to stay open, got %s", pos.Status)
+	}
+
+	// Unfilled 6 contracts at 0.80 are refunded
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount < 46.799 || bankroll.CurrentAmount > 46.801 {
+		t.Errorf("Expected bankroll 46.80 after refund, got %f", bankroll.CurrentAmount)
+	}
+
+	// Settled orders are no longer polled
+	status, err := tracker.GetOrderStatus(result.OrderID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != types.OrderStatusCancelled {
+		t.Errorf("Expected cancelled status, got %s", status.Status)
+	}
+}
+
+func TestTrackerUnfilledEntryClosesPosition(t *testing.T) {
+	tracker, _, positionRepo, bankrollRepo, positionID, cleanup := setupTracker(t)
+	defer cleanup()
+
+	result, err := tracker.Place(context.Background(), "polymarket", entryOrder(), positionID, false)
+	if err != nil {
+		t.Fatalf("Place failed: %v", err)
+	}
+
+	// Nothing fills yet: quantity is left as planned
+	if err := tracker.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Quantity != 10 {
+		t.Errorf("Expected planned quantity 10 while resting, got %f", pos.Quantity)
+	}
+
+	if err := tracker.CancelOrder(result.OrderID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+
+	pos, _ = positionRepo.GetByID(positionID)
+	if pos.Status != persistence.PositionStatusClosed {
+		t.Errorf("Expected position closed, got %s", pos.Status)
+	}
+	if pos.ExitReason == nil || *pos.ExitReason != ExitReasonUnfilled {
+		t.Errorf("Expected exit reason %s, got %v", ExitReasonUnfilled, pos.ExitReason)
+	}
+
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 50.0 {
+		t.Errorf("Expected full refund to 50.0, got %f", bankroll.CurrentAmount)
+	}
+}
+
+func TestTrackerDryRunOrderIsFilled(t *testing.T) {
+	tracker, _, positionRepo, bankrollRepo, positionID, cleanup := setupTracker(t)
+	defer cleanup()
+
+	result, err := tracker.Place(context.Background(), "polymarket", entryOrder(), positionID, true)
+	if err != nil {
+		t.Fatalf("Place failed: %v", err)
+	}
+	if result.Filled != 10 {
+		t.Errorf("Expected simulated order fully filled, got %f", result.Filled)
+	}
+
+	if err := tracker.Poll(); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Quantity != 10 {
+		t.Errorf("Expected quantity 10, got %f", pos.Quantity)
+	}
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 42.0 {
+		t.Errorf("Expected bankroll untouched at 42.0, got %f", bankroll.CurrentAmount)
+	}
+}
+
+func TestTrackerChargesOrderFees(t *testing.T) {
+	tracker, trader, positionRepo, bankrollRepo, positionID, cleanup := setupTracker(t)
+	defer cleanup()
+
+	result, err := tracker.Place(context.Background(), "polymarket", entryOrder(), positionID, false)
+	if err != nil {
+		t.Fatalf("Place failed: %v", err)
+	}
+
+	// Fees accrue as the order fills and are only charged once
+	o := trader.orders[result.OrderID]
+	o.Filled, o.Fees, o.Status = 10, 0.14, types.OrderStatusFilled
+	trader.orders[result.OrderID] = o
+	for i := 0; i < 2; i++ {
+		if err := tracker.Poll(); err != nil {
+			t.Fatalf("Poll failed: %v", err)
+		}
+	}
+
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Fees < 0.1399 || pos.Fees > 0.1401 {
+		t.Errorf("Expected position fees 0.14, got %f", pos.Fees)
+	}
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount < 41.8599 || bankroll.CurrentAmount > 41.8601 {
+		t.Errorf("Expected bankroll 41.86 after fees, got %f", bankroll.CurrentAmount)
+	}
+}
+
+func TestTrackerRequiresTrader(t *testing.T) {
+	tracker, _, _, _, positionID, cleanup := setupTracker(t)
+	defer cleanup()
+
+	if _, err := tracker.Place(context.Background(), "kalshi", entryOrder(), positionID, false); err == nil {
+		t.Error("Expected error for platform without trader")
+	}
+}
@@ -0,0 +1,303 @@
+// Package paper simulates order execution against live order books, so
+// dry-run fills see the partial fills, queue position and slippage that
+// live orders would.
+package paper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// epsilon is the quantity below which an order counts as completely filled.
+const epsilon = 1e-9
+
+// ErrUnknownOrder is returned for an order ID the exchange didn't issue.
+var ErrUnknownOrder = errors.New("unknown paper order")
+
+// BookSource provides a platform's live order books.
+type BookSource interface {
+	GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error)
+}
+
+// Exchange simulates a platform's matching engine against its live order
+// books, implementing the orderer and canceller interfaces of the position
+// manager. Orders that cross the book take liquidity level by level, up to
+// their limit price. A limit order that rests joins the back of the queue
+// at its price and fills once the size displayed at that price has shrunk
+// by more than was ahead of it. Shrinking is assumed to be trading, so
+// fills are optimistic when orders ahead are cancelled instead.
+//
+// The live book never sees simulated orders, so liquidity taken by one
+// order is still there for the next book fetched.
+type Exchange struct {
+	source  BookSource
+	feeRate float64
+	mu      sync.Mutex
+	orders  map[string]*order
+	nextID  int
+	now     func() time.Time
+}
+
+// order is a simulated order and its place in the queue.
+type order struct {
+	result     types.OrderResult
+	orderType  types.OrderType
+	notional   float64 // Value filled so far, for the average price
+	queueAhead float64 // Size ahead of the order at its price
+	levelSize  float64 // Size displayed at its price in the last book
+}
+
+// NewExchange creates an Exchange filling orders against source's books and
+// charging feeRate as a fraction of filled notional.
+func NewExchange(source BookSource, feeRate float64) *Exchange {
+	return &Exchange{
+		source:  source,
+		feeRate: feeRate,
+		orders:  make(map[string]*order),
+		now:     time.Now,
+	}
+}
+
+// PlaceOrder fills what the order can take from the current book. Market,
+// IOC and FOK orders are cancelled with whatever they filled; a FOK order
+// that can't fill completely fills nothing. The rest of a GTC limit order
+// rests. dryRun is ignored: paper orders are always simulated.
+func (e *Exchange) PlaceOrder(ctx context.Context, o types.Order, dryRun bool) (types.OrderResult, error) {
+	if o.Size <= 0 {
+		return types.OrderResult{}, fmt.Errorf("invalid order size %v", o.Size)
+	}
+	book, err := e.source.GetOrderBook(ctx, o.TokenID)
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order book: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextID++
+	ord := &order{
+		result: types.OrderResult{
+			OrderID:   fmt.Sprintf("paper-%d", e.nextID),
+			MarketID:  o.MarketID,
+			TokenID:   o.TokenID,
+			Side:      o.Side,
+			Price:     o.Price,
+			Size:      o.Size,
+			Status:    types.OrderStatusOpen,
+			IsDryRun:  true,
+			CreatedAt: e.now(),
+		},
+		orderType: o.Type,
+	}
+	e.orders[ord.result.OrderID] = ord
+
+	if o.TimeInForce == types.TimeInForceFOK && ord.available(book) < o.Size-epsilon {
+		ord.result.Status = types.OrderStatusCancelled
+		return ord.result, nil
+	}
+
+	ord.take(book, e.feeRate)
+	switch {
+	case ord.remaining() <= epsilon:
+		ord.result.Status = types.OrderStatusFilled
+	case o.Type == types.OrderTypeMarket || o.TimeInForce == types.TimeInForceIOC || o.TimeInForce == types.TimeInForceFOK:
+		ord.result.Status = types.OrderStatusCancelled
+	default:
+		ord.queueAhead = sizeAt(ord.ownSide(book), o.Price)
+		ord.levelSize = ord.queueAhead
+		ord.updateStatus()
+	}
+	return ord.result, nil
+}
+
+// GetOrderStatus returns an order's state. A resting order is first matched
+// against the current book: it takes any liquidity now crossing its price,
+// then fills from the queue.
+func (e *Exchange) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	e.mu.Lock()
+	ord, ok := e.orders[orderID]
+	if !ok {
+		e.mu.Unlock()
+		return types.OrderResult{}, fmt.Errorf("%w: %s", ErrUnknownOrder, orderID)
+	}
+	result := ord.result
+	e.mu.Unlock()
+	if !result.IsResting() {
+		return result, nil
+	}
+
+	book, err := e.source.GetOrderBook(context.Background(), result.TokenID)
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order book: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if ord.result.IsResting() {
+		ord.take(book, e.feeRate)
+		ord.queue(book, e.feeRate)
+		ord.updateStatus()
+	}
+	return ord.result, nil
+}
+
+// CancelOrder cancels a resting order. Cancelling an order that is no
+// longer resting has no effect.
+func (e *Exchange) CancelOrder(orderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ord, ok := e.orders[orderID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownOrder, orderID)
+	}
+	if ord.result.IsResting() {
+		ord.result.Status = types.OrderStatusCancelled
+	}
+	return nil
+}
+
+// GetOpenOrders returns the resting orders for a market, oldest first.
+func (e *Exchange) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var open []types.OrderResult
+	for _, ord := range e.orders {
+		if ord.result.MarketID == marketID && ord.result.IsResting() {
+			open = append(open, ord.result)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].CreatedAt.Before(open[j].CreatedAt) ||
+			open[i].CreatedAt.Equal(open[j].CreatedAt) && open[i].OrderID < open[j].OrderID
+	})
+	return open, nil
+}
+
+// take fills the order from the opposite side of the book, best price
+// first, for as long as the price is within its limit.
+func (o *order) take(book *types.OrderBook, feeRate float64) {
+	for _, level := range o.oppositeSide(book) {
+		if o.remaining() <= epsilon || !o.marketable(level.Price) {
+			return
+		}
+		o.fill(min(level.Size, o.remaining()), level.Price, feeRate)
+	}
+}
+
+// queue moves a resting order up its price level by the size that left the
+// level since the last book, filling it once nothing is ahead.
+func (o *order) queue(book *types.OrderBook, feeRate float64) {
+	level := sizeAt(o.ownSide(book), o.result.Price)
+	traded := o.levelSize - level
+	o.levelSize = level
+	if traded <= 0 || o.remaining() <= epsilon {
+		return
+	}
+
+	if traded <= o.queueAhead {
+		o.queueAhead -= traded
+		return
+	}
+	traded -= o.queueAhead
+	o.queueAhead = 0
+	o.fill(min(traded, o.remaining()), o.result.Price, feeRate)
+}
+
+// available returns the size the order could take from the book.
+func (o *order) available(book *types.OrderBook) float64 {
+	var size float64
+	for _, level := range o.oppositeSide(book) {
+		if !o.marketable(level.Price) {
+			break
+		}
+		size += level.Size
+	}
+	return size
+}
+
+// fill records quantity filled at price.
+func (o *order) fill(quantity, price, feeRate float64) {
+	if quantity <= 0 {
+		return
+	}
+	o.result.Filled += quantity
+	o.notional += quantity * price
+	o.result.AvgFillPrice = o.notional / o.result.Filled
+	o.result.Fees += quantity * price * feeRate
+}
+
+// updateStatus sets a resting order's status from its fill.
+func (o *order) updateStatus() {
+	switch {
+	case o.remaining() <= epsilon:
+		o.result.Status = types.OrderStatusFilled
+	case o.result.Filled > 0:
+		o.result.Status = types.OrderStatusPartial
+	default:
+		o.result.Status = types.OrderStatusOpen
+	}
+}
+
+func (o *order) remaining() float64 {
+	return o.result.Size - o.result.Filled
+}
+
+// marketable reports whether the order can trade at price.
+func (o *order) marketable(price float64) bool {
+	if o.orderType == types.OrderTypeMarket {
+		return true
+	}
+	if o.result.Side == types.OrderSideBuy {
+		return price <= o.result.Price+epsilon
+	}
+	return price >= o.result.Price-epsilon
+}
+
+// oppositeSide returns the levels the order trades against, best first:
+// the asks for a buy and the bids for a sell.
+func (o *order) oppositeSide(book *types.OrderBook) []types.Level {
+	if o.result.Side == types.OrderSideBuy {
+		return sortedLevels(book.Asks, false)
+	}
+	return sortedLevels(book.Bids, true)
+}
+
+// ownSide returns the levels the order rests among.
+func (o *order) ownSide(book *types.OrderBook) []types.Level {
+	if o.result.Side == types.OrderSideBuy {
+		return book.Bids
+	}
+	return book.Asks
+}
+
+// sortedLevels returns a copy of levels sorted by price, highest first if
+// descending.
+func sortedLevels(levels []types.Level, descending bool) []types.Level {
+	sorted := append([]types.Level(nil), levels...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if descending {
+			return sorted[i].Price > sorted[j].Price
+		}
+		return sorted[i].Price < sorted[j].Price
+	})
+	return sorted
+}
+
+// sizeAt returns the total size displayed at price.
+func sizeAt(levels []types.Level, price float64) float64 {
+	var size float64
+	for _, level := range levels {
+		if level.Price >= price-epsilon && level.Price <= price+epsilon {
+			size += level.Size
+		}
+	}
+	return size
+}
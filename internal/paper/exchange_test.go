@@ -0,0 +1,193 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"prediction-bot/pkg/types"
+)
+
+// MockBookSource serves a fixed order book that tests can change between polls.
+type MockBookSource struct {
+	book *types.OrderBook
+	err  error
+}
+
+func (m *MockBookSource) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	return m.book, m.err
+}
+
+func newBook() *types.OrderBook {
+	return &types.OrderBook{
+		Bids: []types.Level{{Price: 0.78, Size: 50}, {Price: 0.77, Size: 100}},
+		Asks: []types.Level{{Price: 0.80, Size: 10}, {Price: 0.81, Size: 20}, {Price: 0.83, Size: 100}},
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPlaceOrder_MarketBuyWalksTheBook(t *testing.T) {
+	exchange := NewExchange(&MockBookSource{book: newBook()}, 0.01)
+
+	result, err := exchange.PlaceOrder(context.Background(), types.Order{
+		TokenID: "yes",
+		Side:    types.OrderSideBuy,
+		Type:    types.OrderTypeMarket,
+		Size:    25,
+	}, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	// 10 at 0.80 and 15 at 0.81
+	if result.Status != types.OrderStatusFilled || result.Filled != 25 {
+		t.Errorf("expected 25 filled, got %v %v", result.Status, result.Filled)
+	}
+	wantPrice := (10*0.80 + 15*0.81) / 25
+	if !approx(result.AvgFillPrice, wantPrice) {
+		t.Errorf("expected average price %v, got %v", wantPrice, result.AvgFillPrice)
+	}
+	if !approx(result.Fees, 0.01*(10*0.80+15*0.81)) {
+		t.Errorf("expected fees on filled notional, got %v", result.Fees)
+	}
+	if !result.IsDryRun {
+		t.Error("expected paper order to be marked dry run")
+	}
+}
+
+func TestPlaceOrder_IOCFillsUpToLimit(t *testing.T) {
+	exchange := NewExchange(&MockBookSource{book: newBook()}, 0)
+
+	// Sell 80 at 0.775 or better: only the 0.78 bid qualifies
+	result, err := exchange.PlaceOrder(context.Background(), types.Order{
+		TokenID:     "yes",
+		Side:        types.OrderSideSell,
+		Type:        types.OrderTypeLimit,
+		Price:       0.775,
+		Size:        80,
+		TimeInForce: types.TimeInForceIOC,
+	}, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusCancelled || result.Filled != 50 || result.AvgFillPrice != 0.78 {
+		t.Errorf("expected 50 filled at 0.78 and the rest cancelled, got %+v", result)
+	}
+}
+
+func TestPlaceOrder_FOKFillsAllOrNothing(t *testing.T) {
+	exchange := NewExchange(&MockBookSource{book: newBook()}, 0)
+
+	order := types.Order{
+		TokenID:     "yes",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.81,
+		Size:        40,
+		TimeInForce: types.TimeInForceFOK,
+	}
+	result, err := exchange.PlaceOrder(context.Background(), order, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusCancelled || result.Filled != 0 {
+		t.Errorf("expected FOK for more than available to fill nothing, got %+v", result)
+	}
+
+	order.Size = 30
+	if result, _ = exchange.PlaceOrder(context.Background(), order, true); result.Status != types.OrderStatusFilled {
+		t.Errorf("expected FOK within available to fill, got %+v", result)
+	}
+}
+
+func TestRestingOrder_FillsThroughQueue(t *testing.T) {
+	source := &MockBookSource{book: newBook()}
+	exchange := NewExchange(source, 0)
+
+	// Join the 0.78 bid behind 50
+	result, err := exchange.PlaceOrder(context.Background(), types.Order{
+		MarketID:    "market-1",
+		TokenID:     "yes",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.78,
+		Size:        20,
+		TimeInForce: types.TimeInForceGTC,
+	}, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusOpen || result.Filled != 0 {
+		t.Fatalf("expected order to rest unfilled, got %+v", result)
+	}
+
+	open, _ := exchange.GetOpenOrders("market-1")
+	if len(open) != 1 || open[0].OrderID != result.OrderID {
+		t.Errorf("expected the order to be open, got %+v", open)
+	}
+
+	// 40 trades: still 10 ahead
+	source.book = newBook()
+	source.book.Bids[0].Size = 10
+	if result, _ = exchange.GetOrderStatus(result.OrderID); result.Filled != 0 {
+		t.Errorf("expected no fill while queued, got %v", result.Filled)
+	}
+
+	// 50 join behind, then 15 trade: the last 5 fill the order
+	source.book.Bids[0].Size = 60
+	exchange.GetOrderStatus(result.OrderID)
+	source.book.Bids[0].Size = 45
+	if result, _ = exchange.GetOrderStatus(result.OrderID); result.Status != types.OrderStatusPartial || result.Filled != 5 {
+		t.Errorf("expected 5 filled from the queue, got %v %v", result.Status, result.Filled)
+	}
+
+	// The ask drops through the limit: the rest is taken
+	source.book = newBook()
+	source.book.Asks = []types.Level{{Price: 0.78, Size: 100}}
+	result, _ = exchange.GetOrderStatus(result.OrderID)
+	if result.Status != types.OrderStatusFilled || result.Filled != 20 || !approx(result.AvgFillPrice, 0.78) {
+		t.Errorf("expected order filled at 0.78, got %+v", result)
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	exchange := NewExchange(&MockBookSource{book: newBook()}, 0)
+
+	result, _ := exchange.PlaceOrder(context.Background(), types.Order{
+		MarketID:    "market-1",
+		TokenID:     "yes",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.79,
+		Size:        10,
+		TimeInForce: types.TimeInForceGTC,
+	}, true)
+	if err := exchange.CancelOrder(result.OrderID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+
+	status, _ := exchange.GetOrderStatus(result.OrderID)
+	if status.Status != types.OrderStatusCancelled {
+		t.Errorf("expected cancelled, got %v", status.Status)
+	}
+	if open, _ := exchange.GetOpenOrders("market-1"); len(open) != 0 {
+		t.Errorf("expected no open orders, got %d", len(open))
+	}
+
+	if err := exchange.CancelOrder("paper-99"); !errors.Is(err, ErrUnknownOrder) {
+		t.Errorf("expected ErrUnknownOrder, got %v", err)
+	}
+}
+
+func TestPlaceOrder_BookError(t *testing.T) {
+	exchange := NewExchange(&MockBookSource{err: errors.New("timeout")}, 0)
+
+	_, err := exchange.PlaceOrder(context.Background(), types.Order{TokenID: "yes", Side: types.OrderSideBuy, Size: 1}, true)
+	if err == nil {
+		t.Error("expected error when the book can't be fetched")
+	}
+}
@@ -0,0 +1,157 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// APICall is one request made to a platform's API.
+type APICall struct {
+	ID           int64
+	API          string // Platform called
+	Endpoint     string // Host, path and query
+	Method       string
+	StatusCode   int // 0 if no response was received
+	Latency      time.Duration
+	Error        string // Transport error, empty if a response was received
+	RequestBody  string // Truncated
+	ResponseBody string // Truncated
+	CreatedAt    time.Time
+}
+
+// APIHealth aggregates a platform's API calls over a period.
+type APIHealth struct {
+	API        string
+	Calls      int
+	Failures   int // Calls that failed to connect, or were answered 429 or 5xx
+	AvgLatency time.Duration
+	MaxLatency time.Duration
+	LastError  string // Latest failure's error, or its status if it had a response
+}
+
+// FailureRate returns the share of calls that failed.
+func (h APIHealth) FailureRate() float64 {
+	if h.Calls == 0 {
+		return 0
+	}
+	return float64(h.Failures) / float64(h.Calls)
+}
+
+// apiFailure is the condition an api_log row is a failure on, the failures
+// the platform transport retries.
+const apiFailure = `(error IS NOT NULL OR status_code = 429 OR status_code >= 500)`
+
+// APILogRepository handles database operations for the API call log.
+type APILogRepository struct {
+	db *sql.DB
+}
+
+// NewAPILogRepository creates a new APILogRepository.
+func NewAPILogRepository(db *sql.DB) *APILogRepository {
+	return &APILogRepository{db: db}
+}
+
+// Record inserts an API call and returns its ID.
+func (r *APILogRepository) Record(c *APICall) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO api_log (api, endpoint, method, status_code, response_time_ms, error, request_body, response_body)
+		VALUES (?, ?, ?, NULLIF(?, 0), ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''))
+	`, c.API, c.Endpoint, c.Method, c.StatusCode, c.Latency.Milliseconds(),
+		c.Error, c.RequestBody, c.ResponseBody)
+	if err != nil {
+		return 0, fmt.Errorf("record api call: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	c.ID = id
+
+	return id, nil
+}
+
+// GetRecent retrieves the most recent API calls, newest first, to one
+// platform or to all if api is empty.
+func (r *APILogRepository) GetRecent(api string, limit int) ([]*APICall, error) {
+	rows, err := r.db.Query(`
+		SELECT id, api, endpoint, method, COALESCE(status_code, 0), COALESCE(response_time_ms, 0),
+			COALESCE(error, ''), COALESCE(request_body, ''), COALESCE(response_body, ''), created_at
+		FROM api_log
+		WHERE ? = '' OR api = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, api, api, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent api calls: %w", err)
+	}
+	defer rows.Close()
+
+	var calls []*APICall
+	for rows.Next() {
+		c := &APICall{}
+		var latencyMS int64
+		if err := rows.Scan(&c.ID, &c.API, &c.Endpoint, &c.Method, &c.StatusCode, &latencyMS,
+			&c.Error, &c.RequestBody, &c.ResponseBody, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan api call: %w", err)
+		}
+		c.Latency = time.Duration(latencyMS) * time.Millisecond
+		calls = append(calls, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api calls: %w", err)
+	}
+	return calls, nil
+}
+
+// Summarize aggregates each platform's API calls since a time, by platform.
+func (r *APILogRepository) Summarize(since time.Time) ([]APIHealth, error) {
+	rows, err := r.db.Query(`
+		SELECT api, COUNT(*),
+			COALESCE(SUM(CASE WHEN `+apiFailure+` THEN 1 ELSE 0 END), 0),
+			COALESCE(AVG(response_time_ms), 0), COALESCE(MAX(response_time_ms), 0),
+			COALESCE((
+				SELECT COALESCE(f.error, 'HTTP ' || f.status_code)
+				FROM api_log f
+				WHERE f.api = api_log.api AND f.created_at >= ? AND `+apiFailure+`
+				ORDER BY f.created_at DESC, f.id DESC
+				LIMIT 1
+			), '')
+		FROM api_log
+		WHERE created_at >= ?
+		GROUP BY api
+		ORDER BY api
+	`, since.UTC().Format("2006-01-02 15:04:05"), since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("summarize api calls: %w", err)
+	}
+	defer rows.Close()
+
+	var health []APIHealth
+	for rows.Next() {
+		var h APIHealth
+		var avgMS float64
+		var maxMS int64
+		if err := rows.Scan(&h.API, &h.Calls, &h.Failures, &avgMS, &maxMS, &h.LastError); err != nil {
+			return nil, fmt.Errorf("scan api health: %w", err)
+		}
+		h.AvgLatency = time.Duration(avgMS * float64(time.Millisecond))
+		h.MaxLatency = time.Duration(maxMS) * time.Millisecond
+		health = append(health, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api health: %w", err)
+	}
+	return health, nil
+}
+
+// DeleteBefore deletes the API calls made before a time and returns how
+// many were deleted.
+func (r *APILogRepository) DeleteBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM api_log WHERE created_at < ?`, before.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, fmt.Errorf("delete api calls: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPILogRepository_RecordSummarizeAndDelete(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewAPILogRepository(db)
+	for _, c := range []*APICall{
+		{API: "kalshi", Endpoint: "api.elections.kalshi.com/trade-api/v2/markets", Method: "GET", StatusCode: 200, Latency: 100 * time.Millisecond},
+		{API: "polymarket", Endpoint: "clob.polymarket.com/book?token_id=1", Method: "GET", StatusCode: 200, Latency: 100 * time.Millisecond, ResponseBody: `{"bids":[]}`},
+		{API: "polymarket", Endpoint: "clob.polymarket.com/order", Method: "POST", StatusCode: 429, Latency: 50 * time.Millisecond, RequestBody: `{"order":{}}`},
+		{API: "polymarket", Endpoint: "clob.polymarket.com/book?token_id=2", Method: "GET", StatusCode: 404, Latency: 60 * time.Millisecond},
+		{API: "polymarket", Endpoint: "clob.polymarket.com/book?token_id=3", Method: "GET", Latency: 2 * time.Second, Error: "connection reset"},
+	} {
+		if _, err := repo.Record(c); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	calls, err := repo.GetRecent("polymarket", 10)
+	if err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+	if len(calls) != 4 || calls[0].Error != "connection reset" || calls[0].StatusCode != 0 {
+		t.Fatalf("expected the platform's calls newest first, got %+v", calls)
+	}
+	if calls[2].RequestBody != `{"order":{}}` || calls[3].ResponseBody != `{"bids":[]}` {
+		t.Errorf("expected the payloads kept, got %q and %q", calls[2].RequestBody, calls[3].ResponseBody)
+	}
+
+	health, err := repo.Summarize(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if len(health) != 2 || health[0].API != "kalshi" || health[0].Failures != 0 {
+		t.Fatalf("expected kalshi healthy then polymarket, got %+v", health)
+	}
+	// A 404 is an answer, not a failure
+	poly := health[1]
+	if poly.Calls != 4 || poly.Failures != 2 || poly.FailureRate() != 0.5 {
+		t.Errorf("expected 2 of 4 polymarket calls failed, got %+v", poly)
+	}
+	if poly.AvgLatency != 552500*time.Microsecond || poly.MaxLatency != 2*time.Second {
+		t.Errorf("expected 552.5ms average and 2s max latency, got %v and %v", poly.AvgLatency, poly.MaxLatency)
+	}
+	if poly.LastError != "connection reset" {
+		t.Errorf("expected the latest failure's error, got %q", poly.LastError)
+	}
+
+	deleted, err := repo.DeleteBefore(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("DeleteBefore failed: %v", err)
+	}
+	if deleted != 5 {
+		t.Errorf("expected 5 calls deleted, got %d", deleted)
+	}
+}
@@ -0,0 +1,160 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Arbitrage is a pair of equivalent markets on two platforms whose implied
+// probabilities diverge. YES is bought on the YES market and NO on the NO
+// market.
+type Arbitrage struct {
+	ID             int64
+	Asset          string
+	Strike         float64
+	Direction      string
+	ResolutionDate time.Time
+	YesPlatform    string
+	YesMarketID    string
+	YesPrice       float64
+	NoPlatform     string
+	NoMarketID     string
+	NoPrice        float64
+	Spread         float64 // Latest divergence in YES price
+	MaxSpread      float64 // Widest divergence seen
+	YesPositionID  *int64  // Nil unless hedged
+	NoPositionID   *int64  // Nil unless hedged
+	SeenCount      int
+	FirstSeen      time.Time
+	LastSeen       time.Time
+}
+
+// Hedged reports whether positions were opened on both legs.
+func (a *Arbitrage) Hedged() bool {
+	return a.YesPositionID != nil && a.NoPositionID != nil
+}
+
+// ArbitrageRepository handles database operations for arbitrage
+// opportunities.
+type ArbitrageRepository struct {
+	db *sql.DB
+}
+
+// NewArbitrageRepository creates a new ArbitrageRepository.
+func NewArbitrageRepository(db *sql.DB) *ArbitrageRepository {
+	return &ArbitrageRepository{db: db}
+}
+
+// Record inserts an opportunity, or updates the existing row for the same
+// pair of markets with the latest prices, and returns the row's ID. The ID
+// and hedge positions of a are set from the stored row.
+func (r *ArbitrageRepository) Record(a *Arbitrage) (int64, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO arbitrage_opportunities (
+			asset, strike, direction, resolution_date,
+			yes_platform, yes_market_id, yes_price,
+			no_platform, no_market_id, no_price, spread, max_spread
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (yes_platform, yes_market_id, no_platform, no_market_id) DO UPDATE SET
+			yes_price = excluded.yes_price,
+			no_price = excluded.no_price,
+			spread = excluded.spread,
+			max_spread = MAX(max_spread, excluded.spread),
+			seen_count = seen_count + 1,
+			last_seen = CURRENT_TIMESTAMP
+	`,
+		a.Asset, a.Strike, a.Direction, a.ResolutionDate.UTC(),
+		a.YesPlatform, a.YesMarketID, a.YesPrice,
+		a.NoPlatform, a.NoMarketID, a.NoPrice, a.Spread, a.Spread,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("record arbitrage: %w", err)
+	}
+
+	err = r.db.QueryRow(`
+		SELECT id, yes_position_id, no_position_id
+		FROM arbitrage_opportunities
+		WHERE yes_platform = ? AND yes_market_id = ? AND no_platform = ? AND no_market_id = ?
+	`, a.YesPlatform, a.YesMarketID, a.NoPlatform, a.NoMarketID).Scan(&a.ID, &a.YesPositionID, &a.NoPositionID)
+	if err != nil {
+		return 0, fmt.Errorf("get arbitrage id: %w", err)
+	}
+
+	return a.ID, nil
+}
+
+// SetHedge records the positions opened on both legs of an opportunity.
+func (r *ArbitrageRepository) SetHedge(id, yesPositionID, noPositionID int64) error {
+	_, err := r.db.Exec(`
+		UPDATE arbitrage_opportunities SET yes_position_id = ?, no_position_id = ?
+		WHERE id = ?
+	`, yesPositionID, noPositionID, id)
+	if err != nil {
+		return fmt.Errorf("set arbitrage hedge: %w", err)
+	}
+	return nil
+}
+
+// GetSince retrieves opportunities last seen at or after the given time,
+// widest spread first.
+func (r *ArbitrageRepository) GetSince(since time.Time) ([]*Arbitrage, error) {
+	rows, err := r.db.Query(`
+		SELECT id, asset, strike, direction, resolution_date,
+			yes_platform, yes_market_id, yes_price,
+			no_platform, no_market_id, no_price, spread, max_spread,
+			yes_position_id, no_position_id, seen_count, first_seen, last_seen
+		FROM arbitrage_opportunities WHERE last_seen >= ?
+		ORDER BY spread DESC, id
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("get arbitrage opportunities: %w", err)
+	}
+	defer rows.Close()
+
+	var opportunities []*Arbitrage
+	for rows.Next() {
+		a := &Arbitrage{}
+		err := rows.Scan(
+			&a.ID, &a.Asset, &a.Strike, &a.Direction, &a.ResolutionDate,
+			&a.YesPlatform, &a.YesMarketID, &a.YesPrice,
+			&a.NoPlatform, &a.NoMarketID, &a.NoPrice, &a.Spread, &a.MaxSpread,
+			&a.YesPositionID, &a.NoPositionID, &a.SeenCount, &a.FirstSeen, &a.LastSeen,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan arbitrage opportunity: %w", err)
+		}
+		opportunities = append(opportunities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate arbitrage opportunities: %w", err)
+	}
+	return opportunities, nil
+}
+
+// HedgedPositionIDs returns the IDs of all positions opened as a leg of a
+// hedge.
+func (r *ArbitrageRepository) HedgedPositionIDs() (map[int64]bool, error) {
+	rows, err := r.db.Query(`
+		SELECT yes_position_id, no_position_id FROM arbitrage_opportunities
+		WHERE yes_position_id IS NOT NULL AND no_position_id IS NOT NULL
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get hedged positions: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]bool)
+	for rows.Next() {
+		var yesID, noID int64
+		if err := rows.Scan(&yesID, &noID); err != nil {
+			return nil, fmt.Errorf("scan hedged positions: %w", err)
+		}
+		ids[yesID] = true
+		ids[noID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate hedged positions: %w", err)
+	}
+	return ids, nil
+}
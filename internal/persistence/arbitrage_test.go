@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestArbitrageRepository_RecordAndHedge(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_arbitrage_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewArbitrageRepository(db)
+	positionRepo := NewPositionRepository(db)
+
+	// Test: Same pair seen twice keeps the latest prices and widest spread
+	var id int64
+	for _, spread := range []float64{0.08, 0.06} {
+		a := &Arbitrage{
+			Asset:          "BTC",
+			Strike:         100000,
+			Direction:      "above",
+			ResolutionDate: time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC),
+			YesPlatform:    "kalshi",
+			YesMarketID:    "KXBTC",
+			YesPrice:       0.74,
+			NoPlatform:     "polymarket",
+			NoMarketID:     "0xBTC",
+			NoPrice:        1 - 0.74 - spread,
+			Spread:         spread,
+		}
+		id, err = repo.Record(a)
+		if err != nil {
+			t.Fatalf("failed to record arbitrage: %v", err)
+		}
+		if a.Hedged() {
+			t.Error("expected opportunity not to be hedged yet")
+		}
+	}
+
+	opportunities, err := repo.GetSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get arbitrage: %v", err)
+	}
+	if len(opportunities) != 1 {
+		t.Fatalf("expected 1 opportunity, got %d", len(opportunities))
+	}
+	a := opportunities[0]
+	if a.ID != id || a.SeenCount != 2 {
+		t.Errorf("expected id %d seen twice, got id %d seen %d times", id, a.ID, a.SeenCount)
+	}
+	if a.Spread != 0.06 || a.MaxSpread != 0.08 {
+		t.Errorf("expected spread 0.06 and max 0.08, got %f and %f", a.Spread, a.MaxSpread)
+	}
+
+	// Test: Hedge legs are reported
+	yesID, _ := positionRepo.Create(&Position{Platform: "kalshi", MarketID: "KXBTC", Side: "YES", Status: PositionStatusOpen})
+	noID, _ := positionRepo.Create(&Position{Platform: "polymarket", MarketID: "0xBTC", Side: "NO", Status: PositionStatusOpen})
+	if err := repo.SetHedge(id, yesID, noID); err != nil {
+		t.Fatalf("failed to set hedge: %v", err)
+	}
+
+	hedged, err := repo.HedgedPositionIDs()
+	if err != nil {
+		t.Fatalf("failed to get hedged positions: %v", err)
+	}
+	if len(hedged) != 2 || !hedged[yesID] || !hedged[noID] {
+		t.Errorf("expected positions %d and %d hedged, got %v", yesID, noID, hedged)
+	}
+
+	// Test: Recording again loads the hedge
+	again := &Arbitrage{YesPlatform: "kalshi", YesMarketID: "KXBTC", NoPlatform: "polymarket", NoMarketID: "0xBTC", Spread: 0.05}
+	if _, err := repo.Record(again); err != nil {
+		t.Fatalf("failed to record arbitrage: %v", err)
+	}
+	if !again.Hedged() {
+		t.Error("expected recorded opportunity to be hedged")
+	}
+}
@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"fmt"
+	"time"
+)
+
+// PositionBackfill is metadata recovered for a position recorded before the
+// columns holding it were added. Zero fields are not recovered.
+type PositionBackfill struct {
+	Asset           string
+	Strike          float64
+	StrikeUpper     float64
+	Direction       string
+	TokenID         string
+	MarketCloseTime *time.Time
+}
+
+// BackfillDefaults sets the columns whose value is known for every position
+// recorded before they were added: entries were executed at market and
+// followed the market, so entry_strategy is "market" and trade_strategy
+// empty. Returns the number of positions updated.
+func (r *PositionRepository) BackfillDefaults() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE positions SET
+			entry_strategy = COALESCE(entry_strategy, 'market'),
+			trade_strategy = COALESCE(trade_strategy, '')
+		WHERE entry_strategy IS NULL OR trade_strategy IS NULL
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("backfill position defaults: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("count backfilled positions: %w", err)
+	}
+	return n, nil
+}
+
+// GetIncomplete retrieves the positions missing metadata a backfill may
+// recover: the asset or direction parsed from the title, the outcome token,
+// or the market close time. Oldest first.
+func (r *PositionRepository) GetIncomplete() ([]*Position, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
+			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
+		FROM positions
+		WHERE COALESCE(asset, '') = '' OR COALESCE(direction, '') = ''
+			OR COALESCE(token_id, '') = '' OR market_close_time IS NULL
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get incomplete positions: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanPositions(rows)
+}
+
+// Backfill fills in a position's missing metadata, keeping whatever was
+// already recorded. It only fills blanks, so like UpdatePeakPrice it is
+// written without a version check. Returns whether anything changed.
+func (r *PositionRepository) Backfill(id int64, b PositionBackfill) (bool, error) {
+	var asset, direction, tokenID interface{}
+	if b.Asset != "" {
+		asset = b.Asset
+	}
+	if b.Direction != "" {
+		direction = b.Direction
+	}
+	if b.TokenID != "" {
+		tokenID = b.TokenID
+	}
+
+	// The strikes are only taken along with the parsed asset and direction
+	result, err := r.db.Exec(`
+		UPDATE positions SET
+			strike = CASE WHEN COALESCE(asset, '') = '' AND ? IS NOT NULL THEN ? ELSE strike END,
+			strike_upper = CASE WHEN COALESCE(asset, '') = '' AND ? IS NOT NULL THEN ? ELSE strike_upper END,
+			asset = COALESCE(NULLIF(asset, ''), ?),
+			direction = COALESCE(NULLIF(direction, ''), ?),
+			token_id = COALESCE(NULLIF(token_id, ''), ?),
+			market_close_time = COALESCE(market_close_time, ?)
+		WHERE id = ? AND (
+			(COALESCE(asset, '') = '' AND ? IS NOT NULL)
+			OR (COALESCE(direction, '') = '' AND ? IS NOT NULL)
+			OR (COALESCE(token_id, '') = '' AND ? IS NOT NULL)
+			OR (market_close_time IS NULL AND ? IS NOT NULL))
+	`,
+		asset, b.Strike, asset, b.StrikeUpper,
+		asset, direction, tokenID, b.MarketCloseTime,
+		id, asset, direction, tokenID, b.MarketCloseTime,
+	)
+	if err != nil {
+		return false, fmt.Errorf("backfill position %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("count backfilled positions: %w", err)
+	}
+	return n > 0, nil
+}
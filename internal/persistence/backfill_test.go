@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPositionRepository_Backfill(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_backfill_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	// A position recorded before the newer columns existed
+	result, err := db.Exec(`
+		INSERT INTO positions (platform, market_id, market_title, entry_price_micros, quantity, side, status)
+		VALUES ('kalshi', 'KXBTC-1', 'Bitcoin above $100,000?', 900000, 10, 'YES', 'closed')
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert legacy position: %v", err)
+	}
+	legacyID, _ := result.LastInsertId()
+
+	repo := NewPositionRepository(db)
+	closeTime := time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)
+	completeID, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "KXETH-1", Asset: "ETH", Strike: 4000, Direction: "above",
+		EntryPrice: 0.9, Quantity: 10, Side: "YES", Status: PositionStatusOpen,
+		TokenID: "yes", MarketCloseTime: &closeTime, EntryStrategy: "limit",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// Test: Known defaults are set on legacy positions only
+	n, err := repo.BackfillDefaults()
+	if err != nil {
+		t.Fatalf("BackfillDefaults failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 position given defaults, got %d", n)
+	}
+	var entryStrategy string
+	if err := db.QueryRow(`SELECT entry_strategy FROM positions WHERE id = ?`, legacyID).Scan(&entryStrategy); err != nil || entryStrategy != "market" {
+		t.Errorf("expected legacy entry strategy market, got %q (%v)", entryStrategy, err)
+	}
+
+	// Test: Only positions with missing metadata are incomplete
+	incomplete, err := repo.GetIncomplete()
+	if err != nil {
+		t.Fatalf("GetIncomplete failed: %v", err)
+	}
+	if len(incomplete) != 1 || incomplete[0].ID != legacyID {
+		t.Fatalf("expected only the legacy position incomplete, got %+v", incomplete)
+	}
+
+	// Test: Blanks are filled
+	changed, err := repo.Backfill(legacyID, PositionBackfill{
+		Asset: "BTC", Strike: 100000, Direction: "above", TokenID: "yes", MarketCloseTime: &closeTime,
+	})
+	if err != nil {
+		t.Fatalf("Backfill failed: %v", err)
+	}
+	if !changed {
+		t.Error("expected the legacy position changed")
+	}
+	pos, err := repo.GetByID(legacyID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if pos.Asset != "BTC" || pos.Strike != 100000 || pos.Direction != "above" || pos.TokenID != "yes" ||
+		pos.MarketCloseTime == nil || !pos.MarketCloseTime.Equal(closeTime) || pos.Version != 1 {
+		t.Errorf("unexpected backfilled position %+v", pos)
+	}
+
+	// Test: Recorded metadata is kept
+	changed, err = repo.Backfill(completeID, PositionBackfill{Asset: "SOL", Strike: 1, Direction: "below", TokenID: "no"})
+	if err != nil {
+		t.Fatalf("Backfill failed: %v", err)
+	}
+	if changed {
+		t.Error("expected a complete position unchanged")
+	}
+	pos, _ = repo.GetByID(completeID)
+	if pos.Asset != "ETH" || pos.Strike != 4000 || pos.Direction != "above" || pos.TokenID != "yes" {
+		t.Errorf("expected recorded metadata kept, got %+v", pos)
+	}
+}
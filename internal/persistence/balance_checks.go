@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Balance check actions: what was done about the difference between a
+// bankroll and the platform's balance.
+const (
+	BalanceActionMatched  = "matched"  // No difference
+	BalanceActionRecorded = "recorded" // Within tolerance, left as is
+	BalanceActionAdjusted = "adjusted" // Within tolerance, bankroll set to the balance
+	BalanceActionAlerted  = "alerted"  // Beyond tolerance, alerted
+)
+
+// BalanceCheck is a comparison of a platform's bankroll with the balance
+// the platform reports. Amounts are stored as integer micro-dollars and
+// exposed in dollars.
+type BalanceCheck struct {
+	ID        int64
+	Platform  string
+	Bankroll  float64 // Bankroll's current amount when checked
+	Balance   float64 // Balance the platform reported
+	Action    string  // BalanceAction* taken
+	CreatedAt time.Time
+}
+
+// Discrepancy returns how far the platform's balance is above the bankroll
+// (negative if below).
+func (c *BalanceCheck) Discrepancy() float64 {
+	return (types.Dollars(c.Balance) - types.Dollars(c.Bankroll)).Float64()
+}
+
+// BalanceCheckRepository handles database operations for balance checks.
+type BalanceCheckRepository struct {
+	db *sql.DB
+}
+
+// NewBalanceCheckRepository creates a new BalanceCheckRepository.
+func NewBalanceCheckRepository(db *sql.DB) *BalanceCheckRepository {
+	return &BalanceCheckRepository{db: db}
+}
+
+// Record inserts a balance check and returns its ID.
+func (r *BalanceCheckRepository) Record(c *BalanceCheck) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO balance_checks (platform, bankroll_micros, balance_micros, action)
+		VALUES (?, ?, ?, ?)
+	`, c.Platform, types.Dollars(c.Bankroll), types.Dollars(c.Balance), c.Action)
+	if err != nil {
+		return 0, fmt.Errorf("record balance check: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	c.ID = id
+
+	return id, nil
+}
+
+// GetRecent retrieves the most recent balance checks, newest first, of one
+// platform or of all if platform is empty.
+func (r *BalanceCheckRepository) GetRecent(platform string, limit int) ([]*BalanceCheck, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, bankroll_micros, balance_micros, action, created_at
+		FROM balance_checks
+		WHERE ? = '' OR platform = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, platform, platform, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent balance checks: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []*BalanceCheck
+	for rows.Next() {
+		c := &BalanceCheck{}
+		var bankroll, balance types.Money
+		if err := rows.Scan(&c.ID, &c.Platform, &bankroll, &balance, &c.Action, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan balance check: %w", err)
+		}
+		c.Bankroll = bankroll.Float64()
+		c.Balance = balance.Float64()
+		checks = append(checks, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate balance checks: %w", err)
+	}
+	return checks, nil
+}
@@ -0,0 +1,51 @@
+package persistence
+
+import (
+	"testing"
+)
+
+func TestBalanceCheckRepository_RecordAndGetRecent(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewBalanceCheckRepository(db)
+	for _, c := range []*BalanceCheck{
+		{Platform: "kalshi", Bankroll: 500, Balance: 500, Action: BalanceActionMatched},
+		{Platform: "polymarket", Bankroll: 1000, Balance: 999.7, Action: BalanceActionAdjusted},
+		{Platform: "polymarket", Bankroll: 999.7, Balance: 1049.7, Action: BalanceActionAlerted},
+	} {
+		if _, err := repo.Record(c); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	checks, err := repo.GetRecent("polymarket", 10)
+	if err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+	if len(checks) != 2 || checks[0].Action != BalanceActionAlerted {
+		t.Fatalf("expected the platform's checks newest first, got %+v", checks)
+	}
+	if d := checks[0].Discrepancy(); d != 50 {
+		t.Errorf("expected a discrepancy of 50, got %v", d)
+	}
+	if d := checks[1].Discrepancy(); d != -0.3 {
+		t.Errorf("expected a discrepancy of -0.3, got %v", d)
+	}
+
+	all, err := repo.GetRecent("", 2)
+	if err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected the limit applied across platforms, got %d", len(all))
+	}
+}
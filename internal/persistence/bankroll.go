@@ -2,10 +2,15 @@ package persistence
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
 )
 
-// Bankroll represents a bankroll record in the database.
+// Bankroll represents a bankroll record in the database. Amounts are stored
+// as integer micro-dollars (types.Money) and exposed in dollars.
 type Bankroll struct {
 	ID            int64
 	Platform      string
@@ -14,6 +19,17 @@ type Bankroll struct {
 	UpdatedAt     string
 }
 
+// scanBankroll scans a bankroll row, converting stored micro-dollars.
+func scanBankroll(row interface{ Scan(...any) error }, b *Bankroll) error {
+	var initial, current types.Money
+	if err := row.Scan(&b.ID, &b.Platform, &initial, &current, &b.UpdatedAt); err != nil {
+		return err
+	}
+	b.InitialAmount = initial.Float64()
+	b.CurrentAmount = current.Float64()
+	return nil
+}
+
 // BankrollRepository handles database operations for bankroll.
 type BankrollRepository struct {
 	db *sql.DB
@@ -27,10 +43,10 @@ func NewBankrollRepository(db *sql.DB) *BankrollRepository {
 // Get retrieves the bankroll for a specific platform.
 func (r *BankrollRepository) Get(platform string) (*Bankroll, error) {
 	b := &Bankroll{}
-	err := r.db.QueryRow(`
-		SELECT id, platform, initial_amount, current_amount, updated_at
+	err := scanBankroll(r.db.QueryRow(`
+		SELECT id, platform, initial_micros, current_micros, updated_at
 		FROM bankroll WHERE platform = ?
-	`, platform).Scan(&b.ID, &b.Platform, &b.InitialAmount, &b.CurrentAmount, &b.UpdatedAt)
+	`, platform), b)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -43,7 +59,7 @@ func (r *BankrollRepository) Get(platform string) (*Bankroll, error) {
 // GetAll retrieves all bankroll records.
 func (r *BankrollRepository) GetAll() ([]*Bankroll, error) {
 	rows, err := r.db.Query(`
-		SELECT id, platform, initial_amount, current_amount, updated_at
+		SELECT id, platform, initial_micros, current_micros, updated_at
 		FROM bankroll ORDER BY platform
 	`)
 	if err != nil {
@@ -54,7 +70,7 @@ func (r *BankrollRepository) GetAll() ([]*Bankroll, error) {
 	var bankrolls []*Bankroll
 	for rows.Next() {
 		b := &Bankroll{}
-		if err := rows.Scan(&b.ID, &b.Platform, &b.InitialAmount, &b.CurrentAmount, &b.UpdatedAt); err != nil {
+		if err := scanBankroll(rows, b); err != nil {
 			return nil, fmt.Errorf("scan bankroll: %w", err)
 		}
 		bankrolls = append(bankrolls, b)
@@ -65,62 +81,281 @@ func (r *BankrollRepository) GetAll() ([]*Bankroll, error) {
 	return bankrolls, nil
 }
 
-// Update sets the current amount for a platform.
+// Ledger entry reasons.
+const (
+	LedgerReasonBalance    = "balance"    // AddToBalance: entries, exits, fees, refunds
+	LedgerReasonAdjustment = "adjustment" // Update: the current amount set directly
+)
+
+// Bankroll operations, the last part of a mutation's idempotency key.
+const (
+	BankrollOpEntry       = "entry"        // Entry cost and fees debited
+	BankrollOpExit        = "exit"         // Exit proceeds credited
+	BankrollOpPartialExit = "partial_exit" // Proceeds of part of a position sold
+	BankrollOpFees        = "fees"         // Order fees debited
+	BankrollOpRefund      = "refund"       // Unfilled order size refunded
+)
+
+// PositionKey returns the idempotency key of a bankroll operation on a
+// position, e.g. "position:7:entry".
+func PositionKey(positionID int64, op string) string {
+	return fmt.Sprintf("position:%d:%s", positionID, op)
+}
+
+// OrderKey returns the idempotency key of a bankroll operation on an order,
+// e.g. "order:abc123:refund".
+func OrderKey(orderID, op string) string {
+	return fmt.Sprintf("order:%s:%s", orderID, op)
+}
+
+// ErrLedgerInconsistent is returned by CheckLedger when a bankroll's current
+// amount is not its initial amount plus the sum of its ledger entries.
+var ErrLedgerInconsistent = errors.New("bankroll inconsistent with ledger")
+
+// Update sets the current amount for a platform, recording the difference
+// as an adjustment in the ledger.
 func (r *BankrollRepository) Update(platform string, amount float64) error {
-	result, err := r.db.Exec(`
-		UPDATE bankroll SET current_amount = ?, updated_at = CURRENT_TIMESTAMP
-		WHERE platform = ?
-	`, amount, platform)
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("update bankroll: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	var current types.Money
+	err = tx.QueryRow(`SELECT current_micros FROM bankroll WHERE platform = ?`, platform).Scan(&current)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("bankroll not found for platform: %s", platform)
+	}
 	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+		return fmt.Errorf("get bankroll: %w", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("bankroll not found for platform: %s", platform)
+
+	if _, err := tx.Exec(`
+		UPDATE bankroll SET current_micros = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE platform = ?
+	`, types.Dollars(amount), platform); err != nil {
+		return fmt.Errorf("update bankroll: %w", err)
+	}
+	if err := insertLedgerEntry(tx, platform, types.Dollars(amount)-current, LedgerReasonAdjustment); err != nil {
+		return err
+	}
+	if err := recordHistory(tx, platform); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
 	return nil
 }
 
-// Initialize creates a new bankroll record for a platform.
+// Initialize creates a new bankroll record for a platform. An existing
+// bankroll restarts from amount, so its ledger is cleared.
 func (r *BankrollRepository) Initialize(platform string, amount float64) error {
-	_, err := r.db.Exec(`
-		INSERT INTO bankroll (platform, initial_amount, current_amount)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO bankroll (platform, initial_micros, current_micros)
 		VALUES (?, ?, ?)
 		ON CONFLICT(platform) DO UPDATE SET
-			initial_amount = excluded.initial_amount,
-			current_amount = excluded.current_amount,
+			initial_micros = excluded.initial_micros,
+			current_micros = excluded.current_micros,
 			updated_at = CURRENT_TIMESTAMP
-	`, platform, amount, amount)
-	if err != nil {
+	`, platform, types.Dollars(amount), types.Dollars(amount)); err != nil {
 		return fmt.Errorf("initialize bankroll: %w", err)
 	}
+	if _, err := tx.Exec(`DELETE FROM bankroll_ledger WHERE platform = ?`, platform); err != nil {
+		return fmt.Errorf("clear ledger: %w", err)
+	}
+	if err := recordHistory(tx, platform); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
 	return nil
 }
 
-// AddToBalance adds (or subtracts if negative) an amount to the current balance.
-func (r *BankrollRepository) AddToBalance(platform string, amount float64) error {
-	result, err := r.db.Exec(`
+// AddToBalance adds (or subtracts if negative) an amount to the current
+// balance exactly, and records it in the ledger.
+func (r *BankrollRepository) AddToBalance(platform string, amount types.Money) error {
+	_, err := r.addToBalance(platform, amount, nil)
+	return err
+}
+
+// AddToBalanceOnce adds an amount to the current balance like AddToBalance,
+// unless a mutation with the same idempotency key (see PositionKey and
+// OrderKey) was already applied. applied is false if it was, so a retry
+// after a timeout can't apply the amount twice.
+func (r *BankrollRepository) AddToBalanceOnce(platform string, amount types.Money, key string) (applied bool, err error) {
+	return r.addToBalance(platform, amount, &key)
+}
+
+// addToBalance adds amount to the current balance and records it in the
+// ledger under key, if any, in one transaction.
+func (r *BankrollRepository) addToBalance(platform string, amount types.Money, key *string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	applied, err := applyToBalance(tx, platform, amount, key)
+	if err != nil || !applied {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// applyToBalance adds amount to the current balance and records it in the
+// ledger under key, if any, within tx. applied is false if the key was
+// already used.
+func applyToBalance(tx *sql.Tx, platform string, amount types.Money, key *string) (applied bool, err error) {
+	// Recording the entry first claims the key
+	result, err := tx.Exec(`
+		INSERT INTO bankroll_ledger (platform, amount_micros, reason, idempotency_key)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`, platform, amount, LedgerReasonBalance, key)
+	if err != nil {
+		return false, fmt.Errorf("insert ledger entry: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	result, err = tx.Exec(`
 		UPDATE bankroll SET
-			current_amount = current_amount + ?,
+			current_micros = current_micros + ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE platform = ?
 	`, amount, platform)
 	if err != nil {
-		return fmt.Errorf("add to balance: %w", err)
+		return false, fmt.Errorf("add to balance: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
+	rows, err = result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("get rows affected: %w", err)
+		return false, fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("bankroll not found for platform: %s", platform)
+		return false, fmt.Errorf("bankroll not found for platform: %s", platform)
+	}
+	if err := recordHistory(tx, platform); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HasApplied reports whether a mutation with the idempotency key was applied
+// to the balance.
+func (r *BankrollRepository) HasApplied(key string) (bool, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM bankroll_ledger WHERE idempotency_key = ?`, key).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check ledger key: %w", err)
+	}
+	return n > 0, nil
+}
+
+// insertLedgerEntry records a change to a platform's current amount.
+func insertLedgerEntry(tx *sql.Tx, platform string, amount types.Money, reason string) error {
+	_, err := tx.Exec(`
+		INSERT INTO bankroll_ledger (platform, amount_micros, reason)
+		VALUES (?, ?, ?)
+	`, platform, amount, reason)
+	if err != nil {
+		return fmt.Errorf("insert ledger entry: %w", err)
+	}
+	return nil
+}
+
+// recordHistory records a platform's current amount in the bankroll
+// history, within tx.
+func recordHistory(tx *sql.Tx, platform string) error {
+	_, err := tx.Exec(`
+		INSERT INTO bankroll_history (platform, amount_micros)
+		SELECT platform, current_micros FROM bankroll WHERE platform = ?
+	`, platform)
+	if err != nil {
+		return fmt.Errorf("record bankroll history: %w", err)
+	}
+	return nil
+}
+
+// BankrollPoint is a platform's current amount after a change to it.
+type BankrollPoint struct {
+	Platform   string
+	Amount     float64
+	RecordedAt time.Time
+}
+
+// GetHistory retrieves the bankroll history recorded at or after since,
+// oldest first, preceded by each platform's last amount before since so the
+// history starts from every bankroll as it stood.
+func (r *BankrollRepository) GetHistory(since time.Time) ([]BankrollPoint, error) {
+	rows, err := r.db.Query(`
+		SELECT platform, amount_micros, recorded_at FROM bankroll_history
+		WHERE id IN (
+			SELECT MAX(id) FROM bankroll_history WHERE recorded_at < ? GROUP BY platform
+		) OR recorded_at >= ?
+		ORDER BY id
+	`, since.UTC().Format("2006-01-02 15:04:05"), since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("get bankroll history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []BankrollPoint
+	for rows.Next() {
+		var p BankrollPoint
+		var amount types.Money
+		if err := rows.Scan(&p.Platform, &amount, &p.RecordedAt); err != nil {
+			return nil, fmt.Errorf("scan bankroll history: %w", err)
+		}
+		p.Amount = amount.Float64()
+		history = append(history, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bankroll history: %w", err)
+	}
+	return history, nil
+}
 
+// CheckLedger verifies that a platform's current amount equals its initial
+// amount plus the sum of its ledger entries. It returns an error wrapping
+// ErrLedgerInconsistent with both amounts if not, and nil if the platform
+// has no bankroll.
+func (r *BankrollRepository) CheckLedger(platform string) error {
+	var current, expected types.Money
+	err := r.db.QueryRow(`
+		SELECT b.current_micros,
+			b.initial_micros + COALESCE((
+				SELECT SUM(l.amount_micros) FROM bankroll_ledger l WHERE l.platform = b.platform
+			), 0)
+		FROM bankroll b WHERE b.platform = ?
+	`, platform).Scan(&current, &expected)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check ledger: %w", err)
+	}
+	if current != expected {
+		return fmt.Errorf("%w: %s current %s, initial plus ledger %s", ErrLedgerInconsistent, platform, current, expected)
+	}
 	return nil
 }
@@ -1,8 +1,13 @@
 package persistence
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
 )
 
 func TestBankrollRepository_Get(t *testing.T) {
@@ -188,7 +193,7 @@ func TestBankrollRepository_AddToBalance(t *testing.T) {
 	repo := NewBankrollRepository(db)
 
 	// Test: Add profit to bankroll
-	err = repo.AddToBalance("polymarket", 5.0) // Win $5
+	err = repo.AddToBalance("polymarket", types.Dollars(5.0)) // Win $5
 	if err != nil {
 		t.Fatalf("failed to add to balance: %v", err)
 	}
@@ -199,7 +204,7 @@ func TestBankrollRepository_AddToBalance(t *testing.T) {
 	}
 
 	// Test: Subtract loss from bankroll
-	err = repo.AddToBalance("polymarket", -10.0) // Lose $10
+	err = repo.AddToBalance("polymarket", types.Dollars(-10.0)) // Lose $10
 	if err != nil {
 		t.Fatalf("failed to subtract from balance: %v", err)
 	}
@@ -210,3 +215,221 @@ func TestBankrollRepository_AddToBalance(t *testing.T) {
 	}
 }
 
+
+func TestBankrollRepository_AddToBalanceIsExact(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_bankroll_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewBankrollRepository(db)
+
+	// Test: Hundreds of small fee deductions and refunds leave no residue
+	for i := 0; i < 500; i++ {
+		if err := repo.AddToBalance("polymarket", types.Dollars(-0.07)); err != nil {
+			t.Fatalf("failed to subtract from balance: %v", err)
+		}
+		if err := repo.AddToBalance("polymarket", types.Dollars(0.07)); err != nil {
+			t.Fatalf("failed to add to balance: %v", err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if err := repo.AddToBalance("polymarket", types.Dollars(0.1)); err != nil {
+			t.Fatalf("failed to add to balance: %v", err)
+		}
+	}
+
+	bankroll, _ := repo.Get("polymarket")
+	if bankroll.CurrentAmount != 60.0 {
+		t.Errorf("expected exactly 60.0, got %.12f", bankroll.CurrentAmount)
+	}
+}
+
+func TestBankrollRepository_CheckLedger(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_bankroll_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewBankrollRepository(db)
+
+	// Test: Balance changes and direct updates are all recorded
+	if err := repo.AddToBalance("polymarket", types.Dollars(-12.5)); err != nil {
+		t.Fatalf("failed to subtract from balance: %v", err)
+	}
+	if err := repo.AddToBalance("polymarket", types.Dollars(0.25)); err != nil {
+		t.Fatalf("failed to add to balance: %v", err)
+	}
+	if err := repo.Update("polymarket", 40.0); err != nil {
+		t.Fatalf("failed to update bankroll: %v", err)
+	}
+	if err := repo.CheckLedger("polymarket"); err != nil {
+		t.Errorf("expected consistent ledger, got %v", err)
+	}
+
+	// Test: A write that bypasses the ledger is detected
+	if _, err := db.Exec(`UPDATE bankroll SET current_micros = current_micros + 1000000 WHERE platform = 'polymarket'`); err != nil {
+		t.Fatalf("failed to corrupt bankroll: %v", err)
+	}
+	err = repo.CheckLedger("polymarket")
+	if !errors.Is(err, ErrLedgerInconsistent) {
+		t.Fatalf("expected ErrLedgerInconsistent, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "current 41.000000, initial plus ledger 40.000000") {
+		t.Errorf("expected both amounts in error, got %q", err)
+	}
+
+	// Test: Reinitializing restarts the ledger
+	if err := repo.Initialize("polymarket", 75.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+	if err := repo.CheckLedger("polymarket"); err != nil {
+		t.Errorf("expected consistent ledger after initialize, got %v", err)
+	}
+
+	// Test: A platform without a bankroll has nothing to check
+	if err := repo.CheckLedger("unknown"); err != nil {
+		t.Errorf("expected nil for unknown platform, got %v", err)
+	}
+}
+
+func TestBankrollRepository_AddToBalanceOnce(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_bankroll_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewBankrollRepository(db)
+	key := PositionKey(7, BankrollOpEntry)
+	if key != "position:7:entry" {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	// Test: A retried mutation is applied once
+	for i := 0; i < 3; i++ {
+		applied, err := repo.AddToBalanceOnce("polymarket", types.Dollars(-10.0), key)
+		if err != nil {
+			t.Fatalf("failed to add to balance: %v", err)
+		}
+		if applied != (i == 0) {
+			t.Errorf("attempt %d: expected applied %v, got %v", i+1, i == 0, applied)
+		}
+	}
+
+	// Test: Other keys and unkeyed mutations still apply
+	if _, err := repo.AddToBalanceOnce("polymarket", types.Dollars(12.0), PositionKey(7, BankrollOpExit)); err != nil {
+		t.Fatalf("failed to add to balance: %v", err)
+	}
+	if err := repo.AddToBalance("polymarket", types.Dollars(1.0)); err != nil {
+		t.Fatalf("failed to add to balance: %v", err)
+	}
+	if err := repo.AddToBalance("polymarket", types.Dollars(1.0)); err != nil {
+		t.Fatalf("failed to add to balance: %v", err)
+	}
+
+	bankroll, _ := repo.Get("polymarket")
+	if bankroll.CurrentAmount != 54.0 {
+		t.Errorf("expected current amount 54.0, got %f", bankroll.CurrentAmount)
+	}
+	if err := repo.CheckLedger("polymarket"); err != nil {
+		t.Errorf("expected consistent ledger, got %v", err)
+	}
+
+	// Test: An unknown platform neither applies nor claims the key
+	if _, err := repo.AddToBalanceOnce("unknown", types.Dollars(5.0), OrderKey("abc", BankrollOpRefund)); err == nil {
+		t.Error("expected error for unknown platform")
+	}
+	if err := repo.Initialize("unknown", 20.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+	applied, err := repo.AddToBalanceOnce("unknown", types.Dollars(5.0), OrderKey("abc", BankrollOpRefund))
+	if err != nil || !applied {
+		t.Errorf("expected refund applied once the bankroll exists, got %v, %v", applied, err)
+	}
+}
+
+func TestBankrollRepository_GetHistory(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewBankrollRepository(db)
+	if err := repo.AddToBalance("kalshi", types.Dollars(-10)); err != nil {
+		t.Fatalf("AddToBalance failed: %v", err)
+	}
+	if err := repo.Update("polymarket", 70); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// Every change is recorded after the amounts seeded by the migrations
+	history, err := repo.GetHistory(time.Time{})
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+	if len(history) < 4 {
+		t.Fatalf("expected the seeded amounts and both changes, got %+v", history)
+	}
+	last := history[len(history)-2:]
+	if last[0].Platform != "kalshi" || last[0].Amount != 40 || last[1].Platform != "polymarket" || last[1].Amount != 70 {
+		t.Errorf("expected kalshi at $40 then polymarket at $70, got %+v", last)
+	}
+	if last[1].RecordedAt.IsZero() {
+		t.Error("expected the time each change was recorded")
+	}
+
+	// Without changes since, each bankroll's last amount is still returned
+	history, err = repo.GetHistory(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+	amounts := make(map[string]float64)
+	for _, p := range history {
+		amounts[p.Platform] = p.Amount
+	}
+	if len(history) != len(amounts) || amounts["kalshi"] != 40 || amounts["polymarket"] != 70 {
+		t.Errorf("expected one point per platform at its current amount, got %+v", history)
+	}
+}
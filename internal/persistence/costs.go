@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Cost kinds.
+const (
+	// CostKindGas is gas paid for an on-chain transaction.
+	CostKindGas = "gas"
+)
+
+// Cost is a trading cost not captured in a position's price PnL.
+type Cost struct {
+	ID         int64
+	Platform   string
+	Kind       string
+	Amount     types.Money
+	PositionID *int64
+	Reference  string // e.g. transaction hash
+	CreatedAt  time.Time
+}
+
+// CostSummary totals the costs of one kind on a platform.
+type CostSummary struct {
+	Platform string
+	Kind     string
+	Count    int
+	Total    types.Money
+}
+
+// CostRepository handles database operations for costs.
+type CostRepository struct {
+	db *sql.DB
+}
+
+// NewCostRepository creates a new CostRepository.
+func NewCostRepository(db *sql.DB) *CostRepository {
+	return &CostRepository{db: db}
+}
+
+// Record inserts a cost and returns its ID.
+func (r *CostRepository) Record(c *Cost) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO costs (platform, kind, amount_micros, position_id, reference)
+		VALUES (?, ?, ?, ?, ?)
+	`, c.Platform, c.Kind, c.Amount, c.PositionID, c.Reference)
+	if err != nil {
+		return 0, fmt.Errorf("record cost: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	c.ID = id
+
+	return id, nil
+}
+
+// Summarize totals all recorded costs by platform and kind.
+func (r *CostRepository) Summarize() ([]CostSummary, error) {
+	rows, err := r.db.Query(`
+		SELECT platform, kind, COUNT(*), SUM(amount_micros)
+		FROM costs
+		GROUP BY platform, kind
+		ORDER BY platform, kind
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("summarize costs: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []CostSummary
+	for rows.Next() {
+		var s CostSummary
+		if err := rows.Scan(&s.Platform, &s.Kind, &s.Count, &s.Total); err != nil {
+			return nil, fmt.Errorf("scan cost summary: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate cost summaries: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -11,7 +11,21 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// OpenDB opens a SQLite database with WAL mode enabled.
+// busyTimeoutMillis is how long a connection waits for another to release
+// the database's write lock.
+const busyTimeoutMillis = 5000
+
+// querier runs statements on a *sql.DB or within a *sql.Tx, so a write can
+// be made alone or as part of a larger transaction.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// OpenDB opens a SQLite database with WAL mode enabled. Transactions take
+// the write lock when they begin, and writers wait up to busyTimeoutMillis
+// for it, so concurrent writers (such as the bot and botctl) are serialized
+// instead of failing with SQLITE_BUSY midway through a transaction.
 func OpenDB(path string) (*sql.DB, error) {
 	// Expand ~ to home directory
 	if strings.HasPrefix(path, "~") {
@@ -28,7 +42,8 @@ func OpenDB(path string) (*sql.DB, error) {
 		return nil, fmt.Errorf("create db directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite3", path)
+	dsn := fmt.Sprintf("%s?_busy_timeout=%d&_txlock=immediate", path, busyTimeoutMillis)
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -48,6 +63,16 @@ func OpenDB(path string) (*sql.DB, error) {
 	return db, nil
 }
 
+// Checkpoint writes the write-ahead log back into the database file and
+// truncates it, so the file alone holds every committed write, such as
+// when the bot shuts down.
+func Checkpoint(db *sql.DB) error {
+	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("checkpoint database: %w", err)
+	}
+	return nil
+}
+
 // RunMigrations executes all SQL migration files in order.
 func RunMigrations(db *sql.DB, migrationsDir string) error {
 	// Create schema_version table if not exists
@@ -68,29 +93,14 @@ func RunMigrations(db *sql.DB, migrationsDir string) error {
 		return fmt.Errorf("get current version: %w", err)
 	}
 
-	// Read migration files
-	entries, err := os.ReadDir(migrationsDir)
+	migrations, err := listMigrations(migrationsDir)
 	if err != nil {
-		return fmt.Errorf("read migrations dir: %w", err)
+		return err
 	}
 
-	// Filter and sort SQL files
-	var migrations []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
-			migrations = append(migrations, entry.Name())
-		}
-	}
-	sort.Strings(migrations)
-
 	// Apply pending migrations
-	for _, filename := range migrations {
-		// Extract version number from filename (e.g., "001_initial.sql" -> 1)
-		var version int
-		if _, err := fmt.Sscanf(filename, "%d_", &version); err != nil {
-			continue // Skip files without version prefix
-		}
-
+	for _, m := range migrations {
+		filename, version := m.filename, m.version
 		if version <= currentVersion {
 			continue // Already applied
 		}
@@ -114,3 +124,38 @@ func RunMigrations(db *sql.DB, migrationsDir string) error {
 
 	return nil
 }
+
+// migration is a versioned SQL migration file.
+type migration struct {
+	filename string
+	version  int
+}
+
+// listMigrations returns the SQL migration files in migrationsDir in order.
+// Files without a version prefix (e.g. "001_initial.sql" is version 1) are
+// skipped.
+func listMigrations(migrationsDir string) ([]migration, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	// Filter and sort SQL files
+	var filenames []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			filenames = append(filenames, entry.Name())
+		}
+	}
+	sort.Strings(filenames)
+
+	var migrations []migration
+	for _, filename := range filenames {
+		var version int
+		if _, err := fmt.Sscanf(filename, "%d_", &version); err != nil {
+			continue // Skip files without version prefix
+		}
+		migrations = append(migrations, migration{filename: filename, version: version})
+	}
+	return migrations, nil
+}
@@ -206,3 +206,27 @@ func TestFullSchema_AllTablesExist(t *testing.T) {
 		t.Errorf("expected probability_threshold 0.80, got %f", probThreshold)
 	}
 }
+
+func TestCheckpoint_TruncatesWAL(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := OpenDB(dbPath)
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE t (v INTEGER); INSERT INTO t VALUES (1)"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Checkpoint(db); err != nil {
+		t.Fatalf("Checkpoint: %v", err)
+	}
+
+	info, err := os.Stat(dbPath + "-wal")
+	if err != nil {
+		t.Fatalf("stat WAL: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("expected the WAL truncated, got %d bytes", info.Size())
+	}
+}
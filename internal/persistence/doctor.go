@@ -0,0 +1,444 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Doctor checks.
+const (
+	// CheckSchemaVersion finds a database behind or ahead of the
+	// migrations, or with migrations missing from its history.
+	CheckSchemaVersion = "schema_version"
+	// CheckOrphanedRows finds rows referencing positions that don't exist.
+	CheckOrphanedRows = "orphaned_rows"
+	// CheckPositionStatus finds positions with an unknown status.
+	CheckPositionStatus = "position_status"
+	// CheckClosedWithoutExitPrice finds closed positions with no exit
+	// price.
+	CheckClosedWithoutExitPrice = "closed_without_exit_price"
+	// CheckClosedWithoutExitTime finds closed positions with no exit time.
+	CheckClosedWithoutExitTime = "closed_without_exit_time"
+	// CheckOpenOnResolvedMarket finds open positions on markets already
+	// recorded as resolved.
+	CheckOpenOnResolvedMarket = "open_on_resolved_market"
+)
+
+// positionStatuses are the valid position statuses.
+var positionStatuses = []string{
+	PositionStatusPendingEntry,
+	PositionStatusOpen,
+	PositionStatusExiting,
+	PositionStatusClosed,
+	PositionStatusError,
+	PositionStatusReconciling,
+	PositionStatusPendingSettlement,
+}
+
+// maxListedIDs is how many row IDs a finding lists.
+const maxListedIDs = 10
+
+// Finding is an integrity problem found by the doctor.
+type Finding struct {
+	Check  string
+	Count  int    // Rows affected
+	Detail string // What is wrong, and with which rows
+	// Fix describes the safe fix for the problem, empty if it needs to be
+	// looked into by hand.
+	Fix string
+	fix func(tx *sql.Tx) error
+}
+
+// Fixable reports whether the doctor can fix the problem itself.
+func (f Finding) Fixable() bool {
+	return f.fix != nil
+}
+
+// Doctor checks a database's schema version and data integrity, and makes
+// the fixes that can't lose information.
+type Doctor struct {
+	db            *sql.DB
+	migrationsDir string
+}
+
+// NewDoctor creates a new Doctor for db, checking its schema version
+// against the migrations in migrationsDir.
+func NewDoctor(db *sql.DB, migrationsDir string) *Doctor {
+	return &Doctor{db: db, migrationsDir: migrationsDir}
+}
+
+// Diagnose runs every check and returns the problems found. No findings
+// means the database is healthy.
+func (d *Doctor) Diagnose() ([]Finding, error) {
+	var findings []Finding
+	for _, check := range []func() ([]Finding, error){
+		d.checkSchemaVersion,
+		d.checkOrphanedRows,
+		d.checkPositionStatus,
+		d.checkClosedPositions,
+		d.checkOpenOnResolvedMarket,
+	} {
+		found, err := check()
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
+
+// Fix applies the fixes of the fixable findings in one transaction and
+// returns how many it applied.
+func (d *Doctor) Fix(findings []Finding) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var fixed int
+	for _, f := range findings {
+		if !f.Fixable() {
+			continue
+		}
+		if err := f.fix(tx); err != nil {
+			return 0, fmt.Errorf("fix %s: %w", f.Check, err)
+		}
+		fixed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit fixes: %w", err)
+	}
+	return fixed, nil
+}
+
+// SchemaVersion returns the latest migration applied to the database.
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("get schema version: %w", err)
+	}
+	return version, nil
+}
+
+// ExportSchema writes the database's schema to w as SQL, stamped with its
+// schema version and the time of the export.
+func ExportSchema(db *sql.DB, w io.Writer, now time.Time) error {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.Query(`
+		SELECT sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, tbl_name, name
+	`)
+	if err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+	defer rows.Close()
+
+	if _, err := fmt.Fprintf(w, "-- prediction-bot schema version %d, exported %s\n",
+		version, now.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("write schema: %w", err)
+	}
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			return fmt.Errorf("read schema: %w", err)
+		}
+		if _, err := fmt.Fprintf(w, "\n%s;\n", stmt); err != nil {
+			return fmt.Errorf("write schema: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+	return nil
+}
+
+// checkSchemaVersion compares the applied migrations with those in the
+// migrations directory.
+func (d *Doctor) checkSchemaVersion() ([]Finding, error) {
+	migrations, err := listMigrations(d.migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := queryIDs(d.db, "SELECT version FROM schema_version ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("get applied migrations: %w", err)
+	}
+
+	isApplied := make(map[int64]bool, len(applied))
+	var current int64
+	for _, v := range applied {
+		isApplied[v] = true
+		current = v
+	}
+
+	var latest int64
+	var pending, skipped []int64
+	for _, m := range migrations {
+		version := int64(m.version)
+		latest = version
+		switch {
+		case isApplied[version]:
+		case version > current:
+			pending = append(pending, version)
+		default:
+			skipped = append(skipped, version)
+		}
+	}
+
+	var findings []Finding
+	if len(pending) > 0 {
+		findings = append(findings, Finding{
+			Check:  CheckSchemaVersion,
+			Count:  len(pending),
+			Detail: fmt.Sprintf("schema version %d is behind the migrations (latest %d), pending: %s", current, latest, listIDs(pending)),
+			Fix:    "start the bot or botctl to apply pending migrations",
+		})
+	}
+	if current > latest {
+		findings = append(findings, Finding{
+			Check:  CheckSchemaVersion,
+			Count:  1,
+			Detail: fmt.Sprintf("schema version %d is ahead of the migrations (latest %d), the binary is older than the database", current, latest),
+		})
+	}
+	if len(skipped) > 0 {
+		findings = append(findings, Finding{
+			Check:  CheckSchemaVersion,
+			Count:  len(skipped),
+			Detail: fmt.Sprintf("migrations never applied below version %d: %s", current, listIDs(skipped)),
+		})
+	}
+	return findings, nil
+}
+
+// checkOrphanedRows finds foreign keys referencing missing rows. The fix
+// clears the dangling reference and keeps the row.
+func (d *Doctor) checkOrphanedRows() ([]Finding, error) {
+	rows, err := d.db.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		return nil, fmt.Errorf("check foreign keys: %w", err)
+	}
+
+	type reference struct {
+		table  string
+		parent string
+		fkid   int
+	}
+	var order []reference
+	orphans := make(map[reference][]int64)
+	for rows.Next() {
+		var ref reference
+		var rowid sql.NullInt64
+		if err := rows.Scan(&ref.table, &rowid, &ref.parent, &ref.fkid); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("read foreign key check: %w", err)
+		}
+		if _, ok := orphans[ref]; !ok {
+			order = append(order, ref)
+		}
+		orphans[ref] = append(orphans[ref], rowid.Int64)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("read foreign key check: %w", err)
+	}
+	rows.Close()
+
+	var findings []Finding
+	for _, ref := range order {
+		column, err := d.foreignKeyColumn(ref.table, ref.fkid)
+		if err != nil {
+			return nil, err
+		}
+		ids := orphans[ref]
+		table := ref.table
+		findings = append(findings, Finding{
+			Check:  CheckOrphanedRows,
+			Count:  len(ids),
+			Detail: fmt.Sprintf("%s rows referencing missing %s through %s: %s", table, ref.parent, column, listIDs(ids)),
+			Fix:    fmt.Sprintf("clear %s.%s", table, column),
+			fix: func(tx *sql.Tx) error {
+				_, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = NULL WHERE rowid IN (%s)",
+					quoteIdent(table), quoteIdent(column), placeholders(len(ids))), int64Args(ids)...)
+				return err
+			},
+		})
+	}
+	return findings, nil
+}
+
+// foreignKeyColumn returns the referencing column of a table's foreign key.
+func (d *Doctor) foreignKeyColumn(table string, fkid int) (string, error) {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%s)", quoteIdent(table)))
+	if err != nil {
+		return "", fmt.Errorf("list foreign keys of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, seq int
+		var parent, from string
+		var to, onUpdate, onDelete, match sql.NullString
+		if err := rows.Scan(&id, &seq, &parent, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return "", fmt.Errorf("read foreign keys of %s: %w", table, err)
+		}
+		if id == fkid {
+			return from, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("read foreign keys of %s: %w", table, err)
+	}
+	return "", fmt.Errorf("foreign key %d of %s not found", fkid, table)
+}
+
+// checkPositionStatus finds positions whose status is not a lifecycle
+// state.
+func (d *Doctor) checkPositionStatus() ([]Finding, error) {
+	ids, err := queryIDs(d.db, fmt.Sprintf("SELECT id FROM positions WHERE status NOT IN (%s) ORDER BY id",
+		placeholders(len(positionStatuses))), stringArgs(positionStatuses)...)
+	if err != nil {
+		return nil, fmt.Errorf("check position statuses: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return []Finding{{
+		Check:  CheckPositionStatus,
+		Count:  len(ids),
+		Detail: fmt.Sprintf("positions with an unknown status: %s", listIDs(ids)),
+	}}, nil
+}
+
+// checkClosedPositions finds closed positions missing their exit. An exit
+// time is taken from the position's last update; a missing exit price
+// can't be recovered.
+func (d *Doctor) checkClosedPositions() ([]Finding, error) {
+	var findings []Finding
+
+	ids, err := queryIDs(d.db, "SELECT id FROM positions WHERE status = ? AND exit_price_micros IS NULL ORDER BY id",
+		PositionStatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("check closed positions: %w", err)
+	}
+	if len(ids) > 0 {
+		findings = append(findings, Finding{
+			Check:  CheckClosedWithoutExitPrice,
+			Count:  len(ids),
+			Detail: fmt.Sprintf("closed positions without an exit price: %s", listIDs(ids)),
+		})
+	}
+
+	ids, err = queryIDs(d.db, "SELECT id FROM positions WHERE status = ? AND exit_time IS NULL ORDER BY id",
+		PositionStatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("check closed positions: %w", err)
+	}
+	if len(ids) > 0 {
+		findings = append(findings, Finding{
+			Check:  CheckClosedWithoutExitTime,
+			Count:  len(ids),
+			Detail: fmt.Sprintf("closed positions without an exit time: %s", listIDs(ids)),
+			Fix:    "set the exit time to the position's last update",
+			fix: func(tx *sql.Tx) error {
+				_, err := tx.Exec(fmt.Sprintf(`
+					UPDATE positions SET exit_time = COALESCE(updated_at, CURRENT_TIMESTAMP), version = version + 1
+					WHERE id IN (%s) AND exit_time IS NULL
+				`, placeholders(len(ids))), int64Args(ids)...)
+				return err
+			},
+		})
+	}
+	return findings, nil
+}
+
+// checkOpenOnResolvedMarket finds open positions on markets whose
+// resolution is recorded, which the settler should have closed.
+func (d *Doctor) checkOpenOnResolvedMarket() ([]Finding, error) {
+	ids, err := queryIDs(d.db, `
+		SELECT p.id FROM positions p
+		JOIN market_resolutions r ON r.platform = p.platform AND r.market_id = p.market_id
+		WHERE p.status = ?
+		ORDER BY p.id
+	`, PositionStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("check open positions on resolved markets: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return []Finding{{
+		Check:  CheckOpenOnResolvedMarket,
+		Count:  len(ids),
+		Detail: fmt.Sprintf("open positions on resolved markets: %s", listIDs(ids)),
+	}}, nil
+}
+
+// queryIDs returns the integer first column of a query's rows.
+func queryIDs(db *sql.DB, query string, args ...interface{}) ([]int64, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// listIDs formats ids for a finding, listing at most maxListedIDs.
+func listIDs(ids []int64) string {
+	parts := make([]string, 0, maxListedIDs)
+	for i, id := range ids {
+		if i == maxListedIDs {
+			parts = append(parts, fmt.Sprintf("and %d more", len(ids)-maxListedIDs))
+			break
+		}
+		parts = append(parts, fmt.Sprint(id))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// placeholders returns n comma-separated query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func int64Args(values []int64) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+func stringArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// quoteIdent quotes a SQL identifier.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,166 @@
+package persistence
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoctor_DiagnoseAndFix(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_doctor_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	doctor := NewDoctor(db, "../../migrations")
+
+	// Test: A freshly migrated database is healthy
+	findings, err := doctor.Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose failed: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings, got %+v", findings)
+	}
+
+	// Break it: orphaned rows need foreign keys off on the connection
+	// that writes them
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"PRAGMA foreign_keys=OFF",
+		`INSERT INTO positions (id, platform, market_id, entry_price_micros, quantity, side, status)
+			VALUES (1, 'kalshi', 'M1', 800000, 10, 'YES', 'closed')`,
+		`INSERT INTO positions (id, platform, market_id, entry_price_micros, quantity, side, status)
+			VALUES (2, 'kalshi', 'M2', 800000, 10, 'YES', 'open')`,
+		`INSERT INTO positions (id, platform, market_id, entry_price_micros, quantity, side, status)
+			VALUES (3, 'kalshi', 'M3', 800000, 10, 'YES', 'sold')`,
+		`INSERT INTO market_resolutions (platform, market_id, outcome) VALUES ('kalshi', 'M2', 'YES')`,
+		`INSERT INTO orders (order_id, platform, market_id, position_id, side, price, size, status)
+			VALUES ('o1', 'kalshi', 'M9', 99, 'BUY', 0.8, 10, 'filled')`,
+		"PRAGMA foreign_keys=ON",
+		"DELETE FROM schema_version WHERE version = 3",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to exec %q: %v", stmt, err)
+		}
+	}
+
+	findings, err = doctor.Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose failed: %v", err)
+	}
+	byCheck := make(map[string]Finding)
+	for _, f := range findings {
+		byCheck[f.Check] = f
+	}
+	expected := map[string]bool{
+		CheckSchemaVersion:          false,
+		CheckOrphanedRows:           true,
+		CheckPositionStatus:         false,
+		CheckClosedWithoutExitPrice: false,
+		CheckClosedWithoutExitTime:  true,
+		CheckOpenOnResolvedMarket:   false,
+	}
+	if len(findings) != len(expected) {
+		t.Errorf("expected %d findings, got %+v", len(expected), findings)
+	}
+	for check, fixable := range expected {
+		f, ok := byCheck[check]
+		if !ok {
+			t.Errorf("expected a %s finding", check)
+			continue
+		}
+		if f.Count != 1 || f.Fixable() != fixable {
+			t.Errorf("%s: expected 1 row, fixable %v, got %+v", check, fixable, f)
+		}
+	}
+	if f := byCheck[CheckOrphanedRows]; !strings.Contains(f.Detail, "orders") || !strings.Contains(f.Detail, "position_id") {
+		t.Errorf("expected orphaned order position_id, got %q", f.Detail)
+	}
+	if f := byCheck[CheckOpenOnResolvedMarket]; !strings.HasSuffix(f.Detail, ": 2") {
+		t.Errorf("expected position 2 open on a resolved market, got %q", f.Detail)
+	}
+
+	// Test: Fix applies only the safe fixes
+	fixed, err := doctor.Fix(findings)
+	if err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+	if fixed != 2 {
+		t.Errorf("expected 2 fixes, got %d", fixed)
+	}
+
+	findings, err = doctor.Diagnose()
+	if err != nil {
+		t.Fatalf("Diagnose failed: %v", err)
+	}
+	for _, f := range findings {
+		if f.Fixable() {
+			t.Errorf("expected fixable findings gone, got %+v", f)
+		}
+	}
+	if len(findings) != 4 {
+		t.Errorf("expected the 4 findings that need a person to remain, got %+v", findings)
+	}
+
+	var orderPosition *int64
+	if err := db.QueryRow("SELECT position_id FROM orders WHERE order_id = 'o1'").Scan(&orderPosition); err != nil {
+		t.Fatalf("failed to read order: %v", err)
+	}
+	if orderPosition != nil {
+		t.Errorf("expected orphaned reference cleared, got %d", *orderPosition)
+	}
+}
+
+func TestExportSchema_StampsVersion(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_schema_export_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	version, err := SchemaVersion(db)
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportSchema(db, &buf, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("ExportSchema failed: %v", err)
+	}
+	schema := buf.String()
+	header := fmt.Sprintf("-- prediction-bot schema version %d, exported 2026-10-16T09:00:00Z\n", version)
+	if !strings.HasPrefix(schema, header) {
+		t.Errorf("expected header %q, got %q", header, strings.SplitN(schema, "\n", 2)[0])
+	}
+	for _, want := range []string{"CREATE TABLE positions", "CREATE TABLE scan_decisions", "CREATE INDEX idx_positions_status"} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected schema to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// EquityPeak is the highest realized equity recorded and when it was
+// reached. The peak is stored as integer micro-dollars and exposed in
+// dollars.
+type EquityPeak struct {
+	Peak      float64
+	ReachedAt time.Time
+}
+
+// Drawdown returns how far equity is below the peak, as a fraction of the
+// peak. Equity at or above the peak is no drawdown.
+func (p *EquityPeak) Drawdown(equity float64) float64 {
+	if p.Peak <= 0 || equity >= p.Peak {
+		return 0
+	}
+	return (p.Peak - equity) / p.Peak
+}
+
+// EquityRepository tracks the peak realized equity.
+type EquityRepository struct {
+	db *sql.DB
+}
+
+// NewEquityRepository creates a new EquityRepository.
+func NewEquityRepository(db *sql.DB) *EquityRepository {
+	return &EquityRepository{db: db}
+}
+
+// RecordEquity raises the peak to equity if equity is above it, or sets it
+// if none was recorded, and returns the peak.
+func (r *EquityRepository) RecordEquity(equity float64) (*EquityPeak, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO equity_peak (id, peak_micros) VALUES (1, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			peak_micros = excluded.peak_micros,
+			reached_at = CURRENT_TIMESTAMP
+		WHERE excluded.peak_micros > equity_peak.peak_micros
+	`, types.Dollars(equity))
+	if err != nil {
+		return nil, fmt.Errorf("record equity: %w", err)
+	}
+
+	return r.GetPeak()
+}
+
+// GetPeak returns the peak realized equity, or nil if none was recorded.
+func (r *EquityRepository) GetPeak() (*EquityPeak, error) {
+	var peak types.Money
+	p := &EquityPeak{}
+	err := r.db.QueryRow(`SELECT peak_micros, reached_at FROM equity_peak WHERE id = 1`).Scan(&peak, &p.ReachedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get equity peak: %w", err)
+	}
+	p.Peak = peak.Float64()
+	return p, nil
+}
@@ -0,0 +1,47 @@
+package persistence
+
+import (
+	"testing"
+)
+
+func TestEquityRepository_RecordEquity(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := NewEquityRepository(db)
+
+	if peak, err := repo.GetPeak(); err != nil || peak != nil {
+		t.Fatalf("expected no peak yet, got %+v, %v", peak, err)
+	}
+
+	for _, tc := range []struct {
+		equity, peak, drawdown float64
+	}{
+		{1000, 1000, 0},
+		{1200.5, 1200.5, 0},
+		{960.4, 1200.5, 0.2}, // Below the peak: kept
+		{1100, 1200.5, 0},
+	} {
+		peak, err := repo.RecordEquity(tc.equity)
+		if err != nil {
+			t.Fatalf("RecordEquity(%v) failed: %v", tc.equity, err)
+		}
+		if peak.Peak != tc.peak {
+			t.Errorf("after %v expected peak %v, got %v", tc.equity, tc.peak, peak.Peak)
+		}
+		if tc.drawdown > 0 {
+			if got := peak.Drawdown(tc.equity); got < tc.drawdown-1e-9 || got > tc.drawdown+1e-9 {
+				t.Errorf("expected drawdown %v at %v, got %v", tc.drawdown, tc.equity, got)
+			}
+		}
+		if peak.ReachedAt.IsZero() {
+			t.Error("expected the time the peak was reached")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timestampFormat is SQLite's CURRENT_TIMESTAMP format. Times stored and
+// compared in it order correctly as strings.
+const timestampFormat = "2006-01-02 15:04:05"
+
+// Event is a significant thing the bot did, recorded for auditing (see the
+// audit package for the event types).
+type Event struct {
+	ID       int64
+	Type     string
+	Platform string
+	MarketID string
+	// PositionID is the position the event concerns, or nil.
+	PositionID *int64
+	// Details is the event's structured details as a JSON object.
+	Details   string
+	CreatedAt time.Time
+}
+
+// EventFilter selects events to query. Zero fields don't filter.
+type EventFilter struct {
+	Types []string
+	Since time.Time // At or after
+	Until time.Time // Before
+	Limit int
+}
+
+// EventRepository handles database operations for events.
+type EventRepository struct {
+	db *sql.DB
+}
+
+// NewEventRepository creates a new EventRepository.
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Record inserts an event and returns its ID. A zero CreatedAt is set to
+// now.
+func (r *EventRepository) Record(e *Event) (int64, error) {
+	return insertEvent(r.db, e)
+}
+
+// insertEvent inserts an event with q and sets its ID.
+func insertEvent(q querier, e *Event) (int64, error) {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	result, err := q.Exec(`
+		INSERT INTO events (event_type, platform, market_id, position_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, e.Type, e.Platform, e.MarketID, e.PositionID, e.Details, e.CreatedAt.UTC().Format(timestampFormat))
+	if err != nil {
+		return 0, fmt.Errorf("record event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	e.ID = id
+
+	return id, nil
+}
+
+// Query retrieves the events matching filter, newest first.
+func (r *EventRepository) Query(filter EventFilter) ([]*Event, error) {
+	var conditions []string
+	var args []interface{}
+	if len(filter.Types) > 0 {
+		conditions = append(conditions, "event_type IN ("+placeholders(len(filter.Types))+")")
+		args = append(args, stringArgs(filter.Types)...)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since.UTC().Format(timestampFormat))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until.UTC().Format(timestampFormat))
+	}
+
+	query := `
+		SELECT id, event_type, COALESCE(platform, ''), COALESCE(market_id, ''), position_id,
+			COALESCE(details, ''), created_at
+		FROM events`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		e := &Event{}
+		var positionID sql.NullInt64
+		if err := rows.Scan(&e.ID, &e.Type, &e.Platform, &e.MarketID, &positionID, &e.Details, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		if positionID.Valid {
+			e.PositionID = &positionID.Int64
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate events: %w", err)
+	}
+	return events, nil
+}
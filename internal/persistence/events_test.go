@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEventRepository_RecordAndQuery(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_events_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	positionID, err := NewPositionRepository(db).Create(&Position{
+		Platform:   "kalshi",
+		MarketID:   "KXBTC-1",
+		EntryPrice: 0.9,
+		Quantity:   10,
+		Side:       "YES",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	repo := NewEventRepository(db)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := &Event{
+		Type:       "entry",
+		Platform:   "kalshi",
+		MarketID:   "KXBTC-1",
+		PositionID: &positionID,
+		Details:    `{"price":0.9}`,
+		CreatedAt:  base,
+	}
+	for _, e := range []*Event{
+		entry,
+		{Type: "api_error", Platform: "kalshi", Details: `{"error":"timeout"}`, CreatedAt: base.Add(time.Hour)},
+		{Type: "exit", Platform: "kalshi", MarketID: "KXBTC-1", PositionID: &positionID, CreatedAt: base.Add(2 * time.Hour)},
+	} {
+		if _, err := repo.Record(e); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if entry.ID == 0 {
+		t.Error("expected ID to be set")
+	}
+
+	// Test: no filter returns everything, newest first
+	events, err := repo.Query(EventFilter{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[0].Type != "exit" || events[2].Type != "entry" {
+		t.Errorf("expected newest first, got %s ... %s", events[0].Type, events[2].Type)
+	}
+	got := events[2]
+	if got.Platform != "kalshi" || got.MarketID != "KXBTC-1" || got.Details != `{"price":0.9}` || !got.CreatedAt.Equal(base) {
+		t.Errorf("unexpected event %+v", got)
+	}
+	if got.PositionID == nil || *got.PositionID != positionID {
+		t.Errorf("expected position %d, got %v", positionID, got.PositionID)
+	}
+	if events[1].PositionID != nil || events[1].MarketID != "" {
+		t.Errorf("expected no position or market, got %+v", events[1])
+	}
+
+	// Test: filter by type
+	events, err = repo.Query(EventFilter{Types: []string{"entry", "exit"}})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("expected 2 entry and exit events, got %d", len(events))
+	}
+
+	// Test: filter by time range, since inclusive and until exclusive
+	events, err = repo.Query(EventFilter{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != "api_error" {
+		t.Errorf("expected only the api error, got %d events", len(events))
+	}
+
+	// Test: limit
+	events, err = repo.Query(EventFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != "exit" {
+		t.Errorf("expected only the newest event, got %d events", len(events))
+	}
+}
@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JournalEntry is a closed position with its entry and exit metadata, for
+// analysis outside the bot.
+type JournalEntry struct {
+	PositionID          int64      `json:"position_id"`
+	Platform            string     `json:"platform"`
+	MarketID            string     `json:"market_id"`
+	MarketTitle         string     `json:"market_title"`
+	Outcome             string     `json:"outcome"`
+	Asset               string     `json:"asset"`
+	Strike              float64    `json:"strike"`
+	StrikeUpper         float64    `json:"strike_upper"`
+	Direction           string     `json:"direction"`
+	Side                string     `json:"side"`
+	TradeStrategy       string     `json:"trade_strategy"`
+	EntryStrategy       string     `json:"entry_strategy"`
+	EntryTime           time.Time  `json:"entry_time"`
+	ExitTime            *time.Time `json:"exit_time"`         // Nil if not recorded
+	MarketCloseTime     *time.Time `json:"market_close_time"` // Nil if unknown
+	EntryPrice          float64    `json:"entry_price"`
+	ExitPrice           float64    `json:"exit_price"`
+	Quantity            float64    `json:"quantity"`
+	Fees                float64    `json:"fees"`
+	RealizedPnL         float64    `json:"realized_pnl"`
+	SafetyMarginAtEntry float64    `json:"safety_margin_at_entry"`
+	VolatilityAtEntry   float64    `json:"volatility_at_entry"`
+	ExitReason          string     `json:"exit_reason"`
+	// SkipReasons are the reasons the market was scanned and passed over
+	// before the position was entered, oldest first.
+	SkipReasons []string `json:"skip_reasons"`
+}
+
+// JournalFilter selects closed positions by exit time. Zero fields don't
+// filter.
+type JournalFilter struct {
+	Platform string
+	Since    time.Time // Exited at or after
+	Until    time.Time // Exited before
+}
+
+// GetJournal retrieves the closed positions matching filter as journal
+// entries, oldest exit first.
+func (r *PositionRepository) GetJournal(filter JournalFilter) ([]*JournalEntry, error) {
+	conditions := []string{"p.status = 'closed'"}
+	var args []interface{}
+	if filter.Platform != "" {
+		conditions = append(conditions, "p.platform = ?")
+		args = append(args, filter.Platform)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "p.exit_time >= ?")
+		args = append(args, filter.Since.UTC().Format(timestampFormat))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "p.exit_time < ?")
+		args = append(args, filter.Until.UTC().Format(timestampFormat))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.platform, p.market_id, COALESCE(p.market_title, ''), COALESCE(p.outcome, ''),
+			COALESCE(p.asset, ''), COALESCE(p.strike, 0), COALESCE(p.strike_upper, 0),
+			COALESCE(p.direction, ''), p.side, COALESCE(p.trade_strategy, ''), COALESCE(p.entry_strategy, ''),
+			p.entry_time, p.exit_time, p.market_close_time, p.entry_price_micros, p.exit_price_micros,
+			p.quantity, p.fees_micros, p.realized_pnl_micros, COALESCE(p.safety_margin_at_entry, 0),
+			COALESCE(p.volatility_at_entry, 0), COALESCE(p.exit_reason, '')
+		FROM positions p WHERE `+where+`
+		ORDER BY p.exit_time, p.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get journal: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*JournalEntry
+	byID := make(map[int64]*JournalEntry)
+	for rows.Next() {
+		e := &JournalEntry{}
+		err := rows.Scan(
+			&e.PositionID, &e.Platform, &e.MarketID, &e.MarketTitle, &e.Outcome,
+			&e.Asset, &e.Strike, &e.StrikeUpper,
+			&e.Direction, &e.Side, &e.TradeStrategy, &e.EntryStrategy,
+			&e.EntryTime, &e.ExitTime, &e.MarketCloseTime, dollars(&e.EntryPrice), dollars(&e.ExitPrice),
+			&e.Quantity, dollars(&e.Fees), dollars(&e.RealizedPnL), &e.SafetyMarginAtEntry,
+			&e.VolatilityAtEntry, &e.ExitReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan journal entry: %w", err)
+		}
+		entries = append(entries, e)
+		byID[e.PositionID] = e
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate journal entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	// The reasons each market was passed over before it was entered
+	skips, err := r.db.Query(`
+		SELECT p.id, d.reason
+		FROM scan_decisions d
+		JOIN positions p ON p.platform = d.platform AND p.market_id = d.market_id
+			AND COALESCE(p.outcome, '') = d.outcome
+		WHERE `+where+` AND d.reason != ? AND d.first_seen <= p.entry_time
+		ORDER BY d.first_seen, d.id
+	`, append(args, ScanDecisionEntered)...)
+	if err != nil {
+		return nil, fmt.Errorf("get journal skip reasons: %w", err)
+	}
+	defer skips.Close()
+
+	for skips.Next() {
+		var positionID int64
+		var reason string
+		if err := skips.Scan(&positionID, &reason); err != nil {
+			return nil, fmt.Errorf("scan journal skip reason: %w", err)
+		}
+		byID[positionID].SkipReasons = append(byID[positionID].SkipReasons, reason)
+	}
+	if err := skips.Err(); err != nil {
+		return nil, fmt.Errorf("iterate journal skip reasons: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,107 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestPositionRepository_GetJournal(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_journal_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	// The market was passed over before it was entered
+	decisions := NewScanDecisionRepository(db)
+	if err := decisions.RecordAll([]*ScanDecision{
+		{Platform: "kalshi", MarketID: "KXBTC-1", Reason: "price_range"},
+		{Platform: "kalshi", MarketID: "KXBTC-1", Eligible: true, Reason: ScanDecisionEntered},
+		{Platform: "kalshi", MarketID: "KXETH-1", Reason: "liquidity"},
+	}); err != nil {
+		t.Fatalf("failed to record scan decisions: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+	closedID, err := repo.Create(&Position{
+		Platform:            "kalshi",
+		MarketID:            "KXBTC-1",
+		MarketTitle:         "Bitcoin above 100k",
+		Asset:               "BTC",
+		Strike:              100000,
+		EntryPrice:          0.9,
+		Quantity:            10,
+		Side:                "YES",
+		Status:              PositionStatusOpen,
+		Fees:                0.1,
+		SafetyMarginAtEntry: 2.1,
+		VolatilityAtEntry:   0.55,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+	if err := repo.Close(closedID, 0.75, "stop_loss", types.Dollars(-1.6)); err != nil {
+		t.Fatalf("failed to close position: %v", err)
+	}
+	if _, err := repo.Create(&Position{Platform: "kalshi", MarketID: "KXETH-1", EntryPrice: 0.8, Quantity: 5, Side: "YES", Status: PositionStatusOpen}); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// Test: only closed positions, with their metadata and skip reasons
+	entries, err := repo.GetJournal(JournalFilter{})
+	if err != nil {
+		t.Fatalf("GetJournal failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 closed position, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.PositionID != closedID || e.Asset != "BTC" || e.Strike != 100000 || e.Side != "YES" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if e.ExitPrice != 0.75 || e.RealizedPnL != -1.6 || e.ExitReason != "stop_loss" || e.ExitTime == nil {
+		t.Errorf("unexpected exit metadata %+v", e)
+	}
+	if e.SafetyMarginAtEntry != 2.1 || e.VolatilityAtEntry != 0.55 || e.Fees != 0.1 {
+		t.Errorf("unexpected entry metadata %+v", e)
+	}
+	if len(e.SkipReasons) != 1 || e.SkipReasons[0] != "price_range" {
+		t.Errorf("expected skip reasons [price_range], got %v", e.SkipReasons)
+	}
+
+	// Test: filters by platform and exit time
+	for name, filter := range map[string]JournalFilter{
+		"other platform": {Platform: "polymarket"},
+		"exited before":  {Since: time.Now().Add(time.Hour)},
+		"exited after":   {Until: time.Now().Add(-time.Hour)},
+	} {
+		entries, err := repo.GetJournal(filter)
+		if err != nil {
+			t.Fatalf("%s: GetJournal failed: %v", name, err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("%s: expected no entries, got %d", name, len(entries))
+		}
+	}
+	entries, err = repo.GetJournal(JournalFilter{Platform: "kalshi", Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("GetJournal failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected the closed position within the range, got %d", len(entries))
+	}
+}
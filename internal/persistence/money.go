@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"prediction-bot/pkg/types"
+)
+
+// dollars scans an integer micro-dollar column into dst in dollars.
+func dollars(dst *float64) sql.Scanner {
+	return dollarsScanner{dst: dst}
+}
+
+type dollarsScanner struct {
+	dst *float64
+}
+
+// Scan implements sql.Scanner. NULL scans as 0.
+func (s dollarsScanner) Scan(src any) error {
+	var m sql.Null[types.Money]
+	if err := m.Scan(src); err != nil {
+		return fmt.Errorf("scan micro-dollars: %w", err)
+	}
+	*s.dst = m.V.Float64()
+	return nil
+}
+
+// nullDollars scans a nullable integer micro-dollar column into dst in
+// dollars, setting dst to nil for NULL.
+func nullDollars(dst **float64) sql.Scanner {
+	return nullDollarsScanner{dst: dst}
+}
+
+type nullDollarsScanner struct {
+	dst **float64
+}
+
+// Scan implements sql.Scanner.
+func (s nullDollarsScanner) Scan(src any) error {
+	var m sql.Null[types.Money]
+	if err := m.Scan(src); err != nil {
+		return fmt.Errorf("scan micro-dollars: %w", err)
+	}
+	if !m.Valid {
+		*s.dst = nil
+		return nil
+	}
+	amount := m.V.Float64()
+	*s.dst = &amount
+	return nil
+}
+
+// nullMicros returns a nullable dollar amount as micro-dollars to write,
+// or nil.
+func nullMicros(amount *float64) any {
+	if amount == nil {
+		return nil
+	}
+	return types.Dollars(*amount)
+}
@@ -0,0 +1,94 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// NearMiss represents a market that failed exactly one eligibility threshold.
+type NearMiss struct {
+	ID          int64
+	Platform    string
+	MarketID    string
+	MarketTitle string
+	Asset       string
+	Criterion   string
+	Value       float64
+	Threshold   float64
+	Shortfall   float64
+	Probability float64
+	BetSide     string
+	SeenCount   int
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+// NearMissRepository handles database operations for near misses.
+type NearMissRepository struct {
+	db *sql.DB
+}
+
+// NewNearMissRepository creates a new NearMissRepository.
+func NewNearMissRepository(db *sql.DB) *NearMissRepository {
+	return &NearMissRepository{db: db}
+}
+
+// Record inserts a near miss, or updates the existing row for the same
+// market and criterion. The stored value, threshold and shortfall keep the
+// closest approach (smallest shortfall) seen so far.
+func (r *NearMissRepository) Record(nm *NearMiss) error {
+	_, err := r.db.Exec(`
+		INSERT INTO near_misses (
+			platform, market_id, market_title, asset, criterion,
+			value, threshold, shortfall, probability, bet_side
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (platform, market_id, criterion) DO UPDATE SET
+			value = CASE WHEN excluded.shortfall < shortfall THEN excluded.value ELSE value END,
+			threshold = CASE WHEN excluded.shortfall < shortfall THEN excluded.threshold ELSE threshold END,
+			probability = CASE WHEN excluded.shortfall < shortfall THEN excluded.probability ELSE probability END,
+			shortfall = MIN(shortfall, excluded.shortfall),
+			seen_count = seen_count + 1,
+			last_seen = CURRENT_TIMESTAMP
+	`,
+		nm.Platform, nm.MarketID, nm.MarketTitle, nm.Asset, nm.Criterion,
+		nm.Value, nm.Threshold, nm.Shortfall, nm.Probability, nm.BetSide,
+	)
+	if err != nil {
+		return fmt.Errorf("record near miss: %w", err)
+	}
+	return nil
+}
+
+// GetSince retrieves near misses last seen at or after the given time.
+func (r *NearMissRepository) GetSince(since time.Time) ([]*NearMiss, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
+			criterion, value, threshold, shortfall, COALESCE(probability, 0),
+			COALESCE(bet_side, ''), seen_count, first_seen, last_seen
+		FROM near_misses WHERE last_seen >= ?
+		ORDER BY criterion, shortfall
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("get near misses: %w", err)
+	}
+	defer rows.Close()
+
+	var nearMisses []*NearMiss
+	for rows.Next() {
+		nm := &NearMiss{}
+		err := rows.Scan(
+			&nm.ID, &nm.Platform, &nm.MarketID, &nm.MarketTitle, &nm.Asset,
+			&nm.Criterion, &nm.Value, &nm.Threshold, &nm.Shortfall, &nm.Probability,
+			&nm.BetSide, &nm.SeenCount, &nm.FirstSeen, &nm.LastSeen,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan near miss: %w", err)
+		}
+		nearMisses = append(nearMisses, nm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate near misses: %w", err)
+	}
+	return nearMisses, nil
+}
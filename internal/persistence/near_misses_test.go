@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNearMissRepository_RecordKeepsClosestApproach(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_near_misses_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewNearMissRepository(db)
+
+	// Test: Same market seen three times, closest approach in the middle
+	for _, value := range []float64{0.75, 0.79, 0.77} {
+		err := repo.Record(&NearMiss{
+			Platform:    "polymarket",
+			MarketID:    "0xNEAR",
+			Asset:       "BTC",
+			Criterion:   "probability",
+			Value:       value,
+			Threshold:   0.80,
+			Shortfall:   0.80 - value,
+			Probability: value,
+			BetSide:     "YES",
+		})
+		if err != nil {
+			t.Fatalf("failed to record near miss: %v", err)
+		}
+	}
+
+	// Test: Different criterion is a separate row
+	if err := repo.Record(&NearMiss{
+		Platform:  "polymarket",
+		MarketID:  "0xOTHER",
+		Criterion: "liquidity",
+		Value:     90,
+		Threshold: 100,
+		Shortfall: 10,
+	}); err != nil {
+		t.Fatalf("failed to record near miss: %v", err)
+	}
+
+	nearMisses, err := repo.GetSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get near misses: %v", err)
+	}
+	if len(nearMisses) != 2 {
+		t.Fatalf("expected 2 near misses, got %d", len(nearMisses))
+	}
+
+	// Ordered by criterion: liquidity, probability
+	prob := nearMisses[1]
+	if prob.Criterion != "probability" {
+		t.Fatalf("expected probability near miss second, got %s", prob.Criterion)
+	}
+	if prob.SeenCount != 3 {
+		t.Errorf("expected seen count 3, got %d", prob.SeenCount)
+	}
+	if prob.Value != 0.79 {
+		t.Errorf("expected closest value 0.79, got %f", prob.Value)
+	}
+	if prob.Shortfall < 0.0099 || prob.Shortfall > 0.0101 {
+		t.Errorf("expected shortfall 0.01, got %f", prob.Shortfall)
+	}
+}
@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Operator action kinds.
+const (
+	OperatorActionClosePosition   = "close_position"
+	OperatorActionSetVolatility   = "set_volatility"
+	OperatorActionClearVolatility = "clear_volatility"
+	OperatorActionDatabaseFix     = "database_fix"
+)
+
+// OperatorAction is a manual intervention in the bot's trading, as opposed
+// to a decision the bot made itself.
+type OperatorAction struct {
+	ID int64
+	// Actor identifies who acted: an API token identity or an OS user, such
+	// as "os:alice".
+	Actor     string
+	Action    string // OperatorAction* kind
+	Target    string // What was acted on, such as "position 7" or "SOL"
+	Details   string
+	CreatedAt time.Time
+}
+
+// OperatorActionRepository handles database operations for operator
+// actions.
+type OperatorActionRepository struct {
+	db *sql.DB
+}
+
+// NewOperatorActionRepository creates a new OperatorActionRepository.
+func NewOperatorActionRepository(db *sql.DB) *OperatorActionRepository {
+	return &OperatorActionRepository{db: db}
+}
+
+// Record inserts an operator action and returns its ID.
+func (r *OperatorActionRepository) Record(a *OperatorAction) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO operator_actions (actor, action, target, details)
+		VALUES (?, ?, ?, ?)
+	`, a.Actor, a.Action, a.Target, a.Details)
+	if err != nil {
+		return 0, fmt.Errorf("record operator action: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	a.ID = id
+
+	return id, nil
+}
+
+// GetRecent retrieves the most recent operator actions, newest first.
+func (r *OperatorActionRepository) GetRecent(limit int) ([]*OperatorAction, error) {
+	rows, err := r.db.Query(`
+		SELECT id, actor, action, target, details, created_at
+		FROM operator_actions
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent operator actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []*OperatorAction
+	for rows.Next() {
+		a := &OperatorAction{}
+		var target, details sql.NullString
+		if err := rows.Scan(&a.ID, &a.Actor, &a.Action, &target, &details, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan operator action: %w", err)
+		}
+		a.Target = target.String
+		a.Details = details.String
+		actions = append(actions, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate operator actions: %w", err)
+	}
+	return actions, nil
+}
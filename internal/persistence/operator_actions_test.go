@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOperatorActionRepository_RecordAndGetRecent(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_operator_actions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewOperatorActionRepository(db)
+
+	// Test: Record assigns IDs
+	closeAction := &OperatorAction{
+		Actor:   "os:alice",
+		Action:  OperatorActionClosePosition,
+		Target:  "position 7",
+		Details: "exit at 0.420, dry run",
+	}
+	if _, err := repo.Record(closeAction); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if closeAction.ID == 0 {
+		t.Error("expected ID to be set")
+	}
+	if _, err := repo.Record(&OperatorAction{Actor: "os:bob", Action: OperatorActionClearVolatility, Target: "SOL"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	// Test: GetRecent returns newest first, up to the limit
+	actions, err := repo.GetRecent(10)
+	if err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(actions))
+	}
+	if actions[0].Actor != "os:bob" || actions[0].Target != "SOL" || actions[0].Details != "" {
+		t.Errorf("expected bob's action first, got %+v", actions[0])
+	}
+	got := actions[1]
+	if got.Action != OperatorActionClosePosition || got.Target != "position 7" || got.Details != "exit at 0.420, dry run" {
+		t.Errorf("unexpected action %+v", got)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("expected created_at to be set")
+	}
+
+	actions, err = repo.GetRecent(1)
+	if err != nil {
+		t.Fatalf("GetRecent failed: %v", err)
+	}
+	if len(actions) != 1 {
+		t.Errorf("expected limit of 1, got %d", len(actions))
+	}
+}
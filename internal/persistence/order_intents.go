@@ -0,0 +1,130 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Order intent statuses.
+const (
+	// OrderIntentPending is an intent journaled before its order was
+	// placed. One left pending may or may not have reached the platform,
+	// including when placing it failed: a timed out request may still have
+	// been executed.
+	OrderIntentPending = "pending"
+	// OrderIntentPlaced is an intent whose order the platform accepted,
+	// still working.
+	OrderIntentPlaced = "placed"
+	// OrderIntentSettled is an intent whose order reached a final state
+	// and was handed back to be recorded.
+	OrderIntentSettled = "settled"
+	// OrderIntentReconciled is an intent a crash left pending or placed,
+	// since checked against the platform.
+	OrderIntentReconciled = "reconciled"
+	// OrderIntentRejected is an intent whose order is known not to have
+	// been placed, refused before it was sent or by the platform.
+	OrderIntentRejected = "rejected"
+)
+
+// OrderIntent is a live order the bot was about to place, journaled before
+// it is placed.
+type OrderIntent struct {
+	ID         int64
+	Platform   string
+	MarketID   string
+	TokenID    string
+	PositionID *int64
+	Side       string
+	Price      float64
+	Size       float64
+	OrderID    string // Set once the platform accepts the order
+	Status     string // OrderIntent* status
+	Detail     string // How it was reconciled or rejected
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// OrderIntentRepository handles database operations for order intents.
+type OrderIntentRepository struct {
+	db *sql.DB
+}
+
+// NewOrderIntentRepository creates a new OrderIntentRepository.
+func NewOrderIntentRepository(db *sql.DB) *OrderIntentRepository {
+	return &OrderIntentRepository{db: db}
+}
+
+// Create journals a pending intent and returns its ID.
+func (r *OrderIntentRepository) Create(i *OrderIntent) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO order_intents (platform, market_id, token_id, position_id, side, price, size, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, i.Platform, i.MarketID, i.TokenID, i.PositionID, i.Side, i.Price, i.Size, OrderIntentPending)
+	if err != nil {
+		return 0, fmt.Errorf("create order intent: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	i.ID = id
+	i.Status = OrderIntentPending
+
+	return id, nil
+}
+
+// MarkPlaced records the order ID the platform accepted an intent's order
+// under.
+func (r *OrderIntentRepository) MarkPlaced(id int64, orderID string) error {
+	if _, err := r.db.Exec(`
+		UPDATE order_intents SET order_id = ?, status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, orderID, OrderIntentPlaced, id); err != nil {
+		return fmt.Errorf("mark order intent placed: %w", err)
+	}
+	return nil
+}
+
+// Resolve moves an intent to a final status, with detail on how.
+func (r *OrderIntentRepository) Resolve(id int64, status, detail string) error {
+	if _, err := r.db.Exec(`
+		UPDATE order_intents SET status = ?, detail = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, status, detail, id); err != nil {
+		return fmt.Errorf("resolve order intent: %w", err)
+	}
+	return nil
+}
+
+// GetUnsettled retrieves the intents still pending or placed, oldest first.
+// Outside of an order in progress these were interrupted by a crash.
+func (r *OrderIntentRepository) GetUnsettled() ([]*OrderIntent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, COALESCE(token_id, ''), position_id, side, price, size,
+			COALESCE(order_id, ''), status, COALESCE(detail, ''), created_at, updated_at
+		FROM order_intents WHERE status IN (?, ?)
+		ORDER BY id
+	`, OrderIntentPending, OrderIntentPlaced)
+	if err != nil {
+		return nil, fmt.Errorf("get unsettled order intents: %w", err)
+	}
+	defer rows.Close()
+
+	var intents []*OrderIntent
+	for rows.Next() {
+		i := &OrderIntent{}
+		if err := rows.Scan(
+			&i.ID, &i.Platform, &i.MarketID, &i.TokenID, &i.PositionID, &i.Side, &i.Price, &i.Size,
+			&i.OrderID, &i.Status, &i.Detail, &i.CreatedAt, &i.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan order intent: %w", err)
+		}
+		intents = append(intents, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate order intents: %w", err)
+	}
+	return intents, nil
+}
@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOrderIntentRepository_Lifecycle(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewOrderIntentRepository(db)
+	newIntent := func(marketID string) *OrderIntent {
+		i := &OrderIntent{Platform: "kalshi", MarketID: marketID, TokenID: "yes", Side: "buy", Price: 0.85, Size: 10}
+		if _, err := repo.Create(i); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return i
+	}
+
+	pending := newIntent("pending")
+	placed := newIntent("placed")
+	settled := newIntent("settled")
+	if pending.ID == 0 || pending.Status != OrderIntentPending {
+		t.Errorf("expected a pending intent with an ID, got %+v", pending)
+	}
+
+	if err := repo.MarkPlaced(placed.ID, "order-1"); err != nil {
+		t.Fatalf("MarkPlaced failed: %v", err)
+	}
+	if err := repo.MarkPlaced(settled.ID, "order-2"); err != nil {
+		t.Fatalf("MarkPlaced failed: %v", err)
+	}
+	if err := repo.Resolve(settled.ID, OrderIntentSettled, ""); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	reconciled := newIntent("reconciled")
+	if err := repo.Resolve(reconciled.ID, OrderIntentReconciled, "no order on the platform"); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	unsettled, err := repo.GetUnsettled()
+	if err != nil {
+		t.Fatalf("GetUnsettled failed: %v", err)
+	}
+	if len(unsettled) != 2 {
+		t.Fatalf("expected the pending and placed intents, got %d", len(unsettled))
+	}
+	if got := unsettled[0]; got.MarketID != "pending" || got.OrderID != "" || got.Status != OrderIntentPending {
+		t.Errorf("expected the pending intent first, got %+v", got)
+	}
+	if got := unsettled[1]; got.MarketID != "placed" || got.OrderID != "order-1" || got.Status != OrderIntentPlaced {
+		t.Errorf("expected the placed intent with its order ID, got %+v", got)
+	}
+	if got := unsettled[1]; got.TokenID != "yes" || got.Price != 0.85 || got.Size != 10 || got.PositionID != nil {
+		t.Errorf("expected the intent's order read back, got %+v", got)
+	}
+}
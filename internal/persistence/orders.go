@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Order represents an order placed by the bot.
+type Order struct {
+	ID         int64
+	OrderID    string
+	Platform   string
+	MarketID   string
+	TokenID    string
+	PositionID *int64
+	Side       string
+	Price      float64
+	Size       float64
+	Filled     float64
+	Fees       float64
+	Status     string
+	IsDryRun   bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// OrderRepository handles database operations for orders.
+type OrderRepository struct {
+	db *sql.DB
+}
+
+// NewOrderRepository creates a new OrderRepository.
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create inserts a new order and returns its ID.
+func (r *OrderRepository) Create(o *Order) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO orders (
+			order_id, platform, market_id, token_id, position_id,
+			side, price, size, filled, fees_micros, status, is_dry_run
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		o.OrderID, o.Platform, o.MarketID, o.TokenID, o.PositionID,
+		o.Side, o.Price, o.Size, o.Filled, types.Dollars(o.Fees), o.Status, o.IsDryRun,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("create order: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	o.ID = id
+
+	return id, nil
+}
+
+// GetByOrderID retrieves an order by its platform order ID.
+func (r *OrderRepository) GetByOrderID(orderID string) (*Order, error) {
+	rows, err := r.db.Query(`
+		SELECT id, order_id, platform, market_id, COALESCE(token_id, ''), position_id,
+			side, price, size, filled, fees_micros, status, is_dry_run, created_at, updated_at
+		FROM orders WHERE order_id = ?
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("get order: %w", err)
+	}
+	defer rows.Close()
+
+	orders, err := r.scanOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	return orders[0], nil
+}
+
+// GetActive retrieves all orders that may still fill.
+func (r *OrderRepository) GetActive() ([]*Order, error) {
+	rows, err := r.db.Query(`
+		SELECT id, order_id, platform, market_id, COALESCE(token_id, ''), position_id,
+			side, price, size, filled, fees_micros, status, is_dry_run, created_at, updated_at
+		FROM orders WHERE status IN ('pending', 'open', 'partially_filled')
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get active orders: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanOrders(rows)
+}
+
+// GetByPosition retrieves all orders linked to a position.
+func (r *OrderRepository) GetByPosition(positionID int64) ([]*Order, error) {
+	rows, err := r.db.Query(`
+		SELECT id, order_id, platform, market_id, COALESCE(token_id, ''), position_id,
+			side, price, size, filled, fees_micros, status, is_dry_run, created_at, updated_at
+		FROM orders WHERE position_id = ?
+		ORDER BY created_at
+	`, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("get orders by position: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanOrders(rows)
+}
+
+// UpdateFill records the latest filled quantity, fees and status of an order.
+func (r *OrderRepository) UpdateFill(orderID string, filled, fees float64, status string) error {
+	_, err := r.db.Exec(`
+		UPDATE orders SET
+			filled = ?,
+			fees_micros = ?,
+			status = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = ?
+	`, filled, types.Dollars(fees), status, orderID)
+	if err != nil {
+		return fmt.Errorf("update order fill: %w", err)
+	}
+	return nil
+}
+
+// scanOrders scans multiple orders from rows.
+func (r *OrderRepository) scanOrders(rows *sql.Rows) ([]*Order, error) {
+	var orders []*Order
+	for rows.Next() {
+		o := &Order{}
+		err := rows.Scan(
+			&o.ID, &o.OrderID, &o.Platform, &o.MarketID, &o.TokenID, &o.PositionID,
+			&o.Side, &o.Price, &o.Size, &o.Filled, dollars(&o.Fees), &o.Status, &o.IsDryRun,
+			&o.CreatedAt, &o.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate orders: %w", err)
+	}
+	return orders, nil
+}
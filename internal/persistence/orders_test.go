@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOrderRepository_Lifecycle(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_orders_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	positionID, err := NewPositionRepository(db).Create(&Position{
+		Platform:   "kalshi",
+		MarketID:   "KXBTC",
+		EntryPrice: 0.85,
+		Quantity:   10,
+		Side:       "YES",
+		Status:     PositionStatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	repo := NewOrderRepository(db)
+
+	order := &Order{
+		OrderID:    "ord-1",
+		Platform:   "kalshi",
+		MarketID:   "KXBTC",
+		TokenID:    "yes",
+		PositionID: &positionID,
+		Side:       "BUY",
+		Price:      0.85,
+		Size:       10,
+		Status:     "open",
+	}
+	if _, err := repo.Create(order); err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+	if order.ID == 0 {
+		t.Error("expected ID to be set")
+	}
+
+	// Test: Open order is active
+	active, err := repo.GetActive()
+	if err != nil {
+		t.Fatalf("failed to get active orders: %v", err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("expected 1 active order, got %d", len(active))
+	}
+
+	// Test: Partial fill keeps the order active
+	if err := repo.UpdateFill("ord-1", 4, 0.07, "partially_filled"); err != nil {
+		t.Fatalf("failed to update fill: %v", err)
+	}
+	got, err := repo.GetByOrderID("ord-1")
+	if err != nil {
+		t.Fatalf("failed to get order: %v", err)
+	}
+	if got.Filled != 4 || got.Fees != 0.07 || got.Status != "partially_filled" {
+		t.Errorf("expected partial fill of 4 with 0.07 fees, got %f %f %s", got.Filled, got.Fees, got.Status)
+	}
+	if got.PositionID == nil || *got.PositionID != positionID {
+		t.Errorf("expected position ID %d, got %v", positionID, got.PositionID)
+	}
+
+	// Test: Cancelled order is no longer active but still linked to its position
+	if err := repo.UpdateFill("ord-1", 4, 0.07, "cancelled"); err != nil {
+		t.Fatalf("failed to update fill: %v", err)
+	}
+	active, _ = repo.GetActive()
+	if len(active) != 0 {
+		t.Errorf("expected no active orders, got %d", len(active))
+	}
+	byPosition, err := repo.GetByPosition(positionID)
+	if err != nil {
+		t.Fatalf("failed to get orders by position: %v", err)
+	}
+	if len(byPosition) != 1 {
+		t.Errorf("expected 1 order for position, got %d", len(byPosition))
+	}
+
+	// Test: Unknown order returns nil
+	missing, err := repo.GetByOrderID("nope")
+	if err != nil || missing != nil {
+		t.Errorf("expected nil for unknown order, got %v, %v", missing, err)
+	}
+}
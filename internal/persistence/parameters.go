@@ -3,6 +3,8 @@ package persistence
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,6 +15,69 @@ type Parameter struct {
 	MinValue  float64
 	MaxValue  float64
 	UpdatedAt time.Time
+	// Platform and Asset scope a parameter learned for their trades (see
+	// ScopedName), empty for a global one.
+	Platform string
+	Asset    string
+}
+
+// ScopedName returns the name a parameter learned for the trades of a
+// platform and asset is stored under: "platform:asset:name". Either may be
+// empty to scope it to the other alone; with both empty it is the global
+// name.
+func ScopedName(platform, asset, name string) string {
+	if platform == "" && asset == "" {
+		return name
+	}
+	return platform + ":" + asset + ":" + name
+}
+
+// SplitScopedName returns the platform, asset and global parameter name of
+// a name returned by ScopedName.
+func SplitScopedName(scoped string) (platform, asset, name string) {
+	parts := strings.SplitN(scoped, ":", 3)
+	if len(parts) != 3 {
+		return "", "", scoped
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+// ScopedValues returns the global values in values (by name, as the
+// parameters table holds them) overridden by those scoped to asset, then
+// to platform, then to both, so each parameter falls back to the global
+// one where nothing more specific was learned.
+func ScopedValues(values map[string]float64, platform, asset string) map[string]float64 {
+	resolved := make(map[string]float64, len(values))
+	rank := make(map[string]int, len(values))
+	for scoped, value := range values {
+		p, a, name := SplitScopedName(scoped)
+		if (p != "" && p != platform) || (a != "" && a != asset) {
+			continue
+		}
+		r := 0
+		if a != "" {
+			r++
+		}
+		if p != "" {
+			r += 2
+		}
+		if _, ok := resolved[name]; ok && rank[name] > r {
+			continue
+		}
+		resolved[name] = value
+		rank[name] = r
+	}
+	return resolved
+}
+
+// HasScopes reports whether values include a scoped parameter.
+func HasScopes(values map[string]float64) bool {
+	for name := range values {
+		if platform, asset, _ := SplitScopedName(name); platform != "" || asset != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // ParameterChange represents a historical parameter adjustment.
@@ -39,7 +104,7 @@ func NewParametersRepository(db *sql.DB) *ParametersRepository {
 func (r *ParametersRepository) GetCurrent() (map[string]Parameter, error) {
 	rows, err := r.db.Query(`
 		SELECT name, value, COALESCE(min_value, 0), COALESCE(max_value, 1),
-		       COALESCE(updated_at, CURRENT_TIMESTAMP)
+		       COALESCE(updated_at, CURRENT_TIMESTAMP), platform, asset
 		FROM parameters
 	`)
 	if err != nil {
@@ -51,7 +116,7 @@ func (r *ParametersRepository) GetCurrent() (map[string]Parameter, error) {
 	for rows.Next() {
 		var p Parameter
 		var updatedAtStr string
-		if err := rows.Scan(&p.Name, &p.Value, &p.MinValue, &p.MaxValue, &updatedAtStr); err != nil {
+		if err := rows.Scan(&p.Name, &p.Value, &p.MinValue, &p.MaxValue, &updatedAtStr, &p.Platform, &p.Asset); err != nil {
 			return nil, fmt.Errorf("scan parameter: %w", err)
 		}
 		p.UpdatedAt = parseTimestamp(updatedAtStr)
@@ -72,10 +137,10 @@ func (r *ParametersRepository) GetByName(name string) (Parameter, error) {
 
 	err := r.db.QueryRow(`
 		SELECT name, value, COALESCE(min_value, 0), COALESCE(max_value, 1),
-		       COALESCE(updated_at, CURRENT_TIMESTAMP)
+		       COALESCE(updated_at, CURRENT_TIMESTAMP), platform, asset
 		FROM parameters
 		WHERE name = ?
-	`, name).Scan(&p.Name, &p.Value, &p.MinValue, &p.MaxValue, &updatedAtStr)
+	`, name).Scan(&p.Name, &p.Value, &p.MinValue, &p.MaxValue, &updatedAtStr, &p.Platform, &p.Asset)
 
 	if err == sql.ErrNoRows {
 		return Parameter{}, fmt.Errorf("parameter not found: %s", name)
@@ -151,6 +216,189 @@ func (r *ParametersRepository) SaveWithReason(name string, value float64, reason
 	return nil
 }
 
+// SaveScoped saves the value of a parameter learned for the trades of a
+// platform and asset (see ScopedName) and records the change in history,
+// from the value the scope fell back to if it had none. A new scoped
+// parameter takes the global one's bounds. With both empty it saves the
+// global parameter.
+func (r *ParametersRepository) SaveScoped(platform, asset, name string, value float64, reason string) error {
+	scoped := ScopedName(platform, asset, name)
+	if scoped == name {
+		return r.SaveWithReason(name, value, reason)
+	}
+	current, err := r.GetCurrent()
+	if err != nil {
+		return fmt.Errorf("get current values: %w", err)
+	}
+	global, ok := current[name]
+	if !ok {
+		return fmt.Errorf("parameter not found: %s", name)
+	}
+	old := ScopedValues(parameterValues(current), platform, asset)[name]
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO parameters (name, platform, asset, value, min_value, max_value)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP
+	`, scoped, platform, asset, value, global.MinValue, global.MaxValue)
+	if err != nil {
+		return fmt.Errorf("save parameter %s: %w", scoped, err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO parameter_history (name, old_value, new_value, reason)
+		VALUES (?, ?, ?, ?)
+	`, scoped, old, value, reason)
+	if err != nil {
+		return fmt.Errorf("insert history: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordChange records a change in history without changing the parameters
+// table, such as of a setting reloaded from config.yaml or a temporary
+// adjustment of a parameter.
+func (r *ParametersRepository) RecordChange(name string, oldValue, newValue float64, reason string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO parameter_history (name, old_value, new_value, reason)
+		VALUES (?, ?, ?, ?)
+	`, name, oldValue, newValue, reason)
+	if err != nil {
+		return fmt.Errorf("record parameter change %s: %w", name, err)
+	}
+	return nil
+}
+
+// SyncConfigValues sets each parameter in the table to its value in values,
+// from config.yaml, if that value changed since it was last synced (or was
+// never synced), recording the change in history with reason. Parameters
+// whose config value didn't change keep their value, however learning
+// adjusted it. Names not in the table are ignored. Returns the changes.
+func (r *ParametersRepository) SyncConfigValues(values map[string]float64, reason string) ([]ParameterChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT name, value, config_value FROM parameters`)
+	if err != nil {
+		return nil, fmt.Errorf("query parameters: %w", err)
+	}
+	var changes []ParameterChange
+	var synced []string
+	for rows.Next() {
+		var name string
+		var value float64
+		var configValue sql.NullFloat64
+		if err := rows.Scan(&name, &value, &configValue); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan parameter: %w", err)
+		}
+		next, ok := values[name]
+		if !ok || (configValue.Valid && configValue.Float64 == next) {
+			continue
+		}
+		synced = append(synced, name)
+		if next != value {
+			changes = append(changes, ParameterChange{Name: name, OldValue: value, NewValue: next, Reason: reason})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate parameters: %w", err)
+	}
+	rows.Close()
+
+	for _, name := range synced {
+		if _, err := tx.Exec(`
+			UPDATE parameters
+			SET value = ?, config_value = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE name = ?
+		`, values[name], values[name], name); err != nil {
+			return nil, fmt.Errorf("update parameter %s: %w", name, err)
+		}
+	}
+	for _, c := range changes {
+		if _, err := tx.Exec(`
+			INSERT INTO parameter_history (name, old_value, new_value, reason)
+			VALUES (?, ?, ?, ?)
+		`, c.Name, c.OldValue, c.NewValue, reason); err != nil {
+			return nil, fmt.Errorf("insert history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return changes, nil
+}
+
+// RevertTo sets each parameter named in values that differs from its value
+// there back to it, recording the change in history with reason. Scoped
+// parameters are set to the value of their global name. Names not in the
+// table are ignored. Returns the changes, by name.
+func (r *ParametersRepository) RevertTo(values map[string]float64, reason string) ([]ParameterChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT name, value FROM parameters ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("query parameters: %w", err)
+	}
+	var changes []ParameterChange
+	for rows.Next() {
+		c := ParameterChange{Reason: reason}
+		if err := rows.Scan(&c.Name, &c.OldValue); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan parameter: %w", err)
+		}
+		_, _, name := SplitScopedName(c.Name)
+		value, ok := values[name]
+		if !ok || value == c.OldValue {
+			continue
+		}
+		c.NewValue = value
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate parameters: %w", err)
+	}
+	rows.Close()
+
+	for _, c := range changes {
+		if _, err := tx.Exec(`
+			UPDATE parameters SET value = ?, updated_at = CURRENT_TIMESTAMP WHERE name = ?
+		`, c.NewValue, c.Name); err != nil {
+			return nil, fmt.Errorf("update parameter %s: %w", c.Name, err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO parameter_history (name, old_value, new_value, reason)
+			VALUES (?, ?, ?, ?)
+		`, c.Name, c.OldValue, c.NewValue, reason); err != nil {
+			return nil, fmt.Errorf("insert history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return changes, nil
+}
+
 // GetHistory returns the most recent parameter changes.
 func (r *ParametersRepository) GetHistory(name string, limit int) ([]ParameterChange, error) {
 	rows, err := r.db.Query(`
@@ -211,6 +459,61 @@ func (r *ParametersRepository) GetLastAdjustmentTime(name string) (time.Time, er
 	return parseTimestamp(createdAtStr.String), nil
 }
 
+// ParameterCache caches the current parameter values, reading the table
+// again once they are older than its TTL, so the bot loop sees adjustments
+// made by learning or botctl without querying the table every cycle.
+type ParameterCache struct {
+	repo *ParametersRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.Mutex
+	values   map[string]float64
+	loadedAt time.Time
+}
+
+// NewParameterCache creates a cache of repo's parameters read again after
+// ttl.
+func NewParameterCache(repo *ParametersRepository, ttl time.Duration) *ParameterCache {
+	return &ParameterCache{repo: repo, ttl: ttl, now: time.Now}
+}
+
+// Values returns each parameter's current value, by name, scoped ones
+// included (see ScopedValues). If the table can't be read, the cached values
+// are returned with the error.
+func (c *ParameterCache) Values() (map[string]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.values != nil && c.now().Sub(c.loadedAt) < c.ttl {
+		return c.values, nil
+	}
+	params, err := c.repo.GetCurrent()
+	if err != nil {
+		return c.values, err
+	}
+	c.values = parameterValues(params)
+	c.loadedAt = c.now()
+	return c.values, nil
+}
+
+// Invalidate makes the next Values read the table, such as after a change
+// the bot made itself.
+func (c *ParameterCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = nil
+}
+
+// parameterValues returns the values of params, by name.
+func parameterValues(params map[string]Parameter) map[string]float64 {
+	values := make(map[string]float64, len(params))
+	for name, p := range params {
+		values[name] = p.Value
+	}
+	return values
+}
+
 // parseTimestamp attempts to parse a timestamp string from SQLite.
 func parseTimestamp(s string) time.Time {
 	formats := []string{
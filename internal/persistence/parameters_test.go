@@ -231,3 +231,183 @@ func TestParametersRepository_GetLastAdjustmentTime(t *testing.T) {
 		t.Errorf("expected recent adjustment time, got %v", lastTime)
 	}
 }
+
+func TestParametersRepository_SyncConfigValues(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	wd, _ := os.Getwd()
+	if err := RunMigrations(db, filepath.Join(wd, "..", "..", "migrations")); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := NewParametersRepository(db)
+
+	// First sync: config.yaml's values replace the defaults
+	changes, err := repo.SyncConfigValues(map[string]float64{"kelly_fraction": 0.20, "stop_loss_percent": 0.15, "take_profit_percent": 0.5}, "config.yaml")
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Name != "kelly_fraction" || changes[0].OldValue != 0.25 || changes[0].NewValue != 0.20 {
+		t.Errorf("expected only kelly_fraction changed from 0.25 to 0.20, got %+v", changes)
+	}
+
+	// Learning adjusts the table; an unchanged config keeps its value
+	if err := repo.SaveWithReason("kelly_fraction", 0.30, "learning"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if changes, err := repo.SyncConfigValues(map[string]float64{"kelly_fraction": 0.20}, "config.yaml"); err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes for an unchanged config, got %+v, %v", changes, err)
+	}
+	if param, _ := repo.GetByName("kelly_fraction"); param.Value != 0.30 {
+		t.Errorf("expected the learned 0.30 kept, got %v", param.Value)
+	}
+
+	// Changing config.yaml overrides it
+	if _, err := repo.SyncConfigValues(map[string]float64{"kelly_fraction": 0.10}, "config.yaml"); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if param, _ := repo.GetByName("kelly_fraction"); param.Value != 0.10 {
+		t.Errorf("expected config's 0.10, got %v", param.Value)
+	}
+	history, err := repo.GetHistory("kelly_fraction", 10)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	overridden := false
+	for _, c := range history {
+		overridden = overridden || (c.OldValue == 0.30 && c.NewValue == 0.10 && c.Reason == "config.yaml")
+	}
+	if len(history) != 3 || !overridden {
+		t.Errorf("expected the override recorded, got %+v", history)
+	}
+}
+
+func TestParametersRepository_SaveScoped(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	wd, _ := os.Getwd()
+	if err := RunMigrations(db, filepath.Join(wd, "..", "..", "migrations")); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := NewParametersRepository(db)
+
+	// A new scoped parameter takes the global bounds, and its history starts
+	// from the global value it fell back to
+	if err := repo.SaveScoped("kalshi", "BTC", "probability_threshold", 0.85, "learning"); err != nil {
+		t.Fatalf("save scoped: %v", err)
+	}
+	param, err := repo.GetByName("kalshi:BTC:probability_threshold")
+	if err != nil {
+		t.Fatalf("get by name: %v", err)
+	}
+	if param.Value != 0.85 || param.MinValue != 0.75 || param.MaxValue != 0.95 || param.Platform != "kalshi" || param.Asset != "BTC" {
+		t.Errorf("expected kalshi BTC at 0.85 within 0.75-0.95, got %+v", param)
+	}
+	history, err := repo.GetHistory("kalshi:BTC:probability_threshold", 10)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(history) != 1 || history[0].OldValue != 0.80 || history[0].NewValue != 0.85 {
+		t.Errorf("expected the change from the global 0.80 recorded, got %+v", history)
+	}
+	if global, _ := repo.GetByName("probability_threshold"); global.Value != 0.80 {
+		t.Errorf("expected the global value kept, got %v", global.Value)
+	}
+
+	// The most specific scope wins: platform and asset, platform, asset,
+	// then global
+	if err := repo.SaveScoped("", "ETH", "probability_threshold", 0.90, "learning"); err != nil {
+		t.Fatalf("save scoped: %v", err)
+	}
+	if err := repo.SaveScoped("kalshi", "", "probability_threshold", 0.88, "learning"); err != nil {
+		t.Fatalf("save scoped: %v", err)
+	}
+	current, err := repo.GetCurrent()
+	if err != nil {
+		t.Fatalf("get current: %v", err)
+	}
+	values := parameterValues(current)
+	if !HasScopes(values) {
+		t.Error("expected scoped parameters found")
+	}
+	for _, tc := range []struct {
+		platform, asset string
+		want            float64
+	}{
+		{"kalshi", "BTC", 0.85},
+		{"kalshi", "ETH", 0.88},
+		{"polymarket", "ETH", 0.90},
+		{"polymarket", "BTC", 0.80},
+	} {
+		resolved := ScopedValues(values, tc.platform, tc.asset)
+		if resolved["probability_threshold"] != tc.want || resolved["kelly_fraction"] != 0.25 {
+			t.Errorf("expected %s %s at %v with the global kelly fraction, got %v", tc.platform, tc.asset, tc.want, resolved)
+		}
+	}
+
+	// Reverting reverts the scoped parameters to the global value too
+	changes, err := repo.RevertTo(map[string]float64{"probability_threshold": 0.80}, "drawdown")
+	if err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Errorf("expected the 3 scoped parameters reverted, got %+v", changes)
+	}
+	current, _ = repo.GetCurrent()
+	if resolved := ScopedValues(parameterValues(current), "kalshi", "BTC"); resolved["probability_threshold"] != 0.80 {
+		t.Errorf("expected kalshi BTC reverted to 0.80, got %v", resolved["probability_threshold"])
+	}
+}
+
+func TestParameterCache_Values(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	wd, _ := os.Getwd()
+	if err := RunMigrations(db, filepath.Join(wd, "..", "..", "migrations")); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	repo := NewParametersRepository(db)
+	cache := NewParameterCache(repo, time.Minute)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	values, err := cache.Values()
+	if err != nil {
+		t.Fatalf("values: %v", err)
+	}
+	if values["kelly_fraction"] != 0.25 {
+		t.Errorf("expected kelly_fraction 0.25, got %v", values["kelly_fraction"])
+	}
+
+	// Cached until the TTL passes
+	if err := repo.Save("kelly_fraction", 0.40); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if values, _ := cache.Values(); values["kelly_fraction"] != 0.25 {
+		t.Errorf("expected the cached 0.25, got %v", values["kelly_fraction"])
+	}
+	now = now.Add(time.Minute)
+	if values, _ := cache.Values(); values["kelly_fraction"] != 0.40 {
+		t.Errorf("expected 0.40 read after the TTL, got %v", values["kelly_fraction"])
+	}
+
+	// Invalidate reads it again at once
+	if err := repo.Save("kelly_fraction", 0.15); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	cache.Invalidate()
+	if values, _ := cache.Values(); values["kelly_fraction"] != 0.15 {
+		t.Errorf("expected 0.15 read after Invalidate, got %v", values["kelly_fraction"])
+	}
+}
@@ -2,11 +2,97 @@ package persistence
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Position lifecycle states.
+const (
+	// PositionStatusPendingEntry is a position whose entry has been recorded
+	// but not yet confirmed.
+	PositionStatusPendingEntry = "pending_entry"
+	// PositionStatusOpen is a live position being monitored.
+	PositionStatusOpen = "open"
+	// PositionStatusExiting is a position whose exit is in progress.
+	PositionStatusExiting = "exiting"
+	// PositionStatusClosed is a fully exited position. It is terminal.
+	PositionStatusClosed = "closed"
+	// PositionStatusError is a position whose last transition failed midway.
+	PositionStatusError = "error"
+	// PositionStatusReconciling is a position being checked against the platform.
+	PositionStatusReconciling = "reconciling"
+	// PositionStatusPendingSettlement is a position whose market resolved in
+	// its favour but whose payout has not yet been redeemed.
+	PositionStatusPendingSettlement = "pending_settlement"
 )
 
-// Position represents a trading position in the database.
+// ErrInvalidTransition is returned when a position status change is not
+// allowed by the lifecycle state machine.
+var ErrInvalidTransition = errors.New("invalid position status transition")
+
+// ErrConflict is returned when a position was modified by another writer
+// since it was read.
+var ErrConflict = errors.New("position update conflict")
+
+// ConflictError describes a failed compare-and-swap on a position. It
+// matches ErrConflict with errors.Is.
+type ConflictError struct {
+	PositionID      int64
+	ExpectedVersion int64 // 0 if the update was conditioned on status only
+	ActualVersion   int64
+	ActualStatus    string
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("position %d was modified concurrently (expected version %d, now version %d, status %s)",
+		e.PositionID, e.ExpectedVersion, e.ActualVersion, e.ActualStatus)
+}
+
+// Unwrap returns ErrConflict.
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// positionTransitions lists the statuses reachable from each status.
+var positionTransitions = map[string][]string{
+	PositionStatusPendingEntry:      {PositionStatusOpen, PositionStatusClosed, PositionStatusError, PositionStatusReconciling},
+	PositionStatusOpen:              {PositionStatusExiting, PositionStatusClosed, PositionStatusError, PositionStatusReconciling, PositionStatusPendingSettlement},
+	PositionStatusExiting:           {PositionStatusOpen, PositionStatusClosed, PositionStatusError, PositionStatusReconciling},
+	PositionStatusError:             {PositionStatusReconciling},
+	PositionStatusReconciling:       {PositionStatusOpen, PositionStatusClosed, PositionStatusError},
+	PositionStatusClosed:            {},
+	PositionStatusPendingSettlement: {PositionStatusExiting, PositionStatusClosed, PositionStatusError, PositionStatusReconciling},
+}
+
+// CanTransition reports whether a position may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, allowed := range positionTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// statusesTransitioningTo returns every status from which to is reachable.
+func statusesTransitioningTo(to string) []string {
+	var from []string
+	for status := range positionTransitions {
+		if CanTransition(status, to) {
+			from = append(from, status)
+		}
+	}
+	return from
+}
+
+// Position represents a trading position in the database. Prices, fees and
+// PnL are stored as integer micro-dollars (types.Money) and exposed in
+// dollars.
 type Position struct {
 	ID                  int64
 	Platform            string
@@ -14,20 +100,35 @@ type Position struct {
 	MarketTitle         string
 	Asset               string
 	Strike              float64
+	StrikeUpper         float64 // Upper bound of a "between" bracket; 0 otherwise
 	Direction           string
+	Outcome             string // Outcome traded on a multi-outcome market; empty for binaries
+	TradeStrategy       string // "fade" for fades of an overpriced side, "hedge" for hedge legs; empty when following the market
+	Strategy            string // Strategy file the position was entered under; empty without one
+	StrategyVersion     int    // Version of that strategy file
+	Experiment          string // Parameter experiment the position was entered under; empty outside one
+	ExperimentArm       string // Arm of that experiment: "champion" or "challenger"
 	EntryPrice          float64
 	ExitPrice           *float64
 	Quantity            float64
 	Side                string
+	TokenID             string
 	Status              string
 	EntryTime           time.Time
 	ExitTime            *time.Time
 	ExitReason          *string
 	RealizedPnL         *float64
+	Fees                float64
+	MarketCloseTime     *time.Time // Nil if unknown
+	PeakPrice           float64    // Highest price seen while open, for trailing stops
+	TakeProfitPercent   *float64   // Overrides the configured take profit; nil uses the default
+	EntryStrategy       string     // How the entry was executed; empty for positions opened before it was recorded
 	SafetyMarginAtEntry float64
 	VolatilityAtEntry   float64
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
+	// Version is incremented on every update and used for compare-and-swap.
+	Version int64
 }
 
 // PositionRepository handles database operations for positions.
@@ -42,16 +143,25 @@ func NewPositionRepository(db *sql.DB) *PositionRepository {
 
 // Create inserts a new position and returns its ID.
 func (r *PositionRepository) Create(pos *Position) (int64, error) {
-	result, err := r.db.Exec(`
+	return createPosition(r.db, pos)
+}
+
+// createPosition inserts a position with q and sets its ID and version.
+func createPosition(q querier, pos *Position) (int64, error) {
+	result, err := q.Exec(`
 		INSERT INTO positions (
 			platform, market_id, market_title, asset, strike, direction,
-			entry_price, quantity, side, status,
-			safety_margin_at_entry, volatility_at_entry
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			entry_price_micros, quantity, side, token_id, status, fees_micros,
+			safety_margin_at_entry, volatility_at_entry, market_close_time,
+			take_profit_percent, entry_strategy, outcome, strike_upper,
+			trade_strategy, strategy, strategy_version, experiment, experiment_arm
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		pos.Platform, pos.MarketID, pos.MarketTitle, pos.Asset, pos.Strike, pos.Direction,
-		pos.EntryPrice, pos.Quantity, pos.Side, pos.Status,
-		pos.SafetyMarginAtEntry, pos.VolatilityAtEntry,
+		types.Dollars(pos.EntryPrice), pos.Quantity, pos.Side, pos.TokenID, pos.Status, types.Dollars(pos.Fees),
+		pos.SafetyMarginAtEntry, pos.VolatilityAtEntry, pos.MarketCloseTime,
+		pos.TakeProfitPercent, pos.EntryStrategy, pos.Outcome, pos.StrikeUpper,
+		pos.TradeStrategy, pos.Strategy, pos.StrategyVersion, pos.Experiment, pos.ExperimentArm,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("create position: %w", err)
@@ -61,27 +171,41 @@ func (r *PositionRepository) Create(pos *Position) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("get last insert id: %w", err)
 	}
+	pos.ID = id
+	pos.Version = 1
 
 	return id, nil
 }
 
 // GetByID retrieves a position by its ID.
 func (r *PositionRepository) GetByID(id int64) (*Position, error) {
+	return getPosition(r.db, id)
+}
+
+// getPosition retrieves a position by its ID with q.
+func getPosition(q querier, id int64) (*Position, error) {
 	pos := &Position{}
-	err := r.db.QueryRow(`
+	err := q.QueryRow(`
 		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
-			COALESCE(strike, 0), COALESCE(direction, ''), entry_price, exit_price,
-			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl,
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
 			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
-			created_at, updated_at
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
 		FROM positions WHERE id = ?
 	`, id).Scan(
 		&pos.ID, &pos.Platform, &pos.MarketID, &pos.MarketTitle, &pos.Asset,
-		&pos.Strike, &pos.Direction, &pos.EntryPrice, &pos.ExitPrice,
+		&pos.Strike, &pos.Direction, dollars(&pos.EntryPrice), nullDollars(&pos.ExitPrice),
 		&pos.Quantity, &pos.Side, &pos.Status, &pos.EntryTime, &pos.ExitTime,
-		&pos.ExitReason, &pos.RealizedPnL,
+		&pos.ExitReason, nullDollars(&pos.RealizedPnL),
 		&pos.SafetyMarginAtEntry, &pos.VolatilityAtEntry,
-		&pos.CreatedAt, &pos.UpdatedAt,
+		&pos.CreatedAt, &pos.UpdatedAt, &pos.Version, &pos.TokenID, dollars(&pos.Fees),
+		&pos.MarketCloseTime, dollars(&pos.PeakPrice), &pos.TakeProfitPercent, &pos.EntryStrategy,
+		&pos.Outcome, &pos.StrikeUpper, &pos.TradeStrategy, &pos.Strategy, &pos.StrategyVersion,
+		&pos.Experiment, &pos.ExperimentArm,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -96,10 +220,14 @@ func (r *PositionRepository) GetByID(id int64) (*Position, error) {
 func (r *PositionRepository) GetOpen() ([]*Position, error) {
 	rows, err := r.db.Query(`
 		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
-			COALESCE(strike, 0), COALESCE(direction, ''), entry_price, exit_price,
-			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl,
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
 			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
-			created_at, updated_at
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
 		FROM positions WHERE status = 'open'
 		ORDER BY entry_time DESC
 	`)
@@ -115,10 +243,14 @@ func (r *PositionRepository) GetOpen() ([]*Position, error) {
 func (r *PositionRepository) GetClosed() ([]*Position, error) {
 	rows, err := r.db.Query(`
 		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
-			COALESCE(strike, 0), COALESCE(direction, ''), entry_price, exit_price,
-			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl,
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
 			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
-			created_at, updated_at
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
 		FROM positions WHERE status = 'closed'
 		ORDER BY exit_time DESC
 	`)
@@ -130,14 +262,53 @@ func (r *PositionRepository) GetClosed() ([]*Position, error) {
 	return r.scanPositions(rows)
 }
 
+// GetClosedPaged retrieves a page of up to limit closed positions, most
+// recently closed first, after skipping offset of them, and how many closed
+// positions there are in all.
+func (r *PositionRepository) GetClosedPaged(limit, offset int) ([]*Position, int, error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM positions WHERE status = 'closed'`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count closed positions: %w", err)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
+			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
+		FROM positions WHERE status = 'closed'
+		ORDER BY exit_time DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get closed positions: %w", err)
+	}
+	defer rows.Close()
+
+	positions, err := r.scanPositions(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return positions, total, nil
+}
+
 // GetOpenByPlatform retrieves all open positions for a specific platform.
 func (r *PositionRepository) GetOpenByPlatform(platform string) ([]*Position, error) {
 	rows, err := r.db.Query(`
 		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
-			COALESCE(strike, 0), COALESCE(direction, ''), entry_price, exit_price,
-			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl,
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
 			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
-			created_at, updated_at
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
 		FROM positions WHERE status = 'open' AND platform = ?
 		ORDER BY entry_time DESC
 	`, platform)
@@ -149,23 +320,78 @@ func (r *PositionRepository) GetOpenByPlatform(platform string) ([]*Position, er
 	return r.scanPositions(rows)
 }
 
-// GetByMarket retrieves an open position by platform and market ID.
+// GetActiveByPlatform retrieves every position not yet closed on a
+// platform, whatever its status.
+func (r *PositionRepository) GetActiveByPlatform(platform string) ([]*Position, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
+			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
+		FROM positions WHERE status != 'closed' AND platform = ?
+		ORDER BY entry_time DESC
+	`, platform)
+	if err != nil {
+		return nil, fmt.Errorf("get active positions by platform: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanPositions(rows)
+}
+
+// GetByStatus retrieves all positions in the given status.
+func (r *PositionRepository) GetByStatus(status string) ([]*Position, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
+			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
+		FROM positions WHERE status = ?
+		ORDER BY entry_time DESC
+	`, status)
+	if err != nil {
+		return nil, fmt.Errorf("get positions by status: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanPositions(rows)
+}
+
+// GetByMarket retrieves the active (not closed) position by platform and market ID.
 func (r *PositionRepository) GetByMarket(platform, marketID string) (*Position, error) {
 	pos := &Position{}
 	err := r.db.QueryRow(`
 		SELECT id, platform, market_id, COALESCE(market_title, ''), COALESCE(asset, ''),
-			COALESCE(strike, 0), COALESCE(direction, ''), entry_price, exit_price,
-			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl,
+			COALESCE(strike, 0), COALESCE(direction, ''), entry_price_micros, exit_price_micros,
+			quantity, side, status, entry_time, exit_time, exit_reason, realized_pnl_micros,
 			COALESCE(safety_margin_at_entry, 0), COALESCE(volatility_at_entry, 0),
-			created_at, updated_at
-		FROM positions WHERE platform = ? AND market_id = ? AND status = 'open'
+			created_at, updated_at, version, COALESCE(token_id, ''), fees_micros,
+			market_close_time, COALESCE(peak_price_micros, entry_price_micros), take_profit_percent,
+			COALESCE(entry_strategy, ''), COALESCE(outcome, ''), COALESCE(strike_upper, 0),
+			COALESCE(trade_strategy, ''), COALESCE(strategy, ''), COALESCE(strategy_version, 0),
+			COALESCE(experiment, ''), COALESCE(experiment_arm, '')
+		FROM positions WHERE platform = ? AND market_id = ? AND status != 'closed'
+		ORDER BY id DESC LIMIT 1
 	`, platform, marketID).Scan(
 		&pos.ID, &pos.Platform, &pos.MarketID, &pos.MarketTitle, &pos.Asset,
-		&pos.Strike, &pos.Direction, &pos.EntryPrice, &pos.ExitPrice,
+		&pos.Strike, &pos.Direction, dollars(&pos.EntryPrice), nullDollars(&pos.ExitPrice),
 		&pos.Quantity, &pos.Side, &pos.Status, &pos.EntryTime, &pos.ExitTime,
-		&pos.ExitReason, &pos.RealizedPnL,
+		&pos.ExitReason, nullDollars(&pos.RealizedPnL),
 		&pos.SafetyMarginAtEntry, &pos.VolatilityAtEntry,
-		&pos.CreatedAt, &pos.UpdatedAt,
+		&pos.CreatedAt, &pos.UpdatedAt, &pos.Version, &pos.TokenID, dollars(&pos.Fees),
+		&pos.MarketCloseTime, dollars(&pos.PeakPrice), &pos.TakeProfitPercent, &pos.EntryStrategy,
+		&pos.Outcome, &pos.StrikeUpper, &pos.TradeStrategy, &pos.Strategy, &pos.StrategyVersion,
+		&pos.Experiment, &pos.ExperimentArm,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -176,57 +402,328 @@ func (r *PositionRepository) GetByMarket(platform, marketID string) (*Position,
 	return pos, nil
 }
 
-// Update updates an existing position.
+// Update updates an existing position if its version still matches
+// pos.Version, then increments pos.Version. Status is not written; use
+// Transition or Close to change it. Returns a *ConflictError if the position
+// was modified since it was read.
 func (r *PositionRepository) Update(pos *Position) error {
-	_, err := r.db.Exec(`
+	return updatePosition(r.db, pos)
+}
+
+// updatePosition writes a position with q, conditioned on its version.
+func updatePosition(q querier, pos *Position) error {
+	result, err := q.Exec(`
 		UPDATE positions SET
 			market_title = ?,
 			asset = ?,
 			strike = ?,
 			direction = ?,
-			entry_price = ?,
-			exit_price = ?,
+			entry_price_micros = ?,
+			exit_price_micros = ?,
 			quantity = ?,
 			side = ?,
-			status = ?,
 			exit_time = ?,
 			exit_reason = ?,
-			realized_pnl = ?,
+			realized_pnl_micros = ?,
+			fees_micros = ?,
 			safety_margin_at_entry = ?,
 			volatility_at_entry = ?,
+			take_profit_percent = ?,
+			entry_strategy = ?,
+			outcome = ?,
+			strike_upper = ?,
+			trade_strategy = ?,
+			version = version + 1,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
+		WHERE id = ? AND version = ?
 	`,
 		pos.MarketTitle, pos.Asset, pos.Strike, pos.Direction,
-		pos.EntryPrice, pos.ExitPrice, pos.Quantity, pos.Side, pos.Status,
-		pos.ExitTime, pos.ExitReason, pos.RealizedPnL,
-		pos.SafetyMarginAtEntry, pos.VolatilityAtEntry,
-		pos.ID,
+		types.Dollars(pos.EntryPrice), nullMicros(pos.ExitPrice), pos.Quantity, pos.Side,
+		pos.ExitTime, pos.ExitReason, nullMicros(pos.RealizedPnL), types.Dollars(pos.Fees),
+		pos.SafetyMarginAtEntry, pos.VolatilityAtEntry, pos.TakeProfitPercent,
+		pos.EntryStrategy, pos.Outcome, pos.StrikeUpper, pos.TradeStrategy,
+		pos.ID, pos.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("update position: %w", err)
 	}
+
+	if err := checkSwapped(q, result, pos.ID, pos.Version); err != nil {
+		return err
+	}
+	pos.Version++
 	return nil
 }
 
-// Close marks a position as closed with exit details.
-func (r *PositionRepository) Close(id int64, exitPrice float64, reason string, pnl float64) error {
+// UpdatePeakPrice raises an open position's peak price to price if it is
+// higher. The peak only ever rises, so it is written without a version check
+// and doesn't conflict with concurrent transitions.
+func (r *PositionRepository) UpdatePeakPrice(id int64, price float64) error {
 	_, err := r.db.Exec(`
+		UPDATE positions SET peak_price_micros = ?
+		WHERE id = ? AND status = 'open' AND COALESCE(peak_price_micros, entry_price_micros) < ?
+	`, types.Dollars(price), id, types.Dollars(price))
+	if err != nil {
+		return fmt.Errorf("update peak price: %w", err)
+	}
+	return nil
+}
+
+// Transition moves a position to a new status. The update only applies if
+// the position still has pos.Status and pos.Version, so two callers racing on
+// the same position cannot both transition it. On success pos.Status and
+// pos.Version are updated. Returns ErrInvalidTransition if the state machine
+// forbids the change and a *ConflictError if the position was modified since
+// it was read.
+func (r *PositionRepository) Transition(pos *Position, to string) error {
+	return transitionPosition(r.db, pos, to)
+}
+
+// transitionPosition moves a position to a new status with q, conditioned
+// on its status and version.
+func transitionPosition(q querier, pos *Position, to string) error {
+	if !CanTransition(pos.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, pos.Status, to)
+	}
+
+	result, err := q.Exec(`
+		UPDATE positions SET
+			status = ?,
+			version = version + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ? AND version = ?
+	`, to, pos.ID, pos.Status, pos.Version)
+	if err != nil {
+		return fmt.Errorf("transition position: %w", err)
+	}
+
+	if err := checkSwapped(q, result, pos.ID, pos.Version); err != nil {
+		return err
+	}
+	pos.Status = to
+	pos.Version++
+	return nil
+}
+
+// Close marks a position as closed with exit details. The position must be
+// in a status from which closing is allowed; otherwise a *ConflictError is
+// returned (for example, when another writer already closed it).
+func (r *PositionRepository) Close(id int64, exitPrice float64, reason string, pnl types.Money) error {
+	return closePosition(r.db, id, exitPrice, reason, pnl)
+}
+
+// closePosition closes a position with q.
+func closePosition(q querier, id int64, exitPrice float64, reason string, pnl types.Money) error {
+	from := statusesTransitioningTo(PositionStatusClosed)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(from)), ",")
+
+	args := []interface{}{types.Dollars(exitPrice), reason, pnl, id}
+	for _, status := range from {
+		args = append(args, status)
+	}
+
+	result, err := q.Exec(`
 		UPDATE positions SET
 			status = 'closed',
-			exit_price = ?,
+			exit_price_micros = ?,
 			exit_time = CURRENT_TIMESTAMP,
 			exit_reason = ?,
-			realized_pnl = ?,
+			realized_pnl_micros = ?,
+			version = version + 1,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`, exitPrice, reason, pnl, id)
+		WHERE id = ? AND status IN (`+placeholders+`)
+	`, args...)
 	if err != nil {
 		return fmt.Errorf("close position: %w", err)
 	}
+
+	return checkSwapped(q, result, id, 0)
+}
+
+// OpenEntry opens a position, debits cost from its platform's bankroll and
+// records event (if not nil) in one transaction, so a crash can't leave a
+// position open without its cost debited or debited without it open. A
+// position not yet created (ID 0) is inserted open; a pending entry has its
+// fill written and moves to open. The debit is keyed by
+// PositionKey(id, BankrollOpEntry), and the event's position is set to the
+// one opened. On failure nothing is written and pos is left unchanged.
+func (r *PositionRepository) OpenEntry(pos *Position, cost types.Money, event *Event) error {
+	return r.inTrade(pos, func(tx *sql.Tx) error {
+		if pos.ID == 0 {
+			pos.Status = PositionStatusOpen
+			if _, err := createPosition(tx, pos); err != nil {
+				return err
+			}
+		} else {
+			if err := updatePosition(tx, pos); err != nil {
+				return fmt.Errorf("record entry fill: %w", err)
+			}
+			if err := transitionPosition(tx, pos, PositionStatusOpen); err != nil {
+				return err
+			}
+		}
+		key := PositionKey(pos.ID, BankrollOpEntry)
+		if _, err := applyToBalance(tx, pos.Platform, -cost, &key); err != nil {
+			return fmt.Errorf("deduct from bankroll: %w", err)
+		}
+		return recordTradeEvent(tx, event, pos.ID)
+	})
+}
+
+// CloseExit closes a position claimed for exit, credits proceeds to its
+// platform's bankroll under PositionKey(id, BankrollOpExit) and records
+// event (if not nil) in one transaction. Changes to pos since it was read,
+// such as the exit fee, are written first. On failure nothing is written and
+// pos is left unchanged.
+func (r *PositionRepository) CloseExit(pos *Position, exitPrice float64, reason string, pnl, proceeds types.Money, event *Event) error {
+	return r.inTrade(pos, func(tx *sql.Tx) error {
+		if err := updatePosition(tx, pos); err != nil {
+			return fmt.Errorf("record exit: %w", err)
+		}
+		if err := closePosition(tx, pos.ID, exitPrice, reason, pnl); err != nil {
+			return err
+		}
+		pos.Status = PositionStatusClosed
+		pos.Version++
+
+		key := PositionKey(pos.ID, BankrollOpExit)
+		if _, err := applyToBalance(tx, pos.Platform, proceeds, &key); err != nil {
+			return fmt.Errorf("add to bankroll: %w", err)
+		}
+		return recordTradeEvent(tx, event, pos.ID)
+	})
+}
+
+// RecordPartialExit writes a position claimed for exit after part of it was
+// sold, credits the proceeds under key, returns the position to open and
+// records event (if not nil) in one transaction. On failure nothing is
+// written and pos is left unchanged.
+func (r *PositionRepository) RecordPartialExit(pos *Position, proceeds types.Money, key string, event *Event) error {
+	return r.inTrade(pos, func(tx *sql.Tx) error {
+		if err := updatePosition(tx, pos); err != nil {
+			return fmt.Errorf("record partial exit: %w", err)
+		}
+		if _, err := applyToBalance(tx, pos.Platform, proceeds, &key); err != nil {
+			return fmt.Errorf("add to bankroll: %w", err)
+		}
+		if err := transitionPosition(tx, pos, PositionStatusOpen); err != nil {
+			return err
+		}
+		return recordTradeEvent(tx, event, pos.ID)
+	})
+}
+
+// inTrade runs write in a transaction, restoring pos if it fails.
+func (r *PositionRepository) inTrade(pos *Position, write func(tx *sql.Tx) error) error {
+	saved := *pos
+	err := func() error {
+		tx, err := r.db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		if err := write(tx); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit transaction: %w", err)
+		}
+		return nil
+	}()
+	if err != nil {
+		*pos = saved
+	}
+	return err
+}
+
+// recordTradeEvent records an entry or exit event within tx, for the
+// position it concerns unless one is set. A nil event records nothing.
+func recordTradeEvent(tx *sql.Tx, event *Event, positionID int64) error {
+	if event == nil {
+		return nil
+	}
+	if event.PositionID == nil {
+		event.PositionID = &positionID
+	}
+	if _, err := insertEvent(tx, event); err != nil {
+		return err
+	}
 	return nil
 }
 
+// StrategyCapital is what a trade strategy's positions on a platform have
+// won or lost and still hold of its bankroll.
+type StrategyCapital struct {
+	// RealizedPnL is the profit or loss realized so far, net of fees on
+	// closed positions.
+	RealizedPnL types.Money
+	// Committed is the cost plus fees of the positions not yet closed.
+	Committed types.Money
+}
+
+// GetStrategyCapital sums the capital of a platform's positions entered on
+// a trade strategy (empty for positions that follow the market).
+func (r *PositionRepository) GetStrategyCapital(platform, strategy string) (StrategyCapital, error) {
+	var c StrategyCapital
+	err := r.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(COALESCE(realized_pnl_micros, 0)), 0),
+			COALESCE(SUM(CASE WHEN status != 'closed' THEN CAST(ROUND(entry_price_micros * quantity) AS INTEGER) + fees_micros ELSE 0 END), 0)
+		FROM positions
+		WHERE platform = ? AND COALESCE(trade_strategy, '') = ?
+	`, platform, strategy).Scan(&c.RealizedPnL, &c.Committed)
+	if err != nil {
+		return c, fmt.Errorf("get strategy capital: %w", err)
+	}
+	return c, nil
+}
+
+// GetUncreditedExits returns the IDs of closed positions whose entry was
+// debited from the bankroll but whose exit proceeds were never credited,
+// which closing and crediting in one transaction (see CloseExit) prevents.
+// Entries that never filled are closed without a debit and aren't returned.
+func (r *PositionRepository) GetUncreditedExits() ([]int64, error) {
+	// Keys as built by PositionKey
+	ids, err := queryIDs(r.db, `
+		SELECT p.id FROM positions p
+		WHERE p.status = ?
+			AND EXISTS (SELECT 1 FROM bankroll_ledger l WHERE l.idempotency_key = 'position:' || p.id || ':' || ?)
+			AND NOT EXISTS (SELECT 1 FROM bankroll_ledger l WHERE l.idempotency_key = 'position:' || p.id || ':' || ?)
+		ORDER BY p.id
+	`, PositionStatusClosed, BankrollOpEntry, BankrollOpExit)
+	if err != nil {
+		return nil, fmt.Errorf("get uncredited exits: %w", err)
+	}
+	return ids, nil
+}
+
+// checkSwapped returns a *ConflictError if a conditional update made with q
+// matched no rows.
+func checkSwapped(q querier, result sql.Result, id int64, expectedVersion int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	current, err := getPosition(q, id)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("position not found: %d", id)
+	}
+	return &ConflictError{
+		PositionID:      id,
+		ExpectedVersion: expectedVersion,
+		ActualVersion:   current.Version,
+		ActualStatus:    current.Status,
+	}
+}
+
 // scanPositions scans multiple positions from rows.
 func (r *PositionRepository) scanPositions(rows *sql.Rows) ([]*Position, error) {
 	var positions []*Position
@@ -234,11 +731,14 @@ func (r *PositionRepository) scanPositions(rows *sql.Rows) ([]*Position, error)
 		pos := &Position{}
 		err := rows.Scan(
 			&pos.ID, &pos.Platform, &pos.MarketID, &pos.MarketTitle, &pos.Asset,
-			&pos.Strike, &pos.Direction, &pos.EntryPrice, &pos.ExitPrice,
+			&pos.Strike, &pos.Direction, dollars(&pos.EntryPrice), nullDollars(&pos.ExitPrice),
 			&pos.Quantity, &pos.Side, &pos.Status, &pos.EntryTime, &pos.ExitTime,
-			&pos.ExitReason, &pos.RealizedPnL,
+			&pos.ExitReason, nullDollars(&pos.RealizedPnL),
 			&pos.SafetyMarginAtEntry, &pos.VolatilityAtEntry,
-			&pos.CreatedAt, &pos.UpdatedAt,
+			&pos.CreatedAt, &pos.UpdatedAt, &pos.Version, &pos.TokenID, dollars(&pos.Fees),
+			&pos.MarketCloseTime, dollars(&pos.PeakPrice), &pos.TakeProfitPercent, &pos.EntryStrategy,
+			&pos.Outcome, &pos.StrikeUpper, &pos.TradeStrategy, &pos.Strategy, &pos.StrategyVersion,
+			&pos.Experiment, &pos.ExperimentArm,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan position: %w", err)
@@ -1,8 +1,15 @@
 package persistence
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
 )
 
 func TestPositionRepository_Create(t *testing.T) {
@@ -54,6 +61,232 @@ func TestPositionRepository_Create(t *testing.T) {
 	}
 }
 
+func TestPositionRepository_MarketCloseTime(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	closeTime := time.Date(2026, 1, 2, 21, 0, 0, 0, time.UTC)
+	withClose, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "SPY-CLOSE", EntryPrice: 0.85, Quantity: 10.0,
+		Side: "YES", Status: "open", MarketCloseTime: &closeTime,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+	withoutClose, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "SPY-NOCLOSE", EntryPrice: 0.85, Quantity: 10.0,
+		Side: "YES", Status: "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	pos, err := repo.GetByID(withClose)
+	if err != nil {
+		t.Fatalf("failed to get position: %v", err)
+	}
+	if pos.MarketCloseTime == nil || !pos.MarketCloseTime.Equal(closeTime) {
+		t.Errorf("expected market close time %v, got %v", closeTime, pos.MarketCloseTime)
+	}
+
+	pos, err = repo.GetByID(withoutClose)
+	if err != nil {
+		t.Fatalf("failed to get position: %v", err)
+	}
+	if pos.MarketCloseTime != nil {
+		t.Errorf("expected nil market close time, got %v", pos.MarketCloseTime)
+	}
+}
+
+func TestPositionRepository_UpdatePeakPrice(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	id, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "BTC-PEAK", EntryPrice: 0.80, Quantity: 10.0,
+		Side: "YES", Status: "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// Test: Peak defaults to the entry price
+	pos, _ := repo.GetByID(id)
+	if pos.PeakPrice != 0.80 {
+		t.Errorf("expected peak to default to entry price 0.80, got %f", pos.PeakPrice)
+	}
+
+	// Test: Peak only rises
+	for _, price := range []float64{0.90, 0.85} {
+		if err := repo.UpdatePeakPrice(id, price); err != nil {
+			t.Fatalf("failed to update peak price: %v", err)
+		}
+	}
+	pos, _ = repo.GetByID(id)
+	if pos.PeakPrice != 0.90 {
+		t.Errorf("expected peak 0.90, got %f", pos.PeakPrice)
+	}
+	if pos.Version != 1 {
+		t.Errorf("expected version unchanged at 1, got %d", pos.Version)
+	}
+}
+
+func TestPositionRepository_TakeProfitOverride(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	id, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "BTC-TP", EntryPrice: 0.80, Quantity: 10.0,
+		Side: "YES", Status: "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	// Test: No override by default
+	pos, _ := repo.GetByID(id)
+	if pos.TakeProfitPercent != nil {
+		t.Errorf("expected no take profit override, got %f", *pos.TakeProfitPercent)
+	}
+
+	// Test: Override is saved on update
+	takeProfit := 0.12
+	pos.TakeProfitPercent = &takeProfit
+	if err := repo.Update(pos); err != nil {
+		t.Fatalf("failed to update position: %v", err)
+	}
+	pos, _ = repo.GetByID(id)
+	if pos.TakeProfitPercent == nil || *pos.TakeProfitPercent != 0.12 {
+		t.Errorf("expected take profit override 0.12, got %v", pos.TakeProfitPercent)
+	}
+}
+
+func TestPositionRepository_Outcome(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	id, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "KXBTC-B95", EntryPrice: 0.80, Quantity: 10.0,
+		Side: "YES", Status: "open", Asset: "BTC", Strike: 95000, StrikeUpper: 99999.99,
+		Direction: "between", Outcome: "$95000 to $99999.99",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	pos, _ := repo.GetByID(id)
+	if pos.Outcome != "$95000 to $99999.99" || pos.StrikeUpper != 99999.99 {
+		t.Errorf("expected bracket outcome to round trip, got %q %v", pos.Outcome, pos.StrikeUpper)
+	}
+
+	open, _ := repo.GetOpen()
+	if len(open) != 1 || open[0].Outcome != pos.Outcome {
+		t.Errorf("expected open position with its outcome, got %+v", open)
+	}
+}
+
+func TestPositionRepository_Strategy(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	id, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "KXBTC-B95", EntryPrice: 0.80, Quantity: 10.0,
+		Side: "YES", Status: "open", Strategy: "tight-stops", StrategyVersion: 3,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+	legacy, err := repo.Create(&Position{
+		Platform: "kalshi", MarketID: "KXBTC-B100", EntryPrice: 0.80, Quantity: 10.0,
+		Side: "YES", Status: "open",
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	pos, _ := repo.GetByID(id)
+	if pos.Strategy != "tight-stops" || pos.StrategyVersion != 3 {
+		t.Errorf("expected strategy to round trip, got %q v%d", pos.Strategy, pos.StrategyVersion)
+	}
+	pos, _ = repo.GetByID(legacy)
+	if pos.Strategy != "" || pos.StrategyVersion != 0 {
+		t.Errorf("expected no strategy, got %q v%d", pos.Strategy, pos.StrategyVersion)
+	}
+}
+
 func TestPositionRepository_GetOpen(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
 	if err != nil {
@@ -242,7 +475,7 @@ func TestPositionRepository_Close(t *testing.T) {
 	reason := "resolution_win"
 	pnl := 0.30 // (1.0 - 0.90) * 3.0
 
-	if err := repo.Close(id, exitPrice, reason, pnl); err != nil {
+	if err := repo.Close(id, exitPrice, reason, types.Dollars(pnl)); err != nil {
 		t.Fatalf("failed to close position: %v", err)
 	}
 
@@ -262,6 +495,50 @@ func TestPositionRepository_Close(t *testing.T) {
 	}
 }
 
+func TestPositionRepository_GetClosedPaged(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	// Five closed positions and one still open
+	for i := 1; i <= 5; i++ {
+		id, _ := repo.Create(&Position{Platform: "kalshi", MarketID: fmt.Sprintf("KX%d", i), EntryPrice: 0.90, Quantity: 1, Side: "YES", Status: "open"})
+		if err := repo.Close(id, 1.0, "resolved", types.Dollars(0.10)); err != nil {
+			t.Fatalf("failed to close position: %v", err)
+		}
+	}
+	repo.Create(&Position{Platform: "kalshi", MarketID: "KXOPEN", EntryPrice: 0.90, Quantity: 1, Side: "YES", Status: "open"})
+
+	page, total, err := repo.GetClosedPaged(2, 0)
+	if err != nil {
+		t.Fatalf("GetClosedPaged failed: %v", err)
+	}
+	if total != 5 || len(page) != 2 {
+		t.Fatalf("expected 2 of 5 closed positions, got %d of %d", len(page), total)
+	}
+	// Closed in the same second, the latest created comes first
+	if page[0].MarketID != "KX5" || page[1].MarketID != "KX4" {
+		t.Errorf("expected the most recently closed first, got %s, %s", page[0].MarketID, page[1].MarketID)
+	}
+
+	last, _, err := repo.GetClosedPaged(2, 4)
+	if err != nil {
+		t.Fatalf("GetClosedPaged failed: %v", err)
+	}
+	if len(last) != 1 || last[0].MarketID != "KX1" {
+		t.Errorf("expected the last page to hold the first closed, got %v", last)
+	}
+}
+
 func TestPositionRepository_GetOpenByPlatform(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
 	if err != nil {
@@ -298,3 +575,430 @@ func TestPositionRepository_GetOpenByPlatform(t *testing.T) {
 	}
 }
 
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{PositionStatusPendingEntry, PositionStatusOpen, true},
+		{PositionStatusOpen, PositionStatusExiting, true},
+		{PositionStatusExiting, PositionStatusClosed, true},
+		{PositionStatusExiting, PositionStatusOpen, true},
+		{PositionStatusError, PositionStatusReconciling, true},
+		{PositionStatusReconciling, PositionStatusOpen, true},
+		{PositionStatusClosed, PositionStatusOpen, false},
+		{PositionStatusError, PositionStatusClosed, false},
+		{PositionStatusPendingEntry, PositionStatusExiting, false},
+		{"unknown", PositionStatusOpen, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPositionRepository_Transition(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	pos := &Position{
+		Platform:   "polymarket",
+		MarketID:   "0xSTATE",
+		EntryPrice: 0.85,
+		Quantity:   4.0,
+		Side:       "YES",
+		Status:     PositionStatusOpen,
+	}
+	id, _ := repo.Create(pos)
+	stale, _ := repo.GetByID(id)
+
+	// Test: First claim succeeds
+	if err := repo.Transition(pos, PositionStatusExiting); err != nil {
+		t.Fatalf("failed to transition: %v", err)
+	}
+	if pos.Status != PositionStatusExiting || pos.Version != 2 {
+		t.Errorf("expected exiting at version 2, got %s at version %d", pos.Status, pos.Version)
+	}
+
+	// Test: Second claim from a stale read conflicts
+	err = repo.Transition(stale, PositionStatusExiting)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ConflictError for stale read, got %v", err)
+	}
+	if conflict.ActualStatus != PositionStatusExiting || conflict.ActualVersion != 2 {
+		t.Errorf("expected conflict to report exiting at version 2, got %s at version %d", conflict.ActualStatus, conflict.ActualVersion)
+	}
+
+	// Test: Transition not in the state machine fails
+	err = repo.Transition(pos, PositionStatusPendingEntry)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition for disallowed transition, got %v", err)
+	}
+
+	// Test: Exiting position is not returned as open
+	open, _ := repo.GetOpen()
+	if len(open) != 0 {
+		t.Errorf("expected no open positions, got %d", len(open))
+	}
+	exiting, _ := repo.GetByStatus(PositionStatusExiting)
+	if len(exiting) != 1 {
+		t.Errorf("expected 1 exiting position, got %d", len(exiting))
+	}
+
+	// Test: Exiting position still blocks duplicate entries
+	found, _ := repo.GetByMarket("polymarket", "0xSTATE")
+	if found == nil || found.ID != id {
+		t.Errorf("expected exiting position from GetByMarket, got %v", found)
+	}
+
+	// Test: Close is terminal
+	if err := repo.Close(id, 0.70, "stop_loss", types.Dollars(-0.6)); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+	if err := repo.Close(id, 0.70, "stop_loss", types.Dollars(-0.6)); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict closing twice, got %v", err)
+	}
+	if found, _ := repo.GetByMarket("polymarket", "0xSTATE"); found != nil {
+		t.Errorf("expected no active position after close, got %v", found)
+	}
+}
+
+func TestPositionRepository_UpdateVersionConflict(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+
+	id, _ := repo.Create(&Position{
+		Platform:   "kalshi",
+		MarketID:   "KXVERSION",
+		EntryPrice: 0.90,
+		Quantity:   3.0,
+		Side:       "YES",
+		Status:     PositionStatusOpen,
+	})
+
+	// Two writers read the same version
+	bot, _ := repo.GetByID(id)
+	cli, _ := repo.GetByID(id)
+	if bot.Version != 1 {
+		t.Fatalf("expected initial version 1, got %d", bot.Version)
+	}
+
+	// Test: First writer wins and bumps the version
+	bot.Quantity = 2.0
+	if err := repo.Update(bot); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if bot.Version != 2 {
+		t.Errorf("expected version 2 after update, got %d", bot.Version)
+	}
+
+	// Test: Second writer conflicts
+	cli.Quantity = 5.0
+	err = repo.Update(cli)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+	if conflict.ExpectedVersion != 1 || conflict.ActualVersion != 2 {
+		t.Errorf("expected conflict 1 vs 2, got %d vs %d", conflict.ExpectedVersion, conflict.ActualVersion)
+	}
+
+	stored, _ := repo.GetByID(id)
+	if stored.Quantity != 2.0 {
+		t.Errorf("expected first writer's quantity 2.0, got %f", stored.Quantity)
+	}
+}
+
+func TestPositionRepository_GetStrategyCapital(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_positions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPositionRepository(db)
+	create := func(marketID, platform, strategy string, price, quantity, fees float64) int64 {
+		id, err := repo.Create(&Position{
+			Platform:      platform,
+			MarketID:      marketID,
+			Asset:         "BTC",
+			EntryPrice:    price,
+			Quantity:      quantity,
+			Side:          "YES",
+			Status:        PositionStatusOpen,
+			Fees:          fees,
+			TradeStrategy: strategy,
+		})
+		if err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+		return id
+	}
+
+	create("open-fade", "kalshi", "fade", 0.20, 10, 0.10)
+	closed := create("closed-fade", "kalshi", "fade", 0.30, 10, 0)
+	if err := repo.Close(closed, 0, "stop_loss", types.Dollars(-3.0)); err != nil {
+		t.Fatalf("failed to close position: %v", err)
+	}
+	create("open-favorite", "kalshi", "", 0.90, 10, 0)
+	create("other-platform", "polymarket", "fade", 0.50, 10, 0)
+
+	fade, err := repo.GetStrategyCapital("kalshi", "fade")
+	if err != nil {
+		t.Fatalf("GetStrategyCapital failed: %v", err)
+	}
+	if fade.RealizedPnL != types.Dollars(-3.0) {
+		t.Errorf("expected realized PnL -3.00, got %s", fade.RealizedPnL)
+	}
+	if fade.Committed != types.Dollars(2.10) {
+		t.Errorf("expected 2.10 committed, got %s", fade.Committed)
+	}
+
+	favorite, err := repo.GetStrategyCapital("kalshi", "")
+	if err != nil {
+		t.Fatalf("GetStrategyCapital failed: %v", err)
+	}
+	if favorite.RealizedPnL != 0 || favorite.Committed != types.Dollars(9.0) {
+		t.Errorf("expected 9.00 committed to following the market, got %+v", favorite)
+	}
+}
+
+// openTradeTestDB opens a migrated in-memory database with a polymarket
+// bankroll of 100.
+func openTradeTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Each connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	if err := NewBankrollRepository(db).Initialize("polymarket", 100); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+	return db
+}
+
+func TestPositionRepository_OpenEntry(t *testing.T) {
+	db := openTradeTestDB(t)
+	repo := NewPositionRepository(db)
+	bankrolls := NewBankrollRepository(db)
+	events := NewEventRepository(db)
+
+	pos := &Position{Platform: "polymarket", MarketID: "0x1", EntryPrice: 0.9, Quantity: 10, Side: "YES",
+		Status: PositionStatusPendingEntry}
+	if err := repo.OpenEntry(pos, types.Dollars(9.05), &Event{Type: "entry", Platform: "polymarket"}); err != nil {
+		t.Fatalf("OpenEntry failed: %v", err)
+	}
+
+	stored, _ := repo.GetByID(pos.ID)
+	if stored == nil || stored.Status != PositionStatusOpen {
+		t.Fatalf("expected position inserted open, got %+v", stored)
+	}
+	bankroll, _ := bankrolls.Get("polymarket")
+	if bankroll.CurrentAmount != 90.95 {
+		t.Errorf("expected 90.95 after the debit, got %v", bankroll.CurrentAmount)
+	}
+	recorded, _ := events.Query(EventFilter{})
+	if len(recorded) != 1 || recorded[0].PositionID == nil || *recorded[0].PositionID != pos.ID {
+		t.Errorf("expected the entry event recorded for position %d, got %+v", pos.ID, recorded)
+	}
+
+	// A pending entry has its fill written and is opened
+	pending := &Position{Platform: "polymarket", MarketID: "0x2", EntryPrice: 0.9, Quantity: 10, Side: "YES",
+		Status: PositionStatusPendingEntry}
+	if _, err := repo.Create(pending); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+	pending.Quantity = 4
+	if err := repo.OpenEntry(pending, types.Dollars(3.6), nil); err != nil {
+		t.Fatalf("OpenEntry failed: %v", err)
+	}
+	stored, _ = repo.GetByID(pending.ID)
+	if stored.Status != PositionStatusOpen || stored.Quantity != 4 || stored.Version != pending.Version {
+		t.Errorf("expected the fill written and the position open, got %+v", stored)
+	}
+	if applied, _ := bankrolls.HasApplied(PositionKey(pending.ID, BankrollOpEntry)); !applied {
+		t.Error("expected the debit keyed by the position")
+	}
+}
+
+func TestPositionRepository_OpenEntryRollsBack(t *testing.T) {
+	db := openTradeTestDB(t)
+	repo := NewPositionRepository(db)
+
+	// No bankroll on the platform: the debit fails after the insert
+	pos := &Position{Platform: "unknown", MarketID: "0x1", EntryPrice: 0.9, Quantity: 10, Side: "YES",
+		Status: PositionStatusPendingEntry}
+	if err := repo.OpenEntry(pos, types.Dollars(9), &Event{Type: "entry"}); err == nil {
+		t.Fatal("expected error without a bankroll")
+	}
+	if pos.ID != 0 || pos.Status != PositionStatusPendingEntry {
+		t.Errorf("expected the position left unchanged, got %+v", pos)
+	}
+
+	var positions, events int
+	db.QueryRow("SELECT COUNT(*) FROM positions").Scan(&positions)
+	db.QueryRow("SELECT COUNT(*) FROM events").Scan(&events)
+	if positions != 0 || events != 0 {
+		t.Errorf("expected nothing written, got %d positions and %d events", positions, events)
+	}
+}
+
+func TestPositionRepository_CloseExit(t *testing.T) {
+	db := openTradeTestDB(t)
+	repo := NewPositionRepository(db)
+	bankrolls := NewBankrollRepository(db)
+
+	pos := &Position{Platform: "polymarket", MarketID: "0x1", EntryPrice: 0.9, Quantity: 10, Side: "YES",
+		Status: PositionStatusPendingEntry}
+	if err := repo.OpenEntry(pos, types.Dollars(9), nil); err != nil {
+		t.Fatalf("OpenEntry failed: %v", err)
+	}
+	if err := repo.Transition(pos, PositionStatusExiting); err != nil {
+		t.Fatalf("failed to claim position: %v", err)
+	}
+
+	// A concurrent close makes the exit conflict, and nothing is credited
+	stale := *pos
+	pos.Fees = 0.1
+	if err := repo.CloseExit(pos, 1.0, "market_resolved", types.Dollars(0.9), types.Dollars(9.9), &Event{Type: "exit"}); err != nil {
+		t.Fatalf("CloseExit failed: %v", err)
+	}
+	if err := repo.CloseExit(&stale, 1.0, "market_resolved", types.Dollars(1), types.Dollars(10), nil); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected a conflict closing twice, got %v", err)
+	}
+
+	stored, _ := repo.GetByID(pos.ID)
+	if stored.Status != PositionStatusClosed || stored.Fees != 0.1 || stored.RealizedPnL == nil || *stored.RealizedPnL != 0.9 {
+		t.Errorf("expected the position closed with its exit fee, got %+v", stored)
+	}
+	if pos.Status != PositionStatusClosed || pos.Version != stored.Version {
+		t.Errorf("expected the position's status and version updated, got %s v%d", pos.Status, pos.Version)
+	}
+	bankroll, _ := bankrolls.Get("polymarket")
+	if bankroll.CurrentAmount != 100.9 {
+		t.Errorf("expected 100.9 after the exit, got %v", bankroll.CurrentAmount)
+	}
+	if err := bankrolls.CheckLedger("polymarket"); err != nil {
+		t.Errorf("expected the ledger consistent: %v", err)
+	}
+}
+
+func TestPositionRepository_MigratesAmountsToMicros(t *testing.T) {
+	db, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	// Migrate up to the last schema with REAL amounts
+	dir := t.TempDir()
+	entries, err := os.ReadDir("../../migrations")
+	if err != nil {
+		t.Fatalf("failed to read migrations: %v", err)
+	}
+	copyMigration := func(name string) {
+		content, err := os.ReadFile(filepath.Join("../../migrations", name))
+		if err != nil {
+			t.Fatalf("failed to read migration: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatalf("failed to copy migration: %v", err)
+		}
+	}
+	for _, e := range entries {
+		if e.Name() < "038" {
+			copyMigration(e.Name())
+		}
+	}
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO positions (id, platform, market_id, entry_price, exit_price, quantity, side, status,
+			realized_pnl, fees, peak_price)
+		VALUES (1, 'kalshi', 'M1', 0.87, 0.93, 11.5, 'YES', 'closed', 0.62, 0.07, 0.95)
+	`); err != nil {
+		t.Fatalf("failed to insert position: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO costs (platform, kind, amount) VALUES ('polymarket', 'gas', 0.0123)`); err != nil {
+		t.Fatalf("failed to insert cost: %v", err)
+	}
+
+	copyMigration("038_money_micros.sql")
+	if err := RunMigrations(db, dir); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	pos, err := NewPositionRepository(db).GetByID(1)
+	if err != nil || pos == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if pos.EntryPrice != 0.87 || pos.ExitPrice == nil || *pos.ExitPrice != 0.93 || pos.Quantity != 11.5 ||
+		pos.RealizedPnL == nil || *pos.RealizedPnL != 0.62 || pos.Fees != 0.07 || pos.PeakPrice != 0.95 {
+		t.Errorf("expected amounts carried over, got %+v", pos)
+	}
+
+	summaries, err := NewCostRepository(db).Summarize()
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Total != types.Dollars(0.0123) {
+		t.Errorf("expected 0.0123 of gas carried over, got %+v", summaries)
+	}
+}
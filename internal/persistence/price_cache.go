@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CachedPrice is the last spot price fetched for a symbol.
+type CachedPrice struct {
+	Symbol    string
+	Price     float64
+	Source    string
+	FetchedAt time.Time
+}
+
+// PriceCacheRepository handles database operations for cached spot prices,
+// so a restart can reuse prices that are still fresh.
+type PriceCacheRepository struct {
+	db *sql.DB
+}
+
+// NewPriceCacheRepository creates a new PriceCacheRepository.
+func NewPriceCacheRepository(db *sql.DB) *PriceCacheRepository {
+	return &PriceCacheRepository{db: db}
+}
+
+// Save stores the last price fetched for a symbol, replacing the previous one.
+func (r *PriceCacheRepository) Save(p CachedPrice) error {
+	_, err := r.db.Exec(`
+		INSERT INTO price_cache (symbol, price, source, fetched_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (symbol) DO UPDATE SET
+			price = excluded.price,
+			source = excluded.source,
+			fetched_at = excluded.fetched_at
+	`, p.Symbol, p.Price, p.Source, p.FetchedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("save cached price %s: %w", p.Symbol, err)
+	}
+	return nil
+}
+
+// GetAll retrieves the cached price of every symbol.
+func (r *PriceCacheRepository) GetAll() ([]CachedPrice, error) {
+	rows, err := r.db.Query(`
+		SELECT symbol, price, source, fetched_at
+		FROM price_cache
+		ORDER BY symbol
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get cached prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []CachedPrice
+	for rows.Next() {
+		var p CachedPrice
+		if err := rows.Scan(&p.Symbol, &p.Price, &p.Source, &p.FetchedAt); err != nil {
+			return nil, fmt.Errorf("scan cached price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate cached prices: %w", err)
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPriceCacheRepository_SaveReplaces(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_price_cache_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPriceCacheRepository(db)
+	fetchedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, p := range []CachedPrice{
+		{Symbol: "BTC", Price: 99000, Source: "binance", FetchedAt: fetchedAt},
+		{Symbol: "ETH", Price: 3000, Source: "binance", FetchedAt: fetchedAt},
+		{Symbol: "BTC", Price: 100000, Source: "coinbase", FetchedAt: fetchedAt.Add(time.Minute)},
+	} {
+		if err := repo.Save(p); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	prices, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("expected one price per symbol, got %+v", prices)
+	}
+	btc := prices[0]
+	if btc.Symbol != "BTC" || btc.Price != 100000 || btc.Source != "coinbase" || !btc.FetchedAt.Equal(fetchedAt.Add(time.Minute)) {
+		t.Errorf("expected the latest BTC price, got %+v", btc)
+	}
+}
@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// PriceHistoryRepository handles database operations for stored price
+// history, which accumulates beyond what data sources return per request.
+type PriceHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewPriceHistoryRepository creates a new PriceHistoryRepository.
+func NewPriceHistoryRepository(db *sql.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// Save stores prices under a symbol. Prices already stored for the same
+// timestamp are kept.
+func (r *PriceHistoryRepository) Save(symbol string, prices []types.Price) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO price_history (symbol, price, timestamp, source)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (symbol, timestamp) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare price history insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, p := range prices {
+		if _, err := stmt.Exec(symbol, p.Price, p.Timestamp.UTC(), p.Source); err != nil {
+			return fmt.Errorf("save price history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit price history: %w", err)
+	}
+	return nil
+}
+
+// GetSince retrieves the prices stored for a symbol at or after since,
+// oldest first.
+func (r *PriceHistoryRepository) GetSince(symbol string, since time.Time) ([]types.Price, error) {
+	rows, err := r.db.Query(`
+		SELECT price, timestamp, source
+		FROM price_history
+		WHERE symbol = ? AND timestamp >= ?
+		ORDER BY timestamp
+	`, symbol, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("get price history: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []types.Price
+	for rows.Next() {
+		p := types.Price{Symbol: symbol}
+		if err := rows.Scan(&p.Price, &p.Timestamp, &p.Source); err != nil {
+			return nil, fmt.Errorf("scan price history: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate price history: %w", err)
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestPriceHistoryRepository_SaveAndGetSince(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_price_history_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewPriceHistoryRepository(db)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Test: Overlapping saves keep one price per timestamp
+	first := []types.Price{
+		{Price: 100, Timestamp: base, Source: "binance"},
+		{Price: 101, Timestamp: base.Add(time.Hour), Source: "binance"},
+	}
+	second := []types.Price{
+		{Price: 999, Timestamp: base.Add(time.Hour), Source: "coinbase"},
+		{Price: 102, Timestamp: base.Add(2 * time.Hour), Source: "coinbase"},
+	}
+	if err := repo.Save("BTC", first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := repo.Save("BTC", second); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	prices, err := repo.GetSince("BTC", base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetSince failed: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("expected 2 prices since the first hour, got %d", len(prices))
+	}
+	if prices[0].Price != 101 || prices[1].Price != 102 {
+		t.Errorf("expected prices [101 102] oldest first, got [%v %v]", prices[0].Price, prices[1].Price)
+	}
+	if !prices[1].Timestamp.Equal(base.Add(2*time.Hour)) || prices[1].Symbol != "BTC" {
+		t.Errorf("unexpected price %+v", prices[1])
+	}
+}
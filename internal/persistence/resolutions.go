@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MarketResolution records the winning side of a resolved market.
+type MarketResolution struct {
+	ID         int64
+	Platform   string
+	MarketID   string
+	Outcome    string
+	ResolvedAt time.Time
+	Audit      *ResolutionAudit // Nil if the resolution has not been audited
+}
+
+// ResolutionAudit compares a market's resolution with the outcome the
+// bot's price provider implies.
+type ResolutionAudit struct {
+	// SettlementValue is the underlying's price at market close according
+	// to the price provider.
+	SettlementValue float64
+	// Source is the provider the settlement value came from, such as
+	// "binance".
+	Source string
+	// ExpectedOutcome is the side ("YES" or "NO") the settlement value
+	// implies.
+	ExpectedOutcome string
+	// Matches reports whether the platform resolved to ExpectedOutcome.
+	Matches bool
+}
+
+// ResolutionRepository handles database operations for market resolutions.
+type ResolutionRepository struct {
+	db *sql.DB
+}
+
+// NewResolutionRepository creates a new ResolutionRepository.
+func NewResolutionRepository(db *sql.DB) *ResolutionRepository {
+	return &ResolutionRepository{db: db}
+}
+
+// Record stores the outcome of a market. A market resolves once, so
+// recording it again is a no-op.
+func (r *ResolutionRepository) Record(platform, marketID, outcome string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO market_resolutions (platform, market_id, outcome)
+		VALUES (?, ?, ?)
+		ON CONFLICT (platform, market_id) DO NOTHING
+	`, platform, marketID, outcome)
+	if err != nil {
+		return fmt.Errorf("record market resolution: %w", err)
+	}
+	return nil
+}
+
+// RecordAudit stores the audit of a recorded resolution, replacing any
+// earlier audit of it.
+func (r *ResolutionRepository) RecordAudit(platform, marketID string, audit ResolutionAudit) error {
+	result, err := r.db.Exec(`
+		UPDATE market_resolutions
+		SET settlement_value = ?, settlement_source = ?, expected_outcome = ?, outcome_matches = ?
+		WHERE platform = ? AND market_id = ?
+	`, audit.SettlementValue, audit.Source, audit.ExpectedOutcome, audit.Matches, platform, marketID)
+	if err != nil {
+		return fmt.Errorf("record resolution audit: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("record resolution audit: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("record resolution audit: no resolution for %s market %s", platform, marketID)
+	}
+	return nil
+}
+
+// Get retrieves the resolution of a market. Returns nil if the market has
+// not been recorded as resolved.
+func (r *ResolutionRepository) Get(platform, marketID string) (*MarketResolution, error) {
+	res, err := scanResolution(r.db.QueryRow(`
+		SELECT `+resolutionColumns+`
+		FROM market_resolutions WHERE platform = ? AND market_id = ?
+	`, platform, marketID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get market resolution: %w", err)
+	}
+	return res, nil
+}
+
+// GetMismatches retrieves the audited resolutions that disagree with the
+// price provider, most recent first.
+func (r *ResolutionRepository) GetMismatches() ([]*MarketResolution, error) {
+	rows, err := r.db.Query(`
+		SELECT ` + resolutionColumns + `
+		FROM market_resolutions
+		WHERE outcome_matches = 0
+		ORDER BY resolved_at DESC, id DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("get resolution mismatches: %w", err)
+	}
+	defer rows.Close()
+
+	var resolutions []*MarketResolution
+	for rows.Next() {
+		res, err := scanResolution(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan market resolution: %w", err)
+		}
+		resolutions = append(resolutions, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate market resolutions: %w", err)
+	}
+	return resolutions, nil
+}
+
+// resolutionColumns lists the market_resolutions columns scanResolution reads.
+const resolutionColumns = `id, platform, market_id, outcome, resolved_at,
+		settlement_value, settlement_source, expected_outcome, outcome_matches`
+
+// scanResolution reads a market resolution from a row of resolutionColumns.
+func scanResolution(row interface{ Scan(...any) error }) (*MarketResolution, error) {
+	res := &MarketResolution{}
+	var value sql.NullFloat64
+	var source, expected sql.NullString
+	var matches sql.NullBool
+	if err := row.Scan(&res.ID, &res.Platform, &res.MarketID, &res.Outcome, &res.ResolvedAt,
+		&value, &source, &expected, &matches); err != nil {
+		return nil, err
+	}
+
+	if matches.Valid {
+		res.Audit = &ResolutionAudit{
+			SettlementValue: value.Float64,
+			Source:          source.String,
+			ExpectedOutcome: expected.String,
+			Matches:         matches.Bool,
+		}
+	}
+	return res, nil
+}
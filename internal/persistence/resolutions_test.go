@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+)
+
+func TestResolutionRepository_RecordAudit(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_resolutions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewResolutionRepository(db)
+
+	if err := repo.Record("kalshi", "KXBTC-1", "YES"); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := repo.Record("kalshi", "KXBTC-2", "NO"); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	res, err := repo.Get("kalshi", "KXBTC-1")
+	if err != nil || res == nil || res.Audit != nil {
+		t.Fatalf("expected unaudited resolution, got %+v (%v)", res, err)
+	}
+
+	if err := repo.RecordAudit("kalshi", "KXBTC-1", ResolutionAudit{SettlementValue: 100400, Source: "binance", ExpectedOutcome: "YES", Matches: true}); err != nil {
+		t.Fatalf("RecordAudit failed: %v", err)
+	}
+	mismatch := ResolutionAudit{SettlementValue: 99950, Source: "coinbase", ExpectedOutcome: "YES", Matches: false}
+	if err := repo.RecordAudit("kalshi", "KXBTC-2", mismatch); err != nil {
+		t.Fatalf("RecordAudit failed: %v", err)
+	}
+
+	res, err = repo.Get("kalshi", "KXBTC-1")
+	if err != nil || res.Audit == nil || !res.Audit.Matches || res.Audit.SettlementValue != 100400 {
+		t.Errorf("expected matching audit, got %+v (%v)", res, err)
+	}
+
+	mismatches, err := repo.GetMismatches()
+	if err != nil {
+		t.Fatalf("GetMismatches failed: %v", err)
+	}
+	if len(mismatches) != 1 || mismatches[0].MarketID != "KXBTC-2" || *mismatches[0].Audit != mismatch {
+		t.Errorf("expected KXBTC-2 as the only mismatch, got %+v", mismatches)
+	}
+
+	if err := repo.RecordAudit("kalshi", "KXBTC-3", mismatch); err == nil {
+		t.Error("expected error auditing an unrecorded resolution")
+	}
+}
@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RollingVolatility is an asset's annualized realized volatility over one
+// horizon, as of a day's close.
+type RollingVolatility struct {
+	Asset      string
+	AsOf       time.Time
+	Horizon    time.Duration
+	Volatility float64
+}
+
+// RollingVolatilityRepository handles database operations for precomputed
+// rolling volatilities. Assets are stored as upper-case symbols (BTC, SOL).
+type RollingVolatilityRepository struct {
+	db *sql.DB
+}
+
+// NewRollingVolatilityRepository creates a new RollingVolatilityRepository.
+func NewRollingVolatilityRepository(db *sql.DB) *RollingVolatilityRepository {
+	return &RollingVolatilityRepository{db: db}
+}
+
+// Save stores rolling volatilities, replacing any already stored for the
+// same asset, day and horizon.
+func (r *RollingVolatilityRepository) Save(volatilities []RollingVolatility) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO rolling_volatility (asset, as_of, horizon_hours, volatility)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (asset, as_of, horizon_hours) DO UPDATE SET
+			volatility = excluded.volatility
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare rolling volatility insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, v := range volatilities {
+		_, err := stmt.Exec(strings.ToUpper(v.Asset), v.AsOf.UTC(), int64(v.Horizon.Hours()), v.Volatility)
+		if err != nil {
+			return fmt.Errorf("save rolling volatility: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit rolling volatility: %w", err)
+	}
+	return nil
+}
+
+// GetSince retrieves the rolling volatilities stored for an asset as of
+// since or later, oldest first and shortest horizon first.
+func (r *RollingVolatilityRepository) GetSince(asset string, since time.Time) ([]RollingVolatility, error) {
+	rows, err := r.db.Query(`
+		SELECT asset, as_of, horizon_hours, volatility
+		FROM rolling_volatility
+		WHERE asset = ? AND as_of >= ?
+		ORDER BY as_of, horizon_hours
+	`, strings.ToUpper(asset), since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("get rolling volatility: %w", err)
+	}
+	defer rows.Close()
+
+	var volatilities []RollingVolatility
+	for rows.Next() {
+		var v RollingVolatility
+		var hours int64
+		if err := rows.Scan(&v.Asset, &v.AsOf, &hours, &v.Volatility); err != nil {
+			return nil, fmt.Errorf("scan rolling volatility: %w", err)
+		}
+		v.Horizon = time.Duration(hours) * time.Hour
+		volatilities = append(volatilities, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rolling volatility: %w", err)
+	}
+	return volatilities, nil
+}
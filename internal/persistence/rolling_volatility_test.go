@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRollingVolatilityRepository_SaveAndGetSince(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_rolling_volatility_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewRollingVolatilityRepository(db)
+	day1 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	week, month := 7*24*time.Hour, 30*24*time.Hour
+
+	err = repo.Save([]RollingVolatility{
+		{Asset: "btc", AsOf: day1, Horizon: month, Volatility: 0.50},
+		{Asset: "btc", AsOf: day1, Horizon: week, Volatility: 0.45},
+		{Asset: "btc", AsOf: day2, Horizon: week, Volatility: 0.40},
+		{Asset: "eth", AsOf: day2, Horizon: week, Volatility: 0.70},
+	})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Test: Saving the same day and horizon again replaces the value
+	if err := repo.Save([]RollingVolatility{{Asset: "BTC", AsOf: day2, Horizon: week, Volatility: 0.42}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := repo.GetSince("BTC", day1)
+	if err != nil {
+		t.Fatalf("GetSince failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 BTC volatilities, got %+v", got)
+	}
+	if got[0].Horizon != week || got[1].Horizon != month || !got[0].AsOf.Equal(day1) {
+		t.Errorf("expected day 1 week then month, got %+v", got[:2])
+	}
+	if got[2].Volatility != 0.42 || !got[2].AsOf.Equal(day2) {
+		t.Errorf("expected day 2 replaced with 0.42, got %+v", got[2])
+	}
+
+	got, err = repo.GetSince("btc", day2)
+	if err != nil {
+		t.Fatalf("GetSince failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("expected 1 BTC volatility since day 2, got %+v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ScanDecisionEntered is the reason recorded for markets a position was
+// opened on.
+const ScanDecisionEntered = "entered"
+
+// ScanDecision records what the bot decided about a market it scanned.
+type ScanDecision struct {
+	ID          int64
+	Platform    string
+	MarketID    string
+	Outcome     string // Outcome of a multi-outcome market; empty for binary markets
+	MarketTitle string
+	Asset       string // Empty if the title could not be parsed
+	// Eligible is true if the market passed the scanner's eligibility
+	// criteria.
+	Eligible bool
+	// Reason is why the market was not entered: the comma-separated
+	// criteria it failed, the scanner's rejection or the position
+	// manager's skip reason. ScanDecisionEntered if it was entered.
+	Reason      string
+	YesPrice    float64
+	NoPrice     float64
+	Probability float64
+	BetSide     string
+	// SafetyMargin and Volatility are nil for markets that were not
+	// analyzed.
+	SafetyMargin *float64
+	Volatility   *float64
+	SeenCount    int
+	FirstSeen    time.Time
+	LastSeen     time.Time
+}
+
+// ScanDecisionRepository handles database operations for scan decisions.
+type ScanDecisionRepository struct {
+	db *sql.DB
+}
+
+// NewScanDecisionRepository creates a new ScanDecisionRepository.
+func NewScanDecisionRepository(db *sql.DB) *ScanDecisionRepository {
+	return &ScanDecisionRepository{db: db}
+}
+
+// RecordAll inserts decisions in one transaction, or updates the existing
+// row for the same market and reason with the latest prices and analysis.
+func (r *ScanDecisionRepository) RecordAll(decisions []*ScanDecision) error {
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO scan_decisions (
+			platform, market_id, outcome, market_title, asset, eligible, reason,
+			yes_price, no_price, probability, bet_side, safety_margin, volatility
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (platform, market_id, outcome, reason) DO UPDATE SET
+			market_title = excluded.market_title,
+			asset = excluded.asset,
+			eligible = excluded.eligible,
+			yes_price = excluded.yes_price,
+			no_price = excluded.no_price,
+			probability = excluded.probability,
+			bet_side = excluded.bet_side,
+			safety_margin = excluded.safety_margin,
+			volatility = excluded.volatility,
+			seen_count = seen_count + 1,
+			last_seen = CURRENT_TIMESTAMP
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare scan decision insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range decisions {
+		_, err := stmt.Exec(
+			d.Platform, d.MarketID, d.Outcome, d.MarketTitle, d.Asset, d.Eligible, d.Reason,
+			d.YesPrice, d.NoPrice, d.Probability, d.BetSide, d.SafetyMargin, d.Volatility,
+		)
+		if err != nil {
+			return fmt.Errorf("record scan decision for %s: %w", d.MarketID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit scan decisions: %w", err)
+	}
+	return nil
+}
+
+// GetSince retrieves scan decisions last seen at or after the given time.
+func (r *ScanDecisionRepository) GetSince(since time.Time) ([]*ScanDecision, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, market_id, outcome, COALESCE(market_title, ''), COALESCE(asset, ''),
+			eligible, reason, COALESCE(yes_price, 0), COALESCE(no_price, 0), COALESCE(probability, 0),
+			COALESCE(bet_side, ''), safety_margin, volatility, seen_count, first_seen, last_seen
+		FROM scan_decisions WHERE last_seen >= ?
+		ORDER BY reason, last_seen
+	`, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("get scan decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []*ScanDecision
+	for rows.Next() {
+		d := &ScanDecision{}
+		var safetyMargin, volatility sql.NullFloat64
+		err := rows.Scan(
+			&d.ID, &d.Platform, &d.MarketID, &d.Outcome, &d.MarketTitle, &d.Asset,
+			&d.Eligible, &d.Reason, &d.YesPrice, &d.NoPrice, &d.Probability,
+			&d.BetSide, &safetyMargin, &volatility, &d.SeenCount, &d.FirstSeen, &d.LastSeen,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("read scan decision: %w", err)
+		}
+		if safetyMargin.Valid {
+			d.SafetyMargin = &safetyMargin.Float64
+		}
+		if volatility.Valid {
+			d.Volatility = &volatility.Float64
+		}
+		decisions = append(decisions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scan decisions: %w", err)
+	}
+	return decisions, nil
+}
@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestScanDecisionRepository_RecordAllAndGetSince(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_scan_decisions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewScanDecisionRepository(db)
+	margin, vol := 1.8, 0.55
+
+	// Test: A scan's decisions are inserted together
+	err = repo.RecordAll([]*ScanDecision{
+		{
+			Platform: "kalshi", MarketID: "KXBTC-1", MarketTitle: "Bitcoin above $100k?", Asset: "BTC",
+			Eligible: false, Reason: "probability,liquidity", YesPrice: 0.70, NoPrice: 0.30, Probability: 0.70, BetSide: "YES",
+		},
+		{
+			Platform: "kalshi", MarketID: "KXBTC-2", MarketTitle: "Bitcoin above $90k?", Asset: "BTC",
+			Eligible: true, Reason: "volatility_risky", YesPrice: 0.85, NoPrice: 0.15, Probability: 0.85, BetSide: "YES",
+			SafetyMargin: &margin, Volatility: &vol,
+		},
+	})
+	if err != nil {
+		t.Fatalf("RecordAll failed: %v", err)
+	}
+
+	// Test: Seeing the same market and reason again updates its row
+	margin2 := 2.1
+	err = repo.RecordAll([]*ScanDecision{{
+		Platform: "kalshi", MarketID: "KXBTC-2", MarketTitle: "Bitcoin above $90k?", Asset: "BTC",
+		Eligible: true, Reason: "volatility_risky", YesPrice: 0.87, NoPrice: 0.13, Probability: 0.87, BetSide: "YES",
+		SafetyMargin: &margin2, Volatility: &vol,
+	}})
+	if err != nil {
+		t.Fatalf("RecordAll failed: %v", err)
+	}
+
+	decisions, err := repo.GetSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetSince failed: %v", err)
+	}
+	if len(decisions) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(decisions))
+	}
+
+	rejected, skipped := decisions[0], decisions[1]
+	if rejected.Reason != "probability,liquidity" || rejected.Eligible || rejected.SafetyMargin != nil || rejected.Volatility != nil {
+		t.Errorf("expected unanalyzed rejection, got %+v", rejected)
+	}
+	if !skipped.Eligible || skipped.SeenCount != 2 || skipped.YesPrice != 0.87 {
+		t.Errorf("expected eligible skip seen twice at latest price, got %+v", skipped)
+	}
+	if skipped.SafetyMargin == nil || *skipped.SafetyMargin != 2.1 || skipped.Volatility == nil || *skipped.Volatility != 0.55 {
+		t.Errorf("expected latest safety margin 2.1 and volatility 0.55, got %v and %v", skipped.SafetyMargin, skipped.Volatility)
+	}
+
+	// Test: Nothing to record is a no-op
+	if err := repo.RecordAll(nil); err != nil {
+		t.Errorf("RecordAll(nil) failed: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ScanSnapshotsKept is how many of each platform's latest scan snapshots
+// are kept: enough to diff the latest cycle against the one before.
+const ScanSnapshotsKept = 2
+
+// ScanSnapshot is what one scan cycle decided about each market it
+// evaluated on a platform.
+type ScanSnapshot struct {
+	ID        int64
+	Platform  string
+	ScannedAt time.Time
+	// Decisions hold the market, eligibility, reason and prices of each
+	// decision; their analysis, counts and timestamps are not kept.
+	Decisions []*ScanDecision
+}
+
+// ScanSnapshotRepository handles database operations for scan snapshots.
+type ScanSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewScanSnapshotRepository creates a new ScanSnapshotRepository.
+func NewScanSnapshotRepository(db *sql.DB) *ScanSnapshotRepository {
+	return &ScanSnapshotRepository{db: db}
+}
+
+// Record stores a platform's scan cycle decisions as its latest snapshot,
+// even if there are none, and drops all but the ScanSnapshotsKept latest.
+func (r *ScanSnapshotRepository) Record(platform string, decisions []*ScanDecision) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO scan_snapshots (platform) VALUES (?)`, platform)
+	if err != nil {
+		return fmt.Errorf("insert scan snapshot: %w", err)
+	}
+	snapshotID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get scan snapshot id: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO scan_snapshot_markets (
+			snapshot_id, market_id, outcome, market_title, asset, eligible, reason,
+			yes_price, no_price, probability, bet_side
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare scan snapshot insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range decisions {
+		_, err := stmt.Exec(
+			snapshotID, d.MarketID, d.Outcome, d.MarketTitle, d.Asset, d.Eligible, d.Reason,
+			d.YesPrice, d.NoPrice, d.Probability, d.BetSide,
+		)
+		if err != nil {
+			return fmt.Errorf("record scan snapshot market %s: %w", d.MarketID, err)
+		}
+	}
+
+	// Drop the platform's older snapshots
+	stale := `SELECT id FROM scan_snapshots WHERE platform = ? AND id NOT IN (
+		SELECT id FROM scan_snapshots WHERE platform = ? ORDER BY id DESC LIMIT ?)`
+	_, err = tx.Exec(`DELETE FROM scan_snapshot_markets WHERE snapshot_id IN (`+stale+`)`,
+		platform, platform, ScanSnapshotsKept)
+	if err != nil {
+		return fmt.Errorf("prune scan snapshot markets: %w", err)
+	}
+	_, err = tx.Exec(`DELETE FROM scan_snapshots WHERE id IN (`+stale+`)`,
+		platform, platform, ScanSnapshotsKept)
+	if err != nil {
+		return fmt.Errorf("prune scan snapshots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit scan snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetPlatforms returns the platforms with scan snapshots, in name order.
+func (r *ScanSnapshotRepository) GetPlatforms() ([]string, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT platform FROM scan_snapshots ORDER BY platform`)
+	if err != nil {
+		return nil, fmt.Errorf("get scan snapshot platforms: %w", err)
+	}
+	defer rows.Close()
+
+	var platforms []string
+	for rows.Next() {
+		var platform string
+		if err := rows.Scan(&platform); err != nil {
+			return nil, fmt.Errorf("read scan snapshot platform: %w", err)
+		}
+		platforms = append(platforms, platform)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scan snapshot platforms: %w", err)
+	}
+	return platforms, nil
+}
+
+// GetLatest retrieves a platform's latest limit snapshots with their
+// decisions, newest first.
+func (r *ScanSnapshotRepository) GetLatest(platform string, limit int) ([]*ScanSnapshot, error) {
+	rows, err := r.db.Query(`
+		SELECT id, platform, scanned_at FROM scan_snapshots
+		WHERE platform = ? ORDER BY id DESC LIMIT ?
+	`, platform, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get scan snapshots: %w", err)
+	}
+
+	var snapshots []*ScanSnapshot
+	for rows.Next() {
+		s := &ScanSnapshot{}
+		if err := rows.Scan(&s.ID, &s.Platform, &s.ScannedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("read scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate scan snapshots: %w", err)
+	}
+	rows.Close()
+
+	for _, s := range snapshots {
+		decisions, err := r.getDecisions(s)
+		if err != nil {
+			return nil, err
+		}
+		s.Decisions = decisions
+	}
+	return snapshots, nil
+}
+
+// getDecisions retrieves the decisions recorded in a snapshot.
+func (r *ScanSnapshotRepository) getDecisions(s *ScanSnapshot) ([]*ScanDecision, error) {
+	rows, err := r.db.Query(`
+		SELECT market_id, outcome, COALESCE(market_title, ''), COALESCE(asset, ''), eligible, reason,
+			COALESCE(yes_price, 0), COALESCE(no_price, 0), COALESCE(probability, 0), COALESCE(bet_side, '')
+		FROM scan_snapshot_markets WHERE snapshot_id = ?
+		ORDER BY market_id, outcome
+	`, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get scan snapshot markets: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []*ScanDecision
+	for rows.Next() {
+		d := &ScanDecision{Platform: s.Platform}
+		err := rows.Scan(
+			&d.MarketID, &d.Outcome, &d.MarketTitle, &d.Asset, &d.Eligible, &d.Reason,
+			&d.YesPrice, &d.NoPrice, &d.Probability, &d.BetSide,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("read scan snapshot market: %w", err)
+		}
+		decisions = append(decisions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate scan snapshot markets: %w", err)
+	}
+	return decisions, nil
+}
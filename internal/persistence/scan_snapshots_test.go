@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+)
+
+func TestScanSnapshotRepository_RecordAndGetLatest(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_scan_snapshots_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewScanSnapshotRepository(db)
+
+	// Test: Three cycles on kalshi, the last one with no markets, and one
+	// on polymarket
+	cycles := [][]*ScanDecision{
+		{{MarketID: "KXBTC-1", Eligible: true, Reason: ScanDecisionEntered, YesPrice: 0.90}},
+		{
+			{MarketID: "KXBTC-1", Eligible: true, Reason: "duplicate_position", YesPrice: 0.92},
+			{MarketID: "KXBTC-2", MarketTitle: "Bitcoin above $90k?", Asset: "BTC", Reason: "probability", YesPrice: 0.70, NoPrice: 0.30, Probability: 0.70, BetSide: "YES"},
+		},
+		nil,
+	}
+	for _, decisions := range cycles {
+		if err := repo.Record("kalshi", decisions); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if err := repo.Record("polymarket", []*ScanDecision{{MarketID: "pm-1", Reason: "liquidity"}}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	platforms, err := repo.GetPlatforms()
+	if err != nil {
+		t.Fatalf("GetPlatforms failed: %v", err)
+	}
+	if len(platforms) != 2 || platforms[0] != "kalshi" || platforms[1] != "polymarket" {
+		t.Errorf("expected kalshi and polymarket, got %v", platforms)
+	}
+
+	// Test: Only the latest snapshots are kept, newest first
+	snapshots, err := repo.GetLatest("kalshi", 5)
+	if err != nil {
+		t.Fatalf("GetLatest failed: %v", err)
+	}
+	if len(snapshots) != ScanSnapshotsKept {
+		t.Fatalf("expected %d snapshots kept, got %d", ScanSnapshotsKept, len(snapshots))
+	}
+	if len(snapshots[0].Decisions) != 0 {
+		t.Errorf("expected the empty cycle first, got %d decisions", len(snapshots[0].Decisions))
+	}
+	previous := snapshots[1]
+	if previous.Platform != "kalshi" || previous.ScannedAt.IsZero() || len(previous.Decisions) != 2 {
+		t.Fatalf("unexpected previous snapshot %+v", previous)
+	}
+	d := previous.Decisions[1]
+	if d.Platform != "kalshi" || d.MarketID != "KXBTC-2" || d.Asset != "BTC" || d.Eligible || d.Reason != "probability" || d.YesPrice != 0.70 || d.BetSide != "YES" {
+		t.Errorf("unexpected decision %+v", d)
+	}
+
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM scan_snapshot_markets`).Scan(&rows); err != nil {
+		t.Fatalf("count snapshot markets: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("expected the pruned cycle's markets dropped, got %d rows", rows)
+	}
+}
@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Session summarizes one run of the bot.
+type Session struct {
+	ID            int64
+	StartedAt     time.Time
+	EndedAt       time.Time
+	DryRun        bool
+	ScanCycles    int
+	MonitorCycles int
+	SettleCycles  int
+	Entries       int
+	Exits         int     // Positions fully closed
+	RealizedPnL   float64 // Dollars, from the positions closed during the session
+	Errors        int
+	CreatedAt     time.Time
+}
+
+// Uptime returns how long the session ran.
+func (s *Session) Uptime() time.Duration {
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+// SessionRepository handles database operations for sessions.
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new SessionRepository.
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Record inserts a session and returns its ID.
+func (r *SessionRepository) Record(s *Session) (int64, error) {
+	result, err := r.db.Exec(`
+		INSERT INTO sessions (
+			started_at, ended_at, dry_run, scan_cycles, monitor_cycles,
+			settle_cycles, entries, exits, realized_pnl_micros, errors
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		s.StartedAt.UTC().Format("2006-01-02 15:04:05"), s.EndedAt.UTC().Format("2006-01-02 15:04:05"),
+		s.DryRun, s.ScanCycles, s.MonitorCycles,
+		s.SettleCycles, s.Entries, s.Exits, types.Dollars(s.RealizedPnL), s.Errors,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("record session: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("get last insert id: %w", err)
+	}
+	s.ID = id
+
+	return id, nil
+}
+
+// GetRecent retrieves the most recent sessions, newest first.
+func (r *SessionRepository) GetRecent(limit int) ([]*Session, error) {
+	rows, err := r.db.Query(`
+		SELECT id, started_at, ended_at, dry_run, scan_cycles, monitor_cycles,
+			settle_cycles, entries, exits, realized_pnl_micros, errors, created_at
+		FROM sessions
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*Session
+	for rows.Next() {
+		s := &Session{}
+		err := rows.Scan(
+			&s.ID, &s.StartedAt, &s.EndedAt, &s.DryRun, &s.ScanCycles, &s.MonitorCycles,
+			&s.SettleCycles, &s.Entries, &s.Exits, dollars(&s.RealizedPnL), &s.Errors, &s.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sessions: %w", err)
+	}
+	return sessions, nil
+}
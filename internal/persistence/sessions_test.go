@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSessionRepository_RecordAndGetRecent(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_sessions_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewSessionRepository(db)
+
+	started := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, pnl := range []float64{1.50, -0.75} {
+		_, err := repo.Record(&Session{
+			StartedAt:     started.Add(time.Duration(i) * 24 * time.Hour),
+			EndedAt:       started.Add(time.Duration(i)*24*time.Hour + 2*time.Hour),
+			DryRun:        true,
+			ScanCycles:    720,
+			MonitorCycles: 1440,
+			Entries:       3,
+			Exits:         2,
+			RealizedPnL:   pnl,
+			Errors:        1,
+		})
+		if err != nil {
+			t.Fatalf("failed to record session: %v", err)
+		}
+	}
+
+	sessions, err := repo.GetRecent(10)
+	if err != nil {
+		t.Fatalf("failed to get sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	// Test: Newest first, with its uptime and counts intact
+	latest := sessions[0]
+	if latest.RealizedPnL != -0.75 {
+		t.Errorf("expected latest session PnL -0.75, got %f", latest.RealizedPnL)
+	}
+	if latest.Uptime() != 2*time.Hour {
+		t.Errorf("expected uptime 2h, got %v", latest.Uptime())
+	}
+	if !latest.DryRun || latest.MonitorCycles != 1440 || latest.Entries != 3 || latest.Exits != 2 || latest.Errors != 1 {
+		t.Errorf("unexpected session counts: %+v", latest)
+	}
+}
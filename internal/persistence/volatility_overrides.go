@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VolatilityOverride is a manually set annualized volatility for an asset.
+type VolatilityOverride struct {
+	Asset      string
+	Volatility float64
+	Reason     string
+	UpdatedAt  time.Time
+}
+
+// VolatilityOverrideRepository handles database operations for volatility
+// overrides. Assets are stored as upper-case symbols (BTC, SOL).
+type VolatilityOverrideRepository struct {
+	db *sql.DB
+}
+
+// NewVolatilityOverrideRepository creates a new VolatilityOverrideRepository.
+func NewVolatilityOverrideRepository(db *sql.DB) *VolatilityOverrideRepository {
+	return &VolatilityOverrideRepository{db: db}
+}
+
+// Set stores the override for an asset, replacing any existing one.
+func (r *VolatilityOverrideRepository) Set(asset string, volatility float64, reason string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO volatility_overrides (asset, volatility, reason, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (asset) DO UPDATE SET
+			volatility = excluded.volatility,
+			reason = excluded.reason,
+			updated_at = excluded.updated_at
+	`, strings.ToUpper(asset), volatility, reason)
+	if err != nil {
+		return fmt.Errorf("set volatility override: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves the override for an asset. Returns nil if none is set.
+func (r *VolatilityOverrideRepository) Get(asset string) (*VolatilityOverride, error) {
+	o := &VolatilityOverride{}
+	var reason sql.NullString
+	err := r.db.QueryRow(`
+		SELECT asset, volatility, reason, updated_at
+		FROM volatility_overrides WHERE asset = ?
+	`, strings.ToUpper(asset)).Scan(&o.Asset, &o.Volatility, &reason, &o.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get volatility override: %w", err)
+	}
+	o.Reason = reason.String
+	return o, nil
+}
+
+// Delete removes the override for an asset, if any.
+func (r *VolatilityOverrideRepository) Delete(asset string) error {
+	_, err := r.db.Exec(`DELETE FROM volatility_overrides WHERE asset = ?`, strings.ToUpper(asset))
+	if err != nil {
+		return fmt.Errorf("delete volatility override: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"os"
+	"testing"
+)
+
+func TestVolatilityOverrideRepository_SetGetDelete(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_volatility_overrides_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := NewVolatilityOverrideRepository(db)
+
+	// Test: No override set
+	o, err := repo.Get("SOL")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if o != nil {
+		t.Fatalf("expected no override, got %+v", o)
+	}
+
+	// Test: Set replaces the previous value, asset is case-insensitive
+	if err := repo.Set("sol", 0.9, "new listing"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := repo.Set("SOL", 1.1, "post-unlock"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	o, err = repo.Get("Sol")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if o == nil || o.Asset != "SOL" || o.Volatility != 1.1 || o.Reason != "post-unlock" {
+		t.Errorf("expected SOL override 1.1 (post-unlock), got %+v", o)
+	}
+
+	// Test: Delete removes it
+	if err := repo.Delete("SOL"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	o, err = repo.Get("SOL")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if o != nil {
+		t.Errorf("expected override deleted, got %+v", o)
+	}
+}
@@ -0,0 +1,11 @@
+// Package adapters compiles in the platform adapters, registering each with
+// the platform registry. Add a blank import here to make a new adapter
+// available to enable from config.
+package adapters
+
+import (
+	_ "prediction-bot/internal/platform/kalshi"
+	_ "prediction-bot/internal/platform/manifold"
+	_ "prediction-bot/internal/platform/polymarket"
+	_ "prediction-bot/internal/platform/predictit"
+)
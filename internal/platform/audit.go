@@ -0,0 +1,106 @@
+package platform
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// APICall is one request made to a platform's API, as audited.
+type APICall struct {
+	Platform     string
+	Method       string
+	Endpoint     string // Host, path and query
+	StatusCode   int    // 0 if no response was received
+	Latency      time.Duration
+	Err          error  // Transport error, nil if a response was received
+	RequestBody  string // Truncated to the audit's payload size
+	ResponseBody string // Truncated to the audit's payload size
+}
+
+// APICallRecorder records the API calls platforms make.
+type APICallRecorder interface {
+	RecordAPICall(call APICall) error
+}
+
+// AuditTransport is an http.RoundTripper that records every request a
+// platform's client sends, with its latency, status and the start of its
+// payloads. Headers, which carry credentials, aren't recorded.
+type AuditTransport struct {
+	name         string
+	base         http.RoundTripper
+	recorder     APICallRecorder
+	payloadBytes int
+}
+
+// NewAuditTransport wraps base (http.DefaultTransport if nil) to record the
+// requests of the platform called name, keeping up to payloadBytes of each
+// body.
+func NewAuditTransport(name string, base http.RoundTripper, recorder APICallRecorder, payloadBytes int) *AuditTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &AuditTransport{name: name, base: base, recorder: recorder, payloadBytes: payloadBytes}
+}
+
+// RoundTrip sends req and records it. A call that can't be recorded is
+// logged and sent anyway.
+func (t *AuditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := APICall{
+		Platform:    t.name,
+		Method:      req.Method,
+		Endpoint:    req.URL.Host + req.URL.RequestURI(),
+		RequestBody: t.requestBody(req),
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	call.Latency = time.Since(start)
+	call.Err = err
+	if resp != nil {
+		call.StatusCode = resp.StatusCode
+		call.ResponseBody = t.peekResponseBody(resp)
+	}
+
+	if recordErr := t.recorder.RecordAPICall(call); recordErr != nil {
+		log.Debug().Err(recordErr).Str("platform", t.name).Msg("failed to record api call")
+	}
+	return resp, err
+}
+
+// requestBody returns the start of req's body, read from a copy so the body
+// sent is untouched. A body that can't be copied isn't recorded.
+func (t *AuditTransport) requestBody(req *http.Request) string {
+	if t.payloadBytes <= 0 || req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	return readPrefix(body, t.payloadBytes)
+}
+
+// peekResponseBody returns the start of resp's body and puts it back in
+// front of the rest for the client to read.
+func (t *AuditTransport) peekResponseBody(resp *http.Response) string {
+	if t.payloadBytes <= 0 || resp.Body == nil {
+		return ""
+	}
+	prefix, _ := io.ReadAll(io.LimitReader(resp.Body, int64(t.payloadBytes)))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+	return string(prefix)
+}
+
+// readPrefix returns up to n bytes read from r.
+func readPrefix(r io.Reader, n int) string {
+	prefix, _ := io.ReadAll(io.LimitReader(r, int64(n)))
+	return string(prefix)
+}
@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recordedCalls records the API calls audited.
+type recordedCalls struct {
+	calls []APICall
+}
+
+func (r *recordedCalls) RecordAPICall(call APICall) error {
+	r.calls = append(r.calls, call)
+	return nil
+}
+
+func TestAuditTransport_RecordsCallsWithTruncatedPayloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte("received " + string(body)))
+	}))
+	defer server.Close()
+
+	recorder := &recordedCalls{}
+	client := &http.Client{Transport: NewAuditTransport("test", nil, recorder, 8)}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/order?dry=1", strings.NewReader(`{"size":100}`))
+	req.Header.Set("Authorization", "secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// The client still reads the whole response
+	if string(body) != `received {"size":100}` {
+		t.Errorf("expected the full response, got %q", body)
+	}
+
+	if len(recorder.calls) != 1 {
+		t.Fatalf("expected 1 call recorded, got %d", len(recorder.calls))
+	}
+	call := recorder.calls[0]
+	if call.Platform != "test" || call.Method != http.MethodPost || call.StatusCode != http.StatusOK {
+		t.Errorf("unexpected call %+v", call)
+	}
+	if !strings.HasSuffix(call.Endpoint, "/order?dry=1") {
+		t.Errorf("expected the path and query recorded, got %q", call.Endpoint)
+	}
+	if call.RequestBody != `{"size":` || call.ResponseBody != "received" {
+		t.Errorf("expected payloads truncated to 8 bytes, got %q and %q", call.RequestBody, call.ResponseBody)
+	}
+}
+
+func TestOptions_WrapTransportAuditsEachRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	recorder := &recordedCalls{}
+	opts := Options{HTTP: TransportConfig{MaxRetries: 1}, Audit: recorder}
+	client := &http.Client{Transport: opts.WrapTransport("test", nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(recorder.calls) != 2 || recorder.calls[0].StatusCode != http.StatusServiceUnavailable || recorder.calls[1].StatusCode != http.StatusOK {
+		t.Errorf("expected the failed call and its retry recorded, got %+v", recorder.calls)
+	}
+}
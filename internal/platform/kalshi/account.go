@@ -1,8 +1,10 @@
 package kalshi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"prediction-bot/pkg/types"
@@ -25,38 +27,54 @@ type positionsResponse struct {
 	MarketPositions []kalshiPosition `json:"market_positions"`
 }
 
-// GetPositions returns all open positions for the account.
+// GetPositions returns all open positions for the account, following the
+// cursor through every page.
 func (c *Client) GetPositions() ([]types.Position, error) {
-	path := BuildURL("/portfolio/positions", map[string]string{
-		"limit": "100",
-	})
+	var positions []types.Position
+	now := time.Now()
+	cursor := ""
 
-	body, err := c.doRequest("GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("get positions: %w", err)
-	}
+	for {
+		path := BuildURL("/portfolio/positions", map[string]string{
+			"limit":        "100",
+			"count_filter": "position",
+			"cursor":       cursor,
+		})
 
-	var response positionsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("parse positions response: %w", err)
-	}
+		body, err := c.doRequest(context.Background(), "GET", path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("get positions: %w", err)
+		}
 
-	positions := make([]types.Position, 0, len(response.MarketPositions))
-	now := time.Now()
+		var response positionsResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("parse positions response: %w", err)
+		}
+
+		for _, kp := range response.MarketPositions {
+			if kp.Position == 0 {
+				continue
+			}
+			pos := types.Position{
+				Platform:         "kalshi",
+				MarketTicker:     kp.MarketTicker,
+				Quantity:         float64(kp.Position),
+				MarketExposure:   float64(kp.MarketExposure) / 100.0, // Convert cents to dollars
+				RealizedPnL:      float64(kp.RealizedPnL) / 100.0,    // Convert cents to dollars
+				TotalTraded:      kp.TotalTraded,
+				FeesPaid:         float64(kp.FeesPaid) / 100.0, // Convert cents to dollars
+				RestingOrdersQty: kp.RestingOrdersCount,
+				Timestamp:        now,
+			}
+			// The exposure is what the contracts held cost
+			pos.AveragePrice = pos.MarketExposure / math.Abs(pos.Quantity)
+			positions = append(positions, pos)
+		}
 
-	for _, kp := range response.MarketPositions {
-		pos := types.Position{
-			Platform:         "kalshi",
-			MarketTicker:     kp.MarketTicker,
-			Quantity:         kp.Position,
-			MarketExposure:   float64(kp.MarketExposure) / 100.0, // Convert cents to dollars
-			RealizedPnL:      float64(kp.RealizedPnL) / 100.0,    // Convert cents to dollars
-			TotalTraded:      kp.TotalTraded,
-			FeesPaid:         float64(kp.FeesPaid) / 100.0, // Convert cents to dollars
-			RestingOrdersQty: kp.RestingOrdersCount,
-			Timestamp:        now,
+		if response.Cursor == "" || len(response.MarketPositions) == 0 {
+			break
 		}
-		positions = append(positions, pos)
+		cursor = response.Cursor
 	}
 
 	return positions, nil
@@ -1,6 +1,12 @@
 package kalshi
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -75,7 +81,7 @@ func TestClient_GetPositions(t *testing.T) {
 	t.Logf("Found %d positions", len(positions))
 
 	for _, pos := range positions {
-		t.Logf("Position: Market=%s, Qty=%d, Exposure=$%.2f, UnrealizedPnL=$%.2f",
+		t.Logf("Position: Market=%s, Qty=%.0f, Exposure=$%.2f, UnrealizedPnL=$%.2f",
 			pos.MarketTicker, pos.Quantity, pos.MarketExposure, pos.UnrealizedPnL)
 	}
 }
@@ -104,3 +110,46 @@ func TestClient_GetPositions_NoCredentials(t *testing.T) {
 		t.Fatal("expected error when credentials are missing")
 	}
 }
+
+func TestClient_GetPositions_FollowsCursor(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiPath+"/portfolio/positions" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"cursor":"page2","market_positions":[
+				{"market_ticker":"KXBTC-25MAR31-B100000","position":10,"market_exposure":650},
+				{"market_ticker":"KXETH-25MAR31-B4000","position":0}
+			]}`))
+		case "page2":
+			w.Write([]byte(`{"cursor":"","market_positions":[
+				{"market_ticker":"KXBTC-25MAR31-B90000","position":-4,"market_exposure":120}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{APIKey: "test-key", PrivateKey: string(keyPEM)})
+	client.baseURL = server.URL
+
+	positions, err := client.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions failed: %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("expected the two held positions from both pages, got %+v", positions)
+	}
+	if positions[0].Quantity != 10 || positions[0].AveragePrice != 0.65 {
+		t.Errorf("expected 10 YES at 0.65, got %+v", positions[0])
+	}
+	if positions[1].Quantity != -4 || positions[1].AveragePrice != 0.3 {
+		t.Errorf("expected 4 NO at 0.30, got %+v", positions[1])
+	}
+}
@@ -9,8 +9,6 @@ import (
 	"encoding/base64"
 	"encoding/pem"
 	"fmt"
-	"strconv"
-	"time"
 )
 
 // Credentials holds Kalshi API credentials.
@@ -63,8 +61,3 @@ func generateSignature(privateKeyPEM, timestamp, method, path string) (string, e
 	// Encode signature as base64
 	return base64.StdEncoding.EncodeToString(signature), nil
 }
-
-// getTimestampMS returns the current timestamp in milliseconds.
-func getTimestampMS() string {
-	return strconv.FormatInt(time.Now().UnixMilli(), 10)
-}
@@ -1,6 +1,7 @@
 package kalshi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,6 +26,23 @@ type Client struct {
 	httpClient *http.Client
 	creds      Credentials
 	baseURL    string
+	clock      clock
+}
+
+// APIError is returned when the Kalshi API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+	// ClockSkew is the measured drift of the local clock, reported on
+	// unauthorized responses since a skewed clock invalidates signatures.
+	ClockSkew time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == http.StatusUnauthorized {
+		return fmt.Sprintf("api error (status %d, clock skew %v): %s", e.StatusCode, e.ClockSkew, e.Body)
+	}
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Body)
 }
 
 // Balance represents account balance information.
@@ -78,8 +96,8 @@ func NewClientWithCreds(creds Credentials) *Client {
 }
 
 // doRequest performs an authenticated request to the Kalshi API.
-func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
-	timestamp := getTimestampMS()
+func (c *Client) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	timestamp := c.clock.timestampMS()
 
 	// Full path includes API version prefix
 	fullPath := apiPath + path
@@ -101,7 +119,7 @@ func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
 		reqBody = &byteReader{data: body}
 	}
 
-	req, err := http.NewRequest(method, fullURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -113,11 +131,13 @@ func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
+	sent := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
+	c.clock.observe(resp.Header.Get("Date"), sent, time.Now())
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -125,7 +145,7 @@ func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody), ClockSkew: c.ClockSkew()}
 	}
 
 	return respBody, nil
@@ -147,20 +167,22 @@ func (r *byteReader) Read(p []byte) (n int, err error) {
 }
 
 // doPublicRequest performs a non-authenticated request to the Kalshi API.
-func (c *Client) doPublicRequest(method, path string) ([]byte, error) {
+func (c *Client) doPublicRequest(ctx context.Context, method, path string) ([]byte, error) {
 	fullURL := c.baseURL + apiPath + path
 
-	req, err := http.NewRequest(method, fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
 
+	sent := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
+	c.clock.observe(resp.Header.Get("Date"), sent, time.Now())
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -168,7 +190,7 @@ func (c *Client) doPublicRequest(method, path string) ([]byte, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody), ClockSkew: c.ClockSkew()}
 	}
 
 	return respBody, nil
@@ -193,7 +215,7 @@ func (c *Client) Ping() error {
 
 // GetBalanceDetails returns the detailed account balance.
 func (c *Client) GetBalanceDetails() (*Balance, error) {
-	body, err := c.doRequest("GET", "/portfolio/balance", nil)
+	body, err := c.doRequest(context.Background(), "GET", "/portfolio/balance", nil)
 	if err != nil {
 		return nil, fmt.Errorf("get balance: %w", err)
 	}
@@ -230,9 +252,14 @@ func (c *Client) Name() string {
 	return "kalshi"
 }
 
+// LotSize returns the smallest quantity Kalshi trades: a whole contract.
+func (c *Client) LotSize() float64 {
+	return 1
+}
+
 // GetExchangeStatus returns the exchange status (public endpoint, no auth needed).
 func (c *Client) GetExchangeStatus() (string, error) {
-	body, err := c.doPublicRequest("GET", "/exchange/status")
+	body, err := c.doPublicRequest(context.Background(), "GET", "/exchange/status")
 	if err != nil {
 		return "", err
 	}
@@ -272,7 +299,7 @@ func BuildURL(basePath string, params map[string]string) string {
 // GetOrderBook implements platform.Platform interface.
 // Kalshi markets have a built-in order book but the public API only exposes
 // the current best bid/ask through the market endpoint.
-func (c *Client) GetOrderBook(marketID string) (*types.OrderBook, error) {
+func (c *Client) GetOrderBook(ctx context.Context, marketID string) (*types.OrderBook, error) {
 	// Kalshi's API doesn't have a dedicated orderbook endpoint.
 	// We return a minimal orderbook based on market data.
 	// Full orderbook would require websocket subscription.
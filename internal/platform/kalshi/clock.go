@@ -0,0 +1,101 @@
+package kalshi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultMaxClockSkew is how far the local clock may drift from the
+// exchange's before signature timestamps are corrected. Kalshi rejects
+// requests signed with a timestamp too far from its own clock as
+// unauthorized.
+const DefaultMaxClockSkew = 2 * time.Second
+
+// clock keeps signature timestamps in line with the exchange's clock. Every
+// response's Date header is used to measure the local clock's drift; while
+// the drift exceeds the maximum, timestamps are offset by it.
+type clock struct {
+	maxSkew time.Duration // Zero uses DefaultMaxClockSkew
+	skew    atomic.Int64  // Last measured exchange minus local time, in nanoseconds
+	offset  atomic.Int64  // Correction added to timestamps, in nanoseconds
+}
+
+// now returns the current time, corrected for clock skew.
+func (c *clock) now() time.Time {
+	return time.Now().Add(time.Duration(c.offset.Load()))
+}
+
+// timestampMS returns the current corrected timestamp in milliseconds.
+func (c *clock) timestampMS() string {
+	return strconv.FormatInt(c.now().UnixMilli(), 10)
+}
+
+// observe measures the skew from a response's Date header, given when the
+// request was sent and its response received. Responses without a valid
+// Date header are ignored.
+func (c *clock) observe(date string, sent, received time.Time) {
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	// The header has second resolution, so the exchange read its clock
+	// somewhere within the following second; compare against the midpoint
+	// of the round trip
+	serverTime = serverTime.Add(500 * time.Millisecond)
+	skew := serverTime.Sub(sent.Add(received.Sub(sent) / 2))
+	c.skew.Store(int64(skew))
+
+	maxSkew := c.maxSkew
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxClockSkew
+	}
+
+	corrected := c.offset.Load() != 0
+	if skew > maxSkew || skew < -maxSkew {
+		c.offset.Store(int64(skew))
+		if !corrected {
+			log.Warn().
+				Dur("skew", skew).
+				Dur("max_skew", maxSkew).
+				Msg("local clock drifted from kalshi, correcting signature timestamps (check NTP)")
+		}
+		return
+	}
+
+	c.offset.Store(0)
+	if corrected {
+		log.Info().
+			Dur("skew", skew).
+			Msg("local clock back in line with kalshi, signature timestamps no longer corrected")
+	}
+}
+
+// SetMaxClockSkew sets how far the local clock may drift from the exchange's
+// before signature timestamps are corrected. Zero uses DefaultMaxClockSkew.
+func (c *Client) SetMaxClockSkew(maxSkew time.Duration) {
+	c.clock.maxSkew = maxSkew
+}
+
+// ClockSkew returns the exchange's clock minus the local clock, as last
+// measured from a response. Positive means the local clock is behind.
+func (c *Client) ClockSkew() time.Duration {
+	return time.Duration(c.clock.skew.Load())
+}
+
+// CheckClockSkew measures the local clock's drift from the exchange's
+// against the public status endpoint and returns it. The drift is also
+// re-measured on every later response, so periodic status checks keep it
+// current.
+func (c *Client) CheckClockSkew() (time.Duration, error) {
+	if _, err := c.doPublicRequest(context.Background(), "GET", "/exchange/status"); err != nil {
+		return 0, fmt.Errorf("check clock skew: %w", err)
+	}
+	return c.ClockSkew(), nil
+}
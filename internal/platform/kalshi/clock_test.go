@@ -0,0 +1,84 @@
+package kalshi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClockObserve(t *testing.T) {
+	c := &clock{}
+	local := time.Now()
+
+	// Exchange 10s ahead: beyond the default maximum, so timestamps are corrected
+	c.observe(local.Add(10*time.Second).UTC().Format(http.TimeFormat), local, local)
+	skew := time.Duration(c.skew.Load())
+	if skew < 9*time.Second || skew > 11*time.Second {
+		t.Fatalf("expected skew of about 10s, got %v", skew)
+	}
+	if offset := c.now().Sub(time.Now()); offset < 9*time.Second {
+		t.Errorf("expected corrected time about 10s ahead, got %v", offset)
+	}
+
+	// Back within the maximum: correction removed
+	c.observe(local.UTC().Format(http.TimeFormat), local, local)
+	if c.offset.Load() != 0 {
+		t.Errorf("expected no correction within the maximum skew, got %v", time.Duration(c.offset.Load()))
+	}
+
+	// Unparseable Date headers are ignored
+	c.observe("", local, local)
+	if c.offset.Load() != 0 {
+		t.Error("expected missing Date header to be ignored")
+	}
+}
+
+func TestCheckClockSkew_CorrectsSignatureTimestamps(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	// The exchange's clock runs a minute ahead of ours
+	ahead := time.Minute
+	var signedAt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(ahead).UTC().Format(http.TimeFormat))
+		switch r.URL.Path {
+		case apiPath + "/exchange/status":
+			w.Write([]byte(`{"exchange_active":true,"trading_active":true}`))
+		case apiPath + "/portfolio/balance":
+			ms, _ := strconv.ParseInt(r.Header.Get("KALSHI-ACCESS-TIMESTAMP"), 10, 64)
+			signedAt = time.UnixMilli(ms)
+			w.Write([]byte(`{"balance":1000}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{APIKey: "test-key", PrivateKey: string(keyPEM)})
+	client.baseURL = server.URL
+
+	skew, err := client.CheckClockSkew()
+	if err != nil {
+		t.Fatalf("CheckClockSkew failed: %v", err)
+	}
+	if skew < ahead-time.Second || skew > ahead+time.Second {
+		t.Errorf("expected skew of about %v, got %v", ahead, skew)
+	}
+
+	if _, err := client.GetBalance(); err != nil {
+		t.Fatalf("GetBalance failed: %v", err)
+	}
+	if drift := signedAt.Sub(time.Now().Add(ahead)); drift < -2*time.Second || drift > 2*time.Second {
+		t.Errorf("expected signature timestamp on the exchange's clock, off by %v", drift)
+	}
+}
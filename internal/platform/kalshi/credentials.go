@@ -0,0 +1,43 @@
+package kalshi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// permissionProbeOrderID is an order ID that never exists. Cancelling it
+// tells a key with trading permission (not found) from one without
+// (forbidden) without touching any real order.
+const permissionProbeOrderID = "00000000-0000-0000-0000-000000000000"
+
+// VerifyCredentials checks that the private key parses, that the exchange
+// accepts the API key's signatures, and that the key may trade. The error
+// names the credential that is misconfigured.
+func (c *Client) VerifyCredentials() error {
+	if _, err := generateSignature(c.creds.PrivateKey, "0", "GET", apiPath+"/portfolio/balance"); err != nil {
+		return fmt.Errorf("KALSHI_PRIVATE_KEY is not a valid RSA private key: %w", err)
+	}
+
+	if _, err := c.doRequest(context.Background(), "GET", "/portfolio/balance", nil); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("KALSHI_API_KEY rejected: expired, revoked or not paired with KALSHI_PRIVATE_KEY: %w", err)
+		}
+		return fmt.Errorf("verify kalshi credentials: %w", err)
+	}
+
+	_, err := c.doRequest(context.Background(), "DELETE", "/portfolio/orders/"+permissionProbeOrderID, nil)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		return fmt.Errorf("KALSHI_API_KEY lacks trading permission: %w", err)
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("verify kalshi trading permission: %w", err)
+	}
+}
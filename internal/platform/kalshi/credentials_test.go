@@ -0,0 +1,65 @@
+package kalshi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCredentials(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	tests := []struct {
+		name         string
+		privateKey   string
+		balanceCode  int
+		cancelCode   int
+		wantErrorFor string
+	}{
+		{"valid trading key", keyPEM, http.StatusOK, http.StatusNotFound, ""},
+		{"malformed private key", "not a key", http.StatusOK, http.StatusNotFound, "KALSHI_PRIVATE_KEY"},
+		{"rejected key", keyPEM, http.StatusUnauthorized, http.StatusNotFound, "KALSHI_API_KEY rejected"},
+		{"read-only key", keyPEM, http.StatusOK, http.StatusForbidden, "trading permission"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "GET" && r.URL.Path == apiPath+"/portfolio/balance":
+					w.WriteHeader(tt.balanceCode)
+					w.Write([]byte(`{"balance":1000}`))
+				case r.Method == "DELETE" && r.URL.Path == apiPath+"/portfolio/orders/"+permissionProbeOrderID:
+					w.WriteHeader(tt.cancelCode)
+					w.Write([]byte(`{"error":{"code":"not_found"}}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClientWithCreds(Credentials{APIKey: "test-key", PrivateKey: tt.privateKey})
+			client.baseURL = server.URL
+
+			err := client.VerifyCredentials()
+			if tt.wantErrorFor == "" {
+				if err != nil {
+					t.Errorf("expected valid credentials, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrorFor) {
+				t.Errorf("expected error naming %q, got %v", tt.wantErrorFor, err)
+			}
+		})
+	}
+}
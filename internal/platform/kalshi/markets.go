@@ -1,9 +1,11 @@
 package kalshi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"prediction-bot/pkg/types"
@@ -40,6 +42,11 @@ type KalshiMarket struct {
 	CapStrike        float64 `json:"cap_strike"`
 }
 
+// marketResponse represents the API response for a single market.
+type marketResponse struct {
+	Market KalshiMarket `json:"market"`
+}
+
 // MarketsResponse represents the API response for listing markets.
 type MarketsResponse struct {
 	Markets []KalshiMarket `json:"markets"`
@@ -47,7 +54,7 @@ type MarketsResponse struct {
 }
 
 // ListMarkets returns a list of markets matching the filter criteria.
-func (c *Client) ListMarkets(filter types.MarketFilter) ([]types.Market, error) {
+func (c *Client) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
 	// Build query parameters
 	params := make(map[string]string)
 
@@ -66,7 +73,7 @@ func (c *Client) ListMarkets(filter types.MarketFilter) ([]types.Market, error)
 	}
 
 	path := BuildURL("/markets", params)
-	body, err := c.doPublicRequest("GET", path)
+	body, err := c.doPublicRequest(ctx, "GET", path)
 	if err != nil {
 		return nil, fmt.Errorf("list markets: %w", err)
 	}
@@ -77,13 +84,117 @@ func (c *Client) ListMarkets(filter types.MarketFilter) ([]types.Market, error)
 	}
 
 	// Convert Kalshi markets to common Market type
-	markets := make([]types.Market, 0, len(response.Markets))
-	for _, km := range response.Markets {
+	return groupBrackets(response.Markets), nil
+}
+
+// groupBrackets converts Kalshi markets to common Markets, combining the
+// markets of an event that has "between" brackets (e.g. "Bitcoin price
+// range on Friday?") into one multi-outcome Market. Each bracket, and any
+// open-ended "greater" or "less" market of the event, becomes an outcome
+// traded on its own ticker. The combined market takes the event ticker as
+// its ID and the place of the event's first market.
+func groupBrackets(kms []KalshiMarket) []types.Market {
+	bracketed := make(map[string]bool)
+	for _, km := range kms {
+		if km.StrikeType == "between" && km.EventTicker != "" {
+			bracketed[km.EventTicker] = true
+		}
+	}
+
+	markets := make([]types.Market, 0, len(kms))
+	events := make(map[string]int) // Event ticker to index in markets
+	for _, km := range kms {
 		market := convertKalshiMarket(km)
-		markets = append(markets, market)
+		if !bracketed[km.EventTicker] {
+			markets = append(markets, market)
+			continue
+		}
+
+		outcome := types.Outcome{
+			MarketID:  market.ID,
+			Name:      outcomeName(km),
+			Price:     market.OutcomeYesPrice,
+			Spread:    market.Spread,
+			Liquidity: market.Liquidity,
+		}
+
+		i, ok := events[km.EventTicker]
+		if !ok {
+			market.ID = km.EventTicker
+			market.Spread = 0
+			market.OutcomeYesPrice = 0
+			market.OutcomeNoPrice = 0
+			market.Outcomes = []types.Outcome{outcome}
+			events[km.EventTicker] = len(markets)
+			markets = append(markets, market)
+			continue
+		}
+		markets[i].Volume += market.Volume
+		markets[i].Liquidity += market.Liquidity
+		markets[i].Outcomes = append(markets[i].Outcomes, outcome)
 	}
 
-	return markets, nil
+	return markets
+}
+
+// outcomeName names the outcome a bracketed event's market trades from its
+// strikes, e.g. "$95000 to $99999.99" or "$100000 or above". Markets
+// without strikes are named by their subtitle.
+func outcomeName(km KalshiMarket) string {
+	strike := func(v float64) string { return "$" + strconv.FormatFloat(v, 'f', -1, 64) }
+
+	switch {
+	case km.StrikeType == "between" && km.CapStrike > 0:
+		return strike(km.FloorStrike) + " to " + strike(km.CapStrike)
+	case strings.HasPrefix(km.StrikeType, "greater") && km.FloorStrike > 0:
+		return strike(km.FloorStrike) + " or above"
+	case strings.HasPrefix(km.StrikeType, "less") && km.CapStrike > 0:
+		return strike(km.CapStrike) + " or below"
+	}
+	if km.Subtitle != "" {
+		return km.Subtitle
+	}
+	return km.Ticker
+}
+
+// GetMarket fetches a single market by ticker.
+func (c *Client) GetMarket(ticker string) (*types.Market, error) {
+	body, err := c.doPublicRequest(context.Background(), "GET", "/markets/"+ticker)
+	if err != nil {
+		return nil, fmt.Errorf("get market: %w", err)
+	}
+
+	var response marketResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parse market response: %w", err)
+	}
+
+	market := convertKalshiMarket(response.Market)
+	return &market, nil
+}
+
+// GetResolution reports whether a market has settled and which side won.
+// Markets settled without a yes/no result (e.g. voided) are reported as
+// unresolved.
+func (c *Client) GetResolution(ticker string) (types.Resolution, error) {
+	body, err := c.doPublicRequest(context.Background(), "GET", "/markets/"+ticker)
+	if err != nil {
+		return types.Resolution{}, fmt.Errorf("get market: %w", err)
+	}
+
+	var response marketResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return types.Resolution{}, fmt.Errorf("parse market response: %w", err)
+	}
+
+	resolution := types.Resolution{MarketID: ticker}
+	switch response.Market.Result {
+	case "yes", "no":
+		resolution.Resolved = true
+		resolution.Outcome = strings.ToUpper(response.Market.Result)
+	}
+
+	return resolution, nil
 }
 
 // convertKalshiMarket converts a Kalshi-specific market to the common Market type.
@@ -111,15 +222,22 @@ func convertKalshiMarket(km KalshiMarket) types.Market {
 
 	noPrice := 1.0 - yesPrice
 
+	var spread float64
+	if km.YesBid > 0 && km.YesAsk > 0 {
+		spread = float64(km.YesAsk-km.YesBid) / 100.0
+	}
+
 	return types.Market{
 		ID:              km.Ticker,
 		Platform:        "kalshi",
 		ConditionID:     km.EventTicker,
 		Title:           km.Title,
 		Description:     km.Subtitle,
+		Category:        km.Category,
 		EndDate:         endDate,
 		Volume:          float64(km.Volume24H) / 100.0, // Convert cents to dollars
 		Liquidity:       float64(km.Liquidity) / 100.0, // Convert cents to dollars
+		Spread:          spread,
 		Active:          isActive,
 		Closed:          isClosed,
 		OutcomeYesPrice: yesPrice,
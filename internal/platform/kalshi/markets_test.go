@@ -1,7 +1,11 @@
 package kalshi
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -21,7 +25,7 @@ func TestClient_ListMarkets_ReturnsActiveMarkets(t *testing.T) {
 		Limit:    10,
 	}
 
-	markets, err := client.ListMarkets(filter)
+	markets, err := client.ListMarkets(context.Background(), filter)
 	if err != nil {
 		t.Fatalf("ListMarkets failed: %v", err)
 	}
@@ -64,7 +68,7 @@ func TestClient_ListMarkets_RespectsLimit(t *testing.T) {
 		Limit: 5,
 	}
 
-	markets, err := client.ListMarkets(filter)
+	markets, err := client.ListMarkets(context.Background(), filter)
 	if err != nil {
 		t.Fatalf("ListMarkets failed: %v", err)
 	}
@@ -86,7 +90,7 @@ func TestClient_ListMarkets_MapsMktFieldsCorrectly(t *testing.T) {
 		Limit: 1,
 	}
 
-	markets, err := client.ListMarkets(filter)
+	markets, err := client.ListMarkets(context.Background(), filter)
 	if err != nil {
 		t.Fatalf("ListMarkets failed: %v", err)
 	}
@@ -114,3 +118,126 @@ func TestClient_ListMarkets_MapsMktFieldsCorrectly(t *testing.T) {
 		t.Logf("Warning: active market with EndDate in the past: %v", m.EndDate)
 	}
 }
+
+func TestClient_ListMarkets_HonorsCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never answer: only the caller's deadline ends the request
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{})
+	client.baseURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.ListMarkets(ctx, types.MarketFilter{Limit: 10})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected ListMarkets to return at the deadline, took %v", elapsed)
+	}
+}
+
+func TestGetResolution(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiPath + "/markets/KXBTC-WON":
+			w.Write([]byte(`{"market":{"ticker":"KXBTC-WON","status":"settled","result":"no"}}`))
+		case apiPath + "/markets/KXBTC-OPEN":
+			w.Write([]byte(`{"market":{"ticker":"KXBTC-OPEN","status":"active","result":""}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{})
+	client.baseURL = server.URL
+
+	resolution, err := client.GetResolution("KXBTC-WON")
+	if err != nil {
+		t.Fatalf("GetResolution failed: %v", err)
+	}
+	if !resolution.Resolved || resolution.Outcome != "NO" {
+		t.Errorf("expected resolved NO, got %+v", resolution)
+	}
+
+	resolution, err = client.GetResolution("KXBTC-OPEN")
+	if err != nil {
+		t.Fatalf("GetResolution failed: %v", err)
+	}
+	if resolution.Resolved {
+		t.Errorf("expected unresolved market, got %+v", resolution)
+	}
+}
+
+func TestGetMarket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiPath+"/markets/KXBTC-OPEN" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"market":{"ticker":"KXBTC-OPEN","status":"active","yes_bid":80,"yes_ask":84,"no_bid":16,"no_ask":20}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{})
+	client.baseURL = server.URL
+
+	market, err := client.GetMarket("KXBTC-OPEN")
+	if err != nil {
+		t.Fatalf("GetMarket failed: %v", err)
+	}
+	if market.ID != "KXBTC-OPEN" || !market.Active {
+		t.Errorf("expected active KXBTC-OPEN, got %+v", market)
+	}
+	if market.OutcomeYesPrice != 0.82 {
+		t.Errorf("expected YES mid price 0.82, got %v", market.OutcomeYesPrice)
+	}
+
+	if _, err := client.GetMarket("KXBTC-MISSING"); err == nil {
+		t.Error("expected error for unknown market")
+	}
+}
+
+func TestGroupBrackets(t *testing.T) {
+	markets := groupBrackets([]KalshiMarket{
+		{Ticker: "KXBTC-B95", EventTicker: "KXBTC-FRI", Title: "Bitcoin price range on Friday?", Status: "active",
+			StrikeType: "between", FloorStrike: 95000, CapStrike: 99999.99, YesBid: 20, YesAsk: 24, Liquidity: 10000},
+		{Ticker: "KXETHD-4000", EventTicker: "KXETHD-FRI", Title: "Ethereum above $4,000 on Friday?", Status: "active",
+			StrikeType: "greater", FloorStrike: 4000, YesBid: 50, YesAsk: 52},
+		{Ticker: "KXBTC-T100", EventTicker: "KXBTC-FRI", Title: "Bitcoin price range on Friday?", Status: "active",
+			StrikeType: "greater", FloorStrike: 100000, YesBid: 70, YesAsk: 74, Liquidity: 5000},
+		{Ticker: "KXBTC-T95", EventTicker: "KXBTC-FRI", Title: "Bitcoin price range on Friday?", Status: "active",
+			StrikeType: "less", CapStrike: 95000, LastPrice: 6},
+	})
+
+	if len(markets) != 2 {
+		t.Fatalf("expected the bracket event and the binary, got %d markets", len(markets))
+	}
+	if markets[1].ID != "KXETHD-4000" || len(markets[1].Outcomes) != 0 {
+		t.Errorf("expected the binary to stay a binary, got %+v", markets[1])
+	}
+
+	event := markets[0]
+	if event.ID != "KXBTC-FRI" || event.Title != "Bitcoin price range on Friday?" || event.Liquidity != 150 {
+		t.Errorf("expected the KXBTC-FRI event with combined liquidity, got %+v", event)
+	}
+	want := []types.Outcome{
+		{MarketID: "KXBTC-B95", Name: "$95000 to $99999.99", Price: 0.22, Spread: 0.04, Liquidity: 100},
+		{MarketID: "KXBTC-T100", Name: "$100000 or above", Price: 0.72, Spread: 0.04, Liquidity: 50},
+		{MarketID: "KXBTC-T95", Name: "$95000 or below", Price: 0.06},
+	}
+	if len(event.Outcomes) != len(want) {
+		t.Fatalf("expected %d outcomes, got %+v", len(want), event.Outcomes)
+	}
+	for i, o := range event.Outcomes {
+		if o.MarketID != want[i].MarketID || o.Name != want[i].Name || math.Abs(o.Price-want[i].Price) > 1e-9 ||
+			math.Abs(o.Spread-want[i].Spread) > 1e-9 || o.Liquidity != want[i].Liquidity {
+			t.Errorf("outcome %d: expected %+v, got %+v", i, want[i], o)
+		}
+	}
+}
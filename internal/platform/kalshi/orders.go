@@ -0,0 +1,365 @@
+package kalshi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"prediction-bot/pkg/types"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// kalshiOrder represents an order as returned by the Kalshi API.
+type kalshiOrder struct {
+	OrderID        string `json:"order_id"`
+	Ticker         string `json:"ticker"`
+	Side           string `json:"side"`   // "yes" or "no"
+	Action         string `json:"action"` // "buy" or "sell"
+	Status         string `json:"status"` // "resting", "canceled", "executed", "pending"
+	YesPrice       int    `json:"yes_price"`
+	NoPrice        int    `json:"no_price"`
+	InitialCount   int    `json:"initial_count"`
+	RemainingCount int    `json:"remaining_count"`
+	FillCount      int    `json:"fill_count"`
+	TakerFillCount int    `json:"taker_fill_count"`
+	MakerFillCount int    `json:"maker_fill_count"`
+	TakerFillCost  int    `json:"taker_fill_cost"` // cents
+	MakerFillCost  int    `json:"maker_fill_cost"` // cents
+	TakerFees      int    `json:"taker_fees"`      // cents
+	MakerFees      int    `json:"maker_fees"`      // cents
+	CreatedTime    string `json:"created_time"`
+}
+
+// ordersResponse represents the API response for listing orders.
+type ordersResponse struct {
+	Orders []kalshiOrder `json:"orders"`
+	Cursor string        `json:"cursor"`
+}
+
+// orderResponse represents the API response for creating an order.
+type orderResponse struct {
+	Order kalshiOrder `json:"order"`
+}
+
+// cancelOrderResponse represents the API response for cancelling an order.
+type cancelOrderResponse struct {
+	Order     kalshiOrder `json:"order"`
+	ReducedBy int         `json:"reduced_by"`
+}
+
+// PlaceOrder places an order on Kalshi.
+// Order.MarketID is the market ticker and Order.TokenID is the contract side
+// ("yes" or "no"). Size is the number of contracts and is rounded down.
+// When dryRun is true, it returns a simulated result without actually placing the order.
+// When dryRun is false, it submits the order to the Trade API for real execution.
+func (c *Client) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	// Validate order fields
+	if err := validateOrder(order); err != nil {
+		return types.OrderResult{}, fmt.Errorf("%w: %w", types.ErrOrderNotPlaced, err)
+	}
+
+	if dryRun {
+		return simulateOrder(order), nil
+	}
+
+	// LIVE TRADING: Submit order to Kalshi Trade API
+	log.Warn().
+		Str("ticker", order.MarketID).
+		Str("contract_side", order.TokenID).
+		Str("side", string(order.Side)).
+		Float64("price", order.Price).
+		Float64("size", order.Size).
+		Msg("⚠️ PLACING LIVE ORDER ON KALSHI")
+
+	// Build the order payload
+	payload := buildOrderPayload(order)
+
+	// Log the payload for audit trail
+	payloadJSON, _ := json.MarshalIndent(payload, "", "  ")
+	log.Debug().
+		RawJSON("payload", payloadJSON).
+		Msg("Order payload")
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("marshal order payload: %w", err)
+	}
+
+	// Submit to Trade API (request is signed by doRequest)
+	respBody, err := c.doRequest(ctx, "POST", "/portfolio/orders", body)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("ticker", order.MarketID).
+			Msg("Failed to place order")
+		return types.OrderResult{}, fmt.Errorf("place order: %w", rejected(err))
+	}
+
+	var resp orderResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return types.OrderResult{}, fmt.Errorf("parse order response: %w", err)
+	}
+	if resp.Order.OrderID == "" {
+		return types.OrderResult{}, fmt.Errorf("order rejected: no order ID in response")
+	}
+
+	result := convertKalshiOrder(resp.Order)
+	if result.CreatedAt.IsZero() {
+		result.CreatedAt = time.Now()
+	}
+
+	log.Info().
+		Str("order_id", result.OrderID).
+		Str("ticker", order.MarketID).
+		Str("side", string(order.Side)).
+		Str("status", string(result.Status)).
+		Float64("price", result.Price).
+		Float64("size", result.Size).
+		Float64("filled", result.Filled).
+		Msg("✅ Order placed successfully")
+
+	return result, nil
+}
+
+// rejected wraps an error placing an order in types.ErrOrderNotPlaced if
+// the API refused the request: a 4xx response is never executed.
+func rejected(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+		return fmt.Errorf("%w: %w", types.ErrOrderNotPlaced, err)
+	}
+	return err
+}
+
+// buildOrderPayload constructs the order payload for the Trade API.
+// Based on Kalshi API documentation:
+// https://trading-api.readme.io/reference/createorder
+func buildOrderPayload(order types.Order) map[string]interface{} {
+	side := strings.ToLower(order.TokenID)
+
+	payload := map[string]interface{}{
+		"ticker":          order.MarketID,
+		"client_order_id": uuid.New().String(),
+		"side":            side,
+		"action":          mapActionToAPI(order.Side),
+		"count":           int(math.Floor(order.Size)),
+		"type":            mapOrderTypeToAPI(order.Type),
+	}
+
+	// Prices are in cents for the contract side being traded
+	priceCents := int(math.Round(order.Price * 100))
+	if side == "no" {
+		payload["no_price"] = priceCents
+	} else {
+		payload["yes_price"] = priceCents
+	}
+
+	if tif := mapTimeInForceToAPI(order.TimeInForce); tif != "" {
+		payload["time_in_force"] = tif
+	}
+
+	return payload
+}
+
+// mapActionToAPI maps order side to the Kalshi action.
+func mapActionToAPI(side types.OrderSide) string {
+	if side == types.OrderSideSell {
+		return "sell"
+	}
+	return "buy"
+}
+
+// mapOrderTypeToAPI maps order type to the Kalshi order type.
+func mapOrderTypeToAPI(orderType types.OrderType) string {
+	if orderType == types.OrderTypeMarket {
+		return "market"
+	}
+	return "limit"
+}
+
+// mapTimeInForceToAPI maps time-in-force to Kalshi format.
+// Returns an empty string for GTC, which is the API default.
+func mapTimeInForceToAPI(tif types.TimeInForce) string {
+	switch tif {
+	case types.TimeInForceFOK:
+		return "fill_or_kill"
+	case types.TimeInForceIOC:
+		return "immediate_or_cancel"
+	default:
+		return ""
+	}
+}
+
+// validateOrder checks that all required fields are present and valid.
+func validateOrder(order types.Order) error {
+	if order.MarketID == "" {
+		return fmt.Errorf("order validation: MarketID (ticker) is required")
+	}
+
+	side := strings.ToLower(order.TokenID)
+	if side != "yes" && side != "no" {
+		return fmt.Errorf("order validation: TokenID must be \"yes\" or \"no\", got %q", order.TokenID)
+	}
+
+	if order.Size < 1 {
+		return fmt.Errorf("order validation: Size must be at least 1 contract")
+	}
+
+	// Kalshi limit prices are whole cents between 1 and 99
+	if order.Type != types.OrderTypeMarket && (order.Price < 0.01 || order.Price > 0.99) {
+		return fmt.Errorf("order validation: Price must be between 0.01 and 0.99")
+	}
+
+	return nil
+}
+
+// simulateOrder creates a simulated order result for dry-run mode.
+func simulateOrder(order types.Order) types.OrderResult {
+	return types.OrderResult{
+		OrderID:   fmt.Sprintf("dryrun-%s", uuid.New().String()),
+		MarketID:  order.MarketID,
+		TokenID:   strings.ToLower(order.TokenID),
+		Side:      order.Side,
+		Price:     order.Price,
+		Size:      math.Floor(order.Size),
+		Status:    types.OrderStatusSimulated,
+		IsDryRun:  true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// GetOpenOrders returns the resting orders for a market ticker.
+func (c *Client) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	path := BuildURL("/portfolio/orders", map[string]string{
+		"ticker": marketID,
+		"status": "resting",
+	})
+
+	body, err := c.doRequest(context.Background(), "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+
+	var response ordersResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parse orders response: %w", err)
+	}
+
+	results := make([]types.OrderResult, 0, len(response.Orders))
+	for _, ko := range response.Orders {
+		results = append(results, convertKalshiOrder(ko))
+	}
+
+	return results, nil
+}
+
+// GetOrderStatus returns the current state of an order by ID.
+func (c *Client) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	body, err := c.doRequest(context.Background(), "GET", "/portfolio/orders/"+orderID, nil)
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order status: %w", err)
+	}
+
+	var response orderResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return types.OrderResult{}, fmt.Errorf("parse order response: %w", err)
+	}
+	if response.Order.OrderID == "" {
+		return types.OrderResult{}, fmt.Errorf("order %s not found", orderID)
+	}
+
+	return convertKalshiOrder(response.Order), nil
+}
+
+// CancelOrder cancels a resting order by ID.
+func (c *Client) CancelOrder(orderID string) error {
+	body, err := c.doRequest(context.Background(), "DELETE", "/portfolio/orders/"+orderID, nil)
+	if err != nil {
+		return fmt.Errorf("cancel order: %w", err)
+	}
+
+	var response cancelOrderResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("parse cancel response: %w", err)
+	}
+
+	if response.Order.Status == "resting" {
+		return fmt.Errorf("order %s still resting after cancel", orderID)
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Int("reduced_by", response.ReducedBy).
+		Msg("Order cancelled")
+
+	return nil
+}
+
+// convertKalshiOrder maps a Kalshi order into the common OrderResult type.
+func convertKalshiOrder(ko kalshiOrder) types.OrderResult {
+	// Kalshi prices are in cents for the side being traded
+	price := float64(ko.YesPrice) / 100.0
+	if ko.Side == "no" {
+		price = float64(ko.NoPrice) / 100.0
+	}
+
+	side := types.OrderSideBuy
+	if ko.Action == "sell" {
+		side = types.OrderSideSell
+	}
+
+	size := ko.InitialCount
+	if size == 0 {
+		size = ko.RemainingCount + ko.FillCount
+	}
+	// Fills come from the reported fill counts only: a canceled or IOC
+	// order has no remaining count whether or not anything filled.
+	filled := ko.FillCount
+	if filled == 0 {
+		filled = ko.TakerFillCount + ko.MakerFillCount
+	}
+
+	var avgFillPrice float64
+	if fillCost := ko.TakerFillCost + ko.MakerFillCost; filled > 0 && fillCost > 0 {
+		avgFillPrice = float64(fillCost) / float64(filled) / 100.0
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, ko.CreatedTime)
+
+	return types.OrderResult{
+		OrderID:      ko.OrderID,
+		MarketID:     ko.Ticker,
+		TokenID:      ko.Side,
+		Side:         side,
+		Price:        price,
+		Size:         float64(size),
+		Filled:       float64(filled),
+		AvgFillPrice: avgFillPrice,
+		Fees:         float64(ko.TakerFees+ko.MakerFees) / 100.0,
+		Status:       mapOrderStatus(ko.Status, size, filled),
+		CreatedAt:    createdAt,
+	}
+}
+
+// mapOrderStatus maps a Kalshi order status to the common OrderStatus.
+func mapOrderStatus(status string, size, filled int) types.OrderStatus {
+	switch status {
+	case "resting":
+		if filled > 0 && filled < size {
+			return types.OrderStatusPartial
+		}
+		return types.OrderStatusOpen
+	case "executed":
+		return types.OrderStatusFilled
+	case "canceled", "cancelled":
+		return types.OrderStatusCancelled
+	default:
+		return types.OrderStatusPending
+	}
+}
@@ -0,0 +1,332 @@
+package kalshi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestConvertKalshiOrder_MapsPartialFill(t *testing.T) {
+	order := convertKalshiOrder(kalshiOrder{
+		OrderID:        "ord-1",
+		Ticker:         "KXBTC-25JAN20-T100000",
+		Side:           "no",
+		Action:         "buy",
+		Status:         "resting",
+		YesPrice:       12,
+		NoPrice:        88,
+		InitialCount:   10,
+		RemainingCount: 6,
+		FillCount:      4,
+	})
+
+	if order.Status != types.OrderStatusPartial {
+		t.Errorf("expected status %v, got %v", types.OrderStatusPartial, order.Status)
+	}
+	if order.Price != 0.88 {
+		t.Errorf("expected NO price 0.88, got %v", order.Price)
+	}
+	if order.Size != 10 || order.Filled != 4 {
+		t.Errorf("expected size 10 filled 4, got size %v filled %v", order.Size, order.Filled)
+	}
+	if !order.IsResting() {
+		t.Error("partially filled resting order should be resting")
+	}
+}
+
+func TestConvertKalshiOrder_CanceledWithoutFills(t *testing.T) {
+	order := convertKalshiOrder(kalshiOrder{
+		OrderID:        "ord-2",
+		Ticker:         "KXBTC-25JAN20-T100000",
+		Side:           "yes",
+		Action:         "sell",
+		Status:         "canceled",
+		YesPrice:       85,
+		InitialCount:   10,
+		RemainingCount: 0,
+		FillCount:      0,
+	})
+
+	if order.Status != types.OrderStatusCancelled {
+		t.Errorf("expected status %v, got %v", types.OrderStatusCancelled, order.Status)
+	}
+	if order.Size != 10 || order.Filled != 0 {
+		t.Errorf("expected size 10 filled 0, got size %v filled %v", order.Size, order.Filled)
+	}
+}
+
+func TestConvertKalshiOrder_SumsTakerAndMakerFillCounts(t *testing.T) {
+	order := convertKalshiOrder(kalshiOrder{
+		OrderID:        "ord-3",
+		Ticker:         "KXBTC-25JAN20-T100000",
+		Side:           "yes",
+		Action:         "buy",
+		Status:         "canceled",
+		YesPrice:       85,
+		InitialCount:   10,
+		TakerFillCount: 3,
+		MakerFillCount: 2,
+		TakerFillCost:  255,
+		MakerFillCost:  170,
+	})
+
+	if order.Filled != 5 {
+		t.Errorf("expected 5 filled, got %v", order.Filled)
+	}
+	if order.FillPrice() != 0.85 {
+		t.Errorf("expected average fill price 0.85, got %v", order.FillPrice())
+	}
+}
+
+func TestMapOrderStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		size   int
+		filled int
+		want   types.OrderStatus
+	}{
+		{"resting", 10, 0, types.OrderStatusOpen},
+		{"resting", 10, 3, types.OrderStatusPartial},
+		{"executed", 10, 10, types.OrderStatusFilled},
+		{"canceled", 10, 0, types.OrderStatusCancelled},
+		{"pending", 10, 0, types.OrderStatusPending},
+	}
+
+	for _, tt := range tests {
+		got := mapOrderStatus(tt.status, tt.size, tt.filled)
+		if got != tt.want {
+			t.Errorf("mapOrderStatus(%q, %d, %d) = %v, want %v", tt.status, tt.size, tt.filled, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceOrder_DryRun_ReturnsSimulatedResult(t *testing.T) {
+	client := NewClientWithCreds(Credentials{APIKey: "test-key"})
+
+	order := types.Order{
+		MarketID:    "KXBTC-25JAN20-T100000",
+		TokenID:     "YES",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.85,
+		Size:        10.7,
+		TimeInForce: types.TimeInForceGTC,
+	}
+
+	result, err := client.PlaceOrder(context.Background(), order, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder dry-run failed: %v", err)
+	}
+
+	if !result.IsDryRun || result.Status != types.OrderStatusSimulated {
+		t.Errorf("expected simulated dry-run result, got %+v", result)
+	}
+	if !strings.HasPrefix(result.OrderID, "dryrun-") {
+		t.Errorf("expected dryrun- order ID, got %s", result.OrderID)
+	}
+	if result.TokenID != "yes" {
+		t.Errorf("expected normalized side yes, got %s", result.TokenID)
+	}
+	if result.Size != 10 {
+		t.Errorf("expected size rounded down to 10 contracts, got %v", result.Size)
+	}
+	if time.Since(result.CreatedAt) > time.Minute {
+		t.Errorf("expected recent CreatedAt, got %v", result.CreatedAt)
+	}
+}
+
+func TestPlaceOrder_ValidatesOrderFields(t *testing.T) {
+	client := NewClientWithCreds(Credentials{APIKey: "test-key"})
+	valid := types.Order{
+		MarketID: "KXBTC",
+		TokenID:  "no",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    0.15,
+		Size:     5,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(o *types.Order)
+	}{
+		{"missing ticker", func(o *types.Order) { o.MarketID = "" }},
+		{"invalid side", func(o *types.Order) { o.TokenID = "maybe" }},
+		{"fractional contract", func(o *types.Order) { o.Size = 0.5 }},
+		{"price zero", func(o *types.Order) { o.Price = 0 }},
+		{"price one", func(o *types.Order) { o.Price = 1.0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := valid
+			tt.modify(&order)
+			if _, err := client.PlaceOrder(context.Background(), order, true); !errors.Is(err, types.ErrOrderNotPlaced) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := client.PlaceOrder(context.Background(), valid, true); err != nil {
+		t.Errorf("expected valid order to pass, got %v", err)
+	}
+}
+
+func TestBuildOrderPayload(t *testing.T) {
+	payload := buildOrderPayload(types.Order{
+		MarketID:    "KXETH-25JAN20-T4000",
+		TokenID:     "NO",
+		Side:        types.OrderSideSell,
+		Type:        types.OrderTypeLimit,
+		Price:       0.126,
+		Size:        7.9,
+		TimeInForce: types.TimeInForceIOC,
+	})
+
+	if payload["ticker"] != "KXETH-25JAN20-T4000" {
+		t.Errorf("unexpected ticker %v", payload["ticker"])
+	}
+	if payload["side"] != "no" || payload["action"] != "sell" || payload["type"] != "limit" {
+		t.Errorf("unexpected side/action/type: %v %v %v", payload["side"], payload["action"], payload["type"])
+	}
+	if payload["count"] != 7 {
+		t.Errorf("expected count 7, got %v", payload["count"])
+	}
+	if payload["no_price"] != 13 {
+		t.Errorf("expected no_price 13 cents, got %v", payload["no_price"])
+	}
+	if _, ok := payload["yes_price"]; ok {
+		t.Error("NO order should not set yes_price")
+	}
+	if payload["time_in_force"] != "immediate_or_cancel" {
+		t.Errorf("expected immediate_or_cancel, got %v", payload["time_in_force"])
+	}
+	if payload["client_order_id"] == "" {
+		t.Error("expected client_order_id")
+	}
+}
+
+func TestPlaceOrder_Live_SignsAndParsesResponse(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var gotPayload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != apiPath+"/portfolio/orders" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("KALSHI-ACCESS-KEY") != "test-key" || r.Header.Get("KALSHI-ACCESS-SIGNATURE") == "" {
+			t.Error("expected signed request headers")
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotPayload)
+
+		w.Write([]byte(`{"order":{"order_id":"ord-42","ticker":"KXBTC","side":"yes","action":"buy",
+			"status":"resting","yes_price":85,"no_price":15,"initial_count":10,"remaining_count":4,
+			"fill_count":6,"created_time":"2026-01-20T12:00:00Z"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{APIKey: "test-key", PrivateKey: string(keyPEM)})
+	client.baseURL = server.URL
+
+	result, err := client.PlaceOrder(context.Background(), types.Order{
+		MarketID: "KXBTC",
+		TokenID:  "yes",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    0.85,
+		Size:     10,
+	}, false)
+	if err != nil {
+		t.Fatalf("PlaceOrder live failed: %v", err)
+	}
+
+	if gotPayload["yes_price"] != float64(85) || gotPayload["count"] != float64(10) {
+		t.Errorf("unexpected payload sent: %v", gotPayload)
+	}
+	if result.OrderID != "ord-42" || result.IsDryRun {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Status != types.OrderStatusPartial || result.Filled != 6 {
+		t.Errorf("expected partial fill of 6, got %v filled %v", result.Status, result.Filled)
+	}
+}
+
+func TestPlaceOrder_Live_ReturnsAPIError(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"insufficient_balance"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{APIKey: "test-key", PrivateKey: string(keyPEM)})
+	client.baseURL = server.URL
+
+	_, err = client.PlaceOrder(context.Background(), types.Order{
+		MarketID: "KXBTC",
+		TokenID:  "yes",
+		Side:     types.OrderSideBuy,
+		Price:    0.85,
+		Size:     10,
+	}, false)
+	if err == nil || !strings.Contains(err.Error(), "insufficient_balance") {
+		t.Errorf("expected API error, got %v", err)
+	}
+	if !errors.Is(err, types.ErrOrderNotPlaced) {
+		t.Errorf("expected a 4xx response to mean the order wasn't placed, got %v", err)
+	}
+}
+
+func TestGetOrderStatus_ParsesOrder(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != apiPath+"/portfolio/orders/ord-42" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"order":{"order_id":"ord-42","ticker":"KXBTC","side":"yes","action":"buy",
+			"status":"canceled","yes_price":85,"no_price":15,"initial_count":10,"remaining_count":0,
+			"fill_count":6,"taker_fill_cost":504,"created_time":"2026-01-20T12:00:00Z"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{APIKey: "test-key", PrivateKey: string(keyPEM)})
+	client.baseURL = server.URL
+
+	result, err := client.GetOrderStatus("ord-42")
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if result.Status != types.OrderStatusCancelled || result.Filled != 6 {
+		t.Errorf("expected cancelled with 6 filled, got %v filled %v", result.Status, result.Filled)
+	}
+	if result.FillPrice() != 0.84 {
+		t.Errorf("expected average fill price 0.84, got %v", result.FillPrice())
+	}
+}
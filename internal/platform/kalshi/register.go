@@ -0,0 +1,14 @@
+package kalshi
+
+import "prediction-bot/internal/platform"
+
+func init() {
+	platform.Register("kalshi", func(opts platform.Options) (platform.Platform, error) {
+		client, err := NewClient()
+		if err != nil {
+			return nil, err
+		}
+		client.httpClient.Transport = opts.WrapTransport("kalshi", client.httpClient.Transport)
+		return client, nil
+	})
+}
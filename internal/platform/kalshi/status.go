@@ -0,0 +1,101 @@
+package kalshi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maintenanceWindow is a scheduled exchange maintenance period.
+type maintenanceWindow struct {
+	Start time.Time `json:"start_datetime"`
+	End   time.Time `json:"end_datetime"`
+}
+
+// GetPlatformStatus reports whether the exchange is accepting trades.
+// Trading is considered unavailable while the exchange reports trading
+// inactive and during a scheduled maintenance window. Maintenance is
+// reported scheduled for types.MaintenanceLeadTime before one starts.
+func (c *Client) GetPlatformStatus() (types.PlatformStatus, error) {
+	body, err := c.doPublicRequest(context.Background(), "GET", "/exchange/status")
+	if err != nil {
+		return types.PlatformStatus{}, fmt.Errorf("get exchange status: %w", err)
+	}
+
+	var response struct {
+		ExchangeActive      bool    `json:"exchange_active"`
+		TradingActive       bool    `json:"trading_active"`
+		EstimatedResumeTime *string `json:"exchange_estimated_resume_time"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return types.PlatformStatus{}, fmt.Errorf("parse status response: %w", err)
+	}
+
+	now := time.Now()
+	status := types.PlatformStatus{
+		Platform:      c.Name(),
+		TradingActive: response.ExchangeActive && response.TradingActive,
+		CheckedAt:     now,
+	}
+	if !response.ExchangeActive {
+		status.Message = "exchange inactive"
+	} else if !response.TradingActive {
+		status.Message = "trading inactive"
+	}
+	if response.EstimatedResumeTime != nil {
+		if t, err := time.Parse(time.RFC3339, *response.EstimatedResumeTime); err == nil {
+			status.ResumeAt = t
+		}
+	}
+
+	// The maintenance schedule is advisory; the live status above still
+	// applies if it cannot be fetched
+	windows, err := c.getMaintenanceWindows()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to get kalshi maintenance schedule")
+		return status, nil
+	}
+
+	for _, w := range windows {
+		if now.Before(w.Start.Add(-types.MaintenanceLeadTime)) || !now.Before(w.End) {
+			continue
+		}
+		if now.Before(w.Start) {
+			status.MaintenanceScheduled = true
+			status.Message = fmt.Sprintf("maintenance scheduled at %s", w.Start.Format(time.RFC3339))
+		} else {
+			status.Maintenance = true
+			status.Message = "scheduled maintenance in progress"
+		}
+		if w.End.After(status.ResumeAt) {
+			status.ResumeAt = w.End
+		}
+		break
+	}
+
+	return status, nil
+}
+
+// getMaintenanceWindows fetches the exchange's scheduled maintenance windows.
+func (c *Client) getMaintenanceWindows() ([]maintenanceWindow, error) {
+	body, err := c.doPublicRequest(context.Background(), "GET", "/exchange/schedule")
+	if err != nil {
+		return nil, fmt.Errorf("get exchange schedule: %w", err)
+	}
+
+	var response struct {
+		Schedule struct {
+			MaintenanceWindows []maintenanceWindow `json:"maintenance_windows"`
+		} `json:"schedule"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parse schedule response: %w", err)
+	}
+
+	return response.Schedule.MaintenanceWindows, nil
+}
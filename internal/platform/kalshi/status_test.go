@@ -0,0 +1,69 @@
+package kalshi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newStatusServer(t *testing.T, statusBody, scheduleBody string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiPath + "/exchange/status":
+			w.Write([]byte(statusBody))
+		case apiPath + "/exchange/schedule":
+			w.Write([]byte(scheduleBody))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetPlatformStatus_TradingInactive(t *testing.T) {
+	server := newStatusServer(t,
+		`{"exchange_active":true,"trading_active":false,"exchange_estimated_resume_time":"2026-01-21T13:00:00Z"}`,
+		`{"schedule":{"maintenance_windows":[]}}`)
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{})
+	client.baseURL = server.URL
+
+	status, err := client.GetPlatformStatus()
+	if err != nil {
+		t.Fatalf("GetPlatformStatus failed: %v", err)
+	}
+	if !status.Halted() || status.TradingActive {
+		t.Errorf("expected halted status, got %+v", status)
+	}
+	if !status.ResumeAt.Equal(time.Date(2026, 1, 21, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected resume time from response, got %v", status.ResumeAt)
+	}
+}
+
+func TestGetPlatformStatus_UpcomingMaintenance(t *testing.T) {
+	start := time.Now().Add(10 * time.Minute).UTC().Truncate(time.Second)
+	end := start.Add(time.Hour)
+	server := newStatusServer(t,
+		`{"exchange_active":true,"trading_active":true}`,
+		fmt.Sprintf(`{"schedule":{"maintenance_windows":[{"start_datetime":%q,"end_datetime":%q}]}}`,
+			start.Format(time.RFC3339), end.Format(time.RFC3339)))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{})
+	client.baseURL = server.URL
+
+	status, err := client.GetPlatformStatus()
+	if err != nil {
+		t.Fatalf("GetPlatformStatus failed: %v", err)
+	}
+	if status.Halted() || !status.MaintenanceScheduled || !status.EntriesPaused() {
+		t.Errorf("expected active trading with upcoming maintenance, got %+v", status)
+	}
+	if !status.ResumeAt.Equal(end) {
+		t.Errorf("expected resume at %v, got %v", end, status.ResumeAt)
+	}
+}
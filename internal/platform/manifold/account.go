@@ -0,0 +1,77 @@
+package manifold
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// GetPositions returns the user's positions, aggregated from their recent
+// bets. YES and NO shares on a market offset each other, as they do on
+// Manifold.
+func (c *Client) GetPositions() ([]types.Position, error) {
+	userID, err := c.currentUserID()
+	if err != nil {
+		return nil, fmt.Errorf("get positions: %w", err)
+	}
+
+	bets, err := c.listBets(map[string]string{"userId": userID, "limit": "1000"})
+	if err != nil {
+		return nil, fmt.Errorf("get positions: %w", err)
+	}
+
+	type holding struct {
+		shares    float64 // YES shares minus NO shares
+		exposure  float64
+		traded    float64
+		fees      float64
+		restingBy float64
+	}
+	holdings := make(map[string]*holding)
+	for _, bet := range bets {
+		h, ok := holdings[bet.ContractID]
+		if !ok {
+			h = &holding{}
+			holdings[bet.ContractID] = h
+		}
+		if bet.IsCancelled && bet.Shares == 0 {
+			continue
+		}
+		if bet.Outcome == "NO" {
+			h.shares -= bet.Shares
+		} else {
+			h.shares += bet.Shares
+		}
+		h.exposure += bet.Amount
+		h.traded += math.Abs(bet.Shares)
+		h.fees += bet.Fees.CreatorFee + bet.Fees.PlatformFee + bet.Fees.LiquidityFee
+		if result := convertBet(bet); result.IsResting() {
+			h.restingBy += result.Size - result.Filled
+		}
+	}
+
+	now := time.Now()
+	positions := make([]types.Position, 0, len(holdings))
+	for marketID, h := range holdings {
+		quantity := int(math.Round(h.shares))
+		if quantity == 0 && h.restingBy == 0 {
+			continue
+		}
+		positions = append(positions, types.Position{
+			Platform:         "manifold",
+			MarketTicker:     marketID,
+			Quantity:         float64(quantity),
+			MarketExposure:   h.exposure,
+			TotalTraded:      int(math.Round(h.traded)),
+			FeesPaid:         h.fees,
+			RestingOrdersQty: int(math.Round(h.restingBy)),
+			Timestamp:        now,
+		})
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].MarketTicker < positions[j].MarketTicker })
+
+	return positions, nil
+}
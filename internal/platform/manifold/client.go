@@ -0,0 +1,199 @@
+// Package manifold is a client for the Manifold Markets API. Manifold trades
+// play money (mana), so strategies can be tried against live markets before
+// risking real funds on other platforms. Amounts in mana are reported as
+// dollars.
+package manifold
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// baseURL is the Manifold API base URL (host only)
+	baseURL = "https://api.manifold.markets"
+	// apiPath is the API version prefix
+	apiPath = "/v0"
+)
+
+// Client is a Manifold Markets API client.
+type Client struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+
+	mu     sync.Mutex
+	userID string            // Authenticated user, looked up once
+	orders map[string]string // Order (bet) ID to the market it was placed on
+}
+
+// APIError is returned when the Manifold API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// manifoldUser represents the authenticated user as returned by /me.
+type manifoldUser struct {
+	ID       string  `json:"id"`
+	Username string  `json:"username"`
+	Balance  float64 `json:"balance"`
+}
+
+// NewClient creates a new Manifold client from the MANIFOLD_API_KEY
+// environment variable.
+func NewClient() (*Client, error) {
+	apiKey := os.Getenv("MANIFOLD_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("missing Manifold credentials: MANIFOLD_API_KEY required")
+	}
+	return NewClientWithKey(apiKey), nil
+}
+
+// NewClientWithKey creates a new Manifold client with an explicit API key.
+// Without a key the client can only read public market data, which is all
+// dry runs need.
+func NewClientWithKey(apiKey string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		orders:  make(map[string]string),
+	}
+}
+
+// Name returns the platform identifier.
+func (c *Client) Name() string {
+	return "manifold"
+}
+
+// doRequest performs a request to the Manifold API, authenticated when the
+// client has an API key. A non-nil payload is sent as JSON.
+func (c *Client) doRequest(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+apiPath+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Key "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	return respBody, nil
+}
+
+// me returns the authenticated user.
+func (c *Client) me() (*manifoldUser, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("manifold account access requires MANIFOLD_API_KEY")
+	}
+
+	body, err := c.doRequest(context.Background(), "GET", "/me", nil)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	var user manifoldUser
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("parse user response: %w", err)
+	}
+	return &user, nil
+}
+
+// currentUserID returns the authenticated user's ID, looked up on first use.
+func (c *Client) currentUserID() (string, error) {
+	c.mu.Lock()
+	userID := c.userID
+	c.mu.Unlock()
+	if userID != "" {
+		return userID, nil
+	}
+
+	user, err := c.me()
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.userID = user.ID
+	c.mu.Unlock()
+	return user.ID, nil
+}
+
+// GetBalance implements platform.Platform interface.
+// Returns the mana balance.
+func (c *Client) GetBalance() (float64, error) {
+	user, err := c.me()
+	if err != nil {
+		return 0, err
+	}
+	return user.Balance, nil
+}
+
+// VerifyCredentials checks that the API key is accepted.
+func (c *Client) VerifyCredentials() error {
+	if _, err := c.currentUserID(); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("MANIFOLD_API_KEY rejected: %w", err)
+		}
+		return fmt.Errorf("verify manifold credentials: %w", err)
+	}
+	return nil
+}
+
+// buildURL appends the non-empty query parameters to path.
+func buildURL(path string, params map[string]string) string {
+	values := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			values.Add(k, v)
+		}
+	}
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
@@ -0,0 +1,215 @@
+package manifold
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// manifoldMarket represents a market from the Manifold API.
+type manifoldMarket struct {
+	ID             string  `json:"id"`
+	Question       string  `json:"question"`
+	Slug           string  `json:"slug"`
+	URL            string  `json:"url"`
+	OutcomeType    string  `json:"outcomeType"` // "BINARY", "MULTIPLE_CHOICE", ...
+	Mechanism      string  `json:"mechanism"`   // "cpmm-1" for binary markets
+	Probability    float64 `json:"probability"`
+	TotalLiquidity float64 `json:"totalLiquidity"`
+	Volume         float64 `json:"volume"`
+	Volume24Hours  float64 `json:"volume24Hours"`
+	CloseTime      int64   `json:"closeTime"` // Unix milliseconds
+	IsResolved     bool    `json:"isResolved"`
+	Resolution     string  `json:"resolution"` // "YES", "NO", "MKT" or "CANCEL"
+}
+
+// ListMarkets returns the binary markets matching the filter criteria.
+// Manifold's other market types are skipped.
+func (c *Client) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
+	params := map[string]string{
+		"contractType": "BINARY",
+		"sort":         "liquidity",
+	}
+	if filter.IsActive != nil && *filter.IsActive {
+		params["filter"] = "open"
+	}
+	if filter.Limit > 0 {
+		params["limit"] = strconv.Itoa(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		params["offset"] = strconv.Itoa(filter.Offset)
+	}
+
+	body, err := c.doRequest(ctx, "GET", buildURL("/search-markets", params), nil)
+	if err != nil {
+		return nil, fmt.Errorf("list markets: %w", err)
+	}
+
+	var response []manifoldMarket
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parse markets response: %w", err)
+	}
+
+	now := time.Now()
+	markets := make([]types.Market, 0, len(response))
+	for _, mm := range response {
+		if mm.OutcomeType != "BINARY" {
+			continue
+		}
+		markets = append(markets, convertMarket(mm, now))
+	}
+
+	return markets, nil
+}
+
+// GetMarket fetches a single market by ID.
+func (c *Client) GetMarket(marketID string) (*types.Market, error) {
+	mm, err := c.getMarket(marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	market := convertMarket(*mm, time.Now())
+	return &market, nil
+}
+
+func (c *Client) getMarket(marketID string) (*manifoldMarket, error) {
+	body, err := c.doRequest(context.Background(), "GET", "/market/"+marketID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get market: %w", err)
+	}
+
+	var mm manifoldMarket
+	if err := json.Unmarshal(body, &mm); err != nil {
+		return nil, fmt.Errorf("parse market response: %w", err)
+	}
+	return &mm, nil
+}
+
+// GetResolution reports whether a market has resolved and which side won.
+// Markets resolved to a probability (MKT) or cancelled (N/A) are reported
+// as unresolved.
+func (c *Client) GetResolution(marketID string) (types.Resolution, error) {
+	mm, err := c.getMarket(marketID)
+	if err != nil {
+		return types.Resolution{}, err
+	}
+
+	resolution := types.Resolution{MarketID: marketID}
+	if mm.IsResolved && (mm.Resolution == "YES" || mm.Resolution == "NO") {
+		resolution.Resolved = true
+		resolution.Outcome = mm.Resolution
+	}
+	return resolution, nil
+}
+
+// GetOrderBook returns the book for a market's YES or NO token, built from
+// the market's open limit orders and its automated market maker (AMM). The
+// AMM is shown as one level on each side at the market probability, sized
+// by the pool's liquidity; orders that take a large share of it move the
+// price further than the book shows.
+func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	marketID, outcome, err := parseTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	mm, err := c.getMarket(marketID)
+	if err != nil {
+		return nil, fmt.Errorf("get order book: %w", err)
+	}
+	limits, err := c.listBets(map[string]string{"contractId": marketID, "kinds": "open-limit"})
+	if err != nil {
+		return nil, fmt.Errorf("get order book: %w", err)
+	}
+
+	// Build the YES book: YES limit orders bid for YES, NO limit orders
+	// at probability p offer YES at p
+	book := &types.OrderBook{MarketID: marketID, TokenID: tokenID}
+	if mm.Probability > 0 && mm.TotalLiquidity > 0 {
+		amm := types.Level{Price: mm.Probability, Size: mm.TotalLiquidity}
+		book.Bids = append(book.Bids, amm)
+		book.Asks = append(book.Asks, amm)
+	}
+	for _, bet := range limits {
+		if bet.IsFilled || bet.IsCancelled || bet.LimitProb <= 0 {
+			continue
+		}
+		remaining := bet.OrderAmount - bet.Amount
+		if bet.Outcome == "YES" {
+			book.Bids = append(book.Bids, types.Level{Price: bet.LimitProb, Size: remaining / bet.LimitProb})
+		} else if bet.LimitProb < 1 {
+			book.Asks = append(book.Asks, types.Level{Price: bet.LimitProb, Size: remaining / (1 - bet.LimitProb)})
+		}
+	}
+
+	// The NO book is the YES book seen from the other side
+	if outcome == "NO" {
+		bids, asks := book.Asks, book.Bids
+		book.Bids, book.Asks = make([]types.Level, len(bids)), make([]types.Level, len(asks))
+		for i, l := range bids {
+			book.Bids[i] = types.Level{Price: 1 - l.Price, Size: l.Size}
+		}
+		for i, l := range asks {
+			book.Asks[i] = types.Level{Price: 1 - l.Price, Size: l.Size}
+		}
+	}
+
+	sort.SliceStable(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.SliceStable(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+	return book, nil
+}
+
+// convertMarket converts a Manifold market to the common Market type. Its
+// YES and NO sides are traded as tokens named "<market ID>:YES" and
+// "<market ID>:NO".
+func convertMarket(mm manifoldMarket, now time.Time) types.Market {
+	var endDate time.Time
+	if mm.CloseTime > 0 {
+		endDate = time.UnixMilli(mm.CloseTime)
+	}
+	closed := mm.IsResolved || (!endDate.IsZero() && !endDate.After(now))
+
+	return types.Market{
+		ID:              mm.ID,
+		Platform:        "manifold",
+		ConditionID:     mm.ID,
+		Title:           mm.Question,
+		Description:     mm.URL,
+		EndDate:         endDate,
+		Volume:          mm.Volume24Hours,
+		Liquidity:       mm.TotalLiquidity,
+		Active:          !closed,
+		Closed:          closed,
+		OutcomeYesPrice: mm.Probability,
+		OutcomeNoPrice:  1 - mm.Probability,
+		Tokens: []types.Token{
+			{TokenID: tokenID(mm.ID, "YES"), Outcome: "Yes", Price: mm.Probability},
+			{TokenID: tokenID(mm.ID, "NO"), Outcome: "No", Price: 1 - mm.Probability},
+		},
+	}
+}
+
+// tokenID returns the token ID of a market's YES or NO side.
+func tokenID(marketID, outcome string) string {
+	return marketID + ":" + outcome
+}
+
+// parseTokenID splits a token ID into its market ID and outcome.
+func parseTokenID(tokenID string) (marketID, outcome string, err error) {
+	i := strings.LastIndex(tokenID, ":")
+	if i <= 0 {
+		return "", "", fmt.Errorf("invalid manifold token ID %q", tokenID)
+	}
+	marketID, outcome = tokenID[:i], strings.ToUpper(tokenID[i+1:])
+	if outcome != "YES" && outcome != "NO" {
+		return "", "", fmt.Errorf("invalid manifold token ID %q: outcome must be YES or NO", tokenID)
+	}
+	return marketID, outcome, nil
+}
@@ -0,0 +1,148 @@
+package manifold
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestConvertMarket(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	closeTime := now.Add(48 * time.Hour)
+
+	market := convertMarket(manifoldMarket{
+		ID:             "abc123",
+		Question:       "Will Bitcoin be above $100,000 on January 12?",
+		OutcomeType:    "BINARY",
+		Probability:    0.62,
+		TotalLiquidity: 500,
+		Volume24Hours:  1200,
+		CloseTime:      closeTime.UnixMilli(),
+	}, now)
+
+	if market.Platform != "manifold" || market.ID != "abc123" {
+		t.Errorf("unexpected identity %s/%s", market.Platform, market.ID)
+	}
+	if !market.EndDate.Equal(closeTime) {
+		t.Errorf("expected end date %v, got %v", closeTime, market.EndDate)
+	}
+	if !market.Active || market.Closed {
+		t.Error("expected open market to be active")
+	}
+	if market.OutcomeYesPrice != 0.62 || math.Abs(market.OutcomeNoPrice-0.38) > 1e-9 {
+		t.Errorf("expected prices 0.62/0.38, got %v/%v", market.OutcomeYesPrice, market.OutcomeNoPrice)
+	}
+	if len(market.Tokens) != 2 || market.Tokens[0].TokenID != "abc123:YES" || market.Tokens[1].TokenID != "abc123:NO" {
+		t.Errorf("unexpected tokens %+v", market.Tokens)
+	}
+
+	closed := convertMarket(manifoldMarket{ID: "old", CloseTime: now.Add(-time.Hour).UnixMilli()}, now)
+	if closed.Active || !closed.Closed {
+		t.Error("expected market past its close time to be closed")
+	}
+}
+
+func TestParseTokenID(t *testing.T) {
+	marketID, outcome, err := parseTokenID("abc123:no")
+	if err != nil || marketID != "abc123" || outcome != "NO" {
+		t.Errorf("expected abc123/NO, got %s/%s (%v)", marketID, outcome, err)
+	}
+
+	for _, bad := range []string{"abc123", ":YES", "abc123:MAYBE"} {
+		if _, _, err := parseTokenID(bad); err == nil {
+			t.Errorf("expected error for token %q", bad)
+		}
+	}
+}
+
+func TestClient_GetOrderBook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiPath + "/market/abc123":
+			w.Write([]byte(`{"id":"abc123","outcomeType":"BINARY","probability":0.6,"totalLiquidity":100}`))
+		case apiPath + "/bets":
+			if r.URL.Query().Get("kinds") != "open-limit" {
+				t.Errorf("expected open limit orders to be requested, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[
+				{"id":"b1","contractId":"abc123","outcome":"YES","limitProb":0.5,"orderAmount":10,"amount":0},
+				{"id":"b2","contractId":"abc123","outcome":"NO","limitProb":0.75,"orderAmount":5,"amount":0},
+				{"id":"b3","contractId":"abc123","outcome":"YES","limitProb":0.55,"orderAmount":10,"amount":0,"isCancelled":true}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithKey("")
+	client.baseURL = server.URL
+
+	yes, err := client.GetOrderBook(context.Background(), "abc123:YES")
+	if err != nil {
+		t.Fatalf("GetOrderBook failed: %v", err)
+	}
+	// AMM at 0.60 on both sides, a YES bid at 0.50 and a YES offer at 0.75
+	wantBids := []types.Level{{Price: 0.6, Size: 100}, {Price: 0.5, Size: 20}}
+	wantAsks := []types.Level{{Price: 0.6, Size: 100}, {Price: 0.75, Size: 20}}
+	assertLevels(t, "YES bids", yes.Bids, wantBids)
+	assertLevels(t, "YES asks", yes.Asks, wantAsks)
+
+	no, err := client.GetOrderBook(context.Background(), "abc123:NO")
+	if err != nil {
+		t.Fatalf("GetOrderBook failed: %v", err)
+	}
+	assertLevels(t, "NO bids", no.Bids, []types.Level{{Price: 0.4, Size: 100}, {Price: 0.25, Size: 20}})
+	assertLevels(t, "NO asks", no.Asks, []types.Level{{Price: 0.4, Size: 100}, {Price: 0.5, Size: 20}})
+}
+
+func TestClient_GetResolution(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiPath + "/market/won":
+			w.Write([]byte(`{"id":"won","isResolved":true,"resolution":"NO"}`))
+		case apiPath + "/market/cancelled":
+			w.Write([]byte(`{"id":"cancelled","isResolved":true,"resolution":"CANCEL"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithKey("")
+	client.baseURL = server.URL
+
+	resolution, err := client.GetResolution("won")
+	if err != nil {
+		t.Fatalf("GetResolution failed: %v", err)
+	}
+	if !resolution.Resolved || resolution.Outcome != "NO" {
+		t.Errorf("expected resolved NO, got %+v", resolution)
+	}
+
+	resolution, err = client.GetResolution("cancelled")
+	if err != nil {
+		t.Fatalf("GetResolution failed: %v", err)
+	}
+	if resolution.Resolved {
+		t.Errorf("expected cancelled market to be unresolved, got %+v", resolution)
+	}
+}
+
+func assertLevels(t *testing.T, name string, got, want []types.Level) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %v, got %v", name, want, got)
+	}
+	for i := range want {
+		if math.Abs(got[i].Price-want[i].Price) > 1e-9 || math.Abs(got[i].Size-want[i].Size) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+			return
+		}
+	}
+}
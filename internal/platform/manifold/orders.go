@@ -0,0 +1,328 @@
+package manifold
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"prediction-bot/pkg/types"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// manifoldBet represents a bet (Manifold's order) as returned by the API.
+// Amounts are in mana; Shares is negative for sales.
+type manifoldBet struct {
+	ID          string      `json:"id"`
+	BetID       string      `json:"betId"` // Set instead of ID when placing a bet
+	ContractID  string      `json:"contractId"`
+	Outcome     string      `json:"outcome"` // "YES" or "NO"
+	Amount      float64     `json:"amount"`  // Mana spent so far
+	Shares      float64     `json:"shares"`  // Shares bought so far
+	LimitProb   float64     `json:"limitProb"`
+	OrderAmount float64     `json:"orderAmount"` // Mana a limit order may spend in total
+	IsFilled    bool        `json:"isFilled"`
+	IsCancelled bool        `json:"isCancelled"`
+	Fees        manifoldFee `json:"fees"`
+	CreatedTime int64       `json:"createdTime"` // Unix milliseconds
+}
+
+// manifoldFee is the fee breakdown of a bet.
+type manifoldFee struct {
+	CreatorFee   float64 `json:"creatorFee"`
+	PlatformFee  float64 `json:"platformFee"`
+	LiquidityFee float64 `json:"liquidityFee"`
+}
+
+// PlaceOrder places a bet on Manifold.
+// Order.TokenID is "<market ID>:YES" or "<market ID>:NO" and Size is the
+// number of shares. Buys spend Size*Price mana, as a limit order at Price
+// unless the order is a market order. Manifold limit orders rest until
+// cancelled, so the unfilled rest of an IOC or FOK order is cancelled right
+// away; a FOK order may therefore fill partially. Sells always execute
+// against the market maker at the current price.
+// When dryRun is true, it returns a simulated result without placing the bet.
+func (c *Client) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	marketID, outcome, err := validateOrder(order)
+	if err != nil {
+		return types.OrderResult{}, err
+	}
+
+	if dryRun {
+		return simulateOrder(order), nil
+	}
+
+	log.Warn().
+		Str("market_id", marketID).
+		Str("outcome", outcome).
+		Str("side", string(order.Side)).
+		Float64("price", order.Price).
+		Float64("size", order.Size).
+		Msg("⚠️ PLACING LIVE ORDER ON MANIFOLD")
+
+	var respBody []byte
+	if order.Side == types.OrderSideSell {
+		respBody, err = c.doRequest(ctx, "POST", "/market/"+marketID+"/sell", map[string]interface{}{
+			"outcome": outcome,
+			"shares":  order.Size,
+		})
+	} else {
+		respBody, err = c.doRequest(ctx, "POST", "/bet", buildBetPayload(order, marketID, outcome))
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("market_id", marketID).
+			Msg("Failed to place order")
+		return types.OrderResult{}, fmt.Errorf("place order: %w", err)
+	}
+
+	var bet manifoldBet
+	if err := json.Unmarshal(respBody, &bet); err != nil {
+		return types.OrderResult{}, fmt.Errorf("parse order response: %w", err)
+	}
+	result := convertBet(bet)
+	if result.OrderID == "" {
+		return types.OrderResult{}, fmt.Errorf("order rejected: no bet ID in response")
+	}
+	result.MarketID = marketID
+	result.Side = order.Side
+	result.Price = order.Price
+	result.Size = order.Size
+	if result.CreatedAt.IsZero() {
+		result.CreatedAt = time.Now()
+	}
+
+	c.mu.Lock()
+	c.orders[result.OrderID] = marketID
+	c.mu.Unlock()
+
+	if result.IsResting() && order.TimeInForce != types.TimeInForceGTC && order.TimeInForce != "" {
+		if err := c.CancelOrder(result.OrderID); err != nil {
+			return result, fmt.Errorf("cancel unfilled %s order: %w", order.TimeInForce, err)
+		}
+		result.Status = types.OrderStatusCancelled
+	}
+
+	log.Info().
+		Str("order_id", result.OrderID).
+		Str("market_id", marketID).
+		Str("side", string(order.Side)).
+		Str("status", string(result.Status)).
+		Float64("price", result.Price).
+		Float64("size", result.Size).
+		Float64("filled", result.Filled).
+		Msg("✅ Order placed successfully")
+
+	return result, nil
+}
+
+// buildBetPayload constructs the payload of a buy. Limit prices are whole
+// percentages, as the API requires.
+func buildBetPayload(order types.Order, marketID, outcome string) map[string]interface{} {
+	payload := map[string]interface{}{
+		"contractId": marketID,
+		"outcome":    outcome,
+		"amount":     math.Round(order.Size*order.Price*100) / 100,
+	}
+	if order.Type != types.OrderTypeMarket {
+		// limitProb is the YES probability the bet may move the market to
+		limitProb := order.Price
+		if outcome == "NO" {
+			limitProb = 1 - order.Price
+		}
+		payload["limitProb"] = math.Round(limitProb*100) / 100
+	}
+	return payload
+}
+
+// validateOrder checks the order and returns the market and outcome its
+// token trades.
+func validateOrder(order types.Order) (marketID, outcome string, err error) {
+	marketID, outcome, err = parseTokenID(order.TokenID)
+	if err != nil {
+		return "", "", fmt.Errorf("order validation: %w", err)
+	}
+	if order.MarketID != "" && order.MarketID != marketID {
+		return "", "", fmt.Errorf("order validation: token %q is not on market %q", order.TokenID, order.MarketID)
+	}
+	if order.Size <= 0 {
+		return "", "", fmt.Errorf("order validation: Size must be positive")
+	}
+	if order.Side == types.OrderSideBuy && (order.Price < 0.01 || order.Price > 0.99) {
+		return "", "", fmt.Errorf("order validation: Price must be between 0.01 and 0.99")
+	}
+	return marketID, outcome, nil
+}
+
+// simulateOrder creates a simulated order result for dry-run mode.
+func simulateOrder(order types.Order) types.OrderResult {
+	return types.OrderResult{
+		OrderID:   fmt.Sprintf("dryrun-%s", uuid.New().String()),
+		MarketID:  order.MarketID,
+		TokenID:   order.TokenID,
+		Side:      order.Side,
+		Price:     order.Price,
+		Size:      order.Size,
+		Status:    types.OrderStatusSimulated,
+		IsDryRun:  true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// GetOpenOrders returns the user's resting limit orders on a market.
+func (c *Client) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	userID, err := c.currentUserID()
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+
+	bets, err := c.listBets(map[string]string{
+		"contractId": marketID,
+		"userId":     userID,
+		"kinds":      "open-limit",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+
+	results := make([]types.OrderResult, 0, len(bets))
+	for _, bet := range bets {
+		if result := convertBet(bet); result.IsResting() {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// GetOrderStatus returns the current state of a bet placed by this client.
+// Manifold can't look bets up by ID alone, so the bet is found among the
+// user's bets on the market it was placed on.
+func (c *Client) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	c.mu.Lock()
+	marketID, ok := c.orders[orderID]
+	c.mu.Unlock()
+	if !ok {
+		return types.OrderResult{}, fmt.Errorf("order %s not placed by this client", orderID)
+	}
+
+	userID, err := c.currentUserID()
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order status: %w", err)
+	}
+
+	bets, err := c.listBets(map[string]string{"contractId": marketID, "userId": userID})
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order status: %w", err)
+	}
+	for _, bet := range bets {
+		if bet.ID == orderID {
+			return convertBet(bet), nil
+		}
+	}
+	return types.OrderResult{}, fmt.Errorf("order %s not found", orderID)
+}
+
+// CancelOrder cancels a resting limit order by ID.
+func (c *Client) CancelOrder(orderID string) error {
+	body, err := c.doRequest(context.Background(), "POST", "/bet/cancel/"+orderID, nil)
+	if err != nil {
+		return fmt.Errorf("cancel order: %w", err)
+	}
+
+	var bet manifoldBet
+	if err := json.Unmarshal(body, &bet); err != nil {
+		return fmt.Errorf("parse cancel response: %w", err)
+	}
+	if !bet.IsCancelled && !bet.IsFilled {
+		return fmt.Errorf("order %s still resting after cancel", orderID)
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Msg("Order cancelled")
+
+	return nil
+}
+
+// listBets returns the bets matching params, newest first.
+func (c *Client) listBets(params map[string]string) ([]manifoldBet, error) {
+	body, err := c.doRequest(context.Background(), "GET", buildURL("/bets", params), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var bets []manifoldBet
+	if err := json.Unmarshal(body, &bets); err != nil {
+		return nil, fmt.Errorf("parse bets response: %w", err)
+	}
+	return bets, nil
+}
+
+// convertBet maps a Manifold bet into the common OrderResult type.
+func convertBet(bet manifoldBet) types.OrderResult {
+	id := bet.ID
+	if id == "" {
+		id = bet.BetID
+	}
+
+	side := types.OrderSideBuy
+	if bet.Shares < 0 || bet.Amount < 0 {
+		side = types.OrderSideSell
+	}
+
+	// A limit order's price is its limit for the outcome bought
+	filled := math.Abs(bet.Shares)
+	var avgFillPrice float64
+	if filled > 0 {
+		avgFillPrice = math.Abs(bet.Amount) / filled
+	}
+	price := avgFillPrice
+	size := filled
+	if bet.LimitProb > 0 {
+		price = bet.LimitProb
+		if bet.Outcome == "NO" {
+			price = 1 - bet.LimitProb
+		}
+		if bet.OrderAmount > 0 && price > 0 {
+			size = filled + (bet.OrderAmount-math.Abs(bet.Amount))/price
+		}
+	}
+
+	var createdAt time.Time
+	if bet.CreatedTime > 0 {
+		createdAt = time.UnixMilli(bet.CreatedTime)
+	}
+
+	return types.OrderResult{
+		OrderID:      id,
+		MarketID:     bet.ContractID,
+		TokenID:      tokenID(bet.ContractID, bet.Outcome),
+		Side:         side,
+		Price:        price,
+		Size:         size,
+		Filled:       filled,
+		AvgFillPrice: avgFillPrice,
+		Fees:         bet.Fees.CreatorFee + bet.Fees.PlatformFee + bet.Fees.LiquidityFee,
+		Status:       mapBetStatus(bet),
+		CreatedAt:    createdAt,
+	}
+}
+
+// mapBetStatus maps a bet's flags to the common OrderStatus. Bets without a
+// limit execute immediately.
+func mapBetStatus(bet manifoldBet) types.OrderStatus {
+	switch {
+	case bet.IsCancelled:
+		return types.OrderStatusCancelled
+	case bet.IsFilled || bet.LimitProb == 0:
+		return types.OrderStatusFilled
+	case bet.Shares != 0:
+		return types.OrderStatusPartial
+	default:
+		return types.OrderStatusOpen
+	}
+}
@@ -0,0 +1,154 @@
+package manifold
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestPlaceOrder_DryRun(t *testing.T) {
+	client := NewClientWithKey("")
+
+	result, err := client.PlaceOrder(context.Background(), types.Order{
+		MarketID: "abc123",
+		TokenID:  "abc123:YES",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    0.6,
+		Size:     10,
+	}, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusSimulated || !result.IsDryRun {
+		t.Errorf("expected simulated dry-run order, got %+v", result)
+	}
+	if !strings.HasPrefix(result.OrderID, "dryrun-") {
+		t.Errorf("expected dry-run order ID, got %s", result.OrderID)
+	}
+}
+
+func TestPlaceOrder_Validation(t *testing.T) {
+	client := NewClientWithKey("")
+
+	tests := []types.Order{
+		{TokenID: "abc123", Side: types.OrderSideBuy, Price: 0.5, Size: 1},
+		{MarketID: "other", TokenID: "abc123:YES", Side: types.OrderSideBuy, Price: 0.5, Size: 1},
+		{TokenID: "abc123:YES", Side: types.OrderSideBuy, Price: 0.5, Size: 0},
+		{TokenID: "abc123:YES", Side: types.OrderSideBuy, Price: 1.2, Size: 1},
+	}
+	for _, order := range tests {
+		if _, err := client.PlaceOrder(context.Background(), order, true); err == nil {
+			t.Errorf("expected validation error for %+v", order)
+		}
+	}
+}
+
+func TestPlaceOrder_IOCCancelsUnfilledRest(t *testing.T) {
+	var bet, cancelled map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Key secret" {
+			t.Errorf("expected API key authorization, got %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case apiPath + "/bet":
+			json.NewDecoder(r.Body).Decode(&bet)
+			// 4 of 10 NO shares filled at 0.35
+			w.Write([]byte(`{"betId":"bet-1","contractId":"abc123","outcome":"NO","amount":1.4,"shares":4,
+				"limitProb":0.65,"orderAmount":4,"fees":{"platformFee":0.02}}`))
+		case apiPath + "/bet/cancel/bet-1":
+			cancelled = map[string]interface{}{}
+			w.Write([]byte(`{"id":"bet-1","isCancelled":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithKey("secret")
+	client.baseURL = server.URL
+
+	result, err := client.PlaceOrder(context.Background(), types.Order{
+		MarketID:    "abc123",
+		TokenID:     "abc123:NO",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.4,
+		Size:        10,
+		TimeInForce: types.TimeInForceIOC,
+	}, false)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	if bet["contractId"] != "abc123" || bet["outcome"] != "NO" || bet["amount"] != 4.0 || bet["limitProb"] != 0.6 {
+		t.Errorf("unexpected bet payload %v", bet)
+	}
+	if cancelled == nil {
+		t.Error("expected the unfilled rest to be cancelled")
+	}
+	if result.OrderID != "bet-1" || result.Status != types.OrderStatusCancelled {
+		t.Errorf("expected cancelled bet-1, got %+v", result)
+	}
+	if result.Filled != 4 || result.AvgFillPrice != 0.35 || result.Fees != 0.02 {
+		t.Errorf("expected 4 filled at 0.35 with 0.02 fees, got %+v", result)
+	}
+}
+
+func TestGetOrderStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiPath + "/me":
+			w.Write([]byte(`{"id":"user-1","balance":1000}`))
+		case apiPath + "/bets":
+			if r.URL.Query().Get("userId") != "user-1" || r.URL.Query().Get("contractId") != "abc123" {
+				t.Errorf("unexpected bets query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[{"id":"bet-1","contractId":"abc123","outcome":"YES","amount":3,"shares":5,
+				"limitProb":0.6,"orderAmount":6}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithKey("secret")
+	client.baseURL = server.URL
+	client.orders["bet-1"] = "abc123"
+
+	result, err := client.GetOrderStatus("bet-1")
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if result.Status != types.OrderStatusPartial || result.Filled != 5 || result.Size != 10 || result.Price != 0.6 {
+		t.Errorf("expected 5 of 10 filled at limit 0.6, got %+v", result)
+	}
+
+	if _, err := client.GetOrderStatus("unknown"); err == nil {
+		t.Error("expected error for an order the client didn't place")
+	}
+}
+
+func TestMapBetStatus(t *testing.T) {
+	tests := []struct {
+		bet  manifoldBet
+		want types.OrderStatus
+	}{
+		{manifoldBet{Shares: 10}, types.OrderStatusFilled},
+		{manifoldBet{LimitProb: 0.5}, types.OrderStatusOpen},
+		{manifoldBet{LimitProb: 0.5, Shares: 2}, types.OrderStatusPartial},
+		{manifoldBet{LimitProb: 0.5, Shares: 2, IsFilled: true}, types.OrderStatusFilled},
+		{manifoldBet{LimitProb: 0.5, IsCancelled: true}, types.OrderStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		if got := mapBetStatus(tt.bet); got != tt.want {
+			t.Errorf("mapBetStatus(%+v) = %v, want %v", tt.bet, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package manifold
+
+import "prediction-bot/internal/platform"
+
+// init registers Manifold. Dry runs only read public markets, so they don't
+// need an API key.
+func init() {
+	platform.Register("manifold", func(opts platform.Options) (platform.Platform, error) {
+		client, err := NewClient()
+		if err != nil {
+			if !opts.DryRun {
+				return nil, err
+			}
+			client = NewClientWithKey("")
+		}
+		client.httpClient.Transport = opts.WrapTransport("manifold", client.httpClient.Transport)
+		return client, nil
+	})
+}
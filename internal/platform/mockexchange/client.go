@@ -0,0 +1,299 @@
+package mockexchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"prediction-bot/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+// Client trades on a mock exchange server. It implements platform.Platform
+// and platform.Trader, and quotes prices for the position monitor.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	name       string
+}
+
+// APIError is returned when the mock exchange responds with a non-2xx
+// status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// NewClient creates a client for the mock exchange served at baseURL. It
+// trades as platform "mock".
+func NewClient(baseURL string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		name:    "mock",
+	}
+}
+
+// Name returns the platform identifier.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// ListMarkets implements platform.Platform interface.
+// Returns the open markets; resolved markets are left out.
+func (c *Client) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
+	var listed []Market
+	if err := c.doRequest(ctx, "GET", "/markets", nil, &listed); err != nil {
+		return nil, fmt.Errorf("list markets: %w", err)
+	}
+
+	markets := make([]types.Market, 0, len(listed))
+	for _, m := range listed {
+		if m.Resolution != "" {
+			continue
+		}
+		if filter.MinLiquidity > 0 && m.Liquidity < filter.MinLiquidity {
+			continue
+		}
+		markets = append(markets, convertMarket(m))
+		if filter.Limit > 0 && len(markets) == filter.Limit {
+			break
+		}
+	}
+	return markets, nil
+}
+
+// GetMarket returns a single market.
+func (c *Client) GetMarket(marketID string) (*types.Market, error) {
+	m, err := c.getMarket(marketID)
+	if err != nil {
+		return nil, err
+	}
+	market := convertMarket(*m)
+	return &market, nil
+}
+
+// GetCurrentPrice returns a market's YES price.
+func (c *Client) GetCurrentPrice(marketID string) (float64, error) {
+	m, err := c.getMarket(marketID)
+	if err != nil {
+		return 0, err
+	}
+	return m.YesPrice, nil
+}
+
+// GetResolution reports whether a market has resolved and to which side.
+func (c *Client) GetResolution(marketID string) (types.Resolution, error) {
+	m, err := c.getMarket(marketID)
+	if err != nil {
+		return types.Resolution{}, err
+	}
+	return types.Resolution{
+		MarketID: marketID,
+		Resolved: m.Resolution != "",
+		Outcome:  m.Resolution,
+	}, nil
+}
+
+// GetOrderBook implements platform.Platform interface.
+// The book has one level on each side at the outcome's price, sized by the
+// market's liquidity.
+func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	marketID, side, err := parseTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.getMarket(marketID)
+	if err != nil {
+		return nil, fmt.Errorf("get order book: %w", err)
+	}
+
+	price := outcomePrice(m, side)
+	level := []types.Level{{Price: price, Size: m.Liquidity}}
+	return &types.OrderBook{
+		MarketID: marketID,
+		TokenID:  tokenID,
+		Bids:     level,
+		Asks:     level,
+	}, nil
+}
+
+// GetBalance implements platform.Platform interface.
+func (c *Client) GetBalance() (float64, error) {
+	var resp struct {
+		Balance float64 `json:"balance"`
+	}
+	if err := c.doRequest(context.Background(), "GET", "/balance", nil, &resp); err != nil {
+		return 0, fmt.Errorf("get balance: %w", err)
+	}
+	return resp.Balance, nil
+}
+
+// GetPositions implements platform.Platform interface.
+func (c *Client) GetPositions() ([]types.Position, error) {
+	var positions []types.Position
+	if err := c.doRequest(context.Background(), "GET", "/positions", nil, &positions); err != nil {
+		return nil, fmt.Errorf("get positions: %w", err)
+	}
+	return positions, nil
+}
+
+// PlaceOrder places an order on the mock exchange, or simulates it when
+// dryRun is true.
+func (c *Client) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	if _, _, err := parseTokenID(order.TokenID); err != nil {
+		return types.OrderResult{}, fmt.Errorf("order validation: %w", err)
+	}
+	if order.Size <= 0 {
+		return types.OrderResult{}, fmt.Errorf("order validation: Size must be positive")
+	}
+	if dryRun {
+		return types.OrderResult{
+			OrderID:   fmt.Sprintf("dryrun-%s", uuid.New().String()),
+			MarketID:  order.MarketID,
+			TokenID:   order.TokenID,
+			Side:      order.Side,
+			Price:     order.Price,
+			Size:      order.Size,
+			Status:    types.OrderStatusSimulated,
+			IsDryRun:  true,
+			CreatedAt: time.Now(),
+		}, nil
+	}
+
+	var result types.OrderResult
+	if err := c.doRequest(ctx, "POST", "/orders", order, &result); err != nil {
+		return types.OrderResult{}, fmt.Errorf("place order: %w", err)
+	}
+	return result, nil
+}
+
+// GetOrderStatus returns the current state and fill of an order.
+func (c *Client) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	var result types.OrderResult
+	if err := c.doRequest(context.Background(), "GET", "/orders/"+url.PathEscape(orderID), nil, &result); err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order: %w", err)
+	}
+	return result, nil
+}
+
+// GetOpenOrders returns the resting orders for a market.
+func (c *Client) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	var open []types.OrderResult
+	if err := c.doRequest(context.Background(), "GET", "/orders?market="+url.QueryEscape(marketID), nil, &open); err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+	return open, nil
+}
+
+// CancelOrder cancels a resting order.
+func (c *Client) CancelOrder(orderID string) error {
+	if err := c.doRequest(context.Background(), "DELETE", "/orders/"+url.PathEscape(orderID), nil, nil); err != nil {
+		return fmt.Errorf("cancel order: %w", err)
+	}
+	return nil
+}
+
+// getMarket fetches a market as listed on the exchange.
+func (c *Client) getMarket(marketID string) (*Market, error) {
+	var m Market
+	if err := c.doRequest(context.Background(), "GET", "/markets/"+url.PathEscape(marketID), nil, &m); err != nil {
+		return nil, fmt.Errorf("get market: %w", err)
+	}
+	return &m, nil
+}
+
+// doRequest performs a request to the mock exchange. A non-nil payload is
+// sent as JSON, and the response is decoded into out when it is non-nil.
+func (c *Client) doRequest(ctx context.Context, method, path string, payload, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// convertMarket converts a listed market to the common type.
+func convertMarket(m Market) types.Market {
+	return types.Market{
+		ID:              m.ID,
+		Platform:        "mock",
+		Title:           m.Title,
+		Category:        m.Category,
+		EndDate:         m.EndDate,
+		Volume:          m.Volume,
+		Liquidity:       m.Liquidity,
+		Active:          m.Resolution == "",
+		Closed:          m.Resolution != "",
+		OutcomeYesPrice: m.YesPrice,
+		OutcomeNoPrice:  1 - m.YesPrice,
+		Tokens: []types.Token{
+			{TokenID: tokenID(m.ID, "YES"), Outcome: "Yes", Price: m.YesPrice},
+			{TokenID: tokenID(m.ID, "NO"), Outcome: "No", Price: 1 - m.YesPrice},
+		},
+	}
+}
+
+// tokenID returns the token of side ("YES" or "NO") on a market.
+func tokenID(marketID, side string) string {
+	return marketID + ":" + side
+}
+
+// parseTokenID splits a token into its market and side.
+func parseTokenID(token string) (marketID, side string, err error) {
+	i := strings.LastIndex(token, ":")
+	if i <= 0 {
+		return "", "", fmt.Errorf("invalid token %q (want <market>:YES or <market>:NO)", token)
+	}
+	marketID, side = token[:i], token[i+1:]
+	if side != "YES" && side != "NO" {
+		return "", "", fmt.Errorf("invalid token %q (want <market>:YES or <market>:NO)", token)
+	}
+	return marketID, side, nil
+}
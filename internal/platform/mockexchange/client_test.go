@@ -0,0 +1,173 @@
+package mockexchange
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func newTestExchange(t *testing.T) (*Server, *Client) {
+	t.Helper()
+	server := NewServer(100)
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	server.ListMarket(Market{
+		ID:        "btc-100k",
+		Title:     "Will Bitcoin be above $100,000 on Jan 20?",
+		EndDate:   time.Now().Add(24 * time.Hour),
+		YesPrice:  0.85,
+		Volume:    10000,
+		Liquidity: 5000,
+	})
+	return server, NewClient(ts.URL)
+}
+
+func TestClient_ListMarkets(t *testing.T) {
+	server, client := newTestExchange(t)
+
+	markets, err := client.ListMarkets(context.Background(), types.MarketFilter{})
+	if err != nil {
+		t.Fatalf("ListMarkets failed: %v", err)
+	}
+	if len(markets) != 1 {
+		t.Fatalf("expected 1 market, got %d", len(markets))
+	}
+	m := markets[0]
+	if m.Platform != "mock" || m.OutcomeYesPrice != 0.85 || len(m.Tokens) != 2 || m.Tokens[1].TokenID != "btc-100k:NO" {
+		t.Errorf("unexpected market %+v", m)
+	}
+
+	if err := server.Resolve("btc-100k", "NO"); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if markets, _ = client.ListMarkets(context.Background(), types.MarketFilter{}); len(markets) != 0 {
+		t.Errorf("expected resolved markets left out, got %d", len(markets))
+	}
+	resolution, err := client.GetResolution("btc-100k")
+	if err != nil {
+		t.Fatalf("GetResolution failed: %v", err)
+	}
+	if !resolution.Resolved || resolution.SettlementPrice("NO") != 1.0 {
+		t.Errorf("expected market resolved to NO, got %+v", resolution)
+	}
+}
+
+func TestClient_PlaceOrder_FillsMarketableOrders(t *testing.T) {
+	server, client := newTestExchange(t)
+
+	result, err := client.PlaceOrder(context.Background(), types.Order{
+		MarketID:    "btc-100k",
+		TokenID:     "btc-100k:YES",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.86,
+		Size:        10,
+		TimeInForce: types.TimeInForceGTC,
+	}, false)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusFilled || result.Filled != 10 || result.AvgFillPrice != 0.85 {
+		t.Errorf("expected a fill of 10 at 0.85, got %+v", result)
+	}
+	if balance, _ := client.GetBalance(); balance != 91.5 {
+		t.Errorf("expected balance 91.5, got %.2f", balance)
+	}
+	if price, _ := client.GetCurrentPrice("btc-100k"); price != 0.85 {
+		t.Errorf("expected current price 0.85, got %.2f", price)
+	}
+
+	// A sell below the market fills at the market price
+	result, err = client.PlaceOrder(context.Background(), types.Order{
+		MarketID:    "btc-100k",
+		TokenID:     "btc-100k:YES",
+		Side:        types.OrderSideSell,
+		Type:        types.OrderTypeLimit,
+		Price:       0.80,
+		Size:        10,
+		TimeInForce: types.TimeInForceIOC,
+	}, false)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusFilled || result.AvgFillPrice != 0.85 {
+		t.Errorf("expected the sell filled at 0.85, got %+v", result)
+	}
+	if orders := server.Orders(); len(orders) != 2 {
+		t.Errorf("expected 2 orders on the exchange, got %d", len(orders))
+	}
+}
+
+func TestClient_PlaceOrder_RestsUntilThePriceReachesIt(t *testing.T) {
+	server, client := newTestExchange(t)
+
+	order := types.Order{
+		MarketID:    "btc-100k",
+		TokenID:     "btc-100k:NO",
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       0.10,
+		Size:        5,
+		TimeInForce: types.TimeInForceIOC,
+	}
+	result, err := client.PlaceOrder(context.Background(), order, false)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if result.Status != types.OrderStatusCancelled {
+		t.Errorf("expected the IOC order cancelled, got %s", result.Status)
+	}
+
+	order.TimeInForce = types.TimeInForceGTC
+	result, err = client.PlaceOrder(context.Background(), order, false)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	open, err := client.GetOpenOrders("btc-100k")
+	if err != nil {
+		t.Fatalf("GetOpenOrders failed: %v", err)
+	}
+	if len(open) != 1 || open[0].OrderID != result.OrderID {
+		t.Fatalf("expected the GTC order resting, got %+v", open)
+	}
+
+	// NO drops to 0.08 when YES rises to 0.92
+	if err := server.SetPrice("btc-100k", 0.92); err != nil {
+		t.Fatalf("SetPrice failed: %v", err)
+	}
+	status, err := client.GetOrderStatus(result.OrderID)
+	if err != nil {
+		t.Fatalf("GetOrderStatus failed: %v", err)
+	}
+	if status.Status != types.OrderStatusFilled || status.Filled != 5 {
+		t.Errorf("expected the resting order filled, got %+v", status)
+	}
+}
+
+func TestClient_PlaceOrder_DryRunSimulates(t *testing.T) {
+	server, client := newTestExchange(t)
+
+	result, err := client.PlaceOrder(context.Background(), types.Order{
+		MarketID: "btc-100k",
+		TokenID:  "btc-100k:YES",
+		Side:     types.OrderSideBuy,
+		Price:    0.85,
+		Size:     10,
+	}, true)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if !result.IsDryRun || result.Status != types.OrderStatusSimulated {
+		t.Errorf("expected a simulated order, got %+v", result)
+	}
+	if len(server.Orders()) != 0 {
+		t.Error("expected no order sent to the exchange")
+	}
+
+	if _, err := client.PlaceOrder(context.Background(), types.Order{TokenID: "btc-100k", Size: 1}, true); err == nil {
+		t.Error("expected an error for a token without a side")
+	}
+}
@@ -0,0 +1,356 @@
+// Package mockexchange is a scriptable prediction market exchange served
+// over HTTP, and a client trading on it. Tests script the exchange (list a
+// market, move its price, resolve it) and run the bot against the client to
+// exercise scanning, entries, exits and settlement end to end without
+// touching a real platform.
+package mockexchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// Market is a YES/NO market listed on the mock exchange.
+type Market struct {
+	ID        string
+	Title     string
+	Category  string
+	EndDate   time.Time
+	YesPrice  float64
+	Volume    float64
+	Liquidity float64
+	// Resolution is the winning side ("YES" or "NO") once resolved.
+	Resolution string
+}
+
+// Server is the mock exchange. Orders fill in full at the market price as
+// soon as their limit allows; GTC orders rest until the price reaches them,
+// other orders that can't fill are cancelled.
+type Server struct {
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	markets  map[string]*Market
+	listed   []string                      // Market IDs in listing order
+	orders   map[string]*types.OrderResult // Every order placed, by ID
+	holdings map[string]float64            // Contracts held, by token
+	balance  float64
+	nextID   int
+}
+
+// NewServer creates a mock exchange with no markets and balance to trade
+// with.
+func NewServer(balance float64) *Server {
+	s := &Server{
+		mux:      http.NewServeMux(),
+		markets:  make(map[string]*Market),
+		orders:   make(map[string]*types.OrderResult),
+		holdings: make(map[string]float64),
+		balance:  balance,
+	}
+	s.mux.HandleFunc("GET /markets", s.handleListMarkets)
+	s.mux.HandleFunc("GET /markets/{id}", s.handleGetMarket)
+	s.mux.HandleFunc("GET /balance", s.handleGetBalance)
+	s.mux.HandleFunc("GET /positions", s.handleGetPositions)
+	s.mux.HandleFunc("POST /orders", s.handlePlaceOrder)
+	s.mux.HandleFunc("GET /orders", s.handleListOrders)
+	s.mux.HandleFunc("GET /orders/{id}", s.handleGetOrder)
+	s.mux.HandleFunc("DELETE /orders/{id}", s.handleCancelOrder)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListMarket lists a market, or replaces the listed market with its ID.
+func (s *Server) ListMarket(m Market) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.markets[m.ID]; !ok {
+		s.listed = append(s.listed, m.ID)
+	}
+	s.markets[m.ID] = &m
+	s.matchResting(m.ID)
+}
+
+// SetPrice moves a market's YES price, filling the resting orders it
+// reaches.
+func (s *Server) SetPrice(marketID string, yesPrice float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.markets[marketID]
+	if !ok {
+		return fmt.Errorf("market not found: %s", marketID)
+	}
+	m.YesPrice = yesPrice
+	s.matchResting(marketID)
+	return nil
+}
+
+// Resolve resolves a market to outcome ("YES" or "NO").
+func (s *Server) Resolve(marketID, outcome string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.markets[marketID]
+	if !ok {
+		return fmt.Errorf("market not found: %s", marketID)
+	}
+	if outcome != "YES" && outcome != "NO" {
+		return fmt.Errorf("invalid outcome %q (want YES or NO)", outcome)
+	}
+	m.Resolution = outcome
+	return nil
+}
+
+// Orders returns every order placed on the exchange, in the order placed.
+func (s *Server) Orders() []types.OrderResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := make([]types.OrderResult, 0, len(s.orders))
+	for i := 1; i <= s.nextID; i++ {
+		if o, ok := s.orders[orderID(i)]; ok {
+			orders = append(orders, *o)
+		}
+	}
+	return orders
+}
+
+// Balance returns the cash balance.
+func (s *Server) Balance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balance
+}
+
+// outcomePrice returns the price of side ("YES" or "NO") on a market.
+func outcomePrice(m *Market, side string) float64 {
+	if side == "NO" {
+		return 1 - m.YesPrice
+	}
+	return m.YesPrice
+}
+
+// marketable reports whether an order can fill at price.
+func marketable(o types.Order, price float64) bool {
+	if o.Type == types.OrderTypeMarket {
+		return true
+	}
+	if o.Side == types.OrderSideBuy {
+		return o.Price >= price
+	}
+	return o.Price <= price
+}
+
+// fill fills an order in full at its market's current price. The caller
+// holds s.mu.
+func (s *Server) fill(result *types.OrderResult, price float64) {
+	cost := price * result.Size
+	if result.Side == types.OrderSideBuy {
+		s.balance -= cost
+		s.holdings[result.TokenID] += result.Size
+	} else {
+		s.balance += cost
+		s.holdings[result.TokenID] -= result.Size
+	}
+	result.Filled = result.Size
+	result.AvgFillPrice = price
+	result.Status = types.OrderStatusFilled
+}
+
+// matchResting fills the resting orders on a market that its price has
+// reached. The caller holds s.mu.
+func (s *Server) matchResting(marketID string) {
+	m := s.markets[marketID]
+	for _, o := range s.orders {
+		if o.MarketID != marketID || !o.IsResting() {
+			continue
+		}
+		_, side, _ := parseTokenID(o.TokenID)
+		price := outcomePrice(m, side)
+		if marketable(types.Order{Type: types.OrderTypeLimit, Side: o.Side, Price: o.Price}, price) {
+			s.fill(o, price)
+		}
+	}
+}
+
+// orderID returns the ID of the nth order placed.
+func orderID(n int) string {
+	return fmt.Sprintf("mock-%d", n)
+}
+
+func (s *Server) handleListMarkets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	markets := make([]Market, 0, len(s.listed))
+	for _, id := range s.listed {
+		markets = append(markets, *s.markets[id])
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, markets)
+}
+
+func (s *Server) handleGetMarket(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	m, ok := s.markets[r.PathValue("id")]
+	var market Market
+	if ok {
+		market = *m
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "market not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, market)
+}
+
+func (s *Server) handleGetBalance(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]float64{"balance": s.Balance()})
+}
+
+func (s *Server) handleGetPositions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	net := make(map[string]float64)
+	for token, held := range s.holdings {
+		marketID, side, err := parseTokenID(token)
+		if err != nil {
+			continue
+		}
+		if side == "NO" {
+			held = -held
+		}
+		net[marketID] += held
+	}
+	s.mu.Unlock()
+
+	positions := make([]types.Position, 0, len(net))
+	for marketID, quantity := range net {
+		if quantity == 0 {
+			continue
+		}
+		positions = append(positions, types.Position{
+			Platform:     "mock",
+			MarketTicker: marketID,
+			Quantity:     quantity,
+		})
+	}
+	writeJSON(w, http.StatusOK, positions)
+}
+
+func (s *Server) handlePlaceOrder(w http.ResponseWriter, r *http.Request) {
+	var order types.Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, fmt.Sprintf("invalid order: %v", err), http.StatusBadRequest)
+		return
+	}
+	marketID, side, err := parseTokenID(order.TokenID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if order.Size <= 0 {
+		http.Error(w, "size must be positive", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.markets[marketID]
+	if !ok {
+		http.Error(w, "market not found", http.StatusNotFound)
+		return
+	}
+	if m.Resolution != "" {
+		http.Error(w, "market resolved", http.StatusConflict)
+		return
+	}
+
+	s.nextID++
+	result := &types.OrderResult{
+		OrderID:   orderID(s.nextID),
+		MarketID:  marketID,
+		TokenID:   order.TokenID,
+		Side:      order.Side,
+		Price:     order.Price,
+		Size:      order.Size,
+		Status:    types.OrderStatusOpen,
+		CreatedAt: time.Now(),
+	}
+	s.orders[result.OrderID] = result
+
+	price := outcomePrice(m, side)
+	switch {
+	case marketable(order, price):
+		s.fill(result, price)
+	case order.TimeInForce != types.TimeInForceGTC:
+		result.Status = types.OrderStatusCancelled
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleListOrders(w http.ResponseWriter, r *http.Request) {
+	marketID := r.URL.Query().Get("market")
+
+	s.mu.Lock()
+	var open []types.OrderResult
+	for _, o := range s.orders {
+		if o.IsResting() && (marketID == "" || o.MarketID == marketID) {
+			open = append(open, *o)
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, open)
+}
+
+func (s *Server) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	o, ok := s.orders[r.PathValue("id")]
+	var result types.OrderResult
+	if ok {
+		result = *o
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "order not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[r.PathValue("id")]
+	if !ok {
+		http.Error(w, "order not found", http.StatusNotFound)
+		return
+	}
+	if o.IsResting() {
+		o.Status = types.OrderStatusCancelled
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeJSON writes v as a JSON response with status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
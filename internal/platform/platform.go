@@ -1,18 +1,23 @@
 package platform
 
-import "prediction-bot/pkg/types"
+import (
+	"context"
+
+	"prediction-bot/pkg/types"
+)
 
 // Platform defines the common interface for prediction market platforms.
 // Both Polymarket and Kalshi clients should implement this interface.
+// Methods taking a context abandon their requests when it is cancelled.
 type Platform interface {
 	// Name returns the platform identifier (e.g., "polymarket", "kalshi")
 	Name() string
 
 	// ListMarkets returns markets matching the given filter
-	ListMarkets(filter types.MarketFilter) ([]types.Market, error)
+	ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error)
 
 	// GetOrderBook returns the order book for a given token/market
-	GetOrderBook(tokenID string) (*types.OrderBook, error)
+	GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error)
 
 	// GetBalance returns the available balance in dollars
 	GetBalance() (float64, error)
@@ -20,3 +25,19 @@ type Platform interface {
 	// GetPositions returns all current positions
 	GetPositions() ([]types.Position, error)
 }
+
+// Trader defines order management for platforms that support trading.
+// Both Polymarket and Kalshi clients implement this interface.
+type Trader interface {
+	// PlaceOrder submits an order, or simulates it when dryRun is true
+	PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error)
+
+	// GetOrderStatus returns the current state and fill of an order
+	GetOrderStatus(orderID string) (types.OrderResult, error)
+
+	// GetOpenOrders returns the resting orders for a market
+	GetOpenOrders(marketID string) ([]types.OrderResult, error)
+
+	// CancelOrder cancels a resting order
+	CancelOrder(orderID string) error
+}
@@ -1,6 +1,7 @@
 package platform
 
 import (
+	"context"
 	"testing"
 
 	"prediction-bot/pkg/types"
@@ -18,11 +19,11 @@ func (m *MockPlatform) Name() string {
 	return m.name
 }
 
-func (m *MockPlatform) ListMarkets(filter types.MarketFilter) ([]types.Market, error) {
+func (m *MockPlatform) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
 	return m.markets, nil
 }
 
-func (m *MockPlatform) GetOrderBook(tokenID string) (*types.OrderBook, error) {
+func (m *MockPlatform) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
 	return &types.OrderBook{TokenID: tokenID}, nil
 }
 
@@ -54,7 +55,7 @@ func TestPlatformInterface(t *testing.T) {
 	}
 
 	// Test ListMarkets
-	markets, err := p.ListMarkets(types.MarketFilter{})
+	markets, err := p.ListMarkets(context.Background(), types.MarketFilter{})
 	if err != nil {
 		t.Errorf("ListMarkets returned error: %v", err)
 	}
@@ -63,7 +64,7 @@ func TestPlatformInterface(t *testing.T) {
 	}
 
 	// Test GetOrderBook
-	ob, err := p.GetOrderBook("token-1")
+	ob, err := p.GetOrderBook(context.Background(), "token-1")
 	if err != nil {
 		t.Errorf("GetOrderBook returned error: %v", err)
 	}
@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +29,13 @@ const (
 
 	// USDC has 6 decimals on Polygon
 	usdcDecimals = 6
+
+	// Conditional Tokens (CTF) contract address on Polygon, which holds
+	// outcome tokens until they are redeemed
+	ctfContractAddress = "0x4D97DCd97eC945f40cF65F87097ACe5EA0476045"
+
+	// ERC1155 balanceOf function selector: keccak256("balanceOf(address,uint256)")[:4]
+	erc1155BalanceOfSelector = "00fdd58e"
 )
 
 // jsonRPCRequest represents a JSON-RPC request.
@@ -62,68 +72,131 @@ func (c *Client) GetBalanceForWallet(walletAddress string) (types.Balance, error
 	// Function selector (4 bytes) + address padded to 32 bytes
 	callData := balanceOfSelector + strings.Repeat("0", 24) + address
 
-	// Create eth_call request
+	resultHex, err := c.ethCall(usdcContractAddress, callData)
+	if err != nil {
+		return types.Balance{}, err
+	}
+
+	amount, err := parseUSDCBalance(resultHex)
+	if err != nil {
+		return types.Balance{}, fmt.Errorf("parse balance: %w", err)
+	}
+
+	return types.Balance{
+		Platform:  "polymarket",
+		Currency:  "USDC",
+		Amount:    amount,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// GetTokenBalance returns how many outcome tokens for tokenID the configured
+// wallet holds. Redeeming a resolved position burns its tokens and credits
+// the payout in USDC, so a zero balance means the payout has been claimed.
+func (c *Client) GetTokenBalance(tokenID string) (float64, error) {
+	if c.creds.WalletAddress == "" {
+		return 0, fmt.Errorf("wallet address not configured (set POLYMARKET_WALLET_ADDRESS)")
+	}
+
+	callData, err := tokenBalanceCallData(c.creds.WalletAddress, tokenID)
+	if err != nil {
+		return 0, err
+	}
+
+	resultHex, err := c.ethCall(ctfContractAddress, callData)
+	if err != nil {
+		return 0, err
+	}
+
+	// Outcome tokens use the same 6 decimals as the USDC collateral
+	amount, err := parseUSDCBalance(resultHex)
+	if err != nil {
+		return 0, fmt.Errorf("parse token balance: %w", err)
+	}
+	return amount, nil
+}
+
+// tokenBalanceCallData builds the ERC1155 balanceOf call data for a wallet
+// and a decimal token ID.
+func tokenBalanceCallData(walletAddress, tokenID string) (string, error) {
+	address := strings.ToLower(strings.TrimPrefix(walletAddress, "0x"))
+	if len(address) != 40 {
+		return "", fmt.Errorf("invalid wallet address: %s", walletAddress)
+	}
+
+	id, ok := new(big.Int).SetString(tokenID, 10)
+	if !ok || id.Sign() < 0 {
+		return "", fmt.Errorf("invalid token id: %s", tokenID)
+	}
+
+	// Function selector (4 bytes) + address and token ID padded to 32 bytes each
+	return erc1155BalanceOfSelector + strings.Repeat("0", 24) + address + fmt.Sprintf("%064x", id), nil
+}
+
+// ethCall performs a read-only contract call on Polygon and returns the
+// hex-encoded result.
+func (c *Client) ethCall(to, callData string) (string, error) {
+	params := []interface{}{
+		map[string]string{
+			"to":   to,
+			"data": "0x" + callData,
+		},
+		"latest",
+	}
+
+	// The result is a hex string representing a uint256
+	var resultHex string
+	if err := c.rpcCall(c.rpcURL, "eth_call", params, &resultHex); err != nil {
+		return "", err
+	}
+	return resultHex, nil
+}
+
+// rpcCall sends a JSON-RPC request to url and decodes its result into result.
+func (c *Client) rpcCall(url, method string, params []interface{}, result interface{}) error {
 	req := jsonRPCRequest{
 		JSONRPC: "2.0",
-		Method:  "eth_call",
-		Params: []interface{}{
-			map[string]string{
-				"to":   usdcContractAddress,
-				"data": "0x" + callData,
-			},
-			"latest",
-		},
-		ID: 1,
+		Method:  method,
+		Params:  params,
+		ID:      1,
 	}
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return types.Balance{}, fmt.Errorf("marshal request: %w", err)
+		return fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", polygonRPC, bytes.NewReader(reqBody))
+	httpReq, err := http.NewRequest("POST", url, bytes.NewReader(reqBody))
 	if err != nil {
-		return types.Balance{}, fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return types.Balance{}, fmt.Errorf("do request: %w", err)
+		return fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return types.Balance{}, fmt.Errorf("read response: %w", err)
+		return fmt.Errorf("read response: %w", err)
 	}
 
 	var rpcResp jsonRPCResponse
 	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
-		return types.Balance{}, fmt.Errorf("unmarshal response: %w", err)
+		return fmt.Errorf("unmarshal response: %w", err)
 	}
 
 	if rpcResp.Error != nil {
-		return types.Balance{}, fmt.Errorf("rpc error: %s", rpcResp.Error.Message)
-	}
-
-	// Parse the result (hex string representing uint256)
-	var resultHex string
-	if err := json.Unmarshal(rpcResp.Result, &resultHex); err != nil {
-		return types.Balance{}, fmt.Errorf("unmarshal result: %w", err)
+		return fmt.Errorf("rpc error: %s", rpcResp.Error.Message)
 	}
 
-	amount, err := parseUSDCBalance(resultHex)
-	if err != nil {
-		return types.Balance{}, fmt.Errorf("parse balance: %w", err)
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result: %w", err)
 	}
 
-	return types.Balance{
-		Platform:  "polymarket",
-		Currency:  "USDC",
-		Amount:    amount,
-		Timestamp: time.Now(),
-	}, nil
+	return nil
 }
 
 // GetBalance implements platform.Platform interface.
@@ -141,12 +214,118 @@ func (c *Client) GetBalance() (float64, error) {
 	return balance.Amount, nil
 }
 
+// positionsPageSize is how many positions the data API returns per page.
+const positionsPageSize = 500
+
+// dataPosition represents a position as returned by the data API.
+type dataPosition struct {
+	Asset        string  `json:"asset"`
+	ConditionID  string  `json:"conditionId"`
+	Size         float64 `json:"size"`
+	AvgPrice     float64 `json:"avgPrice"`
+	InitialValue float64 `json:"initialValue"`
+	CashPnL      float64 `json:"cashPnl"`
+	RealizedPnL  float64 `json:"realizedPnl"`
+	TotalBought  float64 `json:"totalBought"`
+	CurPrice     float64 `json:"curPrice"`
+	Redeemable   bool    `json:"redeemable"`
+	Title        string  `json:"title"`
+	Outcome      string  `json:"outcome"`
+	EndDate      string  `json:"endDate"`
+}
+
 // GetPositions implements platform.Platform interface.
-// Returns current positions (placeholder - Polymarket positions require on-chain queries).
+// Returns the outcome tokens the configured wallet holds, from the data API.
+// Positions on resolved markets that lost, worth nothing, are left out.
 func (c *Client) GetPositions() ([]types.Position, error) {
-	// Polymarket positions are stored on-chain and require subgraph queries.
-	// For now, return empty list. Full implementation requires GraphQL to Polymarket subgraph.
-	return []types.Position{}, nil
+	if c.creds.WalletAddress == "" {
+		return nil, fmt.Errorf("wallet address not configured (set POLYMARKET_WALLET_ADDRESS)")
+	}
+
+	var positions []types.Position
+	now := time.Now()
+
+	for offset := 0; ; offset += positionsPageSize {
+		page, err := c.getPositionsPage(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dp := range page {
+			if dp.Size == 0 || (dp.Redeemable && dp.CurPrice == 0) {
+				continue
+			}
+			positions = append(positions, dp.toPosition(now))
+		}
+
+		if len(page) < positionsPageSize {
+			break
+		}
+	}
+
+	return positions, nil
+}
+
+// getPositionsPage fetches the wallet's positions from offset.
+func (c *Client) getPositionsPage(offset int) ([]dataPosition, error) {
+	query := url.Values{}
+	query.Set("user", c.creds.WalletAddress)
+	query.Set("limit", strconv.Itoa(positionsPageSize))
+	query.Set("offset", strconv.Itoa(offset))
+
+	resp, err := c.httpClient.Get(c.dataURL + "/positions?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("get positions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read positions response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var page []dataPosition
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("parse positions response: %w", err)
+	}
+	return page, nil
+}
+
+// toPosition converts a data API position. Yes and No tokens of a binary
+// market are signed by side; any other outcome is held as its YES side.
+func (dp dataPosition) toPosition(now time.Time) types.Position {
+	pos := types.Position{
+		Platform:       "polymarket",
+		MarketTicker:   dp.ConditionID,
+		Title:          dp.Title,
+		TokenID:        dp.Asset,
+		Quantity:       dp.Size,
+		AveragePrice:   dp.AvgPrice,
+		CurrentPrice:   dp.CurPrice,
+		MarketExposure: dp.InitialValue,
+		RealizedPnL:    dp.RealizedPnL,
+		UnrealizedPnL:  dp.CashPnL,
+		TotalTraded:    int(math.Round(dp.TotalBought)),
+		Timestamp:      now,
+	}
+	switch dp.Outcome {
+	case "Yes":
+	case "No":
+		pos.Quantity = -dp.Size
+	default:
+		pos.Outcome = dp.Outcome
+	}
+	// The end date is reported as a date or a timestamp
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, dp.EndDate); err == nil {
+			pos.EndDate = t
+			break
+		}
+	}
+	return pos
 }
 
 // parseUSDCBalance converts a hex string to a USDC amount (6 decimals).
@@ -2,6 +2,7 @@ package polymarket
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -17,6 +18,7 @@ func TestClient_GetBalanceForWallet_ReturnsUSDCBalance(t *testing.T) {
 	client := &Client{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 		baseURL:    clobBaseURL,
+		rpcURL:     polygonRPC,
 	}
 
 	balance, err := client.GetBalanceForWallet(walletAddress)
@@ -48,6 +50,7 @@ func TestClient_GetBalanceForWallet_WithKnownAddress(t *testing.T) {
 	client := &Client{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 		baseURL:    clobBaseURL,
+		rpcURL:     polygonRPC,
 	}
 
 	// Use a well-known Polymarket address that likely has some USDC
@@ -81,6 +84,7 @@ func TestClient_GetBalance_ImplementsPlatformInterface(t *testing.T) {
 	client := &Client{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 		baseURL:    clobBaseURL,
+		rpcURL:     polygonRPC,
 		creds: Credentials{
 			WalletAddress: walletAddress,
 		},
@@ -98,3 +102,57 @@ func TestClient_GetBalance_ImplementsPlatformInterface(t *testing.T) {
 
 	t.Logf("Balance via Platform interface: %.6f", balance)
 }
+
+func TestTokenBalanceCallData(t *testing.T) {
+	data, err := tokenBalanceCallData("0x00000000000000000000000000000000000000AB", "255")
+	if err != nil {
+		t.Fatalf("tokenBalanceCallData: %v", err)
+	}
+
+	want := "00fdd58e" +
+		"00000000000000000000000000000000000000000000000000000000000000ab" +
+		"00000000000000000000000000000000000000000000000000000000000000ff"
+	if data != want {
+		t.Errorf("unexpected call data:\n got %s\nwant %s", data, want)
+	}
+
+	if _, err := tokenBalanceCallData("0x00000000000000000000000000000000000000AB", "not-a-number"); err == nil {
+		t.Error("expected error for non-numeric token id")
+	}
+}
+
+func TestClient_GetPositions_FromDataAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/positions" || r.URL.Query().Get("user") != "0xwallet" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[
+			{"asset":"111","conditionId":"0xbinary","size":12.5,"avgPrice":0.8,"initialValue":10,"curPrice":0.85,"title":"Will BTC be above $100,000?","outcome":"No","endDate":"2025-03-31"},
+			{"asset":"222","conditionId":"0xmulti","size":5,"avgPrice":0.4,"curPrice":0.5,"title":"Fed decision in March?","outcome":"No change","endDate":"2025-03-19T18:00:00Z"},
+			{"asset":"333","conditionId":"0xlost","size":20,"avgPrice":0.3,"curPrice":0,"redeemable":true,"outcome":"Yes"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{WalletAddress: "0xwallet"})
+	client.dataURL = server.URL
+
+	positions, err := client.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions: %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("expected the lost position left out, got %+v", positions)
+	}
+
+	binary := positions[0]
+	if binary.MarketTicker != "0xbinary" || binary.TokenID != "111" || binary.Quantity != -12.5 || binary.Outcome != "" {
+		t.Errorf("expected a NO position on 0xbinary, got %+v", binary)
+	}
+	if binary.AveragePrice != 0.8 || binary.CurrentPrice != 0.85 || !binary.EndDate.Equal(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected price or end date: %+v", binary)
+	}
+	if multi := positions[1]; multi.Outcome != "No change" || multi.Quantity != 5 {
+		t.Errorf("expected the outcome held as YES, got %+v", multi)
+	}
+}
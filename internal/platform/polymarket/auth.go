@@ -15,6 +15,12 @@ type Credentials struct {
 	APISecret     string
 	Passphrase    string
 	WalletAddress string
+	// PrivateKey is the hex key live orders are signed with (empty if
+	// only dry-run orders are placed)
+	PrivateKey string
+	// SignatureType is how WalletAddress relates to the signing key: one of
+	// the SignatureType constants
+	SignatureType int
 }
 
 // generateL2Signature generates the HMAC signature for L2 API requests.
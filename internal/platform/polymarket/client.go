@@ -1,16 +1,25 @@
 package polymarket
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 )
 
 const (
 	// clobBaseURL is the Polymarket CLOB API base URL
 	clobBaseURL = "https://clob.polymarket.com"
+
+	// statusPageURL is the Polymarket system status summary
+	statusPageURL = "https://status.polymarket.com/api/v2/summary.json"
+
+	// dataAPIURL is the Polymarket data API base URL, which reports the
+	// positions a wallet holds
+	dataAPIURL = "https://data-api.polymarket.com"
 )
 
 // Client is a Polymarket CLOB API client.
@@ -18,6 +27,24 @@ type Client struct {
 	httpClient *http.Client
 	creds      Credentials
 	baseURL    string
+	statusURL  string
+	dataURL    string
+	// rpcURL is the Polygon JSON-RPC endpoint contracts are read from
+	rpcURL string
+	// signerURL is the JSON-RPC endpoint that signs and sends transactions
+	// for the wallet (empty if on-chain redemption is not configured)
+	signerURL string
+}
+
+// APIError is returned when the Polymarket API responds with a non-2xx
+// status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Body)
 }
 
 // NewClient creates a new Polymarket client from environment variables.
@@ -26,11 +53,22 @@ func NewClient() (*Client, error) {
 	apiSecret := os.Getenv("POLYMARKET_API_SECRET")
 	passphrase := os.Getenv("POLYMARKET_PASSPHRASE")
 	walletAddress := os.Getenv("POLYMARKET_WALLET_ADDRESS")
+	signerURL := os.Getenv("POLYMARKET_SIGNER_RPC")
+	privateKey := os.Getenv("POLYMARKET_PRIVATE_KEY")
 
 	if apiKey == "" || apiSecret == "" || passphrase == "" {
 		return nil, fmt.Errorf("missing Polymarket credentials in environment")
 	}
 
+	signatureType := SignatureTypeEOA
+	if s := os.Getenv("POLYMARKET_SIGNATURE_TYPE"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < SignatureTypeEOA || n > SignatureTypeGnosisSafe {
+			return nil, fmt.Errorf("POLYMARKET_SIGNATURE_TYPE must be 0 (EOA), 1 (proxy) or 2 (Gnosis Safe): %q", s)
+		}
+		signatureType = n
+	}
+
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
@@ -40,8 +78,14 @@ func NewClient() (*Client, error) {
 			APISecret:     apiSecret,
 			Passphrase:    passphrase,
 			WalletAddress: walletAddress,
+			PrivateKey:    privateKey,
+			SignatureType: signatureType,
 		},
-		baseURL: clobBaseURL,
+		baseURL:   clobBaseURL,
+		statusURL: statusPageURL,
+		dataURL:   dataAPIURL,
+		rpcURL:    polygonRPC,
+		signerURL: signerURL,
 	}, nil
 }
 
@@ -51,13 +95,16 @@ func NewClientWithCreds(creds Credentials) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		creds:   creds,
-		baseURL: clobBaseURL,
+		creds:     creds,
+		baseURL:   clobBaseURL,
+		statusURL: statusPageURL,
+		dataURL:   dataAPIURL,
+		rpcURL:    polygonRPC,
 	}
 }
 
 // doRequest performs an authenticated request to the Polymarket API.
-func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
+func (c *Client) doRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
 	timestamp := getTimestamp()
 
 	signature, err := generateL2Signature(c.creds, timestamp, method, path, body)
@@ -71,7 +118,7 @@ func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
 		reqBody = &byteReader{data: body}
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -95,7 +142,7 @@ func (c *Client) doRequest(method, path string, body []byte) ([]byte, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return respBody, nil
@@ -133,10 +180,10 @@ func (c *Client) Ping() error {
 }
 
 // doPublicRequest performs a non-authenticated request to the Polymarket API.
-func (c *Client) doPublicRequest(method, path string) ([]byte, error) {
+func (c *Client) doPublicRequest(ctx context.Context, method, path string) ([]byte, error) {
 	url := c.baseURL + path
 
-	req, err := http.NewRequest(method, url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -154,7 +201,7 @@ func (c *Client) doPublicRequest(method, path string) ([]byte, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return respBody, nil
@@ -162,7 +209,7 @@ func (c *Client) doPublicRequest(method, path string) ([]byte, error) {
 
 // GetServerTime fetches the server time (public endpoint, no auth needed).
 func (c *Client) GetServerTime() (int64, error) {
-	body, err := c.doPublicRequest("GET", "/time")
+	body, err := c.doPublicRequest(context.Background(), "GET", "/time")
 	if err != nil {
 		return 0, err
 	}
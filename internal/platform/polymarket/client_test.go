@@ -1,6 +1,7 @@
 package polymarket
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"testing"
@@ -85,7 +86,7 @@ func TestClient_ListMarkets_ReturnsActiveMarkets(t *testing.T) {
 	}
 
 	active := true
-	markets, err := client.ListMarkets(types.MarketFilter{
+	markets, err := client.ListMarkets(context.Background(), types.MarketFilter{
 		IsActive: &active,
 		Limit:    10,
 	})
@@ -129,7 +130,7 @@ func TestClient_GetOrderBook_ReturnsLevels(t *testing.T) {
 
 	// First, try to get markets and find any token with an orderbook
 	active := true
-	markets, err := client.ListMarkets(types.MarketFilter{
+	markets, err := client.ListMarkets(context.Background(), types.MarketFilter{
 		IsActive: &active,
 		Limit:    20, // Reduced to avoid timeout
 	})
@@ -153,7 +154,7 @@ func TestClient_GetOrderBook_ReturnsLevels(t *testing.T) {
 		attempts++
 		tokenID := m.Tokens[0].TokenID
 
-		ob, err := client.GetOrderBook(tokenID)
+		ob, err := client.GetOrderBook(context.Background(), tokenID)
 		if err != nil {
 			t.Logf("Market %s: no orderbook (%v)", m.Title[:min(30, len(m.Title))], err)
 			continue
@@ -0,0 +1,44 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VerifyCredentials checks that the wallet address is well formed, that the
+// API secret can sign requests, that the private key can sign orders for the
+// wallet, and that the exchange accepts the API key, secret and passphrase
+// together. The error names the credential that is misconfigured.
+func (c *Client) VerifyCredentials() error {
+	address := strings.TrimPrefix(c.creds.WalletAddress, "0x")
+	if _, err := hex.DecodeString(address); err != nil || len(address) != 40 {
+		return fmt.Errorf("POLYMARKET_WALLET_ADDRESS is not a valid address: %q", c.creds.WalletAddress)
+	}
+
+	if _, err := generateL2Signature(c.creds, getTimestamp(), "POST", "/order", nil); err != nil {
+		return fmt.Errorf("POLYMARKET_API_SECRET cannot sign requests: %w", err)
+	}
+
+	key, err := c.signingKey()
+	if err != nil {
+		return err
+	}
+	if c.creds.SignatureType == SignatureTypeEOA && !strings.EqualFold(key.address(), c.creds.WalletAddress) {
+		return fmt.Errorf("POLYMARKET_PRIVATE_KEY is the key of %s, not of POLYMARKET_WALLET_ADDRESS %s (set POLYMARKET_SIGNATURE_TYPE for a proxy or safe wallet)",
+			key.address(), c.creds.WalletAddress)
+	}
+
+	if _, err := c.doRequest(context.Background(), "GET", "/auth/api-keys", nil); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("POLYMARKET_API_KEY, POLYMARKET_API_SECRET or POLYMARKET_PASSPHRASE rejected: expired, revoked or not issued together: %w", err)
+		}
+		return fmt.Errorf("verify polymarket credentials: %w", err)
+	}
+
+	return nil
+}
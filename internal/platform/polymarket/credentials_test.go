@@ -0,0 +1,66 @@
+package polymarket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCredentials(t *testing.T) {
+	valid := Credentials{
+		APIKey:        "test-key",
+		APISecret:     "c2VjcmV0",
+		Passphrase:    "test-pass",
+		WalletAddress: "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23",
+		PrivateKey:    "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+	}
+
+	tests := []struct {
+		name         string
+		modify       func(*Credentials)
+		status       int
+		wantErrorFor string
+	}{
+		{"valid credentials", func(*Credentials) {}, http.StatusOK, ""},
+		{"missing wallet", func(c *Credentials) { c.WalletAddress = "" }, http.StatusOK, "POLYMARKET_WALLET_ADDRESS"},
+		{"malformed wallet", func(c *Credentials) { c.WalletAddress = "0xnothex" }, http.StatusOK, "POLYMARKET_WALLET_ADDRESS"},
+		{"missing private key", func(c *Credentials) { c.PrivateKey = "" }, http.StatusOK, "POLYMARKET_PRIVATE_KEY"},
+		{"key of another wallet", func(c *Credentials) { c.WalletAddress = "0x1234567890abcdef1234567890abcdef12345678" }, http.StatusOK, "POLYMARKET_PRIVATE_KEY"},
+		{"key signing for a safe", func(c *Credentials) {
+			c.WalletAddress = "0x1234567890abcdef1234567890abcdef12345678"
+			c.SignatureType = SignatureTypeGnosisSafe
+		}, http.StatusOK, ""},
+		{"malformed secret", func(c *Credentials) { c.APISecret = "not base64!" }, http.StatusOK, "POLYMARKET_API_SECRET"},
+		{"rejected key", func(*Credentials) {}, http.StatusUnauthorized, "POLYMARKET_API_KEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/auth/api-keys" || r.Header.Get("POLY_SIGNATURE") == "" {
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"apiKeys":["test-key"]}`))
+			}))
+			defer server.Close()
+
+			creds := valid
+			tt.modify(&creds)
+			client := NewClientWithCreds(creds)
+			client.baseURL = server.URL
+
+			err := client.VerifyCredentials()
+			if tt.wantErrorFor == "" {
+				if err != nil {
+					t.Errorf("expected valid credentials, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrorFor) {
+				t.Errorf("expected error naming %q, got %v", tt.wantErrorFor, err)
+			}
+		})
+	}
+}
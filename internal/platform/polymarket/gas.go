@@ -0,0 +1,72 @@
+package polymarket
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"prediction-bot/internal/datasource/binance"
+)
+
+// polUSDSymbol is the Binance ticker used to price gas paid in POL.
+const polUSDSymbol = "POLUSDT"
+
+// weiPerPOL is the number of wei in one POL.
+const weiPerPOL = 1e18
+
+// transactionReceipt is the subset of an eth_getTransactionReceipt result
+// needed to compute gas spend.
+type transactionReceipt struct {
+	GasUsed           string `json:"gasUsed"`
+	EffectiveGasPrice string `json:"effectiveGasPrice"`
+}
+
+// GetTransactionCost returns the gas paid for a mined Polygon transaction,
+// in dollars at the current POL price. Returns an error if the transaction
+// has not been mined yet.
+func (c *Client) GetTransactionCost(txHash string) (float64, error) {
+	var receipt *transactionReceipt
+	if err := c.rpcCall(c.rpcURL, "eth_getTransactionReceipt", []interface{}{txHash}, &receipt); err != nil {
+		return 0, fmt.Errorf("get transaction receipt: %w", err)
+	}
+	if receipt == nil {
+		return 0, fmt.Errorf("transaction %s not mined yet", txHash)
+	}
+
+	pol, err := receipt.gasCostPOL()
+	if err != nil {
+		return 0, err
+	}
+
+	price, err := binance.NewClient().GetPrice(polUSDSymbol)
+	if err != nil {
+		return 0, fmt.Errorf("get POL price: %w", err)
+	}
+
+	return pol * price.Price, nil
+}
+
+// gasCostPOL returns gasUsed * effectiveGasPrice converted from wei to POL.
+func (r transactionReceipt) gasCostPOL() (float64, error) {
+	gasUsed, err := parseHexInt(r.GasUsed)
+	if err != nil {
+		return 0, fmt.Errorf("parse gas used: %w", err)
+	}
+	gasPrice, err := parseHexInt(r.EffectiveGasPrice)
+	if err != nil {
+		return 0, fmt.Errorf("parse gas price: %w", err)
+	}
+
+	wei := new(big.Float).SetInt(new(big.Int).Mul(gasUsed, gasPrice))
+	pol, _ := new(big.Float).Quo(wei, big.NewFloat(weiPerPOL)).Float64()
+	return pol, nil
+}
+
+// parseHexInt parses a 0x-prefixed hex quantity.
+func parseHexInt(hexStr string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(hexStr, "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex quantity %q", hexStr)
+	}
+	return n, nil
+}
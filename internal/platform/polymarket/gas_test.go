@@ -0,0 +1,26 @@
+package polymarket
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransactionReceiptGasCostPOL(t *testing.T) {
+	// 150,000 gas at 40 gwei = 0.006 POL
+	receipt := transactionReceipt{
+		GasUsed:           "0x249f0",
+		EffectiveGasPrice: "0x9502f9000",
+	}
+
+	pol, err := receipt.gasCostPOL()
+	if err != nil {
+		t.Fatalf("gasCostPOL failed: %v", err)
+	}
+	if math.Abs(pol-0.006) > 1e-12 {
+		t.Errorf("expected 0.006 POL, got %v", pol)
+	}
+
+	if _, err := (transactionReceipt{GasUsed: "0xzz", EffectiveGasPrice: "0x1"}).gasCostPOL(); err == nil {
+		t.Error("expected error for invalid gas used")
+	}
+}
@@ -0,0 +1,13 @@
+package polymarket
+
+import "golang.org/x/crypto/sha3"
+
+// keccak256 returns the Keccak-256 hash of the concatenated data, as
+// Ethereum uses it: the original Keccak padding, not SHA3-256's.
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -1,10 +1,12 @@
 package polymarket
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"prediction-bot/pkg/types"
@@ -23,7 +25,9 @@ type polymarketMarket struct {
 	MarketSlug     string  `json:"market_slug"`
 	MinIncentiveSizeQual float64 `json:"minimum_order_size"`
 	MinTickSize    float64 `json:"minimum_tick_size"`
+	Tags           []string `json:"tags"`
 	Tokens         []polymarketToken `json:"tokens"`
+	NegRisk        bool    `json:"neg_risk"`
 }
 
 type polymarketToken struct {
@@ -40,7 +44,7 @@ type marketsResponse struct {
 }
 
 // ListMarkets fetches markets from Polymarket API.
-func (c *Client) ListMarkets(filter types.MarketFilter) ([]types.Market, error) {
+func (c *Client) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
 	// Build query parameters
 	params := url.Values{}
 
@@ -67,7 +71,7 @@ func (c *Client) ListMarkets(filter types.MarketFilter) ([]types.Market, error)
 		path += "?" + params.Encode()
 	}
 
-	body, err := c.doPublicRequest("GET", path)
+	body, err := c.doPublicRequest(ctx, "GET", path)
 	if err != nil {
 		return nil, fmt.Errorf("list markets: %w", err)
 	}
@@ -106,7 +110,7 @@ func (c *Client) ListMarkets(filter types.MarketFilter) ([]types.Market, error)
 func (c *Client) GetMarket(conditionID string) (*types.Market, error) {
 	path := fmt.Sprintf("/markets/%s", conditionID)
 
-	body, err := c.doPublicRequest("GET", path)
+	body, err := c.doPublicRequest(context.Background(), "GET", path)
 	if err != nil {
 		return nil, fmt.Errorf("get market: %w", err)
 	}
@@ -130,6 +134,9 @@ func convertMarket(m polymarketMarket) types.Market {
 		Active:      m.Active,
 		Closed:      m.Closed,
 	}
+	if len(m.Tags) > 0 {
+		market.Category = m.Tags[0]
+	}
 
 	// Parse end date
 	if m.EndDateISO != "" {
@@ -157,5 +164,49 @@ func convertMarket(m polymarketMarket) types.Market {
 		}
 	}
 
+	// Markets with more than two outcomes trade each outcome's token as
+	// the YES side of a binary. There is no NO token to buy.
+	if len(m.Tokens) > 2 {
+		market.Outcomes = make([]types.Outcome, 0, len(m.Tokens))
+		for _, t := range m.Tokens {
+			market.Outcomes = append(market.Outcomes, types.Outcome{
+				MarketID: m.ConditionID,
+				Name:     t.Outcome,
+				Price:    t.Price,
+				Tokens:   []types.Token{{TokenID: t.TokenID, Outcome: "Yes", Price: t.Price}},
+			})
+		}
+	}
+
 	return market
 }
+
+// GetResolution reports whether a market has resolved and which outcome won.
+// A market is resolved once it is closed and one of its tokens is flagged as
+// the winner.
+func (c *Client) GetResolution(conditionID string) (types.Resolution, error) {
+	market, err := c.GetMarket(conditionID)
+	if err != nil {
+		return types.Resolution{}, err
+	}
+
+	return resolutionFromMarket(*market), nil
+}
+
+// resolutionFromMarket derives the resolution of a market from its tokens.
+func resolutionFromMarket(market types.Market) types.Resolution {
+	resolution := types.Resolution{MarketID: market.ID}
+	if !market.Closed {
+		return resolution
+	}
+
+	for _, t := range market.Tokens {
+		if t.Winner {
+			resolution.Resolved = true
+			resolution.Outcome = strings.ToUpper(t.Outcome)
+			break
+		}
+	}
+
+	return resolution
+}
@@ -0,0 +1,71 @@
+package polymarket
+
+import (
+	"testing"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestResolutionFromMarket(t *testing.T) {
+	market := types.Market{
+		ID:     "0xabc",
+		Closed: true,
+		Tokens: []types.Token{
+			{TokenID: "1", Outcome: "Yes"},
+			{TokenID: "2", Outcome: "No", Winner: true},
+		},
+	}
+
+	resolution := resolutionFromMarket(market)
+	if !resolution.Resolved || resolution.Outcome != "NO" {
+		t.Errorf("expected resolved NO, got %+v", resolution)
+	}
+	if resolution.SettlementPrice("NO") != 1.0 || resolution.SettlementPrice("YES") != 0.0 {
+		t.Errorf("unexpected settlement prices for %+v", resolution)
+	}
+
+	// Closed but not yet resolved by the oracle
+	market.Tokens[1].Winner = false
+	if resolution := resolutionFromMarket(market); resolution.Resolved {
+		t.Errorf("expected unresolved market without a winner, got %+v", resolution)
+	}
+}
+
+func TestConvertMarket_MultiOutcome(t *testing.T) {
+	market := convertMarket(polymarketMarket{
+		ConditionID: "0xrange",
+		Question:    "Bitcoin price on Friday?",
+		Tokens: []polymarketToken{
+			{TokenID: "1", Outcome: "<$95k", Price: 0.1},
+			{TokenID: "2", Outcome: "$95k-$100k", Price: 0.7},
+			{TokenID: "3", Outcome: ">$100k", Price: 0.2},
+		},
+	})
+
+	if len(market.Outcomes) != 3 {
+		t.Fatalf("expected 3 outcomes, got %+v", market.Outcomes)
+	}
+	mid := market.OutcomeMarkets()[1]
+	if mid.ID != "0xrange" || mid.Outcome != "$95k-$100k" || mid.OutcomeYesPrice != 0.7 {
+		t.Errorf("expected the $95k-$100k outcome at 0.7, got %+v", mid)
+	}
+	if len(mid.Tokens) != 1 || mid.Tokens[0].TokenID != "2" || mid.Tokens[0].Outcome != "Yes" {
+		t.Errorf("expected the outcome's token as YES, got %+v", mid.Tokens)
+	}
+
+	// The winning token settles YES on its outcome and NO on the others
+	market.Closed = true
+	market.Tokens[1].Winner = true
+	resolution := resolutionFromMarket(market)
+	if resolution.SettlementPriceFor("$95k-$100k", "YES") != 1.0 || resolution.SettlementPriceFor("$95k-$100k", "NO") != 0.0 {
+		t.Errorf("expected the winning outcome to settle YES, got %+v", resolution)
+	}
+	if resolution.SettlementPriceFor(">$100k", "YES") != 0.0 || resolution.SettlementPriceFor(">$100k", "NO") != 1.0 {
+		t.Errorf("expected a losing outcome to settle NO, got %+v", resolution)
+	}
+
+	binary := convertMarket(polymarketMarket{Tokens: []polymarketToken{{Outcome: "Yes"}, {Outcome: "No"}}})
+	if len(binary.Outcomes) != 0 {
+		t.Errorf("expected a YES/NO market to have no outcomes, got %+v", binary.Outcomes)
+	}
+}
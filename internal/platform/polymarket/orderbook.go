@@ -1,6 +1,7 @@
 package polymarket
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -23,10 +24,10 @@ type polymarketBookLevel struct {
 }
 
 // GetOrderBook fetches the order book for a specific token.
-func (c *Client) GetOrderBook(tokenID string) (*types.OrderBook, error) {
+func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
 	path := fmt.Sprintf("/book?token_id=%s", tokenID)
 
-	body, err := c.doPublicRequest("GET", path)
+	body, err := c.doPublicRequest(ctx, "GET", path)
 	if err != nil {
 		return nil, fmt.Errorf("get order book: %w", err)
 	}
@@ -73,7 +74,7 @@ func (c *Client) GetOrderBook(tokenID string) (*types.OrderBook, error) {
 }
 
 // GetMarketOrderBooks fetches order books for all tokens in a market.
-func (c *Client) GetMarketOrderBooks(conditionID string) (map[string]*types.OrderBook, error) {
+func (c *Client) GetMarketOrderBooks(ctx context.Context, conditionID string) (map[string]*types.OrderBook, error) {
 	// First get the market to find token IDs
 	market, err := c.GetMarket(conditionID)
 	if err != nil {
@@ -82,7 +83,7 @@ func (c *Client) GetMarketOrderBooks(conditionID string) (map[string]*types.Orde
 
 	result := make(map[string]*types.OrderBook)
 	for _, token := range market.Tokens {
-		ob, err := c.GetOrderBook(token.TokenID)
+		ob, err := c.GetOrderBook(ctx, token.TokenID)
 		if err != nil {
 			// Continue with other tokens if one fails
 			continue
@@ -1,9 +1,12 @@
 package polymarket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -24,10 +27,10 @@ type OrderResponse struct {
 // PlaceOrder places an order on Polymarket.
 // When dryRun is true, it returns a simulated result without actually placing the order.
 // When dryRun is false, it submits the order to the CLOB API for real execution.
-func (c *Client) PlaceOrder(order types.Order, dryRun bool) (types.OrderResult, error) {
+func (c *Client) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
 	// Validate order fields
 	if err := validateOrder(order); err != nil {
-		return types.OrderResult{}, err
+		return types.OrderResult{}, fmt.Errorf("%w: %w", types.ErrOrderNotPlaced, err)
 	}
 
 	if dryRun {
@@ -43,8 +46,16 @@ func (c *Client) PlaceOrder(order types.Order, dryRun bool) (types.OrderResult,
 		Float64("size", order.Size).
 		Msg("⚠️ PLACING LIVE ORDER ON POLYMARKET")
 
-	// Build the order payload
-	payload, err := c.buildOrderPayload(order)
+	// Build and sign the order for the market's exchange contract
+	key, err := c.signingKey()
+	if err != nil {
+		return types.OrderResult{}, err
+	}
+	params, err := c.getOrderParams(ctx, order.TokenID)
+	if err != nil {
+		return types.OrderResult{}, err
+	}
+	payload, err := c.buildOrderPayload(order, key, params)
 	if err != nil {
 		return types.OrderResult{}, fmt.Errorf("build order payload: %w", err)
 	}
@@ -62,13 +73,13 @@ func (c *Client) PlaceOrder(order types.Order, dryRun bool) (types.OrderResult,
 	}
 
 	// Submit to CLOB API
-	respBody, err := c.doRequest("POST", "/order", body)
+	respBody, err := c.doRequest(ctx, "POST", "/order", body)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("market_id", order.MarketID).
 			Msg("Failed to place order")
-		return types.OrderResult{}, fmt.Errorf("place order: %w", err)
+		return types.OrderResult{}, fmt.Errorf("place order: %w", rejected(err))
 	}
 
 	// Parse response
@@ -83,7 +94,7 @@ func (c *Client) PlaceOrder(order types.Order, dryRun bool) (types.OrderResult,
 			Str("error_msg", resp.ErrorMsg).
 			Str("market_id", order.MarketID).
 			Msg("Order placement failed")
-		return types.OrderResult{}, fmt.Errorf("order rejected: %s", resp.ErrorMsg)
+		return types.OrderResult{}, fmt.Errorf("%w: order rejected: %s", types.ErrOrderNotPlaced, resp.ErrorMsg)
 	}
 
 	log.Info().
@@ -107,10 +118,88 @@ func (c *Client) PlaceOrder(order types.Order, dryRun bool) (types.OrderResult,
 	}, nil
 }
 
-// buildOrderPayload constructs the order payload for the CLOB API.
-// Based on Polymarket CLOB API documentation:
+// rejected wraps an error placing an order in types.ErrOrderNotPlaced if
+// the API refused the request: a 4xx response is never executed.
+func rejected(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+		return fmt.Errorf("%w: %w", types.ErrOrderNotPlaced, err)
+	}
+	return err
+}
+
+// orderPayload is the body of an order submitted to the CLOB API.
+type orderPayload struct {
+	Order     signedOrder `json:"order"`
+	Owner     string      `json:"owner"` // API key the order is placed under
+	OrderType string      `json:"orderType"`
+}
+
+// orderParams are the market settings an order must be signed with.
+type orderParams struct {
+	NegRisk    bool // Settled by the neg-risk exchange contract
+	FeeRateBps int  // Fee rate the market charges takers, in basis points
+}
+
+// getOrderParams fetches the exchange contract and fee rate of a token's
+// market, which the signed order must match.
+func (c *Client) getOrderParams(ctx context.Context, tokenID string) (orderParams, error) {
+	var params orderParams
+
+	body, err := c.doRequest(ctx, "GET", "/neg-risk?token_id="+url.QueryEscape(tokenID), nil)
+	if err != nil {
+		return params, fmt.Errorf("get neg risk: %w", err)
+	}
+	var negRisk struct {
+		NegRisk bool `json:"neg_risk"`
+	}
+	if err := json.Unmarshal(body, &negRisk); err != nil {
+		return params, fmt.Errorf("parse neg risk response: %w", err)
+	}
+	params.NegRisk = negRisk.NegRisk
+
+	body, err = c.doRequest(ctx, "GET", "/fee-rate?token_id="+url.QueryEscape(tokenID), nil)
+	if err != nil {
+		return params, fmt.Errorf("get fee rate: %w", err)
+	}
+	var feeRate struct {
+		BaseFee int `json:"base_fee"`
+	}
+	if err := json.Unmarshal(body, &feeRate); err != nil {
+		return params, fmt.Errorf("parse fee rate response: %w", err)
+	}
+	params.FeeRateBps = feeRate.BaseFee
+
+	return params, nil
+}
+
+// signingKey returns the configured private key live orders are signed
+// with.
+func (c *Client) signingKey() (*privateKey, error) {
+	if c.creds.PrivateKey == "" {
+		return nil, fmt.Errorf("private key not configured (set POLYMARKET_PRIVATE_KEY)")
+	}
+	key, err := parsePrivateKey(c.creds.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("POLYMARKET_PRIVATE_KEY: %w", err)
+	}
+	return key, nil
+}
+
+// buildOrderPayload constructs the order payload for the CLOB API, signed
+// by key. Based on Polymarket CLOB API documentation:
 // https://docs.polymarket.com/developers/CLOB/orders/create-order
-func (c *Client) buildOrderPayload(order types.Order) (map[string]interface{}, error) {
+func (c *Client) buildOrderPayload(order types.Order, key *privateKey, params orderParams) (*orderPayload, error) {
+	// The signer's own wallet holds the funds unless it signs for a proxy
+	signer := key.address()
+	maker := signer
+	if c.creds.SignatureType != SignatureTypeEOA {
+		if c.creds.WalletAddress == "" {
+			return nil, fmt.Errorf("wallet address not configured (set POLYMARKET_WALLET_ADDRESS)")
+		}
+		maker = c.creds.WalletAddress
+	}
+
 	// Calculate maker and taker amounts based on side and price
 	// For a BUY order: makerAmount = size * price (USDC), takerAmount = size (shares)
 	// For a SELL order: makerAmount = size (shares), takerAmount = size * price (USDC)
@@ -118,36 +207,50 @@ func (c *Client) buildOrderPayload(order types.Order) (map[string]interface{}, e
 	// Amounts in the Polymarket API are in the smallest unit:
 	// - USDC: 6 decimals (1 USDC = 1,000,000 units)
 	// - Conditional tokens: 6 decimals
+	//
+	// The exchange accepts shares to 2 decimals and USDC to 4 at a 0.01
+	// tick, so the size is rounded down and the cost rounded.
 	const decimals = 1e6
-
-	var makerAmount, takerAmount string
-	if order.Side == types.OrderSideBuy {
-		// Buying shares: pay USDC, receive shares
-		makerAmount = strconv.FormatUint(uint64(math.Round(order.Size*order.Price*decimals)), 10)
-		takerAmount = strconv.FormatUint(uint64(math.Round(order.Size*decimals)), 10)
-	} else {
-		// Selling shares: pay shares, receive USDC
-		makerAmount = strconv.FormatUint(uint64(math.Round(order.Size*decimals)), 10)
-		takerAmount = strconv.FormatUint(uint64(math.Round(order.Size*order.Price*decimals)), 10)
+	shares := math.Floor(order.Size*100+1e-9) / 100
+	if shares <= 0 {
+		return nil, fmt.Errorf("size %v rounds to no shares", order.Size)
 	}
+	usdc := math.Round(shares*order.Price*1e4) / 1e4
+	sharesUnits := strconv.FormatUint(uint64(math.Round(shares*decimals)), 10)
+	usdcUnits := strconv.FormatUint(uint64(math.Round(usdc*decimals)), 10)
 
-	// Map order type and time-in-force to API format
-	orderType := mapOrderTypeToAPI(order.Type, order.TimeInForce)
+	makerAmount, takerAmount := usdcUnits, sharesUnits
+	if order.Side != types.OrderSideBuy {
+		makerAmount, takerAmount = sharesUnits, usdcUnits
+	}
 
-	// Build payload
-	payload := map[string]interface{}{
-		"tokenID":   order.TokenID,
-		"price":     formatPrice(order.Price),
-		"size":      order.Size,
-		"side":      mapSideToAPI(order.Side),
-		"orderType": orderType,
-		// The following fields would be needed for full implementation
-		// but require EIP-712 signing which is handled by the API credentials
-		"makerAmount": makerAmount,
-		"takerAmount": takerAmount,
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+	signed := signedOrder{
+		Salt:          salt,
+		Maker:         maker,
+		Signer:        signer,
+		Taker:         zeroAddress,
+		TokenID:       order.TokenID,
+		MakerAmount:   makerAmount,
+		TakerAmount:   takerAmount,
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    strconv.Itoa(params.FeeRateBps),
+		Side:          mapSideToAPI(order.Side),
+		SignatureType: c.creds.SignatureType,
+	}
+	if err := signed.sign(key, params.NegRisk); err != nil {
+		return nil, fmt.Errorf("sign order: %w", err)
 	}
 
-	return payload, nil
+	return &orderPayload{
+		Order:     signed,
+		Owner:     c.creds.APIKey,
+		OrderType: mapOrderTypeToAPI(order.Type, order.TimeInForce),
+	}, nil
 }
 
 // formatPrice formats a price (0.0-1.0) to the API format (2 decimal string).
@@ -162,7 +265,7 @@ func mapOrderTypeToAPI(orderType types.OrderType, tif types.TimeInForce) string
 	case types.TimeInForceFOK:
 		return "FOK"
 	case types.TimeInForceIOC:
-		return "IOC" // Polymarket calls this FAK (Fill-And-Kill) but accepts IOC
+		return "FAK" // Fill-And-Kill; the CLOB rejects "IOC"
 	case types.TimeInForceGTC:
 		return "GTC"
 	default:
@@ -221,3 +324,139 @@ func simulateOrder(order types.Order) types.OrderResult {
 		CreatedAt: time.Now(),
 	}
 }
+
+// openOrder represents an order as returned by the /data/orders endpoint.
+type openOrder struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Market       string `json:"market"`
+	AssetID      string `json:"asset_id"`
+	Side         string `json:"side"`
+	OriginalSize string `json:"original_size"`
+	SizeMatched  string `json:"size_matched"`
+	Price        string `json:"price"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// cancelResponse represents the API response from order cancellation.
+type cancelResponse struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"`
+}
+
+// GetOpenOrders returns the resting orders for a market (condition ID).
+func (c *Client) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	path := "/data/orders?market=" + url.QueryEscape(marketID)
+
+	body, err := c.doRequest(context.Background(), "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+
+	var orders []openOrder
+	if err := json.Unmarshal(body, &orders); err != nil {
+		// Some deployments wrap the list in a data field
+		var wrapped struct {
+			Data []openOrder `json:"data"`
+		}
+		if err2 := json.Unmarshal(body, &wrapped); err2 != nil {
+			return nil, fmt.Errorf("parse open orders: %w", err)
+		}
+		orders = wrapped.Data
+	}
+
+	results := make([]types.OrderResult, 0, len(orders))
+	for _, o := range orders {
+		results = append(results, convertOpenOrder(o))
+	}
+
+	return results, nil
+}
+
+// GetOrderStatus returns the current state of an order by ID.
+func (c *Client) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	body, err := c.doRequest(context.Background(), "GET", "/data/order/"+url.PathEscape(orderID), nil)
+	if err != nil {
+		return types.OrderResult{}, fmt.Errorf("get order status: %w", err)
+	}
+
+	var o openOrder
+	if err := json.Unmarshal(body, &o); err != nil {
+		return types.OrderResult{}, fmt.Errorf("parse order: %w", err)
+	}
+	if o.ID == "" {
+		return types.OrderResult{}, fmt.Errorf("order %s not found", orderID)
+	}
+
+	return convertOpenOrder(o), nil
+}
+
+// CancelOrder cancels a resting order by ID.
+// Returns an error if the exchange reports the order as not cancelled.
+func (c *Client) CancelOrder(orderID string) error {
+	body, err := json.Marshal(map[string]string{"orderID": orderID})
+	if err != nil {
+		return fmt.Errorf("marshal cancel payload: %w", err)
+	}
+
+	respBody, err := c.doRequest(context.Background(), "DELETE", "/order", body)
+	if err != nil {
+		return fmt.Errorf("cancel order: %w", err)
+	}
+
+	var resp cancelResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("parse cancel response: %w", err)
+	}
+
+	if reason, ok := resp.NotCanceled[orderID]; ok {
+		return fmt.Errorf("order %s not cancelled: %s", orderID, reason)
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Msg("Order cancelled")
+
+	return nil
+}
+
+// convertOpenOrder maps an API order into the common OrderResult type.
+func convertOpenOrder(o openOrder) types.OrderResult {
+	price, _ := strconv.ParseFloat(o.Price, 64)
+	size, _ := strconv.ParseFloat(o.OriginalSize, 64)
+	filled, _ := strconv.ParseFloat(o.SizeMatched, 64)
+
+	side := types.OrderSideBuy
+	if o.Side == "SELL" {
+		side = types.OrderSideSell
+	}
+
+	return types.OrderResult{
+		OrderID:   o.ID,
+		MarketID:  o.Market,
+		TokenID:   o.AssetID,
+		Side:      side,
+		Price:     price,
+		Size:      size,
+		Filled:    filled,
+		Status:    mapOrderStatus(o.Status, size, filled),
+		CreatedAt: time.Unix(o.CreatedAt, 0),
+	}
+}
+
+// mapOrderStatus maps a Polymarket order status to the common OrderStatus.
+func mapOrderStatus(status string, size, filled float64) types.OrderStatus {
+	switch status {
+	case "LIVE", "live":
+		if filled > 0 && filled < size {
+			return types.OrderStatusPartial
+		}
+		return types.OrderStatusOpen
+	case "MATCHED", "matched":
+		return types.OrderStatusFilled
+	case "CANCELED", "canceled", "CANCELLED", "cancelled":
+		return types.OrderStatusCancelled
+	default:
+		return types.OrderStatusPending
+	}
+}
@@ -1,6 +1,8 @@
 package polymarket
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,7 +28,7 @@ func TestPlaceOrder_DryRun_ReturnsSimulatedResult(t *testing.T) {
 	}
 
 	// Act: place order in DRY-RUN mode
-	result, err := client.PlaceOrder(order, true)
+	result, err := client.PlaceOrder(context.Background(), order, true)
 
 	// Assert: should return simulated result without error
 	if err != nil {
@@ -75,12 +77,12 @@ func TestPlaceOrder_DryRun_GeneratesUniqueOrderID(t *testing.T) {
 	}
 
 	// Place two orders and verify they have different IDs
-	result1, err := client.PlaceOrder(order, true)
+	result1, err := client.PlaceOrder(context.Background(), order, true)
 	if err != nil {
 		t.Fatalf("First PlaceOrder should not fail: %v", err)
 	}
 
-	result2, err := client.PlaceOrder(order, true)
+	result2, err := client.PlaceOrder(context.Background(), order, true)
 	if err != nil {
 		t.Fatalf("Second PlaceOrder should not fail: %v", err)
 	}
@@ -176,7 +178,7 @@ func TestPlaceOrder_DryRun_ValidatesOrderFields(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := client.PlaceOrder(tt.order, true)
+			_, err := client.PlaceOrder(context.Background(), tt.order, true)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PlaceOrder() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -201,7 +203,7 @@ func TestPlaceOrder_DryRun_SetsCreatedAtTimestamp(t *testing.T) {
 	}
 
 	before := time.Now()
-	result, err := client.PlaceOrder(order, true)
+	result, err := client.PlaceOrder(context.Background(), order, true)
 	after := time.Now()
 
 	if err != nil {
@@ -240,7 +242,7 @@ func TestPlaceOrder_Live_RequiresValidCredentials(t *testing.T) {
 	}
 
 	// Act: attempt to place live order (dryRun=false)
-	_, err := client.PlaceOrder(order, false)
+	_, err := client.PlaceOrder(context.Background(), order, false)
 
 	// Assert: should return an error (either auth error or API error)
 	// The key thing is that it doesn't just error with "not implemented"
@@ -275,7 +277,7 @@ func TestPlaceOrder_Live_BuildsCorrectOrderPayload(t *testing.T) {
 	}
 
 	// Test that the order can be built (will fail on API call, but should get past validation)
-	_, err := client.PlaceOrder(order, false)
+	_, err := client.PlaceOrder(context.Background(), order, false)
 
 	// We expect an error from the API (invalid credentials or network error),
 	// but NOT a "not implemented" error
@@ -291,46 +293,74 @@ func TestBuildOrderPayload(t *testing.T) {
 		APISecret:     "dGVzdC1zZWNyZXQ=",
 		Passphrase:    "test-passphrase",
 		WalletAddress: "0x1234567890123456789012345678901234567890",
+		PrivateKey:    "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		SignatureType: SignatureTypeGnosisSafe,
 	})
 
 	order := types.Order{
 		MarketID:    "condition-123",
-		TokenID:     "token-456",
+		TokenID:     "12345678901234567890",
 		Side:        types.OrderSideBuy,
 		Type:        types.OrderTypeLimit,
 		Price:       0.65,
-		Size:        5.0,
+		Size:        5.009,
 		TimeInForce: types.TimeInForceGTC,
 	}
 
-	payload, err := client.buildOrderPayload(order)
+	key, err := client.signingKey()
+	if err != nil {
+		t.Fatalf("signingKey should not error: %v", err)
+	}
+	payload, err := client.buildOrderPayload(order, key, orderParams{FeeRateBps: 10})
 	if err != nil {
 		t.Fatalf("buildOrderPayload should not error: %v", err)
 	}
 
-	if payload == nil {
-		t.Fatal("buildOrderPayload should return non-nil payload")
+	if payload.Owner != "test-key" || payload.OrderType != "GTC" {
+		t.Errorf("unexpected owner or order type: %+v", payload)
 	}
 
-	// Verify required fields are present
-	if payload["tokenID"] == nil {
-		t.Error("payload should contain tokenID")
+	// The safe holds the funds; the key signs for it
+	signed := payload.Order
+	if signed.Maker != "0x1234567890123456789012345678901234567890" || signed.Signer != "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+		t.Errorf("unexpected maker or signer: %+v", signed)
+	}
+	if signed.Taker != zeroAddress || signed.Expiration != "0" || signed.Nonce != "0" || signed.FeeRateBps != "10" {
+		t.Errorf("unexpected order terms: %+v", signed)
 	}
 
-	if payload["price"] == nil {
-		t.Error("payload should contain price")
+	// Buying 5.00 shares (rounded down) for 3.25 USDC
+	if signed.Side != "BUY" || signed.MakerAmount != "3250000" || signed.TakerAmount != "5000000" {
+		t.Errorf("unexpected side or amounts: %+v", signed)
+	}
+	if signed.Salt <= 0 || !strings.HasPrefix(signed.Signature, "0x") || len(signed.Signature) != 132 {
+		t.Errorf("expected a salt and signature, got %+v", signed)
 	}
 
-	if payload["size"] == nil {
-		t.Error("payload should contain size")
+	// Selling swaps the amounts
+	order.Side = types.OrderSideSell
+	payload, err = client.buildOrderPayload(order, key, orderParams{})
+	if err != nil {
+		t.Fatalf("buildOrderPayload should not error: %v", err)
+	}
+	if payload.Order.Side != "SELL" || payload.Order.MakerAmount != "5000000" || payload.Order.TakerAmount != "3250000" {
+		t.Errorf("unexpected sell order: %+v", payload.Order)
 	}
 
-	if payload["side"] == nil {
-		t.Error("payload should contain side")
+	// Immediate-or-cancel exits are sent as Fill-And-Kill
+	order.TimeInForce = types.TimeInForceIOC
+	payload, err = client.buildOrderPayload(order, key, orderParams{})
+	if err != nil {
+		t.Fatalf("buildOrderPayload should not error: %v", err)
+	}
+	if payload.OrderType != "FAK" {
+		t.Errorf("expected order type FAK for IOC, got %q", payload.OrderType)
 	}
 
-	if payload["orderType"] == nil {
-		t.Error("payload should contain orderType")
+	// Live orders can't be signed without a key
+	client.creds.PrivateKey = ""
+	if _, err := client.PlaceOrder(context.Background(), order, false); err == nil || !strings.Contains(err.Error(), "POLYMARKET_PRIVATE_KEY") {
+		t.Errorf("expected an error naming the missing key, got %v", err)
 	}
 }
 
@@ -342,7 +372,8 @@ func TestMapOrderTypeToAPI(t *testing.T) {
 		expected    string
 	}{
 		{types.OrderTypeLimit, types.TimeInForceGTC, "GTC"},
-		{types.OrderTypeLimit, types.TimeInForceIOC, "IOC"},
+		{types.OrderTypeLimit, types.TimeInForceIOC, "FAK"},
+		{types.OrderTypeMarket, types.TimeInForceIOC, "FAK"},
 		{types.OrderTypeLimit, types.TimeInForceFOK, "FOK"},
 		{types.OrderTypeMarket, types.TimeInForceFOK, "FOK"},
 	}
@@ -377,3 +408,51 @@ func TestMapSideToAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertOpenOrder_MapsPartialFill(t *testing.T) {
+	order := convertOpenOrder(openOrder{
+		ID:           "0xabc",
+		Status:       "LIVE",
+		Market:       "0x123abc",
+		AssetID:      "token-abc",
+		Side:         "BUY",
+		OriginalSize: "20",
+		SizeMatched:  "5",
+		Price:        "0.85",
+	})
+
+	if order.Status != types.OrderStatusPartial {
+		t.Errorf("expected status %v, got %v", types.OrderStatusPartial, order.Status)
+	}
+	if order.Size != 20 || order.Filled != 5 {
+		t.Errorf("expected size 20 filled 5, got size %v filled %v", order.Size, order.Filled)
+	}
+	if order.Side != types.OrderSideBuy {
+		t.Errorf("expected side buy, got %v", order.Side)
+	}
+	if !order.IsResting() {
+		t.Error("partially filled live order should be resting")
+	}
+}
+
+func TestMapOrderStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		size   float64
+		filled float64
+		want   types.OrderStatus
+	}{
+		{"LIVE", 10, 0, types.OrderStatusOpen},
+		{"LIVE", 10, 4, types.OrderStatusPartial},
+		{"MATCHED", 10, 10, types.OrderStatusFilled},
+		{"CANCELED", 10, 0, types.OrderStatusCancelled},
+		{"UNKNOWN", 10, 0, types.OrderStatusPending},
+	}
+
+	for _, tt := range tests {
+		got := mapOrderStatus(tt.status, tt.size, tt.filled)
+		if got != tt.want {
+			t.Errorf("mapOrderStatus(%q, %v, %v) = %v, want %v", tt.status, tt.size, tt.filled, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// CTF redeemPositions function selector:
+	// keccak256("redeemPositions(address,bytes32,bytes32,uint256[])")[:4]
+	redeemPositionsSelector = "01b7037c"
+
+	// Binary markets have two outcome slots, redeemed as index sets 1 and 2
+	binaryIndexSets = 2
+
+	// NegRiskAdapter contract address on Polygon. Neg-risk markets' tokens
+	// are backed by its wrapped collateral, so only it pays them out.
+	negRiskAdapterAddress = "0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296"
+
+	// NegRiskAdapter redeemPositions function selector:
+	// keccak256("redeemPositions(bytes32,uint256[])")[:4]
+	negRiskRedeemSelector = "dbeccb23"
+)
+
+// ErrRedemptionUnavailable is returned when no transaction signer is
+// configured for redeeming positions.
+var ErrRedemptionUnavailable = errors.New("redemption signer not configured (set POLYMARKET_SIGNER_RPC)")
+
+// RedemptionEnabled reports whether a transaction signer is configured, so
+// that RedeemPositions can be used.
+func (c *Client) RedemptionEnabled() bool {
+	return c.signerURL != ""
+}
+
+// RedeemPositions claims the USDC payout for a resolved market by calling
+// redeemPositions on the Conditional Tokens contract, or on the
+// NegRiskAdapter for the YES and NO tokens held in a neg-risk market.
+// Winning tokens are burned and their payout is credited to the wallet;
+// losing tokens pay nothing.
+//
+// The transaction is submitted with eth_sendTransaction to the signer RPC
+// configured in POLYMARKET_SIGNER_RPC (a node or signing proxy that holds
+// the wallet key), so the bot never handles the private key. Returns the
+// transaction hash.
+func (c *Client) RedeemPositions(conditionID string) (string, error) {
+	if c.signerURL == "" {
+		return "", ErrRedemptionUnavailable
+	}
+	if c.creds.WalletAddress == "" {
+		return "", fmt.Errorf("wallet address not configured (set POLYMARKET_WALLET_ADDRESS)")
+	}
+
+	market, err := c.getRedemptionMarket(conditionID)
+	if err != nil {
+		return "", err
+	}
+
+	to, callData := ctfContractAddress, ""
+	if market.NegRisk {
+		amounts, err := c.heldAmounts(market)
+		if err != nil {
+			return "", err
+		}
+		to = negRiskAdapterAddress
+		callData, err = negRiskRedeemCallData(conditionID, amounts)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		callData, err = redeemCallData(conditionID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	params := []interface{}{
+		map[string]string{
+			"from": c.creds.WalletAddress,
+			"to":   to,
+			"data": "0x" + callData,
+		},
+	}
+
+	var txHash string
+	if err := c.rpcCall(c.signerURL, "eth_sendTransaction", params, &txHash); err != nil {
+		return "", fmt.Errorf("send redeem transaction: %w", err)
+	}
+	return txHash, nil
+}
+
+// redeemCallData builds the redeemPositions call data for a binary market
+// collateralized in USDC with no parent collection.
+func redeemCallData(conditionID string) (string, error) {
+	condition := strings.ToLower(strings.TrimPrefix(conditionID, "0x"))
+	if len(condition) != 64 {
+		return "", fmt.Errorf("invalid condition id: %s", conditionID)
+	}
+
+	collateral := strings.ToLower(strings.TrimPrefix(usdcContractAddress, "0x"))
+
+	var b strings.Builder
+	b.WriteString(redeemPositionsSelector)
+	b.WriteString(strings.Repeat("0", 24) + collateral) // collateralToken
+	b.WriteString(strings.Repeat("0", 64))              // parentCollectionId
+	b.WriteString(condition)                            // conditionId
+	b.WriteString(fmt.Sprintf("%064x", 4*32))           // offset of indexSets
+	b.WriteString(fmt.Sprintf("%064x", binaryIndexSets))
+	for i := 0; i < binaryIndexSets; i++ {
+		b.WriteString(fmt.Sprintf("%064x", 1<<i))
+	}
+	return b.String(), nil
+}
+
+// getRedemptionMarket fetches a market's tokens and whether it is neg-risk.
+func (c *Client) getRedemptionMarket(conditionID string) (polymarketMarket, error) {
+	var m polymarketMarket
+	body, err := c.doPublicRequest(context.Background(), "GET", "/markets/"+conditionID)
+	if err != nil {
+		return m, fmt.Errorf("get market: %w", err)
+	}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return m, fmt.Errorf("parse market: %w", err)
+	}
+	return m, nil
+}
+
+// heldAmounts returns how many of a neg-risk market's YES and NO tokens the
+// wallet holds, in the tokens' base units.
+func (c *Client) heldAmounts(m polymarketMarket) ([binaryIndexSets]*big.Int, error) {
+	var amounts [binaryIndexSets]*big.Int
+	for i, outcome := range []string{"Yes", "No"} {
+		tokenID := ""
+		for _, token := range m.Tokens {
+			if strings.EqualFold(token.Outcome, outcome) {
+				tokenID = token.TokenID
+			}
+		}
+		if tokenID == "" {
+			return amounts, fmt.Errorf("market %s has no %s token", m.ConditionID, outcome)
+		}
+
+		callData, err := tokenBalanceCallData(c.creds.WalletAddress, tokenID)
+		if err != nil {
+			return amounts, err
+		}
+		resultHex, err := c.ethCall(ctfContractAddress, callData)
+		if err != nil {
+			return amounts, fmt.Errorf("get %s token balance: %w", outcome, err)
+		}
+		amount := new(big.Int)
+		if digits := strings.TrimPrefix(resultHex, "0x"); digits != "" {
+			if _, ok := amount.SetString(digits, 16); !ok {
+				return amounts, fmt.Errorf("parse %s token balance: %s", outcome, resultHex)
+			}
+		}
+		amounts[i] = amount
+	}
+	return amounts, nil
+}
+
+// negRiskRedeemCallData builds the NegRiskAdapter redeemPositions call data
+// for the YES and NO amounts of a neg-risk market.
+func negRiskRedeemCallData(conditionID string, amounts [binaryIndexSets]*big.Int) (string, error) {
+	condition := strings.ToLower(strings.TrimPrefix(conditionID, "0x"))
+	if len(condition) != 64 {
+		return "", fmt.Errorf("invalid condition id: %s", conditionID)
+	}
+
+	var b strings.Builder
+	b.WriteString(negRiskRedeemSelector)
+	b.WriteString(condition)                  // conditionId
+	b.WriteString(fmt.Sprintf("%064x", 2*32)) // offset of amounts
+	b.WriteString(fmt.Sprintf("%064x", len(amounts)))
+	for _, amount := range amounts {
+		b.WriteString(fmt.Sprintf("%064x", amount))
+	}
+	return b.String(), nil
+}
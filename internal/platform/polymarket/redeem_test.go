@@ -0,0 +1,137 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testConditionID = "0x00000000000000000000000000000000000000000000000000000000000000c1"
+
+func TestRedeemCallData(t *testing.T) {
+	data, err := redeemCallData(testConditionID)
+	if err != nil {
+		t.Fatalf("redeemCallData: %v", err)
+	}
+
+	want := "01b7037c" +
+		"0000000000000000000000002791bca1f2de4661ed88a30c99a7a9449aa84174" + // USDC
+		"0000000000000000000000000000000000000000000000000000000000000000" + // parent collection
+		"00000000000000000000000000000000000000000000000000000000000000c1" + // condition
+		"0000000000000000000000000000000000000000000000000000000000000080" + // array offset
+		"0000000000000000000000000000000000000000000000000000000000000002" + // array length
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002"
+	if data != want {
+		t.Errorf("unexpected call data:\n got %s\nwant %s", data, want)
+	}
+
+	if _, err := redeemCallData("0x1234"); err == nil {
+		t.Error("expected error for short condition id")
+	}
+}
+
+func TestRedeemPositions_SendsTransaction(t *testing.T) {
+	var req jsonRPCRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"condition_id":"` + testConditionID + `","neg_risk":false}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{WalletAddress: "0x00000000000000000000000000000000000000ab"})
+	client.baseURL = server.URL
+	client.signerURL = server.URL
+
+	txHash, err := client.RedeemPositions(testConditionID)
+	if err != nil {
+		t.Fatalf("RedeemPositions failed: %v", err)
+	}
+	if txHash != "0xabc" {
+		t.Errorf("expected tx hash 0xabc, got %s", txHash)
+	}
+
+	if req.Method != "eth_sendTransaction" || len(req.Params) != 1 {
+		t.Fatalf("expected eth_sendTransaction with one param, got %s %v", req.Method, req.Params)
+	}
+	tx := req.Params[0].(map[string]interface{})
+	if !strings.EqualFold(tx["to"].(string), ctfContractAddress) || !strings.HasPrefix(tx["data"].(string), "0x"+redeemPositionsSelector) {
+		t.Errorf("expected redeemPositions call to the CTF contract, got %v", tx)
+	}
+}
+
+func TestRedeemPositions_NegRiskRedeemsThroughAdapter(t *testing.T) {
+	// The wallet holds 10.5 YES and 2 NO tokens
+	balances := map[string]string{
+		"0x" + erc1155BalanceOfSelector + strings.Repeat("0", 62) + "ab" + fmt.Sprintf("%064x", 111): "0xa037a0",
+		"0x" + erc1155BalanceOfSelector + strings.Repeat("0", 62) + "ab" + fmt.Sprintf("%064x", 222): "0x1e8480",
+	}
+	var sent jsonRPCRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.URL.Path != "/markets/"+testConditionID {
+				t.Errorf("unexpected market request %s", r.URL.Path)
+			}
+			w.Write([]byte(`{"condition_id":"` + testConditionID + `","neg_risk":true,"tokens":[
+				{"token_id":"111","outcome":"Yes"},{"token_id":"222","outcome":"No"}]}`))
+			return
+		}
+		var req jsonRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		tx := req.Params[0].(map[string]interface{})
+		switch req.Method {
+		case "eth_call":
+			if !strings.EqualFold(tx["to"].(string), ctfContractAddress) {
+				t.Errorf("expected balances read from the CTF contract, got %v", tx["to"])
+			}
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"` + balances[tx["data"].(string)] + `"}`))
+		case "eth_sendTransaction":
+			sent = req
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xdef"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{WalletAddress: "0x00000000000000000000000000000000000000ab"})
+	client.baseURL = server.URL
+	client.rpcURL = server.URL
+	client.signerURL = server.URL
+
+	txHash, err := client.RedeemPositions(testConditionID)
+	if err != nil {
+		t.Fatalf("RedeemPositions failed: %v", err)
+	}
+	if txHash != "0xdef" {
+		t.Errorf("expected tx hash 0xdef, got %s", txHash)
+	}
+
+	tx := sent.Params[0].(map[string]interface{})
+	want := "0x" + negRiskRedeemSelector +
+		"00000000000000000000000000000000000000000000000000000000000000c1" + // condition
+		"0000000000000000000000000000000000000000000000000000000000000040" + // array offset
+		"0000000000000000000000000000000000000000000000000000000000000002" + // array length
+		"0000000000000000000000000000000000000000000000000000000000a037a0" + // YES amount
+		"00000000000000000000000000000000000000000000000000000000001e8480" //   NO amount
+	if !strings.EqualFold(tx["to"].(string), negRiskAdapterAddress) || tx["data"] != want {
+		t.Errorf("expected redeemPositions call to the NegRiskAdapter, got %v", tx)
+	}
+}
+
+func TestRedeemPositions_RequiresSigner(t *testing.T) {
+	client := NewClientWithCreds(Credentials{WalletAddress: "0x00000000000000000000000000000000000000ab"})
+
+	if client.RedemptionEnabled() {
+		t.Error("expected redemption disabled without a signer")
+	}
+	if _, err := client.RedeemPositions(testConditionID); !errors.Is(err, ErrRedemptionUnavailable) {
+		t.Errorf("expected ErrRedemptionUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package polymarket
+
+import "prediction-bot/internal/platform"
+
+func init() {
+	platform.Register("polymarket", func(opts platform.Options) (platform.Platform, error) {
+		client, err := NewClient()
+		if err != nil {
+			return nil, err
+		}
+		client.httpClient.Transport = opts.WrapTransport("polymarket", client.httpClient.Transport)
+		return client, nil
+	})
+}
@@ -0,0 +1,69 @@
+package polymarket
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// privateKey is a secp256k1 private key that signs Ethereum messages. The
+// standard library doesn't implement the curve: its generic curve
+// arithmetic assumes a = -3.
+type privateKey struct {
+	key *secp256k1.PrivateKey
+}
+
+// parsePrivateKey parses a hex private key, with or without 0x.
+func parsePrivateKey(s string) (*privateKey, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil || len(raw) != 32 {
+		return nil, fmt.Errorf("private key must be 32 bytes of hex")
+	}
+	var d secp256k1.ModNScalar
+	if overflow := d.SetByteSlice(raw); overflow || d.IsZero() {
+		return nil, fmt.Errorf("private key out of range")
+	}
+	return &privateKey{key: secp256k1.NewPrivateKey(&d)}, nil
+}
+
+// address returns the Ethereum address of the key, EIP-55 checksummed.
+func (k *privateKey) address() string {
+	// The uncompressed public key is 0x04 || X || Y
+	pub := k.key.PubKey().SerializeUncompressed()
+	hash := keccak256(pub[1:])
+	return checksumAddress(hash[12:])
+}
+
+// checksumAddress formats a 20-byte address with the EIP-55 mixed-case
+// checksum.
+func checksumAddress(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+	hash := keccak256([]byte(lower))
+	out := []byte(lower)
+	for i, c := range out {
+		// A letter is upper-cased where the hash's nibble is 8 or more
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// sign signs a 32-byte hash, returning the 65-byte r || s || v signature
+// Ethereum verifies, with v 27 or 28. The nonce is derived from the key and
+// hash (RFC 6979) and s is the lower of its two values (EIP-2).
+func (k *privateKey) sign(hash []byte) []byte {
+	// A compact signature is v || r || s, v 27 or 28 for an uncompressed key
+	compact := ecdsa.SignCompact(k.key, hash, false)
+	signature := make([]byte, 65)
+	copy(signature, compact[1:])
+	signature[64] = compact[0]
+	return signature
+}
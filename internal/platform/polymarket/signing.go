@@ -0,0 +1,177 @@
+package polymarket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// polygonChainID is the chain the exchange contracts are on.
+	polygonChainID = 137
+
+	// ctfExchangeAddress is the exchange contract orders on binary markets
+	// are signed for.
+	ctfExchangeAddress = "0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"
+
+	// negRiskExchangeAddress is the exchange contract orders on neg-risk
+	// (multi-outcome) markets are signed for.
+	negRiskExchangeAddress = "0xC5d563A36AE78145C45a50134d48A1215220f80a"
+
+	// zeroAddress as an order's taker lets anyone fill it.
+	zeroAddress = "0x0000000000000000000000000000000000000000"
+
+	// exchangeName and exchangeVersion identify the exchange in the
+	// EIP-712 domain.
+	exchangeName    = "Polymarket CTF Exchange"
+	exchangeVersion = "1"
+)
+
+// Signature types: how the order's maker relates to its signer.
+const (
+	// SignatureTypeEOA signs with the key of the wallet holding the funds.
+	SignatureTypeEOA = 0
+	// SignatureTypePolyProxy signs for a Polymarket proxy wallet (accounts
+	// created with an email login).
+	SignatureTypePolyProxy = 1
+	// SignatureTypeGnosisSafe signs for a Polymarket Gnosis Safe wallet
+	// (accounts created with a browser wallet).
+	SignatureTypeGnosisSafe = 2
+)
+
+var (
+	domainTypeHash = keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	orderTypeHash  = keccak256([]byte("Order(uint256 salt,address maker,address signer,address taker,uint256 tokenId," +
+		"uint256 makerAmount,uint256 takerAmount,uint256 expiration,uint256 nonce,uint256 feeRateBps,uint8 side,uint8 signatureType)"))
+)
+
+// signedOrder is an order as the exchange contract settles it, with the
+// maker's EIP-712 signature. Amounts are in 6-decimal units.
+type signedOrder struct {
+	Salt          int64  `json:"salt"`
+	Maker         string `json:"maker"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TokenID       string `json:"tokenId"`
+	MakerAmount   string `json:"makerAmount"`
+	TakerAmount   string `json:"takerAmount"`
+	Expiration    string `json:"expiration"`
+	Nonce         string `json:"nonce"`
+	FeeRateBps    string `json:"feeRateBps"`
+	Side          string `json:"side"` // BUY or SELL
+	SignatureType int    `json:"signatureType"`
+	Signature     string `json:"signature"`
+}
+
+// newSalt returns a random order salt, below 2^53 so it survives JSON.
+func newSalt() (int64, error) {
+	salt, err := rand.Int(rand.Reader, big.NewInt(1<<53))
+	if err != nil {
+		return 0, fmt.Errorf("generate salt: %w", err)
+	}
+	return salt.Int64(), nil
+}
+
+// sign sets the order's signature by key, for the exchange contract of a
+// neg-risk market or not.
+func (o *signedOrder) sign(key *privateKey, negRisk bool) error {
+	hash, err := o.hash(negRisk)
+	if err != nil {
+		return err
+	}
+	o.Signature = "0x" + hex.EncodeToString(key.sign(hash))
+	return nil
+}
+
+// hash returns the EIP-712 digest of the order the signature is over.
+func (o *signedOrder) hash(negRisk bool) ([]byte, error) {
+	exchange := ctfExchangeAddress
+	if negRisk {
+		exchange = negRiskExchangeAddress
+	}
+	domain, err := domainSeparator(exchangeName, exchangeVersion, polygonChainID, exchange)
+	if err != nil {
+		return nil, err
+	}
+	structHash, err := o.structHash()
+	if err != nil {
+		return nil, err
+	}
+	return typedDataHash(domain, structHash), nil
+}
+
+// structHash returns the EIP-712 hash of the order's fields.
+func (o *signedOrder) structHash() ([]byte, error) {
+	var side int64
+	switch o.Side {
+	case "BUY":
+	case "SELL":
+		side = 1
+	default:
+		return nil, fmt.Errorf("unknown order side %q", o.Side)
+	}
+
+	words := [][]byte{orderTypeHash, encodeUint(big.NewInt(o.Salt))}
+	for _, addr := range []string{o.Maker, o.Signer, o.Taker} {
+		word, err := encodeAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	for _, field := range []struct{ name, value string }{
+		{"tokenId", o.TokenID},
+		{"makerAmount", o.MakerAmount},
+		{"takerAmount", o.TakerAmount},
+		{"expiration", o.Expiration},
+		{"nonce", o.Nonce},
+		{"feeRateBps", o.FeeRateBps},
+	} {
+		n, ok := new(big.Int).SetString(field.value, 10)
+		if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+			return nil, fmt.Errorf("order %s is not a uint256: %q", field.name, field.value)
+		}
+		words = append(words, encodeUint(n))
+	}
+	words = append(words, encodeUint(big.NewInt(side)), encodeUint(big.NewInt(int64(o.SignatureType))))
+
+	return keccak256(words...), nil
+}
+
+// domainSeparator returns the EIP-712 domain separator of a contract.
+func domainSeparator(name, version string, chainID int64, contract string) ([]byte, error) {
+	addr, err := encodeAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256(
+		domainTypeHash,
+		keccak256([]byte(name)),
+		keccak256([]byte(version)),
+		encodeUint(big.NewInt(chainID)),
+		addr,
+	), nil
+}
+
+// typedDataHash returns the EIP-712 digest signed for a struct in a domain.
+func typedDataHash(domainSeparator, structHash []byte) []byte {
+	return keccak256([]byte{0x19, 0x01}, domainSeparator, structHash)
+}
+
+// encodeUint ABI-encodes a non-negative integer as a 32-byte word.
+func encodeUint(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, 32))
+}
+
+// encodeAddress ABI-encodes a hex address as a 32-byte word.
+func encodeAddress(addr string) ([]byte, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(addr, "0x"))
+	if err != nil || len(raw) != 20 {
+		return nil, fmt.Errorf("invalid address %q", addr)
+	}
+	word := make([]byte, 32)
+	copy(word[12:], raw)
+	return word, nil
+}
@@ -0,0 +1,228 @@
+package polymarket
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		// Function selectors this package calls
+		{"balanceOf(address)", "70a08231"},
+		{"balanceOf(address,uint256)", "00fdd58e"},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(keccak256([]byte(tt.input)))
+		if !strings.HasPrefix(got, tt.want) {
+			t.Errorf("keccak256(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPrivateKey_Address(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"0x0000000000000000000000000000000000000000000000000000000000000001", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"},
+		{"4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"},
+	}
+	for _, tt := range tests {
+		key, err := parsePrivateKey(tt.key)
+		if err != nil {
+			t.Fatalf("parsePrivateKey: %v", err)
+		}
+		if got := key.address(); got != tt.want {
+			t.Errorf("address = %s, want %s", got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "0x1234", "not hex", "0x" + strings.Repeat("f", 64)} {
+		if _, err := parsePrivateKey(bad); err == nil {
+			t.Errorf("expected %q rejected", bad)
+		}
+	}
+}
+
+func TestPrivateKey_SignsPersonalMessage(t *testing.T) {
+	// web3.eth.accounts.sign("Some data", key)
+	key, _ := parsePrivateKey("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	hash := keccak256([]byte("\x19Ethereum Signed Message:\n9Some data"))
+
+	want := "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd" +
+		"6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a029" + "1c"
+	if got := hex.EncodeToString(key.sign(hash)); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestTypedData_SignsEIP712Example(t *testing.T) {
+	// The Mail example of the EIP-712 specification
+	domain, err := domainSeparator("Ether Mail", "1", 1, "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+	if err != nil {
+		t.Fatalf("domainSeparator: %v", err)
+	}
+	if got := hex.EncodeToString(domain); got != "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f" {
+		t.Errorf("domain separator = %s", got)
+	}
+
+	person := func(name, wallet string) []byte {
+		addr, _ := encodeAddress(wallet)
+		return keccak256(keccak256([]byte("Person(string name,address wallet)")), keccak256([]byte(name)), addr)
+	}
+	mail := keccak256(
+		keccak256([]byte("Mail(Person from,Person to,string contents)Person(string name,address wallet)")),
+		person("Cow", "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+		person("Bob", "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"),
+		keccak256([]byte("Hello, Bob!")),
+	)
+	digest := typedDataHash(domain, mail)
+	if got := hex.EncodeToString(digest); got != "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Errorf("digest = %s", got)
+	}
+
+	key, _ := parsePrivateKey(hex.EncodeToString(keccak256([]byte("cow"))))
+	if key.address() != "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826" {
+		t.Errorf("unexpected signer %s", key.address())
+	}
+	want := "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	if got := hex.EncodeToString(key.sign(digest)); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+}
+
+func TestSignedOrder_Sign(t *testing.T) {
+	key, _ := parsePrivateKey("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	order := signedOrder{
+		Salt:          479249096354,
+		Maker:         key.address(),
+		Signer:        key.address(),
+		Taker:         zeroAddress,
+		TokenID:       "71321045679252212594626385532706912750332728571942532289631379312455583992563",
+		MakerAmount:   "50000000",
+		TakerAmount:   "100000000",
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    "0",
+		Side:          "BUY",
+		SignatureType: SignatureTypeEOA,
+	}
+	if err := order.sign(key, false); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if len(order.Signature) != 132 {
+		t.Fatalf("expected a 65-byte hex signature, got %s", order.Signature)
+	}
+
+	// The signature verifies for the signer over the order's digest
+	hash, _ := order.hash(false)
+	raw, _ := hex.DecodeString(strings.TrimPrefix(order.Signature, "0x"))
+	if !verifySignature(key, hash, raw) {
+		t.Error("expected the order signature to verify")
+	}
+
+	// Neg-risk markets are settled by another contract
+	negRisk, _ := order.hash(true)
+	if hex.EncodeToString(negRisk) == hex.EncodeToString(hash) {
+		t.Error("expected a different digest for the neg-risk exchange")
+	}
+
+	order.TokenID = "not-a-number"
+	if err := order.sign(key, false); err == nil {
+		t.Error("expected a non-numeric token ID rejected")
+	}
+}
+
+func TestSignedOrder_SignsKnownVectors(t *testing.T) {
+	// Fixed key (the well-known first Hardhat test account) and salts, so
+	// the digest and deterministic (RFC 6979) signature are fixed. Expected
+	// values were computed with a separate keccak, EIP-712 and secp256k1
+	// implementation, checked against the web3 vector above.
+	key, _ := parsePrivateKey("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if key.address() != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Fatalf("unexpected signer %s", key.address())
+	}
+
+	tests := []struct {
+		name        string
+		negRisk     bool
+		salt        int64
+		tokenID     string
+		makerAmount string
+		takerAmount string
+		side        string
+		wantHash    string
+		wantSig     string
+	}{
+		{
+			name:        "buy on the CTF exchange",
+			salt:        479249096354,
+			tokenID:     "71321045679252212594626385532706912750332728571942532289631379312455583992563",
+			makerAmount: "50000000",
+			takerAmount: "100000000",
+			side:        "BUY",
+			wantHash:    "2d4e37d43ce67ac26fd34fbded7ac34fdcba1b2aff632aac52b36483f1d5eeb8",
+			wantSig: "0x4e4a18de9ac827f073445bb64331b74a5f57feed1b86424cfaa61db51ae0c0de" +
+				"291110ad3c3541ac576a93bfd35adca6f9e4861ce3d46123e56eeadf3e55fd0c" + "1c",
+		},
+		{
+			name:        "sell on the neg-risk exchange",
+			negRisk:     true,
+			salt:        1234567890,
+			tokenID:     "52114319501245915516055106046884209969926127482827954674443846427813813222426",
+			makerAmount: "100000000",
+			takerAmount: "40000000",
+			side:        "SELL",
+			wantHash:    "e3c31209c41f11417746e147efaa963079a3eca07663e3243afa5cb3f183f87c",
+			wantSig: "0xb1b9b76d2ac8a98918878e8e04c928a18c4b71b869d4483c1cc4dc838b03e3e3" +
+				"4c0a6543bfe198e17c6727075a49c9adbd3c15f02eb90823089e4325258ee444" + "1b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := signedOrder{
+				Salt:          tt.salt,
+				Maker:         key.address(),
+				Signer:        key.address(),
+				Taker:         zeroAddress,
+				TokenID:       tt.tokenID,
+				MakerAmount:   tt.makerAmount,
+				TakerAmount:   tt.takerAmount,
+				Expiration:    "0",
+				Nonce:         "0",
+				FeeRateBps:    "0",
+				Side:          tt.side,
+				SignatureType: SignatureTypeEOA,
+			}
+			hash, err := order.hash(tt.negRisk)
+			if err != nil {
+				t.Fatalf("hash: %v", err)
+			}
+			if got := hex.EncodeToString(hash); got != tt.wantHash {
+				t.Errorf("digest = %s, want %s", got, tt.wantHash)
+			}
+			if err := order.sign(key, tt.negRisk); err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			if order.Signature != tt.wantSig {
+				t.Errorf("signature = %s, want %s", order.Signature, tt.wantSig)
+			}
+		})
+	}
+}
+
+// verifySignature checks an r || s || v signature of hash by key: the
+// public key recovered from it is key's.
+func verifySignature(key *privateKey, hash, signature []byte) bool {
+	compact := append([]byte{signature[64]}, signature[:64]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, hash)
+	return err == nil && pub.IsEqual(key.key.PubKey())
+}
@@ -0,0 +1,99 @@
+package polymarket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// statusSummary is the subset of the status page summary the bot uses.
+type statusSummary struct {
+	Status struct {
+		Indicator   string `json:"indicator"` // none, minor, major, critical, maintenance
+		Description string `json:"description"`
+	} `json:"status"`
+	ScheduledMaintenances []scheduledMaintenance `json:"scheduled_maintenances"`
+}
+
+// scheduledMaintenance is a maintenance announced on the status page.
+type scheduledMaintenance struct {
+	Name           string    `json:"name"`
+	Status         string    `json:"status"` // scheduled, in_progress, verifying, completed
+	ScheduledFor   time.Time `json:"scheduled_for"`
+	ScheduledUntil time.Time `json:"scheduled_until"`
+}
+
+// GetPlatformStatus reports whether Polymarket is accepting trades, based on
+// its public status page. Major and critical outages and maintenance in
+// progress halt trading; announced maintenance is reported scheduled from
+// types.MaintenanceLeadTime before it starts.
+func (c *Client) GetPlatformStatus() (types.PlatformStatus, error) {
+	resp, err := c.httpClient.Get(c.statusURL)
+	if err != nil {
+		return types.PlatformStatus{}, fmt.Errorf("get system status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return types.PlatformStatus{}, fmt.Errorf("read status response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return types.PlatformStatus{}, fmt.Errorf("status page error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var summary statusSummary
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return types.PlatformStatus{}, fmt.Errorf("parse status response: %w", err)
+	}
+
+	return summary.toPlatformStatus(c.Name(), time.Now()), nil
+}
+
+// toPlatformStatus converts a status page summary as of now.
+func (s statusSummary) toPlatformStatus(platformName string, now time.Time) types.PlatformStatus {
+	status := types.PlatformStatus{
+		Platform:      platformName,
+		TradingActive: true,
+		CheckedAt:     now,
+	}
+
+	switch s.Status.Indicator {
+	case "major", "critical":
+		status.TradingActive = false
+		status.Message = s.Status.Description
+	case "maintenance":
+		status.Maintenance = true
+		status.Message = s.Status.Description
+	}
+
+	for _, m := range s.ScheduledMaintenances {
+		switch m.Status {
+		case "in_progress", "verifying":
+			status.Maintenance = true
+			status.Message = m.Name
+			status.ResumeAt = m.ScheduledUntil
+		case "scheduled":
+			if now.Before(m.ScheduledFor.Add(-types.MaintenanceLeadTime)) {
+				continue
+			}
+			if !m.ScheduledUntil.IsZero() && !now.Before(m.ScheduledUntil) {
+				continue
+			}
+			// Past its start it is under way, if not yet reported so
+			if now.Before(m.ScheduledFor) {
+				status.MaintenanceScheduled = true
+			} else {
+				status.Maintenance = true
+			}
+			status.Message = fmt.Sprintf("%s scheduled at %s", m.Name, m.ScheduledFor.Format(time.RFC3339))
+			status.ResumeAt = m.ScheduledUntil
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,64 @@
+package polymarket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetPlatformStatus_Operational(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":{"indicator":"none","description":"All Systems Operational"},"scheduled_maintenances":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithCreds(Credentials{})
+	client.statusURL = server.URL
+
+	status, err := client.GetPlatformStatus()
+	if err != nil {
+		t.Fatalf("GetPlatformStatus failed: %v", err)
+	}
+	if status.Halted() || status.Platform != "polymarket" {
+		t.Errorf("expected active polymarket status, got %+v", status)
+	}
+}
+
+func TestStatusSummary_ToPlatformStatus(t *testing.T) {
+	now := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	// Major outage halts trading
+	var summary statusSummary
+	summary.Status.Indicator = "major"
+	summary.Status.Description = "Partial System Outage"
+	if status := summary.toPlatformStatus("polymarket", now); status.TradingActive || !status.Halted() {
+		t.Errorf("expected major outage to halt trading, got %+v", status)
+	}
+
+	// Maintenance starting within the lead time pauses entries, not trading
+	summary = statusSummary{}
+	summary.Status.Indicator = "none"
+	summary.ScheduledMaintenances = []scheduledMaintenance{{
+		Name:           "CLOB upgrade",
+		Status:         "scheduled",
+		ScheduledFor:   now.Add(10 * time.Minute),
+		ScheduledUntil: now.Add(time.Hour),
+	}}
+	status := summary.toPlatformStatus("polymarket", now)
+	if status.Halted() || !status.EntriesPaused() || !status.ResumeAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected imminent maintenance until %v, got %+v", now.Add(time.Hour), status)
+	}
+
+	// Once it starts orders can't execute
+	if status := summary.toPlatformStatus("polymarket", now.Add(15*time.Minute)); !status.Halted() {
+		t.Errorf("expected maintenance under way to halt trading, got %+v", status)
+	}
+
+	// Maintenance further out does not
+	summary.ScheduledMaintenances[0].ScheduledFor = now.Add(3 * time.Hour)
+	summary.ScheduledMaintenances[0].ScheduledUntil = now.Add(4 * time.Hour)
+	if status := summary.toPlatformStatus("polymarket", now); status.Halted() {
+		t.Errorf("expected distant maintenance to be ignored, got %+v", status)
+	}
+}
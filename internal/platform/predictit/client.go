@@ -0,0 +1,96 @@
+// Package predictit reads markets from PredictIt's public market data API.
+// PredictIt has no trading or account API, so the adapter is read-only:
+// enable it in dry-run to paper trade its markets.
+package predictit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+const (
+	// baseURL is the PredictIt site (host only)
+	baseURL = "https://www.predictit.org"
+	// apiPath is the market data API prefix
+	apiPath = "/api/marketdata"
+)
+
+// ErrNoAccountAPI is returned for account data, which PredictIt doesn't
+// expose through its API.
+var ErrNoAccountAPI = errors.New("predictit has no account API")
+
+// Client is a PredictIt market data client.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// APIError is returned when the PredictIt API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// NewClient creates a new PredictIt client. The market data API is public,
+// so no credentials are needed.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		baseURL: baseURL,
+	}
+}
+
+// Name returns the platform identifier.
+func (c *Client) Name() string {
+	return "predictit"
+}
+
+// doRequest performs a GET request to the market data API.
+func (c *Client) doRequest(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+apiPath+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
+
+// GetBalance implements platform.Platform interface.
+// PredictIt has no account API, so it always fails.
+func (c *Client) GetBalance() (float64, error) {
+	return 0, ErrNoAccountAPI
+}
+
+// GetPositions implements platform.Platform interface.
+// PredictIt has no account API, so it always fails.
+func (c *Client) GetPositions() ([]types.Position, error) {
+	return nil, ErrNoAccountAPI
+}
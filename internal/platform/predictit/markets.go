@@ -0,0 +1,242 @@
+package predictit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// investmentLimit is the most one account may invest in a contract.
+// PredictIt doesn't publish order book depth, so it stands in for the
+// liquidity available at the best price.
+const investmentLimit = 850.0
+
+// eastern is the time zone of contract end dates.
+var eastern = loadEastern()
+
+func loadEastern() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return time.FixedZone("EST", -5*60*60)
+}
+
+// predictitMarket represents a market from the PredictIt API.
+type predictitMarket struct {
+	ID        int                 `json:"id"`
+	Name      string              `json:"name"`
+	ShortName string              `json:"shortName"`
+	URL       string              `json:"url"`
+	Status    string              `json:"status"` // "Open" or "Closed"
+	Contracts []predictitContract `json:"contracts"`
+}
+
+// predictitContract represents a contract (outcome) of a PredictIt market.
+// Prices are null when there is no quote.
+type predictitContract struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	ShortName       string  `json:"shortName"`
+	Status          string  `json:"status"`
+	DateEnd         string  `json:"dateEnd"` // Eastern time, or "N/A"
+	LastTradePrice  float64 `json:"lastTradePrice"`
+	BestBuyYesCost  float64 `json:"bestBuyYesCost"`  // Best YES ask
+	BestBuyNoCost   float64 `json:"bestBuyNoCost"`   // Best NO ask
+	BestSellYesCost float64 `json:"bestSellYesCost"` // Best YES bid
+	BestSellNoCost  float64 `json:"bestSellNoCost"`  // Best NO bid
+}
+
+// allMarketsResponse represents the API response for listing markets.
+type allMarketsResponse struct {
+	Markets []predictitMarket `json:"markets"`
+}
+
+// ListMarkets returns the markets matching the filter criteria. The API
+// always returns every market, so Limit and Offset are applied here.
+func (c *Client) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
+	body, err := c.doRequest(ctx, "/all/")
+	if err != nil {
+		return nil, fmt.Errorf("list markets: %w", err)
+	}
+
+	var response allMarketsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("parse markets response: %w", err)
+	}
+
+	markets := make([]types.Market, 0, len(response.Markets))
+	for _, pm := range response.Markets {
+		market := convertMarket(pm)
+		if filter.IsActive != nil && market.Active != *filter.IsActive {
+			continue
+		}
+		markets = append(markets, market)
+	}
+
+	if filter.Offset > 0 {
+		markets = markets[min(filter.Offset, len(markets)):]
+	}
+	if filter.Limit > 0 && len(markets) > filter.Limit {
+		markets = markets[:filter.Limit]
+	}
+	return markets, nil
+}
+
+// GetMarket fetches the market a market or outcome ID belongs to.
+func (c *Client) GetMarket(marketID string) (*types.Market, error) {
+	pm, err := c.getMarket(marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	market := convertMarket(*pm)
+	return &market, nil
+}
+
+func (c *Client) getMarket(marketID string) (*predictitMarket, error) {
+	id, _, _ := strings.Cut(marketID, ":")
+	body, err := c.doRequest(context.Background(), "/markets/"+id)
+	if err != nil {
+		return nil, fmt.Errorf("get market: %w", err)
+	}
+
+	var pm predictitMarket
+	if err := json.Unmarshal(body, &pm); err != nil {
+		return nil, fmt.Errorf("parse market response: %w", err)
+	}
+	return &pm, nil
+}
+
+// GetOrderBook returns the best bid and ask of a contract's YES or NO side,
+// each sized to the investment limit.
+func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	marketID, contractID, side, err := parseTokenID(tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	pm, err := c.getMarket(marketID)
+	if err != nil {
+		return nil, fmt.Errorf("get order book: %w", err)
+	}
+
+	for _, contract := range pm.Contracts {
+		if strconv.Itoa(contract.ID) != contractID {
+			continue
+		}
+
+		bid, ask := contract.BestSellYesCost, contract.BestBuyYesCost
+		if side == "NO" {
+			bid, ask = contract.BestSellNoCost, contract.BestBuyNoCost
+		}
+		book := &types.OrderBook{MarketID: outcomeID(pm.ID, contract.ID), TokenID: tokenID}
+		if bid > 0 {
+			book.Bids = []types.Level{{Price: bid, Size: investmentLimit / bid}}
+		}
+		if ask > 0 {
+			book.Asks = []types.Level{{Price: ask, Size: investmentLimit / ask}}
+		}
+		return book, nil
+	}
+	return nil, fmt.Errorf("get order book: contract %s not found in market %s", contractID, marketID)
+}
+
+// convertMarket converts a PredictIt market to the common Market type. Each
+// contract trades as a binary whose ID is "<market ID>:<contract ID>", with
+// YES and NO tokens named "<market ID>:<contract ID>:YES" and "...:NO". A
+// market with a single contract is that binary; one with several lists them
+// as outcomes.
+func convertMarket(pm predictitMarket) types.Market {
+	market := types.Market{
+		ID:          strconv.Itoa(pm.ID),
+		Platform:    "predictit",
+		ConditionID: strconv.Itoa(pm.ID),
+		Title:       pm.Name,
+		Description: pm.URL,
+		Liquidity:   investmentLimit,
+		Active:      pm.Status == "Open",
+		Closed:      pm.Status == "Closed",
+	}
+
+	for _, contract := range pm.Contracts {
+		if end := parseDateEnd(contract.DateEnd); !end.IsZero() && (market.EndDate.IsZero() || end.Before(market.EndDate)) {
+			market.EndDate = end
+		}
+	}
+
+	if len(pm.Contracts) == 1 {
+		contract := pm.Contracts[0]
+		price, spread := quote(contract)
+		market.ID = outcomeID(pm.ID, contract.ID)
+		market.OutcomeYesPrice = price
+		market.OutcomeNoPrice = 1 - price
+		market.Spread = spread
+		market.Tokens = contractTokens(pm.ID, contract.ID, price)
+		return market
+	}
+
+	market.Outcomes = make([]types.Outcome, 0, len(pm.Contracts))
+	for _, contract := range pm.Contracts {
+		price, spread := quote(contract)
+		market.Outcomes = append(market.Outcomes, types.Outcome{
+			MarketID: outcomeID(pm.ID, contract.ID),
+			Name:     contract.Name,
+			Price:    price,
+			Spread:   spread,
+			Tokens:   contractTokens(pm.ID, contract.ID, price),
+		})
+	}
+	return market
+}
+
+// quote returns a contract's YES price, the mid-price or else the last
+// trade, and its spread.
+func quote(contract predictitContract) (price, spread float64) {
+	if contract.BestBuyYesCost > 0 && contract.BestSellYesCost > 0 {
+		return (contract.BestBuyYesCost + contract.BestSellYesCost) / 2,
+			contract.BestBuyYesCost - contract.BestSellYesCost
+	}
+	return contract.LastTradePrice, 0
+}
+
+// parseDateEnd parses a contract end date, returning the zero time when
+// the contract has none.
+func parseDateEnd(dateEnd string) time.Time {
+	end, err := time.ParseInLocation("2006-01-02T15:04:05", dateEnd, eastern)
+	if err != nil {
+		return time.Time{}
+	}
+	return end
+}
+
+// outcomeID returns the ID a contract trades under.
+func outcomeID(marketID, contractID int) string {
+	return fmt.Sprintf("%d:%d", marketID, contractID)
+}
+
+// contractTokens returns the YES and NO tokens of a contract.
+func contractTokens(marketID, contractID int, yesPrice float64) []types.Token {
+	id := outcomeID(marketID, contractID)
+	return []types.Token{
+		{TokenID: id + ":YES", Outcome: "Yes", Price: yesPrice},
+		{TokenID: id + ":NO", Outcome: "No", Price: 1 - yesPrice},
+	}
+}
+
+// parseTokenID splits a token ID into its market, contract and side.
+func parseTokenID(tokenID string) (marketID, contractID, side string, err error) {
+	parts := strings.Split(tokenID, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid predictit token ID %q", tokenID)
+	}
+	side = strings.ToUpper(parts[2])
+	if side != "YES" && side != "NO" {
+		return "", "", "", fmt.Errorf("invalid predictit token ID %q: side must be YES or NO", tokenID)
+	}
+	return parts[0], parts[1], side, nil
+}
@@ -0,0 +1,107 @@
+package predictit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"prediction-bot/pkg/types"
+)
+
+const marketJSON = `{"id":8000,"name":"What will Bitcoin's price be on Jan. 12?","url":"https://www.predictit.org/markets/detail/8000","status":"Open",
+	"contracts":[
+		{"id":31,"name":"$100,000 or more","dateEnd":"2026-01-12T23:59:00","lastTradePrice":0.4,"bestBuyYesCost":0.42,"bestSellYesCost":0.38,"bestBuyNoCost":0.62,"bestSellNoCost":0.58},
+		{"id":32,"name":"$95,000 to $99,999.99","dateEnd":"N/A","lastTradePrice":0.3,"bestBuyYesCost":null,"bestSellYesCost":0.29}
+	]}`
+
+func TestConvertMarket(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+	client := NewClient()
+	client.baseURL = server.URL
+
+	market, err := client.GetMarket("8000:31")
+	if err != nil {
+		t.Fatalf("GetMarket failed: %v", err)
+	}
+
+	if market.Platform != "predictit" || !market.Active {
+		t.Errorf("expected active predictit market, got %+v", market)
+	}
+	if market.EndDate.IsZero() || market.EndDate.UTC().Hour() != 4 {
+		t.Errorf("expected end date in Eastern time, got %v", market.EndDate)
+	}
+
+	outcomes := market.OutcomeMarkets()
+	if len(outcomes) != 2 {
+		t.Fatalf("expected 2 outcomes, got %d", len(outcomes))
+	}
+	first := outcomes[0]
+	if first.ID != "8000:31" || first.Outcome != "$100,000 or more" {
+		t.Errorf("unexpected outcome %s %q", first.ID, first.Outcome)
+	}
+	if math.Abs(first.OutcomeYesPrice-0.40) > 1e-9 || math.Abs(first.Spread-0.04) > 1e-9 {
+		t.Errorf("expected mid 0.40 and spread 0.04, got %v %v", first.OutcomeYesPrice, first.Spread)
+	}
+	if first.Tokens[1].TokenID != "8000:31:NO" {
+		t.Errorf("unexpected NO token %s", first.Tokens[1].TokenID)
+	}
+	// Without a YES ask the last trade prices the outcome
+	if outcomes[1].OutcomeYesPrice != 0.3 || outcomes[1].Spread != 0 {
+		t.Errorf("expected last trade 0.30, got %v", outcomes[1].OutcomeYesPrice)
+	}
+}
+
+func TestClient_GetOrderBook(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+	client := NewClient()
+	client.baseURL = server.URL
+
+	book, err := client.GetOrderBook(context.Background(), "8000:31:NO")
+	if err != nil {
+		t.Fatalf("GetOrderBook failed: %v", err)
+	}
+	if len(book.Bids) != 1 || book.Bids[0].Price != 0.58 || len(book.Asks) != 1 || book.Asks[0].Price != 0.62 {
+		t.Errorf("expected NO bid 0.58 and ask 0.62, got %+v", book)
+	}
+	if math.Abs(book.Asks[0].Size-investmentLimit/0.62) > 1e-9 {
+		t.Errorf("expected ask sized to the investment limit, got %v", book.Asks[0].Size)
+	}
+
+	if _, err := client.GetOrderBook(context.Background(), "8000:99:YES"); err == nil {
+		t.Error("expected error for an unknown contract")
+	}
+	if _, err := client.GetOrderBook(context.Background(), "8000:31"); err == nil {
+		t.Error("expected error for a token without a side")
+	}
+}
+
+func TestPlaceOrder_DryRunOnly(t *testing.T) {
+	client := NewClient()
+	order := types.Order{MarketID: "8000:31", TokenID: "8000:31:YES", Side: types.OrderSideBuy, Price: 0.42, Size: 10}
+
+	result, err := client.PlaceOrder(context.Background(), order, true)
+	if err != nil || result.Status != types.OrderStatusSimulated {
+		t.Errorf("expected simulated order, got %+v (%v)", result, err)
+	}
+	if _, err := client.PlaceOrder(context.Background(), order, false); err != ErrNoTradingAPI {
+		t.Errorf("expected ErrNoTradingAPI for a live order, got %v", err)
+	}
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiPath + "/markets/8000":
+			w.Write([]byte(marketJSON))
+		case apiPath + "/all/":
+			w.Write([]byte(`{"markets":[` + marketJSON + `]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
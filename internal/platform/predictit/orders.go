@@ -0,0 +1,57 @@
+package predictit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"prediction-bot/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoTradingAPI is returned for live orders, which PredictIt doesn't
+// accept through its API.
+var ErrNoTradingAPI = errors.New("predictit has no trading API")
+
+// PlaceOrder simulates an order on PredictIt. Only dry runs are supported:
+// a live order always fails with ErrNoTradingAPI.
+func (c *Client) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	if _, _, _, err := parseTokenID(order.TokenID); err != nil {
+		return types.OrderResult{}, fmt.Errorf("order validation: %w", err)
+	}
+	if order.Size <= 0 {
+		return types.OrderResult{}, fmt.Errorf("order validation: Size must be positive")
+	}
+	if !dryRun {
+		return types.OrderResult{}, ErrNoTradingAPI
+	}
+
+	return types.OrderResult{
+		OrderID:   fmt.Sprintf("dryrun-%s", uuid.New().String()),
+		MarketID:  order.MarketID,
+		TokenID:   order.TokenID,
+		Side:      order.Side,
+		Price:     order.Price,
+		Size:      order.Size,
+		Status:    types.OrderStatusSimulated,
+		IsDryRun:  true,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// GetOrderStatus fails with ErrNoTradingAPI: there are no live orders.
+func (c *Client) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	return types.OrderResult{}, ErrNoTradingAPI
+}
+
+// GetOpenOrders returns no orders: there are no live orders.
+func (c *Client) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	return nil, nil
+}
+
+// CancelOrder fails with ErrNoTradingAPI: there are no live orders.
+func (c *Client) CancelOrder(orderID string) error {
+	return ErrNoTradingAPI
+}
@@ -0,0 +1,20 @@
+package predictit
+
+import (
+	"fmt"
+
+	"prediction-bot/internal/platform"
+)
+
+// init registers PredictIt. Without a trading API it can only be enabled
+// for dry runs.
+func init() {
+	platform.Register("predictit", func(opts platform.Options) (platform.Platform, error) {
+		if !opts.DryRun {
+			return nil, fmt.Errorf("predictit has no trading API, enable it in dry-run only")
+		}
+		client := NewClient()
+		client.httpClient.Transport = opts.WrapTransport("predictit", client.httpClient.Transport)
+		return client, nil
+	})
+}
@@ -1,6 +1,7 @@
 package platform
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -63,6 +64,12 @@ func (r *RateLimiter) Allow() bool {
 
 // Wait blocks until a token is available, then consumes it.
 func (r *RateLimiter) Wait() {
+	_ = r.WaitContext(context.Background())
+}
+
+// WaitContext blocks until a token is available and consumes it, or until
+// ctx is done, returning its error without consuming one.
+func (r *RateLimiter) WaitContext(ctx context.Context) error {
 	for {
 		r.mu.Lock()
 		r.refill()
@@ -70,7 +77,7 @@ func (r *RateLimiter) Wait() {
 		if r.tokens > 0 {
 			r.tokens--
 			r.mu.Unlock()
-			return
+			return nil
 		}
 
 		// Calculate how long until next token is available
@@ -79,7 +86,13 @@ func (r *RateLimiter) Wait() {
 		r.mu.Unlock()
 
 		// Sleep for a bit less than time_per_token to avoid oversleeping
-		time.Sleep(timePerToken / 2)
+		timer := time.NewTimer(timePerToken / 2)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
 	}
 }
 
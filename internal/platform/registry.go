@@ -0,0 +1,87 @@
+package platform
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Options configures a platform created from the registry.
+type Options struct {
+	// DryRun is true when no live orders will be placed, so adapters can
+	// fall back to reading public data without credentials.
+	DryRun bool
+	// HTTP configures the rate limiting, retries and circuit breaker the
+	// client's requests go through. The zero value sends them as they are.
+	HTTP TransportConfig
+	// Audit, if set, records every request the client sends, keeping up to
+	// AuditPayloadBytes of each body.
+	Audit             APICallRecorder
+	AuditPayloadBytes int
+}
+
+// WrapTransport wraps a platform client's HTTP transport in the middleware
+// opts configures: each request sent is audited, beneath the rate limiting,
+// retries and circuit breaker, so every retry is recorded too.
+func (opts Options) WrapTransport(name string, base http.RoundTripper) http.RoundTripper {
+	if opts.Audit != nil {
+		base = NewAuditTransport(name, base, opts.Audit, opts.AuditPayloadBytes)
+	}
+	return NewTransport(name, base, opts.HTTP)
+}
+
+// Factory creates a platform client, typically from credentials in the
+// environment.
+type Factory func(opts Options) (Platform, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a platform available by name. Adapters call it from an
+// init function, so compiling one in (a blank import is enough) makes it
+// available to enable from config. It panics if the name is registered
+// twice or factory is nil.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("platform: Register factory is nil for " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("platform: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// New creates the platform registered under name.
+func New(name string, opts Options) (Platform, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown platform %q (registered: %v)", name, Registered())
+	}
+
+	p, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("initialize %s: %w", name, err)
+	}
+	return p, nil
+}
+
+// Registered returns the names of the registered platforms, sorted.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,45 @@
+package platform
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	var got Options
+	Register("test-mock", func(opts Options) (Platform, error) {
+		got = opts
+		return &MockPlatform{name: "test-mock"}, nil
+	})
+	Register("test-broken", func(opts Options) (Platform, error) {
+		return nil, errors.New("missing credentials")
+	})
+
+	p, err := New("test-mock", Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if p.Name() != "test-mock" || !got.DryRun {
+		t.Errorf("expected test-mock created for a dry run, got %s %+v", p.Name(), got)
+	}
+
+	if _, err := New("test-broken", Options{}); err == nil {
+		t.Error("expected the factory's error")
+	}
+	if _, err := New("test-unknown", Options{}); err == nil {
+		t.Error("expected error for an unregistered platform")
+	}
+
+	names := Registered()
+	if !slices.Contains(names, "test-mock") || !slices.IsSorted(names) {
+		t.Errorf("expected sorted names including test-mock, got %v", names)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a name twice to panic")
+		}
+	}()
+	Register("test-mock", func(opts Options) (Platform, error) { return nil, nil })
+}
@@ -0,0 +1,256 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrCircuitOpen is returned for requests made while a platform's circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// TransportConfig configures the middleware a platform's HTTP requests go
+// through. Zero values disable each part.
+type TransportConfig struct {
+	// RequestsPerMinute is the token bucket's capacity, refilled over a
+	// minute. Requests beyond it wait for a token.
+	RequestsPerMinute int
+	// MaxRetries is how many times a request answered 429 or 5xx, or that
+	// failed to connect, is retried.
+	MaxRetries int
+	// BaseBackoff is the wait before the first retry, doubled for each one
+	// after, up to MaxBackoff. Each wait is jittered down to half of it.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold is how many requests in a row must fail, retries
+	// exhausted, for the circuit to open.
+	BreakerThreshold int
+	// BreakerCooldown is how long an open circuit fails requests before it
+	// lets one through to test the platform.
+	BreakerCooldown time.Duration
+}
+
+// Transport is an http.RoundTripper that rate limits a platform's requests,
+// retries those that failed transiently with exponential backoff and stops
+// sending any while the platform keeps failing.
+//
+// A 429 was rejected before being processed, so it is retried whatever its
+// method. A 5xx or a connection error may have been processed, so only
+// idempotent requests are retried on them: an order is never placed twice.
+type Transport struct {
+	name    string
+	base    http.RoundTripper
+	cfg     TransportConfig
+	limiter *RateLimiter
+	breaker *circuitBreaker
+	// sleep waits d or until the request's context is done; replaced in
+	// tests
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+// NewTransport wraps base (http.DefaultTransport if nil) for the platform
+// called name.
+func NewTransport(name string, base http.RoundTripper, cfg TransportConfig) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{
+		name:  name,
+		base:  base,
+		cfg:   cfg,
+		sleep: sleepContext,
+	}
+	if cfg.RequestsPerMinute > 0 {
+		t.limiter = NewRateLimiter(cfg.RequestsPerMinute, time.Minute)
+	}
+	if cfg.BreakerThreshold > 0 {
+		t.breaker = &circuitBreaker{threshold: cfg.BreakerThreshold, cooldown: cfg.BreakerCooldown, now: time.Now}
+	}
+	return t
+}
+
+// RoundTrip sends req through the rate limiter, retries and circuit breaker.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.breaker != nil && !t.breaker.allow() {
+		return nil, fmt.Errorf("%s %s: %w", t.name, req.URL.Path, ErrCircuitOpen)
+	}
+
+	retryable := isIdempotent(req.Method)
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		if t.limiter != nil {
+			if err := t.limiter.WaitContext(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		transient := transientFailure(resp, err)
+		if !transient {
+			t.record(true)
+			return resp, err
+		}
+		if attempt >= t.cfg.MaxRetries || !rewindable || (!retryable && !rateLimited(resp)) {
+			t.record(false)
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		event := log.Debug().Str("platform", t.name).Str("method", req.Method).Str("path", req.URL.Path).
+			Int("attempt", attempt+1).Dur("backoff", wait)
+		if err != nil {
+			event = event.Err(err)
+		} else {
+			event = event.Int("status", resp.StatusCode)
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		event.Msg("retrying platform request")
+
+		if err := t.sleep(req, wait); err != nil {
+			t.record(false)
+			return nil, err
+		}
+	}
+}
+
+// record tells the circuit breaker, if any, how a request ended.
+func (t *Transport) record(success bool) {
+	if t.breaker == nil {
+		return
+	}
+	if t.breaker.record(success) {
+		log.Warn().Str("platform", t.name).Dur("cooldown", t.cfg.BreakerCooldown).
+			Msg("platform requests keep failing, circuit breaker opened")
+	}
+}
+
+// backoff returns how long to wait before retrying after attempt (0 for the
+// first): the platform's Retry-After if it sent one, up to MaxBackoff,
+// otherwise the exponential backoff jittered down to half of it.
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait := time.Duration(seconds) * time.Second
+			if t.cfg.MaxBackoff > 0 && wait > t.cfg.MaxBackoff {
+				wait = t.cfg.MaxBackoff
+			}
+			return wait
+		}
+	}
+
+	wait := t.cfg.BaseBackoff << attempt
+	if wait <= 0 || (t.cfg.MaxBackoff > 0 && wait > t.cfg.MaxBackoff) {
+		wait = t.cfg.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// transientFailure reports whether a request failed in a way worth retrying.
+func transientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return rateLimited(resp) || resp.StatusCode >= 500
+}
+
+// rateLimited reports whether resp rejected the request for its rate.
+func rateLimited(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusTooManyRequests
+}
+
+// isIdempotent reports whether requests with method can be sent twice with
+// the effect of once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleepContext waits d or until req's context is done.
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// circuitBreaker opens after threshold failures in a row and fails requests
+// until cooldown has passed. Then it lets one through: its success closes
+// the circuit, its failure opens it again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	probing  bool
+}
+
+// allow reports whether a request may be sent.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record counts how a request ended and reports whether it opened the
+// circuit.
+func (b *circuitBreaker) record(success bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.open = false
+		return false
+	}
+
+	b.failures++
+	if b.open || b.failures >= b.threshold {
+		opened := !b.open
+		b.open = true
+		b.openedAt = b.now()
+		return opened
+	}
+	return false
+}
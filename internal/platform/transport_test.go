@@ -0,0 +1,215 @@
+package platform
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestTransport returns a client whose requests go through a Transport
+// configured with cfg, recording its backoffs instead of sleeping.
+func newTestTransport(cfg TransportConfig, waits *[]time.Duration) (*http.Client, *Transport) {
+	transport := NewTransport("test", nil, cfg)
+	transport.sleep = func(req *http.Request, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return &http.Client{Transport: transport}, transport
+}
+
+func TestTransport_RetriesServerErrorsWithBackoff(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client, _ := newTestTransport(TransportConfig{MaxRetries: 3, BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, &waits)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("Expected 200 after 3 calls, got %d after %d", resp.StatusCode, calls)
+	}
+
+	// Each backoff is jittered within the upper half of its doubling
+	if len(waits) != 2 {
+		t.Fatalf("Expected 2 backoffs, got %v", waits)
+	}
+	for i, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if waits[i] < base/2 || waits[i] > base {
+			t.Errorf("Expected backoff %d within [%v, %v], got %v", i, base/2, base, waits[i])
+		}
+	}
+}
+
+func TestTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client, _ := newTestTransport(TransportConfig{MaxRetries: 2, BaseBackoff: time.Second, MaxBackoff: 1500 * time.Millisecond}, &waits)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 3 {
+		t.Errorf("Expected the last 503 after 3 calls, got %d after %d", resp.StatusCode, calls)
+	}
+	// The second backoff is capped
+	if len(waits) != 2 || waits[1] > 1500*time.Millisecond {
+		t.Errorf("Expected 2 backoffs, the second capped, got %v", waits)
+	}
+}
+
+func TestTransport_RetriesOrdersOnlyWhenRateLimited(t *testing.T) {
+	var calls int32
+	var bodies []string
+	status := http.StatusTooManyRequests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client, _ := newTestTransport(TransportConfig{MaxRetries: 3, BaseBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}, &waits)
+
+	// A 429 wasn't processed: the order is sent again, after Retry-After
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"order":1}`))
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || len(waits) != 1 || waits[0] != 2*time.Second {
+		t.Errorf("Expected 201 after waiting 2s, got %d after %v", resp.StatusCode, waits)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"order":1}` {
+		t.Errorf("Expected the body sent twice, got %q", bodies)
+	}
+
+	// A 5xx may have placed it: it isn't sent again
+	calls = 0
+	status = http.StatusInternalServerError
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{"order":2}`))
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError || calls != 1 {
+		t.Errorf("Expected the 500 returned after 1 call, got %d after %d", resp.StatusCode, calls)
+	}
+}
+
+func TestTransport_CircuitBreakerOpensAndRecovers(t *testing.T) {
+	var calls int32
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client, transport := newTestTransport(TransportConfig{BreakerThreshold: 2, BreakerCooldown: time.Minute}, &waits)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	transport.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get %d failed: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	// Open: failed without reaching the platform
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls reaching the platform, got %d", calls)
+	}
+
+	// After the cooldown one request is let through; its success closes it
+	now = now.Add(time.Minute)
+	failing.Store(false)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Expected the circuit closed, got %v", err)
+		}
+		resp.Body.Close()
+	}
+	if calls != 4 {
+		t.Errorf("Expected 4 calls reaching the platform, got %d", calls)
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := &circuitBreaker{threshold: 1, cooldown: time.Minute, now: func() time.Time { return now }}
+
+	if !b.record(false) {
+		t.Error("Expected the first failure to open the circuit")
+	}
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("Expected a probe allowed after the cooldown")
+	}
+	if b.allow() {
+		t.Error("Expected one probe at a time")
+	}
+	b.record(false)
+	if b.allow() {
+		t.Error("Expected a failed probe to reopen the circuit for another cooldown")
+	}
+}
+
+func TestTransport_ZeroConfigPassesThrough(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client, _ := newTestTransport(TransportConfig{}, &waits)
+	for i := 0; i < 10; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if calls != 10 || len(waits) != 0 {
+		t.Errorf("Expected 10 calls without retries, got %d and %v", calls, waits)
+	}
+}
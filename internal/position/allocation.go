@@ -0,0 +1,76 @@
+package position
+
+import (
+	"fmt"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// StrategyFavorite names the positions that follow the market, buying the
+// side it favors, in a bankroll allocation. They are stored untagged.
+const StrategyFavorite = "favorite"
+
+// SkipReasonAllocationExhausted is the skip reason for entries whose
+// strategy has used up its share of the bankroll.
+const SkipReasonAllocationExhausted = "allocation_exhausted"
+
+// SetAllocation reserves a share of each platform's bankroll for each trade
+// strategy, by name: StrategyFavorite, TradeStrategyFade and
+// TradeStrategyHedge. Each strategy trades its share of the initial
+// bankroll plus its own realized PnL, so one strategy's drawdown cannot
+// consume capital reserved for another. Strategies left out get nothing.
+// Shares must be between 0 and 1 and sum to at most 1. An empty allocation
+// lets every strategy draw on the whole bankroll.
+func (m *Manager) SetAllocation(shares map[string]float64) error {
+	if len(shares) == 0 {
+		m.allocation = nil
+		return nil
+	}
+
+	allocation := make(map[string]float64, len(shares))
+	var total float64
+	for name, share := range shares {
+		var tag string
+		switch name {
+		case StrategyFavorite:
+		case TradeStrategyFade, TradeStrategyHedge:
+			tag = name
+		default:
+			return fmt.Errorf("unknown strategy %q in allocation", name)
+		}
+		if share < 0 || share > 1 {
+			return fmt.Errorf("allocation for %s must be between 0 and 1, got %v", name, share)
+		}
+		allocation[tag] = share
+		total += share
+	}
+	if total > 1+1e-9 {
+		return fmt.Errorf("allocation shares sum to %v, more than the whole bankroll", total)
+	}
+
+	m.allocation = allocation
+	return nil
+}
+
+// strategyBankroll returns how much of a platform's bankroll an entry on a
+// trade strategy (empty for following the market) may use: the strategy's
+// share of the initial bankroll plus its realized PnL, less what its open
+// positions hold, capped at the current bankroll. Without an allocation it
+// is the current bankroll.
+func (m *Manager) strategyBankroll(bankroll *persistence.Bankroll, strategy string) (float64, error) {
+	if m.allocation == nil {
+		return bankroll.CurrentAmount, nil
+	}
+
+	capital, err := m.positionRepo.GetStrategyCapital(bankroll.Platform, strategy)
+	if err != nil {
+		return 0, err
+	}
+	available := types.Dollars(m.allocation[strategy]*bankroll.InitialAmount) +
+		capital.RealizedPnL - capital.Committed
+	if current := types.Dollars(bankroll.CurrentAmount); available > current {
+		available = current
+	}
+	return available.Float64(), nil
+}
@@ -0,0 +1,147 @@
+package position
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+func TestSetAllocationRejectsInvalidShares(t *testing.T) {
+	manager := NewManager(nil, nil, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+
+	tests := []struct {
+		name   string
+		shares map[string]float64
+		want   string
+	}{
+		{"unknown strategy", map[string]float64{"momentum": 0.5}, "unknown strategy"},
+		{"negative share", map[string]float64{StrategyFavorite: -0.1}, "between 0 and 1"},
+		{"over the bankroll", map[string]float64{StrategyFavorite: 0.7, TradeStrategyFade: 0.4}, "sum to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manager.SetAllocation(tt.shares)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := manager.SetAllocation(map[string]float64{StrategyFavorite: 0.6, TradeStrategyFade: 0.2, TradeStrategyHedge: 0.2}); err != nil {
+		t.Errorf("expected a full allocation accepted, got %v", err)
+	}
+}
+
+// TestProcessEntryKeepsStrategiesToTheirAllocation tests that a strategy's
+// losses and open positions use up only its own share of the bankroll.
+func TestProcessEntryKeepsStrategiesToTheirAllocation(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+
+	mockVolatility := &MockVolatilityService{
+		result: volatility.ServiceResult{
+			Asset:          "BTC",
+			CurrentPrice:   100000.0,
+			Volatility:     0.5,
+			SafetyMargin:   1.91,
+			Recommendation: volatility.RecommendationValid,
+		},
+	}
+	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+	if err := manager.SetAllocation(map[string]float64{StrategyFavorite: 0.5, TradeStrategyFade: 0.5}); err != nil {
+		t.Fatalf("SetAllocation failed: %v", err)
+	}
+
+	// A fade lost its whole $25 share
+	lostID, err := positionRepo.Create(&persistence.Position{
+		Platform:      "polymarket",
+		MarketID:      "lost-fade",
+		Asset:         "BTC",
+		EntryPrice:    0.25,
+		Quantity:      100,
+		Side:          "NO",
+		Status:        persistence.PositionStatusOpen,
+		TradeStrategy: TradeStrategyFade,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+	if err := positionRepo.Close(lostID, 0, ExitReasonStopLoss, types.Dollars(-25.0)); err != nil {
+		t.Fatalf("Failed to close position: %v", err)
+	}
+
+	market := func(id string) scanner.EligibleMarket {
+		return scanner.EligibleMarket{
+			Market: types.Market{
+				ID:              id,
+				Platform:        "polymarket",
+				EndDate:         time.Now().Add(24 * time.Hour),
+				OutcomeYesPrice: 0.90,
+				Liquidity:       1000.0,
+			},
+			Parsed:      &scanner.ParsedMarket{Asset: "BTC", Strike: 95000.0, Direction: "above"},
+			Probability: 0.90,
+			BetSide:     "YES",
+		}
+	}
+
+	// Following the market still sizes off its own $25
+	result, err := manager.ProcessEntry(context.Background(), market("favorite-1"), true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("Expected entry within the favorite allocation, got skipped: %s", result.SkipReason)
+	}
+	if result.PositionSize > 25*0.20+0.0001 {
+		t.Errorf("Expected size capped at 20%% of the $25 share, got %f", result.PositionSize)
+	}
+
+	// Once the favorite share is committed, entries stop even though the
+	// platform bankroll has cash left
+	if _, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "favorite-big",
+		Asset:      "ETH",
+		EntryPrice: 0.80,
+		Quantity:   30,
+		Side:       "YES",
+		Status:     persistence.PositionStatusOpen,
+	}); err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+	result, err = manager.ProcessEntry(context.Background(), market("favorite-2"), true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if !result.Skipped || result.SkipReason != SkipReasonAllocationExhausted {
+		t.Errorf("Expected entry skipped for an exhausted allocation, got %+v", result)
+	}
+
+	// The hedge has no share
+	bankrollRepo.Initialize("kalshi", 50.0)
+	results, err := manager.ProcessHedge(context.Background(), hedgeLegs(), 9.0, false)
+	if err != nil {
+		t.Fatalf("ProcessHedge failed: %v", err)
+	}
+	if len(results) != 1 || results[0].SkipReason != SkipReasonAllocationExhausted {
+		t.Errorf("Expected hedge skipped for an exhausted allocation, got %+v", results)
+	}
+}
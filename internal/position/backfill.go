@@ -0,0 +1,132 @@
+package position
+
+import (
+	"fmt"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MarketGetter fetches a market's current listing.
+type MarketGetter interface {
+	GetMarket(marketID string) (*types.Market, error)
+}
+
+// BackfillResult counts what a backfill recovered.
+type BackfillResult struct {
+	Defaults   int64 // Positions given the known entry and trade strategy
+	Parsed     int   // Positions given the asset, strike and direction parsed from their title
+	Tokens     int   // Positions given their outcome token
+	CloseTimes int   // Positions given their market close time
+	Failed     int   // Positions whose market couldn't be fetched
+}
+
+// String summarizes what a backfill recovered.
+func (r BackfillResult) String() string {
+	return fmt.Sprintf("%d given defaults, %d titles parsed, %d tokens, %d close times, %d markets unavailable",
+		r.Defaults, r.Parsed, r.Tokens, r.CloseTimes, r.Failed)
+}
+
+// Backfiller enriches positions recorded before the columns holding their
+// metadata were added, so learning and reporting see the same fields on old
+// positions as on new ones.
+type Backfiller struct {
+	repo    *persistence.PositionRepository
+	markets map[string]MarketGetter
+}
+
+// NewBackfiller creates a Backfiller that only recovers what the database
+// holds: known defaults and titles to re-parse. Set a MarketGetter for a
+// platform to recover its positions' tokens and close times too.
+func NewBackfiller(repo *persistence.PositionRepository) *Backfiller {
+	return &Backfiller{repo: repo, markets: make(map[string]MarketGetter)}
+}
+
+// SetMarketGetter sets the client a platform's markets are fetched with.
+func (b *Backfiller) SetMarketGetter(platform string, getter MarketGetter) {
+	b.markets[platform] = getter
+}
+
+// Run backfills every position missing metadata. A market that can't be
+// fetched is counted as failed and skipped.
+func (b *Backfiller) Run() (BackfillResult, error) {
+	var result BackfillResult
+
+	n, err := b.repo.BackfillDefaults()
+	if err != nil {
+		return result, err
+	}
+	result.Defaults = n
+
+	positions, err := b.repo.GetIncomplete()
+	if err != nil {
+		return result, err
+	}
+
+	for _, pos := range positions {
+		var fill persistence.PositionBackfill
+
+		if pos.Asset == "" || pos.Direction == "" {
+			parsed, err := scanner.ParseListedMarket(types.Market{ID: pos.MarketID, Title: pos.MarketTitle, Outcome: pos.Outcome})
+			if err == nil {
+				fill.Asset = parsed.Asset
+				fill.Strike = parsed.Strike
+				fill.StrikeUpper = parsed.StrikeUpper
+				fill.Direction = parsed.Direction
+			}
+		}
+
+		getter := b.markets[pos.Platform]
+		if getter != nil && (pos.TokenID == "" || pos.MarketCloseTime == nil) {
+			market, err := getter.GetMarket(pos.MarketID)
+			if err != nil || market == nil {
+				log.Debug().Err(err).Int64("position_id", pos.ID).Str("market", pos.MarketID).Msg("Failed to fetch market to backfill")
+				result.Failed++
+			} else {
+				if pos.TokenID == "" {
+					fill.TokenID = positionTokenID(*market, pos)
+				}
+				if pos.MarketCloseTime == nil && !market.EndDate.IsZero() {
+					closeTime := market.EndDate
+					fill.MarketCloseTime = &closeTime
+				}
+			}
+		}
+
+		changed, err := b.repo.Backfill(pos.ID, fill)
+		if err != nil {
+			return result, err
+		}
+		if !changed {
+			continue
+		}
+		if fill.Asset != "" && pos.Asset == "" {
+			result.Parsed++
+		}
+		if fill.TokenID != "" {
+			result.Tokens++
+		}
+		if fill.MarketCloseTime != nil {
+			result.CloseTimes++
+		}
+	}
+
+	return result, nil
+}
+
+// positionTokenID returns the token a position holds in market, on the
+// outcome it trades if the market has several.
+func positionTokenID(market types.Market, pos *persistence.Position) string {
+	if pos.Outcome != "" {
+		for _, outcome := range market.OutcomeMarkets() {
+			if outcome.Outcome == pos.Outcome {
+				return outcomeTokenID(outcome, pos.Side)
+			}
+		}
+		return ""
+	}
+	return outcomeTokenID(market, pos.Side)
+}
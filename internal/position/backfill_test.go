@@ -0,0 +1,88 @@
+package position
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// MockMarketGetter returns markets by ID, failing for unknown ones.
+type MockMarketGetter struct {
+	markets map[string]*types.Market
+}
+
+func (m *MockMarketGetter) GetMarket(marketID string) (*types.Market, error) {
+	market, ok := m.markets[marketID]
+	if !ok {
+		return nil, errors.New("market not found")
+	}
+	return market, nil
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Positions recorded before the newer columns existed
+	for _, marketID := range []string{"0xbtc", "0xgone"} {
+		_, err := db.Exec(`
+			INSERT INTO positions (platform, market_id, market_title, entry_price_micros, quantity, side, status)
+			VALUES ('polymarket', ?, 'Will Bitcoin be above $100,000 on March 1?', 900000, 10, 'NO', 'closed')
+		`, marketID)
+		if err != nil {
+			t.Fatalf("Failed to insert legacy position: %v", err)
+		}
+	}
+
+	closeTime := time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)
+	repo := persistence.NewPositionRepository(db)
+	backfiller := NewBackfiller(repo)
+	backfiller.SetMarketGetter("polymarket", &MockMarketGetter{markets: map[string]*types.Market{
+		"0xbtc": {
+			ID:      "0xbtc",
+			EndDate: closeTime,
+			Tokens:  []types.Token{{TokenID: "yes-token", Outcome: "Yes"}, {TokenID: "no-token", Outcome: "No"}},
+		},
+	}})
+
+	result, err := backfiller.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	want := BackfillResult{Defaults: 2, Parsed: 2, Tokens: 1, CloseTimes: 1, Failed: 1}
+	if result != want {
+		t.Errorf("Expected %+v, got %+v", want, result)
+	}
+
+	positions, err := repo.GetClosed()
+	if err != nil {
+		t.Fatalf("Failed to get positions: %v", err)
+	}
+	byMarket := make(map[string]*persistence.Position)
+	for _, p := range positions {
+		byMarket[p.MarketID] = p
+	}
+
+	btc := byMarket["0xbtc"]
+	if btc.Asset != "BTC" || btc.Strike != 100000 || btc.Direction != "above" || btc.EntryStrategy != EntryStrategyMarket {
+		t.Errorf("Expected the title parsed, got %+v", btc)
+	}
+	if btc.TokenID != "no-token" || btc.MarketCloseTime == nil || !btc.MarketCloseTime.Equal(closeTime) {
+		t.Errorf("Expected the token and close time fetched, got %+v", btc)
+	}
+	if gone := byMarket["0xgone"]; gone.Asset != "BTC" || gone.TokenID != "" || gone.MarketCloseTime != nil {
+		t.Errorf("Expected only the title parsed without the market, got %+v", gone)
+	}
+
+	// A second run has nothing left to recover but the unavailable market
+	result, err = backfiller.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result != (BackfillResult{Failed: 1}) {
+		t.Errorf("Expected nothing recovered, got %+v", result)
+	}
+}
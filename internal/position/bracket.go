@@ -0,0 +1,46 @@
+package position
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/volatility"
+)
+
+// analyzeStrike analyzes the safety of a bet on asset against its strike.
+// Above and below markets are analyzed against strike in their direction.
+// A "between" bracket [strike, upper] is analyzed against both bounds: YES
+// needs the price to stay above strike and below upper, so it is as safe
+// as the riskier bound; NO needs it to leave the bracket on either side,
+// so it is as safe as the safer bound.
+func analyzeStrike(ctx context.Context, analyzer VolatilityAnalyzer, asset string, strike, upper float64, direction, side string, timeToClose time.Duration) (volatility.ServiceResult, error) {
+	if direction != scanner.DirectionBetween {
+		dir := volatility.DirectionAbove
+		if direction == "below" {
+			dir = volatility.DirectionBelow
+		}
+		return analyzer.AnalyzeAsset(ctx, asset, strike, dir, timeToClose)
+	}
+
+	yes := !strings.EqualFold(side, "NO")
+	lowerDir, upperDir := volatility.DirectionAbove, volatility.DirectionBelow
+	if !yes {
+		lowerDir, upperDir = volatility.DirectionBelow, volatility.DirectionAbove
+	}
+
+	lower, err := analyzer.AnalyzeAsset(ctx, asset, strike, lowerDir, timeToClose)
+	if err != nil {
+		return volatility.ServiceResult{}, err
+	}
+	higher, err := analyzer.AnalyzeAsset(ctx, asset, upper, upperDir, timeToClose)
+	if err != nil {
+		return volatility.ServiceResult{}, err
+	}
+
+	if (higher.SafetyMargin < lower.SafetyMargin) == yes {
+		return higher, nil
+	}
+	return lower, nil
+}
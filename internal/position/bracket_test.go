@@ -0,0 +1,63 @@
+package position
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/volatility"
+)
+
+// StrikeVolatilityService returns a safety margin per strike and direction,
+// rejecting margins below the risky threshold.
+type StrikeVolatilityService struct {
+	margins map[volatility.Direction]map[float64]float64
+}
+
+func (s *StrikeVolatilityService) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+	margin := s.margins[direction][strikePrice]
+	recommendation := volatility.RecommendationValid
+	if margin < volatility.SafetyMarginRiskyThreshold {
+		recommendation = volatility.RecommendationReject
+	}
+	return volatility.ServiceResult{
+		StrikePrice:    strikePrice,
+		Direction:      direction,
+		SafetyMargin:   margin,
+		Recommendation: recommendation,
+	}, nil
+}
+
+func TestAnalyzeStrike_Bracket(t *testing.T) {
+	// BTC between 95,000 and 100,000, nearer the top of the bracket
+	service := &StrikeVolatilityService{margins: map[volatility.Direction]map[float64]float64{
+		volatility.DirectionAbove: {95000: 3.0, 100000: -1.2},
+		volatility.DirectionBelow: {95000: -3.0, 100000: 1.2},
+	}}
+
+	tests := []struct {
+		name      string
+		direction string
+		side      string
+		strike    float64
+		margin    float64
+	}{
+		{"YES is as safe as the nearer bound", "between", "YES", 100000, 1.2},
+		{"NO is as safe as the nearer exit", "between", "NO", 100000, -1.2},
+		{"above ignores the upper bound", "above", "YES", 95000, 3.0},
+		{"below", "below", "YES", 95000, -3.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := analyzeStrike(context.Background(), service, "BTC", 95000, 100000, tt.direction, tt.side, time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.StrikePrice != tt.strike || result.SafetyMargin != tt.margin {
+				t.Errorf("expected margin %v against %v, got %v against %v",
+					tt.margin, tt.strike, result.SafetyMargin, result.StrikePrice)
+			}
+		})
+	}
+}
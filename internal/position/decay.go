@@ -0,0 +1,75 @@
+package position
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+// refresher is implemented by analyzers that cache market data, such as
+// volatility.Service, so a re-analysis can force fresh data.
+type refresher interface {
+	Refresh(asset string)
+}
+
+// SetDecayCheckpoints enables scheduled re-analysis: once the time left to a
+// position's market close falls to each of fractions of its holding period
+// (e.g. 0.5 and 0.25), its margin is re-analyzed with fresh data whether or
+// not its price has moved (see CheckDecayCheckpoint). Fractions must be
+// between 0 and 1. No fractions disables the re-analysis.
+func (m *Monitor) SetDecayCheckpoints(fractions []float64) error {
+	for _, f := range fractions {
+		if f <= 0 || f >= 1 {
+			return fmt.Errorf("decay checkpoint must be between 0 and 1, got %v", f)
+		}
+	}
+	m.decayCheckpoints = fractions
+	return nil
+}
+
+// CheckDecayCheckpoint re-analyzes a position when it passes a decay
+// checkpoint it has not yet been re-analyzed at. Returns the checkpoint
+// reached, or 0 if none is due, and whether the safety margin for the actual
+// time left has fallen below VolatilityExitThreshold. Passing several
+// checkpoints between calls re-analyzes once, at the lowest. Checkpoints are
+// remembered in memory, so after a restart a position is re-analyzed once at
+// the lowest it has passed.
+//
+// Fades and positions with an unknown close time are never checked.
+func (m *Monitor) CheckDecayCheckpoint(ctx context.Context, position *persistence.Position, analyzer VolatilityAnalyzer, now time.Time) (float64, bool, error) {
+	if len(m.decayCheckpoints) == 0 || position.MarketCloseTime == nil || position.TradeStrategy == TradeStrategyFade {
+		return 0, false, nil
+	}
+	holding := position.MarketCloseTime.Sub(position.EntryTime)
+	timeToClose := position.MarketCloseTime.Sub(now)
+	if holding <= 0 || timeToClose <= 0 {
+		return 0, false, nil
+	}
+
+	remaining := timeToClose.Seconds() / holding.Seconds()
+	done, started := m.checkpointsDone[position.ID]
+	var checkpoint float64
+	for _, f := range m.decayCheckpoints {
+		if remaining > f || (started && f >= done) {
+			continue
+		}
+		if checkpoint == 0 || f < checkpoint {
+			checkpoint = f
+		}
+	}
+	if checkpoint == 0 {
+		return 0, false, nil
+	}
+
+	if r, ok := analyzer.(refresher); ok {
+		r.Refresh(position.Asset)
+	}
+	safetyMargin, err := m.currentSafetyMargin(ctx, position, analyzer, timeToClose)
+	if err != nil {
+		return checkpoint, false, fmt.Errorf("check decay checkpoint: %w", err)
+	}
+	m.checkpointsDone[position.ID] = checkpoint
+	return checkpoint, safetyMargin < VolatilityExitThreshold, nil
+}
@@ -0,0 +1,127 @@
+package position
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+// refreshingAnalyzer counts the cache refreshes requested before analyses.
+type refreshingAnalyzer struct {
+	MockVolatilityAnalyzer
+	refreshed []string
+}
+
+func (a *refreshingAnalyzer) Refresh(asset string) {
+	a.refreshed = append(a.refreshed, asset)
+}
+
+func TestCheckDecayCheckpoint(t *testing.T) {
+	entry := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	closeTime := entry.Add(8 * time.Hour)
+
+	monitor := NewMonitor(0.15)
+	if err := monitor.SetDecayCheckpoints([]float64{0.5, 0.25}); err != nil {
+		t.Fatalf("SetDecayCheckpoints returned error: %v", err)
+	}
+	analyzer := &refreshingAnalyzer{MockVolatilityAnalyzer: MockVolatilityAnalyzer{safetyMargin: 1.5}}
+	position := &persistence.Position{
+		ID:              1,
+		Asset:           "BTC",
+		Strike:          100000,
+		Direction:       "above",
+		Status:          "open",
+		EntryTime:       entry,
+		MarketCloseTime: &closeTime,
+	}
+
+	steps := []struct {
+		name         string
+		elapsed      time.Duration
+		safetyMargin float64
+		checkpoint   float64
+		exit         bool
+	}{
+		{"before first checkpoint", 3 * time.Hour, 1.5, 0, false},
+		{"half time left", 4 * time.Hour, 1.5, 0.5, false},
+		{"half checkpoint already done", 5 * time.Hour, 1.5, 0, false},
+		{"quarter time left with eroded margin", 6 * time.Hour, 0.7, 0.25, true},
+		{"all checkpoints done", 7 * time.Hour, 0.7, 0, false},
+	}
+
+	for _, step := range steps {
+		analyzer.safetyMargin = step.safetyMargin
+		checkpoint, exit, err := monitor.CheckDecayCheckpoint(context.Background(), position, analyzer, entry.Add(step.elapsed))
+		if err != nil {
+			t.Fatalf("%s: CheckDecayCheckpoint returned error: %v", step.name, err)
+		}
+		if checkpoint != step.checkpoint || exit != step.exit {
+			t.Errorf("%s: CheckDecayCheckpoint() = %v, %v, want %v, %v", step.name, checkpoint, exit, step.checkpoint, step.exit)
+		}
+	}
+
+	if len(analyzer.refreshed) != 2 {
+		t.Errorf("expected a refresh per checkpoint, got %v", analyzer.refreshed)
+	}
+}
+
+func TestCheckDecayCheckpoint_SkipsPassedCheckpoints(t *testing.T) {
+	entry := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	closeTime := entry.Add(8 * time.Hour)
+
+	monitor := NewMonitor(0.15)
+	if err := monitor.SetDecayCheckpoints([]float64{0.5, 0.25}); err != nil {
+		t.Fatalf("SetDecayCheckpoints returned error: %v", err)
+	}
+	position := &persistence.Position{ID: 1, Asset: "BTC", Strike: 100000, Direction: "above", EntryTime: entry, MarketCloseTime: &closeTime}
+	analyzer := &MockVolatilityAnalyzer{safetyMargin: 1.5}
+
+	// Both checkpoints passed since the last check: re-analyzed once, at the lowest
+	checkpoint, _, err := monitor.CheckDecayCheckpoint(context.Background(), position, analyzer, entry.Add(7*time.Hour))
+	if err != nil {
+		t.Fatalf("CheckDecayCheckpoint returned error: %v", err)
+	}
+	if checkpoint != 0.25 {
+		t.Errorf("expected checkpoint 0.25, got %v", checkpoint)
+	}
+	if checkpoint, _, _ := monitor.CheckDecayCheckpoint(context.Background(), position, analyzer, entry.Add(7*time.Hour+30*time.Minute)); checkpoint != 0 {
+		t.Errorf("expected no checkpoint due, got %v", checkpoint)
+	}
+}
+
+func TestCheckDecayCheckpoint_NotChecked(t *testing.T) {
+	entry := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	closeTime := entry.Add(8 * time.Hour)
+	now := entry.Add(6 * time.Hour)
+	analyzer := &MockVolatilityAnalyzer{safetyMargin: 0.5}
+
+	disabled := NewMonitor(0.15)
+	position := &persistence.Position{ID: 1, Asset: "BTC", Direction: "above", EntryTime: entry, MarketCloseTime: &closeTime}
+	if checkpoint, exit, _ := disabled.CheckDecayCheckpoint(context.Background(), position, analyzer, now); checkpoint != 0 || exit {
+		t.Errorf("expected no re-analysis without checkpoints, got %v, %v", checkpoint, exit)
+	}
+
+	monitor := NewMonitor(0.15)
+	if err := monitor.SetDecayCheckpoints([]float64{0.5}); err != nil {
+		t.Fatalf("SetDecayCheckpoints returned error: %v", err)
+	}
+	fade := &persistence.Position{ID: 2, Asset: "BTC", Direction: "above", TradeStrategy: TradeStrategyFade, EntryTime: entry, MarketCloseTime: &closeTime}
+	if checkpoint, exit, _ := monitor.CheckDecayCheckpoint(context.Background(), fade, analyzer, now); checkpoint != 0 || exit {
+		t.Errorf("expected fades not re-analyzed, got %v, %v", checkpoint, exit)
+	}
+	unknown := &persistence.Position{ID: 3, Asset: "BTC", Direction: "above", EntryTime: entry}
+	if checkpoint, exit, _ := monitor.CheckDecayCheckpoint(context.Background(), unknown, analyzer, now); checkpoint != 0 || exit {
+		t.Errorf("expected unknown close time not re-analyzed, got %v, %v", checkpoint, exit)
+	}
+}
+
+func TestSetDecayCheckpoints_RejectsOutOfRange(t *testing.T) {
+	monitor := NewMonitor(0.15)
+	for _, f := range []float64{0, 1, -0.5, 1.5} {
+		if err := monitor.SetDecayCheckpoints([]float64{f}); err == nil {
+			t.Errorf("expected error for checkpoint %v", f)
+		}
+	}
+}
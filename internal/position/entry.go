@@ -0,0 +1,286 @@
+package position
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Entry strategies, recorded on each position.
+const (
+	// EntryStrategyMarket buys with a market order, filling as deep into the
+	// book as it must.
+	EntryStrategyMarket = "market"
+	// EntryStrategyLimit posts a limit order a tick inside the spread and
+	// fills passively.
+	EntryStrategyLimit = "limit"
+	// EntryStrategyLimitCrossed is recorded for a limit entry that timed out
+	// and crossed the spread for the rest.
+	EntryStrategyLimitCrossed = "limit_crossed"
+	// EntryStrategyPassive posts a limit order a number of ticks below the
+	// quoted price and abandons whatever is unfilled at the timeout.
+	EntryStrategyPassive = "passive"
+	// EntryStrategyImported is recorded for a position found on a platform
+	// that the bot didn't enter, such as a trade placed by hand.
+	EntryStrategyImported = "imported"
+)
+
+// EntryTick is the price improvement over the best bid of a limit entry.
+const EntryTick = 0.01
+
+// defaultEntryTimeout is how long a limit entry rests when no timeout is set.
+const defaultEntryTimeout = 30 * time.Second
+
+// defaultBufferTicks is how far below the quoted price a passive entry bids
+// when no buffer is set.
+const defaultBufferTicks = 1
+
+// EntryExecution configures how live entries are executed.
+type EntryExecution struct {
+	// Strategy is EntryStrategyMarket, EntryStrategyLimit or
+	// EntryStrategyPassive. Empty uses EntryStrategyMarket.
+	Strategy string
+	// Timeout is how long a limit entry rests before it is cancelled. Zero
+	// uses 30 seconds.
+	Timeout time.Duration
+	// CrossOnTimeout buys what is left unfilled at the ask once the limit
+	// order times out. Otherwise the rest of the entry is abandoned.
+	// Passive entries never cross.
+	CrossOnTimeout bool
+	// BufferTicks is how many ticks below the quoted price a passive entry
+	// bids. Zero uses 1.
+	BufferTicks int
+}
+
+// SetEntryExecution sets how live and paper-traded entries are executed.
+// Other dry-run entries are recorded at the quoted price.
+func (m *Manager) SetEntryExecution(exec EntryExecution) error {
+	switch exec.Strategy {
+	case "", EntryStrategyMarket, EntryStrategyLimit, EntryStrategyPassive:
+	default:
+		return fmt.Errorf("unknown entry strategy %q (want %q, %q or %q)", exec.Strategy, EntryStrategyMarket, EntryStrategyLimit, EntryStrategyPassive)
+	}
+	if exec.BufferTicks < 0 {
+		return fmt.Errorf("entry buffer must not be negative, got %d ticks", exec.BufferTicks)
+	}
+	if exec.Timeout <= 0 {
+		exec.Timeout = defaultEntryTimeout
+	}
+	if exec.BufferTicks == 0 {
+		exec.BufferTicks = defaultBufferTicks
+	}
+	m.entry = exec
+	return nil
+}
+
+// entryFill is the combined result of the orders placed for an entry.
+type entryFill struct {
+	Quantity float64
+	Price    float64 // Average fill price
+	Fees     float64
+	Strategy string
+}
+
+// limitEntryPrice returns the price of a limit entry: a tick above the best
+// bid, but never above the best ask. price is the midpoint of the side being
+// bought and spread is the best ask minus the best bid.
+func limitEntryPrice(price, spread float64) (limit, ask float64) {
+	bid := price - spread/2
+	ask = roundCents(price + spread/2)
+	limit = math.Min(roundCents(bid+EntryTick), ask)
+	return limit, ask
+}
+
+// passiveEntryPrice returns the price of a passive entry: ticks below the
+// quoted price, but at least a tick.
+func passiveEntryPrice(price float64, ticks int) float64 {
+	return math.Max(roundCents(price-float64(ticks)*EntryTick), EntryTick)
+}
+
+// resting reports whether entries are placed as resting limit orders.
+func (e EntryExecution) resting() bool {
+	return e.Strategy == EntryStrategyLimit || e.Strategy == EntryStrategyPassive
+}
+
+// roundCents rounds a price to the nearest cent.
+func roundCents(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// executeEntry places the orders of a pending position's entry: live
+// through the platform's orderer, and against the paper orderer in dry run.
+// placed is false if the entry is recorded at the quoted price.
+func (m *Manager) executeEntry(ctx context.Context, position *persistence.Position, spread float64, dryRun bool) (fill entryFill, placed bool, err error) {
+	if dryRun && !m.placesEntryOrder(position.Platform, dryRun) {
+		return fill, false, nil
+	}
+	orderer := m.entryOrderer(position, dryRun)
+	if m.entry.resting() {
+		return m.buyEntry(ctx, orderer, position, spread)
+	}
+	return m.buyMarket(ctx, orderer, position)
+}
+
+// entryOrderer returns the orderer a position's orders are placed through:
+// the platform's, journaled, live and the paper orderer in dry run. It is
+// nil if the platform has none.
+func (m *Manager) entryOrderer(position *persistence.Position, dryRun bool) PlatformOrderer {
+	if dryRun {
+		return m.paper[position.Platform]
+	}
+	return m.journal(m.orderers[position.Platform], position)
+}
+
+// placesEntryOrder reports whether executeEntry places an order for an
+// entry on platform, in which case the position is recorded pending first.
+func (m *Manager) placesEntryOrder(platform string, dryRun bool) bool {
+	if dryRun {
+		_, ok := m.paper[platform]
+		return ok
+	}
+	return m.orderers[platform] != nil
+}
+
+// buyMarket enters a pending position with a market order for its whole
+// quantity, filling as deep into the book as it must. placed is false if
+// orderer is nil.
+func (m *Manager) buyMarket(ctx context.Context, orderer PlatformOrderer, position *persistence.Position) (fill entryFill, placed bool, err error) {
+	if orderer == nil {
+		log.Warn().
+			Int64("position_id", position.ID).
+			Str("platform", position.Platform).
+			Msg("No orderer registered for platform, entry recorded at the quoted price")
+		return fill, false, nil
+	}
+
+	result, err := orderer.PlaceOrder(ctx, types.Order{
+		MarketID:    position.MarketID,
+		TokenID:     orderTokenID(position),
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeMarket,
+		Price:       position.EntryPrice,
+		Size:        position.Quantity,
+		TimeInForce: types.TimeInForceIOC,
+	}, false)
+	if err != nil {
+		return fill, false, fmt.Errorf("place entry order: %w", err)
+	}
+	if result, err = m.awaitFill(ctx, orderer, result, m.entry.Timeout, "entry"); err != nil {
+		return fill, true, err
+	}
+	fill.add(result)
+	fill.Strategy = EntryStrategyMarket
+
+	log.Info().
+		Int64("position_id", position.ID).
+		Str("strategy", fill.Strategy).
+		Float64("filled", fill.Quantity).
+		Float64("fill_price", fill.Price).
+		Float64("requested", position.Quantity).
+		Msg("Entry order completed")
+
+	return fill, true, nil
+}
+
+// buyEntry enters a pending position with a limit order inside the spread,
+// waits up to the entry timeout for it to fill, then crosses the spread for
+// the rest or abandons it. A passive entry bids below the quoted price
+// instead and always abandons the rest. placed is false if orderer is nil.
+// The fill's strategy is set even if nothing filled, so unfilled entries
+// count towards the strategy's fill rate.
+func (m *Manager) buyEntry(ctx context.Context, orderer PlatformOrderer, position *persistence.Position, spread float64) (fill entryFill, placed bool, err error) {
+	if orderer == nil {
+		log.Warn().
+			Int64("position_id", position.ID).
+			Str("platform", position.Platform).
+			Msg("No orderer registered for platform, entry recorded at the quoted price")
+		return fill, false, nil
+	}
+
+	limit, ask := limitEntryPrice(position.EntryPrice, spread)
+	fill.Strategy = EntryStrategyLimit
+	cross := m.entry.CrossOnTimeout
+	if m.entry.Strategy == EntryStrategyPassive {
+		limit = passiveEntryPrice(position.EntryPrice, m.entry.BufferTicks)
+		fill.Strategy = EntryStrategyPassive
+		cross = false
+	}
+
+	order := types.Order{
+		MarketID:    position.MarketID,
+		TokenID:     orderTokenID(position),
+		Side:        types.OrderSideBuy,
+		Type:        types.OrderTypeLimit,
+		Price:       limit,
+		Size:        position.Quantity,
+		TimeInForce: types.TimeInForceGTC,
+	}
+
+	result, err := orderer.PlaceOrder(ctx, order, false)
+	if err != nil {
+		return fill, false, fmt.Errorf("place entry order: %w", err)
+	}
+	if result, err = m.awaitFill(ctx, orderer, result, m.entry.Timeout, "entry"); err != nil {
+		return fill, true, err
+	}
+	fill.add(result)
+
+	// Nothing more is bought once ctx is done, such as when shutdown
+	// drains, and less than a lot left unfilled can't be bought
+	remaining := m.roundLots(position.Platform, position.Quantity-fill.Quantity)
+	if remaining > 0 && cross && ctx.Err() == nil {
+		order.Price = ask
+		order.Size = remaining
+		order.TimeInForce = types.TimeInForceIOC
+
+		result, err = orderer.PlaceOrder(ctx, order, false)
+		if err != nil {
+			return fill, true, fmt.Errorf("place crossing entry order: %w", err)
+		}
+		if result, err = m.awaitFill(ctx, orderer, result, m.entry.Timeout, "crossing entry"); err != nil {
+			return fill, true, err
+		}
+		if result.Filled > 0 {
+			fill.add(result)
+			fill.Strategy = EntryStrategyLimitCrossed
+		}
+	}
+
+	log.Info().
+		Int64("position_id", position.ID).
+		Str("strategy", fill.Strategy).
+		Float64("limit_price", limit).
+		Float64("filled", fill.Quantity).
+		Float64("fill_price", fill.Price).
+		Float64("requested", position.Quantity).
+		Msg("Entry order completed")
+
+	return fill, true, nil
+}
+
+// add folds an order's fill into the entry, averaging the fill price.
+func (f *entryFill) add(result types.OrderResult) {
+	if result.Filled <= 0 {
+		return
+	}
+	quantity := f.Quantity + result.Filled
+	f.Price = (f.Price*f.Quantity + result.FillPrice()*result.Filled) / quantity
+	f.Quantity = quantity
+	f.Fees += result.Fees
+}
+
+// orderTokenID returns the token a position's orders trade. Positions
+// without one (Kalshi) are traded by contract side.
+func orderTokenID(position *persistence.Position) string {
+	if position.TokenID == "" {
+		return strings.ToLower(position.Side)
+	}
+	return position.TokenID
+}
@@ -0,0 +1,491 @@
+package position
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/paper"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+// ScriptedOrderer mocks a platform client whose orders fill by a script, one
+// entry per order placed; orders past the script don't fill. A GTC order
+// filled for less than its size rests until it is cancelled.
+type ScriptedOrderer struct {
+	fills     []float64 // Fraction of each order's size filled
+	prices    []float64 // Average fill price of each order
+	placed    []types.Order
+	cancelled []string
+	orders    map[string]types.OrderResult
+}
+
+func (m *ScriptedOrderer) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	i := len(m.placed)
+	m.placed = append(m.placed, order)
+
+	var filled, price float64
+	if i < len(m.fills) {
+		filled, price = m.fills[i]*order.Size, m.prices[i]
+	}
+	status := types.OrderStatusFilled
+	if filled < order.Size {
+		status = types.OrderStatusOpen
+		if order.TimeInForce == types.TimeInForceIOC {
+			status = types.OrderStatusCancelled
+		}
+	}
+
+	result := types.OrderResult{
+		OrderID:      fmt.Sprintf("entry-%d", i+1),
+		MarketID:     order.MarketID,
+		TokenID:      order.TokenID,
+		Side:         order.Side,
+		Price:        order.Price,
+		Size:         order.Size,
+		Filled:       filled,
+		AvgFillPrice: price,
+		Status:       status,
+	}
+	if m.orders == nil {
+		m.orders = make(map[string]types.OrderResult)
+	}
+	m.orders[result.OrderID] = result
+	return result, nil
+}
+
+func (m *ScriptedOrderer) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	return m.orders[orderID], nil
+}
+
+func (m *ScriptedOrderer) CancelOrder(orderID string) error {
+	m.cancelled = append(m.cancelled, orderID)
+	result := m.orders[orderID]
+	result.Status = types.OrderStatusCancelled
+	m.orders[orderID] = result
+	return nil
+}
+
+// setupLimitEntry creates a manager that enters with limit orders through
+// orderer, and a market quoted at 0.80 with a 0.04 spread.
+func setupLimitEntry(t *testing.T, orderer *ScriptedOrderer, crossOnTimeout bool) (*Manager, *persistence.PositionRepository, *persistence.BankrollRepository, scanner.EligibleMarket) {
+	t.Helper()
+
+	db, cleanup := setupTestDB(t)
+	t.Cleanup(cleanup)
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+
+	mockVolatility := &MockVolatilityService{
+		result: volatility.ServiceResult{
+			Asset:          "BTC",
+			SafetyMargin:   1.91,
+			Volatility:     0.5,
+			Recommendation: volatility.RecommendationValid,
+		},
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+
+	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizer)
+	manager.SetPlatformOrderer("polymarket", orderer)
+	err := manager.SetEntryExecution(EntryExecution{
+		Strategy:       EntryStrategyLimit,
+		Timeout:        time.Nanosecond,
+		CrossOnTimeout: crossOnTimeout,
+	})
+	if err != nil {
+		t.Fatalf("SetEntryExecution failed: %v", err)
+	}
+
+	market := scanner.EligibleMarket{
+		Market: types.Market{
+			ID:              "test-market-limit",
+			Platform:        "polymarket",
+			Title:           "Will Bitcoin be above $95,000 on Jan 20?",
+			EndDate:         time.Now().Add(24 * time.Hour),
+			OutcomeYesPrice: 0.80,
+			Spread:          0.04,
+			Liquidity:       1000.0,
+		},
+		Parsed: &scanner.ParsedMarket{
+			Asset:     "BTC",
+			Strike:    95000.0,
+			Direction: "above",
+		},
+		Probability: 0.80,
+		BetSide:     "YES",
+	}
+
+	return manager, positionRepo, bankrollRepo, market
+}
+
+func TestLimitEntryPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     float64
+		spread    float64
+		wantLimit float64
+		wantAsk   float64
+	}{
+		{"tick inside wide spread", 0.80, 0.04, 0.79, 0.82},
+		{"one tick spread joins ask", 0.805, 0.01, 0.81, 0.81},
+		{"unknown spread uses quote", 0.80, 0, 0.80, 0.80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, ask := limitEntryPrice(tt.price, tt.spread)
+			if math.Abs(limit-tt.wantLimit) > 1e-9 || math.Abs(ask-tt.wantAsk) > 1e-9 {
+				t.Errorf("limitEntryPrice(%v, %v) = %v, %v, want %v, %v", tt.price, tt.spread, limit, ask, tt.wantLimit, tt.wantAsk)
+			}
+		})
+	}
+}
+
+func TestSetEntryExecutionRejectsUnknownStrategy(t *testing.T) {
+	manager := NewManager(nil, nil, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	if err := manager.SetEntryExecution(EntryExecution{Strategy: "iceberg"}); err == nil {
+		t.Error("Expected error for unknown entry strategy")
+	}
+	if err := manager.SetEntryExecution(EntryExecution{Strategy: EntryStrategyPassive, BufferTicks: -1}); err == nil {
+		t.Error("Expected error for a negative entry buffer")
+	}
+}
+
+// TestProcessEntryLimitFilled tests that a filled limit entry records the
+// improved price and debits its actual cost.
+func TestProcessEntryLimitFilled(t *testing.T) {
+	orderer := &ScriptedOrderer{fills: []float64{1}, prices: []float64{0.79}}
+	manager, positionRepo, bankrollRepo, market := setupLimitEntry(t, orderer, true)
+
+	result, err := manager.ProcessEntry(context.Background(), market, false)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("Expected entry, got skipped: %s", result.SkipReason)
+	}
+
+	if len(orderer.placed) != 1 {
+		t.Fatalf("Expected 1 entry order, got %d", len(orderer.placed))
+	}
+	order := orderer.placed[0]
+	if order.Side != types.OrderSideBuy || order.Price != 0.79 || order.TimeInForce != types.TimeInForceGTC {
+		t.Errorf("Expected GTC buy at 0.79, got %+v", order)
+	}
+
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != persistence.PositionStatusOpen {
+		t.Errorf("Expected status open, got %s", pos.Status)
+	}
+	if pos.EntryPrice != 0.79 {
+		t.Errorf("Expected entry price 0.79, got %v", pos.EntryPrice)
+	}
+	if pos.EntryStrategy != EntryStrategyLimit {
+		t.Errorf("Expected entry strategy %s, got %s", EntryStrategyLimit, pos.EntryStrategy)
+	}
+
+	bankroll, err := bankrollRepo.Get("polymarket")
+	if err != nil {
+		t.Fatalf("Failed to get bankroll: %v", err)
+	}
+	wantBalance := 50.0 - 0.79*pos.Quantity
+	if math.Abs(bankroll.CurrentAmount-wantBalance) > 0.0001 {
+		t.Errorf("Expected bankroll %.4f, got %.4f", wantBalance, bankroll.CurrentAmount)
+	}
+}
+
+// TestProcessEntryLimitCrossesOnTimeout tests that a limit entry left
+// partially filled at the timeout buys the rest at the ask.
+func TestProcessEntryLimitCrossesOnTimeout(t *testing.T) {
+	orderer := &ScriptedOrderer{fills: []float64{0.5, 1}, prices: []float64{0.79, 0.82}}
+	manager, positionRepo, _, market := setupLimitEntry(t, orderer, true)
+
+	result, err := manager.ProcessEntry(context.Background(), market, false)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("Expected entry, got skipped: %s", result.SkipReason)
+	}
+
+	if len(orderer.cancelled) != 1 || orderer.cancelled[0] != "entry-1" {
+		t.Errorf("Expected resting limit order to be cancelled, got %v", orderer.cancelled)
+	}
+	if len(orderer.placed) != 2 {
+		t.Fatalf("Expected limit and crossing orders, got %d", len(orderer.placed))
+	}
+	cross := orderer.placed[1]
+	if cross.Price != 0.82 || cross.TimeInForce != types.TimeInForceIOC {
+		t.Errorf("Expected IOC at the 0.82 ask, got %+v", cross)
+	}
+	if math.Abs(cross.Size-orderer.placed[0].Size/2) > 1e-9 {
+		t.Errorf("Expected crossing order for the unfilled half, got %v", cross.Size)
+	}
+
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.EntryStrategy != EntryStrategyLimitCrossed {
+		t.Errorf("Expected entry strategy %s, got %s", EntryStrategyLimitCrossed, pos.EntryStrategy)
+	}
+	if math.Abs(pos.EntryPrice-0.805) > 1e-9 {
+		t.Errorf("Expected average entry price 0.805, got %v", pos.EntryPrice)
+	}
+}
+
+// TestProcessEntryLimitAbandonsOnTimeout tests that an unfilled limit entry
+// is abandoned when crossing is disabled, leaving the bankroll untouched.
+func TestProcessEntryLimitAbandonsOnTimeout(t *testing.T) {
+	orderer := &ScriptedOrderer{}
+	manager, positionRepo, bankrollRepo, market := setupLimitEntry(t, orderer, false)
+
+	result, err := manager.ProcessEntry(context.Background(), market, false)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if !result.Skipped || result.SkipReason != SkipReasonEntryUnfilled {
+		t.Fatalf("Expected skip %s, got %+v", SkipReasonEntryUnfilled, result)
+	}
+	if len(orderer.placed) != 1 || len(orderer.cancelled) != 1 {
+		t.Errorf("Expected one cancelled limit order, got %d placed and %d cancelled", len(orderer.placed), len(orderer.cancelled))
+	}
+
+	pos, err := positionRepo.GetByMarket("polymarket", market.Market.ID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos != nil {
+		t.Errorf("Expected no active position, got status %s", pos.Status)
+	}
+	closed, err := positionRepo.GetClosed()
+	if err != nil {
+		t.Fatalf("Failed to get closed positions: %v", err)
+	}
+	if len(closed) != 1 || closed[0].ExitReason == nil || *closed[0].ExitReason != orders.ExitReasonUnfilled {
+		t.Errorf("Expected abandoned entry closed as %s, got %v", orders.ExitReasonUnfilled, closed)
+	}
+
+	bankroll, err := bankrollRepo.Get("polymarket")
+	if err != nil {
+		t.Fatalf("Failed to get bankroll: %v", err)
+	}
+	if bankroll.CurrentAmount != 50.0 {
+		t.Errorf("Expected bankroll untouched at 50, got %v", bankroll.CurrentAmount)
+	}
+}
+
+func TestPassiveEntryPrice(t *testing.T) {
+	tests := []struct {
+		price float64
+		ticks int
+		want  float64
+	}{
+		{0.80, 1, 0.79},
+		{0.80, 3, 0.77},
+		{0.02, 5, 0.01},
+	}
+
+	for _, tt := range tests {
+		if got := passiveEntryPrice(tt.price, tt.ticks); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("passiveEntryPrice(%v, %d) = %v, want %v", tt.price, tt.ticks, got, tt.want)
+		}
+	}
+}
+
+// TestProcessEntryPassive tests that a passive entry bids its buffer below
+// the quoted price, never crosses, and records its strategy whether or not
+// it fills.
+func TestProcessEntryPassive(t *testing.T) {
+	for _, filled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("filled=%v", filled), func(t *testing.T) {
+			orderer := &ScriptedOrderer{}
+			if filled {
+				orderer.fills, orderer.prices = []float64{1}, []float64{0.78}
+			}
+			manager, positionRepo, _, market := setupLimitEntry(t, orderer, true)
+			err := manager.SetEntryExecution(EntryExecution{
+				Strategy:       EntryStrategyPassive,
+				Timeout:        time.Nanosecond,
+				CrossOnTimeout: true,
+				BufferTicks:    2,
+			})
+			if err != nil {
+				t.Fatalf("SetEntryExecution failed: %v", err)
+			}
+
+			result, err := manager.ProcessEntry(context.Background(), market, false)
+			if err != nil {
+				t.Fatalf("ProcessEntry failed: %v", err)
+			}
+			if result.Skipped == filled {
+				t.Fatalf("Expected skipped %v, got %+v", !filled, result)
+			}
+
+			if len(orderer.placed) != 1 {
+				t.Fatalf("Expected only the passive order, got %d orders", len(orderer.placed))
+			}
+			if order := orderer.placed[0]; order.Price != 0.78 || order.TimeInForce != types.TimeInForceGTC {
+				t.Errorf("Expected GTC buy two ticks below 0.80, got %+v", order)
+			}
+
+			var pos *persistence.Position
+			if filled {
+				pos, err = positionRepo.GetByID(result.PositionID)
+			} else {
+				var closed []*persistence.Position
+				closed, err = positionRepo.GetClosed()
+				if len(closed) != 1 {
+					t.Fatalf("Expected the abandoned entry closed, got %d closed", len(closed))
+				}
+				pos = closed[0]
+			}
+			if err != nil {
+				t.Fatalf("Failed to get position: %v", err)
+			}
+			if pos.EntryStrategy != EntryStrategyPassive {
+				t.Errorf("Expected entry strategy %s, got %s", EntryStrategyPassive, pos.EntryStrategy)
+			}
+		})
+	}
+}
+
+// StaticBook serves a fixed order book to a paper exchange.
+type StaticBook struct {
+	book types.OrderBook
+}
+
+func (s *StaticBook) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	book := s.book
+	return &book, nil
+}
+
+// TestPaperTradedDryRun tests that a paper-traded dry run fills its entry
+// and exit against the order book instead of at the quoted prices.
+func TestPaperTradedDryRun(t *testing.T) {
+	orderer := &ScriptedOrderer{}
+	manager, positionRepo, bankrollRepo, market := setupLimitEntry(t, orderer, false)
+	if err := manager.SetEntryExecution(EntryExecution{Strategy: EntryStrategyMarket}); err != nil {
+		t.Fatalf("SetEntryExecution failed: %v", err)
+	}
+	book := &StaticBook{book: types.OrderBook{
+		Bids: []types.Level{{Price: 0.76, Size: 1000}},
+		Asks: []types.Level{{Price: 0.81, Size: 2}, {Price: 0.84, Size: 1000}},
+	}}
+	manager.SetPaperOrderer("polymarket", paper.NewExchange(book, 0))
+
+	result, err := manager.ProcessEntry(context.Background(), market, true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("Expected entry, got skip %s", result.SkipReason)
+	}
+	if len(orderer.placed) != 0 {
+		t.Errorf("Expected no live orders in dry run, got %d", len(orderer.placed))
+	}
+
+	// 2 at 0.81, the rest at 0.84
+	wantPrice := (2*0.81 + (result.Quantity-2)*0.84) / result.Quantity
+	if math.Abs(result.EntryPrice-wantPrice) > 1e-9 {
+		t.Errorf("Expected entry at the book's average %v, got %v", wantPrice, result.EntryPrice)
+	}
+	bankroll, err := bankrollRepo.Get("polymarket")
+	if err != nil {
+		t.Fatalf("Failed to get bankroll: %v", err)
+	}
+	if math.Abs(bankroll.CurrentAmount-(50-result.PositionSize)) > 1e-6 {
+		t.Errorf("Expected bankroll debited the filled cost %v, got %v", result.PositionSize, bankroll.CurrentAmount)
+	}
+
+	// A sell at 0.75 or better fills at the 0.76 bid
+	exit, err := manager.ExecuteExit(context.Background(), result.PositionID, 0.75, ExitReasonStopLoss, true)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+	if exit.ExitPrice != 0.76 || exit.OrderID == "" {
+		t.Errorf("Expected paper sell filled at 0.76, got %+v", exit)
+	}
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != persistence.PositionStatusClosed {
+		t.Errorf("Expected position closed, got %s", pos.Status)
+	}
+}
+
+// TestLiveMarketEntry tests that a live market entry buys through the
+// platform's orderer and records what filled.
+func TestLiveMarketEntry(t *testing.T) {
+	orderer := &ScriptedOrderer{fills: []float64{1}, prices: []float64{0.82}}
+	manager, positionRepo, _, market := setupLimitEntry(t, orderer, false)
+	if err := manager.SetEntryExecution(EntryExecution{Strategy: EntryStrategyMarket}); err != nil {
+		t.Fatalf("SetEntryExecution failed: %v", err)
+	}
+
+	result, err := manager.ProcessEntry(context.Background(), market, false)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("Expected entry, got skip %s", result.SkipReason)
+	}
+	if len(orderer.placed) != 1 || orderer.placed[0].Side != types.OrderSideBuy || orderer.placed[0].Type != types.OrderTypeMarket {
+		t.Fatalf("Expected one market buy, got %+v", orderer.placed)
+	}
+	if result.EntryPrice != 0.82 || result.Strategy != EntryStrategyMarket {
+		t.Errorf("Expected the market fill at 0.82 recorded, got %+v", result)
+	}
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != persistence.PositionStatusOpen || pos.Quantity != orderer.placed[0].Size {
+		t.Errorf("Expected the filled position open, got %s with %v", pos.Status, pos.Quantity)
+	}
+}
+
+// TestLimitEntryWholeLots tests that an entry on a platform trading whole
+// contracts is sized in them, and doesn't cross for less than one.
+func TestLimitEntryWholeLots(t *testing.T) {
+	orderer := &ScriptedOrderer{fills: []float64{0.95}, prices: []float64{0.79}}
+	manager, positionRepo, _, market := setupLimitEntry(t, orderer, true)
+	manager.SetLotSize("polymarket", 1)
+
+	result, err := manager.ProcessEntry(context.Background(), market, false)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("Expected entry, got skip %s", result.SkipReason)
+	}
+	if len(orderer.placed) != 1 {
+		t.Fatalf("Expected no crossing order for less than a contract, got %d orders", len(orderer.placed))
+	}
+	if size := orderer.placed[0].Size; size != math.Floor(size) || size < 1 {
+		t.Errorf("Expected the entry sized in whole contracts, got %v", size)
+	}
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != persistence.PositionStatusOpen || pos.Quantity != result.Quantity {
+		t.Errorf("Expected the filled position open, got %s with %v", pos.Status, pos.Quantity)
+	}
+}
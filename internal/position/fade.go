@@ -0,0 +1,96 @@
+package position
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/volatility"
+)
+
+// TradeStrategyFade tags positions that buy the opposite of the side a
+// market favors, because the volatility model prices the favored side
+// well below the market. Positions that follow the market are untagged.
+const TradeStrategyFade = "fade"
+
+// SetFadeMinEdge enables fading: when the model probability of an eligible
+// market's favored side is at least edge below its price (e.g. market 0.90,
+// model 0.60 for an edge of 0.30), the opposite side is bought instead.
+// Zero disables fading.
+func (m *Manager) SetFadeMinEdge(edge float64) {
+	m.fadeMinEdge = edge
+}
+
+// fadeTrade is the opposite side of an eligible market's bet.
+type fadeTrade struct {
+	side       string
+	entryPrice float64
+	winProb    float64
+	analysis   volatility.ServiceResult
+}
+
+// fade returns the fade of market, whose favored side costs price and was
+// analyzed as followed, or nil if fading is disabled, the favored side
+// isn't overpriced by the minimum edge, or the opposite side can't be
+// bought. The fade is analyzed with the inverted direction.
+func (m *Manager) fade(ctx context.Context, market scanner.EligibleMarket, price float64, followed volatility.ServiceResult, timeToClose time.Duration) (*fadeTrade, error) {
+	if m.fadeMinEdge <= 0 {
+		return nil, nil
+	}
+	if volatility.WinProbability(followed.SafetyMargin) > price-m.fadeMinEdge {
+		return nil, nil
+	}
+
+	side := "NO"
+	if strings.EqualFold(market.BetSide, "NO") {
+		side = "YES"
+	}
+	if outcomeTokenID(market.Market, side) == "" {
+		return nil, nil
+	}
+
+	analysis, err := analyzeStrike(
+		ctx,
+		m.volatility,
+		market.Parsed.Asset,
+		market.Parsed.Strike,
+		market.Parsed.StrikeUpper,
+		fadeDirection(market.Parsed.Direction),
+		side,
+		timeToClose,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fadeTrade{
+		side:       side,
+		entryPrice: 1.0 - price,
+		winProb:    volatility.WinProbability(analysis.SafetyMargin),
+		analysis:   analysis,
+	}, nil
+}
+
+// fadeDirection inverts the direction a fade is analyzed in: a fade of an
+// above market wins below the strike and vice versa. Brackets are
+// analyzed by side, which the fade already inverts.
+func fadeDirection(direction string) string {
+	switch direction {
+	case "above":
+		return "below"
+	case "below":
+		return "above"
+	}
+	return direction
+}
+
+// analysisDirection returns the direction a position's asset is analyzed
+// in, inverted for fades.
+func analysisDirection(position *persistence.Position) string {
+	if position.TradeStrategy == TradeStrategyFade {
+		return fadeDirection(position.Direction)
+	}
+	return position.Direction
+}
@@ -0,0 +1,94 @@
+package position
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+func TestProcessEntryFadesOverpricedSide(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("kalshi", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+
+	// BTC barely above the strike: the model gives YES about 58%, against
+	// the market's 90%
+	service := &StrikeVolatilityService{margins: map[volatility.Direction]map[float64]float64{
+		volatility.DirectionAbove: {100000: 0.1},
+		volatility.DirectionBelow: {100000: -0.1},
+	}}
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20})
+	manager := NewManager(positionRepo, bankrollRepo, service, sizer)
+
+	market := scanner.EligibleMarket{
+		Market: types.Market{
+			ID:              "KXBTC-100K",
+			Platform:        "kalshi",
+			EndDate:         time.Now().Add(24 * time.Hour),
+			OutcomeYesPrice: 0.90,
+		},
+		Parsed:      &scanner.ParsedMarket{Asset: "BTC", Strike: 100000, Direction: "above"},
+		Probability: 0.90,
+		BetSide:     "YES",
+	}
+
+	// Without fading the favored side is rejected
+	result, err := manager.ProcessEntry(context.Background(), market, true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if !result.Skipped || result.SkipReason != SkipReasonVolatilityReject {
+		t.Fatalf("expected volatility reject without fading, got %+v", result)
+	}
+
+	// Fading needs more edge than the model gives
+	manager.SetFadeMinEdge(0.40)
+	if result, _ = manager.ProcessEntry(context.Background(), market, true); result.SkipReason != SkipReasonVolatilityReject {
+		t.Fatalf("expected volatility reject below the minimum edge, got %+v", result)
+	}
+
+	manager.SetFadeMinEdge(0.20)
+	result, err = manager.ProcessEntry(context.Background(), market, true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if result.Skipped {
+		t.Fatalf("expected the fade to be entered, skipped: %s", result.SkipReason)
+	}
+	if result.Side != "NO" || result.TradeStrategy != TradeStrategyFade || result.EntryPrice < 0.0999 || result.EntryPrice > 0.1001 {
+		t.Errorf("expected a NO fade at 0.10, got %+v", result)
+	}
+	if result.SafetyMargin != -0.1 {
+		t.Errorf("expected the fade analyzed below the strike, got margin %v", result.SafetyMargin)
+	}
+
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if pos.Side != "NO" || pos.TradeStrategy != TradeStrategyFade || pos.Direction != "above" {
+		t.Errorf("expected a NO fade on the above market, got %+v", pos)
+	}
+
+	// The monitor keeps analyzing the fade below the strike, and never
+	// exits it on its low margin
+	monitor := NewMonitor(0.15)
+	margin, err := monitor.currentSafetyMargin(context.Background(), pos, service, time.Hour)
+	if err != nil || margin != -0.1 {
+		t.Errorf("expected the fade's margin below the strike, got %v, %v", margin, err)
+	}
+	if exit, _ := monitor.CheckVolatilityExit(context.Background(), pos, service, time.Hour); exit {
+		t.Error("expected no volatility exit for a fade")
+	}
+}
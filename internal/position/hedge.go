@@ -0,0 +1,285 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/pkg/types"
+)
+
+// TradeStrategyHedge tags the legs of a hedge.
+const TradeStrategyHedge = "hedge"
+
+// ExitReasonHedgeUnwound closes, or sells down, the legs of a hedge whose
+// other leg filled less than they did.
+const ExitReasonHedgeUnwound = "hedge_unwound"
+
+// HedgeLeg is one side of a hedged entry.
+type HedgeLeg struct {
+	Market types.Market
+	Parsed *scanner.ParsedMarket
+	Side   string  // "YES" or "NO"
+	Price  float64 // Price paid per contract
+}
+
+// ProcessHedge opens the same quantity on every leg of a hedge, spending
+// size dollars across all legs. Hedged legs are not checked for volatility:
+// together they pay out whichever way the market resolves. Nothing is opened
+// if any leg's market already has a position, or its platform's bankroll or
+// the hedge allocation (see SetAllocation) can't cover the leg.
+//
+// Legs are bought one at a time with market orders where orders are placed
+// (see placesEntryOrder), each for what the legs before it filled. A leg that
+// fills less, or fails, leaves the earlier legs unhedged, so they are sold
+// down to its fill. If the first leg fills nothing the hedge is skipped with
+// SkipReasonEntryUnfilled.
+//
+// If a leg fails after others were opened, the opened legs that couldn't be
+// sold back are returned along with the error.
+func (m *Manager) ProcessHedge(ctx context.Context, legs []HedgeLeg, size float64, dryRun bool) ([]EntryResult, error) {
+	var cost float64
+	for _, leg := range legs {
+		if leg.Price <= 0 {
+			return nil, fmt.Errorf("invalid price %f for %s leg on %s", leg.Price, leg.Side, leg.Market.Platform)
+		}
+		cost += leg.Price
+	}
+	if len(legs) == 0 || size <= 0 {
+		return nil, nil
+	}
+
+	// Every leg must trade the quantity, so it is whole lots on all of them
+	quantity := size / cost
+	for _, leg := range legs {
+		quantity = m.roundLots(leg.Market.Platform, quantity)
+	}
+	if quantity <= 0 {
+		return []EntryResult{{Skipped: true, SkipReason: SkipReasonSizingTooSmall}}, nil
+	}
+
+	// Step 1: Check every leg before opening any
+	needed := make(map[string]types.Money)
+	for _, leg := range legs {
+		existing, err := m.positionRepo.GetByMarket(leg.Market.Platform, leg.Market.ID)
+		if err != nil {
+			return nil, fmt.Errorf("check duplicate position: %w", err)
+		}
+		if existing != nil {
+			return []EntryResult{{Skipped: true, SkipReason: SkipReasonDuplicate}}, nil
+		}
+
+		notional := quantity * leg.Price
+		needed[leg.Market.Platform] += types.Dollars(notional)
+		if dryRun {
+			needed[leg.Market.Platform] += types.Dollars(m.simulatedFee(leg.Market.Platform, notional))
+		}
+	}
+	for platform, amount := range needed {
+		bankroll, err := m.bankrollRepo.Get(platform)
+		if err != nil {
+			return nil, fmt.Errorf("get bankroll: %w", err)
+		}
+		if bankroll == nil || types.Dollars(bankroll.CurrentAmount) < amount {
+			return []EntryResult{{Skipped: true, SkipReason: SkipReasonInsufficientFunds}}, nil
+		}
+		available, err := m.strategyBankroll(bankroll, TradeStrategyHedge)
+		if err != nil {
+			return nil, fmt.Errorf("get strategy bankroll: %w", err)
+		}
+		if types.Dollars(available) < amount {
+			return []EntryResult{{Skipped: true, SkipReason: SkipReasonAllocationExhausted}}, nil
+		}
+	}
+
+	// Step 2: Open each leg for what the legs before it filled, selling the
+	// earlier legs down to any shortfall
+	var results []EntryResult
+	for i, leg := range legs {
+		result, err := m.openLeg(ctx, leg, m.roundLots(leg.Market.Platform, quantity), dryRun)
+		if err != nil {
+			err = fmt.Errorf("open %s leg on %s: %w", leg.Side, leg.Market.Platform, err)
+			return m.unwindLegs(ctx, legs[:i], results, 0, dryRun, err)
+		}
+		if result.Skipped {
+			if i == 0 {
+				return []EntryResult{result}, nil
+			}
+			err := fmt.Errorf("%s leg on %s: %s", leg.Side, leg.Market.Platform, result.SkipReason)
+			return m.unwindLegs(ctx, legs[:i], results, 0, dryRun, err)
+		}
+		if i > 0 && result.Quantity < quantity {
+			if unhedged, err := m.unwindLegs(ctx, legs[:i], results, result.Quantity, dryRun, nil); err != nil {
+				return append(unhedged, result), err
+			}
+		}
+		quantity = result.Quantity
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// openLeg buys one leg of a hedge and records the position and deducts its
+// cost from the platform's bankroll in one transaction. Where orders are
+// placed the position is recorded pending first, and opened at what filled.
+func (m *Manager) openLeg(ctx context.Context, leg HedgeLeg, quantity float64, dryRun bool) (EntryResult, error) {
+	result := EntryResult{}
+
+	positionSize := quantity * leg.Price
+	var fees float64
+	if dryRun {
+		fees = m.simulatedFee(leg.Market.Platform, positionSize)
+	}
+
+	position := &persistence.Position{
+		Platform:      leg.Market.Platform,
+		MarketID:      leg.Market.ID,
+		MarketTitle:   leg.Market.Title,
+		Outcome:       leg.Market.Outcome,
+		EntryPrice:    leg.Price,
+		Quantity:      quantity,
+		Side:          leg.Side,
+		TokenID:       outcomeTokenID(leg.Market, leg.Side),
+		Status:        persistence.PositionStatusPendingEntry,
+		Fees:          fees,
+		EntryStrategy: EntryStrategyMarket,
+		TradeStrategy: TradeStrategyHedge,
+	}
+	if leg.Parsed != nil {
+		position.Asset = leg.Parsed.Asset
+		position.Strike = leg.Parsed.Strike
+		position.StrikeUpper = leg.Parsed.StrikeUpper
+		position.Direction = leg.Parsed.Direction
+	}
+	if !leg.Market.EndDate.IsZero() {
+		closeTime := leg.Market.EndDate
+		position.MarketCloseTime = &closeTime
+	}
+
+	if m.placesEntryOrder(leg.Market.Platform, dryRun) {
+		if _, err := m.positionRepo.Create(position); err != nil {
+			return result, fmt.Errorf("create position: %w", err)
+		}
+
+		// Legs must fill together, so they are bought at market
+		fill, placed, err := m.buyMarket(ctx, m.entryOrderer(position, dryRun), position)
+		if err != nil {
+			m.markError(position)
+			return result, fmt.Errorf("execute entry: %w", err)
+		}
+		if placed {
+			if fill.Quantity <= 0 {
+				if err := m.positionRepo.Close(position.ID, leg.Price, orders.ExitReasonUnfilled, 0); err != nil {
+					return result, fmt.Errorf("abandon unfilled entry: %w", err)
+				}
+				return EntryResult{Skipped: true, SkipReason: SkipReasonEntryUnfilled}, nil
+			}
+			position.Quantity = fill.Quantity
+			position.EntryPrice = fill.Price
+			position.Fees = fill.Fees
+			positionSize = types.Cost(fill.Price, fill.Quantity).Float64()
+			fees = fill.Fees
+		}
+	}
+
+	result.PositionSize = positionSize
+	result.Quantity = position.Quantity
+	result.EntryPrice = position.EntryPrice
+	result.Fees = fees
+	result.Strategy = position.EntryStrategy
+	result.Side = leg.Side
+	result.TradeStrategy = TradeStrategyHedge
+
+	event := m.entryEvent(leg.Market.Platform, leg.Market.ID, result, dryRun)
+	if err := m.positionRepo.OpenEntry(position, types.Dollars(positionSize)+types.Dollars(fees), event); err != nil {
+		if position.ID != 0 {
+			m.markError(position)
+		}
+		return EntryResult{}, fmt.Errorf("open position: %w", err)
+	}
+	result.PositionID = position.ID
+
+	return result, nil
+}
+
+// unwindLegs sells the opened legs of a hedge down to quantity after a
+// later leg filled only that much, or failed with cause. It returns the
+// legs left unhedged, those that couldn't be sold down, with cause and the
+// errors selling them. Legs sold down are updated in results.
+func (m *Manager) unwindLegs(ctx context.Context, legs []HedgeLeg, results []EntryResult, quantity float64, dryRun bool, cause error) ([]EntryResult, error) {
+	errs := []error{cause}
+	var unhedged []EntryResult
+	for i, leg := range legs {
+		if err := m.unwindLeg(ctx, leg, results[i].PositionID, quantity, dryRun); err != nil {
+			errs = append(errs, fmt.Errorf("unwind %s leg on %s: %w", leg.Side, leg.Market.Platform, err))
+			unhedged = append(unhedged, results[i])
+			continue
+		}
+		results[i].Quantity = quantity
+		results[i].PositionSize = types.Cost(results[i].EntryPrice, quantity).Float64()
+	}
+	return unhedged, errors.Join(errs...)
+}
+
+// unwindLeg sells an opened leg down to quantity at the bid, closing it if
+// less than a lot is left.
+func (m *Manager) unwindLeg(ctx context.Context, leg HedgeLeg, positionID int64, quantity float64, dryRun bool) error {
+	position, err := m.positionRepo.GetByID(positionID)
+	if err != nil {
+		return fmt.Errorf("get position: %w", err)
+	}
+	if position == nil {
+		return fmt.Errorf("position not found: %d", positionID)
+	}
+	excess := m.roundLots(position.Platform, position.Quantity-quantity)
+	if excess <= 0 {
+		return nil
+	}
+
+	if err := m.positionRepo.Transition(position, persistence.PositionStatusExiting); err != nil {
+		return fmt.Errorf("claim position for unwind: %w", err)
+	}
+
+	// Sell only the excess, crossing the spread so it fills now
+	bid := math.Max(roundCents(leg.Price-leg.Market.Spread/2), EntryTick)
+	sale := *position
+	sale.Quantity = excess
+	var fill types.OrderResult
+	var placed bool
+	if m.placesEntryOrder(position.Platform, dryRun) {
+		if fill, placed, err = m.sellPosition(ctx, m.entryOrderer(position, dryRun), &sale, bid); err != nil {
+			m.release(position)
+			return fmt.Errorf("sell position: %w", err)
+		}
+	}
+
+	sold, price, fee := excess, bid, 0.0
+	if dryRun {
+		fee = m.simulatedFee(position.Platform, bid*excess)
+	}
+	if placed {
+		if fill.Filled <= 0 {
+			m.release(position)
+			return fmt.Errorf("%w: order %s", ErrExitNotFilled, fill.OrderID)
+		}
+		sold, price, fee = math.Min(fill.Filled, excess), fill.FillPrice(), fill.Fees
+	}
+
+	if m.roundLots(position.Platform, position.Quantity-sold) <= 0 {
+		_, err = m.closePosition(position, price, sold, fee, ExitReasonHedgeUnwound, ExitResult{})
+	} else {
+		_, err = m.recordPartialExit(position, sold, price, fee, ExitReasonHedgeUnwound, ExitResult{})
+	}
+	if err != nil {
+		return err
+	}
+	if sold < excess {
+		return fmt.Errorf("sold %v of %v contracts", sold, excess)
+	}
+	return nil
+}
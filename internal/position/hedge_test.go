@@ -0,0 +1,222 @@
+package position
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/pkg/types"
+)
+
+func hedgeLegs() []HedgeLeg {
+	parsed := &scanner.ParsedMarket{Asset: "BTC", Strike: 100000, Direction: "above"}
+	endDate := time.Now().Add(24 * time.Hour)
+	return []HedgeLeg{
+		{
+			Market: types.Market{ID: "KXBTC", Platform: "kalshi", EndDate: endDate},
+			Parsed: parsed,
+			Side:   "YES",
+			Price:  0.74,
+		},
+		{
+			Market: types.Market{ID: "0xBTC", Platform: "polymarket", EndDate: endDate},
+			Parsed: parsed,
+			Side:   "NO",
+			Price:  0.16,
+		},
+	}
+}
+
+// TestProcessHedgeOpensEqualQuantities tests that both legs are opened with
+// the same quantity and charged to their own platform.
+func TestProcessHedgeOpensEqualQuantities(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	bankrollRepo.Initialize("kalshi", 50.0)
+	bankrollRepo.Initialize("polymarket", 50.0)
+	positionRepo := persistence.NewPositionRepository(db)
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+
+	results, err := manager.ProcessHedge(context.Background(), hedgeLegs(), 9.0, false)
+	if err != nil {
+		t.Fatalf("ProcessHedge failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 legs opened, got %d", len(results))
+	}
+
+	// 9.0 buys 10 pairs at 0.74 + 0.16
+	for _, r := range results {
+		pos, _ := positionRepo.GetByID(r.PositionID)
+		if pos.Status != persistence.PositionStatusOpen {
+			t.Errorf("Expected leg open, got %s", pos.Status)
+		}
+		if pos.Quantity < 9.9999 || pos.Quantity > 10.0001 {
+			t.Errorf("Expected quantity 10, got %f", pos.Quantity)
+		}
+		if pos.Asset != "BTC" {
+			t.Errorf("Expected asset BTC, got %s", pos.Asset)
+		}
+	}
+
+	kalshi, _ := bankrollRepo.Get("kalshi")
+	if kalshi.CurrentAmount < 42.5999 || kalshi.CurrentAmount > 42.6001 {
+		t.Errorf("Expected kalshi bankroll 42.60, got %f", kalshi.CurrentAmount)
+	}
+	poly, _ := bankrollRepo.Get("polymarket")
+	if poly.CurrentAmount < 48.3999 || poly.CurrentAmount > 48.4001 {
+		t.Errorf("Expected polymarket bankroll 48.40, got %f", poly.CurrentAmount)
+	}
+}
+
+// TestProcessHedgeSkipsWhenALegCannotBeFunded tests that neither leg is
+// opened if one platform's bankroll is short.
+func TestProcessHedgeSkipsWhenALegCannotBeFunded(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	bankrollRepo.Initialize("kalshi", 5.0)
+	bankrollRepo.Initialize("polymarket", 50.0)
+	positionRepo := persistence.NewPositionRepository(db)
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+
+	results, err := manager.ProcessHedge(context.Background(), hedgeLegs(), 9.0, false)
+	if err != nil {
+		t.Fatalf("ProcessHedge failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Skipped || results[0].SkipReason != SkipReasonInsufficientFunds {
+		t.Fatalf("Expected hedge skipped for insufficient funds, got %+v", results)
+	}
+
+	open, _ := positionRepo.GetOpen()
+	if len(open) != 0 {
+		t.Errorf("Expected no positions opened, got %d", len(open))
+	}
+}
+
+// TestProcessHedgePlacesLiveOrders tests that live legs are bought with
+// market orders for whole lots, and opened at what filled.
+func TestProcessHedgePlacesLiveOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	bankrollRepo.Initialize("kalshi", 50.0)
+	bankrollRepo.Initialize("polymarket", 50.0)
+	positionRepo := persistence.NewPositionRepository(db)
+
+	kalshi := &ScriptedOrderer{fills: []float64{1}, prices: []float64{0.75}}
+	polymarket := &ScriptedOrderer{fills: []float64{1}, prices: []float64{0.17}}
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetPlatformOrderer("kalshi", kalshi)
+	manager.SetPlatformOrderer("polymarket", polymarket)
+	manager.SetLotSize("kalshi", 1)
+
+	// 9.5 buys 10.55 pairs, 10 in whole Kalshi contracts
+	results, err := manager.ProcessHedge(context.Background(), hedgeLegs(), 9.5, false)
+	if err != nil {
+		t.Fatalf("ProcessHedge failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 legs opened, got %+v", results)
+	}
+
+	for _, placed := range [][]types.Order{kalshi.placed, polymarket.placed} {
+		if len(placed) != 1 {
+			t.Fatalf("Expected 1 order per leg, got %+v", placed)
+		}
+		order := placed[0]
+		if order.Side != types.OrderSideBuy || order.Type != types.OrderTypeMarket || order.TimeInForce != types.TimeInForceIOC || order.Size != 10 {
+			t.Errorf("Expected a market buy of 10, got %+v", order)
+		}
+	}
+
+	pos, _ := positionRepo.GetByID(results[0].PositionID)
+	if pos.Status != persistence.PositionStatusOpen || pos.EntryPrice != 0.75 || pos.Quantity != 10 {
+		t.Errorf("Expected YES leg open at its fill, got %+v", pos)
+	}
+	kalshiBankroll, _ := bankrollRepo.Get("kalshi")
+	if kalshiBankroll.CurrentAmount != 42.5 {
+		t.Errorf("Expected kalshi bankroll 42.5, got %v", kalshiBankroll.CurrentAmount)
+	}
+}
+
+// TestProcessHedgeUnwindsUnmatchedLeg tests that when the second leg fills
+// nothing the first is sold back, and when it fills partially the first is
+// sold down to match.
+func TestProcessHedgeUnwindsUnmatchedLeg(t *testing.T) {
+	tests := []struct {
+		name       string
+		fill       float64 // Fraction of the second leg filled
+		wantErr    bool
+		wantLegs   int
+		wantSold   float64
+		wantStatus string
+	}{
+		{"unfilled", 0, true, 0, 10, persistence.PositionStatusClosed},
+		{"partially filled", 0.6, false, 2, 4, persistence.PositionStatusOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			bankrollRepo := persistence.NewBankrollRepository(db)
+			bankrollRepo.Initialize("kalshi", 50.0)
+			bankrollRepo.Initialize("polymarket", 50.0)
+			positionRepo := persistence.NewPositionRepository(db)
+
+			// The YES leg is sold back at 0.70
+			kalshi := &ScriptedOrderer{fills: []float64{1, 1}, prices: []float64{0.74, 0.70}}
+			polymarket := &ScriptedOrderer{fills: []float64{tt.fill}, prices: []float64{0.16}}
+			manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+			manager.SetPlatformOrderer("kalshi", kalshi)
+			manager.SetPlatformOrderer("polymarket", polymarket)
+
+			results, err := manager.ProcessHedge(context.Background(), hedgeLegs(), 9.0, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(results) != tt.wantLegs {
+				t.Fatalf("Expected %d legs returned, got %+v", tt.wantLegs, results)
+			}
+
+			if len(kalshi.placed) != 2 {
+				t.Fatalf("Expected the YES leg bought and sold, got %+v", kalshi.placed)
+			}
+			sell := kalshi.placed[1]
+			if sell.Side != types.OrderSideSell || sell.Size != tt.wantSold {
+				t.Errorf("Unexpected unwind order %+v", sell)
+			}
+
+			var yes *persistence.Position
+			positions, _ := positionRepo.GetByStatus(tt.wantStatus)
+			for _, pos := range positions {
+				if pos.Platform == "kalshi" {
+					yes = pos
+				}
+			}
+			if yes == nil {
+				t.Fatalf("Expected the YES leg %s", tt.wantStatus)
+			}
+			if tt.wantStatus == persistence.PositionStatusOpen && yes.Quantity != 10-tt.wantSold {
+				t.Errorf("Expected YES leg sold down to %v, got %v", 10-tt.wantSold, yes.Quantity)
+			}
+			if tt.wantStatus == persistence.PositionStatusClosed && (yes.ExitReason == nil || *yes.ExitReason != ExitReasonHedgeUnwound) {
+				t.Errorf("Expected YES leg closed as unwound, got %v", yes.ExitReason)
+			}
+			if err := bankrollRepo.CheckLedger("kalshi"); err != nil {
+				t.Errorf("Expected kalshi bankroll consistent with its ledger: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package position
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PositionLister fetches the positions the account holds on a platform.
+type PositionLister interface {
+	GetPositions() ([]types.Position, error)
+}
+
+// ImportResult counts what an import found.
+type ImportResult struct {
+	Imported int // Positions held on a platform but not recorded, recorded open
+	Known    int // Positions held on a platform and already recorded
+	Failed   int // Platforms whose positions couldn't be fetched
+}
+
+// String summarizes what an import found.
+func (r ImportResult) String() string {
+	return fmt.Sprintf("%d imported, %d already recorded, %d platforms unavailable",
+		r.Imported, r.Known, r.Failed)
+}
+
+// Importer records the positions held on the platforms that the database
+// doesn't know, such as trades placed by hand or entered before the
+// database was lost, so they are monitored and exited like the bot's own.
+// Their cost is debited from the bankroll as an entry's would be, so their
+// exit or settlement credits only what they made. It should run at startup
+// in live mode: dry-run positions are never on a platform.
+type Importer struct {
+	repo      *persistence.PositionRepository
+	platforms map[string]PositionLister
+	markets   map[string]MarketGetter
+}
+
+// NewImporter creates an Importer. Register the platforms whose positions
+// are imported with SetPlatform.
+func NewImporter(repo *persistence.PositionRepository) *Importer {
+	return &Importer{
+		repo:      repo,
+		platforms: make(map[string]PositionLister),
+		markets:   make(map[string]MarketGetter),
+	}
+}
+
+// SetPlatform registers a platform whose positions are imported.
+func (i *Importer) SetPlatform(name string, lister PositionLister) {
+	i.platforms[name] = lister
+}
+
+// SetMarketGetter sets the client a platform's markets are fetched with, to
+// complete what its positions don't report: the title, token and close time.
+func (i *Importer) SetMarketGetter(platform string, getter MarketGetter) {
+	i.markets[platform] = getter
+}
+
+// Run imports every unknown position on the registered platforms. A
+// position is known if one not yet closed is recorded on the same market,
+// outcome and side. A platform whose positions can't be fetched is counted
+// as failed and skipped.
+func (i *Importer) Run() (ImportResult, error) {
+	var result ImportResult
+
+	names := make([]string, 0, len(i.platforms))
+	for name := range i.platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		held, err := i.platforms[name].GetPositions()
+		if err != nil {
+			log.Warn().Err(err).Str("platform", name).Msg("Failed to fetch positions to import")
+			result.Failed++
+			continue
+		}
+
+		recorded, err := i.repo.GetActiveByPlatform(name)
+		if err != nil {
+			return result, err
+		}
+
+		for _, h := range held {
+			if h.Quantity == 0 {
+				continue
+			}
+			if isRecorded(recorded, h) {
+				result.Known++
+				continue
+			}
+
+			pos := i.importedPosition(name, h)
+			cost := types.Cost(pos.EntryPrice, pos.Quantity) + types.Dollars(pos.Fees)
+			if err := i.repo.OpenEntry(pos, cost, nil); err != nil {
+				return result, fmt.Errorf("import position on %s: %w", h.MarketTicker, err)
+			}
+			recorded = append(recorded, pos)
+			result.Imported++
+			log.Warn().
+				Int64("position_id", pos.ID).
+				Str("platform", name).
+				Str("market", pos.MarketID).
+				Str("side", pos.Side).
+				Float64("quantity", pos.Quantity).
+				Float64("entry_price", pos.EntryPrice).
+				Msg("Imported position held on the platform but not recorded")
+		}
+	}
+
+	return result, nil
+}
+
+// importedPosition builds the open position recording h, completed from its
+// market if one can be fetched.
+func (i *Importer) importedPosition(platform string, h types.Position) *persistence.Position {
+	pos := &persistence.Position{
+		Platform:      platform,
+		MarketID:      h.MarketTicker,
+		MarketTitle:   h.Title,
+		Outcome:       h.Outcome,
+		EntryPrice:    h.AveragePrice,
+		Quantity:      math.Abs(h.Quantity),
+		Side:          heldSide(h),
+		TokenID:       h.TokenID,
+		Status:        persistence.PositionStatusOpen,
+		Fees:          h.FeesPaid,
+		EntryStrategy: EntryStrategyImported,
+	}
+	closeTime := h.EndDate
+
+	if getter := i.markets[platform]; getter != nil && (pos.MarketTitle == "" || pos.TokenID == "" || closeTime.IsZero()) {
+		market, err := getter.GetMarket(pos.MarketID)
+		if err != nil || market == nil {
+			log.Debug().Err(err).Str("market", pos.MarketID).Msg("Failed to fetch market of imported position")
+		} else {
+			if pos.MarketTitle == "" {
+				pos.MarketTitle = market.Title
+			}
+			if pos.TokenID == "" {
+				pos.TokenID = positionTokenID(*market, pos)
+			}
+			if closeTime.IsZero() {
+				closeTime = market.EndDate
+			}
+		}
+	}
+	if !closeTime.IsZero() {
+		pos.MarketCloseTime = &closeTime
+	}
+
+	if parsed, err := scanner.ParseListedMarket(types.Market{ID: pos.MarketID, Title: pos.MarketTitle, Outcome: pos.Outcome}); err == nil {
+		pos.Asset = parsed.Asset
+		pos.Strike = parsed.Strike
+		pos.StrikeUpper = parsed.StrikeUpper
+		pos.Direction = parsed.Direction
+	}
+	return pos
+}
+
+// heldSide returns the side a platform position holds: YES for a positive
+// quantity, NO for a negative one.
+func heldSide(h types.Position) string {
+	if h.Quantity < 0 {
+		return "NO"
+	}
+	return "YES"
+}
+
+// isRecorded reports whether a position on h's market, outcome and side is
+// among recorded.
+func isRecorded(recorded []*persistence.Position, h types.Position) bool {
+	side := heldSide(h)
+	for _, pos := range recorded {
+		if pos.MarketID == h.MarketTicker && pos.Outcome == h.Outcome && pos.Side == side {
+			return true
+		}
+	}
+	return false
+}
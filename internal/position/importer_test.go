@@ -0,0 +1,98 @@
+package position
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// StaticPositions lists fixed platform positions, or fails with err.
+type StaticPositions struct {
+	positions []types.Position
+	err       error
+}
+
+func (s *StaticPositions) GetPositions() ([]types.Position, error) {
+	return s.positions, s.err
+}
+
+func TestImporter_Run(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("kalshi", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	repo := persistence.NewPositionRepository(db)
+	_, err := repo.Create(&persistence.Position{
+		Platform:   "kalshi",
+		MarketID:   "KXBTC-26MAR01-B100000",
+		EntryPrice: 0.9,
+		Quantity:   10,
+		Side:       "YES",
+		Status:     persistence.PositionStatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+
+	closeTime := time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)
+	importer := NewImporter(repo)
+	importer.SetPlatform("kalshi", &StaticPositions{positions: []types.Position{
+		{MarketTicker: "KXBTC-26MAR01-B100000", Quantity: 10, AveragePrice: 0.9},
+		{MarketTicker: "KXBTC-26MAR01-B90000", Quantity: -4, AveragePrice: 0.3, FeesPaid: 0.07},
+	}})
+	importer.SetMarketGetter("kalshi", &MockMarketGetter{markets: map[string]*types.Market{
+		"KXBTC-26MAR01-B90000": {
+			ID:      "KXBTC-26MAR01-B90000",
+			Title:   "Will Bitcoin be above $90,000 on March 1?",
+			EndDate: closeTime,
+		},
+	}})
+	importer.SetPlatform("polymarket", &StaticPositions{err: errors.New("unavailable")})
+
+	result, err := importer.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	want := ImportResult{Imported: 1, Known: 1, Failed: 1}
+	if result != want {
+		t.Errorf("Expected %+v, got %+v", want, result)
+	}
+
+	pos, err := repo.GetByMarket("kalshi", "KXBTC-26MAR01-B90000")
+	if err != nil || pos == nil {
+		t.Fatalf("Expected the unknown position imported, got %v", err)
+	}
+	if pos.Status != persistence.PositionStatusOpen || pos.Side != "NO" || pos.Quantity != 4 || pos.EntryPrice != 0.3 {
+		t.Errorf("Expected 4 NO open at 0.30, got %+v", pos)
+	}
+	if pos.EntryStrategy != EntryStrategyImported || pos.Fees != 0.07 {
+		t.Errorf("Expected the position marked imported with its fees, got %+v", pos)
+	}
+	if pos.Asset != "BTC" || pos.Strike != 90000 || pos.MarketCloseTime == nil || !pos.MarketCloseTime.Equal(closeTime) {
+		t.Errorf("Expected the position completed from its market, got %+v", pos)
+	}
+
+	bankroll, err := bankrollRepo.Get("kalshi")
+	if err != nil {
+		t.Fatalf("Failed to get bankroll: %v", err)
+	}
+	if math.Abs(bankroll.CurrentAmount-(50-4*0.3-0.07)) > 1e-9 {
+		t.Errorf("Expected the imported position's cost debited, got bankroll %v", bankroll.CurrentAmount)
+	}
+
+	// Once imported the position is known
+	again, err := importer.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if again.Imported != 0 || again.Known != 2 {
+		t.Errorf("Expected nothing imported twice, got %+v", again)
+	}
+}
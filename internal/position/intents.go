@@ -0,0 +1,273 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// journaledOrderer places a position's live orders, journaling each as an
+// intent before it is placed and settling the intent once the order
+// reaches a final state, so an order a crash interrupts is left unsettled
+// for IntentReconciler to find.
+type journaledOrderer struct {
+	PlatformOrderer
+	intents  *persistence.OrderIntentRepository
+	position *persistence.Position
+	// working holds the intents of the orders still resting, by order ID
+	working map[string]int64
+}
+
+// SetIntentJournal journals each live order as an intent before it is
+// placed (see IntentReconciler). Without it orders aren't journaled.
+func (m *Manager) SetIntentJournal(intents *persistence.OrderIntentRepository) {
+	m.intents = intents
+}
+
+// journal returns orderer, journaling the orders it places for position if
+// an intent journal is set. A nil orderer stays nil.
+func (m *Manager) journal(orderer PlatformOrderer, position *persistence.Position) PlatformOrderer {
+	if m.intents == nil || orderer == nil {
+		return orderer
+	}
+	return &journaledOrderer{
+		PlatformOrderer: orderer,
+		intents:         m.intents,
+		position:        position,
+		working:         make(map[string]int64),
+	}
+}
+
+// PlaceOrder journals the order's intent, then places it. An order that
+// fails to place leaves its intent pending, as the platform may have
+// received it regardless, unless it is known not to have been placed
+// (types.ErrOrderNotPlaced).
+func (o *journaledOrderer) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	positionID := o.position.ID
+	intent := &persistence.OrderIntent{
+		Platform:   o.position.Platform,
+		MarketID:   order.MarketID,
+		TokenID:    order.TokenID,
+		PositionID: &positionID,
+		Side:       string(order.Side),
+		Price:      order.Price,
+		Size:       order.Size,
+	}
+	if _, err := o.intents.Create(intent); err != nil {
+		return types.OrderResult{}, fmt.Errorf("journal order intent: %w", err)
+	}
+
+	result, err := o.PlatformOrderer.PlaceOrder(ctx, order, dryRun)
+	if errors.Is(err, types.ErrOrderNotPlaced) {
+		if rerr := o.intents.Resolve(intent.ID, persistence.OrderIntentRejected, err.Error()); rerr != nil {
+			log.Warn().Err(rerr).Int64("intent_id", intent.ID).Msg("Failed to journal rejected order")
+		}
+		return result, err
+	}
+	if err != nil {
+		return result, err
+	}
+	if !result.IsResting() {
+		o.settle(intent.ID)
+		return result, nil
+	}
+	if err := o.intents.MarkPlaced(intent.ID, result.OrderID); err != nil {
+		log.Warn().Err(err).Int64("intent_id", intent.ID).Str("order_id", result.OrderID).Msg("Failed to journal placed order")
+	}
+	o.working[result.OrderID] = intent.ID
+	return result, nil
+}
+
+// GetOrderStatus returns an order's status, settling its intent once it
+// reaches a final state.
+func (o *journaledOrderer) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	result, err := o.PlatformOrderer.GetOrderStatus(orderID)
+	if err != nil {
+		return result, err
+	}
+	if id, ok := o.working[orderID]; ok && !result.IsResting() {
+		o.settle(id)
+		delete(o.working, orderID)
+	}
+	return result, nil
+}
+
+// settle marks an intent settled. Failing to only costs a needless check
+// at the next start.
+func (o *journaledOrderer) settle(id int64) {
+	if err := o.intents.Resolve(id, persistence.OrderIntentSettled, ""); err != nil {
+		log.Warn().Err(err).Int64("intent_id", id).Msg("Failed to settle order intent")
+	}
+}
+
+// OrderLookup finds and cancels a platform's orders.
+type OrderLookup interface {
+	GetOrderStatus(orderID string) (types.OrderResult, error)
+	GetOpenOrders(marketID string) ([]types.OrderResult, error)
+	CancelOrder(orderID string) error
+}
+
+// IntentResult counts what an intent reconciliation found.
+type IntentResult struct {
+	Cancelled  int // Orphaned orders still resting, cancelled
+	Flagged    int // Open positions an orphaned order may have filled for, marked error to be checked against the platform
+	Resolved   int // Intents checked against the platform
+	Unresolved int // Intents whose platform couldn't be checked, left for the next start
+}
+
+// String summarizes what an intent reconciliation found.
+func (r IntentResult) String() string {
+	return fmt.Sprintf("%d intents resolved, %d orders cancelled, %d positions flagged, %d unresolved",
+		r.Resolved, r.Cancelled, r.Flagged, r.Unresolved)
+}
+
+// IntentReconciler resolves the order intents a crash left unsettled
+// against the platforms. Orphaned orders still resting are cancelled, and
+// an open position an orphaned order may have filled for is marked error,
+// for Reconciler to resolve against what the platform holds. Contracts an
+// orphaned entry bought for a position since closed are held but not
+// recorded, for Importer to adopt. It must run before Reconciler and
+// before trading starts: an order in progress looks orphaned.
+type IntentReconciler struct {
+	intents   *persistence.OrderIntentRepository
+	positions *persistence.PositionRepository
+	platforms map[string]OrderLookup
+}
+
+// NewIntentReconciler creates an IntentReconciler. Register the platforms
+// whose orders are checked with SetPlatform.
+func NewIntentReconciler(intents *persistence.OrderIntentRepository, positions *persistence.PositionRepository) *IntentReconciler {
+	return &IntentReconciler{
+		intents:   intents,
+		positions: positions,
+		platforms: make(map[string]OrderLookup),
+	}
+}
+
+// SetPlatform registers the client a platform's orders are checked with.
+func (r *IntentReconciler) SetPlatform(name string, lookup OrderLookup) {
+	r.platforms[name] = lookup
+}
+
+// Run resolves every unsettled intent whose platform is registered.
+func (r *IntentReconciler) Run() (IntentResult, error) {
+	var result IntentResult
+
+	unsettled, err := r.intents.GetUnsettled()
+	if err != nil {
+		return result, err
+	}
+	for _, intent := range unsettled {
+		lookup := r.platforms[intent.Platform]
+		if lookup == nil {
+			result.Unresolved++
+			continue
+		}
+
+		orders, err := r.findOrders(lookup, intent)
+		if err != nil {
+			log.Warn().Err(err).Int64("intent_id", intent.ID).Str("platform", intent.Platform).
+				Msg("Failed to check order intent against the platform")
+			result.Unresolved++
+			continue
+		}
+
+		cancelled, filled, err := cancelOrphans(lookup, orders)
+		result.Cancelled += cancelled
+		if err != nil {
+			log.Warn().Err(err).Int64("intent_id", intent.ID).Str("platform", intent.Platform).
+				Msg("Failed to cancel orphaned orders")
+			result.Unresolved++
+			continue
+		}
+
+		// A pending intent's order may have filled outright, out of sight
+		// of its open orders
+		mayHaveFilled := filled > 0 || intent.OrderID == ""
+		flagged := false
+		if mayHaveFilled && intent.PositionID != nil {
+			if flagged, err = r.flag(*intent.PositionID); err != nil {
+				return result, err
+			}
+		}
+		if flagged {
+			result.Flagged++
+		}
+
+		detail := fmt.Sprintf("%d orders found, %d cancelled, %g filled", len(orders), cancelled, filled)
+		if err := r.intents.Resolve(intent.ID, persistence.OrderIntentReconciled, detail); err != nil {
+			return result, err
+		}
+		result.Resolved++
+		log.Warn().Int64("intent_id", intent.ID).Str("platform", intent.Platform).Str("market", intent.MarketID).
+			Str("order_id", intent.OrderID).Int("cancelled", cancelled).Float64("filled", filled).Bool("flagged", flagged).
+			Msg("Reconciled order interrupted before it settled")
+	}
+	return result, nil
+}
+
+// findOrders returns an intent's order by ID once it was placed, or the
+// open orders on its market that match it if it may not have been.
+func (r *IntentReconciler) findOrders(lookup OrderLookup, intent *persistence.OrderIntent) ([]types.OrderResult, error) {
+	if intent.OrderID != "" {
+		order, err := lookup.GetOrderStatus(intent.OrderID)
+		if err != nil {
+			return nil, fmt.Errorf("get order status: %w", err)
+		}
+		return []types.OrderResult{order}, nil
+	}
+
+	open, err := lookup.GetOpenOrders(intent.MarketID)
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+	var matching []types.OrderResult
+	for _, order := range open {
+		if string(order.Side) == intent.Side && math.Abs(order.Price-intent.Price) < 1e-9 &&
+			(order.TokenID == "" || intent.TokenID == "" || order.TokenID == intent.TokenID) {
+			matching = append(matching, order)
+		}
+	}
+	return matching, nil
+}
+
+// cancelOrphans cancels the orders still resting and returns how many were
+// cancelled and how much the orders filled in all.
+func cancelOrphans(lookup OrderLookup, orders []types.OrderResult) (cancelled int, filled float64, err error) {
+	for _, order := range orders {
+		if order.IsResting() {
+			if err := lookup.CancelOrder(order.OrderID); err != nil {
+				return cancelled, filled, fmt.Errorf("cancel order %s: %w", order.OrderID, err)
+			}
+			cancelled++
+			if order, err = lookup.GetOrderStatus(order.OrderID); err != nil {
+				return cancelled, filled, fmt.Errorf("get order status after cancel: %w", err)
+			}
+		}
+		filled += order.Filled
+	}
+	return cancelled, filled, nil
+}
+
+// flag marks an open position error, reporting whether it was. Positions
+// pending entry or exiting are flagged by Reconciler already, and those in
+// error are resolved by it.
+func (r *IntentReconciler) flag(positionID int64) (bool, error) {
+	pos, err := r.positions.GetByID(positionID)
+	if err != nil {
+		return false, fmt.Errorf("get position: %w", err)
+	}
+	if pos == nil || pos.Status != persistence.PositionStatusOpen {
+		return false, nil
+	}
+	if err := r.positions.Transition(pos, persistence.PositionStatusError); err != nil {
+		return false, fmt.Errorf("flag position %d: %w", pos.ID, err)
+	}
+	return true, nil
+}
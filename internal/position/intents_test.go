@@ -0,0 +1,180 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/pkg/types"
+)
+
+// failingOrderer fails every order it is asked to place.
+type failingOrderer struct {
+	MockOrderer
+}
+
+func (o *failingOrderer) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	return types.OrderResult{}, errors.New("request timed out")
+}
+
+// rejectingOrderer refuses every order it is asked to place.
+type rejectingOrderer struct {
+	MockOrderer
+}
+
+func (o *rejectingOrderer) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	return types.OrderResult{}, fmt.Errorf("%w: Size must be at least 1 contract", types.ErrOrderNotPlaced)
+}
+
+func TestExecuteExitJournalsIntent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	intentRepo := persistence.NewOrderIntentRepository(db)
+	filledID := createOpenTestPosition(t, positionRepo, "market-filled")
+	failedID := createOpenTestPosition(t, positionRepo, "market-failed")
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetIntentJournal(intentRepo)
+
+	// A filled exit settles its intent
+	manager.SetPlatformOrderer("polymarket", &MockOrderer{fillQuantity: -1, fillPrice: 0.72})
+	if _, err := manager.ExecuteExit(context.Background(), filledID, 0.75, ExitReasonStopLoss, false); err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+	if unsettled, _ := intentRepo.GetUnsettled(); len(unsettled) != 0 {
+		t.Fatalf("Expected the filled exit's intent settled, got %+v", unsettled[0])
+	}
+
+	// One that failed to place may still have reached the platform
+	manager.SetPlatformOrderer("polymarket", &failingOrderer{})
+	if _, err := manager.ExecuteExit(context.Background(), failedID, 0.75, ExitReasonStopLoss, false); err == nil {
+		t.Fatal("Expected the exit to fail")
+	}
+	unsettled, err := intentRepo.GetUnsettled()
+	if err != nil {
+		t.Fatalf("GetUnsettled failed: %v", err)
+	}
+	if len(unsettled) != 1 || unsettled[0].MarketID != "market-failed" || unsettled[0].Status != persistence.OrderIntentPending {
+		t.Fatalf("Expected the failed exit's intent left pending, got %+v", unsettled)
+	}
+	if got := unsettled[0]; got.PositionID == nil || *got.PositionID != failedID || got.Side != "sell" || got.Size != 10 {
+		t.Errorf("Expected the sell journaled for position %d, got %+v", failedID, got)
+	}
+
+	// One known not to have been placed is resolved rejected
+	if err := intentRepo.Resolve(unsettled[0].ID, persistence.OrderIntentReconciled, ""); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	manager.SetPlatformOrderer("polymarket", &rejectingOrderer{})
+	if _, err := manager.ExecuteExit(context.Background(), failedID, 0.75, ExitReasonStopLoss, false); !errors.Is(err, types.ErrOrderNotPlaced) {
+		t.Fatalf("Expected the exit rejected, got %v", err)
+	}
+	if unsettled, _ := intentRepo.GetUnsettled(); len(unsettled) != 0 {
+		t.Errorf("Expected the rejected exit's intent resolved, got %+v", unsettled[0])
+	}
+}
+
+// fakeOrderLookup serves a platform's orders by ID.
+type fakeOrderLookup struct {
+	orders    map[string]types.OrderResult
+	cancelled []string
+}
+
+func (l *fakeOrderLookup) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	order, ok := l.orders[orderID]
+	if !ok {
+		return order, errors.New("order not found")
+	}
+	return order, nil
+}
+
+func (l *fakeOrderLookup) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	var open []types.OrderResult
+	for _, order := range l.orders {
+		if order.MarketID == marketID && order.IsResting() {
+			open = append(open, order)
+		}
+	}
+	return open, nil
+}
+
+func (l *fakeOrderLookup) CancelOrder(orderID string) error {
+	l.cancelled = append(l.cancelled, orderID)
+	order := l.orders[orderID]
+	order.Status = types.OrderStatusCancelled
+	l.orders[orderID] = order
+	return nil
+}
+
+func TestIntentReconciler(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positionRepo := persistence.NewPositionRepository(db)
+	intentRepo := persistence.NewOrderIntentRepository(db)
+	partialID := createOpenTestPosition(t, positionRepo, "market-partial")
+	untouchedID := createOpenTestPosition(t, positionRepo, "market-untouched")
+
+	journal := func(platform, marketID string, positionID int64, orderID string) {
+		intent := &persistence.OrderIntent{Platform: platform, MarketID: marketID, TokenID: "yes", PositionID: &positionID, Side: "sell", Price: 0.75, Size: 10}
+		if _, err := intentRepo.Create(intent); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if orderID != "" {
+			if err := intentRepo.MarkPlaced(intent.ID, orderID); err != nil {
+				t.Fatalf("MarkPlaced failed: %v", err)
+			}
+		}
+	}
+	// Placed and partially filled, then interrupted
+	journal("polymarket", "market-partial", partialID, "order-1")
+	// Pending, its order resting on the platform
+	journal("polymarket", "market-orphan", partialID, "")
+	// Placed, its order cancelled without a fill
+	journal("polymarket", "market-untouched", untouchedID, "order-3")
+	// On a platform that can't be checked
+	journal("kalshi", "market-kalshi", untouchedID, "")
+
+	lookup := &fakeOrderLookup{orders: map[string]types.OrderResult{
+		"order-1": {OrderID: "order-1", MarketID: "market-partial", Side: types.OrderSideSell, Price: 0.75, Size: 10, Filled: 4, Status: types.OrderStatusPartial},
+		"order-2": {OrderID: "order-2", MarketID: "market-orphan", TokenID: "yes", Side: types.OrderSideSell, Price: 0.75, Size: 10, Status: types.OrderStatusOpen},
+		"order-3": {OrderID: "order-3", MarketID: "market-untouched", Side: types.OrderSideSell, Price: 0.75, Size: 10, Status: types.OrderStatusCancelled},
+		"other":   {OrderID: "other", MarketID: "market-orphan", TokenID: "yes", Side: types.OrderSideBuy, Price: 0.20, Size: 5, Status: types.OrderStatusOpen},
+	}}
+	reconciler := NewIntentReconciler(intentRepo, positionRepo)
+	reconciler.SetPlatform("polymarket", lookup)
+
+	result, err := reconciler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Resolved != 3 || result.Unresolved != 1 || result.Cancelled != 2 || result.Flagged != 1 {
+		t.Errorf("Unexpected result: %s", result)
+	}
+	if len(lookup.cancelled) != 2 || lookup.cancelled[0] != "order-1" || lookup.cancelled[1] != "order-2" {
+		t.Errorf("Expected the resting orphans cancelled, got %v", lookup.cancelled)
+	}
+
+	partial, _ := positionRepo.GetByID(partialID)
+	if partial.Status != persistence.PositionStatusError {
+		t.Errorf("Expected the partially filled position flagged, got %s", partial.Status)
+	}
+	untouched, _ := positionRepo.GetByID(untouchedID)
+	if untouched.Status != persistence.PositionStatusOpen {
+		t.Errorf("Expected the unfilled position left open, got %s", untouched.Status)
+	}
+
+	unsettled, _ := intentRepo.GetUnsettled()
+	if len(unsettled) != 1 || unsettled[0].Platform != "kalshi" {
+		t.Errorf("Expected only the unchecked intent left, got %d", len(unsettled))
+	}
+}
@@ -0,0 +1,144 @@
+package position
+
+import (
+	"fmt"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// kellyParameter is the parameters table row Kelly fraction changes are
+// recorded against.
+const kellyParameter = "kelly_fraction"
+
+// SetKellyDrawdown scales the sizer's Kelly fraction down while the bankroll
+// is in drawdown, by the scale of the deepest step reached, and restores it
+// as equity recovers (see AdjustKellyFraction). The sizer's current fraction
+// is the one scaled. Drawdowns must be between 0 and 1 and scales between 0
+// and 1. No steps keeps the fraction fixed.
+func (m *Manager) SetKellyDrawdown(steps []sizing.DrawdownStep) error {
+	for _, step := range steps {
+		if step.Drawdown < 0 || step.Drawdown >= 1 {
+			return fmt.Errorf("kelly drawdown must be between 0 and 1, got %v", step.Drawdown)
+		}
+		if step.Scale < 0 || step.Scale > 1 {
+			return fmt.Errorf("kelly scale at %v drawdown must be between 0 and 1, got %v", step.Drawdown, step.Scale)
+		}
+	}
+	m.kellySteps = steps
+	m.kellyBase = m.sizer.KellyFraction()
+	return nil
+}
+
+// SetSizing changes the Kelly fraction later entries are sized with, before
+// any drawdown scaling, and the sizer's liquidity cap and correlation, such
+// as when the active strategy is reloaded.
+func (m *Manager) SetSizing(kellyFraction, maxLiquidityPct, correlation float64) {
+	m.kellyBase = kellyFraction
+	m.sizer.SetKellyFraction(kellyFraction)
+	m.sizer.SetMaxLiquidityPct(maxLiquidityPct)
+	m.sizer.SetCorrelation(correlation)
+}
+
+// SetParametersRepo sets where Kelly fraction changes are recorded, in
+// parameter_history. Without it changes are only logged.
+func (m *Manager) SetParametersRepo(repo *persistence.ParametersRepository) {
+	m.parametersRepo = repo
+}
+
+// Drawdown returns how far realized equity, the initial bankrolls plus the
+// PnL of closed positions in the order they closed, is below its peak, as a
+// fraction of the peak.
+func (m *Manager) Drawdown() (float64, error) {
+	bankrolls, err := m.bankrollRepo.GetAll()
+	if err != nil {
+		return 0, err
+	}
+	var equity types.Money
+	for _, b := range bankrolls {
+		equity += types.Dollars(b.InitialAmount)
+	}
+
+	closed, err := m.positionRepo.GetClosed()
+	if err != nil {
+		return 0, err
+	}
+	peak := equity
+	// Closed positions come most recent first
+	for i := len(closed) - 1; i >= 0; i-- {
+		if closed[i].RealizedPnL == nil {
+			continue
+		}
+		equity += types.Dollars(*closed[i].RealizedPnL)
+		if equity > peak {
+			peak = equity
+		}
+	}
+
+	if peak <= 0 || equity >= peak {
+		return 0, nil
+	}
+	return (peak - equity).Float64() / peak.Float64(), nil
+}
+
+// RealizedEquity returns the initial bankrolls plus the PnL of closed
+// positions, in dollars.
+func (m *Manager) RealizedEquity() (float64, error) {
+	bankrolls, err := m.bankrollRepo.GetAll()
+	if err != nil {
+		return 0, err
+	}
+	var equity types.Money
+	for _, b := range bankrolls {
+		equity += types.Dollars(b.InitialAmount)
+	}
+
+	closed, err := m.positionRepo.GetClosed()
+	if err != nil {
+		return 0, err
+	}
+	for _, pos := range closed {
+		if pos.RealizedPnL != nil {
+			equity += types.Dollars(*pos.RealizedPnL)
+		}
+	}
+	return equity.Float64(), nil
+}
+
+// AdjustKellyFraction sets the sizer's Kelly fraction for the current
+// drawdown and records it in parameter_history when it changes. Returns the
+// fraction in use.
+func (m *Manager) AdjustKellyFraction() (float64, error) {
+	current := m.sizer.KellyFraction()
+	if len(m.kellySteps) == 0 {
+		return current, nil
+	}
+
+	drawdown, err := m.Drawdown()
+	if err != nil {
+		return current, fmt.Errorf("compute drawdown: %w", err)
+	}
+	fraction := m.kellyBase * sizing.DrawdownScale(m.kellySteps, drawdown)
+	if fraction == current {
+		return current, nil
+	}
+
+	m.sizer.SetKellyFraction(fraction)
+	log.Info().
+		Float64("drawdown", drawdown).
+		Float64("old_kelly_fraction", current).
+		Float64("kelly_fraction", fraction).
+		Msg("Kelly fraction adjusted for drawdown")
+
+	if m.parametersRepo != nil {
+		reason := fmt.Sprintf("drawdown %.1f%%", drawdown*100)
+		// Only recorded: the table keeps the unscaled fraction
+		if err := m.parametersRepo.RecordChange(kellyParameter, current, fraction, reason); err != nil {
+			return fraction, fmt.Errorf("record kelly fraction: %w", err)
+		}
+	}
+	return fraction, nil
+}
@@ -0,0 +1,108 @@
+package position
+
+import (
+	"testing"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/pkg/types"
+)
+
+func TestAdjustKellyFraction(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positionRepo := persistence.NewPositionRepository(db)
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	parametersRepo := persistence.NewParametersRepository(db)
+	sizer := sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.5, MinPosition: 1, MaxBankrollPct: 0.2})
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityAnalyzer{}, sizer)
+	manager.SetParametersRepo(parametersRepo)
+	if err := manager.SetKellyDrawdown([]sizing.DrawdownStep{{Drawdown: 0.05, Scale: 0.5}, {Drawdown: 0.10, Scale: 0.25}}); err != nil {
+		t.Fatalf("SetKellyDrawdown failed: %v", err)
+	}
+
+	// Both platforms start at $50: $100 of equity
+	closeWith := func(pnl float64) {
+		t.Helper()
+		id, err := positionRepo.Create(&persistence.Position{
+			Platform: "kalshi", MarketID: "KXBTC", EntryPrice: 0.9, Quantity: 10, Side: "YES",
+			Status: persistence.PositionStatusOpen,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create position: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE positions SET status = 'closed', realized_pnl_micros = ?, exit_time = datetime('now', ? || ' seconds') WHERE id = ?`, types.Dollars(pnl), id, id); err != nil {
+			t.Fatalf("Failed to close position: %v", err)
+		}
+	}
+
+	steps := []struct {
+		pnl      float64
+		drawdown float64
+		fraction float64
+	}{
+		{pnl: 20, drawdown: 0, fraction: 0.5},      // Peak at $120
+		{pnl: -9, drawdown: 0.075, fraction: 0.25}, // $111
+		{pnl: -9, drawdown: 0.15, fraction: 0.125}, // $102: eighth-Kelly
+		{pnl: 12, drawdown: 0.05, fraction: 0.25},  // $114: recovering
+		{pnl: 10, drawdown: 0, fraction: 0.5},      // $124: new peak
+	}
+	for i, step := range steps {
+		closeWith(step.pnl)
+
+		drawdown, err := manager.Drawdown()
+		if err != nil {
+			t.Fatalf("Drawdown failed: %v", err)
+		}
+		if diff := drawdown - step.drawdown; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("step %d: expected drawdown %v, got %v", i, step.drawdown, drawdown)
+		}
+
+		fraction, err := manager.AdjustKellyFraction()
+		if err != nil {
+			t.Fatalf("AdjustKellyFraction failed: %v", err)
+		}
+		if fraction != step.fraction || sizer.KellyFraction() != step.fraction {
+			t.Errorf("step %d: expected kelly fraction %v, got %v (sizer %v)", i, step.fraction, fraction, sizer.KellyFraction())
+		}
+	}
+
+	// Each change is recorded, the first step left the fraction unchanged
+	history, err := parametersRepo.GetHistory("kelly_fraction", 10)
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+	if len(history) != 4 {
+		t.Fatalf("Expected 4 kelly fraction changes, got %+v", history)
+	}
+	var sawEighth bool
+	for _, change := range history {
+		if change.NewValue == 0.125 && change.Reason == "drawdown 15.0%" {
+			sawEighth = true
+		}
+	}
+	if !sawEighth {
+		t.Errorf("Expected the eighth-Kelly change recorded with its drawdown, got %+v", history)
+	}
+}
+
+func TestSetKellyDrawdownValidates(t *testing.T) {
+	manager := NewManager(nil, nil, nil, sizing.NewSizer(sizing.SizerConfig{KellyFraction: 0.25}))
+
+	for _, steps := range [][]sizing.DrawdownStep{
+		{{Drawdown: -0.1, Scale: 0.5}},
+		{{Drawdown: 1, Scale: 0.5}},
+		{{Drawdown: 0.1, Scale: 1.5}},
+	} {
+		if err := manager.SetKellyDrawdown(steps); err == nil {
+			t.Errorf("Expected %+v rejected", steps)
+		}
+	}
+
+	// Without steps the fraction is left alone
+	fraction, err := manager.AdjustKellyFraction()
+	if err != nil || fraction != 0.25 {
+		t.Errorf("Expected no adjustment, got %v (%v)", fraction, err)
+	}
+}
@@ -1,13 +1,24 @@
 package position
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"prediction-bot/internal/orders"
 	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/risk"
 	"prediction-bot/internal/scanner"
 	"prediction-bot/internal/sizing"
 	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
 )
 
 // Skip reasons for position entry.
@@ -17,20 +28,88 @@ const (
 	SkipReasonVolatilityRisky   = "volatility_risky"
 	SkipReasonSizingNoEdge      = "sizing_no_edge"
 	SkipReasonSizingTooSmall    = "sizing_below_minimum"
+	SkipReasonSizingCorrelated  = "sizing_correlated"
 	SkipReasonInsufficientFunds = "insufficient_funds"
+	SkipReasonPortfolioLimit    = "portfolio_limit"
+	SkipReasonVaRLimit          = "var_limit"
+	SkipReasonEntryUnfilled     = "entry_unfilled"
 )
 
 // Exit reasons for position exit.
 const (
 	ExitReasonStopLoss   = "stop_loss"
+	ExitReasonTakeProfit = "take_profit"
 	ExitReasonVolatility = "volatility_exit"
 	ExitReasonResolved   = "market_resolved"
 	ExitReasonManual     = "manual_exit"
+	ExitReasonFlatten    = "end_of_day_flatten"
+	ExitReasonTimeDecay  = "time_decay"
+	// ExitReasonNotHeld closes a position the platform no longer holds,
+	// sold or settled while the bot didn't record it. Its exit price is
+	// unknown.
+	ExitReasonNotHeld = "not_held"
+)
+
+// ErrOrdersNotCancelled is returned when resting orders remain open after cancellation.
+var ErrOrdersNotCancelled = errors.New("resting orders still open after cancel")
+
+// ErrExitNotFilled is returned when a live exit order ends without any fill.
+var ErrExitNotFilled = errors.New("exit order not filled")
+
+// Defaults for waiting on live order confirmation.
+const (
+	defaultExitTimeout = 30 * time.Second
+	orderPollInterval  = time.Second
 )
 
 // VolatilityAnalyzer defines the interface for volatility analysis.
 type VolatilityAnalyzer interface {
-	AnalyzeAsset(asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error)
+	AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error)
+}
+
+// OrderCanceller defines the interface for inspecting and cancelling resting orders.
+type OrderCanceller interface {
+	GetOpenOrders(marketID string) ([]types.OrderResult, error)
+	CancelOrder(orderID string) error
+}
+
+// PlatformOrderer defines the interface for placing exit orders and
+// confirming their fills.
+type PlatformOrderer interface {
+	PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error)
+	GetOrderStatus(orderID string) (types.OrderResult, error)
+	CancelOrder(orderID string) error
+}
+
+// LotSizer defines the interface for platforms that only trade whole lots,
+// such as Kalshi's whole contracts.
+type LotSizer interface {
+	LotSize() float64
+}
+
+// PriceQuoter defines the interface for fetching the current price of a
+// position's outcome.
+type PriceQuoter interface {
+	GetCurrentPrice(marketID string) (float64, error)
+}
+
+// TradeEvents builds the audit events recorded with entries and exits, in
+// the same transaction as the positions and bankroll (see audit.Recorder). A
+// nil event records nothing.
+type TradeEvents interface {
+	EntryEvent(platform, marketID string, result EntryResult, dryRun bool) *persistence.Event
+	ExitEvent(platform, marketID string, exit ExitResult) *persistence.Event
+}
+
+// DryRunSimulation configures how dry-run fills approximate live execution.
+type DryRunSimulation struct {
+	// Latency delays simulated exits. If the platform has a PriceQuoter, the
+	// exit fills at the price quoted after the delay, so fast moves through a
+	// stop are filled at a worse price as they would be live.
+	Latency time.Duration
+	// FeeRates is the fee per platform as a fraction of notional, charged on
+	// simulated entries and exits.
+	FeeRates map[string]float64
 }
 
 // EntryResult contains the result of processing a position entry.
@@ -53,6 +132,16 @@ type EntryResult struct {
 	Volatility float64
 	// WinProbability is the estimated win probability.
 	WinProbability float64
+	// Fees is the entry fee: simulated in dry-run, charged by the platform
+	// for limit entries.
+	Fees float64
+	// Strategy is how the entry was executed.
+	Strategy string
+	// Side is the side bought: the market's bet side, or its opposite for
+	// a fade.
+	Side string
+	// TradeStrategy is TradeStrategyFade for fades, empty otherwise.
+	TradeStrategy string
 }
 
 // ExitResult contains the result of executing a position exit.
@@ -69,6 +158,16 @@ type ExitResult struct {
 	EntryPrice float64
 	// Quantity is the number of contracts that were closed.
 	Quantity float64
+	// CancelledOrders is the number of resting orders cancelled before the exit.
+	CancelledOrders int
+	// Fees is the total fees paid on the position so far. They are deducted
+	// from RealizedPnL once the position is fully closed.
+	Fees float64
+	// OrderID is the platform ID of the sell order (empty if none was placed).
+	OrderID string
+	// RemainingQuantity is the quantity left open after a partially filled
+	// sell. The position stays open for the next cycle to retry.
+	RemainingQuantity float64
 }
 
 // Manager handles position entry and management logic.
@@ -77,7 +176,33 @@ type Manager struct {
 	bankrollRepo *persistence.BankrollRepository
 	volatility   VolatilityAnalyzer
 	sizer        *sizing.Sizer
+	entryMu      sync.Mutex // Serializes Enter
+	kellyBase    float64
+	kellySteps   []sizing.DrawdownStep
+	risk         *risk.Checker
 	allowRisky   bool
+	fadeMinEdge  float64
+	bestStrike   bool
+	allocation   map[string]float64 // Bankroll share by trade strategy; nil if unallocated
+	cancellers   map[string]OrderCanceller
+	orderers     map[string]PlatformOrderer
+	exitTimeout  time.Duration
+	entry        EntryExecution
+	simulation   DryRunSimulation
+	paper        map[string]PlatformOrderer
+	quoters      map[string]PriceQuoter
+	lots         map[string]float64
+	intents      *persistence.OrderIntentRepository
+	now          func() time.Time
+	sleep        func(time.Duration)
+
+	parametersRepo *persistence.ParametersRepository
+	events         TradeEvents
+
+	// strategyName and strategyVersion identify the strategy file entries
+	// are made under (see SetStrategy).
+	strategyName    string
+	strategyVersion int
 }
 
 // NewManager creates a new position manager with the given dependencies.
@@ -93,6 +218,15 @@ func NewManager(
 		volatility:   volatilityService,
 		sizer:        sizer,
 		allowRisky:   false,
+		cancellers:   make(map[string]OrderCanceller),
+		orderers:     make(map[string]PlatformOrderer),
+		exitTimeout:  defaultExitTimeout,
+		entry:        EntryExecution{Strategy: EntryStrategyMarket, Timeout: defaultEntryTimeout},
+		paper:        make(map[string]PlatformOrderer),
+		quoters:      make(map[string]PriceQuoter),
+		lots:         make(map[string]float64),
+		now:          time.Now,
+		sleep:        time.Sleep,
 	}
 }
 
@@ -101,101 +235,278 @@ func (m *Manager) SetAllowRisky(allow bool) {
 	m.allowRisky = allow
 }
 
+// SetRiskChecker sets the portfolio limits checked before each entry. With
+// no checker, entries are limited by sizing alone.
+func (m *Manager) SetRiskChecker(checker *risk.Checker) {
+	m.risk = checker
+}
+
+// SetClock overrides the time source used for time-to-close calculations.
+// Used by the backtester to replay historical snapshots.
+func (m *Manager) SetClock(now func() time.Time) {
+	m.now = now
+}
+
+// SetOrderCanceller registers the order canceller used for exits on a platform.
+func (m *Manager) SetOrderCanceller(platform string, canceller OrderCanceller) {
+	m.cancellers[platform] = canceller
+}
+
+// SetPlatformOrderer registers the orderer used to place live sell orders
+// for exits on a platform.
+func (m *Manager) SetPlatformOrderer(platform string, orderer PlatformOrderer) {
+	m.orderers[platform] = orderer
+}
+
+// SetExitTimeout sets how long to wait for a live exit order to fill before
+// cancelling what remains of it.
+func (m *Manager) SetExitTimeout(timeout time.Duration) {
+	m.exitTimeout = timeout
+}
+
+// SetLotSize sets the smallest quantity a platform trades, such as the
+// whole contracts of Kalshi: positions on it are sized, and their orders
+// placed, in multiples of lot. Without one any quantity is traded.
+func (m *Manager) SetLotSize(platform string, lot float64) {
+	m.lots[platform] = lot
+}
+
+// roundLots rounds quantity down to whole lots of platform.
+func (m *Manager) roundLots(platform string, quantity float64) float64 {
+	lot := m.lots[platform]
+	if lot <= 0 {
+		return quantity
+	}
+	// Tolerate float error in quantities that are whole lots already
+	return math.Floor(quantity/lot+1e-9) * lot
+}
+
+// SetDryRunSimulation configures the latency and fees applied to dry-run
+// entries and exits.
+func (m *Manager) SetDryRunSimulation(sim DryRunSimulation) {
+	m.simulation = sim
+}
+
+// SetPaperOrderer registers the simulated exchange that dry-run entries and
+// exits on a platform are executed against, so they fill as the order book
+// allows instead of at the quoted price. The fees it charges replace the
+// simulated fee rate.
+func (m *Manager) SetPaperOrderer(platform string, orderer PlatformOrderer) {
+	m.paper[platform] = orderer
+}
+
+// SetPriceQuoter registers the quoter used to re-price simulated exits after
+// latency on a platform.
+func (m *Manager) SetPriceQuoter(platform string, quoter PriceQuoter) {
+	m.quoters[platform] = quoter
+}
+
+// SetStrategy sets the strategy file, by name and version, that later
+// entries are recorded as made under. Empty records none.
+func (m *Manager) SetStrategy(name string, version int) {
+	m.strategyName = name
+	m.strategyVersion = version
+}
+
+// SetTradeEvents sets how the events recorded with each entry and exit are
+// built. Without it, none are recorded.
+func (m *Manager) SetTradeEvents(events TradeEvents) {
+	m.events = events
+}
+
+// EntryAnalysis is an eligible market analyzed for entry by AnalyzeEntry,
+// to be sized and entered by Enter.
+type EntryAnalysis struct {
+	// Market is the market analyzed.
+	Market scanner.EligibleMarket
+	// skip is the result of an entry already ruled out, nil otherwise.
+	skip       *EntryResult
+	side       string
+	strategy   string
+	entryPrice float64
+	volResult  volatility.ServiceResult
+	fade       *fadeTrade
+}
+
 // ProcessEntry processes an eligible market for potential position entry.
 // If dryRun is true, the position is recorded but no actual order is placed.
+// It is AnalyzeEntry followed by Enter.
 //
 // Flow:
 // 1. Check for duplicate position
 // 2. Analyze volatility
 // 3. Calculate position size
-// 4. Persist position to database
-// 5. Deduct from bankroll
-func (m *Manager) ProcessEntry(market scanner.EligibleMarket, dryRun bool) (EntryResult, error) {
-	result := EntryResult{}
+// 4. Check portfolio limits
+// 5. Persist position to database as pending_entry, if an order is placed
+// 6. Execute the entry orders (live and paper-traded entries)
+// 7. Mark position open and deduct from bankroll, in one transaction
+func (m *Manager) ProcessEntry(ctx context.Context, market scanner.EligibleMarket, dryRun bool) (EntryResult, error) {
+	analysis, err := m.AnalyzeEntry(ctx, market)
+	if err != nil {
+		return EntryResult{}, err
+	}
+	return m.Enter(ctx, analysis, dryRun)
+}
+
+// AnalyzeEntry runs the checks of an entry that don't depend on the
+// bankroll or open positions (steps 1 and 2 of ProcessEntry), and the
+// volatility analysis, which is the slow part of an entry. It is safe to
+// analyze several markets concurrently.
+func (m *Manager) AnalyzeEntry(ctx context.Context, market scanner.EligibleMarket) (EntryAnalysis, error) {
+	analysis := EntryAnalysis{Market: market}
+	skip := func(reason string, volResult *volatility.ServiceResult) (EntryAnalysis, error) {
+		result := EntryResult{Skipped: true, SkipReason: reason}
+		if volResult != nil {
+			result.SafetyMargin = volResult.SafetyMargin
+			result.Volatility = volResult.Volatility
+		}
+		analysis.skip = &result
+		return analysis, nil
+	}
 
 	// Step 1: Check for duplicate position
 	existing, err := m.positionRepo.GetByMarket(market.Market.Platform, market.Market.ID)
 	if err != nil {
-		return result, fmt.Errorf("check duplicate position: %w", err)
+		return analysis, fmt.Errorf("check duplicate position: %w", err)
 	}
 	if existing != nil {
-		result.Skipped = true
-		result.SkipReason = SkipReasonDuplicate
-		return result, nil
+		return skip(SkipReasonDuplicate, nil)
 	}
 
-	// Step 2: Get bankroll for this platform
+	// Step 2: Skip the analysis if the platform has nothing to trade with
 	bankroll, err := m.bankrollRepo.Get(market.Market.Platform)
 	if err != nil {
-		return result, fmt.Errorf("get bankroll: %w", err)
+		return analysis, fmt.Errorf("get bankroll: %w", err)
 	}
 	if bankroll == nil || bankroll.CurrentAmount <= 0 {
-		result.Skipped = true
-		result.SkipReason = SkipReasonInsufficientFunds
-		return result, nil
+		return skip(SkipReasonInsufficientFunds, nil)
 	}
 
 	// Step 3: Analyze volatility
-	direction := volatility.DirectionAbove
-	if market.Parsed.Direction == "below" {
-		direction = volatility.DirectionBelow
-	}
-
-	timeToClose := time.Until(market.Market.EndDate)
+	timeToClose := market.ResolutionTime().Sub(m.now())
 	if timeToClose < 0 {
 		timeToClose = 0
 	}
 
-	volResult, err := m.volatility.AnalyzeAsset(
+	volResult, err := analyzeStrike(
+		ctx,
+		m.volatility,
 		market.Parsed.Asset,
 		market.Parsed.Strike,
-		direction,
+		market.Parsed.StrikeUpper,
+		market.Parsed.Direction,
+		market.BetSide,
 		timeToClose,
 	)
 	if err != nil {
-		return result, fmt.Errorf("analyze volatility: %w", err)
+		return analysis, fmt.Errorf("analyze volatility: %w", err)
+	}
+
+	entryPrice := market.Probability
+	if market.BetSide == "NO" {
+		entryPrice = 1.0 - market.Probability
+	}
+
+	// A favored side the model prices well below the market is faded
+	side, strategy := market.BetSide, ""
+	fade, err := m.fade(ctx, market, entryPrice, volResult, timeToClose)
+	if err != nil {
+		return analysis, fmt.Errorf("analyze fade: %w", err)
+	}
+	if fade != nil {
+		side, strategy = fade.side, TradeStrategyFade
+		entryPrice, volResult = fade.entryPrice, fade.analysis
 	}
 
-	// Check volatility recommendation
-	if volResult.Recommendation == volatility.RecommendationReject {
+	// Check volatility recommendation. Fades are entered on the favored
+	// side's mispricing, not on the safety of their own side.
+	if fade == nil && volResult.Recommendation == volatility.RecommendationReject {
+		return skip(SkipReasonVolatilityReject, &volResult)
+	}
+
+	if fade == nil && volResult.Recommendation == volatility.RecommendationRisky && !m.allowRisky {
+		return skip(SkipReasonVolatilityRisky, &volResult)
+	}
+
+	analysis.side, analysis.strategy = side, strategy
+	analysis.entryPrice, analysis.volResult = entryPrice, volResult
+	analysis.fade = fade
+	return analysis, nil
+}
+
+// Enter sizes an analyzed market against the bankroll and open positions
+// and enters it (steps 3 to 8 of ProcessEntry). Portfolio limits span
+// platforms, so entries are serialized: each is sized once the previous
+// one is open.
+func (m *Manager) Enter(ctx context.Context, analysis EntryAnalysis, dryRun bool) (EntryResult, error) {
+	if analysis.skip != nil {
+		return *analysis.skip, nil
+	}
+
+	m.entryMu.Lock()
+	defer m.entryMu.Unlock()
+
+	result := EntryResult{}
+	market := analysis.Market
+	side, strategy := analysis.side, analysis.strategy
+	entryPrice, volResult, fade := analysis.entryPrice, analysis.volResult, analysis.fade
+
+	// The bankroll may have changed since the analysis
+	bankroll, err := m.bankrollRepo.Get(market.Market.Platform)
+	if err != nil {
+		return result, fmt.Errorf("get bankroll: %w", err)
+	}
+	if bankroll == nil || bankroll.CurrentAmount <= 0 {
 		result.Skipped = true
-		result.SkipReason = SkipReasonVolatilityReject
-		result.SafetyMargin = volResult.SafetyMargin
-		result.Volatility = volResult.Volatility
+		result.SkipReason = SkipReasonInsufficientFunds
 		return result, nil
 	}
 
-	if volResult.Recommendation == volatility.RecommendationRisky && !m.allowRisky {
+	// Step 4: Calculate position size against the strategy's share of the
+	// bankroll
+	available, err := m.strategyBankroll(bankroll, strategy)
+	if err != nil {
+		return result, fmt.Errorf("get strategy bankroll: %w", err)
+	}
+	if available <= 0 {
 		result.Skipped = true
-		result.SkipReason = SkipReasonVolatilityRisky
+		result.SkipReason = SkipReasonAllocationExhausted
 		result.SafetyMargin = volResult.SafetyMargin
 		result.Volatility = volResult.Volatility
 		return result, nil
 	}
 
-	// Step 4: Calculate position size
-	entryPrice := market.Probability
-	if market.BetSide == "NO" {
-		entryPrice = 1.0 - market.Probability
+	// Estimate win probability based on safety margin. A fade's is the
+	// model probability of its side.
+	winProb := sizing.EstimateWinProbability(entryPrice, volResult.SafetyMargin)
+	if fade != nil {
+		winProb = fade.winProb
 	}
 
-	// Estimate win probability based on safety margin
-	winProb := sizing.EstimateWinProbability(entryPrice, volResult.SafetyMargin)
+	exposure, err := m.correlatedExposure(market.Parsed.Asset, market.Parsed.Direction, side)
+	if err != nil {
+		return result, err
+	}
 
 	sizingInput := sizing.SizingInput{
-		EntryPrice:   entryPrice,
-		WinProb:      winProb,
-		Bankroll:     bankroll.CurrentAmount,
-		SafetyMargin: volResult.SafetyMargin,
+		EntryPrice:         entryPrice,
+		WinProb:            winProb,
+		Bankroll:           available,
+		SafetyMargin:       volResult.SafetyMargin,
+		Liquidity:          market.Market.Liquidity,
+		CorrelatedExposure: exposure,
 	}
 
 	sizingOutput := m.sizer.Calculate(sizingInput)
 
 	if sizingOutput.PositionSize <= 0 {
 		result.Skipped = true
-		if sizingOutput.Reason == "no_edge" {
+		switch sizingOutput.Reason {
+		case "no_edge":
 			result.SkipReason = SkipReasonSizingNoEdge
-		} else {
+		case "correlated":
+			result.SkipReason = SkipReasonSizingCorrelated
+		default:
 			result.SkipReason = SkipReasonSizingTooSmall
 		}
 		result.SafetyMargin = volResult.SafetyMargin
@@ -203,58 +514,248 @@ func (m *Manager) ProcessEntry(market scanner.EligibleMarket, dryRun bool) (Entr
 		return result, nil
 	}
 
-	// Calculate quantity (number of contracts)
-	quantity := sizingOutput.PositionSize / entryPrice
+	// Check portfolio limits, downsizing the entry to fit the VaR limit
+	limit, size, err := m.checkPortfolio(market, side, entryPrice, sizingOutput.PositionSize)
+	if err != nil {
+		return result, err
+	}
+	if limit != "" {
+		log.Debug().
+			Str("market", market.Market.ID).
+			Str("asset", market.Parsed.Asset).
+			Str("limit", limit).
+			Float64("size", sizingOutput.PositionSize).
+			Msg("Entry rejected by portfolio limit")
+		result.Skipped = true
+		result.SkipReason = SkipReasonPortfolioLimit
+		if limit == risk.LimitVaR {
+			result.SkipReason = SkipReasonVaRLimit
+		}
+		result.SafetyMargin = volResult.SafetyMargin
+		result.Volatility = volResult.Volatility
+		return result, nil
+	}
+	if size < sizingOutput.PositionSize {
+		log.Info().
+			Str("market", market.Market.ID).
+			Str("asset", market.Parsed.Asset).
+			Float64("size", sizingOutput.PositionSize).
+			Float64("downsized", size).
+			Msg("Entry downsized to fit VaR limit")
+	}
+
+	// Calculate quantity (number of contracts), in whole lots on platforms
+	// that only trade those
+	quantity := size / entryPrice
+	if lots := m.roundLots(market.Market.Platform, quantity); lots != quantity {
+		if lots <= 0 {
+			result.Skipped = true
+			result.SkipReason = SkipReasonSizingTooSmall
+			result.SafetyMargin = volResult.SafetyMargin
+			result.Volatility = volResult.Volatility
+			return result, nil
+		}
+		quantity, size = lots, types.Cost(entryPrice, lots).Float64()
+	}
+
+	var fees float64
+	if dryRun {
+		fees = m.simulatedFee(market.Market.Platform, size)
+	}
 
-	// Step 5: Persist position to database
 	position := &persistence.Position{
 		Platform:            market.Market.Platform,
 		MarketID:            market.Market.ID,
 		MarketTitle:         market.Market.Title,
 		Asset:               market.Parsed.Asset,
 		Strike:              market.Parsed.Strike,
+		StrikeUpper:         market.Parsed.StrikeUpper,
 		Direction:           market.Parsed.Direction,
+		Outcome:             market.Market.Outcome,
 		EntryPrice:          entryPrice,
 		Quantity:            quantity,
-		Side:                market.BetSide,
-		Status:              "open",
+		Side:                side,
+		TokenID:             outcomeTokenID(market.Market, side),
+		Status:              persistence.PositionStatusPendingEntry,
+		Fees:                fees,
 		SafetyMarginAtEntry: volResult.SafetyMargin,
 		VolatilityAtEntry:   volResult.Volatility,
+		EntryStrategy:       EntryStrategyMarket,
+		TradeStrategy:       strategy,
+		Strategy:            m.strategyName,
+		StrategyVersion:     m.strategyVersion,
+		Experiment:          market.Experiment,
+		ExperimentArm:       market.Arm,
 	}
-
-	positionID, err := m.positionRepo.Create(position)
-	if err != nil {
-		return result, fmt.Errorf("create position: %w", err)
+	if closeTime := market.ResolutionTime(); !closeTime.IsZero() {
+		position.MarketCloseTime = &closeTime
 	}
 
-	// Step 6: Deduct from bankroll
-	err = m.bankrollRepo.AddToBalance(market.Market.Platform, -sizingOutput.PositionSize)
-	if err != nil {
-		return result, fmt.Errorf("deduct from bankroll: %w", err)
+	// Step 5: Persist the position before placing an order. It stays
+	// pending until the fill is recorded, so a crash while the order works
+	// is found by Reconciler at the next start.
+	cost := types.Dollars(size)
+	if m.placesEntryOrder(market.Market.Platform, dryRun) {
+		if _, err := m.positionRepo.Create(position); err != nil {
+			return result, fmt.Errorf("create position: %w", err)
+		}
+
+		// Step 6: Execute the entry orders and record what actually filled
+		fill, placed, err := m.executeEntry(ctx, position, market.Market.Spread, dryRun)
+		if err != nil {
+			m.markError(position)
+			return result, fmt.Errorf("execute entry: %w", err)
+		}
+		if placed {
+			if fill.Quantity <= 0 {
+				position.EntryStrategy = fill.Strategy
+				if err := m.positionRepo.Update(position); err != nil {
+					m.markError(position)
+					return result, fmt.Errorf("record unfilled entry: %w", err)
+				}
+				if err := m.positionRepo.Close(position.ID, entryPrice, orders.ExitReasonUnfilled, 0); err != nil {
+					return result, fmt.Errorf("abandon unfilled entry: %w", err)
+				}
+				result.Skipped = true
+				result.SkipReason = SkipReasonEntryUnfilled
+				result.SafetyMargin = volResult.SafetyMargin
+				result.Volatility = volResult.Volatility
+				return result, nil
+			}
+
+			position.Quantity = fill.Quantity
+			position.EntryPrice = fill.Price
+			position.Fees = fill.Fees
+			position.EntryStrategy = fill.Strategy
+			quantity, entryPrice, fees = fill.Quantity, fill.Price, fill.Fees
+			cost = types.Cost(entryPrice, quantity)
+		}
 	}
 
 	// Populate result
-	result.PositionID = positionID
-	result.PositionSize = sizingOutput.PositionSize
+	result.PositionSize = cost.Float64()
 	result.Quantity = quantity
 	result.EntryPrice = entryPrice
 	result.SafetyMargin = volResult.SafetyMargin
 	result.Volatility = volResult.Volatility
 	result.WinProbability = winProb
+	result.Fees = fees
+	result.Strategy = position.EntryStrategy
+	result.Side = side
+	result.TradeStrategy = strategy
+
+	// Step 7: Mark position open, deduct cost and fees from bankroll and
+	// record the entry together
+	event := m.entryEvent(market.Market.Platform, market.Market.ID, result, dryRun)
+	if err := m.positionRepo.OpenEntry(position, cost+types.Dollars(fees), event); err != nil {
+		if position.ID != 0 {
+			m.markError(position)
+		}
+		return EntryResult{}, fmt.Errorf("open position: %w", err)
+	}
+	result.PositionID = position.ID
 
 	return result, nil
 }
 
+// TrackPeakPrice records price as the position's peak if it is the highest
+// seen since entry, for trailing stop losses.
+func (m *Manager) TrackPeakPrice(position *persistence.Position, price float64) error {
+	if price <= position.PeakPrice {
+		return nil
+	}
+	if err := m.positionRepo.UpdatePeakPrice(position.ID, price); err != nil {
+		return err
+	}
+	position.PeakPrice = price
+	return nil
+}
+
+// correlatedExposure returns the cost of the open positions on asset that
+// win the same way as an entry on side of a market in direction.
+func (m *Manager) correlatedExposure(asset, direction, side string) (float64, error) {
+	open, err := m.positionRepo.GetOpen()
+	if err != nil {
+		return 0, fmt.Errorf("get open positions: %w", err)
+	}
+
+	bullish := risk.Bullish(direction, side)
+	var exposure types.Money
+	for _, p := range open {
+		if strings.EqualFold(p.Asset, asset) && risk.Bullish(p.Direction, p.Side) == bullish {
+			exposure += types.Cost(p.EntryPrice, p.Quantity)
+		}
+	}
+	return exposure.Float64(), nil
+}
+
+// checkPortfolio returns the portfolio limit an entry of size dollars on
+// side would breach, or an empty string and the size it may be opened at if
+// it is allowed or no limits are set. An entry breaching only the VaR limit
+// is downsized to fit it, if that leaves at least the minimum position.
+func (m *Manager) checkPortfolio(market scanner.EligibleMarket, side string, entryPrice, size float64) (string, float64, error) {
+	if m.risk == nil {
+		return "", size, nil
+	}
+
+	open, err := m.positionRepo.GetOpen()
+	if err != nil {
+		return "", 0, fmt.Errorf("get open positions: %w", err)
+	}
+	bankrolls, err := m.bankrollRepo.GetAll()
+	if err != nil {
+		return "", 0, fmt.Errorf("get bankrolls: %w", err)
+	}
+	var cash types.Money
+	for _, b := range bankrolls {
+		cash += types.Dollars(b.CurrentAmount)
+	}
+
+	entry := risk.Entry{
+		Asset:      market.Parsed.Asset,
+		Direction:  market.Parsed.Direction,
+		Side:       side,
+		Size:       size,
+		EntryPrice: entryPrice,
+		CloseTime:  market.ResolutionTime(),
+	}
+	limit := m.risk.Check(open, cash.Float64(), entry)
+	if limit != risk.LimitVaR {
+		return limit, size, nil
+	}
+
+	entry.Size = m.sizer.Downsize(m.risk.FitVaR(open, cash.Float64(), entry))
+	if entry.Size <= 0 || m.risk.Check(open, cash.Float64(), entry) != "" {
+		return risk.LimitVaR, 0, nil
+	}
+	return "", entry.Size, nil
+}
+
 // ExecuteExit closes a position and updates the database and bankroll.
-// If dryRun is true, the exit is recorded but no actual sell order is placed.
+// If dryRun is true, no sell order is placed and the exit is recorded at
+// exitPrice, adjusted for the configured DryRunSimulation, unless the
+// platform is paper traded: then the sell is simulated against the order
+// book by its paper orderer. In live mode a sell order is placed through the platform's
+// PlatformOrderer and the position is closed at the price actually filled.
 //
 // Flow:
 // 1. Get position from database
-// 2. Verify position is still open
-// 3. Calculate realized PnL
-// 4. Update position status to closed
-// 5. Add exit proceeds to bankroll
-func (m *Manager) ExecuteExit(positionID int64, exitPrice float64, reason string, dryRun bool) (ExitResult, error) {
+// 2. Claim the position by moving it from open to exiting
+// 3. Cancel resting orders on the market (live mode only)
+// 4. Sell on the platform and wait for the fill (live and paper-traded only)
+// 5. Calculate realized PnL
+// 6. Update position status to closed
+// 7. Add exit proceeds to bankroll
+//
+// A sell that fills only partially reduces the position's quantity, credits
+// the proceeds and returns the position to open so the next cycle sells the
+// rest. A sell that doesn't fill at all returns ErrExitNotFilled.
+//
+// Claiming the position first means concurrent monitor cycles or processes
+// cannot both exit it: a caller that read the position before it was claimed
+// fails with persistence.ErrConflict, one that read it after fails with
+// persistence.ErrInvalidTransition.
+func (m *Manager) ExecuteExit(ctx context.Context, positionID int64, exitPrice float64, reason string, dryRun bool) (ExitResult, error) {
 	result := ExitResult{}
 
 	// Step 1: Get position from database
@@ -266,36 +767,424 @@ func (m *Manager) ExecuteExit(positionID int64, exitPrice float64, reason string
 		return result, fmt.Errorf("position not found: %d", positionID)
 	}
 
-	// Step 2: Verify position is still open
-	if position.Status != "open" {
-		return result, fmt.Errorf("position already closed: %d", positionID)
+	// Step 2: Claim the position for exit
+	if err := m.positionRepo.Transition(position, persistence.PositionStatusExiting); err != nil {
+		return result, fmt.Errorf("claim position for exit: %w", err)
 	}
 
-	// Step 3: Calculate realized PnL
-	// PnL = (exitPrice - entryPrice) * quantity
-	realizedPnL := (exitPrice - position.EntryPrice) * position.Quantity
+	quantity, sold := position.Quantity, position.Quantity
+
+	var exitFee float64
+	paper, paperTraded := m.paper[position.Platform]
+	if dryRun && !paperTraded {
+		exitPrice = m.simulateExitPrice(position, exitPrice)
+		exitFee = m.simulatedFee(position.Platform, exitPrice*quantity)
+	} else {
+		orderer := m.orderers[position.Platform]
+		if dryRun {
+			// Step 3: Paper trade the exit against the order book as it is
+			// after the simulated latency
+			if m.simulation.Latency > 0 {
+				m.sleep(m.simulation.Latency)
+			}
+			orderer = paper
+		} else {
+			// Step 3: Cancel resting orders before selling, so a partially
+			// filled entry cannot keep filling after the position is closed
+			cancelled, err := m.cancelOpenOrders(position)
+			if err != nil {
+				// Nothing was sold; release the position so the next cycle retries
+				m.release(position)
+				return result, fmt.Errorf("cancel open orders: %w", err)
+			}
+			result.CancelledOrders = cancelled
+			orderer = m.journal(orderer, position)
+		}
+
+		// Step 4: Sell on the platform and use the confirmed fill
+		fill, placed, err := m.sellPosition(ctx, orderer, position, exitPrice)
+		if err != nil {
+			m.release(position)
+			return result, fmt.Errorf("sell position: %w", err)
+		}
+		if placed {
+			result.OrderID = fill.OrderID
+			unsold := m.roundLots(position.Platform, quantity-fill.Filled)
+			if fill.Filled <= 0 && unsold > 0 {
+				m.release(position)
+				return result, fmt.Errorf("%w: order %s", ErrExitNotFilled, fill.OrderID)
+			}
+			if fill.Filled > 0 {
+				exitPrice = fill.FillPrice()
+				exitFee = fill.Fees
+			}
+			if unsold > 0 {
+				return m.recordPartialExit(position, fill.Filled, exitPrice, exitFee, reason, result)
+			}
+			// Less than a lot left can't be sold, and is written off
+			// with the rest of the position
+			sold = math.Min(fill.Filled, quantity)
+		}
+	}
 
-	// Step 4: Update position status to closed
-	err = m.positionRepo.Close(positionID, exitPrice, reason, realizedPnL)
+	// Steps 5-7: Calculate realized PnL, close the position and credit the
+	// bankroll
+	return m.closePosition(position, exitPrice, sold, exitFee, reason, result)
+}
+
+// Settle closes a position whose market has resolved at the settlement
+// price (1.0 if its side won, 0.0 otherwise) and credits the payout to the
+// bankroll. No order is placed: the platform pays out resolved contracts.
+func (m *Manager) Settle(positionID int64, settlementPrice float64) (ExitResult, error) {
+	position, err := m.positionRepo.GetByID(positionID)
 	if err != nil {
-		return result, fmt.Errorf("close position: %w", err)
+		return ExitResult{}, fmt.Errorf("get position: %w", err)
+	}
+	if position == nil {
+		return ExitResult{}, fmt.Errorf("position not found: %d", positionID)
+	}
+
+	if err := m.positionRepo.Transition(position, persistence.PositionStatusExiting); err != nil {
+		return ExitResult{}, fmt.Errorf("claim position for settlement: %w", err)
 	}
 
-	// Step 5: Add exit proceeds to bankroll
-	// Exit proceeds = exitPrice * quantity
-	exitProceeds := exitPrice * position.Quantity
-	err = m.bankrollRepo.AddToBalance(position.Platform, exitProceeds)
+	return m.closePosition(position, settlementPrice, position.Quantity, 0, ExitReasonResolved, ExitResult{})
+}
+
+// MarkPendingSettlement moves an open position whose market resolved in its
+// favour to pending_settlement while its payout awaits redemption. The
+// bankroll is not credited until Settle is called once the payout has been
+// verified.
+func (m *Manager) MarkPendingSettlement(positionID int64) error {
+	position, err := m.positionRepo.GetByID(positionID)
 	if err != nil {
-		return result, fmt.Errorf("add to bankroll: %w", err)
+		return fmt.Errorf("get position: %w", err)
+	}
+	if position == nil {
+		return fmt.Errorf("position not found: %d", positionID)
 	}
 
+	if err := m.positionRepo.Transition(position, persistence.PositionStatusPendingSettlement); err != nil {
+		return fmt.Errorf("mark position pending settlement: %w", err)
+	}
+	return nil
+}
+
+// CheckBankroll verifies that a platform's bankroll agrees with its ledger.
+// Entries are sized off the bankroll, so a corrupted balance must stop them
+// (see persistence.ErrLedgerInconsistent).
+func (m *Manager) CheckBankroll(platform string) error {
+	return m.bankrollRepo.CheckLedger(platform)
+}
+
+// closePosition closes a claimed position at exitPrice, of which sold was
+// sold and the rest written off, records its realized PnL net of fees,
+// credits the proceeds to the bankroll and records the exit event in one
+// transaction.
+func (m *Manager) closePosition(position *persistence.Position, exitPrice, sold, exitFee float64, reason string, result ExitResult) (ExitResult, error) {
+	quantity := position.Quantity
+
+	// Calculate realized PnL, including any earlier partial exits
+	// PnL = exitPrice * sold - entryPrice * quantity - fees
+	// Amounts are summed as types.Money so they are exact to the micro-dollar
+	fees := types.Dollars(position.Fees) + types.Dollars(exitFee)
+	position.Fees = fees.Float64()
+	pnl := types.Cost(exitPrice, sold) - types.Cost(position.EntryPrice, quantity) - fees
+	if position.RealizedPnL != nil {
+		pnl += types.Dollars(*position.RealizedPnL)
+	}
+	realizedPnL := pnl.Float64()
+
+	// Exit proceeds = exitPrice * sold - exit fee
+	exitProceeds := types.Cost(exitPrice, sold) - types.Dollars(exitFee)
+
 	// Populate result
-	result.PositionID = positionID
-	result.ExitPrice = exitPrice
-	result.ExitReason = reason
-	result.RealizedPnL = realizedPnL
-	result.EntryPrice = position.EntryPrice
-	result.Quantity = position.Quantity
+	closed := result
+	closed.PositionID = position.ID
+	closed.ExitPrice = exitPrice
+	closed.ExitReason = reason
+	closed.RealizedPnL = realizedPnL
+	closed.EntryPrice = position.EntryPrice
+	closed.Quantity = quantity
+	closed.Fees = position.Fees
 
-	return result, nil
+	// Close the position and add the exit proceeds to the bankroll together
+	event := m.exitEvent(position, closed)
+	if err := m.positionRepo.CloseExit(position, exitPrice, reason, pnl, exitProceeds, event); err != nil {
+		m.markError(position)
+		return result, fmt.Errorf("close position: %w", err)
+	}
+
+	return closed, nil
+}
+
+// simulateExitPrice applies the simulated latency to a dry-run exit and
+// returns the price it would fill at.
+func (m *Manager) simulateExitPrice(position *persistence.Position, price float64) float64 {
+	if m.simulation.Latency <= 0 {
+		return price
+	}
+	m.sleep(m.simulation.Latency)
+
+	quoter, ok := m.quoters[position.Platform]
+	if !ok {
+		return price
+	}
+	quoted, err := quoter.GetCurrentPrice(position.MarketID)
+	if err != nil {
+		log.Warn().Err(err).Int64("position_id", position.ID).Msg("Failed to re-price simulated exit, using requested price")
+		return price
+	}
+	return quoted
+}
+
+// simulatedFee returns the fee charged on a dry-run trade of the given notional.
+func (m *Manager) simulatedFee(platform string, notional float64) float64 {
+	return m.simulation.FeeRates[platform] * notional
+}
+
+// sellPosition places a sell order for the whole lots of the position at
+// price and waits for it to reach a final state, cancelling whatever is
+// still resting after the exit timeout. placed is false if orderer is nil.
+// A position of less than a lot fills nothing, without an order.
+func (m *Manager) sellPosition(ctx context.Context, orderer PlatformOrderer, position *persistence.Position, price float64) (fill types.OrderResult, placed bool, err error) {
+	if orderer == nil {
+		log.Warn().
+			Int64("position_id", position.ID).
+			Str("platform", position.Platform).
+			Msg("No orderer registered for platform, exit recorded without a sell order")
+		return fill, false, nil
+	}
+
+	size := m.roundLots(position.Platform, position.Quantity)
+	if size <= 0 {
+		log.Warn().
+			Int64("position_id", position.ID).
+			Float64("quantity", position.Quantity).
+			Msg("Position is less than a lot, written off without a sell order")
+		return fill, true, nil
+	}
+
+	fill, err = orderer.PlaceOrder(ctx, types.Order{
+		MarketID:    position.MarketID,
+		TokenID:     orderTokenID(position),
+		Side:        types.OrderSideSell,
+		Type:        types.OrderTypeLimit,
+		Price:       price,
+		Size:        size,
+		TimeInForce: types.TimeInForceIOC,
+	}, false)
+	if err != nil {
+		return fill, false, fmt.Errorf("place sell order: %w", err)
+	}
+
+	if fill, err = m.awaitFill(ctx, orderer, fill, m.exitTimeout, "sell"); err != nil {
+		return fill, true, err
+	}
+
+	log.Info().
+		Int64("position_id", position.ID).
+		Str("order_id", fill.OrderID).
+		Str("status", string(fill.Status)).
+		Float64("filled", fill.Filled).
+		Float64("fill_price", fill.FillPrice()).
+		Msg("Exit order completed")
+
+	return fill, true, nil
+}
+
+// awaitFill polls an order until it reaches a final state, cancelling
+// whatever is still resting after timeout or once ctx is done. kind names
+// the order in errors.
+func (m *Manager) awaitFill(ctx context.Context, orderer PlatformOrderer, fill types.OrderResult, timeout time.Duration, kind string) (types.OrderResult, error) {
+	var err error
+	deadline := m.now().Add(timeout)
+	for fill.IsResting() && ctx.Err() == nil && m.now().Before(deadline) {
+		m.sleep(orderPollInterval)
+		if fill, err = orderer.GetOrderStatus(fill.OrderID); err != nil {
+			return fill, fmt.Errorf("get %s order status: %w", kind, err)
+		}
+	}
+
+	if fill.IsResting() {
+		if err := orderer.CancelOrder(fill.OrderID); err != nil {
+			return fill, fmt.Errorf("cancel unfilled %s order: %w", kind, err)
+		}
+		if fill, err = orderer.GetOrderStatus(fill.OrderID); err != nil {
+			return fill, fmt.Errorf("get %s order status after cancel: %w", kind, err)
+		}
+	}
+	return fill, nil
+}
+
+// recordPartialExit books the sold part of a position and returns the rest
+// to open.
+func (m *Manager) recordPartialExit(position *persistence.Position, sold, price, fee float64, reason string, result ExitResult) (ExitResult, error) {
+	pnl := types.Cost(price, sold) - types.Cost(position.EntryPrice, sold)
+	if position.RealizedPnL != nil {
+		pnl += types.Dollars(*position.RealizedPnL)
+	}
+	realizedPnL := pnl.Float64()
+
+	// Each partial exit sells from a smaller quantity, which keys it apart
+	// from the others
+	key := persistence.PositionKey(position.ID, fmt.Sprintf("%s:%.6f", persistence.BankrollOpPartialExit, position.Quantity))
+
+	// Fees are deducted from realized PnL when the position finally closes
+	position.Quantity -= sold
+	position.RealizedPnL = &realizedPnL
+	position.Fees = (types.Dollars(position.Fees) + types.Dollars(fee)).Float64()
+
+	partial := result
+	partial.PositionID = position.ID
+	partial.ExitPrice = price
+	partial.ExitReason = reason
+	partial.RealizedPnL = realizedPnL
+	partial.EntryPrice = position.EntryPrice
+	partial.Quantity = sold
+	partial.RemainingQuantity = position.Quantity
+	partial.Fees = position.Fees
+
+	// Book the sale, credit its proceeds and return the rest to open together
+	proceeds := types.Cost(price, sold) - types.Dollars(fee)
+	if err := m.positionRepo.RecordPartialExit(position, proceeds, key, m.exitEvent(position, partial)); err != nil {
+		m.markError(position)
+		return result, fmt.Errorf("record partial exit: %w", err)
+	}
+
+	log.Warn().
+		Int64("position_id", position.ID).
+		Float64("sold", sold).
+		Float64("remaining", position.Quantity).
+		Msg("Exit order partially filled, remaining quantity stays open")
+
+	return partial, nil
+}
+
+// entryEvent builds the event recorded with an entry, nil without TradeEvents.
+func (m *Manager) entryEvent(platform, marketID string, result EntryResult, dryRun bool) *persistence.Event {
+	if m.events == nil {
+		return nil
+	}
+	return m.events.EntryEvent(platform, marketID, result, dryRun)
+}
+
+// exitEvent builds the event recorded with an exit from position, nil
+// without TradeEvents.
+func (m *Manager) exitEvent(position *persistence.Position, exit ExitResult) *persistence.Event {
+	if m.events == nil {
+		return nil
+	}
+	return m.events.ExitEvent(position.Platform, position.MarketID, exit)
+}
+
+// release returns a position claimed for exit to open.
+func (m *Manager) release(position *persistence.Position) {
+	if err := m.positionRepo.Transition(position, persistence.PositionStatusOpen); err != nil {
+		log.Error().Err(err).Int64("position_id", position.ID).Msg("Failed to release position after aborted exit")
+	}
+}
+
+// markError flags a position whose transition failed midway, so recovery can
+// reconcile it against the platform.
+func (m *Manager) markError(position *persistence.Position) {
+	if err := m.positionRepo.Transition(position, persistence.PositionStatusError); err != nil {
+		log.Error().Err(err).Int64("position_id", position.ID).Msg("Failed to mark position as error")
+	}
+}
+
+// cancelOpenOrders cancels all resting orders on the position's market and
+// verifies that none remain. Returns the number of orders cancelled.
+func (m *Manager) cancelOpenOrders(position *persistence.Position) (int, error) {
+	canceller, ok := m.cancellers[position.Platform]
+	if !ok {
+		return 0, nil
+	}
+
+	orders, err := canceller.GetOpenOrders(position.MarketID)
+	if err != nil {
+		return 0, fmt.Errorf("get open orders: %w", err)
+	}
+
+	cancelled := make(map[string]bool)
+	for _, order := range orders {
+		if !order.IsResting() {
+			continue
+		}
+		if err := canceller.CancelOrder(order.OrderID); err != nil {
+			return len(cancelled), fmt.Errorf("cancel order %s: %w", order.OrderID, err)
+		}
+		cancelled[order.OrderID] = true
+	}
+
+	if len(cancelled) == 0 {
+		return 0, nil
+	}
+
+	// Verify cancellation with a fresh read of the order state
+	remaining, err := canceller.GetOpenOrders(position.MarketID)
+	if err != nil {
+		return len(cancelled), fmt.Errorf("verify cancellation: %w", err)
+	}
+	for _, order := range remaining {
+		if cancelled[order.OrderID] && order.IsResting() {
+			return len(cancelled), fmt.Errorf("%w: %s", ErrOrdersNotCancelled, order.OrderID)
+		}
+	}
+
+	return len(cancelled), nil
+}
+
+// CancelRestingOrders cancels the orders resting on the markets of every
+// position not yet closed, such as when the bot shuts down, on the
+// platforms with an order canceller. A market that fails is logged and
+// the rest are still cancelled. Returns the number of orders cancelled.
+func (m *Manager) CancelRestingOrders() (int, error) {
+	platforms := make([]string, 0, len(m.cancellers))
+	for name := range m.cancellers {
+		platforms = append(platforms, name)
+	}
+	sort.Strings(platforms)
+
+	var total int
+	var errs []error
+	for _, name := range platforms {
+		positions, err := m.positionRepo.GetActiveByPlatform(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		markets := make(map[string]bool)
+		for _, position := range positions {
+			if markets[position.MarketID] {
+				continue
+			}
+			markets[position.MarketID] = true
+			cancelled, err := m.cancelOpenOrders(position)
+			total += cancelled
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("platform", name).
+					Str("market_id", position.MarketID).
+					Msg("Failed to cancel resting orders")
+				errs = append(errs, fmt.Errorf("cancel orders on %s market %s: %w", name, position.MarketID, err))
+			}
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// outcomeTokenID returns the token for the outcome bought by side. Markets
+// without tokens (Kalshi) are traded by contract side.
+func outcomeTokenID(market types.Market, side string) string {
+	if len(market.Tokens) == 0 {
+		return strings.ToLower(side)
+	}
+	for _, token := range market.Tokens {
+		if strings.EqualFold(token.Outcome, side) {
+			return token.TokenID
+		}
+	}
+	return ""
 }
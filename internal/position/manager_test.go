@@ -1,12 +1,16 @@
 package position
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"math"
 	"os"
 	"testing"
 	"time"
 
 	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/risk"
 	"prediction-bot/internal/scanner"
 	"prediction-bot/internal/sizing"
 	"prediction-bot/internal/volatility"
@@ -49,11 +53,13 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 
 // MockVolatilityService mocks the volatility service for testing.
 type MockVolatilityService struct {
-	result volatility.ServiceResult
-	err    error
+	result      volatility.ServiceResult
+	err         error
+	timeToClose time.Duration // Time to close of the last analysis
 }
 
-func (m *MockVolatilityService) AnalyzeAsset(asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+func (m *MockVolatilityService) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+	m.timeToClose = timeToClose
 	if m.err != nil {
 		return volatility.ServiceResult{}, m.err
 	}
@@ -123,7 +129,7 @@ func TestProcessEntryDryRun(t *testing.T) {
 	}
 
 	// Process entry in dry-run mode
-	result, err := manager.ProcessEntry(market, true)
+	result, err := manager.ProcessEntry(context.Background(), market, true)
 	if err != nil {
 		t.Fatalf("ProcessEntry failed: %v", err)
 	}
@@ -176,6 +182,71 @@ func TestProcessEntryDryRun(t *testing.T) {
 	}
 }
 
+// TestProcessEntryAnalyzesToDeadline tests that volatility is analyzed over
+// the time to the deadline the title names, rather than to EndDate, and the
+// position closes at it.
+func TestProcessEntryAnalyzesToDeadline(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("kalshi", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+
+	mockVolatility := &MockVolatilityService{
+		result: volatility.ServiceResult{
+			Volatility:     0.5,
+			SafetyMargin:   1.91,
+			Recommendation: volatility.RecommendationValid,
+		},
+	}
+	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+	now := time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC)
+	manager.SetClock(func() time.Time { return now })
+
+	market := scanner.EligibleMarket{
+		Market: types.Market{
+			ID:              "KXBTCD-26JAN2017-T95000",
+			Platform:        "kalshi",
+			Title:           "Bitcoin price on Jan 20 at 12pm ET above $95,000?",
+			EndDate:         now.Add(24 * time.Hour),
+			OutcomeYesPrice: 0.90,
+			Liquidity:       1000.0,
+		},
+		Parsed: &scanner.ParsedMarket{
+			Asset:     "BTC",
+			Strike:    95000.0,
+			Direction: "above",
+		},
+		Probability: 0.90,
+		BetSide:     "YES",
+		Deadline:    now.Add(8 * time.Hour),
+	}
+
+	result, err := manager.ProcessEntry(context.Background(), market, true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if mockVolatility.timeToClose != 8*time.Hour {
+		t.Errorf("Expected volatility analyzed over 8h to the deadline, got %s", mockVolatility.timeToClose)
+	}
+
+	// The position closes at the deadline too, for the exits that read it
+	pos, err := positionRepo.GetByID(result.PositionID)
+	if err != nil || pos == nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if pos.MarketCloseTime == nil || !pos.MarketCloseTime.Equal(market.Deadline) {
+		t.Errorf("Expected the position to close at the deadline %s, got %v", market.Deadline, pos.MarketCloseTime)
+	}
+}
+
 // TestProcessEntryDuplicatePosition tests that duplicate positions are skipped.
 func TestProcessEntryDuplicatePosition(t *testing.T) {
 	db, cleanup := setupTestDB(t)
@@ -237,7 +308,7 @@ func TestProcessEntryDuplicatePosition(t *testing.T) {
 		BetSide:     "YES",
 	}
 
-	result, err := manager.ProcessEntry(market, true)
+	result, err := manager.ProcessEntry(context.Background(), market, true)
 	if err != nil {
 		t.Fatalf("ProcessEntry failed: %v", err)
 	}
@@ -250,6 +321,249 @@ func TestProcessEntryDuplicatePosition(t *testing.T) {
 	}
 }
 
+// TestProcessEntryPortfolioLimit tests that entries breaching a portfolio
+// limit are skipped.
+func TestProcessEntryPortfolioLimit(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// $50 cash on each platform
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	for _, platform := range []string{"polymarket", "kalshi"} {
+		if err := bankrollRepo.Initialize(platform, 50.0); err != nil {
+			t.Fatalf("Failed to initialize bankroll: %v", err)
+		}
+	}
+
+	positionRepo := persistence.NewPositionRepository(db)
+
+	// $18 already on BTC in another market
+	_, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "btc-other",
+		Asset:      "BTC",
+		Strike:     90000.0,
+		Direction:  "above",
+		EntryPrice: 0.90,
+		Quantity:   20.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+
+	mockVolatility := &MockVolatilityService{
+		result: volatility.ServiceResult{
+			SafetyMargin:   2.5,
+			Recommendation: volatility.RecommendationValid,
+		},
+	}
+	sizer := sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	})
+
+	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizer)
+	manager.SetRiskChecker(risk.NewChecker(risk.Limits{MaxAssetExposure: 0.18}))
+
+	market := scanner.EligibleMarket{
+		Market: types.Market{
+			ID:              "test-market-1",
+			Platform:        "polymarket",
+			OutcomeYesPrice: 0.85,
+		},
+		Parsed: &scanner.ParsedMarket{
+			Asset:     "BTC",
+			Strike:    95000.0,
+			Direction: "above",
+		},
+		Probability: 0.85,
+		BetSide:     "YES",
+	}
+
+	// Any entry over $3.24 would put more than 18% of $118 capital on BTC
+	result, err := manager.ProcessEntry(context.Background(), market, true)
+	if err != nil {
+		t.Fatalf("ProcessEntry failed: %v", err)
+	}
+	if !result.Skipped || result.SkipReason != SkipReasonPortfolioLimit {
+		t.Fatalf("Expected skip reason '%s', got %+v", SkipReasonPortfolioLimit, result)
+	}
+
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 50.0 {
+		t.Errorf("Expected bankroll untouched, got %f", bankroll.CurrentAmount)
+	}
+}
+
+// TestProcessEntryCorrelatedSizing tests that open positions betting the
+// same way on the asset shrink a correlation-aware entry, and those betting
+// the other way don't.
+func TestProcessEntryCorrelatedSizing(t *testing.T) {
+	tests := []struct {
+		name        string
+		side        string
+		wantSkipped bool
+	}{
+		{name: "same way", side: "YES", wantSkipped: true},
+		{name: "other way", side: "NO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			bankrollRepo := persistence.NewBankrollRepository(db)
+			if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+				t.Fatalf("Failed to initialize bankroll: %v", err)
+			}
+			positionRepo := persistence.NewPositionRepository(db)
+
+			// $18 already on BTC in another market
+			_, err := positionRepo.Create(&persistence.Position{
+				Platform:   "polymarket",
+				MarketID:   "btc-other",
+				Asset:      "BTC",
+				Strike:     90000.0,
+				Direction:  "above",
+				EntryPrice: 0.90,
+				Quantity:   20.0,
+				Side:       tt.side,
+				Status:     "open",
+			})
+			if err != nil {
+				t.Fatalf("Failed to create position: %v", err)
+			}
+
+			mockVolatility := &MockVolatilityService{
+				result: volatility.ServiceResult{
+					SafetyMargin:   2.5,
+					Recommendation: volatility.RecommendationValid,
+				},
+			}
+			sizer := sizing.NewSizer(sizing.SizerConfig{
+				KellyFraction:  0.25,
+				MinPosition:    1.0,
+				MaxBankrollPct: 0.20,
+				Correlation:    1.0,
+			})
+			manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizer)
+
+			market := scanner.EligibleMarket{
+				Market: types.Market{
+					ID:              "test-market-1",
+					Platform:        "polymarket",
+					OutcomeYesPrice: 0.85,
+				},
+				Parsed: &scanner.ParsedMarket{
+					Asset:     "BTC",
+					Strike:    95000.0,
+					Direction: "above",
+				},
+				Probability: 0.85,
+				BetSide:     "YES",
+			}
+
+			// The $18 exceeds the entry's own Kelly stake
+			result, err := manager.ProcessEntry(context.Background(), market, true)
+			if err != nil {
+				t.Fatalf("ProcessEntry failed: %v", err)
+			}
+			if tt.wantSkipped {
+				if !result.Skipped || result.SkipReason != SkipReasonSizingCorrelated {
+					t.Fatalf("Expected skip reason '%s', got %+v", SkipReasonSizingCorrelated, result)
+				}
+				return
+			}
+			if result.Skipped {
+				t.Fatalf("Expected entry sized independently, got skipped: %s", result.SkipReason)
+			}
+		})
+	}
+}
+
+// TestProcessEntryVaRLimit tests that entries pushing the portfolio's daily
+// VaR over its limit are downsized to fit it, or skipped if they can't be.
+func TestProcessEntryVaRLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxVaR      float64
+		wantSkipped bool
+		wantSize    float64
+	}{
+		{name: "downsized", maxVaR: 0.03, wantSize: 3.0},
+		{name: "below minimum", maxVaR: 0.005, wantSkipped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, cleanup := setupTestDB(t)
+			defer cleanup()
+
+			// $50 cash on each platform
+			bankrollRepo := persistence.NewBankrollRepository(db)
+			for _, platform := range []string{"polymarket", "kalshi"} {
+				if err := bankrollRepo.Initialize(platform, 50.0); err != nil {
+					t.Fatalf("Failed to initialize bankroll: %v", err)
+				}
+			}
+			positionRepo := persistence.NewPositionRepository(db)
+
+			mockVolatility := &MockVolatilityService{
+				result: volatility.ServiceResult{
+					SafetyMargin:   2.5,
+					Recommendation: volatility.RecommendationValid,
+				},
+			}
+			sizer := sizing.NewSizer(sizing.SizerConfig{
+				KellyFraction:  0.25,
+				MinPosition:    1.0,
+				MaxBankrollPct: 0.20,
+			})
+
+			manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizer)
+			manager.SetRiskChecker(risk.NewChecker(risk.Limits{MaxDailyVaR: tt.maxVaR}))
+
+			market := scanner.EligibleMarket{
+				Market: types.Market{
+					ID:              "test-market-1",
+					Platform:        "polymarket",
+					OutcomeYesPrice: 0.85,
+				},
+				Parsed: &scanner.ParsedMarket{
+					Asset:     "BTC",
+					Strike:    95000.0,
+					Direction: "above",
+				},
+				Probability: 0.85,
+				BetSide:     "YES",
+			}
+
+			// A 15% chance of losing within the day puts the whole entry at
+			// risk, so it may be at most the limit's share of $100 capital
+			result, err := manager.ProcessEntry(context.Background(), market, true)
+			if err != nil {
+				t.Fatalf("ProcessEntry failed: %v", err)
+			}
+			if tt.wantSkipped {
+				if !result.Skipped || result.SkipReason != SkipReasonVaRLimit {
+					t.Fatalf("Expected skip reason '%s', got %+v", SkipReasonVaRLimit, result)
+				}
+				return
+			}
+			if result.Skipped {
+				t.Fatalf("Expected entry downsized, got skipped: %s", result.SkipReason)
+			}
+			if result.PositionSize != tt.wantSize {
+				t.Errorf("Expected position size %f, got %f", tt.wantSize, result.PositionSize)
+			}
+		})
+	}
+}
+
 // TestProcessEntryVolatilityReject tests that positions with poor volatility are rejected.
 func TestProcessEntryVolatilityReject(t *testing.T) {
 	db, cleanup := setupTestDB(t)
@@ -296,7 +610,7 @@ func TestProcessEntryVolatilityReject(t *testing.T) {
 		BetSide:     "YES",
 	}
 
-	result, err := manager.ProcessEntry(market, true)
+	result, err := manager.ProcessEntry(context.Background(), market, true)
 	if err != nil {
 		t.Fatalf("ProcessEntry failed: %v", err)
 	}
@@ -356,7 +670,7 @@ func TestProcessEntrySizingTooSmall(t *testing.T) {
 		BetSide:     "YES",
 	}
 
-	result, err := manager.ProcessEntry(market, true)
+	result, err := manager.ProcessEntry(context.Background(), market, true)
 	if err != nil {
 		t.Fatalf("ProcessEntry failed: %v", err)
 	}
@@ -415,7 +729,7 @@ func TestProcessEntryBankrollDeducted(t *testing.T) {
 		BetSide:     "YES",
 	}
 
-	result, err := manager.ProcessEntry(market, true)
+	result, err := manager.ProcessEntry(context.Background(), market, true)
 	if err != nil {
 		t.Fatalf("ProcessEntry failed: %v", err)
 	}
@@ -490,7 +804,7 @@ func TestProcessEntryAllowsRisky(t *testing.T) {
 		BetSide:     "YES",
 	}
 
-	result, err := manager.ProcessEntry(market, true)
+	result, err := manager.ProcessEntry(context.Background(), market, true)
 	if err != nil {
 		t.Fatalf("ProcessEntry failed: %v", err)
 	}
@@ -513,7 +827,7 @@ func TestExecuteExitDryRunStopLoss(t *testing.T) {
 	}
 
 	// Deduct position cost from bankroll (simulating entry)
-	err = bankrollRepo.AddToBalance("polymarket", -9.0) // $9 position (10 contracts * $0.90)
+	err = bankrollRepo.AddToBalance("polymarket", types.Dollars(-9.0)) // $9 position (10 contracts * $0.90)
 	if err != nil {
 		t.Fatalf("Failed to deduct from bankroll: %v", err)
 	}
@@ -551,7 +865,7 @@ func TestExecuteExitDryRunStopLoss(t *testing.T) {
 
 	// Execute exit at a loss (stop loss triggered at $0.75)
 	exitPrice := 0.75
-	result, err := manager.ExecuteExit(positionID, exitPrice, ExitReasonStopLoss, true)
+	result, err := manager.ExecuteExit(context.Background(), positionID, exitPrice, ExitReasonStopLoss, true)
 	if err != nil {
 		t.Fatalf("ExecuteExit failed: %v", err)
 	}
@@ -613,7 +927,7 @@ func TestExecuteExitDryRunVolatility(t *testing.T) {
 	}
 
 	// Deduct position cost from bankroll
-	err = bankrollRepo.AddToBalance("polymarket", -9.0)
+	err = bankrollRepo.AddToBalance("polymarket", types.Dollars(-9.0))
 	if err != nil {
 		t.Fatalf("Failed to deduct from bankroll: %v", err)
 	}
@@ -650,7 +964,7 @@ func TestExecuteExitDryRunVolatility(t *testing.T) {
 
 	// Exit at current price (slight loss due to volatility concerns)
 	exitPrice := 0.88
-	result, err := manager.ExecuteExit(positionID, exitPrice, ExitReasonVolatility, true)
+	result, err := manager.ExecuteExit(context.Background(), positionID, exitPrice, ExitReasonVolatility, true)
 	if err != nil {
 		t.Fatalf("ExecuteExit failed: %v", err)
 	}
@@ -681,7 +995,7 @@ func TestExecuteExitDryRunWin(t *testing.T) {
 	}
 
 	// Deduct position cost from bankroll
-	err = bankrollRepo.AddToBalance("polymarket", -9.0) // Entry: 10 * 0.90 = $9
+	err = bankrollRepo.AddToBalance("polymarket", types.Dollars(-9.0)) // Entry: 10 * 0.90 = $9
 	if err != nil {
 		t.Fatalf("Failed to deduct from bankroll: %v", err)
 	}
@@ -718,7 +1032,7 @@ func TestExecuteExitDryRunWin(t *testing.T) {
 
 	// Market resolved YES, exit at $1.00
 	exitPrice := 1.0
-	result, err := manager.ExecuteExit(positionID, exitPrice, ExitReasonResolved, true)
+	result, err := manager.ExecuteExit(context.Background(), positionID, exitPrice, ExitReasonResolved, true)
 	if err != nil {
 		t.Fatalf("ExecuteExit failed: %v", err)
 	}
@@ -759,7 +1073,7 @@ func TestExecuteExitPositionNotFound(t *testing.T) {
 	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizer)
 
 	// Try to exit a position that doesn't exist
-	_, err := manager.ExecuteExit(99999, 0.50, ExitReasonStopLoss, true)
+	_, err := manager.ExecuteExit(context.Background(), 99999, 0.50, ExitReasonStopLoss, true)
 	if err == nil {
 		t.Fatal("Expected error for non-existent position")
 	}
@@ -792,7 +1106,7 @@ func TestExecuteExitAlreadyClosed(t *testing.T) {
 	}
 
 	// Close it via repository directly
-	err = positionRepo.Close(positionID, 0.95, "test_close", 0.5)
+	err = positionRepo.Close(positionID, 0.95, "test_close", types.Dollars(0.5))
 	if err != nil {
 		t.Fatalf("Failed to close position: %v", err)
 	}
@@ -808,8 +1122,614 @@ func TestExecuteExitAlreadyClosed(t *testing.T) {
 	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizer)
 
 	// Try to exit the already closed position
-	_, err = manager.ExecuteExit(positionID, 0.50, ExitReasonStopLoss, true)
+	_, err = manager.ExecuteExit(context.Background(), positionID, 0.50, ExitReasonStopLoss, true)
 	if err == nil {
 		t.Fatal("Expected error for already closed position")
 	}
 }
+
+// MockOrderCanceller mocks a platform client's order management for testing.
+type MockOrderCanceller struct {
+	orders       []types.OrderResult
+	cancelled    []string
+	ignoreCancel bool
+}
+
+func (m *MockOrderCanceller) GetOpenOrders(marketID string) ([]types.OrderResult, error) {
+	var open []types.OrderResult
+	for _, o := range m.orders {
+		if o.MarketID == marketID && o.IsResting() {
+			open = append(open, o)
+		}
+	}
+	return open, nil
+}
+
+func (m *MockOrderCanceller) CancelOrder(orderID string) error {
+	m.cancelled = append(m.cancelled, orderID)
+	if m.ignoreCancel {
+		return nil
+	}
+	for i := range m.orders {
+		if m.orders[i].OrderID == orderID {
+			m.orders[i].Status = types.OrderStatusCancelled
+		}
+	}
+	return nil
+}
+
+// createOpenTestPosition creates an open position for exit tests.
+func createOpenTestPosition(t *testing.T, positionRepo *persistence.PositionRepository, marketID string) int64 {
+	t.Helper()
+
+	positionID, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   marketID,
+		Asset:      "BTC",
+		Strike:     95000.0,
+		Direction:  "above",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+	return positionID
+}
+
+// TestExecuteExitCancelsRestingOrders tests that a live exit cancels a partially
+// filled entry order before closing the position.
+func TestExecuteExitCancelsRestingOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-cancel")
+
+	canceller := &MockOrderCanceller{
+		orders: []types.OrderResult{
+			{OrderID: "entry-1", MarketID: "test-market-cancel", Size: 20, Filled: 10, Status: types.OrderStatusPartial},
+			{OrderID: "old-1", MarketID: "test-market-cancel", Status: types.OrderStatusFilled},
+			{OrderID: "other-1", MarketID: "other-market", Status: types.OrderStatusOpen},
+		},
+	}
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetOrderCanceller("polymarket", canceller)
+
+	result, err := manager.ExecuteExit(context.Background(), positionID, 0.75, ExitReasonStopLoss, false)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+
+	if result.CancelledOrders != 1 {
+		t.Errorf("Expected 1 cancelled order, got %d", result.CancelledOrders)
+	}
+	if len(canceller.cancelled) != 1 || canceller.cancelled[0] != "entry-1" {
+		t.Errorf("Expected only entry-1 to be cancelled, got %v", canceller.cancelled)
+	}
+
+	pos, err := positionRepo.GetByID(positionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != "closed" {
+		t.Errorf("Expected status 'closed', got '%s'", pos.Status)
+	}
+}
+
+// TestExecuteExitAbortsWhenCancelNotVerified tests that the exit is aborted if
+// a resting order is still open after cancellation.
+func TestExecuteExitAbortsWhenCancelNotVerified(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-stuck")
+
+	canceller := &MockOrderCanceller{
+		orders: []types.OrderResult{
+			{OrderID: "entry-1", MarketID: "test-market-stuck", Status: types.OrderStatusOpen},
+		},
+		ignoreCancel: true,
+	}
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetOrderCanceller("polymarket", canceller)
+
+	_, err := manager.ExecuteExit(context.Background(), positionID, 0.75, ExitReasonStopLoss, false)
+	if !errors.Is(err, ErrOrdersNotCancelled) {
+		t.Fatalf("Expected ErrOrdersNotCancelled, got %v", err)
+	}
+
+	pos, err := positionRepo.GetByID(positionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != "open" {
+		t.Errorf("Expected position to remain 'open', got '%s'", pos.Status)
+	}
+}
+
+// TestExecuteExitDryRunSkipsCancellation tests that dry-run exits never touch orders.
+func TestExecuteExitDryRunSkipsCancellation(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-dry")
+
+	canceller := &MockOrderCanceller{
+		orders: []types.OrderResult{
+			{OrderID: "entry-1", MarketID: "test-market-dry", Status: types.OrderStatusOpen},
+		},
+	}
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetOrderCanceller("polymarket", canceller)
+
+	if _, err := manager.ExecuteExit(context.Background(), positionID, 0.75, ExitReasonStopLoss, true); err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+	if len(canceller.cancelled) != 0 {
+		t.Errorf("Expected no cancellations in dry-run, got %v", canceller.cancelled)
+	}
+}
+
+// TestExecuteExitRejectsPositionAlreadyExiting tests that a position claimed by
+// another monitor cycle cannot be exited twice.
+func TestExecuteExitRejectsPositionAlreadyExiting(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-double-exit")
+
+	// Simulate a concurrent cycle that has already claimed the position
+	claimed, err := positionRepo.GetByID(positionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if err := positionRepo.Transition(claimed, persistence.PositionStatusExiting); err != nil {
+		t.Fatalf("Failed to claim position: %v", err)
+	}
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+
+	_, err = manager.ExecuteExit(context.Background(), positionID, 0.75, ExitReasonStopLoss, true)
+	if !errors.Is(err, persistence.ErrInvalidTransition) {
+		t.Fatalf("Expected ErrInvalidTransition, got %v", err)
+	}
+
+	bankroll, err := bankrollRepo.Get("polymarket")
+	if err != nil {
+		t.Fatalf("Failed to get bankroll: %v", err)
+	}
+	if bankroll.CurrentAmount != 50.0 {
+		t.Errorf("Expected bankroll untouched at 50.0, got %.2f", bankroll.CurrentAmount)
+	}
+}
+
+// MockOrderer mocks a platform client that fills sell orders for testing.
+type MockOrderer struct {
+	placed       []types.Order
+	fillQuantity float64 // -1 fills the full size
+	fillPrice    float64
+	cancelled    []string
+	last         types.OrderResult
+}
+
+func (m *MockOrderer) PlaceOrder(ctx context.Context, order types.Order, dryRun bool) (types.OrderResult, error) {
+	m.placed = append(m.placed, order)
+
+	filled := m.fillQuantity
+	if filled < 0 {
+		filled = order.Size
+	}
+	status := types.OrderStatusFilled
+	if filled < order.Size {
+		status = types.OrderStatusPartial
+	}
+	m.last = types.OrderResult{
+		OrderID:      "exit-1",
+		MarketID:     order.MarketID,
+		TokenID:      order.TokenID,
+		Side:         order.Side,
+		Price:        order.Price,
+		Size:         order.Size,
+		Filled:       filled,
+		AvgFillPrice: m.fillPrice,
+		Status:       status,
+	}
+	return m.last, nil
+}
+
+func (m *MockOrderer) GetOrderStatus(orderID string) (types.OrderResult, error) {
+	return m.last, nil
+}
+
+func (m *MockOrderer) CancelOrder(orderID string) error {
+	m.cancelled = append(m.cancelled, orderID)
+	m.last.Status = types.OrderStatusCancelled
+	return nil
+}
+
+// TestExecuteExitLiveUsesFillPrice tests that a live exit places a sell order
+// and records the executed price instead of the assumed one.
+func TestExecuteExitLiveUsesFillPrice(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-live")
+
+	orderer := &MockOrderer{fillQuantity: -1, fillPrice: 0.72}
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetPlatformOrderer("polymarket", orderer)
+
+	result, err := manager.ExecuteExit(context.Background(), positionID, 0.75, ExitReasonStopLoss, false)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+
+	if len(orderer.placed) != 1 {
+		t.Fatalf("Expected 1 sell order, got %d", len(orderer.placed))
+	}
+	order := orderer.placed[0]
+	if order.Side != types.OrderSideSell || order.Size != 10.0 || order.Price != 0.75 || order.TokenID != "yes" {
+		t.Errorf("Unexpected sell order: %+v", order)
+	}
+
+	if result.ExitPrice != 0.72 || result.OrderID != "exit-1" {
+		t.Errorf("Expected exit at fill price 0.72 via exit-1, got %f via %s", result.ExitPrice, result.OrderID)
+	}
+
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Status != "closed" || pos.ExitPrice == nil || *pos.ExitPrice != 0.72 {
+		t.Errorf("Expected position closed at 0.72, got %s %v", pos.Status, pos.ExitPrice)
+	}
+
+	// Bankroll: 50 + 0.72 * 10
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if diff := bankroll.CurrentAmount - 57.2; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected bankroll 57.2, got %f", bankroll.CurrentAmount)
+	}
+}
+
+// TestExecuteExitLivePartialFill tests that a partially filled sell books the
+// sold quantity and leaves the rest open.
+func TestExecuteExitLivePartialFill(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-partial")
+
+	orderer := &MockOrderer{fillQuantity: 4, fillPrice: 0.70}
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetPlatformOrderer("polymarket", orderer)
+	manager.SetExitTimeout(0)
+
+	result, err := manager.ExecuteExit(context.Background(), positionID, 0.70, ExitReasonStopLoss, false)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+
+	if len(orderer.cancelled) != 1 {
+		t.Errorf("Expected unfilled remainder to be cancelled, got %v", orderer.cancelled)
+	}
+	if result.Quantity != 4 || result.RemainingQuantity != 6 {
+		t.Errorf("Expected 4 sold and 6 remaining, got %f and %f", result.Quantity, result.RemainingQuantity)
+	}
+
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Status != "open" || pos.Quantity != 6 {
+		t.Errorf("Expected open position with 6 remaining, got %s %f", pos.Status, pos.Quantity)
+	}
+
+	// Selling the rest closes the position with PnL for all 10 contracts
+	orderer.fillQuantity = -1
+	result, err = manager.ExecuteExit(context.Background(), positionID, 0.70, ExitReasonStopLoss, false)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+
+	expectedPnL := (0.70 - 0.90) * 10
+	if diff := result.RealizedPnL - expectedPnL; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected total PnL %f, got %f", expectedPnL, result.RealizedPnL)
+	}
+	pos, _ = positionRepo.GetByID(positionID)
+	if pos.Status != "closed" {
+		t.Errorf("Expected position closed, got %s", pos.Status)
+	}
+}
+
+// TestExecuteExitLiveNotFilled tests that an unfilled sell keeps the position open.
+func TestExecuteExitLiveNotFilled(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID := createOpenTestPosition(t, positionRepo, "test-market-unfilled")
+
+	orderer := &MockOrderer{fillQuantity: 0}
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetPlatformOrderer("polymarket", orderer)
+	manager.SetExitTimeout(0)
+
+	_, err := manager.ExecuteExit(context.Background(), positionID, 0.70, ExitReasonStopLoss, false)
+	if !errors.Is(err, ErrExitNotFilled) {
+		t.Fatalf("Expected ErrExitNotFilled, got %v", err)
+	}
+
+	pos, _ := positionRepo.GetByID(positionID)
+	if pos.Status != "open" || pos.Quantity != 10 {
+		t.Errorf("Expected untouched open position, got %s %f", pos.Status, pos.Quantity)
+	}
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 50.0 {
+		t.Errorf("Expected bankroll unchanged, got %f", bankroll.CurrentAmount)
+	}
+}
+
+func TestOutcomeTokenID(t *testing.T) {
+	market := types.Market{Tokens: []types.Token{
+		{TokenID: "tok-yes", Outcome: "Yes"},
+		{TokenID: "tok-no", Outcome: "No"},
+	}}
+	if got := outcomeTokenID(market, "NO"); got != "tok-no" {
+		t.Errorf("Expected tok-no, got %s", got)
+	}
+	if got := outcomeTokenID(types.Market{}, "YES"); got != "yes" {
+		t.Errorf("Expected contract side yes for tokenless market, got %s", got)
+	}
+}
+
+// MockQuoter returns a fixed current price for testing.
+type MockQuoter struct {
+	price float64
+}
+
+func (m *MockQuoter) GetCurrentPrice(marketID string) (float64, error) {
+	return m.price, nil
+}
+
+// TestDryRunSimulationAppliesFeesAndLatency tests that simulated fees are
+// charged on entry and exit and that latency re-prices the exit.
+func TestDryRunSimulationAppliesFeesAndLatency(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+
+	mockVolatility := &MockVolatilityService{
+		result: volatility.ServiceResult{
+			SafetyMargin:   1.91,
+			Volatility:     0.5,
+			Recommendation: volatility.RecommendationValid,
+		},
+	}
+	manager := NewManager(positionRepo, bankrollRepo, mockVolatility, sizing.NewSizer(sizing.SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}))
+
+	var slept time.Duration
+	manager.sleep = func(d time.Duration) { slept += d }
+	manager.SetDryRunSimulation(DryRunSimulation{
+		Latency:  500 * time.Millisecond,
+		FeeRates: map[string]float64{"polymarket": 0.02},
+	})
+	manager.SetPriceQuoter("polymarket", &MockQuoter{price: 0.70})
+
+	entry, err := manager.ProcessEntry(context.Background(), scanner.EligibleMarket{
+		Market: types.Market{
+			ID:       "test-market-sim",
+			Platform: "polymarket",
+			EndDate:  time.Now().Add(24 * time.Hour),
+		},
+		Parsed:      &scanner.ParsedMarket{Asset: "BTC", Strike: 95000.0, Direction: "above"},
+		Probability: 0.90,
+		BetSide:     "YES",
+	}, true)
+	if err != nil || entry.Skipped {
+		t.Fatalf("ProcessEntry failed: %v (skipped=%v)", err, entry.Skipped)
+	}
+
+	entryFee := entry.PositionSize * 0.02
+	if diff := entry.Fees - entryFee; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected entry fee %f, got %f", entryFee, entry.Fees)
+	}
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if diff := bankroll.CurrentAmount - (50.0 - entry.PositionSize - entryFee); diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected bankroll to be charged the entry fee, got %f", bankroll.CurrentAmount)
+	}
+
+	// Stop loss requested at 0.75 but the price moved to 0.70 during latency
+	exit, err := manager.ExecuteExit(context.Background(), entry.PositionID, 0.75, ExitReasonStopLoss, true)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+
+	if slept != 500*time.Millisecond {
+		t.Errorf("Expected 500ms simulated latency, got %s", slept)
+	}
+	if exit.ExitPrice != 0.70 {
+		t.Errorf("Expected exit re-priced to 0.70, got %f", exit.ExitPrice)
+	}
+
+	// Amounts are rounded to the micro-dollar, so allow a few micros of error
+	const tolerance = 5e-6
+	exitFee := 0.70 * entry.Quantity * 0.02
+	expectedPnL := (0.70-0.90)*entry.Quantity - entryFee - exitFee
+	if diff := exit.RealizedPnL - expectedPnL; diff > tolerance || diff < -tolerance {
+		t.Errorf("Expected PnL net of fees %f, got %f", expectedPnL, exit.RealizedPnL)
+	}
+
+	pos, _ := positionRepo.GetByID(entry.PositionID)
+	if diff := pos.Fees - (entryFee + exitFee); diff > tolerance || diff < -tolerance {
+		t.Errorf("Expected stored fees %f, got %f", entryFee+exitFee, pos.Fees)
+	}
+	bankroll, _ = bankrollRepo.Get("polymarket")
+	if diff := bankroll.CurrentAmount - (50.0 + expectedPnL); diff > tolerance || diff < -tolerance {
+		t.Errorf("Expected final bankroll %f, got %f", 50.0+expectedPnL, bankroll.CurrentAmount)
+	}
+}
+
+// TestCancelRestingOrders tests that the orders resting on the markets of
+// positions not yet closed are cancelled once per market.
+func TestCancelRestingOrders(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	positionRepo := persistence.NewPositionRepository(db)
+	createOpenTestPosition(t, positionRepo, "market-a")
+	createOpenTestPosition(t, positionRepo, "market-a")
+	createOpenTestPosition(t, positionRepo, "market-b")
+
+	canceller := &MockOrderCanceller{
+		orders: []types.OrderResult{
+			{OrderID: "exit-a", MarketID: "market-a", Status: types.OrderStatusOpen},
+			{OrderID: "filled-b", MarketID: "market-b", Status: types.OrderStatusFilled},
+			{OrderID: "other", MarketID: "market-c", Status: types.OrderStatusOpen},
+		},
+	}
+
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetOrderCanceller("polymarket", canceller)
+
+	cancelled, err := manager.CancelRestingOrders()
+	if err != nil {
+		t.Fatalf("CancelRestingOrders failed: %v", err)
+	}
+	if cancelled != 1 || len(canceller.cancelled) != 1 || canceller.cancelled[0] != "exit-a" {
+		t.Errorf("Expected only exit-a cancelled, got %d: %v", cancelled, canceller.cancelled)
+	}
+}
+
+// TestAwaitFill tests that a resting order is polled on the manager's clock
+// and cancelled at the timeout, or as soon as ctx is done.
+func TestAwaitFill(t *testing.T) {
+	orderer := &ScriptedOrderer{}
+	manager := NewManager(nil, nil, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	polls := 0
+	manager.now = func() time.Time { return now }
+	manager.sleep = func(d time.Duration) { polls++; now = now.Add(d) }
+
+	place := func() types.OrderResult {
+		result, _ := orderer.PlaceOrder(context.Background(), types.Order{Size: 10, TimeInForce: types.TimeInForceGTC}, false)
+		return result
+	}
+
+	fill, err := manager.awaitFill(context.Background(), orderer, place(), 5*time.Second, "entry")
+	if err != nil {
+		t.Fatalf("awaitFill failed: %v", err)
+	}
+	if polls != 5 || fill.Status != types.OrderStatusCancelled {
+		t.Errorf("Expected the order cancelled after 5 polls, got %s after %d", fill.Status, polls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	polls = 0
+	fill, err = manager.awaitFill(ctx, orderer, place(), time.Hour, "entry")
+	if err != nil {
+		t.Fatalf("awaitFill failed: %v", err)
+	}
+	if polls != 0 || fill.Status != types.OrderStatusCancelled || len(orderer.cancelled) != 2 {
+		t.Errorf("Expected the order cancelled without polling once ctx is done, got %s after %d", fill.Status, polls)
+	}
+}
+
+// TestExecuteExitWritesOffPartialLot tests that a live exit on a platform
+// trading whole contracts sells the whole ones and writes off the rest,
+// instead of leaving it open.
+func TestExecuteExitWritesOffPartialLot(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+	positionRepo := persistence.NewPositionRepository(db)
+	positionID, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "test-market-lots",
+		EntryPrice: 0.90,
+		Quantity:   10.5,
+		Side:       "YES",
+		Status:     "open",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create position: %v", err)
+	}
+
+	orderer := &MockOrderer{fillQuantity: -1, fillPrice: 0.80}
+	manager := NewManager(positionRepo, bankrollRepo, &MockVolatilityService{}, sizing.NewSizer(sizing.SizerConfig{}))
+	manager.SetPlatformOrderer("polymarket", orderer)
+	manager.SetLotSize("polymarket", 1)
+
+	result, err := manager.ExecuteExit(context.Background(), positionID, 0.80, ExitReasonStopLoss, false)
+	if err != nil {
+		t.Fatalf("ExecuteExit failed: %v", err)
+	}
+	if len(orderer.placed) != 1 || orderer.placed[0].Size != 10 {
+		t.Fatalf("Expected a sell of the 10 whole contracts, got %+v", orderer.placed)
+	}
+	if result.RemainingQuantity != 0 || math.Abs(result.RealizedPnL-(8.0-9.45)) > 1e-9 {
+		t.Errorf("Expected the half contract written off, got %+v", result)
+	}
+
+	pos, err := positionRepo.GetByID(positionID)
+	if err != nil {
+		t.Fatalf("Failed to get position: %v", err)
+	}
+	if pos.Status != persistence.PositionStatusClosed {
+		t.Errorf("Expected position closed, got %s", pos.Status)
+	}
+	bankroll, err := bankrollRepo.Get("polymarket")
+	if err != nil {
+		t.Fatalf("Failed to get bankroll: %v", err)
+	}
+	if math.Abs(bankroll.CurrentAmount-58.0) > 1e-9 {
+		t.Errorf("Expected only the sale's proceeds credited, got bankroll %v", bankroll.CurrentAmount)
+	}
+}
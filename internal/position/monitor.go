@@ -1,37 +1,153 @@
 package position
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"time"
 
+	"prediction-bot/internal/datasource"
 	"prediction-bot/internal/persistence"
-	"prediction-bot/internal/volatility"
 )
 
 // VolatilityExitThreshold is the minimum safety margin before triggering a volatility exit.
 // If the current safety margin falls below this threshold, the position should be closed.
 const VolatilityExitThreshold = 0.8
 
-// Monitor handles position monitoring for stop loss and volatility exits.
+// Stop loss types.
+const (
+	// StopLossFixed exits when price falls stop_loss_percent below entry.
+	StopLossFixed = "fixed"
+	// StopLossTrailing exits when price retraces stop_loss_percent from the
+	// highest price seen since entry.
+	StopLossTrailing = "trailing"
+)
+
+// Monitor handles position monitoring for stop loss, take profit, volatility,
+// time decay and end-of-day flattening exits.
 type Monitor struct {
-	stopLossPercent float64
+	stopLossPercent   float64
+	stopLossType      string
+	takeProfitPercent float64
+	timeDecayLead     time.Duration
+	mapper            *datasource.SymbolMapper
+	// flattenLeads maps an asset class to how long before market close its
+	// positions are flattened. Classes not listed are held to resolution.
+	flattenLeads map[string]time.Duration
+	// decayCheckpoints are the shares of a position's holding period left
+	// at which it is re-analyzed, and checkpointsDone the lowest one each
+	// position has passed.
+	decayCheckpoints []float64
+	checkpointsDone  map[int64]float64
 }
 
 // NewMonitor creates a new position monitor with the given stop loss percentage.
 func NewMonitor(stopLossPercent float64) *Monitor {
 	return &Monitor{
 		stopLossPercent: stopLossPercent,
+		stopLossType:    StopLossFixed,
+		mapper:          datasource.NewSymbolMapper(),
+		flattenLeads:    make(map[string]time.Duration),
+		checkpointsDone: make(map[int64]float64),
+	}
+}
+
+// SetStopLossPercent changes how far price may fall before the stop loss
+// exits.
+func (m *Monitor) SetStopLossPercent(stopLossPercent float64) {
+	m.stopLossPercent = stopLossPercent
+}
+
+// SetStopLossType sets whether the stop loss is measured from the entry price
+// (StopLossFixed, the default) or trails the peak price (StopLossTrailing).
+func (m *Monitor) SetStopLossType(stopLossType string) error {
+	switch stopLossType {
+	case StopLossFixed, StopLossTrailing:
+		m.stopLossType = stopLossType
+		return nil
+	default:
+		return fmt.Errorf("unknown stop loss type %q", stopLossType)
+	}
+}
+
+// SetTakeProfitPercent sets the default gain above the entry price at which
+// positions are closed. Zero, the default, disables take profit for positions
+// without their own override.
+func (m *Monitor) SetTakeProfitPercent(takeProfitPercent float64) {
+	m.takeProfitPercent = takeProfitPercent
+}
+
+// SetTimeDecayLead enables the time decay exit: within lead of market close,
+// positions whose safety margin has fallen below its value at entry are
+// closed rather than held into resolution. A non-positive lead disables it.
+func (m *Monitor) SetTimeDecayLead(lead time.Duration) {
+	m.timeDecayLead = lead
+}
+
+// SetFlattenLeadTime enables end-of-day flattening for an asset class (see
+// datasource.AssetClassCrypto and the other classes): its positions
+// are closed lead before their market closes rather than held through
+// resolution. A non-positive lead disables flattening for the class.
+func (m *Monitor) SetFlattenLeadTime(assetClass string, lead time.Duration) {
+	if lead <= 0 {
+		delete(m.flattenLeads, assetClass)
+		return
 	}
+	m.flattenLeads[assetClass] = lead
 }
 
 // CheckStopLoss checks if a position should exit due to stop loss.
 // Returns true if the current price is strictly below the stop loss threshold.
-// Threshold = entry_price * (1 - stop_loss_percent)
+// Threshold = entry_price * (1 - stop_loss_percent) for a fixed stop, or
+// peak_price * (1 - stop_loss_percent) for a trailing stop, where the peak
+// is the highest of the entry price, the stored peak and the current price.
 func (m *Monitor) CheckStopLoss(position *persistence.Position, currentPrice float64) bool {
-	threshold := position.EntryPrice * (1 - m.stopLossPercent)
+	reference := position.EntryPrice
+	if m.stopLossType == StopLossTrailing {
+		reference = math.Max(reference, math.Max(position.PeakPrice, currentPrice))
+	}
+	threshold := reference * (1 - m.stopLossPercent)
 	return currentPrice < threshold
 }
 
+// Trailing reports whether the stop loss trails the peak price, so callers
+// need to record new peaks.
+func (m *Monitor) Trailing() bool {
+	return m.stopLossType == StopLossTrailing
+}
+
+// CheckTakeProfit checks if a position should exit to lock in gains.
+// Returns true if the current price is at or above the take profit target.
+// Target = entry_price * (1 + take_profit_percent), using the position's own
+// take profit if set and the monitor's default otherwise. A non-positive
+// take profit never triggers.
+func (m *Monitor) CheckTakeProfit(position *persistence.Position, currentPrice float64) bool {
+	takeProfitPercent := m.takeProfitPercent
+	if position.TakeProfitPercent != nil {
+		takeProfitPercent = *position.TakeProfitPercent
+	}
+	if takeProfitPercent <= 0 {
+		return false
+	}
+	target := position.EntryPrice * (1 + takeProfitPercent)
+	return currentPrice >= target
+}
+
+// CheckFlatten checks if a position should be closed ahead of its market's
+// close. Returns true if flattening is enabled for the position's asset class
+// and now is within the lead time of the close. Positions with an unknown
+// close time are never flattened.
+func (m *Monitor) CheckFlatten(position *persistence.Position, now time.Time) bool {
+	if position.MarketCloseTime == nil {
+		return false
+	}
+	lead, ok := m.flattenLeads[m.mapper.AssetClass(position.Asset)]
+	if !ok {
+		return false
+	}
+	return !now.Before(position.MarketCloseTime.Add(-lead))
+}
+
 // CheckVolatilityExit checks if a position should exit due to volatility changes.
 // Returns true if the current safety margin is strictly below the exit threshold (0.8).
 //
@@ -42,24 +158,58 @@ func (m *Monitor) CheckStopLoss(position *persistence.Position, currentPrice flo
 //
 // A safety margin below 0.8 indicates that volatility has increased or price has moved
 // unfavorably, making the position too risky to hold.
-func (m *Monitor) CheckVolatilityExit(position *persistence.Position, analyzer VolatilityAnalyzer, timeToClose time.Duration) (bool, error) {
-	// Convert direction string to volatility.Direction
-	direction := volatility.DirectionAbove
-	if position.Direction == "below" {
-		direction = volatility.DirectionBelow
+//
+// Fades are never checked: they are entered with low safety margins by design.
+func (m *Monitor) CheckVolatilityExit(ctx context.Context, position *persistence.Position, analyzer VolatilityAnalyzer, timeToClose time.Duration) (bool, error) {
+	if position.TradeStrategy == TradeStrategyFade {
+		return false, nil
 	}
 
-	// Re-analyze the asset with current data
-	result, err := analyzer.AnalyzeAsset(
+	safetyMargin, err := m.currentSafetyMargin(ctx, position, analyzer, timeToClose)
+	if err != nil {
+		return false, fmt.Errorf("check volatility exit: %w", err)
+	}
+
+	// Trigger exit if safety margin is strictly below the threshold
+	return safetyMargin < VolatilityExitThreshold, nil
+}
+
+// CheckTimeDecayExit checks if a position should exit early as its market
+// nears close. Returns true if the time decay exit is enabled, the market
+// closes within the lead time, and the current safety margin is strictly
+// below the safety margin at entry. Positions with an unknown close time are
+// never checked.
+func (m *Monitor) CheckTimeDecayExit(ctx context.Context, position *persistence.Position, analyzer VolatilityAnalyzer, now time.Time) (bool, error) {
+	if m.timeDecayLead <= 0 || position.MarketCloseTime == nil {
+		return false, nil
+	}
+	timeToClose := position.MarketCloseTime.Sub(now)
+	if timeToClose > m.timeDecayLead {
+		return false, nil
+	}
+
+	safetyMargin, err := m.currentSafetyMargin(ctx, position, analyzer, timeToClose)
+	if err != nil {
+		return false, fmt.Errorf("check time decay exit: %w", err)
+	}
+	return safetyMargin < position.SafetyMarginAtEntry, nil
+}
+
+// currentSafetyMargin re-analyzes a position's asset with current data and
+// returns its safety margin.
+func (m *Monitor) currentSafetyMargin(ctx context.Context, position *persistence.Position, analyzer VolatilityAnalyzer, timeToClose time.Duration) (float64, error) {
+	result, err := analyzeStrike(
+		ctx,
+		analyzer,
 		position.Asset,
 		position.Strike,
-		direction,
+		position.StrikeUpper,
+		analysisDirection(position),
+		position.Side,
 		timeToClose,
 	)
 	if err != nil {
-		return false, fmt.Errorf("check volatility exit: %w", err)
+		return 0, err
 	}
-
-	// Trigger exit if safety margin is strictly below the threshold
-	return result.SafetyMargin < VolatilityExitThreshold, nil
+	return result.SafetyMargin, nil
 }
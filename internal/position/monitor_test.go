@@ -1,10 +1,12 @@
 package position
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"prediction-bot/internal/datasource"
 	"prediction-bot/internal/persistence"
 	"prediction-bot/internal/volatility"
 )
@@ -81,6 +83,82 @@ func TestCheckStopLoss_JustBelowThreshold(t *testing.T) {
 	}
 }
 
+func TestCheckStopLoss_Trailing(t *testing.T) {
+	monitor := NewMonitor(0.10)
+	if err := monitor.SetStopLossType(StopLossTrailing); err != nil {
+		t.Fatalf("SetStopLossType failed: %v", err)
+	}
+
+	// Entry 0.80, peak 0.95: trailing threshold is 0.95 * 0.90 = 0.855
+	position := &persistence.Position{
+		EntryPrice: 0.80,
+		PeakPrice:  0.95,
+		Status:     "open",
+	}
+
+	if !monitor.CheckStopLoss(position, 0.85) {
+		t.Error("expected trailing stop to trigger at 0.85 after a 0.95 peak")
+	}
+	if monitor.CheckStopLoss(position, 0.86) {
+		t.Error("expected trailing stop not to trigger at 0.86")
+	}
+
+	// A current price above the stored peak raises the threshold with it
+	if monitor.CheckStopLoss(position, 0.97) {
+		t.Error("expected no trigger at a new peak")
+	}
+
+	// The same position is far from a fixed stop at 0.72
+	fixed := NewMonitor(0.10)
+	if fixed.CheckStopLoss(position, 0.85) {
+		t.Error("expected fixed stop not to trigger at 0.85")
+	}
+}
+
+func TestSetStopLossType_RejectsUnknownType(t *testing.T) {
+	if err := NewMonitor(0.10).SetStopLossType("ratchet"); err == nil {
+		t.Error("expected error for unknown stop loss type")
+	}
+}
+
+func TestCheckTakeProfit(t *testing.T) {
+	position := &persistence.Position{
+		EntryPrice: 0.80,
+		Status:     "open",
+	}
+
+	// Take profit is disabled by default
+	monitor := NewMonitor(0.15)
+	if monitor.CheckTakeProfit(position, 0.99) {
+		t.Error("expected no take profit when disabled")
+	}
+
+	// Target: 0.80 * (1 + 0.10) = 0.88
+	monitor.SetTakeProfitPercent(0.10)
+	if monitor.CheckTakeProfit(position, 0.87) {
+		t.Error("expected no take profit below 0.88")
+	}
+	if !monitor.CheckTakeProfit(position, 0.89) {
+		t.Error("expected take profit above 0.88")
+	}
+
+	// A per-position override replaces the default: 0.80 * 1.20 = 0.96
+	override := 0.20
+	position.TakeProfitPercent = &override
+	if monitor.CheckTakeProfit(position, 0.89) {
+		t.Error("expected override to hold the position at 0.89")
+	}
+	if !monitor.CheckTakeProfit(position, 0.97) {
+		t.Error("expected override to trigger above 0.96")
+	}
+
+	// An override of zero disables take profit for the position
+	override = 0
+	if monitor.CheckTakeProfit(position, 0.99) {
+		t.Error("expected zero override to disable take profit")
+	}
+}
+
 func TestCheckStopLoss_VariousStopLossPercents(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -150,7 +228,7 @@ type MockVolatilityAnalyzer struct {
 	err          error
 }
 
-func (m *MockVolatilityAnalyzer) AnalyzeAsset(asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+func (m *MockVolatilityAnalyzer) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
 	if m.err != nil {
 		return volatility.ServiceResult{}, m.err
 	}
@@ -175,7 +253,7 @@ func TestCheckVolatilityExit_TriggerOnLowSafetyMargin(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 24*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 24*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -199,7 +277,7 @@ func TestCheckVolatilityExit_NoTriggerOnGoodSafetyMargin(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 24*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 24*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -222,7 +300,7 @@ func TestCheckVolatilityExit_NoTriggerOnValidSafetyMargin(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 12*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 12*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -245,7 +323,7 @@ func TestCheckVolatilityExit_ExactlyAtThreshold(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 24*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 24*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -268,7 +346,7 @@ func TestCheckVolatilityExit_JustBelowThreshold(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 24*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 24*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -291,7 +369,7 @@ func TestCheckVolatilityExit_NegativeSafetyMargin(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 24*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 24*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -314,7 +392,7 @@ func TestCheckVolatilityExit_ErrorFromAnalyzer(t *testing.T) {
 		Status:    "open",
 	}
 
-	_, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 24*time.Hour)
+	_, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 24*time.Hour)
 	if err == nil {
 		t.Errorf("CheckVolatilityExit: expected error from analyzer, got nil")
 	}
@@ -334,7 +412,7 @@ func TestCheckVolatilityExit_DirectionBelow(t *testing.T) {
 		Status:    "open",
 	}
 
-	triggered, err := monitor.CheckVolatilityExit(position, mockAnalyzer, 12*time.Hour)
+	triggered, err := monitor.CheckVolatilityExit(context.Background(), position, mockAnalyzer, 12*time.Hour)
 	if err != nil {
 		t.Fatalf("CheckVolatilityExit returned error: %v", err)
 	}
@@ -342,3 +420,83 @@ func TestCheckVolatilityExit_DirectionBelow(t *testing.T) {
 		t.Errorf("CheckVolatilityExit: expected true for safety_margin=0.5, got false")
 	}
 }
+
+func TestCheckFlatten_PerAssetClass(t *testing.T) {
+	monitor := NewMonitor(0.15)
+	monitor.SetFlattenLeadTime(datasource.AssetClassStock, 15*time.Minute)
+
+	closeTime := time.Date(2026, 1, 2, 21, 0, 0, 0, time.UTC)
+	stock := &persistence.Position{Asset: "SPY", MarketCloseTime: &closeTime}
+	crypto := &persistence.Position{Asset: "BTC", MarketCloseTime: &closeTime}
+	unknownClose := &persistence.Position{Asset: "SPY"}
+
+	tests := []struct {
+		name     string
+		position *persistence.Position
+		now      time.Time
+		want     bool
+	}{
+		{"stock before lead time", stock, closeTime.Add(-16 * time.Minute), false},
+		{"stock within lead time", stock, closeTime.Add(-15 * time.Minute), true},
+		{"crypto not enabled", crypto, closeTime.Add(-time.Minute), false},
+		{"unknown close time", unknownClose, closeTime, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monitor.CheckFlatten(tt.position, tt.now); got != tt.want {
+				t.Errorf("CheckFlatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A zero lead time disables the class again
+	monitor.SetFlattenLeadTime(datasource.AssetClassStock, 0)
+	if monitor.CheckFlatten(stock, closeTime) {
+		t.Error("CheckFlatten() should be false after disabling the asset class")
+	}
+}
+
+func TestCheckTimeDecayExit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	closeTime := now.Add(3 * time.Hour)
+
+	tests := []struct {
+		name         string
+		lead         time.Duration
+		closeTime    *time.Time
+		safetyMargin float64
+		want         bool
+	}{
+		{"disabled", 0, &closeTime, 1.2, false},
+		{"unknown close time", 4 * time.Hour, nil, 1.2, false},
+		{"outside lead time", 2 * time.Hour, &closeTime, 1.2, false},
+		{"margin deteriorated", 4 * time.Hour, &closeTime, 1.2, true},
+		{"margin held", 4 * time.Hour, &closeTime, 2.0, false},
+		{"margin improved", 4 * time.Hour, &closeTime, 2.5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := NewMonitor(0.15)
+			monitor.SetTimeDecayLead(tt.lead)
+
+			position := &persistence.Position{
+				Asset:               "BTC",
+				Strike:              100000,
+				Direction:           "above",
+				Status:              "open",
+				SafetyMarginAtEntry: 2.0,
+				MarketCloseTime:     tt.closeTime,
+			}
+
+			got, err := monitor.CheckTimeDecayExit(context.Background(), position, &MockVolatilityAnalyzer{safetyMargin: tt.safetyMargin}, now)
+			if err != nil {
+				t.Fatalf("CheckTimeDecayExit returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckTimeDecayExit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
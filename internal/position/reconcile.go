@@ -0,0 +1,230 @@
+package position
+
+import (
+	"fmt"
+	"math"
+
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReconcileResult counts what a reconciliation repaired and found.
+type ReconcileResult struct {
+	Opened    int // Pending entries already debited, moved to open
+	Abandoned int // Dry-run entries interrupted before their fill was recorded, closed unfilled
+	Released  int // Dry-run exits interrupted before they were recorded, returned to open
+	Flagged   int // Live entries and exits interrupted midway, marked error to be checked against the platform
+	Restored  int // Positions in error the platform holds, reopened with the quantity held
+	Dropped   int // Positions in error the platform doesn't hold, closed
+	// Uncredited are closed positions whose exit proceeds were never
+	// credited. The exit fee isn't known, so they aren't repaired.
+	Uncredited []int64
+}
+
+// Repaired returns how many positions the reconciliation changed.
+func (r ReconcileResult) Repaired() int {
+	return r.Opened + r.Abandoned + r.Released + r.Flagged + r.Restored + r.Dropped
+}
+
+// String summarizes what a reconciliation repaired and found.
+func (r ReconcileResult) String() string {
+	return fmt.Sprintf("%d opened, %d abandoned, %d released, %d flagged, %d restored, %d dropped, %d exits uncredited",
+		r.Opened, r.Abandoned, r.Released, r.Flagged, r.Restored, r.Dropped, len(r.Uncredited))
+}
+
+// Reconciler repairs positions a crash left midway through an entry or
+// exit. Entries and exits are written in one transaction each, so only
+// those interrupted while their orders worked, or written by versions that
+// didn't, are left inconsistent. It must run before trading starts: an
+// entry or exit in progress looks interrupted.
+type Reconciler struct {
+	positions *persistence.PositionRepository
+	bankroll  *persistence.BankrollRepository
+	dryRun    bool
+	// holdings lists what each platform holds, to resolve positions in
+	// error against
+	holdings map[string]PositionLister
+}
+
+// NewReconciler creates a Reconciler. In dry-run no order was placed on a
+// platform, so interrupted trades are rolled back; live, what filled is
+// unknown, so they are marked error instead.
+func NewReconciler(positions *persistence.PositionRepository, bankroll *persistence.BankrollRepository, dryRun bool) *Reconciler {
+	return &Reconciler{
+		positions: positions,
+		bankroll:  bankroll,
+		dryRun:    dryRun,
+		holdings:  make(map[string]PositionLister),
+	}
+}
+
+// SetPositionLister sets the client the positions a platform holds are
+// fetched with. Live, positions in error on that platform, including those
+// just flagged, are then resolved against what it holds.
+func (r *Reconciler) SetPositionLister(platform string, lister PositionLister) {
+	r.holdings[platform] = lister
+}
+
+// Run repairs every interrupted entry and exit and finds the exits never
+// credited.
+func (r *Reconciler) Run() (ReconcileResult, error) {
+	var result ReconcileResult
+
+	pending, err := r.positions.GetByStatus(persistence.PositionStatusPendingEntry)
+	if err != nil {
+		return result, err
+	}
+	for _, pos := range pending {
+		debited, err := r.bankroll.HasApplied(persistence.PositionKey(pos.ID, persistence.BankrollOpEntry))
+		if err != nil {
+			return result, err
+		}
+		switch {
+		case debited:
+			// Only the move to open was lost
+			if err := r.positions.Transition(pos, persistence.PositionStatusOpen); err != nil {
+				return result, fmt.Errorf("open position %d: %w", pos.ID, err)
+			}
+			result.Opened++
+		case r.dryRun:
+			if err := r.positions.Close(pos.ID, pos.EntryPrice, orders.ExitReasonUnfilled, 0); err != nil {
+				return result, fmt.Errorf("abandon position %d: %w", pos.ID, err)
+			}
+			result.Abandoned++
+		default:
+			if err := r.positions.Transition(pos, persistence.PositionStatusError); err != nil {
+				return result, fmt.Errorf("flag position %d: %w", pos.ID, err)
+			}
+			result.Flagged++
+		}
+		log.Warn().Int64("position_id", pos.ID).Str("market", pos.MarketID).Bool("debited", debited).
+			Msg("Reconciled entry interrupted before it was recorded")
+	}
+
+	exiting, err := r.positions.GetByStatus(persistence.PositionStatusExiting)
+	if err != nil {
+		return result, err
+	}
+	for _, pos := range exiting {
+		if r.dryRun {
+			if err := r.positions.Transition(pos, persistence.PositionStatusOpen); err != nil {
+				return result, fmt.Errorf("release position %d: %w", pos.ID, err)
+			}
+			result.Released++
+		} else {
+			if err := r.positions.Transition(pos, persistence.PositionStatusError); err != nil {
+				return result, fmt.Errorf("flag position %d: %w", pos.ID, err)
+			}
+			result.Flagged++
+		}
+		log.Warn().Int64("position_id", pos.ID).Str("market", pos.MarketID).
+			Msg("Reconciled exit interrupted before it was recorded")
+	}
+
+	if !r.dryRun {
+		if err := r.resolveErrors(&result); err != nil {
+			return result, err
+		}
+	}
+
+	if result.Uncredited, err = r.positions.GetUncreditedExits(); err != nil {
+		return result, err
+	}
+	for _, id := range result.Uncredited {
+		log.Warn().Int64("position_id", id).Msg("Closed position's exit proceeds were never credited to the bankroll")
+	}
+
+	return result, nil
+}
+
+// resolveErrors checks the positions in error on the platforms with a
+// PositionLister against what they hold. A position held is reopened with
+// the quantity held, net of the platform's other open positions on the same
+// side, and its cost debited if it wasn't. One not held is closed: unfilled
+// if its cost was never debited, not held otherwise, leaving its proceeds
+// uncredited. A platform whose holdings can't be fetched is left in error.
+func (r *Reconciler) resolveErrors(result *ReconcileResult) error {
+	flagged, err := r.positions.GetByStatus(persistence.PositionStatusError)
+	if err != nil {
+		return err
+	}
+
+	held := make(map[string][]types.Position)
+	unavailable := make(map[string]bool)
+	for _, pos := range flagged {
+		lister := r.holdings[pos.Platform]
+		if lister == nil || unavailable[pos.Platform] {
+			continue
+		}
+		holdings, fetched := held[pos.Platform]
+		if !fetched {
+			holdings, err = lister.GetPositions()
+			if err != nil {
+				log.Warn().Err(err).Str("platform", pos.Platform).Msg("Failed to fetch positions to reconcile against")
+				unavailable[pos.Platform] = true
+				continue
+			}
+			held[pos.Platform] = holdings
+		}
+
+		quantity, err := r.unaccounted(pos, holdings)
+		if err != nil {
+			return err
+		}
+		if err := r.positions.Transition(pos, persistence.PositionStatusReconciling); err != nil {
+			return fmt.Errorf("reconcile position %d: %w", pos.ID, err)
+		}
+		debited, err := r.bankroll.HasApplied(persistence.PositionKey(pos.ID, persistence.BankrollOpEntry))
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case quantity > 1e-9:
+			pos.Quantity = quantity
+			if err := r.positions.OpenEntry(pos, types.Cost(pos.EntryPrice, quantity), nil); err != nil {
+				return fmt.Errorf("restore position %d: %w", pos.ID, err)
+			}
+			result.Restored++
+		case debited:
+			if err := r.positions.Close(pos.ID, pos.EntryPrice, ExitReasonNotHeld, 0); err != nil {
+				return fmt.Errorf("drop position %d: %w", pos.ID, err)
+			}
+			result.Dropped++
+		default:
+			if err := r.positions.Close(pos.ID, pos.EntryPrice, orders.ExitReasonUnfilled, 0); err != nil {
+				return fmt.Errorf("drop position %d: %w", pos.ID, err)
+			}
+			result.Dropped++
+		}
+		log.Warn().Int64("position_id", pos.ID).Str("market", pos.MarketID).
+			Float64("held", quantity).Bool("debited", debited).
+			Msg("Reconciled position in error against the platform")
+	}
+	return nil
+}
+
+// unaccounted returns how many contracts on pos's market, outcome and side
+// the platform holds beyond the other open positions recorded on them.
+func (r *Reconciler) unaccounted(pos *persistence.Position, holdings []types.Position) (float64, error) {
+	var quantity float64
+	for _, h := range holdings {
+		if h.MarketTicker == pos.MarketID && h.Outcome == pos.Outcome && heldSide(h) == pos.Side {
+			quantity += math.Abs(h.Quantity)
+		}
+	}
+
+	recorded, err := r.positions.GetOpenByPlatform(pos.Platform)
+	if err != nil {
+		return 0, err
+	}
+	for _, other := range recorded {
+		if other.MarketID == pos.MarketID && other.Outcome == pos.Outcome && other.Side == pos.Side {
+			quantity -= other.Quantity
+		}
+	}
+	return quantity, nil
+}
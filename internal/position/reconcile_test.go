@@ -0,0 +1,195 @@
+package position
+
+import (
+	"testing"
+
+	"prediction-bot/internal/orders"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// createInterruptedPositions records what a crash leaves behind: an entry
+// debited but not opened, an entry whose order was working, an exit claimed
+// but not recorded and a close never credited. Returns their IDs in that
+// order.
+func createInterruptedPositions(t *testing.T, positionRepo *persistence.PositionRepository, bankrollRepo *persistence.BankrollRepository) []int64 {
+	t.Helper()
+
+	if err := bankrollRepo.Initialize("polymarket", 1000); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+
+	var ids []int64
+	for _, status := range []string{
+		persistence.PositionStatusPendingEntry,
+		persistence.PositionStatusPendingEntry,
+		persistence.PositionStatusExiting,
+		persistence.PositionStatusOpen,
+	} {
+		id, err := positionRepo.Create(&persistence.Position{
+			Platform:   "polymarket",
+			MarketID:   "0xmarket",
+			EntryPrice: 0.9,
+			Quantity:   10,
+			Side:       "YES",
+			Status:     status,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create position: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	for _, id := range []int64{ids[0], ids[3]} {
+		if _, err := bankrollRepo.AddToBalanceOnce("polymarket", types.Dollars(-9), persistence.PositionKey(id, persistence.BankrollOpEntry)); err != nil {
+			t.Fatalf("Failed to debit entry: %v", err)
+		}
+	}
+	if err := positionRepo.Close(ids[3], 1.0, ExitReasonResolved, types.Dollars(1)); err != nil {
+		t.Fatalf("Failed to close position: %v", err)
+	}
+	return ids
+}
+
+func TestReconciler_DryRunRollsBackInterruptedTrades(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positionRepo := persistence.NewPositionRepository(db)
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	ids := createInterruptedPositions(t, positionRepo, bankrollRepo)
+
+	result, err := NewReconciler(positionRepo, bankrollRepo, true).Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Opened != 1 || result.Abandoned != 1 || result.Released != 1 || result.Flagged != 0 {
+		t.Errorf("Unexpected result %s", result)
+	}
+	if len(result.Uncredited) != 1 || result.Uncredited[0] != ids[3] {
+		t.Errorf("Expected position %d uncredited, got %v", ids[3], result.Uncredited)
+	}
+
+	for i, want := range []string{
+		persistence.PositionStatusOpen,
+		persistence.PositionStatusClosed,
+		persistence.PositionStatusOpen,
+	} {
+		pos, _ := positionRepo.GetByID(ids[i])
+		if pos.Status != want {
+			t.Errorf("Expected position %d %s, got %s", ids[i], want, pos.Status)
+		}
+	}
+	abandoned, _ := positionRepo.GetByID(ids[1])
+	if abandoned.ExitReason == nil || *abandoned.ExitReason != orders.ExitReasonUnfilled {
+		t.Errorf("Expected abandoned entry closed unfilled, got %v", abandoned.ExitReason)
+	}
+
+	// Nothing is left to repair
+	again, err := NewReconciler(positionRepo, bankrollRepo, true).Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if again.Repaired() != 0 {
+		t.Errorf("Expected nothing repaired twice, got %s", again)
+	}
+}
+
+func TestReconciler_LiveFlagsInterruptedTrades(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positionRepo := persistence.NewPositionRepository(db)
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	ids := createInterruptedPositions(t, positionRepo, bankrollRepo)
+
+	result, err := NewReconciler(positionRepo, bankrollRepo, false).Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Opened != 1 || result.Flagged != 2 || result.Abandoned != 0 || result.Released != 0 {
+		t.Errorf("Unexpected result %s", result)
+	}
+
+	// The platform may have filled the working orders
+	for _, id := range ids[1:3] {
+		pos, _ := positionRepo.GetByID(id)
+		if pos.Status != persistence.PositionStatusError {
+			t.Errorf("Expected position %d marked error, got %s", id, pos.Status)
+		}
+	}
+}
+
+func TestReconciler_LiveResolvesErrorsAgainstPlatform(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	positionRepo := persistence.NewPositionRepository(db)
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 1000); err != nil {
+		t.Fatalf("Failed to initialize bankroll: %v", err)
+	}
+
+	// An open position and three in error: one the platform holds part
+	// of, one it sold after the entry was debited and one never filled
+	var ids []int64
+	for _, p := range []struct {
+		market string
+		status string
+	}{
+		{"0xheld", persistence.PositionStatusOpen},
+		{"0xheld", persistence.PositionStatusError},
+		{"0xsold", persistence.PositionStatusError},
+		{"0xunfilled", persistence.PositionStatusError},
+	} {
+		id, err := positionRepo.Create(&persistence.Position{
+			Platform:   "polymarket",
+			MarketID:   p.market,
+			EntryPrice: 0.9,
+			Quantity:   10,
+			Side:       "YES",
+			Status:     p.status,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create position: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := bankrollRepo.AddToBalanceOnce("polymarket", types.Dollars(-9), persistence.PositionKey(ids[2], persistence.BankrollOpEntry)); err != nil {
+		t.Fatalf("Failed to debit entry: %v", err)
+	}
+
+	reconciler := NewReconciler(positionRepo, bankrollRepo, false)
+	reconciler.SetPositionLister("polymarket", &StaticPositions{positions: []types.Position{
+		{MarketTicker: "0xheld", Quantity: 16},
+		{MarketTicker: "0xsold", Quantity: -5}, // The other side
+	}})
+	result, err := reconciler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Restored != 1 || result.Dropped != 2 {
+		t.Errorf("Unexpected result %s", result)
+	}
+
+	// The held position keeps what the open one doesn't account for, its
+	// cost debited
+	held, _ := positionRepo.GetByID(ids[1])
+	if held.Status != persistence.PositionStatusOpen || held.Quantity != 6 {
+		t.Errorf("Expected 6 contracts reopened, got %s with %v", held.Status, held.Quantity)
+	}
+	if debited, _ := bankrollRepo.HasApplied(persistence.PositionKey(ids[1], persistence.BankrollOpEntry)); !debited {
+		t.Error("Expected the restored position's cost debited")
+	}
+
+	for id, want := range map[int64]string{ids[2]: ExitReasonNotHeld, ids[3]: orders.ExitReasonUnfilled} {
+		pos, _ := positionRepo.GetByID(id)
+		if pos.Status != persistence.PositionStatusClosed || pos.ExitReason == nil || *pos.ExitReason != want {
+			t.Errorf("Expected position %d closed %s, got %s %v", id, want, pos.Status, pos.ExitReason)
+		}
+	}
+	// The sold position's proceeds are unknown
+	if len(result.Uncredited) != 1 || result.Uncredited[0] != ids[2] {
+		t.Errorf("Expected position %d uncredited, got %v", ids[2], result.Uncredited)
+	}
+}
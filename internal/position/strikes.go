@@ -0,0 +1,113 @@
+package position
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/volatility"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SkipReasonStrikeNotSelected is the skip reason for eligible markets that
+// SelectStrikes left out in favor of a better strike of their event.
+const SkipReasonStrikeNotSelected = "strike_not_selected"
+
+// SetBestStrikePerEvent configures whether SelectStrikes keeps only the
+// best-valued strike of each event.
+func (m *Manager) SetBestStrikePerEvent(enabled bool) {
+	m.bestStrike = enabled
+}
+
+// SelectStrikes returns the eligible markets to process for entry. With the
+// best strike per event enabled, only one market of each event (see
+// scanner.GroupByEvent) is kept: the one whose bet side the volatility model
+// prices furthest above the market's price for it. Otherwise, or if the
+// model can't price one of an event's markets, markets are returned as is.
+func (m *Manager) SelectStrikes(ctx context.Context, markets []scanner.EligibleMarket) []scanner.EligibleMarket {
+	if !m.bestStrike {
+		return markets
+	}
+
+	var selected []scanner.EligibleMarket
+	for _, event := range scanner.GroupByEvent(markets) {
+		if len(event) == 1 {
+			selected = append(selected, event...)
+			continue
+		}
+
+		best, bestEdge := -1, 0.0
+		for i, market := range event {
+			edge, err := m.modelEdge(ctx, market)
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("market", market.Market.ID).
+					Msg("Cannot price strike, keeping every strike of its event")
+				best = -1
+				break
+			}
+			if best < 0 || edge > bestEdge {
+				best, bestEdge = i, edge
+			}
+		}
+		if best < 0 {
+			selected = append(selected, event...)
+			continue
+		}
+
+		log.Debug().
+			Str("event", scanner.EventKey(event[best])).
+			Str("market", event[best].Market.ID).
+			Float64("edge", bestEdge).
+			Int("strikes", len(event)).
+			Msg("Selected best strike of event")
+		selected = append(selected, event[best])
+	}
+	return selected
+}
+
+// modelEdge returns how far the model probability of a market's bet side is
+// above the market's price for it.
+func (m *Manager) modelEdge(ctx context.Context, market scanner.EligibleMarket) (float64, error) {
+	timeToClose := market.ResolutionTime().Sub(m.now())
+	if timeToClose < 0 {
+		timeToClose = 0
+	}
+
+	yes, err := m.modelProbability(ctx, market.Parsed, timeToClose)
+	if err != nil {
+		return 0, err
+	}
+	if strings.EqualFold(market.BetSide, "NO") {
+		return (1 - yes) - (1 - market.Probability), nil
+	}
+	return yes - market.Probability, nil
+}
+
+// modelProbability returns the model probability that a market resolves
+// YES: that the price ends above or below its strike, or inside a bracket.
+func (m *Manager) modelProbability(ctx context.Context, parsed *scanner.ParsedMarket, timeToClose time.Duration) (float64, error) {
+	direction := volatility.DirectionAbove
+	if parsed.Direction == "below" {
+		direction = volatility.DirectionBelow
+	}
+
+	result, err := m.volatility.AnalyzeAsset(ctx, parsed.Asset, parsed.Strike, direction, timeToClose)
+	if err != nil {
+		return 0, err
+	}
+	probability := volatility.WinProbability(result.SafetyMargin)
+	if parsed.Direction != scanner.DirectionBetween {
+		return probability, nil
+	}
+
+	// Above the lower bound but not above the upper one
+	upper, err := m.volatility.AnalyzeAsset(ctx, parsed.Asset, parsed.StrikeUpper, volatility.DirectionAbove, timeToClose)
+	if err != nil {
+		return 0, err
+	}
+	return probability - volatility.WinProbability(upper.SafetyMargin), nil
+}
@@ -0,0 +1,61 @@
+package position
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/scanner"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+func TestSelectStrikes(t *testing.T) {
+	friday := time.Now().Add(24 * time.Hour)
+	eligible := func(id, asset string, strike, probability float64) scanner.EligibleMarket {
+		return scanner.EligibleMarket{
+			Market:      types.Market{ID: id, Platform: "kalshi", EndDate: friday},
+			Parsed:      &scanner.ParsedMarket{Asset: asset, Strike: strike, Direction: "above"},
+			Probability: probability,
+			BetSide:     "YES",
+		}
+	}
+	markets := []scanner.EligibleMarket{
+		eligible("btc-95k", "BTC", 95000, 0.95),   // Model 99.9%: 5 points of edge
+		eligible("eth-4k", "ETH", 4000, 0.90),     // Alone in its event
+		eligible("btc-100k", "BTC", 100000, 0.85), // Model 96.4%: 11 points
+	}
+
+	service := &StrikeVolatilityService{margins: map[volatility.Direction]map[float64]float64{
+		volatility.DirectionAbove: {95000: 1.5, 100000: 0.9, 4000: 1.0},
+	}}
+	manager := NewManager(nil, nil, service, nil)
+
+	if selected := manager.SelectStrikes(context.Background(), markets); len(selected) != 3 {
+		t.Errorf("expected every strike while disabled, got %d", len(selected))
+	}
+
+	manager.SetBestStrikePerEvent(true)
+	selected := manager.SelectStrikes(context.Background(), markets)
+	if len(selected) != 2 || selected[0].Market.ID != "btc-100k" || selected[1].Market.ID != "eth-4k" {
+		t.Errorf("expected btc-100k and eth-4k, got %+v", selected)
+	}
+}
+
+func TestModelProbability_Bracket(t *testing.T) {
+	// 97.7% above 95,000 and 15.9% above 100,000
+	service := &StrikeVolatilityService{margins: map[volatility.Direction]map[float64]float64{
+		volatility.DirectionAbove: {95000: 1.0, 100000: -0.5},
+	}}
+	manager := NewManager(nil, nil, service, nil)
+
+	probability, err := manager.modelProbability(context.Background(), &scanner.ParsedMarket{
+		Asset: "BTC", Strike: 95000, StrikeUpper: 100000, Direction: scanner.DirectionBetween,
+	}, time.Hour)
+	if err != nil {
+		t.Fatalf("modelProbability failed: %v", err)
+	}
+	if probability < 0.818 || probability > 0.819 {
+		t.Errorf("expected 81.8%% inside the bracket, got %v", probability)
+	}
+}
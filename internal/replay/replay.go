@@ -0,0 +1,229 @@
+// Package replay serves recorded snapshots to the bot as if they were live,
+// at an accelerated speed, so the real scan and monitor loop can be run over
+// a production incident deterministically.
+//
+// A Replay maps the wall clock onto the recorded timeline: once started,
+// each wall second advances the replay by speed seconds from the first
+// snapshot. Its platforms list the markets of the latest snapshot at or
+// before the replay time, and its analyzer the underlying prices recorded
+// up to it.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+// Replay serves snapshots along an accelerated clock.
+type Replay struct {
+	snapshots []backtest.Snapshot
+	speed     float64
+	wall      func() time.Time
+
+	mu       sync.Mutex
+	started  time.Time
+	next     int
+	markets  map[string]types.Market
+	analyzer *backtest.ReplayAnalyzer
+	done     chan struct{}
+}
+
+// New creates a replay of snapshots, sorted by time, at speed times real
+// time.
+func New(snapshots []backtest.Snapshot, speed float64) (*Replay, error) {
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots to replay")
+	}
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay speed must be positive, got %v", speed)
+	}
+	return &Replay{
+		snapshots: snapshots,
+		speed:     speed,
+		wall:      time.Now,
+		markets:   make(map[string]types.Market),
+		analyzer:  backtest.NewReplayAnalyzer(),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Start starts the replay clock at the first snapshot. Done is closed once
+// it passes the last.
+func (r *Replay) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.started.IsZero() {
+		return
+	}
+	r.started = r.wall()
+	time.AfterFunc(r.Scale(r.End().Sub(r.snapshots[0].Timestamp)), func() { close(r.done) })
+}
+
+// Done is closed once the replay passes the last snapshot.
+func (r *Replay) Done() <-chan struct{} {
+	return r.done
+}
+
+// End returns the time of the last snapshot.
+func (r *Replay) End() time.Time {
+	return r.snapshots[len(r.snapshots)-1].Timestamp
+}
+
+// Now returns the replay time: the first snapshot's until Start, then
+// advancing speed times as fast as the wall clock, up to the last
+// snapshot's.
+func (r *Replay) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.now()
+}
+
+// now returns the replay time. r.mu must be held.
+func (r *Replay) now() time.Time {
+	first := r.snapshots[0].Timestamp
+	if r.started.IsZero() {
+		return first
+	}
+	elapsed := time.Duration(float64(r.wall().Sub(r.started)) * r.speed)
+	if now := first.Add(elapsed); now.Before(r.End()) {
+		return now
+	}
+	return r.End()
+}
+
+// Scale returns how long d of replay time lasts on the wall clock, at least
+// a millisecond so it can set a ticker.
+func (r *Replay) Scale(d time.Duration) time.Duration {
+	scaled := time.Duration(float64(d) / r.speed)
+	if scaled < time.Millisecond {
+		return time.Millisecond
+	}
+	return scaled
+}
+
+// current advances the replay to the latest snapshot at or before the
+// replay time, recording the prices and markets of those passed, and
+// returns it. r.mu must be held.
+func (r *Replay) current() backtest.Snapshot {
+	now := r.now()
+	for r.next < len(r.snapshots) && (r.next == 0 || !r.snapshots[r.next].Timestamp.After(now)) {
+		snap := r.snapshots[r.next]
+		r.analyzer.Record(snap)
+		for _, m := range snap.Markets {
+			r.markets[m.Platform+":"+m.ID] = m
+		}
+		r.next++
+	}
+	return r.snapshots[r.next-1]
+}
+
+// Platforms returns a platform for each platform with markets in the
+// snapshots, by name.
+func (r *Replay) Platforms() []*Platform {
+	seen := make(map[string]bool)
+	for _, snap := range r.snapshots {
+		for _, m := range snap.Markets {
+			seen[m.Platform] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	platforms := make([]*Platform, len(names))
+	for i, name := range names {
+		platforms[i] = &Platform{name: name, replay: r}
+	}
+	return platforms
+}
+
+// AnalyzeAsset implements position.VolatilityAnalyzer from the underlying
+// prices recorded up to the replay time.
+func (r *Replay) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current()
+	return r.analyzer.AnalyzeAsset(ctx, asset, strikePrice, direction, timeToClose)
+}
+
+// Platform implements platform.Platform, with the price lookups and
+// resolutions the bot uses, from a platform's recorded markets.
+type Platform struct {
+	name   string
+	replay *Replay
+}
+
+// Name returns the platform identifier.
+func (p *Platform) Name() string {
+	return p.name
+}
+
+// ListMarkets returns the platform's markets in the latest snapshot.
+func (p *Platform) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
+	p.replay.mu.Lock()
+	defer p.replay.mu.Unlock()
+	var markets []types.Market
+	for _, m := range p.replay.current().Markets {
+		if m.Platform == p.name {
+			markets = append(markets, m)
+		}
+	}
+	return markets, nil
+}
+
+// GetOrderBook returns an empty order book (snapshots carry prices only).
+func (p *Platform) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
+	return &types.OrderBook{TokenID: tokenID}, nil
+}
+
+// GetBalance is not tracked by the replay platform.
+func (p *Platform) GetBalance() (float64, error) {
+	return 0, nil
+}
+
+// GetPositions is not tracked by the replay platform.
+func (p *Platform) GetPositions() ([]types.Position, error) {
+	return []types.Position{}, nil
+}
+
+// GetCurrentPrice returns the YES price a market was last recorded at.
+func (p *Platform) GetCurrentPrice(marketID string) (float64, error) {
+	m, err := p.market(marketID)
+	if err != nil {
+		return 0, err
+	}
+	return m.OutcomeYesPrice, nil
+}
+
+// GetResolution reports whether a market has resolved by the replay time
+// (see backtest.Resolution).
+func (p *Platform) GetResolution(marketID string) (types.Resolution, error) {
+	m, err := p.market(marketID)
+	if err != nil {
+		return types.Resolution{}, err
+	}
+	p.replay.mu.Lock()
+	defer p.replay.mu.Unlock()
+	return backtest.Resolution(m, p.replay.now()), nil
+}
+
+// market returns a market as last recorded up to the replay time.
+func (p *Platform) market(marketID string) (types.Market, error) {
+	p.replay.mu.Lock()
+	defer p.replay.mu.Unlock()
+	p.replay.current()
+	m, ok := p.replay.markets[p.name+":"+marketID]
+	if !ok {
+		return types.Market{}, fmt.Errorf("market %s not recorded on %s", marketID, p.name)
+	}
+	return m, nil
+}
@@ -0,0 +1,120 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/backtest"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+// snapshotsHourly returns n snapshots an hour apart listing one market on
+// kalshi and one on polymarket, BTC rising a dollar each.
+func snapshotsHourly(n int) []backtest.Snapshot {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Duration(n-1) * time.Hour)
+	snapshots := make([]backtest.Snapshot, n)
+	for i := range snapshots {
+		yes := 0.5 + float64(i)/100
+		snapshots[i] = backtest.Snapshot{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			Markets: []types.Market{
+				{ID: "k1", Platform: "kalshi", EndDate: end, OutcomeYesPrice: yes, OutcomeNoPrice: 1 - yes},
+				{ID: "p1", Platform: "polymarket", EndDate: end, OutcomeYesPrice: yes, OutcomeNoPrice: 1 - yes},
+			},
+			Prices: map[string]float64{"BTC": 90000 + float64(i)},
+		}
+	}
+	return snapshots
+}
+
+func TestReplay_Now(t *testing.T) {
+	snapshots := snapshotsHourly(4)
+	r, err := New(snapshots, 3600)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	wall := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	r.wall = func() time.Time { return wall }
+
+	if got := r.Now(); !got.Equal(snapshots[0].Timestamp) {
+		t.Errorf("expected the first snapshot before Start, got %v", got)
+	}
+	r.Start()
+	wall = wall.Add(1500 * time.Millisecond)
+	if got, want := r.Now(), snapshots[1].Timestamp.Add(30*time.Minute); !got.Equal(want) {
+		t.Errorf("expected an hour a second, at %v, got %v", want, got)
+	}
+	wall = wall.Add(time.Minute)
+	if got := r.Now(); !got.Equal(r.End()) {
+		t.Errorf("expected the replay to stop at the last snapshot, got %v", got)
+	}
+	if got := r.Scale(5 * time.Minute); got != 83333333*time.Nanosecond {
+		t.Errorf("expected 5 minutes to last 1/3600 as long, got %v", got)
+	}
+}
+
+func TestReplay_Platforms(t *testing.T) {
+	r, err := New(snapshotsHourly(4), 3600)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	wall := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	r.wall = func() time.Time { return wall }
+	r.Start()
+	wall = wall.Add(2 * time.Second)
+
+	platforms := r.Platforms()
+	if len(platforms) != 2 || platforms[0].Name() != "kalshi" || platforms[1].Name() != "polymarket" {
+		t.Fatalf("expected a platform each for kalshi and polymarket, got %v", platforms)
+	}
+	kalshi := platforms[0]
+	markets, err := kalshi.ListMarkets(context.Background(), types.MarketFilter{})
+	if err != nil {
+		t.Fatalf("ListMarkets failed: %v", err)
+	}
+	if len(markets) != 1 || markets[0].ID != "k1" {
+		t.Fatalf("expected kalshi's market only, got %v", markets)
+	}
+	if price, err := kalshi.GetCurrentPrice("k1"); err != nil || price != 0.52 {
+		t.Errorf("expected the price recorded 2 hours in, got %v (%v)", price, err)
+	}
+	if _, err := kalshi.GetCurrentPrice("p1"); err == nil {
+		t.Error("expected an error for another platform's market")
+	}
+
+	result, err := r.AnalyzeAsset(context.Background(), "BTC", 80000, volatility.DirectionAbove, 24*time.Hour)
+	if err == nil || result.CurrentPrice != 90002 {
+		t.Errorf("expected BTC at the price recorded 2 hours in (and too little history), got %v (%v)", result.CurrentPrice, err)
+	}
+
+	if res, _ := kalshi.GetResolution("k1"); res.Resolved {
+		t.Error("expected the market unresolved before its end date")
+	}
+	wall = wall.Add(time.Second)
+	if res, _ := kalshi.GetResolution("k1"); !res.Resolved || res.Outcome != "YES" {
+		t.Errorf("expected the market resolved YES at its end date, got %+v", res)
+	}
+}
+
+func TestReplay_Done(t *testing.T) {
+	r, err := New(snapshotsHourly(2), 3600*1000)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	r.Start()
+	select {
+	case <-r.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the replay done after an hour at 1000 hours a second")
+	}
+
+	if _, err := New(nil, 60); err == nil {
+		t.Error("expected an error without snapshots")
+	}
+	if _, err := New(snapshotsHourly(2), 0); err == nil {
+		t.Error("expected an error without a speed")
+	}
+}
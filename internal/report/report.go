@@ -0,0 +1,202 @@
+// Package report summarizes realized PnL by month and year for each
+// platform, with a per-trade ledger of closed positions suitable for tax
+// filing.
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// Period layouts. Trades are grouped by the UTC month or year they were
+// closed in, when their PnL was realized.
+const (
+	MonthLayout = "2006-01"
+	YearLayout  = "2006"
+)
+
+// Trade is a closed position as a tax lot: what was bought, when, and what
+// it was disposed of for.
+type Trade struct {
+	PositionID  int64
+	Platform    string
+	MarketID    string
+	MarketTitle string
+	Side        string
+	Quantity    float64
+	Acquired    time.Time
+	Disposed    time.Time // The entry time if the exit time wasn't recorded
+	CostBasis   float64   // Entry price times quantity
+	Proceeds    float64   // Exit price times quantity
+	Fees        float64
+	RealizedPnL float64 // Net of fees
+	ExitReason  string
+}
+
+// Holding returns how long the position was held.
+func (t Trade) Holding() time.Duration {
+	return t.Disposed.Sub(t.Acquired)
+}
+
+// Summary is the realized PnL of a platform's trades closed in a period.
+type Summary struct {
+	Period      string // "2026-03" for a month, "2026" for a year
+	Platform    string
+	Trades      int
+	Wins        int
+	Losses      int
+	Fees        float64
+	RealizedPnL float64 // Net of fees
+	AvgHolding  time.Duration
+}
+
+// Report is a per-trade ledger with its monthly and yearly summaries.
+type Report struct {
+	Trades  []Trade   // Oldest disposal first
+	Monthly []Summary // By period, then platform
+	Yearly  []Summary // By period, then platform
+}
+
+// Build builds a report from closed positions.
+func Build(entries []*persistence.JournalEntry) Report {
+	var r Report
+	for _, e := range entries {
+		disposed := e.EntryTime
+		if e.ExitTime != nil {
+			disposed = *e.ExitTime
+		}
+		r.Trades = append(r.Trades, Trade{
+			PositionID:  e.PositionID,
+			Platform:    e.Platform,
+			MarketID:    e.MarketID,
+			MarketTitle: e.MarketTitle,
+			Side:        e.Side,
+			Quantity:    e.Quantity,
+			Acquired:    e.EntryTime,
+			Disposed:    disposed,
+			CostBasis:   types.Cost(e.EntryPrice, e.Quantity).Float64(),
+			Proceeds:    types.Cost(e.ExitPrice, e.Quantity).Float64(),
+			Fees:        e.Fees,
+			RealizedPnL: e.RealizedPnL,
+			ExitReason:  e.ExitReason,
+		})
+	}
+	sort.SliceStable(r.Trades, func(i, j int) bool { return r.Trades[i].Disposed.Before(r.Trades[j].Disposed) })
+
+	r.Monthly = summarize(r.Trades, MonthLayout)
+	r.Yearly = summarize(r.Trades, YearLayout)
+	return r
+}
+
+// summarize groups trades by the period of their disposal, formatted with
+// layout, and platform.
+func summarize(trades []Trade, layout string) []Summary {
+	type totals struct {
+		summary   Summary
+		fees, pnl types.Money // Summed as types.Money so totals are exact
+		holding   time.Duration
+	}
+	groups := make(map[[2]string]*totals)
+	for _, t := range trades {
+		key := [2]string{t.Disposed.UTC().Format(layout), t.Platform}
+		g, ok := groups[key]
+		if !ok {
+			g = &totals{summary: Summary{Period: key[0], Platform: key[1]}}
+			groups[key] = g
+		}
+		g.summary.Trades++
+		if t.RealizedPnL > 0 {
+			g.summary.Wins++
+		} else if t.RealizedPnL < 0 {
+			g.summary.Losses++
+		}
+		g.fees += types.Dollars(t.Fees)
+		g.pnl += types.Dollars(t.RealizedPnL)
+		g.holding += t.Holding()
+	}
+
+	summaries := make([]Summary, 0, len(groups))
+	for _, g := range groups {
+		s := g.summary
+		s.Fees = g.fees.Float64()
+		s.RealizedPnL = g.pnl.Float64()
+		s.AvgHolding = g.holding / time.Duration(s.Trades)
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Period != summaries[j].Period {
+			return summaries[i].Period < summaries[j].Period
+		}
+		return summaries[i].Platform < summaries[j].Platform
+	})
+	return summaries
+}
+
+// WriteLedgerCSV writes trades as CSV with a header row, one row per tax
+// lot. Times are RFC 3339 in UTC.
+func WriteLedgerCSV(out io.Writer, trades []Trade) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{
+		"position_id", "platform", "market_id", "market_title", "side", "quantity",
+		"date_acquired", "date_disposed", "holding_hours", "cost_basis", "proceeds",
+		"fees", "realized_pnl", "exit_reason",
+	})
+	for _, t := range trades {
+		w.Write([]string{
+			strconv.FormatInt(t.PositionID, 10),
+			t.Platform,
+			t.MarketID,
+			t.MarketTitle,
+			t.Side,
+			formatFloat(t.Quantity),
+			t.Acquired.UTC().Format(time.RFC3339),
+			t.Disposed.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(t.Holding().Hours(), 'f', 2, 64),
+			formatMoney(t.CostBasis),
+			formatMoney(t.Proceeds),
+			formatMoney(t.Fees),
+			formatMoney(t.RealizedPnL),
+			t.ExitReason,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// WriteSummaryCSV writes summaries as CSV with a header row.
+func WriteSummaryCSV(out io.Writer, summaries []Summary) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{
+		"period", "platform", "trades", "wins", "losses", "fees", "realized_pnl", "avg_holding_hours",
+	})
+	for _, s := range summaries {
+		w.Write([]string{
+			s.Period,
+			s.Platform,
+			strconv.Itoa(s.Trades),
+			strconv.Itoa(s.Wins),
+			strconv.Itoa(s.Losses),
+			formatMoney(s.Fees),
+			formatMoney(s.RealizedPnL),
+			strconv.FormatFloat(s.AvgHolding.Hours(), 'f', 2, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatMoney formats a dollar amount to the micro-dollar, so summed rows
+// match the totals exactly.
+func formatMoney(v float64) string {
+	return types.Dollars(v).String()
+}
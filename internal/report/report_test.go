@@ -0,0 +1,112 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+func journalEntry(id int64, platform string, entry, exit time.Time, pnl, fees float64) *persistence.JournalEntry {
+	return &persistence.JournalEntry{
+		PositionID:  id,
+		Platform:    platform,
+		MarketID:    "market",
+		Side:        "YES",
+		EntryTime:   entry,
+		ExitTime:    &exit,
+		EntryPrice:  0.9,
+		ExitPrice:   1.0,
+		Quantity:    10,
+		Fees:        fees,
+		RealizedPnL: pnl,
+	}
+}
+
+func TestBuild_SummarizesByPeriodAndPlatform(t *testing.T) {
+	march := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC)
+
+	r := Build([]*persistence.JournalEntry{
+		journalEntry(3, "kalshi", april.Add(-4*time.Hour), april, -2.5, 0.1),
+		journalEntry(1, "kalshi", march.Add(-2*time.Hour), march, 1.0, 0.05),
+		journalEntry(2, "kalshi", march.Add(-6*time.Hour), march.Add(time.Hour), 0.3, 0.05),
+		journalEntry(4, "polymarket", march.Add(-time.Hour), march, 0.7, 0),
+	})
+
+	// Ledger: one lot per trade, oldest disposal first
+	if len(r.Trades) != 4 || r.Trades[0].PositionID != 1 || r.Trades[3].PositionID != 3 {
+		t.Fatalf("unexpected ledger order %+v", r.Trades)
+	}
+	lot := r.Trades[0]
+	if lot.CostBasis != 9.0 || lot.Proceeds != 10.0 || lot.Holding() != 2*time.Hour {
+		t.Errorf("unexpected lot %+v", lot)
+	}
+
+	// Monthly: March kalshi, March polymarket, April kalshi
+	if len(r.Monthly) != 3 {
+		t.Fatalf("expected 3 monthly summaries, got %d", len(r.Monthly))
+	}
+	got := r.Monthly[0]
+	want := Summary{Period: "2026-03", Platform: "kalshi", Trades: 2, Wins: 2, Fees: 0.1, RealizedPnL: 1.3, AvgHolding: 4*time.Hour + 30*time.Minute}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if r.Monthly[1].Platform != "polymarket" || r.Monthly[2].Period != "2026-04" || r.Monthly[2].Losses != 1 {
+		t.Errorf("unexpected monthly summaries %+v", r.Monthly)
+	}
+
+	// Yearly: one per platform
+	if len(r.Yearly) != 2 {
+		t.Fatalf("expected 2 yearly summaries, got %d", len(r.Yearly))
+	}
+	kalshi := r.Yearly[0]
+	if kalshi.Period != "2026" || kalshi.Trades != 3 || kalshi.Wins != 2 || kalshi.Losses != 1 || kalshi.RealizedPnL != -1.2 || kalshi.Fees != 0.2 {
+		t.Errorf("unexpected yearly summary %+v", kalshi)
+	}
+}
+
+func TestBuild_MissingExitTimeUsesEntryTime(t *testing.T) {
+	entry := journalEntry(1, "kalshi", time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC), time.Time{}, 1, 0)
+	entry.ExitTime = nil
+
+	r := Build([]*persistence.JournalEntry{entry})
+	if r.Trades[0].Holding() != 0 || r.Monthly[0].Period != "2026-01" {
+		t.Errorf("expected the lot disposed at entry in January, got %+v", r.Trades[0])
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	exit := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	r := Build([]*persistence.JournalEntry{journalEntry(7, "kalshi", exit.Add(-90*time.Minute), exit, 0.95, 0.05)})
+
+	var ledger bytes.Buffer
+	if err := WriteLedgerCSV(&ledger, r.Trades); err != nil {
+		t.Fatalf("WriteLedgerCSV failed: %v", err)
+	}
+	records, err := csv.NewReader(&ledger).ReadAll()
+	if err != nil {
+		t.Fatalf("ledger is not valid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a header and 1 row, got %d records", len(records))
+	}
+	row := records[1]
+	if row[0] != "7" || row[7] != "2026-03-10T12:00:00Z" || row[8] != "1.50" || row[9] != "9.000000" || row[12] != "0.950000" {
+		t.Errorf("unexpected ledger row %v", row)
+	}
+
+	var summary bytes.Buffer
+	if err := WriteSummaryCSV(&summary, r.Monthly); err != nil {
+		t.Fatalf("WriteSummaryCSV failed: %v", err)
+	}
+	records, err = csv.NewReader(&summary).ReadAll()
+	if err != nil {
+		t.Fatalf("summary is not valid CSV: %v", err)
+	}
+	if len(records) != 2 || records[1][0] != "2026-03" || records[1][2] != "1" || records[1][6] != "0.950000" {
+		t.Errorf("unexpected summary %v", records)
+	}
+}
@@ -0,0 +1,120 @@
+// Package risk enforces portfolio-wide limits on new positions.
+package risk
+
+import (
+	"time"
+
+	"prediction-bot/internal/datasource"
+	"prediction-bot/internal/persistence"
+)
+
+// Limits that can reject an entry.
+const (
+	LimitOpenPositions       = "max_open_positions"
+	LimitAssetExposure       = "max_asset_exposure"
+	LimitDirectionalExposure = "max_directional_exposure"
+)
+
+// Limits holds the portfolio constraints. Exposure limits are fractions of
+// capital: cash across all platforms plus the cost of open positions. A zero
+// value disables the limit.
+type Limits struct {
+	// MaxAssetExposure is the maximum share of capital in positions on one
+	// asset's markets, whichever way they bet (e.g., 0.40 for BTC).
+	MaxAssetExposure float64
+	// MaxOpenPositions is the maximum number of positions open at once.
+	MaxOpenPositions int
+	// MaxDirectionalExposure is the maximum share of capital betting the
+	// same way on correlated assets. Assets in the same class (all crypto,
+	// all stocks) are treated as correlated: a YES above BTC and a YES above
+	// ETH both lose if crypto sells off.
+	MaxDirectionalExposure float64
+	// MaxDailyVaR is the maximum share of capital the portfolio may lose
+	// in a day at VaRConfidence (see Checker.DailyVaR).
+	MaxDailyVaR float64
+	// VaRConfidence is the confidence daily VaR is estimated at (0 uses
+	// DefaultVaRConfidence).
+	VaRConfidence float64
+}
+
+// Entry describes a position about to be opened.
+type Entry struct {
+	Asset     string
+	Direction string  // "above" or "below"
+	Side      string  // "YES" or "NO"
+	Size      float64 // Dollars
+	// EntryPrice is the price paid per contract, which implies the chance
+	// of losing the position.
+	EntryPrice float64
+	CloseTime  time.Time // Zero if unknown
+}
+
+// Checker checks entries against the portfolio limits.
+type Checker struct {
+	limits Limits
+	mapper *datasource.SymbolMapper
+	now    func() time.Time
+}
+
+// NewChecker creates a new Checker with the given limits.
+func NewChecker(limits Limits) *Checker {
+	return &Checker{
+		limits: limits,
+		mapper: datasource.NewSymbolMapper(),
+		now:    time.Now,
+	}
+}
+
+// SetClock sets the clock VaR is estimated at, for replaying history.
+func (c *Checker) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// Check returns the limit the entry would breach given the open positions
+// and the cash across all platforms, or an empty string if it is allowed.
+func (c *Checker) Check(open []*persistence.Position, cash float64, entry Entry) string {
+	if c.limits.MaxOpenPositions > 0 && len(open) >= c.limits.MaxOpenPositions {
+		return LimitOpenPositions
+	}
+
+	capital := cash
+	var assetExposure, directionalExposure float64
+	bullish := Bullish(entry.Direction, entry.Side)
+	for _, p := range open {
+		cost := p.EntryPrice * p.Quantity
+		capital += cost
+		if p.Asset == entry.Asset {
+			assetExposure += cost
+		}
+		if c.correlated(p.Asset, entry.Asset) && Bullish(p.Direction, p.Side) == bullish {
+			directionalExposure += cost
+		}
+	}
+
+	if c.limits.MaxAssetExposure > 0 && assetExposure+entry.Size > c.limits.MaxAssetExposure*capital {
+		return LimitAssetExposure
+	}
+	if c.limits.MaxDirectionalExposure > 0 && directionalExposure+entry.Size > c.limits.MaxDirectionalExposure*capital {
+		return LimitDirectionalExposure
+	}
+	if c.limits.MaxDailyVaR > 0 && c.DailyVaR(open, entry) > c.limits.MaxDailyVaR*capital {
+		return LimitVaR
+	}
+	return ""
+}
+
+// correlated reports whether two assets move together: the same asset, or
+// known assets of the same class.
+func (c *Checker) correlated(a, b string) bool {
+	if a == b {
+		return true
+	}
+	class := c.mapper.AssetClass(a)
+	return class != "" && class == c.mapper.AssetClass(b)
+}
+
+// Bullish reports whether a position profits when the underlying rises:
+// YES on an "above" market or NO on a "below" market.
+func Bullish(direction, side string) bool {
+	return (direction == "below") == (side == "NO")
+}
@@ -0,0 +1,96 @@
+package risk
+
+import (
+	"testing"
+
+	"prediction-bot/internal/persistence"
+)
+
+func openPosition(asset, direction, side string, cost float64) *persistence.Position {
+	return &persistence.Position{Asset: asset, Direction: direction, Side: side, EntryPrice: 0.5, Quantity: cost / 0.5}
+}
+
+func TestChecker_Check(t *testing.T) {
+	// $60 cash plus $40 in positions is $100 of capital
+	open := []*persistence.Position{
+		openPosition("BTC", "above", "YES", 30),
+		openPosition("ETH", "below", "NO", 10),
+	}
+
+	tests := []struct {
+		name   string
+		limits Limits
+		entry  Entry
+		want   string
+	}{
+		{
+			name:   "no limits",
+			limits: Limits{},
+			entry:  Entry{Asset: "BTC", Direction: "above", Side: "YES", Size: 50},
+			want:   "",
+		},
+		{
+			name:   "open position limit reached",
+			limits: Limits{MaxOpenPositions: 2},
+			entry:  Entry{Asset: "SOL", Direction: "above", Side: "YES", Size: 1},
+			want:   LimitOpenPositions,
+		},
+		{
+			name:   "asset exposure within limit",
+			limits: Limits{MaxAssetExposure: 0.40},
+			entry:  Entry{Asset: "BTC", Direction: "below", Side: "YES", Size: 10},
+			want:   "",
+		},
+		{
+			name:   "asset exposure counts both directions",
+			limits: Limits{MaxAssetExposure: 0.40},
+			entry:  Entry{Asset: "BTC", Direction: "below", Side: "YES", Size: 11},
+			want:   LimitAssetExposure,
+		},
+		{
+			name:   "correlated crypto bets add up",
+			limits: Limits{MaxDirectionalExposure: 0.50},
+			entry:  Entry{Asset: "SOL", Direction: "above", Side: "YES", Size: 11},
+			want:   LimitDirectionalExposure,
+		},
+		{
+			name:   "opposite direction is not added",
+			limits: Limits{MaxDirectionalExposure: 0.50},
+			entry:  Entry{Asset: "SOL", Direction: "above", Side: "NO", Size: 11},
+			want:   "",
+		},
+		{
+			name:   "other asset class is not correlated",
+			limits: Limits{MaxDirectionalExposure: 0.50},
+			entry:  Entry{Asset: "SPY", Direction: "above", Side: "YES", Size: 11},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewChecker(tt.limits).Check(open, 60, tt.entry)
+			if got != tt.want {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBullish(t *testing.T) {
+	tests := []struct {
+		direction, side string
+		want            bool
+	}{
+		{"above", "YES", true},
+		{"above", "NO", false},
+		{"below", "YES", false},
+		{"below", "NO", true},
+	}
+
+	for _, tt := range tests {
+		if got := Bullish(tt.direction, tt.side); got != tt.want {
+			t.Errorf("Bullish(%q, %q) = %v, want %v", tt.direction, tt.side, got, tt.want)
+		}
+	}
+}
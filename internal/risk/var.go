@@ -0,0 +1,198 @@
+package risk
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// LimitVaR is the limit on the portfolio's daily value at risk.
+const LimitVaR = "var_limit"
+
+// DefaultVaRConfidence is the confidence daily VaR is estimated at unless
+// configured otherwise.
+const DefaultVaRConfidence = 0.95
+
+// exposure is a position's contribution to portfolio VaR: it loses cost,
+// and only cost, with probability lossProb over the next day.
+type exposure struct {
+	group    string
+	cost     types.Money
+	lossProb float64
+}
+
+// DailyVaR estimates the loss the open positions plus entry (if its size is
+// positive) won't exceed over the next day at the configured confidence.
+//
+// Each binary position loses its cost if it resolves against it, with the
+// probability its entry price implies, spread evenly over the days until
+// its market closes (all of it within a day if the close is unknown).
+// Positions betting the same way on correlated assets are assumed to lose
+// together, the likeliest losers first; other groups are independent.
+func (c *Checker) DailyVaR(open []*persistence.Position, entry Entry) float64 {
+	now := c.now()
+	var exposures []exposure
+	for _, p := range open {
+		var closeTime time.Time
+		if p.MarketCloseTime != nil {
+			closeTime = *p.MarketCloseTime
+		}
+		exposures = append(exposures, exposure{
+			group:    c.varGroup(p.Asset, p.Direction, p.Side),
+			cost:     types.Cost(p.EntryPrice, p.Quantity),
+			lossProb: dailyLossProb(p.EntryPrice, closeTime, now),
+		})
+	}
+	if entry.Size > 0 {
+		exposures = append(exposures, exposure{
+			group:    c.varGroup(entry.Asset, entry.Direction, entry.Side),
+			cost:     types.Dollars(entry.Size),
+			lossProb: dailyLossProb(entry.EntryPrice, entry.CloseTime, now),
+		})
+	}
+
+	return valueAtRisk(exposures, c.confidence()).Float64()
+}
+
+// FitVaR returns the largest size, at most entry.Size and to the
+// micro-dollar, the entry can be opened at without the portfolio's daily
+// VaR exceeding the limit, or 0 if even the open positions exceed it.
+// Without a VaR limit it returns entry.Size.
+func (c *Checker) FitVaR(open []*persistence.Position, cash float64, entry Entry) float64 {
+	if c.limits.MaxDailyVaR <= 0 {
+		return entry.Size
+	}
+	limit := c.limits.MaxDailyVaR * capital(open, cash)
+
+	fits := func(size types.Money) bool {
+		candidate := entry
+		candidate.Size = size.Float64()
+		return c.DailyVaR(open, candidate) <= limit
+	}
+
+	// The VaR only grows with the entry's size
+	lo, hi := types.Money(0), types.Dollars(entry.Size)
+	if fits(hi) {
+		return entry.Size
+	}
+	if !fits(lo) {
+		return 0
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo.Float64()
+}
+
+// varGroup returns the group of positions assumed to lose together: those
+// betting the same way on an asset class, or on the asset if its class is
+// unknown.
+func (c *Checker) varGroup(asset, direction, side string) string {
+	group := c.mapper.AssetClass(asset)
+	if group == "" {
+		group = "asset:" + strings.ToUpper(asset)
+	}
+	if Bullish(direction, side) {
+		return group + "|bullish"
+	}
+	return group + "|bearish"
+}
+
+// confidence returns the configured VaR confidence, or the default.
+func (c *Checker) confidence() float64 {
+	if c.limits.VaRConfidence > 0 && c.limits.VaRConfidence < 1 {
+		return c.limits.VaRConfidence
+	}
+	return DefaultVaRConfidence
+}
+
+// capital returns cash plus the cost of the open positions.
+func capital(open []*persistence.Position, cash float64) float64 {
+	total := types.Dollars(cash)
+	for _, p := range open {
+		total += types.Cost(p.EntryPrice, p.Quantity)
+	}
+	return total.Float64()
+}
+
+// dailyLossProb returns the probability a position bought at entryPrice
+// loses over the next day: the implied probability of losing by the close,
+// at a constant daily rate until closeTime.
+func dailyLossProb(entryPrice float64, closeTime, now time.Time) float64 {
+	lossProb := math.Min(math.Max(1-entryPrice, 0), 1)
+	if closeTime.IsZero() {
+		return lossProb
+	}
+	days := closeTime.Sub(now).Hours() / 24
+	if days <= 1 {
+		return lossProb
+	}
+	return 1 - math.Pow(1-lossProb, 1/days)
+}
+
+// valueAtRisk returns the smallest loss the exposures exceed with
+// probability at most 1-confidence.
+func valueAtRisk(exposures []exposure, confidence float64) types.Money {
+	groups := make(map[string][]exposure)
+	var names []string
+	for _, e := range exposures {
+		if _, ok := groups[e.group]; !ok {
+			names = append(names, e.group)
+		}
+		groups[e.group] = append(groups[e.group], e)
+	}
+
+	// Convolve the independent groups' loss distributions
+	dist := map[types.Money]float64{0: 1}
+	for _, name := range names {
+		next := make(map[types.Money]float64)
+		for groupLoss, pg := range groupLosses(groups[name]) {
+			for loss, p := range dist {
+				next[loss+groupLoss] += p * pg
+			}
+		}
+		dist = next
+	}
+
+	losses := make([]types.Money, 0, len(dist))
+	for loss := range dist {
+		losses = append(losses, loss)
+	}
+	sort.Slice(losses, func(i, j int) bool { return losses[i] < losses[j] })
+
+	var cumulative float64
+	for _, loss := range losses {
+		cumulative += dist[loss]
+		if cumulative >= confidence-1e-12 {
+			return loss
+		}
+	}
+	return losses[len(losses)-1]
+}
+
+// groupLosses returns the loss distribution of comonotonic exposures: when
+// the group loses, its likeliest losers lose first, so k of them lose with
+// the probability the k-th likeliest loses but the next doesn't.
+func groupLosses(exposures []exposure) map[types.Money]float64 {
+	sort.Slice(exposures, func(i, j int) bool { return exposures[i].lossProb > exposures[j].lossProb })
+
+	dist := make(map[types.Money]float64)
+	var loss types.Money
+	prev := 1.0
+	for _, e := range exposures {
+		dist[loss] += prev - e.lossProb
+		loss += e.cost
+		prev = e.lossProb
+	}
+	dist[loss] += prev
+	return dist
+}
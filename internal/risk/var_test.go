@@ -0,0 +1,117 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+func TestChecker_DailyVaR(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	inTenDays := now.Add(10 * 24 * time.Hour)
+
+	position := func(asset, side string, price, cost float64, closeTime *time.Time) *persistence.Position {
+		return &persistence.Position{Asset: asset, Direction: "above", Side: side, EntryPrice: price, Quantity: cost / price, MarketCloseTime: closeTime}
+	}
+
+	tests := []struct {
+		name       string
+		confidence float64
+		open       []*persistence.Position
+		entry      Entry
+		want       float64
+	}{
+		{
+			name:       "loss likelier than the tail",
+			confidence: 0.95,
+			open:       []*persistence.Position{position("BTC", "YES", 0.90, 10, nil)},
+			want:       10,
+		},
+		{
+			name:       "loss rarer than the tail",
+			confidence: 0.85,
+			open:       []*persistence.Position{position("BTC", "YES", 0.90, 10, nil)},
+			want:       0,
+		},
+		{
+			name:       "loss spread over the days to close",
+			confidence: 0.95,
+			open:       []*persistence.Position{position("BTC", "YES", 0.90, 10, &inTenDays)},
+			want:       0,
+		},
+		{
+			name:       "correlated bets lose together",
+			confidence: 0.99,
+			open: []*persistence.Position{
+				position("BTC", "YES", 0.96, 10, nil),
+				position("ETH", "YES", 0.96, 10, nil),
+			},
+			want: 20,
+		},
+		{
+			name:       "uncorrelated bets rarely lose together",
+			confidence: 0.99,
+			open: []*persistence.Position{
+				position("BTC", "YES", 0.96, 10, nil),
+				position("SPY", "YES", 0.96, 10, nil),
+			},
+			want: 10,
+		},
+		{
+			name:       "opposite bets on an asset class are independent",
+			confidence: 0.99,
+			open: []*persistence.Position{
+				position("BTC", "YES", 0.96, 10, nil),
+				position("ETH", "NO", 0.96, 10, nil),
+			},
+			want: 10,
+		},
+		{
+			name:       "entry adds to its group",
+			confidence: 0.99,
+			open:       []*persistence.Position{position("BTC", "YES", 0.96, 10, nil)},
+			entry:      Entry{Asset: "SOL", Direction: "above", Side: "YES", Size: 5, EntryPrice: 0.95},
+			want:       15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(Limits{VaRConfidence: tt.confidence})
+			c.now = func() time.Time { return now }
+
+			if got := c.DailyVaR(tt.open, tt.entry); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DailyVaR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecker_VaRLimit(t *testing.T) {
+	// $100 of cash and a 5% limit allow $5 of daily VaR
+	c := NewChecker(Limits{MaxDailyVaR: 0.05})
+	entry := Entry{Asset: "BTC", Direction: "above", Side: "YES", Size: 20, EntryPrice: 0.50}
+
+	if got := c.Check(nil, 100, entry); got != LimitVaR {
+		t.Errorf("Check() = %q, want %q", got, LimitVaR)
+	}
+	if got := c.FitVaR(nil, 100, entry); math.Abs(got-5) > 1e-6 {
+		t.Errorf("FitVaR() = %v, want 5", got)
+	}
+
+	entry.Size = 4
+	if got := c.Check(nil, 100, entry); got != "" {
+		t.Errorf("Check() = %q, want no limit", got)
+	}
+	if got := c.FitVaR(nil, 100, entry); got != 4 {
+		t.Errorf("FitVaR() = %v, want the whole entry", got)
+	}
+
+	// Open positions already over the limit leave no room
+	open := []*persistence.Position{{Asset: "ETH", Direction: "above", Side: "YES", EntryPrice: 0.5, Quantity: 20}}
+	if got := c.FitVaR(open, 90, entry); got != 0 {
+		t.Errorf("FitVaR() = %v, want 0", got)
+	}
+}
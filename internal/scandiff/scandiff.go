@@ -0,0 +1,156 @@
+// Package scandiff compares a platform's latest scan cycle with the one
+// before it: the markets that became eligible, those that stopped being
+// eligible and why, and the price moves of markets eligible in both.
+package scandiff
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+// Kinds of change between two scans.
+const (
+	KindEligible   = "eligible"   // Newly eligible
+	KindIneligible = "ineligible" // No longer eligible
+	KindPrice      = "price"      // Price moved on a market eligible in both
+)
+
+// ReasonNotListed is the reason given for an eligible market missing from
+// the latest scan, e.g. because it closed.
+const ReasonNotListed = "not_listed"
+
+// DefaultMinPriceMove is the smallest YES price move reported unless
+// configured otherwise.
+const DefaultMinPriceMove = 0.01
+
+// Change is how a market's decision changed between two scans.
+type Change struct {
+	Kind           string
+	Platform       string
+	MarketID       string
+	Outcome        string
+	Title          string
+	Reason         string  // Latest decision's reason, or ReasonNotListed
+	PreviousReason string  // Empty if the market is new
+	PreviousPrice  float64 // YES price in the previous scan (0 if new)
+	Price          float64 // YES price in the latest scan (0 if not listed)
+}
+
+// Move returns the change in the market's YES price.
+func (c Change) Move() float64 {
+	return c.Price - c.PreviousPrice
+}
+
+// Diff is the change in a platform's opportunity set between two scans.
+type Diff struct {
+	Platform string
+	Previous time.Time
+	Current  time.Time
+	Changes  []Change // Newly eligible, then no longer eligible, then price moves
+}
+
+// Compare returns the changes from the previous snapshot to the current
+// one. Price moves smaller than minPriceMove are left out (0 uses
+// DefaultMinPriceMove).
+func Compare(previous, current *persistence.ScanSnapshot, minPriceMove float64) Diff {
+	if minPriceMove <= 0 {
+		minPriceMove = DefaultMinPriceMove
+	}
+	diff := Diff{Platform: current.Platform, Previous: previous.ScannedAt, Current: current.ScannedAt}
+
+	key := func(d *persistence.ScanDecision) string { return d.MarketID + "|" + d.Outcome }
+	before := make(map[string]*persistence.ScanDecision, len(previous.Decisions))
+	for _, d := range previous.Decisions {
+		before[key(d)] = d
+	}
+
+	seen := make(map[string]bool, len(current.Decisions))
+	for _, d := range current.Decisions {
+		seen[key(d)] = true
+		prev := before[key(d)]
+		wasEligible := prev != nil && prev.Eligible
+
+		change := Change{
+			Platform: current.Platform,
+			MarketID: d.MarketID,
+			Outcome:  d.Outcome,
+			Title:    d.MarketTitle,
+			Reason:   d.Reason,
+			Price:    d.YesPrice,
+		}
+		if prev != nil {
+			change.PreviousReason = prev.Reason
+			change.PreviousPrice = prev.YesPrice
+		}
+
+		switch {
+		case d.Eligible && !wasEligible:
+			change.Kind = KindEligible
+		case !d.Eligible && wasEligible:
+			change.Kind = KindIneligible
+		case d.Eligible && math.Abs(change.Move()) >= minPriceMove-1e-9:
+			change.Kind = KindPrice
+		default:
+			continue
+		}
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	// Eligible markets no longer listed
+	for _, prev := range previous.Decisions {
+		if !prev.Eligible || seen[key(prev)] {
+			continue
+		}
+		diff.Changes = append(diff.Changes, Change{
+			Kind:           KindIneligible,
+			Platform:       previous.Platform,
+			MarketID:       prev.MarketID,
+			Outcome:        prev.Outcome,
+			Title:          prev.MarketTitle,
+			Reason:         ReasonNotListed,
+			PreviousReason: prev.Reason,
+			PreviousPrice:  prev.YesPrice,
+		})
+	}
+
+	order := map[string]int{KindEligible: 0, KindIneligible: 1, KindPrice: 2}
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Kind != b.Kind {
+			return order[a.Kind] < order[b.Kind]
+		}
+		if a.Kind == KindPrice && math.Abs(a.Move()) != math.Abs(b.Move()) {
+			return math.Abs(a.Move()) > math.Abs(b.Move())
+		}
+		if a.MarketID != b.MarketID {
+			return a.MarketID < b.MarketID
+		}
+		return a.Outcome < b.Outcome
+	})
+	return diff
+}
+
+// Latest diffs each platform's latest scan with the one before, in
+// platform order. Platforms scanned only once are left out.
+func Latest(repo *persistence.ScanSnapshotRepository, minPriceMove float64) ([]Diff, error) {
+	platforms, err := repo.GetPlatforms()
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []Diff
+	for _, platform := range platforms {
+		snapshots, err := repo.GetLatest(platform, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshots) < 2 {
+			continue
+		}
+		diffs = append(diffs, Compare(snapshots[1], snapshots[0], minPriceMove))
+	}
+	return diffs, nil
+}
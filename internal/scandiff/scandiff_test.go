@@ -0,0 +1,112 @@
+package scandiff
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+)
+
+func TestCompare(t *testing.T) {
+	previous := &persistence.ScanSnapshot{
+		Platform:  "kalshi",
+		ScannedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Decisions: []*persistence.ScanDecision{
+			{MarketID: "still", Eligible: true, Reason: "duplicate_position", YesPrice: 0.90},
+			{MarketID: "flat", Eligible: true, Reason: "volatility_risky", YesPrice: 0.85},
+			{MarketID: "dropped", Eligible: true, Reason: "entered", YesPrice: 0.88},
+			{MarketID: "closed", MarketTitle: "Closed market", Eligible: true, Reason: "volatility_risky", YesPrice: 0.95},
+			{MarketID: "joined", Reason: "probability", YesPrice: 0.70},
+			{MarketID: "rejected", Reason: "liquidity", YesPrice: 0.60},
+		},
+	}
+	current := &persistence.ScanSnapshot{
+		Platform:  "kalshi",
+		ScannedAt: previous.ScannedAt.Add(time.Minute),
+		Decisions: []*persistence.ScanDecision{
+			{MarketID: "still", Eligible: true, Reason: "duplicate_position", YesPrice: 0.93},
+			{MarketID: "flat", Eligible: true, Reason: "volatility_risky", YesPrice: 0.855},
+			{MarketID: "dropped", Reason: "probability", YesPrice: 0.75},
+			{MarketID: "joined", Eligible: true, Reason: "entered", YesPrice: 0.86},
+			{MarketID: "new", Eligible: true, Reason: "volatility_reject", YesPrice: 0.91},
+			{MarketID: "rejected", Reason: "liquidity", YesPrice: 0.40},
+		},
+	}
+
+	diff := Compare(previous, current, 0)
+
+	if diff.Platform != "kalshi" || !diff.Current.After(diff.Previous) {
+		t.Errorf("unexpected diff header %+v", diff)
+	}
+	want := []struct {
+		kind, marketID, reason, previousReason string
+	}{
+		{KindEligible, "joined", "entered", "probability"},
+		{KindEligible, "new", "volatility_reject", ""},
+		{KindIneligible, "closed", ReasonNotListed, "volatility_risky"},
+		{KindIneligible, "dropped", "probability", "entered"},
+		{KindPrice, "still", "duplicate_position", "duplicate_position"},
+	}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), diff.Changes)
+	}
+	for i, w := range want {
+		c := diff.Changes[i]
+		if c.Kind != w.kind || c.MarketID != w.marketID || c.Reason != w.reason || c.PreviousReason != w.previousReason {
+			t.Errorf("change %d: expected %+v, got %+v", i, w, c)
+		}
+	}
+	if c := diff.Changes[2]; c.Title != "Closed market" || c.PreviousPrice != 0.95 || c.Price != 0 {
+		t.Errorf("unexpected delisted change %+v", c)
+	}
+	if move := diff.Changes[4].Move(); move < 0.0299 || move > 0.0301 {
+		t.Errorf("expected a 0.03 move, got %v", move)
+	}
+
+	// A larger threshold hides the price move
+	if diff := Compare(previous, current, 0.05); len(diff.Changes) != 4 {
+		t.Errorf("expected the price move left out, got %+v", diff.Changes)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_scandiff_*.db")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := persistence.OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	repo := persistence.NewScanSnapshotRepository(db)
+	record := func(platform string, decisions ...*persistence.ScanDecision) {
+		if err := repo.Record(platform, decisions); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	record("kalshi", &persistence.ScanDecision{MarketID: "KXBTC-1", Reason: "probability"})
+	record("kalshi", &persistence.ScanDecision{MarketID: "KXBTC-1", Eligible: true, Reason: "entered"})
+	record("polymarket", &persistence.ScanDecision{MarketID: "pm-1", Eligible: true, Reason: "entered"})
+
+	diffs, err := Latest(repo, 0)
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+
+	// Polymarket has been scanned only once
+	if len(diffs) != 1 || diffs[0].Platform != "kalshi" {
+		t.Fatalf("expected only kalshi diffed, got %+v", diffs)
+	}
+	if len(diffs[0].Changes) != 1 || diffs[0].Changes[0].Kind != KindEligible {
+		t.Errorf("expected KXBTC-1 newly eligible, got %+v", diffs[0].Changes)
+	}
+}
@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"sort"
+
+	"prediction-bot/pkg/types"
+)
+
+// Uncategorized groups markets whose platform reports no category.
+const Uncategorized = "uncategorized"
+
+// Coverage counts how many market titles in one platform category the
+// parser understood.
+type Coverage struct {
+	Platform string
+	Category string
+	Total    int
+	Parsed   int
+	// Unparsed holds the titles that failed to parse, in listing order.
+	Unparsed []string
+}
+
+// Rate returns the fraction of titles parsed, or 0 if there were none.
+func (c Coverage) Rate() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.Parsed) / float64(c.Total)
+}
+
+// ParseCoverage runs each market's title through the parser and returns the
+// parse rate per platform and category, sorted by platform then category.
+// Each outcome of a multi-outcome market counts as a title of its own.
+func ParseCoverage(markets []types.Market) []Coverage {
+	type key struct{ platform, category string }
+	groups := make(map[key]*Coverage)
+
+	var outcomes []types.Market
+	for _, market := range markets {
+		outcomes = append(outcomes, market.OutcomeMarkets()...)
+	}
+
+	for _, market := range outcomes {
+		category := market.Category
+		if category == "" {
+			category = Uncategorized
+		}
+		k := key{market.Platform, category}
+		c, ok := groups[k]
+		if !ok {
+			c = &Coverage{Platform: market.Platform, Category: category}
+			groups[k] = c
+		}
+
+		c.Total++
+		if _, err := ParseListedMarket(market); err != nil {
+			c.Unparsed = append(c.Unparsed, outcomeTitle(market))
+			continue
+		}
+		c.Parsed++
+	}
+
+	coverage := make([]Coverage, 0, len(groups))
+	for _, c := range groups {
+		coverage = append(coverage, *c)
+	}
+	sort.Slice(coverage, func(i, j int) bool {
+		if coverage[i].Platform != coverage[j].Platform {
+			return coverage[i].Platform < coverage[j].Platform
+		}
+		return coverage[i].Category < coverage[j].Category
+	})
+	return coverage
+}
+
+// outcomeTitle returns a market's title, followed by the outcome it trades
+// for an outcome of a multi-outcome market.
+func outcomeTitle(market types.Market) string {
+	if market.Outcome == "" {
+		return market.Title
+	}
+	return market.Title + " [" + market.Outcome + "]"
+}
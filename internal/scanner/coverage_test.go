@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"testing"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestParseCoverage(t *testing.T) {
+	markets := []types.Market{
+		{Platform: "polymarket", Category: "Crypto", Title: "Will Bitcoin be above $100,000 on January 18?"},
+		{Platform: "polymarket", Category: "Crypto", Title: "What price will Bitcoin hit in January?"},
+		{Platform: "polymarket", Category: "Crypto", Title: "Will Ethereum be below $3,000 on Friday?"},
+		{Platform: "kalshi", Title: "Bitcoin price at or above $100,000 at 5pm EST?"},
+		{Platform: "polymarket", Category: "Politics", Title: "Will the Fed cut rates in March?"},
+	}
+
+	coverage := ParseCoverage(markets)
+
+	if len(coverage) != 3 {
+		t.Fatalf("expected 3 groups, got %d: %+v", len(coverage), coverage)
+	}
+
+	kalshi := coverage[0]
+	if kalshi.Platform != "kalshi" || kalshi.Category != Uncategorized || kalshi.Parsed != 1 || kalshi.Total != 1 {
+		t.Errorf("expected kalshi/uncategorized 1 of 1, got %+v", kalshi)
+	}
+
+	crypto := coverage[1]
+	if crypto.Platform != "polymarket" || crypto.Category != "Crypto" || crypto.Parsed != 2 || crypto.Total != 3 {
+		t.Errorf("expected polymarket/Crypto 2 of 3, got %+v", crypto)
+	}
+	if len(crypto.Unparsed) != 1 || crypto.Unparsed[0] != "What price will Bitcoin hit in January?" {
+		t.Errorf("expected the unparsed title to be kept, got %v", crypto.Unparsed)
+	}
+	if rate := crypto.Rate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("expected rate 2/3, got %f", rate)
+	}
+
+	politics := coverage[2]
+	if politics.Category != "Politics" || politics.Parsed != 0 || politics.Rate() != 0 {
+		t.Errorf("expected polymarket/Politics 0 of 1, got %+v", politics)
+	}
+}
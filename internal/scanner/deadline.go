@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// maxDeadlineDrift is how far the deadline a title names may be from the
+// platform's EndDate and still be trusted. Further apart, the title was
+// most likely misread, such as a date without a year placed in the wrong
+// year, and EndDate is used.
+const maxDeadlineDrift = 48 * time.Hour
+
+// Deadline returns when a market resolves. Platforms often set EndDate to
+// when trading stops or to the end of the day, while the title names the
+// moment the price is read ("on Jan 20 at 12pm ET"). If the title names a
+// time of day, the deadline is that time on the date the title names, or on
+// EndDate's date in the title's time zone. Otherwise, or if the two
+// disagree by more than a couple of days, it is EndDate. now places dates
+// such as "Friday" that leave out the week or year.
+func Deadline(market types.Market, parsed *ParsedMarket, now time.Time) time.Time {
+	end := market.EndDate
+	if parsed == nil || !parsed.Time.Set {
+		return end
+	}
+
+	zone := parsed.Time.Zone
+	if zone == nil {
+		zone = time.UTC
+	}
+	day, ok := parsed.Date.On(now.In(zone))
+	if !ok {
+		if end.IsZero() {
+			return end
+		}
+		day = end.In(zone)
+	}
+
+	deadline := time.Date(day.Year(), day.Month(), day.Day(), parsed.Time.Hour, parsed.Time.Minute, 0, 0, zone)
+	if !end.IsZero() && (deadline.Sub(end) > maxDeadlineDrift || end.Sub(deadline) > maxDeadlineDrift) {
+		return end
+	}
+	return deadline
+}
@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestDeadline(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	// Tuesday morning
+	now := time.Date(2026, 1, 20, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		title   string
+		endDate time.Time
+		want    time.Time
+	}{
+		{
+			name:    "title date and time",
+			title:   "Will Bitcoin be above $100,000 on Jan 20 at 12pm ET?",
+			endDate: time.Date(2026, 1, 21, 4, 59, 59, 0, time.UTC),
+			want:    time.Date(2026, 1, 20, 12, 0, 0, 0, eastern),
+		},
+		{
+			name:    "title time on the EndDate's day",
+			title:   "Bitcoin price at or above $100,000 at 5pm EST?",
+			endDate: time.Date(2026, 1, 20, 23, 0, 0, 0, time.UTC),
+			want:    time.Date(2026, 1, 20, 17, 0, 0, 0, eastern),
+		},
+		{
+			name:    "24-hour time in UTC",
+			title:   "Will ETH be above $3,500 tomorrow at 08:00 UTC?",
+			endDate: time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC),
+			want:    time.Date(2026, 1, 21, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "time without a zone uses the default",
+			title:   "Will the S&P 500 close above 6000 on Friday at 4pm?",
+			endDate: time.Date(2026, 1, 23, 21, 0, 0, 0, time.UTC),
+			want:    time.Date(2026, 1, 23, 16, 0, 0, 0, eastern),
+		},
+		{
+			name:    "no time keeps EndDate",
+			title:   "Will Bitcoin be above $100,000 on January 20?",
+			endDate: time.Date(2026, 1, 20, 17, 0, 0, 0, time.UTC),
+			want:    time.Date(2026, 1, 20, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "title far from EndDate keeps EndDate",
+			title:   "Will Bitcoin be above $100,000 on March 20 at 12pm ET?",
+			endDate: time.Date(2026, 1, 21, 4, 59, 59, 0, time.UTC),
+			want:    time.Date(2026, 1, 21, 4, 59, 59, 0, time.UTC),
+		},
+		{
+			name:  "no EndDate uses the title",
+			title: "Will Bitcoin be above $100,000 on Jan 20 at 12pm ET?",
+			want:  time.Date(2026, 1, 20, 12, 0, 0, 0, eastern),
+		},
+		{
+			name:  "no EndDate and no title date",
+			title: "Bitcoin price at or above $100,000 at 5pm EST?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseMarketTitle(tt.title)
+			if err != nil {
+				t.Fatalf("failed to parse title: %v", err)
+			}
+			got := Deadline(types.Market{Title: tt.title, EndDate: tt.endDate}, parsed, now)
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseMarketTitle_Time(t *testing.T) {
+	tests := []struct {
+		title  string
+		hour   int
+		minute int
+		zone   string
+	}{
+		{"Will the Bitcoin price be above 104999.99 at 5pm EST?", 17, 0, "America/New_York"},
+		{"Bitcoin above $100k on Jan 20 at 12pm ET?", 12, 0, "America/New_York"},
+		{"Bitcoin above $100k at 12:30 am PT?", 0, 30, "America/Los_Angeles"},
+		{"ETH above $3,500 at 08:00 UTC?", 8, 0, "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			parsed, err := ParseMarketTitle(tt.title)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := parsed.Time
+			if !got.Set || got.Hour != tt.hour || got.Minute != tt.minute || got.Zone.String() != tt.zone {
+				t.Errorf("expected %02d:%02d %s, got %+v", tt.hour, tt.minute, tt.zone, got)
+			}
+		})
+	}
+
+	parsed, err := ParseMarketTitle("Will Bitcoin be above $100,000 on January 18?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Time.Set {
+		t.Errorf("expected no time of day, got %+v", parsed.Time)
+	}
+}
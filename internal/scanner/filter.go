@@ -9,30 +9,81 @@ import (
 )
 
 const (
-	// MaxTimeToResolution is the maximum time allowed until market closes (48 hours)
+	// MaxTimeToResolution is the default maximum time allowed until market
+	// closes (48 hours), used when Parameters.MaxHoursToClose is unset
 	MaxTimeToResolution = 48 * time.Hour
 
-	// MinLiquidity is the minimum liquidity required in dollars
+	// MinLiquidity is the default minimum liquidity required in dollars,
+	// used when Parameters.MinLiquidity is unset
 	MinLiquidity = 100.0
 )
 
+// Eligibility criteria names, used to identify which check a market failed.
+const (
+	CriterionActive           = "active"
+	CriterionClosed           = "closed"
+	CriterionProbability      = "probability"
+	CriterionTimeToResolution = "time_to_resolution"
+	CriterionEnded            = "ended"
+	CriterionLiquidity        = "liquidity"
+	CriterionVolume           = "volume"
+	CriterionMinTimeToClose   = "min_time_to_resolution"
+	CriterionSpread           = "spread"
+	CriterionCloseBuffer      = "close_buffer"
+)
+
+// CriterionFailure describes a failed eligibility check and by how much it failed.
+type CriterionFailure struct {
+	Criterion string
+	// Value is the market's value for the criterion (probability, hours,
+	// dollars or price spread).
+	Value float64
+	// Threshold is the limit the value was checked against.
+	Threshold float64
+	// Shortfall is how far Value is on the wrong side of Threshold (always >= 0).
+	Shortfall float64
+}
+
+// IsThreshold reports whether the failure is against a tunable threshold,
+// as opposed to a market state such as being closed.
+func (f CriterionFailure) IsThreshold() bool {
+	switch f.Criterion {
+	case CriterionProbability, CriterionTimeToResolution, CriterionLiquidity,
+		CriterionVolume, CriterionMinTimeToClose, CriterionSpread:
+		return true
+	}
+	return false
+}
+
 // EligibilityResult contains the result of eligibility check
 type EligibilityResult struct {
 	Eligible    bool
 	Reasons     []string
+	Failures    []CriterionFailure
 	Probability float64
 	BetSide     string // "YES" or "NO"
 }
 
+// NearMiss returns the failure if the market failed exactly one criterion
+// and that criterion is a tunable threshold.
+func (r EligibilityResult) NearMiss() (CriterionFailure, bool) {
+	if len(r.Failures) != 1 || !r.Failures[0].IsThreshold() {
+		return CriterionFailure{}, false
+	}
+	return r.Failures[0], true
+}
+
 // EligibilityFilter checks if markets meet the eligibility criteria
 type EligibilityFilter struct {
 	params config.Parameters
+	now    func() time.Time
 }
 
 // NewEligibilityFilter creates a new eligibility filter with the given parameters
 func NewEligibilityFilter(params config.Parameters) *EligibilityFilter {
 	return &EligibilityFilter{
 		params: params,
+		now:    time.Now,
 	}
 }
 
@@ -59,12 +110,14 @@ func (f *EligibilityFilter) IsEligible(market types.Market) EligibilityResult {
 	if !market.Active {
 		result.Eligible = false
 		result.Reasons = append(result.Reasons, "market is not active")
+		result.Failures = append(result.Failures, CriterionFailure{Criterion: CriterionActive})
 	}
 
 	// Check if market is already closed
 	if market.Closed {
 		result.Eligible = false
 		result.Reasons = append(result.Reasons, "market is already closed")
+		result.Failures = append(result.Failures, CriterionFailure{Criterion: CriterionClosed})
 	}
 
 	// Check probability threshold
@@ -73,30 +126,130 @@ func (f *EligibilityFilter) IsEligible(market types.Market) EligibilityResult {
 		result.Reasons = append(result.Reasons,
 			fmt.Sprintf("probability %.2f%% is below threshold %.2f%%",
 				result.Probability*100, f.params.ProbabilityThreshold*100))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionProbability,
+			Value:     result.Probability,
+			Threshold: f.params.ProbabilityThreshold,
+			Shortfall: f.params.ProbabilityThreshold - result.Probability,
+		})
 	}
 
 	// Check time to resolution
-	timeToResolution := time.Until(market.EndDate)
-	if timeToResolution > MaxTimeToResolution {
+	timeToResolution := market.EndDate.Sub(f.now())
+	maxTimeToResolution := f.maxTimeToResolution()
+	if timeToResolution > maxTimeToResolution {
 		result.Eligible = false
 		result.Reasons = append(result.Reasons,
 			fmt.Sprintf("time to resolution %.1fh exceeds max %.1fh",
-				timeToResolution.Hours(), MaxTimeToResolution.Hours()))
+				timeToResolution.Hours(), maxTimeToResolution.Hours()))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionTimeToResolution,
+			Value:     timeToResolution.Hours(),
+			Threshold: maxTimeToResolution.Hours(),
+			Shortfall: (timeToResolution - maxTimeToResolution).Hours(),
+		})
+	}
+
+	// Check minimum time to resolution (ended markets are reported below)
+	minHours := f.params.MinHoursToClose
+	if minHours > 0 && timeToResolution >= 0 && timeToResolution.Hours() < minHours {
+		result.Eligible = false
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("time to resolution %.1fh is below min %.1fh",
+				timeToResolution.Hours(), minHours))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionMinTimeToClose,
+			Value:     timeToResolution.Hours(),
+			Threshold: minHours,
+			Shortfall: minHours - timeToResolution.Hours(),
+		})
+	}
+
+	// Never enter a market closing within the buffer: there is no time left
+	// for the safety margin to play out or for a stop to be managed
+	closeBuffer := time.Duration(f.params.MinMinutesToClose * float64(time.Minute))
+	if closeBuffer > 0 && timeToResolution >= 0 && timeToResolution < closeBuffer {
+		result.Eligible = false
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("time to resolution %.0fm is within the %.0fm close buffer",
+				timeToResolution.Minutes(), closeBuffer.Minutes()))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionCloseBuffer,
+			Value:     timeToResolution.Minutes(),
+			Threshold: closeBuffer.Minutes(),
+			Shortfall: (closeBuffer - timeToResolution).Minutes(),
+		})
 	}
 
 	// Check if market has already ended
 	if timeToResolution < 0 {
 		result.Eligible = false
 		result.Reasons = append(result.Reasons, "market has already ended")
+		result.Failures = append(result.Failures, CriterionFailure{Criterion: CriterionEnded})
 	}
 
 	// Check liquidity
-	if market.Liquidity < MinLiquidity {
+	minLiquidity := f.minLiquidity()
+	if market.Liquidity < minLiquidity {
 		result.Eligible = false
 		result.Reasons = append(result.Reasons,
 			fmt.Sprintf("liquidity $%.2f is below minimum $%.2f",
-				market.Liquidity, MinLiquidity))
+				market.Liquidity, minLiquidity))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionLiquidity,
+			Value:     market.Liquidity,
+			Threshold: minLiquidity,
+			Shortfall: minLiquidity - market.Liquidity,
+		})
+	}
+
+	// Check volume
+	minVolume := f.params.MinVolume
+	if minVolume > 0 && market.Volume < minVolume {
+		result.Eligible = false
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("volume $%.2f is below minimum $%.2f",
+				market.Volume, minVolume))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionVolume,
+			Value:     market.Volume,
+			Threshold: minVolume,
+			Shortfall: minVolume - market.Volume,
+		})
+	}
+
+	// Check spread (skipped when the platform does not report one)
+	maxSpread := f.params.MaxSpread
+	if maxSpread > 0 && market.Spread > maxSpread {
+		result.Eligible = false
+		result.Reasons = append(result.Reasons,
+			fmt.Sprintf("spread %.2f exceeds max %.2f",
+				market.Spread, maxSpread))
+		result.Failures = append(result.Failures, CriterionFailure{
+			Criterion: CriterionSpread,
+			Value:     market.Spread,
+			Threshold: maxSpread,
+			Shortfall: market.Spread - maxSpread,
+		})
 	}
 
 	return result
 }
+
+// maxTimeToResolution returns the configured maximum time to close, or
+// MaxTimeToResolution if unset.
+func (f *EligibilityFilter) maxTimeToResolution() time.Duration {
+	if f.params.MaxHoursToClose > 0 {
+		return time.Duration(f.params.MaxHoursToClose * float64(time.Hour))
+	}
+	return MaxTimeToResolution
+}
+
+// minLiquidity returns the configured minimum liquidity, or MinLiquidity if
+// unset.
+func (f *EligibilityFilter) minLiquidity() float64 {
+	if f.params.MinLiquidity > 0 {
+		return f.params.MinLiquidity
+	}
+	return MinLiquidity
+}
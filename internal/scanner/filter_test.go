@@ -276,3 +276,152 @@ func containsIgnoreCase(s, substr string) bool {
 			containsIgnoreCase(s[1:], substr[1:])) ||
 		(len(s) > 0 && containsIgnoreCase(s[1:], substr)))
 }
+
+func TestIsEligible_NearMissSingleThreshold(t *testing.T) {
+	params := config.Parameters{
+		ProbabilityThreshold: 0.80,
+	}
+
+	// Market: prob=85%, closes=60h, liquidity=$500 → fails only the time window by 12h
+	market := types.Market{
+		ID:              "test-near-miss",
+		Platform:        "polymarket",
+		Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+		EndDate:         time.Now().Add(60 * time.Hour),
+		Liquidity:       500.0,
+		Active:          true,
+		OutcomeYesPrice: 0.85,
+	}
+
+	filter := NewEligibilityFilter(params)
+	result := filter.IsEligible(market)
+
+	failure, ok := result.NearMiss()
+	if !ok {
+		t.Fatalf("Expected near miss, got failures %v", result.Failures)
+	}
+	if failure.Criterion != CriterionTimeToResolution {
+		t.Errorf("Expected criterion %s, got %s", CriterionTimeToResolution, failure.Criterion)
+	}
+	if failure.Shortfall < 11.9 || failure.Shortfall > 12.0 {
+		t.Errorf("Expected shortfall ~12h, got %.2f", failure.Shortfall)
+	}
+}
+
+func TestIsEligible_NearMissRequiresExactlyOneThreshold(t *testing.T) {
+	params := config.Parameters{
+		ProbabilityThreshold: 0.80,
+	}
+
+	filter := NewEligibilityFilter(params)
+
+	// Fails probability and liquidity
+	twoFailures := types.Market{
+		EndDate:         time.Now().Add(24 * time.Hour),
+		Liquidity:       50.0,
+		Active:          true,
+		OutcomeYesPrice: 0.75,
+	}
+	if _, ok := filter.IsEligible(twoFailures).NearMiss(); ok {
+		t.Error("Expected no near miss for two failed criteria")
+	}
+
+	// Fails only a market state, not a threshold
+	inactive := types.Market{
+		EndDate:         time.Now().Add(24 * time.Hour),
+		Liquidity:       500.0,
+		Active:          false,
+		OutcomeYesPrice: 0.90,
+	}
+	if _, ok := filter.IsEligible(inactive).NearMiss(); ok {
+		t.Error("Expected no near miss for an inactive market")
+	}
+
+	// Fails only liquidity
+	lowLiquidity := types.Market{
+		EndDate:         time.Now().Add(24 * time.Hour),
+		Liquidity:       80.0,
+		Active:          true,
+		OutcomeYesPrice: 0.90,
+	}
+	failure, ok := filter.IsEligible(lowLiquidity).NearMiss()
+	if !ok || failure.Criterion != CriterionLiquidity || failure.Shortfall != 20.0 {
+		t.Errorf("Expected liquidity near miss with $20 shortfall, got %+v (ok=%v)", failure, ok)
+	}
+}
+
+func TestIsEligible_ConfigurableThresholds(t *testing.T) {
+	params := config.Parameters{
+		ProbabilityThreshold: 0.80,
+		MinLiquidity:         1000.0,
+		MinVolume:            500.0,
+		MinHoursToClose:      2.0,
+		MaxHoursToClose:      12.0,
+		MaxSpread:            0.05,
+	}
+	filter := NewEligibilityFilter(params)
+
+	base := types.Market{
+		EndDate:         time.Now().Add(6 * time.Hour),
+		Liquidity:       2000.0,
+		Volume:          1000.0,
+		Spread:          0.02,
+		Active:          true,
+		OutcomeYesPrice: 0.90,
+	}
+	if result := filter.IsEligible(base); !result.Eligible {
+		t.Fatalf("Expected market to be eligible, got reasons: %v", result.Reasons)
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(m *types.Market)
+		criterion string
+	}{
+		{"liquidity below configured minimum", func(m *types.Market) { m.Liquidity = 500.0 }, CriterionLiquidity},
+		{"volume below minimum", func(m *types.Market) { m.Volume = 100.0 }, CriterionVolume},
+		{"closes too soon", func(m *types.Market) { m.EndDate = time.Now().Add(time.Hour) }, CriterionMinTimeToClose},
+		{"closes after configured maximum", func(m *types.Market) { m.EndDate = time.Now().Add(24 * time.Hour) }, CriterionTimeToResolution},
+		{"spread too wide", func(m *types.Market) { m.Spread = 0.10 }, CriterionSpread},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := base
+			tt.modify(&market)
+
+			failure, ok := filter.IsEligible(market).NearMiss()
+			if !ok || failure.Criterion != tt.criterion {
+				t.Errorf("Expected single %s failure, got %+v (ok=%v)", tt.criterion, failure, ok)
+			}
+		})
+	}
+}
+
+func TestIsEligible_CloseBuffer(t *testing.T) {
+	filter := NewEligibilityFilter(config.Parameters{
+		ProbabilityThreshold: 0.80,
+		MinMinutesToClose:    30,
+	})
+
+	market := types.Market{
+		EndDate:         time.Now().Add(45 * time.Minute),
+		Liquidity:       500.0,
+		Active:          true,
+		OutcomeYesPrice: 0.90,
+	}
+	if result := filter.IsEligible(market); !result.Eligible {
+		t.Fatalf("Expected market outside the buffer to be eligible, got reasons: %v", result.Reasons)
+	}
+
+	market.EndDate = time.Now().Add(20 * time.Minute)
+	result := filter.IsEligible(market)
+	if result.Eligible || len(result.Failures) != 1 || result.Failures[0].Criterion != CriterionCloseBuffer {
+		t.Fatalf("Expected a single close buffer failure, got %+v", result.Failures)
+	}
+
+	// The buffer is a safety rule, not a threshold to tune
+	if _, ok := result.NearMiss(); ok {
+		t.Error("Expected close buffer rejections not to be near misses")
+	}
+}
@@ -2,158 +2,425 @@ package scanner
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"prediction-bot/pkg/types"
 )
 
+// DirectionBetween is the direction of a bracket outcome, which resolves YES
+// if the price ends between Strike and StrikeUpper.
+const DirectionBetween = "between"
+
 // ParsedMarket represents the extracted information from a market title
 type ParsedMarket struct {
-	Asset     string  // Normalized symbol (BTC, ETH, SPY, etc.)
-	Strike    float64 // Strike price
-	Direction string  // "above" or "below"
-}
-
-// Asset name to symbol mapping
-var assetMap = map[string]string{
-	"bitcoin":  "BTC",
-	"btc":      "BTC",
-	"ethereum": "ETH",
-	"eth":      "ETH",
-	"solana":   "SOL",
-	"sol":      "SOL",
-	"s&p 500":  "SPY",
-	"s&p500":   "SPY",
-	"spy":      "SPY",
-	"sp500":    "SPY",
-}
-
-// Direction keywords mapping
-var aboveKeywords = []string{"above", "over", "at or above"}
-var belowKeywords = []string{"below", "under", "at or below"}
-
-// Regex patterns
-var (
-	// Match prices like $100,000 or $100000 or $100k or 100000 or 5000
-	pricePattern = regexp.MustCompile(`\$?([\d,]+(?:\.\d+)?)(k)?`)
-
-	// Match asset names (case insensitive)
-	assetPattern = regexp.MustCompile(`(?i)\b(bitcoin|btc|ethereum|eth|solana|sol|s&p\s*500|spy|sp500)\b`)
+	Asset       string    // Normalized symbol (BTC, ETH, SPX, AAPL, etc.)
+	Strike      float64   // Strike price, the lower bound of a bracket
+	StrikeUpper float64   // Upper bound of a bracket (0 otherwise)
+	Direction   string    // "above", "below" or "between"
+	Outcome     string    // Outcome of a multi-outcome market (empty for binaries)
+	Date        TitleDate // Date named in the title (zero if none)
+	Time        TitleTime // Time of day named in the title (zero if none)
+}
+
+// Kinds of TitleDate.
+const (
+	DateCalendar = "calendar"
+	DateWeekday  = "weekday"
+	DateRelative = "relative"
 )
 
+// TitleDate is a date named in a market title. Kind says which fields are
+// set: Month, Day and, if stated, Year for a calendar date ("Jan 18, 2026"),
+// Weekday for a weekday ("Friday"), and Days for a day relative to today
+// ("tomorrow"). The zero TitleDate names no date.
+type TitleDate struct {
+	Kind    string
+	Year    int
+	Month   time.Month
+	Day     int
+	Weekday time.Weekday
+	Days    int
+}
+
+// String formats the date as "2026-01-18", "01-18" without a year,
+// "friday" or "+1d", or "" if it names no date.
+func (d TitleDate) String() string {
+	switch d.Kind {
+	case DateCalendar:
+		if d.Year == 0 {
+			return fmt.Sprintf("%02d-%02d", d.Month, d.Day)
+		}
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	case DateWeekday:
+		return strings.ToLower(d.Weekday.String())
+	case DateRelative:
+		return fmt.Sprintf("%+dd", d.Days)
+	}
+	return ""
+}
+
+// On returns the day the date names as of ref, at midnight in ref's
+// location: the next such calendar date or weekday on or after ref when the
+// title leaves the year or week out. ok is false if it names no date.
+func (d TitleDate) On(ref time.Time) (day time.Time, ok bool) {
+	today := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, ref.Location())
+	switch d.Kind {
+	case DateCalendar:
+		if d.Year != 0 {
+			return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, ref.Location()), true
+		}
+		day = time.Date(ref.Year(), d.Month, d.Day, 0, 0, 0, 0, ref.Location())
+		if day.Before(today) {
+			day = day.AddDate(1, 0, 0)
+		}
+		return day, true
+	case DateWeekday:
+		return today.AddDate(0, 0, (int(d.Weekday)-int(today.Weekday())+7)%7), true
+	case DateRelative:
+		return today.AddDate(0, 0, d.Days), true
+	}
+	return time.Time{}, false
+}
+
+// TitleTime is a time of day named in a market title, such as "5pm EST".
+// Zone is the time zone named after it, or the rules' default time zone.
+// The zero TitleTime names no time.
+type TitleTime struct {
+	Set    bool
+	Hour   int
+	Minute int
+	Zone   *time.Location
+}
+
 // ParseMarketTitle parses a market title and extracts asset, strike, and direction
 func ParseMarketTitle(title string) (*ParsedMarket, error) {
-	titleLower := strings.ToLower(title)
+	return defaultParser.Load().ParseTitle(title)
+}
 
-	// Extract asset
-	asset, err := extractAsset(titleLower)
-	if err != nil {
-		return nil, err
+// ParseOutcome parses one outcome of a multi-outcome market, such as the
+// "$95,000 to $99,999.99" bracket of "Bitcoin price range on Friday?". The
+// asset comes from the market title and the strike and direction from the
+// outcome name: a range ("X to Y", "X-Y", "between X and Y") is a bracket,
+// and a single price with "or above", "or below" or similar is open-ended.
+func ParseOutcome(title, outcome string) (*ParsedMarket, error) {
+	return defaultParser.Load().ParseOutcome(title, outcome)
+}
+
+// ParseListedMarket parses a market for trading: the outcome of a
+// multi-outcome market with ParseOutcome, and a binary from its title. A
+// market whose title does not name the asset takes it from its series
+// ticker, such as KXINX for the S&P 500.
+func ParseListedMarket(market types.Market) (*ParsedMarket, error) {
+	return defaultParser.Load().ParseMarket(market)
+}
+
+// ParseMarket parses a listed market. See ParseListedMarket.
+func (p *Parser) ParseMarket(market types.Market) (*ParsedMarket, error) {
+	fallback := p.tickerAsset(market.ID, market.ConditionID)
+	if market.Outcome != "" {
+		return p.parseOutcome(market.Title, market.Outcome, fallback)
 	}
+	return p.parseTitle(market.Title, fallback)
+}
+
+// ParseTitle parses a market title: the asset, the strike, the direction
+// and the date. Two strikes joined by a range separator are a bracket.
+func (p *Parser) ParseTitle(title string) (*ParsedMarket, error) {
+	return p.parseTitle(title, "")
+}
 
-	// Extract strike price
-	strike, err := extractStrike(title)
+// parseTitle parses a market title, with fallback as the asset if the title
+// names none.
+func (p *Parser) parseTitle(title, fallback string) (*ParsedMarket, error) {
+	asset, err := p.extractAsset(title)
 	if err != nil {
-		return nil, err
+		if fallback == "" {
+			return nil, err
+		}
+		asset = fallback
 	}
 
-	// Extract direction
-	direction, err := extractDirection(titleLower)
-	if err != nil {
-		return nil, err
+	date, rest := p.extractDate(title)
+	prices := p.extractPrices(rest)
+	if len(prices) == 0 {
+		return nil, errors.New("no strike price found in title")
 	}
 
-	return &ParsedMarket{
-		Asset:     asset,
-		Strike:    strike,
-		Direction: direction,
-	}, nil
+	parsed := &ParsedMarket{Asset: asset, Date: date, Time: p.extractTime(title)}
+	if lower, upper, ok := p.extractRange(rest, prices); ok {
+		parsed.Strike, parsed.StrikeUpper, parsed.Direction = lower, upper, DirectionBetween
+		return parsed, nil
+	}
+
+	direction, ok := p.extractDirection(rest)
+	if !ok {
+		return nil, errors.New("no direction (above/below) found in title")
+	}
+	parsed.Strike = strikePrice(prices)
+	parsed.Direction = direction
+	return parsed, nil
+}
+
+// ParseOutcome parses one outcome of a multi-outcome market. See the
+// package-level ParseOutcome.
+func (p *Parser) ParseOutcome(title, outcome string) (*ParsedMarket, error) {
+	return p.parseOutcome(title, outcome, "")
 }
 
-// extractAsset finds and normalizes the asset name from the title
-func extractAsset(titleLower string) (string, error) {
-	matches := assetPattern.FindStringSubmatch(titleLower)
-	if len(matches) < 2 {
-		return "", errors.New("no recognized asset found in title")
+// parseOutcome parses one outcome of a multi-outcome market, with fallback
+// as the asset if neither the title nor the outcome names one.
+func (p *Parser) parseOutcome(title, outcome, fallback string) (*ParsedMarket, error) {
+	asset, err := p.extractAsset(title)
+	if err != nil {
+		if asset, err = p.extractAsset(outcome); err != nil {
+			if fallback == "" {
+				return nil, err
+			}
+			asset = fallback
+		}
 	}
 
-	assetName := strings.ToLower(matches[1])
-	// Normalize S&P variations
-	assetName = strings.ReplaceAll(assetName, " ", "")
-	assetName = strings.ReplaceAll(assetName, "&", "&")
+	date, _ := p.extractDate(title)
+	_, rest := p.extractDate(outcome)
+	prices := p.extractPrices(rest)
+	if len(prices) == 0 {
+		return nil, errors.New("no strike price found in outcome")
+	}
 
-	if symbol, ok := assetMap[assetName]; ok {
-		return symbol, nil
+	parsed := &ParsedMarket{Asset: asset, Outcome: outcome, Date: date, Time: p.extractTime(title)}
+	if lower, upper, ok := p.extractRange(rest, prices); ok {
+		parsed.Strike, parsed.StrikeUpper, parsed.Direction = lower, upper, DirectionBetween
+		return parsed, nil
 	}
 
-	// Try with spaces removed for S&P
-	assetName = strings.ReplaceAll(assetName, " ", "")
-	if symbol, ok := assetMap[assetName]; ok {
-		return symbol, nil
+	direction, ok := p.extractDirection(rest)
+	if !ok {
+		return nil, errors.New("no direction (range, above or below) found in outcome")
 	}
+	parsed.Strike = strikePrice(prices)
+	parsed.Direction = direction
+	return parsed, nil
+}
 
+// extractAsset finds the first asset named in s and returns its symbol.
+func (p *Parser) extractAsset(s string) (string, error) {
+	name := p.assets.FindString(s)
+	if symbol, ok := p.symbols[strings.Join(strings.Fields(strings.ToLower(name)), "")]; ok && name != "" {
+		return symbol, nil
+	}
 	return "", errors.New("no recognized asset found in title")
 }
 
-// Patterns for asset names that contain numbers (to be excluded from price extraction)
-var assetWithNumberPattern = regexp.MustCompile(`(?i)s&p\s*500|sp500`)
+// tickerAsset returns the asset of the first ticker whose series is
+// configured, or "" if none is.
+func (p *Parser) tickerAsset(tickers ...string) string {
+	for _, ticker := range tickers {
+		series, _, _ := strings.Cut(ticker, "-")
+		if symbol, ok := p.tickers[strings.ToUpper(series)]; ok {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// extractDirection returns the direction of the longest direction phrase in
+// s, the earliest on a tie.
+func (p *Parser) extractDirection(s string) (string, bool) {
+	var best *phrase
+	bestAt := -1
+	for i := range p.directions {
+		ph := &p.directions[i]
+		loc := ph.pattern.FindStringIndex(s)
+		if loc == nil {
+			continue
+		}
+		if best == nil || ph.length > best.length || ph.length == best.length && loc[0] < bestAt {
+			best, bestAt = ph, loc[0]
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.direction, true
+}
 
-// extractStrike finds the strike price from the title
-func extractStrike(title string) (float64, error) {
-	// Remove asset names that contain numbers to avoid confusion
-	cleanedTitle := assetWithNumberPattern.ReplaceAllString(title, "")
+// price is a number read from a title.
+type price struct {
+	value      float64
+	marked     bool // Written with "$" or a suffix, so certainly a price
+	start, end int
+}
 
-	matches := pricePattern.FindAllStringSubmatch(cleanedTitle, -1)
-	if len(matches) == 0 {
-		return 0, errors.New("no strike price found in title")
+// extractPrices returns every price in s, in order. Asset names containing
+// digits ("S&P 500") and times of day ("5pm EST") are not prices.
+func (p *Parser) extractPrices(s string) []price {
+	if p.numericAssets != nil {
+		s = blank(p.numericAssets, s)
 	}
+	s = blank(p.timeOfDay, s)
 
-	// Use the first price found
-	for _, match := range matches {
-		if len(match) >= 2 {
-			priceStr := strings.ReplaceAll(match[1], ",", "")
-			price, err := strconv.ParseFloat(priceStr, 64)
-			if err != nil {
-				continue
-			}
+	var prices []price
+	for _, m := range p.price.FindAllStringSubmatchIndex(s, -1) {
+		// Digits inside a word ("Q3") are not prices
+		if r, _ := utf8.DecodeLastRuneInString(s[:m[0]]); m[0] > 0 && unicode.IsLetter(r) {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(s[m[4]:m[5]], ",", ""), 64)
+		if err != nil || value <= 0 {
+			continue
+		}
+		marked := m[2] >= 0
+		if len(m) > 6 && m[6] >= 0 {
+			value *= p.suffixes[strings.ToLower(s[m[6]:m[7]])]
+			marked = true
+		}
+		prices = append(prices, price{value: value, marked: marked, start: m[0], end: m[1]})
+	}
+	return prices
+}
 
-			// Handle "k" suffix (e.g., $100k = $100,000)
-			if len(match) >= 3 && strings.ToLower(match[2]) == "k" {
-				price *= 1000
-			}
+// extractRange returns the bounds of the first two consecutive prices in s
+// joined by a range separator, lowest first.
+func (p *Parser) extractRange(s string, prices []price) (lower, upper float64, ok bool) {
+	for i := 0; i+1 < len(prices); i++ {
+		sep := normalizePhrase(s[prices[i].end:prices[i+1].start])
+		if p.separators[sep] {
+			a, b := prices[i].value, prices[i+1].value
+			return min(a, b), max(a, b), true
+		}
+	}
+	return 0, 0, false
+}
+
+// strikePrice returns the first price written as one, or the first number
+// if none is.
+func strikePrice(prices []price) float64 {
+	for _, pr := range prices {
+		if pr.marked {
+			return pr.value
+		}
+	}
+	return prices[0].value
+}
 
-			if price > 0 {
-				return price, nil
+// extractDate returns the first date named in s, and s with every date
+// blanked out so their numbers aren't read as prices.
+func (p *Parser) extractDate(s string) (TitleDate, string) {
+	var date TitleDate
+	at := len(s) + 1
+	found := func(loc []int, d TitleDate) {
+		if loc[0] < at {
+			date, at = d, loc[0]
+		}
+	}
+
+	for _, m := range p.monthDate.FindAllStringSubmatchIndex(s, -1) {
+		month := p.months[normalizePhrase(s[m[2]:m[3]])]
+		day, _ := strconv.Atoi(s[m[4]:m[5]])
+		year := 0
+		if m[6] >= 0 {
+			year, _ = strconv.Atoi(s[m[6]:m[7]])
+		}
+		if d, ok := calendarDate(year, month, day); ok {
+			found(m, d)
+		}
+	}
+	for _, m := range p.isoDate.FindAllStringSubmatchIndex(s, -1) {
+		year, _ := strconv.Atoi(s[m[2]:m[3]])
+		month, _ := strconv.Atoi(s[m[4]:m[5]])
+		day, _ := strconv.Atoi(s[m[6]:m[7]])
+		if d, ok := calendarDate(year, month, day); ok {
+			found(m, d)
+		}
+	}
+	for _, m := range p.numericDate.FindAllStringSubmatchIndex(s, -1) {
+		month, _ := strconv.Atoi(s[m[2]:m[3]])
+		day, _ := strconv.Atoi(s[m[4]:m[5]])
+		year := 0
+		if m[6] >= 0 {
+			year, _ = strconv.Atoi(s[m[6]:m[7]])
+			if year < 100 {
+				year += 2000
 			}
 		}
+		if d, ok := calendarDate(year, month, day); ok {
+			found(m, d)
+		}
+	}
+	if p.weekdayDate != nil {
+		if loc := p.weekdayDate.FindStringIndex(s); loc != nil {
+			found(loc, TitleDate{Kind: DateWeekday, Weekday: p.weekdays[normalizePhrase(s[loc[0]:loc[1]])]})
+		}
+	}
+	if p.relativeDate != nil {
+		if loc := p.relativeDate.FindStringIndex(s); loc != nil {
+			found(loc, TitleDate{Kind: DateRelative, Days: p.relativeDays[normalizePhrase(s[loc[0]:loc[1]])]})
+		}
 	}
 
-	return 0, errors.New("no valid strike price found in title")
+	for _, pattern := range []*regexp.Regexp{p.monthDate, p.isoDate, p.numericDate} {
+		s = blank(pattern, s)
+	}
+	return date, s
 }
 
-// extractDirection determines if the market is betting above or below
-func extractDirection(titleLower string) (string, error) {
-	// Check for "at or above" / "at or below" first (more specific)
-	if strings.Contains(titleLower, "at or above") {
-		return "above", nil
-	}
-	if strings.Contains(titleLower, "at or below") {
-		return "below", nil
+// extractTime returns the first time of day named in s, in the time zone
+// named after it.
+func (p *Parser) extractTime(s string) TitleTime {
+	m := p.timeOfDay.FindStringSubmatchIndex(s)
+	if m == nil {
+		return TitleTime{}
 	}
 
-	// Check for above keywords
-	for _, keyword := range aboveKeywords {
-		if strings.Contains(titleLower, keyword) {
-			return "above", nil
+	var hour, minute int
+	if m[2] >= 0 {
+		// 12-hour clock: "5pm", "12:30 am"
+		hour, _ = strconv.Atoi(s[m[2]:m[3]])
+		if m[4] >= 0 {
+			minute, _ = strconv.Atoi(s[m[4]:m[5]])
+		}
+		if hour < 1 || hour > 12 {
+			return TitleTime{}
+		}
+		hour %= 12
+		if strings.EqualFold(s[m[6]:m[7]], "pm") {
+			hour += 12
 		}
+	} else {
+		hour, _ = strconv.Atoi(s[m[8]:m[9]])
+		minute, _ = strconv.Atoi(s[m[10]:m[11]])
+	}
+	if hour > 23 || minute > 59 {
+		return TitleTime{}
 	}
 
-	// Check for below keywords
-	for _, keyword := range belowKeywords {
-		if strings.Contains(titleLower, keyword) {
-			return "below", nil
+	zone := p.defaultZone
+	if p.timeZone != nil {
+		if z := p.timeZone.FindStringSubmatch(s[m[1]:]); z != nil {
+			zone = p.zones[normalizePhrase(z[1])]
 		}
 	}
+	return TitleTime{Set: true, Hour: hour, Minute: minute, Zone: zone}
+}
+
+// calendarDate returns the calendar date, if month and day are valid.
+func calendarDate(year, month, day int) (TitleDate, bool) {
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return TitleDate{}, false
+	}
+	return TitleDate{Kind: DateCalendar, Year: year, Month: time.Month(month), Day: day}, true
+}
 
-	return "", errors.New("no direction (above/below) found in title")
+// blank replaces the matches of pattern in s with spaces, keeping the
+// positions of the rest of s.
+func blank(pattern *regexp.Regexp, s string) string {
+	return pattern.ReplaceAllStringFunc(s, func(m string) string {
+		return strings.Repeat(" ", len(m))
+	})
 }
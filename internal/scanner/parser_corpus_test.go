@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const corpusPath = "testdata/parser_corpus.txt"
+
+// corpusEntry is one market title from the parser corpus with its expected
+// parse, or nil if it is expected to be unparseable.
+type corpusEntry struct {
+	line     int
+	title    string
+	expected *ParsedMarket
+	date     string // Expected TitleDate.String(), empty for none
+}
+
+// loadCorpus reads the parser corpus, skipping comments and blank lines.
+func loadCorpus(t *testing.T) []corpusEntry {
+	t.Helper()
+
+	file, err := os.Open(corpusPath)
+	if err != nil {
+		t.Fatalf("open corpus: %v", err)
+	}
+	defer file.Close()
+
+	var entries []corpusEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry, err := parseCorpusLine(text)
+		if err != nil {
+			t.Fatalf("%s:%d: %v", corpusPath, line, err)
+		}
+		entry.line = line
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read corpus: %v", err)
+	}
+	return entries
+}
+
+// parseCorpusLine parses "<title> | <asset> <strike> <direction> [<date>]"
+// or "<title> | unparseable". A bracket's strike is "<lower>-<upper>", and
+// the date is formatted as TitleDate.String.
+func parseCorpusLine(text string) (corpusEntry, error) {
+	sep := strings.LastIndex(text, "|")
+	if sep < 0 {
+		return corpusEntry{}, fmt.Errorf("missing '|' between title and expected result")
+	}
+	entry := corpusEntry{title: strings.TrimSpace(text[:sep])}
+
+	fields := strings.Fields(text[sep+1:])
+	if len(fields) == 1 && fields[0] == "unparseable" {
+		return entry, nil
+	}
+	if len(fields) != 3 && len(fields) != 4 {
+		return entry, fmt.Errorf("expected '<asset> <strike> <direction> [<date>]' or 'unparseable', got %q", text[sep+1:])
+	}
+	expected := &ParsedMarket{Asset: fields[0], Direction: fields[2]}
+	lower, upper, isRange := strings.Cut(fields[1], "-")
+	strike, err := strconv.ParseFloat(lower, 64)
+	if err != nil {
+		return entry, fmt.Errorf("invalid strike %q: %w", fields[1], err)
+	}
+	expected.Strike = strike
+	if isRange {
+		if expected.StrikeUpper, err = strconv.ParseFloat(upper, 64); err != nil {
+			return entry, fmt.Errorf("invalid strike %q: %w", fields[1], err)
+		}
+	}
+	if len(fields) == 4 {
+		entry.date = fields[3]
+	}
+	entry.expected = expected
+	return entry, nil
+}
+
+func TestParseMarketTitle_Corpus(t *testing.T) {
+	entries := loadCorpus(t)
+	if len(entries) == 0 {
+		t.Fatal("corpus is empty")
+	}
+
+	parsed := 0
+	for _, entry := range entries {
+		result, err := ParseMarketTitle(entry.title)
+		if err == nil {
+			parsed++
+		}
+
+		switch {
+		case entry.expected == nil && err == nil:
+			t.Errorf("line %d: %q: expected unparseable, got %+v", entry.line, entry.title, *result)
+		case entry.expected != nil && err != nil:
+			t.Errorf("line %d: %q: expected %+v, got error: %v", entry.line, entry.title, *entry.expected, err)
+		case entry.expected != nil:
+			got := *result
+			got.Date = TitleDate{}
+			got.Time = TitleTime{}
+			if got != *entry.expected || result.Date.String() != entry.date {
+				t.Errorf("line %d: %q: expected %+v on %q, got %+v on %q",
+					entry.line, entry.title, *entry.expected, entry.date, got, result.Date)
+			}
+		}
+	}
+
+	t.Logf("parsed %d of %d corpus titles", parsed, len(entries))
+}
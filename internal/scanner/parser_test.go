@@ -2,6 +2,9 @@ package scanner
 
 import (
 	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
 )
 
 func TestParseMarketTitle_Bitcoin_Above(t *testing.T) {
@@ -79,8 +82,8 @@ func TestParseMarketTitle_SP500_Above(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if result.Asset != "SPY" {
-		t.Errorf("expected Asset='SPY', got '%s'", result.Asset)
+	if result.Asset != "SPX" {
+		t.Errorf("expected Asset='SPX', got '%s'", result.Asset)
 	}
 
 	if result.Strike != 5000 {
@@ -211,3 +214,128 @@ func TestParseMarketTitle_AtOrBelow(t *testing.T) {
 		t.Errorf("expected Direction='below', got '%s'", result.Direction)
 	}
 }
+
+func TestParseOutcome(t *testing.T) {
+	title := "Bitcoin price range on Friday?"
+	tests := []struct {
+		outcome   string
+		strike    float64
+		upper     float64
+		direction string
+	}{
+		{"$95,000 to $99,999.99", 95000, 99999.99, DirectionBetween},
+		{"$95k-$100k", 95000, 100000, DirectionBetween},
+		{"Between $100,000 and $95,000", 95000, 100000, DirectionBetween},
+		{"$100,000 or above", 100000, 0, "above"},
+		{"$105,000+", 105000, 0, "above"},
+		{"$90,000 or less", 90000, 0, "below"},
+		{"Under $90k", 90000, 0, "below"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome, func(t *testing.T) {
+			result, err := ParseOutcome(title, tt.outcome)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Asset != "BTC" || result.Outcome != tt.outcome {
+				t.Errorf("expected BTC outcome %q, got %+v", tt.outcome, result)
+			}
+			if result.Strike != tt.strike || result.StrikeUpper != tt.upper || result.Direction != tt.direction {
+				t.Errorf("expected %v-%v %s, got %v-%v %s",
+					tt.strike, tt.upper, tt.direction, result.Strike, result.StrikeUpper, result.Direction)
+			}
+		})
+	}
+
+	if _, err := ParseOutcome(title, "$100,000"); err == nil {
+		t.Error("expected error for an outcome without a direction")
+	}
+	if _, err := ParseOutcome("Who will win the election?", "$100,000 or above"); err == nil {
+		t.Error("expected error for an outcome without an asset")
+	}
+}
+
+func TestParseMarketTitle_Date(t *testing.T) {
+	tests := []struct {
+		title string
+		date  TitleDate
+	}{
+		{"Will Bitcoin be above $100,000 on January 18?", TitleDate{Kind: DateCalendar, Month: time.January, Day: 18}},
+		{"Bitcoin above $100k on Jan 18, 2026 at 5pm EST?", TitleDate{Kind: DateCalendar, Year: 2026, Month: time.January, Day: 18}},
+		{"BTC above $100k on 12/31/25?", TitleDate{Kind: DateCalendar, Year: 2025, Month: time.December, Day: 31}},
+		{"ETH under 3k by Friday?", TitleDate{Kind: DateWeekday, Weekday: time.Friday}},
+		{"Will ETH be under $3,000 tomorrow?", TitleDate{Kind: DateRelative, Days: 1}},
+		{"Will ETH be under $3,000 this week?", TitleDate{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			result, err := ParseMarketTitle(tt.title)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Date != tt.date {
+				t.Errorf("expected date %+v, got %+v", tt.date, result.Date)
+			}
+		})
+	}
+}
+
+func TestTitleDate_On(t *testing.T) {
+	// Wednesday
+	ref := time.Date(2026, 1, 14, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		date TitleDate
+		want time.Time
+	}{
+		{TitleDate{Kind: DateCalendar, Month: time.January, Day: 18}, time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{TitleDate{Kind: DateCalendar, Month: time.January, Day: 2}, time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{TitleDate{Kind: DateCalendar, Year: 2025, Month: time.December, Day: 31}, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{TitleDate{Kind: DateWeekday, Weekday: time.Friday}, time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{TitleDate{Kind: DateWeekday, Weekday: time.Wednesday}, time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{TitleDate{Kind: DateRelative, Days: 1}, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date.String(), func(t *testing.T) {
+			got, ok := tt.date.On(ref)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s (ok=%v)", tt.want, got, ok)
+			}
+		})
+	}
+
+	if _, ok := (TitleDate{}).On(ref); ok {
+		t.Error("expected no day for the zero date")
+	}
+}
+
+func TestParseListedMarket_AssetFromTicker(t *testing.T) {
+	// Kalshi index markets often leave the index out of the title
+	market := types.Market{
+		ID:          "KXINX-26JAN20H1600-B6000",
+		ConditionID: "KXINX-26JAN20H1600",
+		Platform:    "kalshi",
+		Title:       "Will the index close at or above 6,000 on Jan 20, 2026?",
+	}
+	parsed, err := ParseListedMarket(market)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Asset != "SPX" || parsed.Strike != 6000 || parsed.Direction != "above" {
+		t.Errorf("expected SPX 6000 above, got %+v", parsed)
+	}
+
+	// A title that names the asset takes precedence
+	market.Title = "Will the Nasdaq 100 close at or above 21,000 on Jan 20, 2026?"
+	if parsed, err := ParseListedMarket(market); err != nil || parsed.Asset != "NDX" {
+		t.Errorf("expected NDX from the title, got %+v (%v)", parsed, err)
+	}
+
+	market.ID, market.ConditionID = "0xabc", ""
+	market.Title = "Will the index close at or above 6,000 on Jan 20, 2026?"
+	if _, err := ParseListedMarket(market); err == nil {
+		t.Error("expected error without an asset in the title or ticker")
+	}
+}
@@ -0,0 +1,357 @@
+package scanner
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+	// Embedded so time zones named in titles load on hosts without zoneinfo
+	_ "time/tzdata"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rules.yaml
+var defaultRulesYAML []byte
+
+// Rules configure the market title parser. See rules.yaml for the built-in
+// rules and the format.
+type Rules struct {
+	// Assets maps each asset symbol to the names titles use for it.
+	Assets map[string][]string `yaml:"assets"`
+	// Directions maps "above" and "below" to the phrases that mean them.
+	Directions map[string][]string `yaml:"directions"`
+	// Tickers maps platform series tickers to asset symbols, for markets
+	// whose title does not name the asset.
+	Tickers map[string]string `yaml:"tickers"`
+	Strikes StrikeRules       `yaml:"strikes"`
+	Dates   DateRules         `yaml:"dates"`
+}
+
+// StrikeRules configure how strike prices are read.
+type StrikeRules struct {
+	// Suffixes maps strike suffixes to multipliers, such as k to 1000.
+	Suffixes map[string]float64 `yaml:"suffixes"`
+	// RangeSeparators join the bounds of a bracket, such as "to" in
+	// "$95,000 to $99,999.99".
+	RangeSeparators []string `yaml:"range_separators"`
+}
+
+// DateRules configure how dates are read.
+type DateRules struct {
+	// Months maps month names and abbreviations to month numbers.
+	Months map[string]int `yaml:"months"`
+	// Weekdays maps weekday names and abbreviations to the English weekday.
+	Weekdays map[string]string `yaml:"weekdays"`
+	// RelativeDays maps words such as "tomorrow" to days from today.
+	RelativeDays map[string]int `yaml:"relative_days"`
+	// TimeZones maps the zone names that follow a time of day, such as
+	// "EST", to IANA time zones.
+	TimeZones map[string]string `yaml:"time_zones"`
+	// DefaultTimeZone is the IANA time zone of a time of day given without
+	// one.
+	DefaultTimeZone string `yaml:"default_time_zone"`
+}
+
+// DefaultRules returns the built-in parser rules.
+func DefaultRules() Rules {
+	var rules Rules
+	if err := yaml.Unmarshal(defaultRulesYAML, &rules); err != nil {
+		panic(fmt.Sprintf("parse built-in parser rules: %v", err))
+	}
+	return rules
+}
+
+// LoadRules reads parser rules from a YAML file. The file overrides the
+// built-in rules key by key: an asset, direction or suffix it lists replaces
+// the built-in one, and the rest of the built-in rules are kept.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("read parser rules: %w", err)
+	}
+
+	rules := DefaultRules()
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return Rules{}, fmt.Errorf("parse parser rules: %w", err)
+	}
+	return rules, nil
+}
+
+// defaultParser parses titles for ParseMarketTitle, ParseOutcome and
+// ParseListedMarket.
+var defaultParser atomic.Pointer[Parser]
+
+func init() {
+	parser, err := NewParser(DefaultRules())
+	if err != nil {
+		panic(fmt.Sprintf("compile built-in parser rules: %v", err))
+	}
+	defaultParser.Store(parser)
+}
+
+// SetRules replaces the rules ParseMarketTitle, ParseOutcome and
+// ParseListedMarket parse with.
+func SetRules(rules Rules) error {
+	parser, err := NewParser(rules)
+	if err != nil {
+		return err
+	}
+	defaultParser.Store(parser)
+	return nil
+}
+
+// phrase is a direction phrase compiled to a pattern.
+type phrase struct {
+	pattern   *regexp.Regexp
+	direction string
+	length    int
+}
+
+// Parser parses market titles with a set of rules.
+type Parser struct {
+	assets        *regexp.Regexp
+	symbols       map[string]string // Normalized asset name to symbol
+	numericAssets *regexp.Regexp    // Asset names containing digits; nil if none
+	directions    []phrase
+	tickers       map[string]string // Upper-case series ticker to symbol
+	price         *regexp.Regexp
+	suffixes      map[string]float64
+	separators    map[string]bool
+
+	monthDate    *regexp.Regexp
+	numericDate  *regexp.Regexp
+	isoDate      *regexp.Regexp
+	weekdayDate  *regexp.Regexp // nil if no weekdays are configured
+	relativeDate *regexp.Regexp // nil if no relative days are configured
+	timeOfDay    *regexp.Regexp
+	timeZone     *regexp.Regexp // Zone right after a time of day; nil if none are configured
+	months       map[string]int
+	weekdays     map[string]time.Weekday
+	relativeDays map[string]int
+	zones        map[string]*time.Location
+	defaultZone  *time.Location
+}
+
+// weekdayNames maps English weekday names to weekdays.
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// NewParser compiles rules into a parser.
+func NewParser(rules Rules) (*Parser, error) {
+	if len(rules.Assets) == 0 {
+		return nil, fmt.Errorf("parser rules: no assets")
+	}
+
+	p := &Parser{
+		symbols:      make(map[string]string),
+		tickers:      make(map[string]string),
+		suffixes:     make(map[string]float64),
+		separators:   make(map[string]bool),
+		months:       make(map[string]int),
+		weekdays:     make(map[string]time.Weekday),
+		relativeDays: make(map[string]int),
+		zones:        make(map[string]*time.Location),
+		defaultZone:  time.UTC,
+	}
+
+	var names, numeric []string
+	for symbol, aliases := range rules.Assets {
+		for _, alias := range aliases {
+			name := normalizePhrase(alias)
+			if name == "" {
+				return nil, fmt.Errorf("parser rules: empty name for asset %s", symbol)
+			}
+			p.symbols[strings.Join(strings.Fields(name), "")] = symbol
+			names = append(names, name)
+			if strings.ContainsAny(name, "0123456789") {
+				numeric = append(numeric, name)
+			}
+		}
+	}
+	p.assets = phrasePattern(names)
+	if len(numeric) > 0 {
+		p.numericAssets = phrasePattern(numeric)
+	}
+
+	for direction, phrases := range rules.Directions {
+		if direction != "above" && direction != "below" {
+			return nil, fmt.Errorf("parser rules: unknown direction %q (want above or below)", direction)
+		}
+		for _, text := range phrases {
+			text = normalizePhrase(text)
+			if text == "" {
+				return nil, fmt.Errorf("parser rules: empty %s phrase", direction)
+			}
+			p.directions = append(p.directions, phrase{
+				pattern:   phrasePattern([]string{text}),
+				direction: direction,
+				length:    len(text),
+			})
+		}
+	}
+	if len(p.directions) == 0 {
+		return nil, fmt.Errorf("parser rules: no direction phrases")
+	}
+
+	for series, symbol := range rules.Tickers {
+		if _, ok := rules.Assets[symbol]; !ok {
+			return nil, fmt.Errorf("parser rules: ticker %s maps to unknown asset %q", series, symbol)
+		}
+		p.tickers[strings.ToUpper(series)] = symbol
+	}
+
+	var suffixes []string
+	for suffix, multiplier := range rules.Strikes.Suffixes {
+		if multiplier <= 0 {
+			return nil, fmt.Errorf("parser rules: suffix %q multiplier must be positive", suffix)
+		}
+		suffix = normalizePhrase(suffix)
+		p.suffixes[suffix] = multiplier
+		suffixes = append(suffixes, suffix)
+	}
+	pricePattern := `(\$)?(\d[\d,]*(?:\.\d+)?)`
+	if len(suffixes) > 0 {
+		pricePattern += `(?:\s?(` + alternation(suffixes) + `)\b)?`
+	}
+	p.price = regexp.MustCompile(`(?i)` + pricePattern)
+	for _, sep := range rules.Strikes.RangeSeparators {
+		p.separators[normalizePhrase(sep)] = true
+	}
+
+	var months []string
+	for name, month := range rules.Dates.Months {
+		if month < 1 || month > 12 {
+			return nil, fmt.Errorf("parser rules: month %q must be 1-12, got %d", name, month)
+		}
+		name = normalizePhrase(name)
+		p.months[name] = month
+		months = append(months, name)
+	}
+	if len(months) == 0 {
+		return nil, fmt.Errorf("parser rules: no month names")
+	}
+	p.monthDate = regexp.MustCompile(`(?i)\b(` + alternation(months) + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b(?:,?\s+(\d{4})\b)?`)
+	p.numericDate = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?\b`)
+	p.isoDate = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	p.timeOfDay = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*(am|pm)\b|\b(\d{1,2}):(\d{2})\b`)
+
+	var weekdays []string
+	for name, english := range rules.Dates.Weekdays {
+		weekday, ok := weekdayNames[strings.ToLower(english)]
+		if !ok {
+			return nil, fmt.Errorf("parser rules: weekday %q maps to unknown weekday %q", name, english)
+		}
+		name = normalizePhrase(name)
+		p.weekdays[name] = weekday
+		weekdays = append(weekdays, name)
+	}
+	if len(weekdays) > 0 {
+		p.weekdayDate = phrasePattern(weekdays)
+	}
+
+	var relative []string
+	for name, days := range rules.Dates.RelativeDays {
+		name = normalizePhrase(name)
+		p.relativeDays[name] = days
+		relative = append(relative, name)
+	}
+	if len(relative) > 0 {
+		p.relativeDate = phrasePattern(relative)
+	}
+
+	var zones []string
+	for name, zone := range rules.Dates.TimeZones {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("parser rules: time zone %q: %w", name, err)
+		}
+		name = normalizePhrase(name)
+		p.zones[name] = loc
+		zones = append(zones, name)
+	}
+	if len(zones) > 0 {
+		p.timeZone = regexp.MustCompile(`(?i)^\s*(` + alternation(zones) + `)\b`)
+	}
+	if rules.Dates.DefaultTimeZone != "" {
+		loc, err := time.LoadLocation(rules.Dates.DefaultTimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("parser rules: default time zone: %w", err)
+		}
+		p.defaultZone = loc
+	}
+
+	return p, nil
+}
+
+// normalizePhrase lowercases a phrase and collapses its whitespace.
+func normalizePhrase(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// alternation returns a regexp alternation of phrases, longest first so a
+// phrase is preferred over its prefixes. Spaces match any whitespace, or
+// none.
+func alternation(phrases []string) string {
+	sorted := append([]string(nil), phrases...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	quoted := make([]string, len(sorted))
+	for i, s := range sorted {
+		quoted[i] = strings.ReplaceAll(regexp.QuoteMeta(s), " ", `\s*`)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// phrasePattern returns a case-insensitive pattern matching any of phrases
+// as whole words. Phrases that start or end with a symbol, such as "+",
+// match next to anything on that side.
+func phrasePattern(phrases []string) *regexp.Regexp {
+	// A single pattern can only take one boundary rule per side, so mixed
+	// word and symbol phrases are split into their own groups
+	var word, symbol []string
+	for _, s := range phrases {
+		if isWordByte(s[0]) && isWordByte(s[len(s)-1]) {
+			word = append(word, s)
+		} else {
+			symbol = append(symbol, s)
+		}
+	}
+
+	var groups []string
+	if len(word) > 0 {
+		groups = append(groups, `\b(?:`+alternation(word)+`)\b`)
+	}
+	for _, s := range symbol {
+		group := alternation([]string{s})
+		if isWordByte(s[0]) {
+			group = `\b` + group
+		}
+		if isWordByte(s[len(s)-1]) {
+			group += `\b`
+		}
+		groups = append(groups, group)
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(groups, "|"))
+}
+
+// isWordByte reports whether b is a regexp word character.
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
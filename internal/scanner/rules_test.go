@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRules_OverridesBuiltInRulesByKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	data := `
+assets:
+  DOGE: [dogecoin, doge]
+  ETH: [ethereum]
+strikes:
+  suffixes:
+    mm: 1000000
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules failed: %v", err)
+	}
+	if len(rules.Assets["BTC"]) == 0 {
+		t.Error("expected built-in assets kept")
+	}
+	if got := rules.Assets["ETH"]; len(got) != 1 || got[0] != "ethereum" {
+		t.Errorf("expected ETH aliases replaced, got %v", got)
+	}
+	if rules.Strikes.Suffixes["k"] != 1000 || rules.Strikes.Suffixes["mm"] != 1000000 {
+		t.Errorf("expected suffixes merged, got %v", rules.Strikes.Suffixes)
+	}
+
+	parser, err := NewParser(rules)
+	if err != nil {
+		t.Fatalf("NewParser failed: %v", err)
+	}
+	parsed, err := parser.ParseTitle("Will Dogecoin be above $0.50 on Friday?")
+	if err != nil {
+		t.Fatalf("expected the added asset parsed, got error: %v", err)
+	}
+	if parsed.Asset != "DOGE" || parsed.Strike != 0.50 || parsed.Direction != "above" {
+		t.Errorf("unexpected parse %+v", parsed)
+	}
+	if _, err := parser.ParseTitle("Will ETH be above $4,000 on Friday?"); err == nil {
+		t.Error("expected the replaced alias no longer recognized")
+	}
+	if parsed, err := parser.ParseTitle("Will Bitcoin hit $1.5mm?"); err != nil || parsed.Strike != 1500000 {
+		t.Errorf("expected the added suffix applied, got %+v, %v", parsed, err)
+	}
+}
+
+func TestLoadRules_Errors(t *testing.T) {
+	if _, err := LoadRules(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("assets: [btc"), 0o644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestNewParser_RejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *Rules)
+		want   string
+	}{
+		{"no assets", func(r *Rules) { r.Assets = nil }, "no assets"},
+		{"unknown direction", func(r *Rules) { r.Directions["sideways"] = []string{"flat"} }, "unknown direction"},
+		{"zero suffix", func(r *Rules) { r.Strikes.Suffixes["k"] = 0 }, "must be positive"},
+		{"bad month", func(r *Rules) { r.Dates.Months["smarch"] = 13 }, "must be 1-12"},
+		{"bad weekday", func(r *Rules) { r.Dates.Weekdays["fri"] = "freitag" }, "unknown weekday"},
+		{"unknown ticker asset", func(r *Rules) { r.Tickers["KXDOGE"] = "DOGE" }, "unknown asset"},
+		{"bad time zone", func(r *Rules) { r.Dates.TimeZones["mt"] = "America/Nowhere" }, "time zone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules()
+			tt.modify(&rules)
+			_, err := NewParser(rules)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSetRules_ReplacesTheDefaultParser(t *testing.T) {
+	t.Cleanup(func() {
+		if err := SetRules(DefaultRules()); err != nil {
+			t.Fatalf("failed to restore rules: %v", err)
+		}
+	})
+
+	rules := DefaultRules()
+	rules.Directions["above"] = append(rules.Directions["above"], "acima de")
+	rules.Dates.Weekdays["sexta"] = "friday"
+	if err := SetRules(rules); err != nil {
+		t.Fatalf("SetRules failed: %v", err)
+	}
+
+	parsed, err := ParseMarketTitle("Bitcoin acima de $100k na sexta?")
+	if err != nil {
+		t.Fatalf("ParseMarketTitle failed: %v", err)
+	}
+	if parsed.Direction != "above" || parsed.Date.String() != "friday" {
+		t.Errorf("expected above on friday, got %+v", parsed)
+	}
+
+	if err := SetRules(Rules{}); err == nil {
+		t.Error("expected error for empty rules")
+	}
+	if _, err := ParseMarketTitle("Bitcoin acima de $100k na sexta?"); err != nil {
+		t.Errorf("expected rejected rules to leave the parser unchanged, got %v", err)
+	}
+}
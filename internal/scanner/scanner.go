@@ -1,7 +1,14 @@
 package scanner
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/blackout"
 	"prediction-bot/internal/config"
+	"prediction-bot/internal/experiment"
 	"prediction-bot/internal/platform"
 	"prediction-bot/pkg/types"
 )
@@ -13,11 +20,90 @@ type EligibleMarket struct {
 	Parsed      *ParsedMarket
 	Probability float64
 	BetSide     string // "YES" or "NO"
+	// Deadline is when the market resolves, from its title and EndDate
+	// (see Deadline). Zero uses EndDate.
+	Deadline time.Time
+	// Experiment and Arm are the parameter experiment the market was
+	// scanned under and the arm it was assigned (see
+	// Scanner.SetExperiment); empty outside one.
+	Experiment string
+	Arm        string
+}
+
+// ResolutionTime returns when the market resolves: Deadline if set, and
+// EndDate otherwise.
+func (m EligibleMarket) ResolutionTime() time.Time {
+	if !m.Deadline.IsZero() {
+		return m.Deadline
+	}
+	return m.Market.EndDate
+}
+
+// NearMiss represents a parseable market that failed exactly one
+// eligibility threshold.
+type NearMiss struct {
+	Market      types.Market
+	Parsed      *ParsedMarket
+	Failure     CriterionFailure
+	Probability float64
+	BetSide     string // "YES" or "NO"
+}
+
+// Rejection is a market a scan evaluated but did not return, and why.
+type Rejection struct {
+	Market types.Market
+	Parsed *ParsedMarket // Nil if the title could not be parsed
+	// Reasons are the criteria the market failed (see Criterion*), or
+	// RejectionUnparseable, RejectionSideUnavailable or RejectionBlackout.
+	Reasons     []string
+	Eligible    bool // Passed every eligibility criterion
+	Probability float64
+	BetSide     string // "YES" or "NO"
+}
+
+// RejectionUnparseable counts markets that passed every eligibility
+// criterion but whose title could not be parsed.
+const RejectionUnparseable = "unparseable"
+
+// RejectionSideUnavailable counts eligible markets whose bet side can't be
+// bought, such as NO on a multi-outcome market that only trades YES tokens.
+const RejectionSideUnavailable = "side_unavailable"
+
+// RejectionBlackout counts eligible markets that stay open into a blackout
+// window for their asset (see Scanner.AddBlackoutCalendar).
+const RejectionBlackout = "blackout"
+
+// ScanStats counts the markets a scan listed and why those that were not
+// eligible were rejected.
+type ScanStats struct {
+	Listed   int
+	Eligible int
+	// Rejections counts rejected markets by criterion (see Criterion*),
+	// RejectionUnparseable, RejectionSideUnavailable or RejectionBlackout.
+	// A market failing several criteria is counted under each of them.
+	Rejections map[string]int
+}
+
+// ScanResult is what a scan of one platform found.
+type ScanResult struct {
+	// Eligible are the markets both eligible and parseable.
+	Eligible []EligibleMarket
+	// NearMisses are the parseable markets that failed exactly one threshold.
+	NearMisses []NearMiss
+	// Rejections are the markets evaluated but not returned, and why.
+	Rejections []Rejection
+	Stats      ScanStats
 }
 
 // Scanner scans prediction market platforms for eligible markets
 type Scanner struct {
-	filter *EligibilityFilter
+	filter     *EligibilityFilter
+	scopes     ParameterScopes
+	experiment *experiment.Experiment
+	blackouts  []blackout.Calendar
+	nearMisses []NearMiss
+	rejections []Rejection
+	stats      ScanStats
 }
 
 // NewScanner creates a new scanner with the given parameters
@@ -27,11 +113,84 @@ func NewScanner(params config.Parameters) *Scanner {
 	}
 }
 
-// Scan scans a single platform for eligible markets.
+// SetParameters replaces the parameters markets are filtered with, such as
+// when the active strategy is reloaded. Not safe to call during a scan.
+func (s *Scanner) SetParameters(params config.Parameters) {
+	s.filter.params = params
+}
+
+// ParameterScopes resolves the parameters the markets of a platform and
+// asset are filtered with, such as those learned for their trades.
+type ParameterScopes interface {
+	Parameters(platform, asset string) config.Parameters
+}
+
+// SetParameterScopes filters each market with the parameters scopes
+// resolves for its platform and asset instead of those set with
+// SetParameters. Nil filters every market with those. Not safe to call
+// during a scan.
+func (s *Scanner) SetParameterScopes(scopes ParameterScopes) {
+	s.scopes = scopes
+}
+
+// SetExperiment runs a parameter experiment: markets the experiment assigns
+// to its challenger are filtered with the challenger's parameters, and every
+// eligible market is returned with its arm. Nil ends it. Not safe to call
+// during a scan.
+func (s *Scanner) SetExperiment(e *experiment.Experiment) {
+	s.experiment = e
+}
+
+// SetClock overrides the time source used for time-to-resolution checks.
+// Used by the backtester to replay historical snapshots.
+func (s *Scanner) SetClock(now func() time.Time) {
+	s.filter.now = now
+}
+
+// AddBlackoutCalendar adds a calendar of event blackouts. Markets that are
+// open during a blackout window for their asset are not returned by Scan.
+func (s *Scanner) AddBlackoutCalendar(calendar blackout.Calendar) {
+	s.blackouts = append(s.blackouts, calendar)
+}
+
+// NearMisses returns the near misses found by the most recent Scan.
+func (s *Scanner) NearMisses() []NearMiss {
+	return s.nearMisses
+}
+
+// Rejections returns the markets the most recent Scan rejected.
+func (s *Scanner) Rejections() []Rejection {
+	return s.rejections
+}
+
+// Stats returns the rejection counts of the most recent Scan.
+func (s *Scanner) Stats() ScanStats {
+	return s.stats
+}
+
+// Scan scans a single platform for eligible markets (see Evaluate),
+// keeping its near misses, rejections and stats for NearMisses, Rejections
+// and Stats. Use Evaluate to scan platforms concurrently.
+func (s *Scanner) Scan(ctx context.Context, p platform.Platform) ([]EligibleMarket, error) {
+	result, err := s.Evaluate(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	s.nearMisses = result.NearMisses
+	s.rejections = result.Rejections
+	s.stats = result.Stats
+	return result.Eligible, nil
+}
+
+// Evaluate scans a single platform for eligible markets.
 // It lists all active markets, filters by eligibility criteria,
 // and parses market titles to extract asset, strike, and direction.
-// Returns only markets that are both eligible and parseable.
-func (s *Scanner) Scan(p platform.Platform) ([]EligibleMarket, error) {
+// Each outcome of a multi-outcome market is evaluated as a binary market
+// of its own, and listed and counted as such.
+// Returns only markets that are both eligible and parseable as Eligible.
+// Parseable markets that failed exactly one threshold are kept as near
+// misses. It is safe to evaluate several platforms concurrently.
+func (s *Scanner) Evaluate(ctx context.Context, p platform.Platform) (ScanResult, error) {
 	// List active markets from platform
 	isActive := true
 	filter := types.MarketFilter{
@@ -39,36 +198,201 @@ func (s *Scanner) Scan(p platform.Platform) ([]EligibleMarket, error) {
 		Limit:    500, // Reasonable limit for single scan
 	}
 
-	markets, err := p.ListMarkets(filter)
+	listed, err := p.ListMarkets(ctx, filter)
 	if err != nil {
-		return nil, err
+		return ScanResult{}, err
+	}
+
+	now := s.filter.now()
+	var windows []blackout.Window
+	for _, calendar := range s.blackouts {
+		w, err := calendar.Windows(now)
+		if err != nil {
+			return ScanResult{}, fmt.Errorf("get blackout windows: %w", err)
+		}
+		windows = append(windows, w...)
+	}
+
+	var markets []types.Market
+	for _, market := range listed {
+		markets = append(markets, market.OutcomeMarkets()...)
 	}
 
-	var eligible []EligibleMarket
+	scan := ScanResult{Stats: ScanStats{Listed: len(markets), Rejections: make(map[string]int)}}
+
+	// Filters by scope and arm, built for the first market that needs them
+	filters := make(map[string]*EligibilityFilter)
 
 	for _, market := range markets {
-		// Check eligibility
-		result := s.filter.IsEligible(market)
+		// Parse market title to extract asset, strike, direction. The asset
+		// selects the parameters learned for it, if any.
+		parsed, parseErr := ParseListedMarket(market)
+		asset := ""
+		if parseErr == nil {
+			asset = parsed.Asset
+		} else {
+			parsed = nil
+		}
+
+		// Check eligibility, with the challenger's parameters if the market
+		// is in its arm
+		name, arm := "", ""
+		if s.experiment != nil {
+			name, arm = s.experiment.Name(), s.experiment.Arm(market.Platform, market.ID)
+		}
+		key := market.Platform + "\x00" + asset + "\x00" + arm
+		filter, ok := filters[key]
+		if !ok {
+			filter = s.filterFor(market.Platform, asset, arm)
+			filters[key] = filter
+		}
+		result := filter.IsEligible(market)
 		if !result.Eligible {
+			reasons := make([]string, 0, len(result.Failures))
+			for _, failure := range result.Failures {
+				scan.Stats.Rejections[failure.Criterion]++
+				reasons = append(reasons, failure.Criterion)
+			}
+			scan.reject(market, parsed, result, reasons...)
+			scan.recordNearMiss(market, result)
 			continue
 		}
 
-		// Parse market title to extract asset, strike, direction
-		parsed, err := ParseMarketTitle(market.Title)
-		if err != nil {
+		if parseErr != nil {
 			// Market is eligible but title is not parseable
 			// (e.g., political markets, sports, etc.)
 			// Skip without error
+			scan.Stats.Rejections[RejectionUnparseable]++
+			scan.reject(market, nil, result, RejectionUnparseable)
+			continue
+		}
+
+		if !sideAvailable(market, result.BetSide) {
+			scan.Stats.Rejections[RejectionSideUnavailable]++
+			scan.reject(market, parsed, result, RejectionSideUnavailable)
+			continue
+		}
+
+		deadline := Deadline(market, parsed, now)
+		if blackedOut(windows, parsed.Asset, now, deadline) {
+			scan.Stats.Rejections[RejectionBlackout]++
+			scan.reject(market, parsed, result, RejectionBlackout)
 			continue
 		}
 
-		eligible = append(eligible, EligibleMarket{
+		scan.Eligible = append(scan.Eligible, EligibleMarket{
 			Market:      market,
 			Parsed:      parsed,
 			Probability: result.Probability,
 			BetSide:     result.BetSide,
+			Deadline:    deadline,
+			Experiment:  name,
+			Arm:         arm,
 		})
 	}
+	scan.Stats.Eligible = len(scan.Eligible)
+
+	return scan, nil
+}
+
+// filterFor returns the filter markets of a platform and asset in an
+// experiment arm are checked with: the scanner's, with the parameters
+// resolved for the scope and the challenger's overrides in its arm.
+func (s *Scanner) filterFor(platform, asset, arm string) *EligibilityFilter {
+	if s.scopes == nil && arm != experiment.ArmChallenger {
+		return s.filter
+	}
+	filter := *s.filter
+	if s.scopes != nil {
+		filter.params = s.scopes.Parameters(platform, asset)
+	}
+	if arm == experiment.ArmChallenger {
+		filter.params = s.experiment.Challenger(filter.params)
+	}
+	return &filter
+}
 
-	return eligible, nil
+// reject keeps a market the scan rejected for reasons. parsed is nil if
+// its title could not be parsed.
+func (r *ScanResult) reject(market types.Market, parsed *ParsedMarket, result EligibilityResult, reasons ...string) {
+	r.Rejections = append(r.Rejections, Rejection{
+		Market:      market,
+		Parsed:      parsed,
+		Reasons:     reasons,
+		Eligible:    result.Eligible,
+		Probability: result.Probability,
+		BetSide:     result.BetSide,
+	})
+}
+
+// recordNearMiss keeps an ineligible market if it failed exactly one
+// threshold and its title is parseable (i.e. it would otherwise be traded).
+func (r *ScanResult) recordNearMiss(market types.Market, result EligibilityResult) {
+	failure, ok := result.NearMiss()
+	if !ok {
+		return
+	}
+
+	parsed, err := ParseListedMarket(market)
+	if err != nil {
+		return
+	}
+
+	r.NearMisses = append(r.NearMisses, NearMiss{
+		Market:      market,
+		Parsed:      parsed,
+		Failure:     failure,
+		Probability: result.Probability,
+		BetSide:     result.BetSide,
+	})
+}
+
+// blackedOut reports whether any window blacks out a market on asset open
+// from now until deadline (zero if unknown).
+func blackedOut(windows []blackout.Window, asset string, now, deadline time.Time) bool {
+	for _, w := range windows {
+		if w.Covers(asset, now, deadline) {
+			return true
+		}
+	}
+	return false
+}
+
+// sideAvailable reports whether side can be bought on market. Markets
+// without tokens (Kalshi) trade both sides by contract.
+func sideAvailable(market types.Market, side string) bool {
+	if len(market.Tokens) == 0 {
+		return true
+	}
+	for _, token := range market.Tokens {
+		if strings.EqualFold(token.Outcome, side) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventKey identifies the event an eligible market belongs to. Markets on
+// the same platform and asset that close at the same time are strikes of
+// one event, e.g. "Bitcoin above $95,000 / $100,000 / $105,000 on Friday".
+func EventKey(market EligibleMarket) string {
+	return market.Market.Platform + "|" + market.Parsed.Asset + "|" + market.Market.EndDate.UTC().Format(time.RFC3339)
+}
+
+// GroupByEvent groups eligible markets by EventKey. Groups are in order of
+// their first market, and markets keep their order within a group.
+func GroupByEvent(markets []EligibleMarket) [][]EligibleMarket {
+	var groups [][]EligibleMarket
+	index := make(map[string]int)
+	for _, market := range markets {
+		key := EventKey(market)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(groups)
+			groups = append(groups, []EligibleMarket{market})
+			continue
+		}
+		groups[i] = append(groups[i], market)
+	}
+	return groups
 }
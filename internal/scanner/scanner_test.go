@@ -1,10 +1,16 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
+	"prediction-bot/internal/blackout"
 	"prediction-bot/internal/config"
+	"prediction-bot/internal/experiment"
 	"prediction-bot/internal/platform"
 	"prediction-bot/pkg/types"
 )
@@ -20,14 +26,14 @@ func (m *MockPlatform) Name() string {
 	return m.name
 }
 
-func (m *MockPlatform) ListMarkets(filter types.MarketFilter) ([]types.Market, error) {
+func (m *MockPlatform) ListMarkets(ctx context.Context, filter types.MarketFilter) ([]types.Market, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
 	return m.markets, nil
 }
 
-func (m *MockPlatform) GetOrderBook(tokenID string) (*types.OrderBook, error) {
+func (m *MockPlatform) GetOrderBook(ctx context.Context, tokenID string) (*types.OrderBook, error) {
 	return nil, nil
 }
 
@@ -125,7 +131,7 @@ func TestScanner_Scan_MockPlatform(t *testing.T) {
 	}
 
 	scanner := NewScanner(params)
-	eligible, err := scanner.Scan(mockPlatform)
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
 
 	if err != nil {
 		t.Fatalf("Scan returned error: %v", err)
@@ -198,7 +204,7 @@ func TestScanner_Scan_EmptyPlatform(t *testing.T) {
 	}
 
 	scanner := NewScanner(params)
-	eligible, err := scanner.Scan(mockPlatform)
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
 
 	if err != nil {
 		t.Fatalf("Scan returned error: %v", err)
@@ -248,7 +254,7 @@ func TestScanner_Scan_AllUnparseable(t *testing.T) {
 	}
 
 	scanner := NewScanner(params)
-	eligible, err := scanner.Scan(mockPlatform)
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
 
 	if err != nil {
 		t.Fatalf("Scan returned error: %v", err)
@@ -259,3 +265,490 @@ func TestScanner_Scan_AllUnparseable(t *testing.T) {
 		t.Errorf("Expected 0 eligible markets (all unparseable), got %d", len(eligible))
 	}
 }
+
+func TestScanner_Scan_RecordsNearMisses(t *testing.T) {
+	now := time.Now()
+	mockPlatform := &MockPlatform{
+		name: "mock",
+		markets: []types.Market{
+			{
+				ID:              "near-miss-prob",
+				Platform:        "mock",
+				Title:           "Will Bitcoin be above $100,000 on Jan 20?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.78,
+				OutcomeNoPrice:  0.22,
+				Liquidity:       500.0,
+			},
+			{
+				ID:              "near-miss-unparseable",
+				Platform:        "mock",
+				Title:           "Will it rain tomorrow?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.78,
+				OutcomeNoPrice:  0.22,
+				Liquidity:       500.0,
+			},
+			{
+				ID:              "far-miss",
+				Platform:        "mock",
+				Title:           "Will Ethereum be above $5,000 on Jan 20?",
+				EndDate:         now.Add(96 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.50,
+				OutcomeNoPrice:  0.50,
+				Liquidity:       500.0,
+			},
+		},
+	}
+
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.80})
+
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(eligible) != 0 {
+		t.Errorf("expected no eligible markets, got %d", len(eligible))
+	}
+
+	nearMisses := scanner.NearMisses()
+	if len(nearMisses) != 1 {
+		t.Fatalf("expected 1 near miss, got %d", len(nearMisses))
+	}
+	if nearMisses[0].Market.ID != "near-miss-prob" {
+		t.Errorf("expected near-miss-prob, got %s", nearMisses[0].Market.ID)
+	}
+	if nearMisses[0].Failure.Criterion != CriterionProbability {
+		t.Errorf("expected probability criterion, got %s", nearMisses[0].Failure.Criterion)
+	}
+	if nearMisses[0].Parsed == nil || nearMisses[0].Parsed.Asset != "BTC" {
+		t.Errorf("expected parsed BTC market, got %+v", nearMisses[0].Parsed)
+	}
+
+	// Near misses are reset on every scan
+	mockPlatform.markets = nil
+	if _, err := scanner.Scan(context.Background(), mockPlatform); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scanner.NearMisses()) != 0 {
+		t.Errorf("expected near misses to reset, got %d", len(scanner.NearMisses()))
+	}
+}
+
+// TestScanner_Scan_CountsRejections tests that a scan counts rejected
+// markets under each criterion they failed.
+func TestScanner_Scan_CountsRejections(t *testing.T) {
+	now := time.Now()
+	mockPlatform := &MockPlatform{
+		name: "mock",
+		markets: []types.Market{
+			{
+				ID:              "eligible",
+				Title:           "Will Bitcoin be above $100,000?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.85,
+				OutcomeNoPrice:  0.15,
+				Liquidity:       1000.0,
+			},
+			{
+				ID:              "low-probability",
+				Title:           "Will Bitcoin be above $150,000?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.60,
+				OutcomeNoPrice:  0.40,
+				Liquidity:       1000.0,
+			},
+			{
+				ID:              "closed-far",
+				Title:           "Will Bitcoin be above $90,000?",
+				EndDate:         now.Add(30 * 24 * time.Hour),
+				Closed:          true,
+				OutcomeYesPrice: 0.90,
+				OutcomeNoPrice:  0.10,
+				Liquidity:       1000.0,
+			},
+			{
+				ID:              "political",
+				Title:           "Who will win the 2024 election?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.85,
+				OutcomeNoPrice:  0.15,
+				Liquidity:       1000.0,
+			},
+		},
+	}
+
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.80})
+	if _, err := scanner.Scan(context.Background(), mockPlatform); err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+
+	stats := scanner.Stats()
+	if stats.Listed != 4 || stats.Eligible != 1 {
+		t.Errorf("Expected 4 listed and 1 eligible, got %d and %d", stats.Listed, stats.Eligible)
+	}
+
+	expected := map[string]int{
+		CriterionProbability:      1,
+		CriterionActive:           1,
+		CriterionClosed:           1,
+		CriterionTimeToResolution: 1,
+		RejectionUnparseable:      1,
+	}
+	if len(stats.Rejections) != len(expected) {
+		t.Errorf("Expected rejections %v, got %v", expected, stats.Rejections)
+	}
+	for criterion, count := range expected {
+		if stats.Rejections[criterion] != count {
+			t.Errorf("Expected %d rejected by %s, got %d", count, criterion, stats.Rejections[criterion])
+		}
+	}
+}
+
+// TestScanner_Scan_RecordsRejections tests that a scan keeps every market
+// it rejected with the reasons why.
+func TestScanner_Scan_RecordsRejections(t *testing.T) {
+	now := time.Now()
+	mockPlatform := &MockPlatform{
+		name: "mock",
+		markets: []types.Market{
+			{
+				ID:              "eligible",
+				Title:           "Will Bitcoin be above $100,000?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.85,
+				OutcomeNoPrice:  0.15,
+				Liquidity:       1000.0,
+			},
+			{
+				ID:              "closed-far",
+				Title:           "Will Bitcoin be above $90,000?",
+				EndDate:         now.Add(30 * 24 * time.Hour),
+				Closed:          true,
+				Active:          true,
+				OutcomeYesPrice: 0.90,
+				OutcomeNoPrice:  0.10,
+				Liquidity:       1000.0,
+			},
+			{
+				ID:              "political",
+				Title:           "Who will win the 2024 election?",
+				EndDate:         now.Add(24 * time.Hour),
+				Active:          true,
+				OutcomeYesPrice: 0.85,
+				OutcomeNoPrice:  0.15,
+				Liquidity:       1000.0,
+			},
+		},
+	}
+
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.80})
+	if _, err := scanner.Scan(context.Background(), mockPlatform); err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+
+	rejections := scanner.Rejections()
+	if len(rejections) != 2 {
+		t.Fatalf("expected 2 rejections, got %d", len(rejections))
+	}
+
+	closed := rejections[0]
+	if closed.Market.ID != "closed-far" || closed.Eligible {
+		t.Errorf("expected ineligible closed-far first, got %+v", closed)
+	}
+	if want := []string{CriterionClosed, CriterionTimeToResolution}; !reflect.DeepEqual(closed.Reasons, want) {
+		t.Errorf("expected reasons %v, got %v", want, closed.Reasons)
+	}
+	if closed.Parsed == nil || closed.Parsed.Asset != "BTC" {
+		t.Errorf("expected parsed BTC market, got %+v", closed.Parsed)
+	}
+
+	political := rejections[1]
+	if political.Market.ID != "political" || !political.Eligible || political.Parsed != nil {
+		t.Errorf("expected eligible unparsed political market, got %+v", political)
+	}
+	if len(political.Reasons) != 1 || political.Reasons[0] != RejectionUnparseable {
+		t.Errorf("expected unparseable, got %v", political.Reasons)
+	}
+	if political.Probability != 0.85 || political.BetSide != "YES" {
+		t.Errorf("expected YES at 0.85, got %s at %v", political.BetSide, political.Probability)
+	}
+}
+
+// TestScanner_Scan_EvaluatesOutcomes tests that each outcome of a
+// multi-outcome market is evaluated as a binary market of its own.
+func TestScanner_Scan_EvaluatesOutcomes(t *testing.T) {
+	now := time.Now()
+	yesOnly := func(id string) []types.Token { return []types.Token{{TokenID: id, Outcome: "Yes"}} }
+	mockPlatform := &MockPlatform{
+		name: "mock",
+		markets: []types.Market{
+			{
+				ID:        "range",
+				Platform:  "mock",
+				Title:     "Bitcoin price range on Friday?",
+				EndDate:   now.Add(24 * time.Hour),
+				Active:    true,
+				Liquidity: 500.0,
+				Outcomes: []types.Outcome{
+					{MarketID: "range-low", Name: "$90,000 or below", Price: 0.04},
+					{MarketID: "range-mid", Name: "$90,000 to $100,000", Price: 0.88},
+					{MarketID: "range-high", Name: "$100,000 or above", Price: 0.08},
+				},
+			},
+			{
+				ID:        "tokens",
+				Platform:  "mock",
+				Title:     "Ethereum price on Friday?",
+				EndDate:   now.Add(24 * time.Hour),
+				Active:    true,
+				Liquidity: 500.0,
+				Outcomes: []types.Outcome{
+					{MarketID: "tokens", Name: "$4,000 or above", Price: 0.10, Tokens: yesOnly("eth-high")},
+					{MarketID: "tokens", Name: "$4,000 or below", Price: 0.90, Tokens: yesOnly("eth-low")},
+				},
+			},
+		},
+	}
+
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.85})
+
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NO on the $90,000 or below and $100,000 or above brackets, YES on the
+	// middle one, and YES on ETH $4,000 or below. NO on ETH $4,000 or above
+	// has no token to buy.
+	if len(eligible) != 4 {
+		t.Fatalf("expected 4 eligible outcomes, got %d: %+v", len(eligible), eligible)
+	}
+	mid := eligible[1]
+	if mid.Market.ID != "range-mid" || mid.BetSide != "YES" || mid.Market.Outcome != "$90,000 to $100,000" {
+		t.Errorf("expected YES on the middle bracket, got %s %s %q", mid.Market.ID, mid.BetSide, mid.Market.Outcome)
+	}
+	if mid.Parsed.Direction != DirectionBetween || mid.Parsed.Strike != 90000 || mid.Parsed.StrikeUpper != 100000 {
+		t.Errorf("expected bracket 90000-100000, got %+v", mid.Parsed)
+	}
+	if eligible[3].Market.Outcome != "$4,000 or below" || eligible[3].BetSide != "YES" {
+		t.Errorf("expected YES on ETH $4,000 or below, got %+v", eligible[3])
+	}
+
+	stats := scanner.Stats()
+	if stats.Listed != 5 || stats.Rejections[RejectionSideUnavailable] != 1 {
+		t.Errorf("expected 5 outcomes listed and 1 side unavailable, got %+v", stats)
+	}
+}
+
+func TestGroupByEvent(t *testing.T) {
+	friday := time.Date(2026, 1, 23, 21, 0, 0, 0, time.UTC)
+	eligible := func(id, platform, asset string, end time.Time) EligibleMarket {
+		return EligibleMarket{
+			Market: types.Market{ID: id, Platform: platform, EndDate: end},
+			Parsed: &ParsedMarket{Asset: asset},
+		}
+	}
+
+	groups := GroupByEvent([]EligibleMarket{
+		eligible("btc-95k", "kalshi", "BTC", friday),
+		eligible("eth-4k", "kalshi", "ETH", friday),
+		eligible("btc-100k", "kalshi", "BTC", friday.In(time.FixedZone("EST", -5*3600))),
+		eligible("btc-100k-poly", "polymarket", "BTC", friday),
+		eligible("btc-100k-sat", "kalshi", "BTC", friday.Add(24*time.Hour)),
+	})
+
+	var got [][]string
+	for _, group := range groups {
+		var ids []string
+		for _, m := range group {
+			ids = append(ids, m.Market.ID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]string{{"btc-95k", "btc-100k"}, {"eth-4k"}, {"btc-100k-poly"}, {"btc-100k-sat"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected groups %v, got %v", want, got)
+	}
+}
+
+// failingCalendar is a blackout calendar that can't be read.
+type failingCalendar struct{}
+
+func (failingCalendar) Windows(now time.Time) ([]blackout.Window, error) {
+	return nil, errors.New("calendar unavailable")
+}
+
+func TestScanner_Scan_SkipsBlackouts(t *testing.T) {
+	now := time.Now()
+	market := func(id, title string, closesIn time.Duration) types.Market {
+		return types.Market{
+			ID:              id,
+			Title:           title,
+			EndDate:         now.Add(closesIn),
+			Active:          true,
+			OutcomeYesPrice: 0.85,
+			OutcomeNoPrice:  0.15,
+			Liquidity:       1000.0,
+		}
+	}
+	mockPlatform := &MockPlatform{
+		name: "mock",
+		markets: []types.Market{
+			market("btc-into-fomc", "Will Bitcoin be above $100,000?", 24*time.Hour),
+			market("btc-before-fomc", "Will Bitcoin be above $95,000?", 2*time.Hour),
+			market("sol-into-unlock", "Will Solana be above $200?", 24*time.Hour),
+			market("eth-clear", "Will Ethereum be above $3,000?", 24*time.Hour),
+		},
+	}
+
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.80})
+	scanner.AddBlackoutCalendar(blackout.Static{
+		{Name: "FOMC", Start: now.Add(6 * time.Hour), End: now.Add(8 * time.Hour), Assets: []string{"BTC"}},
+		{Name: "ended", Start: now.Add(-8 * time.Hour), End: now.Add(-6 * time.Hour)},
+	})
+	scanner.AddBlackoutCalendar(blackout.Static{
+		{Name: "SOL unlock", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Assets: []string{"SOL"}},
+	})
+
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+
+	var ids []string
+	for _, m := range eligible {
+		ids = append(ids, m.Market.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"btc-before-fomc", "eth-clear"}) {
+		t.Errorf("Expected markets clear of blackouts, got %v", ids)
+	}
+	if got := scanner.Stats().Rejections[RejectionBlackout]; got != 2 {
+		t.Errorf("Expected 2 rejected for blackouts, got %d", got)
+	}
+
+	// A calendar that can't be read stops the scan rather than letting
+	// markets through
+	scanner.AddBlackoutCalendar(failingCalendar{})
+	if _, err := scanner.Scan(context.Background(), mockPlatform); err == nil {
+		t.Error("Expected error when a blackout calendar fails, got nil")
+	}
+}
+
+func TestScanner_Scan_ExperimentChallenger(t *testing.T) {
+	now := time.Now()
+	var markets []types.Market
+	for i := 0; i < 40; i++ {
+		markets = append(markets, types.Market{
+			ID:              fmt.Sprintf("btc-%d", i),
+			Platform:        "mock",
+			Title:           fmt.Sprintf("Will Bitcoin be above $%d,000?", 90+i),
+			EndDate:         now.Add(24 * time.Hour),
+			Active:          true,
+			OutcomeYesPrice: 0.88,
+			OutcomeNoPrice:  0.12,
+			Liquidity:       1000.0,
+		})
+	}
+	mockPlatform := &MockPlatform{name: "mock", markets: markets}
+
+	// The challenger's threshold rejects the 88% markets in its arm
+	exp, err := experiment.New("threshold-90", 0.5, map[string]float64{"probability_threshold": 0.90})
+	if err != nil {
+		t.Fatalf("experiment.New failed: %v", err)
+	}
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.85})
+	scanner.SetExperiment(exp)
+
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+
+	champions := 0
+	for _, m := range markets {
+		if exp.Arm(m.Platform, m.ID) == experiment.ArmChampion {
+			champions++
+		}
+	}
+	if champions == 0 || champions == len(markets) {
+		t.Fatalf("expected markets in both arms, got %d champions of %d", champions, len(markets))
+	}
+	if len(eligible) != champions {
+		t.Fatalf("expected the %d champion markets eligible, got %d", champions, len(eligible))
+	}
+	for _, m := range eligible {
+		if m.Experiment != "threshold-90" || m.Arm != experiment.ArmChampion {
+			t.Errorf("expected %s flagged with the champion arm, got %q %q", m.Market.ID, m.Experiment, m.Arm)
+		}
+	}
+
+	// Without the experiment every market is eligible and unflagged
+	scanner.SetExperiment(nil)
+	eligible, err = scanner.Scan(context.Background(), mockPlatform)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if len(eligible) != len(markets) || eligible[0].Arm != "" {
+		t.Errorf("expected all %d markets eligible without an arm, got %d", len(markets), len(eligible))
+	}
+}
+
+// assetThresholds resolves the probability threshold of each asset, falling
+// back to the default threshold.
+type assetThresholds map[string]float64
+
+func (a assetThresholds) Parameters(platform, asset string) config.Parameters {
+	threshold, ok := a[asset]
+	if !ok {
+		threshold = a[""]
+	}
+	return config.Parameters{ProbabilityThreshold: threshold}
+}
+
+func TestScanner_Scan_ParameterScopes(t *testing.T) {
+	now := time.Now()
+	market := func(id, title string) types.Market {
+		return types.Market{
+			ID:              id,
+			Platform:        "mock",
+			Title:           title,
+			EndDate:         now.Add(24 * time.Hour),
+			Active:          true,
+			OutcomeYesPrice: 0.88,
+			OutcomeNoPrice:  0.12,
+			Liquidity:       1000.0,
+		}
+	}
+	mockPlatform := &MockPlatform{
+		name: "mock",
+		markets: []types.Market{
+			market("btc", "Will Bitcoin be above $100,000?"),
+			market("eth", "Will Ethereum be above $3,000?"),
+			market("sol", "Will Solana be above $200?"),
+		},
+	}
+
+	// ETH learned a higher threshold than the rest
+	scanner := NewScanner(config.Parameters{ProbabilityThreshold: 0.95})
+	scanner.SetParameterScopes(assetThresholds{"": 0.85, "ETH": 0.90})
+
+	eligible, err := scanner.Scan(context.Background(), mockPlatform)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	var ids []string
+	for _, m := range eligible {
+		ids = append(ids, m.Market.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"btc", "sol"}) {
+		t.Errorf("expected ETH held to its own threshold, got %v", ids)
+	}
+}
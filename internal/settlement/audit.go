@@ -0,0 +1,134 @@
+package settlement
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/scanner"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PriceHistory defines the interface for the hourly price history of the
+// assets markets are written on. Each price is the close of the hour-long
+// candle opening at its timestamp.
+type PriceHistory interface {
+	GetHistory(asset string, hours int) ([]types.Price, error)
+}
+
+// candleLength is the length of the candles PriceHistory returns.
+const candleLength = time.Hour
+
+// SetPriceHistory sets the price provider resolutions are audited against.
+// Without one, resolutions are recorded unaudited.
+func (s *Settler) SetPriceHistory(history PriceHistory) {
+	s.priceHistory = history
+}
+
+// audit labels a binary market's resolution with the underlying's value at
+// market close according to the price provider and whether the platform
+// resolved the way that value implies. Mismatches raise an alert: a run of
+// them points to the platform settling on a different source than the one
+// the bot trades against. Returns the audit, or nil if the market was not
+// audited.
+func (s *Settler) audit(pos *persistence.Position, resolution types.Resolution) *persistence.ResolutionAudit {
+	if s.priceHistory == nil || pos.Outcome != "" || pos.Asset == "" || pos.Strike <= 0 || pos.MarketCloseTime == nil {
+		return nil
+	}
+	if !strings.EqualFold(resolution.Outcome, "YES") && !strings.EqualFold(resolution.Outcome, "NO") {
+		return nil
+	}
+
+	settlement, err := s.settlementValue(pos.Asset, *pos.MarketCloseTime)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("platform", pos.Platform).
+			Str("market_id", pos.MarketID).
+			Str("asset", pos.Asset).
+			Msg("failed to get settlement value for resolution audit")
+		return nil
+	}
+
+	expected := expectedOutcome(pos.Direction, pos.Strike, pos.StrikeUpper, settlement.Price)
+	audit := persistence.ResolutionAudit{
+		SettlementValue: settlement.Price,
+		Source:          settlement.Source,
+		ExpectedOutcome: expected,
+		Matches:         strings.EqualFold(expected, resolution.Outcome),
+	}
+	if err := s.resolutionRepo.RecordAudit(pos.Platform, pos.MarketID, audit); err != nil {
+		log.Error().
+			Err(err).
+			Str("platform", pos.Platform).
+			Str("market_id", pos.MarketID).
+			Msg("failed to record resolution audit")
+		return nil
+	}
+
+	if !audit.Matches {
+		log.Error().
+			Str("platform", pos.Platform).
+			Str("market_id", pos.MarketID).
+			Str("asset", pos.Asset).
+			Float64("strike", pos.Strike).
+			Str("direction", pos.Direction).
+			Float64("settlement_value", settlement.Price).
+			Str("source", settlement.Source).
+			Str("expected_outcome", expected).
+			Str("outcome", resolution.Outcome).
+			Msg("ALERT: market resolved against the price provider")
+	}
+	return &audit
+}
+
+// settlementValue returns the asset's price at closeTime: the close of the
+// last candle that ends by then, which must end within a candle of it.
+func (s *Settler) settlementValue(asset string, closeTime time.Time) (types.Price, error) {
+	hours := int(math.Ceil(s.now().Sub(closeTime).Hours())) + 2
+	if hours < 2 {
+		return types.Price{}, fmt.Errorf("market closed in the future at %s", closeTime)
+	}
+
+	history, err := s.priceHistory.GetHistory(asset, hours)
+	if err != nil {
+		return types.Price{}, fmt.Errorf("get price history: %w", err)
+	}
+
+	var best types.Price
+	for _, p := range history {
+		end := p.Timestamp.Add(candleLength)
+		if end.After(closeTime) || !end.After(closeTime.Add(-candleLength)) {
+			continue
+		}
+		if best.Timestamp.IsZero() || p.Timestamp.After(best.Timestamp) {
+			best = p
+		}
+	}
+	if best.Timestamp.IsZero() {
+		return types.Price{}, fmt.Errorf("no %s price at %s", asset, closeTime.UTC().Format(time.RFC3339))
+	}
+	return best, nil
+}
+
+// expectedOutcome returns the side a market on strike resolves to if the
+// underlying settles at value. A "between" bracket includes both bounds.
+func expectedOutcome(direction string, strike, upper, value float64) string {
+	var yes bool
+	switch direction {
+	case scanner.DirectionBetween:
+		yes = value >= strike && value <= upper
+	case "below":
+		yes = value < strike
+	default:
+		yes = value > strike
+	}
+	if yes {
+		return "YES"
+	}
+	return "NO"
+}
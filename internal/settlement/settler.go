@@ -0,0 +1,426 @@
+// Package settlement detects resolved markets and settles the positions held
+// in them.
+package settlement
+
+import (
+	"fmt"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+	"prediction-bot/pkg/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Resolver defines the interface for checking whether a market has resolved.
+type Resolver interface {
+	GetResolution(marketID string) (types.Resolution, error)
+}
+
+// RedemptionChecker defines the interface for platforms whose winning
+// positions must be redeemed before the payout reaches the balance.
+type RedemptionChecker interface {
+	// GetTokenBalance returns how many outcome tokens are still held.
+	// Redemption burns them, so a zero balance means the payout is claimed.
+	GetTokenBalance(tokenID string) (float64, error)
+}
+
+// Redeemer defines the interface for platforms that can claim the payout of
+// a resolved market on the bot's behalf.
+type Redeemer interface {
+	// RedeemPositions submits the claim for a market and returns a reference
+	// to it, such as a transaction hash.
+	RedeemPositions(marketID string) (string, error)
+}
+
+// GasReporter defines the interface for platforms that can price the gas
+// paid for an on-chain transaction.
+type GasReporter interface {
+	// GetTransactionCost returns the gas paid for a transaction, in dollars.
+	GetTransactionCost(reference string) (float64, error)
+}
+
+// DefaultRedemptionWindow is how long a winning position may wait for its
+// payout to be redeemed before an alert is raised.
+const DefaultRedemptionWindow = 24 * time.Hour
+
+// RedemptionTimeout is how long a submitted redemption may leave the tokens
+// held before it is taken as reverted or dropped, and submitted again.
+const RedemptionTimeout = 15 * time.Minute
+
+// Result summarizes a settlement run.
+type Result struct {
+	// Checked is the number of open positions checked against their platform.
+	Checked int
+	// Settled contains the exits of positions closed at resolution.
+	Settled []position.ExitResult
+	// Pending is the number of positions awaiting payout redemption.
+	Pending int
+	// Overdue is the number of pending positions past the redemption window.
+	Overdue int
+	// Audited is the number of resolved markets audited against the price
+	// provider.
+	Audited int
+	// Mismatched is the number of audited markets that resolved against the
+	// price provider.
+	Mismatched int
+}
+
+// Settler checks open positions for market resolution and closes them at the
+// settlement price.
+type Settler struct {
+	positionRepo   *persistence.PositionRepository
+	resolutionRepo *persistence.ResolutionRepository
+	manager        *position.Manager
+	resolvers      map[string]Resolver
+	redemptions    map[string]RedemptionChecker
+	redeemers      map[string]Redeemer
+	gasReporters   map[string]GasReporter
+	costRepo       *persistence.CostRepository
+	priceHistory   PriceHistory
+	window         time.Duration
+	now            func() time.Time
+	// alerted holds the pending positions already alerted as overdue, so
+	// each is only reported once.
+	alerted map[int64]bool
+	// submitted holds the redemptions of pending positions whose claim has
+	// been submitted, so it is not resent while it confirms.
+	submitted map[int64]redemption
+}
+
+// redemption is a submitted payout claim.
+type redemption struct {
+	ref string // Transaction hash, or the platform's reference
+	at  time.Time
+}
+
+// NewSettler creates a new settler with the given dependencies.
+func NewSettler(
+	positionRepo *persistence.PositionRepository,
+	resolutionRepo *persistence.ResolutionRepository,
+	manager *position.Manager,
+) *Settler {
+	return &Settler{
+		positionRepo:   positionRepo,
+		resolutionRepo: resolutionRepo,
+		manager:        manager,
+		resolvers:      make(map[string]Resolver),
+		redemptions:    make(map[string]RedemptionChecker),
+		redeemers:      make(map[string]Redeemer),
+		gasReporters:   make(map[string]GasReporter),
+		window:         DefaultRedemptionWindow,
+		now:            time.Now,
+		alerted:        make(map[int64]bool),
+		submitted:      make(map[int64]redemption),
+	}
+}
+
+// SetResolver registers the resolver used for markets on a platform.
+func (s *Settler) SetResolver(platformName string, resolver Resolver) {
+	s.resolvers[platformName] = resolver
+}
+
+// SetRedemptionChecker registers the checker for a platform whose payouts
+// must be redeemed. Winning positions on it are held in pending_settlement
+// until the checker confirms the payout was claimed.
+func (s *Settler) SetRedemptionChecker(platformName string, checker RedemptionChecker) {
+	s.redemptions[platformName] = checker
+}
+
+// SetRedeemer registers the redeemer used to claim payouts on a platform.
+// Pending positions on it are redeemed automatically; without one, payouts
+// must be claimed manually.
+func (s *Settler) SetRedeemer(platformName string, redeemer Redeemer) {
+	s.redeemers[platformName] = redeemer
+}
+
+// SetGasReporter registers the reporter used to price redemption
+// transactions on a platform. Gas is only recorded if a cost repository is
+// also set.
+func (s *Settler) SetGasReporter(platformName string, reporter GasReporter) {
+	s.gasReporters[platformName] = reporter
+}
+
+// SetCostRepository sets the ledger that gas spent on redemptions is
+// recorded in.
+func (s *Settler) SetCostRepository(repo *persistence.CostRepository) {
+	s.costRepo = repo
+}
+
+// SetRedemptionWindow sets how long a payout may remain unredeemed before
+// an alert is raised.
+func (s *Settler) SetRedemptionWindow(window time.Duration) {
+	s.window = window
+}
+
+// SetClock replaces the time source (used for testing).
+func (s *Settler) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// Run checks every open position on a platform with a resolver. Positions in
+// resolved markets are closed with position.ExitReasonResolved at 1.0 if
+// their side won and 0.0 otherwise, and the outcome is recorded for the
+// learning system. Winning positions on a platform with a redemption
+// checker are instead held in pending_settlement until their payout is
+// verified. With a price history set, each resolved binary market is also
+// audited against the price provider. Errors for individual positions are
+// logged and do not stop the run.
+func (s *Settler) Run() (Result, error) {
+	result := Result{}
+	audited := make(map[string]bool)
+
+	positions, err := s.positionRepo.GetOpen()
+	if err != nil {
+		return result, fmt.Errorf("get open positions: %w", err)
+	}
+
+	for _, pos := range positions {
+		resolver, ok := s.resolvers[pos.Platform]
+		if !ok {
+			continue
+		}
+		result.Checked++
+
+		resolution, err := resolver.GetResolution(pos.MarketID)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Int64("position_id", pos.ID).
+				Str("platform", pos.Platform).
+				Str("market_id", pos.MarketID).
+				Msg("failed to check market resolution")
+			continue
+		}
+		if !resolution.Resolved {
+			continue
+		}
+
+		if err := s.resolutionRepo.Record(pos.Platform, pos.MarketID, resolution.Outcome); err != nil {
+			log.Error().
+				Err(err).
+				Str("platform", pos.Platform).
+				Str("market_id", pos.MarketID).
+				Msg("failed to record market resolution")
+		} else if key := pos.Platform + "/" + pos.MarketID; !audited[key] {
+			if audit := s.audit(pos, resolution); audit != nil {
+				audited[key] = true
+				result.Audited++
+				if !audit.Matches {
+					result.Mismatched++
+				}
+			}
+		}
+
+		settlementPrice := resolution.SettlementPriceFor(pos.Outcome, pos.Side)
+		if _, ok := s.redemptions[pos.Platform]; ok && settlementPrice > 0 {
+			if err := s.manager.MarkPendingSettlement(pos.ID); err != nil {
+				log.Error().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Msg("failed to mark position pending settlement")
+				continue
+			}
+
+			log.Info().
+				Int64("position_id", pos.ID).
+				Str("platform", pos.Platform).
+				Str("market_id", pos.MarketID).
+				Str("outcome", resolution.Outcome).
+				Msg("position won, awaiting payout redemption")
+			continue
+		}
+
+		exit, err := s.manager.Settle(pos.ID, settlementPrice)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Int64("position_id", pos.ID).
+				Msg("failed to settle position")
+			continue
+		}
+
+		log.Info().
+			Int64("position_id", pos.ID).
+			Str("platform", pos.Platform).
+			Str("market_id", pos.MarketID).
+			Str("side", pos.Side).
+			Str("outcome", resolution.Outcome).
+			Float64("settlement_price", exit.ExitPrice).
+			Float64("realized_pnl", exit.RealizedPnL).
+			Msg("position settled")
+
+		result.Settled = append(result.Settled, exit)
+	}
+
+	if err := s.checkRedemptions(&result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// checkRedemptions settles pending positions whose payout has been redeemed,
+// submits a redemption for those still holding tokens if the platform has a
+// redeemer, and alerts on those still unredeemed after the redemption
+// window. A position's pending time is measured from its last update, which
+// is its move to pending_settlement.
+func (s *Settler) checkRedemptions(result *Result) error {
+	positions, err := s.positionRepo.GetByStatus(persistence.PositionStatusPendingSettlement)
+	if err != nil {
+		return fmt.Errorf("get pending settlement positions: %w", err)
+	}
+
+	for _, pos := range positions {
+		checker, ok := s.redemptions[pos.Platform]
+		if !ok {
+			continue
+		}
+
+		// Only a claim whose tokens are known to be held is resubmitted
+		var stillHeld bool
+		if pos.TokenID != "" {
+			held, err := checker.GetTokenBalance(pos.TokenID)
+			stillHeld = err == nil && held > 0
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Int64("position_id", pos.ID).
+					Str("platform", pos.Platform).
+					Msg("failed to check payout redemption")
+			} else if held <= 0 {
+				exit, err := s.manager.Settle(pos.ID, 1.0)
+				if err != nil {
+					log.Error().
+						Err(err).
+						Int64("position_id", pos.ID).
+						Msg("failed to settle redeemed position")
+					continue
+				}
+
+				log.Info().
+					Int64("position_id", pos.ID).
+					Str("platform", pos.Platform).
+					Str("market_id", pos.MarketID).
+					Float64("realized_pnl", exit.RealizedPnL).
+					Msg("payout redeemed, position settled")
+
+				if claim, ok := s.submitted[pos.ID]; ok {
+					s.recordGas(pos, claim.ref)
+				}
+				delete(s.alerted, pos.ID)
+				delete(s.submitted, pos.ID)
+				result.Settled = append(result.Settled, exit)
+				continue
+			}
+		}
+
+		s.redeem(pos, stillHeld)
+
+		result.Pending++
+		pendingFor := s.now().Sub(pos.UpdatedAt)
+		if pendingFor < s.window {
+			continue
+		}
+		result.Overdue++
+
+		if !s.alerted[pos.ID] {
+			s.alerted[pos.ID] = true
+			log.Error().
+				Int64("position_id", pos.ID).
+				Str("platform", pos.Platform).
+				Str("market_id", pos.MarketID).
+				Str("token_id", pos.TokenID).
+				Dur("pending_for", pendingFor).
+				Msg("ALERT: resolved position payout not redeemed")
+		}
+	}
+
+	return nil
+}
+
+// redeem submits the payout claim for a pending position. Success is only
+// confirmed once the tokens are gone from the wallet: a claim is submitted
+// again if the tokens are still held RedemptionTimeout after it, as its
+// transaction reverted or was dropped.
+func (s *Settler) redeem(pos *persistence.Position, held bool) {
+	redeemer, ok := s.redeemers[pos.Platform]
+	if !ok {
+		return
+	}
+	if claim, ok := s.submitted[pos.ID]; ok {
+		if !held || s.now().Sub(claim.at) < RedemptionTimeout {
+			return
+		}
+		log.Warn().
+			Int64("position_id", pos.ID).
+			Str("platform", pos.Platform).
+			Str("market_id", pos.MarketID).
+			Str("reference", claim.ref).
+			Dur("submitted_for", s.now().Sub(claim.at)).
+			Msg("payout redemption not confirmed, resubmitting")
+		// A reverted transaction still paid for its gas
+		s.recordGas(pos, claim.ref)
+		delete(s.submitted, pos.ID)
+	}
+
+	ref, err := redeemer.RedeemPositions(pos.MarketID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Int64("position_id", pos.ID).
+			Str("platform", pos.Platform).
+			Str("market_id", pos.MarketID).
+			Msg("failed to redeem payout")
+		return
+	}
+	s.submitted[pos.ID] = redemption{ref: ref, at: s.now()}
+
+	log.Info().
+		Int64("position_id", pos.ID).
+		Str("platform", pos.Platform).
+		Str("market_id", pos.MarketID).
+		Str("reference", ref).
+		Msg("payout redemption submitted")
+}
+
+// recordGas records the gas paid for a position's redemption transaction in
+// the cost ledger. Failures are logged; the position is settled regardless.
+func (s *Settler) recordGas(pos *persistence.Position, ref string) {
+	reporter, ok := s.gasReporters[pos.Platform]
+	if !ok || s.costRepo == nil || ref == "" {
+		return
+	}
+
+	amount, err := reporter.GetTransactionCost(ref)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Int64("position_id", pos.ID).
+			Str("reference", ref).
+			Msg("failed to get redemption gas cost")
+		return
+	}
+
+	positionID := pos.ID
+	if _, err := s.costRepo.Record(&persistence.Cost{
+		Platform:   pos.Platform,
+		Kind:       persistence.CostKindGas,
+		Amount:     types.Dollars(amount),
+		PositionID: &positionID,
+		Reference:  ref,
+	}); err != nil {
+		log.Error().
+			Err(err).
+			Int64("position_id", pos.ID).
+			Msg("failed to record redemption gas cost")
+		return
+	}
+
+	log.Info().
+		Int64("position_id", pos.ID).
+		Str("reference", ref).
+		Float64("gas_cost", amount).
+		Msg("redemption gas recorded")
+}
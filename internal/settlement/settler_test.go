@@ -0,0 +1,475 @@
+package settlement
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/internal/position"
+	"prediction-bot/internal/sizing"
+	"prediction-bot/internal/volatility"
+	"prediction-bot/pkg/types"
+)
+
+// MockResolver returns fixed resolutions per market for testing.
+type MockResolver struct {
+	resolutions map[string]types.Resolution
+}
+
+func (m *MockResolver) GetResolution(marketID string) (types.Resolution, error) {
+	r, ok := m.resolutions[marketID]
+	if !ok {
+		return types.Resolution{}, errors.New("market not found")
+	}
+	return r, nil
+}
+
+// MockVolatility satisfies position.VolatilityAnalyzer; settlement never uses it.
+type MockVolatility struct{}
+
+func (m *MockVolatility) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction volatility.Direction, timeToClose time.Duration) (volatility.ServiceResult, error) {
+	return volatility.ServiceResult{}, nil
+}
+
+func setupSettler(t *testing.T) (*Settler, *sql.DB, *persistence.PositionRepository, *persistence.BankrollRepository) {
+	t.Helper()
+
+	db, err := persistence.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	positionRepo := persistence.NewPositionRepository(db)
+	bankrollRepo := persistence.NewBankrollRepository(db)
+	if err := bankrollRepo.Initialize("polymarket", 50.0); err != nil {
+		t.Fatalf("failed to initialize bankroll: %v", err)
+	}
+
+	manager := position.NewManager(positionRepo, bankrollRepo, &MockVolatility{}, sizing.NewSizer(sizing.SizerConfig{}))
+	settler := NewSettler(positionRepo, persistence.NewResolutionRepository(db), manager)
+
+	return settler, db, positionRepo, bankrollRepo
+}
+
+func createPosition(t *testing.T, repo *persistence.PositionRepository, marketID, side string) int64 {
+	t.Helper()
+
+	id, err := repo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   marketID,
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       side,
+		Status:     persistence.PositionStatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+	return id
+}
+
+func TestRunSettlesResolvedMarkets(t *testing.T) {
+	settler, db, positionRepo, bankrollRepo := setupSettler(t)
+
+	winner := createPosition(t, positionRepo, "market-won", "YES")
+	loser := createPosition(t, positionRepo, "market-lost", "YES")
+	pending := createPosition(t, positionRepo, "market-open", "NO")
+
+	settler.SetResolver("polymarket", &MockResolver{resolutions: map[string]types.Resolution{
+		"market-won":  {MarketID: "market-won", Resolved: true, Outcome: "YES"},
+		"market-lost": {MarketID: "market-lost", Resolved: true, Outcome: "NO"},
+		"market-open": {MarketID: "market-open"},
+	}})
+
+	result, err := settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Checked != 3 || len(result.Settled) != 2 {
+		t.Fatalf("expected 3 checked and 2 settled, got %d and %d", result.Checked, len(result.Settled))
+	}
+
+	won, _ := positionRepo.GetByID(winner)
+	if won.Status != persistence.PositionStatusClosed || *won.ExitPrice != 1.0 || *won.ExitReason != position.ExitReasonResolved {
+		t.Errorf("expected winner closed at 1.0 as resolved, got %s %v %v", won.Status, *won.ExitPrice, *won.ExitReason)
+	}
+	lost, _ := positionRepo.GetByID(loser)
+	if lost.Status != persistence.PositionStatusClosed || *lost.ExitPrice != 0.0 {
+		t.Errorf("expected loser closed at 0.0, got %s %v", lost.Status, *lost.ExitPrice)
+	}
+	open, _ := positionRepo.GetByID(pending)
+	if open.Status != persistence.PositionStatusOpen {
+		t.Errorf("expected unresolved position to stay open, got %s", open.Status)
+	}
+
+	// Only the winner pays out: 50 + 10 * 1.0
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 60.0 {
+		t.Errorf("expected bankroll 60.0, got %f", bankroll.CurrentAmount)
+	}
+
+	resolution, err := persistence.NewResolutionRepository(db).Get("polymarket", "market-lost")
+	if err != nil || resolution == nil || resolution.Outcome != "NO" {
+		t.Errorf("expected recorded NO resolution, got %+v (%v)", resolution, err)
+	}
+}
+
+func TestRunSkipsPlatformsWithoutResolver(t *testing.T) {
+	settler, _, positionRepo, _ := setupSettler(t)
+	createPosition(t, positionRepo, "market-1", "YES")
+
+	result, err := settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Checked != 0 || len(result.Settled) != 0 {
+		t.Errorf("expected nothing checked, got %+v", result)
+	}
+}
+
+// MockRedemptionChecker reports held token balances for testing.
+type MockRedemptionChecker struct {
+	balances map[string]float64
+}
+
+func (m *MockRedemptionChecker) GetTokenBalance(tokenID string) (float64, error) {
+	return m.balances[tokenID], nil
+}
+
+func TestRunHoldsWinnersUntilPayoutRedeemed(t *testing.T) {
+	settler, _, positionRepo, bankrollRepo := setupSettler(t)
+
+	winner, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "market-won",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		TokenID:    "token-yes",
+		Status:     persistence.PositionStatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+	loser := createPosition(t, positionRepo, "market-lost", "YES")
+
+	checker := &MockRedemptionChecker{balances: map[string]float64{"token-yes": 10.0}}
+	settler.SetResolver("polymarket", &MockResolver{resolutions: map[string]types.Resolution{
+		"market-won":  {MarketID: "market-won", Resolved: true, Outcome: "YES"},
+		"market-lost": {MarketID: "market-lost", Resolved: true, Outcome: "NO"},
+	}})
+	settler.SetRedemptionChecker("polymarket", checker)
+	settler.SetRedemptionWindow(time.Hour)
+
+	// Losers have nothing to redeem and close immediately; the winner waits
+	result, err := settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Settled) != 1 || result.Pending != 1 || result.Overdue != 0 {
+		t.Fatalf("expected 1 settled and 1 pending, got %+v", result)
+	}
+	lost, _ := positionRepo.GetByID(loser)
+	if lost.Status != persistence.PositionStatusClosed {
+		t.Errorf("expected loser closed, got %s", lost.Status)
+	}
+	won, _ := positionRepo.GetByID(winner)
+	if won.Status != persistence.PositionStatusPendingSettlement {
+		t.Errorf("expected winner pending settlement, got %s", won.Status)
+	}
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 50.0 {
+		t.Errorf("expected bankroll uncredited at 50.0, got %f", bankroll.CurrentAmount)
+	}
+
+	// Still unredeemed past the window
+	settler.SetClock(func() time.Time { return time.Now().Add(2 * time.Hour) })
+	result, err = settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Pending != 1 || result.Overdue != 1 {
+		t.Errorf("expected 1 overdue pending position, got %+v", result)
+	}
+
+	// Redemption burns the tokens and the payout is credited
+	checker.balances["token-yes"] = 0
+	result, err = settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Settled) != 1 || result.Pending != 0 {
+		t.Errorf("expected redeemed position settled, got %+v", result)
+	}
+	won, _ = positionRepo.GetByID(winner)
+	if won.Status != persistence.PositionStatusClosed || *won.ExitPrice != 1.0 {
+		t.Errorf("expected winner closed at 1.0, got %s %v", won.Status, won.ExitPrice)
+	}
+	bankroll, _ = bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 60.0 {
+		t.Errorf("expected bankroll 60.0 after redemption, got %f", bankroll.CurrentAmount)
+	}
+}
+
+// MockRedeemer burns the held tokens of a market when asked to redeem it,
+// unless its transactions revert.
+type MockRedeemer struct {
+	checker *MockRedemptionChecker
+	tokens  map[string]string // market ID -> token ID
+	calls   int
+	reverts bool
+}
+
+func (m *MockRedeemer) RedeemPositions(marketID string) (string, error) {
+	m.calls++
+	if !m.reverts {
+		m.checker.balances[m.tokens[marketID]] = 0
+	}
+	return fmt.Sprintf("0xtx%d", m.calls), nil
+}
+
+// MockGasReporter prices every transaction at a fixed cost.
+type MockGasReporter struct {
+	cost float64
+	refs []string
+}
+
+func (m *MockGasReporter) GetTransactionCost(reference string) (float64, error) {
+	m.refs = append(m.refs, reference)
+	return m.cost, nil
+}
+
+func TestRunRedeemsPendingPayouts(t *testing.T) {
+	settler, db, positionRepo, bankrollRepo := setupSettler(t)
+
+	winner, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "market-won",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		TokenID:    "token-yes",
+		Status:     persistence.PositionStatusOpen,
+	})
+	if err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	checker := &MockRedemptionChecker{balances: map[string]float64{"token-yes": 10.0}}
+	redeemer := &MockRedeemer{checker: checker, tokens: map[string]string{"market-won": "token-yes"}}
+	settler.SetResolver("polymarket", &MockResolver{resolutions: map[string]types.Resolution{
+		"market-won": {MarketID: "market-won", Resolved: true, Outcome: "YES"},
+	}})
+	settler.SetRedemptionChecker("polymarket", checker)
+	settler.SetRedeemer("polymarket", redeemer)
+	gas := &MockGasReporter{cost: 0.02}
+	costRepo := persistence.NewCostRepository(db)
+	settler.SetGasReporter("polymarket", gas)
+	settler.SetCostRepository(costRepo)
+
+	// The claim is submitted once; the position stays pending until the
+	// tokens are confirmed gone
+	result, err := settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if redeemer.calls != 1 || result.Pending != 1 {
+		t.Fatalf("expected one redemption and one pending position, got %d calls and %+v", redeemer.calls, result)
+	}
+
+	result, err = settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if redeemer.calls != 1 || len(result.Settled) != 1 {
+		t.Errorf("expected redeemed position settled without resubmitting, got %d calls and %+v", redeemer.calls, result)
+	}
+
+	won, _ := positionRepo.GetByID(winner)
+	if won.Status != persistence.PositionStatusClosed {
+		t.Errorf("expected winner closed, got %s", won.Status)
+	}
+	bankroll, _ := bankrollRepo.Get("polymarket")
+	if bankroll.CurrentAmount != 60.0 {
+		t.Errorf("expected bankroll 60.0 after redemption, got %f", bankroll.CurrentAmount)
+	}
+	// Gas for the redemption is recorded in the ledger once settled
+	if len(gas.refs) != 1 || gas.refs[0] != "0xtx1" {
+		t.Errorf("expected gas priced for 0xtx1 once, got %v", gas.refs)
+	}
+	summaries, err := costRepo.Summarize()
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Kind != persistence.CostKindGas || summaries[0].Total != types.Dollars(0.02) {
+		t.Errorf("expected 0.02 of gas recorded, got %+v", summaries)
+	}
+}
+
+func TestRunResubmitsUnconfirmedRedemption(t *testing.T) {
+	settler, _, positionRepo, _ := setupSettler(t)
+
+	now := time.Date(2026, 1, 20, 17, 0, 0, 0, time.UTC)
+	settler.SetClock(func() time.Time { return now })
+
+	if _, err := positionRepo.Create(&persistence.Position{
+		Platform:   "polymarket",
+		MarketID:   "market-won",
+		EntryPrice: 0.90,
+		Quantity:   10.0,
+		Side:       "YES",
+		TokenID:    "token-yes",
+		Status:     persistence.PositionStatusOpen,
+	}); err != nil {
+		t.Fatalf("failed to create position: %v", err)
+	}
+
+	checker := &MockRedemptionChecker{balances: map[string]float64{"token-yes": 10.0}}
+	redeemer := &MockRedeemer{checker: checker, tokens: map[string]string{"market-won": "token-yes"}, reverts: true}
+	settler.SetResolver("polymarket", &MockResolver{resolutions: map[string]types.Resolution{
+		"market-won": {MarketID: "market-won", Resolved: true, Outcome: "YES"},
+	}})
+	settler.SetRedemptionChecker("polymarket", checker)
+	settler.SetRedeemer("polymarket", redeemer)
+
+	// The reverted claim isn't resent while it may still confirm
+	for range 2 {
+		if _, err := settler.Run(); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+	if redeemer.calls != 1 {
+		t.Fatalf("expected one redemption while it confirms, got %d", redeemer.calls)
+	}
+
+	// The tokens are still held after the timeout, so it is resent
+	now = now.Add(RedemptionTimeout)
+	redeemer.reverts = false
+	if _, err := settler.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if redeemer.calls != 2 {
+		t.Fatalf("expected the redemption resubmitted, got %d calls", redeemer.calls)
+	}
+	result, err := settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Settled) != 1 {
+		t.Errorf("expected the position settled once redeemed, got %+v", result)
+	}
+}
+
+// MockPriceHistory returns fixed hourly prices for testing.
+type MockPriceHistory struct {
+	prices []types.Price
+}
+
+func (m *MockPriceHistory) GetHistory(asset string, hours int) ([]types.Price, error) {
+	return m.prices, nil
+}
+
+func TestRunAuditsResolutionsAgainstPriceProvider(t *testing.T) {
+	settler, db, positionRepo, _ := setupSettler(t)
+
+	closeTime := time.Date(2026, 1, 20, 17, 0, 0, 0, time.UTC)
+	settler.SetClock(func() time.Time { return closeTime.Add(30 * time.Minute) })
+	// The candle ending at the close settles at 100,400; the ones around it
+	// would put the market on the other side of the strikes
+	settler.SetPriceHistory(&MockPriceHistory{prices: []types.Price{
+		{Price: 99000, Timestamp: closeTime.Add(-2 * time.Hour), Source: "binance"},
+		{Price: 100400, Timestamp: closeTime.Add(-time.Hour), Source: "binance"},
+		{Price: 99500, Timestamp: closeTime, Source: "binance"},
+	}})
+
+	create := func(marketID, direction string, strike, upper float64) {
+		t.Helper()
+		if _, err := positionRepo.Create(&persistence.Position{
+			Platform:        "polymarket",
+			MarketID:        marketID,
+			Asset:           "BTC",
+			Strike:          strike,
+			StrikeUpper:     upper,
+			Direction:       direction,
+			EntryPrice:      0.90,
+			Quantity:        10.0,
+			Side:            "YES",
+			Status:          persistence.PositionStatusOpen,
+			MarketCloseTime: &closeTime,
+		}); err != nil {
+			t.Fatalf("failed to create position: %v", err)
+		}
+	}
+	create("market-above", "above", 100000, 0)
+	create("market-drift", "above", 100000, 0)
+	create("market-between", "between", 100000, 100500)
+	create("market-no-asset", "", 0, 0)
+
+	settler.SetResolver("polymarket", &MockResolver{resolutions: map[string]types.Resolution{
+		"market-above":    {MarketID: "market-above", Resolved: true, Outcome: "YES"},
+		"market-drift":    {MarketID: "market-drift", Resolved: true, Outcome: "NO"},
+		"market-between":  {MarketID: "market-between", Resolved: true, Outcome: "YES"},
+		"market-no-asset": {MarketID: "market-no-asset", Resolved: true, Outcome: "YES"},
+	}})
+
+	result, err := settler.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Settled) != 4 || result.Audited != 3 || result.Mismatched != 1 {
+		t.Fatalf("expected 4 settled, 3 audited and 1 mismatched, got %+v", result)
+	}
+
+	resolutionRepo := persistence.NewResolutionRepository(db)
+	drift, err := resolutionRepo.Get("polymarket", "market-drift")
+	if err != nil || drift == nil || drift.Audit == nil {
+		t.Fatalf("expected audited resolution, got %+v (%v)", drift, err)
+	}
+	want := persistence.ResolutionAudit{SettlementValue: 100400, Source: "binance", ExpectedOutcome: "YES", Matches: false}
+	if *drift.Audit != want {
+		t.Errorf("expected audit %+v, got %+v", want, *drift.Audit)
+	}
+
+	unaudited, _ := resolutionRepo.Get("polymarket", "market-no-asset")
+	if unaudited == nil || unaudited.Audit != nil {
+		t.Errorf("expected market without an asset recorded unaudited, got %+v", unaudited)
+	}
+
+	mismatches, err := resolutionRepo.GetMismatches()
+	if err != nil || len(mismatches) != 1 || mismatches[0].MarketID != "market-drift" {
+		t.Errorf("expected market-drift as the only mismatch, got %+v (%v)", mismatches, err)
+	}
+}
+
+func TestExpectedOutcome(t *testing.T) {
+	tests := []struct {
+		direction string
+		upper     float64
+		value     float64
+		want      string
+	}{
+		{"above", 0, 101, "YES"},
+		{"above", 0, 99, "NO"},
+		{"below", 0, 99, "YES"},
+		{"below", 0, 101, "NO"},
+		{"between", 110, 100, "YES"},
+		{"between", 110, 110, "YES"},
+		{"between", 110, 111, "NO"},
+	}
+
+	for _, tt := range tests {
+		if got := expectedOutcome(tt.direction, 100, tt.upper, tt.value); got != tt.want {
+			t.Errorf("%s 100-%v at %v: expected %s, got %s", tt.direction, tt.upper, tt.value, tt.want, got)
+		}
+	}
+}
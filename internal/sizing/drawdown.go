@@ -0,0 +1,44 @@
+package sizing
+
+// DrawdownStep scales the Kelly fraction once the bankroll has drawn down
+// at least Drawdown (a fraction of its peak, e.g. 0.10 for 10%).
+type DrawdownStep struct {
+	Drawdown float64
+	Scale    float64 // Multiplier on the configured Kelly fraction (e.g. 0.25 turns half-Kelly into eighth-Kelly)
+}
+
+// DrawdownScale returns the scale of the deepest step drawdown has reached,
+// or 1 if it has reached none. Steps need not be sorted.
+func DrawdownScale(steps []DrawdownStep, drawdown float64) float64 {
+	scale := 1.0
+	deepest := -1.0
+	for _, step := range steps {
+		if drawdown >= step.Drawdown && step.Drawdown > deepest {
+			deepest = step.Drawdown
+			scale = step.Scale
+		}
+	}
+	return scale
+}
+
+// SetKellyFraction changes the fraction of Kelly used by later sizes.
+func (s *Sizer) SetKellyFraction(fraction float64) {
+	s.config.KellyFraction = fraction
+}
+
+// SetMaxLiquidityPct changes the maximum share of a market's liquidity
+// later sizes may take (0 disables the cap).
+func (s *Sizer) SetMaxLiquidityPct(pct float64) {
+	s.config.MaxLiquidityPct = pct
+}
+
+// SetCorrelation changes how closely later positions are assumed to move
+// with the open positions betting the same way on their asset.
+func (s *Sizer) SetCorrelation(correlation float64) {
+	s.config.Correlation = correlation
+}
+
+// KellyFraction returns the fraction of Kelly currently in use.
+func (s *Sizer) KellyFraction() float64 {
+	return s.config.KellyFraction
+}
@@ -0,0 +1,30 @@
+package sizing
+
+import "testing"
+
+func TestDrawdownScale(t *testing.T) {
+	// Unsorted on purpose
+	steps := []DrawdownStep{{Drawdown: 0.10, Scale: 0.25}, {Drawdown: 0.05, Scale: 0.5}}
+
+	tests := []struct {
+		drawdown float64
+		want     float64
+	}{
+		{0, 1},
+		{0.04, 1},
+		{0.05, 0.5},
+		{0.08, 0.5},
+		{0.10, 0.25},
+		{0.30, 0.25},
+	}
+
+	for _, tt := range tests {
+		if got := DrawdownScale(steps, tt.drawdown); got != tt.want {
+			t.Errorf("DrawdownScale(%v) = %v, want %v", tt.drawdown, got, tt.want)
+		}
+	}
+
+	if got := DrawdownScale(nil, 0.5); got != 1 {
+		t.Errorf("expected no steps to keep the scale at 1, got %v", got)
+	}
+}
@@ -1,28 +1,52 @@
 package sizing
 
-import "math"
+import (
+	"math"
+
+	"prediction-bot/pkg/types"
+)
 
 // SizerConfig holds configuration for the Sizer.
 type SizerConfig struct {
 	KellyFraction  float64 // Fraction of Kelly to use (e.g., 0.25 for quarter Kelly)
 	MinPosition    float64 // Minimum position size in dollars
 	MaxBankrollPct float64 // Maximum percentage of bankroll per position
+	// MaxLiquidityPct is the maximum share of the market's liquidity per
+	// position (0 disables the cap). A large share of a thin market cannot
+	// be exited at a fair price, whatever Kelly suggests.
+	MaxLiquidityPct float64
+	// AmountDecimals is the number of decimal places position sizes are
+	// rounded down to (0 uses DefaultAmountDecimals). Small bankrolls need
+	// more than cents, e.g. 6 for USDC micro-units.
+	AmountDecimals int
+	// Correlation is how closely a new position is assumed to move with the
+	// open positions betting the same way on its asset (0 sizes each trade
+	// independently). Perfectly correlated positions are one bet, so that
+	// share of their exposure is taken off the new position's Kelly stake.
+	Correlation float64
 }
 
+// DefaultAmountDecimals rounds position sizes down to cents.
+const DefaultAmountDecimals = 2
+
 // SizingInput contains the inputs needed to calculate position size.
 type SizingInput struct {
 	EntryPrice   float64 // Price to buy at (0 < price < 1)
 	WinProb      float64 // Estimated win probability
 	Bankroll     float64 // Total available capital
 	SafetyMargin float64 // Volatility safety margin
+	Liquidity    float64 // Market liquidity in dollars (0 if unknown)
+	// CorrelatedExposure is the dollars open on the same asset betting the
+	// same way, used when the Sizer is configured with a Correlation.
+	CorrelatedExposure float64
 }
 
 // SizingOutput contains the calculated position size and metadata.
 type SizingOutput struct {
-	PositionSize float64 // Final position size in dollars (rounded down to cents)
+	PositionSize float64 // Final position size in dollars (rounded down to AmountDecimals)
 	RawKelly     float64 // Raw Kelly position before constraints
 	BankrollPct  float64 // Percentage of bankroll for this position
-	Reason       string  // Reason if position is 0 (e.g., "no_edge", "below_minimum")
+	Reason       string  // Reason if position is 0 (e.g., "no_edge", "correlated", "below_minimum")
 }
 
 // Sizer calculates position sizes with constraints.
@@ -50,9 +74,29 @@ func (s *Sizer) Calculate(input SizingInput) SizingOutput {
 		}
 	}
 
+	// Take correlated exposure off the Kelly stake: it already carries
+	// that share of the bet
+	kelly := rawKelly
+	if s.config.Correlation > 0 && input.CorrelatedExposure > 0 {
+		kelly -= s.config.Correlation * input.CorrelatedExposure
+		if kelly <= 0 {
+			return SizingOutput{
+				PositionSize: 0,
+				RawKelly:     rawKelly,
+				BankrollPct:  0,
+				Reason:       "correlated",
+			}
+		}
+	}
+
 	// Apply maximum constraint (max % of bankroll)
 	maxPosition := input.Bankroll * s.config.MaxBankrollPct
-	position := math.Min(rawKelly, maxPosition)
+	position := math.Min(kelly, maxPosition)
+
+	// Apply liquidity constraint (max % of market liquidity)
+	if s.config.MaxLiquidityPct > 0 && input.Liquidity > 0 {
+		position = math.Min(position, input.Liquidity*s.config.MaxLiquidityPct)
+	}
 
 	// Check if position is below minimum
 	if position < s.config.MinPosition {
@@ -66,8 +110,8 @@ func (s *Sizer) Calculate(input SizingInput) SizingOutput {
 		}
 	}
 
-	// Round down to cents (2 decimal places)
-	position = math.Floor(position*100) / 100
+	// Round down to the configured precision
+	position = s.roundDown(position)
 
 	// Calculate final bankroll percentage
 	bankrollPct := position / input.Bankroll
@@ -80,6 +124,26 @@ func (s *Sizer) Calculate(input SizingInput) SizingOutput {
 	}
 }
 
+// Downsize returns amount, a reduced position size, rounded down to the
+// configured precision, or 0 if it is below the minimum position.
+func (s *Sizer) Downsize(amount float64) float64 {
+	if amount < s.config.MinPosition {
+		return 0
+	}
+	return s.roundDown(amount)
+}
+
+// roundDown rounds an amount down to the configured number of decimal
+// places. It is rounded in types.Money, so an amount already on a cent,
+// such as 0.29, isn't taken down a cent by float error.
+func (s *Sizer) roundDown(amount float64) float64 {
+	decimals := s.config.AmountDecimals
+	if decimals <= 0 {
+		decimals = DefaultAmountDecimals
+	}
+	return types.Dollars(amount).RoundDown(decimals).Float64()
+}
+
 // EstimateWinProbability estimates the true win probability based on market price and safety margin.
 //
 // The idea is that if volatility analysis shows a high safety margin, the true probability
@@ -100,6 +100,29 @@ func TestSizer_Calculate_RoundsDown(t *testing.T) {
 	}
 }
 
+func TestSizer_Calculate_KeepsConfiguredPrecision(t *testing.T) {
+	input := SizingInput{
+		EntryPrice:   0.90,
+		WinProb:      0.92,
+		Bankroll:     33.33,
+		SafetyMargin: 1.5,
+	}
+
+	cents := NewSizer(SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20}).Calculate(input)
+	micro := NewSizer(SizerConfig{KellyFraction: 0.25, MinPosition: 1.0, MaxBankrollPct: 0.20, AmountDecimals: 6}).Calculate(input)
+
+	// Micro-unit sizing keeps the sub-cent remainder that cents discard
+	if micro.PositionSize <= cents.PositionSize {
+		t.Errorf("expected 6-decimal size %v above cent size %v", micro.PositionSize, cents.PositionSize)
+	}
+	if micro.PositionSize-cents.PositionSize >= 0.01 {
+		t.Errorf("expected sizes within a cent, got %v and %v", micro.PositionSize, cents.PositionSize)
+	}
+	if rounded := math.Floor(micro.PositionSize*1e6) / 1e6; micro.PositionSize != rounded {
+		t.Errorf("expected size rounded down to 6 decimals, got %v", micro.PositionSize)
+	}
+}
+
 func TestSizer_Calculate_ReturnsMetadata(t *testing.T) {
 	sizer := NewSizer(SizerConfig{
 		KellyFraction:  0.25,
@@ -224,3 +247,86 @@ func TestSizer_Calculate_NoEdge(t *testing.T) {
 		t.Errorf("Calculate() with no edge should have reason 'no_edge', got %v", result.Reason)
 	}
 }
+
+func TestSizer_Calculate_CapsAtLiquidityShare(t *testing.T) {
+	sizer := NewSizer(SizerConfig{
+		KellyFraction:   0.25,
+		MinPosition:     1.0,
+		MaxBankrollPct:  0.20,
+		MaxLiquidityPct: 0.05,
+	})
+
+	input := SizingInput{
+		EntryPrice:   0.80,
+		WinProb:      0.95,
+		Bankroll:     100.0,
+		SafetyMargin: 2.0,
+		Liquidity:    200.0, // 5% is $10, below the ~$18.74 Kelly size
+	}
+
+	result := sizer.Calculate(input)
+	if result.PositionSize != 10.0 {
+		t.Errorf("Calculate() should cap at 5%% of liquidity (10.0), got %v", result.PositionSize)
+	}
+
+	// A market too thin for the minimum position is skipped
+	input.Liquidity = 10.0
+	result = sizer.Calculate(input)
+	if result.PositionSize != 0 || result.Reason != "below_minimum" {
+		t.Errorf("Calculate() in thin market should skip as below_minimum, got %v (%s)", result.PositionSize, result.Reason)
+	}
+
+	// Unknown liquidity leaves the Kelly size uncapped
+	input.Liquidity = 0
+	result = sizer.Calculate(input)
+	if result.PositionSize <= 10.0 {
+		t.Errorf("Calculate() without liquidity should not be capped, got %v", result.PositionSize)
+	}
+}
+
+func TestSizer_Downsize(t *testing.T) {
+	sizer := NewSizer(SizerConfig{MinPosition: 1.0})
+
+	if got := sizer.Downsize(4.56789); got != 4.56 {
+		t.Errorf("Downsize(4.56789) = %v, want 4.56", got)
+	}
+	// 1.13 * 100 is 112.99999999999999 in float64
+	if got := sizer.Downsize(1.13); got != 1.13 {
+		t.Errorf("Downsize(1.13) = %v, want 1.13", got)
+	}
+	if got := sizer.Downsize(0.99); got != 0 {
+		t.Errorf("Downsize(0.99) = %v, want 0 below the minimum", got)
+	}
+}
+
+func TestSizer_Calculate_ShrinksForCorrelatedExposure(t *testing.T) {
+	config := SizerConfig{
+		KellyFraction:  0.25,
+		MinPosition:    1.0,
+		MaxBankrollPct: 0.20,
+	}
+	input := SizingInput{
+		EntryPrice:         0.80,
+		WinProb:            0.95,
+		Bankroll:           100.0,
+		SafetyMargin:       2.0,
+		CorrelatedExposure: 10.0,
+	}
+
+	// Without a correlation each trade is sized independently (~$18.74)
+	independent := NewSizer(config).Calculate(input)
+
+	config.Correlation = 0.5
+	sizer := NewSizer(config)
+	result := sizer.Calculate(input)
+	if diff := independent.PositionSize - result.PositionSize; diff < 4.99 || diff > 5.01 {
+		t.Errorf("expected half the $10 exposure taken off %v, got %v", independent.PositionSize, result.PositionSize)
+	}
+
+	// Exposure carrying the whole Kelly stake leaves nothing to add
+	input.CorrelatedExposure = 40.0
+	result = sizer.Calculate(input)
+	if result.PositionSize != 0 || result.Reason != "correlated" {
+		t.Errorf("expected no position for correlated exposure, got %+v", result)
+	}
+}
@@ -0,0 +1,130 @@
+package strategy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Registry holds the strategies defined in a directory, by name, and
+// reloads them when the files change.
+type Registry struct {
+	dir string
+
+	mu         sync.Mutex
+	strategies map[string]Strategy
+	modTimes   map[string]time.Time // Modification time of each file loaded
+}
+
+// NewRegistry creates a registry of the strategy files in dir. Call Load
+// to read them.
+func NewRegistry(dir string) *Registry {
+	return &Registry{dir: dir, strategies: make(map[string]Strategy)}
+}
+
+// Load reads every .yaml, .yml and .json file in the directory. Fails if
+// any file is invalid or two files define the same name, keeping the
+// strategies loaded before.
+func (r *Registry) Load() error {
+	files, err := r.files()
+	if err != nil {
+		return err
+	}
+
+	strategies := make(map[string]Strategy, len(files))
+	for path := range files {
+		s, err := Load(path)
+		if err != nil {
+			return err
+		}
+		if other, ok := strategies[s.Name]; ok {
+			return fmt.Errorf("strategy %s defined in both %s and %s", s.Name, other.Path, path)
+		}
+		strategies[s.Name] = s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strategies = strategies
+	r.modTimes = files
+	return nil
+}
+
+// Reload loads the directory again if a file was added, removed or
+// modified since the last load. Returns whether the strategies were
+// reloaded. If the files no longer load, the strategies loaded before are
+// kept and the error returned.
+func (r *Registry) Reload() (bool, error) {
+	files, err := r.files()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	unchanged := len(files) == len(r.modTimes)
+	for path, modTime := range files {
+		if loaded, ok := r.modTimes[path]; !ok || !loaded.Equal(modTime) {
+			unchanged = false
+		}
+	}
+	r.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+
+	if err := r.Load(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns a strategy by name.
+func (r *Registry) Get(name string) (Strategy, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.strategies[name]
+	return s, ok
+}
+
+// Names returns the names of the strategies loaded, sorted.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.strategies))
+	for name := range r.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// files returns the strategy files in the directory and when each was
+// last modified.
+func (r *Registry) files() (map[string]time.Time, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read strategies: %w", err)
+	}
+
+	files := make(map[string]time.Time)
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("read strategies: %w", err)
+		}
+		files[filepath.Join(r.dir, entry.Name())] = info.ModTime()
+	}
+	return files, nil
+}
@@ -0,0 +1,68 @@
+package strategy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeStrategy(t *testing.T, path, data string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write strategy: %v", err)
+	}
+	// Set explicitly so a rewrite within the file system's timestamp
+	// resolution still counts as a change
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to set modification time: %v", err)
+	}
+}
+
+func TestRegistry_ReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	path := filepath.Join(dir, "tight.yaml")
+	writeStrategy(t, path, "schema: 1\nname: tight\nversion: 1", start)
+	writeStrategy(t, filepath.Join(dir, "wide.json"), `{"schema": 1, "name": "wide", "version": 1}`, start)
+	writeStrategy(t, filepath.Join(dir, "README.md"), "not a strategy", start)
+
+	registry := NewRegistry(dir)
+	if err := registry.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if names := registry.Names(); len(names) != 2 || names[0] != "tight" || names[1] != "wide" {
+		t.Fatalf("expected tight and wide, got %v", names)
+	}
+
+	if changed, err := registry.Reload(); err != nil || changed {
+		t.Errorf("expected no reload without changes, got %v, %v", changed, err)
+	}
+
+	writeStrategy(t, path, "schema: 1\nname: tight\nversion: 2", start.Add(time.Minute))
+	if changed, err := registry.Reload(); err != nil || !changed {
+		t.Fatalf("expected reload after a change, got %v, %v", changed, err)
+	}
+	if s, _ := registry.Get("tight"); s.Version != 2 {
+		t.Errorf("expected version 2, got %d", s.Version)
+	}
+
+	// An invalid edit keeps the strategies loaded before
+	writeStrategy(t, path, "schema: 1\nname: tight\nversion: 0", start.Add(2*time.Minute))
+	if _, err := registry.Reload(); err == nil {
+		t.Error("expected error for an invalid file")
+	}
+	if s, ok := registry.Get("tight"); !ok || s.Version != 2 {
+		t.Errorf("expected version 2 kept, got %+v", s)
+	}
+}
+
+func TestRegistry_RejectsDuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	writeStrategy(t, filepath.Join(dir, "a.yaml"), "schema: 1\nname: same\nversion: 1", time.Now())
+	writeStrategy(t, filepath.Join(dir, "b.yaml"), "schema: 1\nname: same\nversion: 2", time.Now())
+
+	if err := NewRegistry(dir).Load(); err == nil {
+		t.Error("expected error for two files defining the same strategy")
+	}
+}
@@ -0,0 +1,158 @@
+package strategy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// Schema is the JSON Schema strategy files are validated against, for
+// editors and other tools to validate them with.
+func Schema() []byte {
+	return schemaJSON
+}
+
+// schema is the subset of JSON Schema that schema.json uses: types,
+// required and unknown properties, numeric bounds, enums and patterns.
+type schema struct {
+	Type                 string             `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+}
+
+// strategySchema is schema.json, parsed once.
+var strategySchema = func() *schema {
+	var s schema
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		panic(fmt.Sprintf("parse strategy schema: %v", err))
+	}
+	return &s
+}()
+
+// validate checks a decoded YAML or JSON document against the schema.
+// Returns the first violation found, prefixed with the path of the value
+// (such as "sizing.kelly_fraction").
+func (s *schema) validate(path string, value any) error {
+	switch s.Type {
+	case "object":
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return violation(path, "must be an object")
+		}
+		for _, key := range s.Required {
+			if _, ok := fields[key]; !ok {
+				return violation(join(path, key), "is required")
+			}
+		}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			property, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return violation(join(path, key), "is not a known property")
+				}
+				continue
+			}
+			if err := property.validate(join(path, key), fields[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return violation(path, "must be a string")
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(str) {
+			return violation(path, fmt.Sprintf("%q does not match %s", str, s.Pattern))
+		}
+		return s.validateEnum(path, str)
+
+	case "number", "integer":
+		n, ok := number(value)
+		if !ok {
+			return violation(path, "must be a number")
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			return violation(path, "must be an integer")
+		}
+		switch {
+		case s.Minimum != nil && n < *s.Minimum:
+			return violation(path, fmt.Sprintf("must be at least %v, got %v", *s.Minimum, n))
+		case s.Maximum != nil && n > *s.Maximum:
+			return violation(path, fmt.Sprintf("must be at most %v, got %v", *s.Maximum, n))
+		case s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum:
+			return violation(path, fmt.Sprintf("must be above %v, got %v", *s.ExclusiveMinimum, n))
+		case s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum:
+			return violation(path, fmt.Sprintf("must be below %v, got %v", *s.ExclusiveMaximum, n))
+		}
+		return s.validateEnum(path, n)
+	}
+	return nil
+}
+
+// validateEnum checks that a string or number is one of the enum's values.
+func (s *schema) validateEnum(path string, value any) error {
+	if len(s.Enum) == 0 {
+		return nil
+	}
+	allowed := make([]string, 0, len(s.Enum))
+	for _, e := range s.Enum {
+		if n, ok := number(e); ok {
+			e = n
+		}
+		if e == value {
+			return nil
+		}
+		allowed = append(allowed, fmt.Sprint(e))
+	}
+	return violation(path, fmt.Sprintf("must be one of %s, got %v", strings.Join(allowed, ", "), value))
+}
+
+// number converts the numbers YAML and JSON decode to float64.
+func number(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func violation(path, message string) error {
+	if path == "" {
+		return fmt.Errorf("strategy %s", message)
+	}
+	return fmt.Errorf("%s %s", path, message)
+}
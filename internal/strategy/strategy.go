@@ -0,0 +1,125 @@
+// Package strategy loads strategies: named, versioned variants of the
+// trading parameters (filters, thresholds, sizing and exits) defined in
+// YAML or JSON files validated against schema.json, so variants can be
+// tracked in git and the positions entered under each compared.
+package strategy
+
+import (
+	"fmt"
+	"os"
+
+	"prediction-bot/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Strategy is a named, versioned set of overrides of the trading
+// parameters. Fields left out of the file are nil and keep the parameter
+// from config.yaml.
+type Strategy struct {
+	Schema      int        `yaml:"schema"` // Version of the file format, 1
+	Name        string     `yaml:"name"`
+	Version     int        `yaml:"version"` // Bumped on each change, and recorded on positions
+	Description string     `yaml:"description"`
+	Filters     Filters    `yaml:"filters"`
+	Thresholds  Thresholds `yaml:"thresholds"`
+	Sizing      Sizing     `yaml:"sizing"`
+	Exits       Exits      `yaml:"exits"`
+
+	// Path is the file the strategy was loaded from.
+	Path string `yaml:"-"`
+}
+
+// Filters override the scanner's market eligibility filters.
+type Filters struct {
+	MinLiquidity      *float64 `yaml:"min_liquidity"`
+	MinVolume         *float64 `yaml:"min_volume"`
+	MinHoursToClose   *float64 `yaml:"min_hours_to_close"`
+	MaxHoursToClose   *float64 `yaml:"max_hours_to_close"`
+	MinMinutesToClose *float64 `yaml:"min_minutes_to_close"`
+	MaxSpread         *float64 `yaml:"max_spread"`
+}
+
+// Thresholds override the entry thresholds.
+type Thresholds struct {
+	ProbabilityThreshold *float64 `yaml:"probability_threshold"`
+}
+
+// Sizing overrides how positions are sized.
+type Sizing struct {
+	KellyFraction    *float64 `yaml:"kelly_fraction"`
+	MaxLiquidityPct  *float64 `yaml:"max_liquidity_pct"`
+	KellyCorrelation *float64 `yaml:"kelly_correlation"`
+}
+
+// Exits override when positions are exited.
+type Exits struct {
+	StopLossPercent    *float64 `yaml:"stop_loss_percent"`
+	StopLossType       *string  `yaml:"stop_loss_type"`
+	TakeProfitPercent  *float64 `yaml:"take_profit_percent"`
+	TimeDecayExitHours *float64 `yaml:"time_decay_exit_hours"`
+}
+
+// Parse reads a strategy from YAML or JSON (a JSON document is valid YAML)
+// and validates it against the schema.
+func Parse(data []byte) (Strategy, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Strategy{}, fmt.Errorf("parse strategy: %w", err)
+	}
+	if err := strategySchema.validate("", document); err != nil {
+		return Strategy{}, fmt.Errorf("validate strategy: %w", err)
+	}
+
+	var s Strategy
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return Strategy{}, fmt.Errorf("parse strategy: %w", err)
+	}
+	return s, nil
+}
+
+// Load reads a strategy file.
+func Load(path string) (Strategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Strategy{}, fmt.Errorf("read strategy: %w", err)
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return Strategy{}, fmt.Errorf("%s: %w", path, err)
+	}
+	s.Path = path
+	return s, nil
+}
+
+// Label identifies the strategy and its version, as in "tight-stops v3".
+func (s Strategy) Label() string {
+	return fmt.Sprintf("%s v%d", s.Name, s.Version)
+}
+
+// Apply returns base with the strategy's overrides applied.
+func (s Strategy) Apply(base config.Parameters) config.Parameters {
+	params := base
+	set(&params.MinLiquidity, s.Filters.MinLiquidity)
+	set(&params.MinVolume, s.Filters.MinVolume)
+	set(&params.MinHoursToClose, s.Filters.MinHoursToClose)
+	set(&params.MaxHoursToClose, s.Filters.MaxHoursToClose)
+	set(&params.MinMinutesToClose, s.Filters.MinMinutesToClose)
+	set(&params.MaxSpread, s.Filters.MaxSpread)
+	set(&params.ProbabilityThreshold, s.Thresholds.ProbabilityThreshold)
+	set(&params.KellyFraction, s.Sizing.KellyFraction)
+	set(&params.MaxLiquidityPct, s.Sizing.MaxLiquidityPct)
+	set(&params.KellyCorrelation, s.Sizing.KellyCorrelation)
+	set(&params.StopLossPercent, s.Exits.StopLossPercent)
+	set(&params.StopLossType, s.Exits.StopLossType)
+	set(&params.TakeProfitPercent, s.Exits.TakeProfitPercent)
+	set(&params.TimeDecayExitHours, s.Exits.TimeDecayExitHours)
+	return params
+}
+
+// set overrides a parameter with an override the strategy sets.
+func set[T any](param *T, override *T) {
+	if override != nil {
+		*param = *override
+	}
+}
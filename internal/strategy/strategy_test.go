@@ -0,0 +1,127 @@
+package strategy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"prediction-bot/internal/config"
+)
+
+const tightStops = `
+schema: 1
+name: tight-stops
+version: 3
+description: Tighter stops
+filters:
+  min_liquidity: 250
+  max_hours_to_close: 24
+thresholds:
+  probability_threshold: 0.85
+sizing:
+  kelly_fraction: 0.15
+exits:
+  stop_loss_percent: 0.10
+  stop_loss_type: trailing
+`
+
+func TestParse_AppliesOverrides(t *testing.T) {
+	s, err := Parse([]byte(tightStops))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if s.Name != "tight-stops" || s.Version != 3 || s.Label() != "tight-stops v3" {
+		t.Errorf("unexpected strategy %+v", s)
+	}
+
+	base := config.Parameters{
+		ProbabilityThreshold: 0.80,
+		StopLossPercent:      0.15,
+		StopLossType:         "fixed",
+		TakeProfitPercent:    0.05,
+		KellyFraction:        0.25,
+		MinLiquidity:         100,
+		MaxHoursToClose:      48,
+		MaxSpread:            0.10,
+	}
+	params := s.Apply(base)
+	if params.ProbabilityThreshold != 0.85 || params.StopLossPercent != 0.10 || params.StopLossType != "trailing" ||
+		params.KellyFraction != 0.15 || params.MinLiquidity != 250 || params.MaxHoursToClose != 24 {
+		t.Errorf("expected overrides applied, got %+v", params)
+	}
+	if params.TakeProfitPercent != 0.05 || params.MaxSpread != 0.10 {
+		t.Errorf("expected parameters left out kept, got %+v", params)
+	}
+	if base.KellyFraction != 0.25 {
+		t.Error("expected base parameters unchanged")
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	s, err := Parse([]byte(`{"schema": 1, "name": "wide", "version": 1, "filters": {"max_spread": 0.2}}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if s.Filters.MaxSpread == nil || *s.Filters.MaxSpread != 0.2 {
+		t.Errorf("expected max spread 0.2, got %+v", s.Filters)
+	}
+}
+
+func TestParse_ValidatesAgainstSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{"missing version", "schema: 1\nname: a", "version is required"},
+		{"unknown schema", "schema: 2\nname: a\nversion: 1", "schema must be one of 1"},
+		{"fractional version", "schema: 1\nname: a\nversion: 1.5", "version must be an integer"},
+		{"invalid name", "schema: 1\nname: Tight Stops\nversion: 1", "name"},
+		{"unknown section", "schema: 1\nname: a\nversion: 1\nentries: {}", "entries is not a known property"},
+		{"misspelled key", "schema: 1\nname: a\nversion: 1\nsizing:\n  kelly: 0.5", "sizing.kelly is not a known property"},
+		{"out of range", "schema: 1\nname: a\nversion: 1\nsizing:\n  kelly_fraction: 1.5", "sizing.kelly_fraction must be at most 1"},
+		{"zero stop", "schema: 1\nname: a\nversion: 1\nexits:\n  stop_loss_percent: 0", "exits.stop_loss_percent must be above 0"},
+		{"wrong type", "schema: 1\nname: a\nversion: 1\nfilters:\n  min_volume: lots", "filters.min_volume must be a number"},
+		{"unknown stop type", "schema: 1\nname: a\nversion: 1\nexits:\n  stop_loss_type: chandelier", "exits.stop_loss_type must be one of fixed, trailing"},
+		{"not an object", "- a", "strategy must be an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.document))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestExampleStrategiesAreValid(t *testing.T) {
+	paths, err := filepath.Glob("../../strategies/*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("expected example strategies")
+	}
+	for _, path := range paths {
+		if _, err := Load(path); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+func TestLoad_NamesFileInErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(path, []byte("schema: 1\nname: a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("expected error naming %s, got %v", path, err)
+	}
+}
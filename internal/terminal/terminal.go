@@ -0,0 +1,64 @@
+// Package terminal detects limited terminals and adapts output to them.
+package terminal
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// Plain reports whether output to f should be plain ASCII without color:
+// when NO_COLOR is set (https://no-color.org), TERM is "dumb", or f is not
+// a terminal, such as when output is piped to a file.
+func Plain(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// asciiReplacer transliterates the non-ASCII symbols the bot prints.
+var asciiReplacer = strings.NewReplacer(
+	"⚠️", "!!",
+	"⚠", "!",
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+", "╠", "+", "╣", "+",
+	"═", "=", "║", "|",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	"─", "-", "│", "|",
+	"•", "*",
+	"→", "->",
+)
+
+// ASCII replaces box-drawing characters, arrows and symbols in s with ASCII
+// equivalents.
+func ASCII(s string) string {
+	return asciiReplacer.Replace(s)
+}
+
+// asciiWriter transliterates everything written through it.
+type asciiWriter struct {
+	w io.Writer
+}
+
+// NewASCIIWriter returns a writer that replaces non-ASCII symbols as ASCII
+// does before writing to w. Each write is transliterated on its own, so
+// callers must not split a character across writes; fmt and zerolog write
+// whole lines.
+func NewASCIIWriter(w io.Writer) io.Writer {
+	return asciiWriter{w: w}
+}
+
+// Write implements io.Writer.
+func (a asciiWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(a.w, ASCII(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,54 @@
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestPlain(t *testing.T) {
+	f, err := os.CreateTemp("", "test_terminal_*.log")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	// A file is never a terminal
+	if !Plain(f) {
+		t.Error("expected output to a file to be plain")
+	}
+
+	// Environment overrides apply regardless of the output
+	t.Setenv("TERM", "dumb")
+	if !Plain(os.Stdout) {
+		t.Error("expected dumb terminal to be plain")
+	}
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("NO_COLOR", "1")
+	if !Plain(os.Stdout) {
+		t.Error("expected NO_COLOR to be plain")
+	}
+}
+
+func TestASCIIWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewASCIIWriter(&buf)
+
+	n, err := fmt.Fprintf(w, "╭─ %s ─╮ q quit • r refresh  $1 → $2 ⚠️\n", "Bankroll")
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if n == 0 {
+		t.Error("expected bytes written to be reported")
+	}
+
+	want := "+- Bankroll -+ q quit * r refresh  $1 -> $2 !!\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
@@ -51,10 +51,21 @@ type AnalysisInput struct {
 	TimeToCloseHours float64
 	// IsCrypto indicates if this is a crypto asset (affects annualization)
 	IsCrypto bool
+	// AnnualizationDays overrides the trading days per year implied by
+	// IsCrypto when > 0
+	AnnualizationDays float64
+	// TermStructure holds realized volatility by horizon. When set, the term
+	// closest to TimeToCloseHours is used instead of Volatility
+	TermStructure TermStructure
 }
 
 // AnalysisResult contains the output of volatility analysis
 type AnalysisResult struct {
+	// Volatility is the annualized volatility the analysis used
+	Volatility float64
+	// Horizon is the term structure horizon Volatility was taken from (zero
+	// if no term structure was given)
+	Horizon time.Duration
 	// DistanceToStrike is the relative distance from current to strike
 	// Positive means favorable direction
 	DistanceToStrike float64
@@ -82,7 +93,14 @@ type AnalysisResult struct {
 // A higher safety margin indicates a safer trade.
 func Analyze(input AnalysisInput) AnalysisResult {
 	result := AnalysisResult{
-		Timestamp: time.Now(),
+		Volatility: input.Volatility,
+		Timestamp:  time.Now(),
+	}
+
+	timeToClose := time.Duration(input.TimeToCloseHours * float64(time.Hour))
+	if term, ok := input.TermStructure.Closest(timeToClose); ok {
+		result.Volatility = term.Volatility
+		result.Horizon = term.Horizon
 	}
 
 	// Validate input
@@ -114,15 +132,13 @@ func Analyze(input AnalysisInput) AnalysisResult {
 
 	// Calculate expected move
 	// expected_move = volatility * sqrt(time_in_years)
-	var tradingDays float64
-	if input.IsCrypto {
-		tradingDays = TradingDaysCrypto
-	} else {
-		tradingDays = TradingDaysStock
+	days := tradingDays(input.IsCrypto)
+	if input.AnnualizationDays > 0 {
+		days = input.AnnualizationDays
 	}
 
-	timeInYears := input.TimeToCloseHours / 24.0 / tradingDays
-	result.ExpectedMove = input.Volatility * math.Sqrt(timeInYears)
+	timeInYears := input.TimeToCloseHours / 24.0 / days
+	result.ExpectedMove = result.Volatility * math.Sqrt(timeInYears)
 
 	// Calculate safety margin
 	// safety_margin = distance_to_strike / (2 * expected_move)
@@ -160,3 +176,12 @@ func determineRecommendation(safetyMargin float64) Recommendation {
 		return RecommendationReject
 	}
 }
+
+// WinProbability returns the model probability that the price ends on the
+// favorable side of the strike, for a position with the given safety
+// margin. The price is modeled as a driftless normal move with standard
+// deviation ExpectedMove, so the strike is 2 * safety_margin deviations
+// away.
+func WinProbability(safetyMargin float64) float64 {
+	return 0.5 * math.Erfc(-2*safetyMargin/math.Sqrt2)
+}
@@ -1,6 +1,7 @@
 package volatility
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -305,3 +306,74 @@ func TestAnalyze_PopulatesTimestamp(t *testing.T) {
 		t.Errorf("Expected timestamp between %v and %v, got %v", before, after, result.Timestamp)
 	}
 }
+
+func TestAnalyze_AnnualizationDaysOverridesDefault(t *testing.T) {
+	input := AnalysisInput{
+		CurrentPrice:     100.0,
+		StrikePrice:      90.0,
+		Direction:        DirectionAbove,
+		Volatility:       0.3,
+		TimeToCloseHours: 24,
+		IsCrypto:         true,
+	}
+	defaultResult := Analyze(input)
+
+	// Annualizing crypto over stock trading days matches a stock analysis
+	input.AnnualizationDays = TradingDaysStock
+	customResult := Analyze(input)
+
+	input.IsCrypto = false
+	input.AnnualizationDays = 0
+	stockResult := Analyze(input)
+
+	if customResult.ExpectedMove <= defaultResult.ExpectedMove {
+		t.Errorf("Expected fewer annualization days to raise the expected move, got %.4f vs default %.4f",
+			customResult.ExpectedMove, defaultResult.ExpectedMove)
+	}
+	if math.Abs(customResult.ExpectedMove-stockResult.ExpectedMove) > 1e-12 {
+		t.Errorf("Expected move %.6f to match stock move %.6f", customResult.ExpectedMove, stockResult.ExpectedMove)
+	}
+}
+
+func TestAnalyze_UsesClosestTerm(t *testing.T) {
+	input := AnalysisInput{
+		CurrentPrice:     100.0,
+		StrikePrice:      90.0,
+		Direction:        DirectionAbove,
+		Volatility:       0.3,
+		TimeToCloseHours: 24,
+		IsCrypto:         true,
+		TermStructure: TermStructure{
+			{Horizon: 7 * 24 * time.Hour, Volatility: 0.9},
+			{Horizon: 30 * 24 * time.Hour, Volatility: 0.5},
+		},
+	}
+
+	result := Analyze(input)
+
+	if result.Volatility != 0.9 || result.Horizon != 7*24*time.Hour {
+		t.Errorf("Expected 7d volatility 0.9, got %v over %v", result.Volatility, result.Horizon)
+	}
+	expectedMove := 0.9 * math.Sqrt(1.0/TradingDaysCrypto)
+	if math.Abs(result.ExpectedMove-expectedMove) > 1e-12 {
+		t.Errorf("Expected move %.6f, got %.6f", expectedMove, result.ExpectedMove)
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	tests := []struct {
+		safetyMargin float64
+		want         float64
+	}{
+		{0, 0.5},
+		{0.5, 0.8413},  // One standard deviation
+		{1.0, 0.9772},  // Two
+		{-0.5, 0.1587}, // Wrong side of the strike
+	}
+
+	for _, tt := range tests {
+		if got := WinProbability(tt.safetyMargin); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("WinProbability(%v) = %v, want %v", tt.safetyMargin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package volatility
+
+// AssetConfig tunes volatility analysis for a single asset. Zero values keep
+// the defaults.
+type AssetConfig struct {
+	// AnnualizationDays is the number of trading days per year used to
+	// annualize volatility (defaults to the asset class's, see TradingDaysFor)
+	AnnualizationDays float64
+	// MinVolatility is the floor applied to calculated volatility
+	MinVolatility float64
+	// MaxVolatility is the ceiling applied to calculated volatility
+	MaxVolatility float64
+	// Override is used instead of calculated volatility when > 0, e.g. for
+	// assets without enough price history
+	Override float64
+}
+
+// Bound clamps a calculated volatility to the configured floor and ceiling.
+func (c AssetConfig) Bound(vol float64) float64 {
+	if c.MinVolatility > 0 && vol < c.MinVolatility {
+		vol = c.MinVolatility
+	}
+	if c.MaxVolatility > 0 && vol > c.MaxVolatility {
+		vol = c.MaxVolatility
+	}
+	return vol
+}
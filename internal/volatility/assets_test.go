@@ -0,0 +1,25 @@
+package volatility
+
+import "testing"
+
+func TestAssetConfig_Bound(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      AssetConfig
+		vol      float64
+		expected float64
+	}{
+		{"no limits", AssetConfig{}, 0.05, 0.05},
+		{"raised to floor", AssetConfig{MinVolatility: 0.3}, 0.1, 0.3},
+		{"capped at ceiling", AssetConfig{MaxVolatility: 1.5}, 2.4, 1.5},
+		{"within limits", AssetConfig{MinVolatility: 0.3, MaxVolatility: 1.5}, 0.8, 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Bound(tt.vol); got != tt.expected {
+				t.Errorf("Bound(%v) = %v, want %v", tt.vol, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package volatility
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// BackfillResult summarizes a volatility backfill.
+type BackfillResult struct {
+	Asset   string
+	Fetched int // Prices fetched from the data sources
+	Stored  int // Prices stored for the asset over the backfilled period
+	First   time.Time
+	Last    time.Time
+	// Days is how many days of rolling volatility were computed.
+	Days int
+	// Latest is the term structure as of the last day computed.
+	Latest TermStructure
+}
+
+// RollingTerms is a term structure as of a day's close.
+type RollingTerms struct {
+	AsOf  time.Time
+	Terms TermStructure
+}
+
+// Backfill fetches days of hourly price history for an asset in one go,
+// stores it, and stores the rolling term structure as of each day's close
+// in rolling. Run on a fresh deployment, it gives the first scans a full
+// history instead of building it up one fetch at a time. Needs a price
+// history repository (see SetPriceHistoryRepository).
+func (s *Service) Backfill(asset string, days int, rolling *persistence.RollingVolatilityRepository) (BackfillResult, error) {
+	result := BackfillResult{Asset: strings.ToUpper(asset)}
+	if s.historyRepo == nil {
+		return result, fmt.Errorf("backfill %s: no price history repository", asset)
+	}
+	if days <= 0 {
+		return result, fmt.Errorf("backfill %s: days must be positive, got %d", asset, days)
+	}
+
+	fetched, err := s.aggregator.GetHistory(asset, days*24)
+	if err != nil {
+		return result, fmt.Errorf("failed to get history for %s: %w", asset, err)
+	}
+	result.Fetched = len(fetched)
+	if err := s.historyRepo.Save(result.Asset, fetched); err != nil {
+		return result, err
+	}
+
+	stored, err := s.historyRepo.GetSince(result.Asset, time.Now().Add(-time.Duration(days)*24*time.Hour))
+	if err != nil {
+		return result, err
+	}
+	result.Stored = len(stored)
+	if len(stored) == 0 {
+		return result, nil
+	}
+	result.First, result.Last = stored[0].Timestamp, stored[len(stored)-1].Timestamp
+
+	cfg := s.assets[result.Asset]
+	tradingDays := cfg.AnnualizationDays
+	if tradingDays <= 0 {
+		tradingDays = TradingDaysFor(s.aggregator.AssetClass(asset))
+	}
+
+	series := CalculateRollingTermStructure(stored, DefaultHorizons, tradingDays)
+	var volatilities []persistence.RollingVolatility
+	for _, day := range series {
+		for i := range day.Terms {
+			day.Terms[i].Volatility = cfg.Bound(day.Terms[i].Volatility)
+			volatilities = append(volatilities, persistence.RollingVolatility{
+				Asset:      result.Asset,
+				AsOf:       day.AsOf,
+				Horizon:    day.Terms[i].Horizon,
+				Volatility: day.Terms[i].Volatility,
+			})
+		}
+	}
+	if err := rolling.Save(volatilities); err != nil {
+		return result, err
+	}
+
+	result.Days = len(series)
+	if len(series) > 0 {
+		result.Latest = series[len(series)-1].Terms
+	}
+	return result, nil
+}
+
+// CalculateRollingTermStructure calculates the term structure as of each
+// UTC day's close (midnight after it) from the prices before it, for every
+// day with history covering the longest horizon, oldest first.
+func CalculateRollingTermStructure(prices []types.Price, horizons []time.Duration, tradingDays float64) []RollingTerms {
+	if len(prices) == 0 || len(horizons) == 0 {
+		return nil
+	}
+
+	sorted := make([]types.Price, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var longest time.Duration
+	for _, h := range horizons {
+		if h > longest {
+			longest = h
+		}
+	}
+	// A horizon of n days needs the close of the day before it too
+	lookback := longest + 24*time.Hour
+
+	first := sorted[0].Timestamp.UTC().Truncate(24 * time.Hour)
+	last := sorted[len(sorted)-1].Timestamp.UTC().Truncate(24 * time.Hour)
+
+	var series []RollingTerms
+	for asOf := first.Add(lookback); !asOf.After(last); asOf = asOf.Add(24 * time.Hour) {
+		from := sort.Search(len(sorted), func(i int) bool { return !sorted[i].Timestamp.Before(asOf.Add(-lookback)) })
+		to := sort.Search(len(sorted), func(i int) bool { return !sorted[i].Timestamp.Before(asOf) })
+
+		terms := CalculateTermStructure(sorted[from:to], horizons, tradingDays)
+		if len(terms) == 0 {
+			continue
+		}
+		series = append(series, RollingTerms{AsOf: asOf, Terms: terms})
+	}
+	return series
+}
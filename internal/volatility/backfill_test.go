@@ -0,0 +1,76 @@
+package volatility
+
+import (
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+func TestCalculateRollingTermStructure_OneStructurePerFullDay(t *testing.T) {
+	// Calm for 35 days, then a volatile final week
+	var closes []float64
+	price := 100.0
+	for i := 0; i < 35; i++ {
+		price *= 1.001
+		closes = append(closes, price)
+	}
+	for i := 0; i < 7; i++ {
+		if i%2 == 0 {
+			price *= 1.05
+		} else {
+			price *= 0.95
+		}
+		closes = append(closes, price)
+	}
+	prices := hourlyPrices(closes)
+
+	series := CalculateRollingTermStructure(prices, DefaultHorizons, TradingDaysCrypto)
+
+	// 42 days of history: the first day with 31 days behind it is day 31,
+	// and the last is the final day's start (its close is not in yet)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if len(series) != 11 {
+		t.Fatalf("expected 11 days, got %d", len(series))
+	}
+	if first := series[0].AsOf; !first.Equal(base.Add(31 * 24 * time.Hour)) {
+		t.Errorf("expected first day 31 days in, got %s", first)
+	}
+	if last := series[len(series)-1].AsOf; !last.Equal(base.Add(41 * 24 * time.Hour)) {
+		t.Errorf("expected last day 41 days in, got %s", last)
+	}
+
+	// Each day matches the term structure of the prices before it
+	for _, day := range []RollingTerms{series[0], series[len(series)-1]} {
+		var before []types.Price
+		for _, p := range prices {
+			if p.Timestamp.Before(day.AsOf) {
+				before = append(before, p)
+			}
+		}
+		want := CalculateTermStructure(before, DefaultHorizons, TradingDaysCrypto)
+		if len(day.Terms) != len(want) {
+			t.Fatalf("%s: expected %d terms, got %d", day.AsOf, len(want), len(day.Terms))
+		}
+		for i := range want {
+			if diff := day.Terms[i].Volatility - want[i].Volatility; diff > 1e-12 || diff < -1e-12 {
+				t.Errorf("%s: expected %v vol %.6f, got %.6f", day.AsOf, want[i].Horizon, want[i].Volatility, day.Terms[i].Volatility)
+			}
+		}
+	}
+
+	// The volatile week shows in the short horizon
+	calm, volatile := series[0].Terms[0].Volatility, series[len(series)-1].Terms[0].Volatility
+	if volatile <= calm {
+		t.Errorf("expected 7d vol to rise from %.4f after the volatile week, got %.4f", calm, volatile)
+	}
+}
+
+func TestCalculateRollingTermStructure_InsufficientHistory(t *testing.T) {
+	if series := CalculateRollingTermStructure(hourlyPrices([]float64{100, 101, 102}), DefaultHorizons, TradingDaysCrypto); len(series) != 0 {
+		t.Errorf("expected no days from 3 days of history, got %d", len(series))
+	}
+	if series := CalculateRollingTermStructure(nil, DefaultHorizons, TradingDaysCrypto); series != nil {
+		t.Errorf("expected nil for no prices, got %v", series)
+	}
+}
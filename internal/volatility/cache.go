@@ -0,0 +1,198 @@
+package volatility
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// CacheTTL is how long prices fetched for an asset class are reused before
+// they are fetched again. Zero fetches every time.
+type CacheTTL struct {
+	// Price is the TTL of the spot price
+	Price time.Duration
+	// History is the TTL of the hourly price history volatility is
+	// calculated from
+	History time.Duration
+}
+
+// CacheStats counts price lookups served from the cache (hits) and fetched
+// from the data sources (misses).
+type CacheStats struct {
+	PriceHits     int
+	PriceMisses   int
+	HistoryHits   int
+	HistoryMisses int
+}
+
+// HitRate returns the share of all lookups served from the cache, or 0
+// before any lookup.
+func (s CacheStats) HitRate() float64 {
+	hits := s.PriceHits + s.HistoryHits
+	total := hits + s.PriceMisses + s.HistoryMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+// cachedHistory is a price history and when it was fetched.
+type cachedHistory struct {
+	prices    []types.Price
+	fetchedAt time.Time
+}
+
+// priceCache holds the prices and histories fetched per asset symbol.
+type priceCache struct {
+	mu        sync.Mutex
+	ttls      map[string]CacheTTL
+	prices    map[string]persistence.CachedPrice
+	histories map[string]cachedHistory
+	stats     CacheStats
+	repo      *persistence.PriceCacheRepository
+	now       func() time.Time
+}
+
+func newPriceCache() *priceCache {
+	return &priceCache{
+		ttls:      make(map[string]CacheTTL),
+		prices:    make(map[string]persistence.CachedPrice),
+		histories: make(map[string]cachedHistory),
+		now:       time.Now,
+	}
+}
+
+// SetCacheTTL sets how long prices of an asset class (see
+// datasource.AssetClassCrypto) are cached.
+func (s *Service) SetCacheTTL(assetClass string, ttl CacheTTL) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	s.cache.ttls[assetClass] = ttl
+}
+
+// SetPriceCacheRepository sets the repository fetched spot prices are
+// stored in, so WarmUp can reuse those still fresh after a restart.
+func (s *Service) SetPriceCacheRepository(repo *persistence.PriceCacheRepository) {
+	s.cache.repo = repo
+}
+
+// CacheStats returns the cache hits and misses since the service was created.
+func (s *Service) CacheStats() CacheStats {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	return s.cache.stats
+}
+
+// WarmUp fills the cache before the first cycle: it loads the stored spot
+// prices, then fetches the price and history of each asset not already
+// cached. Returns the number of assets whose price and history are cached.
+// An asset that fails to fetch is left to be fetched when analyzed.
+func (s *Service) WarmUp(assets []string) (int, error) {
+	if s.cache.repo != nil {
+		stored, err := s.cache.repo.GetAll()
+		if err != nil {
+			return 0, err
+		}
+		s.cache.mu.Lock()
+		for _, p := range stored {
+			s.cache.prices[p.Symbol] = p
+		}
+		s.cache.mu.Unlock()
+	}
+
+	var fetched int
+	for _, asset := range assets {
+		if _, err := s.price(asset); err != nil {
+			continue
+		}
+		if _, err := s.cachedHistory(asset); err != nil {
+			continue
+		}
+		fetched++
+	}
+	return fetched, nil
+}
+
+// price returns the spot price of an asset, from the cache while it is
+// fresh.
+func (s *Service) price(asset string) (types.Price, error) {
+	symbol := strings.ToUpper(asset)
+	ttl := s.cacheTTL(asset).Price
+	c := s.cache
+
+	c.mu.Lock()
+	cached, ok := c.prices[symbol]
+	if ok && c.now().Sub(cached.FetchedAt) < ttl {
+		c.stats.PriceHits++
+		c.mu.Unlock()
+		return types.Price{Symbol: symbol, Price: cached.Price, Timestamp: cached.FetchedAt, Source: cached.Source}, nil
+	}
+	c.stats.PriceMisses++
+	c.mu.Unlock()
+
+	price, err := s.source.GetPrice(asset)
+	if err != nil {
+		return price, err
+	}
+
+	cached = persistence.CachedPrice{Symbol: symbol, Price: price.Price, Source: price.Source, FetchedAt: c.now()}
+	c.mu.Lock()
+	c.prices[symbol] = cached
+	c.mu.Unlock()
+	if c.repo != nil {
+		if err := c.repo.Save(cached); err != nil {
+			return price, fmt.Errorf("cache price for %s: %w", asset, err)
+		}
+	}
+	return price, nil
+}
+
+// cachedHistory returns the price history of an asset (see history), from
+// the cache while it is fresh.
+func (s *Service) cachedHistory(asset string) ([]types.Price, error) {
+	symbol := strings.ToUpper(asset)
+	ttl := s.cacheTTL(asset).History
+	c := s.cache
+
+	c.mu.Lock()
+	cached, ok := c.histories[symbol]
+	if ok && c.now().Sub(cached.fetchedAt) < ttl {
+		c.stats.HistoryHits++
+		c.mu.Unlock()
+		return cached.prices, nil
+	}
+	c.stats.HistoryMisses++
+	c.mu.Unlock()
+
+	history, err := s.history(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.histories[symbol] = cachedHistory{prices: history, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return history, nil
+}
+
+// cacheTTL returns the cache TTL of an asset's class.
+func (s *Service) cacheTTL(asset string) CacheTTL {
+	class := s.aggregator.AssetClass(asset)
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	return s.cache.ttls[class]
+}
+
+// Refresh drops an asset's cached price and history, so its next analysis
+// fetches them again.
+func (s *Service) Refresh(asset string) {
+	symbol := strings.ToUpper(asset)
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	delete(s.cache.prices, symbol)
+	delete(s.cache.histories, symbol)
+}
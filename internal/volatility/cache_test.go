@@ -0,0 +1,153 @@
+package volatility
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/datasource"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
+)
+
+// countingSource returns fixed prices, counting the fetches.
+type countingSource struct {
+	prices    int
+	histories int
+}
+
+func (s *countingSource) GetPrice(symbol string) (types.Price, error) {
+	s.prices++
+	return types.Price{Symbol: symbol, Price: 100000, Source: "test"}, nil
+}
+
+func (s *countingSource) GetHistory(symbol string, hours int) ([]types.Price, error) {
+	s.histories++
+	return []types.Price{{Symbol: symbol, Price: 99000}, {Symbol: symbol, Price: 100000}}, nil
+}
+
+func TestService_PriceCache(t *testing.T) {
+	source := &countingSource{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := NewService("")
+	service.source = source
+	service.cache.now = func() time.Time { return now }
+	service.SetCacheTTL(datasource.AssetClassCrypto, CacheTTL{Price: 30 * time.Second, History: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.price("BTC"); err != nil {
+			t.Fatalf("price failed: %v", err)
+		}
+		if _, err := service.cachedHistory("BTC"); err != nil {
+			t.Fatalf("cachedHistory failed: %v", err)
+		}
+	}
+	if source.prices != 1 || source.histories != 1 {
+		t.Errorf("Expected one fetch of each within the TTL, got %d prices and %d histories", source.prices, source.histories)
+	}
+
+	// The price expires before the history
+	now = now.Add(time.Minute)
+	service.price("BTC")
+	service.cachedHistory("BTC")
+	if source.prices != 2 || source.histories != 1 {
+		t.Errorf("Expected only the price refetched, got %d prices and %d histories", source.prices, source.histories)
+	}
+
+	want := CacheStats{PriceHits: 2, PriceMisses: 2, HistoryHits: 3, HistoryMisses: 1}
+	if stats := service.CacheStats(); stats != want {
+		t.Errorf("Expected %+v, got %+v", want, stats)
+	}
+	if rate := want.HitRate(); rate != 5.0/8 {
+		t.Errorf("Expected hit rate 5/8, got %v", rate)
+	}
+
+	// Asset classes without a TTL are fetched every time
+	service.price("SPX")
+	service.price("SPX")
+	if source.prices != 4 {
+		t.Errorf("Expected uncached prices fetched each time, got %d fetches", source.prices)
+	}
+
+	// A refresh refetches both within their TTLs
+	service.Refresh("btc")
+	service.price("BTC")
+	service.cachedHistory("BTC")
+	if source.prices != 5 || source.histories != 2 {
+		t.Errorf("Expected both refetched after a refresh, got %d prices and %d histories", source.prices, source.histories)
+	}
+}
+
+func TestService_WarmUp(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test_price_cache_*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	db, err := persistence.OpenDB(tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	if err := persistence.RunMigrations(db, "../../migrations"); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	repo := persistence.NewPriceCacheRepository(db)
+
+	now := time.Now()
+	ttl := CacheTTL{Price: time.Minute, History: time.Hour}
+
+	// A price fetched before a restart is stored
+	before := NewService("")
+	before.source = &countingSource{}
+	before.SetCacheTTL(datasource.AssetClassCrypto, ttl)
+	before.SetPriceCacheRepository(repo)
+	if _, err := before.price("ETH"); err != nil {
+		t.Fatalf("price failed: %v", err)
+	}
+
+	source := &countingSource{}
+	service := NewService("")
+	service.source = source
+	service.SetCacheTTL(datasource.AssetClassCrypto, ttl)
+	service.SetPriceCacheRepository(repo)
+	service.cache.now = func() time.Time { return now }
+
+	warmed, err := service.WarmUp([]string{"BTC", "ETH"})
+	if err != nil {
+		t.Fatalf("WarmUp failed: %v", err)
+	}
+	if warmed != 2 {
+		t.Errorf("Expected 2 assets warmed, got %d", warmed)
+	}
+	// ETH's stored price is still fresh; only BTC's is fetched
+	if source.prices != 1 || source.histories != 2 {
+		t.Errorf("Expected 1 price and 2 histories fetched, got %d and %d", source.prices, source.histories)
+	}
+
+	if _, err := service.AnalyzeAsset(context.Background(), "BTC", 90000, DirectionAbove, 24*time.Hour); err != nil {
+		t.Fatalf("AnalyzeAsset failed: %v", err)
+	}
+	if source.prices != 1 || source.histories != 2 {
+		t.Errorf("Expected the analysis served from the warmed cache, got %d prices and %d histories fetched", source.prices, source.histories)
+	}
+}
+
+func TestService_AnalyzeAsset_Cancelled(t *testing.T) {
+	source := &countingSource{}
+	service := NewService("")
+	service.source = source
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := service.AnalyzeAsset(ctx, "BTC", 90000, DirectionAbove, 24*time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if source.prices != 0 || source.histories != 0 {
+		t.Errorf("Expected nothing fetched once cancelled, got %d prices and %d histories", source.prices, source.histories)
+	}
+}
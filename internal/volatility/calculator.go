@@ -3,6 +3,7 @@ package volatility
 import (
 	"math"
 
+	"prediction-bot/internal/datasource"
 	"prediction-bot/pkg/types"
 )
 
@@ -11,6 +12,12 @@ const (
 	TradingDaysCrypto = 365
 	// TradingDaysStock is the number of trading days per year for stocks
 	TradingDaysStock = 252
+	// TradingDaysFX is the number of trading days per year for currencies
+	// (every weekday)
+	TradingDaysFX = 260
+	// TradingDaysCommodity is the number of trading days per year for
+	// commodity futures
+	TradingDaysCommodity = 252
 )
 
 // CalculateVolatility calculates the annualized volatility from a series of prices.
@@ -19,6 +26,13 @@ const (
 // For stocks (isCrypto=false), it uses 252 trading days.
 // Returns 0 if there are insufficient data points (less than 2 prices).
 func CalculateVolatility(prices []types.Price, isCrypto bool) float64 {
+	return CalculateVolatilityDays(prices, tradingDays(isCrypto))
+}
+
+// CalculateVolatilityDays calculates the annualized volatility from a series
+// of prices like CalculateVolatility, annualizing by the given number of
+// trading days per year.
+func CalculateVolatilityDays(prices []types.Price, tradingDays float64) float64 {
 	if len(prices) < 2 {
 		return 0
 	}
@@ -55,14 +69,32 @@ func CalculateVolatility(prices []types.Price, isCrypto bool) float64 {
 	dailyVol := math.Sqrt(variance)
 
 	// Annualize the volatility
-	var tradingDays float64
-	if isCrypto {
-		tradingDays = TradingDaysCrypto
-	} else {
-		tradingDays = TradingDaysStock
-	}
-
 	annualizedVol := dailyVol * math.Sqrt(tradingDays)
 
 	return annualizedVol
 }
+
+// tradingDays returns the default number of trading days per year for an
+// asset.
+func tradingDays(isCrypto bool) float64 {
+	if isCrypto {
+		return TradingDaysCrypto
+	}
+	return TradingDaysStock
+}
+
+// TradingDaysFor returns the default number of trading days per year for an
+// asset class (see datasource.AssetClassCrypto). Unknown classes trade like
+// stocks.
+func TradingDaysFor(assetClass string) float64 {
+	switch assetClass {
+	case datasource.AssetClassCrypto:
+		return TradingDaysCrypto
+	case datasource.AssetClassFX:
+		return TradingDaysFX
+	case datasource.AssetClassCommodity:
+		return TradingDaysCommodity
+	default:
+		return TradingDaysStock
+	}
+}
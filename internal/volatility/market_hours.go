@@ -0,0 +1,143 @@
+package volatility
+
+import (
+	"time"
+
+	"prediction-bot/internal/datasource"
+)
+
+// Regular trading session of US stock markets, in New York time.
+const (
+	sessionOpen   = 9*time.Hour + 30*time.Minute
+	sessionClose  = 16 * time.Hour
+	sessionLength = sessionClose - sessionOpen
+)
+
+// weekBoundary is when the currency and commodity futures week opens on
+// Sunday and closes on Friday, in New York time.
+const weekBoundary = 17 * time.Hour
+
+// marketZone is the time zone of the US stock market session.
+var marketZone = mustLoadLocation("America/New_York")
+
+// marketHolidays lists the weekdays US stock markets are closed, as
+// YYYY-MM-DD. Update it as exchanges publish their calendars.
+var marketHolidays = map[string]bool{
+	"2025-01-01": true, "2025-01-09": true, "2025-01-20": true, "2025-02-17": true,
+	"2025-04-18": true, "2025-05-26": true, "2025-06-19": true, "2025-07-04": true,
+	"2025-09-01": true, "2025-11-27": true, "2025-12-25": true,
+
+	"2026-01-01": true, "2026-01-19": true, "2026-02-16": true, "2026-04-03": true,
+	"2026-05-25": true, "2026-06-19": true, "2026-07-03": true, "2026-09-07": true,
+	"2026-11-26": true, "2026-12-25": true,
+
+	"2027-01-01": true, "2027-01-18": true, "2027-02-15": true, "2027-03-26": true,
+	"2027-05-31": true, "2027-06-18": true, "2027-07-05": true, "2027-09-06": true,
+	"2027-11-25": true, "2027-12-24": true,
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// IsTradingDay reports whether US stock markets hold a session on the New
+// York date of day.
+func IsTradingDay(day time.Time) bool {
+	day = day.In(marketZone)
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	return !marketHolidays[day.Format("2006-01-02")]
+}
+
+// SessionTime returns how much of the regular US stock market session
+// falls between from and to. Early closes are not modeled.
+func SessionTime(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	var total time.Duration
+	start := from.In(marketZone)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, marketZone)
+	for !day.After(to) {
+		if IsTradingDay(day) {
+			open, end := day.Add(sessionOpen), day.Add(sessionClose)
+			if open.Before(from) {
+				open = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(open) {
+				total += end.Sub(open)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// TradingTimeToClose converts the time from now until a stock market closes
+// to calendar time for analysis: each full session counts as a day. Stock
+// volatility is annualized over trading days, so only time the market
+// trades adds to the expected move; nights, weekends and holidays do not.
+func TradingTimeToClose(now, closeTime time.Time) time.Duration {
+	return time.Duration(float64(SessionTime(now, closeTime)) * float64(24*time.Hour) / float64(sessionLength))
+}
+
+// WeekdayTime returns how much of the currency and commodity futures week,
+// Sunday 5pm to Friday 5pm New York time, falls between from and to. The
+// daily maintenance break and holidays are not modeled.
+func WeekdayTime(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	var total time.Duration
+	start := from.In(marketZone)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, marketZone)
+	for !day.After(to) {
+		next := day.AddDate(0, 0, 1)
+		open, end := day, next
+		switch day.Weekday() {
+		case time.Saturday:
+			end = open
+		case time.Sunday:
+			open = day.Add(weekBoundary)
+		case time.Friday:
+			end = day.Add(weekBoundary)
+		}
+		if open.Before(from) {
+			open = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(open) {
+			total += end.Sub(open)
+		}
+		day = next
+	}
+	return total
+}
+
+// AnalysisTimeToClose converts the time from now until a market on an
+// asset of the given class closes (see datasource.AssetClassCrypto) to the
+// time the analysis should price: crypto trades around the clock, currencies
+// and commodities every weekday, and stocks only in their sessions (see
+// TradingTimeToClose).
+func AnalysisTimeToClose(assetClass string, now, closeTime time.Time) time.Duration {
+	switch assetClass {
+	case datasource.AssetClassCrypto:
+		return closeTime.Sub(now)
+	case datasource.AssetClassFX, datasource.AssetClassCommodity:
+		return WeekdayTime(now, closeTime)
+	default:
+		return TradingTimeToClose(now, closeTime)
+	}
+}
@@ -0,0 +1,122 @@
+package volatility
+
+import (
+	"testing"
+	"time"
+
+	"prediction-bot/internal/datasource"
+)
+
+func TestIsTradingDay(t *testing.T) {
+	tests := []struct {
+		day  string
+		want bool
+	}{
+		{"2026-01-20", true},  // Tuesday
+		{"2026-01-17", false}, // Saturday
+		{"2026-01-19", false}, // Martin Luther King Jr. Day
+		{"2026-07-03", false}, // Independence Day observed
+	}
+
+	for _, tt := range tests {
+		day, _ := time.ParseInLocation("2006-01-02", tt.day, marketZone)
+		if got := IsTradingDay(day.Add(12 * time.Hour)); got != tt.want {
+			t.Errorf("IsTradingDay(%s) = %v, want %v", tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestSessionTime(t *testing.T) {
+	at := func(day string, hour, minute int) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", day, marketZone)
+		return d.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"within a session", at("2026-01-20", 10, 0), at("2026-01-20", 12, 30), 150 * time.Minute},
+		{"before the open to the close", at("2026-01-20", 6, 0), at("2026-01-20", 16, 0), sessionLength},
+		{"overnight", at("2026-01-20", 15, 0), at("2026-01-21", 10, 30), 2 * time.Hour},
+		{"over a weekend", at("2026-01-23", 15, 0), at("2026-01-26", 10, 30), 2 * time.Hour},
+		{"over a long weekend", at("2026-01-16", 16, 0), at("2026-01-20", 16, 0), sessionLength},
+		{"market closed throughout", at("2026-01-24", 9, 0), at("2026-01-25", 17, 0), 0},
+		{"backwards", at("2026-01-21", 10, 0), at("2026-01-20", 10, 0), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SessionTime(tt.from, tt.to); got != tt.want {
+				t.Errorf("SessionTime = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTradingTimeToClose_CountsSessionsAsDays(t *testing.T) {
+	// Friday's close to Monday's close is one session
+	friday := time.Date(2026, 1, 23, 21, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 1, 26, 21, 0, 0, 0, time.UTC)
+	if got := TradingTimeToClose(friday, monday); got != 24*time.Hour {
+		t.Errorf("expected 24h, got %s", got)
+	}
+
+	// Half a session is half a day
+	open := time.Date(2026, 1, 20, 14, 30, 0, 0, time.UTC)
+	if got := TradingTimeToClose(open, open.Add(sessionLength/2)); got != 12*time.Hour {
+		t.Errorf("expected 12h, got %s", got)
+	}
+}
+
+func TestWeekdayTime(t *testing.T) {
+	at := func(day string, hour int) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", day, marketZone)
+		return d.Add(time.Duration(hour) * time.Hour)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"overnight", at("2026-01-20", 20), at("2026-01-21", 8), 12 * time.Hour},
+		{"over a weekend", at("2026-01-23", 12), at("2026-01-26", 12), 24 * time.Hour},
+		{"friday close to sunday open", at("2026-01-23", 17), at("2026-01-25", 17), 0},
+		{"backwards", at("2026-01-21", 10), at("2026-01-20", 10), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeekdayTime(tt.from, tt.to); got != tt.want {
+				t.Errorf("WeekdayTime = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalysisTimeToClose_ByAssetClass(t *testing.T) {
+	// Friday noon to Monday noon in New York
+	friday := time.Date(2026, 1, 23, 17, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 1, 26, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		class string
+		want  time.Duration
+	}{
+		{datasource.AssetClassCrypto, 72 * time.Hour},
+		{datasource.AssetClassFX, 24 * time.Hour},
+		{datasource.AssetClassCommodity, 24 * time.Hour},
+		{datasource.AssetClassStock, TradingTimeToClose(friday, monday)},
+	}
+
+	for _, tt := range tests {
+		if got := AnalysisTimeToClose(tt.class, friday, monday); got != tt.want {
+			t.Errorf("AnalysisTimeToClose(%s) = %s, want %s", tt.class, got, tt.want)
+		}
+	}
+	if got := TradingDaysFor(datasource.AssetClassFX); got != TradingDaysFX {
+		t.Errorf("expected FX annualized over %d days, got %v", TradingDaysFX, got)
+	}
+}
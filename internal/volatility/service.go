@@ -1,12 +1,20 @@
 package volatility
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"prediction-bot/internal/datasource"
+	"prediction-bot/internal/persistence"
+	"prediction-bot/pkg/types"
 )
 
+// historyHours is how much hourly price history is used, enough for the
+// longest default horizon plus a day.
+const historyHours = 31 * 24
+
 // ServiceResult contains the complete volatility analysis result with context
 type ServiceResult struct {
 	// Asset is the analyzed asset name (e.g., "BTC", "ETH")
@@ -19,10 +27,25 @@ type ServiceResult struct {
 	Direction Direction
 	// TimeToClose is the duration until market closes
 	TimeToClose time.Duration
+	// AnalysisTimeToClose is the time to close the analysis used: for
+	// stocks, the trading sessions until close counted as days (see
+	// TradingTimeToClose); for currencies and commodities, the weekday time
+	// until close; for crypto, TimeToClose
+	AnalysisTimeToClose time.Duration
 	// IsCrypto indicates if this is a cryptocurrency
 	IsCrypto bool
-	// Volatility is the calculated annualized volatility
+	// AssetClass is the asset's class (see datasource.AssetClassCrypto)
+	AssetClass string
+	// Volatility is the annualized volatility used for the analysis
 	Volatility float64
+	// TermStructure is the realized volatility by horizon (empty if
+	// Volatility was overridden)
+	TermStructure TermStructure
+	// VolatilityHorizon is the horizon Volatility was taken from, the one
+	// closest to TimeToClose (zero if not from the term structure)
+	VolatilityHorizon time.Duration
+	// Overridden indicates Volatility was set manually rather than calculated
+	Overridden bool
 	// DistanceToStrike is the relative distance from current to strike
 	DistanceToStrike float64
 	// ExpectedMove is the expected price movement based on volatility
@@ -37,17 +60,50 @@ type ServiceResult struct {
 
 // Service combines data source and volatility analysis capabilities
 type Service struct {
-	aggregator *datasource.Aggregator
+	aggregator   *datasource.Aggregator
+	source       datasource.PriceProvider // The aggregator, replaced in tests
+	cache        *priceCache
+	assets       map[string]AssetConfig
+	overrideRepo *persistence.VolatilityOverrideRepository
+	historyRepo  *persistence.PriceHistoryRepository
 }
 
 // NewService creates a new volatility service.
 // alphaVantageKey can be empty if only crypto analysis is needed.
 func NewService(alphaVantageKey string) *Service {
+	aggregator := datasource.NewAggregator(alphaVantageKey)
 	return &Service{
-		aggregator: datasource.NewAggregator(alphaVantageKey),
+		aggregator: aggregator,
+		source:     aggregator,
+		cache:      newPriceCache(),
+		assets:     make(map[string]AssetConfig),
 	}
 }
 
+// SetAssetConfig sets the volatility configuration for an asset symbol
+// (e.g. "BTC", "SOL").
+func (s *Service) SetAssetConfig(asset string, cfg AssetConfig) {
+	s.assets[strings.ToUpper(asset)] = cfg
+}
+
+// SetPolygonKey adds Polygon as a stock and index data source.
+func (s *Service) SetPolygonKey(apiKey string) {
+	s.aggregator.SetPolygonKey(apiKey)
+}
+
+// SetOverrideRepository sets the repository of manual volatility overrides.
+// An override stored there takes precedence over one in the asset config.
+func (s *Service) SetOverrideRepository(repo *persistence.VolatilityOverrideRepository) {
+	s.overrideRepo = repo
+}
+
+// SetPriceHistoryRepository sets the repository fetched price history is
+// stored in. Volatility is then calculated from the stored history, which
+// also covers data sources being temporarily unavailable.
+func (s *Service) SetPriceHistoryRepository(repo *persistence.PriceHistoryRepository) {
+	s.historyRepo = repo
+}
+
 // AnalyzeAsset fetches real price data and performs volatility analysis.
 // It returns a complete ServiceResult with all analysis data.
 //
@@ -56,7 +112,10 @@ func NewService(alphaVantageKey string) *Service {
 //   - strikePrice: The strike price for the market condition
 //   - direction: Whether betting above or below strike
 //   - timeToClose: Duration until market closes
-func (s *Service) AnalyzeAsset(asset string, strikePrice float64, direction Direction, timeToClose time.Duration) (ServiceResult, error) {
+//
+// The data sources don't take a context, so a cancelled ctx stops the
+// analysis before each fetch rather than during one.
+func (s *Service) AnalyzeAsset(ctx context.Context, asset string, strikePrice float64, direction Direction, timeToClose time.Duration) (ServiceResult, error) {
 	result := ServiceResult{
 		Asset:       asset,
 		StrikePrice: strikePrice,
@@ -66,39 +125,71 @@ func (s *Service) AnalyzeAsset(asset string, strikePrice float64, direction Dire
 	}
 
 	// Get current price
-	price, err := s.aggregator.GetPrice(asset)
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	price, err := s.price(asset)
 	if err != nil {
 		return result, fmt.Errorf("failed to get current price for %s: %w", asset, err)
 	}
 	result.CurrentPrice = price.Price
 	result.IsCrypto = s.aggregator.IsCrypto(asset)
+	result.AssetClass = s.aggregator.AssetClass(asset)
+	result.AnalysisTimeToClose = AnalysisTimeToClose(result.AssetClass, result.Timestamp, result.Timestamp.Add(timeToClose))
 
-	// Get historical data for volatility calculation (14 days = 336 hours)
-	const historyHours = 336
-	history, err := s.aggregator.GetHistory(asset, historyHours)
+	cfg := s.assets[strings.ToUpper(asset)]
+	days := cfg.AnnualizationDays
+	if days <= 0 {
+		days = TradingDaysFor(result.AssetClass)
+	}
+
+	override, err := s.override(asset, cfg)
 	if err != nil {
-		return result, fmt.Errorf("failed to get history for %s: %w", asset, err)
+		return result, err
 	}
 
-	// Calculate volatility
-	result.Volatility = CalculateVolatility(history, result.IsCrypto)
-	if result.Volatility <= 0 {
-		return result, fmt.Errorf("could not calculate volatility for %s: insufficient data", asset)
+	if override > 0 {
+		result.Volatility = override
+		result.Overridden = true
+	} else {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		history, err := s.cachedHistory(asset)
+		if err != nil {
+			return result, fmt.Errorf("failed to get history for %s: %w", asset, err)
+		}
+
+		// Calculate volatility, overall and by horizon
+		result.Volatility = CalculateVolatilityDays(history, days)
+		if result.Volatility <= 0 {
+			return result, fmt.Errorf("could not calculate volatility for %s: insufficient data", asset)
+		}
+		result.Volatility = cfg.Bound(result.Volatility)
+
+		result.TermStructure = CalculateTermStructure(history, DefaultHorizons, days)
+		for i := range result.TermStructure {
+			result.TermStructure[i].Volatility = cfg.Bound(result.TermStructure[i].Volatility)
+		}
 	}
 
 	// Perform analysis
 	analysisInput := AnalysisInput{
-		CurrentPrice:     result.CurrentPrice,
-		StrikePrice:      strikePrice,
-		Direction:        direction,
-		Volatility:       result.Volatility,
-		TimeToCloseHours: timeToClose.Hours(),
-		IsCrypto:         result.IsCrypto,
+		CurrentPrice:      result.CurrentPrice,
+		StrikePrice:       strikePrice,
+		Direction:         direction,
+		Volatility:        result.Volatility,
+		TimeToCloseHours:  result.AnalysisTimeToClose.Hours(),
+		IsCrypto:          result.IsCrypto,
+		AnnualizationDays: days,
+		TermStructure:     result.TermStructure,
 	}
 
 	analysisResult := Analyze(analysisInput)
 
 	// Copy analysis results
+	result.Volatility = analysisResult.Volatility
+	result.VolatilityHorizon = analysisResult.Horizon
 	result.DistanceToStrike = analysisResult.DistanceToStrike
 	result.ExpectedMove = analysisResult.ExpectedMove
 	result.SafetyMargin = analysisResult.SafetyMargin
@@ -106,3 +197,44 @@ func (s *Service) AnalyzeAsset(asset string, strikePrice float64, direction Dire
 
 	return result, nil
 }
+
+// history returns the hourly price history of an asset over historyHours.
+// With a history repository, fetched prices are stored first and the stored
+// history is returned, so a failed fetch falls back to what is stored.
+func (s *Service) history(asset string) ([]types.Price, error) {
+	fetched, fetchErr := s.source.GetHistory(asset, historyHours)
+	if s.historyRepo == nil {
+		return fetched, fetchErr
+	}
+
+	symbol := strings.ToUpper(asset)
+	if fetchErr == nil {
+		if err := s.historyRepo.Save(symbol, fetched); err != nil {
+			return nil, err
+		}
+	}
+
+	stored, err := s.historyRepo.GetSince(symbol, time.Now().Add(-historyHours*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) < 2 && fetchErr != nil {
+		return nil, fetchErr
+	}
+	return stored, nil
+}
+
+// override returns the manual volatility for an asset, or 0 if none is set.
+// The override repository takes precedence over the asset config.
+func (s *Service) override(asset string, cfg AssetConfig) (float64, error) {
+	if s.overrideRepo != nil {
+		o, err := s.overrideRepo.Get(asset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get volatility override for %s: %w", asset, err)
+		}
+		if o != nil && o.Volatility > 0 {
+			return o.Volatility, nil
+		}
+	}
+	return cfg.Override, nil
+}
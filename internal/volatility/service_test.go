@@ -1,6 +1,7 @@
 package volatility
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -16,7 +17,7 @@ func TestVolatilityService_AnalyzeAsset(t *testing.T) {
 	service := NewService("")
 
 	// Test: AnalyzeAsset("BTC", $100000, "above", 24h) busca dados reais e retorna análise
-	result, err := service.AnalyzeAsset("BTC", 100000, DirectionAbove, 24*time.Hour)
+	result, err := service.AnalyzeAsset(context.Background(), "BTC", 100000, DirectionAbove, 24*time.Hour)
 	if err != nil {
 		t.Fatalf("AnalyzeAsset failed: %v", err)
 	}
@@ -56,7 +57,7 @@ func TestVolatilityService_AnalyzeAsset(t *testing.T) {
 func TestVolatilityService_AnalyzeAsset_UnknownAsset(t *testing.T) {
 	service := NewService("")
 
-	_, err := service.AnalyzeAsset("UNKNOWNASSET", 100, DirectionAbove, 24*time.Hour)
+	_, err := service.AnalyzeAsset(context.Background(), "UNKNOWNASSET", 100, DirectionAbove, 24*time.Hour)
 	if err == nil {
 		t.Error("Expected error for unknown asset, got nil")
 	}
@@ -70,7 +71,7 @@ func TestVolatilityService_AnalyzeAsset_ETH(t *testing.T) {
 	service := NewService("")
 
 	// Test with ETH to verify other crypto assets work
-	result, err := service.AnalyzeAsset("ETH", 3000, DirectionBelow, 48*time.Hour)
+	result, err := service.AnalyzeAsset(context.Background(), "ETH", 3000, DirectionBelow, 48*time.Hour)
 	if err != nil {
 		t.Fatalf("AnalyzeAsset for ETH failed: %v", err)
 	}
@@ -0,0 +1,121 @@
+package volatility
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// DefaultHorizons are the horizons of the realized volatility term structure.
+var DefaultHorizons = []time.Duration{7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// VolatilityTerm is the annualized realized volatility over one horizon.
+type VolatilityTerm struct {
+	Horizon    time.Duration
+	Volatility float64
+}
+
+// TermStructure is a set of realized volatilities by horizon, shortest first.
+type TermStructure []VolatilityTerm
+
+// Closest returns the term whose horizon is closest to the given time to
+// close, and false if the structure is empty.
+func (ts TermStructure) Closest(timeToClose time.Duration) (VolatilityTerm, bool) {
+	if len(ts) == 0 {
+		return VolatilityTerm{}, false
+	}
+
+	best := ts[0]
+	for _, term := range ts[1:] {
+		if absDuration(term.Horizon-timeToClose) < absDuration(best.Horizon-timeToClose) {
+			best = term
+		}
+	}
+	return best, true
+}
+
+// CalculateTermStructure calculates the EWMA realized volatility of daily
+// closes for each horizon, annualized by tradingDays. Each horizon's decay
+// uses a span of that many days (lambda = 1 - 2/(days+1)), so short horizons
+// react faster to recent moves. Horizons without at least two daily returns
+// are left out.
+func CalculateTermStructure(prices []types.Price, horizons []time.Duration, tradingDays float64) TermStructure {
+	returns := dailyLogReturns(prices)
+
+	var ts TermStructure
+	for _, horizon := range horizons {
+		days := int(math.Round(horizon.Hours() / 24))
+		if days < 2 || len(returns) < 2 {
+			continue
+		}
+
+		window := returns
+		if len(window) > days {
+			window = window[len(window)-days:]
+		}
+
+		lambda := 1 - 2/float64(days+1)
+		dailyVol := math.Sqrt(ewmaVariance(window, lambda))
+		ts = append(ts, VolatilityTerm{
+			Horizon:    horizon,
+			Volatility: dailyVol * math.Sqrt(tradingDays),
+		})
+	}
+
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Horizon < ts[j].Horizon })
+	return ts
+}
+
+// ewmaVariance returns the exponentially weighted mean of squared returns,
+// weighting the most recent return highest.
+func ewmaVariance(returns []float64, lambda float64) float64 {
+	var weighted, totalWeight float64
+	weight := 1.0
+	for i := len(returns) - 1; i >= 0; i-- {
+		weighted += weight * returns[i] * returns[i]
+		totalWeight += weight
+		weight *= lambda
+	}
+	return weighted / totalWeight
+}
+
+// dailyLogReturns resamples prices to the last close of each UTC day and
+// returns the log returns between consecutive days, oldest first.
+func dailyLogReturns(prices []types.Price) []float64 {
+	sorted := make([]types.Price, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var closes []float64
+	var lastDay time.Time
+	for _, p := range sorted {
+		if p.Price <= 0 {
+			continue
+		}
+		day := p.Timestamp.UTC().Truncate(24 * time.Hour)
+		if len(closes) > 0 && day.Equal(lastDay) {
+			closes[len(closes)-1] = p.Price
+			continue
+		}
+		closes = append(closes, p.Price)
+		lastDay = day
+	}
+
+	if len(closes) < 2 {
+		return nil
+	}
+	returns := make([]float64, 0, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		returns = append(returns, math.Log(closes[i]/closes[i-1]))
+	}
+	return returns
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package volatility
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"prediction-bot/pkg/types"
+)
+
+// hourlyPrices builds hourly prices from daily closes, repeating each close
+// for every hour of its day.
+func hourlyPrices(closes []float64) []types.Price {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var prices []types.Price
+	for d, c := range closes {
+		for h := 0; h < 24; h++ {
+			prices = append(prices, types.Price{
+				Symbol:    "BTCUSDT",
+				Price:     c,
+				Timestamp: base.Add(time.Duration(d*24+h) * time.Hour),
+			})
+		}
+	}
+	return prices
+}
+
+func TestCalculateTermStructure_RecentMovesDominateShortHorizon(t *testing.T) {
+	// Calm for 30 days, then a volatile final week
+	var closes []float64
+	price := 100.0
+	for i := 0; i < 30; i++ {
+		price *= 1.001
+		closes = append(closes, price)
+	}
+	for i := 0; i < 7; i++ {
+		if i%2 == 0 {
+			price *= 1.05
+		} else {
+			price *= 0.95
+		}
+		closes = append(closes, price)
+	}
+
+	ts := CalculateTermStructure(hourlyPrices(closes), DefaultHorizons, TradingDaysCrypto)
+	if len(ts) != 2 {
+		t.Fatalf("expected 2 terms, got %d", len(ts))
+	}
+	if ts[0].Horizon != 7*24*time.Hour || ts[1].Horizon != 30*24*time.Hour {
+		t.Errorf("expected 7d and 30d horizons, got %v and %v", ts[0].Horizon, ts[1].Horizon)
+	}
+	if ts[0].Volatility <= ts[1].Volatility {
+		t.Errorf("expected 7d vol %.4f above 30d vol %.4f after a volatile week", ts[0].Volatility, ts[1].Volatility)
+	}
+
+	// Daily moves of ~5% annualize to roughly 0.05 * sqrt(365)
+	expected := 0.05 * math.Sqrt(TradingDaysCrypto)
+	if ts[0].Volatility < expected*0.8 || ts[0].Volatility > expected*1.2 {
+		t.Errorf("expected 7d vol near %.2f, got %.4f", expected, ts[0].Volatility)
+	}
+}
+
+func TestCalculateTermStructure_InsufficientData(t *testing.T) {
+	ts := CalculateTermStructure(hourlyPrices([]float64{100}), DefaultHorizons, TradingDaysCrypto)
+	if len(ts) != 0 {
+		t.Errorf("expected no terms from a single day, got %+v", ts)
+	}
+}
+
+func TestTermStructure_Closest(t *testing.T) {
+	ts := TermStructure{
+		{Horizon: 7 * 24 * time.Hour, Volatility: 0.9},
+		{Horizon: 30 * 24 * time.Hour, Volatility: 0.5},
+	}
+
+	if term, _ := ts.Closest(24 * time.Hour); term.Volatility != 0.9 {
+		t.Errorf("expected 7d term for 1 day to close, got %+v", term)
+	}
+	if term, _ := ts.Closest(25 * 24 * time.Hour); term.Volatility != 0.5 {
+		t.Errorf("expected 30d term for 25 days to close, got %+v", term)
+	}
+	if _, ok := (TermStructure{}).Closest(time.Hour); ok {
+		t.Error("expected no term from an empty structure")
+	}
+}
@@ -0,0 +1,341 @@
+// Package webui serves the dashboard's bankroll, positions and stats over
+// HTTP, as a JSON API and an auto-refreshing HTML page, for checking on the
+// bot away from its terminal.
+package webui
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"prediction-bot/internal/dashboard"
+	"prediction-bot/internal/persistence"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultRefreshInterval is how often the HTML page reloads itself.
+const DefaultRefreshInterval = 10 * time.Second
+
+// operatorActionLimit is how many of the most recent operator actions are
+// shown.
+const operatorActionLimit = 20
+
+// OperatorActions is a source of the manual interventions in the bot's
+// trading.
+type OperatorActions interface {
+	GetRecent(limit int) ([]*persistence.OperatorAction, error)
+}
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown.
+const shutdownTimeout = 5 * time.Second
+
+// Server serves dashboard data over HTTP.
+type Server struct {
+	provider dashboard.DataProvider
+	actions  OperatorActions
+	dryRun   bool
+	username string
+	password string
+	refresh  time.Duration
+	metrics  http.Handler
+	now      func() time.Time
+}
+
+// NewServer creates a new Server reading from provider.
+func NewServer(provider dashboard.DataProvider, dryRun bool) *Server {
+	return &Server{
+		provider: provider,
+		dryRun:   dryRun,
+		refresh:  DefaultRefreshInterval,
+		now:      time.Now,
+	}
+}
+
+// SetBasicAuth requires every request to authenticate with username and
+// password. An empty password leaves the server open.
+func (s *Server) SetBasicAuth(username, password string) {
+	s.username = username
+	s.password = password
+}
+
+// SetOperatorActions shows the most recent operator actions alongside the
+// bot's own trading, so human interventions can be told apart from the
+// bot's decisions. Without it none are shown.
+func (s *Server) SetOperatorActions(actions OperatorActions) {
+	s.actions = actions
+}
+
+// SetMetrics serves the bot's metrics at /metrics for Prometheus to scrape,
+// such as an alert.Watchdog. Without it /metrics is not served.
+func (s *Server) SetMetrics(metrics http.Handler) {
+	s.metrics = metrics
+}
+
+// SetRefreshInterval sets how often the HTML page reloads itself. Zero uses
+// DefaultRefreshInterval.
+func (s *Server) SetRefreshInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	s.refresh = interval
+}
+
+// Handler returns the server's routes:
+//
+//	GET /               HTML dashboard
+//	GET /api/status     bankrolls, positions and stats together
+//	GET /api/bankrolls
+//	GET /api/positions
+//	GET /api/stats
+//	GET /api/operator-actions
+//	GET /metrics        Prometheus metrics (see SetMetrics)
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handlePage)
+	mux.HandleFunc("GET /api/status", s.handleStatus)
+	mux.HandleFunc("GET /api/bankrolls", s.handleBankrolls)
+	mux.HandleFunc("GET /api/positions", s.handlePositions)
+	mux.HandleFunc("GET /api/stats", s.handleStats)
+	mux.HandleFunc("GET /api/operator-actions", s.handleOperatorActions)
+	if s.metrics != nil {
+		mux.Handle("GET /metrics", s.metrics)
+	}
+	return s.authenticate(mux)
+}
+
+// ListenAndServe serves on addr until ctx is cancelled, then shuts down
+// gracefully.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	log.Info().
+		Str("addr", addr).
+		Bool("basic_auth", s.password != "").
+		Msg("web dashboard listening")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve web dashboard: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("shut down web dashboard: %w", err)
+		}
+		if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve web dashboard: %w", err)
+		}
+		return nil
+	}
+}
+
+// authenticate wraps next with basic auth if a password is set.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.password == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(username), []byte(s.username)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="prediction-bot", charset="UTF-8"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.status()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, status)
+}
+
+func (s *Server) handleBankrolls(w http.ResponseWriter, r *http.Request) {
+	bankrolls, err := s.provider.GetBankrolls()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, toBankrolls(bankrolls))
+}
+
+func (s *Server) handlePositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := s.provider.GetPositions()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, toPositions(positions))
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.provider.GetStats()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, toStats(stats))
+}
+
+func (s *Server) handleOperatorActions(w http.ResponseWriter, r *http.Request) {
+	actions, err := s.operatorActions()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	writeJSON(w, actions)
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	status, err := s.status()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page := struct {
+		Status
+		RefreshSeconds int
+	}{status, int(s.refresh.Seconds())}
+	if err := pageTemplate.Execute(w, page); err != nil {
+		log.Error().Err(err).Msg("failed to render web dashboard")
+	}
+}
+
+// status reads everything the dashboard shows.
+func (s *Server) status() (Status, error) {
+	bankrolls, err := s.provider.GetBankrolls()
+	if err != nil {
+		return Status{}, fmt.Errorf("get bankrolls: %w", err)
+	}
+	positions, err := s.provider.GetPositions()
+	if err != nil {
+		return Status{}, fmt.Errorf("get positions: %w", err)
+	}
+	stats, err := s.provider.GetStats()
+	if err != nil {
+		return Status{}, fmt.Errorf("get stats: %w", err)
+	}
+	actions, err := s.operatorActions()
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{
+		UpdatedAt:       s.now().UTC(),
+		DryRun:          s.dryRun,
+		Bankrolls:       toBankrolls(bankrolls),
+		Positions:       toPositions(positions),
+		Stats:           toStats(stats),
+		OperatorActions: actions,
+	}, nil
+}
+
+// operatorActions reads the most recent operator actions, none if the
+// server has no source of them.
+func (s *Server) operatorActions() ([]OperatorAction, error) {
+	if s.actions == nil {
+		return []OperatorAction{}, nil
+	}
+	actions, err := s.actions.GetRecent(operatorActionLimit)
+	if err != nil {
+		return nil, fmt.Errorf("get operator actions: %w", err)
+	}
+	return toOperatorActions(actions), nil
+}
+
+// fail logs err and reports it to the client without its details.
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	log.Error().Err(err).Msg("web dashboard request failed")
+	http.Error(w, "failed to read dashboard data", http.StatusInternalServerError)
+}
+
+// writeJSON writes v as the JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("failed to write web dashboard response")
+	}
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"money":   func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	"signed":  func(v float64) string { return fmt.Sprintf("%+.2f", v) },
+	"percent": func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+	"price":   func(v float64) string { return fmt.Sprintf("%.3f", v) },
+	"time":    func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="{{.RefreshSeconds}}">
+<title>Prediction Bot</title>
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; margin: 1rem; background: #111; color: #eee; }
+h1 { font-size: 1.3rem; }
+h2 { font-size: 1.05rem; color: #f6c; margin-top: 1.5rem; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+th, td { text-align: left; padding: 0.3rem 0.4rem; border-bottom: 1px solid #333; }
+th { color: #999; font-weight: normal; }
+.pos { color: #4c4; } .neg { color: #e55; } .muted { color: #888; }
+.mode { font-size: 0.8rem; padding: 0.1rem 0.4rem; border-radius: 0.3rem; background: #333; }
+</style>
+</head>
+<body>
+<h1>Prediction Bot <span class="mode">{{if .DryRun}}DRY RUN{{else}}LIVE{{end}}</span></h1>
+<p class="muted">Updated {{time .UpdatedAt}}</p>
+
+<h2>Bankroll</h2>
+<table>
+<tr><th>Platform</th><th>Current</th><th>Change</th></tr>
+{{range .Bankrolls}}<tr><td>{{.Platform}}</td><td>{{money .CurrentAmount}}</td><td class="{{if lt .Delta 0.0}}neg{{else}}pos{{end}}">{{signed .Delta}} ({{percent .DeltaPercent}})</td></tr>
+{{else}}<tr><td colspan="3" class="muted">No bankrolls</td></tr>
+{{end}}</table>
+
+<h2>Open Positions</h2>
+<table>
+<tr><th>Market</th><th>Side</th><th>Entry</th><th>Now</th><th>PnL</th></tr>
+{{range .Positions}}<tr><td>{{.MarketTitle}}<br><span class="muted">{{.Platform}}</span></td><td>{{.Side}}</td><td>{{price .EntryPrice}}</td><td>{{price .CurrentPrice}}</td><td class="{{if lt .UnrealizedPnL 0.0}}neg{{else}}pos{{end}}">{{signed .UnrealizedPnL}}</td></tr>
+{{else}}<tr><td colspan="5" class="muted">No open positions</td></tr>
+{{end}}</table>
+
+<h2>Stats</h2>
+<table>
+<tr><td>Trades</td><td>{{.Stats.TotalTrades}} ({{.Stats.WinningTrades}} won, {{.Stats.LosingTrades}} lost)</td></tr>
+<tr><td>Win rate</td><td>{{percent .Stats.WinRate}}</td></tr>
+<tr><td>Realized PnL</td><td>{{money .Stats.RealizedPnL}}</td></tr>
+<tr><td>Unrealized PnL</td><td>{{money .Stats.UnrealizedPnL}}</td></tr>
+<tr><td>Net PnL</td><td>{{money .Stats.NetPnL}}</td></tr>
+<tr><td>Max drawdown</td><td>{{percent .Stats.MaxDrawdownPercent}}</td></tr>
+</table>
+
+<h2>Operator Actions</h2>
+<table>
+<tr><th>When</th><th>Who</th><th>Action</th><th>Target</th><th>Details</th></tr>
+{{range .OperatorActions}}<tr><td>{{time .CreatedAt}}</td><td>{{.Actor}}</td><td>{{.Action}}</td><td>{{.Target}}</td><td class="muted">{{.Details}}</td></tr>
+{{else}}<tr><td colspan="5" class="muted">No operator actions</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
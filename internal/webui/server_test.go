@@ -0,0 +1,244 @@
+package webui
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"prediction-bot/internal/dashboard/views"
+	"prediction-bot/internal/persistence"
+)
+
+// MockDataProvider mocks the dashboard data provider for testing.
+type MockDataProvider struct {
+	bankrolls []views.BankrollData
+	positions []views.PositionData
+	stats     views.StatsData
+	err       error
+}
+
+func (m *MockDataProvider) GetBankrolls() ([]views.BankrollData, error) {
+	return m.bankrolls, m.err
+}
+
+func (m *MockDataProvider) GetPositions() ([]views.PositionData, error) {
+	return m.positions, m.err
+}
+
+func (m *MockDataProvider) GetStats() (views.StatsData, error) {
+	return m.stats, m.err
+}
+
+func newTestProvider() *MockDataProvider {
+	return &MockDataProvider{
+		bankrolls: []views.BankrollData{
+			{Platform: "polymarket", InitialAmount: 50, CurrentAmount: 55},
+		},
+		positions: []views.PositionData{
+			{
+				ID:           7,
+				Platform:     "kalshi",
+				MarketTitle:  "Will Bitcoin be above $100,000?",
+				EntryPrice:   0.80,
+				CurrentPrice: 0.90,
+				Quantity:     10,
+				Side:         "YES",
+			},
+		},
+		stats: views.StatsData{TotalTrades: 4, WinningTrades: 3, LosingTrades: 1, RealizedPnL: 5, MaxDrawdown: 0.1},
+	}
+}
+
+func get(t *testing.T, handler http.Handler, path string, auth func(*http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if auth != nil {
+		auth(req)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStatusAPI(t *testing.T) {
+	server := NewServer(newTestProvider(), true)
+	server.now = func() time.Time { return time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC) }
+
+	rec := get(t, server.Handler(), "/api/status", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if !status.DryRun {
+		t.Error("expected dry_run true")
+	}
+	if len(status.Bankrolls) != 1 || status.Bankrolls[0].Delta != 5 || status.Bankrolls[0].DeltaPercent != 10 {
+		t.Errorf("expected polymarket bankroll up $5 (10%%), got %+v", status.Bankrolls)
+	}
+	if len(status.Positions) != 1 || status.Positions[0].UnrealizedPnL < 0.99 || status.Positions[0].UnrealizedPnL > 1.01 {
+		t.Errorf("expected one position with $1 unrealized PnL, got %+v", status.Positions)
+	}
+	if status.Positions[0].CloseTime != nil {
+		t.Errorf("expected unknown close time to be omitted, got %v", status.Positions[0].CloseTime)
+	}
+	if status.Stats.WinRate != 75 || status.Stats.MaxDrawdownPercent != 10 {
+		t.Errorf("expected 75%% win rate and 10%% drawdown, got %+v", status.Stats)
+	}
+}
+
+func TestSectionAPIs(t *testing.T) {
+	handler := NewServer(newTestProvider(), false).Handler()
+
+	for _, path := range []string{"/api/bankrolls", "/api/positions", "/api/stats"} {
+		rec := get(t, handler, path, nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", path, rec.Code)
+		}
+		if !json.Valid(rec.Body.Bytes()) {
+			t.Errorf("%s: expected valid JSON, got %s", path, rec.Body.String())
+		}
+	}
+
+	rec := get(t, handler, "/api/positions", nil)
+	if !strings.Contains(rec.Body.String(), `"market_title":"Will Bitcoin be above $100,000?"`) {
+		t.Errorf("expected position in response, got %s", rec.Body.String())
+	}
+}
+
+func TestPageRendersAndRefreshes(t *testing.T) {
+	server := NewServer(newTestProvider(), false)
+	server.SetRefreshInterval(30 * time.Second)
+
+	rec := get(t, server.Handler(), "/", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`<meta http-equiv="refresh" content="30">`,
+		"LIVE",
+		"polymarket",
+		"Will Bitcoin be above $100,000?",
+		"75.0%",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected page to contain %q", want)
+		}
+	}
+}
+
+// mockOperatorActions mocks the operator action source for testing.
+type mockOperatorActions []*persistence.OperatorAction
+
+func (m mockOperatorActions) GetRecent(limit int) ([]*persistence.OperatorAction, error) {
+	return m, nil
+}
+
+func TestOperatorActions(t *testing.T) {
+	server := NewServer(newTestProvider(), false)
+	handler := server.Handler()
+
+	rec := get(t, handler, "/api/operator-actions", nil)
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("expected no actions without a source, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	server.SetOperatorActions(mockOperatorActions{{
+		Actor:     "os:alice",
+		Action:    persistence.OperatorActionClosePosition,
+		Target:    "position 7",
+		Details:   "exit at 0.420",
+		CreatedAt: time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC),
+	}})
+
+	rec = get(t, handler, "/api/operator-actions", nil)
+	var actions []OperatorAction
+	if err := json.Unmarshal(rec.Body.Bytes(), &actions); err != nil {
+		t.Fatalf("decode actions: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Actor != "os:alice" || actions[0].Target != "position 7" {
+		t.Errorf("expected alice's close, got %+v", actions)
+	}
+
+	body := get(t, handler, "/", nil).Body.String()
+	for _, want := range []string{"Operator Actions", "os:alice", "close_position", "position 7"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected page to contain %q", want)
+		}
+	}
+}
+
+func TestUnknownPathNotFound(t *testing.T) {
+	rec := get(t, NewServer(newTestProvider(), false).Handler(), "/nope", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	server := NewServer(newTestProvider(), false)
+	if rec := get(t, server.Handler(), "/metrics", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without metrics, got %d", rec.Code)
+	}
+
+	server.SetMetrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("prediction_bot_cycles_total 3\n"))
+	}))
+	rec := get(t, server.Handler(), "/metrics", nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "prediction_bot_cycles_total 3") {
+		t.Errorf("expected metrics served, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	server := NewServer(newTestProvider(), false)
+	server.SetBasicAuth("admin", "secret")
+	handler := server.Handler()
+
+	tests := []struct {
+		name string
+		auth func(*http.Request)
+		want int
+	}{
+		{"no credentials", nil, http.StatusUnauthorized},
+		{"wrong password", func(r *http.Request) { r.SetBasicAuth("admin", "guess") }, http.StatusUnauthorized},
+		{"wrong username", func(r *http.Request) { r.SetBasicAuth("root", "secret") }, http.StatusUnauthorized},
+		{"valid", func(r *http.Request) { r.SetBasicAuth("admin", "secret") }, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, handler, "/api/status", tt.auth)
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, rec.Code)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestProviderErrorHidesDetails(t *testing.T) {
+	provider := newTestProvider()
+	provider.err = errors.New("database is locked")
+
+	rec := get(t, NewServer(provider, false).Handler(), "/api/status", nil)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "database is locked") {
+		t.Error("expected error details to stay out of the response")
+	}
+}
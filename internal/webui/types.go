@@ -0,0 +1,140 @@
+package webui
+
+import (
+	"time"
+
+	"prediction-bot/internal/dashboard/views"
+	"prediction-bot/internal/persistence"
+)
+
+// Status is everything the dashboard shows, as served by /api/status.
+type Status struct {
+	UpdatedAt time.Time  `json:"updated_at"`
+	DryRun    bool       `json:"dry_run"`
+	Bankrolls []Bankroll `json:"bankrolls"`
+	Positions []Position `json:"positions"`
+	Stats     Stats      `json:"stats"`
+	// OperatorActions are the most recent manual interventions, newest
+	// first.
+	OperatorActions []OperatorAction `json:"operator_actions"`
+}
+
+// Bankroll is a platform's bankroll.
+type Bankroll struct {
+	Platform      string  `json:"platform"`
+	InitialAmount float64 `json:"initial_amount"`
+	CurrentAmount float64 `json:"current_amount"`
+	Delta         float64 `json:"delta"`
+	DeltaPercent  float64 `json:"delta_percent"`
+}
+
+// Position is an open position.
+type Position struct {
+	ID            int64      `json:"id"`
+	Platform      string     `json:"platform"`
+	MarketTitle   string     `json:"market_title"`
+	Asset         string     `json:"asset"`
+	Side          string     `json:"side"`
+	Direction     string     `json:"direction"`
+	EntryPrice    float64    `json:"entry_price"`
+	CurrentPrice  float64    `json:"current_price"`
+	Quantity      float64    `json:"quantity"`
+	UnrealizedPnL float64    `json:"unrealized_pnl"`
+	SafetyMargin  float64    `json:"safety_margin"`
+	EntryTime     time.Time  `json:"entry_time"`
+	CloseTime     *time.Time `json:"close_time,omitempty"`
+}
+
+// Stats are the trading statistics.
+type Stats struct {
+	TotalTrades        int     `json:"total_trades"`
+	WinningTrades      int     `json:"winning_trades"`
+	LosingTrades       int     `json:"losing_trades"`
+	WinRate            float64 `json:"win_rate"` // Percent
+	TotalPnL           float64 `json:"total_pnl"`
+	RealizedPnL        float64 `json:"realized_pnl"`
+	UnrealizedPnL      float64 `json:"unrealized_pnl"`
+	Fees               float64 `json:"fees"`
+	GasCost            float64 `json:"gas_cost"`
+	NetPnL             float64 `json:"net_pnl"`
+	MaxDrawdownPercent float64 `json:"max_drawdown_percent"`
+}
+
+// OperatorAction is a manual intervention in the bot's trading.
+type OperatorAction struct {
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toOperatorActions(actions []*persistence.OperatorAction) []OperatorAction {
+	result := make([]OperatorAction, 0, len(actions))
+	for _, a := range actions {
+		result = append(result, OperatorAction{
+			Actor:     a.Actor,
+			Action:    a.Action,
+			Target:    a.Target,
+			Details:   a.Details,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	return result
+}
+
+func toBankrolls(bankrolls []views.BankrollData) []Bankroll {
+	result := make([]Bankroll, 0, len(bankrolls))
+	for _, b := range bankrolls {
+		result = append(result, Bankroll{
+			Platform:      b.Platform,
+			InitialAmount: b.InitialAmount,
+			CurrentAmount: b.CurrentAmount,
+			Delta:         b.Delta(),
+			DeltaPercent:  b.DeltaPercent(),
+		})
+	}
+	return result
+}
+
+func toPositions(positions []views.PositionData) []Position {
+	result := make([]Position, 0, len(positions))
+	for _, p := range positions {
+		position := Position{
+			ID:            p.ID,
+			Platform:      p.Platform,
+			MarketTitle:   p.MarketTitle,
+			Asset:         p.Asset,
+			Side:          p.Side,
+			Direction:     p.Direction,
+			EntryPrice:    p.EntryPrice,
+			CurrentPrice:  p.CurrentPrice,
+			Quantity:      p.Quantity,
+			UnrealizedPnL: p.UnrealizedPnL(),
+			SafetyMargin:  p.SafetyMargin,
+			EntryTime:     p.EntryTime,
+		}
+		if !p.CloseTime.IsZero() {
+			closeTime := p.CloseTime
+			position.CloseTime = &closeTime
+		}
+		result = append(result, position)
+	}
+	return result
+}
+
+func toStats(s views.StatsData) Stats {
+	return Stats{
+		TotalTrades:        s.TotalTrades,
+		WinningTrades:      s.WinningTrades,
+		LosingTrades:       s.LosingTrades,
+		WinRate:            s.WinRate(),
+		TotalPnL:           s.TotalPnL,
+		RealizedPnL:        s.RealizedPnL,
+		UnrealizedPnL:      s.UnrealizedPnL,
+		Fees:               s.Fees,
+		GasCost:            s.GasCost,
+		NetPnL:             s.NetPnL,
+		MaxDrawdownPercent: s.MaxDrawdown * 100,
+	}
+}
@@ -0,0 +1,3 @@
+-- Optimistic locking for positions: every update increments version and
+-- only applies if the caller's version still matches
+ALTER TABLE positions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,22 @@
+-- Near misses: markets that failed exactly one eligibility threshold.
+-- One row per market and criterion; repeated scans keep the closest approach.
+CREATE TABLE near_misses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    market_id TEXT NOT NULL,
+    market_title TEXT,
+    asset TEXT,
+    criterion TEXT NOT NULL,
+    value REAL NOT NULL,
+    threshold REAL NOT NULL,
+    shortfall REAL NOT NULL,
+    probability REAL,
+    bet_side TEXT,
+    seen_count INTEGER NOT NULL DEFAULT 1,
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (platform, market_id, criterion)
+);
+
+CREATE INDEX idx_near_misses_criterion ON near_misses(criterion);
+CREATE INDEX idx_near_misses_last_seen ON near_misses(last_seen);
@@ -0,0 +1,21 @@
+-- Orders: every order placed by the bot and its latest known fill state
+CREATE TABLE orders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id TEXT NOT NULL UNIQUE,
+    platform TEXT NOT NULL,
+    market_id TEXT NOT NULL,
+    token_id TEXT,
+    position_id INTEGER,
+    side TEXT NOT NULL,
+    price REAL NOT NULL,
+    size REAL NOT NULL,
+    filled REAL NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    is_dry_run INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (position_id) REFERENCES positions(id)
+);
+
+CREATE INDEX idx_orders_status ON orders(status);
+CREATE INDEX idx_orders_position_id ON orders(position_id);
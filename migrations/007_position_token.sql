@@ -0,0 +1,3 @@
+-- Outcome token held by a position, needed to place sell orders on exit.
+-- Polymarket uses the CLOB token ID; Kalshi uses the contract side.
+ALTER TABLE positions ADD COLUMN token_id TEXT;
@@ -0,0 +1,2 @@
+-- Fees paid on a position (entry and exit), deducted from realized PnL
+ALTER TABLE positions ADD COLUMN fees REAL NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+-- Market resolutions: the winning side of each market the bot held to
+-- resolution, used by the learning system
+CREATE TABLE market_resolutions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    market_id TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    resolved_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (platform, market_id)
+);
@@ -0,0 +1,3 @@
+-- Close time of the market a position is held in, used to flatten positions
+-- ahead of resolution. NULL for positions opened before it was recorded.
+ALTER TABLE positions ADD COLUMN market_close_time DATETIME;
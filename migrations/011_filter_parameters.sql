@@ -0,0 +1,7 @@
+-- Scan filter thresholds, tunable like the other trading parameters
+INSERT INTO parameters (name, value, min_value, max_value) VALUES
+    ('min_liquidity', 100.0, 50.0, 1000.0),
+    ('min_volume', 0.0, 0.0, 10000.0),
+    ('min_hours_to_close', 0.0, 0.0, 24.0),
+    ('max_hours_to_close', 48.0, 12.0, 168.0),
+    ('max_spread', 0.10, 0.02, 0.25);
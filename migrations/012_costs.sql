@@ -0,0 +1,17 @@
+-- Platform fees charged on each order so far, in dollars
+ALTER TABLE orders ADD COLUMN fees REAL NOT NULL DEFAULT 0;
+
+-- Costs: trading costs not captured in a position's price PnL, such as gas
+-- paid for on-chain transactions
+CREATE TABLE costs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    amount REAL NOT NULL,
+    position_id INTEGER,
+    reference TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (position_id) REFERENCES positions(id)
+);
+
+CREATE INDEX idx_costs_kind ON costs(kind);
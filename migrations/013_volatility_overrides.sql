@@ -0,0 +1,8 @@
+-- Manual volatility per asset, used instead of the value calculated from
+-- price history (e.g. for assets with too little history)
+CREATE TABLE IF NOT EXISTS volatility_overrides (
+    asset TEXT PRIMARY KEY,
+    volatility REAL NOT NULL,
+    reason TEXT,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,19 @@
+-- Store bankroll amounts as integer micro-dollars, so that balance updates
+-- are exact instead of accumulating float rounding errors
+CREATE TABLE bankroll_micros (
+    id INTEGER PRIMARY KEY,
+    platform TEXT NOT NULL UNIQUE,
+    initial_micros INTEGER NOT NULL,
+    current_micros INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO bankroll_micros (id, platform, initial_micros, current_micros, updated_at)
+SELECT id, platform,
+    CAST(ROUND(initial_amount * 1000000) AS INTEGER),
+    CAST(ROUND(current_amount * 1000000) AS INTEGER),
+    updated_at
+FROM bankroll;
+
+DROP TABLE bankroll;
+ALTER TABLE bankroll_micros RENAME TO bankroll;
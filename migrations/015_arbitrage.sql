@@ -0,0 +1,28 @@
+-- Arbitrage opportunities: equivalent markets on two platforms whose implied
+-- probabilities diverge. One row per pair of markets; repeated scans keep the
+-- latest prices and the widest spread seen.
+CREATE TABLE arbitrage_opportunities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    asset TEXT NOT NULL,
+    strike REAL NOT NULL,
+    direction TEXT NOT NULL,
+    resolution_date DATETIME NOT NULL,
+    yes_platform TEXT NOT NULL,
+    yes_market_id TEXT NOT NULL,
+    yes_price REAL NOT NULL,
+    no_platform TEXT NOT NULL,
+    no_market_id TEXT NOT NULL,
+    no_price REAL NOT NULL,
+    spread REAL NOT NULL,
+    max_spread REAL NOT NULL,
+    yes_position_id INTEGER,
+    no_position_id INTEGER,
+    seen_count INTEGER NOT NULL DEFAULT 1,
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (yes_platform, yes_market_id, no_platform, no_market_id),
+    FOREIGN KEY (yes_position_id) REFERENCES positions(id),
+    FOREIGN KEY (no_position_id) REFERENCES positions(id)
+);
+
+CREATE INDEX idx_arbitrage_last_seen ON arbitrage_opportunities(last_seen);
@@ -0,0 +1,3 @@
+-- Highest price seen since entry, tracked for trailing stop losses. NULL
+-- until the price first rises above the entry price.
+ALTER TABLE positions ADD COLUMN peak_price REAL;
@@ -0,0 +1,3 @@
+-- Per-position take profit, as a fraction above the entry price. NULL uses
+-- the configured take_profit_percent.
+ALTER TABLE positions ADD COLUMN take_profit_percent REAL;
@@ -0,0 +1,17 @@
+-- Sessions: one row per bot run, written on graceful shutdown
+CREATE TABLE sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at DATETIME NOT NULL,
+    ended_at DATETIME NOT NULL,
+    dry_run BOOLEAN NOT NULL,
+    scan_cycles INTEGER NOT NULL DEFAULT 0,
+    monitor_cycles INTEGER NOT NULL DEFAULT 0,
+    settle_cycles INTEGER NOT NULL DEFAULT 0,
+    entries INTEGER NOT NULL DEFAULT 0,
+    exits INTEGER NOT NULL DEFAULT 0,
+    realized_pnl REAL NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_started_at ON sessions(started_at);
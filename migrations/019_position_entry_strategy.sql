@@ -0,0 +1,3 @@
+-- How the entry was executed: "market", "limit", or "limit_crossed" when a
+-- limit entry timed out and crossed the spread for the rest
+ALTER TABLE positions ADD COLUMN entry_strategy TEXT;
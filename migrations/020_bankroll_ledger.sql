@@ -0,0 +1,17 @@
+-- Every change to a bankroll's current amount, so current_micros can be
+-- checked against initial_micros plus the sum of its entries
+CREATE TABLE bankroll_ledger (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    amount_micros INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_bankroll_ledger_platform ON bankroll_ledger(platform);
+
+-- Carry over the balance change made before the ledger existed
+INSERT INTO bankroll_ledger (platform, amount_micros, reason)
+SELECT platform, current_micros - initial_micros, 'opening'
+FROM bankroll
+WHERE current_micros != initial_micros;
@@ -0,0 +1,6 @@
+-- Idempotency key of a bankroll mutation (position or order ID plus the
+-- operation), so a retried mutation is applied only once. NULL for
+-- mutations without one; SQLite allows any number of NULLs in a unique index.
+ALTER TABLE bankroll_ledger ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX idx_bankroll_ledger_idempotency_key ON bankroll_ledger(idempotency_key);
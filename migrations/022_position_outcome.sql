@@ -0,0 +1,4 @@
+-- The outcome traded by a position on one outcome of a multi-outcome market
+-- (e.g. a price bracket), and the upper strike of a "between" bracket
+ALTER TABLE positions ADD COLUMN outcome TEXT;
+ALTER TABLE positions ADD COLUMN strike_upper REAL;
@@ -0,0 +1,4 @@
+-- The trading strategy a position was entered on: "fade" for the opposite
+-- side of a market the volatility model says is overpriced, empty for
+-- positions that follow the market
+ALTER TABLE positions ADD COLUMN trade_strategy TEXT;
@@ -0,0 +1,7 @@
+-- Resolution audits: the underlying's settlement value from the price
+-- provider at market close, the outcome it implies, and whether the
+-- platform resolved the same way, to detect resolution-source drift
+ALTER TABLE market_resolutions ADD COLUMN settlement_value REAL;
+ALTER TABLE market_resolutions ADD COLUMN settlement_source TEXT;
+ALTER TABLE market_resolutions ADD COLUMN expected_outcome TEXT;
+ALTER TABLE market_resolutions ADD COLUMN outcome_matches BOOLEAN;
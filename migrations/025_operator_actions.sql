@@ -0,0 +1,13 @@
+-- Operator actions: every manual intervention (closing a position,
+-- changing a parameter), who made it and when, so the audit trail tells
+-- the bot's decisions from human ones
+CREATE TABLE operator_actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target TEXT,
+    details TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_operator_actions_created_at ON operator_actions(created_at);
@@ -0,0 +1,27 @@
+-- Scan decisions: every market a scan evaluated, whether it passed the
+-- eligibility criteria, and why it was rejected, skipped or entered, with
+-- its prices and the safety margin computed for it. One row per market and
+-- reason; repeated scans keep the latest prices.
+CREATE TABLE scan_decisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    market_id TEXT NOT NULL,
+    outcome TEXT NOT NULL DEFAULT '',
+    market_title TEXT,
+    asset TEXT,
+    eligible BOOLEAN NOT NULL,
+    reason TEXT NOT NULL,
+    yes_price REAL,
+    no_price REAL,
+    probability REAL,
+    bet_side TEXT,
+    safety_margin REAL,
+    volatility REAL,
+    seen_count INTEGER NOT NULL DEFAULT 1,
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (platform, market_id, outcome, reason)
+);
+
+CREATE INDEX idx_scan_decisions_reason ON scan_decisions(reason);
+CREATE INDEX idx_scan_decisions_last_seen ON scan_decisions(last_seen);
@@ -0,0 +1,12 @@
+-- Rolling volatility: the realized volatility term structure of an asset
+-- as of each day's close, precomputed from stored price history by the
+-- volatility backfill
+CREATE TABLE rolling_volatility (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    asset TEXT NOT NULL,
+    as_of DATETIME NOT NULL,
+    horizon_hours INTEGER NOT NULL,
+    volatility REAL NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (asset, as_of, horizon_hours)
+);
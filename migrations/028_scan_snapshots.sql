@@ -0,0 +1,26 @@
+-- Scan snapshots: what each of a platform's latest scan cycles decided
+-- about every market it evaluated, so consecutive cycles can be diffed.
+-- Unlike scan_decisions, rows are not merged across cycles; only the latest
+-- few snapshots per platform are kept.
+CREATE TABLE scan_snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    scanned_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE scan_snapshot_markets (
+    snapshot_id INTEGER NOT NULL REFERENCES scan_snapshots(id),
+    market_id TEXT NOT NULL,
+    outcome TEXT NOT NULL DEFAULT '',
+    market_title TEXT,
+    asset TEXT,
+    eligible BOOLEAN NOT NULL,
+    reason TEXT NOT NULL,
+    yes_price REAL,
+    no_price REAL,
+    probability REAL,
+    bet_side TEXT
+);
+
+CREATE INDEX idx_scan_snapshots_platform ON scan_snapshots(platform, id);
+CREATE INDEX idx_scan_snapshot_markets_snapshot ON scan_snapshot_markets(snapshot_id);
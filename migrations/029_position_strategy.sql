@@ -0,0 +1,5 @@
+-- The strategy file a position was entered under, by name and version, so
+-- the performance of strategy variants can be compared. Empty for positions
+-- entered without one.
+ALTER TABLE positions ADD COLUMN strategy TEXT;
+ALTER TABLE positions ADD COLUMN strategy_version INTEGER;
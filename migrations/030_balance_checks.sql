@@ -0,0 +1,12 @@
+-- Each comparison of a platform's bankroll with the balance the platform
+-- reports, and what was done about the difference
+CREATE TABLE balance_checks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    bankroll_micros INTEGER NOT NULL,
+    balance_micros INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_balance_checks_platform ON balance_checks(platform, created_at);
@@ -0,0 +1,5 @@
+-- Truncated request and response bodies of each platform API call
+ALTER TABLE api_log ADD COLUMN request_body TEXT;
+ALTER TABLE api_log ADD COLUMN response_body TEXT;
+
+CREATE INDEX idx_api_log_api_created_at ON api_log(api, created_at);
@@ -0,0 +1,3 @@
+-- The value config.yaml last set for each parameter, so a restart only
+-- overrides what learning adjusted when config.yaml itself changed
+ALTER TABLE parameters ADD COLUMN config_value REAL;
@@ -0,0 +1,7 @@
+-- The highest realized equity (initial bankrolls plus realized PnL) seen,
+-- which the learning cycle measures its drawdown guardrail from
+CREATE TABLE equity_peak (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    peak_micros INTEGER NOT NULL,
+    reached_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- The parameter experiment a position was entered under, by name, and the
+-- arm it was assigned: champion (the live parameters) or challenger, so the
+-- arms' performance can be compared. Empty for positions entered outside an
+-- experiment.
+ALTER TABLE positions ADD COLUMN experiment TEXT;
+ALTER TABLE positions ADD COLUMN experiment_arm TEXT;
@@ -0,0 +1,5 @@
+-- Parameters learned for one platform's or asset's trades, stored as rows
+-- named platform:asset:name beside the global parameter they fall back to.
+-- Empty is any platform or asset; global rows have both empty.
+ALTER TABLE parameters ADD COLUMN platform TEXT NOT NULL DEFAULT '';
+ALTER TABLE parameters ADD COLUMN asset TEXT NOT NULL DEFAULT '';
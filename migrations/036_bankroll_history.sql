@@ -0,0 +1,14 @@
+-- Each platform's current bankroll after every change to it, which the
+-- dashboard plots as the equity curve
+CREATE TABLE bankroll_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    amount_micros INTEGER NOT NULL,
+    recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_bankroll_history_recorded_at ON bankroll_history(recorded_at);
+
+-- Start the curve from the bankrolls as they stand
+INSERT INTO bankroll_history (platform, amount_micros)
+SELECT platform, current_micros FROM bankroll;
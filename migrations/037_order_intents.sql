@@ -0,0 +1,21 @@
+-- Order intents: a journal of the live orders the bot is about to place,
+-- written before each order so one a crash interrupts can be found and
+-- reconciled against the platform at the next start
+CREATE TABLE order_intents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    platform TEXT NOT NULL,
+    market_id TEXT NOT NULL,
+    token_id TEXT,
+    position_id INTEGER,
+    side TEXT NOT NULL,
+    price REAL NOT NULL,
+    size REAL NOT NULL,
+    order_id TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    detail TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (position_id) REFERENCES positions(id)
+);
+
+CREATE INDEX idx_order_intents_status ON order_intents(status);
@@ -0,0 +1,34 @@
+-- Store the remaining dollar amounts as integer micro-dollars, like the
+-- bankroll and its ledger: position prices, fees and realized PnL, order
+-- fees, costs and session PnL. Quantities stay REAL, as platforms trade
+-- fractional contracts.
+ALTER TABLE positions ADD COLUMN entry_price_micros INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE positions ADD COLUMN exit_price_micros INTEGER;
+ALTER TABLE positions ADD COLUMN peak_price_micros INTEGER;
+ALTER TABLE positions ADD COLUMN realized_pnl_micros INTEGER;
+ALTER TABLE positions ADD COLUMN fees_micros INTEGER NOT NULL DEFAULT 0;
+
+UPDATE positions SET
+    entry_price_micros = CAST(ROUND(entry_price * 1000000) AS INTEGER),
+    exit_price_micros = CAST(ROUND(exit_price * 1000000) AS INTEGER),
+    peak_price_micros = CAST(ROUND(peak_price * 1000000) AS INTEGER),
+    realized_pnl_micros = CAST(ROUND(realized_pnl * 1000000) AS INTEGER),
+    fees_micros = CAST(ROUND(fees * 1000000) AS INTEGER);
+
+ALTER TABLE positions DROP COLUMN entry_price;
+ALTER TABLE positions DROP COLUMN exit_price;
+ALTER TABLE positions DROP COLUMN peak_price;
+ALTER TABLE positions DROP COLUMN realized_pnl;
+ALTER TABLE positions DROP COLUMN fees;
+
+ALTER TABLE orders ADD COLUMN fees_micros INTEGER NOT NULL DEFAULT 0;
+UPDATE orders SET fees_micros = CAST(ROUND(fees * 1000000) AS INTEGER);
+ALTER TABLE orders DROP COLUMN fees;
+
+ALTER TABLE costs ADD COLUMN amount_micros INTEGER NOT NULL DEFAULT 0;
+UPDATE costs SET amount_micros = CAST(ROUND(amount * 1000000) AS INTEGER);
+ALTER TABLE costs DROP COLUMN amount;
+
+ALTER TABLE sessions ADD COLUMN realized_pnl_micros INTEGER NOT NULL DEFAULT 0;
+UPDATE sessions SET realized_pnl_micros = CAST(ROUND(realized_pnl * 1000000) AS INTEGER);
+ALTER TABLE sessions DROP COLUMN realized_pnl;
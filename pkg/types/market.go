@@ -9,14 +9,59 @@ type Market struct {
 	ConditionID     string
 	Title           string
 	Description     string
+	Category        string // Platform category or first tag (empty if unknown)
 	EndDate         time.Time
 	Volume          float64
 	Liquidity       float64
+	Spread          float64 // Best ask minus best bid (0 if unknown)
 	Active          bool
 	Closed          bool
 	OutcomeYesPrice float64
 	OutcomeNoPrice  float64
 	Tokens          []Token
+	// Outcomes are the outcomes of a multi-outcome market, such as the
+	// price brackets of "Bitcoin price range on Friday?". Empty for YES/NO
+	// binaries.
+	Outcomes []Outcome
+	// Outcome names the outcome of a multi-outcome market that this market
+	// trades, on the markets returned by OutcomeMarkets.
+	Outcome string
+}
+
+// Outcome is one outcome of a multi-outcome market. Each outcome trades as
+// a YES/NO binary of its own.
+type Outcome struct {
+	MarketID  string  // Market the outcome trades on
+	Name      string  // e.g. "$95,000 to $99,999.99"
+	Price     float64 // Price of YES on the outcome
+	Spread    float64 // Best ask minus best bid (0 if unknown)
+	Liquidity float64 // 0 uses the market's liquidity
+	Tokens    []Token // Outcome tokens, if the platform trades tokens
+}
+
+// OutcomeMarkets returns the binary markets a market trades as: itself for
+// a YES/NO binary, and one market per outcome of a multi-outcome market.
+func (m Market) OutcomeMarkets() []Market {
+	if len(m.Outcomes) == 0 {
+		return []Market{m}
+	}
+
+	markets := make([]Market, 0, len(m.Outcomes))
+	for _, o := range m.Outcomes {
+		market := m
+		market.ID = o.MarketID
+		market.Outcomes = nil
+		market.Outcome = o.Name
+		market.OutcomeYesPrice = o.Price
+		market.OutcomeNoPrice = 1 - o.Price
+		market.Spread = o.Spread
+		market.Tokens = o.Tokens
+		if o.Liquidity > 0 {
+			market.Liquidity = o.Liquidity
+		}
+		markets = append(markets, market)
+	}
+	return markets
 }
 
 // Token represents a market outcome token.
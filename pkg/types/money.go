@@ -0,0 +1,54 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// MicrosPerDollar is the number of Money units in one dollar.
+const MicrosPerDollar = 1_000_000
+
+// Money is an amount of dollars in integer micro-dollars, the precision of
+// USDC. Sums and differences of Money are exact, so balances do not drift
+// the way repeatedly added float64 dollars do. Platforms report float64
+// dollars; convert with Dollars on the way in and Float64 on the way out.
+type Money int64
+
+// Dollars converts a dollar amount to Money, rounding to the nearest
+// micro-dollar.
+func Dollars(amount float64) Money {
+	return Money(math.Round(amount * MicrosPerDollar))
+}
+
+// Cost returns the Money value of quantity contracts at price, rounded once
+// to the nearest micro-dollar.
+func Cost(price, quantity float64) Money {
+	return Dollars(price * quantity)
+}
+
+// RoundDown rounds the amount down to a whole number of 10^-decimals
+// dollars, e.g. cents for 2. Decimals are clamped to 0 through 6.
+func (m Money) RoundDown(decimals int) Money {
+	decimals = min(max(decimals, 0), 6)
+	step := Money(math.Pow10(6 - decimals))
+	rem := m % step
+	if rem < 0 {
+		rem += step
+	}
+	return m - rem
+}
+
+// Float64 returns the amount in dollars.
+func (m Money) Float64() float64 {
+	return float64(m) / MicrosPerDollar
+}
+
+// String formats the amount as dollars with full precision, e.g. "-1.250000".
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+		m = -m
+	}
+	return fmt.Sprintf("%s%d.%06d", sign, m/MicrosPerDollar, m%MicrosPerDollar)
+}
@@ -0,0 +1,45 @@
+package types
+
+import "testing"
+
+func TestMoney_SumsAreExact(t *testing.T) {
+	// 0.1 cannot be represented in float64; adding it 1000 times drifts
+	var floatSum float64
+	var moneySum Money
+	for i := 0; i < 1000; i++ {
+		floatSum += 0.1
+		moneySum += Dollars(0.1)
+	}
+
+	if floatSum == 100.0 {
+		t.Fatal("expected float64 sum to drift from 100")
+	}
+	if moneySum != Dollars(100) || moneySum.Float64() != 100.0 {
+		t.Errorf("expected exact 100.0, got %v", moneySum)
+	}
+}
+
+func TestMoney_Conversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		expected string
+	}{
+		{"rounds to nearest micro", Dollars(1.2345674), "1.234567"},
+		{"rounds half away from zero", Dollars(0.0000005), "0.000001"},
+		{"negative", Dollars(-1.25), "-1.250000"},
+		{"cost of contracts", Cost(0.87, 11.5), "10.005000"},
+		{"rounds down to cents", Dollars(0.29).RoundDown(2), "0.290000"},
+		{"rounds down a fraction of a cent", Dollars(4.56789).RoundDown(2), "4.560000"},
+		{"rounds negative down", Dollars(-1.251).RoundDown(2), "-1.260000"},
+		{"rounds down to whole dollars", Dollars(7.99).RoundDown(0), "7.000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.money.String(); got != tt.expected {
+				t.Errorf("String() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
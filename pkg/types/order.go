@@ -1,6 +1,15 @@
 package types
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrOrderNotPlaced is wrapped by the errors of orders known not to have
+// been placed: refused before they were sent, or rejected by the platform.
+// Other errors placing an order leave it unknown whether the platform
+// received it, such as a timed out request.
+var ErrOrderNotPlaced = errors.New("order not placed")
 
 // OrderSide represents the side of an order (buy or sell).
 type OrderSide string
@@ -33,6 +42,7 @@ type OrderStatus string
 const (
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusPartial   OrderStatus = "partially_filled"
 	OrderStatusFilled    OrderStatus = "filled"
 	OrderStatusCancelled OrderStatus = "cancelled"
 	OrderStatusSimulated OrderStatus = "simulated"
@@ -51,13 +61,29 @@ type Order struct {
 
 // OrderResult represents the result of placing an order.
 type OrderResult struct {
-	OrderID   string
-	MarketID  string
-	TokenID   string
-	Side      OrderSide
-	Price     float64
-	Size      float64
-	Status    OrderStatus
-	IsDryRun  bool
-	CreatedAt time.Time
+	OrderID      string
+	MarketID     string
+	TokenID      string
+	Side         OrderSide
+	Price        float64
+	Size         float64
+	Filled       float64 // Quantity filled so far (0 if unknown)
+	AvgFillPrice float64 // Average execution price (0 if unknown)
+	Fees         float64 // Platform fees charged so far, in dollars
+	Status       OrderStatus
+	IsDryRun     bool
+	CreatedAt    time.Time
+}
+
+// FillPrice returns the average execution price, falling back to the limit price.
+func (r OrderResult) FillPrice() float64 {
+	if r.AvgFillPrice > 0 {
+		return r.AvgFillPrice
+	}
+	return r.Price
+}
+
+// IsResting returns true if the order can still be (partially) filled.
+func (r OrderResult) IsResting() bool {
+	return r.Status == OrderStatusPending || r.Status == OrderStatusOpen || r.Status == OrderStatusPartial
 }
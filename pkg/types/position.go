@@ -6,13 +6,18 @@ import "time"
 type Position struct {
 	Platform         string
 	MarketTicker     string
-	Quantity         int       // Number of contracts held (positive = Yes, negative = No)
+	Title            string    // Market title, where the platform reports it with the position
+	TokenID          string    // Outcome token held, on platforms trading tokens
+	Outcome          string    // Outcome held on a market with more than two; empty for binaries
+	Quantity         float64   // Number of contracts held (positive = Yes, negative = No)
 	AveragePrice     float64   // Average entry price (0.0 to 1.0)
+	CurrentPrice     float64   // Current price of the side held; 0 if not reported
 	MarketExposure   float64   // Total market exposure in dollars
 	RealizedPnL      float64   // Realized profit/loss
 	UnrealizedPnL    float64   // Unrealized profit/loss
 	TotalTraded      int       // Total contracts traded
 	FeesPaid         float64   // Total fees paid
 	RestingOrdersQty int       // Quantity in resting orders
+	EndDate          time.Time // When the market closes; zero if not reported
 	Timestamp        time.Time // When the position was last updated
 }
@@ -0,0 +1,37 @@
+package types
+
+import "strings"
+
+// Resolution describes whether a market has resolved and which outcome won.
+type Resolution struct {
+	MarketID string
+	Resolved bool
+	Outcome  string // Winning side ("YES" or "NO") when resolved
+}
+
+// SettlementPrice returns the payout per contract held on side: 1.0 if side
+// won and 0.0 otherwise.
+func (r Resolution) SettlementPrice(side string) float64 {
+	if strings.EqualFold(side, r.Outcome) {
+		return 1.0
+	}
+	return 0.0
+}
+
+// SettlementPriceFor returns the payout per contract held on side of one
+// outcome of a multi-outcome market. When r.Outcome names the winning
+// outcome rather than a side, YES on the winner and NO on every other
+// outcome pay 1.0. An empty outcome is a binary market.
+func (r Resolution) SettlementPriceFor(outcome, side string) float64 {
+	if outcome == "" || strings.EqualFold(r.Outcome, "YES") || strings.EqualFold(r.Outcome, "NO") {
+		return r.SettlementPrice(side)
+	}
+	won := strings.EqualFold(outcome, r.Outcome)
+	if strings.EqualFold(side, "NO") {
+		won = !won
+	}
+	if won {
+		return 1.0
+	}
+	return 0.0
+}
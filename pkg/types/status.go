@@ -0,0 +1,33 @@
+package types
+
+import "time"
+
+// MaintenanceLeadTime is how far ahead of announced maintenance a platform
+// is treated as unavailable for new entries. Orders still execute until the
+// maintenance starts.
+const MaintenanceLeadTime = 30 * time.Minute
+
+// PlatformStatus describes whether a platform is currently accepting trades.
+type PlatformStatus struct {
+	Platform      string
+	TradingActive bool
+	Maintenance   bool // Maintenance in progress
+	// MaintenanceScheduled is set within MaintenanceLeadTime of announced
+	// maintenance, before it starts.
+	MaintenanceScheduled bool
+	Message              string
+	ResumeAt             time.Time // Zero if unknown
+	CheckedAt            time.Time
+}
+
+// Halted reports whether orders cannot be expected to execute on the platform.
+func (s PlatformStatus) Halted() bool {
+	return !s.TradingActive || s.Maintenance
+}
+
+// EntriesPaused reports whether new entries should wait: while the platform
+// is halted, and ahead of its maintenance, so nothing is entered that can't
+// be exited.
+func (s PlatformStatus) EntriesPaused() bool {
+	return s.Halted() || s.MaintenanceScheduled
+}
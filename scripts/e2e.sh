@@ -0,0 +1,8 @@
+#!/usr/bin/env bash
+# Runs the end-to-end smoke tests: the bot trades against the mock exchange
+# on a fresh database. Run before deploying live; exits non-zero on failure.
+set -euo pipefail
+
+cd "$(dirname "$0")/.."
+
+go test -tags e2e -count=1 -v ./e2e/... "$@"